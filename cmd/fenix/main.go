@@ -7,15 +7,23 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+	"github.com/matiasleandrokruk/fenix/internal/domain/audit"
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/server"
 	"github.com/matiasleandrokruk/fenix/internal/version"
@@ -29,6 +37,18 @@ func run(args []string, out io.Writer) int {
 	if len(args) > 0 && args[0] == "serve" {
 		return runServe(args[1:], out)
 	}
+	if len(args) > 0 && args[0] == "audit" {
+		return runAudit(args[1:], out)
+	}
+	if len(args) > 0 && args[0] == "backup" {
+		return runBackup(args[1:], out)
+	}
+	if len(args) > 0 && args[0] == "reindex" {
+		return runReindex(args[1:], out)
+	}
+	if len(args) > 0 && args[0] == "import-run" {
+		return runImportRun(args[1:], out)
+	}
 
 	fs := flag.NewFlagSet("fenix", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
@@ -65,22 +85,34 @@ func resolveDefaultPort() int {
 }
 
 func runServe(args []string, out io.Writer) int {
-	port, parseErr := parseServeFlags(args)
+	opts, parseErr := parseServeFlags(args)
 	if parseErr != nil {
 		return 2
 	}
+	logger, err := logging.New(out, opts.logLevel, opts.logFormat)
+	if err != nil {
+		fmt.Fprintf(out, "serve: %v\n", err) //nolint:errcheck
+		return 2
+	}
+	// Code without a logger in its context, and the standard log package,
+	// fall back to the default; make it honor the flags too.
+	slog.SetDefault(logger)
 
 	db, err := openServeDB()
 	if err != nil {
-		fmt.Fprintf(out, "db init failed: %v\n", err) //nolint:errcheck
+		logger.Error("db init failed", logging.Err(err))
 		return 1
 	}
 
 	cfg := server.DefaultConfig()
-	cfg.Port = port
+	cfg.Port = opts.port
+	cfg.LogLevel = opts.logLevel
+	cfg.LogFormat = opts.logFormat
+	cfg.LogOutput = out
+	cfg.OTLPEndpoint = opts.otlpEndpoint
 	srv, err := server.NewServer(db, cfg)
 	if err != nil {
-		fmt.Fprintf(out, "server: init failed: %v\n", err) //nolint:errcheck
+		logger.Error("server init failed", logging.Err(err))
 		return 1
 	}
 
@@ -95,15 +127,16 @@ func runServe(args []string, out io.Writer) int {
 	select {
 	case startErr := <-errCh:
 		if startErr != nil {
-			fmt.Fprintf(out, "server failed: %v\n", startErr) //nolint:errcheck
+			logger.Error("server failed", logging.Err(startErr))
 			_ = srv.Shutdown(context.Background())
 			return 1
 		}
 	case <-sigCtx.Done():
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		// Leave background workers time to stop after the HTTP drain.
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownGracePeriod+5*time.Second)
 		defer cancel()
 		if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil {
-			fmt.Fprintf(out, "server shutdown failed: %v\n", shutdownErr) //nolint:errcheck
+			logger.Error("server shutdown failed", logging.Err(shutdownErr))
 			return 1
 		}
 	}
@@ -111,23 +144,49 @@ func runServe(args []string, out io.Writer) int {
 	return 0
 }
 
-func parseServeFlags(args []string) (int, error) {
+type serveFlags struct {
+	port         int
+	logLevel     slog.Level
+	logFormat    string
+	otlpEndpoint string
+}
+
+// parseServeFlags reads --port, --log-level, --log-format and --otlp-endpoint,
+// defaulting to the PORT, LOG_LEVEL, LOG_FORMAT and
+// OTEL_EXPORTER_OTLP_ENDPOINT environment variables.
+func parseServeFlags(args []string) (serveFlags, error) {
 	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 	port := fs.Int("port", resolveDefaultPort(), "HTTP port")
+	level := fs.String("log-level", os.Getenv("LOG_LEVEL"), "Minimum log level: debug, info, warn or error")
+	format := fs.String("log-format", envOr("LOG_FORMAT", logging.FormatText), "Log format: text or json")
+	otlpEndpoint := fs.String("otlp-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), "OTLP/HTTP collector URL for agent run traces; empty disables tracing")
 	if err := fs.Parse(args); err != nil {
-		return 0, fmt.Errorf("parse serve flags: %w", err)
+		return serveFlags{}, fmt.Errorf("parse serve flags: %w", err)
+	}
+	logLevel, err := logging.ParseLevel(*level)
+	if err != nil {
+		return serveFlags{}, err
 	}
-	return *port, nil
+	return serveFlags{port: *port, logLevel: logLevel, logFormat: *format, otlpEndpoint: *otlpEndpoint}, nil
 }
 
-func openServeDB() (*sql.DB, error) {
-	dbPath := os.Getenv("DATABASE_URL")
-	if dbPath == "" {
-		dbPath = "./data/fenixcrm.db"
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
 	}
+	return fallback
+}
 
-	db, err := sqlite.NewDB(dbPath)
+func resolveDBPath() string {
+	if dbPath := os.Getenv("DATABASE_URL"); dbPath != "" {
+		return dbPath
+	}
+	return "./data/fenixcrm.db"
+}
+
+func openServeDB() (*sql.DB, error) {
+	db, err := sqlite.NewDB(resolveDBPath())
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -138,6 +197,264 @@ func openServeDB() (*sql.DB, error) {
 	return db, nil
 }
 
+// runAudit dispatches `fenix audit <command>`.
+// Task 4.8: audit retention.
+func runAudit(args []string, out io.Writer) int {
+	if len(args) == 0 || args[0] != "purge" {
+		fmt.Fprintln(out, "usage: fenix audit purge --workspace <id> --older-than <age> [--dry-run]") //nolint:errcheck
+		return 2
+	}
+	return runAuditPurge(args[1:], out)
+}
+
+type auditPurgeFlags struct {
+	workspaceID string
+	olderThan   time.Duration
+	dryRun      bool
+}
+
+func parseAuditPurgeFlags(args []string) (auditPurgeFlags, error) {
+	fs := flag.NewFlagSet("audit purge", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	workspaceID := fs.String("workspace", "", "Workspace whose audit events are purged (required)")
+	olderThan := fs.String("older-than", "", "Purge events older than this age, e.g. 365d or 720h (required)")
+	dryRun := fs.Bool("dry-run", false, "Only count the events that would be purged")
+	if err := fs.Parse(args); err != nil {
+		return auditPurgeFlags{}, fmt.Errorf("parse audit purge flags: %w", err)
+	}
+	if *workspaceID == "" {
+		return auditPurgeFlags{}, errors.New("--workspace is required")
+	}
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		return auditPurgeFlags{}, err
+	}
+	return auditPurgeFlags{workspaceID: *workspaceID, olderThan: age, dryRun: *dryRun}, nil
+}
+
+// parseAge accepts Go durations plus a day suffix ("365d").
+func parseAge(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, errors.New("--older-than is required")
+	}
+	var age time.Duration
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --older-than %q", raw)
+		}
+		age = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --older-than %q", raw)
+		}
+		age = d
+	}
+	if age <= 0 {
+		return 0, fmt.Errorf("--older-than must be positive, got %q", raw)
+	}
+	return age, nil
+}
+
+func runAuditPurge(args []string, out io.Writer) int {
+	opts, err := parseAuditPurgeFlags(args)
+	if err != nil {
+		fmt.Fprintf(out, "audit purge: %v\n", err) //nolint:errcheck
+		return 2
+	}
+
+	db, err := openServeDB()
+	if err != nil {
+		fmt.Fprintf(out, "db init failed: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	defer db.Close() //nolint:errcheck
+
+	cutoff := time.Now().UTC().Add(-opts.olderThan)
+	n, err := audit.NewAuditService(db).Purge(context.Background(), opts.workspaceID, cutoff, opts.dryRun)
+	if err != nil {
+		fmt.Fprintf(out, "audit purge failed: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	if opts.dryRun {
+		fmt.Fprintf(out, "would purge %d audit events older than %s\n", n, cutoff.Format(time.RFC3339)) //nolint:errcheck
+		return 0
+	}
+	fmt.Fprintf(out, "purged %d audit events older than %s\n", n, cutoff.Format(time.RFC3339)) //nolint:errcheck
+	return 0
+}
+
+type backupFlags struct {
+	to    string
+	force bool
+}
+
+func parseBackupFlags(args []string) (backupFlags, error) {
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	to := fs.String("to", "", "Path of the backup file (required)")
+	force := fs.Bool("force", false, "Replace the backup file if it already exists")
+	if err := fs.Parse(args); err != nil {
+		return backupFlags{}, err
+	}
+	if *to == "" {
+		return backupFlags{}, errors.New("--to is required")
+	}
+	return backupFlags{to: *to, force: *force}, nil
+}
+
+// runBackup implements `fenix backup --to <path> [--force]`.
+// The snapshot is consistent while the server keeps writing. With --force an
+// existing file is replaced atomically: the backup is written next to it and
+// renamed over it, so a failed run never leaves a truncated backup behind.
+func runBackup(args []string, out io.Writer) int {
+	opts, err := parseBackupFlags(args)
+	if err != nil {
+		fmt.Fprintf(out, "backup: %v\n", err) //nolint:errcheck
+		return 2
+	}
+	if _, statErr := os.Stat(opts.to); statErr == nil && !opts.force {
+		fmt.Fprintf(out, "backup: %s already exists (use --force to replace it)\n", opts.to) //nolint:errcheck
+		return 1
+	}
+
+	db, err := sqlite.NewDB(resolveDBPath())
+	if err != nil {
+		fmt.Fprintf(out, "db init failed: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	defer db.Close() //nolint:errcheck
+
+	tmp := opts.to + ".tmp"
+	_ = os.Remove(tmp)
+	pages, err := sqlite.Backup(context.Background(), db, tmp)
+	if err == nil {
+		err = os.Rename(tmp, opts.to)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		fmt.Fprintf(out, "backup failed: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	fmt.Fprintf(out, "backed up %d pages to %s\n", pages, opts.to) //nolint:errcheck
+	return 0
+}
+
+type reindexFlags struct {
+	workspaceID string
+	check       bool
+}
+
+func parseReindexFlags(args []string) (reindexFlags, error) {
+	fs := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	workspaceID := fs.String("workspace", "", "Workspace whose knowledge search index is rebuilt (required)")
+	check := fs.Bool("check", false, "Only report drift; exit 1 when the index is out of sync")
+	if err := fs.Parse(args); err != nil {
+		return reindexFlags{}, err
+	}
+	if *workspaceID == "" {
+		return reindexFlags{}, errors.New("--workspace is required")
+	}
+	return reindexFlags{workspaceID: *workspaceID, check: *check}, nil
+}
+
+// runReindex implements `fenix reindex --workspace <id> [--check]`.
+// It rebuilds the knowledge FTS5 index from knowledge_item; with --check it
+// only compares row counts, which makes it usable as a CI drift gate.
+func runReindex(args []string, out io.Writer) int {
+	opts, err := parseReindexFlags(args)
+	if err != nil {
+		fmt.Fprintf(out, "reindex: %v\n", err) //nolint:errcheck
+		return 2
+	}
+
+	db, err := openServeDB()
+	if err != nil {
+		fmt.Fprintf(out, "db init failed: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	defer db.Close() //nolint:errcheck
+
+	ctx := context.Background()
+	svc := knowledge.NewIngestService(db, eventbus.New())
+	if !opts.check {
+		n, rebuildErr := svc.RebuildFTS(ctx, opts.workspaceID)
+		if rebuildErr != nil {
+			fmt.Fprintf(out, "reindex failed: %v\n", rebuildErr) //nolint:errcheck
+			return 1
+		}
+		fmt.Fprintf(out, "reindexed %d knowledge items\n", n) //nolint:errcheck
+	}
+
+	status, err := svc.VerifyFTS(ctx, opts.workspaceID)
+	if err != nil {
+		fmt.Fprintf(out, "reindex failed: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	fmt.Fprintf(out, "knowledge fts: %d base rows, %d indexed rows, %d missing, %d orphaned\n", //nolint:errcheck
+		status.BaseRows, status.IndexedRows, status.Missing, status.Orphaned)
+	if !status.InSync() {
+		fmt.Fprintln(out, "knowledge fts index is out of sync") //nolint:errcheck
+		return 1
+	}
+	return 0
+}
+
+type importRunFlags struct {
+	workspaceID string
+	file        string
+}
+
+func parseImportRunFlags(args []string) (importRunFlags, error) {
+	fs := flag.NewFlagSet("import-run", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	workspaceID := fs.String("workspace", "", "Workspace the run is imported into (required)")
+	file := fs.String("file", "", "Path of the run export bundle (required)")
+	if err := fs.Parse(args); err != nil {
+		return importRunFlags{}, err
+	}
+	if *workspaceID == "" {
+		return importRunFlags{}, errors.New("--workspace is required")
+	}
+	if *file == "" {
+		return importRunFlags{}, errors.New("--file is required")
+	}
+	return importRunFlags{workspaceID: *workspaceID, file: *file}, nil
+}
+
+// runImportRun implements `fenix import-run --workspace <id> --file <path>`.
+// It loads a bundle written by the run export endpoint, e.g. to reproduce a
+// production run in a development database; see Orchestrator.ImportRun.
+func runImportRun(args []string, out io.Writer) int {
+	opts, err := parseImportRunFlags(args)
+	if err != nil {
+		fmt.Fprintf(out, "import-run: %v\n", err) //nolint:errcheck
+		return 2
+	}
+	bundle, err := os.ReadFile(opts.file)
+	if err != nil {
+		fmt.Fprintf(out, "import-run: %v\n", err) //nolint:errcheck
+		return 1
+	}
+
+	db, err := openServeDB()
+	if err != nil {
+		fmt.Fprintf(out, "db init failed: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	defer db.Close() //nolint:errcheck
+
+	imported, err := agent.NewOrchestrator(db).ImportRun(context.Background(), opts.workspaceID, bundle)
+	if err != nil {
+		fmt.Fprintf(out, "import-run failed: %v\n", err) //nolint:errcheck
+		return 1
+	}
+	fmt.Fprintf(out, "imported run %s (definition %s, status %s)\n", imported.ID, imported.DefinitionID, imported.Status) //nolint:errcheck
+	return 0
+}
+
 func printHelp(out io.Writer) {
 	helpText := `FenixCRM - Agentic CRM OS
 
@@ -151,10 +468,20 @@ Options:
 Commands:
   serve        Start the server (default)
   migrate      Run database migrations
+  audit purge  Delete old audit events (--workspace, --older-than, --dry-run)
+  backup       Write a consistent copy of the database (--to, --force)
+  reindex      Rebuild the knowledge search index (--workspace, --check)
+  import-run   Import an exported agent run as a paused definition (--workspace, --file)
 
 Examples:
   fenix --version
   fenix serve --port 8080
-  fenix migrate up`
+  fenix serve --log-level debug --log-format json
+  fenix serve --otlp-endpoint http://localhost:4318
+  fenix migrate up
+  fenix audit purge --workspace <id> --older-than 365d --dry-run
+  fenix backup --to ./backups/fenixcrm.db
+  fenix reindex --workspace <id> --check
+  fenix import-run --workspace <id> --file run-export.json`
 	fmt.Fprintln(out, helpText) //nolint:errcheck
 }
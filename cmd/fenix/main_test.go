@@ -2,8 +2,12 @@ package main
 
 import (
 	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRun_Default_PrintsVersion(t *testing.T) {
@@ -44,3 +48,182 @@ func TestRun_InvalidFlag_Returns2(t *testing.T) {
 		t.Fatalf("expected exit code 2, got %d", code)
 	}
 }
+
+func TestRun_AuditPurge_MissingWorkspace_Returns2(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	code := run([]string{"audit", "purge", "--older-than", "365d"}, &out)
+
+	if code != 2 {
+		t.Fatalf("expected exit code 2, got %d", code)
+	}
+	if !strings.Contains(out.String(), "--workspace is required") {
+		t.Fatalf("expected workspace error, got %q", out.String())
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]time.Duration{
+		"365d": 365 * 24 * time.Hour,
+		"36h":  36 * time.Hour,
+	}
+	for raw, want := range cases {
+		got, err := parseAge(raw)
+		if err != nil || got != want {
+			t.Fatalf("parseAge(%q) = %v, %v; want %v", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"", "abc", "-5d", "0d"} {
+		if _, err := parseAge(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestRun_AuditPurge_DryRun(t *testing.T) {
+	t.Setenv("DATABASE_URL", filepath.Join(t.TempDir(), "fenix.db"))
+
+	var out bytes.Buffer
+	code := run([]string{"audit", "purge", "--workspace", "ws-1", "--older-than", "30d", "--dry-run"}, &out)
+
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "would purge 0 audit events") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestRun_Backup(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DATABASE_URL", filepath.Join(dir, "fenix.db"))
+	dest := filepath.Join(dir, "backup.db")
+
+	var out bytes.Buffer
+	if code := run([]string{"backup"}, &out); code != 2 {
+		t.Fatalf("missing --to: expected exit code 2, got %d", code)
+	}
+
+	out.Reset()
+	if code := run([]string{"backup", "--to", dest}, &out); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "backed up") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	out.Reset()
+	if code := run([]string{"backup", "--to", dest}, &out); code != 1 {
+		t.Fatalf("existing destination: expected exit code 1, got %d: %s", code, out.String())
+	}
+
+	out.Reset()
+	if code := run([]string{"backup", "--to", dest, "--force"}, &out); code != 0 {
+		t.Fatalf("--force: expected exit code 0, got %d: %s", code, out.String())
+	}
+}
+
+func TestRun_Reindex(t *testing.T) {
+	t.Setenv("DATABASE_URL", filepath.Join(t.TempDir(), "fenix.db"))
+
+	var out bytes.Buffer
+	if code := run([]string{"reindex"}, &out); code != 2 {
+		t.Fatalf("missing --workspace: expected exit code 2, got %d", code)
+	}
+
+	out.Reset()
+	if code := run([]string{"reindex", "--workspace", "ws-1"}, &out); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "reindexed 0 knowledge items") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	out.Reset()
+	if code := run([]string{"reindex", "--workspace", "ws-1", "--check"}, &out); code != 0 {
+		t.Fatalf("--check: expected exit code 0, got %d: %s", code, out.String())
+	}
+	if strings.Contains(out.String(), "reindexed") || !strings.Contains(out.String(), "0 base rows, 0 indexed rows") {
+		t.Fatalf("unexpected --check output: %q", out.String())
+	}
+}
+
+func TestRun_ImportRun(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DATABASE_URL", filepath.Join(dir, "fenix.db"))
+	db, err := openServeDB()
+	if err != nil {
+		t.Fatalf("openServeDB: %v", err)
+	}
+	if _, err = db.Exec(`INSERT INTO workspace (id, name, slug, created_at, updated_at)
+		VALUES ('ws-dev', 'Dev', 'dev', datetime('now'), datetime('now'))`); err != nil {
+		t.Fatalf("insert workspace: %v", err)
+	}
+	_ = db.Close()
+
+	bundle := filepath.Join(dir, "run-export.json")
+	if err = os.WriteFile(bundle, []byte(`{"schema_version":"v1",`+
+		`"definition":{"id":"agent-1","agent_type":"support","allowed_tools":[]},`+
+		`"run":{"id":"run-1","trigger_type":"manual","status":"success","started_at":"2026-01-02T03:04:05Z"},"evidence":[]}`), 0o600); err != nil {
+		t.Fatalf("write bundle: %v", err)
+	}
+
+	var out bytes.Buffer
+	if code := run([]string{"import-run", "--workspace", "ws-dev"}, &out); code != 2 {
+		t.Fatalf("missing --file: expected exit code 2, got %d", code)
+	}
+
+	out.Reset()
+	if code := run([]string{"import-run", "--workspace", "ws-dev", "--file", bundle}, &out); code != 0 {
+		t.Fatalf("expected exit code 0, got %d: %s", code, out.String())
+	}
+	if !strings.Contains(out.String(), "imported run") || !strings.Contains(out.String(), "status success") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestParseServeFlags_Logging(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_FORMAT", "json")
+
+	opts, err := parseServeFlags([]string{"--port", "9090"})
+	if err != nil {
+		t.Fatalf("parseServeFlags() error = %v", err)
+	}
+	if opts.port != 9090 || opts.logLevel != slog.LevelWarn || opts.logFormat != "json" {
+		t.Fatalf("opts = %+v; want port 9090, warn, json from the environment", opts)
+	}
+
+	opts, err = parseServeFlags([]string{"--log-level", "debug", "--log-format", "text"})
+	if err != nil {
+		t.Fatalf("parseServeFlags(flags) error = %v", err)
+	}
+	if opts.logLevel != slog.LevelDebug || opts.logFormat != "text" {
+		t.Fatalf("opts = %+v; want flags to override the environment", opts)
+	}
+	if _, err = parseServeFlags([]string{"--log-level", "loud"}); err == nil {
+		t.Fatal("parseServeFlags(--log-level loud) error = nil; want an error")
+	}
+}
+
+func TestParseServeFlags_OTLPEndpoint(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4318")
+
+	opts, err := parseServeFlags(nil)
+	if err != nil {
+		t.Fatalf("parseServeFlags() error = %v", err)
+	}
+	if opts.otlpEndpoint != "http://collector:4318" {
+		t.Fatalf("otlpEndpoint = %q; want the environment value", opts.otlpEndpoint)
+	}
+	opts, err = parseServeFlags([]string{"--otlp-endpoint", ""})
+	if err != nil {
+		t.Fatalf("parseServeFlags(flags) error = %v", err)
+	}
+	if opts.otlpEndpoint != "" {
+		t.Fatalf("otlpEndpoint = %q; want the flag to disable tracing", opts.otlpEndpoint)
+	}
+}
@@ -0,0 +1,10 @@
+// Package docs embeds the hand-maintained API description so the server can
+// serve it without reading the working tree at runtime.
+package docs
+
+import _ "embed"
+
+// OpenAPI is the contents of docs/openapi.yaml.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
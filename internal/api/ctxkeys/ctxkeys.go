@@ -20,10 +20,82 @@ const (
 
 	// RunID is the context key for the active agent run when tool execution happens inside a runtime flow.
 	RunID Key = "run_id"
+
+	// TokenID is the context key for the jti claim of the access JWT.
+	// Injected by AuthMiddleware, read by logout to revoke the current token.
+	TokenID Key = "token_id"
+
+	// TokenExpiresAt is the context key for the access JWT expiry (RFC 3339).
+	// Injected by AuthMiddleware alongside TokenID.
+	TokenExpiresAt Key = "token_expires_at"
+
+	// Role is the context key for the RBAC role of the authenticated user.
+	// Injected by AuthMiddleware from JWT claims, read by handlers.RequireRole.
+	Role Key = "role"
+
+	// DryRun is the context key marking tool execution as a preview: write
+	// executors validate params and describe the change without persisting it.
+	// Holds a bool; set with WithDryRun, read with IsDryRun.
+	DryRun Key = "dry_run"
+
+	// APIVersion is the context key for the major version of the API route
+	// serving the request. Holds an int; set with WithAPIVersion, read with
+	// APIVersionFrom.
+	APIVersion Key = "api_version"
+
+	// PrincipalHolder is the context key for a *Principal installed by
+	// middleware that runs outside AuthMiddleware. Set with WithPrincipal,
+	// read with PrincipalFrom.
+	PrincipalHolder Key = "principal"
 )
 
+// Principal is filled in by AuthMiddleware with the identity it resolved, so
+// middleware wrapping it (which never sees the inner context) can still
+// attribute the request, e.g. when recovering from a panic.
+type Principal struct {
+	WorkspaceID string
+	UserID      string
+}
+
 // WithValue adds a ctxkeys.Key value to the context.
 // Task 1.6.10: Helper used by AuthMiddleware to inject claims using typed keys.
 func WithValue(ctx context.Context, key Key, value string) context.Context {
 	return context.WithValue(ctx, key, value)
 }
+
+// WithDryRun marks ctx so that write tool executors only preview their effect.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, DryRun, true)
+}
+
+// IsDryRun reports whether ctx was marked with WithDryRun.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(DryRun).(bool)
+	return dryRun
+}
+
+// WithAPIVersion records the API major version serving the request.
+func WithAPIVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, APIVersion, version)
+}
+
+// APIVersionFrom returns the API major version recorded in ctx, or 1 when
+// none was recorded.
+func APIVersionFrom(ctx context.Context) int {
+	if version, ok := ctx.Value(APIVersion).(int); ok && version > 0 {
+		return version
+	}
+	return 1
+}
+
+// WithPrincipal installs an empty Principal in ctx for inner middleware to fill.
+func WithPrincipal(ctx context.Context) (context.Context, *Principal) {
+	p := &Principal{}
+	return context.WithValue(ctx, PrincipalHolder, p), p
+}
+
+// PrincipalFrom returns the Principal installed by WithPrincipal, or nil.
+func PrincipalFrom(ctx context.Context) *Principal {
+	p, _ := ctx.Value(PrincipalHolder).(*Principal)
+	return p
+}
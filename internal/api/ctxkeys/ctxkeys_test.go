@@ -17,3 +17,25 @@ func TestWithValue_SetsAndGetsTypedKey(t *testing.T) {
 		t.Fatalf("expected ws-999, got %q", got)
 	}
 }
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	if IsDryRun(context.Background()) {
+		t.Fatal("expected background context not to be dry-run")
+	}
+	if !IsDryRun(WithDryRun(context.Background())) {
+		t.Fatal("expected WithDryRun context to be dry-run")
+	}
+}
+
+func TestAPIVersion(t *testing.T) {
+	t.Parallel()
+
+	if got := APIVersionFrom(context.Background()); got != 1 {
+		t.Fatalf("expected default version 1, got %d", got)
+	}
+	if got := APIVersionFrom(WithAPIVersion(context.Background(), 2)); got != 2 {
+		t.Fatalf("expected version 2, got %d", got)
+	}
+}
@@ -4,14 +4,20 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
 )
 
+// maxAccountImportBytes caps the multipart body accepted by ImportAccounts.
+const maxAccountImportBytes = 5 << 20
+
 // AccountHandler handles HTTP requests for account CRUD operations.
 type AccountHandler struct {
 	accountService *crm.AccountService
@@ -41,6 +47,8 @@ type CreateAccountRequest struct {
 	OwnerID     string `json:"ownerId"`
 	Address     string `json:"address,omitempty"`
 	Metadata    string `json:"metadata,omitempty"`
+	// CustomFields must match the workspace's account field definitions.
+	CustomFields map[string]any `json:"customFields,omitempty"`
 }
 
 // UpdateAccountRequest is the request body for updating an account.
@@ -52,23 +60,51 @@ type UpdateAccountRequest struct {
 	OwnerID     string `json:"ownerId,omitempty"`
 	Address     string `json:"address,omitempty"`
 	Metadata    string `json:"metadata,omitempty"`
+	// CustomFields replaces the stored values when present.
+	CustomFields map[string]any `json:"customFields,omitempty"`
+}
+
+// PatchAccountRequest is the request body for a partial account update.
+// Absent fields keep their stored value; null or an empty string clears an
+// optional field and is rejected for name and ownerId.
+type PatchAccountRequest struct {
+	Name        optionalString `json:"name"`
+	Domain      optionalString `json:"domain"`
+	Industry    optionalString `json:"industry"`
+	SizeSegment optionalString `json:"sizeSegment"`
+	OwnerID     optionalString `json:"ownerId"`
+	Address     optionalString `json:"address"`
+	Metadata    optionalString `json:"metadata"`
+	// CustomFields replaces the stored values when present.
+	CustomFields map[string]any `json:"customFields"`
+}
+
+func (req PatchAccountRequest) clearedRequiredField() string {
+	switch {
+	case req.Name.cleared():
+		return "name"
+	case req.OwnerID.cleared():
+		return "ownerId"
+	}
+	return ""
 }
 
 // AccountResponse is the response body for account operations.
 type AccountResponse struct {
-	ID                string  `json:"id"`
-	WorkspaceID       string  `json:"workspaceId"`
-	Name              string  `json:"name"`
-	Domain            *string `json:"domain,omitempty"`
-	Industry          *string `json:"industry,omitempty"`
-	SizeSegment       *string `json:"sizeSegment,omitempty"`
-	OwnerID           string  `json:"ownerId"`
-	Address           *string `json:"address,omitempty"`
-	Metadata          *string `json:"metadata,omitempty"`
-	ActiveSignalCount *int    `json:"active_signal_count,omitempty"`
-	CreatedAt         string  `json:"createdAt"`
-	UpdatedAt         string  `json:"updatedAt"`
-	DeletedAt         *string `json:"deletedAt,omitempty"`
+	ID                string         `json:"id"`
+	WorkspaceID       string         `json:"workspaceId"`
+	Name              string         `json:"name"`
+	Domain            *string        `json:"domain,omitempty"`
+	Industry          *string        `json:"industry,omitempty"`
+	SizeSegment       *string        `json:"sizeSegment,omitempty"`
+	OwnerID           string         `json:"ownerId"`
+	Address           *string        `json:"address,omitempty"`
+	Metadata          *string        `json:"metadata,omitempty"`
+	CustomFields      map[string]any `json:"customFields,omitempty"`
+	ActiveSignalCount *int           `json:"active_signal_count,omitempty"`
+	CreatedAt         string         `json:"createdAt"`
+	UpdatedAt         string         `json:"updatedAt"`
+	DeletedAt         *string        `json:"deletedAt,omitempty"`
 }
 
 // ListAccountsResponse is the response body for listing accounts.
@@ -82,6 +118,8 @@ type Meta struct {
 	Total  int `json:"total"`
 	Limit  int `json:"limit"`
 	Offset int `json:"offset"`
+	// NextCursor is set in cursor mode when another page follows.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // CreateAccount handles POST /api/v1/accounts
@@ -99,28 +137,34 @@ func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate required fields
-	if req.Name == "" || req.OwnerID == "" {
-		writeError(w, http.StatusBadRequest, "name and ownerId are required")
+	if fields := requireFields(map[string]string{"name": req.Name, "ownerId": req.OwnerID}); len(fields) > 0 {
+		writeValidationError(w, fields)
 		return
 	}
 
 	// Create account via service
 	account, svcErr := h.accountService.Create(ctx, crm.CreateAccountInput{
-		WorkspaceID: wsID,
-		Name:        req.Name,
-		Domain:      req.Domain,
-		Industry:    req.Industry,
-		SizeSegment: req.SizeSegment,
-		OwnerID:     req.OwnerID,
-		Address:     req.Address,
-		Metadata:    req.Metadata,
+		WorkspaceID:  wsID,
+		Name:         req.Name,
+		Domain:       req.Domain,
+		Industry:     req.Industry,
+		SizeSegment:  req.SizeSegment,
+		OwnerID:      req.OwnerID,
+		Address:      req.Address,
+		Metadata:     req.Metadata,
+		CustomFields: req.CustomFields,
 	})
+	if errors.Is(svcErr, crm.ErrInvalidCustomFields) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, svcErr.Error())
+		return
+	}
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create account: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create account: %v", svcErr))
 		return
 	}
 
 	// Write response
+	setLocation(w, "accounts", account.ID)
 	w.WriteHeader(http.StatusCreated)
 	if !writeJSONOr500(w, accountToResponse(account)) {
 		return
@@ -138,24 +182,55 @@ func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
 
 	accountID := chi.URLParam(r, paramID)
 	if accountID == "" {
-		writeError(w, http.StatusBadRequest, errAccountIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errAccountIDRequired)
 		return
 	}
 
 	// Get account via service
 	account, svcErr := h.accountService.Get(ctx, wsID, accountID)
-	if handleGetError(w, svcErr, errAccountNotFound, errFailedToGetAccount) {
+	if errorsIsNoRows(svcErr) && r.URL.Query().Get(queryIncludeDeleted) == "true" {
+		if h.writeAccountGone(w, r, wsID, accountID) {
+			return
+		}
+	}
+	if handleGetError(w, svcErr, codeAccountNotFound, errAccountNotFound, errFailedToGetAccount) {
 		return
 	}
 
 	// Write response
 	resp := accountToResponse(account)
 	h.attachActiveSignalCount(ctx, wsID, &resp)
+	if writeNotModified(w, r, accountETag(account, resp.ActiveSignalCount)) {
+		return
+	}
 	if !writeJSONOr500(w, resp) {
 		return
 	}
 }
 
+// goneResponse is the 410 body for a soft-deleted record.
+type goneResponse struct {
+	Error     errorDetail `json:"error"`
+	DeletedAt *string     `json:"deletedAt"`
+}
+
+// writeAccountGone answers 410 Gone when accountID was soft-deleted and
+// reports whether it wrote a response; unknown ids are left to the caller.
+func (h *AccountHandler) writeAccountGone(w http.ResponseWriter, r *http.Request, wsID, accountID string) bool {
+	deletedAt, err := h.accountService.DeletedAt(r.Context(), wsID, accountID)
+	if err != nil && !errorsIsNoRows(err) {
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(errFailedToGetAccount, err))
+		return true
+	}
+	if deletedAt == nil {
+		return false
+	}
+	w.Header().Set(headerContentType, mimeJSON)
+	w.WriteHeader(http.StatusGone)
+	_ = json.NewEncoder(w).Encode(goneResponse{Error: errorDetail{Code: codeAccountDeleted, Message: errAccountDeleted}, DeletedAt: formatDeletedAt(deletedAt)})
+	return true
+}
+
 // ListAccounts handles GET /api/v1/accounts with pagination
 // Task 1.3.7: List accounts with pagination filters
 func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
@@ -165,11 +240,27 @@ func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	page := parsePaginationParams(r)
-	items, total, err := h.accountService.List(ctx, wsID, crm.ListAccountsInput{Limit: page.Limit, Offset: page.Offset})
+	cursor, cursorMode, err := cursorFromRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, errInvalidCursor)
+		return
+	}
+	input := crm.ListAccountsInput{Limit: page.Limit, Offset: page.Offset}
+	if cursorMode {
+		input = crm.ListAccountsInput{Limit: page.Limit + 1, Cursor: &crm.KeysetCursor{CreatedAt: cursor.CreatedAt, ID: cursor.ID}}
+	}
+	items, total, err := h.accountService.List(ctx, wsID, input)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list accounts: %v", err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list accounts: %v", err))
 		return
 	}
+	meta := Meta{Total: total, Limit: page.Limit, Offset: page.Offset}
+	if cursorMode {
+		meta.Offset = 0
+		items, meta.NextCursor = cursorPage(items, page.Limit, func(acc *crm.Account) pageCursor {
+			return pageCursor{CreatedAt: acc.CreatedAt, ID: acc.ID}
+		})
+	}
 	counts := countActiveSignalsByEntity(ctx, h.signalCounter, wsID, entityTypeAccount, collectEntityIDs(items, func(acc *crm.Account) string {
 		return acc.ID
 	}))
@@ -181,7 +272,7 @@ func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
 		}
 		resp = append(resp, mapped)
 	}
-	if !writePaginatedOr500(w, resp, total, page) {
+	if !writePaginatedMeta(w, resp, meta) {
 		return
 	}
 }
@@ -202,6 +293,7 @@ func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 		w,
 		r,
 		errAccountIDRequired,
+		codeAccountNotFound,
 		errAccountNotFound,
 		errFailedToGetAccount,
 		"failed to update account: %v",
@@ -218,11 +310,192 @@ func (h *AccountHandler) UpdateAccount(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// PatchAccount handles PATCH /api/v1/accounts/{id}
+// Only the fields present in the body change; see PatchAccountRequest.
+func (h *AccountHandler) PatchAccount(w http.ResponseWriter, r *http.Request) {
+	handleEntityUpdate[
+		crm.Account,
+		PatchAccountRequest,
+		crm.UpdateAccountInput,
+		AccountResponse,
+	](
+		w,
+		r,
+		errAccountIDRequired,
+		codeAccountNotFound,
+		errAccountNotFound,
+		errFailedToGetAccount,
+		"failed to update account: %v",
+		h.accountService.Get,
+		buildPatchInput,
+		func(ctx context.Context, wsID, accountID string, input crm.UpdateAccountInput) (*AccountResponse, error) {
+			updated, err := h.accountService.Update(ctx, wsID, accountID, input)
+			if err != nil {
+				return nil, fmt.Errorf("update account: %w", err)
+			}
+			resp := accountToResponse(updated)
+			return &resp, nil
+		},
+	)
+}
+
 // DeleteAccount handles DELETE /api/v1/accounts/{id}
 // Task 1.3.7: Soft delete an account (sets deleted_at timestamp)
 // TD-3 fix: returns 404 if account does not exist or is already deleted
 func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
-	handleVerifiedDelete(w, r, errAccountIDRequired, errAccountNotFound, errFailedToGetAccount, "failed to delete account: %v", h.accountService.Get, h.accountService.Delete)
+	handleVerifiedDelete(w, r, errAccountIDRequired, codeAccountNotFound, errAccountNotFound, errFailedToGetAccount, "failed to delete account: %v", h.accountService.Get, h.accountService.Delete)
+}
+
+// RestoreAccount handles POST /api/v1/accounts/{id}/restore
+// Returns 404 if the account does not exist and 409 if it is not deleted.
+func (h *AccountHandler) RestoreAccount(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	accountID := chi.URLParam(r, paramID)
+	if accountID == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, errAccountIDRequired)
+		return
+	}
+
+	account, err := h.accountService.Restore(r.Context(), wsID, accountID)
+	if errors.Is(err, crm.ErrRecordNotDeleted) {
+		writeError(w, http.StatusConflict, codeConflict, "account is not deleted")
+		return
+	}
+	if handleGetError(w, err, codeAccountNotFound, errAccountNotFound, "failed to restore account: %v") {
+		return
+	}
+
+	if !writeJSONOr500(w, accountToResponse(account)) {
+		return
+	}
+}
+
+// ImportAccountsResponse is the response body for a CSV account import.
+type ImportAccountsResponse struct {
+	Data    []crm.AccountImportResult `json:"data"`
+	Created int                       `json:"created"`
+	Failed  int                       `json:"failed"`
+}
+
+// ImportAccounts handles POST /api/v1/accounts/import
+// Expects multipart/form-data with a CSV "file" part and an optional "ownerId"
+// field (defaults to the caller). Bad rows are reported per line, not fatal.
+func (h *AccountHandler) ImportAccounts(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxAccountImportBytes)
+	if err := r.ParseMultipartForm(maxAccountImportBytes); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, codePayloadTooLarge, "import file too large")
+			return
+		}
+		writeError(w, http.StatusBadRequest, codeBadRequest, "multipart/form-data body is required")
+		return
+	}
+	defer r.MultipartForm.RemoveAll() //nolint:errcheck
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	ownerID := r.FormValue("ownerId")
+	if ownerID == "" {
+		ownerID, _ = r.Context().Value(ctxkeys.UserID).(string)
+	}
+	if ownerID == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "ownerId is required")
+		return
+	}
+
+	results, err := h.accountService.ImportCSV(r.Context(), wsID, ownerID, file)
+	switch {
+	case errors.Is(err, crm.ErrInvalidAccountImport):
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	case errors.Is(err, crm.ErrAccountImportTooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, codePayloadTooLarge, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to import accounts: %v", err))
+		return
+	}
+
+	resp := ImportAccountsResponse{Data: results}
+	for _, res := range results {
+		if res.ID != "" {
+			resp.Created++
+		} else {
+			resp.Failed++
+		}
+	}
+	if !writeJSONOr500(w, resp) {
+		return
+	}
+}
+
+// BulkDeleteAccountsRequest is the request body for POST /api/v1/accounts/bulk-delete.
+type BulkDeleteAccountsRequest struct {
+	IDs []string `json:"ids"`
+}
+
+// BulkDeleteAccountsResponse reports the outcome of each requested ID.
+type BulkDeleteAccountsResponse struct {
+	Data    []crm.AccountBulkDeleteResult `json:"data"`
+	Deleted int                           `json:"deleted"`
+	Skipped int                           `json:"skipped"`
+	Failed  int                           `json:"failed"`
+}
+
+// BulkDeleteAccounts handles POST /api/v1/accounts/bulk-delete
+// Soft-deletes up to crm.MaxAccountBulkDeleteIDs accounts. Already-deleted
+// and unknown IDs are reported per ID and do not fail the request.
+func (h *AccountHandler) BulkDeleteAccounts(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	var req BulkDeleteAccountsRequest
+	if !decodeBodyJSON(w, r, &req) {
+		return
+	}
+	if len(req.IDs) == 0 {
+		writeValidationError(w, map[string]string{"ids": "is required"})
+		return
+	}
+
+	results, err := h.accountService.BulkDelete(r.Context(), wsID, req.IDs)
+	switch {
+	case errors.Is(err, crm.ErrAccountBulkDeleteTooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, codePayloadTooLarge, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to delete accounts: %v", err))
+		return
+	}
+
+	resp := BulkDeleteAccountsResponse{Data: results}
+	for _, res := range results {
+		switch res.Status {
+		case crm.BulkDeleteStatusDeleted:
+			resp.Deleted++
+		case crm.BulkDeleteStatusSkipped:
+			resp.Skipped++
+		default:
+			resp.Failed++
+		}
+	}
+	_ = writeJSONOr500(w, resp)
 }
 
 // --- helpers ---
@@ -230,18 +503,19 @@ func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
 // accountToResponse converts a domain Account to an AccountResponse.
 func accountToResponse(acc *crm.Account) AccountResponse {
 	return AccountResponse{
-		ID:          acc.ID,
-		WorkspaceID: acc.WorkspaceID,
-		Name:        acc.Name,
-		Domain:      acc.Domain,
-		Industry:    acc.Industry,
-		SizeSegment: acc.SizeSegment,
-		OwnerID:     acc.OwnerID,
-		Address:     acc.Address,
-		Metadata:    acc.Metadata,
-		CreatedAt:   acc.CreatedAt.Format(timeFormatISO),
-		UpdatedAt:   acc.UpdatedAt.Format(timeFormatISO),
-		DeletedAt:   formatDeletedAt(acc.DeletedAt),
+		ID:           acc.ID,
+		WorkspaceID:  acc.WorkspaceID,
+		Name:         acc.Name,
+		Domain:       acc.Domain,
+		Industry:     acc.Industry,
+		SizeSegment:  acc.SizeSegment,
+		OwnerID:      acc.OwnerID,
+		Address:      acc.Address,
+		Metadata:     acc.Metadata,
+		CustomFields: acc.CustomFields,
+		CreatedAt:    acc.CreatedAt.Format(timeFormatISO),
+		UpdatedAt:    acc.UpdatedAt.Format(timeFormatISO),
+		DeletedAt:    formatDeletedAt(acc.DeletedAt),
 	}
 }
 
@@ -255,6 +529,15 @@ func (h *AccountHandler) attachActiveSignalCount(ctx context.Context, workspaceI
 	}
 }
 
+// accountETag versions an account response; the signal count is included
+// because new signals do not touch the account's updated_at.
+func accountETag(account *crm.Account, activeSignals *int) string {
+	if activeSignals == nil {
+		return weakETag(account.ID, account.UpdatedAt)
+	}
+	return weakETag(account.ID, account.UpdatedAt, strconv.Itoa(*activeSignals))
+}
+
 // formatDeletedAt formats deleted_at timestamp as string or nil.
 func formatDeletedAt(t *time.Time) *string {
 	if t == nil {
@@ -264,11 +547,14 @@ func formatDeletedAt(t *time.Time) *string {
 	return &s
 }
 
-// writeError writes a JSON error response.
-func writeError(w http.ResponseWriter, statusCode int, message string) {
-	w.Header().Set(headerContentType, mimeJSON)
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(map[string]string{"error": message}); err != nil {
-		http.Error(w, `{"error":"failed to encode error response"}`, http.StatusInternalServerError)
+// requireFields returns a "is required" entry for every empty value, keyed by
+// its JSON field name.
+func requireFields(values map[string]string) map[string]string {
+	fields := make(map[string]string)
+	for name, value := range values {
+		if value == "" {
+			fields[name] = errFieldRequired
+		}
 	}
+	return fields
 }
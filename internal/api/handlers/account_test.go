@@ -8,8 +8,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -56,6 +58,9 @@ func TestAccountHandler_CreateAccount(t *testing.T) {
 	if _, ok := resp["id"]; !ok {
 		t.Error("response missing 'id' field")
 	}
+	if got, want := w.Header().Get("Location"), fmt.Sprintf("/api/v1/accounts/%v", resp["id"]); got != want {
+		t.Errorf("Location = %q; want %q", got, want)
+	}
 	if resp["name"] != "Test Account" {
 		t.Errorf("response name = %v; want 'Test Account'", resp["name"])
 	}
@@ -70,6 +75,7 @@ func TestAccountHandler_CreateAccount_MissingWorkspace_Returns400(t *testing.T)
 
 	body, _ := json.Marshal(map[string]any{"name": "A", "ownerId": ownerID})
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/accounts", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	w := httptest.NewRecorder()
 	handler.CreateAccount(w, req)
 
@@ -111,6 +117,29 @@ func TestAccountHandler_CreateAccount_MissingRequired_Returns400(t *testing.T) {
 	if w.Code != http.StatusBadRequest {
 		t.Fatalf("status=%d want=%d", w.Code, http.StatusBadRequest)
 	}
+	assertValidationFields(t, w, map[string]string{"ownerId": "is required"})
+}
+
+// assertValidationFields checks rr holds a writeValidationError body with
+// exactly the want fields.
+func assertValidationFields(t *testing.T, rr *httptest.ResponseRecorder, want map[string]string) {
+	t.Helper()
+	var resp errorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode validation error: %v body=%s", err, rr.Body.String())
+	}
+	body := resp.Error
+	if body.Code != codeValidationFailed || body.Message != errValidationFailed {
+		t.Fatalf("error = %s %q; want %s %q", body.Code, body.Message, codeValidationFailed, errValidationFailed)
+	}
+	if len(body.Fields) != len(want) {
+		t.Fatalf("fields = %v; want %v", body.Fields, want)
+	}
+	for name, problem := range want {
+		if body.Fields[name] != problem {
+			t.Fatalf("fields[%q] = %q; want %q (fields=%v)", name, body.Fields[name], problem, body.Fields)
+		}
+	}
 }
 
 // TestAccountHandler_GetAccount tests GET /api/v1/accounts/:id
@@ -158,6 +187,50 @@ func TestAccountHandler_GetAccount(t *testing.T) {
 }
 
 // TestAccountHandler_GetAccountNotFound tests GET for non-existent account
+func TestAccountHandler_GetAccount_IfNoneMatchReturns304(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewAccountService(db)
+	handler := NewAccountHandler(svc)
+
+	created, err := svc.Create(context.Background(), crm.CreateAccountInput{
+		WorkspaceID: wsID,
+		Name:        "Cached Account",
+		OwnerID:     ownerID,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	get := func(etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/"+created.ID, nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", created.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		handler.GetAccount(w, req)
+		return w
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("GetAccount status = %d, ETag = %q", first.Code, etag)
+	}
+	if w := get(etag); w.Code != http.StatusNotModified {
+		t.Fatalf("GetAccount with matching If-None-Match status = %d; want 304", w.Code)
+	}
+	if w := get(`W/"stale"`); w.Code != http.StatusOK {
+		t.Fatalf("GetAccount with stale If-None-Match status = %d; want 200", w.Code)
+	}
+}
+
 func TestAccountHandler_GetAccountNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -180,6 +253,59 @@ func TestAccountHandler_GetAccountNotFound(t *testing.T) {
 	}
 }
 
+func TestAccountHandler_GetAccount_IncludeDeleted(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewAccountService(db)
+	handler := NewAccountHandler(svc)
+
+	created, _ := svc.Create(context.Background(), crm.CreateAccountInput{
+		WorkspaceID: wsID,
+		Name:        "Deleted Account",
+		OwnerID:     ownerID,
+	})
+	if err := svc.Delete(context.Background(), wsID, created.ID); err != nil {
+		t.Fatalf("seed delete account error = %v", err)
+	}
+
+	get := func(id, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/accounts/%s%s", id, query), nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.GetAccount(w, req)
+		return w
+	}
+
+	w := get(created.ID, "?include_deleted=true")
+	if w.Code != http.StatusGone {
+		t.Fatalf("GetAccount(deleted, include_deleted) status = %d; want %d", w.Code, http.StatusGone)
+	}
+	var body struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+		DeletedAt *string `json:"deletedAt"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("json unmarshal error = %v", err)
+	}
+	if body.DeletedAt == nil || *body.DeletedAt == "" || body.Error.Code != "ACCOUNT_DELETED" {
+		t.Fatalf("expected ACCOUNT_DELETED with deletedAt in 410 body, got %s", w.Body.String())
+	}
+
+	if code := get(created.ID, "").Code; code != http.StatusNotFound {
+		t.Errorf("GetAccount(deleted) status = %d; want %d", code, http.StatusNotFound)
+	}
+	if code := get("never-existed", "?include_deleted=true").Code; code != http.StatusNotFound {
+		t.Errorf("GetAccount(unknown, include_deleted) status = %d; want %d", code, http.StatusNotFound)
+	}
+}
+
 // TestAccountHandler_ListAccounts tests GET /api/v1/accounts with pagination
 func TestAccountHandler_ListAccounts(t *testing.T) {
 	t.Parallel()
@@ -282,6 +408,105 @@ func TestAccountHandler_UpdateAccount(t *testing.T) {
 	}
 }
 
+func TestAccountHandler_PatchAccount_KeepsOmittedFields(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewAccountService(db)
+	handler := NewAccountHandler(svc)
+
+	created, err := svc.Create(context.Background(), crm.CreateAccountInput{
+		WorkspaceID: wsID,
+		Name:        "Original Name",
+		Domain:      "original.example",
+		Industry:    "Finance",
+		OwnerID:     ownerID,
+	})
+	if err != nil {
+		t.Fatalf("seed account failed: %v", err)
+	}
+
+	body := []byte(`{"name":"Patched Name","domain":""}`)
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/accounts/"+created.ID, bytes.NewReader(body))
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", created.ID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+	handler.PatchAccount(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("PatchAccount status = %d; want %d body=%s", w.Code, http.StatusOK, w.Body.String())
+	}
+	updated, err := svc.Get(context.Background(), wsID, created.ID)
+	if err != nil {
+		t.Fatalf("Get account error = %v", err)
+	}
+	if updated.Name != "Patched Name" {
+		t.Errorf("name = %q; want %q", updated.Name, "Patched Name")
+	}
+	if updated.Industry == nil || *updated.Industry != "Finance" {
+		t.Errorf("industry = %v; want omitted field kept as Finance", updated.Industry)
+	}
+	if updated.Domain != nil {
+		t.Errorf("domain = %q; want cleared by empty value", *updated.Domain)
+	}
+	if updated.OwnerID != ownerID {
+		t.Errorf("ownerId = %q; want %q", updated.OwnerID, ownerID)
+	}
+}
+
+func TestAccountHandler_PatchAccount_NullClearsOptionalAndRejectsRequired(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewAccountService(db)
+	handler := NewAccountHandler(svc)
+
+	created, err := svc.Create(context.Background(), crm.CreateAccountInput{
+		WorkspaceID: wsID,
+		Name:        "Original Name",
+		Industry:    "Finance",
+		OwnerID:     ownerID,
+	})
+	if err != nil {
+		t.Fatalf("seed account failed: %v", err)
+	}
+
+	patch := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/accounts/"+created.ID, strings.NewReader(body))
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", created.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.PatchAccount(w, req)
+		return w
+	}
+
+	for _, body := range []string{`{"name":null}`, `{"name":""}`, `{"ownerId":null}`} {
+		if w := patch(body); w.Code != http.StatusBadRequest {
+			t.Fatalf("PatchAccount(%s) status = %d; want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+	if w := patch(`{"industry":null}`); w.Code != http.StatusOK {
+		t.Fatalf("PatchAccount status = %d; want %d body=%s", w.Code, http.StatusOK, w.Body.String())
+	}
+	updated, err := svc.Get(context.Background(), wsID, created.ID)
+	if err != nil {
+		t.Fatalf("Get account error = %v", err)
+	}
+	if updated.Industry != nil {
+		t.Errorf("industry = %q; want cleared by null", *updated.Industry)
+	}
+	if updated.Name != "Original Name" {
+		t.Errorf("name = %q; want kept after rejected patches", updated.Name)
+	}
+}
+
 // TestAccountHandler_DeleteAccount tests DELETE /api/v1/accounts/:id (soft delete)
 func TestAccountHandler_DeleteAccount(t *testing.T) {
 	t.Parallel()
@@ -356,6 +581,74 @@ func TestAccountHandler_DeleteAlreadyDeleted(t *testing.T) {
 }
 
 // TestAccountHandler_ListAccounts_LimitCapped tests TD-2: limit > 100 is capped to 100
+func TestAccountHandler_ListAccounts_CursorPagination(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewAccountService(db)
+	handler := NewAccountHandler(svc)
+
+	for i := 1; i <= 5; i++ {
+		if _, err := svc.Create(context.Background(), crm.CreateAccountInput{
+			WorkspaceID: wsID,
+			Name:        fmt.Sprintf("Cursor Account %d", i),
+			OwnerID:     ownerID,
+		}); err != nil {
+			t.Fatalf("seed create account %d error = %v", i, err)
+		}
+	}
+
+	seen := map[string]bool{}
+	pages := 0
+	cursor := ""
+	for {
+		req := httptest.NewRequest("GET", "/api/v1/accounts?limit=2&cursor="+cursor, nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		w := httptest.NewRecorder()
+		handler.ListAccounts(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("ListAccounts status = %d; body = %s", w.Code, w.Body.String())
+		}
+
+		var resp struct {
+			Data []AccountResponse `json:"data"`
+			Meta Meta              `json:"meta"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("json unmarshal error = %v", err)
+		}
+		if resp.Meta.Total != 5 || resp.Meta.Limit != 2 {
+			t.Fatalf("meta = %+v", resp.Meta)
+		}
+		for _, acc := range resp.Data {
+			if seen[acc.ID] {
+				t.Fatalf("account %s returned twice", acc.ID)
+			}
+			seen[acc.ID] = true
+		}
+		pages++
+		if resp.Meta.NextCursor == "" {
+			break
+		}
+		if pages > 5 {
+			t.Fatal("cursor pagination did not terminate")
+		}
+		cursor = resp.Meta.NextCursor
+	}
+	if len(seen) != 5 || pages != 3 {
+		t.Fatalf("saw %d accounts over %d pages; want 5 over 3", len(seen), pages)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v1/accounts?cursor=not-a-cursor", nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	w := httptest.NewRecorder()
+	handler.ListAccounts(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("invalid cursor status = %d; want 400", w.Code)
+	}
+}
+
 func TestAccountHandler_ListAccounts_LimitCapped(t *testing.T) {
 	t.Parallel()
 
@@ -482,6 +775,120 @@ func TestAccountHandler_DeleteAccount_MissingID_Returns400(t *testing.T) {
 	}
 }
 
+// TestAccountHandler_RestoreAccount tests POST /api/v1/accounts/{id}/restore
+func TestAccountHandler_RestoreAccount(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewAccountService(db)
+	handler := NewAccountHandler(svc)
+
+	created, _ := svc.Create(context.Background(), crm.CreateAccountInput{
+		WorkspaceID: wsID,
+		Name:        "Restorable",
+		OwnerID:     ownerID,
+	})
+
+	restore := func(id string) int {
+		req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/accounts/%s/restore", id), nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		w := httptest.NewRecorder()
+		handler.RestoreAccount(w, req)
+		return w.Code
+	}
+
+	if code := restore(created.ID); code != http.StatusConflict {
+		t.Errorf("Restore(live) status = %d; want %d", code, http.StatusConflict)
+	}
+	if code := restore("missing"); code != http.StatusNotFound {
+		t.Errorf("Restore(missing) status = %d; want %d", code, http.StatusNotFound)
+	}
+	if err := svc.Delete(context.Background(), wsID, created.ID); err != nil {
+		t.Fatalf("seed delete account error = %v", err)
+	}
+	if code := restore(created.ID); code != http.StatusOK {
+		t.Errorf("Restore(deleted) status = %d; want %d", code, http.StatusOK)
+	}
+	if _, err := svc.Get(context.Background(), wsID, created.ID); err != nil {
+		t.Errorf("Get() after restore error = %v", err)
+	}
+}
+
+// TestAccountHandler_ImportAccounts tests POST /api/v1/accounts/import
+func TestAccountHandler_ImportAccounts(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	handler := NewAccountHandler(crm.NewAccountService(db))
+
+	newImportRequest := func(csvData string) *http.Request {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		part, _ := mw.CreateFormFile("file", "accounts.csv")
+		part.Write([]byte(csvData)) //nolint:errcheck
+		mw.Close()                  //nolint:errcheck
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/accounts/import", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		ctx := contextWithWorkspaceID(req.Context(), wsID)
+		ctx = ctxkeys.WithValue(ctx, ctxkeys.UserID, ownerID)
+		return req.WithContext(ctx)
+	}
+
+	w := httptest.NewRecorder()
+	handler.ImportAccounts(w, newImportRequest("name,domain\nAcme,acme.com\n,bad.com\n"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("ImportAccounts status = %d; want %d (body: %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp ImportAccountsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("json unmarshal error = %v", err)
+	}
+	if resp.Created != 1 || resp.Failed != 1 || len(resp.Data) != 2 {
+		t.Errorf("response = %+v; want 1 created, 1 failed", resp)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ImportAccounts(w, newImportRequest("revenue\n1\n"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bad header status = %d; want %d", w.Code, http.StatusBadRequest)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/accounts/import", bytes.NewReader([]byte("{}")))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	handler.ImportAccounts(w, req.WithContext(contextWithWorkspaceID(req.Context(), wsID)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("non-multipart status = %d; want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestAccountHandler_ImportAccounts_TooLarge_Returns413(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	handler := NewAccountHandler(crm.NewAccountService(db))
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	part, _ := mw.CreateFormFile("file", "accounts.csv")
+	part.Write(bytes.Repeat([]byte("a"), maxAccountImportBytes+1)) //nolint:errcheck
+	mw.Close()                                                     //nolint:errcheck
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/accounts/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	handler.ImportAccounts(w, req.WithContext(contextWithWorkspaceID(req.Context(), wsID)))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("ImportAccounts status = %d; want %d", w.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
 func TestFormatDeletedAt(t *testing.T) {
 	t.Parallel()
 
@@ -507,15 +914,13 @@ func contextWithWorkspaceID(ctx context.Context, wsID string) context.Context {
 // mustOpenDBWithMigrations opens an in-memory DB with migrations applied.
 func mustOpenDBWithMigrations(t testing.TB) *sql.DB {
 	t.Helper()
-	db, err := sqlite.NewDB(":memory:")
+	// IMPORTANT: :memory: databases are per-connection in SQLite.
+	// MemoryOptions forces a single connection so migrations and subsequent
+	// queries always run against the same in-memory DB.
+	db, err := sqlite.NewDBWithOptions(":memory:", sqlite.MemoryOptions())
 	if err != nil {
 		t.Fatalf("NewDB error = %v", err)
 	}
-	// IMPORTANT: :memory: databases are per-connection in SQLite.
-	// Force a single connection so migrations and subsequent queries
-	// always run against the same in-memory DB.
-	db.SetMaxOpenConns(1)
-	db.SetMaxIdleConns(1)
 	t.Cleanup(func() { db.Close() })
 
 	if err := sqlite.MigrateUp(db); err != nil {
@@ -620,3 +1025,41 @@ func TestAccountHandler_DeleteAccount_ServiceError_500(t *testing.T) {
 		t.Fatalf("expected 500, got %d", rr.Code)
 	}
 }
+
+func TestAccountHandler_BulkDeleteAccounts(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewAccountService(db)
+	handler := NewAccountHandler(svc)
+
+	acc, err := svc.Create(context.Background(), crm.CreateAccountInput{WorkspaceID: wsID, Name: "Acme", OwnerID: ownerID})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	bulkDelete := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/accounts/bulk-delete", bytes.NewReader([]byte(body)))
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		w := httptest.NewRecorder()
+		handler.BulkDeleteAccounts(w, req)
+		return w
+	}
+
+	w := bulkDelete(`{"ids":["` + acc.ID + `","missing-id"]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("BulkDeleteAccounts status = %d; want %d (body: %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp BulkDeleteAccountsResponse
+	if err = json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("json unmarshal error = %v", err)
+	}
+	if resp.Deleted != 1 || resp.Failed != 1 || resp.Skipped != 0 || len(resp.Data) != 2 {
+		t.Errorf("response = %+v; want 1 deleted, 1 failed", resp)
+	}
+
+	if w = bulkDelete(`{"ids":[]}`); w.Code != http.StatusBadRequest {
+		t.Errorf("empty ids status = %d; want %d", w.Code, http.StatusBadRequest)
+	}
+}
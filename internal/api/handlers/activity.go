@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -41,7 +42,7 @@ func (h *ActivityHandler) CreateActivity(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	if !isActivityRequestValid(req) {
-		writeError(w, http.StatusBadRequest, "activityType, entityType, entityId, ownerId and subject are required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "activityType, entityType, entityId, ownerId and subject are required")
 		return
 	}
 	out, err := h.service.Create(r.Context(), crm.CreateActivityInput{
@@ -58,10 +59,10 @@ func (h *ActivityHandler) CreateActivity(w http.ResponseWriter, r *http.Request)
 		CompletedAt:  req.CompletedAt,
 		Metadata:     req.Metadata,
 	})
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create activity: %v", err))
+	if handleActivityWriteError(w, err, "failed to create activity: %v") {
 		return
 	}
+	setLocation(w, "activities", out.ID)
 	w.WriteHeader(http.StatusCreated)
 	if !writeJSONOr500(w, out) {
 		return
@@ -75,7 +76,7 @@ func (h *ActivityHandler) GetActivity(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chi.URLParam(r, paramID)
 	out, svcErr := h.service.Get(r.Context(), wsID, id)
-	if handleGetError(w, svcErr, errActivityNotFound, "failed to get activity: %v") {
+	if handleGetError(w, svcErr, codeActivityNotFound, errActivityNotFound, "failed to get activity: %v") {
 		return
 	}
 	if !writeJSONOr500(w, out) {
@@ -89,37 +90,60 @@ func (h *ActivityHandler) ListActivities(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	page := parsePaginationParams(r)
-	items, total, svcErr := h.service.List(r.Context(), wsID, crm.ListActivitiesInput{Limit: page.Limit, Offset: page.Offset})
+	q := r.URL.Query()
+	items, total, svcErr := h.service.List(r.Context(), wsID, crm.ListActivitiesInput{
+		Limit:      page.Limit,
+		Offset:     page.Offset,
+		EntityType: q.Get(paramEntityType),
+		EntityID:   q.Get(paramEntityID),
+		OwnerID:    q.Get(queryOwnerID),
+		Status:     q.Get(queryStatus),
+	})
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list activities: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list activities: %v", svcErr))
 		return
 	}
-	if !writePaginatedOr500(w, items, total, page) {
+	if !writePaginated(w, items, total, page.Limit, page.Offset) {
 		return
 	}
 }
 
 func (h *ActivityHandler) UpdateActivity(w http.ResponseWriter, r *http.Request) {
-	handleEntityUpdate[
-		crm.Activity,
-		UpdateActivityRequest,
-		crm.UpdateActivityInput,
-		crm.Activity,
-	](
-		w,
-		r,
-		"activity id is required",
-		errActivityNotFound,
-		"failed to get activity: %v",
-		"failed to update activity: %v",
-		h.service.Get,
-		buildUpdateActivityInput,
-		h.service.Update,
-	)
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	id, existing, ok := getEntityForUpdate(w, r, wsID, "activity id is required", codeActivityNotFound, errActivityNotFound, "failed to get activity: %v", h.service.Get)
+	if !ok {
+		return
+	}
+	var req UpdateActivityRequest
+	if !decodeBodyJSON(w, r, &req) {
+		return
+	}
+	out, err := h.service.Update(r.Context(), wsID, id, buildUpdateActivityInput(req, existing))
+	if handleActivityWriteError(w, err, "failed to update activity: %v") {
+		return
+	}
+	_ = writeJSONOr500(w, out)
+}
+
+// CompleteActivity marks a pending activity completed.
+// POST /api/v1/activities/{id}/complete
+func (h *ActivityHandler) CompleteActivity(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.service.Complete(r.Context(), wsID, chi.URLParam(r, paramID))
+	if handleActivityWriteError(w, err, "failed to complete activity: %v") {
+		return
+	}
+	_ = writeJSONOr500(w, out)
 }
 
 func (h *ActivityHandler) DeleteActivity(w http.ResponseWriter, r *http.Request) {
-	handleDeleteWithNotFound(w, r, "activity id is required", sql.ErrNoRows, errActivityNotFound, "failed to delete activity: %v", h.service.Delete)
+	handleDeleteWithNotFound(w, r, "activity id is required", sql.ErrNoRows, codeActivityNotFound, errActivityNotFound, "failed to delete activity: %v", h.service.Delete)
 }
 
 // isActivityRequestValid checks required fields for CreateActivity.
@@ -140,9 +164,27 @@ func buildUpdateActivityInput(req UpdateActivityRequest, existing *crm.Activity)
 		AssignedTo:   req.AssignedTo,
 		Subject:      coalesce(req.Subject, existing.Subject),
 		Body:         req.Body,
-		Status:       req.Status,
+		Status:       coalesce(req.Status, existing.Status),
 		DueAt:        req.DueAt,
 		CompletedAt:  req.CompletedAt,
 		Metadata:     req.Metadata,
 	}
 }
+
+// handleActivityWriteError maps ActivityService write errors: invalid input
+// is 400, a move out of a terminal status is 409.
+func handleActivityWriteError(w http.ResponseWriter, err error, internalFmt string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errorsIsNoRows(err):
+		writeError(w, http.StatusNotFound, codeActivityNotFound, errActivityNotFound)
+	case errors.Is(err, crm.ErrInvalidActivityInput):
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+	case errors.Is(err, crm.ErrInvalidActivityTransition):
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(internalFmt, err))
+	}
+	return true
+}
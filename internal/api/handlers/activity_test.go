@@ -22,6 +22,7 @@ func TestActivityHandler_CreateActivity_MissingWorkspace_Returns400(t *testing.T
 
 	body, _ := json.Marshal(map[string]any{"subject": "call"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/activities", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 
 	rr := httptest.NewRecorder()
 	h.CreateActivity(rr, req)
@@ -136,6 +137,7 @@ func TestActivityHandler_GetActivity_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewActivityHandler(crm.NewActivityService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/activities/a1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "a1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -155,6 +157,7 @@ func TestActivityHandler_ListActivities_MissingWorkspace_Returns400(t *testing.T
 	h := NewActivityHandler(crm.NewActivityService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/activities", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	h.ListActivities(rr, req)
 
@@ -170,6 +173,7 @@ func TestActivityHandler_UpdateActivity_MissingWorkspace_Returns400(t *testing.T
 	h := NewActivityHandler(crm.NewActivityService(db))
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/activities/a1", bytes.NewBufferString(`{"subject":"x"}`))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "a1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -189,6 +193,7 @@ func TestActivityHandler_DeleteActivity_MissingWorkspace_Returns400(t *testing.T
 	h := NewActivityHandler(crm.NewActivityService(db))
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/activities/a1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "a1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -357,3 +362,79 @@ func TestActivityHandler_DeleteActivity_ServiceError_500(t *testing.T) {
 		t.Fatalf("expected 500, got %d", rr.Code)
 	}
 }
+
+func TestActivityHandler_CompleteActivity(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	h := NewActivityHandler(crm.NewActivityService(db))
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	_, err := db.Exec(`INSERT INTO activity (id, workspace_id, activity_type, entity_type, entity_id, owner_id, subject, status, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		"act-open", wsID, "task", "account", "acc-1", ownerID, "Open task", "pending", now, now,
+		"act-cancelled", wsID, "task", "account", "acc-1", ownerID, "Cancelled task", "cancelled", now, now)
+	if err != nil {
+		t.Fatalf("seed activity error=%v", err)
+	}
+
+	complete := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/activities/"+id+"/complete", nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", id)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		rr := httptest.NewRecorder()
+		h.CompleteActivity(rr, req)
+		return rr
+	}
+
+	if rr := complete("act-open"); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := complete("act-cancelled"); rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for cancelled activity, got %d", rr.Code)
+	}
+	if rr := complete("missing"); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing activity, got %d", rr.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/activities?status=completed&owner_id="+ownerID, nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	h.ListActivities(rr, req)
+	var body struct {
+		Data []crm.Activity `json:"data"`
+		Meta Meta           `json:"meta"`
+	}
+	if err = json.NewDecoder(rr.Body).Decode(&body); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if body.Meta.Total != 1 || len(body.Data) != 1 || body.Data[0].ID != "act-open" {
+		t.Fatalf("expected the completed task only, got %+v", body)
+	}
+}
+
+func TestActivityHandler_CreateActivity_InvalidType_Returns400(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	h := NewActivityHandler(crm.NewActivityService(db))
+
+	body, _ := json.Marshal(map[string]any{
+		"activityType": "meeting",
+		"entityType":   "account",
+		"entityId":     "acc-1",
+		"ownerId":      ownerID,
+		"subject":      "Kickoff",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/activities", bytes.NewReader(body))
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	h.CreateActivity(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
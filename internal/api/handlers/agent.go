@@ -7,7 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -18,11 +18,12 @@ import (
 )
 
 const (
-	defaultAgentLanguage = "es"
-	errQueryRequired     = "query is required"
-	queryWorkflowID      = "workflow_id"
-	dispatchReasonKey    = "reason"
-	rejectionReasonKey   = "rejection_reason"
+	defaultAgentLanguage   = "es"
+	queryWorkflowID        = "workflow_id"
+	queryTriggerType       = "trigger_type"
+	queryAgentDefinitionID = "agent_definition_id"
+	dispatchReasonKey      = "reason"
+	rejectionReasonKey     = "rejection_reason"
 )
 
 // AgentHandler handles agent-related HTTP requests
@@ -64,6 +65,7 @@ type agentRunResponse struct {
 	EntityType        *string         `json:"entity_type,omitempty"`
 	EntityID          *string         `json:"entity_id,omitempty"`
 	RejectionReason   *string         `json:"rejection_reason,omitempty"`
+	ConversationID    *string         `json:"conversation_id,omitempty"`
 	StartedAt         string          `json:"startedAt"`
 	CompletedAt       *string         `json:"completedAt,omitempty"`
 	CreatedAt         string          `json:"createdAt"`
@@ -106,57 +108,66 @@ func buildTriggerInput(req triggerAgentRequest, workspaceID, userID string) agen
 // TriggerAgent handles POST /api/v1/agents/trigger
 // Traces: FR-230, FR-231
 func (h *AgentHandler) TriggerAgent(w http.ResponseWriter, r *http.Request) {
-	workspaceID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || workspaceID == "" {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
 	userID, _ := r.Context().Value(ctxkeys.UserID).(string)
 
+	idem, ok := idempotentTriggerFromRequest(w, r, "agents.trigger")
+	if !ok {
+		return
+	}
+
 	var req triggerAgentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 
 	if req.AgentID == "" {
-		writeError(w, http.StatusBadRequest, "agent_id is required")
+		writeValidationError(w, map[string]string{"agent_id": errFieldRequired})
 		return
 	}
 
-	run, err := h.orchestrator.TriggerAgent(r.Context(), buildTriggerInput(req, workspaceID, userID))
+	if !idem.reserve(w, r, h.orchestrator, workspaceID, writeAgentRunReplay) {
+		return
+	}
+	run, err := runIdempotent(idem, h.orchestrator.TriggerAgent)(r.Context(), buildTriggerInput(req, workspaceID, userID))
 	if err != nil {
 		h.handleTriggerError(w, err)
 		return
 	}
 
-	w.Header().Set(headerContentType, mimeJSON)
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(map[string]any{"data": agentRunToResponse(run)})
+	writeAgentRunData(w, http.StatusCreated, run)
 }
 
 // GetAgentRun handles GET /api/v1/agents/runs/{id}
 func (h *AgentHandler) GetAgentRun(w http.ResponseWriter, r *http.Request) {
-	workspaceID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || workspaceID == "" {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
 	runID := chi.URLParam(r, paramID)
 	if runID == "" {
-		writeError(w, http.StatusBadRequest, "run id is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "run id is required")
 		return
 	}
 
 	run, err := h.orchestrator.GetAgentRun(r.Context(), workspaceID, runID)
 	if err != nil {
 		if errors.Is(err, agent.ErrAgentRunNotFound) {
-			writeError(w, http.StatusNotFound, errAgentRunNotFound)
+			writeError(w, http.StatusNotFound, codeAgentRunNotFound, errAgentRunNotFound)
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "failed to get agent run")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to get agent run")
+		return
+	}
+	// Status joins the tag: some transitions stamp updated_at with second
+	// precision, and pollers must not miss one made within the same second.
+	if writeNotModified(w, r, weakETag(run.ID, run.UpdatedAt, run.Status)) {
 		return
 	}
 
@@ -165,100 +176,237 @@ func (h *AgentHandler) GetAgentRun(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]any{"data": agentRunToResponse(run)})
 }
 
-// parsePageParams extracts limit and offset from query string with defaults.
-func parsePageParams(r *http.Request) (limit, offset int64) {
-	limit, offset = 25, 0
-	if l, err := strconv.ParseInt(r.URL.Query().Get("limit"), 10, 64); err == nil && l > 0 {
-		limit = l
-	}
-	if o, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64); err == nil && o > 0 {
-		offset = o
-	}
-	return
-}
-
 // ListAgentRuns handles GET /api/v1/agents/runs
 func (h *AgentHandler) ListAgentRuns(w http.ResponseWriter, r *http.Request) {
-	workspaceID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || workspaceID == "" {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
-	limit, offset := parsePageParams(r)
+	limit, offset := PaginationFromRequest(r)
+	cursor, cursorMode, err := cursorFromRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, errInvalidCursor)
+		return
+	}
 	filters := parseRunFilters(r)
 
-	runs, total, err := h.orchestrator.ListAgentRuns(r.Context(), workspaceID, agent.ListRunsInput{
-		Limit:      limit,
-		Offset:     offset,
-		Status:     filters.status,
-		EntityType: filters.entityType,
-		EntityID:   filters.entityID,
-		WorkflowID: filters.workflowID,
-	})
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to list agent runs")
+	input := agent.ListRunsInput{
+		Limit:        int64(limit),
+		Offset:       int64(offset),
+		Status:       filters.status,
+		DefinitionID: filters.definitionID,
+		TriggerType:  filters.triggerType,
+		EntityType:   filters.entityType,
+		EntityID:     filters.entityID,
+		WorkflowID:   filters.workflowID,
+	}
+	if cursorMode {
+		input.Limit, input.Offset = int64(limit+1), 0
+		input.Cursor = &agent.RunCursor{CreatedAt: cursor.CreatedAt, ID: cursor.ID}
+	}
+	runs, total, err := h.orchestrator.ListAgentRuns(r.Context(), workspaceID, input)
+	switch {
+	case errors.Is(err, agent.ErrInvalidRunStatus):
+		writeError(w, http.StatusBadRequest, codeBadRequest, "invalid status")
+		return
+	case errors.Is(err, agent.ErrInvalidTriggerType):
+		writeError(w, http.StatusBadRequest, codeBadRequest, "invalid trigger type")
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to list agent runs")
 		return
 	}
+	meta := Meta{Total: int(total), Limit: limit, Offset: offset}
+	if cursorMode {
+		meta.Offset = 0
+		runs, meta.NextCursor = cursorPage(runs, limit, func(run *agent.Run) pageCursor {
+			return pageCursor{CreatedAt: run.CreatedAt, ID: run.ID}
+		})
+	}
 
 	out := make([]agentRunResponse, 0, len(runs))
 	for _, run := range runs {
 		out = append(out, agentRunToResponse(run))
 	}
 
-	w.Header().Set(headerContentType, mimeJSON)
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(map[string]any{
-		"data": out,
-		"meta": map[string]any{"total": total, "limit": limit, "offset": offset},
-	})
+	_ = writePaginatedMeta(w, out, meta)
 }
 
 type runFilters struct {
-	status     string
-	entityType string
-	entityID   string
-	workflowID string
+	status       string
+	definitionID string
+	triggerType  string
+	entityType   string
+	entityID     string
+	workflowID   string
 }
 
 func parseRunFilters(r *http.Request) runFilters {
 	query := r.URL.Query()
 	return runFilters{
-		status:     query.Get(queryStatus),
-		entityType: query.Get(paramEntityType),
-		entityID:   query.Get(paramEntityID),
-		workflowID: query.Get(queryWorkflowID),
+		status:       query.Get(queryStatus),
+		definitionID: query.Get(queryAgentDefinitionID),
+		triggerType:  query.Get(queryTriggerType),
+		entityType:   query.Get(paramEntityType),
+		entityID:     query.Get(paramEntityID),
+		workflowID:   query.Get(queryWorkflowID),
 	}
 }
 
 // ListAgentDefinitions handles GET /api/v1/agents/definitions
 func (h *AgentHandler) ListAgentDefinitions(w http.ResponseWriter, r *http.Request) {
-	workspaceID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || workspaceID == "" {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
 	definitions, err := h.orchestrator.ListAgentDefinitions(r.Context(), workspaceID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to list agent definitions")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to list agent definitions")
 		return
 	}
 
 	out := make([]agentDefinitionResponse, 0, len(definitions))
 	for _, def := range definitions {
-		out = append(out, agentDefinitionResponse{
-			ID:           def.ID,
-			WorkspaceID:  def.WorkspaceID,
-			Name:         def.Name,
-			Description:  def.Description,
-			AgentType:    def.AgentType,
-			Objective:    def.Objective,
-			AllowedTools: def.AllowedTools,
-			Status:       def.Status,
-			CreatedAt:    def.CreatedAt.Format(http.TimeFormat),
-			UpdatedAt:    def.UpdatedAt.Format(http.TimeFormat),
-		})
+		out = append(out, agentDefinitionToResponse(def))
+	}
+
+	w.Header().Set(headerContentType, mimeJSON)
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": out})
+}
+
+// GetAgentDefinition handles GET /api/v1/agents/definitions/{id}.
+func (h *AgentHandler) GetAgentDefinition(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	def, err := h.orchestrator.GetAgentDefinition(r.Context(), workspaceID, chi.URLParam(r, paramID))
+	if err != nil {
+		if errors.Is(err, agent.ErrAgentNotFound) {
+			writeError(w, http.StatusNotFound, codeNotFound, "agent definition not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to get agent definition")
+		return
+	}
+
+	w.Header().Set(headerContentType, mimeJSON)
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": agentDefinitionToResponse(def)})
+}
+
+func agentDefinitionToResponse(def *agent.Definition) agentDefinitionResponse {
+	return agentDefinitionResponse{
+		ID:           def.ID,
+		WorkspaceID:  def.WorkspaceID,
+		Name:         def.Name,
+		Description:  def.Description,
+		AgentType:    def.AgentType,
+		Objective:    def.Objective,
+		AllowedTools: def.AllowedTools,
+		Status:       def.Status,
+		CreatedAt:    def.CreatedAt.Format(http.TimeFormat),
+		UpdatedAt:    def.UpdatedAt.Format(http.TimeFormat),
+	}
+}
+
+type createAgentDefinitionRequest struct {
+	Name          string          `json:"name"`
+	Description   *string         `json:"description,omitempty"`
+	AgentType     string          `json:"agentType"`
+	Objective     json.RawMessage `json:"objective,omitempty"`
+	AllowedTools  []string        `json:"allowedTools,omitempty"`
+	Limits        json.RawMessage `json:"limits,omitempty"`
+	TriggerConfig json.RawMessage `json:"triggerConfig,omitempty"`
+	PolicySetID   *string         `json:"policySetId,omitempty"`
+}
+
+// CreateAgentDefinition handles POST /api/v1/agents/definitions.
+// Unknown allowedTools answer 400; tools the agent type needs but the
+// definition does not allow come back as warnings on the 201.
+func (h *AgentHandler) CreateAgentDefinition(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	var req createAgentDefinitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
+		return
+	}
+	fields := map[string]string{}
+	if strings.TrimSpace(req.Name) == "" {
+		fields["name"] = errFieldRequired
+	}
+	if strings.TrimSpace(req.AgentType) == "" {
+		fields["agentType"] = errFieldRequired
+	}
+	if len(fields) > 0 {
+		writeValidationError(w, fields)
+		return
+	}
+
+	def, warnings, err := h.orchestrator.CreateAgentDefinition(r.Context(), agent.CreateAgentDefinitionInput{
+		WorkspaceID:   workspaceID,
+		Name:          req.Name,
+		Description:   req.Description,
+		AgentType:     req.AgentType,
+		Objective:     req.Objective,
+		AllowedTools:  req.AllowedTools,
+		Limits:        req.Limits,
+		TriggerConfig: req.TriggerConfig,
+		PolicySetID:   req.PolicySetID,
+	})
+	switch {
+	case errors.Is(err, agent.ErrUnknownAllowedTool), errors.Is(err, agent.ErrInvalidAgentDefinition):
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	case errors.Is(err, agent.ErrAgentDefinitionExists):
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to create agent definition")
+		return
+	}
+
+	if warnings == nil {
+		warnings = []string{}
+	}
+	setLocation(w, agentDefinitionsCollection, def.ID)
+	w.Header().Set(headerContentType, mimeJSON)
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"data":     agentDefinitionToResponse(def),
+		"warnings": warnings,
+	})
+}
+
+type agentMetricsResponse struct {
+	AbstentionsByReason map[agent.AbstentionReason]int64 `json:"abstentionsByReason"`
+	AbstentionsTotal    int64                            `json:"abstentionsTotal"`
+}
+
+// GetAgentMetrics handles GET /api/v1/agents/metrics?agent_id=
+func (h *AgentHandler) GetAgentMetrics(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := h.orchestrator.CountAbstentionsByReason(r.Context(), workspaceID, r.URL.Query().Get("agent_id"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to get agent metrics")
+		return
+	}
+
+	out := agentMetricsResponse{AbstentionsByReason: counts}
+	for _, count := range counts {
+		out.AbstentionsTotal += count
 	}
 
 	w.Header().Set(headerContentType, mimeJSON)
@@ -268,25 +416,24 @@ func (h *AgentHandler) ListAgentDefinitions(w http.ResponseWriter, r *http.Reque
 
 // CancelAgentRun handles POST /api/v1/agents/runs/{id}/cancel
 func (h *AgentHandler) CancelAgentRun(w http.ResponseWriter, r *http.Request) {
-	workspaceID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || workspaceID == "" {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
 	runID := chi.URLParam(r, paramID)
 	if runID == "" {
-		writeError(w, http.StatusBadRequest, "run id is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "run id is required")
 		return
 	}
 
 	run, err := h.orchestrator.UpdateAgentRunStatus(r.Context(), workspaceID, runID, agent.StatusFailed)
 	if err != nil {
 		if errors.Is(err, agent.ErrAgentRunNotFound) {
-			writeError(w, http.StatusNotFound, errAgentRunNotFound)
+			writeError(w, http.StatusNotFound, codeAgentRunNotFound, errAgentRunNotFound)
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "failed to cancel agent run")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to cancel agent run")
 		return
 	}
 
@@ -295,6 +442,37 @@ func (h *AgentHandler) CancelAgentRun(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]any{"data": agentRunToResponse(run)})
 }
 
+// ExportAgentRun handles GET /api/v1/agents/runs/{id}/export
+// The body is the raw agent.RunExport bundle, served as a download, so it can
+// be loaded into a dev database with Orchestrator.ImportRun.
+func (h *AgentHandler) ExportAgentRun(w http.ResponseWriter, r *http.Request) {
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	runID := chi.URLParam(r, paramID)
+	if runID == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "run id is required")
+		return
+	}
+
+	bundle, err := h.orchestrator.ExportRun(r.Context(), workspaceID, runID)
+	if err != nil {
+		if errors.Is(err, agent.ErrAgentRunNotFound) {
+			writeError(w, http.StatusNotFound, codeAgentRunNotFound, errAgentRunNotFound)
+			return
+		}
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to export agent run")
+		return
+	}
+
+	w.Header().Set(headerContentType, mimeJSON)
+	w.Header().Set(headerContentDisposition, fmt.Sprintf(`attachment; filename="agent-run-%s.json"`, runID))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(bundle)
+}
+
 // Helper functions
 
 func agentRunToResponse(run *agent.Run) agentRunResponse {
@@ -330,6 +508,9 @@ func agentRunToResponse(run *agent.Run) agentRunResponse {
 	if meta.rejectionReason != "" {
 		resp.RejectionReason = &meta.rejectionReason
 	}
+	if conversationID := run.ConversationID(); conversationID != "" {
+		resp.ConversationID = &conversationID
+	}
 	if run.CompletedAt != nil {
 		completedAt := run.CompletedAt.Format(http.TimeFormat)
 		resp.CompletedAt = &completedAt
@@ -434,13 +615,15 @@ func firstNonEmptyString(values ...string) string {
 func (h *AgentHandler) handleTriggerError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, agent.ErrAgentNotFound):
-		writeError(w, http.StatusNotFound, "agent definition not found")
+		writeError(w, http.StatusNotFound, codeAgentDefinitionNotFound, "agent definition not found")
 	case errors.Is(err, agent.ErrAgentNotActive):
-		writeError(w, http.StatusBadRequest, "agent is not active")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "agent is not active")
 	case errors.Is(err, agent.ErrInvalidTriggerType):
-		writeError(w, http.StatusBadRequest, "invalid trigger type")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "invalid trigger type")
+	case errors.Is(err, agent.ErrCopilotUserRequired), errors.Is(err, agent.ErrCopilotConversationRequired):
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 	default:
-		writeError(w, http.StatusInternalServerError, "failed to trigger agent")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to trigger agent")
 	}
 }
 
@@ -459,6 +642,7 @@ type supportAgentRequest struct {
 	CustomerQuery string `json:"customer_query"`
 	Language      string `json:"language,omitempty"`
 	Priority      string `json:"priority,omitempty"`
+	ReplyToNoteID string `json:"reply_to_note_id,omitempty"`
 }
 
 type prospectingAgentRequest struct {
@@ -466,6 +650,11 @@ type prospectingAgentRequest struct {
 	Language string `json:"language,omitempty"`
 }
 
+type prospectingBatchRequest struct {
+	Limit    int    `json:"limit,omitempty"`
+	Language string `json:"language,omitempty"`
+}
+
 type kbAgentRequest struct {
 	CaseID   string `json:"case_id"`
 	Language string `json:"language,omitempty"`
@@ -488,12 +677,8 @@ type insightsAgentRequest struct {
 // buildSupportConfig validates and converts an HTTP request into a SupportAgentConfig.
 // Returns ("", nil) on validation error after writing the HTTP error response.
 func buildSupportConfig(w http.ResponseWriter, req supportAgentRequest, workspaceID string) (agents.SupportAgentConfig, bool) {
-	if req.CaseID == "" {
-		writeError(w, http.StatusBadRequest, "case_id is required")
-		return agents.SupportAgentConfig{}, false
-	}
-	if req.CustomerQuery == "" {
-		writeError(w, http.StatusBadRequest, "customer_query is required")
+	if fields := requireFields(map[string]string{"case_id": req.CaseID, "customer_query": req.CustomerQuery}); len(fields) > 0 {
+		writeValidationError(w, fields)
 		return agents.SupportAgentConfig{}, false
 	}
 	return agents.SupportAgentConfig{
@@ -502,21 +687,26 @@ func buildSupportConfig(w http.ResponseWriter, req supportAgentRequest, workspac
 		CustomerQuery: req.CustomerQuery,
 		Language:      req.Language,
 		Priority:      req.Priority,
+		ReplyToNoteID: req.ReplyToNoteID,
 	}, true
 }
 
 // TriggerSupportAgent handles POST /api/v1/agents/support/trigger
 // Traces: FR-230, FR-231
 func (h *SupportAgentHandler) TriggerSupportAgent(w http.ResponseWriter, r *http.Request) {
-	workspaceID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || workspaceID == "" {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	idem, ok := idempotentTriggerFromRequest(w, r, "agents.support.trigger")
+	if !ok {
 		return
 	}
 
 	var req supportAgentRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 
@@ -525,35 +715,35 @@ func (h *SupportAgentHandler) TriggerSupportAgent(w http.ResponseWriter, r *http
 		return
 	}
 
-	run, err := h.supportAgent.Run(r.Context(), config)
+	if !idem.reserve(w, r, h.supportAgent.Orchestrator(), workspaceID, writeAgentRunReplay) {
+		return
+	}
+	run, err := runIdempotent(idem, h.supportAgent.Run)(r.Context(), config)
 	if err != nil {
 		handleSupportRunError(w, err)
 		return
 	}
 
-	w.Header().Set(headerContentType, mimeJSON)
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(map[string]any{"data": agentRunToResponse(run)})
+	writeAgentRunData(w, http.StatusCreated, run)
 }
 
 func handleSupportRunError(w http.ResponseWriter, err error) {
 	if errors.Is(err, agents.ErrCaseIDRequired) {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 	if errors.Is(err, sql.ErrNoRows) {
-		writeError(w, http.StatusNotFound, "case not found")
+		writeError(w, http.StatusNotFound, codeCaseNotFound, "case not found")
 		return
 	}
-	writeError(w, http.StatusInternalServerError, "failed to run support agent")
+	writeError(w, http.StatusInternalServerError, codeInternal, "failed to run support agent")
 }
 
 // extractAgentContext pulls workspace and user IDs from the request context.
 // Returns ok=false and writes an error response when workspace is missing.
 func extractAgentContext(w http.ResponseWriter, r *http.Request) (workspaceID, userID string, ok bool) {
-	wid, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || wid == "" {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	wid, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return "", "", false
 	}
 	uid, _ := r.Context().Value(ctxkeys.UserID).(string)
@@ -564,7 +754,7 @@ func extractAgentContext(w http.ResponseWriter, r *http.Request) (workspaceID, u
 // Returns false and writes a 400 error response on decode failure.
 func decodeAgentRequest[T any](w http.ResponseWriter, r *http.Request, dst *T) bool {
 	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return false
 	}
 	return true
@@ -572,8 +762,15 @@ func decodeAgentRequest[T any](w http.ResponseWriter, r *http.Request, dst *T) b
 
 // writeAgentQueuedResponse writes a 201 Created JSON response for a queued agent run.
 func writeAgentQueuedResponse(w http.ResponseWriter, runID, agentName string) {
+	writeAgentQueuedResponseStatus(w, http.StatusCreated, runID, agentName)
+}
+
+func writeAgentQueuedResponseStatus(w http.ResponseWriter, status int, runID, agentName string) {
 	w.Header().Set(headerContentType, mimeJSON)
-	w.WriteHeader(http.StatusCreated)
+	if status == http.StatusCreated {
+		setLocation(w, agentRunsCollection, runID)
+	}
+	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(map[string]any{
 		"run_id": runID,
 		"status": "queued",
@@ -595,7 +792,7 @@ func NewProspectingAgentHandler(prospectingAgent *agents.ProspectingAgent) *Pros
 // buildProspectingConfig validates and converts an HTTP request into a ProspectingAgentConfig.
 func buildProspectingConfig(w http.ResponseWriter, req prospectingAgentRequest, workspaceID string) (agents.ProspectingAgentConfig, bool) {
 	if req.LeadID == "" {
-		writeError(w, http.StatusBadRequest, "lead_id is required")
+		writeValidationError(w, map[string]string{"lead_id": errFieldRequired})
 		return agents.ProspectingAgentConfig{}, false
 	}
 	language := req.Language
@@ -619,24 +816,83 @@ func withProspectingTriggeredBy(config agents.ProspectingAgentConfig, userID str
 
 // TriggerProspectingAgent handles POST /api/v1/agents/prospecting/trigger.
 func (h *ProspectingAgentHandler) TriggerProspectingAgent(w http.ResponseWriter, r *http.Request) {
+	idem, ok := idempotentTriggerFromRequest(w, r, "agents.prospecting.trigger")
+	if !ok {
+		return
+	}
 	config, ok := prepareTriggeredAgentConfig(w, r, buildProspectingConfig, withProspectingTriggeredBy)
 	if !ok {
 		return
 	}
-	runQueuedAgent(w, r, config, h.prospectingAgent.Run, handleProspectingRunError, "failed to run prospecting agent", "prospecting")
+	replay := func(w http.ResponseWriter, run *agent.Run) {
+		writeAgentQueuedResponseStatus(w, http.StatusOK, run.ID, "prospecting")
+	}
+	if !idem.reserve(w, r, h.prospectingAgent.Orchestrator(), config.WorkspaceID, replay) {
+		return
+	}
+	runQueuedAgent(w, r, config, runIdempotent(idem, h.prospectingAgent.Run), handleProspectingRunError, "failed to run prospecting agent", "prospecting")
+}
+
+// maxProspectingBatchLimit caps the leads one batch request prospects.
+const maxProspectingBatchLimit = 50
+
+// TriggerProspectingBatch handles POST /api/v1/agents/prospecting/batch.
+// It prospects up to limit new leads, highest score first, one run per lead.
+// A batch stopped by a failing run (e.g. a daily limit) still answers 201
+// with the runs made, and stopped_reason says why it stopped.
+func (h *ProspectingAgentHandler) TriggerProspectingBatch(w http.ResponseWriter, r *http.Request) {
+	workspaceID, userID, ok := extractAgentContext(w, r)
+	if !ok {
+		return
+	}
+	var req prospectingBatchRequest
+	if !decodeAgentRequest(w, r, &req) {
+		return
+	}
+	if req.Limit < 0 || req.Limit > maxProspectingBatchLimit {
+		writeValidationError(w, map[string]string{"limit": fmt.Sprintf("must be between 0 and %d", maxProspectingBatchLimit)})
+		return
+	}
+	config := agents.ProspectingBatchConfig{WorkspaceID: workspaceID, Limit: req.Limit, Language: req.Language}
+	if config.Language == "" {
+		config.Language = defaultAgentLanguage
+	}
+	if userID != "" {
+		config.TriggeredByUserID = &userID
+	}
+
+	runs, err := h.prospectingAgent.RunBatch(r.Context(), config)
+	if err != nil && len(runs) == 0 {
+		if handleProspectingRunError(w, err) {
+			return
+		}
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to run prospecting batch")
+		return
+	}
+	runIDs := make([]string, 0, len(runs))
+	for _, run := range runs {
+		runIDs = append(runIDs, run.ID)
+	}
+	resp := map[string]any{"run_ids": runIDs, "status": "queued", "agent": "prospecting"}
+	if err != nil {
+		resp["stopped_reason"] = err.Error()
+	}
+	w.Header().Set(headerContentType, mimeJSON)
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
 }
 
 func handleProspectingRunError(w http.ResponseWriter, err error) bool {
 	if errors.Is(err, agents.ErrLeadIDRequired) {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return true
 	}
 	if errors.Is(err, agents.ErrLeadNotFound) {
-		writeError(w, http.StatusNotFound, err.Error())
+		writeError(w, http.StatusNotFound, codeLeadNotFound, err.Error())
 		return true
 	}
 	if errors.Is(err, agents.ErrProspectingDailyLeadLimitExceeded) || errors.Is(err, agents.ErrProspectingDailyCostLimitExceeded) {
-		writeError(w, http.StatusTooManyRequests, err.Error())
+		writeError(w, http.StatusTooManyRequests, codeRateLimited, err.Error())
 		return true
 	}
 	return false
@@ -655,7 +911,7 @@ func NewKBAgentHandler(kbAgent *agents.KBAgent) *KBAgentHandler {
 
 func buildKBConfig(w http.ResponseWriter, req kbAgentRequest, workspaceID string) (agents.KBAgentConfig, bool) {
 	if req.CaseID == "" {
-		writeError(w, http.StatusBadRequest, "case_id is required")
+		writeValidationError(w, map[string]string{"case_id": errFieldRequired})
 		return agents.KBAgentConfig{}, false
 	}
 	language := req.Language
@@ -689,19 +945,19 @@ func (h *KBAgentHandler) TriggerKBAgent(w http.ResponseWriter, r *http.Request)
 
 func handleKBRunError(w http.ResponseWriter, err error) bool {
 	if errors.Is(err, agents.ErrKBCaseIDRequired) {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return true
 	}
 	if errors.Is(err, agents.ErrCaseNotFound) {
-		writeError(w, http.StatusNotFound, err.Error())
+		writeError(w, http.StatusNotFound, codeCaseNotFound, err.Error())
 		return true
 	}
 	if errors.Is(err, agents.ErrCaseNotResolved) {
-		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		writeError(w, http.StatusUnprocessableEntity, codeUnprocessableEntity, err.Error())
 		return true
 	}
 	if errors.Is(err, agents.ErrKBDailyLimitExceeded) {
-		writeError(w, http.StatusTooManyRequests, err.Error())
+		writeError(w, http.StatusTooManyRequests, codeRateLimited, err.Error())
 		return true
 	}
 	return false
@@ -719,7 +975,7 @@ func NewDealRiskAgentHandler(dealRiskAgent *agents.DealRiskAgent) *DealRiskAgent
 
 func buildDealRiskConfig(w http.ResponseWriter, req dealRiskAgentRequest, workspaceID string) (agents.DealRiskAgentConfig, bool) {
 	if req.DealID == "" {
-		writeError(w, http.StatusBadRequest, "deal_id is required")
+		writeValidationError(w, map[string]string{"deal_id": errFieldRequired})
 		return agents.DealRiskAgentConfig{}, false
 	}
 	language := req.Language
@@ -752,15 +1008,15 @@ func (h *DealRiskAgentHandler) TriggerDealRiskAgent(w http.ResponseWriter, r *ht
 
 func handleDealRiskRunError(w http.ResponseWriter, err error) bool {
 	if errors.Is(err, agents.ErrDealIDRequired) {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return true
 	}
 	if errors.Is(err, agents.ErrDealNotFound) {
-		writeError(w, http.StatusNotFound, err.Error())
+		writeError(w, http.StatusNotFound, codeDealNotFound, err.Error())
 		return true
 	}
 	if errors.Is(err, agents.ErrDealRiskDailyLimitExceeded) || errors.Is(err, agents.ErrDealRiskDailyCostLimitExceeded) {
-		writeError(w, http.StatusTooManyRequests, err.Error())
+		writeError(w, http.StatusTooManyRequests, codeRateLimited, err.Error())
 		return true
 	}
 	return false
@@ -797,10 +1053,7 @@ func NewInsightsAgentHandlerWithShadow(
 }
 
 func buildInsightsConfig(w http.ResponseWriter, req insightsAgentRequest, workspaceID string) (agents.InsightsAgentConfig, bool) {
-	if req.Query == "" {
-		writeError(w, http.StatusBadRequest, errQueryRequired)
-		return agents.InsightsAgentConfig{}, false
-	}
+	fields := requireFields(map[string]string{"query": req.Query})
 	language := req.Language
 	if language == "" {
 		language = defaultAgentLanguage
@@ -813,19 +1066,21 @@ func buildInsightsConfig(w http.ResponseWriter, req insightsAgentRequest, worksp
 	if req.DateFrom != "" {
 		t, err := parseDateTimeValue(req.DateFrom)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "date_from must be RFC3339")
-			return agents.InsightsAgentConfig{}, false
+			fields["date_from"] = "must be RFC3339"
 		}
 		config.DateFrom = t
 	}
 	if req.DateTo != "" {
 		t, err := parseDateTimeValue(req.DateTo)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "date_to must be RFC3339")
-			return agents.InsightsAgentConfig{}, false
+			fields["date_to"] = "must be RFC3339"
 		}
 		config.DateTo = t
 	}
+	if len(fields) > 0 {
+		writeValidationError(w, fields)
+		return agents.InsightsAgentConfig{}, false
+	}
 	return config, true
 }
 
@@ -868,6 +1123,7 @@ func (h *InsightsAgentHandler) TriggerInsightsAgent(w http.ResponseWriter, r *ht
 	enrichInsightsPrimaryResponse(response, rollout, run, req.ShadowMode, shadow)
 
 	w.Header().Set(headerContentType, mimeJSON)
+	setLocation(w, agentRunsCollection, run.ID)
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(response)
 }
@@ -932,7 +1188,7 @@ func runQueuedAgent[Config any](
 		if handled := handleErr(w, err); handled {
 			return
 		}
-		writeError(w, http.StatusInternalServerError, internalMsg)
+		writeError(w, http.StatusInternalServerError, codeInternal, internalMsg)
 		return
 	}
 	writeAgentQueuedResponse(w, runResult.ID, agentName)
@@ -950,7 +1206,7 @@ func (h *InsightsAgentHandler) runInsightsPrimary(
 	if handled := handleInsightsRunError(w, err); handled {
 		return nil, false
 	}
-	writeError(w, http.StatusInternalServerError, "failed to run insights agent")
+	writeError(w, http.StatusInternalServerError, codeInternal, "failed to run insights agent")
 	return nil, false
 }
 
@@ -997,12 +1253,12 @@ func (h *InsightsAgentHandler) triggerInsightsDeclarativePrimary(
 	rollout insightsRolloutConfig,
 ) {
 	if h == nil || h.shadow == nil {
-		writeError(w, http.StatusInternalServerError, "declarative insights rollout is not configured")
+		writeError(w, http.StatusInternalServerError, codeInternal, "declarative insights rollout is not configured")
 		return
 	}
 	execution, err := h.shadow.ExecutePrimary(r.Context(), config, rollout.AgentID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to run declarative insights workflow")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to run declarative insights workflow")
 		return
 	}
 	run := execution.WrapperRun
@@ -1018,17 +1274,18 @@ func (h *InsightsAgentHandler) triggerInsightsDeclarativePrimary(
 	}
 
 	w.Header().Set(headerContentType, mimeJSON)
+	setLocation(w, agentRunsCollection, run.ID)
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(response)
 }
 
 func handleInsightsRunError(w http.ResponseWriter, err error) bool {
 	if errors.Is(err, agents.ErrInsightsQueryRequired) {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return true
 	}
 	if errors.Is(err, agents.ErrInsightsDailyLimitExceeded) {
-		writeError(w, http.StatusTooManyRequests, err.Error())
+		writeError(w, http.StatusTooManyRequests, codeRateLimited, err.Error())
 		return true
 	}
 	return false
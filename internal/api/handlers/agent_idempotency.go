@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+)
+
+const (
+	headerIdempotencyKey     = "Idempotency-Key"
+	maxIdempotencyKeyLength  = 255
+	errIdempotencyKeyTooLong = "Idempotency-Key must be at most 255 characters"
+	errIdempotencyCheck      = "failed to check idempotency key"
+	// maxAgentTriggerBodyBytes caps the body buffered for the fingerprint,
+	// independently of the router-wide limit which may be disabled.
+	maxAgentTriggerBodyBytes = 1 << 20
+	errAgentTriggerTooLarge  = "request body too large"
+)
+
+// idempotentTrigger is the Idempotency-Key of an agent trigger request. A
+// nil *idempotentTrigger (no header sent) turns every method into a no-op.
+type idempotentTrigger struct {
+	orchestrator *agent.Orchestrator
+	workspaceID  string
+	key          string
+	fingerprint  string
+}
+
+// idempotentTriggerFromRequest reads the Idempotency-Key header and
+// fingerprints scope plus the raw body, leaving the body readable for the
+// handler. It writes a 400 and returns false for an unusable request.
+func idempotentTriggerFromRequest(w http.ResponseWriter, r *http.Request, scope string) (*idempotentTrigger, bool) {
+	key := strings.TrimSpace(r.Header.Get(headerIdempotencyKey))
+	if key == "" {
+		return nil, true
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		writeError(w, http.StatusBadRequest, codeBadRequest, errIdempotencyKeyTooLong)
+		return nil, false
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxAgentTriggerBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, codePayloadTooLarge, errAgentTriggerTooLarge)
+			return nil, false
+		}
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
+		return nil, false
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	sum := sha256.Sum256(append([]byte(scope+"\n"), body...))
+	return &idempotentTrigger{key: key, fingerprint: hex.EncodeToString(sum[:])}, true
+}
+
+// reserve claims the key before the agent runs. It returns false once it
+// has written the response itself: the original run replayed with 200, or a
+// 409 for a key reused with another payload or still in progress.
+func (t *idempotentTrigger) reserve(
+	w http.ResponseWriter,
+	r *http.Request,
+	orchestrator *agent.Orchestrator,
+	workspaceID string,
+	writeReplay func(http.ResponseWriter, *agent.Run),
+) bool {
+	if t == nil {
+		return true
+	}
+	run, err := orchestrator.ReserveIdempotencyKey(r.Context(), workspaceID, t.key, t.fingerprint)
+	switch {
+	case errors.Is(err, agent.ErrIdempotencyKeyConflict), errors.Is(err, agent.ErrIdempotencyKeyInProgress):
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
+		return false
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, errIdempotencyCheck)
+		return false
+	case run != nil:
+		writeReplay(w, run)
+		return false
+	}
+	t.orchestrator, t.workspaceID = orchestrator, workspaceID
+	return true
+}
+
+// finish binds the key to the new run, or frees it when the trigger failed
+// so the client can retry with the same key. It ignores ctx cancellation: a
+// client that disconnected would otherwise leave the key in progress until
+// it expires, and every retry would get a conflict.
+func (t *idempotentTrigger) finish(ctx context.Context, run *agent.Run, err error) {
+	if t == nil || t.orchestrator == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	if err != nil || run == nil {
+		_ = t.orchestrator.ReleaseIdempotencyKey(ctx, t.workspaceID, t.key)
+		return
+	}
+	_ = t.orchestrator.CompleteIdempotencyKey(ctx, t.workspaceID, t.key, run.ID)
+}
+
+// runIdempotent wraps an agent run func so its outcome settles t.
+func runIdempotent[Config any](
+	t *idempotentTrigger,
+	run func(context.Context, Config) (*agent.Run, error),
+) func(context.Context, Config) (*agent.Run, error) {
+	return func(ctx context.Context, config Config) (*agent.Run, error) {
+		result, err := run(ctx, config)
+		t.finish(ctx, result, err)
+		return result, err
+	}
+}
+
+// writeAgentRunData writes the {data: run} body of the trigger endpoints.
+func writeAgentRunData(w http.ResponseWriter, status int, run *agent.Run) {
+	w.Header().Set(headerContentType, mimeJSON)
+	if status == http.StatusCreated {
+		setLocation(w, agentRunsCollection, run.ID)
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": agentRunToResponse(run)})
+}
+
+func writeAgentRunReplay(w http.ResponseWriter, run *agent.Run) {
+	writeAgentRunData(w, http.StatusOK, run)
+}
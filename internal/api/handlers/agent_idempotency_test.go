@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+)
+
+func TestAgentHandler_TriggerAgent_IdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	insertTestAgentDef(t, db, "agent-ok", wsID)
+	h := NewAgentHandler(agent.NewOrchestrator(db))
+
+	trigger := func(key string, body map[string]any) *httptest.ResponseRecorder {
+		t.Helper()
+		raw, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/agents/trigger", bytes.NewReader(raw))
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set(headerIdempotencyKey, key)
+		}
+		rr := httptest.NewRecorder()
+		h.TriggerAgent(rr, req)
+		return rr
+	}
+	runID := func(rr *httptest.ResponseRecorder) string {
+		t.Helper()
+		var resp struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil || resp.Data.ID == "" {
+			t.Fatalf("decode run response (err = %v)", err)
+		}
+		return resp.Data.ID
+	}
+	body := map[string]any{"agent_id": "agent-ok", "trigger_type": "manual"}
+
+	first := trigger("retry-1", body)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("first trigger expected 201, got %d: %s", first.Code, first.Body.String())
+	}
+	firstID := runID(first)
+
+	repeat := trigger("retry-1", body)
+	if repeat.Code != http.StatusOK {
+		t.Fatalf("repeated trigger expected 200, got %d: %s", repeat.Code, repeat.Body.String())
+	}
+	if got := runID(repeat); got != firstID {
+		t.Fatalf("repeated trigger returned run %s, want %s", got, firstID)
+	}
+
+	conflict := trigger("retry-1", map[string]any{"agent_id": "agent-ok", "trigger_type": "manual", "inputs": map[string]any{"x": 1}})
+	if conflict.Code != http.StatusConflict {
+		t.Fatalf("different payload expected 409, got %d: %s", conflict.Code, conflict.Body.String())
+	}
+
+	if rr := trigger(strings.Repeat("k", maxIdempotencyKeyLength+1), body); rr.Code != http.StatusBadRequest {
+		t.Fatalf("oversized key expected 400, got %d", rr.Code)
+	}
+	oversized := map[string]any{"agent_id": "agent-ok", "trigger_type": "manual", "inputs": map[string]any{"x": strings.Repeat("a", maxAgentTriggerBodyBytes)}}
+	if rr := trigger("retry-2", oversized); rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized body expected 413, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var runs int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM agent_run WHERE workspace_id = ?`, wsID).Scan(&runs); err != nil {
+		t.Fatalf("count runs: %v", err)
+	}
+	if runs != 1 {
+		t.Fatalf("expected 1 agent run, got %d", runs)
+	}
+
+	if rr := trigger("", body); rr.Code != http.StatusCreated || runID(rr) == firstID {
+		t.Fatalf("trigger without key expected a new run, got %d", rr.Code)
+	}
+}
+
+func TestIdempotentTrigger_FinishReleasesKeyAfterCancel(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	orchestrator := agent.NewOrchestrator(db)
+	trigger := &idempotentTrigger{key: "retry-cancelled", fingerprint: "fp"}
+
+	req := httptest.NewRequest(http.MethodPost, "/agents/trigger", nil)
+	if !trigger.reserve(httptest.NewRecorder(), req, orchestrator, wsID, writeAgentRunReplay) {
+		t.Fatal("reserve failed")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	trigger.finish(ctx, nil, context.Canceled)
+
+	if _, err := orchestrator.ReserveIdempotencyKey(context.Background(), wsID, trigger.key, trigger.fingerprint); errors.Is(err, agent.ErrIdempotencyKeyInProgress) {
+		t.Fatal("key still in progress after the cancelled trigger was finished")
+	} else if err != nil {
+		t.Fatalf("ReserveIdempotencyKey: %v", err)
+	}
+}
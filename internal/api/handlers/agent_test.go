@@ -7,8 +7,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -74,6 +76,8 @@ func (m *mockKBToolExecutorHandler) Execute(_ context.Context, _ json.RawMessage
 	return m.out, nil
 }
 
+func (*mockKBToolExecutorHandler) ParamsSchema() json.RawMessage { return nil }
+
 type agentsTestDealGetter struct {
 	deal *crm.Deal
 	err  error
@@ -146,7 +150,7 @@ func newTestProspectingAgentHandler(t *testing.T, db *sql.DB, wsID, ownerID stri
 	if regErr := reg.Register(tool.BuiltinGetAccount, tool.NewGetAccountExecutor(accountSvc)); regErr != nil {
 		t.Fatalf("register get_account executor: %v", regErr)
 	}
-	if regErr := reg.Register(tool.BuiltinCreateTask, tool.NewCreateTaskExecutor(db)); regErr != nil {
+	if regErr := reg.Register(tool.BuiltinCreateTask, tool.NewCreateTaskExecutor(crm.NewActivityService(db))); regErr != nil {
 		t.Fatalf("register create_task executor: %v", regErr)
 	}
 
@@ -232,7 +236,7 @@ func newTestDealRiskAgentHandler(
 	if regErr := reg.Register(tool.BuiltinGetAccount, &agentsTestAccountToolExecutor{getter: accountGetter}); regErr != nil {
 		t.Fatalf("register get_account executor: %v", regErr)
 	}
-	if regErr := reg.Register(tool.BuiltinCreateTask, tool.NewCreateTaskExecutor(db)); regErr != nil {
+	if regErr := reg.Register(tool.BuiltinCreateTask, tool.NewCreateTaskExecutor(crm.NewActivityService(db))); regErr != nil {
 		t.Fatalf("register create_task executor: %v", regErr)
 	}
 
@@ -257,6 +261,8 @@ func (m *agentsTestDealToolExecutor) Execute(ctx context.Context, params json.Ra
 	return raw, nil
 }
 
+func (*agentsTestDealToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
 type agentsTestAccountToolExecutor struct{ getter agents.AccountGetter }
 
 func (m *agentsTestAccountToolExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
@@ -274,6 +280,8 @@ func (m *agentsTestAccountToolExecutor) Execute(ctx context.Context, params json
 	return raw, nil
 }
 
+func (*agentsTestAccountToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
 func newTestInsightsAgentHandlerWithShadow(t *testing.T, db *sql.DB, wsID string) *InsightsAgentHandler {
 	t.Helper()
 	runnerRegistry := agent.NewRunnerRegistry()
@@ -391,8 +399,9 @@ func TestAgentHandler_TriggerAgent_MissingAgentID(t *testing.T) {
 	h.TriggerAgent(rr, req)
 
 	if rr.Code != http.StatusBadRequest {
-		t.Errorf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
 	}
+	assertValidationFields(t, rr, map[string]string{"agent_id": "is required"})
 }
 
 // TestAgentHandler_TriggerAgent_AgentNotFound returns 404 for nonexistent agent.
@@ -439,7 +448,18 @@ func TestAgentHandler_TriggerAgent_Success(t *testing.T) {
 	h.TriggerAgent(rr, req)
 
 	if rr.Code != http.StatusCreated {
-		t.Errorf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode run: %v", err)
+	}
+	if got := rr.Header().Get("Location"); got != "/api/v1/agents/runs/"+resp.Data.ID {
+		t.Errorf("Location = %q; want /api/v1/agents/runs/%s", got, resp.Data.ID)
 	}
 }
 
@@ -534,6 +554,66 @@ func TestAgentHandler_ListAgentRuns_Success(t *testing.T) {
 	}
 }
 
+func TestAgentHandler_ListAgentRuns_CursorPagination(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	h := NewAgentHandler(agent.NewOrchestrator(db))
+
+	if _, err := db.Exec(`
+		INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		VALUES ('agent-cursor', ?, 'cursor-agent', 'support', 'active')
+	`, wsID); err != nil {
+		t.Fatalf("insert agent_definition: %v", err)
+	}
+	// Two runs share a created_at so the id tie-break is exercised.
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	createdAt := []time.Time{base, base, base.Add(time.Second), base.Add(2 * time.Second)}
+	for i, ts := range createdAt {
+		if _, err := db.Exec(`
+			INSERT INTO agent_run (id, workspace_id, agent_definition_id, trigger_type, status, started_at, created_at)
+			VALUES (?, ?, 'agent-cursor', 'manual', 'success', ?, ?)
+		`, fmt.Sprintf("run-cursor-%d", i), wsID, ts, ts); err != nil {
+			t.Fatalf("insert agent_run %d: %v", i, err)
+		}
+	}
+
+	var got []string
+	cursor := ""
+	for page := 0; page < 5; page++ {
+		req := httptest.NewRequest(http.MethodGet, "/agents/runs?limit=3&cursor="+cursor, nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+		h.ListAgentRuns(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+		var resp struct {
+			Data []agentRunResponse `json:"data"`
+			Meta Meta               `json:"meta"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if resp.Meta.Total != len(createdAt) {
+			t.Fatalf("meta.total = %d, want %d", resp.Meta.Total, len(createdAt))
+		}
+		for _, run := range resp.Data {
+			got = append(got, run.ID)
+		}
+		if resp.Meta.NextCursor == "" {
+			break
+		}
+		cursor = resp.Meta.NextCursor
+	}
+
+	want := []string{"run-cursor-3", "run-cursor-2", "run-cursor-1", "run-cursor-0"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("runs = %v, want %v", got, want)
+	}
+}
+
 func TestAgentHandler_ListAgentRuns_FiltersByEntityAndExposesContext(t *testing.T) {
 	t.Parallel()
 
@@ -609,6 +689,57 @@ func TestAgentHandler_ListAgentRuns_FiltersByEntityAndExposesContext(t *testing.
 	}
 }
 
+func TestAgentHandler_ListAgentRuns_FiltersByStatusAndAgent(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	if _, err := db.Exec(`
+		INSERT INTO agent_definition (id, workspace_id, name, agent_type, status) VALUES
+		('agent-a', ?, 'Agent A', 'support', 'active'),
+		('agent-b', ?, 'Agent B', 'prospecting', 'active')
+	`, wsID, wsID); err != nil {
+		t.Fatalf("insert agent definitions: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO agent_run (id, workspace_id, agent_definition_id, trigger_type, status, started_at) VALUES
+		('run-a-failed', ?, 'agent-a', 'manual', 'failed', datetime('now')),
+		('run-a-success', ?, 'agent-a', 'manual', 'success', datetime('now')),
+		('run-b-failed', ?, 'agent-b', 'manual', 'failed', datetime('now'))
+	`, wsID, wsID, wsID); err != nil {
+		t.Fatalf("insert agent runs: %v", err)
+	}
+	h := NewAgentHandler(agent.NewOrchestrator(db))
+
+	req := httptest.NewRequest(http.MethodGet, "/agents/runs?status=failed&agent_definition_id=agent-a&trigger_type=manual", nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	h.ListAgentRuns(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data []agentRunResponse `json:"data"`
+		Meta Meta               `json:"meta"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Meta.Total != 1 || len(resp.Data) != 1 || resp.Data[0].ID != "run-a-failed" {
+		t.Fatalf("expected only run-a-failed with total 1, got %s", rr.Body.String())
+	}
+
+	for _, query := range []string{"status=exploded", "trigger_type=webhook"} {
+		req := httptest.NewRequest(http.MethodGet, "/agents/runs?"+query, nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+		h.ListAgentRuns(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d: %s", query, rr.Code, rr.Body.String())
+		}
+	}
+}
+
 // TestAgentHandler_ListAgentDefinitions_MissingWorkspace returns 401.
 // Traces: FR-230
 func TestAgentHandler_ListAgentDefinitions_MissingWorkspace(t *testing.T) {
@@ -693,6 +824,84 @@ func TestAgentHandler_CancelAgentRun_NotFound(t *testing.T) {
 	}
 }
 
+// TestAgentHandler_ExportAgentRun_NotFound returns 404.
+// Traces: FR-230
+func TestAgentHandler_ExportAgentRun_NotFound(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	orch := agent.NewOrchestrator(db)
+	h := NewAgentHandler(orch)
+
+	r := chi.NewRouter()
+	r.Get("/agents/runs/{id}/export", h.ExportAgentRun)
+
+	req := httptest.NewRequest(http.MethodGet, "/agents/runs/no-run/export", nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestAgentHandler_CreateAgentDefinition_UnknownTool(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	orch := agent.NewOrchestrator(db)
+	orch.SetToolCatalog(tool.NewToolRegistry(db))
+	h := NewAgentHandler(orch)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/agents/definitions", strings.NewReader(body))
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+		h.CreateAgentDefinition(rr, req)
+		return rr
+	}
+
+	rr := post(`{"name":"Support","agentType":"support","allowedTools":["create_task","bogus_tool"]}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "bogus_tool") {
+		t.Errorf("error does not name the unknown tool: %s", rr.Body.String())
+	}
+
+	rr = post(`{"name":"Support","agentType":"support","allowedTools":["create_task","search_knowledge"]}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var created struct {
+		Data agentDefinitionResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	location := rr.Header().Get("Location")
+	if location != "/api/v1/agents/definitions/"+created.Data.ID {
+		t.Errorf("Location = %q; want the created definition", location)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/agents/definitions/{id}", h.GetAgentDefinition)
+	req := httptest.NewRequest(http.MethodGet, location, nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	got := httptest.NewRecorder()
+	r.ServeHTTP(got, req)
+	if got.Code != http.StatusOK || !strings.Contains(got.Body.String(), created.Data.ID) {
+		t.Errorf("GET Location = %d: %s", got.Code, got.Body.String())
+	}
+
+	if rr = post(`{"name":"Support","agentType":"support"}`); rr.Code != http.StatusConflict {
+		t.Errorf("duplicate name: expected 409, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
 // TestAgentHandler_TriggerAgent_InvalidJSON returns 400.
 // Traces: FR-230
 func TestAgentHandler_TriggerAgent_InvalidJSON(t *testing.T) {
@@ -816,6 +1025,107 @@ func TestAgentHandler_GetAgentRun_Success(t *testing.T) {
 	}
 }
 
+func TestAgentHandler_GetAgentMetrics_CountsAbstentionsByReason(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	insertTestAgentDef(t, db, "agent-metrics", wsID)
+
+	ctx := context.Background()
+	orch := agent.NewOrchestrator(db)
+	run, err := orch.TriggerAgent(ctx, agent.TriggerAgentInput{
+		AgentID:     "agent-metrics",
+		WorkspaceID: wsID,
+		TriggerType: agent.TriggerTypeManual,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+	if _, err = orch.UpdateAgentRun(ctx, wsID, run.ID, agent.RunUpdates{
+		Status:           agent.StatusAbstained,
+		AbstentionReason: agent.AbstentionLowConfidence.Ptr(),
+		Completed:        true,
+	}); err != nil {
+		t.Fatalf("UpdateAgentRun: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/agents/metrics?agent_id=agent-metrics", nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	NewAgentHandler(orch).GetAgentMetrics(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data struct {
+			AbstentionsByReason map[string]int64 `json:"abstentionsByReason"`
+			AbstentionsTotal    int64            `json:"abstentionsTotal"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Data.AbstentionsTotal != 1 || resp.Data.AbstentionsByReason["low_confidence"] != 1 {
+		t.Fatalf("metrics = %+v", resp.Data)
+	}
+	if n, ok := resp.Data.AbstentionsByReason["policy_block"]; !ok || n != 0 {
+		t.Fatalf("policy_block = %d (present=%v), want 0", n, ok)
+	}
+}
+
+func TestAgentHandler_GetAgentRun_PollingWithETag(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	insertTestAgentDef(t, db, "agent-etag", wsID)
+
+	orch := agent.NewOrchestrator(db)
+	run, err := orch.TriggerAgent(context.Background(), agent.TriggerAgentInput{
+		AgentID:     "agent-etag",
+		WorkspaceID: wsID,
+		TriggerType: agent.TriggerTypeManual,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/agents/runs/{id}", NewAgentHandler(orch).GetAgentRun)
+	poll := func(etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/agents/runs/"+run.ID, nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	first := poll("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("first poll: status=%d etag=%q", first.Code, etag)
+	}
+	if again := poll(etag); again.Code != http.StatusNotModified || again.Body.Len() != 0 {
+		t.Fatalf("unchanged poll: status=%d body=%q; want empty 304", again.Code, again.Body.String())
+	}
+
+	if _, err = orch.UpdateAgentRunStatus(context.Background(), wsID, run.ID, agent.StatusSuccess); err != nil {
+		t.Fatalf("UpdateAgentRunStatus: %v", err)
+	}
+	changed := poll(etag)
+	if changed.Code != http.StatusOK {
+		t.Fatalf("poll after completion: status=%d; want 200", changed.Code)
+	}
+	if changed.Header().Get("ETag") == etag {
+		t.Fatal("ETag unchanged after the run completed")
+	}
+}
+
 // TestAgentHandler_GetAgentRun_WithCompletedAt verifies agentRunToResponse covers the non-nil CompletedAt branch.
 func TestAgentHandler_GetAgentRun_WithCompletedAt(t *testing.T) {
 	t.Parallel()
@@ -984,6 +1294,50 @@ func TestProspectingAgentHandler_TriggerProspecting_200(t *testing.T) {
 	}
 }
 
+func TestProspectingAgentHandler_TriggerProspectingBatch(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createUser(t, db, wsID)
+	h, leadID := newTestProspectingAgentHandler(t, db, wsID, ownerID)
+
+	trigger := func(body map[string]any) *httptest.ResponseRecorder {
+		raw, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/agents/prospecting/batch", bytes.NewReader(raw))
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		h.TriggerProspectingBatch(rr, req)
+		return rr
+	}
+
+	if rr := trigger(map[string]any{"limit": maxProspectingBatchLimit + 1}); rr.Code != http.StatusBadRequest {
+		t.Fatalf("oversized limit expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr := trigger(map[string]any{"limit": 5})
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		RunIDs []string `json:"run_ids"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.RunIDs) != 1 {
+		t.Fatalf("expected one run for the single new lead, got %v", resp.RunIDs)
+	}
+	var triggerContext string
+	if err := db.QueryRow(`SELECT trigger_context FROM agent_run WHERE id = ?`, resp.RunIDs[0]).Scan(&triggerContext); err != nil {
+		t.Fatalf("load run: %v", err)
+	}
+	if !strings.Contains(triggerContext, leadID) {
+		t.Fatalf("run trigger context %s does not name lead %s", triggerContext, leadID)
+	}
+}
+
 func TestProspectingAgentHandler_TriggerProspecting_MissingLeadID(t *testing.T) {
 	t.Parallel()
 
@@ -2124,6 +2478,7 @@ func TestInsightsAgentHandler_TriggerInsights_InvalidDateFrom(t *testing.T) {
 	if rr.Code != http.StatusBadRequest {
 		t.Fatalf("expected 400 for bad date_from, got %d: %s", rr.Code, rr.Body.String())
 	}
+	assertValidationFields(t, rr, map[string]string{"date_from": "must be RFC3339"})
 }
 
 // TestInsightsAgentHandler_TriggerInsights_InvalidDateTo verifies date_to parsing error → 400.
@@ -2208,6 +2563,9 @@ func TestSupportAgentHandler_TriggerSupportAgent_CaseNotFound(t *testing.T) {
 	insertTestAgentDef(t, db, "support-agent", wsID)
 	orch := agent.NewOrchestrator(db)
 	reg := tool.NewToolRegistry(db)
+	if err := reg.Register(tool.BuiltinGetCase, tool.NewGetCaseExecutor(crm.NewCaseService(db))); err != nil {
+		t.Fatalf("register %s: %v", tool.BuiltinGetCase, err)
+	}
 	supportAgent := agents.NewSupportAgentWithDB(orch, reg, &mockKnowledgeSearchHandler{}, db)
 	h := NewSupportAgentHandler(supportAgent)
 
@@ -2324,6 +2682,9 @@ func TestSupportAgentHandler_TriggerSupportAgent_201(t *testing.T) {
 			t.Fatalf("register %s: %v", name, regErr)
 		}
 	}
+	if regErr := reg.Register(tool.BuiltinGetCase, tool.NewGetCaseExecutor(crm.NewCaseService(db))); regErr != nil {
+		t.Fatalf("register %s: %v", tool.BuiltinGetCase, regErr)
+	}
 	sa := agents.NewSupportAgentWithDB(orch, reg, &mockKnowledgeSearchHandler{}, db)
 	h := NewSupportAgentHandler(sa)
 
@@ -2376,15 +2737,15 @@ func TestAgentHandlerHelperCoverage(t *testing.T) {
 	}
 
 	req := httptest.NewRequest(http.MethodGet, "/agents/runs?limit=10&offset=5", nil)
-	limit, offset := parsePageParams(req)
+	limit, offset := PaginationFromRequest(req)
 	if limit != 10 || offset != 5 {
-		t.Fatalf("parsePageParams() = (%d,%d)", limit, offset)
+		t.Fatalf("PaginationFromRequest() = (%d,%d)", limit, offset)
 	}
 
 	defaultReq := httptest.NewRequest(http.MethodGet, "/agents/runs?limit=-1&offset=bad", nil)
-	limit, offset = parsePageParams(defaultReq)
+	limit, offset = PaginationFromRequest(defaultReq)
 	if limit != 25 || offset != 0 {
-		t.Fatalf("parsePageParams(default) = (%d,%d)", limit, offset)
+		t.Fatalf("PaginationFromRequest(default) = (%d,%d)", limit, offset)
 	}
 
 	startedAt := time.Now().UTC()
@@ -39,13 +39,13 @@ type approvalResponse struct {
 func (h *ApprovalHandler) ListPendingApprovals(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(ctxkeys.UserID).(string)
 	if !ok || userID == "" {
-		writeError(w, http.StatusUnauthorized, "missing user context")
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "missing user context")
 		return
 	}
 
 	items, err := h.service.GetPendingApprovals(r.Context(), userID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to list approvals")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to list approvals")
 		return
 	}
 
@@ -73,19 +73,19 @@ func (h *ApprovalHandler) ListPendingApprovals(w http.ResponseWriter, r *http.Re
 func (h *ApprovalHandler) DecideApproval(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(ctxkeys.UserID).(string)
 	if !ok || userID == "" {
-		writeError(w, http.StatusUnauthorized, "missing user context")
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "missing user context")
 		return
 	}
 
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, "approval id is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "approval id is required")
 		return
 	}
 
 	var req decideApprovalRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 
@@ -100,16 +100,16 @@ func (h *ApprovalHandler) DecideApproval(w http.ResponseWriter, r *http.Request)
 func (h *ApprovalHandler) handleDecisionError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, policy.ErrInvalidDecision):
-		writeError(w, http.StatusBadRequest, "invalid decision")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "invalid decision")
 	case errors.Is(err, policy.ErrApprovalNotFound):
-		writeError(w, http.StatusNotFound, "approval request not found")
+		writeError(w, http.StatusNotFound, codeApprovalNotFound, "approval request not found")
 	case errors.Is(err, policy.ErrApprovalForbidden):
-		writeError(w, http.StatusForbidden, "approval request is not assigned to current user")
+		writeError(w, http.StatusForbidden, codeForbidden, "approval request is not assigned to current user")
 	case errors.Is(err, policy.ErrApprovalExpired):
-		writeError(w, http.StatusConflict, "approval request is expired")
+		writeError(w, http.StatusConflict, codeConflict, "approval request is expired")
 	case errors.Is(err, policy.ErrApprovalAlreadyClosed):
-		writeError(w, http.StatusConflict, "approval request is already decided")
+		writeError(w, http.StatusConflict, codeConflict, "approval request is already decided")
 	default:
-		writeError(w, http.StatusInternalServerError, "failed to decide approval request")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to decide approval request")
 	}
 }
@@ -37,7 +37,7 @@ func (h *AttachmentHandler) CreateAttachment(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	if !isAttachmentRequestValid(req) {
-		writeError(w, http.StatusBadRequest, "entityType, entityId, uploaderId, filename and storagePath are required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "entityType, entityId, uploaderId, filename and storagePath are required")
 		return
 	}
 	out, svcErr := h.service.Create(r.Context(), crm.CreateAttachmentInput{
@@ -53,9 +53,10 @@ func (h *AttachmentHandler) CreateAttachment(w http.ResponseWriter, r *http.Requ
 		Metadata:    req.Metadata,
 	})
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create attachment: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create attachment: %v", svcErr))
 		return
 	}
+	setLocation(w, "attachments", out.ID)
 	w.WriteHeader(http.StatusCreated)
 	if !writeJSONOr500(w, out) {
 		return
@@ -69,7 +70,7 @@ func (h *AttachmentHandler) GetAttachment(w http.ResponseWriter, r *http.Request
 	}
 	id := chi.URLParam(r, paramID)
 	out, svcErr := h.service.Get(r.Context(), wsID, id)
-	if handleGetError(w, svcErr, "attachment not found", "failed to get attachment: %v") {
+	if handleGetError(w, svcErr, codeAttachmentNotFound, "attachment not found", "failed to get attachment: %v") {
 		return
 	}
 	if !writeJSONOr500(w, out) {
@@ -85,16 +86,16 @@ func (h *AttachmentHandler) ListAttachments(w http.ResponseWriter, r *http.Reque
 	page := parsePaginationParams(r)
 	items, total, svcErr := h.service.List(r.Context(), wsID, crm.ListAttachmentsInput{Limit: page.Limit, Offset: page.Offset})
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list attachments: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list attachments: %v", svcErr))
 		return
 	}
-	if !writePaginatedOr500(w, items, total, page) {
+	if !writePaginated(w, items, total, page.Limit, page.Offset) {
 		return
 	}
 }
 
 func (h *AttachmentHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
-	handleDeleteWithNotFound(w, r, "attachment id is required", sql.ErrNoRows, "attachment not found", "failed to delete attachment: %v", h.service.Delete)
+	handleDeleteWithNotFound(w, r, "attachment id is required", sql.ErrNoRows, codeAttachmentNotFound, "attachment not found", "failed to delete attachment: %v", h.service.Delete)
 }
 
 // isAttachmentRequestValid checks required fields for CreateAttachment.
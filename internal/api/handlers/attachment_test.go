@@ -21,6 +21,7 @@ func TestAttachmentHandler_CreateAttachment_MissingWorkspace_Returns400(t *testi
 
 	body, _ := json.Marshal(map[string]any{"filename": "f.txt"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/attachments", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 
 	rr := httptest.NewRecorder()
 	h.CreateAttachment(rr, req)
@@ -135,6 +136,7 @@ func TestAttachmentHandler_GetAttachment_MissingWorkspace_Returns400(t *testing.
 	h := NewAttachmentHandler(crm.NewAttachmentService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/attachments/a1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "a1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -154,6 +156,7 @@ func TestAttachmentHandler_ListAttachments_MissingWorkspace_Returns400(t *testin
 	h := NewAttachmentHandler(crm.NewAttachmentService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/attachments", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	h.ListAttachments(rr, req)
 
@@ -195,6 +198,7 @@ func TestAttachmentHandler_DeleteAttachment_MissingWorkspace_Returns400(t *testi
 	h := NewAttachmentHandler(crm.NewAttachmentService(db))
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/attachments/a1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "a1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -5,8 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
@@ -19,13 +19,16 @@ type AuditHandler struct {
 }
 
 const (
-	errAuditEventIDRequired = "audit event id is required"
-	errFailedToQueryAudit   = "failed to query audit events: %v"
-	errFailedToGetAudit     = "failed to get audit event: %v"
-	errFormatMustBeCSV      = "format must be csv"
-	queryParamAction        = "action"
-	queryParamFormat        = "format"
-	formatCSV               = "csv"
+	errAuditEventIDRequired   = "audit event id is required"
+	errFailedToQueryAudit     = "failed to query audit events: %v"
+	errFailedToGetAudit       = "failed to get audit event: %v"
+	errUnsupportedAuditFormat = "format must be csv or ndjson"
+	errFailedToGetAuditStats  = "failed to get audit stats"
+	errInvalidAuditStatsFrom  = "from must be an RFC3339 timestamp"
+	errInvalidAuditStatsTo    = "to must be an RFC3339 timestamp"
+	queryParamAction          = "action"
+	queryParamFormat          = "format"
+	mimeNDJSON                = "application/x-ndjson"
 )
 
 func NewAuditHandler(auditService *domainaudit.AuditService) *AuditHandler {
@@ -53,11 +56,11 @@ func (h *AuditHandler) Query(w http.ResponseWriter, r *http.Request) {
 		Offset:      page.Offset,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(errFailedToQueryAudit, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(errFailedToQueryAudit, err))
 		return
 	}
 
-	_ = writePaginatedOr500(w, items, len(items), page)
+	_ = writePaginated(w, items, len(items), page.Limit, page.Offset)
 }
 
 // GetByID handles GET /api/v1/audit/events/{id}.
@@ -69,55 +72,129 @@ func (h *AuditHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errAuditEventIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errAuditEventIDRequired)
 		return
 	}
 
 	event, err := h.auditService.GetByID(r.Context(), id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			writeError(w, http.StatusNotFound, errAuditEventNotFound)
+			writeError(w, http.StatusNotFound, codeAuditEventNotFound, errAuditEventNotFound)
 			return
 		}
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(errFailedToGetAudit, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(errFailedToGetAudit, err))
 		return
 	}
 	if event.WorkspaceID != wsID {
-		writeError(w, http.StatusNotFound, errAuditEventNotFound)
+		writeError(w, http.StatusNotFound, codeAuditEventNotFound, errAuditEventNotFound)
 		return
 	}
 
 	_ = writeJSONOr500(w, event)
 }
 
-// Export handles POST /api/v1/audit/export.
-// Task 4.6 — FR-071 CSV export.
+// Export handles GET (and legacy POST) /api/v1/audit/export.
+// Task 4.6 — FR-071 streaming CSV/NDJSON export.
 func (h *AuditHandler) Export(w http.ResponseWriter, r *http.Request) {
 	wsID, ok := requireWorkspaceID(w, r)
 	if !ok {
 		return
 	}
-	if r.URL.Query().Get(queryParamFormat) != formatCSV {
-		writeError(w, http.StatusBadRequest, errFormatMustBeCSV)
+	format := domainaudit.ExportFormat(r.URL.Query().Get(queryParamFormat))
+	contentType, ok := auditExportContentTypes[format]
+	if !ok {
+		writeError(w, http.StatusBadRequest, codeBadRequest, errUnsupportedAuditFormat)
 		return
 	}
 
-	reader, err := h.auditService.Export(r.Context(), domainaudit.ExportInput{
-		WorkspaceID: wsID,
-		ActorID:     r.URL.Query().Get("actor_id"),
-		EntityType:  r.URL.Query().Get(paramEntityType),
-		Action:      r.URL.Query().Get(queryParamAction),
-		Outcome:     r.URL.Query().Get("outcome"),
-		DateFrom:    r.URL.Query().Get("date_from"),
-		DateTo:      r.URL.Query().Get("date_to"),
-	})
+	q := r.URL.Query()
+	filter := domainaudit.ExportFilter{
+		ActorID:    q.Get("actor_id"),
+		EntityType: q.Get(paramEntityType),
+		Action:     q.Get(queryParamAction),
+		Outcome:    q.Get("outcome"),
+		DateFrom:   firstNonEmpty(q.Get("from"), q.Get("date_from")),
+		DateTo:     firstNonEmpty(q.Get("to"), q.Get("date_to")),
+	}
+
+	// Headers are committed lazily so a failure before the first row can
+	// still be reported as a regular JSON error.
+	sw := &auditExportWriter{
+		w:           w,
+		contentType: contentType,
+		filename:    "audit_events." + string(format),
+	}
+	if err := h.auditService.Export(r.Context(), wsID, filter, format, sw); err != nil && !sw.started {
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToExportAudit)
+	}
+}
+
+// Stats handles GET /api/v1/audit/stats.
+// Counts events by action, outcome and actor type plus the top actors within
+// ?from=&to= (RFC3339), defaulting to the last 30 days.
+func (h *AuditHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	from, err := parseOptionalRFC3339(r.URL.Query().Get("from"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, errInvalidAuditStatsFrom)
+		return
+	}
+	to, err := parseOptionalRFC3339(r.URL.Query().Get("to"))
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToExportAudit)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errInvalidAuditStatsTo)
 		return
 	}
 
-	w.Header().Set(headerContentType, mimeCSV)
-	w.Header().Set(headerContentDisposition, `attachment; filename="audit_events.csv"`)
-	w.WriteHeader(http.StatusOK)
-	_, _ = io.Copy(w, reader)
+	stats, err := h.auditService.Stats(r.Context(), wsID, from, to)
+	if err != nil {
+		if errors.Is(err, domainaudit.ErrInvalidStatsWindow) {
+			writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+			return
+		}
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToGetAuditStats)
+		return
+	}
+
+	_ = writeJSONOr500(w, map[string]any{"data": stats})
+}
+
+func parseOptionalRFC3339(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, raw)
+}
+
+var auditExportContentTypes = map[domainaudit.ExportFormat]string{
+	domainaudit.ExportFormatCSV:    mimeCSV,
+	domainaudit.ExportFormatNDJSON: mimeNDJSON,
+}
+
+type auditExportWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (s *auditExportWriter) Write(p []byte) (int, error) {
+	if !s.started {
+		s.started = true
+		s.w.Header().Set(headerContentType, s.contentType)
+		s.w.Header().Set(headerContentDisposition, fmt.Sprintf(`attachment; filename=%q`, s.filename))
+		s.w.WriteHeader(http.StatusOK)
+	}
+	return s.w.Write(p)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
 }
@@ -146,6 +146,35 @@ func TestAuditHandler_Export_200_CSV(t *testing.T) {
 	}
 }
 
+func TestAuditHandler_Export_GET_NDJSON_FromTo(t *testing.T) {
+	t.Parallel()
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	h := NewAuditHandler(domainaudit.NewAuditService(db))
+	now := time.Now().UTC()
+	seedAuditEvent(t, h.auditService, wsID, "tool.executed", domainaudit.OutcomeSuccess, now)
+	seedAuditEvent(t, h.auditService, wsID, "tool.executed", domainaudit.OutcomeSuccess, now.AddDate(0, 0, -10))
+
+	from := now.AddDate(0, 0, -1).Format("2006-01-02")
+	to := now.AddDate(0, 0, 1).Format("2006-01-02")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/export?format=ndjson&from="+from+"&to="+to, nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+
+	h.Export(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if got := rr.Header().Get("Content-Type"); got != "application/x-ndjson" {
+		t.Fatalf("expected application/x-ndjson content type, got %q", got)
+	}
+	lines := strings.Split(strings.TrimSpace(rr.Body.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 ndjson line within window, got %d: %q", len(lines), rr.Body.String())
+	}
+}
+
 func TestAuditHandler_Export_400_BadFormat(t *testing.T) {
 	t.Parallel()
 	db := mustOpenDBWithMigrations(t)
@@ -168,6 +197,7 @@ func TestAuditHandler_Query_MissingWorkspaceID_400(t *testing.T) {
 	db := mustOpenDBWithMigrations(t)
 	h := NewAuditHandler(domainaudit.NewAuditService(db))
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/events", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 
 	h.Query(rr, req)
@@ -183,6 +213,7 @@ func TestAuditHandler_GetByID_MissingWorkspace_400(t *testing.T) {
 	h := NewAuditHandler(domainaudit.NewAuditService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/events/some-id", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "some-id")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -259,3 +290,58 @@ func seedAuditEvent(
 	}
 	return e
 }
+
+func TestAuditHandler_Stats_200_DefaultWindow(t *testing.T) {
+	t.Parallel()
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	otherWS, _ := setupWorkspaceAndOwner(t, db)
+	h := NewAuditHandler(domainaudit.NewAuditService(db))
+	now := time.Now().UTC()
+	seedAuditEvent(t, h.auditService, wsID, "tool.executed", domainaudit.OutcomeSuccess, now.Add(-time.Hour))
+	seedAuditEvent(t, h.auditService, wsID, "tool.executed", domainaudit.OutcomeDenied, now.Add(-2*time.Hour))
+	seedAuditEvent(t, h.auditService, wsID, "tool.executed", domainaudit.OutcomeSuccess, now.Add(-45*24*time.Hour))
+	seedAuditEvent(t, h.auditService, otherWS, "tool.executed", domainaudit.OutcomeSuccess, now.Add(-time.Hour))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/stats", nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+
+	h.Stats(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data domainaudit.AuditStats `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Data.Total != 2 || len(resp.Data.ByOutcome) != 2 || len(resp.Data.TopActors) != 2 {
+		t.Fatalf("unexpected stats: %+v", resp.Data)
+	}
+}
+
+func TestAuditHandler_Stats_400_InvalidWindow(t *testing.T) {
+	t.Parallel()
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	h := NewAuditHandler(domainaudit.NewAuditService(db))
+
+	for _, query := range []string{
+		"?from=yesterday",
+		"?to=2026-13-01",
+		"?from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/audit/stats"+query, nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+
+		h.Stats(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d: %s", query, rr.Code, rr.Body.String())
+		}
+	}
+}
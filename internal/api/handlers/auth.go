@@ -1,13 +1,19 @@
-// Task 1.6.12: HTTP handlers for register + login (public endpoints — no AuthMiddleware)
+// Task 1.6.12: HTTP handlers for register + login + refresh (public endpoints — no AuthMiddleware)
+// and logout (authenticated — revokes the current token).
 // Translates HTTP requests into domain/auth.AuthService calls and maps domain errors to HTTP codes.
 package handlers
 
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/api/middleware"
 	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
+	pkgauth "github.com/matiasleandrokruk/fenix/pkg/auth"
 )
 
 // AuthHandler handles authentication HTTP requests (register and login).
@@ -36,12 +42,18 @@ type LoginRequest struct {
 	Password string `json:"password"`
 }
 
-// AuthResponse is the response body returned after successful register or login.
+// RefreshRequest is the request body for POST /auth/refresh.
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// AuthResponse is the response body returned after successful register, login or refresh.
 // Task 1.6.12: camelCase JSON to match frontend conventions (userId, workspaceId).
 type AuthResponse struct {
-	Token       string `json:"token"`
-	UserID      string `json:"userId"`
-	WorkspaceID string `json:"workspaceId"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	UserID       string `json:"userId"`
+	WorkspaceID  string `json:"workspaceId"`
 }
 
 // Register handles POST /auth/register.
@@ -49,18 +61,18 @@ type AuthResponse struct {
 //
 // Response codes:
 //   - 201 Created: registration successful
-//   - 400 Bad Request: invalid JSON or missing required fields
+//   - 400 Bad Request: invalid JSON, missing required fields or weak password
 //   - 409 Conflict: email already registered
 //   - 500 Internal Server Error: unexpected failure
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 	var req RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 
 	if err := validateRegisterRequest(req); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 
@@ -71,21 +83,19 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 		WorkspaceName: req.WorkspaceName,
 	})
 	if err != nil {
+		if errors.Is(err, domainauth.ErrWeakPassword) {
+			writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+			return
+		}
 		if errors.Is(err, domainauth.ErrEmailAlreadyExists) {
-			writeError(w, http.StatusConflict, "email already registered")
+			writeError(w, http.StatusConflict, codeConflict, "email already registered")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "registration failed")
+		writeError(w, http.StatusInternalServerError, codeInternal, "registration failed")
 		return
 	}
 
-	w.Header().Set(headerContentType, mimeJSON)
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(AuthResponse{ //nolint:errcheck
-		Token:       result.Token,
-		UserID:      result.UserID,
-		WorkspaceID: result.WorkspaceID,
-	})
+	writeAuthResponse(w, http.StatusCreated, result)
 }
 
 // Login handles POST /auth/login.
@@ -95,38 +105,137 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 //   - 200 OK: login successful
 //   - 400 Bad Request: invalid JSON or missing required fields
 //   - 401 Unauthorized: invalid credentials (generic — doesn't reveal if email exists)
+//   - 429 Too Many Requests: email or client IP locked out after repeated failures
 //   - 500 Internal Server Error: unexpected failure
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 
 	if err := validateLoginRequest(req); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 
 	result, err := h.authService.Login(r.Context(), domainauth.LoginInput{
 		Email:    req.Email,
 		Password: req.Password,
+		IP:       middleware.RemoteIP(r),
 	})
 	if err != nil {
+		if errors.Is(err, domainauth.ErrLoginLocked) {
+			writeError(w, http.StatusTooManyRequests, codeRateLimited, "too many failed login attempts")
+			return
+		}
 		if errors.Is(err, domainauth.ErrInvalidCredentials) {
-			writeError(w, http.StatusUnauthorized, "invalid credentials")
+			writeError(w, http.StatusUnauthorized, codeUnauthorized, "invalid credentials")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, codeInternal, "login failed")
+		return
+	}
+
+	writeAuthResponse(w, http.StatusOK, result)
+}
+
+// Refresh handles POST /auth/refresh.
+// Task 1.6.15: Exchanges a single-use refresh token for a new JWT + refresh token.
+//
+// Response codes:
+//   - 200 OK: tokens rotated
+//   - 400 Bad Request: invalid JSON or missing refreshToken
+//   - 401 Unauthorized: unknown, expired, revoked or reused refresh token
+//   - 500 Internal Server Error: unexpected failure
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
+		return
+	}
+	if req.RefreshToken == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "refreshToken is required")
+		return
+	}
+
+	result, err := h.authService.Refresh(r.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, domainauth.ErrInvalidRefreshToken) {
+			writeError(w, http.StatusUnauthorized, codeUnauthorized, "invalid refresh token")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, codeInternal, "token refresh failed")
+		return
+	}
+
+	writeAuthResponse(w, http.StatusOK, result)
+}
+
+// LogoutRequest is the optional request body for POST /auth/logout.
+type LogoutRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+// Logout handles POST /auth/logout.
+// Task 1.6.16: Revokes the current access token (by jti) and, if provided, the refresh token.
+// Requires AuthMiddleware so the token claims are available in context.
+//
+// Response codes:
+//   - 204 No Content: token revoked
+//   - 400 Bad Request: invalid JSON or token without jti
+//   - 401 Unauthorized: missing authentication context
+//   - 500 Internal Server Error: unexpected failure
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, _ := ctx.Value(ctxkeys.UserID).(string)
+	workspaceID, _ := ctx.Value(ctxkeys.WorkspaceID).(string)
+	if userID == "" || workspaceID == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "missing user context")
+		return
+	}
+
+	var req LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
+		return
+	}
+
+	tokenID, _ := ctx.Value(ctxkeys.TokenID).(string)
+	expiresAt := time.Now().Add(pkgauth.GetJWTExpiry())
+	if raw, _ := ctx.Value(ctxkeys.TokenExpiresAt).(string); raw != "" {
+		if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+			expiresAt = parsed
+		}
+	}
+
+	err := h.authService.Logout(ctx, domainauth.LogoutInput{
+		WorkspaceID:  workspaceID,
+		UserID:       userID,
+		TokenID:      tokenID,
+		ExpiresAt:    expiresAt,
+		RefreshToken: req.RefreshToken,
+	})
+	if err != nil {
+		if errors.Is(err, domainauth.ErrTokenNotRevocable) {
+			writeError(w, http.StatusBadRequest, codeBadRequest, "token cannot be revoked")
 			return
 		}
-		writeError(w, http.StatusInternalServerError, "login failed")
+		writeError(w, http.StatusInternalServerError, codeInternal, "logout failed")
 		return
 	}
 
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAuthResponse(w http.ResponseWriter, status int, result *domainauth.AuthResult) {
 	w.Header().Set(headerContentType, mimeJSON)
-	w.WriteHeader(http.StatusOK)
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(AuthResponse{ //nolint:errcheck
-		Token:       result.Token,
-		UserID:      result.UserID,
-		WorkspaceID: result.WorkspaceID,
+		Token:        result.Token,
+		RefreshToken: result.RefreshToken,
+		UserID:       result.UserID,
+		WorkspaceID:  result.WorkspaceID,
 	})
 }
 
@@ -11,8 +11,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/api/middleware"
 	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 )
@@ -66,9 +69,10 @@ type loginPayload struct {
 
 // authResponse is the expected success body returned by both endpoints.
 type authResponse struct {
-	Token       string `json:"token"`
-	UserID      string `json:"userId"`
-	WorkspaceID string `json:"workspaceId"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	UserID       string `json:"userId"`
+	WorkspaceID  string `json:"workspaceId"`
 }
 
 // postRequest builds a POST request with JSON body.
@@ -356,6 +360,33 @@ func TestAuthHandler_Login_WrongPassword(t *testing.T) {
 	}
 }
 
+// TestAuthHandler_Login_LockedOut_Returns429 verifies that repeated failures
+// from one client IP lock it out, for known and unknown emails alike.
+func TestAuthHandler_Login_LockedOut_Returns429(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenAuthDB(t)
+	h := NewAuthHandler(domainauth.NewAuthService(db, domainauth.WithLoginLockout(domainauth.LockoutPolicy{
+		MaxFailures: 2, Window: time.Minute, Cooldown: time.Minute,
+	})))
+
+	codes := make([]int, 0, 3)
+	for _, email := range []string{"ghost@acme.com", "ghost@acme.com", "other@acme.com"} {
+		req := postRequest(t, "/auth/login", loginPayload{Email: email, Password: "WrongPassword!"})
+		req.RemoteAddr = "203.0.113.9:4000"
+		rr := httptest.NewRecorder()
+		h.Login(rr, req)
+		codes = append(codes, rr.Code)
+	}
+
+	want := []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}
+	for i := range want {
+		if codes[i] != want[i] {
+			t.Fatalf("login statuses = %v; want %v", codes, want)
+		}
+	}
+}
+
 // TestAuthHandler_Login_NonExistentEmail verifies 401 on unknown email.
 func TestAuthHandler_Login_NonExistentEmail(t *testing.T) {
 	t.Parallel()
@@ -492,6 +523,31 @@ func TestAuthHandler_Register_11CharPassword_Returns400(t *testing.T) {
 	}
 }
 
+// TestAuthHandler_Register_CommonPassword_Returns400 verifies that a password on
+// the common-passwords list is rejected even though it is long enough.
+func TestAuthHandler_Register_CommonPassword_Returns400(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenAuthDB(t)
+	h := newAuthHandler(db)
+
+	req := postRequest(t, "/auth/register", registerPayload{
+		Email:         "common@acme.com",
+		Password:      "Password1234",
+		DisplayName:   "Common",
+		WorkspaceName: "Acme Corp",
+	})
+	rr := httptest.NewRecorder()
+	h.Register(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("common password status = %d; want %d. body: %s", rr.Code, http.StatusBadRequest, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), "too common") {
+		t.Errorf("body = %s; want weak password reason", rr.Body.String())
+	}
+}
+
 // TestAuthHandler_Login_ContentTypeJSON verifies Content-Type header on success.
 func TestAuthHandler_Login_ContentTypeJSON(t *testing.T) {
 	t.Parallel()
@@ -557,3 +613,104 @@ func TestAuthHandler_Login_IDsMatchRegistration(t *testing.T) {
 		t.Errorf("Login WorkspaceID = %q; want %q", loginResp.WorkspaceID, regResp.WorkspaceID)
 	}
 }
+
+// ===== REFRESH TESTS =====
+
+// TestAuthHandler_Refresh_RotatesTokens verifies 200 + new token pair, and that the old refresh token is rejected.
+func TestAuthHandler_Refresh_RotatesTokens(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenAuthDB(t)
+	h := newAuthHandler(db)
+
+	regRR := httptest.NewRecorder()
+	h.Register(regRR, postRequest(t, "/auth/register", registerPayload{
+		Email:         "refresh@acme.com",
+		Password:      "SecurePass123!",
+		DisplayName:   "Refresh",
+		WorkspaceName: "Acme Corp",
+	}))
+	var reg authResponse
+	if err := json.NewDecoder(regRR.Body).Decode(&reg); err != nil {
+		t.Fatalf("decode register response: %v", err)
+	}
+	if reg.RefreshToken == "" {
+		t.Fatal("register response missing refreshToken")
+	}
+
+	rr := httptest.NewRecorder()
+	h.Refresh(rr, postRequest(t, "/auth/refresh", map[string]string{"refreshToken": reg.RefreshToken}))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Refresh status = %d; want %d. body: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	var resp authResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode refresh response: %v", err)
+	}
+	if resp.Token == "" || resp.RefreshToken == "" || resp.RefreshToken == reg.RefreshToken {
+		t.Fatalf("unexpected refresh response: %+v", resp)
+	}
+	if resp.UserID != reg.UserID || resp.WorkspaceID != reg.WorkspaceID {
+		t.Fatalf("refresh identity mismatch: %+v", resp)
+	}
+
+	rr = httptest.NewRecorder()
+	h.Refresh(rr, postRequest(t, "/auth/refresh", map[string]string{"refreshToken": reg.RefreshToken}))
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Refresh(old token) status = %d; want %d", rr.Code, http.StatusUnauthorized)
+	}
+}
+
+// TestAuthHandler_Refresh_MissingToken verifies 400 when refreshToken is absent.
+func TestAuthHandler_Refresh_MissingToken(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenAuthDB(t)
+	h := newAuthHandler(db)
+
+	rr := httptest.NewRecorder()
+	h.Refresh(rr, postRequest(t, "/auth/refresh", map[string]string{}))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("Refresh status = %d; want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+// ===== LOGOUT TESTS =====
+
+// TestAuthHandler_Logout_RevokesCurrentToken verifies 204 and that the jti lands on the revocation list.
+func TestAuthHandler_Logout_RevokesCurrentToken(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenAuthDB(t)
+	svc := domainauth.NewAuthService(db)
+	h := NewAuthHandler(svc)
+
+	regRR := httptest.NewRecorder()
+	h.Register(regRR, postRequest(t, "/auth/register", registerPayload{
+		Email:         "logout@acme.com",
+		Password:      "SecurePass123!",
+		DisplayName:   "Logout",
+		WorkspaceName: "Acme Corp",
+	}))
+	var reg authResponse
+	if err := json.NewDecoder(regRR.Body).Decode(&reg); err != nil {
+		t.Fatalf("decode register response: %v", err)
+	}
+
+	req := postRequest(t, "/auth/logout", map[string]string{"refreshToken": reg.RefreshToken})
+	req.Header.Set("Authorization", "Bearer "+reg.Token)
+	rr := httptest.NewRecorder()
+	middleware.AuthMiddlewareWithRevocation(svc)(http.HandlerFunc(h.Logout)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("Logout status = %d; want %d. body: %s", rr.Code, http.StatusNoContent, rr.Body.String())
+	}
+
+	// The same token is now rejected by the revocation-aware middleware.
+	req = postRequest(t, "/auth/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+reg.Token)
+	rr = httptest.NewRecorder()
+	middleware.AuthMiddlewareWithRevocation(svc)(http.HandlerFunc(h.Logout)).ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("reused token status = %d; want %d", rr.Code, http.StatusUnauthorized)
+	}
+}
@@ -3,8 +3,10 @@ package handlers
 import (
 	"context"
 	"net/http"
+	"slices"
 
 	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
 )
 
 type ActionAuthorizer interface {
@@ -27,19 +29,46 @@ func checkActionAuthorization(
 
 	userID, ok := r.Context().Value(ctxkeys.UserID).(string)
 	if !ok || userID == "" {
-		writeError(w, http.StatusUnauthorized, "missing user_id in context")
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "missing user_id in context")
 		return false
 	}
 
 	allowed, err := authz.CheckActionPermission(r.Context(), userID, resource, action, nil)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "authorization failed")
+		writeError(w, http.StatusInternalServerError, codeInternal, "authorization failed")
 		return false
 	}
 	if !allowed {
-		writeError(w, http.StatusForbidden, "forbidden")
+		writeError(w, http.StatusForbidden, codeForbidden, "forbidden")
 		return false
 	}
 
 	return true
 }
+
+// RequireRole returns middleware that only lets through users whose JWT role
+// claim is one of roles. It must run after AuthMiddleware: a request without
+// user context gets 401, an authenticated user with another role gets 403.
+// Tokens issued before roles existed carry no claim and count as RoleMember.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value(ctxkeys.UserID).(string)
+			if !ok || userID == "" {
+				writeError(w, http.StatusUnauthorized, codeUnauthorized, "missing user_id in context")
+				return
+			}
+
+			role, _ := r.Context().Value(ctxkeys.Role).(string)
+			if role == "" {
+				role = domainauth.RoleMember
+			}
+			if !slices.Contains(roles, role) {
+				writeError(w, http.StatusForbidden, codeForbidden, "insufficient role")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
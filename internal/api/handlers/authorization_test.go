@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
+)
+
+func TestRequireRole(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		userID   string
+		role     string
+		allowed  []string
+		wantCode int
+	}{
+		{"admin allowed", "u1", domainauth.RoleAdmin, []string{domainauth.RoleAdmin}, http.StatusOK},
+		{"agent allowed on agent route", "u1", domainauth.RoleAgent, []string{domainauth.RoleAgent, domainauth.RoleAdmin}, http.StatusOK},
+		{"agent forbidden on admin route", "u1", domainauth.RoleAgent, []string{domainauth.RoleAdmin}, http.StatusForbidden},
+		{"member forbidden on agent route", "u1", domainauth.RoleMember, []string{domainauth.RoleAgent, domainauth.RoleAdmin}, http.StatusForbidden},
+		{"missing role treated as member", "u1", "", []string{domainauth.RoleAdmin}, http.StatusForbidden},
+		{"missing role allowed where member is", "u1", "", []string{domainauth.RoleMember}, http.StatusOK},
+		{"missing user context", "", domainauth.RoleAdmin, []string{domainauth.RoleAdmin}, http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := RequireRole(tc.allowed...)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			ctx := context.Background()
+			if tc.userID != "" {
+				ctx = ctxkeys.WithValue(ctx, ctxkeys.UserID, tc.userID)
+			}
+			if tc.role != "" {
+				ctx = ctxkeys.WithValue(ctx, ctxkeys.Role, tc.role)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+
+			if w.Code != tc.wantCode {
+				t.Fatalf("status = %d; want %d (body: %s)", w.Code, tc.wantCode, w.Body.String())
+			}
+		})
+	}
+}
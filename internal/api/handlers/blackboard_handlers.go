@@ -32,7 +32,7 @@ func (h *BlackboardHandler) RunPipeline(w http.ResponseWriter, r *http.Request)
 
 	cognitiveWorkspaceID := chi.URLParam(r, routeParamCognitiveWorkspaceID)
 	if cognitiveWorkspaceID == "" {
-		writeError(w, http.StatusBadRequest, "cognitive workspace id is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "cognitive workspace id is required")
 		return
 	}
 
@@ -50,10 +50,10 @@ func (h *BlackboardHandler) RunPipeline(w http.ResponseWriter, r *http.Request)
 func writeBlackboardPipelineError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, blackboard.ErrPipelineAlreadyRunning):
-		writeError(w, http.StatusConflict, err.Error())
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
 	case errors.Is(err, blackboard.ErrCognitiveWorkspaceNotFound):
-		writeError(w, http.StatusNotFound, err.Error())
+		writeError(w, http.StatusNotFound, codeNotFound, err.Error())
 	default:
-		writeError(w, http.StatusInternalServerError, "failed to run blackboard pipeline")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to run blackboard pipeline")
 	}
 }
@@ -43,7 +43,7 @@ type CreateCaseRequest struct {
 type UpdateCaseRequest = CreateCaseRequest
 
 func (h *CaseHandler) CreateCase(w http.ResponseWriter, r *http.Request) {
-	wsID, ok := requireWorkspaceForCaseMutation(w, r)
+	wsID, ok := requireWorkspaceID(w, r)
 	if !ok {
 		return
 	}
@@ -55,26 +55,18 @@ func (h *CaseHandler) CreateCase(w http.ResponseWriter, r *http.Request) {
 	if handleCaseCreateError(w, svcErr) {
 		return
 	}
+	setLocation(w, "cases", out.ID)
 	writeCreatedJSON(w, out)
 }
 
-func requireWorkspaceForCaseMutation(w http.ResponseWriter, r *http.Request) (string, bool) {
-	wsID, wsErr := getWorkspaceID(r.Context())
-	if wsErr != nil {
-		writeError(w, http.StatusBadRequest, errMissingWorkspaceID)
-		return "", false
-	}
-	return wsID, true
-}
-
 func decodeCreateCaseRequest(w http.ResponseWriter, r *http.Request) (CreateCaseRequest, bool) {
 	var req CreateCaseRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return CreateCaseRequest{}, false
 	}
 	if req.OwnerID == "" || req.Subject == "" {
-		writeError(w, http.StatusBadRequest, "ownerId and subject are required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "ownerId and subject are required")
 		return CreateCaseRequest{}, false
 	}
 	return req, true
@@ -104,10 +96,10 @@ func handleCaseCreateError(w http.ResponseWriter, svcErr error) bool {
 		return false
 	}
 	if errors.Is(svcErr, crm.ErrInvalidCaseInput) {
-		writeError(w, http.StatusBadRequest, svcErr.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, svcErr.Error())
 		return true
 	}
-	writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create case: %v", svcErr))
+	writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create case: %v", svcErr))
 	return true
 }
 
@@ -115,48 +107,46 @@ func writeCreatedJSON(w http.ResponseWriter, out any) {
 	w.Header().Set(headerContentType, mimeJSON)
 	w.WriteHeader(http.StatusCreated)
 	if encodeErr := json.NewEncoder(w).Encode(out); encodeErr != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToEncode)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToEncode)
 	}
 }
 
 func (h *CaseHandler) GetCase(w http.ResponseWriter, r *http.Request) {
-	wsID, wsErr := getWorkspaceID(r.Context())
-	if wsErr != nil {
-		writeError(w, http.StatusBadRequest, errMissingWorkspaceID)
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 	id := chi.URLParam(r, paramID)
 	out, svcErr := h.service.Get(r.Context(), wsID, id)
 	if errors.Is(svcErr, sql.ErrNoRows) {
-		writeError(w, http.StatusNotFound, errCaseNotFound)
+		writeError(w, http.StatusNotFound, codeCaseNotFound, errCaseNotFound)
 		return
 	}
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get case: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to get case: %v", svcErr))
 		return
 	}
 	h.attachActiveSignalCount(r.Context(), wsID, out)
 	if encodeErr := json.NewEncoder(w).Encode(out); encodeErr != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToEncode)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToEncode)
 		return
 	}
 }
 
 func (h *CaseHandler) ListCases(w http.ResponseWriter, r *http.Request) {
-	wsID, wsErr := getWorkspaceID(r.Context())
-	if wsErr != nil {
-		writeError(w, http.StatusBadRequest, errMissingWorkspaceID)
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 	page := parsePaginationParams(r)
 	input, err := parseCaseListInput(r, page)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 	items, total, svcErr := h.service.List(r.Context(), wsID, input)
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list cases: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list cases: %v", svcErr))
 		return
 	}
 	counts := countActiveSignalsByEntity(r.Context(), h.signalCounter, wsID, entityTypeCase, collectEntityIDs(items, func(item *crm.CaseTicket) string {
@@ -167,46 +157,45 @@ func (h *CaseHandler) ListCases(w http.ResponseWriter, r *http.Request) {
 			item.ActiveSignalCount = intPtr(count)
 		}
 	}
-	if !writePaginatedOr500(w, items, total, page) {
+	if !writePaginated(w, items, total, page.Limit, page.Offset) {
 		return
 	}
 }
 
 func (h *CaseHandler) UpdateCase(w http.ResponseWriter, r *http.Request) {
-	wsID, wsErr := getWorkspaceID(r.Context())
-	if wsErr != nil {
-		writeError(w, http.StatusBadRequest, errMissingWorkspaceID)
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 	id := chi.URLParam(r, paramID)
 	existing, svcErr := h.service.Get(r.Context(), wsID, id)
 	if errors.Is(svcErr, sql.ErrNoRows) {
-		writeError(w, http.StatusNotFound, errCaseNotFound)
+		writeError(w, http.StatusNotFound, codeCaseNotFound, errCaseNotFound)
 		return
 	}
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to get case: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to get case: %v", svcErr))
 		return
 	}
 	var req UpdateCaseRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 	out, svcErr := h.service.Update(r.Context(), wsID, id, buildUpdateCaseInput(req, existing))
 	if svcErr != nil {
 		if errors.Is(svcErr, crm.ErrInvalidCaseInput) {
-			writeError(w, http.StatusBadRequest, svcErr.Error())
+			writeError(w, http.StatusBadRequest, codeBadRequest, svcErr.Error())
 			return
 		}
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to update case: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to update case: %v", svcErr))
 		return
 	}
 	json.NewEncoder(w).Encode(out) //nolint:errcheck
 }
 
 func (h *CaseHandler) DeleteCase(w http.ResponseWriter, r *http.Request) {
-	handleDeleteWithNotFound(w, r, "case id is required", sql.ErrNoRows, errCaseNotFound, "failed to delete case: %v", h.service.Delete)
+	handleDeleteWithNotFound(w, r, "case id is required", sql.ErrNoRows, codeCaseNotFound, errCaseNotFound, "failed to delete case: %v", h.service.Delete)
 }
 
 // buildUpdateCaseInput merges update request with existing case values.
@@ -235,6 +224,18 @@ func parseCaseListInput(r *http.Request, page paginationParams) (crm.ListCasesIn
 	priority := strings.TrimSpace(q.Get("priority"))
 	ownerID := strings.TrimSpace(q.Get(queryOwnerID))
 	accountID := strings.TrimSpace(q.Get(queryAccountID))
+	channel := strings.TrimSpace(q.Get("channel"))
+	createdFrom, err := parseOptionalRFC3339(strings.TrimSpace(q.Get("created_from")))
+	if err != nil {
+		return crm.ListCasesInput{}, errors.New("invalid created_from. expected RFC3339")
+	}
+	createdTo, err := parseOptionalRFC3339(strings.TrimSpace(q.Get("created_to")))
+	if err != nil {
+		return crm.ListCasesInput{}, errors.New("invalid created_to. expected RFC3339")
+	}
+	if !createdFrom.IsZero() && !createdTo.IsZero() && createdTo.Before(createdFrom) {
+		return crm.ListCasesInput{}, errors.New("created_to must not be before created_from")
+	}
 	sortParam := strings.TrimSpace(q.Get("sort"))
 	if sortParam == "" {
 		sortParam = querySortDesc
@@ -254,13 +255,16 @@ func parseCaseListInput(r *http.Request, page paginationParams) (crm.ListCasesIn
 	}
 
 	return crm.ListCasesInput{
-		Limit:     page.Limit,
-		Offset:    page.Offset,
-		Status:    status,
-		Priority:  priority,
-		OwnerID:   ownerID,
-		AccountID: accountID,
-		Sort:      sortParam,
+		Limit:       page.Limit,
+		Offset:      page.Offset,
+		Status:      status,
+		Priority:    priority,
+		OwnerID:     ownerID,
+		AccountID:   accountID,
+		Channel:     channel,
+		CreatedFrom: createdFrom,
+		CreatedTo:   createdTo,
+		Sort:        sortParam,
 	}, nil
 }
 
@@ -37,6 +37,13 @@ func TestCaseHandler_CreateCase_Success(t *testing.T) {
 	if rr.Code != http.StatusCreated {
 		t.Fatalf("expected 201, got %d body=%s", rr.Code, rr.Body.String())
 	}
+	var created crm.CaseTicket
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode case: %v", err)
+	}
+	if got := rr.Header().Get("Location"); got != "/api/v1/cases/"+created.ID {
+		t.Fatalf("Location = %q; want /api/v1/cases/%s", got, created.ID)
+	}
 }
 
 func TestCaseHandler_CreateCase_MissingRequired_Returns400(t *testing.T) {
@@ -176,6 +183,7 @@ func TestCaseHandler_GetCase_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewCaseHandler(crm.NewCaseService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/cases/c1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "c1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -195,6 +203,7 @@ func TestCaseHandler_ListCases_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewCaseHandler(crm.NewCaseService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/cases", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	h.ListCases(rr, req)
 
@@ -210,6 +219,7 @@ func TestCaseHandler_UpdateCase_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewCaseHandler(crm.NewCaseService(db))
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/cases/c1", bytes.NewBufferString(`{"subject":"x"}`))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "c1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -229,6 +239,7 @@ func TestCaseHandler_DeleteCase_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewCaseHandler(crm.NewCaseService(db))
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/cases/c1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "c1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -306,6 +317,7 @@ func TestCaseHandler_CreateCase_MissingWorkspace_Returns400(t *testing.T) {
 
 	body, _ := json.Marshal(map[string]any{"ownerId": "u1", "subject": "test"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/cases", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	h.CreateCase(rr, req)
 
@@ -378,6 +390,51 @@ func TestCaseHandler_ListCases_MultipleFilters_Returns400(t *testing.T) {
 	}
 }
 
+func TestCaseHandler_ListCases_ChannelAndCreatedRange(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewCaseService(db)
+	h := NewCaseHandler(svc)
+	for _, channel := range []string{"email", "chat"} {
+		if _, err := svc.Create(context.Background(), crm.CreateCaseInput{WorkspaceID: wsID, OwnerID: ownerID, Subject: channel + " case", Channel: channel}); err != nil {
+			t.Fatalf("seed %s case: %v", channel, err)
+		}
+	}
+
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/cases?"+query, nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+		h.ListCases(rr, req)
+		return rr
+	}
+
+	rr := list("channel=email&status=open&created_from=2000-01-01T00:00:00Z")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data []crm.CaseTicket `json:"data"`
+		Meta struct {
+			Total int `json:"total"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Meta.Total != 1 || len(resp.Data) != 1 || resp.Data[0].Channel == nil || *resp.Data[0].Channel != "email" {
+		t.Fatalf("response = %+v; want only the email case", resp)
+	}
+
+	for _, query := range []string{"created_from=yesterday", "created_from=2026-03-02T00:00:00Z&created_to=2026-03-01T00:00:00Z"} {
+		if rr = list(query); rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, rr.Code)
+		}
+	}
+}
+
 func TestCaseHandler_UpdateCase_NotFound_Returns404(t *testing.T) {
 	t.Parallel()
 
@@ -84,7 +84,7 @@ func (h *ContactHandler) CreateContact(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if !isContactRequestValid(req) {
-		writeError(w, http.StatusBadRequest, "accountId, firstName, lastName and ownerId are required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "accountId, firstName, lastName and ownerId are required")
 		return
 	}
 
@@ -101,10 +101,11 @@ func (h *ContactHandler) CreateContact(w http.ResponseWriter, r *http.Request) {
 		Metadata:    req.Metadata,
 	})
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create contact: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create contact: %v", svcErr))
 		return
 	}
 
+	setLocation(w, "contacts", contact.ID)
 	w.WriteHeader(http.StatusCreated)
 	if !writeJSONOr500(w, contactToResponse(contact)) {
 		return
@@ -121,12 +122,12 @@ func (h *ContactHandler) GetContact(w http.ResponseWriter, r *http.Request) {
 
 	contactID := chi.URLParam(r, paramID)
 	if contactID == "" {
-		writeError(w, http.StatusBadRequest, errContactIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errContactIDRequired)
 		return
 	}
 
 	contact, svcErr := h.contactService.Get(ctx, wsID, contactID)
-	if handleGetError(w, svcErr, errContactNotFound, errFailedToGetContact) {
+	if handleGetError(w, svcErr, codeContactNotFound, errContactNotFound, errFailedToGetContact) {
 		return
 	}
 
@@ -155,13 +156,13 @@ func (h *ContactHandler) ListContactsByAccount(w http.ResponseWriter, r *http.Re
 
 	accountID := chi.URLParam(r, queryAccountID)
 	if accountID == "" {
-		writeError(w, http.StatusBadRequest, "account_id is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "account_id is required")
 		return
 	}
 
 	contacts, svcErr := h.contactService.ListByAccount(ctx, wsID, accountID)
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list contacts by account: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list contacts by account: %v", svcErr))
 		return
 	}
 
@@ -171,7 +172,7 @@ func (h *ContactHandler) ListContactsByAccount(w http.ResponseWriter, r *http.Re
 	}
 
 	n := len(responses)
-	if !writePaginatedOr500(w, responses, n, paginationParams{Limit: n, Offset: 0}) {
+	if !writePaginated(w, responses, n, n, 0) {
 		return
 	}
 }
@@ -187,6 +188,7 @@ func (h *ContactHandler) UpdateContact(w http.ResponseWriter, r *http.Request) {
 		w,
 		r,
 		errContactIDRequired,
+		codeContactNotFound,
 		errContactNotFound,
 		errFailedToGetContact,
 		"failed to update contact: %v",
@@ -205,7 +207,7 @@ func (h *ContactHandler) UpdateContact(w http.ResponseWriter, r *http.Request) {
 
 // DeleteContact handles DELETE /api/v1/contacts/{id}
 func (h *ContactHandler) DeleteContact(w http.ResponseWriter, r *http.Request) {
-	handleVerifiedDelete(w, r, errContactIDRequired, errContactNotFound, errFailedToGetContact, "failed to delete contact: %v", h.contactService.Get, h.contactService.Delete)
+	handleVerifiedDelete(w, r, errContactIDRequired, codeContactNotFound, errContactNotFound, errFailedToGetContact, "failed to delete contact: %v", h.contactService.Get, h.contactService.Delete)
 }
 
 // isContactRequestValid checks required fields for CreateContact.
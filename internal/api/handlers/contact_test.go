@@ -54,6 +54,7 @@ func TestContactHandler_CreateContact_MissingWorkspace_Returns400(t *testing.T)
 	body, _ := json.Marshal(map[string]any{"accountId": "a1", "firstName": "A", "lastName": "B", "ownerId": ownerID})
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/contacts", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	w := httptest.NewRecorder()
 	handler.CreateContact(w, req)
 
@@ -417,6 +418,7 @@ func TestContactHandler_ListContactsByAccount_MissingWorkspace(t *testing.T) {
 	handler := NewContactHandler(crm.NewContactService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/acc-1/contacts", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("account_id", "acc-1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -4,11 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	"github.com/matiasleandrokruk/fenix/internal/domain/copilot"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 type CopilotActionsService interface {
@@ -151,8 +152,8 @@ func handleCopilotEntityAction[T any](
 
 	resp, err := action(r.Context(), common)
 	if err != nil {
-		log.Printf("%s: %v", errorMsg, err)
-		writeError(w, http.StatusInternalServerError, errorMsg)
+		logging.FromContext(r.Context()).Error(errorMsg, slog.String(logging.KeyComponent, "copilot"), logging.Err(err))
+		writeError(w, http.StatusInternalServerError, codeInternal, errorMsg)
 		return
 	}
 
@@ -170,9 +171,10 @@ type copilotEntityInput struct {
 
 func buildCopilotEntityInput(r *http.Request) (copilotEntityInput, error) {
 	ctx := r.Context()
-	wsID, err := getWorkspaceID(ctx)
-	if err != nil {
-		return copilotEntityInput{}, actionRequestError{status: http.StatusUnauthorized, message: "missing workspace context"}
+	wsID, ok := workspaceIDFromRequest(r)
+	if !ok {
+		status, message := missingWorkspaceError(r)
+		return copilotEntityInput{}, actionRequestError{status: status, message: message}
 	}
 
 	userID, _ := ctx.Value(ctxkeys.UserID).(string)
@@ -199,10 +201,10 @@ func buildCopilotEntityInput(r *http.Request) (copilotEntityInput, error) {
 func writeCopilotActionsError(w http.ResponseWriter, err error) {
 	var reqErr actionRequestError
 	if ok := errorAsAction(err, &reqErr); ok {
-		writeError(w, reqErr.status, reqErr.message)
+		writeError(w, reqErr.status, codeForStatus(reqErr.status), reqErr.message)
 		return
 	}
-	writeError(w, http.StatusInternalServerError, "request failed")
+	writeError(w, http.StatusInternalServerError, codeInternal, "request failed")
 }
 
 func errorAsAction(err error, target *actionRequestError) bool {
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent/agents"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+const copilotRoleUser = "user"
+
+// CopilotAskHandler runs the support or prospecting agent from an inline
+// copilot request, recording the conversation on the run.
+type CopilotAskHandler struct {
+	supportAgent     *agents.SupportAgent
+	prospectingAgent *agents.ProspectingAgent
+}
+
+// NewCopilotAskHandler creates a new CopilotAskHandler.
+func NewCopilotAskHandler(supportAgent *agents.SupportAgent, prospectingAgent *agents.ProspectingAgent) *CopilotAskHandler {
+	return &CopilotAskHandler{supportAgent: supportAgent, prospectingAgent: prospectingAgent}
+}
+
+type copilotAskRequest struct {
+	Message        string                 `json:"message"`
+	AgentType      string                 `json:"agent_type,omitempty"`
+	ConversationID string                 `json:"conversation_id,omitempty"`
+	History        []agent.CopilotMessage `json:"history,omitempty"`
+	CaseID         string                 `json:"case_id,omitempty"`
+	LeadID         string                 `json:"lead_id,omitempty"`
+	Language       string                 `json:"language,omitempty"`
+	Priority       string                 `json:"priority,omitempty"`
+}
+
+// Ask handles POST /api/v1/copilot/ask. The agent is taken from agent_type
+// when given and inferred from the request otherwise; a new conversation id
+// is generated when the request does not continue one.
+func (h *CopilotAskHandler) Ask(w http.ResponseWriter, r *http.Request) {
+	workspaceID, userID, ok := extractAgentContext(w, r)
+	if !ok {
+		return
+	}
+	if userID == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "missing user context")
+		return
+	}
+
+	var req copilotAskRequest
+	if !decodeAgentRequest(w, r, &req) {
+		return
+	}
+	if req.Message == "" {
+		writeValidationError(w, map[string]string{"message": errFieldRequired})
+		return
+	}
+
+	agentType, err := agents.ResolveCopilotAgent(agents.CopilotAsk{
+		AgentType: req.AgentType,
+		Message:   req.Message,
+		CaseID:    req.CaseID,
+		LeadID:    req.LeadID,
+	})
+	if err != nil {
+		writeValidationError(w, map[string]string{"agent_type": err.Error()})
+		return
+	}
+
+	conversation := copilotConversation(req)
+	switch agentType {
+	case agents.CopilotAgentSupport:
+		h.askSupport(w, r, workspaceID, req, conversation)
+	default:
+		h.askProspecting(w, r, workspaceID, userID, req, conversation)
+	}
+}
+
+func (h *CopilotAskHandler) askSupport(w http.ResponseWriter, r *http.Request, workspaceID string, req copilotAskRequest, conversation *agent.CopilotConversation) {
+	config, valid := buildSupportConfig(w, supportAgentRequest{
+		CaseID:        req.CaseID,
+		CustomerQuery: req.Message,
+		Language:      req.Language,
+		Priority:      req.Priority,
+	}, workspaceID)
+	if !valid {
+		return
+	}
+	config.Copilot = conversation
+
+	run, err := h.supportAgent.Run(r.Context(), config)
+	if err != nil {
+		handleSupportRunError(w, err)
+		return
+	}
+	writeAgentRunData(w, http.StatusCreated, run)
+}
+
+func (h *CopilotAskHandler) askProspecting(w http.ResponseWriter, r *http.Request, workspaceID, userID string, req copilotAskRequest, conversation *agent.CopilotConversation) {
+	config, valid := buildProspectingConfig(w, prospectingAgentRequest{
+		LeadID:   req.LeadID,
+		Language: req.Language,
+	}, workspaceID)
+	if !valid {
+		return
+	}
+	config = withProspectingTriggeredBy(config, userID)
+	config.Copilot = conversation
+
+	run, err := h.prospectingAgent.Run(r.Context(), config)
+	if err != nil {
+		if !handleProspectingRunError(w, err) {
+			writeError(w, http.StatusInternalServerError, codeInternal, "failed to run prospecting agent")
+		}
+		return
+	}
+	writeAgentRunData(w, http.StatusCreated, run)
+}
+
+// copilotConversation appends the request message to the prior turns.
+func copilotConversation(req copilotAskRequest) *agent.CopilotConversation {
+	id := req.ConversationID
+	if id == "" {
+		id = uuid.NewV7().String()
+	}
+	messages := make([]agent.CopilotMessage, 0, len(req.History)+1)
+	messages = append(messages, req.History...)
+	messages = append(messages, agent.CopilotMessage{Role: copilotRoleUser, Content: req.Message})
+	return &agent.CopilotConversation{ID: id, Messages: messages}
+}
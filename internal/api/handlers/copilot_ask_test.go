@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+)
+
+func copilotAskRequestFor(t *testing.T, wsID, userID string, body map[string]any) *http.Request {
+	t.Helper()
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/copilot/ask", bytes.NewReader(raw))
+	ctx := contextWithWorkspaceID(req.Context(), wsID)
+	if userID != "" {
+		ctx = context.WithValue(ctx, ctxkeys.UserID, userID)
+	}
+	return req.WithContext(ctx)
+}
+
+func TestCopilotAskHandler_Ask_RoutesToProspectingAsCopilotRun(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createUser(t, db, wsID)
+	ph, leadID := newTestProspectingAgentHandler(t, db, wsID, ownerID)
+	h := NewCopilotAskHandler(nil, ph.prospectingAgent)
+
+	rr := httptest.NewRecorder()
+	h.Ask(rr, copilotAskRequestFor(t, wsID, ownerID, map[string]any{
+		"message":         "Draft a first touch for this lead",
+		"lead_id":         leadID,
+		"conversation_id": "conv-42",
+		"history":         []map[string]string{{"role": "assistant", "content": "Which lead?"}},
+	}))
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data agentRunResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Data.TriggerType != agent.TriggerTypeCopilot || resp.Data.AgentDefinitionID != "prospecting-agent" {
+		t.Fatalf("unexpected run: %+v", resp.Data)
+	}
+	if resp.Data.ConversationID == nil || *resp.Data.ConversationID != "conv-42" {
+		t.Fatalf("conversation_id = %v; want conv-42", resp.Data.ConversationID)
+	}
+	if resp.Data.TriggeredByUserID == nil || *resp.Data.TriggeredByUserID != ownerID {
+		t.Fatalf("triggeredByUserId = %v; want %s", resp.Data.TriggeredByUserID, ownerID)
+	}
+
+	insertTestAgentDef(t, db, "agent-manual", wsID)
+	if _, err := agent.NewOrchestrator(db).TriggerAgent(context.Background(), agent.TriggerAgentInput{
+		AgentID: "agent-manual", WorkspaceID: wsID, TriggerType: agent.TriggerTypeManual,
+	}); err != nil {
+		t.Fatalf("trigger manual run: %v", err)
+	}
+
+	listReq := httptest.NewRequest(http.MethodGet, "/agents/runs?trigger_type=copilot", nil)
+	listReq = listReq.WithContext(contextWithWorkspaceID(listReq.Context(), wsID))
+	listRR := httptest.NewRecorder()
+	NewAgentHandler(agent.NewOrchestrator(db)).ListAgentRuns(listRR, listReq)
+	if listRR.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d: %s", listRR.Code, listRR.Body.String())
+	}
+	var list struct {
+		Data []agentRunResponse `json:"data"`
+	}
+	if err := json.Unmarshal(listRR.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].ID != resp.Data.ID {
+		t.Fatalf("copilot filter returned %+v; want only run %s", list.Data, resp.Data.ID)
+	}
+}
+
+func TestCopilotAskHandler_Ask_UnresolvedIntent_Returns400(t *testing.T) {
+	t.Parallel()
+
+	h := NewCopilotAskHandler(nil, nil)
+	rr := httptest.NewRecorder()
+	h.Ask(rr, copilotAskRequestFor(t, "ws-1", "user-1", map[string]any{"message": "what now?"}))
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCopilotAskHandler_Ask_MissingUser_Returns401(t *testing.T) {
+	t.Parallel()
+
+	h := NewCopilotAskHandler(nil, nil)
+	rr := httptest.NewRecorder()
+	h.Ask(rr, copilotAskRequestFor(t, "ws-1", "", map[string]any{"message": "help", "agent_type": "support"}))
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	"github.com/matiasleandrokruk/fenix/internal/domain/copilot"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 type CopilotChatService interface {
@@ -47,14 +49,14 @@ func (h *CopilotChatHandler) Chat(w http.ResponseWriter, r *http.Request) {
 
 	stream, err := h.chatService.Chat(r.Context(), input)
 	if err != nil {
-		log.Printf("[copilot] chat failed: %v", err)
-		writeError(w, http.StatusInternalServerError, "chat failed")
+		logging.FromContext(r.Context()).Error("copilot chat failed", slog.String(logging.KeyComponent, "copilot"), logging.Err(err))
+		writeError(w, http.StatusInternalServerError, codeInternal, "chat failed")
 		return
 	}
 
 	bw, flusher, err := prepareCopilotChatStream(w)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		writeError(w, http.StatusInternalServerError, codeInternal, "streaming not supported")
 		return
 	}
 	streamCopilotChunks(bw, flusher, stream)
@@ -62,9 +64,10 @@ func (h *CopilotChatHandler) Chat(w http.ResponseWriter, r *http.Request) {
 
 func buildCopilotChatInput(r *http.Request) (copilot.ChatInput, error) {
 	ctx := r.Context()
-	wsID, err := getWorkspaceID(ctx)
-	if err != nil {
-		return copilot.ChatInput{}, chatRequestError{status: http.StatusUnauthorized, message: "missing workspace context"}
+	wsID, ok := workspaceIDFromRequest(r)
+	if !ok {
+		status, message := missingWorkspaceError(r)
+		return copilot.ChatInput{}, chatRequestError{status: status, message: message}
 	}
 
 	userID, _ := ctx.Value(ctxkeys.UserID).(string)
@@ -93,6 +96,9 @@ func prepareCopilotChatStream(w http.ResponseWriter) (*bufio.Writer, http.Flushe
 	w.Header().Set(headerContentType, "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
+	// The stream lasts as long as the answer; lift the server's WriteTimeout.
+	// Best effort: writers that cannot clear it keep the default.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
 
 	flusher, ok := w.(http.Flusher)
 	if !ok {
@@ -116,10 +122,10 @@ func streamCopilotChunks(bw *bufio.Writer, flusher http.Flusher, stream <-chan c
 func writeCopilotChatError(w http.ResponseWriter, err error) {
 	var reqErr chatRequestError
 	if ok := errorAs(err, &reqErr); ok {
-		writeError(w, reqErr.status, reqErr.message)
+		writeError(w, reqErr.status, codeForStatus(reqErr.status), reqErr.message)
 		return
 	}
-	writeError(w, http.StatusInternalServerError, "chat failed")
+	writeError(w, http.StatusInternalServerError, codeInternal, "chat failed")
 }
 
 func errorAs(err error, target *chatRequestError) bool {
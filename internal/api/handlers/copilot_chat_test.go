@@ -123,8 +123,8 @@ func TestCopilotChatHandler_Validation(t *testing.T) {
 		req = req.WithContext(context.WithValue(req.Context(), ctxkeys.UserID, "u_1"))
 		rr := httptest.NewRecorder()
 		h.Chat(rr, req)
-		if rr.Code != http.StatusUnauthorized {
-			t.Fatalf("expected 401, got %d", rr.Code)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d", rr.Code)
 		}
 	})
 
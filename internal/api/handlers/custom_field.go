@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+// CustomFieldHandler serves the workspace's account and deal custom field
+// definitions.
+type CustomFieldHandler struct{ service *crm.CustomFieldService }
+
+func NewCustomFieldHandler(service *crm.CustomFieldService) *CustomFieldHandler {
+	return &CustomFieldHandler{service: service}
+}
+
+type CreateCustomFieldRequest struct {
+	EntityType string `json:"entityType"` // account|deal
+	Name       string `json:"name"`
+	Type       string `json:"type"` // string|number|boolean|date
+	Required   bool   `json:"required,omitempty"`
+}
+
+// ListCustomFields handles GET /api/v1/custom-fields[?entityType=account|deal]
+func (h *CustomFieldHandler) ListCustomFields(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	defs, err := h.service.List(r.Context(), wsID, r.URL.Query().Get("entityType"))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list custom fields: %v", err))
+		return
+	}
+	_ = writeJSONOr500(w, map[string]any{"data": defs})
+}
+
+// CreateCustomField handles POST /api/v1/custom-fields
+// Returns 400 for an invalid definition and 409 when the name is taken.
+func (h *CustomFieldHandler) CreateCustomField(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	var req CreateCustomFieldRequest
+	if !decodeBodyJSON(w, r, &req) {
+		return
+	}
+	if fields := requireFields(map[string]string{"entityType": req.EntityType, "name": req.Name, "type": req.Type}); len(fields) > 0 {
+		writeValidationError(w, fields)
+		return
+	}
+	def, err := h.service.Create(r.Context(), crm.CreateCustomFieldDefInput{
+		WorkspaceID: wsID,
+		EntityType:  req.EntityType,
+		Name:        req.Name,
+		Type:        req.Type,
+		Required:    req.Required,
+	})
+	switch {
+	case errors.Is(err, crm.ErrInvalidCustomFieldDef):
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	case errors.Is(err, crm.ErrCustomFieldDefExists):
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create custom field: %v", err))
+		return
+	}
+	writeCreatedJSON(w, def)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestCustomFieldHandler_CreateAndList(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	h := NewCustomFieldHandler(crm.NewCustomFieldService(db))
+
+	create := func(payload map[string]any) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/custom-fields", bytes.NewReader(body))
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+		h.CreateCustomField(rr, req)
+		return rr
+	}
+
+	if rr := create(map[string]any{"entityType": "account", "name": "seats", "type": "number"}); rr.Code != http.StatusCreated {
+		t.Fatalf("create: expected 201, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	if rr := create(map[string]any{"entityType": "account", "name": "seats", "type": "number"}); rr.Code != http.StatusConflict {
+		t.Fatalf("duplicate: expected 409, got %d", rr.Code)
+	}
+	if rr := create(map[string]any{"entityType": "account", "name": "tier", "type": "enum"}); rr.Code != http.StatusBadRequest {
+		t.Fatalf("unknown type: expected 400, got %d", rr.Code)
+	}
+	if rr := create(map[string]any{"entityType": "account"}); rr.Code != http.StatusBadRequest {
+		t.Fatalf("missing fields: expected 400, got %d", rr.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/custom-fields?entityType=account", nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	h.ListCustomFields(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list: expected 200, got %d", rr.Code)
+	}
+	var resp struct {
+		Data []crm.CustomFieldDef `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].Name != "seats" || resp.Data[0].Type != "number" {
+		t.Fatalf("list data = %+v; want the seats number field", resp.Data)
+	}
+}
+
+func TestAccountHandler_CreateAccount_InvalidCustomFields_Returns400(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	if _, err := crm.NewCustomFieldService(db).Create(t.Context(), crm.CreateCustomFieldDefInput{
+		WorkspaceID: wsID, EntityType: "account", Name: "seats", Type: crm.CustomFieldTypeNumber,
+	}); err != nil {
+		t.Fatalf("define seats: %v", err)
+	}
+	h := NewAccountHandler(crm.NewAccountService(db))
+
+	body, _ := json.Marshal(map[string]any{"name": "Acme", "ownerId": ownerID, "customFields": map[string]any{"seats": "ten"}})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/accounts", bytes.NewReader(body))
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	h.CreateAccount(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d body=%s", rr.Code, rr.Body.String())
+	}
+}
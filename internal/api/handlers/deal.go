@@ -37,6 +37,8 @@ type CreateDealRequest struct {
 	ExpectedClose string   `json:"expectedClose,omitempty"`
 	Status        string   `json:"status,omitempty"`
 	Metadata      string   `json:"metadata,omitempty"`
+	// CustomFields must match the workspace's deal field definitions.
+	CustomFields map[string]any `json:"customFields,omitempty"`
 }
 
 type UpdateDealRequest = CreateDealRequest
@@ -51,7 +53,7 @@ func (h *DealHandler) CreateDeal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !isDealRequestValid(req) {
-		writeError(w, http.StatusBadRequest, "accountId, pipelineId, stageId, ownerId and title are required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "accountId, pipelineId, stageId, ownerId and title are required")
 		return
 	}
 	out, svcErr := h.service.Create(r.Context(), crm.CreateDealInput{
@@ -67,16 +69,18 @@ func (h *DealHandler) CreateDeal(w http.ResponseWriter, r *http.Request) {
 		ExpectedClose: req.ExpectedClose,
 		Status:        req.Status,
 		Metadata:      req.Metadata,
+		CustomFields:  req.CustomFields,
 	})
 	if svcErr != nil {
-		if errors.Is(svcErr, crm.ErrInvalidDealInput) {
-			writeError(w, http.StatusBadRequest, svcErr.Error())
+		if errors.Is(svcErr, crm.ErrInvalidDealInput) || errors.Is(svcErr, crm.ErrInvalidCustomFields) {
+			writeError(w, http.StatusBadRequest, codeBadRequest, svcErr.Error())
 			return
 		}
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create deal: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create deal: %v", svcErr))
 		return
 	}
 	w.Header().Set(headerContentType, mimeJSON)
+	setLocation(w, "deals", out.ID)
 	w.WriteHeader(http.StatusCreated)
 	_ = writeJSONOr500(w, out)
 }
@@ -95,7 +99,7 @@ func (h *DealHandler) GetDeal(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chi.URLParam(r, paramID)
 	out, svcErr := h.service.Get(r.Context(), wsID, id)
-	if handleGetError(w, svcErr, errDealNotFound, "failed to get deal: %v") {
+	if handleGetError(w, svcErr, codeDealNotFound, errDealNotFound, "failed to get deal: %v") {
 		return
 	}
 	h.attachActiveSignalCount(r.Context(), wsID, out)
@@ -112,12 +116,12 @@ func (h *DealHandler) ListDeals(w http.ResponseWriter, r *http.Request) {
 	page := parsePaginationParams(r)
 	input, err := parseDealListInput(r, page)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 	items, total, svcErr := h.service.List(r.Context(), wsID, input)
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list deals: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list deals: %v", svcErr))
 		return
 	}
 	counts := countActiveSignalsByEntity(r.Context(), h.signalCounter, wsID, "deal", collectEntityIDs(items, func(item *crm.Deal) string {
@@ -128,11 +132,37 @@ func (h *DealHandler) ListDeals(w http.ResponseWriter, r *http.Request) {
 			item.ActiveSignalCount = intPtr(count)
 		}
 	}
-	if !writePaginatedOr500(w, items, total, page) {
+	if !writePaginated(w, items, total, page.Limit, page.Offset) {
 		return
 	}
 }
 
+// dealForecastResponse is the body of GET /api/v1/deals/forecast.
+type dealForecastResponse struct {
+	PipelineID       string  `json:"pipelineId"`
+	WeightedForecast float64 `json:"weightedForecast"`
+}
+
+// Forecast handles GET /api/v1/deals/forecast?pipeline_id=: the sum of
+// amount × stage probability over the pipeline's open deals.
+func (h *DealHandler) Forecast(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	pipelineID := strings.TrimSpace(r.URL.Query().Get("pipeline_id"))
+	if pipelineID == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "pipeline_id is required")
+		return
+	}
+	forecast, err := h.service.WeightedForecast(r.Context(), wsID, pipelineID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to forecast deals: %v", err))
+		return
+	}
+	_ = writeJSONOr500(w, dealForecastResponse{PipelineID: pipelineID, WeightedForecast: forecast})
+}
+
 func parseDealListInput(r *http.Request, page paginationParams) (crm.ListDealsInput, error) {
 	q := r.URL.Query()
 	status := strings.TrimSpace(q.Get(queryStatus))
@@ -192,7 +222,7 @@ func (h *DealHandler) UpdateDeal(w http.ResponseWriter, r *http.Request) {
 
 	id := chiURLParamID(r)
 	existing, svcErr := h.service.Get(r.Context(), wsID, id)
-	if handleGetError(w, svcErr, errDealNotFound, "failed to get deal: %v") {
+	if handleGetError(w, svcErr, codeDealNotFound, errDealNotFound, "failed to get deal: %v") {
 		return
 	}
 
@@ -203,11 +233,11 @@ func (h *DealHandler) UpdateDeal(w http.ResponseWriter, r *http.Request) {
 
 	out, upErr := h.service.Update(r.Context(), wsID, id, buildUpdateDealInput(req, existing))
 	if upErr != nil {
-		if errors.Is(upErr, crm.ErrInvalidDealInput) {
-			writeError(w, http.StatusBadRequest, upErr.Error())
+		if errors.Is(upErr, crm.ErrInvalidDealInput) || errors.Is(upErr, crm.ErrInvalidCustomFields) {
+			writeError(w, http.StatusBadRequest, codeBadRequest, upErr.Error())
 			return
 		}
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to update deal: %v", upErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to update deal: %v", upErr))
 		return
 	}
 
@@ -229,9 +259,10 @@ func buildUpdateDealInput(req UpdateDealRequest, existing *crm.Deal) crm.UpdateD
 		ExpectedClose: req.ExpectedClose,
 		Status:        req.Status,
 		Metadata:      req.Metadata,
+		CustomFields:  req.CustomFields,
 	}
 }
 
 func (h *DealHandler) DeleteDeal(w http.ResponseWriter, r *http.Request) {
-	handleDeleteWithNotFound(w, r, errDealNotFound, sql.ErrNoRows, errDealNotFound, "failed to delete deal: %v", h.service.Delete)
+	handleDeleteWithNotFound(w, r, errDealNotFound, sql.ErrNoRows, codeDealNotFound, errDealNotFound, "failed to delete deal: %v", h.service.Delete)
 }
@@ -64,6 +64,51 @@ func TestDealHandler_CreateGetDelete(t *testing.T) {
 	}
 }
 
+func TestDealHandler_Forecast(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewDealService(db)
+	h := NewDealHandler(svc)
+
+	accountID := createAccountForTask15(t, db, wsID, ownerID, "Forecast Account")
+	pipelineID, stageID := createPipelineAndStageForTask15(t, db, wsID)
+	if _, err := db.Exec(`UPDATE pipeline_stage SET probability = 0.25 WHERE id = ?`, stageID); err != nil {
+		t.Fatalf("set stage probability: %v", err)
+	}
+	amount := 4000.0
+	if _, err := svc.Create(context.Background(), crm.CreateDealInput{
+		WorkspaceID: wsID, AccountID: accountID, PipelineID: pipelineID, StageID: stageID,
+		OwnerID: ownerID, Title: "Forecast deal", Amount: &amount,
+	}); err != nil {
+		t.Fatalf("create deal: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/deals/forecast?pipeline_id="+pipelineID, nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	w := httptest.NewRecorder()
+	h.Forecast(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Forecast status=%d body=%s", w.Code, w.Body.String())
+	}
+	var got dealForecastResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode forecast: %v", err)
+	}
+	if got.PipelineID != pipelineID || got.WeightedForecast != 1000 {
+		t.Fatalf("forecast = %+v; want %s weighted 1000", got, pipelineID)
+	}
+
+	missing := httptest.NewRequest(http.MethodGet, "/api/v1/deals/forecast", nil)
+	missing = missing.WithContext(contextWithWorkspaceID(missing.Context(), wsID))
+	missingW := httptest.NewRecorder()
+	h.Forecast(missingW, missing)
+	if missingW.Code != http.StatusBadRequest {
+		t.Fatalf("Forecast without pipeline_id status=%d; want 400", missingW.Code)
+	}
+}
+
 func TestDealHandler_CreateDeal_MissingWorkspace_Returns400(t *testing.T) {
 	t.Parallel()
 
@@ -72,6 +117,7 @@ func TestDealHandler_CreateDeal_MissingWorkspace_Returns400(t *testing.T) {
 
 	body, _ := json.Marshal(map[string]any{"title": "x"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/deals", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 
 	rr := httptest.NewRecorder()
 	h.CreateDeal(rr, req)
@@ -154,6 +200,7 @@ func TestDealHandler_ListDeals_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewDealHandler(crm.NewDealService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/deals", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	h.ListDeals(rr, req)
 
@@ -169,6 +216,7 @@ func TestDealHandler_GetDeal_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewDealHandler(crm.NewDealService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/deals/d1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "d1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -188,6 +236,7 @@ func TestDealHandler_UpdateDeal_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewDealHandler(crm.NewDealService(db))
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/deals/d1", bytes.NewBufferString(`{"title":"u"}`))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "d1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -207,6 +256,7 @@ func TestDealHandler_DeleteDeal_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewDealHandler(crm.NewDealService(db))
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/deals/d1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "d1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
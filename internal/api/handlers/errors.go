@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Error codes identify the kind of an error response. Unlike messages they
+// are stable, so clients can branch on them.
+const (
+	// Generic codes, one per status, for errors without a specific code.
+	codeBadRequest          = "BAD_REQUEST"
+	codeUnauthorized        = "UNAUTHORIZED"
+	codeForbidden           = "FORBIDDEN"
+	codeNotFound            = "NOT_FOUND"
+	codeConflict            = "CONFLICT"
+	codeGone                = "GONE"
+	codePreconditionFailed  = "PRECONDITION_FAILED"
+	codePayloadTooLarge     = "PAYLOAD_TOO_LARGE"
+	codeUnprocessableEntity = "UNPROCESSABLE_ENTITY"
+	codeRateLimited         = "RATE_LIMITED"
+	codeInternal            = "INTERNAL_ERROR"
+	codeServiceUnavailable  = "SERVICE_UNAVAILABLE"
+
+	// Request codes
+	codeInvalidBody      = "INVALID_BODY"
+	codeValidationFailed = "VALIDATION_FAILED"
+
+	// Resource codes
+	codeAccountDeleted     = "ACCOUNT_DELETED"
+	codeWorkspaceSlugTaken = "WORKSPACE_SLUG_TAKEN"
+
+	// Not-found codes, one per resource
+	codeAccountNotFound         = "ACCOUNT_NOT_FOUND"
+	codeActivityNotFound        = "ACTIVITY_NOT_FOUND"
+	codeAgentDefinitionNotFound = "AGENT_DEFINITION_NOT_FOUND"
+	codeAgentRunNotFound        = "AGENT_RUN_NOT_FOUND"
+	codeApprovalNotFound        = "APPROVAL_NOT_FOUND"
+	codeAttachmentNotFound      = "ATTACHMENT_NOT_FOUND"
+	codeAuditEventNotFound      = "AUDIT_EVENT_NOT_FOUND"
+	codeCaseNotFound            = "CASE_NOT_FOUND"
+	codeContactNotFound         = "CONTACT_NOT_FOUND"
+	codeDealNotFound            = "DEAL_NOT_FOUND"
+	codeEvalBenchmarkNotFound   = "EVAL_BENCHMARK_NOT_FOUND"
+	codeEvalRunNotFound         = "EVAL_RUN_NOT_FOUND"
+	codeEvalSuiteNotFound       = "EVAL_SUITE_NOT_FOUND"
+	codeKnowledgeItemNotFound   = "KNOWLEDGE_ITEM_NOT_FOUND"
+	codeLeadNotFound            = "LEAD_NOT_FOUND"
+	codeNoteNotFound            = "NOTE_NOT_FOUND"
+	codePipelineNotFound        = "PIPELINE_NOT_FOUND"
+	codePromptVersionNotFound   = "PROMPT_VERSION_NOT_FOUND"
+	codeQuotaStateNotFound      = "QUOTA_STATE_NOT_FOUND"
+	codeSignalNotFound          = "SIGNAL_NOT_FOUND"
+	codeStageNotFound           = "STAGE_NOT_FOUND"
+	codeToolNotFound            = "TOOL_NOT_FOUND"
+	codeWorkflowNotFound        = "WORKFLOW_NOT_FOUND"
+	codeWorkspaceNotFound       = "WORKSPACE_NOT_FOUND"
+)
+
+// errorResponse is the body of every error response:
+//
+//	{"error": {"code": "ACCOUNT_NOT_FOUND", "message": "account not found"}}
+//
+// Fields is only set on VALIDATION_FAILED, naming each invalid field.
+type errorResponse struct {
+	Error errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+const errFailedToEncodeError = `{"error":{"code":"INTERNAL_ERROR","message":"failed to encode error response"}}`
+
+// writeError writes a JSON error response with the machine-readable code
+// and the human-readable message.
+func writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	writeErrorResponse(w, statusCode, errorDetail{Code: code, Message: message})
+}
+
+// writeValidationError writes a 400 response naming each invalid field (JSON
+// name → problem) so clients can flag the exact form inputs. Use writeError
+// for errors not tied to a field.
+func writeValidationError(w http.ResponseWriter, fields map[string]string) {
+	writeErrorResponse(w, http.StatusBadRequest, errorDetail{Code: codeValidationFailed, Message: errValidationFailed, Fields: fields})
+}
+
+func writeErrorResponse(w http.ResponseWriter, statusCode int, detail errorDetail) {
+	w.Header().Set(headerContentType, mimeJSON)
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(errorResponse{Error: detail}); err != nil {
+		http.Error(w, errFailedToEncodeError, http.StatusInternalServerError)
+	}
+}
+
+// codeForStatus returns the generic code of statusCode, for errors whose
+// status is only known at run time.
+func codeForStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codeBadRequest
+	case http.StatusUnauthorized:
+		return codeUnauthorized
+	case http.StatusForbidden:
+		return codeForbidden
+	case http.StatusNotFound:
+		return codeNotFound
+	case http.StatusConflict:
+		return codeConflict
+	case http.StatusGone:
+		return codeGone
+	case http.StatusPreconditionFailed:
+		return codePreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return codePayloadTooLarge
+	case http.StatusUnprocessableEntity:
+		return codeUnprocessableEntity
+	case http.StatusTooManyRequests:
+		return codeRateLimited
+	case http.StatusServiceUnavailable:
+		return codeServiceUnavailable
+	}
+	if statusCode >= http.StatusInternalServerError {
+		return codeInternal
+	}
+	return codeBadRequest
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestErrorResponses_CarryStableCodes(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	accounts := NewAccountHandler(crm.NewAccountService(db))
+	cases := NewCaseHandler(crm.NewCaseService(db))
+	agents := NewAgentHandler(agent.NewOrchestrator(db))
+
+	request := func(method, body, id string) *http.Request {
+		req := httptest.NewRequest(method, "/", bytes.NewReader([]byte(body)))
+		ctx := contextWithWorkspaceID(req.Context(), wsID)
+		if id != "" {
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+		}
+		return req.WithContext(ctx)
+	}
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		req        *http.Request
+		wantStatus int
+		wantCode   string
+	}{
+		{"account not found", accounts.GetAccount, request(http.MethodGet, "", "missing"), http.StatusNotFound, "ACCOUNT_NOT_FOUND"},
+		{"case not found", cases.GetCase, request(http.MethodGet, "", "missing"), http.StatusNotFound, "CASE_NOT_FOUND"},
+		{"agent run not found", agents.GetAgentRun, request(http.MethodGet, "", "missing"), http.StatusNotFound, "AGENT_RUN_NOT_FOUND"},
+		{"agent definition not found", agents.TriggerAgent, request(http.MethodPost, `{"agent_id":"missing","trigger_type":"manual"}`, ""), http.StatusNotFound, "AGENT_DEFINITION_NOT_FOUND"},
+		{"invalid body", accounts.CreateAccount, request(http.MethodPost, "{", ""), http.StatusBadRequest, "INVALID_BODY"},
+		{"validation failed", accounts.CreateAccount, request(http.MethodPost, `{"name":"Acme"}`, ""), http.StatusBadRequest, "VALIDATION_FAILED"},
+		{"missing workspace", accounts.ListAccounts, httptest.NewRequest(http.MethodGet, "/", nil), http.StatusUnauthorized, "UNAUTHORIZED"},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		tt.handler(rr, tt.req)
+		if rr.Code != tt.wantStatus {
+			t.Fatalf("%s: status = %d; want %d body=%s", tt.name, rr.Code, tt.wantStatus, rr.Body.String())
+		}
+		var resp errorResponse
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: decode error body: %v body=%s", tt.name, err, rr.Body.String())
+		}
+		if resp.Error.Code != tt.wantCode || resp.Error.Message == "" {
+			t.Fatalf("%s: error = %+v; want code %s with a message", tt.name, resp.Error, tt.wantCode)
+		}
+	}
+}
+
+func TestCodeForStatus(t *testing.T) {
+	t.Parallel()
+
+	for status, want := range map[int]string{
+		http.StatusBadRequest:          codeBadRequest,
+		http.StatusUnauthorized:        codeUnauthorized,
+		http.StatusNotFound:            codeNotFound,
+		http.StatusTooManyRequests:     codeRateLimited,
+		http.StatusInternalServerError: codeInternal,
+		http.StatusBadGateway:          codeInternal,
+		http.StatusTeapot:              codeBadRequest,
+	} {
+		if got := codeForStatus(status); got != want {
+			t.Errorf("codeForStatus(%d) = %s; want %s", status, got, want)
+		}
+	}
+}
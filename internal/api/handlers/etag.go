@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// weakETag builds a weak validator from a resource's id and last update.
+// extra carries derived values the response includes that do not bump
+// updated_at (e.g. counts joined from other tables).
+func weakETag(id string, updatedAt time.Time, extra ...string) string {
+	parts := append([]string{id, strconv.FormatInt(updatedAt.UTC().UnixNano(), 36)}, extra...)
+	return `W/"` + strings.Join(parts, "-") + `"`
+}
+
+// writeNotModified sets the ETag header and, when the request's
+// If-None-Match matches etag, answers 304 Not Modified. It reports whether
+// the response was written; callers write the full body otherwise.
+func writeNotModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set(headerETag, etag)
+	if !etagMatches(r.Header.Get(headerIfNoneMatch), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches applies the weak comparison If-None-Match requires (RFC 9110
+// §13.1.2): the W/ prefix is ignored on both sides.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
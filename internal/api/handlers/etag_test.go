@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWeakETag_ChangesWithUpdateAndExtras(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	base := weakETag("acc-1", at)
+	if base[:3] != `W/"` {
+		t.Fatalf("etag = %s; want weak validator", base)
+	}
+	if base != weakETag("acc-1", at.In(time.FixedZone("x", 3600))) {
+		t.Fatal("etag depends on the time zone of updatedAt")
+	}
+	for _, other := range []string{
+		weakETag("acc-2", at),
+		weakETag("acc-1", at.Add(time.Millisecond)),
+		weakETag("acc-1", at, "3"),
+	} {
+		if other == base {
+			t.Fatalf("etag %s did not change", other)
+		}
+	}
+}
+
+func TestWriteNotModified(t *testing.T) {
+	t.Parallel()
+
+	etag := weakETag("run-1", time.Unix(1700000000, 0), "running")
+	cases := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{name: "no header", want: false},
+		{name: "exact", ifNoneMatch: etag, want: true},
+		{name: "strong form", ifNoneMatch: etag[2:], want: true},
+		{name: "in list", ifNoneMatch: `"other", ` + etag, want: true},
+		{name: "wildcard", ifNoneMatch: "*", want: true},
+		{name: "stale", ifNoneMatch: `W/"run-1-old"`, want: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.ifNoneMatch != "" {
+				req.Header.Set(headerIfNoneMatch, tc.ifNoneMatch)
+			}
+			rr := httptest.NewRecorder()
+
+			if got := writeNotModified(rr, req, etag); got != tc.want {
+				t.Fatalf("writeNotModified() = %v; want %v", got, tc.want)
+			}
+			if rr.Header().Get(headerETag) != etag {
+				t.Fatalf("ETag = %q; want %q", rr.Header().Get(headerETag), etag)
+			}
+			if tc.want && rr.Code != http.StatusNotModified {
+				t.Fatalf("status = %d; want 304", rr.Code)
+			}
+		})
+	}
+}
@@ -74,7 +74,7 @@ func (h *EvalHandler) CreateSuite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !isCreateSuiteRequestValid(req) {
-		writeError(w, http.StatusBadRequest, errEvalSuiteNameRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errEvalSuiteNameRequired)
 		return
 	}
 	suite, err := h.suites.Create(r.Context(), domaineval.CreateSuiteInput{
@@ -85,10 +85,11 @@ func (h *EvalHandler) CreateSuite(w http.ResponseWriter, r *http.Request) {
 		Thresholds:  req.Thresholds,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create eval suite: %v", err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create eval suite: %v", err))
 		return
 	}
 	w.Header().Set(headerContentType, mimeJSON)
+	setLocation(w, evalSuitesCollection, suite.ID)
 	w.WriteHeader(http.StatusCreated)
 	_ = writeJSONOr500(w, suite)
 }
@@ -106,7 +107,7 @@ func (h *EvalHandler) ListSuites(w http.ResponseWriter, r *http.Request) {
 	}
 	suites, err := h.suites.List(r.Context(), wsID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list eval suites: %v", err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list eval suites: %v", err))
 		return
 	}
 	_ = writeJSONOr500(w, map[string]any{"data": suites})
@@ -125,7 +126,7 @@ func (h *EvalHandler) GetSuite(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chi.URLParam(r, paramID)
 	suite, err := h.suites.GetByID(r.Context(), wsID, id)
-	if handleGetError(w, err, errEvalSuiteNotFound, "failed to get eval suite: %v") {
+	if handleGetError(w, err, codeEvalSuiteNotFound, errEvalSuiteNotFound, "failed to get eval suite: %v") {
 		return
 	}
 	_ = writeJSONOr500(w, suite)
@@ -199,7 +200,7 @@ func (h *EvalHandler) CreateBenchmark(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if h.benchmarks == nil {
-		writeError(w, http.StatusInternalServerError, "benchmark registry unavailable")
+		writeError(w, http.StatusInternalServerError, codeInternal, "benchmark registry unavailable")
 		return
 	}
 
@@ -212,7 +213,7 @@ func (h *EvalHandler) CreateBenchmark(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !isCreateBenchmarkRequestValid(req) {
-		writeError(w, http.StatusBadRequest, "slug, name, and domain are required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "slug, name, and domain are required")
 		return
 	}
 
@@ -228,14 +229,36 @@ func (h *EvalHandler) CreateBenchmark(w http.ResponseWriter, r *http.Request) {
 		Tags:            req.Tags,
 	})
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create benchmark case: %v", err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create benchmark case: %v", err))
 		return
 	}
 	w.Header().Set(headerContentType, mimeJSON)
+	setLocation(w, evalBenchmarksCollection, benchmarkCase.ID)
 	w.WriteHeader(http.StatusCreated)
 	_ = writeJSONOr500(w, benchmarkCase)
 }
 
+// GetBenchmark — GET /api/v1/admin/eval/benchmarks/{id}
+func (h *EvalHandler) GetBenchmark(w http.ResponseWriter, r *http.Request) {
+	if !checkActionAuthorization(w, r, h.authz, resourceAPI, "admin.eval.benchmarks.get") {
+		return
+	}
+	if h.benchmarks == nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, "benchmark registry unavailable")
+		return
+	}
+
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	benchmarkCase, err := h.benchmarks.GetByID(r.Context(), wsID, chi.URLParam(r, paramID))
+	if handleGetError(w, err, codeEvalBenchmarkNotFound, errEvalBenchmarkNotFound, "failed to get benchmark case: %v") {
+		return
+	}
+	_ = writeJSONOr500(w, benchmarkCase)
+}
+
 // RunEval — POST /api/v1/admin/eval/run
 // Task 4.7: FR-242
 func (h *EvalHandler) RunEval(w http.ResponseWriter, r *http.Request) {
@@ -252,7 +275,7 @@ func (h *EvalHandler) RunEval(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !isRunEvalRequestValid(req) {
-		writeError(w, http.StatusBadRequest, errEvalSuiteIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errEvalSuiteIDRequired)
 		return
 	}
 	run, err := h.runEvalRequest(r.Context(), wsID, req)
@@ -298,18 +321,18 @@ func (h *EvalHandler) runEvalRequest(
 func writeRunEvalError(w http.ResponseWriter, err error) {
 	var replayErr *domaineval.ReplaySourceError
 	if errors.As(err, &replayErr) {
-		writeError(w, http.StatusUnprocessableEntity, replayErr.Error())
+		writeError(w, http.StatusUnprocessableEntity, codeUnprocessableEntity, replayErr.Error())
 		return
 	}
 	if errors.Is(err, sql.ErrNoRows) {
 		if strings.Contains(err.Error(), "benchmark case") {
-			writeError(w, http.StatusNotFound, errEvalBenchmarkNotFound)
+			writeError(w, http.StatusNotFound, codeEvalBenchmarkNotFound, errEvalBenchmarkNotFound)
 			return
 		}
-		writeError(w, http.StatusNotFound, errEvalSuiteNotFound)
+		writeError(w, http.StatusNotFound, codeEvalSuiteNotFound, errEvalSuiteNotFound)
 		return
 	}
-	writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to run eval: %v", err))
+	writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to run eval: %v", err))
 }
 
 // ListRuns — GET /api/v1/admin/eval/runs
@@ -326,7 +349,7 @@ func (h *EvalHandler) ListRuns(w http.ResponseWriter, r *http.Request) {
 	page := parsePaginationParams(r)
 	runs, err := h.runner.ListRuns(r.Context(), wsID, page.Limit, page.Offset)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list eval runs: %v", err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list eval runs: %v", err))
 		return
 	}
 	_ = writeJSONOr500(w, map[string]any{"data": runs})
@@ -345,7 +368,7 @@ func (h *EvalHandler) GetRun(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chi.URLParam(r, paramID)
 	run, err := h.runner.GetRun(r.Context(), wsID, id)
-	if handleGetError(w, err, errEvalRunNotFound, "failed to get eval run: %v") {
+	if handleGetError(w, err, codeEvalRunNotFound, errEvalRunNotFound, "failed to get eval run: %v") {
 		return
 	}
 	_ = writeJSONOr500(w, run)
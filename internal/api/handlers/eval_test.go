@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -19,12 +20,10 @@ import (
 // Task 4.7: FR-242
 func mustOpenDBWithMigrationsEval(t *testing.T) *sql.DB {
 	t.Helper()
-	db, err := sqlite.NewDB(":memory:")
+	db, err := sqlite.NewDBWithOptions(":memory:", sqlite.MemoryOptions())
 	if err != nil {
 		t.Fatalf("NewDB: %v", err)
 	}
-	db.SetMaxOpenConns(1)
-	db.SetMaxIdleConns(1)
 	t.Cleanup(func() { db.Close() })
 	if err := sqlite.MigrateUp(db); err != nil {
 		t.Fatalf("MigrateUp: %v", err)
@@ -390,6 +389,7 @@ func TestEvalHandler_MissingWorkspaceID_400(t *testing.T) {
 	h := NewEvalHandler(eval.NewSuiteService(db), eval.NewRunnerService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/eval/suites", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 
 	h.ListSuites(rr, req)
@@ -449,6 +449,7 @@ func TestEvalHandler_RunEval_MissingWorkspace_400(t *testing.T) {
 	db := mustOpenDBWithMigrationsEval(t)
 	h := NewEvalHandler(eval.NewSuiteService(db), eval.NewRunnerService(db))
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/eval/run", bytes.NewBufferString(`{"eval_suite_id":"x"}`))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	req.Header.Set("Content-Type", "application/json")
 	rr := httptest.NewRecorder()
 	h.RunEval(rr, req)
@@ -512,6 +513,28 @@ func TestEvalHandler_CreateBenchmark_201(t *testing.T) {
 	if resp["slug"] != "password-reset" {
 		t.Fatalf("expected slug password-reset, got %v", resp["slug"])
 	}
+	location := rr.Header().Get("Location")
+	if location != fmt.Sprintf("/api/v1/admin/eval/benchmarks/%v", resp["id"]) {
+		t.Fatalf("Location = %q; want the created benchmark case", location)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/admin/eval/benchmarks/{id}", h.GetBenchmark)
+	get := httptest.NewRequest(http.MethodGet, location, nil)
+	get = get.WithContext(contextWithWorkspaceIDEval(get.Context(), wsID))
+	got := httptest.NewRecorder()
+	r.ServeHTTP(got, get)
+	if got.Code != http.StatusOK {
+		t.Fatalf("GET Location = %d: %s", got.Code, got.Body.String())
+	}
+
+	missing := httptest.NewRequest(http.MethodGet, "/api/v1/admin/eval/benchmarks/nope", nil)
+	missing = missing.WithContext(contextWithWorkspaceIDEval(missing.Context(), wsID))
+	got = httptest.NewRecorder()
+	r.ServeHTTP(got, missing)
+	if got.Code != http.StatusNotFound {
+		t.Fatalf("GET unknown benchmark = %d; want 404", got.Code)
+	}
 }
 
 func TestEvalHandler_RunEval_ServiceError_500(t *testing.T) {
@@ -623,6 +646,7 @@ func TestEvalHandler_ListRuns_MissingWorkspace_400(t *testing.T) {
 	db := mustOpenDBWithMigrationsEval(t)
 	h := NewEvalHandler(eval.NewSuiteService(db), eval.NewRunnerService(db))
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/eval/runs", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	h.ListRuns(rr, req)
 	if rr.Code != http.StatusBadRequest {
@@ -68,13 +68,13 @@ func (h *GovernanceHandler) GetGovernanceSummary(w http.ResponseWriter, r *http.
 		return
 	}
 	if h.usage == nil {
-		writeError(w, http.StatusInternalServerError, errFailedToListUsageEvts)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToListUsageEvts)
 		return
 	}
 
 	events, err := h.usage.ListEvents(r.Context(), workspaceID, nil, governanceUsageLimit)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToListUsageEvts)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToListUsageEvts)
 		return
 	}
 
@@ -85,7 +85,7 @@ func (h *GovernanceHandler) GetGovernanceSummary(w http.ResponseWriter, r *http.
 
 	policies, err := h.usage.ListActivePolicies(r.Context(), workspaceID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToListPolicies)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToListPolicies)
 		return
 	}
 
@@ -107,7 +107,7 @@ func (h *GovernanceHandler) EraseRelationshipMemory(w http.ResponseWriter, r *ht
 		return
 	}
 	if h.lifecycle == nil {
-		writeError(w, http.StatusInternalServerError, errFailedToEraseRelationshipData)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToEraseRelationshipData)
 		return
 	}
 
@@ -116,17 +116,17 @@ func (h *GovernanceHandler) EraseRelationshipMemory(w http.ResponseWriter, r *ht
 		return
 	}
 	if req.EntityType == "" {
-		writeError(w, http.StatusBadRequest, errEntityTypeRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errEntityTypeRequired)
 		return
 	}
 	if req.EntityID == "" {
-		writeError(w, http.StatusBadRequest, errEntityIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errEntityIDRequired)
 		return
 	}
 
 	entityType := relationship.EntityType(req.EntityType)
 	if err := h.lifecycle.EraseEntityMemory(r.Context(), workspaceID, entityType, req.EntityID); err != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToEraseRelationshipData)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToEraseRelationshipData)
 		return
 	}
 
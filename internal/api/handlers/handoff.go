@@ -8,7 +8,6 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
 )
 
@@ -25,9 +24,8 @@ func NewHandoffHandler(service *agent.HandoffService) *HandoffHandler {
 // GetHandoffPackage handles GET /api/v1/agents/runs/{id}/handoff?case_id=<id>
 // Returns the handoff context for an escalated agent run (read-only).
 func (h *HandoffHandler) GetHandoffPackage(w http.ResponseWriter, r *http.Request) {
-	workspaceID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || workspaceID == "" {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
@@ -48,9 +46,8 @@ func (h *HandoffHandler) GetHandoffPackage(w http.ResponseWriter, r *http.Reques
 // InitiateHandoff handles POST /api/v1/agents/runs/{id}/handoff
 // Builds the handoff package, updates the case status, and emits an event.
 func (h *HandoffHandler) InitiateHandoff(w http.ResponseWriter, r *http.Request) {
-	workspaceID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || workspaceID == "" {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
@@ -61,7 +58,7 @@ func (h *HandoffHandler) InitiateHandoff(w http.ResponseWriter, r *http.Request)
 		Reason string `json:"reason"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 
@@ -80,10 +77,10 @@ func (h *HandoffHandler) InitiateHandoff(w http.ResponseWriter, r *http.Request)
 func (h *HandoffHandler) writeHandoffError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, agent.ErrAgentRunNotFound):
-		writeError(w, http.StatusNotFound, "agent run not found")
+		writeError(w, http.StatusNotFound, codeAgentRunNotFound, "agent run not found")
 	case errors.Is(err, agent.ErrHandoffCaseNotFound):
-		writeError(w, http.StatusNotFound, "case not found")
+		writeError(w, http.StatusNotFound, codeCaseNotFound, "case not found")
 	default:
-		writeError(w, http.StatusInternalServerError, "handoff failed")
+		writeError(w, http.StatusInternalServerError, codeInternal, "handoff failed")
 	}
 }
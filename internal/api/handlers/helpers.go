@@ -8,7 +8,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
@@ -19,17 +18,6 @@ type activeSignalCounter interface {
 	CountActiveByEntities(ctx context.Context, workspaceID, entityType string, entityIDs []string) (map[string]int, error)
 }
 
-// paginationParams holds parsed limit and offset values.
-type paginationParams struct {
-	Limit  int
-	Offset int
-}
-
-const (
-	defaultPaginationLimit = 25
-	maxPaginationLimit     = 100
-)
-
 // HTTP response string constants (extracted to satisfy goconst lint gate).
 const (
 	// resourceAPI is the resource type passed to checkActionAuthorization for API-level actions.
@@ -44,16 +32,17 @@ const (
 	timeFormatISO            = "2006-01-02T15:04:05Z"
 
 	// Error messages — workspace / auth
-	errMissingWorkspaceID      = "missing workspace_id in context"
-	errMissingWorkspaceContext = "missing workspace context"
-	errMissingWorkspaceShort   = "missing workspace_id"
+	errMissingWorkspaceID = "missing workspace_id in context"
+	errMissingUserContext = "missing user context"
 
 	// Error messages — request
-	errInvalidBody = "invalid request body"
+	errInvalidBody      = "invalid request body"
+	errValidationFailed = "validation failed"
+	errFieldRequired    = "is required"
 
 	// Error messages — encode
 	errFailedToEncode     = "failed to encode response"
-	errFailedToEncodeJSON = `{"error":"failed to encode response"}`
+	errFailedToEncodeJSON = `{"error":{"code":"INTERNAL_ERROR","message":"failed to encode response"}}`
 	errEmptyJSON          = "{}"
 
 	// Entity type constants for signal counting.
@@ -63,6 +52,7 @@ const (
 	// Error messages — account
 	errAccountIDRequired  = "account id is required"
 	errAccountNotFound    = "account not found"
+	errAccountDeleted     = "account has been deleted"
 	errFailedToGetAccount = "failed to get account: %v"
 
 	// Error messages — contact
@@ -107,40 +97,35 @@ const (
 	queryStatus    = "status"
 	querySortAsc   = "created_at"
 	querySortDesc  = "-created_at"
+	// queryIncludeDeleted=true makes single-record GETs answer 410 Gone,
+	// rather than 404, for soft-deleted records.
+	queryIncludeDeleted = "include_deleted"
 
 	// Error messages — workflow
 	errWorkflowIDRequired = "workflow id is required"
 )
 
-// getWorkspaceID retrieves workspace_id from context.
-// Uses ctxkeys.WorkspaceID — same type+value as WorkspaceMiddleware injection.
-// This eliminates the silent type mismatch between different context key types (TD-1).
-func getWorkspaceID(ctx context.Context) (string, error) {
-	wsID, ok := ctx.Value(ctxkeys.WorkspaceID).(string)
-	if !ok || wsID == "" {
-		return "", fmt.Errorf("workspace_id not found in context")
-	}
-	return wsID, nil
+// workspaceIDFromRequest returns the workspace the request is scoped to.
+// Uses ctxkeys.WorkspaceID — same type+value as WorkspaceMiddleware injection
+// (TD-1); it is the only place handlers read the workspace from.
+//
+// Convention when the workspace is missing (see missingWorkspaceError):
+//   - 401 Unauthorized when the request is not authenticated at all, i.e. no
+//     user in context — the client must (re)authenticate.
+//   - 400 Bad Request when the user is authenticated but the request carries
+//     no workspace — the client must fix the request, not its credentials.
+func workspaceIDFromRequest(r *http.Request) (string, bool) {
+	wsID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
+	return wsID, ok && wsID != ""
 }
 
-// parsePaginationParams extracts and validates limit/offset from URL query params.
-// Extracted to reduce cyclomatic complexity of ListAccounts (was 11, now isolated here).
-func parsePaginationParams(r *http.Request) paginationParams {
-	limit := defaultPaginationLimit
-	offset := 0
-
-	if lim, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && lim > 0 {
-		if lim > maxPaginationLimit {
-			lim = maxPaginationLimit
-		}
-		limit = lim
-	}
-
-	if off, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && off >= 0 {
-		offset = off
+// missingWorkspaceError returns the status and message for a request without
+// a workspace, following the convention documented on workspaceIDFromRequest.
+func missingWorkspaceError(r *http.Request) (int, string) {
+	if userID, _ := r.Context().Value(ctxkeys.UserID).(string); userID == "" {
+		return http.StatusUnauthorized, errMissingUserContext
 	}
-
-	return paginationParams{Limit: limit, Offset: offset}
+	return http.StatusBadRequest, errMissingWorkspaceID
 }
 
 // coalesce returns val if non-empty, otherwise returns fallback.
@@ -166,13 +151,14 @@ func coalescePtr(val string, fallback *string) string {
 // Required fields (Name, OwnerID) default to existing values if omitted.
 func buildUpdateInput(req UpdateAccountRequest, existing *crm.Account) crm.UpdateAccountInput {
 	input := crm.UpdateAccountInput{
-		Name:        req.Name,
-		Domain:      req.Domain,
-		Industry:    req.Industry,
-		SizeSegment: req.SizeSegment,
-		OwnerID:     req.OwnerID,
-		Address:     req.Address,
-		Metadata:    req.Metadata,
+		Name:         req.Name,
+		Domain:       req.Domain,
+		Industry:     req.Industry,
+		SizeSegment:  req.SizeSegment,
+		OwnerID:      req.OwnerID,
+		Address:      req.Address,
+		Metadata:     req.Metadata,
+		CustomFields: req.CustomFields,
 	}
 	if input.Name == "" {
 		input.Name = existing.Name
@@ -183,11 +169,65 @@ func buildUpdateInput(req UpdateAccountRequest, existing *crm.Account) crm.Updat
 	return input
 }
 
-// requireWorkspaceID obtiene workspace_id desde contexto y responde 400 cuando falta.
+// buildPatchInput overlays the fields present in a PATCH request on the
+// stored account.
+func buildPatchInput(req PatchAccountRequest, existing *crm.Account) crm.UpdateAccountInput {
+	return crm.UpdateAccountInput{
+		Name:         req.Name.apply(&existing.Name),
+		Domain:       req.Domain.apply(existing.Domain),
+		Industry:     req.Industry.apply(existing.Industry),
+		SizeSegment:  req.SizeSegment.apply(existing.SizeSegment),
+		OwnerID:      req.OwnerID.apply(&existing.OwnerID),
+		Address:      req.Address.apply(existing.Address),
+		Metadata:     req.Metadata.apply(existing.Metadata),
+		CustomFields: req.CustomFields,
+	}
+}
+
+// optionalString is a PATCH field that tells an absent key (Set false) from
+// an explicit null (Set true, Value nil) and from a value.
+type optionalString struct {
+	Set   bool
+	Value *string
+}
+
+func (o *optionalString) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// apply returns the PATCH value when the field was sent, otherwise the
+// stored one. Null and "" both clear the field.
+func (o optionalString) apply(existing *string) string {
+	if !o.Set {
+		return coalescePtr("", existing)
+	}
+	return coalescePtr("", o.Value)
+}
+
+// cleared reports whether the field was sent as null or "".
+func (o optionalString) cleared() bool {
+	return o.Set && (o.Value == nil || *o.Value == "")
+}
+
+// patchRequest is implemented by PATCH bodies with required fields;
+// clearedRequiredField returns the JSON name of a required field the body
+// clears, or "" when there is none.
+type patchRequest interface {
+	clearedRequiredField() string
+}
+
+// requireWorkspaceID obtiene workspace_id desde contexto y, cuando falta,
+// responde 401 o 400 según la convención de workspaceIDFromRequest.
 func requireWorkspaceID(w http.ResponseWriter, r *http.Request) (string, bool) {
-	wsID, wsErr := getWorkspaceID(r.Context())
-	if wsErr != nil {
-		writeError(w, http.StatusBadRequest, errMissingWorkspaceID)
+	wsID, ok := workspaceIDFromRequest(r)
+	if !ok {
+		status, message := missingWorkspaceError(r)
+		writeError(w, status, codeForStatus(status), message)
 		return "", false
 	}
 	return wsID, true
@@ -196,7 +236,7 @@ func requireWorkspaceID(w http.ResponseWriter, r *http.Request) (string, bool) {
 // decodeBodyJSON decodifica body JSON y responde 400 si es inválido.
 func decodeBodyJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
 	if decodeErr := json.NewDecoder(r.Body).Decode(dst); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return false
 	}
 	return true
@@ -206,20 +246,12 @@ func decodeBodyJSON(w http.ResponseWriter, r *http.Request, dst any) bool {
 func writeJSONOr500(w http.ResponseWriter, payload any) bool {
 	w.Header().Set(headerContentType, mimeJSON)
 	if encodeErr := json.NewEncoder(w).Encode(payload); encodeErr != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToEncode)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToEncode)
 		return false
 	}
 	return true
 }
 
-// writePaginatedOr500 escribe respuesta estándar paginada {data, meta}.
-func writePaginatedOr500(w http.ResponseWriter, items any, total int, page paginationParams) bool {
-	return writeJSONOr500(w, map[string]any{
-		"data": items,
-		"meta": Meta{Total: total, Limit: page.Limit, Offset: page.Offset},
-	})
-}
-
 func collectEntityIDs[T any](items []*T, idFn func(*T) string) []string {
 	ids := make([]string, 0, len(items))
 	for _, item := range items {
@@ -252,13 +284,13 @@ func countActiveSignalsByEntity(
 }
 
 // handleGetError unifica manejo ErrNoRows + error interno para endpoints Get.
-func handleGetError(w http.ResponseWriter, err error, notFoundMsg, internalFmt string) bool {
+func handleGetError(w http.ResponseWriter, err error, notFoundCode, notFoundMsg, internalFmt string) bool {
 	if errorsIsNoRows(err) {
-		writeError(w, http.StatusNotFound, notFoundMsg)
+		writeError(w, http.StatusNotFound, notFoundCode, notFoundMsg)
 		return true
 	}
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(internalFmt, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(internalFmt, err))
 		return true
 	}
 	return false
@@ -274,6 +306,7 @@ func getEntityForUpdate[T any](
 	r *http.Request,
 	wsID string,
 	idRequiredMsg string,
+	notFoundCode string,
 	notFoundMsg string,
 	internalFmt string,
 	getter func(context.Context, string, string) (*T, error),
@@ -281,17 +314,17 @@ func getEntityForUpdate[T any](
 	ctx := r.Context()
 	id := chiURLParamID(r)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, idRequiredMsg)
+		writeError(w, http.StatusBadRequest, codeBadRequest, idRequiredMsg)
 		return "", nil, false
 	}
 
 	existing, err := getter(ctx, wsID, id)
 	if errorsIsNoRows(err) {
-		writeError(w, http.StatusNotFound, notFoundMsg)
+		writeError(w, http.StatusNotFound, notFoundCode, notFoundMsg)
 		return "", nil, false
 	}
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(internalFmt, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(internalFmt, err))
 		return "", nil, false
 	}
 
@@ -304,11 +337,12 @@ func ensureEntityExistsBeforeDelete[T any](
 	r *http.Request,
 	wsID string,
 	idRequiredMsg string,
+	notFoundCode string,
 	notFoundMsg string,
 	internalFmt string,
 	getter func(context.Context, string, string) (*T, error),
 ) (string, bool) {
-	id, _, ok := getEntityForUpdate(w, r, wsID, idRequiredMsg, notFoundMsg, internalFmt, getter)
+	id, _, ok := getEntityForUpdate(w, r, wsID, idRequiredMsg, notFoundCode, notFoundMsg, internalFmt, getter)
 	if !ok {
 		return "", false
 	}
@@ -344,7 +378,7 @@ func handleMappedListWithPagination[T any, R any](
 	page := parsePaginationParams(r)
 	items, total, err := listFn(r.Context(), wsID, page.Limit, page.Offset)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(errFmt, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(errFmt, err))
 		return
 	}
 
@@ -353,7 +387,7 @@ func handleMappedListWithPagination[T any, R any](
 		mapped[i] = mapper(item)
 	}
 
-	_ = writePaginatedOr500(w, mapped, total, page)
+	_ = writePaginated(w, mapped, total, page.Limit, page.Offset)
 }
 
 // handleEntityUpdate centraliza el flujo común de UPDATE por entidad:
@@ -362,6 +396,7 @@ func handleEntityUpdate[Entity any, Req any, In any, Out any](
 	w http.ResponseWriter,
 	r *http.Request,
 	idRequiredMsg string,
+	notFoundCode string,
 	notFoundMsg string,
 	getErrFmt string,
 	updateErrFmt string,
@@ -374,7 +409,7 @@ func handleEntityUpdate[Entity any, Req any, In any, Out any](
 		return
 	}
 
-	id, existing, ok := getEntityForUpdate(w, r, wsID, idRequiredMsg, notFoundMsg, getErrFmt, getter)
+	id, existing, ok := getEntityForUpdate(w, r, wsID, idRequiredMsg, notFoundCode, notFoundMsg, getErrFmt, getter)
 	if !ok {
 		return
 	}
@@ -383,10 +418,24 @@ func handleEntityUpdate[Entity any, Req any, In any, Out any](
 	if !decodeBodyJSON(w, r, &req) {
 		return
 	}
+	if patch, isPatch := any(req).(patchRequest); isPatch {
+		if field := patch.clearedRequiredField(); field != "" {
+			writeError(w, http.StatusBadRequest, codeBadRequest, field+" cannot be cleared")
+			return
+		}
+	}
 
 	out, upErr := updater(r.Context(), wsID, id, buildInput(req, existing))
+	if errors.Is(upErr, crm.ErrInvalidLeadTransition) {
+		writeError(w, http.StatusConflict, codeConflict, upErr.Error())
+		return
+	}
+	if errors.Is(upErr, crm.ErrInvalidCustomFields) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, upErr.Error())
+		return
+	}
 	if upErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(updateErrFmt, upErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(updateErrFmt, upErr))
 		return
 	}
 
@@ -398,6 +447,7 @@ func handleDeleteWithNotFound(
 	r *http.Request,
 	idRequiredMsg string,
 	notFoundErr error,
+	notFoundCode string,
 	notFoundMsg string,
 	internalFmt string,
 	deleteFn func(context.Context, string, string) error,
@@ -409,16 +459,16 @@ func handleDeleteWithNotFound(
 
 	id := chiURLParamID(r)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, idRequiredMsg)
+		writeError(w, http.StatusBadRequest, codeBadRequest, idRequiredMsg)
 		return
 	}
 
 	if err := deleteFn(r.Context(), wsID, id); err != nil {
 		if notFoundErr != nil && errors.Is(err, notFoundErr) {
-			writeError(w, http.StatusNotFound, notFoundMsg)
+			writeError(w, http.StatusNotFound, notFoundCode, notFoundMsg)
 			return
 		}
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(internalFmt, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(internalFmt, err))
 		return
 	}
 
@@ -445,7 +495,7 @@ func handleAuthorizedDelete(
 
 	id := chiURLParamID(r)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, idRequiredMsg)
+		writeError(w, http.StatusBadRequest, codeBadRequest, idRequiredMsg)
 		return
 	}
 
@@ -461,6 +511,7 @@ func handleVerifiedDelete[T any](
 	w http.ResponseWriter,
 	r *http.Request,
 	idRequiredMsg string,
+	notFoundCode string,
 	notFoundMsg string,
 	internalGetFmt string,
 	internalDeleteFmt string,
@@ -472,13 +523,13 @@ func handleVerifiedDelete[T any](
 		return
 	}
 
-	id, ok := ensureEntityExistsBeforeDelete(w, r, wsID, idRequiredMsg, notFoundMsg, internalGetFmt, getter)
+	id, ok := ensureEntityExistsBeforeDelete(w, r, wsID, idRequiredMsg, notFoundCode, notFoundMsg, internalGetFmt, getter)
 	if !ok {
 		return
 	}
 
 	if err := deleteFn(r.Context(), wsID, id); err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(internalDeleteFmt, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(internalDeleteFmt, err))
 		return
 	}
 
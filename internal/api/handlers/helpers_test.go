@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireWorkspaceID_MissingWorkspaceStatusConvention(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		userID     string
+		wsID       string
+		wantOK     bool
+		wantStatus int
+	}{
+		{name: "unauthenticated", wantStatus: http.StatusUnauthorized},
+		{name: "authenticated without workspace", userID: "user-1", wantStatus: http.StatusBadRequest},
+		{name: "authenticated with workspace", userID: "user-1", wsID: "ws-1", wantOK: true, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts", nil)
+			if tt.userID != "" {
+				req = req.WithContext(contextWithUserID(req.Context(), tt.userID))
+			}
+			if tt.wsID != "" {
+				req = req.WithContext(contextWithWorkspaceID(req.Context(), tt.wsID))
+			}
+			rr := httptest.NewRecorder()
+
+			wsID, ok := requireWorkspaceID(rr, req)
+
+			if ok != tt.wantOK || wsID != tt.wsID {
+				t.Fatalf("requireWorkspaceID = (%q, %v); want (%q, %v)", wsID, ok, tt.wsID, tt.wantOK)
+			}
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d; want %d", rr.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -58,9 +58,8 @@ type evidenceSource struct {
 func (h *KnowledgeEvidenceHandler) Build(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	wsID, ok := knowledgeEvidenceWorkspaceID(ctx)
+	wsID, ok := requireWorkspaceID(w, r)
 	if !ok {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
 		return
 	}
 
@@ -81,19 +80,14 @@ func (h *KnowledgeEvidenceHandler) Build(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-func knowledgeEvidenceWorkspaceID(ctx context.Context) (string, bool) {
-	wsID, wsErr := getWorkspaceID(ctx)
-	return wsID, wsErr == nil
-}
-
 func decodeEvidenceRequest(w http.ResponseWriter, r *http.Request) (evidenceRequest, bool) {
 	var req evidenceRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return evidenceRequest{}, false
 	}
 	if req.Query == "" {
-		writeError(w, http.StatusBadRequest, "query is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "query is required")
 		return evidenceRequest{}, false
 	}
 	return req, true
@@ -106,7 +100,7 @@ func (h *KnowledgeEvidenceHandler) buildEvidencePack(ctx context.Context, w http
 		Limit:       req.Limit,
 	})
 	if buildErr != nil {
-		writeError(w, http.StatusInternalServerError, "failed to build evidence pack")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to build evidence pack")
 		return nil, false
 	}
 	return pack, true
@@ -1,11 +1,14 @@
 // Task 2.2: HTTP handler for knowledge ingestion.
 // POST /api/v1/knowledge/ingest — creates a knowledge_item + embedding_document chunks.
+// GET /api/v1/knowledge/items/{id} — returns one knowledge_item.
 package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
 )
 
@@ -32,10 +35,24 @@ type ingestRequest struct {
 	EntityType        *string `json:"entityType,omitempty"`
 	EntityID          *string `json:"entityId,omitempty"`
 	Metadata          *string `json:"metadata,omitempty"`
+	// DedupOnIngest returns an existing item with the same content instead
+	// of creating a new one.
+	DedupOnIngest bool `json:"dedupOnIngest,omitempty"`
+	// ChunkMetadata tags every chunk, e.g. {"source": "help_center"}.
+	ChunkMetadata map[string]string `json:"chunkMetadata,omitempty"`
+	// Sections replace rawContent with parts whose chunks carry their own
+	// metadata, e.g. {"section": "faq_answer"}.
+	Sections []ingestSectionRequest `json:"sections,omitempty"`
 }
 
-// ingestResponse is the JSON response body for a successful ingest.
-type ingestResponse struct {
+// ingestSectionRequest is one part of the content of an ingest request.
+type ingestSectionRequest struct {
+	Text     string            `json:"text"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// knowledgeItemResponse is the JSON body of a knowledge item.
+type knowledgeItemResponse struct {
 	ID                string  `json:"id"`
 	WorkspaceID       string  `json:"workspaceId"`
 	SourceSystem      *string `json:"sourceSystem,omitempty"`
@@ -50,24 +67,31 @@ type ingestResponse struct {
 	CreatedAt         string  `json:"createdAt"`
 }
 
+// ingestResponse is the JSON response body for a successful ingest.
+type ingestResponse struct {
+	knowledgeItemResponse
+	// Outcome is created, updated or unchanged (re-ingest with the same content).
+	Outcome      string `json:"outcome"`
+	WasDuplicate bool   `json:"wasDuplicate"`
+}
+
 // Ingest handles POST /api/v1/knowledge/ingest.
 func (h *KnowledgeIngestHandler) Ingest(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	wsID, wsErr := getWorkspaceID(ctx)
-	if wsErr != nil {
-		writeError(w, http.StatusUnauthorized, errMissingWorkspaceContext)
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
 	var req ingestRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 
 	if valErr := validateIngestRequest(req); valErr != nil {
-		writeError(w, http.StatusBadRequest, valErr.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, valErr.Error())
 		return
 	}
 
@@ -84,17 +108,56 @@ func (h *KnowledgeIngestHandler) Ingest(w http.ResponseWriter, r *http.Request)
 		EntityType:        req.EntityType,
 		EntityID:          req.EntityID,
 		Metadata:          req.Metadata,
+		DedupOnIngest:     req.DedupOnIngest,
+		ChunkMetadata:     req.ChunkMetadata,
+		Sections:          toContentSections(req.Sections),
 	}
 
 	item, ingestErr := h.ingestService.Ingest(ctx, input)
+	if errors.Is(ingestErr, knowledge.ErrInvalidSections) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, ingestErr.Error())
+		return
+	}
+	if errors.Is(ingestErr, knowledge.ErrQuotaExceeded) {
+		writeError(w, http.StatusTooManyRequests, codeRateLimited, ingestErr.Error())
+		return
+	}
 	if ingestErr != nil {
-		writeError(w, http.StatusInternalServerError, "failed to ingest knowledge item")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to ingest knowledge item")
 		return
 	}
 
+	status := http.StatusOK
+	if item.Outcome == knowledge.IngestOutcomeCreated {
+		status = http.StatusCreated
+		setLocation(w, knowledgeItemsCollection, item.ID)
+	}
 	w.Header().Set(headerContentType, mimeJSON)
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(status)
 	if encodeErr := json.NewEncoder(w).Encode(ingestResponse{
+		knowledgeItemResponse: toKnowledgeItemResponse(item),
+		Outcome:               string(item.Outcome),
+		WasDuplicate:          item.WasDuplicate,
+	}); encodeErr != nil {
+		http.Error(w, errFailedToEncodeJSON, http.StatusInternalServerError)
+	}
+}
+
+// Get handles GET /api/v1/knowledge/items/{id}.
+func (h *KnowledgeIngestHandler) Get(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	item, err := h.ingestService.Get(r.Context(), wsID, chi.URLParam(r, paramID))
+	if handleGetError(w, err, codeKnowledgeItemNotFound, "knowledge item not found", "failed to get knowledge item: %v") {
+		return
+	}
+	_ = writeJSONOr500(w, toKnowledgeItemResponse(item))
+}
+
+func toKnowledgeItemResponse(item *knowledge.KnowledgeItem) knowledgeItemResponse {
+	return knowledgeItemResponse{
 		ID:                item.ID,
 		WorkspaceID:       item.WorkspaceID,
 		SourceSystem:      item.SourceSystem,
@@ -107,8 +170,6 @@ func (h *KnowledgeIngestHandler) Ingest(w http.ResponseWriter, r *http.Request)
 		EntityType:        item.EntityType,
 		EntityID:          item.EntityID,
 		CreatedAt:         item.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
-	}); encodeErr != nil {
-		http.Error(w, errFailedToEncodeJSON, http.StatusInternalServerError)
 	}
 }
 
@@ -123,12 +184,26 @@ func validateIngestRequest(req ingestRequest) error {
 	if hasValue(req.SourceObjectID) && !hasValue(req.SourceSystem) {
 		return errorf("sourceSystem is required when sourceObjectId is provided")
 	}
+	if len(req.Sections) > 0 && req.RawContent != "" {
+		return errorf("rawContent and sections cannot both be provided")
+	}
 	if !isValidSourceType(req.SourceType) {
-		return errorf("invalid sourceType: must be one of document, email, call, note, case, ticket, kb_article, api, other")
+		return errorf("invalid sourceType: must be one of document, email, call, note, case, ticket, kb_article, api, url, other")
 	}
 	return nil
 }
 
+func toContentSections(sections []ingestSectionRequest) []knowledge.ContentSection {
+	if len(sections) == 0 {
+		return nil
+	}
+	out := make([]knowledge.ContentSection, len(sections))
+	for i, section := range sections {
+		out[i] = knowledge.ContentSection{Text: section.Text, Metadata: section.Metadata}
+	}
+	return out
+}
+
 func hasValue(s *string) bool {
 	return s != nil && *s != ""
 }
@@ -144,6 +219,7 @@ func isValidSourceType(s string) bool {
 		knowledge.SourceTypeTicket,
 		knowledge.SourceTypeKBArticle,
 		knowledge.SourceTypeAPI,
+		knowledge.SourceTypeURL,
 		knowledge.SourceTypeOther:
 		return true
 	}
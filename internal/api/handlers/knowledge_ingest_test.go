@@ -6,10 +6,12 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
 )
@@ -62,6 +64,35 @@ func TestKnowledgeIngestHandler_Success_Returns201(t *testing.T) {
 	if resp["sourceObjectId"] != "doc-42" {
 		t.Errorf("expected sourceObjectId doc-42, got %v", resp["sourceObjectId"])
 	}
+	location := rr.Header().Get("Location")
+	if location != fmt.Sprintf("/api/v1/knowledge/items/%v", resp["id"]) {
+		t.Fatalf("Location = %q; want the created knowledge item", location)
+	}
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/knowledge/items/{id}", handler.Get)
+	get := httptest.NewRequest(http.MethodGet, location, nil)
+	get = get.WithContext(contextWithWorkspaceID(get.Context(), wsID))
+	got := httptest.NewRecorder()
+	r.ServeHTTP(got, get)
+	if got.Code != http.StatusOK {
+		t.Fatalf("GET Location = %d: %s", got.Code, got.Body.String())
+	}
+	var item map[string]any
+	if err := json.Unmarshal(got.Body.Bytes(), &item); err != nil {
+		t.Fatalf("decode item: %v", err)
+	}
+	if item["title"] != "Test Document" {
+		t.Errorf("GET title = %v; want Test Document", item["title"])
+	}
+
+	other := httptest.NewRequest(http.MethodGet, location, nil)
+	other = other.WithContext(contextWithWorkspaceID(other.Context(), "ws-other"))
+	got = httptest.NewRecorder()
+	r.ServeHTTP(got, other)
+	if got.Code != http.StatusNotFound {
+		t.Fatalf("GET from another workspace = %d; want 404", got.Code)
+	}
 }
 
 func TestKnowledgeIngestHandler_MissingTitle_Returns400(t *testing.T) {
@@ -201,3 +232,68 @@ func TestKnowledgeIngestHandler_SourceObjectRequiresSourceSystem_Returns400(t *t
 		t.Fatalf("expected 400 when sourceObjectId has no sourceSystem, got %d", rr.Code)
 	}
 }
+
+func TestKnowledgeIngestHandler_QuotaExceeded_Returns429(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	if _, err := db.Exec(`UPDATE workspace SET settings = '{"knowledge_quota":{"max_bytes":10}}' WHERE id = ?`, wsID); err != nil {
+		t.Fatalf("set quota: %v", err)
+	}
+	handler := NewKnowledgeIngestHandler(knowledge.NewIngestService(db, eventbus.New()))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"sourceType": "document",
+		"title":      "Too Big",
+		"rawContent": "This content is longer than ten bytes.",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/knowledge/ingest", bytes.NewReader(body))
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	handler.Ingest(rr, req)
+
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d — body: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestKnowledgeIngestHandler_Reingest_ReportsOutcome(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	handler := NewKnowledgeIngestHandler(knowledge.NewIngestService(db, eventbus.New()))
+
+	ingest := func(content string) (int, string) {
+		body, _ := json.Marshal(map[string]interface{}{
+			"sourceType": "case",
+			"title":      "Case 7",
+			"rawContent": content,
+			"entityType": "case",
+			"entityId":   "case-7",
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/knowledge/ingest", bytes.NewReader(body))
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+		handler.Ingest(rr, req)
+		var resp map[string]interface{}
+		_ = json.NewDecoder(rr.Body).Decode(&resp)
+		outcome, _ := resp["outcome"].(string)
+		return rr.Code, outcome
+	}
+
+	for _, tc := range []struct {
+		content     string
+		wantStatus  int
+		wantOutcome string
+	}{
+		{"printer offline", http.StatusCreated, "created"},
+		{"printer offline", http.StatusOK, "unchanged"},
+		{"printer back online", http.StatusOK, "updated"},
+	} {
+		if status, outcome := ingest(tc.content); status != tc.wantStatus || outcome != tc.wantOutcome {
+			t.Fatalf("ingest %q: status=%d outcome=%q; want %d %q", tc.content, status, outcome, tc.wantStatus, tc.wantOutcome)
+		}
+	}
+}
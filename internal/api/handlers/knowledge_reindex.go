@@ -31,21 +31,20 @@ type reindexResponse struct {
 func (h *KnowledgeReindexHandler) Reindex(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	wsID, wsErr := getWorkspaceID(ctx)
-	if wsErr != nil {
-		writeError(w, http.StatusUnauthorized, "missing workspace context")
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
 	var req reindexRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 
 	queued, queueErr := h.reindexService.QueueWorkspaceReindex(ctx, wsID, req.EntityType)
 	if queueErr != nil {
-		writeError(w, http.StatusInternalServerError, "failed to queue reindex")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to queue reindex")
 		return
 	}
 
@@ -57,6 +56,6 @@ func (h *KnowledgeReindexHandler) Reindex(w http.ResponseWriter, r *http.Request
 		ItemsQueued:   queued,
 		EstimatedTime: fmt.Sprintf("%ds", int(estimated.Seconds()+0.5)),
 	}); encodeErr != nil {
-		http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
+		http.Error(w, errFailedToEncodeJSON, http.StatusInternalServerError)
 	}
 }
@@ -1,9 +1,12 @@
 // Task 2.5: HTTP handler for hybrid knowledge search.
 // POST /api/v1/knowledge/search — runs BM25 + vector search and returns ranked results.
+// POST /api/v1/search/feedback — records a relevance signal on a search result.
+// GET /api/v1/knowledge/index-stats — reports the embedding backlog of the index.
 package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
@@ -21,8 +24,14 @@ func NewKnowledgeSearchHandler(svc *knowledge.SearchService) *KnowledgeSearchHan
 
 // searchRequest is the JSON request body for POST /api/v1/knowledge/search.
 type searchRequest struct {
-	Query string `json:"query"`
-	Limit int    `json:"limit,omitempty"`
+	Query    string `json:"query"`
+	Language string `json:"language,omitempty"`
+	Limit    int    `json:"limit,omitempty"`
+	Offset   int    `json:"offset,omitempty"`
+	// MetadataFilters keeps only chunks tagged with these values at ingest.
+	MetadataFilters map[string]string `json:"metadata_filters,omitempty"`
+	// Fuzzy retries keyword matching with corrected terms on no match.
+	Fuzzy bool `json:"fuzzy,omitempty"`
 }
 
 // searchResultItem is a single item in the search response.
@@ -36,38 +45,47 @@ type searchResultItem struct {
 
 // searchResponse is the JSON response body for POST /api/v1/knowledge/search.
 type searchResponse struct {
-	Results []searchResultItem `json:"results"`
-	Query   string             `json:"query"`
+	Results         []searchResultItem `json:"results"`
+	Query           string             `json:"query"`
+	TotalCandidates int                `json:"total_candidates"`
+	HasMore         bool               `json:"has_more"`
 }
 
 // Search handles POST /api/v1/knowledge/search.
 func (h *KnowledgeSearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	wsID, wsErr := getWorkspaceID(ctx)
-	if wsErr != nil {
-		writeError(w, http.StatusUnauthorized, "missing workspace context")
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
 	var req searchRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 
 	if req.Query == "" {
-		writeError(w, http.StatusBadRequest, "query is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "query is required")
 		return
 	}
 
 	results, searchErr := h.searchService.HybridSearch(ctx, knowledge.SearchInput{
-		Query:       req.Query,
-		WorkspaceID: wsID,
-		Limit:       req.Limit,
+		Query:           req.Query,
+		WorkspaceID:     wsID,
+		Language:        req.Language,
+		Limit:           req.Limit,
+		Offset:          req.Offset,
+		MetadataFilters: req.MetadataFilters,
+		Fuzzy:           req.Fuzzy,
 	})
+	if errors.Is(searchErr, knowledge.ErrOffsetOutOfRange) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, searchErr.Error())
+		return
+	}
 	if searchErr != nil {
-		writeError(w, http.StatusInternalServerError, "search failed")
+		writeError(w, http.StatusInternalServerError, codeInternal, "search failed")
 		return
 	}
 
@@ -85,9 +103,65 @@ func (h *KnowledgeSearchHandler) Search(w http.ResponseWriter, r *http.Request)
 	w.Header().Set(headerContentType, mimeJSON)
 	w.WriteHeader(http.StatusOK)
 	if encodeErr := json.NewEncoder(w).Encode(searchResponse{
-		Results: items,
-		Query:   results.Query,
+		Results:         items,
+		Query:           results.Query,
+		TotalCandidates: results.TotalCandidates,
+		HasMore:         results.HasMore,
 	}); encodeErr != nil {
-		http.Error(w, `{"error":"failed to encode response"}`, http.StatusInternalServerError)
+		http.Error(w, errFailedToEncodeJSON, http.StatusInternalServerError)
+	}
+}
+
+// searchFeedbackRequest is the JSON request body for POST /api/v1/search/feedback.
+type searchFeedbackRequest struct {
+	Query           string `json:"query"`
+	KnowledgeItemID string `json:"knowledge_item_id"`
+	Signal          string `json:"signal"`
+}
+
+// RecordFeedback handles POST /api/v1/search/feedback.
+func (h *KnowledgeSearchHandler) RecordFeedback(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	var req searchFeedbackRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
+		return
+	}
+	if req.KnowledgeItemID == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "knowledge_item_id is required")
+		return
+	}
+
+	err := h.searchService.RecordFeedback(r.Context(), wsID, req.Query, req.KnowledgeItemID, knowledge.FeedbackSignal(req.Signal))
+	switch {
+	case errors.Is(err, knowledge.ErrInvalidFeedbackSignal):
+		writeError(w, http.StatusBadRequest, codeBadRequest, "signal must be one of click, helpful, not_helpful")
+	case errors.Is(err, knowledge.ErrFeedbackQueryRequired):
+		writeError(w, http.StatusBadRequest, codeBadRequest, "query is required")
+	case errors.Is(err, knowledge.ErrFeedbackItemNotFound):
+		writeError(w, http.StatusNotFound, codeKnowledgeItemNotFound, "knowledge item not found")
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to record feedback")
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// IndexStats handles GET /api/v1/knowledge/index-stats.
+func (h *KnowledgeSearchHandler) IndexStats(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.searchService.IndexStats(r.Context(), wsID)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to get index stats")
+		return
 	}
+	_ = writeJSONOr500(w, map[string]any{"data": stats})
 }
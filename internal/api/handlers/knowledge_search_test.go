@@ -142,6 +142,31 @@ func TestKnowledgeSearchHandler_MissingQuery_Returns400(t *testing.T) {
 	}
 }
 
+func TestKnowledgeSearchHandler_OffsetOutOfRange_Returns400(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+
+	handler := NewKnowledgeSearchHandler(knowledge.NewSearchService(db, &searchStubLLM{}))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"query":  "test",
+		"offset": knowledge.MaxSearchOffset + 1,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/knowledge/search", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+
+	rr := httptest.NewRecorder()
+	handler.Search(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for offset past the maximum, got %d", rr.Code)
+	}
+}
+
 func TestKnowledgeSearchHandler_MissingWorkspace_Returns401(t *testing.T) {
 	t.Parallel()
 
@@ -198,3 +223,80 @@ func TestKnowledgeSearchHandler_EmptyIndex_Returns200WithNoResults(t *testing.T)
 		t.Errorf("expected 0 results on empty index, got %d", len(results))
 	}
 }
+
+func TestKnowledgeSearchHandler_RecordFeedback(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	item, err := knowledge.NewIngestService(db, eventbus.New()).Ingest(contextWithWorkspaceID(t.Context(), wsID), knowledge.CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  knowledge.SourceTypeDocument,
+		Title:       "Pricing Strategy",
+		RawContent:  "our pricing discount policy for enterprise customers",
+	})
+	if err != nil {
+		t.Fatalf("ingest failed: %v", err)
+	}
+	handler := NewKnowledgeSearchHandler(knowledge.NewSearchService(db, &searchStubLLM{}))
+
+	tests := []struct {
+		name string
+		body map[string]string
+		want int
+	}{
+		{"helpful", map[string]string{"query": "pricing", "knowledge_item_id": item.ID, "signal": "helpful"}, http.StatusNoContent},
+		{"unknown signal", map[string]string{"query": "pricing", "knowledge_item_id": item.ID, "signal": "love"}, http.StatusBadRequest},
+		{"missing query", map[string]string{"knowledge_item_id": item.ID, "signal": "click"}, http.StatusBadRequest},
+		{"unknown item", map[string]string{"query": "pricing", "knowledge_item_id": "missing", "signal": "click"}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		body, _ := json.Marshal(tt.body)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/search/feedback", bytes.NewReader(body))
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+		handler.RecordFeedback(rr, req)
+		if rr.Code != tt.want {
+			t.Fatalf("%s: status = %d; want %d — body: %s", tt.name, rr.Code, tt.want, rr.Body.String())
+		}
+	}
+
+	var n int
+	if err = db.QueryRow(`SELECT COUNT(*) FROM search_feedback WHERE workspace_id = ?`, wsID).Scan(&n); err != nil || n != 1 {
+		t.Fatalf("search_feedback rows = %d, %v; want 1", n, err)
+	}
+}
+
+func TestKnowledgeSearchHandler_IndexStats_Returns200(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	if _, err := knowledge.NewIngestService(db, eventbus.New()).Ingest(t.Context(), knowledge.CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  knowledge.SourceTypeDocument,
+		Title:       "Pricing Strategy",
+		RawContent:  "our pricing discount policy for enterprise customers",
+	}); err != nil {
+		t.Fatalf("ingest failed: %v", err)
+	}
+	handler := NewKnowledgeSearchHandler(knowledge.NewSearchService(db, &searchStubLLM{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/knowledge/index-stats", nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	handler.IndexStats(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data knowledge.IndexStats `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Data.Chunks != 1 || resp.Data.Pending != 1 || resp.Data.Embedded != 0 {
+		t.Fatalf("stats = %+v; want one pending chunk", resp.Data)
+	}
+}
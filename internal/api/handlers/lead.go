@@ -10,6 +10,9 @@ import (
 	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
 )
 
+// leadSortScore is the ?sort= value that lists leads as a prioritized queue.
+const leadSortScore = "score"
+
 // LeadHandler handles HTTP requests for lead CRUD operations.
 type LeadHandler struct {
 	leadService *crm.LeadService
@@ -82,7 +85,7 @@ func (h *LeadHandler) CreateLead(w http.ResponseWriter, r *http.Request) {
 
 	// Validate required fields
 	if req.OwnerID == "" {
-		writeError(w, http.StatusBadRequest, "ownerId is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "ownerId is required")
 		return
 	}
 
@@ -98,11 +101,12 @@ func (h *LeadHandler) CreateLead(w http.ResponseWriter, r *http.Request) {
 		Metadata:    req.Metadata,
 	})
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create lead: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create lead: %v", svcErr))
 		return
 	}
 
 	// Write response
+	setLocation(w, "leads", lead.ID)
 	w.WriteHeader(http.StatusCreated)
 	if !writeJSONOr500(w, leadToResponse(lead)) {
 		return
@@ -120,13 +124,13 @@ func (h *LeadHandler) GetLead(w http.ResponseWriter, r *http.Request) {
 
 	leadID := chi.URLParam(r, paramID)
 	if leadID == "" {
-		writeError(w, http.StatusBadRequest, errLeadIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errLeadIDRequired)
 		return
 	}
 
 	// Get lead via service
 	lead, svcErr := h.leadService.Get(ctx, wsID, leadID)
-	if handleGetError(w, svcErr, errLeadNotFound, errFailedToGetLead) {
+	if handleGetError(w, svcErr, codeLeadNotFound, errLeadNotFound, errFailedToGetLead) {
 		return
 	}
 
@@ -136,7 +140,8 @@ func (h *LeadHandler) GetLead(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ListLeads handles GET /api/v1/leads with pagination and owner filter
+// ListLeads handles GET /api/v1/leads and GET /api/v2/leads with pagination
+// and owner filter; each version gets its own list envelope.
 // Task 1.5: List leads with pagination filters
 func (h *LeadHandler) ListLeads(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -148,22 +153,30 @@ func (h *LeadHandler) ListLeads(w http.ResponseWriter, r *http.Request) {
 	// Parse pagination params
 	page := parsePaginationParams(r)
 
-	// Check for owner_id filter
+	// owner_id filters by owner; sort=score returns the prioritized queue,
+	// optionally restricted to one status.
 	ownerID := r.URL.Query().Get("owner_id")
 
 	var leads []*crm.Lead
 	var total int
 
 	var listErr error
-	if ownerID != "" {
+	switch {
+	case ownerID != "":
 		leads, listErr = h.leadService.ListByOwner(ctx, wsID, ownerID)
 		total = len(leads)
 		leads = applyPagination(leads, page.Limit, page.Offset)
-	} else {
+	case r.URL.Query().Get("sort") == leadSortScore:
+		leads, total, listErr = h.leadService.ListByScore(ctx, wsID, crm.ListLeadsByScoreInput{
+			Limit:  page.Limit,
+			Offset: page.Offset,
+			Status: r.URL.Query().Get(queryStatus),
+		})
+	default:
 		leads, total, listErr = h.leadService.List(ctx, wsID, crm.ListLeadsInput{Limit: page.Limit, Offset: page.Offset})
 	}
 	if listErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list leads: %v", listErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list leads: %v", listErr))
 		return
 	}
 
@@ -173,7 +186,7 @@ func (h *LeadHandler) ListLeads(w http.ResponseWriter, r *http.Request) {
 		responses[i] = leadToResponse(lead)
 	}
 
-	if !writePaginatedOr500(w, responses, total, page) {
+	if !writeVersionedPage(w, r, responses, total, page.Limit, page.Offset) {
 		return
 	}
 }
@@ -190,6 +203,7 @@ func (h *LeadHandler) UpdateLead(w http.ResponseWriter, r *http.Request) {
 		w,
 		r,
 		errLeadIDRequired,
+		codeLeadNotFound,
 		errLeadNotFound,
 		errFailedToGetLead,
 		"failed to update lead: %v",
@@ -209,7 +223,7 @@ func (h *LeadHandler) UpdateLead(w http.ResponseWriter, r *http.Request) {
 // DeleteLead handles DELETE /api/v1/leads/{id}
 // Task 1.5: Soft delete a lead (sets deleted_at timestamp)
 func (h *LeadHandler) DeleteLead(w http.ResponseWriter, r *http.Request) {
-	handleVerifiedDelete(w, r, errLeadIDRequired, errLeadNotFound, errFailedToGetLead, "failed to delete lead: %v", h.leadService.Get, h.leadService.Delete)
+	handleVerifiedDelete(w, r, errLeadIDRequired, codeLeadNotFound, errLeadNotFound, errFailedToGetLead, "failed to delete lead: %v", h.leadService.Get, h.leadService.Delete)
 }
 
 // --- helpers ---
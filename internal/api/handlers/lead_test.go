@@ -51,6 +51,9 @@ func TestLeadHandler_CreateLead(t *testing.T) {
 	if _, ok := resp["id"]; !ok {
 		t.Error("response missing 'id' field")
 	}
+	if got, want := w.Header().Get("Location"), fmt.Sprintf("/api/v1/leads/%v", resp["id"]); got != want {
+		t.Errorf("Location = %q; want %q", got, want)
+	}
 	if resp["status"] != "new" {
 		t.Errorf("response status = %v; want 'new'", resp["status"])
 	}
@@ -65,6 +68,7 @@ func TestLeadHandler_CreateLead_MissingWorkspace_Returns400(t *testing.T) {
 	body, _ := json.Marshal(map[string]any{"ownerId": ownerID})
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/leads", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	w := httptest.NewRecorder()
 	handler.CreateLead(w, req)
 
@@ -293,7 +297,7 @@ func TestLeadHandler_UpdateLead(t *testing.T) {
 	created, _ := svc.Create(context.Background(), crm.CreateLeadInput{
 		WorkspaceID: wsID,
 		Source:      "website",
-		Status:      "new",
+		Status:      "contacted",
 		OwnerID:     ownerID,
 	})
 
@@ -329,6 +333,34 @@ func TestLeadHandler_UpdateLead(t *testing.T) {
 	}
 }
 
+func TestLeadHandler_UpdateLead_IllegalTransition_Returns409(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewLeadService(db)
+	handler := NewLeadHandler(svc)
+
+	created, err := svc.Create(context.Background(), crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID})
+	if err != nil {
+		t.Fatalf("create lead: %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]any{"status": "converted"})
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/leads/"+created.ID, bytes.NewReader(body))
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", created.ID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	w := httptest.NewRecorder()
+	handler.UpdateLead(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status=%d want=%d body=%s", w.Code, http.StatusConflict, w.Body.String())
+	}
+}
+
 func TestLeadHandler_DeleteLead(t *testing.T) {
 	t.Parallel()
 
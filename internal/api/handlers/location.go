@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+)
+
+const (
+	headerLocation = "Location"
+	apiV1BasePath  = "/api/v1"
+
+	agentRunsCollection         = "agents/runs"
+	agentDefinitionsCollection  = "agents/definitions"
+	pipelineStagesCollection    = "pipelines/stages"
+	knowledgeItemsCollection    = "knowledge/items"
+	toolsCollection             = "admin/tools"
+	promptsCollection           = "admin/prompts"
+	promptExperimentsCollection = "admin/prompts/experiments"
+	evalSuitesCollection        = "admin/eval/suites"
+	evalBenchmarksCollection    = "admin/eval/benchmarks"
+)
+
+// setLocation points the Location header of a 201 response at the canonical
+// URL of the created resource, /api/v1/<collection>/<id>.
+func setLocation(w http.ResponseWriter, collection, id string) {
+	w.Header().Set(headerLocation, apiV1BasePath+"/"+collection+"/"+url.PathEscape(id))
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -25,6 +26,8 @@ type CreateNoteRequest struct {
 	Content    string `json:"content"`
 	IsInternal bool   `json:"isInternal"`
 	Metadata   string `json:"metadata,omitempty"`
+	// ParentNoteID makes the note a reply to another note on the same entity.
+	ParentNoteID string `json:"parentNoteId,omitempty"`
 }
 
 type UpdateNoteRequest struct {
@@ -43,22 +46,28 @@ func (h *NoteHandler) CreateNote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !isNoteRequestValid(req) {
-		writeError(w, http.StatusBadRequest, "entityType, entityId, authorId and content are required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "entityType, entityId, authorId and content are required")
 		return
 	}
 	out, svcErr := h.service.Create(r.Context(), crm.CreateNoteInput{
-		WorkspaceID: wsID,
-		EntityType:  req.EntityType,
-		EntityID:    req.EntityID,
-		AuthorID:    req.AuthorID,
-		Content:     req.Content,
-		IsInternal:  req.IsInternal,
-		Metadata:    req.Metadata,
+		WorkspaceID:  wsID,
+		EntityType:   req.EntityType,
+		EntityID:     req.EntityID,
+		AuthorID:     req.AuthorID,
+		Content:      req.Content,
+		IsInternal:   req.IsInternal,
+		Metadata:     req.Metadata,
+		ParentNoteID: req.ParentNoteID,
 	})
+	if errors.Is(svcErr, crm.ErrInvalidNoteParent) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, svcErr.Error())
+		return
+	}
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create note: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create note: %v", svcErr))
 		return
 	}
+	setLocation(w, "notes", out.ID)
 	w.WriteHeader(http.StatusCreated)
 	if !writeJSONOr500(w, out) {
 		return
@@ -72,7 +81,7 @@ func (h *NoteHandler) GetNote(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chi.URLParam(r, paramID)
 	out, svcErr := h.service.Get(r.Context(), wsID, id)
-	if handleGetError(w, svcErr, errNoteNotFound, "failed to get note: %v") {
+	if handleGetError(w, svcErr, codeNoteNotFound, errNoteNotFound, "failed to get note: %v") {
 		return
 	}
 	if !writeJSONOr500(w, out) {
@@ -88,12 +97,33 @@ func (h *NoteHandler) ListNotes(w http.ResponseWriter, r *http.Request) {
 	page := parsePaginationParams(r)
 	items, total, svcErr := h.service.List(r.Context(), wsID, crm.ListNotesInput{Limit: page.Limit, Offset: page.Offset})
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list notes: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list notes: %v", svcErr))
+		return
+	}
+	if !writePaginated(w, items, total, page.Limit, page.Offset) {
 		return
 	}
-	if !writePaginatedOr500(w, items, total, page) {
+}
+
+// ListNoteThread handles GET /api/v1/notes/thread?entityType=&entityId= and
+// returns the entity's notes as reply trees, oldest first.
+func (h *NoteHandler) ListNoteThread(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	entityType := r.URL.Query().Get("entityType")
+	entityID := r.URL.Query().Get("entityId")
+	if fields := requireFields(map[string]string{"entityType": entityType, "entityId": entityID}); len(fields) > 0 {
+		writeValidationError(w, fields)
+		return
+	}
+	threads, svcErr := h.service.ListThread(r.Context(), wsID, entityType, entityID)
+	if svcErr != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list note thread: %v", svcErr))
 		return
 	}
+	_ = writeJSONOr500(w, map[string]any{"data": threads})
 }
 
 func (h *NoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
@@ -106,6 +136,7 @@ func (h *NoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
 		w,
 		r,
 		errNoteIDRequired,
+		codeNoteNotFound,
 		errNoteNotFound,
 		"failed to get note: %v",
 		"failed to update note: %v",
@@ -118,7 +149,7 @@ func (h *NoteHandler) UpdateNote(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *NoteHandler) DeleteNote(w http.ResponseWriter, r *http.Request) {
-	handleDeleteWithNotFound(w, r, errNoteIDRequired, sql.ErrNoRows, errNoteNotFound, "failed to delete note: %v", h.service.Delete)
+	handleDeleteWithNotFound(w, r, errNoteIDRequired, sql.ErrNoRows, codeNoteNotFound, errNoteNotFound, "failed to delete note: %v", h.service.Delete)
 }
 
 // isNoteRequestValid checks required fields for CreateNote.
@@ -21,6 +21,7 @@ func TestNoteHandler_GetNote_MissingWorkspace_Returns400(t *testing.T) {
 	handler := NewNoteHandler(crm.NewNoteService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/notes/n1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "n1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -40,6 +41,7 @@ func TestNoteHandler_ListNotes_MissingWorkspace_Returns400(t *testing.T) {
 	handler := NewNoteHandler(crm.NewNoteService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/notes", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	handler.ListNotes(rr, req)
 
@@ -56,6 +58,7 @@ func TestNoteHandler_UpdateNote_MissingWorkspace_Returns400(t *testing.T) {
 
 	body := bytes.NewBufferString(`{"content":"updated"}`)
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/notes/n1", body)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "n1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -75,6 +78,7 @@ func TestNoteHandler_DeleteNote_MissingWorkspace_Returns400(t *testing.T) {
 	handler := NewNoteHandler(crm.NewNoteService(db))
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/notes/n1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "n1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -155,6 +159,7 @@ func TestNoteHandler_CreateNote_MissingWorkspace_Returns400(t *testing.T) {
 
 	body, _ := json.Marshal(map[string]any{"content": "test"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/notes", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 
 	rr := httptest.NewRecorder()
 	handler.CreateNote(rr, req)
@@ -297,6 +302,65 @@ func TestNoteHandler_CreateNote_Success(t *testing.T) {
 	}
 }
 
+func TestNoteHandler_ListNoteThread_ReturnsNestedReplies(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	handler := NewNoteHandler(crm.NewNoteService(db))
+	ctx := contextWithWorkspaceID(context.Background(), wsID)
+
+	createNote := func(parentID string) (string, int) {
+		body, _ := json.Marshal(map[string]any{
+			"entityType":   "case",
+			"entityId":     "case-thread-1",
+			"authorId":     ownerID,
+			"content":      "message",
+			"parentNoteId": parentID,
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/notes", bytes.NewReader(body)).WithContext(ctx)
+		rr := httptest.NewRecorder()
+		handler.CreateNote(rr, req)
+		var note crm.Note
+		_ = json.NewDecoder(rr.Body).Decode(&note)
+		return note.ID, rr.Code
+	}
+	rootID, code := createNote("")
+	if code != http.StatusCreated {
+		t.Fatalf("create root status = %d", code)
+	}
+	replyID, _ := createNote(rootID)
+	nestedID, _ := createNote(replyID)
+	if _, code = createNote("missing"); code != http.StatusBadRequest {
+		t.Fatalf("create with unknown parent status = %d; want 400", code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/notes/thread?entityType=case&entityId=case-thread-1", nil).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	handler.ListNoteThread(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data []crm.NoteThread `json:"data"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(resp.Data) != 1 || resp.Data[0].ID != rootID ||
+		len(resp.Data[0].Replies) != 1 || resp.Data[0].Replies[0].ID != replyID ||
+		len(resp.Data[0].Replies[0].Replies) != 1 || resp.Data[0].Replies[0].Replies[0].ID != nestedID {
+		t.Fatalf("thread = %s; want %s > %s > %s", rr.Body.String(), rootID, replyID, nestedID)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/notes/thread?entityType=case", nil).WithContext(ctx)
+	rr = httptest.NewRecorder()
+	handler.ListNoteThread(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("missing entityId status = %d; want 400", rr.Code)
+	}
+}
+
 func TestNoteHandler_DeleteNote_Success(t *testing.T) {
 	t.Parallel()
 
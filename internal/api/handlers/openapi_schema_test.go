@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/openapi"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+// TestOpenAPISchemas_MatchResponseStructs fails when a response struct gains
+// or loses a JSON field without docs/openapi.yaml following.
+func TestOpenAPISchemas_MatchResponseStructs(t *testing.T) {
+	t.Parallel()
+
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("openapi.Load: %v", err)
+	}
+	tests := []struct {
+		schema string
+		value  any
+	}{
+		{"Account", AccountResponse{}},
+		{"Pipeline", crm.Pipeline{}},
+		{"PipelineStage", crm.PipelineStage{}},
+		{"AgentRun", agentRunResponse{}},
+		{"AgentDefinition", agentDefinitionResponse{}},
+	}
+	for _, tt := range tests {
+		want := jsonFieldNames(reflect.TypeOf(tt.value))
+		if got := spec.SchemaProperties(tt.schema); !slices.Equal(got, want) {
+			t.Errorf("schema %s properties = %v; want %v from %T", tt.schema, got, want, tt.value)
+		}
+	}
+}
+
+// jsonFieldNames returns the sorted JSON names of typ's encoded fields.
+func jsonFieldNames(typ reflect.Type) []string {
+	var names []string
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+)
+
+// paginationParams holds parsed limit and offset values.
+type paginationParams struct {
+	Limit  int
+	Offset int
+}
+
+const (
+	defaultPaginationLimit = 25
+	maxPaginationLimit     = 100
+
+	cursorQueryParam = "cursor"
+	errInvalidCursor = "invalid cursor"
+)
+
+var errMalformedCursor = errors.New("malformed cursor")
+
+// pageCursor is the decoded form of the opaque cursor query param: the
+// created_at and id of the last item of the previous page.
+type pageCursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// PaginationFromRequest reads limit and offset from the query string.
+// A missing, non-numeric or non-positive limit falls back to the default (25)
+// and larger values are capped at 100 (TD-2). A missing, non-numeric or
+// negative offset becomes 0.
+func PaginationFromRequest(r *http.Request) (limit, offset int) {
+	limit = defaultPaginationLimit
+	if lim, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && lim > 0 {
+		limit = min(lim, maxPaginationLimit)
+	}
+	if off, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && off >= 0 {
+		offset = off
+	}
+	return limit, offset
+}
+
+// parsePaginationParams is PaginationFromRequest for handlers that pass the
+// page around as one value.
+func parsePaginationParams(r *http.Request) paginationParams {
+	limit, offset := PaginationFromRequest(r)
+	return paginationParams{Limit: limit, Offset: offset}
+}
+
+// writePaginated writes the standard list envelope {data, meta} and responds
+// 500 if encoding fails.
+func writePaginated(w http.ResponseWriter, data any, total, limit, offset int) bool {
+	return writePaginatedMeta(w, data, Meta{Total: total, Limit: limit, Offset: offset})
+}
+
+func writePaginatedMeta(w http.ResponseWriter, data any, meta Meta) bool {
+	return writeJSONOr500(w, map[string]any{"data": data, "meta": meta})
+}
+
+// PageV2 is the page metadata of the /api/v2 list envelope {data, page}.
+// NextOffset is set when another page follows.
+type PageV2 struct {
+	Total      int  `json:"total"`
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+	HasMore    bool `json:"has_more"`
+	NextOffset *int `json:"next_offset,omitempty"`
+}
+
+// writeVersionedPage writes the list envelope of the API version serving r:
+// {data, meta} for v1 and {data, page} from v2 on.
+func writeVersionedPage(w http.ResponseWriter, r *http.Request, data any, total, limit, offset int) bool {
+	if ctxkeys.APIVersionFrom(r.Context()) < 2 {
+		return writePaginated(w, data, total, limit, offset)
+	}
+	page := PageV2{Total: total, Limit: limit, Offset: offset}
+	if next := offset + limit; next < total {
+		page.HasMore = true
+		page.NextOffset = &next
+	}
+	return writeJSONOr500(w, map[string]any{"data": data, "page": page})
+}
+
+// cursorFromRequest reports whether the request uses cursor pagination. The
+// presence of the cursor param selects it; an empty value asks for the first
+// page, so clients can switch modes without knowing any item.
+func cursorFromRequest(r *http.Request) (pageCursor, bool, error) {
+	q := r.URL.Query()
+	if !q.Has(cursorQueryParam) {
+		return pageCursor{}, false, nil
+	}
+	raw := q.Get(cursorQueryParam)
+	if raw == "" {
+		return pageCursor{}, true, nil
+	}
+	c, err := decodePageCursor(raw)
+	return c, true, err
+}
+
+func encodePageCursor(c pageCursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageCursor(raw string) (pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return pageCursor{}, errMalformedCursor
+	}
+	var c pageCursor
+	if json.Unmarshal(data, &c) != nil || c.ID == "" || c.CreatedAt.IsZero() {
+		return pageCursor{}, errMalformedCursor
+	}
+	return c, nil
+}
+
+// cursorPage trims a page fetched with limit+1 items to limit and returns
+// the cursor of its last item, or "" when there is no further page.
+func cursorPage[T any](items []*T, limit int, key func(*T) pageCursor) ([]*T, string) {
+	if len(items) <= limit {
+		return items, ""
+	}
+	items = items[:limit]
+	return items, encodePageCursor(key(items[limit-1]))
+}
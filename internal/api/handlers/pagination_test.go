@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+)
+
+func TestPaginationFromRequest(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantLimit  int
+		wantOffset int
+	}{
+		{"", 25, 0},
+		{"?limit=10&offset=5", 10, 5},
+		{"?limit=100", 100, 0},
+		{"?limit=101", 100, 0},
+		{"?limit=100000&offset=20", 100, 20},
+		{"?limit=0", 25, 0},
+		{"?limit=-5&offset=-3", 25, 0},
+		{"?limit=abc&offset=xyz", 25, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts"+tt.query, nil)
+			limit, offset := PaginationFromRequest(req)
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Fatalf("PaginationFromRequest(%q) = (%d, %d); want (%d, %d)", tt.query, limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestWritePaginated_Envelope(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if !writePaginated(rec, []string{"a", "b"}, 7, 2, 4) {
+		t.Fatal("writePaginated() = false")
+	}
+	if ct := rec.Header().Get(headerContentType); ct != mimeJSON {
+		t.Fatalf("content type = %q", ct)
+	}
+
+	var body struct {
+		Data []string `json:"data"`
+		Meta Meta     `json:"meta"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Data) != 2 || body.Meta != (Meta{Total: 7, Limit: 2, Offset: 4}) {
+		t.Fatalf("body = %+v", body)
+	}
+}
+
+func TestWriteVersionedPage_V2Envelope(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/leads", nil)
+	req = req.WithContext(ctxkeys.WithAPIVersion(req.Context(), 2))
+
+	for _, tc := range []struct {
+		total, offset int
+		next          *int
+	}{
+		{total: 7, offset: 2, next: intPtr(4)},
+		{total: 7, offset: 6},
+	} {
+		rec := httptest.NewRecorder()
+		if !writeVersionedPage(rec, req, []string{"a", "b"}, tc.total, 2, tc.offset) {
+			t.Fatal("writeVersionedPage() = false")
+		}
+		var body struct {
+			Data []string        `json:"data"`
+			Page PageV2          `json:"page"`
+			Meta json.RawMessage `json:"meta"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if body.Meta != nil || body.Page.Total != tc.total || body.Page.Offset != tc.offset {
+			t.Fatalf("body = %+v", body)
+		}
+		if body.Page.HasMore != (tc.next != nil) {
+			t.Fatalf("offset %d: has_more = %v", tc.offset, body.Page.HasMore)
+		}
+		if tc.next != nil && (body.Page.NextOffset == nil || *body.Page.NextOffset != *tc.next) {
+			t.Fatalf("offset %d: next_offset = %v; want %d", tc.offset, body.Page.NextOffset, *tc.next)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -24,6 +25,25 @@ type CreatePipelineRequest struct {
 
 type UpdatePipelineRequest = CreatePipelineRequest
 
+// PatchPipelineRequest is the body of a partial pipeline update: absent
+// fields keep their stored value, a null or empty settings clears it, and
+// clearing name or entityType is rejected.
+type PatchPipelineRequest struct {
+	Name       optionalString `json:"name"`
+	EntityType optionalString `json:"entityType"`
+	Settings   optionalString `json:"settings"`
+}
+
+func (req PatchPipelineRequest) clearedRequiredField() string {
+	switch {
+	case req.Name.cleared():
+		return "name"
+	case req.EntityType.cleared():
+		return "entityType"
+	}
+	return ""
+}
+
 type CreatePipelineStageRequest struct {
 	Name           string   `json:"name"`
 	Position       int64    `json:"position"`
@@ -40,6 +60,7 @@ const (
 	errStageNotFound       = "stage not found"
 	errStageIDRequired     = "stage id is required"
 	errFailedToGetPipeline = "failed to get pipeline: %v"
+	queryReassignTo        = "reassign_to"
 )
 
 func (h *PipelineHandler) CreatePipeline(w http.ResponseWriter, r *http.Request) {
@@ -51,15 +72,16 @@ func (h *PipelineHandler) CreatePipeline(w http.ResponseWriter, r *http.Request)
 	if !decodeBodyJSON(w, r, &req) {
 		return
 	}
-	if req.Name == "" || req.EntityType == "" {
-		writeError(w, http.StatusBadRequest, "name and entityType are required")
+	if fields := requireFields(map[string]string{"name": req.Name, "entityType": req.EntityType}); len(fields) > 0 {
+		writeValidationError(w, fields)
 		return
 	}
 	out, svcErr := h.service.Create(r.Context(), crm.CreatePipelineInput{WorkspaceID: wsID, Name: req.Name, EntityType: req.EntityType, Settings: req.Settings})
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create pipeline: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create pipeline: %v", svcErr))
 		return
 	}
+	setLocation(w, "pipelines", out.ID)
 	w.WriteHeader(http.StatusCreated)
 	if !writeJSONOr500(w, out) {
 		return
@@ -79,7 +101,7 @@ func (h *PipelineHandler) GetPipeline(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chi.URLParam(r, paramID)
 	out, svcErr := h.service.Get(r.Context(), wsID, id)
-	if handleGetError(w, svcErr, errPipelineNotFound, errFailedToGetPipeline) {
+	if handleGetError(w, svcErr, codePipelineNotFound, errPipelineNotFound, errFailedToGetPipeline) {
 		return
 	}
 	if !writeJSONOr500(w, out) {
@@ -97,6 +119,7 @@ func (h *PipelineHandler) UpdatePipeline(w http.ResponseWriter, r *http.Request)
 		w,
 		r,
 		errPipelineIDRequired,
+		codePipelineNotFound,
 		errPipelineNotFound,
 		errFailedToGetPipeline,
 		"failed to update pipeline: %v",
@@ -112,19 +135,45 @@ func (h *PipelineHandler) UpdatePipeline(w http.ResponseWriter, r *http.Request)
 	)
 }
 
+func (h *PipelineHandler) PatchPipeline(w http.ResponseWriter, r *http.Request) {
+	handleEntityUpdate[
+		crm.Pipeline,
+		PatchPipelineRequest,
+		crm.UpdatePipelineInput,
+		crm.Pipeline,
+	](
+		w,
+		r,
+		errPipelineIDRequired,
+		codePipelineNotFound,
+		errPipelineNotFound,
+		errFailedToGetPipeline,
+		"failed to update pipeline: %v",
+		h.service.Get,
+		func(req PatchPipelineRequest, existing *crm.Pipeline) crm.UpdatePipelineInput {
+			return crm.UpdatePipelineInput{
+				Name:       req.Name.apply(&existing.Name),
+				EntityType: req.EntityType.apply(&existing.EntityType),
+				Settings:   req.Settings.apply(existing.Settings),
+			}
+		},
+		h.service.Update,
+	)
+}
+
 func (h *PipelineHandler) DeletePipeline(w http.ResponseWriter, r *http.Request) {
-	handleVerifiedDelete(w, r, errPipelineIDRequired, errPipelineNotFound, errFailedToGetPipeline, "failed to delete pipeline: %v", h.service.Get, h.service.Delete)
+	handleVerifiedDelete(w, r, errPipelineIDRequired, codePipelineNotFound, errPipelineNotFound, errFailedToGetPipeline, "failed to delete pipeline: %v", h.service.Get, h.service.Delete)
 }
 
 func (h *PipelineHandler) CreateStage(w http.ResponseWriter, r *http.Request) {
 	pipelineID := chi.URLParam(r, paramID)
 	var req CreatePipelineStageRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
-	if req.Name == "" {
-		writeError(w, http.StatusBadRequest, "name is required")
+	if fields := validateStageRequest(req); len(fields) > 0 {
+		writeValidationError(w, fields)
 		return
 	}
 	if req.Position == 0 {
@@ -139,12 +188,13 @@ func (h *PipelineHandler) CreateStage(w http.ResponseWriter, r *http.Request) {
 		RequiredFields: req.RequiredFields,
 	})
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to create stage: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to create stage: %v", svcErr))
 		return
 	}
+	setLocation(w, pipelineStagesCollection, out.ID)
 	w.WriteHeader(http.StatusCreated)
 	if encodeErr := json.NewEncoder(w).Encode(out); encodeErr != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToEncode)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToEncode)
 		return
 	}
 }
@@ -153,13 +203,26 @@ func (h *PipelineHandler) ListStages(w http.ResponseWriter, r *http.Request) {
 	pipelineID := chi.URLParam(r, paramID)
 	items, svcErr := h.service.ListStages(r.Context(), pipelineID)
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list stages: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list stages: %v", svcErr))
 		return
 	}
 	n := len(items)
-	if !writePaginatedOr500(w, items, n, paginationParams{Limit: n, Offset: 0}) {
+	if !writePaginated(w, items, n, n, 0) {
+		return
+	}
+}
+
+func (h *PipelineHandler) GetStage(w http.ResponseWriter, r *http.Request) {
+	stageID := chi.URLParam(r, paramStageID)
+	if stageID == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, errStageIDRequired)
+		return
+	}
+	out, svcErr := h.service.GetStage(r.Context(), stageID)
+	if handleGetError(w, svcErr, codeStageNotFound, errStageNotFound, "failed to get stage: %v") {
 		return
 	}
+	_ = writeJSONOr500(w, out)
 }
 
 func (h *PipelineHandler) UpdateStage(w http.ResponseWriter, r *http.Request) {
@@ -169,7 +232,7 @@ func (h *PipelineHandler) UpdateStage(w http.ResponseWriter, r *http.Request) {
 	}
 	var req UpdatePipelineStageRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return
 	}
 	req = fillStageDefaults(req, existing)
@@ -181,11 +244,11 @@ func (h *PipelineHandler) UpdateStage(w http.ResponseWriter, r *http.Request) {
 		RequiredFields: req.RequiredFields,
 	})
 	if svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to update stage: %v", svcErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to update stage: %v", svcErr))
 		return
 	}
 	if encodeErr := json.NewEncoder(w).Encode(out); encodeErr != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToEncode)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToEncode)
 		return
 	}
 }
@@ -196,11 +259,11 @@ func (h *PipelineHandler) getStageForUpdate(w http.ResponseWriter, r *http.Reque
 		stageID = chiURLParamID(r)
 	}
 	if stageID == "" {
-		writeError(w, http.StatusBadRequest, errStageIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errStageIDRequired)
 		return "", nil, false
 	}
 	existing, svcErr := h.service.GetStage(r.Context(), stageID)
-	if handleGetError(w, svcErr, errStageNotFound, "failed to get stage: %v") {
+	if handleGetError(w, svcErr, codeStageNotFound, errStageNotFound, "failed to get stage: %v") {
 		return "", nil, false
 	}
 	return stageID, existing, true
@@ -222,15 +285,39 @@ func (h *PipelineHandler) DeleteStage(w http.ResponseWriter, r *http.Request) {
 		stageID = chi.URLParam(r, paramID)
 	}
 	if stageID == "" {
-		writeError(w, http.StatusBadRequest, errStageIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errStageIDRequired)
 		return
 	}
-	if _, svcErr := h.service.GetStage(r.Context(), stageID); handleGetError(w, svcErr, errStageNotFound, "failed to get stage: %v") {
+	if _, svcErr := h.service.GetStage(r.Context(), stageID); handleGetError(w, svcErr, codeStageNotFound, errStageNotFound, "failed to get stage: %v") {
 		return
 	}
-	if svcErr := h.service.DeleteStage(r.Context(), stageID); svcErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to delete stage: %v", svcErr))
+	svcErr := h.service.DeleteStage(r.Context(), stageID, r.URL.Query().Get(queryReassignTo))
+	switch {
+	case errors.Is(svcErr, crm.ErrStageInUse):
+		writeError(w, http.StatusConflict, codeConflict, "stage has deals or cases; pass reassign_to to move them")
+		return
+	case errors.Is(svcErr, crm.ErrInvalidStageReassignment):
+		writeError(w, http.StatusBadRequest, codeBadRequest, "reassign_to must be another stage of the same pipeline")
+		return
+	case svcErr != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to delete stage: %v", svcErr))
 		return
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// validateStageRequest reports the invalid fields of a stage create request.
+// A zero position is allowed and defaults to 1.
+func validateStageRequest(req CreatePipelineStageRequest) map[string]string {
+	fields := requireFields(map[string]string{"name": req.Name})
+	if req.Position < 0 {
+		fields["position"] = "must be 1 or greater"
+	}
+	if req.Probability != nil && (*req.Probability < 0 || *req.Probability > 1) {
+		fields["probability"] = "must be between 0 and 1"
+	}
+	if req.SLAHours != nil && *req.SLAHours < 0 {
+		fields["slaHours"] = "must not be negative"
+	}
+	return fields
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-chi/chi/v5"
@@ -31,6 +32,13 @@ func TestPipelineHandler_CreatePipeline_Success(t *testing.T) {
 	if rr.Code != http.StatusCreated {
 		t.Fatalf("expected 201, got %d body=%s", rr.Code, rr.Body.String())
 	}
+	var created crm.Pipeline
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode pipeline: %v", err)
+	}
+	if got := rr.Header().Get("Location"); got != "/api/v1/pipelines/"+created.ID {
+		t.Fatalf("Location = %q; want /api/v1/pipelines/%s", got, created.ID)
+	}
 }
 
 func TestPipelineHandler_CreatePipeline_MissingRequired_Returns400(t *testing.T) {
@@ -50,6 +58,7 @@ func TestPipelineHandler_CreatePipeline_MissingRequired_Returns400(t *testing.T)
 	if rr.Code != http.StatusBadRequest {
 		t.Fatalf("expected 400, got %d", rr.Code)
 	}
+	assertValidationFields(t, rr, map[string]string{"entityType": "is required"})
 }
 
 func TestPipelineHandler_CreatePipeline_MissingWorkspace_Returns400(t *testing.T) {
@@ -60,6 +69,7 @@ func TestPipelineHandler_CreatePipeline_MissingWorkspace_Returns400(t *testing.T
 
 	body, _ := json.Marshal(map[string]any{"name": "Sales", "entityType": "deal"})
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/pipelines", bytes.NewReader(body))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 
 	rr := httptest.NewRecorder()
 	h.CreatePipeline(rr, req)
@@ -168,6 +178,7 @@ func TestPipelineHandler_ListPipelines_MissingWorkspace_Returns400(t *testing.T)
 	h := NewPipelineHandler(crm.NewPipelineService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pipelines", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	h.ListPipelines(rr, req)
 
@@ -183,6 +194,7 @@ func TestPipelineHandler_GetPipeline_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewPipelineHandler(crm.NewPipelineService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/pipelines/p1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "p1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -223,6 +235,73 @@ func TestPipelineHandler_UpdatePipeline_Success(t *testing.T) {
 	}
 }
 
+func TestPipelineHandler_PatchPipeline_KeepsOmittedFields(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewPipelineService(db)
+	h := NewPipelineHandler(svc)
+
+	p, err := svc.Create(t.Context(), crm.CreatePipelineInput{WorkspaceID: wsID, Name: "Initial", EntityType: "case", Settings: `{"color":"blue"}`})
+	if err != nil {
+		t.Fatalf("seed pipeline failed: %v", err)
+	}
+
+	body, _ := json.Marshal(map[string]any{"name": "Patched"})
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/pipelines/"+p.ID, bytes.NewReader(body))
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", p.ID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rr := httptest.NewRecorder()
+	h.PatchPipeline(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	updated, err := svc.Get(t.Context(), wsID, p.ID)
+	if err != nil {
+		t.Fatalf("Get pipeline error = %v", err)
+	}
+	if updated.Name != "Patched" || updated.EntityType != "case" {
+		t.Fatalf("pipeline = %+v; want patched name and kept entity type", updated)
+	}
+	if updated.Settings == nil || *updated.Settings != `{"color":"blue"}` {
+		t.Fatalf("settings = %v; want omitted field kept", updated.Settings)
+	}
+}
+
+func TestPipelineHandler_PatchPipeline_RejectsClearingRequiredFields(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewPipelineService(db)
+	h := NewPipelineHandler(svc)
+
+	p, err := svc.Create(t.Context(), crm.CreatePipelineInput{WorkspaceID: wsID, Name: "Initial", EntityType: "case"})
+	if err != nil {
+		t.Fatalf("seed pipeline failed: %v", err)
+	}
+
+	for _, body := range []string{`{"name":null}`, `{"entityType":""}`} {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/pipelines/"+p.ID, strings.NewReader(body))
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", p.ID)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		rr := httptest.NewRecorder()
+		h.PatchPipeline(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("PatchPipeline(%s) status = %d; want %d", body, rr.Code, http.StatusBadRequest)
+		}
+	}
+}
+
 func TestPipelineHandler_DeletePipeline_Success(t *testing.T) {
 	t.Parallel()
 
@@ -257,6 +336,7 @@ func TestPipelineHandler_UpdatePipeline_MissingWorkspace_Returns400(t *testing.T
 	h := NewPipelineHandler(crm.NewPipelineService(db))
 
 	req := httptest.NewRequest(http.MethodPut, "/api/v1/pipelines/p1", bytes.NewBufferString(`{"name":"x"}`))
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "p1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -303,6 +383,7 @@ func TestPipelineHandler_DeletePipeline_MissingWorkspace_Returns400(t *testing.T
 	h := NewPipelineHandler(crm.NewPipelineService(db))
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/pipelines/p1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "p1")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -315,6 +396,47 @@ func TestPipelineHandler_DeletePipeline_MissingWorkspace_Returns400(t *testing.T
 	}
 }
 
+func TestPipelineHandler_CreateStage_SetsLocationOfStage(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewPipelineService(db)
+	pipeline, err := svc.Create(context.Background(), crm.CreatePipelineInput{WorkspaceID: wsID, Name: "Sales", EntityType: "deal"})
+	if err != nil {
+		t.Fatalf("create pipeline: %v", err)
+	}
+	h := NewPipelineHandler(svc)
+	r := chi.NewRouter()
+	r.Post("/api/v1/pipelines/{id}/stages", h.CreateStage)
+	r.Get("/api/v1/pipelines/stages/{stage_id}", h.GetStage)
+
+	body, _ := json.Marshal(map[string]any{"name": "Qualify", "position": 1})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pipelines/"+pipeline.ID+"/stages", bytes.NewReader(body))
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d body=%s", rr.Code, rr.Body.String())
+	}
+	var created crm.PipelineStage
+	if err = json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode stage: %v", err)
+	}
+	location := rr.Header().Get("Location")
+	if location != "/api/v1/pipelines/stages/"+created.ID {
+		t.Fatalf("Location = %q; want /api/v1/pipelines/stages/%s", location, created.ID)
+	}
+
+	get := httptest.NewRequest(http.MethodGet, location, nil)
+	get = get.WithContext(contextWithWorkspaceID(get.Context(), wsID))
+	got := httptest.NewRecorder()
+	r.ServeHTTP(got, get)
+	if got.Code != http.StatusOK {
+		t.Fatalf("GET Location = %d: %s", got.Code, got.Body.String())
+	}
+}
+
 func TestPipelineHandler_CreateStage_InvalidJSON_Returns400(t *testing.T) {
 	t.Parallel()
 
@@ -352,6 +474,31 @@ func TestPipelineHandler_CreateStage_MissingName_Returns400(t *testing.T) {
 	if rr.Code != http.StatusBadRequest {
 		t.Fatalf("expected 400, got %d", rr.Code)
 	}
+	assertValidationFields(t, rr, map[string]string{"name": "is required"})
+}
+
+func TestPipelineHandler_CreateStage_InvalidFields_ReportsEachField(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	h := NewPipelineHandler(crm.NewPipelineService(db))
+
+	body, _ := json.Marshal(map[string]any{"name": "Qualified", "position": -2, "probability": 1.5})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/pipelines/p1/stages", bytes.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", "p1")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rr := httptest.NewRecorder()
+	h.CreateStage(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+	assertValidationFields(t, rr, map[string]string{
+		"position":    "must be 1 or greater",
+		"probability": "must be between 0 and 1",
+	})
 }
 
 func TestPipelineHandler_ListStages_EmptyPipelineID_StillHandlesRequest(t *testing.T) {
@@ -562,6 +709,41 @@ func TestPipelineHandler_DeleteStage_UsesIDFallback(t *testing.T) {
 	}
 }
 
+func TestPipelineHandler_DeleteStage_InUseReturns409(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewPipelineService(db)
+	h := NewPipelineHandler(svc)
+
+	p, err := svc.Create(t.Context(), crm.CreatePipelineInput{WorkspaceID: wsID, Name: "Support", EntityType: "case"})
+	if err != nil {
+		t.Fatalf("seed pipeline failed: %v", err)
+	}
+	stage, err := svc.CreateStage(t.Context(), crm.CreatePipelineStageInput{PipelineID: p.ID, Name: "Triage", Position: 1})
+	if err != nil {
+		t.Fatalf("seed stage failed: %v", err)
+	}
+	if _, err = db.Exec(`INSERT INTO case_ticket (id, workspace_id, owner_id, pipeline_id, stage_id, subject, priority, status, created_at, updated_at)
+		VALUES ('case-in-stage', ?, ?, ?, ?, 'Login broken', 'high', 'open', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`,
+		wsID, ownerID, p.ID, stage.ID); err != nil {
+		t.Fatalf("seed case failed: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/pipelines/stages/"+stage.ID, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("stage_id", stage.ID)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rr := httptest.NewRecorder()
+	h.DeleteStage(rr, req)
+
+	if rr.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
 func TestFillStageDefaults_KeepsExistingWhenEmpty(t *testing.T) {
 	t.Parallel()
 
@@ -64,10 +64,10 @@ func (h *PolicyHandler) ListPolicySets(w http.ResponseWriter, r *http.Request) {
 	isActiveFilter := r.URL.Query().Get(queryParamIsActive)
 	sets, err := h.queryPolicySets(r, wsID, isActiveFilter, page)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(errFailedToQueryPolicySets, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(errFailedToQueryPolicySets, err))
 		return
 	}
-	_ = writePaginatedOr500(w, sets, len(sets), page)
+	_ = writePaginated(w, sets, len(sets), page.Limit, page.Offset)
 }
 
 func (h *PolicyHandler) queryPolicySets(r *http.Request, wsID, isActiveFilter string, page paginationParams) ([]policySetRow, error) {
@@ -126,17 +126,17 @@ func (h *PolicyHandler) GetPolicyVersions(w http.ResponseWriter, r *http.Request
 	}
 	setID := chi.URLParam(r, paramID)
 	if setID == "" {
-		writeError(w, http.StatusBadRequest, errPolicySetIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errPolicySetIDRequired)
 		return
 	}
 	page := parsePaginationParams(r)
 
 	versions, err := h.queryPolicyVersions(r, wsID, setID, page)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf(errFailedToQueryVersions, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(errFailedToQueryVersions, err))
 		return
 	}
-	_ = writePaginatedOr500(w, versions, len(versions), page)
+	_ = writePaginated(w, versions, len(versions), page.Limit, page.Offset)
 }
 
 func (h *PolicyHandler) queryPolicyVersions(r *http.Request, wsID, setID string, page paginationParams) ([]policyVersionRow, error) {
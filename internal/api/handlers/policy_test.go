@@ -132,6 +132,7 @@ func TestPolicyHandler_ListPolicySets_400_MissingWorkspaceID(t *testing.T) {
 	h := NewPolicyHandler(db)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/policy/sets", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 
 	h.ListPolicySets(rr, req)
@@ -205,6 +206,7 @@ func TestPolicyHandler_GetPolicyVersions_400_MissingWorkspaceID(t *testing.T) {
 	h := NewPolicyHandler(db)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/policy/sets/some-id/versions", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("id", "some-id")
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
@@ -29,6 +29,7 @@ type PromptVersionService interface {
 type PromptExperimentService interface {
 	StartPromptExperiment(ctx context.Context, input agent.StartPromptExperimentInput) (*agent.PromptExperiment, error)
 	ListPromptExperiments(ctx context.Context, workspaceID, agentID string) ([]*agent.PromptExperiment, error)
+	GetPromptExperimentByID(ctx context.Context, workspaceID, experimentID string) (*agent.PromptExperiment, error)
 	StopPromptExperiment(ctx context.Context, input agent.StopPromptExperimentInput) (*agent.PromptExperiment, error)
 }
 
@@ -69,26 +70,25 @@ func (h *PromptHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	workspaceID, ok := requirePromptWorkspaceID(r)
+	workspaceID, ok := requireWorkspaceID(w, r)
 	if !ok {
-		http.Error(w, errMissingWorkspaceShort, http.StatusUnauthorized)
 		return
 	}
 	agentID := r.URL.Query().Get("agent_id")
 	if agentID == "" {
-		http.Error(w, "missing agent_id query param", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "missing agent_id query param")
 		return
 	}
 
 	versions, err := h.service.ListPromptVersions(r.Context(), workspaceID, agentID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 		return
 	}
 
 	w.Header().Set(headerContentType, mimeJSON)
 	if encodeErr := json.NewEncoder(w).Encode(map[string]any{"data": toPromptVersionResponses(versions)}); encodeErr != nil {
-		http.Error(w, errFailedToEncode, http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToEncode)
 	}
 }
 
@@ -97,16 +97,15 @@ func (h *PromptHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	workspaceID, ok := requirePromptWorkspaceID(r)
+	workspaceID, ok := requireWorkspaceID(w, r)
 	if !ok {
-		http.Error(w, errMissingWorkspaceShort, http.StatusUnauthorized)
 		return
 	}
 	userID, _ := r.Context().Value(ctxkeys.UserID).(string)
 
 	req, err := decodeCreatePromptRequest(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 
@@ -119,15 +118,40 @@ func (h *PromptHandler) Create(w http.ResponseWriter, r *http.Request) {
 		CreatedBy:          &userID,
 	})
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 		return
 	}
 
 	w.Header().Set(headerContentType, mimeJSON)
+	setLocation(w, promptsCollection, pv.ID)
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(map[string]any{"data": toPromptVersionResponse(pv)})
 }
 
+func (h *PromptHandler) Get(w http.ResponseWriter, r *http.Request) {
+	if !checkActionAuthorization(w, r, h.authz, resourceAPI, "admin.prompts.get") {
+		return
+	}
+
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	promptVersionID, ok := getPromptVersionIDParam(w, r)
+	if !ok {
+		return
+	}
+	if _, err := h.service.GetPromptVersionByID(r.Context(), workspaceID, promptVersionID); err != nil {
+		if isPromptNotFoundError(err) {
+			writeError(w, http.StatusNotFound, codePromptVersionNotFound, "prompt version not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
+		return
+	}
+	h.respondWithPromptVersion(w, r, workspaceID, promptVersionID)
+}
+
 func decodeCreatePromptRequest(r *http.Request) (CreatePromptVersionRequest, error) {
 	var req CreatePromptVersionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -150,18 +174,10 @@ func (h *PromptHandler) Promote(w http.ResponseWriter, r *http.Request) {
 	h.handlePromptVersionAction(w, r, "admin.prompts.promote", h.service.PromotePrompt, writePromoteError)
 }
 
-func requirePromptWorkspaceID(r *http.Request) (string, bool) {
-	workspaceID, ok := r.Context().Value(ctxkeys.WorkspaceID).(string)
-	if !ok || workspaceID == "" {
-		return "", false
-	}
-	return workspaceID, true
-}
-
 func getPromptVersionIDParam(w http.ResponseWriter, r *http.Request) (string, bool) {
 	promptVersionID := chi.URLParam(r, paramID)
 	if promptVersionID == "" {
-		http.Error(w, "missing id param", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "missing id param")
 		return "", false
 	}
 	return promptVersionID, true
@@ -170,11 +186,11 @@ func getPromptVersionIDParam(w http.ResponseWriter, r *http.Request) (string, bo
 func writePromoteError(w http.ResponseWriter, err error) {
 	switch {
 	case isPromptNotFoundError(err):
-		http.Error(w, "prompt version not found", http.StatusNotFound)
+		writeError(w, http.StatusNotFound, codePromptVersionNotFound, "prompt version not found")
 	case errors.Is(err, agent.ErrPromptPromotionEvalMissing), errors.Is(err, agent.ErrPromptPromotionEvalFailed):
-		http.Error(w, err.Error(), http.StatusConflict)
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
 	default:
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 	}
 }
 
@@ -189,13 +205,13 @@ func isPromptNotFoundError(err error) bool {
 func (h *PromptHandler) respondWithPromptVersion(w http.ResponseWriter, r *http.Request, workspaceID, promptVersionID string) {
 	pv, err := h.service.GetPromptVersionByID(r.Context(), workspaceID, promptVersionID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 		return
 	}
 
 	w.Header().Set(headerContentType, mimeJSON)
 	if encodeErr := json.NewEncoder(w).Encode(map[string]any{"data": toPromptVersionResponse(pv)}); encodeErr != nil {
-		http.Error(w, errFailedToEncode, http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToEncode)
 	}
 }
 
@@ -205,14 +221,14 @@ func (h *PromptHandler) Rollback(w http.ResponseWriter, r *http.Request) {
 
 func writeRollbackError(w http.ResponseWriter, err error) {
 	if errors.Is(err, agent.ErrPromptRollbackInvalid) {
-		http.Error(w, err.Error(), http.StatusConflict)
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
 		return
 	}
 	if isPromptNotFoundError(err) {
-		http.Error(w, "prompt version not found", http.StatusNotFound)
+		writeError(w, http.StatusNotFound, codePromptVersionNotFound, "prompt version not found")
 		return
 	}
-	http.Error(w, err.Error(), http.StatusInternalServerError)
+	writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 }
 
 func (h *PromptHandler) handlePromptVersionAction(
@@ -226,9 +242,8 @@ func (h *PromptHandler) handlePromptVersionAction(
 		return
 	}
 
-	workspaceID, ok := requirePromptWorkspaceID(r)
+	workspaceID, ok := requireWorkspaceID(w, r)
 	if !ok {
-		http.Error(w, errMissingWorkspaceShort, http.StatusUnauthorized)
 		return
 	}
 	promptVersionID, ok := getPromptVersionIDParam(w, r)
@@ -37,20 +37,19 @@ func (h *PromptHandler) ListExperiments(w http.ResponseWriter, r *http.Request)
 	if !checkActionAuthorization(w, r, h.authz, resourceAPI, "admin.prompts.experiments.list") {
 		return
 	}
-	workspaceID, ok := requirePromptWorkspaceID(r)
+	workspaceID, ok := requireWorkspaceID(w, r)
 	if !ok {
-		http.Error(w, errMissingWorkspaceShort, http.StatusUnauthorized)
 		return
 	}
 	agentID := r.URL.Query().Get("agent_id")
 	if agentID == "" {
-		http.Error(w, "missing agent_id query param", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, "missing agent_id query param")
 		return
 	}
 
 	experiments, err := h.experiments.ListPromptExperiments(r.Context(), workspaceID, agentID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 		return
 	}
 
@@ -73,10 +72,33 @@ func (h *PromptHandler) StartExperiment(w http.ResponseWriter, r *http.Request)
 	}
 
 	w.Header().Set(headerContentType, mimeJSON)
+	setLocation(w, promptExperimentsCollection, experiment.ID)
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(map[string]any{"data": toPromptExperimentResponse(experiment)})
 }
 
+func (h *PromptHandler) GetExperiment(w http.ResponseWriter, r *http.Request) {
+	if !checkActionAuthorization(w, r, h.authz, resourceAPI, "admin.prompts.experiments.get") {
+		return
+	}
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	experimentID, ok := getPromptVersionIDParam(w, r)
+	if !ok {
+		return
+	}
+	experiment, err := h.experiments.GetPromptExperimentByID(r.Context(), workspaceID, experimentID)
+	if err != nil {
+		writePromptExperimentError(w, err)
+		return
+	}
+
+	w.Header().Set(headerContentType, mimeJSON)
+	_ = json.NewEncoder(w).Encode(map[string]any{"data": toPromptExperimentResponse(experiment)})
+}
+
 func (h *PromptHandler) StopExperiment(w http.ResponseWriter, r *http.Request) {
 	if !checkActionAuthorization(w, r, h.authz, resourceAPI, "admin.prompts.experiments.stop") {
 		return
@@ -96,14 +118,13 @@ func (h *PromptHandler) StopExperiment(w http.ResponseWriter, r *http.Request) {
 }
 
 func buildStartExperimentInput(w http.ResponseWriter, r *http.Request) (agent.StartPromptExperimentInput, bool) {
-	workspaceID, ok := requirePromptWorkspaceID(r)
+	workspaceID, ok := requireWorkspaceID(w, r)
 	if !ok {
-		http.Error(w, errMissingWorkspaceShort, http.StatusUnauthorized)
 		return agent.StartPromptExperimentInput{}, false
 	}
 	req, err := decodeStartExperimentRequest(r)
 	if err != nil {
-		http.Error(w, errInvalidBody, http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return agent.StartPromptExperimentInput{}, false
 	}
 	userID, _ := r.Context().Value(ctxkeys.UserID).(string)
@@ -126,9 +147,8 @@ func decodeStartExperimentRequest(r *http.Request) (StartPromptExperimentRequest
 }
 
 func buildStopExperimentInput(w http.ResponseWriter, r *http.Request) (agent.StopPromptExperimentInput, bool) {
-	workspaceID, ok := requirePromptWorkspaceID(r)
+	workspaceID, ok := requireWorkspaceID(w, r)
 	if !ok {
-		http.Error(w, errMissingWorkspaceShort, http.StatusUnauthorized)
 		return agent.StopPromptExperimentInput{}, false
 	}
 	experimentID, ok := getPromptVersionIDParam(w, r)
@@ -137,7 +157,7 @@ func buildStopExperimentInput(w http.ResponseWriter, r *http.Request) (agent.Sto
 	}
 	req, err := decodeStopExperimentRequest(r)
 	if err != nil {
-		http.Error(w, errInvalidBody, http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return agent.StopPromptExperimentInput{}, false
 	}
 	return agent.StopPromptExperimentInput{
@@ -164,13 +184,13 @@ func writePromptExperimentError(w http.ResponseWriter, err error) {
 	case errors.Is(err, agent.ErrPromptExperimentInvalidSplit),
 		errors.Is(err, agent.ErrPromptExperimentSameVersion),
 		errors.Is(err, agent.ErrPromptExperimentAgentMismatch):
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 	case errors.Is(err, agent.ErrPromptExperimentAlreadyRunning):
-		http.Error(w, err.Error(), http.StatusConflict)
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
 	case errors.Is(err, agent.ErrPromptExperimentNotFound), errors.Is(err, agent.ErrPromptVersionNotFound):
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, http.StatusNotFound, codeNotFound, err.Error())
 	default:
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 	}
 }
 
@@ -121,6 +121,14 @@ func (m *mockPromptExperimentService) ListPromptExperiments(_ context.Context, _
 	return experiments, nil
 }
 
+func (m *mockPromptExperimentService) GetPromptExperimentByID(_ context.Context, _, experimentID string) (*agent.PromptExperiment, error) {
+	experiment, ok := m.experiments[experimentID]
+	if !ok {
+		return nil, agent.ErrPromptExperimentNotFound
+	}
+	return experiment, nil
+}
+
 func (m *mockPromptExperimentService) StopPromptExperiment(_ context.Context, input agent.StopPromptExperimentInput) (*agent.PromptExperiment, error) {
 	experiment, ok := m.experiments[input.ExperimentID]
 	if !ok {
@@ -177,6 +185,15 @@ func TestCreatePromptHandler_Returns201(t *testing.T) {
 	if rr.Code != http.StatusCreated {
 		t.Fatalf("expected 201, got %d", rr.Code)
 	}
+	var resp struct {
+		Data PromptVersionResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got := rr.Header().Get("Location"); got != "/api/v1/admin/prompts/"+resp.Data.ID {
+		t.Fatalf("Location = %q; want /api/v1/admin/prompts/%s", got, resp.Data.ID)
+	}
 }
 
 func TestCreatePromptHandler_ReturnsBadRequestOnInvalidBody(t *testing.T) {
@@ -545,6 +562,10 @@ func (s *promptExperimentErrorService) ListPromptExperiments(_ context.Context,
 	return nil, nil
 }
 
+func (s *promptExperimentErrorService) GetPromptExperimentByID(_ context.Context, _, _ string) (*agent.PromptExperiment, error) {
+	return nil, s.err
+}
+
 func (s *promptExperimentErrorService) StopPromptExperiment(_ context.Context, _ agent.StopPromptExperimentInput) (*agent.PromptExperiment, error) {
 	return nil, nil
 }
@@ -32,7 +32,7 @@ func (h *ReportHandler) GetSalesFunnel(w http.ResponseWriter, r *http.Request) {
 
 	report, err := h.reportService.GetSalesFunnel(r.Context(), wsID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to build sales funnel report: %v", err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to build sales funnel report: %v", err))
 		return
 	}
 	_ = writeJSONOr500(w, report)
@@ -45,7 +45,7 @@ func (h *ReportHandler) GetDealAging(w http.ResponseWriter, r *http.Request) {
 	}
 	rows, err := h.reportService.GetDealAging(r.Context(), wsID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to build deal aging report: %v", err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to build deal aging report: %v", err))
 		return
 	}
 	_ = writeJSONOr500(w, rows)
@@ -63,7 +63,7 @@ func (h *ReportHandler) GetSupportBacklog(w http.ResponseWriter, r *http.Request
 
 	report, err := h.reportService.GetSupportBacklog(r.Context(), wsID, agingDays)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to build support backlog report: %v", err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to build support backlog report: %v", err))
 		return
 	}
 	_ = writeJSONOr500(w, report)
@@ -77,7 +77,7 @@ func (h *ReportHandler) GetSupportVolume(w http.ResponseWriter, r *http.Request)
 
 	rows, err := h.reportService.GetCaseVolume(r.Context(), wsID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to build support volume report: %v", err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to build support volume report: %v", err))
 		return
 	}
 	_ = writeJSONOr500(w, rows)
@@ -103,12 +103,12 @@ func (h *ReportHandler) ExportSupportBacklogCSV(w http.ResponseWriter, r *http.R
 
 func exportCSVReport(w http.ResponseWriter, r *http.Request, _ string, filename, errMsg string, load func() (io.Reader, error)) {
 	if r.URL.Query().Get("format") != "csv" {
-		writeError(w, http.StatusBadRequest, "format must be csv")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "format must be csv")
 		return
 	}
 	reader, err := load()
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("%s: %v", errMsg, err))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("%s: %v", errMsg, err))
 		return
 	}
 	w.Header().Set(headerContentType, mimeCSV)
@@ -125,7 +125,7 @@ func parseAgingDays(w http.ResponseWriter, r *http.Request) (int, bool) {
 	}
 	agingDays, err := strconv.Atoi(v)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "aging_days must be an integer")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "aging_days must be an integer")
 		return 0, false
 	}
 	return agingDays, true
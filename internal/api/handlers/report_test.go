@@ -41,6 +41,7 @@ func TestReportHandler_GetSalesFunnel_MissingWorkspace_400(t *testing.T) {
 	db := mustOpenDBWithMigrations(t)
 	h := NewReportHandler(crm.NewReportService(db))
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/sales/funnel", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 
 	h.GetSalesFunnel(rr, req)
@@ -157,6 +158,7 @@ func TestReportHandler_GetDealAging_MissingWorkspace_400(t *testing.T) {
 	db := mustOpenDBWithMigrations(t)
 	h := NewReportHandler(crm.NewReportService(db))
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/sales/aging", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 
 	h.GetDealAging(rr, req)
@@ -189,6 +191,7 @@ func TestReportHandler_GetSupportBacklog_MissingWorkspace_400(t *testing.T) {
 	db := mustOpenDBWithMigrations(t)
 	h := NewReportHandler(crm.NewReportService(db))
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/support/backlog", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 
 	h.GetSupportBacklog(rr, req)
@@ -203,6 +206,7 @@ func TestReportHandler_GetSupportVolume_MissingWorkspace_400(t *testing.T) {
 	db := mustOpenDBWithMigrations(t)
 	h := NewReportHandler(crm.NewReportService(db))
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/support/volume", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 
 	h.GetSupportVolume(rr, req)
@@ -235,6 +239,7 @@ func TestReportHandler_ExportSalesFunnelCSV_MissingWorkspace_400(t *testing.T) {
 	h := NewReportHandler(crm.NewReportService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/sales/funnel/export?format=csv", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 
 	h.ExportSalesFunnelCSV(rr, req)
@@ -267,6 +272,7 @@ func TestReportHandler_ExportSupportBacklogCSV_MissingWorkspace_400(t *testing.T
 	h := NewReportHandler(crm.NewReportService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/reports/support/backlog/export?format=csv", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 
 	h.ExportSupportBacklogCSV(rr, req)
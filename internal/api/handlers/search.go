@@ -0,0 +1,90 @@
+// GET /api/v1/search — top-level hybrid search over the workspace knowledge.
+// Unlike POST /api/v1/knowledge/search it takes query-string parameters and
+// answers with the standard list envelope, so it can back a search box.
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+)
+
+// SearchHandler handles GET /api/v1/search.
+type SearchHandler struct {
+	searchService *knowledge.SearchService
+}
+
+// NewSearchHandler creates a SearchHandler.
+func NewSearchHandler(svc *knowledge.SearchService) *SearchHandler {
+	return &SearchHandler{searchService: svc}
+}
+
+// searchHit is a single item of the GET /api/v1/search response.
+type searchHit struct {
+	ID         string                `json:"id"`
+	Title      string                `json:"title"`
+	Snippet    string                `json:"snippet"`
+	Highlights []knowledge.Highlight `json:"highlights"`
+	Score      float64               `json:"score"`
+	Method     string                `json:"method"`
+}
+
+// Search handles GET /api/v1/search?q=&limit=&source_type=.
+// limit defaults to 20 and is capped at 50.
+func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	query := strings.TrimSpace(q.Get("q"))
+	if query == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "q is required")
+		return
+	}
+	limit := 0
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			writeError(w, http.StatusBadRequest, codeBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+	sourceType := q.Get("source_type")
+	if sourceType != "" && !isValidSourceType(sourceType) {
+		writeError(w, http.StatusBadRequest, codeBadRequest, "invalid source_type")
+		return
+	}
+
+	results, err := h.searchService.HybridSearch(r.Context(), knowledge.SearchInput{
+		Query:       query,
+		WorkspaceID: wsID,
+		Limit:       limit,
+		SourceType:  knowledge.SourceType(sourceType),
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, "search failed")
+		return
+	}
+
+	hits := make([]searchHit, len(results.Items))
+	for i, item := range results.Items {
+		highlights := item.Highlights
+		if highlights == nil {
+			highlights = []knowledge.Highlight{}
+		}
+		hits[i] = searchHit{
+			ID:         item.KnowledgeItemID,
+			Title:      item.Title,
+			Snippet:    item.Snippet,
+			Highlights: highlights,
+			Score:      item.Score,
+			Method:     string(item.Method),
+		}
+	}
+	writeVersionedPage(w, r, hits, results.TotalCandidates, knowledge.ResolveLimit(limit), 0)
+}
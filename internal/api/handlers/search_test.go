@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestSearchHandler_MissingQuery_Returns400(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	handler := NewSearchHandler(knowledge.NewSearchService(db, &searchStubLLM{}))
+
+	for _, target := range []string{"/api/v1/search", "/api/v1/search?q=pricing&limit=abc", "/api/v1/search?q=pricing&source_type=bogus"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+		handler.Search(rr, req)
+
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("GET %s: expected 400, got %d — body: %s", target, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestSearchHandler_Success_Returns200(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	ingestSvc := knowledge.NewIngestService(db, eventbus.New())
+	for _, st := range []knowledge.SourceType{knowledge.SourceTypeDocument, knowledge.SourceTypeNote} {
+		if _, err := ingestSvc.Ingest(contextWithWorkspaceID(t.Context(), wsID), knowledge.CreateKnowledgeItemInput{
+			WorkspaceID: wsID,
+			SourceType:  st,
+			Title:       "Pricing Strategy",
+			RawContent:  "our pricing discount policy for enterprise customers",
+		}); err != nil {
+			t.Fatalf("ingest failed: %v", err)
+		}
+	}
+	handler := NewSearchHandler(knowledge.NewSearchService(db, &searchStubLLM{}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/search?q=pricing&limit=500&source_type=document", nil)
+	req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+	rr := httptest.NewRecorder()
+	handler.Search(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d — body: %s", rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data []searchHit `json:"data"`
+		Meta Meta        `json:"meta"`
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Data) != 1 {
+		t.Fatalf("expected 1 document result, got %+v", resp.Data)
+	}
+	hit := resp.Data[0]
+	if hit.Title != "Pricing Strategy" || hit.Method == "" || hit.Score <= 0 {
+		t.Errorf("unexpected hit %+v", hit)
+	}
+	if len(hit.Highlights) == 0 {
+		t.Errorf("expected highlights in snippet %q", hit.Snippet)
+	}
+	if resp.Meta.Limit != 50 {
+		t.Errorf("meta.limit = %d; want capped 50", resp.Meta.Limit)
+	}
+}
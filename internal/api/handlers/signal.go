@@ -63,7 +63,7 @@ func (h *SignalHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	filters, err := decodeSignalFilters(r)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 
@@ -92,13 +92,13 @@ func (h *SignalHandler) Dismiss(w http.ResponseWriter, r *http.Request) {
 
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, "signal id is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "signal id is required")
 		return
 	}
 
 	actorID, _ := r.Context().Value(ctxkeys.UserID).(string)
 	if actorID == "" {
-		writeError(w, http.StatusBadRequest, "missing user_id in context")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "missing user_id in context")
 		return
 	}
 
@@ -151,13 +151,13 @@ func decodeSignalFilters(r *http.Request) (signaldomain.Filters, error) {
 func writeSignalError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, signaldomain.ErrSignalNotFound):
-		writeError(w, http.StatusNotFound, "signal not found")
+		writeError(w, http.StatusNotFound, codeSignalNotFound, "signal not found")
 	case errors.Is(err, signaldomain.ErrInvalidSignalInput):
-		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		writeError(w, http.StatusUnprocessableEntity, codeUnprocessableEntity, err.Error())
 	case errors.Is(err, signaldomain.ErrSignalDismissInvalid):
-		writeError(w, http.StatusConflict, err.Error())
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
 	default:
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 	}
 }
 
@@ -31,10 +31,10 @@ func (h *TimelineHandler) ListTimelineByEntity(w http.ResponseWriter, r *http.Re
 	page := parsePaginationParams(r)
 	items, listErr := h.service.ListByEntity(r.Context(), wsID, entityType, entityID, crm.ListTimelineInput{Limit: page.Limit, Offset: page.Offset})
 	if listErr != nil {
-		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list timeline by entity: %v", listErr))
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list timeline by entity: %v", listErr))
 		return
 	}
-	if !writePaginatedOr500(w, items, len(items), page) {
+	if !writePaginated(w, items, len(items), page.Limit, page.Offset) {
 		return
 	}
 }
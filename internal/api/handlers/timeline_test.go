@@ -60,6 +60,7 @@ func TestTimelineHandler_ListTimeline_MissingWorkspace_Returns400(t *testing.T)
 	handler := NewTimelineHandler(crm.NewTimelineService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/timeline", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	handler.ListTimeline(rr, req)
 
@@ -110,6 +111,7 @@ func TestTimelineHandler_ListTimelineByEntity_MissingWorkspace_Returns400(t *tes
 	handler := NewTimelineHandler(crm.NewTimelineService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/timeline/account/a1", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rctx := chi.NewRouteContext()
 	rctx.URLParams.Add("entity_type", "account")
 	rctx.URLParams.Add("entity_id", "a1")
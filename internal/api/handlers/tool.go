@@ -48,15 +48,14 @@ func (h *ToolHandler) ListTools(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	workspaceID, err := getWorkspaceID(r.Context())
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "missing workspace_id in context")
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
 	items, err := h.registry.ListToolDefinitions(r.Context(), workspaceID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to list tools")
+		writeError(w, http.StatusInternalServerError, codeInternal, "failed to list tools")
 		return
 	}
 
@@ -70,14 +69,38 @@ func (h *ToolHandler) ListTools(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]any{"data": out, "meta": map[string]int{"total": len(out)}})
 }
 
+func (h *ToolHandler) GetTool(w http.ResponseWriter, r *http.Request) {
+	if !checkActionAuthorization(w, r, h.authz, resourceAPI, "admin.tools.get") {
+		return
+	}
+
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+
+	id := chi.URLParam(r, paramID)
+	if id == "" {
+		writeError(w, http.StatusBadRequest, codeBadRequest, errToolIDRequired)
+		return
+	}
+
+	item, err := h.registry.GetToolDefinitionByID(r.Context(), workspaceID, id)
+	if err != nil {
+		writeToolError(w, err)
+		return
+	}
+
+	writeJSONOr500(w, toToolResponse(item))
+}
+
 func (h *ToolHandler) CreateTool(w http.ResponseWriter, r *http.Request) {
 	if !checkActionAuthorization(w, r, h.authz, resourceAPI, "admin.tools.create") {
 		return
 	}
 
-	workspaceID, err := getWorkspaceID(r.Context())
-	if err != nil {
-		writeError(w, http.StatusBadRequest, errMissingWorkspaceID)
+	workspaceID, ok := requireWorkspaceID(w, r)
+	if !ok {
 		return
 	}
 
@@ -96,11 +119,12 @@ func (h *ToolHandler) CreateTool(w http.ResponseWriter, r *http.Request) {
 		CreatedBy:           createdBy,
 	})
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 
 	w.Header().Set(headerContentType, mimeJSON)
+	setLocation(w, toolsCollection, item.ID)
 	w.WriteHeader(http.StatusCreated)
 	_ = json.NewEncoder(w).Encode(toToolResponse(item))
 }
@@ -117,7 +141,7 @@ func (h *ToolHandler) UpdateTool(w http.ResponseWriter, r *http.Request) {
 
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errToolIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errToolIDRequired)
 		return
 	}
 
@@ -166,7 +190,7 @@ func (h *ToolHandler) setToolActive(w http.ResponseWriter, r *http.Request, isAc
 
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errToolIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errToolIDRequired)
 		return
 	}
 
@@ -182,11 +206,11 @@ func (h *ToolHandler) setToolActive(w http.ResponseWriter, r *http.Request, isAc
 func decodeToolRequest(w http.ResponseWriter, r *http.Request) (createToolRequest, bool) {
 	var req createToolRequest
 	if decodeErr := json.NewDecoder(r.Body).Decode(&req); decodeErr != nil {
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 		return createToolRequest{}, false
 	}
 	if req.Name == "" {
-		writeError(w, http.StatusBadRequest, "name is required")
+		writeError(w, http.StatusBadRequest, codeBadRequest, "name is required")
 		return createToolRequest{}, false
 	}
 	return req, true
@@ -197,11 +221,11 @@ func writeToolError(w http.ResponseWriter, err error) {
 	case err == nil:
 		return
 	case errors.Is(err, tool.ErrToolDefinitionNotFound):
-		writeError(w, http.StatusNotFound, "tool definition not found")
+		writeError(w, http.StatusNotFound, codeToolNotFound, "tool definition not found")
 	case errors.Is(err, tool.ErrToolDefinitionInvalid):
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 	default:
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 	}
 }
 
@@ -55,6 +55,23 @@ func TestToolHandler_CreateAndListTools(t *testing.T) {
 	if rr.Code != http.StatusCreated {
 		t.Fatalf("status=%d want=%d body=%s", rr.Code, http.StatusCreated, rr.Body.String())
 	}
+	var created toolResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode created tool: %v", err)
+	}
+	location := rr.Header().Get("Location")
+	if location != "/api/v1/admin/tools/"+created.ID {
+		t.Fatalf("Location = %q; want /api/v1/admin/tools/%s", location, created.ID)
+	}
+	r := chi.NewRouter()
+	r.Get("/api/v1/admin/tools/{id}", h.GetTool)
+	getReq := httptest.NewRequest(http.MethodGet, location, nil)
+	getReq = getReq.WithContext(contextWithWorkspaceID(getReq.Context(), wsID))
+	getRR := httptest.NewRecorder()
+	r.ServeHTTP(getRR, getReq)
+	if getRR.Code != http.StatusOK {
+		t.Fatalf("GET Location status=%d body=%s", getRR.Code, getRR.Body.String())
+	}
 
 	listReq := httptest.NewRequest(http.MethodGet, "/api/v1/admin/tools", nil)
 	listReq = listReq.WithContext(contextWithWorkspaceID(listReq.Context(), wsID))
@@ -65,7 +65,7 @@ func (h *UsageHandler) ListUsage(w http.ResponseWriter, r *http.Request) {
 	runID := optionalTrimmedQuery(r, queryRunID)
 	items, err := h.service.ListEvents(r.Context(), workspaceID, runID, page.Limit)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToListUsage)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToListUsage)
 		return
 	}
 
@@ -73,7 +73,7 @@ func (h *UsageHandler) ListUsage(w http.ResponseWriter, r *http.Request) {
 	for _, item := range items {
 		out = append(out, usageEventToResponse(item))
 	}
-	_ = writePaginatedOr500(w, out, len(out), page)
+	_ = writePaginated(w, out, len(out), page.Limit, page.Offset)
 }
 
 func (h *UsageHandler) GetQuotaState(w http.ResponseWriter, r *http.Request) {
@@ -95,11 +95,11 @@ func (h *UsageHandler) GetQuotaState(w http.ResponseWriter, r *http.Request) {
 
 	state, err := h.service.GetState(r.Context(), workspaceID, policyID, periodStart, periodEnd)
 	if errors.Is(err, usagedomain.ErrQuotaStateNotFound) {
-		writeError(w, http.StatusNotFound, "quota state not found")
+		writeError(w, http.StatusNotFound, codeQuotaStateNotFound, "quota state not found")
 		return
 	}
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, errFailedToGetQuotaState)
+		writeError(w, http.StatusInternalServerError, codeInternal, errFailedToGetQuotaState)
 		return
 	}
 
@@ -109,7 +109,7 @@ func (h *UsageHandler) GetQuotaState(w http.ResponseWriter, r *http.Request) {
 func requireQuotaPolicyID(w http.ResponseWriter, r *http.Request) (string, bool) {
 	policyID := r.URL.Query().Get(queryQuotaPolicyID)
 	if policyID == "" {
-		writeError(w, http.StatusBadRequest, errQuotaPolicyIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errQuotaPolicyIDRequired)
 		return "", false
 	}
 	return policyID, true
@@ -118,11 +118,11 @@ func requireQuotaPolicyID(w http.ResponseWriter, r *http.Request) (string, bool)
 func writeQuotaPeriodError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, errBadPeriodStart):
-		writeError(w, http.StatusBadRequest, errInvalidPeriodStart)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errInvalidPeriodStart)
 	case errors.Is(err, errBadPeriodEnd):
-		writeError(w, http.StatusBadRequest, errInvalidPeriodEnd)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errInvalidPeriodEnd)
 	default:
-		writeError(w, http.StatusBadRequest, errInvalidBody)
+		writeError(w, http.StatusBadRequest, codeInvalidBody, errInvalidBody)
 	}
 }
 
@@ -69,6 +69,7 @@ func TestUsageHandler_ListUsage_MissingWorkspace_Returns400(t *testing.T) {
 	h := NewUsageHandler(usagedomain.NewService(db))
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/usage", nil)
+	req = req.WithContext(contextWithUserID(req.Context(), "user-1"))
 	rr := httptest.NewRecorder()
 	h.ListUsage(rr, req)
 
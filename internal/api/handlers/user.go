@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+// UserHandler serves workspace user administration.
+type UserHandler struct{ reassign *crm.OwnerReassignService }
+
+func NewUserHandler(reassign *crm.OwnerReassignService) *UserHandler {
+	return &UserHandler{reassign: reassign}
+}
+
+// ReassignOwner handles POST /api/v1/users/{id}/reassign?to=: every live
+// record owned by user {id} moves to user "to". Responds with the number of
+// records moved per entity type.
+func (h *UserHandler) ReassignOwner(w http.ResponseWriter, r *http.Request) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return
+	}
+	fromID := chi.URLParam(r, paramID)
+	toID := r.URL.Query().Get("to")
+	if toID == "" {
+		writeValidationError(w, map[string]string{"to": "is required"})
+		return
+	}
+
+	out, err := h.reassign.ReassignOwner(r.Context(), wsID, fromID, toID)
+	switch {
+	case errors.Is(err, crm.ErrInvalidOwnerReassignment):
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to reassign owner: %v", err))
+		return
+	}
+	_ = writeJSONOr500(w, out)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestUserHandler_ReassignOwner(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, fromID := setupWorkspaceAndOwner(t, db)
+	toID := createUser(t, db, wsID)
+	if _, err := db.Exec(`INSERT INTO account (id, workspace_id, name, owner_id, created_at, updated_at)
+		VALUES ('acc-reassign', ?, 'Initech', ?, datetime('now'), datetime('now'))`, wsID, fromID); err != nil {
+		t.Fatalf("seed account: %v", err)
+	}
+
+	r := chi.NewRouter()
+	r.Post("/users/{id}/reassign", NewUserHandler(crm.NewOwnerReassignService(db)).ReassignOwner)
+	do := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		req = req.WithContext(contextWithWorkspaceID(req.Context(), wsID))
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assertErrorCode(t, do("/users/"+fromID+"/reassign"), http.StatusBadRequest, codeValidationFailed)
+	assertErrorCode(t, do("/users/"+fromID+"/reassign?to=missing"), http.StatusBadRequest, codeBadRequest)
+
+	rr := do("/users/" + fromID + "/reassign?to=" + toID)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d; body=%s", rr.Code, rr.Body.String())
+	}
+	var got crm.OwnerReassignment
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got != (crm.OwnerReassignment{Accounts: 1}) {
+		t.Fatalf("response = %+v; want 1 account", got)
+	}
+}
@@ -216,6 +216,7 @@ func (h *WorkflowHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setLocation(w, "workflows", out.ID)
 	w.WriteHeader(http.StatusCreated)
 	_ = writeJSONOr500(w, map[string]any{"data": workflowToResponse(out)})
 }
@@ -232,7 +233,7 @@ func (h *WorkflowHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errWorkflowIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errWorkflowIDRequired)
 		return
 	}
 
@@ -257,7 +258,7 @@ func (h *WorkflowHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	input, err := decodeWorkflowListInput(r)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+		writeError(w, http.StatusBadRequest, codeBadRequest, err.Error())
 		return
 	}
 
@@ -284,7 +285,7 @@ func (h *WorkflowHandler) Diff(w http.ResponseWriter, r *http.Request) {
 	}
 	response, err := workflowDiffToResponse(req)
 	if err != nil {
-		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		writeError(w, http.StatusUnprocessableEntity, codeUnprocessableEntity, err.Error())
 		return
 	}
 	_ = writeJSONOr500(w, map[string]any{"data": response})
@@ -300,7 +301,7 @@ func (h *WorkflowHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errWorkflowIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errWorkflowIDRequired)
 		return
 	}
 	var req UpdateWorkflowRequest
@@ -333,7 +334,7 @@ func (h *WorkflowHandler) ListVersions(w http.ResponseWriter, r *http.Request) {
 
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errWorkflowIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errWorkflowIDRequired)
 		return
 	}
 
@@ -362,7 +363,7 @@ func (h *WorkflowHandler) NewVersion(w http.ResponseWriter, r *http.Request) {
 
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errWorkflowIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errWorkflowIDRequired)
 		return
 	}
 
@@ -387,7 +388,7 @@ func (h *WorkflowHandler) Rollback(w http.ResponseWriter, r *http.Request) {
 
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errWorkflowIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errWorkflowIDRequired)
 		return
 	}
 
@@ -438,7 +439,7 @@ func (h *WorkflowHandler) Graph(w http.ResponseWriter, r *http.Request) { // CLS
 	}
 	result, err := agent.ValidateWorkflowForTooling(r.Context(), item)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 		return
 	}
 	if r.URL.Query().Get("format") == "visual" {
@@ -461,7 +462,7 @@ func (h *WorkflowHandler) Validate(w http.ResponseWriter, r *http.Request) {
 	}
 	result, err := agent.ValidateWorkflowForTooling(r.Context(), item)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 		return
 	}
 	response := workflowValidateToResponse(result)
@@ -481,7 +482,7 @@ func (h *WorkflowHandler) Preview(w http.ResponseWriter, r *http.Request) { // C
 	}
 	result, err := agent.ValidateWorkflowForTooling(r.Context(), previewWorkflow(req))
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 		return
 	}
 	response := workflowPreviewToResponse(result)
@@ -577,7 +578,7 @@ func writeVisualSourceGenerationFailure(w http.ResponseWriter, workflowID string
 func validateVisualAuthoringCandidate(w http.ResponseWriter, ctx context.Context, candidate *workflowdomain.Workflow) bool {
 	validation, err := agent.ValidateWorkflowForTooling(ctx, candidate)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 		return false
 	}
 	validationResponse := workflowValidateToResponse(validation)
@@ -624,12 +625,12 @@ func (h *WorkflowHandler) Execute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if !h.isRuntimeConfigured() {
-		writeError(w, http.StatusInternalServerError, "workflow execute runtime is not configured")
+		writeError(w, http.StatusInternalServerError, codeInternal, "workflow execute runtime is not configured")
 		return
 	}
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errWorkflowIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errWorkflowIDRequired)
 		return
 	}
 	var req ExecuteWorkflowRequest
@@ -653,7 +654,7 @@ func (h *WorkflowHandler) loadAndRunWorkflow(w http.ResponseWriter, r *http.Requ
 		return nil, false
 	}
 	if vErr := validateWorkflowForExecution(item); vErr != nil {
-		writeError(w, http.StatusConflict, vErr.Error())
+		writeError(w, http.StatusConflict, codeConflict, vErr.Error())
 		return nil, false
 	}
 	run, err := h.executeDSLWorkflow(r, workspaceID, item, req)
@@ -671,7 +672,7 @@ func (h *WorkflowHandler) loadWorkflowForJudge(w http.ResponseWriter, r *http.Re
 	}
 	id := chi.URLParam(r, paramID)
 	if id == "" {
-		writeError(w, http.StatusBadRequest, errWorkflowIDRequired)
+		writeError(w, http.StatusBadRequest, codeBadRequest, errWorkflowIDRequired)
 		return "", "", nil, false
 	}
 	item, err := h.reader.Get(r.Context(), workspaceID, id)
@@ -685,7 +686,7 @@ func (h *WorkflowHandler) loadWorkflowForJudge(w http.ResponseWriter, r *http.Re
 func (h *WorkflowHandler) verifyWorkflowForJudge(w http.ResponseWriter, r *http.Request, item *workflowdomain.Workflow) (*agent.JudgeResult, bool) {
 	result, err := agent.NewJudge().Verify(r.Context(), item)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 		return nil, false
 	}
 	return result, true
@@ -706,7 +707,7 @@ func validateWorkflowForActivation(w http.ResponseWriter, item *workflowdomain.W
 	if item.Status == workflowdomain.StatusTesting {
 		return true
 	}
-	writeError(w, http.StatusConflict, "workflow must be in testing to activate")
+	writeError(w, http.StatusConflict, codeConflict, "workflow must be in testing to activate")
 	return false
 }
 
@@ -925,30 +926,30 @@ func decodeWorkflowListInput(r *http.Request) (workflowdomain.ListWorkflowsInput
 func writeWorkflowError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, workflowdomain.ErrWorkflowNotFound):
-		writeError(w, http.StatusNotFound, "workflow not found")
+		writeError(w, http.StatusNotFound, codeWorkflowNotFound, "workflow not found")
 	case errors.Is(err, workflowdomain.ErrInvalidWorkflowInput):
-		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		writeError(w, http.StatusUnprocessableEntity, codeUnprocessableEntity, err.Error())
 	case errors.Is(err, workflowdomain.ErrWorkflowNameConflict),
 		errors.Is(err, workflowdomain.ErrWorkflowNotEditable),
 		errors.Is(err, workflowdomain.ErrInvalidStatusTransition),
 		errors.Is(err, workflowdomain.ErrWorkflowVersionInvalid),
 		errors.Is(err, workflowdomain.ErrWorkflowDeleteInvalid),
 		errors.Is(err, workflowdomain.ErrWorkflowActiveConflict):
-		writeError(w, http.StatusConflict, err.Error())
+		writeError(w, http.StatusConflict, codeConflict, err.Error())
 	default:
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 	}
 }
 
 func writeWorkflowExecuteError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, workflowdomain.ErrWorkflowNotFound), errors.Is(err, agent.ErrDSLWorkflowNotFound):
-		writeError(w, http.StatusNotFound, "workflow not found")
+		writeError(w, http.StatusNotFound, codeWorkflowNotFound, "workflow not found")
 	case errors.Is(err, workflowdomain.ErrInvalidWorkflowInput),
 		errors.Is(err, agent.ErrInvalidTriggerType):
-		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		writeError(w, http.StatusUnprocessableEntity, codeUnprocessableEntity, err.Error())
 	default:
-		writeError(w, http.StatusInternalServerError, err.Error())
+		writeError(w, http.StatusInternalServerError, codeInternal, err.Error())
 	}
 }
 
@@ -1474,6 +1474,8 @@ func (s workflowStubToolExecutor) Execute(_ context.Context, _ json.RawMessage)
 	return s.result, nil
 }
 
+func (workflowStubToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
 func setupWorkflowToolRegistry(t testing.TB, db *sql.DB, wsID string) *tool.ToolRegistry {
 	t.Helper()
 
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+type WorkspaceHandler struct{ service *crm.WorkspaceService }
+
+func NewWorkspaceHandler(service *crm.WorkspaceService) *WorkspaceHandler {
+	return &WorkspaceHandler{service: service}
+}
+
+type UpdateWorkspaceRequest struct {
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Settings string `json:"settings,omitempty"`
+}
+
+const (
+	errWorkspaceNotFound    = "workspace not found"
+	errFailedToGetWorkspace = "failed to get workspace: %v"
+)
+
+// ListWorkspaces lists the workspaces of the calling user.
+func (h *WorkspaceHandler) ListWorkspaces(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(ctxkeys.UserID).(string)
+	if !ok || userID == "" {
+		writeError(w, http.StatusUnauthorized, codeUnauthorized, "missing user context")
+		return
+	}
+	items, svcErr := h.service.ListForUser(r.Context(), userID)
+	if svcErr != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf("failed to list workspaces: %v", svcErr))
+		return
+	}
+	n := len(items)
+	if !writePaginated(w, items, n, n, 0) {
+		return
+	}
+}
+
+func (h *WorkspaceHandler) GetWorkspace(w http.ResponseWriter, r *http.Request) {
+	id, ok := requireOwnWorkspace(w, r)
+	if !ok {
+		return
+	}
+	out, svcErr := h.service.Get(r.Context(), id)
+	if handleGetError(w, svcErr, codeWorkspaceNotFound, errWorkspaceNotFound, errFailedToGetWorkspace) {
+		return
+	}
+	if !writeJSONOr500(w, out) {
+		return
+	}
+}
+
+func (h *WorkspaceHandler) UpdateWorkspace(w http.ResponseWriter, r *http.Request) {
+	id, ok := requireOwnWorkspace(w, r)
+	if !ok {
+		return
+	}
+	existing, svcErr := h.service.Get(r.Context(), id)
+	if handleGetError(w, svcErr, codeWorkspaceNotFound, errWorkspaceNotFound, errFailedToGetWorkspace) {
+		return
+	}
+	var req UpdateWorkspaceRequest
+	if !decodeBodyJSON(w, r, &req) {
+		return
+	}
+	out, svcErr := h.service.Update(r.Context(), id, crm.UpdateWorkspaceInput{
+		Name:     coalesce(req.Name, existing.Name),
+		Slug:     coalesce(req.Slug, existing.Slug),
+		Settings: coalescePtr(req.Settings, existing.Settings),
+	})
+	if svcErr != nil {
+		writeWorkspaceError(w, svcErr, "failed to update workspace: %v")
+		return
+	}
+	if !writeJSONOr500(w, out) {
+		return
+	}
+}
+
+// DeleteWorkspace soft-deletes the caller's workspace; its members are locked
+// out from then on.
+func (h *WorkspaceHandler) DeleteWorkspace(w http.ResponseWriter, r *http.Request) {
+	id, ok := requireOwnWorkspace(w, r)
+	if !ok {
+		return
+	}
+	svcErr := h.service.Delete(r.Context(), id)
+	if handleGetError(w, svcErr, codeWorkspaceNotFound, errWorkspaceNotFound, "failed to delete workspace: %v") {
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireOwnWorkspace returns the {id} URL param when it is the caller's
+// workspace. Other workspaces are reported as not found, so their existence
+// does not leak.
+func requireOwnWorkspace(w http.ResponseWriter, r *http.Request) (string, bool) {
+	wsID, ok := requireWorkspaceID(w, r)
+	if !ok {
+		return "", false
+	}
+	if id := chi.URLParam(r, paramID); id != wsID {
+		writeError(w, http.StatusNotFound, codeWorkspaceNotFound, errWorkspaceNotFound)
+		return "", false
+	}
+	return wsID, true
+}
+
+func writeWorkspaceError(w http.ResponseWriter, err error, format string) {
+	switch {
+	case errors.Is(err, crm.ErrInvalidWorkspaceSlug):
+		writeValidationError(w, map[string]string{"slug": err.Error()})
+	case errors.Is(err, crm.ErrWorkspaceSlugTaken):
+		writeError(w, http.StatusConflict, codeWorkspaceSlugTaken, err.Error())
+	case errors.Is(err, sql.ErrNoRows):
+		writeError(w, http.StatusNotFound, codeWorkspaceNotFound, errWorkspaceNotFound)
+	default:
+		writeError(w, http.StatusInternalServerError, codeInternal, fmt.Sprintf(format, err))
+	}
+}
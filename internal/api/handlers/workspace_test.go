@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestWorkspaceHandler_GetListUpdate(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	otherWS, _ := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewWorkspaceService(db)
+	taken, err := svc.Create(context.Background(), crm.CreateWorkspaceInput{Name: "Globex", Slug: "globex"})
+	if err != nil {
+		t.Fatalf("create workspace: %v", err)
+	}
+	h := NewWorkspaceHandler(svc)
+
+	request := func(method, body, id string) *http.Request {
+		req := httptest.NewRequest(method, "/", bytes.NewReader([]byte(body)))
+		ctx := context.WithValue(contextWithWorkspaceID(req.Context(), wsID), ctxkeys.UserID, ownerID)
+		if id != "" {
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", id)
+			ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+		}
+		return req.WithContext(ctx)
+	}
+
+	rr := httptest.NewRecorder()
+	h.GetWorkspace(rr, request(http.MethodGet, "", wsID))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("get own status = %d; body=%s", rr.Code, rr.Body.String())
+	}
+	for _, id := range []string{otherWS, taken.ID} {
+		rr = httptest.NewRecorder()
+		h.GetWorkspace(rr, request(http.MethodGet, "", id))
+		assertErrorCode(t, rr, http.StatusNotFound, codeWorkspaceNotFound)
+	}
+
+	rr = httptest.NewRecorder()
+	h.UpdateWorkspace(rr, request(http.MethodPut, `{"slug":"globex"}`, wsID))
+	assertErrorCode(t, rr, http.StatusConflict, codeWorkspaceSlugTaken)
+
+	rr = httptest.NewRecorder()
+	h.UpdateWorkspace(rr, request(http.MethodPut, `{"slug":"Bad Slug"}`, wsID))
+	assertErrorCode(t, rr, http.StatusBadRequest, codeValidationFailed)
+
+	rr = httptest.NewRecorder()
+	h.UpdateWorkspace(rr, request(http.MethodPut, `{"name":"Acme","slug":"acme"}`, wsID))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update status = %d; body=%s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	h.ListWorkspaces(rr, request(http.MethodGet, "", ""))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("list status = %d; body=%s", rr.Code, rr.Body.String())
+	}
+	var list struct {
+		Data []crm.Workspace `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil {
+		t.Fatalf("decode list: %v", err)
+	}
+	if len(list.Data) != 1 || list.Data[0].ID != wsID || list.Data[0].Slug != "acme" {
+		t.Fatalf("list = %+v; want only %s, renamed", list.Data, wsID)
+	}
+}
+
+func assertErrorCode(t *testing.T, rr *httptest.ResponseRecorder, wantStatus int, wantCode string) {
+	t.Helper()
+	if rr.Code != wantStatus {
+		t.Fatalf("status = %d; want %d body=%s", rr.Code, wantStatus, rr.Body.String())
+	}
+	var resp errorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode error body: %v body=%s", err, rr.Body.String())
+	}
+	if resp.Error.Code != wantCode {
+		t.Fatalf("error code = %s; want %s", resp.Error.Code, wantCode)
+	}
+}
@@ -3,9 +3,11 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	pkgauth "github.com/matiasleandrokruk/fenix/pkg/auth"
@@ -18,9 +20,45 @@ import (
 //  1. Read "Authorization: Bearer <token>" header
 //  2. Reject if missing or not Bearer scheme → 401
 //  3. Parse + validate JWT → 401 on invalid/expired
-//  4. Inject ctxkeys.UserID and ctxkeys.WorkspaceID into context
+//     (AuthMiddlewareWithMembership also rejects a workspace claim the user
+//     does not belong to → 403)
+//  4. Inject ctxkeys.UserID, ctxkeys.WorkspaceID, ctxkeys.Role and token id/expiry into context
 //  5. Call next handler
 func AuthMiddleware(next http.Handler) http.Handler {
+	return authenticate(nil, nil, next)
+}
+
+// TokenRevocationChecker reports whether an access token jti was revoked.
+// domain/auth.AuthService satisfies this interface.
+type TokenRevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
+
+// AuthMiddlewareWithRevocation is AuthMiddleware plus a revocation-list check:
+// tokens whose jti was revoked (e.g. on logout) are rejected with 401.
+// Task 1.6.16: token revocation.
+func AuthMiddlewareWithRevocation(checker TokenRevocationChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return authenticate(checker, nil, next)
+	}
+}
+
+// WorkspaceMembershipChecker reports whether a user belongs to a workspace.
+// domain/auth.AuthService satisfies this interface.
+type WorkspaceMembershipChecker interface {
+	IsWorkspaceMember(ctx context.Context, userID, workspaceID string) (bool, error)
+}
+
+// AuthMiddlewareWithMembership is AuthMiddlewareWithRevocation plus a check
+// that the token's workspaceId claim is the user's own workspace: a token
+// naming another workspace is rejected with 403 before any handler runs.
+func AuthMiddlewareWithMembership(checker TokenRevocationChecker, members WorkspaceMembershipChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return authenticate(checker, members, next)
+	}
+}
+
+func authenticate(checker TokenRevocationChecker, members WorkspaceMembershipChecker, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		tokenString := extractBearerToken(r)
 		if tokenString == "" {
@@ -34,10 +72,49 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		if checker != nil && claims.ID != "" {
+			revoked, revErr := checker.IsTokenRevoked(r.Context(), claims.ID)
+			if revErr != nil {
+				// Fail closed: an unreadable revocation list must not let tokens through.
+				writeUnauthorized(w, "unable to verify token")
+				return
+			}
+			if revoked {
+				writeUnauthorized(w, "token has been revoked")
+				return
+			}
+		}
+
+		if members != nil {
+			member, memberErr := members.IsWorkspaceMember(r.Context(), claims.UserID, claims.WorkspaceID)
+			if memberErr != nil {
+				// Fail closed, as for the revocation list.
+				writeUnauthorized(w, "unable to verify workspace membership")
+				return
+			}
+			if !member {
+				writeForbidden(w, "user is not a member of this workspace")
+				return
+			}
+		}
+
 		// Inject claims into context using typed keys (prevents collision — Task 1.3 TD-1 lesson)
 		ctx := r.Context()
 		ctx = ctxkeys.WithValue(ctx, ctxkeys.UserID, claims.UserID)
 		ctx = ctxkeys.WithValue(ctx, ctxkeys.WorkspaceID, claims.WorkspaceID)
+		if claims.Role != "" {
+			ctx = ctxkeys.WithValue(ctx, ctxkeys.Role, claims.Role)
+		}
+		if claims.ID != "" {
+			ctx = ctxkeys.WithValue(ctx, ctxkeys.TokenID, claims.ID)
+		}
+		if claims.ExpiresAt != nil {
+			ctx = ctxkeys.WithValue(ctx, ctxkeys.TokenExpiresAt, claims.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+		if principal := ctxkeys.PrincipalFrom(ctx); principal != nil {
+			principal.WorkspaceID = claims.WorkspaceID
+			principal.UserID = claims.UserID
+		}
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -66,7 +143,17 @@ func extractBearerToken(r *http.Request) string {
 // writeUnauthorized writes a 401 JSON response.
 // Uses consistent format with writeError in handlers package.
 func writeUnauthorized(w http.ResponseWriter, message string) {
+	writeAuthError(w, http.StatusUnauthorized, "UNAUTHORIZED", message)
+}
+
+// writeForbidden writes a 403 JSON response for an authenticated caller
+// outside the workspace its token names.
+func writeForbidden(w http.ResponseWriter, message string) {
+	writeAuthError(w, http.StatusForbidden, "FORBIDDEN", message)
+}
+
+func writeAuthError(w http.ResponseWriter, status int, code, message string) {
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
-	json.NewEncoder(w).Encode(map[string]string{"error": message}) //nolint:errcheck
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]map[string]string{"error": {"code": code, "message": message}}) //nolint:errcheck
 }
@@ -5,6 +5,7 @@ package middleware_test
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -334,3 +335,155 @@ func buildExpiredToken(t *testing.T, userID, workspaceID string) string {
 
 	return signed
 }
+
+// ===== TESTS: REVOCATION =====
+
+type fakeRevocationChecker struct {
+	revoked map[string]bool
+}
+
+func (f fakeRevocationChecker) IsTokenRevoked(_ context.Context, jti string) (bool, error) {
+	return f.revoked[jti], nil
+}
+
+// TestAuthMiddlewareWithRevocation_RejectsRevokedToken verifies a revoked jti returns 401.
+func TestAuthMiddlewareWithRevocation_RejectsRevokedToken(t *testing.T) {
+	t.Parallel()
+
+	token, err := pkgauth.GenerateJWT("user-1", "ws-1")
+	if err != nil {
+		t.Fatalf("GenerateJWT error = %v", err)
+	}
+	claims, err := pkgauth.ParseJWT(token)
+	if err != nil {
+		t.Fatalf("ParseJWT error = %v", err)
+	}
+
+	called := false
+	checker := fakeRevocationChecker{revoked: map[string]bool{claims.ID: true}}
+	handler := middleware.AuthMiddlewareWithRevocation(checker)(nextHandler(&called, nil))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, makeRequest(token))
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusUnauthorized)
+	}
+	if called {
+		t.Error("next handler should NOT be called for a revoked token")
+	}
+}
+
+// TestAuthMiddlewareWithRevocation_AllowsActiveTokenAndInjectsTokenID verifies pass-through and jti injection.
+func TestAuthMiddlewareWithRevocation_AllowsActiveTokenAndInjectsTokenID(t *testing.T) {
+	t.Parallel()
+
+	token, err := pkgauth.GenerateJWT("user-1", "ws-1")
+	if err != nil {
+		t.Fatalf("GenerateJWT error = %v", err)
+	}
+
+	called := false
+	var ctx context.Context
+	handler := middleware.AuthMiddlewareWithRevocation(fakeRevocationChecker{})(nextHandler(&called, &ctx))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, makeRequest(token))
+
+	if rr.Code != http.StatusOK || !called {
+		t.Fatalf("status = %d, called = %v; want 200, true", rr.Code, called)
+	}
+	if jti, _ := ctx.Value(ctxkeys.TokenID).(string); jti == "" {
+		t.Error("expected TokenID in context")
+	}
+	if exp, _ := ctx.Value(ctxkeys.TokenExpiresAt).(string); exp == "" {
+		t.Error("expected TokenExpiresAt in context")
+	}
+}
+
+// ===== TESTS: WORKSPACE MEMBERSHIP =====
+
+type fakeMembershipChecker struct {
+	members map[string]string // userID → workspaceID
+	err     error
+}
+
+func (f fakeMembershipChecker) IsWorkspaceMember(_ context.Context, userID, workspaceID string) (bool, error) {
+	return f.members[userID] == workspaceID, f.err
+}
+
+// TestAuthMiddlewareWithMembership_ValidMembershipInjectsClaims verifies a
+// member passes through with UserID and WorkspaceID in context.
+func TestAuthMiddlewareWithMembership_ValidMembershipInjectsClaims(t *testing.T) {
+	t.Parallel()
+
+	token, err := pkgauth.GenerateJWT("user-1", "ws-1")
+	if err != nil {
+		t.Fatalf("GenerateJWT error = %v", err)
+	}
+
+	called := false
+	var ctx context.Context
+	members := fakeMembershipChecker{members: map[string]string{"user-1": "ws-1"}}
+	handler := middleware.AuthMiddlewareWithMembership(fakeRevocationChecker{}, members)(nextHandler(&called, &ctx))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, makeRequest(token))
+
+	if rr.Code != http.StatusOK || !called {
+		t.Fatalf("status = %d, called = %v; want 200, true", rr.Code, called)
+	}
+	if got, _ := ctx.Value(ctxkeys.UserID).(string); got != "user-1" {
+		t.Errorf("UserID = %q; want user-1", got)
+	}
+	if got, _ := ctx.Value(ctxkeys.WorkspaceID).(string); got != "ws-1" {
+		t.Errorf("WorkspaceID = %q; want ws-1", got)
+	}
+}
+
+// TestAuthMiddlewareWithMembership_RejectsForgedWorkspaceClaim verifies a
+// token naming another user's workspace returns 403.
+func TestAuthMiddlewareWithMembership_RejectsForgedWorkspaceClaim(t *testing.T) {
+	t.Parallel()
+
+	token, err := pkgauth.GenerateJWT("user-1", "ws-2")
+	if err != nil {
+		t.Fatalf("GenerateJWT error = %v", err)
+	}
+
+	called := false
+	members := fakeMembershipChecker{members: map[string]string{"user-1": "ws-1", "user-2": "ws-2"}}
+	handler := middleware.AuthMiddlewareWithMembership(fakeRevocationChecker{}, members)(nextHandler(&called, nil))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, makeRequest(token))
+
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("status = %d; want %d", rr.Code, http.StatusForbidden)
+	}
+	if called {
+		t.Error("next handler should NOT be called for a forged workspace claim")
+	}
+}
+
+// TestAuthMiddlewareWithMembership_FailsClosedOnCheckError verifies a lookup
+// failure rejects the request.
+func TestAuthMiddlewareWithMembership_FailsClosedOnCheckError(t *testing.T) {
+	t.Parallel()
+
+	token, err := pkgauth.GenerateJWT("user-1", "ws-1")
+	if err != nil {
+		t.Fatalf("GenerateJWT error = %v", err)
+	}
+
+	called := false
+	members := fakeMembershipChecker{members: map[string]string{"user-1": "ws-1"}, err: errors.New("db down")}
+	handler := middleware.AuthMiddlewareWithMembership(fakeRevocationChecker{}, members)(nextHandler(&called, nil))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, makeRequest(token))
+
+	if rr.Code != http.StatusUnauthorized || called {
+		t.Errorf("status = %d, called = %v; want 401, false", rr.Code, called)
+	}
+}
@@ -28,7 +28,8 @@ func CORSMiddleware(allowedOrigins ...string) func(http.Handler) http.Handler {
 			if _, ok := allowed[origin]; ok {
 				w.Header().Set(headerACAllowOrigin, origin)
 				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+				w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, If-None-Match")
+				w.Header().Set("Access-Control-Expose-Headers", "ETag, Deprecation, Link")
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
 				w.Header().Set("Vary", headerOrigin)
 			}
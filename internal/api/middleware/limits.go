@@ -0,0 +1,152 @@
+// limits.go: request body size and per-request time limits.
+// Handlers decode bodies directly with json.NewDecoder, so both middlewares
+// replace the error response a handler writes after hitting a limit with the
+// matching status (413 / 503) instead of requiring every handler to map it.
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	errBodyTooLarge   = `{"error":{"code":"PAYLOAD_TOO_LARGE","message":"request body too large"}}`
+	errRequestTimeout = `{"error":{"code":"REQUEST_TIMEOUT","message":"request timed out"}}`
+)
+
+// MaxBodyBytes limits request bodies to limit bytes. Requests declaring a
+// larger Content-Length get 413 without reaching the handler; bodies that
+// turn out larger while being read make the handler's error response a 413.
+// limit <= 0 disables the check.
+func MaxBodyBytes(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if limit <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.ContentLength > limit {
+				writeLimitError(w, http.StatusRequestEntityTooLarge, errBodyTooLarge)
+				return
+			}
+			body := &limitedBody{ReadCloser: http.MaxBytesReader(w, r.Body, limit)}
+			r.Body = body
+			next.ServeHTTP(&limitWriter{
+				ResponseWriter: w,
+				replace: func(code int) (int, string, bool) {
+					return http.StatusRequestEntityTooLarge, errBodyTooLarge, code >= 400 && body.exceeded.Load()
+				},
+			}, r)
+		})
+	}
+}
+
+// RequestTimeout gives each request a context deadline of timeout. A handler
+// still running at the deadline sees its context cancelled; a 5xx it writes
+// afterwards, or no response at all, becomes 503. Slow request bodies are cut
+// off earlier by the server's ReadTimeout. timeout <= 0 disables the deadline.
+// Requests to streamingPaths (SSE endpoints that stay open for as long as
+// the stream runs) get no deadline; they end when the client disconnects.
+func RequestTimeout(timeout time.Duration, streamingPaths ...string) func(http.Handler) http.Handler {
+	exempt := make(map[string]struct{}, len(streamingPaths))
+	for _, path := range streamingPaths {
+		exempt[path] = struct{}{}
+	}
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := exempt[r.URL.Path]; ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			timedOut := func() bool { return errors.Is(ctx.Err(), context.DeadlineExceeded) }
+			lw := &limitWriter{
+				ResponseWriter: w,
+				replace: func(code int) (int, string, bool) {
+					return http.StatusServiceUnavailable, errRequestTimeout, code >= 500 && timedOut()
+				},
+			}
+			next.ServeHTTP(lw, r.WithContext(ctx))
+
+			if !lw.wroteHeader && timedOut() {
+				lw.WriteHeader(http.StatusServiceUnavailable)
+			}
+		})
+	}
+}
+
+// limitedBody records whether the wrapped http.MaxBytesReader hit its limit.
+type limitedBody struct {
+	io.ReadCloser
+	exceeded atomic.Bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.exceeded.Store(true)
+	}
+	return n, err
+}
+
+// limitWriter swaps the handler's response for a limit error when replace
+// says so at WriteHeader time, discarding the handler's body.
+type limitWriter struct {
+	http.ResponseWriter
+	replace     func(code int) (status int, body string, ok bool)
+	wroteHeader bool
+	discard     bool
+}
+
+func (w *limitWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if status, body, ok := w.replace(code); ok {
+		w.discard = true
+		writeLimitError(w.ResponseWriter, status, body)
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *limitWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *limitWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush implements http.Flusher so SSE streams work through the limits.
+func (w *limitWriter) Flush() {
+	if w.discard {
+		return
+	}
+	if fl, ok := w.ResponseWriter.(http.Flusher); ok {
+		fl.Flush()
+	}
+}
+
+func writeLimitError(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, body)
+}
@@ -0,0 +1,148 @@
+// limits_test.go: unit tests for MaxBodyBytes and RequestTimeout.
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// decodeHandler mimics the repo's handlers: decode JSON, 400 on any error.
+var decodeHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"code":"INVALID_BODY","message":"invalid request body"}}`))
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+})
+
+func TestMaxBodyBytes_UnderLimit_Passes(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"acme"}`))
+	rr := httptest.NewRecorder()
+	MaxBodyBytes(64)(decodeHandler).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d; want %d", rr.Code, http.StatusCreated)
+	}
+}
+
+func TestMaxBodyBytes_DeclaredLengthTooLarge_Returns413(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true })
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"`+strings.Repeat("a", 100)+`"}`))
+	rr := httptest.NewRecorder()
+	MaxBodyBytes(64)(next).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d; want %d", rr.Code, http.StatusRequestEntityTooLarge)
+	}
+	if called {
+		t.Fatal("handler called for a body declared over the limit")
+	}
+}
+
+func TestMaxBodyBytes_StreamedBodyTooLarge_ReplacesHandlerError(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"`+strings.Repeat("a", 100)+`"}`))
+	req.ContentLength = -1 // chunked: size unknown up front
+	rr := httptest.NewRecorder()
+	MaxBodyBytes(64)(decodeHandler).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d; want %d", rr.Code, http.StatusRequestEntityTooLarge)
+	}
+	if got := rr.Body.String(); got != errBodyTooLarge {
+		t.Fatalf("body = %q; want %q", got, errBodyTooLarge)
+	}
+}
+
+func TestMaxBodyBytes_InvalidJSONUnderLimit_KeepsHandlerError(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{not json`))
+	rr := httptest.NewRecorder()
+	MaxBodyBytes(64)(decodeHandler).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d; want %d", rr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestRequestTimeout_SlowHandler_Returns503(t *testing.T) {
+	t.Parallel()
+
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(`{"error":{"code":"INTERNAL_ERROR","message":"failed to list accounts"}}`))
+	})
+	rr := httptest.NewRecorder()
+	RequestTimeout(20*time.Millisecond)(slow).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d; want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+	if got := rr.Body.String(); got != errRequestTimeout {
+		t.Fatalf("body = %q; want %q", got, errRequestTimeout)
+	}
+}
+
+func TestRequestTimeout_SilentHandler_Returns503(t *testing.T) {
+	t.Parallel()
+
+	silent := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	rr := httptest.NewRecorder()
+	RequestTimeout(20*time.Millisecond)(silent).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d; want %d", rr.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestRequestTimeout_StreamingPath_HasNoDeadline(t *testing.T) {
+	t.Parallel()
+
+	stream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); ok {
+			t.Error("streaming request has a deadline; want none")
+		}
+		time.Sleep(40 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
+	rr := httptest.NewRecorder()
+	RequestTimeout(20*time.Millisecond, "/stream")(stream).ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/stream", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d; want %d", rr.Code, http.StatusOK)
+	}
+}
+
+func TestRequestTimeout_FastHandler_Unchanged(t *testing.T) {
+	t.Parallel()
+
+	fast := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Error("request context has no deadline")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	rr := httptest.NewRecorder()
+	RequestTimeout(time.Second)(fast).ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("status = %d; want %d", rr.Code, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import "net/http"
+
+const errLLMUnavailable = `{"error":{"code":"LLM_UNAVAILABLE","message":"llm provider unavailable"}}`
+
+// RequireHealthyLLM answers 503 while healthy reports false for the request,
+// so agent triggers fail fast instead of failing mid-run when the LLM
+// provider they would use is down. healthy gets the request so it can tell
+// which provider that is, e.g. from the caller's workspace.
+func RequireHealthyLLM(healthy func(*http.Request) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !healthy(r) {
+				writeLimitError(w, http.StatusServiceUnavailable, errLLMUnavailable)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
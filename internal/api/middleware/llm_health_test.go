@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireHealthyLLM(t *testing.T) {
+	t.Parallel()
+
+	healthy := true
+	handler := RequireHealthyLLM(func(*http.Request) bool { return healthy })(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	serve := func() int {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/api/v1/agents/kb/trigger", nil))
+		return rr.Code
+	}
+
+	if code := serve(); code != http.StatusAccepted {
+		t.Fatalf("healthy: status = %d; want %d", code, http.StatusAccepted)
+	}
+	healthy = false
+	if code := serve(); code != http.StatusServiceUnavailable {
+		t.Fatalf("unhealthy: status = %d; want %d", code, http.StatusServiceUnavailable)
+	}
+}
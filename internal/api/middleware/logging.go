@@ -0,0 +1,40 @@
+// logging.go: request-scoped structured logging.
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+)
+
+// RequestLogger stores logger, tagged with the request ID, in each request's
+// context for handlers to retrieve with logging.FromContext, and logs one
+// access record per request once it completes. It must run after chi's
+// RequestID middleware.
+func RequestLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqLogger := logger.With(slog.String(logging.KeyRequestID, chimiddleware.GetReqID(r.Context())))
+			ww := chimiddleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+
+			next.ServeHTTP(ww, r.WithContext(logging.WithLogger(r.Context(), reqLogger)))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			reqLogger.Info("http request",
+				slog.String(logging.KeyComponent, "http"),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Int("bytes", ww.BytesWritten()),
+				slog.Duration("duration", time.Since(start)))
+		})
+	}
+}
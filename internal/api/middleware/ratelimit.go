@@ -3,7 +3,6 @@
 package middleware
 
 import (
-	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -61,18 +60,14 @@ func (l *ipLimiter) allow(ip string) bool {
 	return true
 }
 
-// remoteIP extracts the client IP, respecting X-Real-IP set by RealIP middleware.
-func remoteIP(r *http.Request) string {
-	if ip := r.Header.Get(headerXRealIP); ip != "" {
-		return ip
-	}
-	host, _, err := net.SplitHostPort(r.RemoteAddr)
-	if err != nil {
-		return r.RemoteAddr
-	}
-	return host
+// RemoteIP returns the client IP: the socket peer, or the address a trusted
+// proxy forwarded once RealIP has run. Forwarding headers are never read here.
+func RemoteIP(r *http.Request) string {
+	return remoteHost(r.RemoteAddr)
 }
 
+const errRateLimited = `{"error":{"code":"RATE_LIMITED","message":"too many requests"}}`
+
 // RateLimitMiddleware returns a middleware that limits requests per IP.
 //   - limit: maximum number of requests allowed in the window
 //   - window: duration of each rate-limit window
@@ -82,9 +77,9 @@ func RateLimitMiddleware(limit int, window time.Duration) func(http.Handler) htt
 	limiter := newIPLimiter(limit, window)
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ip := remoteIP(r)
+			ip := RemoteIP(r)
 			if !limiter.allow(ip) {
-				http.Error(w, `{"error":"too many requests"}`, http.StatusTooManyRequests)
+				writeLimitError(w, http.StatusTooManyRequests, errRateLimited)
 				return
 			}
 			next.ServeHTTP(w, r)
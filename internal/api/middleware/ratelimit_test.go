@@ -53,6 +53,12 @@ func TestRateLimit_ExceedLimit_Returns429(t *testing.T) {
 	if rr.Code != http.StatusTooManyRequests {
 		t.Errorf("status = %d; want %d (should be rate-limited)", rr.Code, http.StatusTooManyRequests)
 	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q; want application/json", ct)
+	}
+	if body := rr.Body.String(); body != errRateLimited {
+		t.Errorf("body = %q; want %q", body, errRateLimited)
+	}
 }
 
 // TestRateLimit_DifferentIPs_IndependentCounters verifies that two different IPs
@@ -106,34 +112,25 @@ func TestRateLimit_WindowReset_AllowsAgain(t *testing.T) {
 	}
 }
 
-// TestRateLimit_XRealIP_UsedOverRemoteAddr verifies that X-Real-IP header is used
-// for bucket keying (RealIP middleware sets this).
-func TestRateLimit_XRealIP_UsedOverRemoteAddr(t *testing.T) {
+// TestRateLimit_XRealIPHeader_Ignored verifies that a client-sent X-Real-IP
+// header cannot move it into a fresh bucket; only RealIP with a trusted
+// proxy changes the address buckets are keyed on.
+func TestRateLimit_XRealIPHeader_Ignored(t *testing.T) {
 	t.Parallel()
 
-	limit := 1
-	handler := RateLimitMiddleware(limit, time.Minute)(rateLimitOKHandler)
-
-	realIP := "192.168.1.50"
-	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
-	req.RemoteAddr = "10.0.0.99:12345" // different from real IP
-	req.Header.Set(headerXRealIP, realIP)
-
-	// First request from realIP — should pass.
-	rr1 := httptest.NewRecorder()
-	handler.ServeHTTP(rr1, req)
-	if rr1.Code != http.StatusOK {
-		t.Errorf("first request: status = %d; want %d", rr1.Code, http.StatusOK)
-	}
-
-	// Second request with same X-Real-IP — should be limited.
-	req2 := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
-	req2.RemoteAddr = "10.0.0.100:12345" // different RemoteAddr
-	req2.Header.Set(headerXRealIP, realIP)
+	handler := RateLimitMiddleware(1, time.Minute)(rateLimitOKHandler)
 
-	rr2 := httptest.NewRecorder()
-	handler.ServeHTTP(rr2, req2)
-	if rr2.Code != http.StatusTooManyRequests {
-		t.Errorf("second request same X-Real-IP: status = %d; want %d", rr2.Code, http.StatusTooManyRequests)
+	for i, spoofed := range []string{"192.168.1.50", "192.168.1.51"} {
+		req := newIPRequest("10.0.0.99")
+		req.Header.Set(headerXRealIP, spoofed)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		want := http.StatusOK
+		if i > 0 {
+			want = http.StatusTooManyRequests
+		}
+		if rr.Code != want {
+			t.Errorf("request %d with X-Real-IP %s: status = %d; want %d", i+1, spoofed, rr.Code, want)
+		}
 	}
 }
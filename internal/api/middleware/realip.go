@@ -0,0 +1,90 @@
+// realip.go: client address resolution behind trusted reverse proxies.
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+const headerXForwardedFor = "X-Forwarded-For"
+
+// ParseTrustedProxies parses proxy addresses given as IPs or CIDR prefixes.
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(value); err == nil {
+			out = append(out, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", value)
+		}
+		out = append(out, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return out, nil
+}
+
+// RealIP replaces r.RemoteAddr with the client address forwarded by a trusted
+// proxy: the right-most X-Forwarded-For entry that is not itself a trusted
+// proxy, else X-Real-IP. Requests from any other peer keep their socket
+// address, so clients cannot choose the IP that rate limits and login
+// lockouts key on. No trusted proxies disables forwarding headers entirely.
+func RealIP(trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	trusted := func(addr netip.Addr) bool {
+		for _, prefix := range trustedProxies {
+			if prefix.Contains(addr.Unmap()) {
+				return true
+			}
+		}
+		return false
+	}
+	return func(next http.Handler) http.Handler {
+		if len(trustedProxies) == 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			peer, err := netip.ParseAddr(remoteHost(r.RemoteAddr))
+			if err == nil && trusted(peer) {
+				if client, ok := forwardedClient(r, trusted); ok {
+					r.RemoteAddr = client.String()
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// forwardedClient reads the client address the trusted peer forwarded.
+func forwardedClient(r *http.Request, trusted func(netip.Addr) bool) (netip.Addr, bool) {
+	hops := strings.Split(strings.Join(r.Header.Values(headerXForwardedFor), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			break
+		}
+		if !trusted(addr) {
+			return addr.Unmap(), true
+		}
+	}
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get(headerXRealIP))); err == nil {
+		return addr.Unmap(), true
+	}
+	return netip.Addr{}, false
+}
+
+// remoteHost strips the port from a RemoteAddr, if any.
+func remoteHost(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
@@ -0,0 +1,57 @@
+// realip_test.go: unit tests for RealIP and ParseTrustedProxies.
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRealIP_OnlyTrustsForwardingHeadersFromTrustedProxies(t *testing.T) {
+	t.Parallel()
+
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.7"})
+	if err != nil {
+		t.Fatalf("ParseTrustedProxies: %v", err)
+	}
+	var got string
+	handler := RealIP(trusted)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = RemoteIP(r)
+	}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		want       string
+	}{
+		{"untrusted peer keeps socket address", "203.0.113.9:4000", "198.51.100.1", "198.51.100.2", "203.0.113.9"},
+		{"trusted peer forwards client", "10.1.2.3:4000", "198.51.100.1", "", "198.51.100.1"},
+		{"spoofed left-most hop is skipped", "10.1.2.3:4000", "1.2.3.4, 198.51.100.1, 192.0.2.7", "", "198.51.100.1"},
+		{"trusted peer falls back to X-Real-IP", "192.0.2.7:4000", "", "198.51.100.2", "198.51.100.2"},
+		{"trusted peer without headers", "10.1.2.3:4000", "", "", "10.1.2.3"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.remoteAddr
+		if tt.forwarded != "" {
+			req.Header.Set(headerXForwardedFor, tt.forwarded)
+		}
+		if tt.realIP != "" {
+			req.Header.Set(headerXRealIP, tt.realIP)
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if got != tt.want {
+			t.Errorf("%s: RemoteIP = %q; want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseTrustedProxies_RejectsInvalidEntries(t *testing.T) {
+	t.Parallel()
+
+	if _, err := ParseTrustedProxies([]string{"10.0.0.0/8", "proxy.internal"}); err == nil {
+		t.Fatal("expected an error for a hostname entry")
+	}
+}
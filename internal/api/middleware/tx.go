@@ -0,0 +1,100 @@
+// tx.go: request-scoped database transaction.
+// Transaction lets a handler compose several service calls atomically: the
+// services pick the transaction up from the request context (see
+// sqlite.ContextWithTx) instead of each committing on its own.
+package middleware
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+)
+
+const txComponent = "http.transaction"
+
+// Transaction runs the handler inside a transaction stored in the request
+// context. The transaction commits when the handler responds 2xx and rolls
+// back on any other status or a panic, which is re-raised for the recoverer.
+// The response is buffered until then, so a failed commit still turns into
+// a 500 instead of a success the client cannot trust. Once the transaction
+// has ended it runs the callbacks the handler queued with sqlite.AfterTx,
+// and those of sqlite.AfterCommit if it committed.
+//
+// It is opt-in per route: streaming endpoints must not use it, and every
+// write a wrapped handler makes has to go through context-aware services.
+func Transaction(db *sql.DB) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := logging.FromContext(r.Context())
+			tx, err := db.BeginTx(r.Context(), nil)
+			if err != nil {
+				logger.Error("begin request transaction failed", slog.String(logging.KeyComponent, txComponent), logging.Err(err))
+				writeTxError(w, "failed to begin transaction")
+				return
+			}
+			ctx := sqlite.ContextWithTx(r.Context(), tx)
+			committed := false
+			defer func() {
+				if !committed {
+					_ = tx.Rollback()
+				}
+				sqlite.FinishTx(ctx, committed)
+			}()
+
+			buf := &bufferedWriter{header: http.Header{}, status: http.StatusOK}
+			next.ServeHTTP(buf, r.WithContext(ctx))
+
+			if buf.status >= 200 && buf.status < 300 {
+				if err = tx.Commit(); err != nil {
+					logger.Error("commit request transaction failed", slog.String(logging.KeyComponent, txComponent), logging.Err(err))
+					writeTxError(w, "failed to commit transaction")
+					return
+				}
+				committed = true
+			}
+			buf.flushTo(w)
+		})
+	}
+}
+
+// writeTxError writes a 500 in the API error format.
+func writeTxError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	_ = json.NewEncoder(w).Encode(map[string]map[string]string{"error": {"code": "INTERNAL_ERROR", "message": message}})
+}
+
+// bufferedWriter holds the response until the transaction outcome is known.
+type bufferedWriter struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *bufferedWriter) Header() http.Header { return w.header }
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status, w.wroteHeader = code, true
+}
+
+func (w *bufferedWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(p)
+}
+
+func (w *bufferedWriter) flushTo(dst http.ResponseWriter) {
+	for k, v := range w.header {
+		dst.Header()[k] = v
+	}
+	dst.WriteHeader(w.status)
+	_, _ = dst.Write(w.body.Bytes())
+}
@@ -0,0 +1,202 @@
+// tx_test.go: unit tests for the Transaction middleware.
+package middleware
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+)
+
+func newTxTestDB(t *testing.T) (db *sql.DB, workspaceID, ownerID string) {
+	t.Helper()
+	db, err := sqlite.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err = sqlite.MigrateUp(db); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	workspaceID, ownerID = "ws-tx", "user-tx"
+	if _, err = db.Exec(`INSERT INTO workspace (id, name, slug, created_at, updated_at) VALUES (?, 'Tx', 'tx', datetime('now'), datetime('now'))`, workspaceID); err != nil {
+		t.Fatalf("insert workspace: %v", err)
+	}
+	if _, err = db.Exec(`INSERT INTO user_account (id, workspace_id, email, display_name, status, created_at, updated_at)
+		VALUES (?, ?, 'tx@example.com', 'Tx', 'active', datetime('now'), datetime('now'))`, ownerID, workspaceID); err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	return db, workspaceID, ownerID
+}
+
+func countAccounts(t *testing.T, db *sql.DB, workspaceID string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM account WHERE workspace_id = ?`, workspaceID).Scan(&n); err != nil {
+		t.Fatalf("count accounts: %v", err)
+	}
+	return n
+}
+
+// createTwoAccounts creates an account, then a second one through a service
+// that opens its own (nested) transaction, and finally responds status.
+func createTwoAccounts(db *sql.DB, workspaceID, ownerID string, status int) http.Handler {
+	svc := crm.NewAccountService(db)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if _, err := svc.Create(ctx, crm.CreateAccountInput{WorkspaceID: workspaceID, Name: "Acme", OwnerID: ownerID}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if _, err := svc.ImportCSV(ctx, workspaceID, ownerID, strings.NewReader("name\nGlobex\n")); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if status == 0 {
+			panic("boom")
+		}
+		w.WriteHeader(status)
+	})
+}
+
+func TestTransaction_CommitsOn2xx(t *testing.T) {
+	t.Parallel()
+	db, wsID, ownerID := newTxTestDB(t)
+
+	rr := httptest.NewRecorder()
+	Transaction(db)(createTwoAccounts(db, wsID, ownerID, http.StatusCreated)).
+		ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d; body=%s", rr.Code, rr.Body.String())
+	}
+	if n := countAccounts(t, db, wsID); n != 2 {
+		t.Fatalf("accounts = %d; want 2", n)
+	}
+}
+
+func TestTransaction_RollsBackOnHandlerError(t *testing.T) {
+	t.Parallel()
+	db, wsID, ownerID := newTxTestDB(t)
+
+	rr := httptest.NewRecorder()
+	Transaction(db)(createTwoAccounts(db, wsID, ownerID, http.StatusUnprocessableEntity)).
+		ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d; want 422", rr.Code)
+	}
+	if n := countAccounts(t, db, wsID); n != 0 {
+		t.Fatalf("accounts = %d; want 0 after rollback", n)
+	}
+}
+
+func TestTransaction_RollsBackOnPanic(t *testing.T) {
+	t.Parallel()
+	db, wsID, ownerID := newTxTestDB(t)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic was not re-raised")
+			}
+		}()
+		Transaction(db)(createTwoAccounts(db, wsID, ownerID, 0)).
+			ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+	}()
+
+	if n := countAccounts(t, db, wsID); n != 0 {
+		t.Fatalf("accounts = %d; want 0 after rollback", n)
+	}
+}
+
+// createPublishedAccount creates an account through a service publishing on
+// bus, which also writes its account.created audit event, and responds status.
+func createPublishedAccount(db *sql.DB, bus eventbus.EventBus, workspaceID, ownerID string, status int) http.Handler {
+	accounts := crm.NewAccountServiceWithBus(db, bus)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := accounts.Create(r.Context(), crm.CreateAccountInput{WorkspaceID: workspaceID, Name: "Acme", OwnerID: ownerID}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(status)
+	})
+}
+
+func countAuditEvents(t *testing.T, db *sql.DB, workspaceID string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM audit_event WHERE workspace_id = ?`, workspaceID).Scan(&n); err != nil {
+		t.Fatalf("count audit events: %v", err)
+	}
+	return n
+}
+
+func TestTransaction_PublishesAfterCommit(t *testing.T) {
+	t.Parallel()
+	db, wsID, ownerID := newTxTestDB(t)
+	bus := eventbus.New()
+	created := bus.Subscribe(knowledge.TopicRecordCreated)
+
+	rr := httptest.NewRecorder()
+	Transaction(db)(createPublishedAccount(db, bus, wsID, ownerID, http.StatusCreated)).
+		ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d; body=%s", rr.Code, rr.Body.String())
+	}
+	select {
+	case <-created:
+	case <-time.After(time.Second):
+		t.Fatal("record.created was not published after commit")
+	}
+	if n := countAuditEvents(t, db, wsID); n != 1 {
+		t.Fatalf("audit events = %d; want 1", n)
+	}
+}
+
+func TestTransaction_RollbackDropsPublishesAndKeepsAudit(t *testing.T) {
+	t.Parallel()
+	db, wsID, ownerID := newTxTestDB(t)
+	bus := eventbus.New()
+	created := bus.Subscribe(knowledge.TopicRecordCreated)
+
+	rr := httptest.NewRecorder()
+	Transaction(db)(createPublishedAccount(db, bus, wsID, ownerID, http.StatusUnprocessableEntity)).
+		ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d; want 422", rr.Code)
+	}
+	if n := countAccounts(t, db, wsID); n != 0 {
+		t.Fatalf("accounts = %d; want 0 after rollback", n)
+	}
+	select {
+	case evt := <-created:
+		t.Fatalf("published %v for a rolled-back account", evt.Payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if n := countAuditEvents(t, db, wsID); n != 1 {
+		t.Fatalf("audit events = %d; want 1 after rollback", n)
+	}
+}
+
+func TestContextDB_UsesPoolWithoutTx(t *testing.T) {
+	t.Parallel()
+	db, wsID, ownerID := newTxTestDB(t)
+
+	if _, err := crm.NewAccountService(db).Create(context.Background(), crm.CreateAccountInput{WorkspaceID: wsID, Name: "Acme", OwnerID: ownerID}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if n := countAccounts(t, db, wsID); n != 1 {
+		t.Fatalf("accounts = %d; want 1", n)
+	}
+}
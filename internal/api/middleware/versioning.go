@@ -0,0 +1,45 @@
+// versioning.go: API version tagging and deprecation headers for versioned
+// route trees (/api/v1, /api/v2, ...).
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+)
+
+const (
+	headerDeprecation = "Deprecation"
+	headerLink        = "Link"
+)
+
+// APIVersion records the API major version in the request context so shared
+// handlers can pick the response shape of the version serving them.
+func APIVersion(version int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(ctxkeys.WithAPIVersion(r.Context(), version)))
+		})
+	}
+}
+
+// Deprecation marks every response as deprecated since the given time with
+// the RFC 9745 Deprecation header. When successor returns a non-empty URL
+// for the request, a Link header with rel="successor-version" points to it.
+// successor may be nil.
+func Deprecation(since time.Time, successor func(*http.Request) string) func(http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", since.Unix())
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(headerDeprecation, deprecation)
+			if successor != nil {
+				if link := successor(r); link != "" {
+					w.Header().Add(headerLink, fmt.Sprintf(`<%s>; rel="successor-version"`, link))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
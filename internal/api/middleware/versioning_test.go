@@ -0,0 +1,58 @@
+// versioning_test.go: unit tests for APIVersion and Deprecation.
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+)
+
+func TestAPIVersion_SetsContextVersion(t *testing.T) {
+	t.Parallel()
+
+	var got int
+	handler := APIVersion(2)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = ctxkeys.APIVersionFrom(r.Context())
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got != 2 {
+		t.Fatalf("version = %d; want 2", got)
+	}
+}
+
+func TestDeprecation_SetsHeaders(t *testing.T) {
+	t.Parallel()
+
+	since := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	successor := func(r *http.Request) string {
+		if r.URL.Path == "/leads" {
+			return "/api/v2/leads"
+		}
+		return ""
+	}
+	handler := Deprecation(since, successor)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/leads", nil))
+	if got := rr.Header().Get("Deprecation"); got != "@1790812800" {
+		t.Fatalf("Deprecation = %q; want @1790812800", got)
+	}
+	if got := rr.Header().Get("Link"); got != `</api/v2/leads>; rel="successor-version"` {
+		t.Fatalf("Link = %q", got)
+	}
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/cases", nil))
+	if rr.Header().Get("Deprecation") == "" {
+		t.Fatal("expected Deprecation header without a successor")
+	}
+	if got := rr.Header().Get("Link"); got != "" {
+		t.Fatalf("Link = %q; want none without a successor", got)
+	}
+}
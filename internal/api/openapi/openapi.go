@@ -0,0 +1,191 @@
+// Package openapi loads, validates and serves the OpenAPI 3 description of
+// the HTTP API. The document itself is hand-maintained in docs/openapi.yaml;
+// router tests use Spec to check that every registered route is documented.
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/matiasleandrokruk/fenix/docs"
+	"gopkg.in/yaml.v3"
+)
+
+// httpMethods are the OpenAPI path item keys that declare an operation.
+var httpMethods = []string{"get", "put", "post", "delete", "patch", "head", "options"}
+
+// pathParam matches a path template parameter such as {id} or {cwID}.
+var pathParam = regexp.MustCompile(`\{[^}/]+\}`)
+
+// Operation is one documented method + path template pair.
+type Operation struct {
+	Method string // upper case, e.g. "GET"
+	Path   string // template as written in the spec, e.g. "/api/v1/accounts/{id}"
+}
+
+// Spec is a parsed and validated OpenAPI 3 document.
+type Spec struct {
+	doc        map[string]any
+	json       []byte
+	operations []Operation
+	index      map[string]bool // operationKey -> documented
+}
+
+var loadEmbedded = sync.OnceValues(func() (*Spec, error) {
+	return Parse(docs.OpenAPI)
+})
+
+// Load returns the spec embedded from docs/openapi.yaml. It is parsed once
+// per process.
+func Load() (*Spec, error) {
+	return loadEmbedded()
+}
+
+// Parse decodes a YAML (or JSON) OpenAPI document and validates that it is
+// version 3.x, declares at least one path, gives every operation a response
+// and only uses local $refs that resolve.
+func Parse(data []byte) (*Spec, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("openapi: parse: %w", err)
+	}
+	if version, _ := doc["openapi"].(string); !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("openapi: unsupported version %q", version)
+	}
+	paths, _ := doc["paths"].(map[string]any)
+	if len(paths) == 0 {
+		return nil, errors.New("openapi: no paths")
+	}
+
+	s := &Spec{doc: doc, index: make(map[string]bool)}
+	for path, rawItem := range paths {
+		item, ok := rawItem.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("openapi: path %s: not an object", path)
+		}
+		for _, method := range httpMethods {
+			rawOp, ok := item[method]
+			if !ok {
+				continue
+			}
+			op, _ := rawOp.(map[string]any)
+			if responses, _ := op["responses"].(map[string]any); len(responses) == 0 {
+				return nil, fmt.Errorf("openapi: %s %s: no responses", strings.ToUpper(method), path)
+			}
+			s.operations = append(s.operations, Operation{Method: strings.ToUpper(method), Path: path})
+			s.index[operationKey(method, path)] = true
+		}
+	}
+	slices.SortFunc(s.operations, func(a, b Operation) int {
+		return strings.Compare(a.Path+" "+a.Method, b.Path+" "+b.Method)
+	})
+
+	if err := checkRefs(doc, doc); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: encode json: %w", err)
+	}
+	s.json = raw
+	return s, nil
+}
+
+// JSON returns the document encoded as JSON.
+func (s *Spec) JSON() []byte {
+	return s.json
+}
+
+// Operations returns every documented operation, sorted by path and method.
+func (s *Spec) Operations() []Operation {
+	return slices.Clone(s.operations)
+}
+
+// Documents reports whether the spec has an operation for method and route.
+// route may be a chi pattern: parameter names and a trailing slash are
+// ignored, so "/api/v1/accounts/{account_id}/" matches
+// "/api/v1/accounts/{id}".
+func (s *Spec) Documents(method, route string) bool {
+	return s.index[operationKey(method, route)]
+}
+
+// SchemaProperties returns the property names of components.schemas.name,
+// sorted, or nil if the schema does not exist.
+func (s *Spec) SchemaProperties(name string) []string {
+	components, _ := s.doc["components"].(map[string]any)
+	schemas, _ := components["schemas"].(map[string]any)
+	schema, _ := schemas[name].(map[string]any)
+	props, _ := schema["properties"].(map[string]any)
+	if props == nil {
+		return nil
+	}
+	names := make([]string, 0, len(props))
+	for prop := range props {
+		names = append(names, prop)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ServeHTTP writes the document as application/json.
+func (s *Spec) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(s.json)))
+	_, _ = w.Write(s.json)
+}
+
+func operationKey(method, route string) string {
+	route = pathParam.ReplaceAllString(route, "{}")
+	if len(route) > 1 {
+		route = strings.TrimSuffix(route, "/")
+	}
+	return strings.ToUpper(method) + " " + route
+}
+
+// checkRefs walks node and fails on the first local $ref that does not
+// resolve within doc. Remote refs are left to external tooling.
+func checkRefs(doc map[string]any, node any) error {
+	switch v := node.(type) {
+	case map[string]any:
+		if ref, ok := v["$ref"].(string); ok && strings.HasPrefix(ref, "#/") {
+			if !resolves(doc, ref) {
+				return fmt.Errorf("openapi: unresolved $ref %q", ref)
+			}
+		}
+		for _, child := range v {
+			if err := checkRefs(doc, child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if err := checkRefs(doc, child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolves follows a "#/a/b" JSON pointer through doc.
+func resolves(doc map[string]any, ref string) bool {
+	var node any = doc
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]any)
+		if !ok {
+			return false
+		}
+		if node, ok = m[token]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const minimalSpec = `
+openapi: 3.0.3
+info:
+  title: test
+  version: 1.0.0
+paths:
+  /api/v1/accounts/{id}:
+    get:
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Account'
+components:
+  schemas:
+    Account:
+      type: object
+      properties:
+        name:
+          type: string
+        id:
+          type: string
+`
+
+func TestLoad_EmbeddedSpecIsValid(t *testing.T) {
+	t.Parallel()
+
+	spec, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !spec.Documents(http.MethodGet, "/health") {
+		t.Fatal("embedded spec does not document GET /health")
+	}
+	if !json.Valid(spec.JSON()) {
+		t.Fatal("JSON() is not valid JSON")
+	}
+}
+
+func TestParse_RejectsInvalidDocuments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{"swagger 2", strings.Replace(minimalSpec, "openapi: 3.0.3", "swagger: '2.0'", 1), "unsupported version"},
+		{"no paths", "openapi: 3.0.3\npaths: {}\n", "no paths"},
+		{"unresolved ref", strings.Replace(minimalSpec, "schemas/Account'", "schemas/Missing'", 1), "unresolved $ref"},
+		{"no responses", "openapi: 3.0.3\npaths:\n  /x:\n    get:\n      summary: x\n", "no responses"},
+		{"not yaml", "openapi: [", "parse"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse([]byte(tt.spec)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Parse() error = %v; want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSpec_DocumentsMatchesChiPatterns(t *testing.T) {
+	t.Parallel()
+
+	spec, err := Parse([]byte(minimalSpec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for _, route := range []string{"/api/v1/accounts/{id}", "/api/v1/accounts/{account_id}/"} {
+		if !spec.Documents("get", route) {
+			t.Errorf("Documents(GET, %q) = false; want true", route)
+		}
+	}
+	if spec.Documents(http.MethodDelete, "/api/v1/accounts/{id}") {
+		t.Error("Documents(DELETE) = true; want false")
+	}
+	if ops := spec.Operations(); len(ops) != 1 || ops[0] != (Operation{Method: http.MethodGet, Path: "/api/v1/accounts/{id}"}) {
+		t.Errorf("Operations() = %+v", ops)
+	}
+	if props := spec.SchemaProperties("Account"); !slices.Equal(props, []string{"id", "name"}) {
+		t.Errorf("SchemaProperties(Account) = %v; want [id name]", props)
+	}
+	if props := spec.SchemaProperties("Missing"); props != nil {
+		t.Errorf("SchemaProperties(Missing) = %v; want nil", props)
+	}
+}
+
+func TestSpec_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	spec, err := Parse([]byte(minimalSpec))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	rr := httptest.NewRecorder()
+	spec.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if body["openapi"] != "3.0.3" {
+		t.Fatalf("openapi = %v; want 3.0.3", body["openapi"])
+	}
+}
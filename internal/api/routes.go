@@ -7,13 +7,17 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	"github.com/matiasleandrokruk/fenix/internal/api/handlers"
 	apmiddleware "github.com/matiasleandrokruk/fenix/internal/api/middleware"
+	"github.com/matiasleandrokruk/fenix/internal/api/openapi"
 	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
 	"github.com/matiasleandrokruk/fenix/internal/domain/agent/agents"
 	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
@@ -33,16 +37,42 @@ import (
 	"github.com/matiasleandrokruk/fenix/internal/infra/config"
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
 	"github.com/matiasleandrokruk/fenix/internal/infra/llm"
+	"github.com/matiasleandrokruk/fenix/pkg/secretbox"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // routeByID is the chi route pattern for resource-by-ID endpoints (used 27 times).
 const routeByID = "/{id}"
 
+const (
+	apiV1Prefix = "/api/v1"
+	apiV2Prefix = "/api/v2"
+)
+
+// streamingRoutes are the SSE endpoints exempt from the request deadline.
+var streamingRoutes = []string{apiV1Prefix + "/copilot/chat"}
+
+// apiV1DeprecatedSince is when /api/v2 was introduced and /api/v1 deprecated.
+var apiV1DeprecatedSince = time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)
+
 // RouterRuntime carries optional shared runtime dependencies for router-scoped services.
 type RouterRuntime struct {
 	Bus               eventbus.EventBus
 	BackgroundContext context.Context
 	StartBackground   func(func())
+	// LoginLockout overrides domainauth.DefaultLockoutPolicy when set.
+	LoginLockout *domainauth.LockoutPolicy
+	// MaxBodyBytes and RequestTimeout bound every request; 0 disables them.
+	MaxBodyBytes   int64
+	RequestTimeout time.Duration
+	// Logger is stored in every request's context; slog.Default() when nil.
+	Logger *slog.Logger
+	// TracerProvider traces agent runs; runs are not traced when nil.
+	TracerProvider trace.TracerProvider
+	// PanicsRecoveredOutside is set by callers that wrap the router in their
+	// own panic recovery (the server does, to audit panics). Otherwise the
+	// router recovers panics itself with chi's Recoverer.
+	PanicsRecoveredOutside bool
 }
 
 // NewRouter creates and configures a new chi router with all routes.
@@ -83,12 +113,26 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 	if err != nil {
 		return nil, fmt.Errorf("api: create embed provider: %w", err)
 	}
-
-	// Global middleware (runs on all routes)
+	embedProvider = llm.NewCachingProvider(embedProvider, llm.DefaultEmbedCacheSize)
+	trustedProxies, err := apmiddleware.ParseTrustedProxies(cfg.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("api: %w", err)
+	}
+	chatHealth := llm.NewHealthMonitor(chatProvider, cfg.LLMHealthInterval)
+	embedHealth := llm.NewHealthMonitor(embedProvider, cfg.LLMHealthInterval)
+	runtime.StartBackground(func() { chatHealth.Start(runtime.BackgroundContext) })
+	runtime.StartBackground(func() { embedHealth.Start(runtime.BackgroundContext) })
+
+	// Global middleware (runs on all routes). Behind the server, panics are
+	// recovered outside every middleware registered here.
+	if !runtime.PanicsRecoveredOutside {
+		r.Use(middleware.Recoverer)
+	}
 	r.Use(middleware.RequestID)
-	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
+	r.Use(apmiddleware.RealIP(trustedProxies))
+	r.Use(apmiddleware.RequestLogger(runtime.Logger))
+	r.Use(apmiddleware.MaxBodyBytes(runtime.MaxBodyBytes))
+	r.Use(apmiddleware.RequestTimeout(runtime.RequestTimeout, streamingRoutes...))
 
 	// C2/CLSF-67: CORS — strict origin allowlist for BFF and local dev origins.
 	r.Use(apmiddleware.CORSMiddleware(cfg.CORSAllowedOrigins...))
@@ -105,40 +149,85 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 
 	// Health check — unauthenticated, checks DB (Task 4.9 — NFR-030)
 	r.Get("/health", handlers.NewHealthHandler(db))
-	r.Get("/readyz", handlers.NewReadyzHandler(db, chatProvider, embedProvider))
+	r.Get("/readyz", handlers.NewReadyzHandler(db, chatHealth, embedHealth))
 
 	// Metrics — unauthenticated, Prometheus text format (Task 4.9 — NFR-030)
 	r.Get("/metrics", handlers.MetricsHandler)
 
+	// OpenAPI description — unauthenticated so client generators can fetch it.
+	spec, err := openapi.Load()
+	if err != nil {
+		return nil, fmt.Errorf("api: load openapi spec: %w", err)
+	}
+	r.Get(apiV1Prefix+"/openapi.json", spec.ServeHTTP) // GET /api/v1/openapi.json
+
 	// Auth endpoints — public, no JWT required (Task 1.6.13)
-	// C4: rate limiting — login: 5 req/min per IP; register: 3 req/hour per IP.
-	authHandler := handlers.NewAuthHandler(domainauth.NewAuthServiceWithAudit(db, auditService))
+	// C4: rate limiting — login: 5 req/min per IP; register: 3 req/hour per IP;
+	// refresh: 30 req/min per IP.
+	lockout := domainauth.DefaultLockoutPolicy()
+	if runtime.LoginLockout != nil {
+		lockout = *runtime.LoginLockout
+	}
+	// Registration is the only way to create a workspace, since it also
+	// creates the workspace's first admin.
+	workspaceService := crm.NewWorkspaceService(db)
+	authOpts := []domainauth.Option{
+		domainauth.WithLoginLockout(lockout),
+		domainauth.WithWorkspaceProvisioner(func(ctx context.Context, tx *sql.Tx, name string) (string, error) {
+			return workspaceService.CreateTx(ctx, tx, crm.CreateWorkspaceInput{Name: name})
+		}),
+	}
+	if cfg.PasswordBreachCheck {
+		authOpts = append(authOpts, domainauth.WithBreachChecker(domainauth.NewHIBPChecker()))
+	}
+	authService := domainauth.NewAuthServiceWithAudit(db, auditService, authOpts...)
+	authHandler := handlers.NewAuthHandler(authService)
+	// Task 1.6.16: protected routes reject tokens revoked via /auth/logout
+	// and tokens whose workspace claim is not the user's own workspace.
+	requireAuth := apmiddleware.AuthMiddlewareWithMembership(authService, authService)
 	loginLimiter := apmiddleware.RateLimitMiddleware(5, time.Minute)
 	registerLimiter := apmiddleware.RateLimitMiddleware(3, time.Hour)
+	refreshLimiter := apmiddleware.RateLimitMiddleware(30, time.Minute)
 	r.Route("/auth", func(r chi.Router) {
 		r.With(registerLimiter).Post("/register", authHandler.Register) // POST /auth/register
 		r.With(loginLimiter).Post("/login", authHandler.Login)          // POST /auth/login
+		r.With(refreshLimiter).Post("/refresh", authHandler.Refresh)    // POST /auth/refresh
+		r.With(requireAuth).Post("/logout", authHandler.Logout)         // POST /auth/logout
 	})
 
 	// ===== PROTECTED ROUTES (JWT required via AuthMiddleware) =====
 
-	// All /api/v1/* routes require a valid Bearer JWT token (Task 1.6.13)
+	// All /api/v{n}/* routes require a valid Bearer JWT token (Task 1.6.13)
 	// AuthMiddleware validates the token and injects UserID + WorkspaceID into context.
-	r.Route("/api/v1", func(r chi.Router) {
-		r.Use(apmiddleware.AuthMiddleware)
-		r.Use(apmiddleware.AuditMiddleware(auditService))
+	// Every version shares this middleware stack. Handlers are built once in the
+	// v1 block and mounted on each version that serves them; v1 responses carry
+	// deprecation headers linking to the v2 route when one exists.
+	apiMiddleware := chi.Middlewares{requireAuth, apmiddleware.AuditMiddleware(auditService)}
+	apiV2 := chi.NewRouter()
+	apiV2.Use(apiMiddleware...)
+	apiV2.Use(apmiddleware.APIVersion(2))
+	r.Route(apiV1Prefix, func(r chi.Router) {
+		r.Use(apiMiddleware...)
+		r.Use(apmiddleware.APIVersion(1))
+		r.Use(apmiddleware.Deprecation(apiV1DeprecatedSince, apiV2Successor(apiV2)))
 
 		// Shared app services for protected APIs
 		sharedBus := runtime.Bus
 		auditService.RegisterEventSubscribers(sharedBus)
 		ingestSvc := knowledge.NewIngestService(db, sharedBus)
 		embedder := knowledge.NewEmbedderService(db, embedProvider)
+		embedder.SetDimensions(cfg.EmbedDimensions)
+		embedder.SetBatchSize(cfg.EmbedBatchSize)
+		embedder.SetWorkers(cfg.EmbedWorkers)
 		reindexSvc := knowledge.NewReindexService(db, sharedBus, ingestSvc, auditService)
 		runtime.StartBackground(func() { embedder.Start(runtime.BackgroundContext, sharedBus) })
 		runtime.StartBackground(func() { reindexSvc.Start(runtime.BackgroundContext) })
 		policyEngine := policy.NewPolicyEngine(db, nil, auditService)
 		usageService := usagedomain.NewService(db)
 		toolRegistry := tooldomain.NewToolRegistryWithRuntimeAndUsage(db, policyEngine, auditService, usageService)
+		if cfg.ToolMaxResultBytes > 0 {
+			toolRegistry.SetMaxResultBytes(cfg.ToolMaxResultBytes)
+		}
 		approvalService := policy.NewApprovalServiceWithBus(db, auditService, sharedBus)
 		runnerRegistry := agent.NewRunnerRegistry()
 		agentOrchestrator := agent.NewOrchestratorWithRegistry(db, runnerRegistry)
@@ -150,14 +239,34 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 			blackboard.NewPlannerExecutor(db, policyEngine, approvalService, toolRegistry, auditService),
 		)
 		agentOrchestrator.SetBlackboardOrchestrator(blackboardOrchestrator)
+		agentOrchestrator.SetEventBus(sharedBus)
+		modelConfigs := llm.NewModelConfigService(db)
+		agentOrchestrator.SetLLMProviders(llm.NewProviderFactory(cfg, modelConfigs, chatProvider, embedProvider))
+		agentOrchestrator.SetToolCatalog(toolRegistry)
+		if webhookSecrets, err := secretbox.New(cfg.WebhookSecretKey); err == nil {
+			agentOrchestrator.SetWebhookSecretBox(webhookSecrets)
+		}
+		agents.RegisterOutputValidators(agentOrchestrator)
+		if runtime.TracerProvider != nil {
+			agentOrchestrator.SetTracerProvider(runtime.TracerProvider)
+			runtime.StartBackground(func() {
+				agentOrchestrator.StartRunSpanSweeper(runtime.BackgroundContext, agent.DefaultRunSpanSweepInterval, agent.DefaultRunSpanMaxAge)
+			})
+		}
+		runWebhookNotifier := agent.NewRunWebhookNotifier(agentOrchestrator, sharedBus)
+		runtime.StartBackground(func() { runWebhookNotifier.Start(runtime.BackgroundContext) })
 
 		// Lead endpoints (Task 1.5)
 		accountService := crm.NewAccountServiceWithBus(db, sharedBus)
 		contactService := crm.NewContactService(db)
 		dealService := crm.NewDealServiceWithBus(db, sharedBus)
 		caseService := crm.NewCaseServiceWithBus(db, sharedBus)
-		leadHandler := handlers.NewLeadHandler(crm.NewLeadService(db))
+		leadService := crm.NewLeadServiceWithBus(db, sharedBus)
+		leadHandler := handlers.NewLeadHandler(leadService)
 		pipelineHandler := handlers.NewPipelineHandler(crm.NewPipelineService(db))
+		workspaceHandler := handlers.NewWorkspaceHandler(workspaceService)
+		userHandler := handlers.NewUserHandler(crm.NewOwnerReassignService(db))
+		customFieldHandler := handlers.NewCustomFieldHandler(crm.NewCustomFieldService(db))
 		activityHandler := handlers.NewActivityHandler(crm.NewActivityServiceWithBus(db, sharedBus))
 		noteHandler := handlers.NewNoteHandler(crm.NewNoteServiceWithBus(db, sharedBus))
 		attachmentHandler := handlers.NewAttachmentHandler(crm.NewAttachmentService(db))
@@ -170,6 +279,8 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 		workflowRepo := workflowdomain.NewRepository(db)
 		workflowService := workflowdomain.NewServiceWithDependencies(workflowRepo, schedulerSvc)
 		searchSvc := knowledge.NewSearchService(db, embedProvider)
+		searchSvc.SetFeedbackBoost(cfg.SearchFeedbackBoost)
+		runtime.StartBackground(func() { searchSvc.WarmUpAll(runtime.BackgroundContext) })
 		evidenceSvc := knowledge.NewEvidencePackService(db, searchSvc, knowledge.DefaultEvidenceConfig())
 		groundsValidator := agent.NewGroundsValidator(evidenceSvc)
 		workflowHandler := handlers.NewWorkflowHandlerWithRuntime(workflowService, policyEngine, db, agentOrchestrator, toolRegistry, policyEngine, approvalService, groundsValidator, dslRunner)
@@ -179,12 +290,20 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 		contactHandler := handlers.NewContactHandler(contactService)
 		dealHandler := handlers.NewDealHandlerWithSignalCounter(dealService, signalSvc)
 		caseHandler := handlers.NewCaseHandlerWithSignalCounter(caseService, signalSvc)
+		// RBAC: admin routes need the admin role; agent triggers need agent or above.
+		requireAdmin := handlers.RequireRole(domainauth.RoleAdmin)
+		requireAgent := handlers.RequireRole(domainauth.RoleAgent, domainauth.RoleAdmin)
+
 		r.Route("/accounts", func(r chi.Router) {
-			r.Post("/", accountHandler.CreateAccount)         // POST /api/v1/accounts
-			r.Get("/", accountHandler.ListAccounts)           // GET /api/v1/accounts
-			r.Get(routeByID, accountHandler.GetAccount)       // GET /api/v1/accounts/{id}
-			r.Put(routeByID, accountHandler.UpdateAccount)    // PUT /api/v1/accounts/{id}
-			r.Delete(routeByID, accountHandler.DeleteAccount) // DELETE /api/v1/accounts/{id}
+			r.Post("/", accountHandler.CreateAccount)                 // POST /api/v1/accounts
+			r.Get("/", accountHandler.ListAccounts)                   // GET /api/v1/accounts
+			r.Post("/import", accountHandler.ImportAccounts)          // POST /api/v1/accounts/import
+			r.Post("/bulk-delete", accountHandler.BulkDeleteAccounts) // POST /api/v1/accounts/bulk-delete
+			r.Get(routeByID, accountHandler.GetAccount)               // GET /api/v1/accounts/{id}
+			r.Put(routeByID, accountHandler.UpdateAccount)            // PUT /api/v1/accounts/{id}
+			r.Patch(routeByID, accountHandler.PatchAccount)           // PATCH /api/v1/accounts/{id}
+			r.Delete(routeByID, accountHandler.DeleteAccount)         // DELETE /api/v1/accounts/{id}
+			r.Post("/{id}/restore", accountHandler.RestoreAccount)
 			r.Get("/{account_id}/contacts", contactHandler.ListContactsByAccount)
 		})
 
@@ -202,10 +321,12 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 			r.Put(routeByID, leadHandler.UpdateLead)    // PUT /api/v1/leads/{id}
 			r.Delete(routeByID, leadHandler.DeleteLead) // DELETE /api/v1/leads/{id}
 		})
+		apiV2.Get("/leads", leadHandler.ListLeads) // GET /api/v2/leads
 
 		r.Route("/deals", func(r chi.Router) {
 			r.Post("/", dealHandler.CreateDeal)
 			r.Get("/", dealHandler.ListDeals)
+			r.Get("/forecast", dealHandler.Forecast) // GET /api/v1/deals/forecast?pipeline_id=
 			r.Get(routeByID, dealHandler.GetDeal)
 			r.Put(routeByID, dealHandler.UpdateDeal)
 			r.Delete(routeByID, dealHandler.DeleteDeal)
@@ -219,14 +340,22 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 			r.Delete(routeByID, caseHandler.DeleteCase)
 		})
 
+		// Custom fields: members read the definitions; admins define them.
+		r.Route("/custom-fields", func(r chi.Router) {
+			r.Get("/", customFieldHandler.ListCustomFields)
+			r.With(requireAdmin).Post("/", customFieldHandler.CreateCustomField)
+		})
+
 		r.Route("/pipelines", func(r chi.Router) {
 			r.Post("/", pipelineHandler.CreatePipeline)
 			r.Get("/", pipelineHandler.ListPipelines)
 			r.Get(routeByID, pipelineHandler.GetPipeline)
 			r.Put(routeByID, pipelineHandler.UpdatePipeline)
+			r.Patch(routeByID, pipelineHandler.PatchPipeline)
 			r.Delete(routeByID, pipelineHandler.DeletePipeline)
 			r.Post("/{id}/stages", pipelineHandler.CreateStage)
 			r.Get("/{id}/stages", pipelineHandler.ListStages)
+			r.Get("/stages/{stage_id}", pipelineHandler.GetStage)
 			r.Put("/stages/{stage_id}", pipelineHandler.UpdateStage)
 			r.Delete("/stages/{stage_id}", pipelineHandler.DeleteStage)
 		})
@@ -236,12 +365,14 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 			r.Get("/", activityHandler.ListActivities)
 			r.Get(routeByID, activityHandler.GetActivity)
 			r.Put(routeByID, activityHandler.UpdateActivity)
+			r.Post("/{id}/complete", activityHandler.CompleteActivity)
 			r.Delete(routeByID, activityHandler.DeleteActivity)
 		})
 
 		r.Route("/notes", func(r chi.Router) {
 			r.Post("/", noteHandler.CreateNote)
 			r.Get("/", noteHandler.ListNotes)
+			r.Get("/thread", noteHandler.ListNoteThread)
 			r.Get(routeByID, noteHandler.GetNote)
 			r.Put(routeByID, noteHandler.UpdateNote)
 			r.Delete(routeByID, noteHandler.DeleteNote)
@@ -297,6 +428,8 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 		r.Route("/audit", func(r chi.Router) {
 			r.Get("/events", auditHandler.Query)
 			r.Get("/events/{id}", auditHandler.GetByID)
+			r.Get("/stats", auditHandler.Stats)
+			r.Get("/export", auditHandler.Export)
 			r.Post("/export", auditHandler.Export)
 		})
 		r.Get("/usage", usageHandler.ListUsage)
@@ -317,6 +450,7 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 
 		knowledgeIngestHandler := handlers.NewKnowledgeIngestHandler(ingestSvc)
 		knowledgeSearchHandler := handlers.NewKnowledgeSearchHandler(searchSvc)
+		searchHandler := handlers.NewSearchHandler(searchSvc)
 		knowledgeEvidenceHandler := handlers.NewKnowledgeEvidenceHandler(evidenceSvc)
 		knowledgeReindexHandler := handlers.NewKnowledgeReindexHandler(reindexSvc)
 		approvalHandler := handlers.NewApprovalHandler(approvalService)
@@ -331,29 +465,50 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 		copilotActionsHandler := handlers.NewCopilotActionsHandler(copilotActionsSvc)
 
 		_ = tooldomain.RegisterBuiltInExecutors(toolRegistry, tooldomain.BuiltinServices{
-			DB:      db,
-			Case:    caseService,
-			Lead:    crm.NewLeadService(db),
-			Account: crm.NewAccountService(db),
-			Deal:    dealService,
-			Ingest:  ingestSvc,
+			DB:       db,
+			Case:     caseService,
+			Lead:     leadService,
+			Account:  crm.NewAccountService(db),
+			Deal:     dealService,
+			Activity: crm.NewActivityServiceWithBus(db, sharedBus),
+			Note:     crm.NewNoteServiceWithBus(db, sharedBus),
+			Ingest:   ingestSvc,
 		})
 		_ = toolRegistry.EnsureBuiltInToolDefinitionsForAllWorkspaces(context.Background())
 		r.Route("/knowledge", func(r chi.Router) {
-			r.Post("/ingest", knowledgeIngestHandler.Ingest)    // POST /api/v1/knowledge/ingest
-			r.Post("/search", knowledgeSearchHandler.Search)    // POST /api/v1/knowledge/search
-			r.Post("/evidence", knowledgeEvidenceHandler.Build) // POST /api/v1/knowledge/evidence
-			r.Post("/reindex", knowledgeReindexHandler.Reindex) // POST /api/v1/knowledge/reindex
+			r.Post("/ingest", knowledgeIngestHandler.Ingest)         // POST /api/v1/knowledge/ingest
+			r.Post("/search", knowledgeSearchHandler.Search)         // POST /api/v1/knowledge/search
+			r.Post("/evidence", knowledgeEvidenceHandler.Build)      // POST /api/v1/knowledge/evidence
+			r.Post("/reindex", knowledgeReindexHandler.Reindex)      // POST /api/v1/knowledge/reindex
+			r.Get("/index-stats", knowledgeSearchHandler.IndexStats) // GET /api/v1/knowledge/index-stats
+			r.Get("/items/{id}", knowledgeIngestHandler.Get)         // GET /api/v1/knowledge/items/{id}
 		})
+		r.Get("/search", searchHandler.Search)                            // GET /api/v1/search
+		r.Post("/search/feedback", knowledgeSearchHandler.RecordFeedback) // POST /api/v1/search/feedback
 
 		r.Route("/approvals", func(r chi.Router) {
 			r.Get("/", approvalHandler.ListPendingApprovals) // GET /api/v1/approvals
 			r.Put(routeByID, approvalHandler.DecideApproval) // PUT /api/v1/approvals/{id}
 		})
 
+		// Workspaces: members read their own; admins change them. New
+		// workspaces are only provisioned by registration, which creates
+		// their first admin with them.
+		r.Route("/workspaces", func(r chi.Router) {
+			r.Get("/", workspaceHandler.ListWorkspaces)                           // GET /api/v1/workspaces
+			r.Get(routeByID, workspaceHandler.GetWorkspace)                       // GET /api/v1/workspaces/{id}
+			r.With(requireAdmin).Put(routeByID, workspaceHandler.UpdateWorkspace) // PUT /api/v1/workspaces/{id}
+			r.With(requireAdmin).Delete(routeByID, workspaceHandler.DeleteWorkspace)
+		})
+
+		// Users: admins hand a departing rep's records over to someone else.
+		r.With(requireAdmin).Post("/users/{id}/reassign", userHandler.ReassignOwner) // POST /api/v1/users/{id}/reassign?to=
+
 		r.Route("/admin/tools", func(r chi.Router) {
+			r.Use(requireAdmin)
 			r.Get("/", toolHandler.ListTools)        // GET /api/v1/admin/tools
 			r.Post("/", toolHandler.CreateTool)      // POST /api/v1/admin/tools
+			r.Get(routeByID, toolHandler.GetTool)    // GET /api/v1/admin/tools/{id}
 			r.Put(routeByID, toolHandler.UpdateTool) // PUT /api/v1/admin/tools/{id}
 			r.Put("/{id}/activate", toolHandler.ActivateTool)
 			r.Put("/{id}/deactivate", toolHandler.DeactivateTool)
@@ -361,17 +516,21 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 		})
 
 		r.Route("/admin/blackboard", func(r chi.Router) {
+			r.Use(requireAdmin)
 			r.Post("/{cwID}/plan", blackboardHandler.RunPipeline)
 		})
 
 		// Task 3.9: Prompt Versioning routes
 		r.Route("/admin/prompts", func(r chi.Router) {
+			r.Use(requireAdmin)
 			r.Get("/", promptHandler.List)                  // GET /api/v1/admin/prompts?agent_id={id}
 			r.Post("/", promptHandler.Create)               // POST /api/v1/admin/prompts
+			r.Get(routeByID, promptHandler.Get)             // GET /api/v1/admin/prompts/{id}
 			r.Put("/{id}/promote", promptHandler.Promote)   // PUT /api/v1/admin/prompts/{id}/promote
 			r.Put("/{id}/rollback", promptHandler.Rollback) // PUT /api/v1/admin/prompts/{id}/rollback
 			r.Get("/experiments", promptHandler.ListExperiments)
 			r.Post("/experiments", promptHandler.StartExperiment)
+			r.Get("/experiments/{id}", promptHandler.GetExperiment)
 			r.Put("/experiments/{id}/stop", promptHandler.StopExperiment)
 		})
 
@@ -381,27 +540,23 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 		evalBenchmarkSvc := domaineval.NewBenchmarkRegistryService(db, evalRunnerSvc)
 		evalHandler := handlers.NewEvalHandlerWithAuthorizer(evalSuiteSvc, evalRunnerSvc, evalBenchmarkSvc, policyEngine)
 		r.Route("/admin/eval", func(r chi.Router) {
+			r.Use(requireAdmin)
 			r.Route("/suites", func(r chi.Router) {
 				r.Post("/", evalHandler.CreateSuite)   // POST /api/v1/admin/eval/suites
 				r.Get("/", evalHandler.ListSuites)     // GET  /api/v1/admin/eval/suites
 				r.Get(routeByID, evalHandler.GetSuite) // GET  /api/v1/admin/eval/suites/{id}
 			})
-			r.Post("/benchmarks", evalHandler.CreateBenchmark) // POST /api/v1/admin/eval/benchmarks
-			r.Post("/run", evalHandler.RunEval)                // POST /api/v1/admin/eval/run
-			r.Get("/runs", evalHandler.ListRuns)               // GET  /api/v1/admin/eval/runs
-			r.Get("/runs/{id}", evalHandler.GetRun)            // GET  /api/v1/admin/eval/runs/{id}
-		})
-
-		r.Route("/copilot", func(r chi.Router) {
-			r.Post("/chat", copilotChatHandler.Chat)                         // POST /api/v1/copilot/chat
-			r.Post("/suggest-actions", copilotActionsHandler.SuggestActions) // POST /api/v1/copilot/suggest-actions
-			r.Post("/summarize", copilotActionsHandler.Summarize)            // POST /api/v1/copilot/summarize
-			r.Post("/sales-brief", copilotActionsHandler.SalesBrief)         // POST /api/v1/copilot/sales-brief
+			r.Post("/benchmarks", evalHandler.CreateBenchmark)  // POST /api/v1/admin/eval/benchmarks
+			r.Get("/benchmarks/{id}", evalHandler.GetBenchmark) // GET  /api/v1/admin/eval/benchmarks/{id}
+			r.Post("/run", evalHandler.RunEval)                 // POST /api/v1/admin/eval/run
+			r.Get("/runs", evalHandler.ListRuns)                // GET  /api/v1/admin/eval/runs
+			r.Get("/runs/{id}", evalHandler.GetRun)             // GET  /api/v1/admin/eval/runs/{id}
 		})
 
 		// Task 3.7: Agent Runtime routes
 		agentHandler := handlers.NewAgentHandler(agentOrchestrator)
 		supportAgent := agents.NewSupportAgentWithDBAndUsage(agentOrchestrator, toolRegistry, evidenceSvc, db, usageService)
+		supportAgent.SetCaseAssigner(caseService)
 		supportAgentHandler := handlers.NewSupportAgentHandler(supportAgent)
 		// Task 4.5b — FR-231: Prospecting Agent wiring.
 		prospectingAgent := agents.NewProspectingAgent(
@@ -475,6 +630,8 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 			DB:               db,
 		}
 		resumeHandler := agent.NewWorkflowResumeHandler(dslRunner, resumeRC)
+		eventTriggerBridge := agent.NewEventTriggerBridge(agentOrchestrator, sharedBus, resumeRC)
+		runtime.StartBackground(func() { eventTriggerBridge.Start(runtime.BackgroundContext) })
 		schedulerWorker := schedulerdomain.NewWorker(schedulerRepo, resumeHandler.Handle)
 		runtime.StartBackground(func() {
 			if workerErr := schedulerWorker.Start(runtime.BackgroundContext); workerErr != nil && !errors.Is(workerErr, context.Canceled) {
@@ -486,25 +643,80 @@ func newRouterWithConfigAndRuntime(db *sql.DB, cfg config.Config, runtime Router
 		handoffService := agent.NewHandoffService(db, caseService, sharedBus)
 		handoffHandler := handlers.NewHandoffHandler(handoffService)
 
+		copilotAskHandler := handlers.NewCopilotAskHandler(supportAgent, prospectingAgent)
+		r.Route("/copilot", func(r chi.Router) {
+			r.Post("/chat", copilotChatHandler.Chat)                         // POST /api/v1/copilot/chat
+			r.Post("/suggest-actions", copilotActionsHandler.SuggestActions) // POST /api/v1/copilot/suggest-actions
+			r.Post("/summarize", copilotActionsHandler.Summarize)            // POST /api/v1/copilot/summarize
+			r.Post("/sales-brief", copilotActionsHandler.SalesBrief)         // POST /api/v1/copilot/sales-brief
+			r.With(requireAgent).Post("/ask", copilotAskHandler.Ask)         // POST /api/v1/copilot/ask
+		})
+
+		// Triggers of agents that draft with the chat provider answer 503 up
+		// front while it is down.
+		requireLLM := apmiddleware.RequireHealthyLLM(llmHealthGate(chatHealth, modelConfigs))
 		r.Route("/agents", func(r chi.Router) {
-			r.Post("/trigger", agentHandler.TriggerAgent)                       // POST /api/v1/agents/trigger
-			r.Get("/runs", agentHandler.ListAgentRuns)                          // GET  /api/v1/agents/runs
-			r.Get("/runs/{id}", agentHandler.GetAgentRun)                       // GET  /api/v1/agents/runs/{id}
-			r.Post("/runs/{id}/cancel", agentHandler.CancelAgentRun)            // POST /api/v1/agents/runs/{id}/cancel
-			r.Get("/runs/{id}/handoff", handoffHandler.GetHandoffPackage)       // GET  /api/v1/agents/runs/{id}/handoff
-			r.Post("/runs/{id}/handoff", handoffHandler.InitiateHandoff)        // POST /api/v1/agents/runs/{id}/handoff
-			r.Get("/definitions", agentHandler.ListAgentDefinitions)            // GET  /api/v1/agents/definitions
-			r.Post("/support/trigger", supportAgentHandler.TriggerSupportAgent) // POST /api/v1/agents/support/trigger
-			r.Post("/prospecting/trigger", prospectingAgentHandler.TriggerProspectingAgent)
-			r.Post("/kb/trigger", kbAgentHandler.TriggerKBAgent)
+			r.With(requireAgent).Post("/trigger", agentHandler.TriggerAgent)              // POST /api/v1/agents/trigger
+			r.Get("/runs", agentHandler.ListAgentRuns)                                    // GET  /api/v1/agents/runs
+			r.Get("/runs/{id}", agentHandler.GetAgentRun)                                 // GET  /api/v1/agents/runs/{id}
+			r.Post("/runs/{id}/cancel", agentHandler.CancelAgentRun)                      // POST /api/v1/agents/runs/{id}/cancel
+			r.Get("/runs/{id}/export", agentHandler.ExportAgentRun)                       // GET  /api/v1/agents/runs/{id}/export
+			r.Get("/runs/{id}/handoff", handoffHandler.GetHandoffPackage)                 // GET  /api/v1/agents/runs/{id}/handoff
+			r.Post("/runs/{id}/handoff", handoffHandler.InitiateHandoff)                  // POST /api/v1/agents/runs/{id}/handoff
+			r.Get("/definitions", agentHandler.ListAgentDefinitions)                      // GET  /api/v1/agents/definitions
+			r.Get("/definitions/{id}", agentHandler.GetAgentDefinition)                   // GET  /api/v1/agents/definitions/{id}
+			r.With(requireAdmin).Post("/definitions", agentHandler.CreateAgentDefinition) // POST /api/v1/agents/definitions
+			r.Get("/metrics", agentHandler.GetAgentMetrics)                               // GET  /api/v1/agents/metrics
+			r.With(requireAgent).Post("/support/trigger", supportAgentHandler.TriggerSupportAgent)
+			r.With(requireAgent, requireLLM).Post("/prospecting/trigger", prospectingAgentHandler.TriggerProspectingAgent)
+			r.With(requireAgent, requireLLM).Post("/prospecting/batch", prospectingAgentHandler.TriggerProspectingBatch)
+			r.With(requireLLM).Post("/kb/trigger", kbAgentHandler.TriggerKBAgent)
 			r.Post("/insights/trigger", insightsAgentHandler.TriggerInsightsAgent)
-			r.Post("/deal-risk/trigger", dealRiskAgentHandler.TriggerDealRiskAgent)
+			r.With(requireLLM).Post("/deal-risk/trigger", dealRiskAgentHandler.TriggerDealRiskAgent)
 		})
 	})
+	r.Mount(apiV2Prefix, apiV2)
 
 	return r, nil
 }
 
+// llmHealthGate reports whether an agent trigger may run. The health monitor
+// only probes the default chat provider, so a workspace whose model config
+// picks its own chat provider or model is let through rather than gated on a
+// provider it does not use; its runs still fail if that provider is down.
+func llmHealthGate(defaultHealth *llm.HealthMonitor, configs *llm.ModelConfigService) func(*http.Request) bool {
+	return func(req *http.Request) bool {
+		if defaultHealth.LastHealth().Healthy() {
+			return true
+		}
+		wsID, _ := req.Context().Value(ctxkeys.WorkspaceID).(string)
+		if wsID == "" {
+			return false
+		}
+		mc, err := configs.Get(req.Context(), wsID)
+		return err == nil && (mc.Provider != "" || mc.ChatModel != "")
+	}
+}
+
+// apiV2Successor returns the /api/v2 URL of a v1 request when apiV2 serves
+// the same path and method, for the v1 successor-version Link header.
+func apiV2Successor(apiV2 chi.Routes) func(*http.Request) string {
+	return func(req *http.Request) string {
+		rctx := chi.RouteContext(req.Context())
+		if rctx == nil {
+			return ""
+		}
+		path := rctx.RoutePath
+		if path == "" {
+			path = strings.TrimPrefix(req.URL.Path, apiV1Prefix)
+		}
+		if !apiV2.Match(chi.NewRouteContext(), req.Method, path) {
+			return ""
+		}
+		return apiV2Prefix + path
+	}
+}
+
 func normalizeRouterRuntime(runtime RouterRuntime) RouterRuntime {
 	if runtime.Bus == nil {
 		runtime.Bus = eventbus.New()
@@ -517,5 +729,8 @@ func normalizeRouterRuntime(runtime RouterRuntime) RouterRuntime {
 			go fn()
 		}
 	}
+	if runtime.Logger == nil {
+		runtime.Logger = slog.Default()
+	}
 	return runtime
 }
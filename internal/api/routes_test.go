@@ -6,8 +6,10 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -15,8 +17,12 @@ import (
 	"testing"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/api/openapi"
 	"github.com/matiasleandrokruk/fenix/internal/infra/config"
+	"github.com/matiasleandrokruk/fenix/internal/infra/llm"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+	pkgauth "github.com/matiasleandrokruk/fenix/pkg/auth"
 )
 
 func TestMain(m *testing.M) {
@@ -59,6 +65,24 @@ func mustOpenAPITestDB(t *testing.T) *sql.DB {
 	return db
 }
 
+// mustCreateAPITestUser inserts an active user in its own workspace so tokens
+// minted for it pass the auth middleware's membership check.
+func mustCreateAPITestUser(t *testing.T, db *sql.DB, userID, workspaceID string) {
+	t.Helper()
+	if _, err := db.Exec(`
+		INSERT INTO workspace (id, name, slug, created_at, updated_at)
+		VALUES (?, ?, ?, datetime('now'), datetime('now'))
+	`, workspaceID, workspaceID, workspaceID); err != nil {
+		t.Fatalf("mustCreateAPITestUser: insert workspace: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO user_account (id, workspace_id, email, display_name, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, 'active', datetime('now'), datetime('now'))
+	`, userID, workspaceID, userID+"@example.com", userID); err != nil {
+		t.Fatalf("mustCreateAPITestUser: insert user_account: %v", err)
+	}
+}
+
 // TestNewRouter_HealthEndpoint verifies that NewRouter registers the /health route.
 func TestNewRouter_HealthEndpoint(t *testing.T) {
 	db := mustOpenAPITestDB(t)
@@ -77,6 +101,21 @@ func TestNewRouter_HealthEndpoint(t *testing.T) {
 	}
 }
 
+func TestNewRouter_RecoversPanicsWhenUsedDirectly(t *testing.T) {
+	db := mustOpenAPITestDB(t)
+
+	router := mustNewRouter(t, db)
+	router.Get("/panic-test", func(http.ResponseWriter, *http.Request) { panic("boom") })
+
+	req := httptest.NewRequest(http.MethodGet, "/panic-test", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 from a panicking handler, got %d", w.Code)
+	}
+}
+
 func TestNewRouter_ReadyzEndpoint(t *testing.T) {
 	db := mustOpenAPITestDB(t)
 	cfg := testCfg()
@@ -131,6 +170,96 @@ func TestNewRouter_BlackboardPlanEndpoint_Unauthorized(t *testing.T) {
 	}
 }
 
+// TestNewRouter_AdminRoutes_RequireAdminRole verifies RBAC on /admin/* and the
+// generic, support and prospecting triggers: 403 for an insufficient role,
+// never 401.
+func TestNewRouter_AdminRoutes_RequireAdminRole(t *testing.T) {
+	db := mustOpenAPITestDB(t)
+	router := mustNewRouter(t, db)
+	mustCreateAPITestUser(t, db, "user-rbac", "ws-rbac")
+	// Handlers behind RequireRole still check the user's own grants.
+	if _, err := db.Exec(`
+		INSERT INTO role (id, workspace_id, name, permissions, created_at, updated_at)
+		VALUES ('role-rbac', 'ws-rbac', 'api-admin', '{"api":["admin"]}', datetime('now'), datetime('now'))
+	`); err != nil {
+		t.Fatalf("insert role: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO user_role (id, user_id, role_id, created_at) VALUES ('ur-rbac', 'user-rbac', 'role-rbac', datetime('now'))
+	`); err != nil {
+		t.Fatalf("insert user_role: %v", err)
+	}
+
+	do := func(method, path, role string) int {
+		token, err := pkgauth.GenerateJWTWithRole("user-rbac", "ws-rbac", role)
+		if err != nil {
+			t.Fatalf("GenerateJWTWithRole: %v", err)
+		}
+		req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := do(http.MethodGet, "/api/v1/admin/prompts?agent_id=a1", "member"); code != http.StatusForbidden {
+		t.Errorf("member on /admin/prompts: status = %d; want 403", code)
+	}
+	if code := do(http.MethodGet, "/api/v1/admin/prompts?agent_id=a1", "agent"); code != http.StatusForbidden {
+		t.Errorf("agent on /admin/prompts: status = %d; want 403", code)
+	}
+	if code := do(http.MethodGet, "/api/v1/admin/prompts?agent_id=a1", "admin"); code == http.StatusForbidden || code == http.StatusUnauthorized {
+		t.Errorf("admin on /admin/prompts: status = %d; want access", code)
+	}
+	if code := do(http.MethodPost, "/api/v1/custom-fields", "member"); code != http.StatusForbidden {
+		t.Errorf("member creating a custom field: status = %d; want 403", code)
+	}
+	if code := do(http.MethodGet, "/api/v1/custom-fields?entityType=account", "member"); code == http.StatusForbidden || code == http.StatusUnauthorized {
+		t.Errorf("member listing custom fields: status = %d; want access", code)
+	}
+	if code := do(http.MethodPost, "/api/v1/agents/support/trigger", "member"); code != http.StatusForbidden {
+		t.Errorf("member on support trigger: status = %d; want 403", code)
+	}
+	if code := do(http.MethodPost, "/api/v1/agents/trigger", "member"); code != http.StatusForbidden {
+		t.Errorf("member on generic agent trigger: status = %d; want 403", code)
+	}
+	if code := do(http.MethodPost, "/api/v1/agents/trigger", "agent"); code == http.StatusForbidden || code == http.StatusUnauthorized {
+		t.Errorf("agent on generic agent trigger: status = %d; want access", code)
+	}
+	if code := do(http.MethodPost, "/api/v1/agents/prospecting/trigger", "agent"); code == http.StatusForbidden || code == http.StatusUnauthorized {
+		t.Errorf("agent on prospecting trigger: status = %d; want access", code)
+	}
+}
+
+// TestNewRouter_RejectsForgedWorkspaceClaim verifies that a valid token whose
+// workspaceId claim names a workspace the user does not belong to gets 403.
+func TestNewRouter_RejectsForgedWorkspaceClaim(t *testing.T) {
+	db := mustOpenAPITestDB(t)
+	router := mustNewRouter(t, db)
+	mustCreateAPITestUser(t, db, "user-a", "ws-a")
+	mustCreateAPITestUser(t, db, "user-b", "ws-b")
+
+	get := func(userID, workspaceID string) *httptest.ResponseRecorder {
+		token, err := pkgauth.GenerateJWTWithRole(userID, workspaceID, "member")
+		if err != nil {
+			t.Fatalf("GenerateJWTWithRole: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/leads", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("user-a", "ws-a"); w.Code != http.StatusOK {
+		t.Fatalf("own workspace: status = %d; body = %s", w.Code, w.Body.String())
+	}
+	if w := get("user-a", "ws-b"); w.Code != http.StatusForbidden {
+		t.Fatalf("forged workspace claim: status = %d; want 403; body = %s", w.Code, w.Body.String())
+	}
+}
+
 // ===== C2: CORS integration tests =====
 
 // testCfg returns a Config with a known BFFOrigin for router-level tests.
@@ -319,3 +448,158 @@ func TestRouter_Register_RateLimit_Returns429(t *testing.T) {
 		t.Errorf("4th register request: status = %d; want %d", w.Code, http.StatusTooManyRequests)
 	}
 }
+
+// TestNewRouter_APIVersions_ShareHandlersWithVersionedEnvelopes verifies that
+// GET /leads is served under /api/v1 and /api/v2 with each version's list
+// envelope, that v1 responses carry deprecation headers and that v2 shares
+// the v1 auth stack.
+func TestNewRouter_APIVersions_ShareHandlersWithVersionedEnvelopes(t *testing.T) {
+	db := mustOpenAPITestDB(t)
+	router := mustNewRouter(t, db)
+	mustCreateAPITestUser(t, db, "user-versions", "ws-versions")
+	token, err := pkgauth.GenerateJWTWithRole("user-versions", "ws-versions", "member")
+	if err != nil {
+		t.Fatalf("GenerateJWTWithRole: %v", err)
+	}
+	get := func(path string, authed bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if authed {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	v1 := get("/api/v1/leads", true)
+	if v1.Code != http.StatusOK {
+		t.Fatalf("v1 leads: status = %d; body = %s", v1.Code, v1.Body.String())
+	}
+	var v1Body map[string]json.RawMessage
+	if err := json.Unmarshal(v1.Body.Bytes(), &v1Body); err != nil {
+		t.Fatalf("decode v1: %v", err)
+	}
+	if _, ok := v1Body["meta"]; !ok {
+		t.Errorf("v1 envelope = %s; want meta", v1.Body.String())
+	}
+	if v1.Header().Get("Deprecation") == "" {
+		t.Error("expected Deprecation header on v1")
+	}
+	if got := v1.Header().Get("Link"); got != `</api/v2/leads>; rel="successor-version"` {
+		t.Errorf("v1 Link = %q", got)
+	}
+
+	v2 := get("/api/v2/leads", true)
+	if v2.Code != http.StatusOK {
+		t.Fatalf("v2 leads: status = %d; body = %s", v2.Code, v2.Body.String())
+	}
+	var v2Body struct {
+		Data []json.RawMessage `json:"data"`
+		Page *struct {
+			Total   int  `json:"total"`
+			HasMore bool `json:"has_more"`
+		} `json:"page"`
+		Meta json.RawMessage `json:"meta"`
+	}
+	if err := json.Unmarshal(v2.Body.Bytes(), &v2Body); err != nil {
+		t.Fatalf("decode v2: %v", err)
+	}
+	if v2Body.Page == nil || v2Body.Meta != nil || v2Body.Data == nil {
+		t.Errorf("v2 envelope = %s; want data and page", v2.Body.String())
+	}
+	if v2.Header().Get("Deprecation") != "" {
+		t.Error("unexpected Deprecation header on v2")
+	}
+
+	cases := get("/api/v1/cases", true)
+	if cases.Header().Get("Deprecation") == "" || cases.Header().Get("Link") != "" {
+		t.Errorf("v1 cases headers = %v; want Deprecation without successor Link", cases.Header())
+	}
+	if code := get("/api/v2/cases", true).Code; code != http.StatusNotFound {
+		t.Errorf("v2 cases: status = %d; want 404", code)
+	}
+	if code := get("/api/v2/leads", false).Code; code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated v2 leads: status = %d; want 401", code)
+	}
+}
+
+// TestNewRouter_OpenAPIDocumentsEveryRoute keeps docs/openapi.yaml in sync
+// with the router: every registered route must be documented, and every
+// documented operation must be registered.
+func TestNewRouter_OpenAPIDocumentsEveryRoute(t *testing.T) {
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("openapi.Load: %v", err)
+	}
+	router := mustNewRouter(t, mustOpenAPITestDB(t))
+
+	registered := make(map[string]bool)
+	walkErr := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !spec.Documents(method, route) {
+			t.Errorf("route %s %s is not documented in docs/openapi.yaml", method, route)
+		}
+		registered[method+" "+route] = true
+		return nil
+	})
+	if walkErr != nil {
+		t.Fatalf("chi.Walk: %v", walkErr)
+	}
+
+	for _, op := range spec.Operations() {
+		if !router.Match(chi.NewRouteContext(), op.Method, op.Path) {
+			t.Errorf("docs/openapi.yaml documents %s %s, which is not registered", op.Method, op.Path)
+		}
+	}
+	if len(registered) == 0 {
+		t.Fatal("chi.Walk found no routes")
+	}
+}
+
+func TestNewRouter_ServesOpenAPIJSONWithoutAuth(t *testing.T) {
+	router := mustNewRouter(t, mustOpenAPITestDB(t))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; body = %s", w.Code, w.Body.String())
+	}
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") || doc.Paths["/api/v1/openapi.json"] == nil {
+		t.Fatalf("openapi = %q with %d paths; want a 3.x document describing itself", doc.OpenAPI, len(doc.Paths))
+	}
+}
+
+type downLLM struct{ llm.LLMProvider }
+
+func (downLLM) HealthCheck(context.Context) error { return errors.New("provider down") }
+
+func TestLLMHealthGate_SkipsWorkspacesWithOwnChatModel(t *testing.T) {
+	db := mustOpenAPITestDB(t)
+	mustCreateAPITestUser(t, db, "user-default", "ws-default")
+	mustCreateAPITestUser(t, db, "user-own", "ws-own-model")
+
+	configs := llm.NewModelConfigService(db)
+	if _, err := configs.Upsert(context.Background(), llm.ModelConfig{WorkspaceID: "ws-own-model", ChatModel: "other-model"}); err != nil {
+		t.Fatalf("upsert model config: %v", err)
+	}
+	health := llm.NewHealthMonitor(downLLM{}, 0)
+	health.Check(context.Background())
+	gate := llmHealthGate(health, configs)
+
+	allowed := func(wsID string) bool {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/agents/kb/trigger", nil)
+		return gate(req.WithContext(context.WithValue(req.Context(), ctxkeys.WorkspaceID, wsID)))
+	}
+	if allowed("ws-default") {
+		t.Error("workspace on the down default provider was let through")
+	}
+	if !allowed("ws-own-model") {
+		t.Error("workspace with its own chat model was gated on the default provider")
+	}
+}
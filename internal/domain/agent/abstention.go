@@ -0,0 +1,110 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AbstentionReason classifies why a run did not act. Runs that abstain store
+// one of these codes in abstention_reason so abstentions can be aggregated;
+// the human-readable explanation stays in the run output.
+type AbstentionReason string
+
+const (
+	AbstentionInsufficientSignals AbstentionReason = "insufficient_signals"
+	AbstentionLowConfidence       AbstentionReason = "low_confidence"
+	AbstentionPolicyBlock         AbstentionReason = "policy_block"
+	AbstentionMissingContext      AbstentionReason = "missing_context"
+)
+
+var ErrInvalidAbstentionReason = errors.New("invalid abstention reason")
+
+// AbstentionReasons lists every valid AbstentionReason.
+func AbstentionReasons() []AbstentionReason {
+	return []AbstentionReason{
+		AbstentionInsufficientSignals,
+		AbstentionLowConfidence,
+		AbstentionPolicyBlock,
+		AbstentionMissingContext,
+	}
+}
+
+// Valid reports whether r is one of the defined abstention reasons.
+func (r AbstentionReason) Valid() bool {
+	switch r {
+	case AbstentionInsufficientSignals, AbstentionLowConfidence, AbstentionPolicyBlock, AbstentionMissingContext:
+		return true
+	default:
+		return false
+	}
+}
+
+// Ptr returns r as the *string stored on Run and RunUpdates.
+func (r AbstentionReason) Ptr() *string {
+	s := string(r)
+	return &s
+}
+
+// validateAbstentionReason checks the reason an update stores. Rejected,
+// delegated and escalated runs keep the free-text reason shown to the human
+// reviewing or taking over; every other status must use the taxonomy.
+func validateAbstentionReason(status string, reason *string) error {
+	if reason == nil || *reason == "" {
+		return nil
+	}
+	switch status {
+	case StatusRejected, StatusDelegated, StatusEscalated:
+		return nil
+	}
+	if !AbstentionReason(*reason).Valid() {
+		return fmt.Errorf("%w: %q", ErrInvalidAbstentionReason, *reason)
+	}
+	return nil
+}
+
+// CountAbstentionsByReason returns the number of runs per abstention reason
+// in a workspace, optionally restricted to one agent definition. Every reason
+// is present in the result, with zero when no run used it.
+func (o *Orchestrator) CountAbstentionsByReason(ctx context.Context, workspaceID, agentID string) (map[AbstentionReason]int64, error) {
+	reasons := AbstentionReasons()
+	args := []any{workspaceID}
+	placeholders := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		placeholders = append(placeholders, "?")
+		args = append(args, string(reason))
+	}
+	query := `
+		SELECT abstention_reason, COUNT(*)
+		FROM agent_run
+		WHERE workspace_id = ? AND abstention_reason IN (` + strings.Join(placeholders, ", ") + `)`
+	if agentID != "" {
+		query += ` AND agent_definition_id = ?`
+		args = append(args, agentID)
+	}
+	query += ` GROUP BY abstention_reason`
+
+	rows, err := o.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count abstentions by reason: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[AbstentionReason]int64, len(reasons))
+	for _, reason := range reasons {
+		counts[reason] = 0
+	}
+	for rows.Next() {
+		var reason string
+		var count int64
+		if scanErr := rows.Scan(&reason, &count); scanErr != nil {
+			return nil, fmt.Errorf("scan abstention count: %w", scanErr)
+		}
+		counts[AbstentionReason(reason)] = count
+	}
+	if rowsErr := rows.Err(); rowsErr != nil {
+		return nil, fmt.Errorf("iterate abstention counts: %w", rowsErr)
+	}
+	return counts, nil
+}
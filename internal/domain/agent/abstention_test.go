@@ -0,0 +1,130 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func triggerAbstentionTestRun(t *testing.T, orch *Orchestrator, agentID string) *Run {
+	t.Helper()
+	run, err := orch.TriggerAgent(context.Background(), TriggerAgentInput{
+		AgentID:     agentID,
+		WorkspaceID: "ws-abstain",
+		TriggerType: TriggerTypeManual,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+	return run
+}
+
+func setupAbstentionTest(t *testing.T) *Orchestrator {
+	t.Helper()
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+	for _, id := range []string{"agent-abstain-a", "agent-abstain-b"} {
+		if _, err := db.Exec(
+			`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+			 VALUES (?, 'ws-abstain', ?, 'support', 'active')`, id, id); err != nil {
+			t.Fatalf("insert definition: %v", err)
+		}
+	}
+	return NewOrchestrator(db)
+}
+
+func TestUpdateAgentRun_ValidatesAbstentionReason(t *testing.T) {
+	orch := setupAbstentionTest(t)
+	ctx := context.Background()
+
+	run := triggerAbstentionTestRun(t, orch, "agent-abstain-a")
+	_, err := orch.UpdateAgentRun(ctx, "ws-abstain", run.ID, RunUpdates{
+		Status:           StatusAbstained,
+		AbstentionReason: stringPtr("not enough data"),
+		Completed:        true,
+	})
+	if !errors.Is(err, ErrInvalidAbstentionReason) {
+		t.Fatalf("free-text reason: err = %v, want ErrInvalidAbstentionReason", err)
+	}
+
+	updated, err := orch.UpdateAgentRun(ctx, "ws-abstain", run.ID, RunUpdates{
+		Status:           StatusAbstained,
+		AbstentionReason: AbstentionLowConfidence.Ptr(),
+		Completed:        true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateAgentRun(low_confidence): %v", err)
+	}
+	if updated.AbstentionReason == nil || *updated.AbstentionReason != string(AbstentionLowConfidence) {
+		t.Fatalf("AbstentionReason = %v, want %s", updated.AbstentionReason, AbstentionLowConfidence)
+	}
+}
+
+func TestUpdateAgentRun_DelegatedRunKeepsFreeTextReason(t *testing.T) {
+	orch := setupAbstentionTest(t)
+
+	run := triggerAbstentionTestRun(t, orch, "agent-abstain-a")
+	if _, err := orch.UpdateAgentRun(context.Background(), "ws-abstain", run.ID, RunUpdates{
+		Status:           StatusDelegated,
+		AbstentionReason: stringPtr("Enterprise review required"),
+		Completed:        true,
+	}); err != nil {
+		t.Fatalf("UpdateAgentRun(delegated): %v", err)
+	}
+}
+
+func TestCountAbstentionsByReason(t *testing.T) {
+	orch := setupAbstentionTest(t)
+	ctx := context.Background()
+
+	abstain := func(agentID string, reason AbstentionReason) {
+		t.Helper()
+		run := triggerAbstentionTestRun(t, orch, agentID)
+		if _, err := orch.UpdateAgentRun(ctx, "ws-abstain", run.ID, RunUpdates{
+			Status:           StatusAbstained,
+			AbstentionReason: reason.Ptr(),
+			Completed:        true,
+		}); err != nil {
+			t.Fatalf("UpdateAgentRun: %v", err)
+		}
+	}
+	abstain("agent-abstain-a", AbstentionMissingContext)
+	abstain("agent-abstain-a", AbstentionMissingContext)
+	abstain("agent-abstain-b", AbstentionPolicyBlock)
+
+	delegated := triggerAbstentionTestRun(t, orch, "agent-abstain-a")
+	if _, err := orch.UpdateAgentRun(ctx, "ws-abstain", delegated.ID, RunUpdates{
+		Status:           StatusDelegated,
+		AbstentionReason: stringPtr("Enterprise review required"),
+		Completed:        true,
+	}); err != nil {
+		t.Fatalf("UpdateAgentRun(delegated): %v", err)
+	}
+
+	counts, err := orch.CountAbstentionsByReason(ctx, "ws-abstain", "")
+	if err != nil {
+		t.Fatalf("CountAbstentionsByReason: %v", err)
+	}
+	want := map[AbstentionReason]int64{
+		AbstentionInsufficientSignals: 0,
+		AbstentionLowConfidence:       0,
+		AbstentionPolicyBlock:         1,
+		AbstentionMissingContext:      2,
+	}
+	if len(counts) != len(want) {
+		t.Fatalf("counts = %v, want %v", counts, want)
+	}
+	for reason, n := range want {
+		if counts[reason] != n {
+			t.Fatalf("counts[%s] = %d, want %d", reason, counts[reason], n)
+		}
+	}
+
+	counts, err = orch.CountAbstentionsByReason(ctx, "ws-abstain", "agent-abstain-b")
+	if err != nil {
+		t.Fatalf("CountAbstentionsByReason(agent): %v", err)
+	}
+	if counts[AbstentionPolicyBlock] != 1 || counts[AbstentionMissingContext] != 0 {
+		t.Fatalf("agent-filtered counts = %v", counts)
+	}
+}
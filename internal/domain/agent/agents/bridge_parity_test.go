@@ -118,8 +118,9 @@ func runBridgeSupportResolveFlow(t *testing.T, db *sql.DB) (*agent.Run, string,
 
 	registry := tool.NewToolRegistry(db)
 	if err := tool.RegisterBuiltInExecutors(registry, tool.BuiltinServices{
-		DB:   db,
-		Case: crm.NewCaseService(db),
+		DB:       db,
+		Case:     crm.NewCaseService(db),
+		Activity: crm.NewActivityService(db),
 	}); err != nil {
 		t.Fatalf("register builtins: %v", err)
 	}
@@ -0,0 +1,89 @@
+package agents
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+)
+
+// Agent types a copilot request can be routed to.
+const (
+	CopilotAgentSupport     = "support"
+	CopilotAgentProspecting = "prospecting"
+)
+
+var (
+	ErrInvalidCopilotAgentType = errors.New("invalid copilot agent type")
+	ErrCopilotAgentUnresolved  = errors.New("could not infer copilot agent from request")
+)
+
+// CopilotAsk is an inline copilot request before it is routed to an agent.
+type CopilotAsk struct {
+	AgentType string
+	Message   string
+	CaseID    string
+	LeadID    string
+}
+
+// Words that point a copilot message at one agent. Spanish variants match
+// the agents' default language.
+var (
+	copilotSupportTerms = map[string]bool{
+		"case": true, "ticket": true, "issue": true, "problem": true, "error": true,
+		"bug": true, "refund": true, "complaint": true, "customer": true,
+		"caso": true, "incidencia": true, "problema": true, "reclamo": true, "cliente": true,
+	}
+	copilotProspectingTerms = map[string]bool{
+		"lead": true, "leads": true, "prospect": true, "prospecting": true, "outreach": true,
+		"qualify": true, "pitch": true, "prospecto": true, "prospectos": true, "contactar": true,
+	}
+)
+
+// ResolveCopilotAgent picks the agent for a copilot request. An explicit
+// AgentType wins; otherwise a lone case or lead id decides, and failing that
+// the message is matched against each agent's vocabulary.
+func ResolveCopilotAgent(ask CopilotAsk) (string, error) {
+	switch ask.AgentType {
+	case CopilotAgentSupport, CopilotAgentProspecting:
+		return ask.AgentType, nil
+	case "":
+	default:
+		return "", ErrInvalidCopilotAgentType
+	}
+
+	switch {
+	case ask.CaseID != "" && ask.LeadID == "":
+		return CopilotAgentSupport, nil
+	case ask.LeadID != "" && ask.CaseID == "":
+		return CopilotAgentProspecting, nil
+	}
+
+	support, prospecting := 0, 0
+	for _, word := range strings.FieldsFunc(strings.ToLower(ask.Message), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		if copilotSupportTerms[word] {
+			support++
+		}
+		if copilotProspectingTerms[word] {
+			prospecting++
+		}
+	}
+	switch {
+	case support > prospecting:
+		return CopilotAgentSupport, nil
+	case prospecting > support:
+		return CopilotAgentProspecting, nil
+	default:
+		return "", ErrCopilotAgentUnresolved
+	}
+}
+
+func copilotTriggerType(conversation *agent.CopilotConversation) string {
+	if conversation != nil {
+		return agent.TriggerTypeCopilot
+	}
+	return agent.TriggerTypeManual
+}
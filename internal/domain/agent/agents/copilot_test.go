@@ -0,0 +1,37 @@
+package agents
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResolveCopilotAgent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		ask     CopilotAsk
+		want    string
+		wantErr error
+	}{
+		{name: "explicit type wins", ask: CopilotAsk{AgentType: CopilotAgentProspecting, CaseID: "case-1"}, want: CopilotAgentProspecting},
+		{name: "unknown type", ask: CopilotAsk{AgentType: "insights"}, wantErr: ErrInvalidCopilotAgentType},
+		{name: "case id", ask: CopilotAsk{CaseID: "case-1", Message: "draft outreach"}, want: CopilotAgentSupport},
+		{name: "lead id", ask: CopilotAsk{LeadID: "lead-1"}, want: CopilotAgentProspecting},
+		{name: "support wording", ask: CopilotAsk{Message: "The customer reports an error on the ticket"}, want: CopilotAgentSupport},
+		{name: "prospecting wording", ask: CopilotAsk{Message: "¿Cómo contactar a este prospecto?"}, want: CopilotAgentProspecting},
+		{name: "ambiguous", ask: CopilotAsk{Message: "what should I do next?"}, wantErr: ErrCopilotAgentUnresolved},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ResolveCopilotAgent(tt.ask)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v; want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("agent = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
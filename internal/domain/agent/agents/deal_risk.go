@@ -91,7 +91,7 @@ func NewDealRiskAgent(
 }
 
 func (a *DealRiskAgent) AllowedTools() []string {
-	return []string{"search_knowledge", "create_task", "get_deal", "get_account"}
+	return []string{tool.SearchKnowledge, "create_task", "get_deal", "get_account"}
 }
 
 func (a *DealRiskAgent) Objective() json.RawMessage {
@@ -129,7 +129,8 @@ func (a *DealRiskAgent) Run(ctx context.Context, config DealRiskAgentConfig) (*a
 		return nil, fmt.Errorf("trigger deal risk run: %w", err)
 	}
 
-	result, err := a.executeDealRiskFlow(ctx, normalized)
+	trace := agent.NewReasoningTracer(a.orchestrator, run.WorkspaceID, run.ID)
+	result, err := a.executeDealRiskFlow(ctx, normalized, trace)
 	if err != nil {
 		if updateErr := a.markRunFailed(ctx, run); updateErr != nil {
 			return run, updateErr
@@ -175,7 +176,11 @@ func (a *DealRiskAgent) markRunFailed(ctx context.Context, run *agent.Run) error
 	return nil
 }
 
-func (a *DealRiskAgent) executeDealRiskFlow(ctx context.Context, config DealRiskAgentConfig) (*DealRiskResult, error) {
+func (a *DealRiskAgent) executeDealRiskFlow(
+	ctx context.Context,
+	config DealRiskAgentConfig,
+	trace *agent.ReasoningTracer,
+) (*DealRiskResult, error) {
 	start := time.Now()
 	totalTokens := int64(0)
 	totalCost := dealRiskBaseRunCost
@@ -199,29 +204,33 @@ func (a *DealRiskAgent) executeDealRiskFlow(ctx context.Context, config DealRisk
 		return nil, err
 	}
 
+	trace.Record(ctx, "load_deal", "Loaded deal and account context", map[string]any{
+		"deal_status": deal.Status,
+		"stage_id":    deal.StageID,
+	})
+
 	query := fmt.Sprintf("deal title=%s account=%s stage=%s status=%s", deal.Title, account.Name, deal.StageID, deal.Status)
 	evidence := a.searchSignals(toolCtx, config.WorkspaceID, query)
 	signals := evaluateDealRisk(deal, account, evidence)
+	trace.Record(ctx, "evaluate_deal_risk", "Evaluated deal risk signals", map[string]any{
+		"risk_level": signals.RiskLevel,
+		"signals":    signals,
+	})
 	status, output, toolCalls, err := a.resolveDealRiskOutcome(toolCtx, config, deal, account, evidence, signals, query)
 	if err != nil {
 		return nil, err
 	}
+	trace.Record(ctx, "decide", "Resolved deal risk action: "+status, map[string]any{"status": status})
 
 	outputJSON, _ := json.Marshal(output)
 	toolCallsJSON, _ := json.Marshal(toolCalls)
-	reasoningTrace, _ := json.Marshal([]map[string]any{{
-		"step":       "evaluate_deal_risk",
-		"risk_level": signals.RiskLevel,
-		"signals":    signals,
-		"timestamp":  time.Now().UTC().Format(time.RFC3339),
-	}})
 	latency := time.Since(start).Milliseconds()
 
 	return &DealRiskResult{
 		Status:         status,
 		Output:         outputJSON,
 		ToolCalls:      toolCallsJSON,
-		ReasoningTrace: reasoningTrace,
+		ReasoningTrace: trace.Trace(),
 		TotalTokens:    &totalTokens,
 		TotalCost:      &totalCost,
 		LatencyMs:      &latency,
@@ -368,7 +377,7 @@ func baseDealRiskToolCalls(dealID, accountID, query string) []map[string]any {
 			"executed_at": time.Now().UTC().Format(time.RFC3339),
 		},
 		{
-			"tool_name": "search_knowledge",
+			"tool_name": tool.SearchKnowledge,
 			"params": map[string]any{
 				"query": query,
 				"limit": dealRiskEvidenceLimit,
@@ -41,6 +41,8 @@ func (m *mockDealToolExecutor) Execute(ctx context.Context, params json.RawMessa
 	return mustJSON(map[string]any{"deal": deal}), nil
 }
 
+func (*mockDealToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
 func insertDealRiskAgentDefinition(t *testing.T, db *sql.DB, workspaceID string) {
 	t.Helper()
 	ensureAgentTestWorkspace(t, db, workspaceID)
@@ -62,7 +64,7 @@ func newTestDealRiskAgent(
 	t.Helper()
 	orch := agent.NewOrchestrator(db)
 	registry := tool.NewToolRegistry(db)
-	if err := registry.Register(tool.BuiltinCreateTask, tool.NewCreateTaskExecutor(db)); err != nil {
+	if err := registry.Register(tool.BuiltinCreateTask, tool.NewCreateTaskExecutor(crm.NewActivityService(db))); err != nil {
 		t.Fatalf("register create_task: %v", err)
 	}
 	if err := registry.Register(tool.BuiltinGetDeal, &mockDealToolExecutor{getter: dealGetter}); err != nil {
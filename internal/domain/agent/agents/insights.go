@@ -29,6 +29,7 @@ type InsightsAgentConfig struct {
 
 const insightsBaseRunCostEuros = 0.01 // Task 4.5d — sin LLM en MVP.
 const metricSalesFunnel = "sales_funnel"
+const metricWinRate = "win_rate"
 const insightsDefaultLanguage = "es"
 
 // InsightsAgent implements FR-231 insights flow.
@@ -56,7 +57,7 @@ func NewInsightsAgent(
 
 // AllowedTools returns tools allowed to the insights agent.
 func (a *InsightsAgent) AllowedTools() []string {
-	return []string{"search_knowledge", "query_metrics"}
+	return []string{tool.SearchKnowledge, "query_metrics"}
 }
 
 // Objective returns objective payload used by the runtime.
@@ -175,7 +176,7 @@ func (a *InsightsAgent) executeInsightsFlow(
 	output := a.resolveInsightsOutput(metric, config.Query, metricsData, searchResults, topScore)
 	calls := []map[string]any{
 		{"tool_name": "query_metrics", "metric": metric, "executed_at": time.Now().UTC().Format(time.RFC3339)},
-		{"tool_name": "search_knowledge", "limit": 3, "executed_at": time.Now().UTC().Format(time.RFC3339)},
+		{"tool_name": tool.SearchKnowledge, "limit": 3, "executed_at": time.Now().UTC().Format(time.RFC3339)},
 	}
 	outputJSON, _ := json.Marshal(output)
 	toolCalls, _ := json.Marshal(calls)
@@ -202,6 +203,8 @@ func parseQueryIntent(query string) string {
 		return "case_volume"
 	case strings.Contains(q, "mttr"), strings.Contains(q, "resolución"), strings.Contains(q, "tiempo"):
 		return "mttr"
+	case strings.Contains(q, "win rate"), strings.Contains(q, "tasa de cierre"), strings.Contains(q, "ganad"), strings.Contains(q, "perdid"):
+		return metricWinRate
 	case strings.Contains(q, "deal"), strings.Contains(q, "venta"), strings.Contains(q, "funnel"):
 		return metricSalesFunnel
 	default:
@@ -255,6 +255,7 @@ func TestParseQueryIntent_AdditionalBranches(t *testing.T) {
 		{query: "aging de deals", want: "deal_aging"},
 		{query: "tiempo de resolución mttr", want: "mttr"},
 		{query: "ventas del funnel", want: "sales_funnel"},
+		{query: "deals ganados vs perdidos", want: "win_rate"},
 		{query: "consulta desconocida", want: "sales_funnel"},
 	}
 
@@ -66,7 +66,7 @@ func NewKBAgent(
 
 // AllowedTools returns tools allowed to the KB agent.
 func (a *KBAgent) AllowedTools() []string {
-	return []string{"search_knowledge", "create_knowledge_item", "update_knowledge_item"}
+	return []string{tool.SearchKnowledge, "create_knowledge_item", "update_knowledge_item"}
 }
 
 // Objective returns objective payload used by the runtime.
@@ -41,6 +41,8 @@ func (m *mockToolExecutor) Execute(_ context.Context, _ json.RawMessage) (json.R
 	return m.out, nil
 }
 
+func (*mockToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
 func insertKBAgentDefinition(t *testing.T, db *sql.DB, workspaceID string) {
 	t.Helper()
 	ensureAgentTestWorkspace(t, db, workspaceID)
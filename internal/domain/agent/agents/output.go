@@ -0,0 +1,151 @@
+package agents
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+)
+
+// Actions reported in ProspectingOutput.Action.
+const (
+	prospectingActionDraftOutreach   = "draft_outreach"
+	prospectingActionSkip            = "skip"
+	prospectingActionPendingApproval = "pending_approval"
+	prospectingActionConvert         = "convert"
+)
+
+var (
+	errOutputActionInvalid     = errors.New("unknown action")
+	errOutputLeadIDMissing     = errors.New("lead_id is required")
+	errOutputCaseIDMissing     = errors.New("CaseID is required")
+	errOutputConfidenceRange   = errors.New("confidence out of range")
+	errOutputDetailsMissing    = errors.New("details are required")
+	errOutputApprovalIDMissing = errors.New("approval id is required")
+	errOutputDealIDMissing     = errors.New("deal_id is required")
+)
+
+// ProspectingOutput is the agent_run.output of a prospecting run.
+type ProspectingOutput struct {
+	Action string `json:"action"`
+	// Reason explains skip and pending_approval actions.
+	Reason     string  `json:"reason,omitempty"`
+	LeadID     string  `json:"lead_id"`
+	Confidence float64 `json:"confidence"` // 0-1
+	// ApprovalID is set with action pending_approval.
+	ApprovalID string `json:"approval_id,omitempty"`
+	// Details is set with action draft_outreach.
+	Details *ProspectingOutputDetails `json:"details,omitempty"`
+	// DealID is set with action convert.
+	DealID string `json:"deal_id,omitempty"`
+}
+
+// ProspectingOutputDetails holds what a draft_outreach action produced.
+type ProspectingOutputDetails struct {
+	Draft  string `json:"draft"`
+	TaskID string `json:"task_id"`
+	NoteID string `json:"note_id"`
+}
+
+// Validate reports whether o is a well-formed prospecting output.
+func (o ProspectingOutput) Validate() error {
+	switch {
+	case o.LeadID == "":
+		return errOutputLeadIDMissing
+	case o.Confidence < 0 || o.Confidence > 1:
+		return fmt.Errorf("%w: %v", errOutputConfidenceRange, o.Confidence)
+	}
+	switch o.Action {
+	case prospectingActionSkip:
+		return nil
+	case prospectingActionDraftOutreach:
+		if o.Details == nil {
+			return errOutputDetailsMissing
+		}
+		return nil
+	case prospectingActionPendingApproval:
+		if o.ApprovalID == "" {
+			return errOutputApprovalIDMissing
+		}
+		return nil
+	case prospectingActionConvert:
+		if o.DealID == "" {
+			return errOutputDealIDMissing
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", errOutputActionInvalid, o.Action)
+	}
+}
+
+// SupportOutput is the agent_run.output of a support run. Its keys keep the
+// Go field names the support agent has always persisted.
+type SupportOutput struct {
+	Type          string   `json:"Type"`
+	Details       string   `json:"Details"`
+	CaseID        string   `json:"CaseID"`
+	Status        string   `json:"Status"`
+	Confidence    int      `json:"Confidence"` // 0-100
+	NextSteps     []string `json:"NextSteps"`
+	ApprovalID    string   `json:"ApprovalID"`
+	Metadata      string   `json:"Metadata"`
+	ReplyToNoteID string   `json:"ReplyToNoteID"`
+}
+
+// Validate reports whether o is a well-formed support output.
+func (o SupportOutput) Validate() error {
+	switch {
+	case o.CaseID == "":
+		return errOutputCaseIDMissing
+	case o.Confidence < 0 || o.Confidence > 100:
+		return fmt.Errorf("%w: %d", errOutputConfidenceRange, o.Confidence)
+	}
+	switch o.Type {
+	case supportActionUpdateCase, supportActionAbstain, supportActionEscalate:
+		return nil
+	case supportPendingApprovalAction:
+		if o.ApprovalID == "" {
+			return errOutputApprovalIDMissing
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", errOutputActionInvalid, o.Type)
+	}
+}
+
+// ValidateProspectingOutput is the agent.OutputValidator of prospecting runs.
+func ValidateProspectingOutput(raw json.RawMessage) error {
+	var out ProspectingOutput
+	if err := decodeStrict(raw, &out); err != nil {
+		return err
+	}
+	return out.Validate()
+}
+
+// ValidateSupportOutput is the agent.OutputValidator of support runs.
+func ValidateSupportOutput(raw json.RawMessage) error {
+	var out SupportOutput
+	if err := decodeStrict(raw, &out); err != nil {
+		return err
+	}
+	return out.Validate()
+}
+
+// RegisterOutputValidators makes orchestrator validate the output of the Go
+// agents with a typed output before persisting it.
+func RegisterOutputValidators(orchestrator *agent.Orchestrator) {
+	orchestrator.SetOutputValidator(AgentTypeProspecting, ValidateProspectingOutput)
+	orchestrator.SetOutputValidator(AgentTypeSupport, ValidateSupportOutput)
+}
+
+// decodeStrict decodes raw into v, rejecting keys v does not declare.
+func decodeStrict(raw json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("decode output: %w", err)
+	}
+	return nil
+}
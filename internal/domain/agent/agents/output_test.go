@@ -0,0 +1,90 @@
+package agents
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestProspectingOutput_SerializedShape(t *testing.T) {
+	t.Parallel()
+
+	raw, err := json.Marshal(ProspectingOutput{
+		Action:     prospectingActionDraftOutreach,
+		LeadID:     "lead-1",
+		Confidence: 0.8,
+		Details:    &ProspectingOutputDetails{Draft: "Hi", TaskID: "task-1", NoteID: "note-1"},
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var got map[string]any
+	if err = json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := map[string]any{
+		"action":     "draft_outreach",
+		"lead_id":    "lead-1",
+		"confidence": 0.8,
+		"details":    map[string]any{"draft": "Hi", "task_id": "task-1", "note_id": "note-1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("output = %s; want %v", raw, want)
+	}
+	if err = ValidateProspectingOutput(raw); err != nil {
+		t.Fatalf("ValidateProspectingOutput() error = %v", err)
+	}
+
+	skip, _ := json.Marshal(ProspectingOutput{Action: prospectingActionSkip, Reason: "insufficient_signals", LeadID: "lead-1", Confidence: 0.2})
+	if string(skip) != `{"action":"skip","reason":"insufficient_signals","lead_id":"lead-1","confidence":0.2}` {
+		t.Fatalf("skip output = %s", skip)
+	}
+}
+
+func TestValidateProspectingOutput_RejectsMalformed(t *testing.T) {
+	t.Parallel()
+
+	for name, raw := range map[string]string{
+		"unknown action":        `{"action":"call","lead_id":"lead-1","confidence":0.9}`,
+		"missing lead":          `{"action":"skip","confidence":0.1}`,
+		"confidence above 1":    `{"action":"skip","lead_id":"lead-1","confidence":1.5}`,
+		"draft without details": `{"action":"draft_outreach","lead_id":"lead-1","confidence":0.9}`,
+		"approval without id":   `{"action":"pending_approval","lead_id":"lead-1","confidence":0.9}`,
+		"unknown key":           `{"action":"skip","lead_id":"lead-1","confidence":0.1,"extra":1}`,
+		"wrong type":            `{"action":"skip","lead_id":"lead-1","confidence":"high"}`,
+	} {
+		if err := ValidateProspectingOutput(json.RawMessage(raw)); err == nil {
+			t.Errorf("%s: ValidateProspectingOutput() = nil; want error", name)
+		}
+	}
+}
+
+func TestSupportOutput_SerializedShape(t *testing.T) {
+	t.Parallel()
+
+	action := &Action{Type: supportActionUpdateCase, Details: "Resolved", CaseID: "case-1", Status: "resolved", Confidence: 90, NextSteps: []string{"close"}}
+	raw, err := action.toJSON()
+	if err != nil {
+		t.Fatalf("toJSON() error = %v", err)
+	}
+	want := `{"Type":"update_case","Details":"Resolved","CaseID":"case-1","Status":"resolved","Confidence":90,"NextSteps":["close"],"ApprovalID":"","Metadata":"","ReplyToNoteID":""}`
+	if string(raw) != want {
+		t.Fatalf("output = %s; want %s", raw, want)
+	}
+	if err = ValidateSupportOutput(raw); err != nil {
+		t.Fatalf("ValidateSupportOutput() error = %v", err)
+	}
+
+	if _, err = (&Action{Type: supportPendingApprovalAction, CaseID: "case-1"}).toJSON(); err == nil {
+		t.Fatal("toJSON(pending approval without id) = nil error; want error")
+	}
+	for name, raw := range map[string]string{
+		"unknown type":       `{"Type":"refund","CaseID":"case-1"}`,
+		"missing case":       `{"Type":"abstain"}`,
+		"confidence too big": `{"Type":"abstain","CaseID":"case-1","Confidence":101}`,
+	} {
+		if err = ValidateSupportOutput(json.RawMessage(raw)); err == nil {
+			t.Errorf("%s: ValidateSupportOutput() = nil; want error", name)
+		}
+	}
+}
@@ -25,10 +25,19 @@ type ProspectingAgentConfig struct {
 	LeadID            string  `json:"lead_id"`
 	Language          string  `json:"language,omitempty"`
 	TriggeredByUserID *string `json:"-"`
+	// Copilot, when set, triggers the run as TriggerTypeCopilot from this conversation.
+	Copilot *agent.CopilotConversation `json:"-"`
 }
 
 const baseRunCostEuros = 0.05
 
+const (
+	leadStatusNew       = "new"
+	leadStatusContacted = "contacted"
+	leadStatusQualified = "qualified"
+	leadStatusConverted = "converted"
+)
+
 // LeadGetter abstracts lead retrieval for testability.
 type LeadGetter interface {
 	Get(ctx context.Context, workspaceID, leadID string) (*crm.Lead, error)
@@ -71,9 +80,14 @@ func NewProspectingAgent(
 	}
 }
 
+// Orchestrator returns the orchestrator that records the prospecting agent's runs.
+func (a *ProspectingAgent) Orchestrator() *agent.Orchestrator {
+	return a.orchestrator
+}
+
 // AllowedTools returns tools allowed to the prospecting agent.
 func (a *ProspectingAgent) AllowedTools() []string {
-	return []string{"search_knowledge", "create_task", "get_lead", "get_account"}
+	return []string{tool.SearchKnowledge, "create_task", "get_lead", "get_account", tool.BuiltinUpdateLead, tool.BuiltinCreateNote, tool.BuiltinCreateDeal}
 }
 
 // Objective returns objective payload used by the runtime.
@@ -84,11 +98,12 @@ func (a *ProspectingAgent) Objective() json.RawMessage {
 		"instructions": []string{
 			"1. Retrieve lead and account context",
 			"2. Search prior signals in knowledge",
-			"3. If confidence > 0.6 draft personalized outreach and create follow-up task",
-			"4. If confidence <= 0.6 skip with reason insufficient_signals",
+			"3. If confidence > 0.6 draft personalized outreach, save it as an internal account note and create follow-up task",
+			"4. If confidence <= 0.6 skip with reason " + string(agent.AbstentionInsufficientSignals),
+			"5. If the lead is qualified, convert it with create_deal in the first stage of the deal pipeline instead of drafting outreach",
 		},
 		"response_format": map[string]string{
-			"action":     "draft_outreach|skip",
+			"action":     "draft_outreach|convert|skip",
 			"details":    "action details",
 			"lead_id":    "lead identifier",
 			"confidence": "0-1",
@@ -117,17 +132,20 @@ func (a *ProspectingAgent) Run(ctx context.Context, config ProspectingAgentConfi
 		AgentID:        "prospecting-agent",
 		WorkspaceID:    normalized.WorkspaceID,
 		TriggeredBy:    normalized.TriggeredByUserID,
-		TriggerType:    agent.TriggerTypeManual,
+		TriggerType:    copilotTriggerType(normalized.Copilot),
 		TriggerContext: triggerContext,
 		Inputs:         inputs,
+		Conversation:   normalized.Copilot,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("trigger prospecting run: %w", err)
 	}
 
-	result, err := a.executeProspectingFlow(ctx, normalized)
+	ctx = guardAgentRun(ctx, a.orchestrator, run, a.AllowedTools())
+	trace := agent.NewReasoningTracer(a.orchestrator, run.WorkspaceID, run.ID)
+	result, err := a.executeProspectingFlow(ctx, normalized, trace)
 	if err != nil {
-		updateErr := a.markRunFailed(ctx, run)
+		updateErr := a.markRunFailed(ctx, run, err)
 		if updateErr != nil {
 			return run, updateErr
 		}
@@ -135,14 +153,15 @@ func (a *ProspectingAgent) Run(ctx context.Context, config ProspectingAgentConfi
 	}
 
 	_, err = a.orchestrator.UpdateAgentRun(ctx, run.WorkspaceID, run.ID, agent.RunUpdates{
-		Status:         result.Status,
-		Output:         result.Output,
-		ToolCalls:      result.ToolCalls,
-		ReasoningTrace: result.ReasoningTrace,
-		TotalTokens:    result.TotalTokens,
-		TotalCost:      result.TotalCost,
-		LatencyMs:      result.LatencyMs,
-		Completed:      true,
+		Status:           result.Status,
+		Output:           result.Output,
+		ToolCalls:        result.ToolCalls,
+		ReasoningTrace:   result.ReasoningTrace,
+		TotalTokens:      result.TotalTokens,
+		TotalCost:        result.TotalCost,
+		LatencyMs:        result.LatencyMs,
+		AbstentionReason: result.AbstentionReason,
+		Completed:        true,
 	})
 	if err != nil {
 		return run, fmt.Errorf("complete prospecting run: %w", err)
@@ -164,9 +183,8 @@ func (a *ProspectingAgent) normalizeConfig(ctx context.Context, config Prospecti
 	return config, nil
 }
 
-func (a *ProspectingAgent) markRunFailed(ctx context.Context, run *agent.Run) error {
-	_, err := a.orchestrator.UpdateAgentRunStatus(ctx, run.WorkspaceID, run.ID, agent.StatusFailed)
-	if err != nil {
+func (a *ProspectingAgent) markRunFailed(ctx context.Context, run *agent.Run, cause error) error {
+	if err := markAgentRunFailed(ctx, a.orchestrator, run, cause); err != nil {
 		return fmt.Errorf("mark prospecting run failed: %w", err)
 	}
 	return nil
@@ -181,14 +199,23 @@ type ProspectingResult struct {
 	TotalTokens    *int64
 	TotalCost      *float64
 	LatencyMs      *int64
+	// AbstentionReason is set when the lead is skipped.
+	AbstentionReason *string
 }
 
-func (a *ProspectingAgent) executeProspectingFlow(ctx context.Context, config ProspectingAgentConfig) (*ProspectingResult, error) {
+func (a *ProspectingAgent) executeProspectingFlow(
+	ctx context.Context,
+	config ProspectingAgentConfig,
+	trace *agent.ReasoningTracer,
+) (*ProspectingResult, error) {
 	startTime := time.Now()
 	totalTokens := int64(0)
 	totalCost := baseRunCostEuros // Task 4.5b — baseline non-LLM run cost tracking.
 
 	toolCtx := context.WithValue(ctx, ctxkeys.WorkspaceID, config.WorkspaceID)
+	if err := validateAgentPlan(toolCtx, a.toolRegistry, config.WorkspaceID, prospectingToolPlan); err != nil {
+		return nil, err
+	}
 
 	lead, err := a.fetchLead(toolCtx, config)
 	if errors.Is(err, sql.ErrNoRows) {
@@ -201,14 +228,23 @@ func (a *ProspectingAgent) executeProspectingFlow(ctx context.Context, config Pr
 	toolCtx = context.WithValue(toolCtx, ctxkeys.UserID, lead.OwnerID)
 
 	accountName := a.resolveAccountName(toolCtx, lead)
+	trace.Record(ctx, "load_lead", "Loaded lead and account context", map[string]any{
+		"lead_status":  lead.Status,
+		"account_name": accountName,
+	})
 
 	query := fmt.Sprintf("lead source=%s account=%s status=%s", safePtr(lead.Source), accountName, lead.Status)
-	evidence := a.searchSignals(toolCtx, config.WorkspaceID, query)
+	evidence := a.searchSignals(toolCtx, config.WorkspaceID, query, config.Language)
 
 	confidence := 0.0
 	if len(evidence.Items) > 0 {
 		confidence = evidence.Items[0].Score
 	}
+	trace.Record(ctx, "evaluate_signals", "Searched knowledge for lead signals", map[string]any{
+		"query":      query,
+		"results":    len(evidence.Items),
+		"confidence": confidence,
+	})
 
 	toolCalls := baseProspectingToolCalls(config.LeadID, lead.AccountID, query)
 	status, out, nextToolCalls, tokens, cost, flowErr := a.resolveAction(ctx, toolCtx, config, lead, accountName, confidence)
@@ -218,25 +254,32 @@ func (a *ProspectingAgent) executeProspectingFlow(ctx context.Context, config Pr
 	toolCalls = append(toolCalls, nextToolCalls...)
 	totalTokens += tokens
 	totalCost += cost
+	trace.Record(ctx, "decide", "Resolved prospecting action: "+status, map[string]any{
+		"status": status,
+		"tokens": tokens,
+	})
 
+	if err = out.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", agent.ErrInvalidRunOutput, err)
+	}
 	outputJSON, _ := json.Marshal(out)
 	toolCallsJSON, _ := json.Marshal(toolCalls)
-	reasoningTrace, _ := json.Marshal([]map[string]any{{
-		"step":       "evaluate_signals",
-		"results":    len(evidence.Items),
-		"confidence": confidence,
-		"timestamp":  time.Now().UTC().Format(time.RFC3339),
-	}})
 	latency := time.Since(startTime).Milliseconds()
 
+	var abstentionReason *string
+	if out.Action == prospectingActionSkip {
+		abstentionReason = agent.AbstentionInsufficientSignals.Ptr()
+	}
+
 	return &ProspectingResult{
-		Status:         status,
-		Output:         outputJSON,
-		ToolCalls:      toolCallsJSON,
-		ReasoningTrace: reasoningTrace,
-		TotalTokens:    &totalTokens,
-		TotalCost:      &totalCost,
-		LatencyMs:      &latency,
+		Status:           status,
+		Output:           outputJSON,
+		ToolCalls:        toolCallsJSON,
+		ReasoningTrace:   trace.Trace(),
+		TotalTokens:      &totalTokens,
+		TotalCost:        &totalCost,
+		LatencyMs:        &latency,
+		AbstentionReason: abstentionReason,
 	}, nil
 }
 
@@ -251,10 +294,11 @@ func (a *ProspectingAgent) resolveAccountName(ctx context.Context, lead *crm.Lea
 	return acc.Name
 }
 
-func (a *ProspectingAgent) searchSignals(ctx context.Context, workspaceID, query string) *knowledge.SearchResults {
+func (a *ProspectingAgent) searchSignals(ctx context.Context, workspaceID, query, language string) *knowledge.SearchResults {
 	evidence, err := a.knowledgeSearch.HybridSearch(ctx, knowledge.SearchInput{
 		WorkspaceID: workspaceID,
 		Query:       query,
+		Language:    language,
 		Limit:       5,
 	})
 	if err != nil {
@@ -263,6 +307,16 @@ func (a *ProspectingAgent) searchSignals(ctx context.Context, workspaceID, query
 	return evidence
 }
 
+// prospectingToolPlan is every tool executeProspectingFlow may call.
+var prospectingToolPlan = []string{
+	tool.BuiltinGetLead,
+	tool.BuiltinGetAccount,
+	tool.BuiltinCreateTask,
+	tool.BuiltinCreateNote,
+	tool.BuiltinUpdateLead,
+	tool.BuiltinCreateDeal,
+}
+
 func baseProspectingToolCalls(leadID string, accountID *string, query string) []map[string]any {
 	toolCalls := []map[string]any{{
 		"tool_name":   "get_lead",
@@ -277,7 +331,7 @@ func baseProspectingToolCalls(leadID string, accountID *string, query string) []
 		})
 	}
 	toolCalls = append(toolCalls, map[string]any{
-		"tool_name": "search_knowledge",
+		"tool_name": tool.SearchKnowledge,
 		"params": map[string]any{
 			"query": query,
 			"limit": 5,
@@ -294,38 +348,52 @@ func (a *ProspectingAgent) resolveAction(
 	lead *crm.Lead,
 	accountName string,
 	confidence float64,
-) (string, map[string]any, []map[string]any, int64, float64, error) {
+) (string, ProspectingOutput, []map[string]any, int64, float64, error) {
 	if confidence <= 0.6 {
-		return agent.StatusSuccess, map[string]any{
-			"action":     "skip",
-			"reason":     "insufficient_signals",
-			"lead_id":    lead.ID,
-			"confidence": confidence,
+		return agent.StatusSuccess, ProspectingOutput{
+			Action:     prospectingActionSkip,
+			Reason:     string(agent.AbstentionInsufficientSignals),
+			LeadID:     lead.ID,
+			Confidence: confidence,
 		}, nil, 0, 0, nil
 	}
 
 	if isHighSensitivityMetadata(lead.Metadata) {
 		approvalID, err := a.requestProspectingApproval(toolCtx, config.WorkspaceID, lead, accountName, confidence)
 		if err != nil {
-			return "", nil, nil, 0, 0, err
+			return "", ProspectingOutput{}, nil, 0, 0, err
 		}
-		return agent.StatusEscalated, map[string]any{
-			"action":      "pending_approval",
-			"reason":      "high_sensitivity",
-			"lead_id":     lead.ID,
-			"confidence":  confidence,
-			"approval_id": approvalID,
+		return agent.StatusEscalated, ProspectingOutput{
+			Action:     prospectingActionPendingApproval,
+			Reason:     sensitivityHighReason,
+			LeadID:     lead.ID,
+			Confidence: confidence,
+			ApprovalID: approvalID,
 		}, []map[string]any{{"tool_name": "approval.requested"}}, 0, 0, nil
 	}
 
+	if lead.Status == leadStatusQualified && lead.AccountID != nil && *lead.AccountID != "" {
+		pipelineID, stageID, found, err := a.firstDealStage(ctx, config.WorkspaceID)
+		if err != nil {
+			return "", ProspectingOutput{}, nil, 0, 0, err
+		}
+		if found {
+			out, toolCalls, convertErr := a.convertLead(toolCtx, lead, accountName, pipelineID, stageID, confidence)
+			if convertErr != nil {
+				return "", ProspectingOutput{}, nil, 0, 0, convertErr
+			}
+			return agent.StatusSuccess, out, toolCalls, 0, 0, nil
+		}
+	}
+
 	draft, usedTokens, draftCost, draftErr := a.generateDraft(ctx, config.Language, lead, accountName)
 	if draftErr != nil {
-		return "", nil, nil, 0, 0, draftErr
+		return "", ProspectingOutput{}, nil, 0, 0, draftErr
 	}
 
 	taskID, createTaskErr := a.createFollowUpTask(toolCtx, lead)
 	if createTaskErr != nil {
-		return "", nil, nil, 0, 0, createTaskErr
+		return "", ProspectingOutput{}, nil, 0, 0, createTaskErr
 	}
 
 	createTaskCall := map[string]any{
@@ -338,14 +406,106 @@ func (a *ProspectingAgent) resolveAction(
 		},
 		"executed_at": time.Now().UTC().Format(time.RFC3339),
 	}
+	toolCalls := []map[string]any{createTaskCall}
 
-	out := map[string]any{
-		"action":     "draft_outreach",
-		"details":    map[string]any{"draft": draft, "task_id": taskID},
-		"lead_id":    lead.ID,
-		"confidence": confidence,
+	noteID, noteCall, noteErr := a.saveDraftNote(toolCtx, lead, draft)
+	if noteErr != nil {
+		return "", ProspectingOutput{}, nil, 0, 0, noteErr
+	}
+	toolCalls = append(toolCalls, noteCall)
+
+	markCall, markErr := a.markLeadContacted(toolCtx, lead)
+	if markErr != nil {
+		return "", ProspectingOutput{}, nil, 0, 0, markErr
+	}
+	if markCall != nil {
+		toolCalls = append(toolCalls, markCall)
+	}
+
+	out := ProspectingOutput{
+		Action:     prospectingActionDraftOutreach,
+		Details:    &ProspectingOutputDetails{Draft: draft, TaskID: taskID, NoteID: noteID},
+		LeadID:     lead.ID,
+		Confidence: confidence,
+	}
+	return agent.StatusSuccess, out, toolCalls, usedTokens, draftCost + 0.15, nil
+}
+
+// firstDealStage returns the lowest stage of the workspace's oldest deal
+// pipeline, where converted leads start. found is false when the workspace
+// has no deal pipeline with stages.
+func (a *ProspectingAgent) firstDealStage(ctx context.Context, workspaceID string) (pipelineID, stageID string, found bool, err error) {
+	if a.db == nil {
+		return "", "", false, nil
+	}
+	err = a.db.QueryRowContext(ctx, `
+		SELECT ps.pipeline_id, ps.id
+		FROM pipeline_stage ps
+		JOIN pipeline p ON p.id = ps.pipeline_id
+		WHERE p.workspace_id = ? AND p.entity_type = 'deal'
+		ORDER BY p.created_at, p.id, ps.position, ps.id
+		LIMIT 1`, workspaceID).Scan(&pipelineID, &stageID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", "", false, nil
+	}
+	if err != nil {
+		return "", "", false, fmt.Errorf("find deal pipeline stage: %w", err)
+	}
+	return pipelineID, stageID, true, nil
+}
+
+// convertLead opens a deal for a qualified lead's account with create_deal
+// and marks the lead converted, returning the tool calls to record.
+func (a *ProspectingAgent) convertLead(
+	ctx context.Context,
+	lead *crm.Lead,
+	accountName, pipelineID, stageID string,
+	confidence float64,
+) (ProspectingOutput, []map[string]any, error) {
+	params := map[string]any{
+		"account_id":  *lead.AccountID,
+		"pipeline_id": pipelineID,
+		"stage_id":    stageID,
+		"owner_id":    lead.OwnerID,
+	}
+	if accountName != "" {
+		params["title"] = accountName
+	}
+	raw, err := executeGuardedTool(ctx, a.toolRegistry, workspaceFromCtx(ctx), tool.BuiltinCreateDeal, mustJSON(params))
+	if err != nil {
+		return ProspectingOutput{}, nil, fmt.Errorf("convert lead: %w", err)
+	}
+	var parsed struct {
+		DealID string `json:"deal_id"`
+	}
+	if unmarshalErr := json.Unmarshal(raw, &parsed); unmarshalErr != nil {
+		return ProspectingOutput{}, nil, fmt.Errorf("decode converted deal: %w", unmarshalErr)
+	}
+	if parsed.DealID == "" {
+		return ProspectingOutput{}, nil, ErrDealCreationFailed
 	}
-	return agent.StatusSuccess, out, []map[string]any{createTaskCall}, usedTokens, draftCost + 0.15, nil
+	toolCalls := []map[string]any{{
+		"tool_name":   tool.BuiltinCreateDeal,
+		"params":      params,
+		"executed_at": time.Now().UTC().Format(time.RFC3339),
+	}}
+
+	leadParams := map[string]any{"lead_id": lead.ID, "status": leadStatusConverted}
+	if _, err = executeGuardedTool(ctx, a.toolRegistry, workspaceFromCtx(ctx), tool.BuiltinUpdateLead, mustJSON(leadParams)); err != nil {
+		return ProspectingOutput{}, nil, fmt.Errorf("mark lead converted: %w", err)
+	}
+	toolCalls = append(toolCalls, map[string]any{
+		"tool_name":   tool.BuiltinUpdateLead,
+		"params":      leadParams,
+		"executed_at": time.Now().UTC().Format(time.RFC3339),
+	})
+
+	return ProspectingOutput{
+		Action:     prospectingActionConvert,
+		LeadID:     lead.ID,
+		Confidence: confidence,
+		DealID:     parsed.DealID,
+	}, toolCalls, nil
 }
 
 func (a *ProspectingAgent) requestProspectingApproval(
@@ -380,7 +540,7 @@ func (a *ProspectingAgent) requestProspectingApproval(
 }
 
 func (a *ProspectingAgent) fetchLead(ctx context.Context, config ProspectingAgentConfig) (*crm.Lead, error) {
-	raw, err := a.toolRegistry.Execute(ctx, config.WorkspaceID, tool.BuiltinGetLead, mustJSON(map[string]any{"lead_id": config.LeadID}))
+	raw, err := executeGuardedTool(ctx, a.toolRegistry, config.WorkspaceID, tool.BuiltinGetLead, mustJSON(map[string]any{"lead_id": config.LeadID}))
 	if err != nil {
 		return nil, fmt.Errorf("get lead: %w", err)
 	}
@@ -398,7 +558,7 @@ func (a *ProspectingAgent) fetchLead(ctx context.Context, config ProspectingAgen
 }
 
 func (a *ProspectingAgent) fetchAccount(ctx context.Context, accountID string) (*crm.Account, error) {
-	raw, err := a.toolRegistry.Execute(ctx, workspaceFromCtx(ctx), tool.BuiltinGetAccount, mustJSON(map[string]any{"account_id": accountID}))
+	raw, err := executeGuardedTool(ctx, a.toolRegistry, workspaceFromCtx(ctx), tool.BuiltinGetAccount, mustJSON(map[string]any{"account_id": accountID}))
 	if err != nil {
 		return nil, fmt.Errorf("get account: %w", err)
 	}
@@ -419,7 +579,7 @@ func (a *ProspectingAgent) createFollowUpTask(ctx context.Context, lead *crm.Lea
 	if lead.AccountID == nil || *lead.AccountID == "" {
 		return "", ErrAccountRequired
 	}
-	raw, err := a.toolRegistry.Execute(ctx, workspaceFromCtx(ctx), tool.BuiltinCreateTask, mustJSON(map[string]any{
+	raw, err := executeGuardedTool(ctx, a.toolRegistry, workspaceFromCtx(ctx), tool.BuiltinCreateTask, mustJSON(map[string]any{
 		"owner_id":    lead.OwnerID,
 		"title":       "Follow-up prospecting",
 		"entity_type": "account",
@@ -441,23 +601,73 @@ func (a *ProspectingAgent) createFollowUpTask(ctx context.Context, lead *crm.Lea
 	return parsed.TaskID, nil
 }
 
+// saveDraftNote attaches the outreach draft to the lead's account as an
+// internal note, so reps find it in the CRM, and returns the tool call to record.
+func (a *ProspectingAgent) saveDraftNote(ctx context.Context, lead *crm.Lead, draft string) (string, map[string]any, error) {
+	params := map[string]any{
+		"author_id":   lead.OwnerID,
+		"content":     draft,
+		"entity_type": "account",
+		"entity_id":   safePtr(lead.AccountID),
+		"is_internal": true,
+	}
+	raw, err := executeGuardedTool(ctx, a.toolRegistry, workspaceFromCtx(ctx), tool.BuiltinCreateNote, mustJSON(params))
+	if err != nil {
+		return "", nil, fmt.Errorf("save outreach draft note: %w", err)
+	}
+	var parsed struct {
+		NoteID string `json:"note_id"`
+	}
+	if unmarshalErr := json.Unmarshal(raw, &parsed); unmarshalErr != nil {
+		return "", nil, fmt.Errorf("decode outreach draft note: %w", unmarshalErr)
+	}
+	return parsed.NoteID, map[string]any{
+		"tool_name":   tool.BuiltinCreateNote,
+		"params":      params,
+		"executed_at": time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// markLeadContacted moves a new lead to contacted once outreach is drafted
+// and returns the tool call to record. Leads past that stage are left alone.
+func (a *ProspectingAgent) markLeadContacted(ctx context.Context, lead *crm.Lead) (map[string]any, error) {
+	if lead.Status != leadStatusNew {
+		return nil, nil
+	}
+	params := map[string]any{"lead_id": lead.ID, "status": leadStatusContacted}
+	if _, err := executeGuardedTool(ctx, a.toolRegistry, workspaceFromCtx(ctx), tool.BuiltinUpdateLead, mustJSON(params)); err != nil {
+		return nil, fmt.Errorf("mark lead contacted: %w", err)
+	}
+	return map[string]any{
+		"tool_name":   tool.BuiltinUpdateLead,
+		"params":      params,
+		"executed_at": time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
 func (a *ProspectingAgent) generateDraft(
 	ctx context.Context,
 	language string,
 	lead *crm.Lead,
 	accountName string,
 ) (string, int64, float64, error) {
-	if a.llmProvider == nil {
+	provider := a.orchestrator.LLMProvider(ctx, lead.WorkspaceID, a.llmProvider)
+	if provider == nil {
 		return "", 0, 0, ErrLLMNotConfigured
 	}
-	resp, err := a.llmProvider.ChatCompletion(ctx, llm.ChatRequest{
+	req := llm.ChatRequest{
 		Messages: []llm.Message{
 			{Role: "system", Content: "Redacta emails de prospección breves, personalizados y profesionales."},
 			{Role: "user", Content: fmt.Sprintf("Idioma: %s. Empresa: %s. Estado lead: %s. Fuente: %s. Redacta un email de outreach de máximo 120 palabras.", language, accountName, lead.Status, safePtr(lead.Source))},
 		},
 		Temperature: 0.2,
 		MaxTokens:   180,
-	})
+	}
+	model := provider.ModelInfo()
+	if err := llm.CheckPromptFits(model.ID, req, model.MaxTokens); err != nil {
+		return "", 0, 0, fmt.Errorf("generate outreach draft: %w", err)
+	}
+	resp, err := provider.ChatCompletion(ctx, req)
 	if err != nil {
 		return "", 0, 0, fmt.Errorf("generate outreach draft: %w", err)
 	}
@@ -467,7 +677,10 @@ func (a *ProspectingAgent) generateDraft(
 	}
 	tokens := int64(resp.Tokens)
 	if tokens == 0 {
-		tokens = int64(len(strings.Fields(content)))
+		tokens = int64(llm.CountMessageTokens(model.ID, req.Messages) + llm.CountTokens(model.ID, content))
+	}
+	if err = agent.RunGuardFromContext(ctx).AddTokens(tokens); err != nil {
+		return "", 0, 0, err
 	}
 	cost := float64(tokens) * 0.0001
 	if cost < 0.1 {
@@ -515,6 +728,7 @@ var (
 	ErrAccountNotFound                   = &ProspectingError{message: "account not found"}
 	ErrAccountRequired                   = &ProspectingError{message: "account_id is required to create follow-up task"}
 	ErrTaskCreationFailed                = &ProspectingError{message: "failed to create follow-up task"}
+	ErrDealCreationFailed                = &ProspectingError{message: "failed to create deal for converted lead"}
 	ErrLLMNotConfigured                  = &ProspectingError{message: "llm provider not configured"}
 	ErrEmptyDraft                        = &ProspectingError{message: "llm returned empty draft"}
 	ErrProspectingDailyLeadLimitExceeded = &ProspectingError{message: "daily lead limit exceeded"}
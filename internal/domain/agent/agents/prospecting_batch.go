@@ -0,0 +1,66 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+const defaultProspectingBatchSize = 10
+
+var ErrLeadQueueUnavailable = &ProspectingError{message: "lead service does not list leads by score"}
+
+// LeadQueue lists leads highest score first. crm.LeadService implements it;
+// batch prospecting requires it of the agent's lead service.
+type LeadQueue interface {
+	ListByScore(ctx context.Context, workspaceID string, input crm.ListLeadsByScoreInput) ([]*crm.Lead, int, error)
+}
+
+// ProspectingBatchConfig configures one batch of prospecting runs.
+type ProspectingBatchConfig struct {
+	WorkspaceID       string
+	Limit             int
+	Language          string
+	TriggeredByUserID *string
+}
+
+// RunBatch prospects up to config.Limit new leads, highest score first, one
+// run per lead. It stops at the first failing run (e.g. a daily limit) and
+// returns the runs made so far with the error.
+func (a *ProspectingAgent) RunBatch(ctx context.Context, config ProspectingBatchConfig) ([]*agent.Run, error) {
+	queue, ok := a.leadService.(LeadQueue)
+	if !ok {
+		return nil, ErrLeadQueueUnavailable
+	}
+	limit := config.Limit
+	if limit <= 0 {
+		limit = defaultProspectingBatchSize
+	}
+
+	leads, _, err := queue.ListByScore(ctx, config.WorkspaceID, crm.ListLeadsByScoreInput{
+		Limit:  limit,
+		Status: leadStatusNew,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list prospecting batch leads: %w", err)
+	}
+
+	runs := make([]*agent.Run, 0, len(leads))
+	for _, lead := range leads {
+		run, runErr := a.Run(ctx, ProspectingAgentConfig{
+			WorkspaceID:       config.WorkspaceID,
+			LeadID:            lead.ID,
+			Language:          config.Language,
+			TriggeredByUserID: config.TriggeredByUserID,
+		})
+		if run != nil {
+			runs = append(runs, run)
+		}
+		if runErr != nil {
+			return runs, runErr
+		}
+	}
+	return runs, nil
+}
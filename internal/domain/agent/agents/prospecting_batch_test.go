@@ -0,0 +1,78 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+)
+
+// mockLeadQueue serves leads by ID and lists them in the given queue order.
+type mockLeadQueue struct {
+	queue     []*crm.Lead
+	requested crm.ListLeadsByScoreInput
+}
+
+func (m *mockLeadQueue) Get(_ context.Context, _, leadID string) (*crm.Lead, error) {
+	for _, lead := range m.queue {
+		if lead.ID == leadID {
+			return lead, nil
+		}
+	}
+	return nil, ErrLeadNotFound
+}
+
+func (m *mockLeadQueue) ListByScore(_ context.Context, _ string, input crm.ListLeadsByScoreInput) ([]*crm.Lead, int, error) {
+	m.requested = input
+	if input.Limit < len(m.queue) {
+		return m.queue[:input.Limit], len(m.queue), nil
+	}
+	return m.queue, len(m.queue), nil
+}
+
+func TestProspectingAgent_RunBatch_ProspectsHighestScoredNewLeadsFirst(t *testing.T) {
+	db := setupProspectingTestDB(t)
+	defer db.Close()
+	insertProspectingAgentDefinition(t, db, "ws-1")
+
+	high, mid, low := 92.0, 61.0, 12.0
+	queue := &mockLeadQueue{queue: []*crm.Lead{
+		{ID: "lead-high", Status: "new", Score: &high},
+		{ID: "lead-mid", Status: "new", Score: &mid},
+		{ID: "lead-low", Status: "new", Score: &low},
+	}}
+	a := newTestProspectingAgent(t, db,
+		&mockKnowledgeSearch{results: &knowledge.SearchResults{Items: []knowledge.SearchResult{{Score: 0.2}}}},
+		&mockLLMProvider{},
+		queue,
+		&mockAccountGetter{},
+	)
+
+	runs, err := a.RunBatch(context.Background(), ProspectingBatchConfig{WorkspaceID: "ws-1", Limit: 2})
+	if err != nil {
+		t.Fatalf("RunBatch: %v", err)
+	}
+	if queue.requested.Status != leadStatusNew || queue.requested.Limit != 2 {
+		t.Fatalf("queue requested %+v; want status new, limit 2", queue.requested)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("runs = %d; want 2", len(runs))
+	}
+	for i, want := range []string{"lead-high", "lead-mid"} {
+		if !contains(string(runs[i].Inputs), want) {
+			t.Fatalf("run %d inputs = %s; want lead %s", i, runs[i].Inputs, want)
+		}
+	}
+}
+
+func TestProspectingAgent_RunBatch_RequiresLeadQueue(t *testing.T) {
+	db := setupProspectingTestDB(t)
+	defer db.Close()
+
+	a := newTestProspectingAgent(t, db, &mockKnowledgeSearch{}, &mockLLMProvider{}, &mockLeadGetter{}, &mockAccountGetter{})
+	if _, err := a.RunBatch(context.Background(), ProspectingBatchConfig{WorkspaceID: "ws-1"}); !errors.Is(err, ErrLeadQueueUnavailable) {
+		t.Fatalf("err = %v; want ErrLeadQueueUnavailable", err)
+	}
+}
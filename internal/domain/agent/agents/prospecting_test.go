@@ -6,11 +6,15 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+	"github.com/matiasleandrokruk/fenix/internal/domain/audit"
 	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
 	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
 	"github.com/matiasleandrokruk/fenix/internal/domain/tool"
@@ -83,6 +87,30 @@ func (m *mockLeadToolExecutor) Execute(ctx context.Context, params json.RawMessa
 	return mustJSON(map[string]any{"lead": lead}), nil
 }
 
+func (*mockLeadToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
+// mockLeadUpdateToolExecutor applies update_lead status changes to the lead
+// returned by the getter.
+type mockLeadUpdateToolExecutor struct{ getter LeadGetter }
+
+func (m *mockLeadUpdateToolExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
+	var in struct {
+		LeadID string `json:"lead_id"`
+		Status string `json:"status"`
+	}
+	if err := json.Unmarshal(params, &in); err != nil {
+		return nil, err
+	}
+	lead, err := m.getter.Get(ctx, "", in.LeadID)
+	if err != nil {
+		return nil, err
+	}
+	lead.Status = in.Status
+	return mustJSON(map[string]any{"lead": lead}), nil
+}
+
+func (*mockLeadUpdateToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
 type mockAccountToolExecutor struct{ getter AccountGetter }
 
 func (m *mockAccountToolExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
@@ -99,6 +127,8 @@ func (m *mockAccountToolExecutor) Execute(ctx context.Context, params json.RawMe
 	return mustJSON(map[string]any{"account": account}), nil
 }
 
+func (*mockAccountToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
 func setupProspectingTestDB(t *testing.T) *sql.DB {
 	t.Helper()
 	db, err := sql.Open("sqlite", ":memory:")
@@ -163,16 +193,24 @@ func newTestProspectingAgent(
 ) *ProspectingAgent {
 	t.Helper()
 	orch := agent.NewOrchestrator(db)
+	// As wired in production: failed runs must still persist past them.
+	RegisterOutputValidators(orch)
 	registry := tool.NewToolRegistry(db)
-	if err := registry.Register(tool.BuiltinCreateTask, tool.NewCreateTaskExecutor(db)); err != nil {
+	if err := registry.Register(tool.BuiltinCreateTask, tool.NewCreateTaskExecutor(crm.NewActivityService(db))); err != nil {
 		t.Fatalf("register create_task: %v", err)
 	}
+	if err := registry.Register(tool.BuiltinCreateNote, tool.NewCreateNoteExecutor(crm.NewNoteService(db))); err != nil {
+		t.Fatalf("register create_note: %v", err)
+	}
 	if err := registry.Register(tool.BuiltinGetLead, &mockLeadToolExecutor{getter: lead}); err != nil {
 		t.Fatalf("register get_lead: %v", err)
 	}
 	if err := registry.Register(tool.BuiltinGetAccount, &mockAccountToolExecutor{getter: account}); err != nil {
 		t.Fatalf("register get_account: %v", err)
 	}
+	if err := registry.Register(tool.BuiltinUpdateLead, &mockLeadUpdateToolExecutor{getter: lead}); err != nil {
+		t.Fatalf("register update_lead: %v", err)
+	}
 	return NewProspectingAgent(orch, registry, search, provider, lead, account, db)
 }
 
@@ -183,7 +221,7 @@ func TestProspectingAgent_AllowedTools(t *testing.T) {
 
 	a := newTestProspectingAgent(t, db, &mockKnowledgeSearch{results: emptyResults()}, &mockLLMProvider{}, &mockLeadGetter{}, &mockAccountGetter{})
 	tools := a.AllowedTools()
-	want := []string{"search_knowledge", "create_task", "get_lead", "get_account"}
+	want := []string{"search_knowledge", "create_task", "get_lead", "get_account", "update_lead", "create_note", "create_deal"}
 	if len(tools) != len(want) {
 		t.Fatalf("expected %d tools, got %d", len(want), len(tools))
 	}
@@ -215,10 +253,11 @@ func TestProspectingAgent_Run_HighConfidence_DraftsOutreach(t *testing.T) {
 
 	leadID := "lead-1"
 	accountID := "acc-1"
+	lead := &crm.Lead{ID: leadID, AccountID: &accountID, Status: "new", OwnerID: ownerID}
 	a := newTestProspectingAgent(t, db,
 		&mockKnowledgeSearch{results: &knowledge.SearchResults{Items: []knowledge.SearchResult{{Score: 0.9}}}},
 		&mockLLMProvider{content: "Hola, ¿agendamos una llamada breve esta semana?", tokens: 32},
-		&mockLeadGetter{lead: &crm.Lead{ID: leadID, AccountID: &accountID, Status: "new", OwnerID: ownerID}},
+		&mockLeadGetter{lead: lead},
 		&mockAccountGetter{account: &crm.Account{ID: accountID, Name: "Acme"}},
 	)
 
@@ -243,6 +282,7 @@ func TestProspectingAgent_Run_HighConfidence_DraftsOutreach(t *testing.T) {
 		Details    struct {
 			Draft  string `json:"draft"`
 			TaskID string `json:"task_id"`
+			NoteID string `json:"note_id"`
 		} `json:"details"`
 	}
 	if err := json.Unmarshal(stored.Output, &output); err != nil {
@@ -257,6 +297,175 @@ func TestProspectingAgent_Run_HighConfidence_DraftsOutreach(t *testing.T) {
 	if output.Confidence <= 0.6 {
 		t.Fatalf("confidence=%f want > 0.6", output.Confidence)
 	}
+	if lead.Status != "contacted" {
+		t.Fatalf("lead status=%s want=contacted", lead.Status)
+	}
+	var toolCalls []struct {
+		ToolName string `json:"tool_name"`
+	}
+	if err := json.Unmarshal(stored.ToolCalls, &toolCalls); err != nil {
+		t.Fatalf("unmarshal tool calls: %v", err)
+	}
+	if last := toolCalls[len(toolCalls)-1].ToolName; last != tool.BuiltinUpdateLead {
+		t.Fatalf("last tool call=%s want=%s", last, tool.BuiltinUpdateLead)
+	}
+	if toolCalls[len(toolCalls)-2].ToolName != tool.BuiltinCreateNote {
+		t.Fatalf("expected %s tool call before %s, got %+v", tool.BuiltinCreateNote, tool.BuiltinUpdateLead, toolCalls)
+	}
+
+	var content, entityType, entityID string
+	var isInternal bool
+	if err := db.QueryRow(`SELECT content, entity_type, entity_id, is_internal FROM note WHERE id = ?`, output.Details.NoteID).
+		Scan(&content, &entityType, &entityID, &isInternal); err != nil {
+		t.Fatalf("load draft note: %v", err)
+	}
+	if content != output.Details.Draft || entityType != "account" || entityID != accountID || !isInternal {
+		t.Fatalf("unexpected draft note content=%q entity=%s/%s internal=%v", content, entityType, entityID, isInternal)
+	}
+}
+
+// mockCreateDealToolExecutor records the create_deal params it receives.
+type mockCreateDealToolExecutor struct{ params map[string]any }
+
+func (m *mockCreateDealToolExecutor) Execute(_ context.Context, params json.RawMessage) (json.RawMessage, error) {
+	if err := json.Unmarshal(params, &m.params); err != nil {
+		return nil, err
+	}
+	return mustJSON(map[string]any{"deal_id": "deal-1", "stage_id": m.params["stage_id"]}), nil
+}
+
+func (*mockCreateDealToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
+func TestProspectingAgent_Run_QualifiedLead_ConvertsToDeal(t *testing.T) {
+	db := setupProspectingTestDB(t)
+	defer db.Close()
+	insertProspectingAgentDefinition(t, db, "ws-1")
+	ownerID := insertProspectingTestUser(t, db, "ws-1")
+	if _, err := db.Exec(`
+		INSERT INTO pipeline (id, workspace_id, name, entity_type, created_at, updated_at)
+		VALUES ('pipe-1', 'ws-1', 'Sales', 'deal', datetime('now'), datetime('now'));
+		INSERT INTO pipeline_stage (id, pipeline_id, name, position, created_at, updated_at)
+		VALUES ('stage-2', 'pipe-1', 'Proposal', 2, datetime('now'), datetime('now')),
+		       ('stage-1', 'pipe-1', 'Discovery', 1, datetime('now'), datetime('now'));`); err != nil {
+		t.Fatalf("insert pipeline: %v", err)
+	}
+
+	accountID := "acc-1"
+	lead := &crm.Lead{ID: "lead-1", AccountID: &accountID, Status: "qualified", OwnerID: ownerID}
+	a := newTestProspectingAgent(t, db,
+		&mockKnowledgeSearch{results: &knowledge.SearchResults{Items: []knowledge.SearchResult{{Score: 0.9}}}},
+		&mockLLMProvider{content: "unused"},
+		&mockLeadGetter{lead: lead},
+		&mockAccountGetter{account: &crm.Account{ID: accountID, Name: "Acme"}},
+	)
+	createDeal := &mockCreateDealToolExecutor{}
+	if err := a.toolRegistry.Register(tool.BuiltinCreateDeal, createDeal); err != nil {
+		t.Fatalf("register create_deal: %v", err)
+	}
+
+	run, err := a.Run(context.Background(), ProspectingAgentConfig{WorkspaceID: "ws-1", LeadID: lead.ID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err := agent.NewOrchestrator(db).GetAgentRun(context.Background(), "ws-1", run.ID)
+	if err != nil {
+		t.Fatalf("GetAgentRun: %v", err)
+	}
+	var output ProspectingOutput
+	if err = json.Unmarshal(stored.Output, &output); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if stored.Status != agent.StatusSuccess || output.Action != prospectingActionConvert || output.DealID != "deal-1" {
+		t.Fatalf("status=%s output=%+v; want success convert with deal-1", stored.Status, output)
+	}
+	if createDeal.params["pipeline_id"] != "pipe-1" || createDeal.params["stage_id"] != "stage-1" ||
+		createDeal.params["account_id"] != accountID || createDeal.params["owner_id"] != ownerID || createDeal.params["title"] != "Acme" {
+		t.Fatalf("create_deal params = %v", createDeal.params)
+	}
+	if lead.Status != "converted" {
+		t.Fatalf("lead status=%s want=converted", lead.Status)
+	}
+	var toolCalls []struct {
+		ToolName string `json:"tool_name"`
+	}
+	if err = json.Unmarshal(stored.ToolCalls, &toolCalls); err != nil {
+		t.Fatalf("unmarshal tool calls: %v", err)
+	}
+	n := len(toolCalls)
+	if n < 2 || toolCalls[n-2].ToolName != tool.BuiltinCreateDeal || toolCalls[n-1].ToolName != tool.BuiltinUpdateLead {
+		t.Fatalf("tool calls = %+v; want create_deal then update_lead last", toolCalls)
+	}
+}
+
+func TestProspectingAgent_Run_FailsWhenMaxToolCallsExceeded(t *testing.T) {
+	db := setupProspectingTestDB(t)
+	defer db.Close()
+	insertProspectingAgentDefinition(t, db, "ws-1")
+	if _, err := db.Exec(`UPDATE agent_definition SET limits = '{"max_tool_calls":2}' WHERE id = 'prospecting-agent'`); err != nil {
+		t.Fatalf("set limits: %v", err)
+	}
+	ownerID := insertProspectingTestUser(t, db, "ws-1")
+
+	accountID := "acc-1"
+	lead := &crm.Lead{ID: "lead-1", AccountID: &accountID, Status: "new", OwnerID: ownerID}
+	a := newTestProspectingAgent(t, db,
+		&mockKnowledgeSearch{results: &knowledge.SearchResults{Items: []knowledge.SearchResult{{Score: 0.9}}}},
+		&mockLLMProvider{content: "Hola", tokens: 8},
+		&mockLeadGetter{lead: lead},
+		&mockAccountGetter{account: &crm.Account{ID: accountID, Name: "Acme"}},
+	)
+
+	run, err := a.Run(context.Background(), ProspectingAgentConfig{WorkspaceID: "ws-1", LeadID: lead.ID})
+	if !errors.Is(err, agent.ErrRunLimitExceeded) {
+		t.Fatalf("expected ErrRunLimitExceeded, got %v", err)
+	}
+	if run == nil {
+		t.Fatal("expected failed run to be returned")
+	}
+	stored, getErr := agent.NewOrchestrator(db).GetAgentRun(context.Background(), "ws-1", run.ID)
+	if getErr != nil {
+		t.Fatalf("GetAgentRun: %v", getErr)
+	}
+	if stored.Status != agent.StatusFailed {
+		t.Fatalf("status=%s want=%s", stored.Status, agent.StatusFailed)
+	}
+	if !strings.Contains(string(stored.Output), "max_tool_calls=2") {
+		t.Fatalf("expected limit reason in output, got %s", stored.Output)
+	}
+	if lead.Status != "new" {
+		t.Fatalf("lead status=%s want=new", lead.Status)
+	}
+}
+
+func TestProspectingAgent_Run_FailsWhenMaxTokensExceeded(t *testing.T) {
+	db := setupProspectingTestDB(t)
+	defer db.Close()
+	insertProspectingAgentDefinition(t, db, "ws-1")
+	if _, err := db.Exec(`UPDATE agent_definition SET limits = '{"max_tokens":10}' WHERE id = 'prospecting-agent'`); err != nil {
+		t.Fatalf("set limits: %v", err)
+	}
+	ownerID := insertProspectingTestUser(t, db, "ws-1")
+
+	accountID := "acc-1"
+	lead := &crm.Lead{ID: "lead-1", AccountID: &accountID, Status: "new", OwnerID: ownerID}
+	a := newTestProspectingAgent(t, db,
+		&mockKnowledgeSearch{results: &knowledge.SearchResults{Items: []knowledge.SearchResult{{Score: 0.9}}}},
+		&mockLLMProvider{content: "Hola", tokens: 32},
+		&mockLeadGetter{lead: lead},
+		&mockAccountGetter{account: &crm.Account{ID: accountID, Name: "Acme"}},
+	)
+
+	run, err := a.Run(context.Background(), ProspectingAgentConfig{WorkspaceID: "ws-1", LeadID: lead.ID})
+	if !errors.Is(err, agent.ErrRunLimitExceeded) {
+		t.Fatalf("expected ErrRunLimitExceeded, got %v", err)
+	}
+	stored, getErr := agent.NewOrchestrator(db).GetAgentRun(context.Background(), "ws-1", run.ID)
+	if getErr != nil {
+		t.Fatalf("GetAgentRun: %v", getErr)
+	}
+	if stored.Status != agent.StatusFailed || !strings.Contains(string(stored.Output), "max_tokens=10") {
+		t.Fatalf("status=%s output=%s; want failed with max_tokens reason", stored.Status, stored.Output)
+	}
 }
 
 // Task 4.5b — TDD 4/5.
@@ -284,6 +493,20 @@ func TestProspectingAgent_Run_LowConfidence_Skips(t *testing.T) {
 	if !contains(string(stored.Output), "\"skip\"") {
 		t.Fatalf("output=%s expected skip", string(stored.Output))
 	}
+	if stored.AbstentionReason == nil || *stored.AbstentionReason != string(agent.AbstentionInsufficientSignals) {
+		t.Fatalf("AbstentionReason = %v, want %s", stored.AbstentionReason, agent.AbstentionInsufficientSignals)
+	}
+	var steps []agent.ReasoningStep
+	if err = json.Unmarshal(stored.ReasoningTrace, &steps); err != nil {
+		t.Fatalf("unmarshal reasoning trace %s: %v", stored.ReasoningTrace, err)
+	}
+	if len(steps) != 3 || steps[1].Step != "evaluate_signals" || steps[1].Data["confidence"] != 0.4 {
+		t.Fatalf("reasoning trace = %s", stored.ReasoningTrace)
+	}
+	var notes int
+	if err = db.QueryRow(`SELECT COUNT(*) FROM note`).Scan(&notes); err != nil || notes != 0 {
+		t.Fatalf("expected no draft note on skip, got %d (err=%v)", notes, err)
+	}
 }
 
 // Task 4.5b — TDD 5/5.
@@ -390,3 +613,98 @@ func TestProspectingError_Error_ReturnsMessage(t *testing.T) {
 		t.Fatal("ProspectingError.Error() should not be empty")
 	}
 }
+
+type countingToolExecutor struct{ calls int }
+
+func (e *countingToolExecutor) Execute(context.Context, json.RawMessage) (json.RawMessage, error) {
+	e.calls++
+	return json.RawMessage(`{}`), nil
+}
+
+func (*countingToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
+func TestExecuteGuardedTool_BlocksToolOutsideAllowlist(t *testing.T) {
+	db := setupProspectingTestDB(t)
+	defer db.Close()
+	ensureAgentTestWorkspace(t, db, "ws-1")
+	ownerID := insertProspectingTestUser(t, db, "ws-1")
+
+	auditSvc := audit.NewAuditService(db)
+	registry := tool.NewToolRegistryWithRuntime(db, nil, auditSvc)
+	getLead, updateDeal := &countingToolExecutor{}, &countingToolExecutor{}
+	if err := registry.Register(tool.BuiltinGetLead, getLead); err != nil {
+		t.Fatalf("register get_lead: %v", err)
+	}
+	if err := registry.Register(tool.BuiltinUpdateDeal, updateDeal); err != nil {
+		t.Fatalf("register update_deal: %v", err)
+	}
+
+	a := &ProspectingAgent{}
+	ctx := agent.WithRunGuard(context.Background(), agent.NewRunGuard(agent.RunLimits{}).AllowTools(a.AllowedTools()))
+	ctx = context.WithValue(ctx, ctxkeys.UserID, ownerID)
+	ctx = context.WithValue(ctx, ctxkeys.RunID, "run-1")
+
+	// A plan steered toward update_deal, which prospecting may not call.
+	plan := []struct {
+		tool   string
+		params string
+	}{
+		{tool.BuiltinGetLead, `{"lead_id":"lead-1"}`},
+		{tool.BuiltinUpdateDeal, `{"deal_id":"deal-1","status":"won"}`},
+	}
+	if _, err := executeGuardedTool(ctx, registry, "ws-1", plan[0].tool, json.RawMessage(plan[0].params)); err != nil {
+		t.Fatalf("get_lead: %v", err)
+	}
+	_, err := executeGuardedTool(ctx, registry, "ws-1", plan[1].tool, json.RawMessage(plan[1].params))
+	var notAllowed *agent.ToolNotAllowedError
+	if !errors.As(err, &notAllowed) || notAllowed.Tool != tool.BuiltinUpdateDeal {
+		t.Fatalf("update_deal error = %v; want *agent.ToolNotAllowedError", err)
+	}
+	if getLead.calls != 1 || updateDeal.calls != 0 {
+		t.Fatalf("executor calls get_lead=%d update_deal=%d; want 1 and 0", getLead.calls, updateDeal.calls)
+	}
+
+	events, err := auditSvc.ListByAction(context.Background(), "ws-1", tool.BuiltinUpdateDeal, 10, 0)
+	if err != nil {
+		t.Fatalf("ListByAction: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("update_deal audit events = %d; want 1", len(events))
+	}
+	got := events[0]
+	if got.Outcome != audit.OutcomeDenied || got.ActorType != audit.ActorTypeAgent || got.ActorID != "run-1" {
+		t.Fatalf("audit event outcome=%s actor=%s/%s; want denied by agent run-1", got.Outcome, got.ActorType, got.ActorID)
+	}
+	if got.EntityID == nil || *got.EntityID != "deal-1" || !strings.Contains(string(got.Details), string(tool.ToolErrorNotAllowed)) {
+		t.Fatalf("audit event entity=%v details=%s", got.EntityID, got.Details)
+	}
+}
+
+func TestValidateAgentPlan_BlocksPlanOutsideAllowlist(t *testing.T) {
+	db := setupProspectingTestDB(t)
+	defer db.Close()
+	ensureAgentTestWorkspace(t, db, "ws-1")
+
+	auditSvc := audit.NewAuditService(db)
+	registry := tool.NewToolRegistryWithRuntime(db, nil, auditSvc)
+	a := &ProspectingAgent{}
+	ctx := agent.WithRunGuard(context.Background(), agent.NewRunGuard(agent.RunLimits{}).AllowTools(a.AllowedTools()))
+	ctx = context.WithValue(ctx, ctxkeys.RunID, "run-1")
+
+	if err := validateAgentPlan(ctx, registry, "ws-1", prospectingToolPlan); err != nil {
+		t.Fatalf("validateAgentPlan(prospecting plan) = %v; want nil", err)
+	}
+
+	err := validateAgentPlan(ctx, registry, "ws-1", append(slices.Clone(prospectingToolPlan), tool.BuiltinUpdateDeal))
+	var notAllowed *agent.ToolNotAllowedError
+	if !errors.As(err, &notAllowed) || notAllowed.Tool != tool.BuiltinUpdateDeal {
+		t.Fatalf("validateAgentPlan(plan with update_deal) = %v; want *agent.ToolNotAllowedError", err)
+	}
+	events, err := auditSvc.ListByAction(context.Background(), "ws-1", tool.BuiltinUpdateDeal, 10, 0)
+	if err != nil {
+		t.Fatalf("ListByAction: %v", err)
+	}
+	if len(events) != 1 || events[0].Outcome != audit.OutcomeDenied {
+		t.Fatalf("update_deal audit events = %+v; want one denied", events)
+	}
+}
@@ -0,0 +1,87 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+	"github.com/matiasleandrokruk/fenix/internal/domain/tool"
+	"github.com/matiasleandrokruk/fenix/internal/infra/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// executeGuardedTool counts the call against the run guard carried by ctx
+// (see agent.Orchestrator.GuardRun) before executing it. A tool outside the
+// guard's allowlist is never executed; the refusal is audited as denied.
+// Each call, denied or not, is traced as a child span of the run.
+func executeGuardedTool(ctx context.Context, registry *tool.ToolRegistry, workspaceID, toolName string, params json.RawMessage) (result json.RawMessage, err error) {
+	ctx, span := tracing.Start(ctx, "agent.tool_call", attribute.String("fenix.tool.name", toolName))
+	defer func() { tracing.End(span, err) }()
+
+	if err = agent.RunGuardFromContext(ctx).ToolCall(toolName); err != nil {
+		if errors.Is(err, agent.ErrToolNotAllowed) {
+			return nil, registry.Deny(ctx, workspaceID, toolName, params, err)
+		}
+		return nil, err
+	}
+	return registry.Execute(ctx, workspaceID, toolName, params)
+}
+
+// runGuardedInProcessTool is executeGuardedTool for a tool the agent runs
+// itself, such as tool.SearchKnowledge: the run guard allowlists and counts
+// the call and the registry audits it, while fn does the work.
+func runGuardedInProcessTool(
+	ctx context.Context,
+	registry *tool.ToolRegistry,
+	workspaceID, toolName string,
+	params json.RawMessage,
+	fn func(context.Context) error,
+) (err error) {
+	ctx, span := tracing.Start(ctx, "agent.tool_call", attribute.String("fenix.tool.name", toolName))
+	defer func() { tracing.End(span, err) }()
+
+	if err = agent.RunGuardFromContext(ctx).ToolCall(toolName); err != nil {
+		if errors.Is(err, agent.ErrToolNotAllowed) && registry != nil {
+			return registry.Deny(ctx, workspaceID, toolName, params, err)
+		}
+		return err
+	}
+	if registry == nil {
+		return fn(ctx)
+	}
+	return registry.RunInProcess(ctx, workspaceID, toolName, params, fn)
+}
+
+// validateAgentPlan checks the tools a flow may call against the run guard's
+// allowlist before any of them runs, so a flow that drifted from its agent's
+// AllowedTools fails up front instead of part way through. The first refused
+// tool is audited as denied.
+func validateAgentPlan(ctx context.Context, registry *tool.ToolRegistry, workspaceID string, planned []string) error {
+	err := agent.RunGuardFromContext(ctx).ValidatePlan(planned)
+	var notAllowed *agent.ToolNotAllowedError
+	if registry != nil && errors.As(err, &notAllowed) {
+		return registry.Deny(ctx, workspaceID, notAllowed.Tool, nil, err)
+	}
+	return err
+}
+
+// guardAgentRun guards run with its definition's limits and restricts it to
+// allowedTools.
+func guardAgentRun(ctx context.Context, orchestrator *agent.Orchestrator, run *agent.Run, allowedTools []string) context.Context {
+	ctx = orchestrator.GuardRun(ctx, run)
+	agent.RunGuardFromContext(ctx).AllowTools(allowedTools)
+	return ctx
+}
+
+// markAgentRunFailed marks run failed. A run stopped by its limits keeps the
+// reason in its output; other failures only change the status.
+func markAgentRunFailed(ctx context.Context, orchestrator *agent.Orchestrator, run *agent.Run, cause error) error {
+	var err error
+	if errors.Is(cause, agent.ErrRunLimitExceeded) {
+		_, err = orchestrator.FailAgentRun(ctx, run.WorkspaceID, run.ID, cause.Error())
+	} else {
+		_, err = orchestrator.UpdateAgentRunStatus(ctx, run.WorkspaceID, run.ID, agent.StatusFailed)
+	}
+	return err
+}
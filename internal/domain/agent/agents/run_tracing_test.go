@@ -0,0 +1,60 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+	"github.com/matiasleandrokruk/fenix/internal/infra/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestProspectingAgent_Run_TracesToolAndLLMCallsUnderRunTraceID(t *testing.T) {
+	db := setupProspectingTestDB(t)
+	defer db.Close()
+	insertProspectingAgentDefinition(t, db, "ws-1")
+	ownerID := insertProspectingTestUser(t, db, "ws-1")
+
+	accountID := "acc-1"
+	lead := &crm.Lead{ID: "lead-1", AccountID: &accountID, Status: "new", OwnerID: ownerID}
+	a := newTestProspectingAgent(t, db,
+		&mockKnowledgeSearch{results: &knowledge.SearchResults{Items: []knowledge.SearchResult{{Score: 0.9}}}},
+		&mockLLMProvider{content: "Hola, ¿agendamos una llamada?", tokens: 32},
+		&mockLeadGetter{lead: lead},
+		&mockAccountGetter{account: &crm.Account{ID: accountID, Name: "Acme"}},
+	)
+	recorder := tracetest.NewSpanRecorder()
+	a.orchestrator.SetTracerProvider(tracing.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	run, err := a.Run(context.Background(), ProspectingAgentConfig{WorkspaceID: "ws-1", LeadID: lead.ID})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	var root sdktrace.ReadOnlySpan
+	counts := map[string]int{}
+	for _, span := range spans {
+		counts[span.Name()]++
+		if span.Name() == "agent.run" {
+			root = span
+		}
+	}
+	if root == nil {
+		t.Fatalf("ended spans = %v; want an ended agent.run span", counts)
+	}
+	if got, want := root.SpanContext().TraceID().String(), strings.ReplaceAll(*run.TraceID, "-", ""); got != want {
+		t.Fatalf("run span trace ID = %s; want the run's TraceID %s", got, want)
+	}
+	if counts["llm.chat_completion"] != 1 || counts["agent.tool_call"] == 0 {
+		t.Fatalf("ended spans = %v; want one llm.chat_completion and the tool calls", counts)
+	}
+	for _, span := range spans {
+		if span.Name() != "agent.run" && span.Parent().SpanID() != root.SpanContext().SpanID() {
+			t.Fatalf("span %s parent = %s; want the run span", span.Name(), span.Parent().SpanID())
+		}
+	}
+}
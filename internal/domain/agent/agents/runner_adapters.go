@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/matiasleandrokruk/fenix/internal/domain/agent"
+	"github.com/matiasleandrokruk/fenix/internal/domain/tool"
 )
 
 // SupportRunner adapts SupportAgent to the AgentRunner contract without
@@ -42,6 +43,42 @@ var (
 	_ agent.Runner = (*DealRiskRunner)(nil)
 )
 
+var (
+	_ agent.ToolRequirer = (*SupportRunner)(nil)
+	_ agent.ToolRequirer = (*ProspectingRunner)(nil)
+	_ agent.ToolRequirer = (*KBRunner)(nil)
+	_ agent.ToolRequirer = (*InsightsRunner)(nil)
+	_ agent.ToolRequirer = (*DealRiskRunner)(nil)
+)
+
+// RequiredTools returns the tools a support run cannot do without: it loads
+// the case, grounds the answer in the knowledge base and updates the case.
+func (r *SupportRunner) RequiredTools() []string {
+	return []string{tool.BuiltinGetCase, tool.SearchKnowledge, tool.BuiltinUpdateCase}
+}
+
+// RequiredTools returns the tools a prospecting run cannot do without: it
+// loads the lead and searches prior signals.
+func (r *ProspectingRunner) RequiredTools() []string {
+	return []string{tool.BuiltinGetLead, tool.SearchKnowledge}
+}
+
+// RequiredTools returns the tools a KB run cannot do without: it searches for
+// similar articles and creates new ones.
+func (r *KBRunner) RequiredTools() []string {
+	return []string{tool.SearchKnowledge, tool.BuiltinCreateKnowledgeItem}
+}
+
+// RequiredTools returns the tool an insights run cannot do without.
+func (r *InsightsRunner) RequiredTools() []string {
+	return []string{tool.BuiltinQueryMetrics}
+}
+
+// RequiredTools returns the tool a deal risk run cannot do without.
+func (r *DealRiskRunner) RequiredTools() []string {
+	return []string{tool.BuiltinGetDeal}
+}
+
 func (r *SupportRunner) Run(ctx context.Context, rc *agent.RunContext, input agent.TriggerAgentInput) (*agent.Run, error) {
 	_ = rc
 	cfg, err := decodeSupportAgentInput(input)
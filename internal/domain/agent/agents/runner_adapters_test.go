@@ -181,3 +181,31 @@ func mustMarshalJSON(t *testing.T, v any) json.RawMessage {
 	}
 	return data
 }
+
+func TestRunnerRequiredToolsAreAllowedSubsets(t *testing.T) {
+	cases := []struct {
+		name     string
+		required []string
+		allowed  []string
+	}{
+		{"support", (&SupportRunner{}).RequiredTools(), (&SupportAgent{}).AllowedTools()},
+		{"prospecting", (&ProspectingRunner{}).RequiredTools(), (&ProspectingAgent{}).AllowedTools()},
+		{"kb", (&KBRunner{}).RequiredTools(), (&KBAgent{}).AllowedTools()},
+		{"insights", (&InsightsRunner{}).RequiredTools(), (&InsightsAgent{}).AllowedTools()},
+		{"deal_risk", (&DealRiskRunner{}).RequiredTools(), (&DealRiskAgent{}).AllowedTools()},
+	}
+	for _, tc := range cases {
+		allowed := make(map[string]bool, len(tc.allowed))
+		for _, name := range tc.allowed {
+			allowed[name] = true
+		}
+		if len(tc.required) == 0 || len(tc.required) >= len(tc.allowed) {
+			t.Errorf("%s: %d required of %d allowed tools; want a proper subset", tc.name, len(tc.required), len(tc.allowed))
+		}
+		for _, name := range tc.required {
+			if !allowed[name] {
+				t.Errorf("%s: required tool %q is not allowed", tc.name, name)
+			}
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
 	"github.com/matiasleandrokruk/fenix/internal/domain/tool"
 	"github.com/matiasleandrokruk/fenix/internal/domain/usage"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 type KnowledgeSearchInterface interface {
@@ -36,6 +38,11 @@ type SupportAgentConfig struct {
 	Priority       string `json:"priority,omitempty"`
 	ContextAccount string `json:"context_account,omitempty"`
 	ContextContact string `json:"context_contact,omitempty"`
+	// ReplyToNoteID is the customer message (a note on the case) being
+	// answered; the agent's reply is threaded under it.
+	ReplyToNoteID string `json:"reply_to_note_id,omitempty"`
+	// Copilot, when set, triggers the run as TriggerTypeCopilot from this conversation.
+	Copilot *agent.CopilotConversation `json:"-"`
 }
 
 const supportActionUpdateCase = "update_case"
@@ -43,10 +50,13 @@ const supportActionAbstain = "abstain"
 const supportActionEscalate = "escalate"
 const supportPendingApprovalAction = "pending_approval"
 const supportSystemActorID = "system"
+const supportToolCaseAutoAssigned = "case.auto_assigned"
+const supportComponent = "agents.support"
 
 const (
 	supportResolveThreshold  = 0.85
 	supportEscalateThreshold = 0.55
+	supportEvidenceLimit     = 5
 )
 
 // SupportAgent handles customer support case resolution
@@ -58,6 +68,7 @@ type SupportAgent struct {
 	db              *sql.DB
 	audit           supportAuditLogger
 	usage           supportUsageRecorder
+	caseAssigner    supportCaseAssigner
 }
 
 type supportAuditLogger interface {
@@ -68,6 +79,10 @@ type supportUsageRecorder interface {
 	RecordEvent(ctx context.Context, input usage.RecordEventInput) (*usage.Event, error)
 }
 
+type supportCaseAssigner interface {
+	AutoAssign(ctx context.Context, workspaceID, caseID string, input crm.AutoAssignInput) (*crm.CaseTicket, error)
+}
+
 // NewSupportAgent creates a new Support Agent instance
 func NewSupportAgent(
 	orchestrator *agent.Orchestrator,
@@ -107,14 +122,25 @@ func NewSupportAgentWithDBAndUsage(
 	}
 }
 
+// SetCaseAssigner enables round-robin assignment of cases the agent escalates.
+// Without one, escalated cases keep their current owner.
+func (a *SupportAgent) SetCaseAssigner(assigner supportCaseAssigner) {
+	a.caseAssigner = assigner
+}
+
+// Orchestrator returns the orchestrator that records the Support Agent's runs.
+func (a *SupportAgent) Orchestrator() *agent.Orchestrator {
+	return a.orchestrator
+}
+
 // AllowedTools returns the tools available to the Support Agent
 func (a *SupportAgent) AllowedTools() []string {
 	return []string{
 		supportActionUpdateCase,
 		"send_reply",
 		"create_task",
-		"search_knowledge",
-		"get_case",
+		tool.SearchKnowledge,
+		tool.BuiltinGetCase,
 		"get_contact",
 	}
 }
@@ -156,7 +182,9 @@ func (a *SupportAgent) Run(ctx context.Context, config SupportAgentConfig) (*age
 		return nil, err
 	}
 
-	result, err := a.executeSupportFlow(ctx, run.ID, config)
+	ctx = guardAgentRun(ctx, a.orchestrator, run, a.AllowedTools())
+	trace := agent.NewReasoningTracer(a.orchestrator, run.WorkspaceID, run.ID)
+	result, err := a.executeSupportFlow(ctx, run.ID, config, trace)
 	if err != nil {
 		return run, a.failSupportRun(ctx, run, err)
 	}
@@ -183,22 +211,65 @@ type SupportResult struct {
 }
 
 // executeSupportFlow runs the main support logic
-func (a *SupportAgent) executeSupportFlow(ctx context.Context, runID string, config SupportAgentConfig) (*SupportResult, error) {
+// supportToolPlan is every tool executeSupportFlow may call.
+var supportToolPlan = []string{
+	tool.BuiltinGetCase,
+	tool.SearchKnowledge,
+	tool.BuiltinUpdateCase,
+	tool.BuiltinSendReply,
+	tool.BuiltinCreateTask,
+}
+
+func (a *SupportAgent) executeSupportFlow(
+	ctx context.Context,
+	runID string,
+	config SupportAgentConfig,
+	trace *agent.ReasoningTracer,
+) (*SupportResult, error) {
 	startTime := time.Now()
 	var totalTokens int64
-	var totalCost float64
+	totalCost := baseRunCostEuros
 
+	if err := validateAgentPlan(ctx, a.toolRegistry, config.WorkspaceID, supportToolPlan); err != nil {
+		return nil, err
+	}
 	caseContext, err := a.getCaseContext(ctx, config.WorkspaceID, config.CaseID)
 	if err != nil {
 		return nil, err
 	}
+	trace.Record(ctx, "analyze", "Analyzed customer query", map[string]any{
+		"case_status":   caseContext.Status,
+		"case_priority": caseContext.Priority,
+	})
 
-	evidence := a.loadSupportEvidencePack(ctx, caseContext.WorkspaceID, config.CustomerQuery)
+	evidence, err := a.loadSupportEvidencePack(ctx, caseContext, config.CustomerQuery, runID)
+	if err != nil {
+		return nil, err
+	}
+	trace.Record(ctx, "search", "Built evidence pack from knowledge base", map[string]any{
+		"results":    supportEvidenceSourceCount(evidence),
+		"confidence": supportEvidenceConfidence(evidence),
+		"query":      supportEvidenceQuery(evidence),
+	})
 
 	action := a.determineAction(config, caseContext, evidence)
+	trace.Record(ctx, "policy", "Evaluated approval and execution policy gates", map[string]any{
+		"requires_approval": actionRequiresApproval(action),
+	})
 	if actionRequiresApproval(action) {
-		return a.buildApprovalEscalationResult(ctx, startTime, config, caseContext, evidence, action, &totalTokens, &totalCost)
-	}
+		result, escalateErr := a.buildApprovalEscalationResult(ctx, startTime, config, caseContext, evidence, action, &totalTokens, &totalCost)
+		if escalateErr != nil {
+			return nil, escalateErr
+		}
+		trace.Record(ctx, "decide", "Determined action: "+supportPendingApprovalAction, map[string]any{
+			"confidence": action.Confidence,
+		})
+		result.ReasoningTrace = trace.Trace()
+		return result, nil
+	}
+	trace.Record(ctx, "decide", "Determined action: "+action.Type, map[string]any{
+		"confidence": action.Confidence,
+	})
 
 	toolCalls, handoffReason, err := a.executeAction(ctx, runID, action, caseContext)
 	if err != nil {
@@ -207,7 +278,15 @@ func (a *SupportAgent) executeSupportFlow(ctx context.Context, runID string, con
 	if handoffReason != "" {
 		action.NextSteps = append(action.NextSteps, "handoff_created")
 	}
-	return buildSupportResult(startTime, config, evidence, action, toolCalls, &totalTokens, &totalCost), nil
+	trace.Record(ctx, "execute", "Executed action: "+action.Type, map[string]any{
+		"handoff": handoffReason != "",
+	})
+	result, err := buildSupportResult(startTime, config, evidence, action, toolCalls, &totalTokens, &totalCost)
+	if err != nil {
+		return nil, err
+	}
+	result.ReasoningTrace = trace.Trace()
+	return result, nil
 }
 
 // CaseContext holds the context of a support case
@@ -250,7 +329,7 @@ func (a *SupportAgent) determineAction(config SupportAgentConfig, caseContext *C
 	return supportAbstainedAction(config)
 }
 
-func (a *SupportAgent) executeAction(ctx context.Context, runID string, action *Action, caseContext *CaseContext) (json.RawMessage, string, error) {
+func (a *SupportAgent) executeAction(ctx context.Context, runID string, action *Action, caseContext *CaseContext) ([]map[string]any, string, error) {
 	toolCtx := supportToolContext(ctx, caseContext, runID)
 	switch action.Type {
 	case supportActionUpdateCase:
@@ -260,20 +339,25 @@ func (a *SupportAgent) executeAction(ctx context.Context, runID string, action *
 	case supportActionEscalate:
 		return a.executeEscalatedAction(toolCtx, runID, action, caseContext)
 	default:
-		raw, err := json.Marshal([]map[string]any{})
-		if err != nil {
-			return raw, "", fmt.Errorf("marshal empty support tool calls: %w", err)
-		}
-		return raw, "", nil
+		return []map[string]any{}, "", nil
 	}
 }
 
+// loadSupportCase reads the case through the get_case tool so the lookup is
+// workspace-scoped, permission-checked and audited like the agent's writes.
 func (a *SupportAgent) loadSupportCase(ctx context.Context, workspaceID, caseID string) (*crm.CaseTicket, error) {
-	caseTicket, err := crm.NewCaseService(a.db).Get(ctx, workspaceID, caseID)
+	toolCtx := context.WithValue(ctx, ctxkeys.WorkspaceID, workspaceID)
+	raw, err := a.executeTool(toolCtx, workspaceID, tool.BuiltinGetCase, map[string]any{"case_id": caseID})
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrSupportCaseContextLoadFailed, err)
 	}
-	return caseTicket, nil
+	var parsed struct {
+		Case *crm.CaseTicket `json:"case"`
+	}
+	if err = json.Unmarshal(raw, &parsed); err != nil || parsed.Case == nil {
+		return nil, fmt.Errorf("%w: decode case", ErrSupportCaseContextLoadFailed)
+	}
+	return parsed.Case, nil
 }
 
 func buildCaseContext(caseTicket *crm.CaseTicket) *CaseContext {
@@ -314,9 +398,12 @@ type Action struct {
 	NextSteps  []string
 	ApprovalID string
 	Metadata   string
+	// ReplyToNoteID threads the reply sent for this action; see
+	// SupportAgentConfig.ReplyToNoteID.
+	ReplyToNoteID string
 }
 
-func (a *SupportAgent) executeResolvedAction(toolCtx context.Context, action *Action, caseContext *CaseContext) (json.RawMessage, string, error) {
+func (a *SupportAgent) executeResolvedAction(toolCtx context.Context, action *Action, caseContext *CaseContext) ([]map[string]any, string, error) {
 	toolCalls := []map[string]any{}
 	if err := a.appendCaseUpdateToolCall(toolCtx, &toolCalls, action, caseContext); err != nil {
 		return nil, "", err
@@ -324,26 +411,18 @@ func (a *SupportAgent) executeResolvedAction(toolCtx context.Context, action *Ac
 	if err := a.appendReplyToolCall(toolCtx, &toolCalls, action, caseContext); err != nil {
 		return nil, "", err
 	}
-	raw, err := json.Marshal(toolCalls)
-	if err != nil {
-		return raw, "", fmt.Errorf("marshal resolved support tool calls: %w", err)
-	}
-	return raw, "", nil
+	return toolCalls, "", nil
 }
 
-func (a *SupportAgent) executeAbstainedAction(toolCtx context.Context, action *Action, caseContext *CaseContext) (json.RawMessage, string, error) {
+func (a *SupportAgent) executeAbstainedAction(toolCtx context.Context, action *Action, caseContext *CaseContext) ([]map[string]any, string, error) {
 	toolCalls := []map[string]any{}
 	if err := a.appendReplyToolCall(toolCtx, &toolCalls, action, caseContext); err != nil {
 		return nil, "", err
 	}
-	raw, err := json.Marshal(toolCalls)
-	if err != nil {
-		return raw, "", fmt.Errorf("marshal abstained support tool calls: %w", err)
-	}
-	return raw, "", nil
+	return toolCalls, "", nil
 }
 
-func (a *SupportAgent) executeEscalatedAction(toolCtx context.Context, runID string, action *Action, caseContext *CaseContext) (json.RawMessage, string, error) {
+func (a *SupportAgent) executeEscalatedAction(toolCtx context.Context, runID string, action *Action, caseContext *CaseContext) ([]map[string]any, string, error) {
 	toolCalls := []map[string]any{}
 	if err := a.appendEscalationTaskToolCall(toolCtx, &toolCalls, caseContext); err != nil {
 		return nil, "", err
@@ -351,16 +430,33 @@ func (a *SupportAgent) executeEscalatedAction(toolCtx context.Context, runID str
 	if err := a.initiateSupportHandoff(toolCtx, runID, caseContext, action); err != nil {
 		return nil, "", err
 	}
-	raw, err := json.Marshal(toolCalls)
-	if err != nil {
-		return raw, action.Details, fmt.Errorf("marshal escalated support tool calls: %w", err)
+	a.appendEscalationAssignment(toolCtx, &toolCalls, caseContext)
+	return toolCalls, action.Details, nil
+}
+
+// output returns the typed agent_run.output of the action.
+func (a *Action) output() SupportOutput {
+	return SupportOutput{
+		Type:          a.Type,
+		Details:       a.Details,
+		CaseID:        a.CaseID,
+		Status:        a.Status,
+		Confidence:    a.Confidence,
+		NextSteps:     a.NextSteps,
+		ApprovalID:    a.ApprovalID,
+		Metadata:      a.Metadata,
+		ReplyToNoteID: a.ReplyToNoteID,
 	}
-	return raw, action.Details, nil
 }
 
-func (a *Action) toJSON() json.RawMessage {
-	data, _ := json.Marshal(a)
-	return data
+// toJSON validates and serializes the action's output.
+func (a *Action) toJSON() (json.RawMessage, error) {
+	out := a.output()
+	if err := out.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", agent.ErrInvalidRunOutput, err)
+	}
+	data, _ := json.Marshal(out)
+	return data, nil
 }
 
 func nilIfEmpty(v string) *string {
@@ -397,40 +493,11 @@ func (a *SupportAgent) requestSupportApproval(ctx context.Context, caseContext *
 	})
 }
 
-func buildReasoningTrace(_ SupportAgentConfig, evidence *knowledge.EvidencePack, action *Action) json.RawMessage {
-	sourceCount := 0
-	if evidence != nil {
-		sourceCount = len(evidence.Sources)
-	}
-	trace := []map[string]any{
-		{
-			"step":        "analyze",
-			"description": "Analyzed customer query",
-			"timestamp":   time.Now().UTC().Format(time.RFC3339),
-		},
-		{
-			"step":        "search",
-			"description": "Built evidence pack from knowledge base",
-			"results":     sourceCount,
-			"confidence":  supportEvidenceConfidence(evidence),
-			"query":       supportEvidenceQuery(evidence),
-			"timestamp":   time.Now().UTC().Format(time.RFC3339),
-		},
-		{
-			"step":              "policy",
-			"description":       "Evaluated approval and execution policy gates",
-			"requires_approval": actionRequiresApproval(action),
-			"timestamp":         time.Now().UTC().Format(time.RFC3339),
-		},
-		{
-			"step":        "decide",
-			"description": "Determined action: " + action.Type,
-			"confidence":  action.Confidence,
-			"timestamp":   time.Now().UTC().Format(time.RFC3339),
-		},
+func supportEvidenceSourceCount(evidence *knowledge.EvidencePack) int {
+	if evidence == nil {
+		return 0
 	}
-	data, _ := json.Marshal(trace)
-	return data
+	return len(evidence.Sources)
 }
 
 func validateSupportConfig(config SupportAgentConfig) error {
@@ -454,9 +521,10 @@ func (a *SupportAgent) triggerSupportRun(ctx context.Context, config SupportAgen
 		AgentID:        "support-agent",
 		WorkspaceID:    config.WorkspaceID,
 		TriggeredBy:    triggeredByPtr,
-		TriggerType:    agent.TriggerTypeManual,
+		TriggerType:    copilotTriggerType(config.Copilot),
 		TriggerContext: triggerContext,
 		Inputs:         inputs,
+		Conversation:   config.Copilot,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("trigger support run: %w", err)
@@ -480,8 +548,7 @@ func supportRunPayloads(config SupportAgentConfig, allowedTools []string) (json.
 }
 
 func (a *SupportAgent) failSupportRun(ctx context.Context, run *agent.Run, cause error) error {
-	_, err := a.orchestrator.UpdateAgentRunStatus(ctx, run.WorkspaceID, run.ID, agent.StatusFailed)
-	if err != nil {
+	if err := markAgentRunFailed(ctx, a.orchestrator, run, cause); err != nil {
 		return fmt.Errorf("mark support run failed: %w", err)
 	}
 	a.auditSupportRun(ctx, run, nil, cause)
@@ -510,20 +577,35 @@ func (a *SupportAgent) completeSupportRun(ctx context.Context, run *agent.Run, r
 	return nil
 }
 
-func (a *SupportAgent) loadSupportEvidencePack(ctx context.Context, workspaceID, query string) *knowledge.EvidencePack {
+// loadSupportEvidencePack builds the run's evidence pack and stores it for
+// the run. The search runs as the search_knowledge tool call: the run guard
+// allowlists and counts it and the registry audits it like the other tools,
+// but the evidence builder does the work, since the decision needs the full
+// pack. An error fails the run, so no run completes without the evidence it
+// decided on.
+func (a *SupportAgent) loadSupportEvidencePack(ctx context.Context, caseContext *CaseContext, query, runID string) (*knowledge.EvidencePack, error) {
 	if a.evidenceBuilder == nil {
-		return emptySupportEvidencePack(query)
+		return emptySupportEvidencePack(query), nil
 	}
 
-	evidence, err := a.evidenceBuilder.BuildEvidencePack(ctx, knowledge.BuildEvidencePackInput{
-		Query:       query,
-		WorkspaceID: workspaceID,
-		Limit:       5,
-	})
+	params, _ := json.Marshal(map[string]any{"query": query, "limit": supportEvidenceLimit})
+	var evidence *knowledge.EvidencePack
+	search := func(toolCtx context.Context) error {
+		var buildErr error
+		evidence, buildErr = a.evidenceBuilder.BuildEvidencePack(toolCtx, knowledge.BuildEvidencePackInput{
+			Query:       query,
+			WorkspaceID: caseContext.WorkspaceID,
+			Limit:       supportEvidenceLimit,
+			RunID:       runID,
+		})
+		return buildErr
+	}
+	toolCtx := supportToolContext(ctx, caseContext, runID)
+	err := runGuardedInProcessTool(toolCtx, a.toolRegistry, caseContext.WorkspaceID, tool.SearchKnowledge, params, search)
 	if err != nil {
-		return emptySupportEvidencePack(query)
+		return nil, fmt.Errorf("build support evidence pack: %w", err)
 	}
-	return evidence
+	return evidence, nil
 }
 
 func (a *SupportAgent) buildApprovalEscalationResult(
@@ -548,8 +630,11 @@ func (a *SupportAgent) buildApprovalEscalationResult(
 		Confidence: action.Confidence,
 		ApprovalID: approvalID,
 	}
-	toolCalls, _ := json.Marshal([]map[string]any{{"tool_name": "approval.requested"}})
-	result := buildSupportResult(startTime, config, evidence, escalatedAction, toolCalls, totalTokens, totalCost)
+	toolCalls := []map[string]any{{"tool_name": "approval.requested"}}
+	result, err := buildSupportResult(startTime, config, evidence, escalatedAction, toolCalls, totalTokens, totalCost)
+	if err != nil {
+		return nil, err
+	}
 	result.Status = agent.StatusEscalated
 	return result, nil
 }
@@ -559,22 +644,32 @@ func buildSupportResult(
 	config SupportAgentConfig,
 	evidence *knowledge.EvidencePack,
 	action *Action,
-	toolCalls json.RawMessage,
+	toolCalls []map[string]any,
 	totalTokens *int64,
 	totalCost *float64,
-) *SupportResult {
+) (*SupportResult, error) {
+	output, err := action.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	// Only the registry tools the run executed are tool calls; the knowledge
+	// search behind the evidence pack, audited by the registry but run
+	// in-process, is recorded as retrieval queries and evidence IDs.
+	toolCallsJSON, err := json.Marshal(toolCalls)
+	if err != nil {
+		return nil, fmt.Errorf("marshal support tool calls: %w", err)
+	}
 	elapsed := time.Since(startTime).Milliseconds()
 	return &SupportResult{
 		Status:         supportResultStatus(action.Type),
-		Output:         action.toJSON(),
+		Output:         output,
 		RetrievalQuery: marshalSupportRetrievalQueries(config.CustomerQuery),
 		EvidenceIDs:    marshalSupportEvidenceIDs(evidence),
-		ToolCalls:      toolCalls,
-		ReasoningTrace: buildReasoningTrace(config, evidence, action),
+		ToolCalls:      toolCallsJSON,
 		TotalTokens:    totalTokens,
 		TotalCost:      totalCost,
 		LatencyMs:      &elapsed,
-	}
+	}, nil
 }
 
 func shouldResolveSupportAction(score float64) bool {
@@ -583,13 +678,14 @@ func shouldResolveSupportAction(score float64) bool {
 
 func supportResolvedAction(config SupportAgentConfig) *Action {
 	return &Action{
-		Type:       supportActionUpdateCase,
-		Details:    "Applied solution from knowledge base",
-		CaseID:     config.CaseID,
-		Status:     "resolved",
-		Confidence: 90,
-		NextSteps:  []string{"send_resolution_email"},
-		Metadata:   config.Priority,
+		Type:          supportActionUpdateCase,
+		Details:       "Applied solution from knowledge base",
+		CaseID:        config.CaseID,
+		Status:        "resolved",
+		Confidence:    90,
+		NextSteps:     []string{"send_resolution_email"},
+		Metadata:      config.Priority,
+		ReplyToNoteID: config.ReplyToNoteID,
 	}
 }
 
@@ -606,12 +702,13 @@ func supportEscalatedAction(config SupportAgentConfig) *Action {
 
 func supportAbstainedAction(config SupportAgentConfig) *Action {
 	return &Action{
-		Type:       supportActionAbstain,
-		Details:    "Evidence is not strong enough to resolve the case automatically",
-		CaseID:     config.CaseID,
-		Status:     "open",
-		Confidence: 50,
-		NextSteps:  []string{"await_human_review_if_customer_replies"},
+		Type:          supportActionAbstain,
+		Details:       "Evidence is not strong enough to resolve the case automatically",
+		CaseID:        config.CaseID,
+		Status:        "open",
+		Confidence:    50,
+		NextSteps:     []string{"await_human_review_if_customer_replies"},
+		ReplyToNoteID: config.ReplyToNoteID,
 	}
 }
 
@@ -639,11 +736,15 @@ func (a *SupportAgent) appendReplyToolCall(
 	action *Action,
 	caseContext *CaseContext,
 ) error {
-	replyOut, err := a.executeTool(toolCtx, caseContext.WorkspaceID, tool.BuiltinSendReply, map[string]any{
+	params := map[string]any{
 		"case_id":     action.CaseID,
 		"body":        buildSupportReply(caseContext, action),
 		"is_internal": false,
-	})
+	}
+	if action.ReplyToNoteID != "" {
+		params["parent_note_id"] = action.ReplyToNoteID
+	}
+	replyOut, err := a.executeTool(toolCtx, caseContext.WorkspaceID, tool.BuiltinSendReply, params)
 	if err != nil {
 		return err
 	}
@@ -669,6 +770,28 @@ func (a *SupportAgent) appendEscalationTaskToolCall(
 	return nil
 }
 
+// appendEscalationAssignment hands the escalated case to the next human in
+// the rotation. It is best effort: a failed assignment must not undo the
+// escalation, which has already been handed off, so the error is logged and
+// recorded in the run's tool calls instead of failing the run.
+func (a *SupportAgent) appendEscalationAssignment(ctx context.Context, toolCalls *[]map[string]any, caseContext *CaseContext) {
+	if a.caseAssigner == nil {
+		return
+	}
+	assigned, err := a.caseAssigner.AutoAssign(ctx, caseContext.WorkspaceID, caseContext.ID, crm.AutoAssignInput{})
+	if err != nil {
+		logging.FromContext(ctx).Warn("auto-assign escalated case failed",
+			slog.String(logging.KeyComponent, supportComponent),
+			slog.String(logging.KeyWorkspaceID, caseContext.WorkspaceID),
+			slog.String("case_id", caseContext.ID), logging.Err(err))
+		result, _ := json.Marshal(map[string]any{"case_id": caseContext.ID, "error": err.Error()})
+		*toolCalls = append(*toolCalls, supportToolCall(supportToolCaseAutoAssigned, result))
+		return
+	}
+	result, _ := json.Marshal(map[string]any{"case_id": assigned.ID, "owner_id": assigned.OwnerID})
+	*toolCalls = append(*toolCalls, supportToolCall(supportToolCaseAutoAssigned, result))
+}
+
 func supportToolCall(toolName string, result json.RawMessage) map[string]any {
 	return map[string]any{
 		"tool_name":   toolName,
@@ -914,7 +1037,7 @@ func firstJSONStringFromRaw(raw json.RawMessage, key string) string {
 
 func (a *SupportAgent) executeTool(ctx context.Context, workspaceID, toolName string, payload map[string]any) (json.RawMessage, error) {
 	raw, _ := json.Marshal(payload)
-	result, err := a.toolRegistry.Execute(ctx, workspaceID, toolName, raw)
+	result, err := executeGuardedTool(ctx, a.toolRegistry, workspaceID, toolName, raw)
 	if err != nil {
 		return nil, fmt.Errorf("execute tool %s: %w", toolName, err)
 	}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,8 +84,9 @@ func newTestSupportAgent(t *testing.T, db *sql.DB, search SupportEvidenceBuilder
 	orch := agent.NewOrchestrator(db)
 	registry := tool.NewToolRegistry(db)
 	if err := tool.RegisterBuiltInExecutors(registry, tool.BuiltinServices{
-		DB:   db,
-		Case: crm.NewCaseService(db),
+		DB:       db,
+		Case:     crm.NewCaseService(db),
+		Activity: crm.NewActivityService(db),
 	}); err != nil {
 		t.Fatalf("register builtins: %v", err)
 	}
@@ -208,6 +210,20 @@ func TestSupportAgent_Run_EscalatesWhenNoKnowledge(t *testing.T) {
 	if stored.Status != agent.StatusEscalated {
 		t.Fatalf("expected escalated, got %s", stored.Status)
 	}
+	var steps []agent.ReasoningStep
+	if err = json.Unmarshal(stored.ReasoningTrace, &steps); err != nil {
+		t.Fatalf("unmarshal reasoning trace %s: %v", stored.ReasoningTrace, err)
+	}
+	var stepNames []string
+	for _, step := range steps {
+		if step.Timestamp.IsZero() {
+			t.Fatalf("step %q has no timestamp", step.Step)
+		}
+		stepNames = append(stepNames, step.Step)
+	}
+	if got := strings.Join(stepNames, ","); got != "analyze,search,policy,decide,execute" {
+		t.Fatalf("reasoning steps = %s", got)
+	}
 
 	caseTicket, err := crm.NewCaseService(db).Get(context.Background(), wsID, caseID)
 	if err != nil {
@@ -218,6 +234,84 @@ func TestSupportAgent_Run_EscalatesWhenNoKnowledge(t *testing.T) {
 	}
 }
 
+func TestSupportAgent_Run_EscalationAutoAssignsCase(t *testing.T) {
+	db := setupAgentTestDB(t)
+	defer db.Close()
+
+	wsID, ownerID := seedSupportWorkspace(t, db)
+	insertSupportAgentDefinition(t, db, wsID)
+	caseID := seedSupportCase(t, db, wsID, ownerID, "high")
+	sa := newTestSupportAgent(t, db, &mockKnowledgeSearch{results: emptyResults()})
+	sa.SetCaseAssigner(crm.NewCaseService(db))
+
+	run, err := sa.Run(supportRunContext(context.Background(), wsID, ownerID), SupportAgentConfig{
+		WorkspaceID:   wsID,
+		CaseID:        caseID,
+		CustomerQuery: "I need help",
+		Priority:      "high",
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	var method, toOwner string
+	if err = db.QueryRow(
+		`SELECT method, to_owner_id FROM case_assignment_history WHERE workspace_id = ? AND case_id = ?`,
+		wsID, caseID,
+	).Scan(&method, &toOwner); err != nil {
+		t.Fatalf("query case_assignment_history: %v", err)
+	}
+	if method != "auto" || toOwner != ownerID {
+		t.Fatalf("assignment = %s to %s; want auto to %s", method, toOwner, ownerID)
+	}
+
+	stored, err := agent.NewOrchestrator(db).GetAgentRun(context.Background(), wsID, run.ID)
+	if err != nil {
+		t.Fatalf("load run: %v", err)
+	}
+	if !strings.Contains(string(stored.ToolCalls), supportToolCaseAutoAssigned) {
+		t.Fatalf("tool calls %s missing %s", stored.ToolCalls, supportToolCaseAutoAssigned)
+	}
+}
+
+type failingCaseAssigner struct{}
+
+func (failingCaseAssigner) AutoAssign(context.Context, string, string, crm.AutoAssignInput) (*crm.CaseTicket, error) {
+	return nil, errors.New("no active users to assign")
+}
+
+func TestSupportAgent_Run_RecordsFailedAutoAssign(t *testing.T) {
+	db := setupAgentTestDB(t)
+	defer db.Close()
+
+	wsID, ownerID := seedSupportWorkspace(t, db)
+	insertSupportAgentDefinition(t, db, wsID)
+	caseID := seedSupportCase(t, db, wsID, ownerID, "high")
+	sa := newTestSupportAgent(t, db, &mockKnowledgeSearch{results: emptyResults()})
+	sa.SetCaseAssigner(failingCaseAssigner{})
+
+	run, err := sa.Run(supportRunContext(context.Background(), wsID, ownerID), SupportAgentConfig{
+		WorkspaceID:   wsID,
+		CaseID:        caseID,
+		CustomerQuery: "I need help",
+		Priority:      "high",
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	stored, err := agent.NewOrchestrator(db).GetAgentRun(context.Background(), wsID, run.ID)
+	if err != nil {
+		t.Fatalf("load run: %v", err)
+	}
+	if stored.Status != agent.StatusEscalated {
+		t.Fatalf("status = %s; want escalated despite the failed assignment", stored.Status)
+	}
+	if !strings.Contains(string(stored.ToolCalls), "no active users to assign") {
+		t.Fatalf("tool calls %s missing the auto-assign error", stored.ToolCalls)
+	}
+}
+
 func TestSupportAgent_Run_ResolvesWhenHighConfidence(t *testing.T) {
 	db := setupAgentTestDB(t)
 	defer db.Close()
@@ -275,6 +369,263 @@ func TestSupportAgent_Run_ResolvesWhenHighConfidence(t *testing.T) {
 	}
 }
 
+func TestSupportAgent_Run_PersistsToolCallsAndOutput(t *testing.T) {
+	db := setupAgentTestDB(t)
+	defer db.Close()
+
+	wsID, ownerID := seedSupportWorkspace(t, db)
+	insertSupportAgentDefinition(t, db, wsID)
+	caseID := seedSupportCase(t, db, wsID, ownerID, "medium")
+	sa := newTestSupportAgent(t, db, &mockKnowledgeSearch{
+		results: &knowledge.SearchResults{
+			Items: []knowledge.SearchResult{{KnowledgeItemID: "ki-1", Score: 0.9, Snippet: "restart the service"}},
+		},
+	})
+
+	run, err := sa.Run(supportRunContext(context.Background(), wsID, ownerID), SupportAgentConfig{
+		WorkspaceID:   wsID,
+		CaseID:        caseID,
+		CustomerQuery: "service is down",
+		Priority:      "medium",
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	var toolCallsJSON, outputJSON, queriesJSON, evidenceJSON string
+	var totalCost sql.NullFloat64
+	var latencyMs sql.NullInt64
+	if err = db.QueryRow(`SELECT tool_calls, output, retrieval_queries, retrieved_evidence_ids, total_cost, latency_ms FROM agent_run WHERE id = ?`, run.ID).
+		Scan(&toolCallsJSON, &outputJSON, &queriesJSON, &evidenceJSON, &totalCost, &latencyMs); err != nil {
+		t.Fatalf("load agent_run: %v", err)
+	}
+	if !strings.Contains(queriesJSON, "service is down") || !strings.Contains(evidenceJSON, "ki-1") {
+		t.Fatalf("knowledge search not recorded: retrieval_queries=%s retrieved_evidence_ids=%s", queriesJSON, evidenceJSON)
+	}
+
+	var toolCalls []map[string]any
+	if err = json.Unmarshal([]byte(toolCallsJSON), &toolCalls); err != nil {
+		t.Fatalf("decode tool_calls %s: %v", toolCallsJSON, err)
+	}
+	want := []string{tool.BuiltinUpdateCase, tool.BuiltinSendReply}
+	if len(toolCalls) != len(want) {
+		t.Fatalf("tool_calls = %s; want %v", toolCallsJSON, want)
+	}
+	for i, name := range want {
+		if toolCalls[i]["tool_name"] != name {
+			t.Fatalf("tool_calls[%d] = %v; want %s", i, toolCalls[i]["tool_name"], name)
+		}
+	}
+	if toolCalls[0]["result"] == nil || toolCalls[1]["result"] == nil {
+		t.Fatalf("expected registry results on the write tool calls, got %s", toolCallsJSON)
+	}
+
+	var output SupportOutput
+	if err = json.Unmarshal([]byte(outputJSON), &output); err != nil {
+		t.Fatalf("decode output %s: %v", outputJSON, err)
+	}
+	if output.Type != supportActionUpdateCase || output.CaseID != caseID || output.Status != "resolved" {
+		t.Fatalf("output = %+v", output)
+	}
+	if !totalCost.Valid || totalCost.Float64 <= 0 || !latencyMs.Valid {
+		t.Fatalf("expected cost and latency, got cost=%v latency=%v", totalCost, latencyMs)
+	}
+}
+
+func TestSupportAgent_Run_AuditsKnowledgeSearchAsToolCall(t *testing.T) {
+	db := setupAgentTestDB(t)
+	defer db.Close()
+
+	wsID, ownerID := seedSupportWorkspace(t, db)
+	insertSupportAgentDefinition(t, db, wsID)
+	caseID := seedSupportCase(t, db, wsID, ownerID, "medium")
+	auditService := audit.NewAuditService(db)
+	registry := tool.NewToolRegistryWithRuntime(db, nil, auditService)
+	if err := tool.RegisterBuiltInExecutors(registry, tool.BuiltinServices{
+		DB:       db,
+		Case:     crm.NewCaseService(db),
+		Activity: crm.NewActivityService(db),
+	}); err != nil {
+		t.Fatalf("register builtins: %v", err)
+	}
+	if err := registry.EnsureBuiltInToolDefinitionsForAllWorkspaces(context.Background()); err != nil {
+		t.Fatalf("ensure builtins: %v", err)
+	}
+	sa := NewSupportAgentWithDB(agent.NewOrchestrator(db), registry, &mockKnowledgeSearch{
+		results: &knowledge.SearchResults{
+			Items: []knowledge.SearchResult{{KnowledgeItemID: "ki-1", Score: 0.9, Snippet: "restart the service"}},
+		},
+	}, db)
+
+	run, err := sa.Run(supportRunContext(context.Background(), wsID, ownerID), SupportAgentConfig{
+		WorkspaceID:   wsID,
+		CaseID:        caseID,
+		CustomerQuery: "service is down",
+		Priority:      "medium",
+	})
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	events, err := auditService.ListByAction(context.Background(), wsID, tool.SearchKnowledge, 10, 0)
+	if err != nil {
+		t.Fatalf("list audit events: %v", err)
+	}
+	if len(events) != 1 || events[0].ActorID != run.ID || events[0].Outcome != audit.OutcomeSuccess {
+		t.Fatalf("search_knowledge audit events = %+v; want one success by run %s", events, run.ID)
+	}
+}
+
+func TestSupportAgent_Run_KnowledgeSearchCountsTowardMaxToolCalls(t *testing.T) {
+	db := setupAgentTestDB(t)
+	defer db.Close()
+
+	wsID, ownerID := seedSupportWorkspace(t, db)
+	insertSupportAgentDefinition(t, db, wsID)
+	// get_case and search_knowledge use up the limit before any write.
+	if _, err := db.Exec(`UPDATE agent_definition SET limits = '{"max_tool_calls":2}' WHERE id = 'support-agent'`); err != nil {
+		t.Fatalf("set limits: %v", err)
+	}
+	caseID := seedSupportCase(t, db, wsID, ownerID, "medium")
+	sa := newTestSupportAgent(t, db, &mockKnowledgeSearch{
+		results: &knowledge.SearchResults{
+			Items: []knowledge.SearchResult{{KnowledgeItemID: "ki-1", Score: 0.9, Snippet: "restart the service"}},
+		},
+	})
+
+	_, err := sa.Run(supportRunContext(context.Background(), wsID, ownerID), SupportAgentConfig{
+		WorkspaceID:   wsID,
+		CaseID:        caseID,
+		CustomerQuery: "service is down",
+		Priority:      "medium",
+	})
+	if !errors.Is(err, agent.ErrRunLimitExceeded) || !strings.Contains(err.Error(), tool.BuiltinUpdateCase) {
+		t.Fatalf("err = %v; want the first write tool over the limit the search used", err)
+	}
+}
+
+func TestSupportAgent_Run_FailsWhenEvidencePackCannotBeStored(t *testing.T) {
+	db := setupAgentTestDB(t)
+	defer db.Close()
+
+	wsID, ownerID := seedSupportWorkspace(t, db)
+	insertSupportAgentDefinition(t, db, wsID)
+	caseID := seedSupportCase(t, db, wsID, ownerID, "medium")
+	storeErr := errors.New("evidence: store run evidence pack: disk full")
+	sa := newTestSupportAgent(t, db, &mockKnowledgeSearch{err: storeErr})
+
+	run, err := sa.Run(supportRunContext(context.Background(), wsID, ownerID), SupportAgentConfig{
+		WorkspaceID:   wsID,
+		CaseID:        caseID,
+		CustomerQuery: "service is down",
+		Priority:      "medium",
+	})
+	if !errors.Is(err, storeErr) {
+		t.Fatalf("expected the storage error, got %v", err)
+	}
+
+	stored, err := agent.NewOrchestrator(db).GetAgentRun(context.Background(), wsID, run.ID)
+	if err != nil {
+		t.Fatalf("load run: %v", err)
+	}
+	if stored.Status != agent.StatusFailed {
+		t.Fatalf("expected failed, got %s", stored.Status)
+	}
+}
+
+func TestSupportAgent_Run_FailsWhenMaxToolCallsExceeded(t *testing.T) {
+	db := setupAgentTestDB(t)
+	defer db.Close()
+
+	wsID, ownerID := seedSupportWorkspace(t, db)
+	insertSupportAgentDefinition(t, db, wsID)
+	if _, err := db.Exec(`UPDATE agent_definition SET limits = '{"max_tool_calls":1}' WHERE id = 'support-agent'`); err != nil {
+		t.Fatalf("set limits: %v", err)
+	}
+	caseID := seedSupportCase(t, db, wsID, ownerID, "medium")
+	sa := newTestSupportAgent(t, db, &mockKnowledgeSearch{
+		results: &knowledge.SearchResults{
+			Items: []knowledge.SearchResult{{KnowledgeItemID: "ki-1", Score: 0.9, Snippet: "restart the service"}},
+		},
+	})
+
+	run, err := sa.Run(supportRunContext(context.Background(), wsID, ownerID), SupportAgentConfig{
+		WorkspaceID:   wsID,
+		CaseID:        caseID,
+		CustomerQuery: "service is down",
+		Priority:      "medium",
+	})
+	if !errors.Is(err, agent.ErrRunLimitExceeded) {
+		t.Fatalf("expected ErrRunLimitExceeded, got %v", err)
+	}
+	if run == nil {
+		t.Fatal("expected failed run to be returned")
+	}
+
+	stored, err := agent.NewOrchestrator(db).GetAgentRun(context.Background(), wsID, run.ID)
+	if err != nil {
+		t.Fatalf("load run: %v", err)
+	}
+	if stored.Status != agent.StatusFailed {
+		t.Fatalf("expected failed, got %s", stored.Status)
+	}
+	if !strings.Contains(string(stored.Output), "max_tool_calls=1") {
+		t.Fatalf("expected limit reason in output, got %s", stored.Output)
+	}
+
+	caseTicket, err := crm.NewCaseService(db).Get(context.Background(), wsID, caseID)
+	if err != nil {
+		t.Fatalf("get case: %v", err)
+	}
+	if caseTicket.Status == "resolved" {
+		t.Fatal("expected case to stay unresolved after aborted run")
+	}
+}
+
+func TestSupportAgent_Run_ThreadsReplyUnderCustomerMessage(t *testing.T) {
+	db := setupAgentTestDB(t)
+	defer db.Close()
+
+	wsID, ownerID := seedSupportWorkspace(t, db)
+	insertSupportAgentDefinition(t, db, wsID)
+	caseID := seedSupportCase(t, db, wsID, ownerID, "medium")
+	question, err := crm.NewNoteService(db).Create(context.Background(), crm.CreateNoteInput{
+		WorkspaceID: wsID,
+		EntityType:  "case",
+		EntityID:    caseID,
+		AuthorID:    ownerID,
+		Content:     "service is down again",
+	})
+	if err != nil {
+		t.Fatalf("create customer note: %v", err)
+	}
+	sa := newTestSupportAgent(t, db, &mockKnowledgeSearch{
+		results: &knowledge.SearchResults{
+			Items: []knowledge.SearchResult{{KnowledgeItemID: "ki-1", Score: 0.9, Snippet: "restart the service"}},
+		},
+	})
+
+	if _, err = sa.Run(supportRunContext(context.Background(), wsID, ownerID), SupportAgentConfig{
+		WorkspaceID:   wsID,
+		CaseID:        caseID,
+		CustomerQuery: "service is down",
+		ReplyToNoteID: question.ID,
+	}); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+
+	threads, err := crm.NewNoteService(db).ListThread(context.Background(), wsID, "case", caseID)
+	if err != nil {
+		t.Fatalf("ListThread() error = %v", err)
+	}
+	if len(threads) != 1 || threads[0].ID != question.ID || len(threads[0].Replies) != 1 {
+		t.Fatalf("threads = %+v; want the agent reply under %s", threads, question.ID)
+	}
+	if threads[0].Replies[0].IsInternal {
+		t.Fatal("expected a customer-facing reply")
+	}
+}
+
 func TestSupportAgent_Run_AbstainsWhenConfidenceIsMedium(t *testing.T) {
 	db := setupAgentTestDB(t)
 	defer db.Close()
@@ -574,8 +925,9 @@ func TestSupportAgent_Run_RecordsUsageForCompletedRun(t *testing.T) {
 	insertSupportAgentDefinition(t, db, wsID)
 	registry := tool.NewToolRegistry(db)
 	if err := tool.RegisterBuiltInExecutors(registry, tool.BuiltinServices{
-		DB:   db,
-		Case: crm.NewCaseService(db),
+		DB:       db,
+		Case:     crm.NewCaseService(db),
+		Activity: crm.NewActivityService(db),
 	}); err != nil {
 		t.Fatalf("register builtins: %v", err)
 	}
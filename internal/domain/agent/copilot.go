@@ -0,0 +1,76 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Trigger context keys recorded on copilot runs.
+const (
+	TriggerContextConversationID = "conversation_id"
+	TriggerContextConversation   = "conversation"
+)
+
+var (
+	ErrCopilotUserRequired         = errors.New("copilot runs require the initiating user")
+	ErrCopilotConversationRequired = errors.New("copilot runs require a conversation id")
+)
+
+// CopilotMessage is one turn of the copilot conversation that led to a run.
+type CopilotMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// CopilotConversation is the inline conversation a copilot run was started
+// from. TriggerAgent records it in the run's trigger_context.
+type CopilotConversation struct {
+	ID       string
+	Messages []CopilotMessage
+}
+
+// ConversationID returns the copilot conversation a run was started from, or "".
+func (r *Run) ConversationID() string {
+	var tc map[string]any
+	if err := json.Unmarshal(r.TriggerContext, &tc); err != nil {
+		return ""
+	}
+	id, _ := tc[TriggerContextConversationID].(string)
+	return id
+}
+
+// copilotTriggerContext validates a copilot trigger and merges its
+// conversation into the trigger context. Inputs that already carry a
+// conversation id (e.g. replays of a copilot run) keep it.
+func copilotTriggerContext(in TriggerAgentInput) (json.RawMessage, error) {
+	if in.TriggeredBy == nil || *in.TriggeredBy == "" {
+		return nil, ErrCopilotUserRequired
+	}
+
+	tc := map[string]any{}
+	trimmed := bytes.TrimSpace(in.TriggerContext)
+	if len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) {
+		if err := json.Unmarshal(trimmed, &tc); err != nil {
+			tc = map[string]any{"original_trigger_context": json.RawMessage(trimmed)}
+		}
+	}
+	if in.Conversation != nil && in.Conversation.ID != "" {
+		tc[TriggerContextConversationID] = in.Conversation.ID
+		messages := in.Conversation.Messages
+		if messages == nil {
+			messages = []CopilotMessage{}
+		}
+		tc[TriggerContextConversation] = messages
+	}
+	if id, _ := tc[TriggerContextConversationID].(string); id == "" {
+		return nil, ErrCopilotConversationRequired
+	}
+
+	out, err := json.Marshal(tc)
+	if err != nil {
+		return nil, fmt.Errorf("encode copilot trigger context: %w", err)
+	}
+	return out, nil
+}
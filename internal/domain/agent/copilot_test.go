@@ -0,0 +1,116 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func insertCopilotTestAgent(t *testing.T, orch *Orchestrator) {
+	t.Helper()
+	if _, err := orch.db.ExecContext(context.Background(),
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		 VALUES ('agent-copilot', 'ws-copilot', 'Copilot Agent', 'support', 'active')`); err != nil {
+		t.Fatalf("insert agent_definition: %v", err)
+	}
+}
+
+func TestTriggerAgent_CopilotRecordsUserAndConversation(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	orch := NewOrchestrator(db)
+	insertCopilotTestAgent(t, orch)
+	userID := "user-1"
+
+	run, err := orch.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:        "agent-copilot",
+		WorkspaceID:    "ws-copilot",
+		TriggeredBy:    &userID,
+		TriggerType:    TriggerTypeCopilot,
+		TriggerContext: json.RawMessage(`{"case_id":"case-1"}`),
+		Conversation: &CopilotConversation{
+			ID:       "conv-1",
+			Messages: []CopilotMessage{{Role: "user", Content: "why is this case open?"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+
+	stored, err := orch.GetAgentRun(ctx, "ws-copilot", run.ID)
+	if err != nil {
+		t.Fatalf("GetAgentRun: %v", err)
+	}
+	if stored.TriggerType != TriggerTypeCopilot || stored.TriggeredByUserID == nil || *stored.TriggeredByUserID != userID {
+		t.Fatalf("unexpected copilot run: %+v", stored)
+	}
+	if stored.ConversationID() != "conv-1" {
+		t.Fatalf("ConversationID() = %q; want conv-1", stored.ConversationID())
+	}
+	var tc struct {
+		CaseID       string           `json:"case_id"`
+		Conversation []CopilotMessage `json:"conversation"`
+	}
+	if err := json.Unmarshal(stored.TriggerContext, &tc); err != nil {
+		t.Fatalf("decode trigger_context: %v", err)
+	}
+	if tc.CaseID != "case-1" || len(tc.Conversation) != 1 || tc.Conversation[0].Content != "why is this case open?" {
+		t.Fatalf("unexpected trigger_context: %s", stored.TriggerContext)
+	}
+
+	replay, err := orch.ReplayRun(ctx, "ws-copilot", run.ID)
+	if err != nil {
+		t.Fatalf("ReplayRun: %v", err)
+	}
+	if replay.ConversationID() != "conv-1" {
+		t.Fatalf("replay ConversationID() = %q; want conv-1", replay.ConversationID())
+	}
+
+	runs, total, err := orch.ListAgentRuns(ctx, "ws-copilot", ListRunsInput{TriggerType: TriggerTypeManual})
+	if err != nil {
+		t.Fatalf("ListAgentRuns: %v", err)
+	}
+	if total != 0 || len(runs) != 0 {
+		t.Fatalf("manual filter returned %d runs (total %d); want none", len(runs), total)
+	}
+	_, total, err = orch.ListAgentRuns(ctx, "ws-copilot", ListRunsInput{TriggerType: TriggerTypeCopilot})
+	if err != nil {
+		t.Fatalf("ListAgentRuns: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("copilot filter total = %d; want 2", total)
+	}
+}
+
+func TestTriggerAgent_CopilotRequiresUserAndConversation(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	orch := NewOrchestrator(db)
+	insertCopilotTestAgent(t, orch)
+	userID := "user-1"
+
+	_, err := orch.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:      "agent-copilot",
+		WorkspaceID:  "ws-copilot",
+		TriggerType:  TriggerTypeCopilot,
+		Conversation: &CopilotConversation{ID: "conv-1"},
+	})
+	if !errors.Is(err, ErrCopilotUserRequired) {
+		t.Fatalf("err = %v; want ErrCopilotUserRequired", err)
+	}
+
+	_, err = orch.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:     "agent-copilot",
+		WorkspaceID: "ws-copilot",
+		TriggeredBy: &userID,
+		TriggerType: TriggerTypeCopilot,
+	})
+	if !errors.Is(err, ErrCopilotConversationRequired) {
+		t.Fatalf("err = %v; want ErrCopilotConversationRequired", err)
+	}
+}
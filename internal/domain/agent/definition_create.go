@@ -0,0 +1,192 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+var (
+	ErrInvalidAgentDefinition = errors.New("invalid agent definition")
+	// ErrUnknownAllowedTool is returned by CreateAgentDefinition when
+	// allowed_tools names a tool the workspace does not have.
+	ErrUnknownAllowedTool    = errors.New("unknown allowed tool")
+	ErrAgentDefinitionExists = errors.New("agent definition already exists")
+)
+
+// ToolCatalog lists the tool names known to a workspace.
+// Implemented by tool.ToolRegistry.
+type ToolCatalog interface {
+	KnownToolNames(ctx context.Context, workspaceID string) (map[string]bool, error)
+}
+
+// ToolRequirer is implemented by runners whose agent type depends on
+// specific tools. CreateAgentDefinition warns when a definition of that type
+// does not allow one of them.
+type ToolRequirer interface {
+	RequiredTools() []string
+}
+
+// SetToolCatalog enables allowed_tools validation in CreateAgentDefinition.
+func (o *Orchestrator) SetToolCatalog(catalog ToolCatalog) {
+	o.toolCatalog = catalog
+}
+
+// CreateAgentDefinitionInput holds the fields of a new agent definition.
+type CreateAgentDefinitionInput struct {
+	WorkspaceID   string
+	Name          string
+	Description   *string
+	AgentType     string
+	Objective     json.RawMessage
+	AllowedTools  []string
+	Limits        json.RawMessage
+	TriggerConfig json.RawMessage
+	PolicySetID   *string
+}
+
+// CreateAgentDefinition stores a new active agent definition. With a tool
+// catalog set, every allowed_tools entry must name a tool known to the
+// workspace, otherwise nothing is stored and ErrUnknownAllowedTool lists the
+// offending names. The returned warnings name the tools the runner of the
+// agent type requires but the definition does not allow; they are logged
+// too, since such runs cannot use those tools.
+func (o *Orchestrator) CreateAgentDefinition(ctx context.Context, in CreateAgentDefinitionInput) (*Definition, []string, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	in.AgentType = strings.TrimSpace(in.AgentType)
+	if in.Name == "" {
+		return nil, nil, fmt.Errorf("%w: name is required", ErrInvalidAgentDefinition)
+	}
+	if in.AgentType == "" {
+		return nil, nil, fmt.Errorf("%w: agent_type is required", ErrInvalidAgentDefinition)
+	}
+	allowed := normalizeToolNames(in.AllowedTools)
+	known, err := o.knownToolNames(ctx, in.WorkspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if unknown := unknownTools(allowed, known); len(unknown) > 0 {
+		return nil, nil, fmt.Errorf("%w: %s (not registered in the workspace)",
+			ErrUnknownAllowedTool, strings.Join(unknown, ", "))
+	}
+
+	allowedJSON, err := json.Marshal(allowed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode allowed_tools: %w", err)
+	}
+	triggerConfig, err := o.sealWebhookSecret(in.TriggerConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	created, err := sqlcgen.New(o.db).CreateAgentDefinition(ctx, sqlcgen.CreateAgentDefinitionParams{
+		ID:            uuid.NewV7().String(),
+		WorkspaceID:   in.WorkspaceID,
+		Name:          in.Name,
+		Description:   in.Description,
+		AgentType:     in.AgentType,
+		Objective:     jsonOr(in.Objective, emptyJSONObject),
+		AllowedTools:  allowedJSON,
+		Limits:        jsonOr(in.Limits, emptyJSONObject),
+		TriggerConfig: jsonOr(triggerConfig, emptyJSONObject),
+		PolicySetID:   in.PolicySetID,
+	})
+	if err != nil {
+		if sqlite.IsUniqueViolation(err) {
+			return nil, nil, fmt.Errorf("%w: %q", ErrAgentDefinitionExists, in.Name)
+		}
+		return nil, nil, fmt.Errorf("insert agent definition: %w", err)
+	}
+	def, err := o.getAgentDefinition(ctx, created.ID, in.WorkspaceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warnings := o.missingToolWarnings(in.AgentType, allowed, known)
+	for _, w := range warnings {
+		logging.FromContext(ctx).WarnContext(ctx, "agent definition misses a required tool",
+			slog.String(logging.KeyComponent, orchestratorComponent),
+			slog.String("agent_definition_id", def.ID),
+			slog.String("warning", w))
+	}
+	return def, warnings, nil
+}
+
+// knownToolNames returns nil when no catalog is set, which skips validation.
+func (o *Orchestrator) knownToolNames(ctx context.Context, workspaceID string) (map[string]bool, error) {
+	if o.toolCatalog == nil {
+		return nil, nil
+	}
+	known, err := o.toolCatalog.KnownToolNames(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list known tools: %w", err)
+	}
+	return known, nil
+}
+
+// missingToolWarnings reports the tools required by the agent type's runner
+// that allowed lacks. Required tools the workspace does not know are skipped:
+// they could not be allowed anyway.
+func (o *Orchestrator) missingToolWarnings(agentType string, allowed []string, known map[string]bool) []string {
+	if o.runnerRegistry == nil {
+		return nil
+	}
+	runner, ok := o.runnerRegistry.Get(agentType)
+	if !ok {
+		return nil
+	}
+	requirer, ok := runner.(ToolRequirer)
+	if !ok {
+		return nil
+	}
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		allowedSet[name] = true
+	}
+	var warnings []string
+	for _, name := range requirer.RequiredTools() {
+		if allowedSet[name] || (known != nil && !known[name]) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("agent type %q requires tool %q, which allowed_tools does not include", agentType, name))
+	}
+	return warnings
+}
+
+// normalizeToolNames trims the names and drops empty and repeated ones,
+// keeping the first occurrence order.
+func normalizeToolNames(names []string) []string {
+	out := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	return out
+}
+
+func unknownTools(names []string, known map[string]bool) []string {
+	if known == nil {
+		return nil
+	}
+	var unknown []string
+	for _, name := range names {
+		if !known[name] {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
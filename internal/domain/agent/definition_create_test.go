@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type stubToolCatalog map[string]bool
+
+func (c stubToolCatalog) KnownToolNames(context.Context, string) (map[string]bool, error) {
+	return c, nil
+}
+
+// toolRequirerRunner is a runner whose agent type needs tools.
+type toolRequirerRunner struct {
+	stubRunner
+	required []string
+}
+
+func (r toolRequirerRunner) RequiredTools() []string { return r.required }
+
+func newDefinitionTestOrchestrator(t *testing.T) *Orchestrator {
+	t.Helper()
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	registry := NewRunnerRegistry()
+	if err := registry.Register("support", toolRequirerRunner{required: []string{"update_case", "send_reply", "search_knowledge"}}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	orch := NewOrchestratorWithRegistry(db, registry)
+	orch.SetToolCatalog(stubToolCatalog{"update_case": true, "send_reply": true, "create_task": true})
+	return orch
+}
+
+func TestCreateAgentDefinition_RejectsUnknownTool(t *testing.T) {
+	orch := newDefinitionTestOrchestrator(t)
+	ctx := context.Background()
+
+	_, _, err := orch.CreateAgentDefinition(ctx, CreateAgentDefinitionInput{
+		WorkspaceID:  "ws-1",
+		Name:         "Support",
+		AgentType:    "support",
+		AllowedTools: []string{"update_case", "send_repyl"},
+	})
+	if !errors.Is(err, ErrUnknownAllowedTool) {
+		t.Fatalf("CreateAgentDefinition err = %v; want ErrUnknownAllowedTool", err)
+	}
+	if !strings.Contains(err.Error(), `"send_repyl"`) {
+		t.Errorf("error %q does not name the unknown tool", err)
+	}
+
+	defs, err := orch.ListAgentDefinitions(ctx, "ws-1")
+	if err != nil {
+		t.Fatalf("ListAgentDefinitions: %v", err)
+	}
+	if len(defs) != 0 {
+		t.Fatalf("stored %d definitions; want none", len(defs))
+	}
+}
+
+func TestCreateAgentDefinition_WarnsOnMissingRequiredTool(t *testing.T) {
+	orch := newDefinitionTestOrchestrator(t)
+	ctx := context.Background()
+
+	def, warnings, err := orch.CreateAgentDefinition(ctx, CreateAgentDefinitionInput{
+		WorkspaceID:  "ws-1",
+		Name:         " Support ",
+		AgentType:    "support",
+		AllowedTools: []string{"update_case", "create_task", "update_case"},
+	})
+	if err != nil {
+		t.Fatalf("CreateAgentDefinition: %v", err)
+	}
+	if def.Name != "Support" || def.Status != agentStatusActive {
+		t.Errorf("definition = %+v; want active Support", def)
+	}
+	if want := []string{"update_case", "create_task"}; !reflect.DeepEqual(def.AllowedTools, want) {
+		t.Errorf("allowed tools = %v; want %v", def.AllowedTools, want)
+	}
+	// search_knowledge is required too, but the workspace does not know it.
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `"send_reply"`) {
+		t.Errorf("warnings = %v; want one about send_reply", warnings)
+	}
+
+	_, _, err = orch.CreateAgentDefinition(ctx, CreateAgentDefinitionInput{WorkspaceID: "ws-1", Name: "Support", AgentType: "support"})
+	if !errors.Is(err, ErrAgentDefinitionExists) {
+		t.Errorf("duplicate name err = %v; want ErrAgentDefinitionExists", err)
+	}
+}
@@ -75,6 +75,7 @@ func (r *DSLRunner) Run(ctx context.Context, rc *RunContext, input TriggerAgentI
 	if err != nil {
 		return nil, err
 	}
+	ctx = WithRunID(ctx, run.ID)
 	evalCtx := mergeDSLContexts(input.TriggerContext, input.Inputs)
 	carta := parseCartaWorkflowSpec(workflow)
 	if early, earlyErr := r.runPreflights(ctx, rc, workflow, carta, input, run, evalCtx); earlyErr != nil || early != nil {
@@ -508,7 +509,7 @@ func (r *DSLRunner) applyGroundsAbstention(ctx context.Context, rc *RunContext,
 	updated, err := rc.Orchestrator.UpdateAgentRun(ctx, input.WorkspaceID, run.ID, RunUpdates{
 		Status:               StatusAbstained,
 		Output:               output,
-		AbstentionReason:     stringPtr(result.Reason),
+		AbstentionReason:     result.Code.Ptr(),
 		RetrievalQueries:     marshalStringArray(result.Query),
 		RetrievedEvidenceIDs: marshalEvidenceIDs(result.EvidencePack),
 		Completed:            true,
@@ -292,8 +292,8 @@ AGENT search_knowledge
 	if run.Status != StatusAbstained {
 		t.Fatalf("status = %s, want %s", run.Status, StatusAbstained)
 	}
-	if run.AbstentionReason == nil || *run.AbstentionReason == "" {
-		t.Fatalf("AbstentionReason = %v, want non-empty", run.AbstentionReason)
+	if run.AbstentionReason == nil || *run.AbstentionReason != string(AbstentionMissingContext) {
+		t.Fatalf("AbstentionReason = %v, want %s", run.AbstentionReason, AbstentionMissingContext)
 	}
 	if len(run.RetrievalQueries) == 0 {
 		t.Fatal("RetrievalQueries = empty, want grounds query")
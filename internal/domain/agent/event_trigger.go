@@ -0,0 +1,241 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+)
+
+// eventTriggerConfigKey names the trigger_config entry listing the event bus
+// topics that start a definition, e.g. {"events": ["lead.created"]}.
+const eventTriggerConfigKey = "events"
+
+// eventTriggerDepthKey is the trigger_context field recording how many
+// event-triggered agent runs led to a run: 0 for an event no agent caused,
+// the causing run's depth plus one otherwise.
+const eventTriggerDepthKey = "event_depth"
+
+const (
+	// maxEventTriggerDepth stops chains of agents reacting to each other's
+	// events (A updates a case, B runs and updates it, A runs again, ...).
+	maxEventTriggerDepth = 3
+	// maxConcurrentEventRuns bounds the event-triggered runs in flight.
+	maxConcurrentEventRuns = 8
+)
+
+// DefaultEventTriggerTopics are the domain events an EventTriggerBridge
+// listens to unless given its own list.
+var DefaultEventTriggerTopics = []string{
+	"lead.created",
+	"case.sla_breached",
+	"case.escalated",
+	"case.updated",
+	"deal.updated",
+	"signal.created",
+}
+
+// EventTriggerBridge starts agents from domain events. For each event on one
+// of its topics it executes every active definition in the event's workspace
+// whose trigger_config subscribes to that topic, with TriggerTypeEvent, the
+// event payload as trigger_context and as inputs (see eventRunInputs).
+// Events caused by an agent run (their payload names it as agent_run_id) do
+// not trigger that agent again, so an agent updating the record it was
+// triggered for does not loop, and start no run once the causing chain is
+// maxEventTriggerDepth runs deep, so agents reacting to each other stop.
+// At most maxConcurrentEventRuns runs execute at once.
+type EventTriggerBridge struct {
+	orchestrator *Orchestrator
+	bus          eventbus.EventBus
+	runContext   *RunContext
+	topics       []string
+	runs         sync.WaitGroup
+	slots        chan struct{}
+}
+
+// NewEventTriggerBridge creates a bridge executing runs with rc, for topics
+// or for DefaultEventTriggerTopics when none are given.
+func NewEventTriggerBridge(o *Orchestrator, bus eventbus.EventBus, rc *RunContext, topics ...string) *EventTriggerBridge {
+	if len(topics) == 0 {
+		topics = DefaultEventTriggerTopics
+	}
+	return &EventTriggerBridge{
+		orchestrator: o,
+		bus:          bus,
+		runContext:   rc,
+		topics:       topics,
+		slots:        make(chan struct{}, maxConcurrentEventRuns),
+	}
+}
+
+// Start consumes the bridge's topics until ctx is cancelled, then waits for
+// the runs it started. Runs in the calling goroutine — launch with:
+// go b.Start(ctx)
+func (b *EventTriggerBridge) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, topic := range b.topics {
+		ch := b.bus.Subscribe(topic)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case evt := <-ch:
+					b.handleEvent(ctx, evt)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	b.runs.Wait()
+}
+
+// handleEvent starts the definitions subscribed to evt, each run in its own
+// goroutine so a slow agent does not hold up the topic until every run slot
+// is taken. Events without a workspace are ignored; a definition that fails
+// to run is logged and does not stop the others.
+func (b *EventTriggerBridge) handleEvent(ctx context.Context, evt eventbus.Event) {
+	payload, err := json.Marshal(evt.Payload)
+	if err != nil {
+		return
+	}
+	var fields map[string]any
+	if json.Unmarshal(payload, &fields) != nil {
+		return
+	}
+	workspaceID := eventWorkspaceID(fields)
+	if workspaceID == "" {
+		return
+	}
+	logger := logging.FromContext(ctx).With(
+		slog.String(logging.KeyComponent, orchestratorComponent),
+		slog.String(logging.KeyTopic, evt.Topic),
+		slog.String(logging.KeyWorkspaceID, workspaceID))
+	defs, err := b.orchestrator.ListAgentDefinitions(ctx, workspaceID)
+	if err != nil {
+		logger.Error("list agents for event failed", logging.Err(err))
+		return
+	}
+	cause, depth := b.causingRun(ctx, workspaceID, fields)
+	if depth > maxEventTriggerDepth {
+		logger.Warn("event trigger depth exceeded; not starting agents", slog.Int(eventTriggerDepthKey, depth))
+		return
+	}
+	inputs, err := json.Marshal(eventRunInputs(fields))
+	if err != nil {
+		return
+	}
+	fields[eventTriggerDepthKey] = depth
+	triggerContext, err := json.Marshal(fields)
+	if err != nil {
+		return
+	}
+	for _, def := range defs {
+		if def.Status != agentStatusActive || !subscribesToEvent(def.TriggerConfig, evt.Topic) || sameAgent(def, cause) {
+			continue
+		}
+		in := TriggerAgentInput{
+			AgentID:        def.ID,
+			WorkspaceID:    workspaceID,
+			TriggerType:    TriggerTypeEvent,
+			TriggerContext: triggerContext,
+			Inputs:         inputs,
+		}
+		select {
+		case b.slots <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		b.runs.Add(1)
+		go func() {
+			defer b.runs.Done()
+			defer func() { <-b.slots }()
+			if _, runErr := b.orchestrator.ExecuteAgent(ctx, b.runContext, in); runErr != nil {
+				logger.Error("run agent from event failed", slog.String("agent_id", in.AgentID), logging.Err(runErr))
+			}
+		}()
+	}
+}
+
+// causingRun returns the definition of the agent run named by the event's
+// agent_run_id and the depth of the runs the event starts (see
+// eventTriggerDepthKey), or nil and 0 when the event was not caused by a run.
+func (b *EventTriggerBridge) causingRun(ctx context.Context, workspaceID string, fields map[string]any) (*Definition, int) {
+	runID, _ := fields["agent_run_id"].(string)
+	if runID == "" {
+		return nil, 0
+	}
+	run, err := b.orchestrator.GetAgentRun(ctx, workspaceID, runID)
+	if err != nil {
+		return nil, 0
+	}
+	depth := eventTriggerDepth(run.TriggerContext) + 1
+	def, err := b.orchestrator.GetAgentDefinition(ctx, workspaceID, run.DefinitionID)
+	if err != nil {
+		return &Definition{ID: run.DefinitionID}, depth
+	}
+	return def, depth
+}
+
+// eventTriggerDepth reads eventTriggerDepthKey from a run's trigger_context;
+// runs not started by an event have depth 0.
+func eventTriggerDepth(triggerContext json.RawMessage) int {
+	var tc struct {
+		Depth int `json:"event_depth"`
+	}
+	if json.Unmarshal(triggerContext, &tc) != nil {
+		return 0
+	}
+	return tc.Depth
+}
+
+// sameAgent reports whether def is the agent that caused the event: the
+// definition of the causing run, or one with the same agent type, since the
+// built-in runners record their runs under their own definition.
+func sameAgent(def, cause *Definition) bool {
+	if cause == nil {
+		return false
+	}
+	return def.ID == cause.ID || (cause.AgentType != "" && def.AgentType == cause.AgentType)
+}
+
+// eventRunInputs returns the inputs of a run started by an event: the payload
+// fields, plus "<entity_type>_id" (e.g. case_id) set from entity_id for the
+// payloads that name their record generically, which is what runners read.
+func eventRunInputs(fields map[string]any) map[string]any {
+	inputs := make(map[string]any, len(fields)+1)
+	for key, value := range fields {
+		inputs[key] = value
+	}
+	entityType, _ := fields["entity_type"].(string)
+	entityID, _ := fields["entity_id"].(string)
+	if entityType != "" && entityID != "" {
+		if _, ok := inputs[entityType+"_id"]; !ok {
+			inputs[entityType+"_id"] = entityID
+		}
+	}
+	return inputs
+}
+
+// eventWorkspaceID reads the workspace from a decoded event payload, which
+// is either a map with "workspace_id" or a struct with a WorkspaceID field.
+func eventWorkspaceID(fields map[string]any) string {
+	for _, key := range []string{"workspace_id", "WorkspaceID"} {
+		if id, _ := fields[key].(string); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// subscribesToEvent reports whether trigger_config.events lists topic.
+func subscribesToEvent(triggerConfig map[string]any, topic string) bool {
+	events, _ := triggerConfig[eventTriggerConfigKey].([]any)
+	return slices.Contains(events, any(topic))
+}
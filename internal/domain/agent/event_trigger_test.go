@@ -0,0 +1,265 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+// eventTestRunner completes each run right away, keeping its inputs.
+type eventTestRunner struct{}
+
+func (eventTestRunner) Run(ctx context.Context, rc *RunContext, input TriggerAgentInput) (*Run, error) {
+	run, err := rc.Orchestrator.TriggerAgent(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	update := emptyTracesUpdate(StatusSuccess, json.RawMessage(`{}`), json.RawMessage(emptyJSONArray), true)
+	update.Inputs = run.Inputs
+	return rc.Orchestrator.UpdateAgentRun(ctx, input.WorkspaceID, run.ID, update)
+}
+
+// startEventTriggerTest inserts the definitions as {id, agent_type, status,
+// trigger_config}, starts a bridge whose runners complete runs right away and
+// returns the orchestrator, the bus and a stop func that waits for the runs.
+func startEventTriggerTest(t *testing.T, defs [][4]string) (*Orchestrator, *eventbus.Bus, func()) {
+	t.Helper()
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	registry := NewRunnerRegistry()
+	for _, def := range defs {
+		if _, err := db.ExecContext(ctx,
+			`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status, trigger_config)
+			 VALUES (?, 'ws-events', ?, ?, ?, ?)`, def[0], def[0], def[1], def[2], def[3]); err != nil {
+			t.Fatalf("insert agent_definition %s: %v", def[0], err)
+		}
+		if _, ok := registry.Get(def[1]); !ok {
+			if err := registry.Register(def[1], eventTestRunner{}); err != nil {
+				t.Fatalf("register runner: %v", err)
+			}
+		}
+	}
+
+	bus := eventbus.New()
+	orch := NewOrchestratorWithRegistry(db, registry)
+	bgCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		NewEventTriggerBridge(orch, bus, &RunContext{}).Start(bgCtx)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond) // let Start subscribe
+	return orch, bus, func() {
+		cancel()
+		<-done
+	}
+}
+
+// waitForRun polls the workspace runs until one of agentID has status.
+func waitForRun(t *testing.T, orch *Orchestrator, agentID, status string) *Run {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		runs, _, err := orch.ListAgentRuns(context.Background(), "ws-events", ListRunsInput{Limit: 10})
+		if err != nil {
+			t.Fatalf("ListAgentRuns: %v", err)
+		}
+		for _, run := range runs {
+			if run.DefinitionID == agentID && run.Status == status {
+				return run
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("no %s run of %s", status, agentID)
+	return nil
+}
+
+func TestEventTriggerBridge_RunsSubscribedAgentOnLeadCreated(t *testing.T) {
+	orch, bus, stop := startEventTriggerTest(t, [][4]string{
+		{"agent-prospect", "prospecting", "active", `{"events":["lead.created"]}`},
+		{"agent-other", "support", "active", `{"events":["case.sla_breached"]}`},
+		{"agent-paused", "prospecting", "paused", `{"events":["lead.created"]}`},
+	})
+
+	bus.Publish("lead.created", map[string]any{"workspace_id": "ws-events", "lead_id": "lead-1"})
+
+	run := waitForRun(t, orch, "agent-prospect", StatusSuccess)
+	stop()
+	if run.TriggerType != TriggerTypeEvent {
+		t.Fatalf("trigger_type = %s; want event", run.TriggerType)
+	}
+	var triggerContext, inputs map[string]any
+	if err := json.Unmarshal(run.TriggerContext, &triggerContext); err != nil {
+		t.Fatalf("unmarshal trigger_context: %v", err)
+	}
+	if triggerContext["lead_id"] != "lead-1" {
+		t.Fatalf("trigger_context = %v; want the event payload", triggerContext)
+	}
+	if err := json.Unmarshal(run.Inputs, &inputs); err != nil || inputs["lead_id"] != "lead-1" {
+		t.Fatalf("inputs = %s (%v); want the lead_id for the runner", run.Inputs, err)
+	}
+	runs, _, err := orch.ListAgentRuns(context.Background(), "ws-events", ListRunsInput{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListAgentRuns: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("runs = %d; want 1 for the subscribed active agent", len(runs))
+	}
+}
+
+func TestEventTriggerBridge_SkipsEventsCausedByTheAgent(t *testing.T) {
+	orch, bus, stop := startEventTriggerTest(t, [][4]string{
+		{"agent-prospect", "prospecting", "active", `{"events":["deal.updated"]}`},
+		{"agent-risk", "deal_risk", "active", `{"events":["deal.updated"]}`},
+	})
+	cause, err := orch.TriggerAgent(context.Background(), TriggerAgentInput{
+		AgentID: "agent-prospect", WorkspaceID: "ws-events", TriggerType: TriggerTypeManual,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+
+	bus.Publish("deal.updated", map[string]any{
+		"workspace_id": "ws-events", "entity_type": "deal", "entity_id": "deal-1", "agent_run_id": cause.ID,
+	})
+
+	run := waitForRun(t, orch, "agent-risk", StatusSuccess)
+	stop()
+	var inputs map[string]any
+	if err := json.Unmarshal(run.Inputs, &inputs); err != nil || inputs["deal_id"] != "deal-1" {
+		t.Fatalf("inputs = %s (%v); want deal_id from entity_id", run.Inputs, err)
+	}
+	runs, _, err := orch.ListAgentRuns(context.Background(), "ws-events", ListRunsInput{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListAgentRuns: %v", err)
+	}
+	for _, r := range runs {
+		if r.DefinitionID == "agent-prospect" && r.ID != cause.ID {
+			t.Fatalf("agent-prospect was re-triggered by its own change: run %s", r.ID)
+		}
+	}
+}
+
+func TestEventTriggerBridge_StopsPastMaxCausationDepth(t *testing.T) {
+	orch, bus, stop := startEventTriggerTest(t, [][4]string{
+		{"agent-a", "case_triage", "active", `{"events":["case.updated"]}`},
+		{"agent-b", "case_followup", "active", `{"events":["case.updated"]}`},
+	})
+	triggerAt := func(agentID string, depth int) *Run {
+		t.Helper()
+		run, err := orch.TriggerAgent(context.Background(), TriggerAgentInput{
+			AgentID: agentID, WorkspaceID: "ws-events", TriggerType: TriggerTypeEvent,
+			TriggerContext: json.RawMessage(`{"event_depth":` + strconv.Itoa(depth) + `}`),
+		})
+		if err != nil {
+			t.Fatalf("TriggerAgent: %v", err)
+		}
+		return run
+	}
+
+	// A run at the limit causes no further runs.
+	atLimit := triggerAt("agent-a", maxEventTriggerDepth)
+	bus.Publish("case.updated", map[string]any{"workspace_id": "ws-events", "case_id": "case-1", "agent_run_id": atLimit.ID})
+	// One below the limit still starts the other agent, one level deeper.
+	belowLimit := triggerAt("agent-a", maxEventTriggerDepth-1)
+	bus.Publish("case.updated", map[string]any{"workspace_id": "ws-events", "case_id": "case-2", "agent_run_id": belowLimit.ID})
+
+	run := waitForRun(t, orch, "agent-b", StatusSuccess)
+	stop()
+	if got := eventTriggerDepth(run.TriggerContext); got != maxEventTriggerDepth {
+		t.Fatalf("event_depth = %d; want %d", got, maxEventTriggerDepth)
+	}
+	runs, _, err := orch.ListAgentRuns(context.Background(), "ws-events", ListRunsInput{Limit: 10})
+	if err != nil {
+		t.Fatalf("ListAgentRuns: %v", err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("runs = %d; want the 2 causing runs and 1 below the depth limit", len(runs))
+	}
+}
+
+// blockingEventRunner holds each run until release is closed, tracking the
+// most runs in flight at once.
+type blockingEventRunner struct {
+	release           chan struct{}
+	active, maxActive atomic.Int32
+}
+
+func (r *blockingEventRunner) Run(ctx context.Context, rc *RunContext, input TriggerAgentInput) (*Run, error) {
+	n := r.active.Add(1)
+	defer r.active.Add(-1)
+	for {
+		seen := r.maxActive.Load()
+		if n <= seen || r.maxActive.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	<-r.release
+	return eventTestRunner{}.Run(ctx, rc, input)
+}
+
+func TestEventTriggerBridge_BoundsConcurrentRuns(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status, trigger_config)
+		 VALUES ('agent-slow', 'ws-events', 'agent-slow', 'slow', 'active', '{"events":["lead.created"]}')`); err != nil {
+		t.Fatalf("insert agent_definition: %v", err)
+	}
+	runner := &blockingEventRunner{release: make(chan struct{})}
+	registry := NewRunnerRegistry()
+	if err := registry.Register("slow", runner); err != nil {
+		t.Fatalf("register runner: %v", err)
+	}
+	orch := NewOrchestratorWithRegistry(db, registry)
+	bus := eventbus.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewEventTriggerBridge(orch, bus, &RunContext{}).Start(ctx)
+		close(done)
+	}()
+	time.Sleep(10 * time.Millisecond) // let Start subscribe
+
+	events := maxConcurrentEventRuns + 4
+	for i := 0; i < events; i++ {
+		bus.Publish("lead.created", map[string]any{"workspace_id": "ws-events", "lead_id": "lead-" + strconv.Itoa(i)})
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for runner.active.Load() < maxConcurrentEventRuns && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := runner.maxActive.Load(); got != maxConcurrentEventRuns {
+		t.Fatalf("max concurrent runs = %d; want %d", got, maxConcurrentEventRuns)
+	}
+	close(runner.release)
+	deadline = time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		runs, _, err := orch.ListAgentRuns(context.Background(), "ws-events", ListRunsInput{Limit: 50})
+		if err != nil {
+			t.Fatalf("ListAgentRuns: %v", err)
+		}
+		if len(runs) == events {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	runs, _, err := orch.ListAgentRuns(context.Background(), "ws-events", ListRunsInput{Limit: 50})
+	if err != nil {
+		t.Fatalf("ListAgentRuns: %v", err)
+	}
+	if len(runs) != events {
+		t.Fatalf("runs = %d; want every event to run once a slot frees", len(runs))
+	}
+}
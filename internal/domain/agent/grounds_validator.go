@@ -26,6 +26,7 @@ type GroundsValidator struct {
 
 type GroundsResult struct {
 	Met          bool
+	Code         AbstentionReason
 	Reason       string
 	Query        string
 	EvidencePack *knowledge.EvidencePack
@@ -79,6 +80,7 @@ func validateGroundsSources(grounds *CartaGrounds, pack *knowledge.EvidencePack)
 	if len(pack.Sources) < grounds.MinSources {
 		return &GroundsResult{
 			Met:          false,
+			Code:         AbstentionMissingContext,
 			Reason:       fmt.Sprintf("insufficient evidence: %d source(s) (need %d)", len(pack.Sources), grounds.MinSources),
 			EvidencePack: pack,
 		}
@@ -90,6 +92,7 @@ func validateGroundsConfidence(grounds *CartaGrounds, pack *knowledge.EvidencePa
 	if !groundsConfidenceMet(pack.Confidence, grounds.MinConfidence) {
 		return &GroundsResult{
 			Met:          false,
+			Code:         AbstentionLowConfidence,
 			Reason:       fmt.Sprintf("insufficient evidence: confidence=%s (need %s)", pack.Confidence, grounds.MinConfidence),
 			EvidencePack: pack,
 		}
@@ -105,6 +108,7 @@ func validateGroundsStaleness(now time.Time, grounds *CartaGrounds, pack *knowle
 	if allowedAge > 0 && evidencePackIsStale(now, pack, allowedAge) {
 		return &GroundsResult{
 			Met:          false,
+			Code:         AbstentionMissingContext,
 			Reason:       fmt.Sprintf("insufficient evidence: stale sources exceed %d %s", grounds.MaxStaleness, grounds.MaxAgeUnit),
 			EvidencePack: pack,
 		}
@@ -32,6 +32,7 @@ const handoffPayloadEntityIDKey = "entity_id"
 type CaseServiceInterface interface {
 	Get(ctx context.Context, workspaceID, caseID string) (*crm.CaseTicket, error)
 	Update(ctx context.Context, workspaceID, caseID string, input crm.UpdateCaseInput) (*crm.CaseTicket, error)
+	Escalate(ctx context.Context, workspaceID, caseID, reason string) (*crm.CaseTicket, error)
 }
 
 // HandoffPackage is the structured context delivered to a human agent
@@ -108,9 +109,11 @@ func (s *HandoffService) InitiateHandoff(ctx context.Context, workspaceID, runID
 	if err != nil {
 		return nil, err
 	}
-	run.AbstentionReason = stringPtr(reason)
+	if run.Status != StatusAbstained {
+		run.AbstentionReason = stringPtr(reason)
+	}
 
-	cs, err := s.loadAndEscalateCase(ctx, workspaceID, caseID, run)
+	cs, err := s.loadAndEscalateCase(ctx, workspaceID, caseID, reason)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +149,7 @@ func (s *HandoffService) loadRun(ctx context.Context, workspaceID, runID string)
 		       retrieval_queries, retrieved_evidence_ids, reasoning_trace,
 		       tool_calls, output, abstention_reason,
 		       total_tokens, total_cost, latency_ms, trace_id,
-		       started_at, completed_at, created_at
+		       started_at, completed_at, created_at, updated_at
 		FROM agent_run
 		WHERE id = ? AND workspace_id = ?
 	`, runID, workspaceID)
@@ -158,13 +161,22 @@ func (s *HandoffService) loadRun(ctx context.Context, workspaceID, runID string)
 	return run, err
 }
 
-// loadAndEscalateCase fetches the case and updates its status to "escalated".
-func (s *HandoffService) loadAndEscalateCase(ctx context.Context, workspaceID, caseID string, _ *Run) (*crm.CaseTicket, error) {
+// loadAndEscalateCase escalates the case through CaseService.Escalate, which
+// raises its priority and SLA. A case already at the highest priority only
+// has its status set to "escalated".
+func (s *HandoffService) loadAndEscalateCase(ctx context.Context, workspaceID, caseID, reason string) (*crm.CaseTicket, error) {
 	existing, err := s.caseService.Get(ctx, workspaceID, caseID)
 	if err != nil {
 		return nil, ErrHandoffCaseNotFound
 	}
 
+	escalated, err := s.caseService.Escalate(ctx, workspaceID, caseID, reason)
+	if err == nil {
+		return escalated, nil
+	}
+	if !errors.Is(err, crm.ErrCaseAtMaxPriority) {
+		return nil, fmt.Errorf("escalate case: %w", err)
+	}
 	updated, err := s.caseService.Update(ctx, workspaceID, caseID, crm.UpdateCaseInput{
 		OwnerID:  existing.OwnerID,
 		Subject:  existing.Subject,
@@ -185,10 +197,13 @@ func (s *HandoffService) publishHandoffEvent(pkg *HandoffPackage) {
 	s.bus.Publish(topicHandoff, pkg)
 }
 
+// persistHandoffReason records the handoff reason on the run. Abstained runs
+// keep their AbstentionReason code; the reason still travels in the package.
 func (s *HandoffService) persistHandoffReason(ctx context.Context, workspaceID, runID, reason string) error {
 	_, err := s.db.ExecContext(ctx, `
 		UPDATE agent_run
-		SET abstention_reason = COALESCE(?, abstention_reason), updated_at = datetime('now')
+		SET abstention_reason = CASE WHEN status = 'abstained' THEN abstention_reason ELSE COALESCE(?, abstention_reason) END,
+		    updated_at = datetime('now')
 		WHERE id = ? AND workspace_id = ?
 	`, nullableHandoffReason(reason), runID, workspaceID)
 	if err != nil {
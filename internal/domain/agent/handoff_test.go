@@ -111,6 +111,34 @@ func newHandoffSvc(t *testing.T) (*HandoffService, *sql.DB) {
 
 // ── Tests ────────────────────────────────────────────────────────────────────
 
+// TestInitiateHandoff_UrgentCaseOnlyChangesStatus verifies a case already at
+// the highest priority is still handed off, keeping its priority.
+func TestInitiateHandoff_UrgentCaseOnlyChangesStatus(t *testing.T) {
+	svc, db := newHandoffSvc(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	const wsID = "ws-handoff-urgent"
+	const runID = "run-handoff-urgent"
+	const agentDefID = "agent-handoff-urgent"
+	const caseID = "case-handoff-urgent"
+
+	insertHandoffTestAgentDef(t, db, agentDefID, wsID)
+	insertHandoffTestRun(t, db, runID, wsID, agentDefID)
+	insertHandoffTestCase(t, db, caseID, wsID)
+	if _, err := db.Exec(`UPDATE case_ticket SET priority = 'urgent' WHERE id = ?`, caseID); err != nil {
+		t.Fatalf("set urgent priority: %v", err)
+	}
+
+	pkg, err := svc.InitiateHandoff(ctx, wsID, runID, caseID, "no solution found")
+	if err != nil {
+		t.Fatalf("InitiateHandoff: %v", err)
+	}
+	if pkg.CaseStatus != StatusEscalated || pkg.CasePriority != "urgent" {
+		t.Fatalf("case status=%q priority=%q; want escalated, urgent", pkg.CaseStatus, pkg.CasePriority)
+	}
+}
+
 // TestInitiateHandoff_Success verifies the happy path: run escalated, case updated, package returned.
 // Traces: FR-232
 func TestInitiateHandoff_Success(t *testing.T) {
@@ -163,8 +191,9 @@ func TestInitiateHandoff_Success(t *testing.T) {
 	if pkg.AbstentionReason == nil || *pkg.AbstentionReason != "no solution found" {
 		t.Errorf("AbstentionReason: got %v, want no solution found", pkg.AbstentionReason)
 	}
-	if pkg.CasePriority != "medium" {
-		t.Errorf("CasePriority: got %q, want medium", pkg.CasePriority)
+	// Escalation raises the medium case one level.
+	if pkg.CasePriority != "high" {
+		t.Errorf("CasePriority: got %q, want high", pkg.CasePriority)
 	}
 	if pkg.CaseOwnerID != "user-1" {
 		t.Errorf("CaseOwnerID: got %q, want user-1", pkg.CaseOwnerID)
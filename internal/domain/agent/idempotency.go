@@ -0,0 +1,88 @@
+package agent
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// IdempotencyKeyTTL is how long a trigger idempotency key maps to its run.
+// After it a reused key starts a new run.
+const IdempotencyKeyTTL = 24 * time.Hour
+
+var (
+	// ErrIdempotencyKeyConflict is returned when a key is reused with a
+	// different request.
+	ErrIdempotencyKeyConflict = errors.New("idempotency key reused with a different request")
+	// ErrIdempotencyKeyInProgress is returned when the first request with a
+	// key has not produced a run yet.
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+)
+
+// ReserveIdempotencyKey claims key for a new trigger. It returns (nil, nil)
+// when the caller should go on and run the agent, then call
+// CompleteIdempotencyKey or ReleaseIdempotencyKey. A key already bound to a
+// run with the same fingerprint returns that run instead.
+func (o *Orchestrator) ReserveIdempotencyKey(ctx context.Context, workspaceID, key, fingerprint string) (*Run, error) {
+	now := time.Now().UTC()
+	if _, err := o.db.ExecContext(ctx, `
+		DELETE FROM agent_run_idempotency
+		WHERE workspace_id = ? AND idempotency_key = ? AND created_at < ?
+	`, workspaceID, key, now.Add(-IdempotencyKeyTTL).Format(time.RFC3339Nano)); err != nil {
+		return nil, fmt.Errorf("expire idempotency key: %w", err)
+	}
+
+	res, err := o.db.ExecContext(ctx, `
+		INSERT INTO agent_run_idempotency (workspace_id, idempotency_key, request_fingerprint, run_id, created_at)
+		VALUES (?, ?, ?, NULL, ?)
+		ON CONFLICT(workspace_id, idempotency_key) DO NOTHING
+	`, workspaceID, key, fingerprint, now.Format(time.RFC3339Nano))
+	if err != nil {
+		return nil, fmt.Errorf("reserve idempotency key: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 1 {
+		return nil, nil
+	}
+
+	var storedFingerprint string
+	var runID sql.NullString
+	err = o.db.QueryRowContext(ctx, `
+		SELECT request_fingerprint, run_id FROM agent_run_idempotency
+		WHERE workspace_id = ? AND idempotency_key = ?
+	`, workspaceID, key).Scan(&storedFingerprint, &runID)
+	if err != nil {
+		return nil, fmt.Errorf("get idempotency key: %w", err)
+	}
+	if storedFingerprint != fingerprint {
+		return nil, ErrIdempotencyKeyConflict
+	}
+	if !runID.Valid {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+	return o.GetAgentRun(ctx, workspaceID, runID.String)
+}
+
+// CompleteIdempotencyKey binds a reserved key to the run it created.
+func (o *Orchestrator) CompleteIdempotencyKey(ctx context.Context, workspaceID, key, runID string) error {
+	if _, err := o.db.ExecContext(ctx, `
+		UPDATE agent_run_idempotency SET run_id = ?
+		WHERE workspace_id = ? AND idempotency_key = ?
+	`, runID, workspaceID, key); err != nil {
+		return fmt.Errorf("complete idempotency key: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey drops a reservation whose trigger failed, so the
+// client can retry with the same key.
+func (o *Orchestrator) ReleaseIdempotencyKey(ctx context.Context, workspaceID, key string) error {
+	if _, err := o.db.ExecContext(ctx, `
+		DELETE FROM agent_run_idempotency
+		WHERE workspace_id = ? AND idempotency_key = ? AND run_id IS NULL
+	`, workspaceID, key); err != nil {
+		return fmt.Errorf("release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOrchestrator_ReserveIdempotencyKey(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	orch := NewOrchestrator(db)
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		 VALUES ('agent-1', 'ws-1', 'Test Agent', 'support', 'active')`); err != nil {
+		t.Fatalf("insert agent_definition: %v", err)
+	}
+
+	if run, err := orch.ReserveIdempotencyKey(ctx, "ws-1", "key-1", "fp-a"); err != nil || run != nil {
+		t.Fatalf("first reserve = (%v, %v); want (nil, nil)", run, err)
+	}
+	if _, err := orch.ReserveIdempotencyKey(ctx, "ws-1", "key-1", "fp-a"); !errors.Is(err, ErrIdempotencyKeyInProgress) {
+		t.Fatalf("reserve while in progress error = %v; want ErrIdempotencyKeyInProgress", err)
+	}
+
+	run, err := orch.TriggerAgent(ctx, TriggerAgentInput{AgentID: "agent-1", WorkspaceID: "ws-1", TriggerType: TriggerTypeManual})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+	if err = orch.CompleteIdempotencyKey(ctx, "ws-1", "key-1", run.ID); err != nil {
+		t.Fatalf("CompleteIdempotencyKey: %v", err)
+	}
+
+	replayed, err := orch.ReserveIdempotencyKey(ctx, "ws-1", "key-1", "fp-a")
+	if err != nil || replayed == nil || replayed.ID != run.ID {
+		t.Fatalf("replay = (%v, %v); want run %s", replayed, err, run.ID)
+	}
+	if _, err = orch.ReserveIdempotencyKey(ctx, "ws-1", "key-1", "fp-b"); !errors.Is(err, ErrIdempotencyKeyConflict) {
+		t.Fatalf("different fingerprint error = %v; want ErrIdempotencyKeyConflict", err)
+	}
+	if got, err := orch.ReserveIdempotencyKey(ctx, "ws-2", "key-1", "fp-b"); err != nil || got != nil {
+		t.Fatalf("other workspace reserve = (%v, %v); want (nil, nil)", got, err)
+	}
+
+	// A failed trigger releases the key for a retry.
+	if _, err = orch.ReserveIdempotencyKey(ctx, "ws-1", "key-2", "fp-a"); err != nil {
+		t.Fatalf("reserve key-2: %v", err)
+	}
+	if err = orch.ReleaseIdempotencyKey(ctx, "ws-1", "key-2"); err != nil {
+		t.Fatalf("ReleaseIdempotencyKey: %v", err)
+	}
+	if got, err := orch.ReserveIdempotencyKey(ctx, "ws-1", "key-2", "fp-b"); err != nil || got != nil {
+		t.Fatalf("reserve after release = (%v, %v); want (nil, nil)", got, err)
+	}
+
+	// Past the TTL the key starts a new run.
+	expired := time.Now().UTC().Add(-IdempotencyKeyTTL - time.Minute).Format(time.RFC3339Nano)
+	if _, err = db.ExecContext(ctx, `UPDATE agent_run_idempotency SET created_at = ? WHERE idempotency_key = 'key-1'`, expired); err != nil {
+		t.Fatalf("age key: %v", err)
+	}
+	if got, err := orch.ReserveIdempotencyKey(ctx, "ws-1", "key-1", "fp-b"); err != nil || got != nil {
+		t.Fatalf("reserve after TTL = (%v, %v); want (nil, nil)", got, err)
+	}
+}
@@ -8,15 +8,25 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	"github.com/matiasleandrokruk/fenix/internal/domain/blackboard"
 	blackboardagents "github.com/matiasleandrokruk/fenix/internal/domain/blackboard/agents"
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/llm"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+	"github.com/matiasleandrokruk/fenix/pkg/secretbox"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// orchestratorComponent is the logging.KeyComponent of orchestrator logs.
+const orchestratorComponent = "agent.orchestrator"
+
 var (
 	ErrAgentNotFound       = errors.New("agent definition not found")
 	ErrAgentRunNotFound    = errors.New("agent run not found")
@@ -54,6 +64,9 @@ const emptyJSONObject = `{}`
 
 const agentStatusActive = "active"
 
+// agentStatusPaused keeps a definition from being triggered.
+const agentStatusPaused = "paused"
+
 // Trigger type constants
 const (
 	TriggerTypeEvent    = "event"
@@ -106,15 +119,29 @@ type Run struct {
 	StartedAt            time.Time
 	CompletedAt          *time.Time
 	CreatedAt            time.Time
+	UpdatedAt            time.Time
 }
 
 type ListRunsInput struct {
-	Limit      int64
-	Offset     int64
-	Status     string
-	EntityType string
-	EntityID   string
-	WorkflowID string
+	Limit  int64
+	Offset int64
+	// Status matches either the runtime status or the public outcome.
+	Status       string
+	DefinitionID string
+	TriggerType  string
+	EntityType   string
+	EntityID     string
+	WorkflowID   string
+	// Cursor switches to keyset pagination: when non-nil, Offset is ignored
+	// and runs strictly older than the cursor are returned. A zero cursor
+	// starts from the newest run.
+	Cursor *RunCursor
+}
+
+// RunCursor is the (created_at, id) position of the last run of a page.
+type RunCursor struct {
+	CreatedAt time.Time
+	ID        string
 }
 
 type SkillDefinition struct {
@@ -139,6 +166,11 @@ type TriggerAgentInput struct {
 	TriggerContext       json.RawMessage
 	Inputs               json.RawMessage
 	CognitiveWorkspaceID *string // optional; enables blackboard attachment when set (Task A.5)
+	// DryRun executes the agent with ctxkeys.DryRun set, so write tools
+	// return a preview instead of persisting. Honored by ExecuteAgent.
+	DryRun bool
+	// Conversation is recorded in trigger_context for TriggerTypeCopilot runs.
+	Conversation *CopilotConversation
 }
 
 type ToolCall struct {
@@ -156,6 +188,19 @@ type Orchestrator struct {
 	runnerRegistry         *RunnerRegistry
 	blackboardOrchestrator blackboardPipelineRunner
 	busRegistry            *blackboard.BusRegistry
+	eventBus               eventbus.EventBus
+	llmProviders           WorkspaceLLMProviders
+	tracer                 trace.Tracer
+	runSpans               sync.Map // run ID -> open trace.Span, see startRunSpan
+	outputValidators       map[string]OutputValidator
+	toolCatalog            ToolCatalog    // nil skips allowed_tools validation
+	webhookSecrets         *secretbox.Box // nil rejects new webhook secrets
+}
+
+// WorkspaceLLMProviders resolves the LLM provider configured for a workspace.
+// Implemented by llm.ProviderFactory.
+type WorkspaceLLMProviders interface {
+	ForWorkspace(ctx context.Context, workspaceID string) llm.LLMProvider
 }
 
 type blackboardPipelineRunner interface {
@@ -178,11 +223,57 @@ func (o *Orchestrator) SetBlackboardOrchestrator(orchestrator blackboardPipeline
 	o.blackboardOrchestrator = orchestrator
 }
 
+// SetEventBus enables TopicAgentRunCompleted events for completed runs.
+func (o *Orchestrator) SetEventBus(bus eventbus.EventBus) {
+	o.eventBus = bus
+}
+
+// SetLLMProviders enables per-workspace model selection in LLMProvider.
+func (o *Orchestrator) SetLLMProviders(providers WorkspaceLLMProviders) {
+	o.llmProviders = providers
+}
+
+// LLMProvider returns the provider agents should use for workspaceID, or
+// fallback when no per-workspace resolution is configured. Its calls are
+// traced as children of the run span carried by their context.
+func (o *Orchestrator) LLMProvider(ctx context.Context, workspaceID string, fallback llm.LLMProvider) llm.LLMProvider {
+	provider := fallback
+	if o != nil && o.llmProviders != nil {
+		provider = o.llmProviders.ForWorkspace(ctx, workspaceID)
+	}
+	if provider == nil {
+		return nil
+	}
+	return tracedLLMProvider{LLMProvider: provider}
+}
+
+// publishRunCompleted announces a terminal run; a no-op without an event bus.
+func (o *Orchestrator) publishRunCompleted(run *Run) {
+	if o.eventBus == nil || run == nil {
+		return
+	}
+	o.eventBus.Publish(TopicAgentRunCompleted, RunCompletedEvent{
+		WorkspaceID:  run.WorkspaceID,
+		RunID:        run.ID,
+		DefinitionID: run.DefinitionID,
+		Status:       run.Status,
+		Output:       run.Output,
+		CompletedAt:  run.CompletedAt,
+	})
+}
+
 // TriggerAgent creates a new agent run and returns it
 func (o *Orchestrator) TriggerAgent(ctx context.Context, in TriggerAgentInput) (*Run, error) {
 	if !isValidTriggerType(in.TriggerType) {
 		return nil, ErrInvalidTriggerType
 	}
+	if in.TriggerType == TriggerTypeCopilot {
+		triggerContext, err := copilotTriggerContext(in)
+		if err != nil {
+			return nil, err
+		}
+		in.TriggerContext = triggerContext
+	}
 
 	agent, err := o.getAgentDefinition(ctx, in.AgentID, in.WorkspaceID)
 	if err != nil {
@@ -201,10 +292,12 @@ func (o *Orchestrator) TriggerAgent(ctx context.Context, in TriggerAgentInput) (
 	if err != nil {
 		return nil, err
 	}
+	o.startRunSpan(ctx, run)
 	return run, nil
 }
 
 func newAgentRun(in TriggerAgentInput) *Run {
+	now := time.Now().UTC()
 	return &Run{
 		ID:                   uuid.NewV7().String(),
 		WorkspaceID:          in.WorkspaceID,
@@ -221,8 +314,9 @@ func newAgentRun(in TriggerAgentInput) *Run {
 		Output:               json.RawMessage(emptyJSONObject),
 		TraceID:              stringPtr(uuid.NewV7().String()),
 		CognitiveWorkspaceID: in.CognitiveWorkspaceID,
-		StartedAt:            time.Now().UTC(),
-		CreatedAt:            time.Now().UTC(),
+		StartedAt:            now,
+		CreatedAt:            now,
+		UpdatedAt:            now,
 	}
 }
 
@@ -235,8 +329,8 @@ func (o *Orchestrator) persistRun(ctx context.Context, run *Run) error {
 			tool_calls, output, abstention_reason,
 			total_tokens, total_cost, latency_ms, trace_id,
 			cognitive_workspace_id,
-			started_at, completed_at, created_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULL, ?)
+			started_at, completed_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULL, ?, ?)
 	`,
 		run.ID, run.WorkspaceID, run.DefinitionID, run.TriggeredByUserID,
 		run.TriggerType, run.TriggerContext, run.Status, run.Inputs,
@@ -244,7 +338,7 @@ func (o *Orchestrator) persistRun(ctx context.Context, run *Run) error {
 		run.ToolCalls, run.Output, run.AbstentionReason,
 		run.TotalTokens, run.TotalCost, run.LatencyMs, run.TraceID,
 		run.CognitiveWorkspaceID,
-		run.StartedAt, run.CreatedAt,
+		run.StartedAt, run.CreatedAt, run.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("insert agent run: %w", err)
@@ -267,6 +361,10 @@ func (o *Orchestrator) ExecuteAgent(ctx context.Context, rc *RunContext, in Trig
 		return nil, err
 	}
 
+	if in.DryRun {
+		ctx = ctxkeys.WithDryRun(ctx)
+	}
+
 	runCtx := prepareRunContext(rc, o, in)
 	specializedRuntime := startSpecializedBlackboardRuntime(ctx, runCtx)
 	if specializedRuntime != nil {
@@ -316,7 +414,7 @@ func (o *Orchestrator) GetAgentRun(ctx context.Context, workspaceID, runID strin
 		       retrieval_queries, retrieved_evidence_ids, reasoning_trace,
 		       tool_calls, output, abstention_reason,
 		       total_tokens, total_cost, latency_ms, trace_id,
-		       started_at, completed_at, created_at
+		       started_at, completed_at, created_at, updated_at
 		FROM agent_run
 		WHERE id = ? AND workspace_id = ?
 	`, runID, workspaceID)
@@ -331,8 +429,14 @@ func (o *Orchestrator) GetAgentRun(ctx context.Context, workspaceID, runID strin
 	return run, nil
 }
 
-// ListAgentRuns lists agent runs with pagination
+// ListAgentRuns lists agent runs with pagination. Runs are ordered newest
+// first; the total counts every run matching the filters. Status, definition
+// and trigger type filters run in SQL; entity and workflow filters read the
+// decoded run context.
 func (o *Orchestrator) ListAgentRuns(ctx context.Context, workspaceID string, input ListRunsInput) ([]*Run, int64, error) {
+	if err := validateRunFilters(input); err != nil {
+		return nil, 0, err
+	}
 	limit := input.Limit
 	if limit <= 0 {
 		limit = 25
@@ -340,6 +444,12 @@ func (o *Orchestrator) ListAgentRuns(ctx context.Context, workspaceID string, in
 	if input.Offset < 0 {
 		input.Offset = 0
 	}
+	if input.Cursor != nil {
+		return o.listAgentRunsAfter(ctx, workspaceID, input, limit)
+	}
+	if !input.hasContextFilters() {
+		return o.listAgentRunsPage(ctx, workspaceID, input, limit)
+	}
 	runs, err := o.listFilteredRuns(ctx, workspaceID, input)
 	if err != nil {
 		return nil, 0, err
@@ -347,18 +457,86 @@ func (o *Orchestrator) ListAgentRuns(ctx context.Context, workspaceID string, in
 	return paginateRuns(runs, limit, input.Offset), int64(len(runs)), nil
 }
 
-func (o *Orchestrator) listFilteredRuns(ctx context.Context, workspaceID string, input ListRunsInput) ([]*Run, error) {
-	rows, err := o.db.QueryContext(ctx, `
+// listAgentRunsPage pages in SQL when every filter is a SQL filter.
+func (o *Orchestrator) listAgentRunsPage(ctx context.Context, workspaceID string, input ListRunsInput, limit int64) ([]*Run, int64, error) {
+	where, args := runFilterClause(input)
+	query := agentRunListQuery + where + agentRunListOrder + `
+		LIMIT ? OFFSET ?`
+	runs, err := o.queryFilteredRuns(ctx, query, append(append([]any{workspaceID}, args...), limit, input.Offset), input, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := o.countFilteredRuns(ctx, workspaceID, input)
+	if err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// listAgentRunsAfter is the keyset variant of ListAgentRuns: it reads runs
+// past input.Cursor and stops as soon as limit of them match the filters.
+func (o *Orchestrator) listAgentRunsAfter(ctx context.Context, workspaceID string, input ListRunsInput, limit int64) ([]*Run, int64, error) {
+	query := agentRunListQuery
+	args := []any{workspaceID}
+	if input.Cursor.ID != "" {
+		query += ` AND (created_at, id) < (?, ?)`
+		args = append(args, input.Cursor.CreatedAt.UTC(), input.Cursor.ID)
+	}
+	where, filterArgs := runFilterClause(input)
+	runs, err := o.queryFilteredRuns(ctx, query+where+agentRunListOrder, append(args, filterArgs...), input, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := o.countFilteredRuns(ctx, workspaceID, input)
+	if err != nil {
+		return nil, 0, err
+	}
+	return runs, total, nil
+}
+
+// countFilteredRuns counts in SQL when no filter needs the decoded run.
+func (o *Orchestrator) countFilteredRuns(ctx context.Context, workspaceID string, input ListRunsInput) (int64, error) {
+	if input.hasContextFilters() {
+		runs, err := o.listFilteredRuns(ctx, workspaceID, input)
+		if err != nil {
+			return 0, err
+		}
+		return int64(len(runs)), nil
+	}
+	where, args := runFilterClause(input)
+	var total int64
+	if err := o.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM agent_run WHERE workspace_id = ?`+where,
+		append([]any{workspaceID}, args...)...,
+	).Scan(&total); err != nil {
+		return 0, fmt.Errorf("count agent runs: %w", err)
+	}
+	return total, nil
+}
+
+const (
+	agentRunListQuery = `
 		SELECT id, workspace_id, agent_definition_id, triggered_by_user_id,
 		       trigger_type, trigger_context, status, inputs,
 		       retrieval_queries, retrieved_evidence_ids, reasoning_trace,
 		       tool_calls, output, abstention_reason,
 		       total_tokens, total_cost, latency_ms, trace_id,
-		       started_at, completed_at, created_at
+		       started_at, completed_at, created_at, updated_at
 		FROM agent_run
-		WHERE workspace_id = ?
-		ORDER BY created_at DESC
-	`, workspaceID)
+		WHERE workspace_id = ?`
+	agentRunListOrder = `
+		ORDER BY created_at DESC, id DESC`
+)
+
+func (o *Orchestrator) listFilteredRuns(ctx context.Context, workspaceID string, input ListRunsInput) ([]*Run, error) {
+	where, args := runFilterClause(input)
+	return o.queryFilteredRuns(ctx, agentRunListQuery+where+agentRunListOrder, append([]any{workspaceID}, args...), input, 0)
+}
+
+// queryFilteredRuns scans the runs returned by query that match the input's
+// context filters. A positive maxRuns stops reading once that many runs matched.
+func (o *Orchestrator) queryFilteredRuns(ctx context.Context, query string, args []any, input ListRunsInput, maxRuns int64) ([]*Run, error) {
+	rows, err := o.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("list agent runs: %w", err)
 	}
@@ -370,9 +548,12 @@ func (o *Orchestrator) listFilteredRuns(ctx context.Context, workspaceID string,
 		if scanErr != nil {
 			return nil, scanErr
 		}
-		if matchesRunFilters(run, input) {
+		if matchesRunContextFilters(run, input) {
 			runs = append(runs, run)
 		}
+		if maxRuns > 0 && int64(len(runs)) >= maxRuns {
+			break
+		}
 	}
 	if rowsErr := rows.Err(); rowsErr != nil {
 		return nil, fmt.Errorf("iterate agent runs: %w", rowsErr)
@@ -399,25 +580,20 @@ type runContextMetadata struct {
 	rejectionReason string
 }
 
-func matchesRunFilters(run *Run, input ListRunsInput) bool {
+func matchesRunContextFilters(run *Run, input ListRunsInput) bool {
 	if run == nil {
 		return false
 	}
+	if !input.hasContextFilters() {
+		return true
+	}
 	meta := extractRunContextMetadata(run)
 
-	return matchRunStatusFilter(run, input.Status) &&
-		matchesOptionalFilter(meta.workflowID, input.WorkflowID) &&
+	return matchesOptionalFilter(meta.workflowID, input.WorkflowID) &&
 		matchesOptionalFilter(meta.entityType, input.EntityType) &&
 		matchesOptionalFilter(meta.entityID, input.EntityID)
 }
 
-func matchRunStatusFilter(run *Run, expected string) bool {
-	if expected == "" {
-		return true
-	}
-	return run.Status == expected || PublicRunOutcome(run) == expected
-}
-
 func matchesOptionalFilter(actual, expected string) bool {
 	return expected == "" || actual == expected
 }
@@ -625,15 +801,31 @@ func (o *Orchestrator) UpdateAgentRunStatus(ctx context.Context, workspaceID, ru
 	if err != nil {
 		return nil, err
 	}
-	return o.GetAgentRun(ctx, workspaceID, runID)
+	updated, err := o.GetAgentRun(ctx, workspaceID, runID)
+	if err != nil {
+		return nil, err
+	}
+	o.endRunSpan(updated)
+	o.publishRunCompleted(updated)
+	return updated, nil
 }
 
 // UpdateAgentRun updates an agent run with full data
 func (o *Orchestrator) UpdateAgentRun(ctx context.Context, workspaceID, runID string, updates RunUpdates) (*Run, error) {
+	if err := validateAbstentionReason(updates.Status, updates.AbstentionReason); err != nil {
+		return nil, err
+	}
 	run, err := o.loadUpdatableRun(ctx, workspaceID, runID, updates.Status)
 	if err != nil {
 		return nil, err
 	}
+	// A failed run records an error envelope (see FailAgentRun), not the
+	// agent's output, so only other outputs are held to the schema.
+	if updates.Status != StatusFailed {
+		if err = o.ValidateOutput(ctx, workspaceID, run.DefinitionID, updates.Output); err != nil {
+			return nil, err
+		}
+	}
 
 	now, completedAt := updateCompletionTimes(updates.Completed)
 	enrichCompletedRun(&updates, run)
@@ -659,7 +851,15 @@ func (o *Orchestrator) UpdateAgentRun(ctx context.Context, workspaceID, runID st
 		return nil, fmt.Errorf("commit agent run update: %w", err)
 	}
 
-	return o.GetAgentRun(ctx, workspaceID, runID)
+	updated, err := o.GetAgentRun(ctx, workspaceID, runID)
+	if err != nil {
+		return nil, err
+	}
+	if updates.Completed {
+		o.endRunSpan(updated)
+		o.publishRunCompleted(updated)
+	}
+	return updated, nil
 }
 
 func (o *Orchestrator) loadUpdatableRun(ctx context.Context, workspaceID, runID, nextStatus string) (*Run, error) {
@@ -869,7 +1069,7 @@ func scanAgentRun(scan agentRunScanner) (*Run, error) {
 		&n.retrievalQueries, &n.retrievedEvidence, &n.reasoningTrace,
 		&n.toolCalls, &n.output, &n.abstentionReason,
 		&n.totalTokens, &n.totalCost, &n.latencyMs, &n.traceID,
-		&r.StartedAt, &n.completedAt, &r.CreatedAt,
+		&r.StartedAt, &n.completedAt, &r.CreatedAt, &r.UpdatedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("scan agent definition: %w", err)
@@ -1047,7 +1247,7 @@ func (o *Orchestrator) closeBlackboardWorkspace(ctx context.Context, cognitiveWo
 		return err
 	}
 	o.busRegistry.Evict(*cognitiveWorkspaceID) // release cached bus on workspace close (Task R.12)
-	o.triggerBlackboardPipeline(*cognitiveWorkspaceID)
+	o.triggerBlackboardPipeline(ctx, *cognitiveWorkspaceID)
 	return nil
 }
 
@@ -1067,16 +1267,22 @@ func (o *Orchestrator) markCognitiveWorkspaceClosed(ctx context.Context, cogniti
 	return rows > 0, nil
 }
 
-func (o *Orchestrator) triggerBlackboardPipeline(cognitiveWorkspaceID string) {
+// triggerBlackboardPipeline runs the pipeline in the background, detached
+// from ctx's cancellation but logging to ctx's logger.
+func (o *Orchestrator) triggerBlackboardPipeline(ctx context.Context, cognitiveWorkspaceID string) {
 	if o.blackboardOrchestrator == nil {
 		return
 	}
 
+	logger := logging.FromContext(ctx)
 	go func() {
 		bg, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 		defer cancel()
 		if _, err := o.blackboardOrchestrator.RunPipeline(bg, cognitiveWorkspaceID); err != nil {
-			log.Printf("agent orchestrator: blackboard pipeline trigger failed for %s: %v", cognitiveWorkspaceID, err)
+			logger.Error("blackboard pipeline trigger failed",
+				slog.String(logging.KeyComponent, orchestratorComponent),
+				slog.String("cognitive_workspace_id", cognitiveWorkspaceID),
+				logging.Err(err))
 		}
 	}()
 }
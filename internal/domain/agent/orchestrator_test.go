@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	"github.com/matiasleandrokruk/fenix/internal/domain/blackboard"
 	bbagents "github.com/matiasleandrokruk/fenix/internal/domain/blackboard/agents"
 )
@@ -836,6 +837,50 @@ func TestExecuteAgent_DelegatesToRunner(t *testing.T) {
 	}
 }
 
+func TestExecuteAgent_DryRunMarksRunnerContext(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		 VALUES ('def-dry', 'ws-dry', 'Dry Agent', 'support', 'active')`)
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	runner := &dryRunCaptureRunner{}
+	registry := NewRunnerRegistry()
+	if err := registry.Register("support", runner); err != nil {
+		t.Fatalf("Register(): %v", err)
+	}
+
+	orch := NewOrchestratorWithRegistry(db, registry)
+	for _, dry := range []bool{false, true} {
+		if _, err := orch.ExecuteAgent(ctx, &RunContext{}, TriggerAgentInput{
+			AgentID:     "def-dry",
+			WorkspaceID: "ws-dry",
+			TriggerType: TriggerTypeManual,
+			DryRun:      dry,
+		}); err != nil {
+			t.Fatalf("ExecuteAgent(DryRun=%v): %v", dry, err)
+		}
+	}
+	if len(runner.dryRun) != 2 || runner.dryRun[0] || !runner.dryRun[1] {
+		t.Fatalf("runner saw dry-run = %v, want [false true]", runner.dryRun)
+	}
+}
+
+// dryRunCaptureRunner records whether each Run call saw ctxkeys.DryRun.
+type dryRunCaptureRunner struct {
+	dryRun []bool
+}
+
+func (r *dryRunCaptureRunner) Run(ctx context.Context, _ *RunContext, in TriggerAgentInput) (*Run, error) {
+	r.dryRun = append(r.dryRun, ctxkeys.IsDryRun(ctx))
+	return &Run{ID: "dry-run-id", WorkspaceID: in.WorkspaceID, DefinitionID: in.AgentID, Status: StatusSuccess}, nil
+}
+
 // captureRunner implements Runner and records the RunContext it receives.
 // Used to assert blackboard attachment state without side effects.
 type captureRunner struct {
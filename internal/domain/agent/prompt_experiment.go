@@ -105,7 +105,7 @@ func (s *PromptService) ListPromptExperiments(ctx context.Context, workspaceID,
 }
 
 func (s *PromptService) StopPromptExperiment(ctx context.Context, input StopPromptExperimentInput) (*PromptExperiment, error) {
-	experiment, err := s.getPromptExperimentByID(ctx, input.WorkspaceID, input.ExperimentID)
+	experiment, err := s.GetPromptExperimentByID(ctx, input.WorkspaceID, input.ExperimentID)
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +117,7 @@ func (s *PromptService) StopPromptExperiment(ctx context.Context, input StopProm
 		return nil, err
 	}
 
-	updated, err := s.getPromptExperimentByID(ctx, input.WorkspaceID, input.ExperimentID)
+	updated, err := s.GetPromptExperimentByID(ctx, input.WorkspaceID, input.ExperimentID)
 	if err != nil {
 		return nil, err
 	}
@@ -279,7 +279,8 @@ func (s *PromptService) ensureNoRunningExperiment(ctx context.Context, workspace
 	return nil
 }
 
-func (s *PromptService) getPromptExperimentByID(ctx context.Context, workspaceID, experimentID string) (*PromptExperiment, error) {
+// GetPromptExperimentByID returns one experiment of the workspace.
+func (s *PromptService) GetPromptExperimentByID(ctx context.Context, workspaceID, experimentID string) (*PromptExperiment, error) {
 	row := s.db.QueryRowContext(ctx, `
 		SELECT id, workspace_id, agent_definition_id, control_prompt_version_id, candidate_prompt_version_id,
 		       control_traffic_percent, candidate_traffic_percent, status, winner_prompt_version_id,
@@ -0,0 +1,115 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ReasoningStep is one entry of agent_run.reasoning_trace. The column keeps
+// its JSON array shape; each element is a serialized ReasoningStep.
+type ReasoningStep struct {
+	Step       string         `json:"step"`
+	Detail     string         `json:"detail,omitempty"`
+	Data       map[string]any `json:"data,omitempty"`
+	Timestamp  time.Time      `json:"timestamp"`
+	DurationMs int64          `json:"duration_ms"`
+}
+
+// AppendReasoningStep appends step to the run's reasoning_trace in place, so
+// the trace of an in-flight run can be inspected before it completes. A
+// missing or non-array trace is replaced by a one-element array.
+func (o *Orchestrator) AppendReasoningStep(ctx context.Context, workspaceID, runID string, step ReasoningStep) error {
+	raw, err := json.Marshal(step)
+	if err != nil {
+		return fmt.Errorf("marshal reasoning step: %w", err)
+	}
+	res, err := o.db.ExecContext(ctx, `
+		UPDATE agent_run
+		SET reasoning_trace = json_insert(
+		        CASE
+		            WHEN json_valid(reasoning_trace) AND json_type(reasoning_trace) = 'array' THEN reasoning_trace
+		            ELSE '[]'
+		        END,
+		        '$[#]', json(?)),
+		    updated_at = ?
+		WHERE id = ? AND workspace_id = ?
+	`, string(raw), time.Now().UTC(), runID, workspaceID)
+	if err != nil {
+		return fmt.Errorf("append reasoning step: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAgentRunNotFound
+	}
+	return nil
+}
+
+// ReasoningTracer records the reasoning steps of one run. Each Record call
+// timestamps the step, measures the time since the previous step (or since
+// the tracer was created) and appends it to the run through the orchestrator.
+// Appends are best effort: Trace always returns every recorded step, so the
+// final RunUpdates.ReasoningTrace is complete even if an append failed.
+//
+// A nil *ReasoningTracer is valid and records nothing.
+type ReasoningTracer struct {
+	orchestrator *Orchestrator
+	workspaceID  string
+	runID        string
+	steps        []ReasoningStep
+	last         time.Time
+	now          func() time.Time
+}
+
+// NewReasoningTracer returns a tracer for the given run. With a nil
+// orchestrator steps are only kept in memory.
+func NewReasoningTracer(o *Orchestrator, workspaceID, runID string) *ReasoningTracer {
+	t := &ReasoningTracer{
+		orchestrator: o,
+		workspaceID:  workspaceID,
+		runID:        runID,
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+	t.last = t.now()
+	return t
+}
+
+// Record closes the current step and persists it.
+func (t *ReasoningTracer) Record(ctx context.Context, step, detail string, data map[string]any) {
+	if t == nil {
+		return
+	}
+	now := t.now()
+	entry := ReasoningStep{
+		Step:       step,
+		Detail:     detail,
+		Data:       data,
+		Timestamp:  now,
+		DurationMs: now.Sub(t.last).Milliseconds(),
+	}
+	t.last = now
+	t.steps = append(t.steps, entry)
+	if t.orchestrator != nil {
+		_ = t.orchestrator.AppendReasoningStep(ctx, t.workspaceID, t.runID, entry)
+	}
+}
+
+// Steps returns the recorded steps in order.
+func (t *ReasoningTracer) Steps() []ReasoningStep {
+	if t == nil {
+		return nil
+	}
+	return t.steps
+}
+
+// Trace returns the recorded steps serialized for agent_run.reasoning_trace.
+func (t *ReasoningTracer) Trace() json.RawMessage {
+	if t == nil || len(t.steps) == 0 {
+		return json.RawMessage(emptyJSONArray)
+	}
+	data, err := json.Marshal(t.steps)
+	if err != nil {
+		return json.RawMessage(emptyJSONArray)
+	}
+	return data
+}
@@ -0,0 +1,105 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReasoningTracer_AppendsStepsIncrementally(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		 VALUES ('agent-trace', 'ws-trace', 'Trace', 'support', 'active')`); err != nil {
+		t.Fatalf("insert definition: %v", err)
+	}
+
+	orch := NewOrchestrator(db)
+	run, err := orch.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:     "agent-trace",
+		WorkspaceID: "ws-trace",
+		TriggerType: TriggerTypeManual,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := start
+	trace := NewReasoningTracer(orch, run.WorkspaceID, run.ID)
+	trace.now = func() time.Time { return clock }
+	trace.last = start
+
+	clock = start.Add(120 * time.Millisecond)
+	trace.Record(ctx, "search", "Built evidence pack", map[string]any{"results": 2})
+
+	stored, err := orch.GetAgentRun(ctx, run.WorkspaceID, run.ID)
+	if err != nil {
+		t.Fatalf("GetAgentRun: %v", err)
+	}
+	var steps []ReasoningStep
+	if err = json.Unmarshal(stored.ReasoningTrace, &steps); err != nil {
+		t.Fatalf("unmarshal trace %s: %v", stored.ReasoningTrace, err)
+	}
+	if len(steps) != 1 || steps[0].Step != "search" || steps[0].DurationMs != 120 {
+		t.Fatalf("trace after first step = %+v", steps)
+	}
+
+	clock = clock.Add(30 * time.Millisecond)
+	trace.Record(ctx, "decide", "Determined action: escalate", nil)
+
+	stored, err = orch.GetAgentRun(ctx, run.WorkspaceID, run.ID)
+	if err != nil {
+		t.Fatalf("GetAgentRun: %v", err)
+	}
+	steps = nil
+	if err = json.Unmarshal(stored.ReasoningTrace, &steps); err != nil {
+		t.Fatalf("unmarshal trace %s: %v", stored.ReasoningTrace, err)
+	}
+	if len(steps) != 2 {
+		t.Fatalf("expected 2 persisted steps, got %d", len(steps))
+	}
+	if steps[1].Step != "decide" || steps[1].DurationMs != 30 || !steps[1].Timestamp.Equal(clock) {
+		t.Fatalf("second step = %+v", steps[1])
+	}
+	if got, _ := steps[0].Data["results"].(float64); got != 2 {
+		t.Fatalf("first step data = %+v", steps[0].Data)
+	}
+
+	var inMemory []ReasoningStep
+	if err = json.Unmarshal(trace.Trace(), &inMemory); err != nil {
+		t.Fatalf("unmarshal Trace(): %v", err)
+	}
+	if len(inMemory) != len(steps) {
+		t.Fatalf("Trace() has %d steps, persisted %d", len(inMemory), len(steps))
+	}
+}
+
+func TestAppendReasoningStep_RunNotFound(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	err := NewOrchestrator(db).AppendReasoningStep(context.Background(), "ws-none", "run-none", ReasoningStep{Step: "x"})
+	if !errors.Is(err, ErrAgentRunNotFound) {
+		t.Fatalf("expected ErrAgentRunNotFound, got %v", err)
+	}
+}
+
+func TestReasoningTracer_NilAndInMemory(t *testing.T) {
+	var nilTracer *ReasoningTracer
+	nilTracer.Record(context.Background(), "noop", "", nil)
+	if got := string(nilTracer.Trace()); got != emptyJSONArray {
+		t.Fatalf("nil tracer Trace() = %s, want []", got)
+	}
+
+	trace := NewReasoningTracer(nil, "ws", "run")
+	trace.Record(context.Background(), "analyze", "Analyzed query", nil)
+	if steps := trace.Steps(); len(steps) != 1 || steps[0].Step != "analyze" {
+		t.Fatalf("in-memory steps = %+v", steps)
+	}
+}
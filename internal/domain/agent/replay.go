@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// TriggerContextReplayedFrom is the trigger_context key that links a replayed
+// run to the run whose inputs it reused.
+const TriggerContextReplayedFrom = "replayed_from"
+
+// ReplayRun triggers a fresh run of the same agent definition with the inputs
+// and trigger context of runID, e.g. to compare outputs on identical inputs
+// after promoting a new prompt version. The new run's trigger_context carries
+// replayed_from = runID. The definition must still be active.
+func (o *Orchestrator) ReplayRun(ctx context.Context, workspaceID, runID string) (*Run, error) {
+	original, err := o.GetAgentRun(ctx, workspaceID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	triggerContext, err := replayTriggerContext(original.TriggerContext, original.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:        original.DefinitionID,
+		WorkspaceID:    workspaceID,
+		TriggeredBy:    original.TriggeredByUserID,
+		TriggerType:    original.TriggerType,
+		TriggerContext: triggerContext,
+		Inputs:         original.Inputs,
+	})
+}
+
+// ReplayedFrom returns the run ID a replayed run was created from, or "".
+func (r *Run) ReplayedFrom() string {
+	var tc map[string]any
+	if err := json.Unmarshal(r.TriggerContext, &tc); err != nil {
+		return ""
+	}
+	id, _ := tc[TriggerContextReplayedFrom].(string)
+	return id
+}
+
+// replayTriggerContext adds replayed_from to an object trigger context. A
+// context that is not a JSON object is kept under original_trigger_context.
+func replayTriggerContext(raw json.RawMessage, runID string) (json.RawMessage, error) {
+	tc := map[string]any{}
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) {
+		if err := json.Unmarshal(trimmed, &tc); err != nil {
+			tc = map[string]any{"original_trigger_context": json.RawMessage(trimmed)}
+		}
+	}
+	tc[TriggerContextReplayedFrom] = runID
+
+	out, err := json.Marshal(tc)
+	if err != nil {
+		return nil, fmt.Errorf("encode replay trigger context: %w", err)
+	}
+	return out, nil
+}
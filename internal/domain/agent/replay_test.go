@@ -0,0 +1,98 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestReplayRun_ReusesInputsAndTagsReplayedFrom(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	orch := NewOrchestrator(db)
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		 VALUES ('agent-replay', 'ws-replay', 'Replay Agent', 'support', 'active')`); err != nil {
+		t.Fatalf("insert agent_definition: %v", err)
+	}
+
+	original, err := orch.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:        "agent-replay",
+		WorkspaceID:    "ws-replay",
+		TriggerType:    TriggerTypeEvent,
+		TriggerContext: json.RawMessage(`{"event":"case.created","case_id":"case-1"}`),
+		Inputs:         json.RawMessage(`{"customer_query":"¿dónde está mi factura?"}`),
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+
+	replay, err := orch.ReplayRun(ctx, "ws-replay", original.ID)
+	if err != nil {
+		t.Fatalf("ReplayRun: %v", err)
+	}
+	if replay.ID == original.ID {
+		t.Fatal("expected a fresh run ID")
+	}
+
+	stored, err := orch.GetAgentRun(ctx, "ws-replay", replay.ID)
+	if err != nil {
+		t.Fatalf("GetAgentRun: %v", err)
+	}
+	if stored.DefinitionID != "agent-replay" || stored.TriggerType != TriggerTypeEvent || stored.Status != StatusRunning {
+		t.Fatalf("unexpected replay run: %+v", stored)
+	}
+	if string(stored.Inputs) != string(original.Inputs) {
+		t.Fatalf("inputs = %s; want %s", stored.Inputs, original.Inputs)
+	}
+	if stored.ReplayedFrom() != original.ID {
+		t.Fatalf("ReplayedFrom() = %q; want %q", stored.ReplayedFrom(), original.ID)
+	}
+	var tc map[string]any
+	if err := json.Unmarshal(stored.TriggerContext, &tc); err != nil {
+		t.Fatalf("decode trigger_context: %v", err)
+	}
+	if tc["case_id"] != "case-1" || tc["event"] != "case.created" {
+		t.Fatalf("original trigger_context not preserved: %v", tc)
+	}
+	if original.ReplayedFrom() != "" {
+		t.Fatalf("original run should not be marked as replayed, got %q", original.ReplayedFrom())
+	}
+}
+
+func TestReplayRun_UnknownRunOrOtherWorkspace(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	orch := NewOrchestrator(db)
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		 VALUES ('agent-replay-ws', 'ws-a', 'Replay Agent', 'support', 'active')`); err != nil {
+		t.Fatalf("insert agent_definition: %v", err)
+	}
+	run, err := orch.TriggerAgent(ctx, TriggerAgentInput{AgentID: "agent-replay-ws", WorkspaceID: "ws-a", TriggerType: TriggerTypeManual})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+
+	if _, err = orch.ReplayRun(ctx, "ws-a", "missing-run"); !errors.Is(err, ErrAgentRunNotFound) {
+		t.Fatalf("expected ErrAgentRunNotFound, got %v", err)
+	}
+	if _, err = orch.ReplayRun(ctx, "ws-b", run.ID); !errors.Is(err, ErrAgentRunNotFound) {
+		t.Fatalf("expected ErrAgentRunNotFound across workspaces, got %v", err)
+	}
+}
+
+func TestReplayTriggerContext_NonObjectContextIsWrapped(t *testing.T) {
+	out, err := replayTriggerContext(json.RawMessage(`["a"]`), "run-1")
+	if err != nil {
+		t.Fatalf("replayTriggerContext: %v", err)
+	}
+	if string(out) != `{"original_trigger_context":["a"],"replayed_from":"run-1"}` {
+		t.Fatalf("unexpected trigger context: %s", out)
+	}
+}
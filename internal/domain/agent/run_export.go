@@ -0,0 +1,330 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/tool"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+// runExportSchemaVersion versions the RunExport bundle layout.
+const runExportSchemaVersion = "v1"
+
+// TriggerContextImportedFrom is the trigger_context key that links an
+// imported run to the run it was exported from.
+const TriggerContextImportedFrom = "imported_from"
+
+var ErrInvalidRunExport = errors.New("invalid run export")
+
+// RunExport is the self-contained bundle written by ExportRun: everything
+// needed to reproduce a run outside the workspace it ran in. Values under
+// secret-looking keys are redacted.
+type RunExport struct {
+	SchemaVersion string                `json:"schema_version"`
+	ExportedAt    time.Time             `json:"exported_at"`
+	Definition    RunExportDefinition   `json:"definition"`
+	Run           RunExportRun          `json:"run"`
+	Evidence      []RunExportEvidence   `json:"evidence"`
+	Steps         []RunExportStepStatus `json:"steps,omitempty"`
+}
+
+// RunExportDefinition is the agent definition as it was at export time.
+type RunExportDefinition struct {
+	ID            string          `json:"id"`
+	Name          string          `json:"name"`
+	Description   *string         `json:"description,omitempty"`
+	AgentType     string          `json:"agent_type"`
+	Objective     json.RawMessage `json:"objective,omitempty"`
+	AllowedTools  []string        `json:"allowed_tools"`
+	Limits        json.RawMessage `json:"limits,omitempty"`
+	TriggerConfig json.RawMessage `json:"trigger_config,omitempty"`
+	Status        string          `json:"status"`
+}
+
+// RunExportRun holds the run's inputs, trigger context, trace and output.
+type RunExportRun struct {
+	ID                   string          `json:"id"`
+	TriggerType          string          `json:"trigger_type"`
+	TriggerContext       json.RawMessage `json:"trigger_context,omitempty"`
+	Status               string          `json:"status"`
+	Inputs               json.RawMessage `json:"inputs,omitempty"`
+	RetrievalQueries     json.RawMessage `json:"retrieval_queries,omitempty"`
+	RetrievedEvidenceIDs json.RawMessage `json:"retrieved_evidence_ids,omitempty"`
+	ReasoningTrace       json.RawMessage `json:"reasoning_trace,omitempty"`
+	ToolCalls            json.RawMessage `json:"tool_calls,omitempty"`
+	Output               json.RawMessage `json:"output,omitempty"`
+	AbstentionReason     *string         `json:"abstention_reason,omitempty"`
+	TotalTokens          *int64          `json:"total_tokens,omitempty"`
+	TotalCost            *float64        `json:"total_cost,omitempty"`
+	LatencyMs            *int64          `json:"latency_ms,omitempty"`
+	TraceID              *string         `json:"trace_id,omitempty"`
+	StartedAt            time.Time       `json:"started_at"`
+	CompletedAt          *time.Time      `json:"completed_at,omitempty"`
+}
+
+// RunExportEvidence is one evidence row the run retrieved.
+type RunExportEvidence struct {
+	ID              string    `json:"id"`
+	KnowledgeItemID string    `json:"knowledge_item_id"`
+	Method          string    `json:"method"`
+	Score           float64   `json:"score"`
+	Snippet         *string   `json:"snippet,omitempty"`
+	PiiRedacted     bool      `json:"pii_redacted"`
+	Metadata        *string   `json:"metadata,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// RunExportStepStatus summarises one runtime step of the run.
+type RunExportStepStatus struct {
+	StepType string `json:"step_type"`
+	Status   string `json:"status"`
+	Attempt  int    `json:"attempt"`
+}
+
+// ExportRun returns runID as an indented JSON RunExport bundle, e.g. for a
+// support engineer to reproduce a reported bad output with ImportRun.
+func (o *Orchestrator) ExportRun(ctx context.Context, workspaceID, runID string) ([]byte, error) {
+	run, err := o.GetAgentRun(ctx, workspaceID, runID)
+	if err != nil {
+		return nil, err
+	}
+	definition, err := o.getAgentDefinition(ctx, run.DefinitionID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := o.ListRunSteps(ctx, workspaceID, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := RunExport{
+		SchemaVersion: runExportSchemaVersion,
+		ExportedAt:    time.Now().UTC(),
+		Definition:    exportDefinition(definition),
+		Run:           exportRun(run),
+		Evidence:      o.exportRunEvidence(ctx, workspaceID, run.RetrievedEvidenceIDs),
+		Steps:         exportSteps(steps),
+	}
+	out, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode run export: %w", err)
+	}
+	return out, nil
+}
+
+// ImportRun loads a RunExport bundle into workspaceID as a new agent
+// definition and a new run with fresh IDs; the run's trigger_context carries
+// imported_from = the exported run ID. Evidence stays in the bundle: the
+// knowledge items it points to are not imported.
+//
+// The imported definition is paused so no trigger runs it in the target
+// workspace, and a run exported mid-flight is imported as failed: nothing
+// will ever finish it there.
+func (o *Orchestrator) ImportRun(ctx context.Context, workspaceID string, bundle []byte) (*Run, error) {
+	var in RunExport
+	if err := json.Unmarshal(bundle, &in); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRunExport, err)
+	}
+	if in.SchemaVersion != runExportSchemaVersion {
+		return nil, fmt.Errorf("%w: unsupported schema_version %q", ErrInvalidRunExport, in.SchemaVersion)
+	}
+	if in.Run.ID == "" || in.Definition.AgentType == "" {
+		return nil, fmt.Errorf("%w: run id and definition agent_type are required", ErrInvalidRunExport)
+	}
+
+	triggerContext, err := importTriggerContext(in.Run.TriggerContext, in.Run.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin run import: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	definitionID, err := importDefinition(ctx, tx, workspaceID, in.Definition)
+	if err != nil {
+		return nil, err
+	}
+	runID := uuid.NewV7().String()
+	now := time.Now().UTC()
+	status, completedAt := importRunStatus(in.Run, now)
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO agent_run (
+			id, workspace_id, agent_definition_id, trigger_type, trigger_context,
+			status, inputs, retrieval_queries, retrieved_evidence_ids, reasoning_trace,
+			tool_calls, output, abstention_reason, total_tokens, total_cost, latency_ms,
+			trace_id, started_at, completed_at, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`,
+		runID, workspaceID, definitionID, in.Run.TriggerType, triggerContext,
+		status, jsonOr(in.Run.Inputs, emptyJSONObject), jsonOr(in.Run.RetrievalQueries, emptyJSONArray),
+		jsonOr(in.Run.RetrievedEvidenceIDs, emptyJSONArray), jsonOr(in.Run.ReasoningTrace, emptyJSONArray),
+		jsonOr(in.Run.ToolCalls, emptyJSONArray), jsonOr(in.Run.Output, emptyJSONObject),
+		in.Run.AbstentionReason, in.Run.TotalTokens, in.Run.TotalCost, in.Run.LatencyMs,
+		in.Run.TraceID, in.Run.StartedAt.UTC(), completedAt, now, now,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("insert imported agent run: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit run import: %w", err)
+	}
+	return o.GetAgentRun(ctx, workspaceID, runID)
+}
+
+func exportDefinition(def *Definition) RunExportDefinition {
+	return RunExportDefinition{
+		ID:            def.ID,
+		Name:          def.Name,
+		Description:   def.Description,
+		AgentType:     def.AgentType,
+		Objective:     tool.RedactSecrets(def.Objective),
+		AllowedTools:  def.AllowedTools,
+		Limits:        redactedJSONMap(def.Limits),
+		TriggerConfig: redactedJSONMap(def.TriggerConfig),
+		Status:        def.Status,
+	}
+}
+
+func exportRun(run *Run) RunExportRun {
+	return RunExportRun{
+		ID:                   run.ID,
+		TriggerType:          run.TriggerType,
+		TriggerContext:       tool.RedactSecrets(run.TriggerContext),
+		Status:               run.Status,
+		Inputs:               tool.RedactSecrets(run.Inputs),
+		RetrievalQueries:     run.RetrievalQueries,
+		RetrievedEvidenceIDs: run.RetrievedEvidenceIDs,
+		ReasoningTrace:       tool.RedactSecrets(run.ReasoningTrace),
+		ToolCalls:            tool.RedactSecrets(run.ToolCalls),
+		Output:               tool.RedactSecrets(run.Output),
+		AbstentionReason:     run.AbstentionReason,
+		TotalTokens:          run.TotalTokens,
+		TotalCost:            run.TotalCost,
+		LatencyMs:            run.LatencyMs,
+		TraceID:              run.TraceID,
+		StartedAt:            run.StartedAt.UTC(),
+		CompletedAt:          run.CompletedAt,
+	}
+}
+
+func exportSteps(steps []*RunStep) []RunExportStepStatus {
+	out := make([]RunExportStepStatus, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, RunExportStepStatus{StepType: step.StepType, Status: step.Status, Attempt: step.Attempt})
+	}
+	return out
+}
+
+// exportRunEvidence loads the evidence rows named by the run. IDs whose row
+// is gone are skipped, as in the handoff package.
+func (o *Orchestrator) exportRunEvidence(ctx context.Context, workspaceID string, evidenceIDs json.RawMessage) []RunExportEvidence {
+	q := sqlcgen.New(o.db)
+	ids := handoffEvidenceIDs(evidenceIDs)
+	out := make([]RunExportEvidence, 0, len(ids))
+	for _, id := range ids {
+		row, err := q.GetEvidenceByID(ctx, sqlcgen.GetEvidenceByIDParams{ID: id, WorkspaceID: workspaceID})
+		if err != nil {
+			continue
+		}
+		out = append(out, RunExportEvidence{
+			ID:              row.ID,
+			KnowledgeItemID: row.KnowledgeItemID,
+			Method:          row.Method,
+			Score:           row.Score,
+			Snippet:         row.Snippet,
+			PiiRedacted:     row.PiiRedacted,
+			Metadata:        row.Metadata,
+			CreatedAt:       row.CreatedAt,
+		})
+	}
+	return out
+}
+
+// importRunStatus keeps a terminal status and completion time; any other
+// status becomes failed, completed at now.
+func importRunStatus(run RunExportRun, now time.Time) (string, *time.Time) {
+	if isTerminalRunStatus(run.Status) {
+		return run.Status, run.CompletedAt
+	}
+	return StatusFailed, &now
+}
+
+// importDefinition inserts def into workspaceID as a paused definition.
+func importDefinition(ctx context.Context, tx *sql.Tx, workspaceID string, def RunExportDefinition) (string, error) {
+	allowedTools, err := json.Marshal(def.AllowedTools)
+	if err != nil {
+		return "", fmt.Errorf("encode imported allowed_tools: %w", err)
+	}
+	name := def.Name
+	if name == "" {
+		name = def.AgentType
+	}
+	created, err := sqlcgen.New(tx).CreateAgentDefinition(ctx, sqlcgen.CreateAgentDefinitionParams{
+		ID:            uuid.NewV7().String(),
+		WorkspaceID:   workspaceID,
+		Name:          name + " (imported " + def.ID + ")",
+		Description:   def.Description,
+		AgentType:     def.AgentType,
+		Objective:     jsonOr(def.Objective, emptyJSONObject),
+		AllowedTools:  allowedTools,
+		Limits:        jsonOr(def.Limits, emptyJSONObject),
+		TriggerConfig: jsonOr(def.TriggerConfig, emptyJSONObject),
+	})
+	if err != nil {
+		return "", fmt.Errorf("insert imported agent definition: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE agent_definition SET status = ? WHERE id = ?`, agentStatusPaused, created.ID); err != nil {
+		return "", fmt.Errorf("pause imported agent definition: %w", err)
+	}
+	return created.ID, nil
+}
+
+// importTriggerContext adds imported_from to the exported trigger context,
+// the same way replayTriggerContext adds replayed_from.
+func importTriggerContext(raw json.RawMessage, runID string) (json.RawMessage, error) {
+	tc := map[string]any{}
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) {
+		if err := json.Unmarshal(trimmed, &tc); err != nil {
+			tc = map[string]any{"original_trigger_context": json.RawMessage(trimmed)}
+		}
+	}
+	tc[TriggerContextImportedFrom] = runID
+
+	out, err := json.Marshal(tc)
+	if err != nil {
+		return nil, fmt.Errorf("encode import trigger context: %w", err)
+	}
+	return out, nil
+}
+
+func redactedJSONMap(m map[string]any) json.RawMessage {
+	if len(m) == 0 {
+		return nil
+	}
+	raw, err := json.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return tool.RedactSecrets(raw)
+}
+
+// jsonOr returns raw compacted, undoing the bundle's indentation, or
+// fallback when raw is empty.
+func jsonOr(raw json.RawMessage, fallback string) json.RawMessage {
+	var buf bytes.Buffer
+	if len(bytes.TrimSpace(raw)) == 0 || json.Compact(&buf, raw) != nil {
+		return json.RawMessage(fallback)
+	}
+	return buf.Bytes()
+}
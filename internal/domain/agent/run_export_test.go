@@ -0,0 +1,151 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExportRun_ImportRunRoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	orch := NewOrchestrator(db)
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, allowed_tools, limits, status)
+		 VALUES ('agent-export', 'ws-prod', 'Support Agent', 'support', '["get_case","send_reply"]', '{"max_tool_calls":5}', 'active')`); err != nil {
+		t.Fatalf("insert agent_definition: %v", err)
+	}
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO evidence (id, knowledge_item_id, workspace_id, method, score, snippet)
+		 VALUES ('ev-1', 'ki-1', 'ws-prod', 'bm25', 0.8, 'Reset your password from the login page')`); err != nil {
+		t.Fatalf("insert evidence: %v", err)
+	}
+
+	inputs := json.RawMessage(`{"customer_query":"can't log in","api_key":"sk-live-123"}`)
+	run, err := orch.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:        "agent-export",
+		WorkspaceID:    "ws-prod",
+		TriggerType:    TriggerTypeEvent,
+		TriggerContext: json.RawMessage(`{"case_id":"case-1"}`),
+		Inputs:         inputs,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+	tokens := int64(321)
+	if _, err = orch.UpdateAgentRun(ctx, "ws-prod", run.ID, RunUpdates{
+		Status:               StatusSuccess,
+		Inputs:               inputs,
+		RetrievalQueries:     json.RawMessage(`["password reset"]`),
+		RetrievedEvidenceIDs: json.RawMessage(`["ev-1"]`),
+		ToolCalls:            json.RawMessage(`[{"tool_name":"send_reply","params":{"case_id":"case-1","auth_token":"tok-1"}}]`),
+		Output:               json.RawMessage(`{"action":"send_reply","reply":"Use the reset link."}`),
+		TotalTokens:          &tokens,
+		Completed:            true,
+	}); err != nil {
+		t.Fatalf("UpdateAgentRun: %v", err)
+	}
+
+	bundle, err := orch.ExportRun(ctx, "ws-prod", run.ID)
+	if err != nil {
+		t.Fatalf("ExportRun: %v", err)
+	}
+	if strings.Contains(string(bundle), "sk-live-123") || strings.Contains(string(bundle), "tok-1") {
+		t.Fatalf("bundle leaks secrets: %s", bundle)
+	}
+	var exported RunExport
+	if err = json.Unmarshal(bundle, &exported); err != nil {
+		t.Fatalf("decode bundle: %v", err)
+	}
+	if len(exported.Evidence) != 1 || exported.Evidence[0].ID != "ev-1" {
+		t.Fatalf("evidence = %+v; want ev-1", exported.Evidence)
+	}
+	if exported.Definition.AgentType != "support" || len(exported.Definition.AllowedTools) != 2 {
+		t.Fatalf("definition = %+v", exported.Definition)
+	}
+
+	imported, err := orch.ImportRun(ctx, "ws-dev", bundle)
+	if err != nil {
+		t.Fatalf("ImportRun: %v", err)
+	}
+	if imported.ID == run.ID || imported.WorkspaceID != "ws-dev" {
+		t.Fatalf("imported run = %+v; want a fresh run in ws-dev", imported)
+	}
+	if imported.Status != StatusSuccess || imported.TotalTokens == nil || *imported.TotalTokens != tokens {
+		t.Fatalf("imported run = %+v; want success with %d tokens", imported, tokens)
+	}
+	if string(imported.Output) != `{"action":"send_reply","reply":"Use the reset link."}` {
+		t.Fatalf("output = %s", imported.Output)
+	}
+	if string(imported.RetrievedEvidenceIDs) != `["ev-1"]` {
+		t.Fatalf("retrieved evidence = %s", imported.RetrievedEvidenceIDs)
+	}
+	var tc map[string]any
+	if err = json.Unmarshal(imported.TriggerContext, &tc); err != nil {
+		t.Fatalf("decode trigger_context: %v", err)
+	}
+	if tc[TriggerContextImportedFrom] != run.ID || tc["case_id"] != "case-1" {
+		t.Fatalf("trigger_context = %v", tc)
+	}
+	var importedInputs map[string]any
+	if err = json.Unmarshal(imported.Inputs, &importedInputs); err != nil {
+		t.Fatalf("decode inputs: %v", err)
+	}
+	if importedInputs["customer_query"] != "can't log in" || importedInputs["api_key"] != "[REDACTED]" {
+		t.Fatalf("inputs = %v", importedInputs)
+	}
+
+	def, err := orch.GetAgentDefinition(ctx, "ws-dev", imported.DefinitionID)
+	if err != nil {
+		t.Fatalf("GetAgentDefinition: %v", err)
+	}
+	if def.AgentType != "support" || len(def.AllowedTools) != 2 || def.Limits["max_tool_calls"] != float64(5) {
+		t.Fatalf("imported definition = %+v", def)
+	}
+	if def.Status != agentStatusPaused {
+		t.Fatalf("imported definition status = %q; want %q", def.Status, agentStatusPaused)
+	}
+
+	reexported, err := orch.ExportRun(ctx, "ws-dev", imported.ID)
+	if err != nil {
+		t.Fatalf("ExportRun(imported): %v", err)
+	}
+	var again RunExport
+	if err = json.Unmarshal(reexported, &again); err != nil {
+		t.Fatalf("decode re-export: %v", err)
+	}
+	if string(again.Run.ToolCalls) != string(exported.Run.ToolCalls) || string(again.Run.Output) != string(exported.Run.Output) {
+		t.Fatalf("re-export differs: tool_calls %s vs %s", again.Run.ToolCalls, exported.Run.ToolCalls)
+	}
+}
+
+func TestImportRun_FailsRunExportedMidFlight(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bundle := `{"schema_version":"v1","definition":{"id":"agent-1","agent_type":"support","allowed_tools":[]},` +
+		`"run":{"id":"run-1","trigger_type":"manual","status":"running","started_at":"2026-01-02T03:04:05Z"},"evidence":[]}`
+	imported, err := NewOrchestrator(db).ImportRun(context.Background(), "ws-dev", []byte(bundle))
+	if err != nil {
+		t.Fatalf("ImportRun: %v", err)
+	}
+	if imported.Status != StatusFailed || imported.CompletedAt == nil {
+		t.Fatalf("imported run status = %q, completed_at = %v; want failed and completed", imported.Status, imported.CompletedAt)
+	}
+}
+
+func TestImportRun_RejectsInvalidBundle(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	orch := NewOrchestrator(db)
+	for _, bundle := range []string{`not json`, `{"schema_version":"v0"}`, `{"schema_version":"v1","run":{}}`} {
+		if _, err := orch.ImportRun(context.Background(), "ws-dev", []byte(bundle)); !errors.Is(err, ErrInvalidRunExport) {
+			t.Fatalf("ImportRun(%s) error = %v; want ErrInvalidRunExport", bundle, err)
+		}
+	}
+}
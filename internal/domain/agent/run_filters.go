@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidRunStatus = errors.New("invalid run status")
+
+// runStatusFilters lists the values ListRunsInput.Status accepts: runtime
+// statuses and the public outcomes derived from them.
+var runStatusFilters = map[string]bool{
+	StatusRunning:                      true,
+	StatusAccepted:                     true,
+	StatusRejected:                     true,
+	StatusDelegated:                    true,
+	StatusSuccess:                      true,
+	StatusPartial:                      true,
+	StatusAbstained:                    true,
+	StatusFailed:                       true,
+	StatusEscalated:                    true,
+	PublicOutcomeCompleted:             true,
+	PublicOutcomeCompletedWithWarnings: true,
+	PublicOutcomeAwaitingApproval:      true,
+	PublicOutcomeHandedOff:             true,
+	PublicOutcomeDeniedByPolicy:        true,
+}
+
+func validateRunFilters(input ListRunsInput) error {
+	if input.Status != "" && !runStatusFilters[input.Status] {
+		return fmt.Errorf("%w: %q", ErrInvalidRunStatus, input.Status)
+	}
+	if input.TriggerType != "" && !isValidTriggerType(input.TriggerType) {
+		return ErrInvalidTriggerType
+	}
+	return nil
+}
+
+// hasContextFilters reports whether a filter reads the decoded trigger
+// context or output and so cannot run in SQL.
+func (in ListRunsInput) hasContextFilters() bool {
+	return in.EntityType != "" || in.EntityID != "" || in.WorkflowID != ""
+}
+
+// runFilterClause returns the " AND ..." conditions for the SQL filters.
+func runFilterClause(input ListRunsInput) (string, []any) {
+	var clause strings.Builder
+	args := make([]any, 0, 4)
+	if input.Status != "" {
+		clause.WriteString(` AND (status = ? OR ` + runPublicOutcomeSQL + ` = ?)`)
+		args = append(args, input.Status, input.Status)
+	}
+	if input.DefinitionID != "" {
+		clause.WriteString(` AND agent_definition_id = ?`)
+		args = append(args, input.DefinitionID)
+	}
+	if input.TriggerType != "" {
+		clause.WriteString(` AND trigger_type = ?`)
+		args = append(args, input.TriggerType)
+	}
+	return clause.String(), args
+}
+
+// runPublicOutcomeSQL is PublicRunOutcome as a SQL expression over agent_run.
+var runPublicOutcomeSQL = `(CASE
+	WHEN ` + runAwaitsApprovalSQL + ` THEN '` + PublicOutcomeAwaitingApproval + `'
+	WHEN status = '` + StatusSuccess + `' THEN '` + PublicOutcomeCompleted + `'
+	WHEN status = '` + StatusPartial + `' THEN '` + PublicOutcomeCompletedWithWarnings + `'
+	WHEN status = '` + StatusAccepted + `' THEN '` + StatusRunning + `'
+	WHEN status IN ('` + StatusEscalated + `', '` + StatusDelegated + `') THEN '` + PublicOutcomeHandedOff + `'
+	WHEN status = '` + StatusRejected + `' AND ` + runDeniedByPolicySQL + ` THEN '` + PublicOutcomeDeniedByPolicy + `'
+	WHEN status = '` + StatusRejected + `' THEN '` + StatusFailed + `'
+	ELSE status
+END)`
+
+// runAwaitsApprovalSQL mirrors RunAwaitsApproval.
+var runAwaitsApprovalSQL = `(COALESCE(CASE WHEN json_valid(output) THEN CASE WHEN json_type(output) = 'object' THEN
+	json_extract(output, '$.action') = '` + pendingApprovalAction + `'
+	OR json_type(output, '$.approval_id') IS NOT NULL END END, 0))`
+
+// runDeniedByPolicySQL mirrors RunDeniedByPolicy for rejected runs.
+var runDeniedByPolicySQL = `(instr(lower(` + outputTextFieldSQL(dispatchReasonKey) + `), 'policy') > 0
+	OR instr(lower(` + outputTextFieldSQL(rejectionReasonKey) + `), 'policy') > 0
+	OR instr(lower(COALESCE(abstention_reason, '')), 'policy') > 0)`
+
+// outputTextFieldSQL reads a top-level string field of output, falling back
+// to an empty string like firstJSONString does for missing and non-string
+// values.
+func outputTextFieldSQL(key string) string {
+	return `COALESCE(CASE WHEN json_valid(output) THEN
+		CASE WHEN json_type(output, '$.` + key + `') = 'text' THEN json_extract(output, '$.` + key + `') END END, '')`
+}
@@ -0,0 +1,132 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRunPublicOutcomeSQL_MatchesPublicRunOutcome(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		 VALUES ('agent-outcome', 'ws-outcome', 'Outcome', 'support', 'active')`); err != nil {
+		t.Fatalf("insert definition: %v", err)
+	}
+
+	cases := []struct {
+		id, status, output string
+		abstention         any
+	}{
+		{"run-running", StatusRunning, `{}`, nil},
+		{"run-accepted", StatusAccepted, `{}`, nil},
+		{"run-accepted-approval", StatusAccepted, `{"approval_id":null}`, nil},
+		{"run-success-pending", StatusSuccess, `{"action":"pending_approval"}`, nil},
+		{"run-success", StatusSuccess, `{"action":"update_case"}`, nil},
+		{"run-partial", StatusPartial, `{}`, nil},
+		{"run-abstained", StatusAbstained, `{}`, string(AbstentionLowConfidence)},
+		{"run-failed", StatusFailed, `not json`, nil},
+		{"run-escalated", StatusEscalated, `{}`, nil},
+		{"run-delegated", StatusDelegated, `[]`, nil},
+		{"run-rejected-policy-reason", StatusRejected, `{"reason":"Blocked by Policy"}`, nil},
+		{"run-rejected-policy-abstention", StatusRejected, `{}`, "policy denied"},
+		{"run-rejected-non-string", StatusRejected, `{"rejection_reason":{"policy":true}}`, nil},
+		{"run-rejected", StatusRejected, `{"reason":"budget"}`, nil},
+	}
+	for _, tc := range cases {
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO agent_run (id, workspace_id, agent_definition_id, trigger_type, status, output, abstention_reason, started_at)
+			VALUES (?, 'ws-outcome', 'agent-outcome', 'manual', ?, ?, ?, datetime('now'))`,
+			tc.id, tc.status, tc.output, tc.abstention,
+		); err != nil {
+			t.Fatalf("insert %s: %v", tc.id, err)
+		}
+	}
+
+	orch := NewOrchestrator(db)
+	for _, tc := range cases {
+		run, err := orch.GetAgentRun(ctx, "ws-outcome", tc.id)
+		if err != nil {
+			t.Fatalf("GetAgentRun(%s): %v", tc.id, err)
+		}
+		var got string
+		if err := db.QueryRowContext(ctx,
+			`SELECT `+runPublicOutcomeSQL+` FROM agent_run WHERE id = ?`, tc.id,
+		).Scan(&got); err != nil {
+			t.Fatalf("select outcome for %s: %v", tc.id, err)
+		}
+		if want := PublicRunOutcome(run); got != want {
+			t.Errorf("%s: SQL outcome = %q; PublicRunOutcome = %q", tc.id, got, want)
+		}
+	}
+}
+
+func TestListAgentRuns_SQLFiltersAndCounts(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO agent_definition (id, workspace_id, name, agent_type, status) VALUES
+		('agent-support', 'ws-sql', 'Support', 'support', 'active'),
+		('agent-prospecting', 'ws-sql', 'Prospecting', 'prospecting', 'active')`); err != nil {
+		t.Fatalf("insert definitions: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO agent_run (id, workspace_id, agent_definition_id, trigger_type, status, started_at, created_at) VALUES
+		('run-1', 'ws-sql', 'agent-prospecting', 'manual', 'failed', datetime('now'), '2026-01-01 00:00:01'),
+		('run-2', 'ws-sql', 'agent-prospecting', 'schedule', 'failed', datetime('now'), '2026-01-01 00:00:02'),
+		('run-3', 'ws-sql', 'agent-prospecting', 'schedule', 'failed', datetime('now'), '2026-01-01 00:00:03'),
+		('run-4', 'ws-sql', 'agent-prospecting', 'manual', 'success', datetime('now'), '2026-01-01 00:00:04'),
+		('run-5', 'ws-sql', 'agent-support', 'manual', 'failed', datetime('now'), '2026-01-01 00:00:05')`); err != nil {
+		t.Fatalf("insert runs: %v", err)
+	}
+	orch := NewOrchestrator(db)
+
+	runs, total, err := orch.ListAgentRuns(ctx, "ws-sql", ListRunsInput{
+		Limit: 2, Status: StatusFailed, DefinitionID: "agent-prospecting",
+	})
+	if err != nil {
+		t.Fatalf("ListAgentRuns: %v", err)
+	}
+	if total != 3 || len(runs) != 2 || runs[0].ID != "run-3" || runs[1].ID != "run-2" {
+		t.Fatalf("page 1: total=%d ids=%v; want total=3 ids=[run-3 run-2]", total, collectRunIDs(runs))
+	}
+	runs, _, err = orch.ListAgentRuns(ctx, "ws-sql", ListRunsInput{
+		Limit: 2, Offset: 2, Status: StatusFailed, DefinitionID: "agent-prospecting",
+	})
+	if err != nil {
+		t.Fatalf("ListAgentRuns page 2: %v", err)
+	}
+	if len(runs) != 1 || runs[0].ID != "run-1" {
+		t.Fatalf("page 2 ids=%v; want [run-1]", collectRunIDs(runs))
+	}
+
+	runs, total, err = orch.ListAgentRuns(ctx, "ws-sql", ListRunsInput{
+		Limit: 10, TriggerType: TriggerTypeSchedule, Cursor: &RunCursor{},
+	})
+	if err != nil {
+		t.Fatalf("ListAgentRuns cursor: %v", err)
+	}
+	if total != 2 || len(runs) != 2 {
+		t.Fatalf("schedule filter: total=%d ids=%v; want 2 runs", total, collectRunIDs(runs))
+	}
+
+	_, total, err = orch.ListAgentRuns(ctx, "ws-sql", ListRunsInput{Status: PublicOutcomeCompleted})
+	if err != nil {
+		t.Fatalf("ListAgentRuns completed: %v", err)
+	}
+	if total != 1 {
+		t.Fatalf("completed total = %d; want 1", total)
+	}
+
+	if _, _, err := orch.ListAgentRuns(ctx, "ws-sql", ListRunsInput{Status: "done"}); !errors.Is(err, ErrInvalidRunStatus) {
+		t.Fatalf("err = %v; want ErrInvalidRunStatus", err)
+	}
+	if _, _, err := orch.ListAgentRuns(ctx, "ws-sql", ListRunsInput{TriggerType: "webhook"}); !errors.Is(err, ErrInvalidTriggerType) {
+		t.Fatalf("err = %v; want ErrInvalidTriggerType", err)
+	}
+}
@@ -0,0 +1,165 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+)
+
+// Per-run keys read from Definition.Limits. Missing or non-positive values
+// leave that dimension unlimited.
+const (
+	LimitMaxToolCalls = "max_tool_calls"
+	LimitMaxTokens    = "max_tokens"
+)
+
+// ErrRunLimitExceeded is returned when a run goes over its definition's
+// max_tool_calls or max_tokens.
+var ErrRunLimitExceeded = errors.New("agent run limit exceeded")
+
+// RunLimits caps what a single agent run may consume. Zero means unlimited.
+type RunLimits struct {
+	MaxToolCalls int64
+	MaxTokens    int64
+}
+
+// ParseRunLimits reads max_tool_calls and max_tokens from a definition's
+// limits. Values that are not positive numbers are ignored.
+func ParseRunLimits(limits map[string]any) RunLimits {
+	return RunLimits{
+		MaxToolCalls: positiveLimit(limits[LimitMaxToolCalls]),
+		MaxTokens:    positiveLimit(limits[LimitMaxTokens]),
+	}
+}
+
+func positiveLimit(v any) int64 {
+	var n float64
+	switch x := v.(type) {
+	case float64:
+		n = x
+	case int:
+		n = float64(x)
+	case int64:
+		n = float64(x)
+	case json.Number:
+		n, _ = x.Float64()
+	}
+	if n < 1 {
+		return 0
+	}
+	return int64(n)
+}
+
+// RunGuard counts the tool calls and tokens of one run against its limits
+// and, once AllowTools is set, rejects tools outside the agent's allowlist.
+// A nil *RunGuard allows everything, so callers need not check for one.
+type RunGuard struct {
+	limits  RunLimits
+	allowed map[string]bool
+
+	mu        sync.Mutex
+	toolCalls int64
+	tokens    int64
+}
+
+// NewRunGuard returns a guard enforcing limits.
+func NewRunGuard(limits RunLimits) *RunGuard {
+	return &RunGuard{limits: limits}
+}
+
+// ToolCall counts a call to toolName. It fails with a *ToolNotAllowedError
+// when toolName is outside the allowlist, and with ErrRunLimitExceeded when
+// the run has already made max_tool_calls calls.
+func (g *RunGuard) ToolCall(toolName string) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := g.checkAllowed(toolName); err != nil {
+		return err
+	}
+	if g.limits.MaxToolCalls > 0 && g.toolCalls >= g.limits.MaxToolCalls {
+		return fmt.Errorf("%w: tool call %q would exceed max_tool_calls=%d", ErrRunLimitExceeded, toolName, g.limits.MaxToolCalls)
+	}
+	g.toolCalls++
+	return nil
+}
+
+// AddTokens counts n LLM tokens and fails with ErrRunLimitExceeded once the
+// run's total is over max_tokens. Tokens are reported after the model call,
+// so the check stops the run from going further rather than preventing the
+// call that crossed the limit.
+func (g *RunGuard) AddTokens(n int64) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tokens += n
+	if g.limits.MaxTokens > 0 && g.tokens > g.limits.MaxTokens {
+		return fmt.Errorf("%w: used %d tokens, max_tokens=%d", ErrRunLimitExceeded, g.tokens, g.limits.MaxTokens)
+	}
+	return nil
+}
+
+type runGuardKey struct{}
+
+// WithRunGuard returns a context carrying g for the tool and LLM calls of a run.
+func WithRunGuard(ctx context.Context, g *RunGuard) context.Context {
+	return context.WithValue(ctx, runGuardKey{}, g)
+}
+
+// RunGuardFromContext returns the guard set by WithRunGuard, or nil.
+func RunGuardFromContext(ctx context.Context) *RunGuard {
+	g, _ := ctx.Value(runGuardKey{}).(*RunGuard)
+	return g
+}
+
+// GuardRun returns ctx carrying a RunGuard built from the limits of run's
+// agent definition, the run ID (see WithRunID) and the run's trace span when
+// it is traced. If the definition cannot be loaded the run is left unlimited
+// rather than failed.
+func (o *Orchestrator) GuardRun(ctx context.Context, run *Run) context.Context {
+	var limits RunLimits
+	if def, err := o.getAgentDefinition(ctx, run.DefinitionID, run.WorkspaceID); err == nil {
+		limits = ParseRunLimits(def.Limits)
+	}
+	return WithRunGuard(o.withRunSpan(WithRunID(ctx, run.ID), run.ID), NewRunGuard(limits))
+}
+
+// WithRunID returns ctx carrying runID as ctxkeys.RunID: tool executions are
+// attributed to the run, and the domain events they cause name it, which
+// keeps EventTriggerBridge from re-triggering the agent on its own changes.
+func WithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, ctxkeys.RunID, runID)
+}
+
+// FailAgentRun marks a run failed and records reason as output.error, keeping
+// the run's other recorded fields.
+func (o *Orchestrator) FailAgentRun(ctx context.Context, workspaceID, runID, reason string) (*Run, error) {
+	run, err := o.GetAgentRun(ctx, workspaceID, runID)
+	if err != nil {
+		return nil, err
+	}
+	output, err := json.Marshal(map[string]any{"error": reason})
+	if err != nil {
+		return nil, fmt.Errorf("marshal failed run output: %w", err)
+	}
+	return o.UpdateAgentRun(ctx, workspaceID, runID, RunUpdates{
+		Status:               StatusFailed,
+		Inputs:               run.Inputs,
+		RetrievalQueries:     run.RetrievalQueries,
+		RetrievedEvidenceIDs: run.RetrievedEvidenceIDs,
+		ReasoningTrace:       run.ReasoningTrace,
+		ToolCalls:            run.ToolCalls,
+		Output:               output,
+		TotalTokens:          run.TotalTokens,
+		TotalCost:            run.TotalCost,
+		Completed:            true,
+	})
+}
@@ -0,0 +1,134 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseRunLimits(t *testing.T) {
+	t.Parallel()
+
+	var limits map[string]any
+	if err := json.Unmarshal([]byte(`{"max_tool_calls":3,"max_tokens":1000,"timeout":30}`), &limits); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if got := ParseRunLimits(limits); got != (RunLimits{MaxToolCalls: 3, MaxTokens: 1000}) {
+		t.Fatalf("ParseRunLimits() = %+v", got)
+	}
+	if got := ParseRunLimits(map[string]any{"max_tool_calls": -1, "max_tokens": "10"}); got != (RunLimits{}) {
+		t.Fatalf("ParseRunLimits(invalid) = %+v; want unlimited", got)
+	}
+	if got := ParseRunLimits(nil); got != (RunLimits{}) {
+		t.Fatalf("ParseRunLimits(nil) = %+v; want unlimited", got)
+	}
+}
+
+func TestRunGuard_ToolCallLimit(t *testing.T) {
+	t.Parallel()
+
+	g := NewRunGuard(RunLimits{MaxToolCalls: 2})
+	for i := range 2 {
+		if err := g.ToolCall("create_task"); err != nil {
+			t.Fatalf("call %d: %v", i+1, err)
+		}
+	}
+	err := g.ToolCall("create_task")
+	if !errors.Is(err, ErrRunLimitExceeded) {
+		t.Fatalf("third call error = %v; want ErrRunLimitExceeded", err)
+	}
+	if !strings.Contains(err.Error(), "max_tool_calls=2") {
+		t.Fatalf("error %q does not name the limit", err)
+	}
+}
+
+func TestRunGuard_TokenLimit(t *testing.T) {
+	t.Parallel()
+
+	g := NewRunGuard(RunLimits{MaxTokens: 100})
+	if err := g.AddTokens(100); err != nil {
+		t.Fatalf("AddTokens(100): %v", err)
+	}
+	if err := g.AddTokens(1); !errors.Is(err, ErrRunLimitExceeded) {
+		t.Fatalf("AddTokens over limit error = %v; want ErrRunLimitExceeded", err)
+	}
+}
+
+func TestRunGuard_NilAllowsEverything(t *testing.T) {
+	t.Parallel()
+
+	g := RunGuardFromContext(context.Background())
+	if g != nil {
+		t.Fatalf("RunGuardFromContext(empty) = %v; want nil", g)
+	}
+	if err := g.ToolCall("x"); err != nil {
+		t.Fatalf("nil ToolCall: %v", err)
+	}
+	if err := g.AddTokens(1 << 40); err != nil {
+		t.Fatalf("nil AddTokens: %v", err)
+	}
+}
+
+func TestOrchestrator_GuardRunAndFailAgentRun(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	orch := NewOrchestrator(db)
+	// The error envelope of a failed run must not go through the schema.
+	orch.SetOutputValidator("support", func(json.RawMessage) error { return errors.New("not a support output") })
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status, limits)
+		 VALUES ('agent-1', 'ws-1', 'Test Agent', 'support', 'active', '{"max_tool_calls":1}')`); err != nil {
+		t.Fatalf("insert agent_definition: %v", err)
+	}
+	run, err := orch.TriggerAgent(ctx, TriggerAgentInput{AgentID: "agent-1", WorkspaceID: "ws-1", TriggerType: TriggerTypeManual})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+
+	guarded := orch.GuardRun(ctx, run)
+	g := RunGuardFromContext(guarded)
+	if err := g.ToolCall("a"); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	limitErr := g.ToolCall("b")
+	if !errors.Is(limitErr, ErrRunLimitExceeded) {
+		t.Fatalf("second call error = %v; want ErrRunLimitExceeded", limitErr)
+	}
+
+	failed, err := orch.FailAgentRun(ctx, "ws-1", run.ID, limitErr.Error())
+	if err != nil {
+		t.Fatalf("FailAgentRun: %v", err)
+	}
+	if failed.Status != StatusFailed || failed.CompletedAt == nil {
+		t.Fatalf("run status=%s completed_at=%v; want failed and completed", failed.Status, failed.CompletedAt)
+	}
+	var output struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(failed.Output, &output); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if !strings.Contains(output.Error, "max_tool_calls=1") {
+		t.Fatalf("output.error = %q; want the exceeded limit", output.Error)
+	}
+}
+
+func TestRunGuard_AllowTools(t *testing.T) {
+	t.Parallel()
+
+	g := NewRunGuard(RunLimits{MaxToolCalls: 1}).AllowTools([]string{"get_lead"})
+	if err := g.ValidatePlan([]string{"get_lead", "send_reply"}); !errors.Is(err, ErrToolNotAllowed) {
+		t.Fatalf("ValidatePlan() = %v; want ErrToolNotAllowed", err)
+	}
+	if err := g.ToolCall("send_reply"); !errors.Is(err, ErrToolNotAllowed) {
+		t.Fatalf("ToolCall(send_reply) = %v; want ErrToolNotAllowed", err)
+	}
+	// The rejected call is not counted against max_tool_calls.
+	if err := g.ToolCall("get_lead"); err != nil {
+		t.Fatalf("ToolCall(get_lead) = %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidRunOutput is returned by UpdateAgentRun when the output does not
+// match the schema registered for the run's agent type.
+var ErrInvalidRunOutput = errors.New("invalid agent run output")
+
+// OutputValidator checks a run output against the schema of one agent type.
+type OutputValidator func(output json.RawMessage) error
+
+// SetOutputValidator makes UpdateAgentRun validate the output of runs whose
+// agent definition has agentType before persisting it. Call it during wiring,
+// before runs start.
+func (o *Orchestrator) SetOutputValidator(agentType string, validate OutputValidator) {
+	if o.outputValidators == nil {
+		o.outputValidators = make(map[string]OutputValidator)
+	}
+	o.outputValidators[agentType] = validate
+}
+
+// ValidateOutput checks output against the validator registered for the
+// agent type of definitionID. Empty outputs, agent types without a validator
+// and unknown definitions pass.
+func (o *Orchestrator) ValidateOutput(ctx context.Context, workspaceID, definitionID string, output json.RawMessage) error {
+	if len(output) == 0 || len(o.outputValidators) == 0 {
+		return nil
+	}
+	var agentType string
+	err := o.db.QueryRowContext(ctx,
+		`SELECT agent_type FROM agent_definition WHERE id = ? AND workspace_id = ?`, definitionID, workspaceID,
+	).Scan(&agentType)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load agent type: %w", err)
+	}
+	validate, ok := o.outputValidators[agentType]
+	if !ok {
+		return nil
+	}
+	if err = validate(output); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidRunOutput, agentType, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestUpdateAgentRun_ValidatesOutputByAgentType(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		 VALUES ('agent-out', 'ws-out', 'Typed', 'support', 'active'),
+		        ('agent-free', 'ws-out', 'Untyped', 'kb', 'active')`)
+	if err != nil {
+		t.Fatalf("insert definitions: %v", err)
+	}
+
+	orch := NewOrchestrator(db)
+	orch.SetOutputValidator("support", func(output json.RawMessage) error {
+		var out struct {
+			CaseID string `json:"CaseID"`
+		}
+		if err := json.Unmarshal(output, &out); err != nil || out.CaseID == "" {
+			return errors.New("CaseID is required")
+		}
+		return nil
+	})
+	trigger := func(agentID string) *Run {
+		run, triggerErr := orch.TriggerAgent(ctx, TriggerAgentInput{AgentID: agentID, WorkspaceID: "ws-out", TriggerType: TriggerTypeManual})
+		if triggerErr != nil {
+			t.Fatalf("TriggerAgent(%s): %v", agentID, triggerErr)
+		}
+		return run
+	}
+	complete := func(run *Run, output string) error {
+		_, updateErr := orch.UpdateAgentRun(ctx, "ws-out", run.ID, RunUpdates{Status: StatusSuccess, Output: json.RawMessage(output), Completed: true})
+		return updateErr
+	}
+
+	typed := trigger("agent-out")
+	if err = complete(typed, `{"ok":true}`); !errors.Is(err, ErrInvalidRunOutput) {
+		t.Fatalf("UpdateAgentRun(invalid) error = %v; want ErrInvalidRunOutput", err)
+	}
+	stored, err := orch.GetAgentRun(ctx, "ws-out", typed.ID)
+	if err != nil {
+		t.Fatalf("GetAgentRun: %v", err)
+	}
+	if stored.Status != StatusRunning {
+		t.Fatalf("Status after rejected output = %q; want %q", stored.Status, StatusRunning)
+	}
+	if err = complete(typed, `{"CaseID":"case-1"}`); err != nil {
+		t.Fatalf("UpdateAgentRun(valid): %v", err)
+	}
+
+	if err = complete(trigger("agent-free"), `{"ok":true}`); err != nil {
+		t.Fatalf("UpdateAgentRun(no validator): %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/llm"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+	"github.com/matiasleandrokruk/fenix/internal/infra/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	runTracerName = "github.com/matiasleandrokruk/fenix/internal/domain/agent"
+	runSpanName   = "agent.run"
+
+	// DefaultRunSpanSweepInterval is how often SweepRunSpans runs once
+	// started, and DefaultRunSpanMaxAge how long a run span may stay open.
+	DefaultRunSpanSweepInterval = 5 * time.Minute
+	DefaultRunSpanMaxAge        = time.Hour
+)
+
+// openRunSpan is a run span waiting for its run to finish.
+type openRunSpan struct {
+	span        trace.Span
+	workspaceID string
+	startedAt   time.Time
+}
+
+// SetTracerProvider enables an OpenTelemetry span per agent run, exported
+// under the run's TraceID. Runs are not traced without it.
+func (o *Orchestrator) SetTracerProvider(tp trace.TracerProvider) {
+	o.tracer = tp.Tracer(runTracerName)
+}
+
+// startRunSpan starts the root span of run with run.TraceID as trace ID,
+// linked to the span of ctx when the trigger had one. The span stays open
+// until the run reaches a terminal status; GuardRun hands it to the run's
+// tool and LLM calls so their spans nest under it.
+func (o *Orchestrator) startRunSpan(ctx context.Context, run *Run) {
+	if o.tracer == nil {
+		return
+	}
+	opts := []trace.SpanStartOption{
+		trace.WithNewRoot(),
+		trace.WithAttributes(
+			attribute.String("fenix.run.id", run.ID),
+			attribute.String("fenix.workspace.id", run.WorkspaceID),
+			attribute.String("fenix.agent.id", run.DefinitionID),
+			attribute.String("fenix.run.trigger_type", run.TriggerType),
+		),
+	}
+	if parent := trace.SpanContextFromContext(ctx); parent.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: parent}))
+	}
+	_, span := o.tracer.Start(tracing.WithTraceID(ctx, derefString(run.TraceID)), runSpanName, opts...)
+	if !span.IsRecording() {
+		return
+	}
+	o.runSpans.Store(run.ID, &openRunSpan{span: span, workspaceID: run.WorkspaceID, startedAt: time.Now()})
+}
+
+// withRunSpan returns ctx carrying the open span of runID, if any.
+func (o *Orchestrator) withRunSpan(ctx context.Context, runID string) context.Context {
+	span, ok := o.runSpans.Load(runID)
+	if !ok {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, span.(*openRunSpan).span)
+}
+
+// endRunSpan ends the span of a run that reached a terminal status.
+func (o *Orchestrator) endRunSpan(run *Run) {
+	value, ok := o.runSpans.LoadAndDelete(run.ID)
+	if !ok {
+		return
+	}
+	span := value.(*openRunSpan).span
+	span.SetAttributes(attribute.String("fenix.run.status", run.Status))
+	if run.Status == StatusFailed {
+		span.SetStatus(codes.Error, "run failed")
+	}
+	span.End()
+}
+
+// SweepRunSpans ends the open run spans that no terminal path ended: those
+// of runs that are gone or finished outside this orchestrator, and those
+// open longer than maxAge, e.g. because the run's goroutine died. The run
+// itself is left alone; see RecoverRun. It returns the spans ended.
+func (o *Orchestrator) SweepRunSpans(ctx context.Context, maxAge time.Duration) int {
+	ended := 0
+	o.runSpans.Range(func(key, value any) bool {
+		runID, open := key.(string), value.(*openRunSpan)
+		run, err := o.GetAgentRun(ctx, open.workspaceID, runID)
+		switch {
+		case err == nil && isTerminalRunStatus(run.Status):
+			o.endRunSpan(run)
+		case errors.Is(err, ErrAgentRunNotFound) || time.Since(open.startedAt) > maxAge:
+			if _, ok := o.runSpans.LoadAndDelete(runID); !ok {
+				return true
+			}
+			open.span.SetStatus(codes.Error, "run span abandoned")
+			open.span.End()
+		default:
+			return true
+		}
+		ended++
+		return true
+	})
+	return ended
+}
+
+// StartRunSpanSweeper calls SweepRunSpans every interval until ctx is done.
+func (o *Orchestrator) StartRunSpanSweeper(ctx context.Context, interval, maxAge time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if n := o.SweepRunSpans(ctx, maxAge); n > 0 {
+			logging.FromContext(ctx).Warn("ended run spans left open",
+				slog.String(logging.KeyComponent, orchestratorComponent), slog.Int("spans", n))
+		}
+	}
+}
+
+// tracedLLMProvider records a child span of the run for each LLM call.
+type tracedLLMProvider struct {
+	llm.LLMProvider
+}
+
+func (p tracedLLMProvider) ChatCompletion(ctx context.Context, req llm.ChatRequest) (*llm.ChatResponse, error) {
+	ctx, span := tracing.Start(ctx, "llm.chat_completion", attribute.String("llm.model", p.ModelInfo().ID))
+	resp, err := p.LLMProvider.ChatCompletion(ctx, req)
+	if resp != nil {
+		span.SetAttributes(attribute.Int("llm.tokens", resp.Tokens))
+	}
+	tracing.End(span, err)
+	return resp, err
+}
+
+func (p tracedLLMProvider) Embed(ctx context.Context, req llm.EmbedRequest) (*llm.EmbedResponse, error) {
+	ctx, span := tracing.Start(ctx, "llm.embed",
+		attribute.String("llm.model", p.ModelInfo().ID),
+		attribute.Int("llm.texts", len(req.Texts)))
+	resp, err := p.LLMProvider.Embed(ctx, req)
+	tracing.End(span, err)
+	return resp, err
+}
@@ -0,0 +1,95 @@
+package agent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedRunOrchestrator(t *testing.T) (*Orchestrator, *tracetest.SpanRecorder, *Run) {
+	t.Helper()
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status)
+		 VALUES ('agent-traced', 'ws-traced', 'Traced', 'support', 'active')`); err != nil {
+		t.Fatalf("insert definition: %v", err)
+	}
+	orch := NewOrchestrator(db)
+	recorder := tracetest.NewSpanRecorder()
+	orch.SetTracerProvider(tracing.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	run, err := orch.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:     "agent-traced",
+		WorkspaceID: "ws-traced",
+		TriggerType: TriggerTypeManual,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+	return orch, recorder, run
+}
+
+func TestRecoverRun_FailedRecoveryEndsRunSpan(t *testing.T) {
+	orch, recorder, run := newTracedRunOrchestrator(t)
+	ctx := context.Background()
+	if _, err := orch.db.ExecContext(ctx, `
+		INSERT INTO agent_run_step (
+			id, workspace_id, agent_run_id, step_index, step_type, status, attempt, created_at, updated_at
+		) VALUES ('step-reason-1', 'ws-traced', ?, 1, ?, ?, 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	`, run.ID, StepTypeReason, StepStatusRunning); err != nil {
+		t.Fatalf("insert step: %v", err)
+	}
+
+	if _, err := orch.RecoverRun(ctx, "ws-traced", run.ID); err != nil {
+		t.Fatalf("RecoverRun: %v", err)
+	}
+	if ended := recorder.Ended(); len(ended) != 1 || ended[0].Name() != runSpanName {
+		t.Fatalf("ended spans = %d; want the run span", len(ended))
+	}
+	if _, open := orch.runSpans.Load(run.ID); open {
+		t.Fatal("run span still open after the run failed in recovery")
+	}
+}
+
+func TestSweepRunSpans_EndsFinishedAndStaleSpans(t *testing.T) {
+	orch, recorder, run := newTracedRunOrchestrator(t)
+	ctx := context.Background()
+
+	if n := orch.SweepRunSpans(ctx, time.Hour); n != 0 {
+		t.Fatalf("SweepRunSpans(running, fresh) = %d; want 0", n)
+	}
+	if n := orch.SweepRunSpans(ctx, 0); n != 1 {
+		t.Fatalf("SweepRunSpans(running, stale) = %d; want 1", n)
+	}
+
+	// A run finished without ending its span, e.g. by another instance.
+	second, err := orch.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:     "agent-traced",
+		WorkspaceID: "ws-traced",
+		TriggerType: TriggerTypeManual,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+	if _, err = orch.db.ExecContext(ctx, `UPDATE agent_run SET status = ? WHERE id = ?`, StatusSuccess, second.ID); err != nil {
+		t.Fatalf("finish run: %v", err)
+	}
+	if n := orch.SweepRunSpans(ctx, time.Hour); n != 1 {
+		t.Fatalf("SweepRunSpans(finished) = %d; want 1", n)
+	}
+
+	if ended := recorder.Ended(); len(ended) != 2 {
+		t.Fatalf("ended spans = %d; want both run spans", len(ended))
+	}
+	for _, id := range []string{run.ID, second.ID} {
+		if _, open := orch.runSpans.Load(id); open {
+			t.Fatalf("span of run %s still open", id)
+		}
+	}
+}
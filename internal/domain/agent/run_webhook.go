@@ -0,0 +1,336 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+	"github.com/matiasleandrokruk/fenix/pkg/secretbox"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+// Event bus topics for run completion webhooks.
+const (
+	// TopicAgentRunCompleted is published by the orchestrator when a run
+	// reaches a terminal state. Payload: RunCompletedEvent.
+	TopicAgentRunCompleted = "agent.run.completed"
+	// TopicAgentRunWebhookRetry carries a *RunWebhookDelivery scheduled for
+	// another attempt.
+	TopicAgentRunWebhookRetry = "agent.run.webhook.retry"
+	// TopicAgentRunWebhookDeadLetter receives a RunWebhookDeadLetter once a
+	// delivery has exhausted its attempts.
+	TopicAgentRunWebhookDeadLetter = "agent.run.webhook.dead_letter"
+)
+
+// Webhook request headers.
+const (
+	HeaderWebhookSignature = "X-Fenix-Signature"
+	HeaderWebhookEvent     = "X-Fenix-Event"
+	HeaderWebhookDelivery  = "X-Fenix-Delivery"
+)
+
+const (
+	webhookTriggerConfigKey = "webhook"
+	webhookMaxAttempts      = 5
+	webhookBaseDelay        = time.Second
+	webhookRequestTimeout   = 10 * time.Second
+	webhookOutputSummaryMax = 1000
+	webhookSignaturePrefix  = "sha256="
+	webhookContentType      = "application/json"
+)
+
+var (
+	errWebhookStatus    = errors.New("webhook endpoint returned non-2xx status")
+	errWebhookSecretKey = errors.New("no webhook secret key configured")
+)
+
+// RunCompletedEvent is the payload of TopicAgentRunCompleted.
+type RunCompletedEvent struct {
+	WorkspaceID  string
+	RunID        string
+	DefinitionID string
+	Status       string
+	Output       json.RawMessage
+	CompletedAt  *time.Time
+}
+
+// WebhookConfig is the per-definition webhook stored under
+// trigger_config.webhook as {"url": "...", "secret": "..."}. The stored
+// secret is sealed with the orchestrator's webhook secret box.
+type WebhookConfig struct {
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+// runWebhookPayload is the signed JSON body POSTed to the webhook URL.
+type runWebhookPayload struct {
+	Event         string     `json:"event"`
+	WorkspaceID   string     `json:"workspace_id"`
+	AgentID       string     `json:"agent_id"`
+	RunID         string     `json:"run_id"`
+	Status        string     `json:"status"`
+	OutputSummary string     `json:"output_summary,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// RunWebhookDelivery is one pending webhook POST. The secret is kept
+// unexported so it never leaves the package through the dead-letter topic.
+type RunWebhookDelivery struct {
+	ID          string
+	WorkspaceID string
+	RunID       string
+	URL         string
+	Body        []byte
+	Attempt     int
+	secret      string
+}
+
+// RunWebhookDeadLetter is published to TopicAgentRunWebhookDeadLetter when a
+// delivery fails permanently.
+type RunWebhookDeadLetter struct {
+	Delivery  RunWebhookDelivery
+	LastError string
+}
+
+// webhookConfigFromTrigger extracts a usable webhook from trigger_config.
+func webhookConfigFromTrigger(triggerConfig map[string]any) (WebhookConfig, bool) {
+	raw, ok := triggerConfig[webhookTriggerConfigKey]
+	if !ok {
+		return WebhookConfig{}, false
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return WebhookConfig{}, false
+	}
+	var cfg WebhookConfig
+	if json.Unmarshal(data, &cfg) != nil || cfg.Secret == "" {
+		return WebhookConfig{}, false
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return WebhookConfig{}, false
+	}
+	return cfg, true
+}
+
+// SetWebhookSecretBox sets the box that seals webhook secrets at rest.
+// Without one, CreateAgentDefinition rejects definitions with a webhook
+// secret.
+func (o *Orchestrator) SetWebhookSecretBox(box *secretbox.Box) {
+	o.webhookSecrets = box
+}
+
+// sealWebhookSecret returns triggerConfig with its webhook secret sealed.
+// Configs without a plaintext webhook secret are returned unchanged.
+func (o *Orchestrator) sealWebhookSecret(triggerConfig json.RawMessage) (json.RawMessage, error) {
+	var cfg map[string]any
+	if len(triggerConfig) == 0 || json.Unmarshal(triggerConfig, &cfg) != nil {
+		return triggerConfig, nil
+	}
+	hook, _ := cfg[webhookTriggerConfigKey].(map[string]any)
+	secret, _ := hook["secret"].(string)
+	if secret == "" || secretbox.IsSealed(secret) {
+		return triggerConfig, nil
+	}
+	if o.webhookSecrets == nil {
+		return nil, fmt.Errorf("%w: webhook secrets cannot be stored without a webhook secret key", ErrInvalidAgentDefinition)
+	}
+	sealed, err := o.webhookSecrets.Seal(secret)
+	if err != nil {
+		return nil, fmt.Errorf("seal webhook secret: %w", err)
+	}
+	hook["secret"] = sealed
+	out, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("encode trigger_config: %w", err)
+	}
+	return out, nil
+}
+
+// openWebhookSecret returns the signing key for a stored secret. Secrets
+// stored before they were sealed are used as they are.
+func (o *Orchestrator) openWebhookSecret(stored string) (string, error) {
+	if !secretbox.IsSealed(stored) {
+		return stored, nil
+	}
+	if o.webhookSecrets == nil {
+		return "", errWebhookSecretKey
+	}
+	return o.webhookSecrets.Open(stored)
+}
+
+// SignWebhookBody returns the HeaderWebhookSignature value for body:
+// "sha256=" followed by the hex HMAC-SHA256 of the body keyed with secret.
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return webhookSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// RunWebhookNotifier delivers run completion webhooks. Delivery happens on the
+// event bus consumer goroutine, never on the path that completed the run.
+// Failed attempts are re-published to TopicAgentRunWebhookRetry with
+// exponential backoff; after webhookMaxAttempts the delivery goes to
+// TopicAgentRunWebhookDeadLetter.
+type RunWebhookNotifier struct {
+	orchestrator *Orchestrator
+	bus          eventbus.EventBus
+	client       *http.Client
+	maxAttempts  int
+	baseDelay    time.Duration
+}
+
+// NewRunWebhookNotifier creates a notifier that reads webhook config from the
+// orchestrator's agent definitions.
+func NewRunWebhookNotifier(o *Orchestrator, bus eventbus.EventBus) *RunWebhookNotifier {
+	return &RunWebhookNotifier{
+		orchestrator: o,
+		bus:          bus,
+		client:       &http.Client{Timeout: webhookRequestTimeout},
+		maxAttempts:  webhookMaxAttempts,
+		baseDelay:    webhookBaseDelay,
+	}
+}
+
+// Start consumes completion and retry events until ctx is cancelled.
+// Runs in the calling goroutine — launch with: go n.Start(ctx)
+func (n *RunWebhookNotifier) Start(ctx context.Context) {
+	completed := n.bus.Subscribe(TopicAgentRunCompleted)
+	retries := n.bus.Subscribe(TopicAgentRunWebhookRetry)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case evt := <-completed:
+			if payload, ok := evt.Payload.(RunCompletedEvent); ok {
+				n.handleCompleted(ctx, payload)
+			}
+		case evt := <-retries:
+			if delivery, ok := evt.Payload.(*RunWebhookDelivery); ok {
+				n.attempt(ctx, delivery)
+			}
+		}
+	}
+}
+
+func (n *RunWebhookNotifier) handleCompleted(ctx context.Context, evt RunCompletedEvent) {
+	delivery, ok := n.newDelivery(ctx, evt)
+	if !ok {
+		return
+	}
+	n.attempt(ctx, delivery)
+}
+
+// newDelivery builds the delivery for evt, or reports false when the agent
+// definition has no webhook configured.
+func (n *RunWebhookNotifier) newDelivery(ctx context.Context, evt RunCompletedEvent) (*RunWebhookDelivery, bool) {
+	def, err := n.orchestrator.GetAgentDefinition(ctx, evt.WorkspaceID, evt.DefinitionID)
+	if err != nil {
+		return nil, false
+	}
+	cfg, ok := webhookConfigFromTrigger(def.TriggerConfig)
+	if !ok {
+		return nil, false
+	}
+	secret, err := n.orchestrator.openWebhookSecret(cfg.Secret)
+	if err != nil {
+		logging.FromContext(ctx).WarnContext(ctx, "run webhook skipped: cannot open webhook secret",
+			slog.String(logging.KeyComponent, orchestratorComponent),
+			slog.String(logging.KeyWorkspaceID, evt.WorkspaceID),
+			slog.String("agent_definition_id", evt.DefinitionID),
+			logging.Err(err))
+		return nil, false
+	}
+	body, err := json.Marshal(runWebhookPayload{
+		Event:         TopicAgentRunCompleted,
+		WorkspaceID:   evt.WorkspaceID,
+		AgentID:       evt.DefinitionID,
+		RunID:         evt.RunID,
+		Status:        evt.Status,
+		OutputSummary: summarizeRunOutput(evt.Output),
+		CompletedAt:   evt.CompletedAt,
+	})
+	if err != nil {
+		return nil, false
+	}
+	return &RunWebhookDelivery{
+		ID:          uuid.NewV7().String(),
+		WorkspaceID: evt.WorkspaceID,
+		RunID:       evt.RunID,
+		URL:         cfg.URL,
+		Body:        body,
+		Attempt:     1,
+		secret:      secret,
+	}, true
+}
+
+func (n *RunWebhookNotifier) attempt(ctx context.Context, d *RunWebhookDelivery) {
+	err := n.post(ctx, d)
+	if err == nil {
+		return
+	}
+	if d.Attempt >= n.maxAttempts {
+		n.bus.Publish(TopicAgentRunWebhookDeadLetter, RunWebhookDeadLetter{Delivery: *d, LastError: err.Error()})
+		return
+	}
+	next := *d
+	next.Attempt++
+	delay := n.baseDelay << (d.Attempt - 1)
+	time.AfterFunc(delay, func() { n.bus.Publish(TopicAgentRunWebhookRetry, &next) })
+}
+
+func (n *RunWebhookNotifier) post(ctx context.Context, d *RunWebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", webhookContentType)
+	req.Header.Set(HeaderWebhookEvent, TopicAgentRunCompleted)
+	req.Header.Set(HeaderWebhookDelivery, d.ID)
+	req.Header.Set(HeaderWebhookSignature, SignWebhookBody(d.secret, d.Body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %s", errWebhookStatus, resp.Status)
+	}
+	return nil
+}
+
+// summarizeRunOutput compacts the run output and truncates it to
+// webhookOutputSummaryMax bytes.
+func summarizeRunOutput(output json.RawMessage) string {
+	if len(output) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	summary := string(output)
+	if json.Compact(&buf, output) == nil {
+		summary = buf.String()
+	}
+	if summary == "null" || summary == emptyJSONObject {
+		return ""
+	}
+	if len(summary) > webhookOutputSummaryMax {
+		summary = strings.ToValidUTF8(summary[:webhookOutputSummaryMax], "") + "…"
+	}
+	return summary
+}
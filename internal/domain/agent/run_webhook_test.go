@@ -0,0 +1,216 @@
+package agent
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/pkg/secretbox"
+)
+
+func TestSignWebhookBody(t *testing.T) {
+	// Reference value: printf '{"ok":true}' | openssl dgst -sha256 -hmac secret
+	got := SignWebhookBody("secret", []byte(`{"ok":true}`))
+	want := "sha256=f6b4a2841c93f8bf2fb8f2c13d8fb0b6c8e8019f09ee405d248daa8385fad638"
+	if got != want {
+		t.Fatalf("signature = %q, want %q", got, want)
+	}
+}
+
+func TestWebhookConfigFromTrigger(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]any
+		ok     bool
+	}{
+		{"no webhook", map[string]any{"type": "manual"}, false},
+		{"valid", map[string]any{"webhook": map[string]any{"url": "https://hooks.example.com/x", "secret": "s"}}, true},
+		{"missing secret", map[string]any{"webhook": map[string]any{"url": "https://hooks.example.com/x"}}, false},
+		{"bad scheme", map[string]any{"webhook": map[string]any{"url": "ftp://hooks.example.com", "secret": "s"}}, false},
+		{"not an object", map[string]any{"webhook": "https://hooks.example.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := webhookConfigFromTrigger(tt.config); ok != tt.ok {
+				t.Fatalf("ok = %v, want %v", ok, tt.ok)
+			}
+		})
+	}
+}
+
+func newWebhookSecretBox(t *testing.T) *secretbox.Box {
+	t.Helper()
+	box, err := secretbox.New("test-webhook-key")
+	if err != nil {
+		t.Fatalf("secretbox.New: %v", err)
+	}
+	return box
+}
+
+func TestCreateAgentDefinition_SealsWebhookSecret(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+	trigger := json.RawMessage(`{"webhook":{"url":"https://hooks.example.com/x","secret":"hook-secret"}}`)
+	input := CreateAgentDefinitionInput{WorkspaceID: "ws-hook", Name: "Hooked", AgentType: "support", TriggerConfig: trigger}
+
+	if _, _, err := NewOrchestrator(db).CreateAgentDefinition(ctx, input); !errors.Is(err, ErrInvalidAgentDefinition) {
+		t.Fatalf("CreateAgentDefinition without a box err = %v, want ErrInvalidAgentDefinition", err)
+	}
+
+	orch := NewOrchestrator(db)
+	orch.SetWebhookSecretBox(newWebhookSecretBox(t))
+	def, _, err := orch.CreateAgentDefinition(ctx, input)
+	if err != nil {
+		t.Fatalf("CreateAgentDefinition: %v", err)
+	}
+	var stored string
+	if err := db.QueryRowContext(ctx, `SELECT trigger_config FROM agent_definition WHERE id = ?`, def.ID).Scan(&stored); err != nil {
+		t.Fatalf("select trigger_config: %v", err)
+	}
+	if strings.Contains(stored, "hook-secret") {
+		t.Fatalf("trigger_config stores the plaintext secret: %s", stored)
+	}
+	cfg, ok := webhookConfigFromTrigger(def.TriggerConfig)
+	if !ok || cfg.URL != "https://hooks.example.com/x" {
+		t.Fatalf("webhook config = %+v, %v", cfg, ok)
+	}
+	if secret, err := orch.openWebhookSecret(cfg.Secret); err != nil || secret != "hook-secret" {
+		t.Fatalf("openWebhookSecret = %q, %v", secret, err)
+	}
+}
+
+func setupWebhookRun(t *testing.T, db *sql.DB, hookURL string) (*Orchestrator, eventbus.EventBus, *Run) {
+	t.Helper()
+	ctx := context.Background()
+	box := newWebhookSecretBox(t)
+	sealed, err := box.Seal("hook-secret")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	trigger, _ := json.Marshal(map[string]any{
+		"webhook": map[string]any{"url": hookURL, "secret": sealed},
+	})
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status, trigger_config)
+		 VALUES ('agent-hook', 'ws-hook', 'Hooked', 'support', 'active', ?)`, string(trigger)); err != nil {
+		t.Fatalf("insert agent_definition: %v", err)
+	}
+
+	bus := eventbus.New()
+	orch := NewOrchestrator(db)
+	orch.SetEventBus(bus)
+	orch.SetWebhookSecretBox(box)
+	run, err := orch.TriggerAgent(ctx, TriggerAgentInput{
+		AgentID:     "agent-hook",
+		WorkspaceID: "ws-hook",
+		TriggerType: TriggerTypeManual,
+	})
+	if err != nil {
+		t.Fatalf("TriggerAgent: %v", err)
+	}
+	return orch, bus, run
+}
+
+func TestRunWebhookNotifier_PostsSignedPayloadOnCompletion(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	type received struct {
+		body      []byte
+		signature string
+		event     string
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{body: body, signature: r.Header.Get(HeaderWebhookSignature), event: r.Header.Get(HeaderWebhookEvent)}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	orch, bus, run := setupWebhookRun(t, db, srv.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go NewRunWebhookNotifier(orch, bus).Start(ctx)
+	time.Sleep(10 * time.Millisecond) // let Start subscribe
+
+	if _, err := orch.UpdateAgentRun(context.Background(), run.WorkspaceID, run.ID, RunUpdates{
+		Status:    StatusSuccess,
+		Output:    json.RawMessage(`{"summary": "case resolved"}`),
+		Completed: true,
+	}); err != nil {
+		t.Fatalf("UpdateAgentRun: %v", err)
+	}
+
+	select {
+	case req := <-got:
+		if req.signature != SignWebhookBody("hook-secret", req.body) {
+			t.Fatalf("signature %q does not match body", req.signature)
+		}
+		if req.event != TopicAgentRunCompleted {
+			t.Fatalf("event header = %q", req.event)
+		}
+		var payload runWebhookPayload
+		if err := json.Unmarshal(req.body, &payload); err != nil {
+			t.Fatalf("unmarshal payload: %v", err)
+		}
+		if payload.RunID != run.ID || payload.Status != StatusSuccess || payload.OutputSummary != `{"summary":"case resolved"}` {
+			t.Fatalf("payload = %+v", payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for webhook delivery")
+	}
+}
+
+func TestRunWebhookNotifier_RetriesThenDeadLetters(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	orch, bus, run := setupWebhookRun(t, db, srv.URL)
+	deadLetters := bus.Subscribe(TopicAgentRunWebhookDeadLetter)
+
+	notifier := NewRunWebhookNotifier(orch, bus)
+	notifier.maxAttempts = 3
+	notifier.baseDelay = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.Start(ctx)
+	time.Sleep(10 * time.Millisecond) // let Start subscribe
+
+	if _, err := orch.UpdateAgentRunStatus(context.Background(), run.WorkspaceID, run.ID, StatusFailed); err != nil {
+		t.Fatalf("UpdateAgentRunStatus: %v", err)
+	}
+
+	select {
+	case evt := <-deadLetters:
+		dl, ok := evt.Payload.(RunWebhookDeadLetter)
+		if !ok {
+			t.Fatalf("unexpected payload %T", evt.Payload)
+		}
+		if dl.Delivery.RunID != run.ID || dl.Delivery.Attempt != 3 || dl.LastError == "" {
+			t.Fatalf("dead letter = %+v", dl)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for dead letter")
+	}
+	if n := hits.Load(); n != 3 {
+		t.Fatalf("webhook hit %d times, want 3", n)
+	}
+}
@@ -578,7 +578,12 @@ func (o *Orchestrator) failRecoveredRun(ctx context.Context, tx *sql.Tx, run *Ru
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("commit failed run recovery: %w", err)
 	}
-	return o.GetAgentRun(ctx, run.WorkspaceID, run.ID)
+	failed, err := o.GetAgentRun(ctx, run.WorkspaceID, run.ID)
+	if err != nil {
+		return nil, err
+	}
+	o.endRunSpan(failed)
+	return failed, nil
 }
 
 func shouldFailRecovery(step *RunStep) bool {
@@ -23,6 +23,8 @@ func (s stubToolExecutor) Execute(_ context.Context, _ json.RawMessage) (json.Ra
 	return s.result, nil
 }
 
+func (stubToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
 func TestSkillRunnerRunLoadsBridgeWorkflowFromInput(t *testing.T) {
 	t.Parallel()
 
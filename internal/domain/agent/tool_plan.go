@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrToolNotAllowed is matched by every *ToolNotAllowedError.
+var ErrToolNotAllowed = errors.New("tool not allowed for agent")
+
+// ToolNotAllowedError reports a tool call outside an agent's allowlist.
+type ToolNotAllowedError struct {
+	Tool    string
+	Allowed []string
+}
+
+func (e *ToolNotAllowedError) Error() string {
+	return fmt.Sprintf("%v: %q is not in [%s]", ErrToolNotAllowed, e.Tool, strings.Join(e.Allowed, ", "))
+}
+
+// Is makes errors.Is(err, ErrToolNotAllowed) match.
+func (e *ToolNotAllowedError) Is(target error) bool {
+	return target == ErrToolNotAllowed
+}
+
+// AllowTools restricts the run to allowed. Calls to any other tool fail in
+// ToolCall before they reach the registry. It returns g for chaining.
+func (g *RunGuard) AllowTools(allowed []string) *RunGuard {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.allowed = toolSet(allowed)
+	return g
+}
+
+// ValidatePlan checks planned against the guard's allowlist without counting
+// any calls. An agent's Objective only describes what to do; the allowlist is
+// what it may do, so a plan steered by injected instructions is still held
+// to it.
+func (g *RunGuard) ValidatePlan(planned []string) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, name := range planned {
+		if err := g.checkAllowed(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkAllowed must be called with g.mu held.
+func (g *RunGuard) checkAllowed(toolName string) error {
+	if g.allowed == nil || g.allowed[toolName] {
+		return nil
+	}
+	allowed := make([]string, 0, len(g.allowed))
+	for name := range g.allowed {
+		allowed = append(allowed, name)
+	}
+	slices.Sort(allowed)
+	return &ToolNotAllowedError{Tool: toolName, Allowed: allowed}
+}
+
+func toolSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
@@ -0,0 +1,189 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
+)
+
+// Task 4.7: tamper-evident hash chain.
+//
+// Every event logged through AuditService.Log stores
+// hash = sha256(prev_hash || canonical(event)), where prev_hash is the hash of
+// the previous event of the same workspace in insertion order. Editing a row
+// breaks its own hash; deleting or reordering rows breaks the next prev_hash.
+
+const chainVerifyBatchSize = 500
+
+// chainLocks serializes chain appends per workspace. It is package level
+// because several AuditService instances may share one database.
+var chainLocks sync.Map // workspaceID -> *sync.Mutex
+
+func lockWorkspaceChain(workspaceID string) func() {
+	v, _ := chainLocks.LoadOrStore(workspaceID, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// chainRecord is the canonical, order-stable form of an event that is hashed.
+type chainRecord struct {
+	ID                 string  `json:"id"`
+	WorkspaceID        string  `json:"workspace_id"`
+	ActorID            string  `json:"actor_id"`
+	ActorType          string  `json:"actor_type"`
+	Action             string  `json:"action"`
+	EntityType         *string `json:"entity_type"`
+	EntityID           *string `json:"entity_id"`
+	Details            string  `json:"details"`
+	PermissionsChecked string  `json:"permissions_checked"`
+	Outcome            string  `json:"outcome"`
+	TraceID            *string `json:"trace_id"`
+	IPAddress          *string `json:"ip_address"`
+	UserAgent          *string `json:"user_agent"`
+	CreatedAt          string  `json:"created_at"`
+}
+
+func chainRecordFromParams(p sqlcgen.CreateAuditEventParams) chainRecord {
+	return chainRecord{
+		ID:                 p.ID,
+		WorkspaceID:        p.WorkspaceID,
+		ActorID:            p.ActorID,
+		ActorType:          p.ActorType,
+		Action:             p.Action,
+		EntityType:         p.EntityType,
+		EntityID:           p.EntityID,
+		Details:            string(p.Details),
+		PermissionsChecked: string(p.PermissionsChecked),
+		Outcome:            p.Outcome,
+		TraceID:            p.TraceID,
+		IPAddress:          p.IpAddress,
+		UserAgent:          p.UserAgent,
+		CreatedAt:          canonicalChainTime(p.CreatedAt),
+	}
+}
+
+func chainRecordFromRow(row sqlcgen.ListAuditChainPageRow) chainRecord {
+	return chainRecord{
+		ID:                 row.ID,
+		WorkspaceID:        row.WorkspaceID,
+		ActorID:            row.ActorID,
+		ActorType:          row.ActorType,
+		Action:             row.Action,
+		EntityType:         row.EntityType,
+		EntityID:           row.EntityID,
+		Details:            string(row.Details),
+		PermissionsChecked: string(row.PermissionsChecked),
+		Outcome:            row.Outcome,
+		TraceID:            row.TraceID,
+		IPAddress:          row.IpAddress,
+		UserAgent:          row.UserAgent,
+		CreatedAt:          canonicalChainTime(row.CreatedAt),
+	}
+}
+
+func canonicalChainTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// chainHash returns hex(sha256(prevHash || canonical(rec))).
+func chainHash(prevHash string, rec chainRecord) (string, error) {
+	canonical, err := json.Marshal(rec)
+	if err != nil {
+		return "", fmt.Errorf("canonicalize audit event: %w", err)
+	}
+	h := sha256.New()
+	h.Write([]byte(prevHash))
+	h.Write(canonical)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// latestChainHash returns the hash new events link to: the newest chained
+// event, or the purge anchor when every chained event has been purged.
+func (s *AuditService) latestChainHash(ctx context.Context, workspaceID string) (string, error) {
+	hash, err := s.querier.GetLatestAuditEventHash(ctx, workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s.chainAnchor(ctx, workspaceID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("get latest audit hash: %w", err)
+	}
+	return hash, nil
+}
+
+// chainAnchor returns the prev_hash expected for the oldest remaining chained
+// event: empty for a never-purged workspace, otherwise the last purged hash.
+func (s *AuditService) chainAnchor(ctx context.Context, workspaceID string) (string, error) {
+	anchor, err := s.querier.GetAuditChainAnchor(ctx, workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get audit chain anchor: %w", err)
+	}
+	return anchor, nil
+}
+
+// VerifyChain walks the workspace hash chain in insertion order and returns the
+// first broken link, or nil when the chain is intact. Events written before
+// hash chaining was introduced carry no hash and are skipped. After a purge the
+// walk starts from the stored anchor instead of an empty genesis hash.
+func (s *AuditService) VerifyChain(ctx context.Context, workspaceID string) (*ChainBreak, error) {
+	prevHash, err := s.chainAnchor(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		afterSeq int64
+		position int
+	)
+	for {
+		rows, err := s.querier.ListAuditChainPage(ctx, sqlcgen.ListAuditChainPageParams{
+			WorkspaceID: workspaceID,
+			AfterSeq:    afterSeq,
+			Lim:         chainVerifyBatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list audit chain: %w", err)
+		}
+		for i := range rows {
+			position++
+			row := rows[i]
+			if row.PrevHash != prevHash {
+				return &ChainBreak{
+					EventID:          row.ID,
+					Position:         position,
+					Reason:           ChainBreakPrevHashMismatch,
+					ExpectedPrevHash: prevHash,
+					ActualPrevHash:   row.PrevHash,
+				}, nil
+			}
+			expected, hashErr := chainHash(row.PrevHash, chainRecordFromRow(row))
+			if hashErr != nil {
+				return nil, hashErr
+			}
+			if expected != row.Hash {
+				return &ChainBreak{
+					EventID:          row.ID,
+					Position:         position,
+					Reason:           ChainBreakHashMismatch,
+					ExpectedPrevHash: prevHash,
+					ActualPrevHash:   row.PrevHash,
+				}, nil
+			}
+			prevHash = row.Hash
+		}
+		if len(rows) < chainVerifyBatchSize {
+			return nil, nil
+		}
+		afterSeq = rows[len(rows)-1].Seq
+	}
+}
@@ -0,0 +1,165 @@
+// Task 4.7: tamper-evident audit hash chain
+// Traces: FR-070
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+func logChainEvent(t *testing.T, svc *AuditService, wsID, action string) *AuditEvent {
+	t.Helper()
+	e := &AuditEvent{
+		ID:          uuid.NewV7().String(),
+		WorkspaceID: wsID,
+		ActorID:     "actor-1",
+		ActorType:   ActorTypeUser,
+		Action:      action,
+		Details:     []byte(`{"metadata":{"k":"v"}}`),
+		Outcome:     OutcomeSuccess,
+		CreatedAt:   time.Now(),
+	}
+	if err := svc.Log(context.Background(), e); err != nil {
+		t.Fatalf("Log failed: %v", err)
+	}
+	return e
+}
+
+func TestVerifyChain_IntactAndLinkedPerWorkspace(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	wsID := uuid.NewV7().String()
+	otherWS := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+	createWorkspaceForTest(t, db, otherWS)
+
+	first := logChainEvent(t, svc, wsID, "a")
+	otherFirst := logChainEvent(t, svc, otherWS, "a")
+	second := logChainEvent(t, svc, wsID, "b")
+
+	if first.PrevHash != "" || first.Hash == "" {
+		t.Fatalf("expected genesis event with empty prev_hash, got prev=%q hash=%q", first.PrevHash, first.Hash)
+	}
+	if second.PrevHash != first.Hash {
+		t.Fatalf("expected second.prev_hash to link to first.hash")
+	}
+	if otherFirst.PrevHash != "" {
+		t.Fatalf("expected independent chain per workspace, got prev_hash %q", otherFirst.PrevHash)
+	}
+
+	stored, err := svc.GetByID(context.Background(), second.ID)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if stored.Hash != second.Hash || stored.PrevHash != second.PrevHash {
+		t.Fatalf("stored hashes do not match logged event")
+	}
+
+	brk, err := svc.VerifyChain(context.Background(), wsID)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if brk != nil {
+		t.Fatalf("expected intact chain, got %+v", brk)
+	}
+}
+
+func TestVerifyChain_DetectsModifiedEvent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+
+	logChainEvent(t, svc, wsID, "a")
+	tampered := logChainEvent(t, svc, wsID, "b")
+	logChainEvent(t, svc, wsID, "c")
+
+	if _, err := db.Exec(`DROP TRIGGER trg_audit_event_no_update`); err != nil {
+		t.Fatalf("drop trigger: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE audit_event SET outcome = 'denied' WHERE id = ?`, tampered.ID); err != nil {
+		t.Fatalf("tamper update: %v", err)
+	}
+
+	brk, err := svc.VerifyChain(context.Background(), wsID)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if brk == nil {
+		t.Fatal("expected chain break after tampering")
+	}
+	if brk.EventID != tampered.ID || brk.Reason != ChainBreakHashMismatch || brk.Position != 2 {
+		t.Fatalf("unexpected break: %+v", brk)
+	}
+}
+
+func TestVerifyChain_DetectsDeletedEvent(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+
+	first := logChainEvent(t, svc, wsID, "a")
+	deleted := logChainEvent(t, svc, wsID, "b")
+	third := logChainEvent(t, svc, wsID, "c")
+
+	if _, err := db.Exec(`DROP TRIGGER trg_audit_event_no_delete`); err != nil {
+		t.Fatalf("drop trigger: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM audit_event WHERE id = ?`, deleted.ID); err != nil {
+		t.Fatalf("tamper delete: %v", err)
+	}
+
+	brk, err := svc.VerifyChain(context.Background(), wsID)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if brk == nil {
+		t.Fatal("expected chain break after deletion")
+	}
+	if brk.EventID != third.ID || brk.Reason != ChainBreakPrevHashMismatch || brk.ExpectedPrevHash != first.Hash {
+		t.Fatalf("unexpected break: %+v", brk)
+	}
+}
+
+func TestLog_ConcurrentCallsKeepChainLinear(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+
+	const n = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Separate service instances share the same per-workspace lock.
+			svc := NewAuditService(db)
+			errs <- svc.LogWithDetails(context.Background(), wsID, "actor", ActorTypeSystem, "concurrent", nil, nil, nil, OutcomeSuccess)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("concurrent Log failed: %v", err)
+		}
+	}
+
+	brk, err := NewAuditService(db).VerifyChain(context.Background(), wsID)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if brk != nil {
+		t.Fatalf("expected linear chain, got break %+v", brk)
+	}
+}
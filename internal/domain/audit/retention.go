@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
+)
+
+// Purge deletes audit events of workspaceID created before olderThan and
+// returns how many were removed. With dryRun it only counts them.
+//
+// Only the oldest contiguous run of events (insertion order) older than the
+// cutoff is removed, so the remaining hash chain stays linked; the chain is
+// re-anchored at the new oldest event.
+// Task 4.8: audit retention
+func (s *AuditService) Purge(ctx context.Context, workspaceID string, olderThan time.Time, dryRun bool) (int, error) {
+	if workspaceID == "" {
+		return 0, ErrPurgeWorkspaceRequired
+	}
+	cutoff := olderThan.UTC().Format(windowLayout)
+
+	if dryRun {
+		n, err := s.querier.CountAuditEventsForPurge(ctx, sqlcgen.CountAuditEventsForPurgeParams{
+			WorkspaceID: workspaceID,
+			Cutoff:      cutoff,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("count audit events for purge: %w", err)
+		}
+		return int(n), nil
+	}
+
+	unlock := lockWorkspaceChain(workspaceID)
+	defer unlock()
+	return s.purgeInTx(ctx, workspaceID, cutoff)
+}
+
+func (s *AuditService) purgeInTx(ctx context.Context, workspaceID, cutoff string) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	qtx := sqlcgen.New(s.db).WithTx(tx)
+
+	anchor, err := qtx.GetAuditPurgeAnchorHash(ctx, sqlcgen.GetAuditPurgeAnchorHashParams{
+		WorkspaceID: workspaceID,
+		Cutoff:      cutoff,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("get audit purge anchor: %w", err)
+	}
+
+	// The purge session row is only visible inside this transaction and is the
+	// sole way past the append-only delete trigger.
+	if err = qtx.BeginAuditPurgeSession(ctx, workspaceID); err != nil {
+		return 0, fmt.Errorf("begin audit purge session: %w", err)
+	}
+	n, err := qtx.DeleteAuditEventsForPurge(ctx, sqlcgen.DeleteAuditEventsForPurgeParams{
+		WorkspaceID: workspaceID,
+		Cutoff:      cutoff,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("delete audit events for purge: %w", err)
+	}
+	if err = qtx.EndAuditPurgeSession(ctx, workspaceID); err != nil {
+		return 0, fmt.Errorf("end audit purge session: %w", err)
+	}
+
+	if anchor != "" {
+		if err = qtx.UpsertAuditChainAnchor(ctx, sqlcgen.UpsertAuditChainAnchorParams{
+			WorkspaceID: workspaceID,
+			AnchorHash:  anchor,
+			UpdatedAt:   time.Now().UTC(),
+		}); err != nil {
+			return 0, fmt.Errorf("upsert audit chain anchor: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit: %w", err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,127 @@
+// Task 4.8: audit retention purge
+// Traces: FR-070
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+func countWorkspaceEvents(t *testing.T, db *sql.DB, wsID string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM audit_event WHERE workspace_id = ?`, wsID).Scan(&n); err != nil {
+		t.Fatalf("count audit events: %v", err)
+	}
+	return n
+}
+
+func TestPurge_DryRunCountsThenPurgeReanchorsChain(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+
+	old := time.Now().UTC().AddDate(-2, 0, 0)
+	for i := 0; i < 3; i++ {
+		mustLogEvent(t, svc, wsID, "actor", "old", OutcomeSuccess, old.Add(time.Duration(i)*time.Minute))
+	}
+	for i := 0; i < 2; i++ {
+		mustLogEvent(t, svc, wsID, "actor", "recent", OutcomeSuccess, time.Now().UTC())
+	}
+	cutoff := time.Now().UTC().AddDate(-1, 0, 0)
+
+	n, err := svc.Purge(ctx, wsID, cutoff, true)
+	if err != nil {
+		t.Fatalf("dry-run Purge failed: %v", err)
+	}
+	if n != 3 || countWorkspaceEvents(t, db, wsID) != 5 {
+		t.Fatalf("expected dry-run to count 3 and keep 5 rows, got n=%d rows=%d", n, countWorkspaceEvents(t, db, wsID))
+	}
+
+	n, err = svc.Purge(ctx, wsID, cutoff, false)
+	if err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if n != 3 || countWorkspaceEvents(t, db, wsID) != 2 {
+		t.Fatalf("expected 3 purged and 2 left, got n=%d rows=%d", n, countWorkspaceEvents(t, db, wsID))
+	}
+
+	mustLogEvent(t, svc, wsID, "actor", "after-purge", OutcomeSuccess, time.Now().UTC())
+	brk, err := svc.VerifyChain(ctx, wsID)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if brk != nil {
+		t.Fatalf("expected re-anchored chain to verify, got %+v", brk)
+	}
+}
+
+func TestPurge_AllEventsThenNewEventsStillVerify(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+
+	mustLogEvent(t, svc, wsID, "actor", "old", OutcomeSuccess, time.Now().UTC().AddDate(-2, 0, 0))
+	if _, err := svc.Purge(ctx, wsID, time.Now().UTC(), false); err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	mustLogEvent(t, svc, wsID, "actor", "new", OutcomeSuccess, time.Now().UTC())
+
+	brk, err := svc.VerifyChain(ctx, wsID)
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if brk != nil {
+		t.Fatalf("expected chain to continue from anchor, got %+v", brk)
+	}
+}
+
+func TestPurge_IsScopedToWorkspace(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+	otherWS := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+	createWorkspaceForTest(t, db, otherWS)
+
+	old := time.Now().UTC().AddDate(-2, 0, 0)
+	mustLogEvent(t, svc, wsID, "actor", "old", OutcomeSuccess, old)
+	mustLogEvent(t, svc, otherWS, "actor", "old", OutcomeSuccess, old)
+
+	if _, err := svc.Purge(ctx, wsID, time.Now().UTC(), false); err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if countWorkspaceEvents(t, db, otherWS) != 1 {
+		t.Fatal("expected other workspace events to be untouched")
+	}
+
+	if _, err := svc.Purge(ctx, "", time.Now().UTC(), true); !errors.Is(err, ErrPurgeWorkspaceRequired) {
+		t.Fatalf("expected ErrPurgeWorkspaceRequired, got %v", err)
+	}
+}
+
+func TestPurge_DirectDeleteStillRejected(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+	mustLogEvent(t, svc, wsID, "actor", "x", OutcomeSuccess, time.Now().UTC())
+
+	if _, err := db.Exec(`DELETE FROM audit_event WHERE workspace_id = ?`, wsID); err == nil {
+		t.Fatal("expected append-only trigger to reject direct delete")
+	}
+}
@@ -7,15 +7,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"reflect"
 	"strings"
 	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
 
+const auditComponent = "audit.service"
+
 // Event topic constants — Task 4.6: FR-070 audit trail subscribers.
 const (
 	topicAgentRunStarted   = "agent.run.started"
@@ -45,7 +50,8 @@ const (
 )
 
 // AuditService provides audit logging capabilities
-// All operations are append-only; no updates or deletes are supported
+// All operations are append-only; no updates are supported and deletes are
+// limited to the retention Purge
 //
 //nolint:revive // servicio de dominio estable y ampliamente referenciado
 type AuditService struct {
@@ -54,6 +60,9 @@ type AuditService struct {
 }
 
 // NewAuditService creates a new audit service
+// Events logged with a context carrying a request transaction (see
+// sqlite.ContextWithTx) are written once it ends, outside of it, so a
+// rolled-back request still leaves its audit trail.
 func NewAuditService(db *sql.DB) *AuditService {
 	return &AuditService{
 		db:      db,
@@ -63,7 +72,23 @@ func NewAuditService(db *sql.DB) *AuditService {
 
 // Log creates a new audit event (append-only, immutable)
 // This is the ONLY way to create audit events - no updates, no deletes
+// Task 4.7: appends the event to the workspace hash chain; calls for the same
+// workspace are serialized so the chain stays linear.
 func (s *AuditService) Log(ctx context.Context, event *AuditEvent) error {
+	if _, inTx := sqlite.TxFromContext(ctx); inTx {
+		sqlite.AfterTx(ctx, func(ctx context.Context) {
+			if err := s.log(ctx, event); err != nil {
+				logging.FromContext(ctx).Error("write deferred audit event failed",
+					slog.String(logging.KeyComponent, auditComponent),
+					slog.String("action", event.Action), logging.Err(err))
+			}
+		})
+		return nil
+	}
+	return s.log(ctx, event)
+}
+
+func (s *AuditService) log(ctx context.Context, event *AuditEvent) error {
 	details := normalizeJSON(event.Details, []byte("{}"))
 	permissionsChecked := normalizeJSON(event.PermissionsChecked, []byte("[]"))
 
@@ -84,9 +109,25 @@ func (s *AuditService) Log(ctx context.Context, event *AuditEvent) error {
 		CreatedAt:          event.CreatedAt,
 	}
 
+	unlock := lockWorkspaceChain(event.WorkspaceID)
+	defer unlock()
+
+	prevHash, err := s.latestChainHash(ctx, event.WorkspaceID)
+	if err != nil {
+		return err
+	}
+	hash, err := chainHash(prevHash, chainRecordFromParams(params))
+	if err != nil {
+		return err
+	}
+	params.PrevHash = prevHash
+	params.Hash = hash
+
 	if err := s.querier.CreateAuditEvent(ctx, params); err != nil {
 		return fmt.Errorf("create audit event: %w", err)
 	}
+	event.PrevHash = prevHash
+	event.Hash = hash
 	return nil
 }
 
@@ -138,13 +179,19 @@ func (s *AuditService) GetByID(ctx context.Context, id string) (*AuditEvent, err
 }
 
 // ListByWorkspace retrieves audit events for a workspace (with pagination)
-// Results are ordered by created_at DESC (newest first)
+// Results are ordered by created_at DESC (newest first).
+// An optional TimeWindow restricts results to events created inside it.
 func (s *AuditService) ListByWorkspace(
 	ctx context.Context,
 	workspaceID string,
 	limit int,
 	offset int,
+	window ...TimeWindow,
 ) ([]*AuditEvent, int, error) {
+	if len(window) > 0 {
+		return s.ListByWorkspaceInRange(ctx, workspaceID, window[0].From, window[0].To, limit, offset)
+	}
+
 	params := sqlcgen.ListAuditEventsByWorkspaceParams{
 		WorkspaceID: workspaceID,
 		Limit:       int64(limit),
@@ -164,17 +211,56 @@ func (s *AuditService) ListByWorkspace(
 	return mapAuditEvents(rows), int(count), nil
 }
 
+// ListByWorkspaceInRange retrieves audit events for a workspace created between
+// from and to (inclusive), newest first, together with the total matching count.
+// A zero from or to leaves that side of the range open.
+func (s *AuditService) ListByWorkspaceInRange(
+	ctx context.Context,
+	workspaceID string,
+	from, to time.Time,
+	limit int,
+	offset int,
+) ([]*AuditEvent, int, error) {
+	dateFrom, dateTo := windowBounds(TimeWindow{From: from, To: to})
+
+	rows, err := s.querier.ListAuditEventsByTimeRange(ctx, sqlcgen.ListAuditEventsByTimeRangeParams{
+		WorkspaceID: workspaceID,
+		DateFrom:    dateFrom,
+		DateTo:      dateTo,
+		Off:         int64(offset),
+		Lim:         int64(limit),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list audit events by time range: %w", err)
+	}
+
+	count, err := s.querier.CountAuditEventsByTimeRange(ctx, sqlcgen.CountAuditEventsByTimeRangeParams{
+		WorkspaceID: workspaceID,
+		DateFrom:    dateFrom,
+		DateTo:      dateTo,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("count audit events by time range: %w", err)
+	}
+
+	return mapAuditEvents(rows), int(count), nil
+}
+
 // ListByActor retrieves audit events for a specific actor
 func (s *AuditService) ListByActor(
 	ctx context.Context,
 	actorID string,
 	limit int,
+	window ...TimeWindow,
 ) ([]*AuditEvent, error) {
+	dateFrom, dateTo := windowBounds(window...)
 	return listAuditEvents(
 		func() ([]sqlcgen.AuditEvent, error) {
 			return s.querier.ListAuditEventsByActor(ctx, sqlcgen.ListAuditEventsByActorParams{
-				ActorID: actorID,
-				Limit:   int64(limit),
+				ActorID:  actorID,
+				DateFrom: dateFrom,
+				DateTo:   dateTo,
+				Limit:    int64(limit),
 			})
 		},
 	)
@@ -186,12 +272,16 @@ func (s *AuditService) ListByEntity(
 	entityType string,
 	entityID string,
 	limit int,
+	window ...TimeWindow,
 ) ([]*AuditEvent, error) {
+	dateFrom, dateTo := windowBounds(window...)
 	return listAuditEvents(
 		func() ([]sqlcgen.AuditEvent, error) {
 			return s.querier.ListAuditEventsByEntity(ctx, sqlcgen.ListAuditEventsByEntityParams{
 				EntityType: &entityType,
 				EntityID:   &entityID,
+				DateFrom:   dateFrom,
+				DateTo:     dateTo,
 				Limit:      int64(limit),
 			})
 		},
@@ -205,12 +295,16 @@ func (s *AuditService) ListByOutcome(
 	outcome Outcome,
 	limit int,
 	offset int,
+	window ...TimeWindow,
 ) ([]*AuditEvent, error) {
+	dateFrom, dateTo := windowBounds(window...)
 	return listAuditEvents(
 		func() ([]sqlcgen.AuditEvent, error) {
 			return s.querier.ListAuditEventsByOutcome(ctx, sqlcgen.ListAuditEventsByOutcomeParams{
 				WorkspaceID: workspaceID,
 				Outcome:     string(outcome),
+				DateFrom:    dateFrom,
+				DateTo:      dateTo,
 				Limit:       int64(limit),
 				Offset:      int64(offset),
 			})
@@ -225,12 +319,16 @@ func (s *AuditService) ListByAction(
 	action string,
 	limit int,
 	offset int,
+	window ...TimeWindow,
 ) ([]*AuditEvent, error) {
+	dateFrom, dateTo := windowBounds(window...)
 	return listAuditEvents(
 		func() ([]sqlcgen.AuditEvent, error) {
 			return s.querier.ListAuditEventsByAction(ctx, sqlcgen.ListAuditEventsByActionParams{
 				WorkspaceID: workspaceID,
 				Action:      action,
+				DateFrom:    dateFrom,
+				DateTo:      dateTo,
 				Limit:       int64(limit),
 				Offset:      int64(offset),
 			})
@@ -258,86 +356,144 @@ func (s *AuditService) Query(ctx context.Context, in QueryInput) ([]*AuditEvent,
 	)
 }
 
-// Export returns audit events as a streaming CSV reader.
+// exportBatchSize is the keyset page size used while streaming exports.
+const exportBatchSize = 500
+
+// Export streams audit events of workspaceID matching filter to w as CSV or NDJSON.
+// Rows are paged with a (created_at, id) keyset cursor, so the full result set
+// is never held in memory.
 // Task 4.6: FR-071 Audit Export
-func (s *AuditService) Export(ctx context.Context, in ExportInput) (io.Reader, error) {
-	pr, pw := io.Pipe()
-	go s.writeCSVExport(ctx, pw, in)
-	return pr, nil
+func (s *AuditService) Export(
+	ctx context.Context,
+	workspaceID string,
+	filter ExportFilter,
+	format ExportFormat,
+	w io.Writer,
+) error {
+	enc, err := newAuditExportEncoder(format, w)
+	if err != nil {
+		return err
+	}
+	if err = enc.writeHeader(); err != nil {
+		return err
+	}
+	if err = s.streamExportPages(ctx, workspaceID, filter, enc.writeEvent); err != nil {
+		return err
+	}
+	return enc.flush()
 }
 
-func (s *AuditService) writeCSVExport(ctx context.Context, pw *io.PipeWriter, in ExportInput) {
-	w := csv.NewWriter(pw)
-	if err := writeAuditCSVHeader(w); err != nil {
-		_ = pw.CloseWithError(err)
-		return
+func (s *AuditService) streamExportPages(
+	ctx context.Context,
+	workspaceID string,
+	filter ExportFilter,
+	emit func(*AuditEvent) error,
+) error {
+	cursorID, cursorCreatedAt := "", ""
+	for {
+		rows, err := s.querier.ListAuditEventsForExport(ctx, sqlcgen.ListAuditEventsForExportParams{
+			WorkspaceID:     workspaceID,
+			ActorID:         filter.ActorID,
+			EntityType:      filter.EntityType,
+			Action:          filter.Action,
+			Outcome:         filter.Outcome,
+			DateFrom:        normalizeDateArg(filter.DateFrom),
+			DateTo:          normalizeDateArg(filter.DateTo),
+			CursorID:        cursorID,
+			CursorCreatedAt: cursorCreatedAt,
+			Lim:             exportBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("list audit events for export: %w", err)
+		}
+		for i := range rows {
+			if emitErr := emit(exportRowToAuditEvent(rows[i])); emitErr != nil {
+				return emitErr
+			}
+		}
+		if len(rows) < exportBatchSize {
+			return nil
+		}
+		last := rows[len(rows)-1]
+		cursorID, cursorCreatedAt = last.ID, last.CursorCreatedAt
 	}
-	if err := s.writeAuditCSVRows(ctx, w, in); err != nil {
-		_ = pw.CloseWithError(err)
-		return
+}
+
+type auditExportEncoder interface {
+	writeHeader() error
+	writeEvent(*AuditEvent) error
+	flush() error
+}
+
+func newAuditExportEncoder(format ExportFormat, w io.Writer) (auditExportEncoder, error) {
+	switch format {
+	case ExportFormatCSV:
+		return &csvAuditEncoder{w: csv.NewWriter(w)}, nil
+	case ExportFormatNDJSON:
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return &ndjsonAuditEncoder{enc: enc}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedExportFormat, format)
 	}
-	w.Flush()
-	_ = pw.CloseWithError(w.Error())
 }
 
-func writeAuditCSVHeader(w *csv.Writer) error {
-	if err := w.Write([]string{
+type csvAuditEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvAuditEncoder) writeHeader() error {
+	if err := e.w.Write([]string{
 		"id", "workspace_id", "actor_id", "actor_type", "action",
-		"entity_type", "entity_id", "outcome", "trace_id", "created_at",
+		"entity_type", "entity_id", "outcome", "trace_id", "created_at", "details",
 	}); err != nil {
 		return fmt.Errorf("write audit CSV header: %w", err)
 	}
 	return nil
 }
 
-func (s *AuditService) writeAuditCSVRows(ctx context.Context, w *csv.Writer, in ExportInput) error {
-	offset := 0
-	const batchSize = 500
-	for {
-		events, queryErr := s.Query(ctx, QueryInput{
-			WorkspaceID: in.WorkspaceID,
-			ActorID:     in.ActorID,
-			EntityType:  in.EntityType,
-			Action:      in.Action,
-			Outcome:     in.Outcome,
-			DateFrom:    in.DateFrom,
-			DateTo:      in.DateTo,
-			Limit:       batchSize,
-			Offset:      offset,
-		})
-		if queryErr != nil {
-			return queryErr
-		}
-		if err := writeAuditCSVBatch(w, events); err != nil {
-			return err
-		}
-		if len(events) < batchSize {
-			return nil
-		}
-		offset += batchSize
-	}
-}
-
-func writeAuditCSVBatch(w *csv.Writer, events []*AuditEvent) error {
-	for _, ev := range events {
-		if err := w.Write([]string{
-			ev.ID,
-			ev.WorkspaceID,
-			ev.ActorID,
-			string(ev.ActorType),
-			ev.Action,
-			derefString(ev.EntityType),
-			derefString(ev.EntityID),
-			string(ev.Outcome),
-			derefString(ev.TraceID),
-			ev.CreatedAt.UTC().Format(time.RFC3339),
-		}); err != nil {
-			return fmt.Errorf("write audit CSV row: %w", err)
-		}
+func (e *csvAuditEncoder) writeEvent(ev *AuditEvent) error {
+	if err := e.w.Write([]string{
+		ev.ID,
+		ev.WorkspaceID,
+		ev.ActorID,
+		string(ev.ActorType),
+		ev.Action,
+		derefString(ev.EntityType),
+		derefString(ev.EntityID),
+		string(ev.Outcome),
+		derefString(ev.TraceID),
+		ev.CreatedAt.UTC().Format(time.RFC3339),
+		string(ev.Details),
+	}); err != nil {
+		return fmt.Errorf("write audit CSV row: %w", err)
+	}
+	return nil
+}
+
+func (e *csvAuditEncoder) flush() error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		return fmt.Errorf("flush audit CSV: %w", err)
+	}
+	return nil
+}
+
+type ndjsonAuditEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonAuditEncoder) writeHeader() error { return nil }
+
+func (e *ndjsonAuditEncoder) writeEvent(ev *AuditEvent) error {
+	if err := e.enc.Encode(ev); err != nil {
+		return fmt.Errorf("write audit NDJSON line: %w", err)
 	}
 	return nil
 }
 
+func (e *ndjsonAuditEncoder) flush() error { return nil }
+
 // RegisterEventSubscribers wires the audit service to all domain event topics.
 // Task 4.6: Completes FR-070 audit trail for agent/tool/policy/approval events.
 func (s *AuditService) RegisterEventSubscribers(bus eventbus.EventBus) {
@@ -382,6 +538,29 @@ func resolveQueryLimit(limit int) int {
 	return limit
 }
 
+// Open bounds used when a TimeWindow side is unset; they compare against the
+// first 19 characters of created_at ("YYYY-MM-DD HH:MM:SS").
+const (
+	windowOpenFrom = "0000-01-01 00:00:00"
+	windowOpenTo   = "9999-12-31 23:59:59"
+	windowLayout   = "2006-01-02 15:04:05"
+)
+
+// windowBounds converts an optional TimeWindow into inclusive SQL bounds.
+func windowBounds(window ...TimeWindow) (string, string) {
+	from, to := windowOpenFrom, windowOpenTo
+	if len(window) == 0 {
+		return from, to
+	}
+	if !window[0].From.IsZero() {
+		from = window[0].From.UTC().Format(windowLayout)
+	}
+	if !window[0].To.IsZero() {
+		to = window[0].To.UTC().Format(windowLayout)
+	}
+	return from, to
+}
+
 func normalizeDateArg(raw string) any {
 	if raw == "" {
 		return ""
@@ -579,9 +758,33 @@ func rowToAuditEvent(row sqlcgen.AuditEvent) *AuditEvent {
 		IPAddress:          row.IpAddress,
 		UserAgent:          row.UserAgent,
 		CreatedAt:          row.CreatedAt,
+		PrevHash:           row.PrevHash,
+		Hash:               row.Hash,
 	}
 }
 
+// exportRowToAuditEvent converts a keyset export row to domain AuditEvent
+func exportRowToAuditEvent(row sqlcgen.ListAuditEventsForExportRow) *AuditEvent {
+	return rowToAuditEvent(sqlcgen.AuditEvent{
+		ID:                 row.ID,
+		WorkspaceID:        row.WorkspaceID,
+		ActorID:            row.ActorID,
+		ActorType:          row.ActorType,
+		Action:             row.Action,
+		EntityType:         row.EntityType,
+		EntityID:           row.EntityID,
+		Details:            row.Details,
+		PermissionsChecked: row.PermissionsChecked,
+		Outcome:            row.Outcome,
+		TraceID:            row.TraceID,
+		IpAddress:          row.IpAddress,
+		UserAgent:          row.UserAgent,
+		CreatedAt:          row.CreatedAt,
+		PrevHash:           row.PrevHash,
+		Hash:               row.Hash,
+	})
+}
+
 // generateID generates a new UUID for audit events
 func generateID() string {
 	// Using UUID v7 for better time-based ordering
@@ -3,11 +3,13 @@ package audit
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"database/sql"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"reflect"
 	"strings"
@@ -911,6 +913,80 @@ func TestQuery_FilterByDateRange_ReturnsInRange(t *testing.T) {
 	}
 }
 
+func TestListByWorkspaceInRange_ReturnsWindowWithTotal(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+
+	q1Start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	q1End := time.Date(2026, 3, 31, 23, 59, 59, 0, time.UTC)
+	mustLogEvent(t, svc, wsID, uuid.NewV7().String(), "x", OutcomeSuccess, q1Start.Add(-time.Hour))
+	mustLogEvent(t, svc, wsID, uuid.NewV7().String(), "x", OutcomeSuccess, q1Start.Add(24*time.Hour))
+	mustLogEvent(t, svc, wsID, uuid.NewV7().String(), "x", OutcomeSuccess, q1Start.Add(48*time.Hour))
+	mustLogEvent(t, svc, wsID, uuid.NewV7().String(), "x", OutcomeSuccess, q1End.Add(-time.Hour))
+	mustLogEvent(t, svc, wsID, uuid.NewV7().String(), "x", OutcomeSuccess, q1End.Add(time.Hour))
+
+	items, total, err := svc.ListByWorkspaceInRange(ctx, wsID, q1Start, q1End, 1, 0)
+	if err != nil {
+		t.Fatalf("ListByWorkspaceInRange failed: %v", err)
+	}
+	if total != 3 {
+		t.Fatalf("expected total 3, got %d", total)
+	}
+	if len(items) != 1 || !items[0].CreatedAt.Equal(q1End.Add(-time.Hour)) {
+		t.Fatalf("expected newest in-range event first, got %+v", items)
+	}
+
+	viaWindow, windowTotal, err := svc.ListByWorkspace(ctx, wsID, 10, 0, TimeWindow{From: q1Start})
+	if err != nil {
+		t.Fatalf("ListByWorkspace with window failed: %v", err)
+	}
+	if windowTotal != 4 || len(viaWindow) != 4 {
+		t.Fatalf("expected 4 events from open-ended window, got total=%d len=%d", windowTotal, len(viaWindow))
+	}
+}
+
+func TestListByAction_WithTimeWindow_FiltersByCreatedAt(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+	actorID := uuid.NewV7().String()
+
+	cutoff := time.Now().UTC().Add(-time.Hour)
+	mustLogEvent(t, svc, wsID, actorID, "tool.executed", OutcomeSuccess, cutoff.Add(-time.Hour))
+	mustLogEvent(t, svc, wsID, actorID, "tool.executed", OutcomeSuccess, cutoff.Add(time.Minute))
+
+	all, err := svc.ListByAction(ctx, wsID, "tool.executed", 10, 0)
+	if err != nil {
+		t.Fatalf("ListByAction failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 events without window, got %d", len(all))
+	}
+
+	recent, err := svc.ListByAction(ctx, wsID, "tool.executed", 10, 0, TimeWindow{From: cutoff})
+	if err != nil {
+		t.Fatalf("ListByAction with window failed: %v", err)
+	}
+	if len(recent) != 1 {
+		t.Fatalf("expected 1 event after cutoff, got %d", len(recent))
+	}
+
+	byActor, err := svc.ListByActor(ctx, actorID, 10, TimeWindow{To: cutoff})
+	if err != nil {
+		t.Fatalf("ListByActor with window failed: %v", err)
+	}
+	if len(byActor) != 1 {
+		t.Fatalf("expected 1 event before cutoff, got %d", len(byActor))
+	}
+}
+
 func TestQuery_FilterByActorID_ReturnsOnlyMatching(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -960,6 +1036,74 @@ func TestQuery_MultipleFilters_CombinesCorrectly(t *testing.T) {
 	}
 }
 
+func TestListByFilter_CombinesActorTypeOutcomeAndRange(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 28, 23, 59, 59, 0, time.UTC)
+	logEvent := func(actorType ActorType, outcome Outcome, createdAt time.Time) {
+		t.Helper()
+		if err := svc.Log(ctx, &AuditEvent{
+			ID:          uuid.NewV7().String(),
+			WorkspaceID: wsID,
+			ActorID:     uuid.NewV7().String(),
+			ActorType:   actorType,
+			Action:      "tool.denied",
+			EntityType:  strPtr("case"),
+			Outcome:     outcome,
+			CreatedAt:   createdAt,
+		}); err != nil {
+			t.Fatalf("log event failed: %v", err)
+		}
+	}
+	logEvent(ActorTypeAgent, OutcomeDenied, from.Add(time.Hour))
+	logEvent(ActorTypeAgent, OutcomeDenied, from.Add(48*time.Hour))
+	logEvent(ActorTypeAgent, OutcomeDenied, to.Add(time.Hour))
+	logEvent(ActorTypeAgent, OutcomeSuccess, from.Add(2*time.Hour))
+	logEvent(ActorTypeUser, OutcomeDenied, from.Add(3*time.Hour))
+
+	filter := AuditFilter{ActorType: ActorTypeAgent, Outcome: OutcomeDenied, EntityType: "case", From: from, To: to}
+	items, total, err := svc.ListByFilter(ctx, wsID, filter, 1, 0)
+	if err != nil {
+		t.Fatalf("ListByFilter failed: %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("expected total 2, got %d", total)
+	}
+	if len(items) != 1 || !items[0].CreatedAt.Equal(from.Add(48*time.Hour)) {
+		t.Fatalf("expected newest matching event first, got %+v", items)
+	}
+
+	next, _, err := svc.ListByFilter(ctx, wsID, filter, 1, 1)
+	if err != nil {
+		t.Fatalf("ListByFilter page 2 failed: %v", err)
+	}
+	if len(next) != 1 || !next[0].CreatedAt.Equal(from.Add(time.Hour)) {
+		t.Fatalf("unexpected second page: %+v", next)
+	}
+
+	_, all, err := svc.ListByFilter(ctx, wsID, AuditFilter{Action: "tool.denied"}, 10, 0)
+	if err != nil {
+		t.Fatalf("ListByFilter by action failed: %v", err)
+	}
+	if all != 5 {
+		t.Fatalf("expected 5 events for action filter, got %d", all)
+	}
+
+	_, other, err := svc.ListByFilter(ctx, uuid.NewV7().String(), AuditFilter{}, 10, 0)
+	if err != nil {
+		t.Fatalf("ListByFilter other workspace failed: %v", err)
+	}
+	if other != 0 {
+		t.Fatalf("expected no events for another workspace, got %d", other)
+	}
+}
+
 func TestExportCSV_Returns1000Rows(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -972,15 +1116,11 @@ func TestExportCSV_Returns1000Rows(t *testing.T) {
 		mustLogEvent(t, svc, wsID, uuid.NewV7().String(), "bulk", OutcomeSuccess, time.Now().Add(time.Duration(i)*time.Millisecond))
 	}
 
-	r, err := svc.Export(ctx, ExportInput{WorkspaceID: wsID})
-	if err != nil {
+	var buf bytes.Buffer
+	if err := svc.Export(ctx, wsID, ExportFilter{}, ExportFormatCSV, &buf); err != nil {
 		t.Fatalf("Export failed: %v", err)
 	}
-	b, err := io.ReadAll(r)
-	if err != nil {
-		t.Fatalf("read export failed: %v", err)
-	}
-	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
 	if len(lines) != 1001 { // header + 1000 rows
 		t.Fatalf("expected 1001 csv lines, got %d", len(lines))
 	}
@@ -995,11 +1135,11 @@ func TestExportCSV_ContainsHeaderRow(t *testing.T) {
 	createWorkspaceForTest(t, db, wsID)
 	mustLogEvent(t, svc, wsID, uuid.NewV7().String(), "x", OutcomeSuccess, time.Now())
 
-	r, err := svc.Export(ctx, ExportInput{WorkspaceID: wsID})
-	if err != nil {
+	var buf bytes.Buffer
+	if err := svc.Export(ctx, wsID, ExportFilter{}, ExportFormatCSV, &buf); err != nil {
 		t.Fatalf("Export failed: %v", err)
 	}
-	br := bufio.NewReader(r)
+	br := bufio.NewReader(&buf)
 	header, err := br.ReadString('\n')
 	if err != nil {
 		t.Fatalf("read header failed: %v", err)
@@ -1007,6 +1147,97 @@ func TestExportCSV_ContainsHeaderRow(t *testing.T) {
 	if !strings.Contains(header, "id,workspace_id,actor_id,actor_type,action") {
 		t.Fatalf("unexpected csv header: %s", header)
 	}
+	if !strings.HasSuffix(strings.TrimSpace(header), ",details") {
+		t.Fatalf("expected details column in csv header: %s", header)
+	}
+}
+
+func TestExportCSV_SameTimestampAcrossBatches_NoRowsLost(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+
+	at := time.Now().UTC()
+	total := exportBatchSize + 25
+	for i := 0; i < total; i++ {
+		mustLogEvent(t, svc, wsID, uuid.NewV7().String(), "bulk", OutcomeSuccess, at)
+	}
+
+	var buf bytes.Buffer
+	if err := svc.Export(ctx, wsID, ExportFilter{}, ExportFormatCSV, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("parse csv failed: %v", err)
+	}
+	seen := make(map[string]struct{}, total)
+	for _, rec := range records[1:] {
+		seen[rec[0]] = struct{}{}
+	}
+	if len(records) != total+1 || len(seen) != total {
+		t.Fatalf("expected %d unique rows, got %d rows (%d unique)", total, len(records)-1, len(seen))
+	}
+}
+
+func TestExportNDJSON_RoundTripsDetailsAndIsolatesWorkspace(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+	otherWS := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+	createWorkspaceForTest(t, db, otherWS)
+
+	details := &EventDetails{
+		OldValue: map[string]any{"name": "Acme <old>"},
+		NewValue: map[string]any{"name": "Acme & Co", "tags": []any{"a", "b"}},
+		Metadata: map[string]any{"source": "import"},
+	}
+	if err := svc.LogWithDetails(ctx, wsID, "actor-1", ActorTypeUser, "update_account", nil, nil, details, OutcomeSuccess); err != nil {
+		t.Fatalf("LogWithDetails failed: %v", err)
+	}
+	mustLogEvent(t, svc, otherWS, "actor-2", "update_account", OutcomeSuccess, time.Now())
+
+	var buf bytes.Buffer
+	if err := svc.Export(ctx, wsID, ExportFilter{}, ExportFormatNDJSON, &buf); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 ndjson line for workspace, got %d", len(lines))
+	}
+
+	var got AuditEvent
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("decode ndjson line failed: %v", err)
+	}
+	if got.WorkspaceID != wsID {
+		t.Fatalf("expected workspace %s, got %s", wsID, got.WorkspaceID)
+	}
+	var gotDetails EventDetails
+	if err := json.Unmarshal(got.Details, &gotDetails); err != nil {
+		t.Fatalf("decode details failed: %v", err)
+	}
+	if !reflect.DeepEqual(&gotDetails, details) {
+		t.Fatalf("details not preserved: got %+v want %+v", gotDetails, details)
+	}
+}
+
+func TestExport_UnsupportedFormat(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+
+	var buf bytes.Buffer
+	err := svc.Export(context.Background(), "ws", ExportFilter{}, ExportFormat("xml"), &buf)
+	if !errors.Is(err, ErrUnsupportedExportFormat) {
+		t.Fatalf("expected ErrUnsupportedExportFormat, got %v", err)
+	}
 }
 
 func mustLogEvent(t *testing.T, svc *AuditService, wsID, actorID, action string, outcome Outcome, createdAt time.Time) {
@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+func TestStats_AggregatesWindowPerWorkspace(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+	otherWS := uuid.NewV7().String()
+	createWorkspaceForTest(t, db, wsID)
+	createWorkspaceForTest(t, db, otherWS)
+
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 31, 23, 59, 59, 0, time.UTC)
+	logEvent := func(ws, actorID string, actorType ActorType, action string, outcome Outcome, createdAt time.Time) {
+		t.Helper()
+		if err := svc.Log(ctx, &AuditEvent{
+			ID:          uuid.NewV7().String(),
+			WorkspaceID: ws,
+			ActorID:     actorID,
+			ActorType:   actorType,
+			Action:      action,
+			Outcome:     outcome,
+			CreatedAt:   createdAt,
+		}); err != nil {
+			t.Fatalf("log event failed: %v", err)
+		}
+	}
+	in := from.Add(24 * time.Hour)
+	logEvent(wsID, "agent-1", ActorTypeAgent, "tool.executed", OutcomeSuccess, in)
+	logEvent(wsID, "agent-1", ActorTypeAgent, "tool.executed", OutcomeSuccess, in.Add(time.Hour))
+	logEvent(wsID, "agent-1", ActorTypeAgent, "tool.denied", OutcomeDenied, in.Add(2*time.Hour))
+	logEvent(wsID, "user-1", ActorTypeUser, "login", OutcomeSuccess, in.Add(3*time.Hour))
+	logEvent(wsID, "user-1", ActorTypeUser, "tool.executed", OutcomeError, to.Add(time.Hour))
+	logEvent(otherWS, "agent-2", ActorTypeAgent, "tool.executed", OutcomeSuccess, in)
+
+	stats, err := svc.Stats(ctx, wsID, from, to)
+	if err != nil {
+		t.Fatalf("Stats failed: %v", err)
+	}
+	if stats.Total != 4 {
+		t.Fatalf("expected total 4, got %d", stats.Total)
+	}
+	assertCounts(t, "by_action", stats.ByAction, []StatCount{{"tool.executed", 2}, {"login", 1}, {"tool.denied", 1}})
+	assertCounts(t, "by_outcome", stats.ByOutcome, []StatCount{{"success", 3}, {"denied", 1}})
+	assertCounts(t, "by_actor_type", stats.ByActorType, []StatCount{{"agent", 3}, {"user", 1}})
+	want := []ActorStat{{"agent-1", ActorTypeAgent, 3}, {"user-1", ActorTypeUser, 1}}
+	if len(stats.TopActors) != len(want) {
+		t.Fatalf("expected %d top actors, got %+v", len(want), stats.TopActors)
+	}
+	for i := range want {
+		if stats.TopActors[i] != want[i] {
+			t.Fatalf("top_actors[%d] = %+v; want %+v", i, stats.TopActors[i], want[i])
+		}
+	}
+
+	empty, err := svc.Stats(ctx, uuid.NewV7().String(), from, to)
+	if err != nil {
+		t.Fatalf("Stats unknown workspace failed: %v", err)
+	}
+	if empty.Total != 0 || len(empty.ByAction) != 0 || len(empty.TopActors) != 0 {
+		t.Fatalf("expected empty stats for unknown workspace, got %+v", empty)
+	}
+}
+
+func TestStats_ValidatesWindow(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	svc := NewAuditService(db)
+	ctx := context.Background()
+	wsID := uuid.NewV7().String()
+
+	now := time.Now().UTC()
+	if _, err := svc.Stats(ctx, wsID, now, now.Add(-time.Hour)); !errors.Is(err, ErrInvalidStatsWindow) {
+		t.Fatalf("expected ErrInvalidStatsWindow for reversed window, got %v", err)
+	}
+	if _, err := svc.Stats(ctx, wsID, now.Add(-MaxStatsWindow-time.Hour), now); !errors.Is(err, ErrInvalidStatsWindow) {
+		t.Fatalf("expected ErrInvalidStatsWindow for oversized window, got %v", err)
+	}
+	if _, err := svc.Stats(ctx, "", time.Time{}, time.Time{}); !errors.Is(err, ErrStatsWorkspaceRequired) {
+		t.Fatalf("expected ErrStatsWorkspaceRequired, got %v", err)
+	}
+
+	stats, err := svc.Stats(ctx, wsID, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Stats default window failed: %v", err)
+	}
+	if got := stats.To.Sub(stats.From); got != DefaultStatsWindow {
+		t.Fatalf("expected default window %v, got %v", DefaultStatsWindow, got)
+	}
+}
+
+func assertCounts(t *testing.T, name string, got, want []StatCount) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s = %+v; want %+v", name, got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%s[%d] = %+v; want %+v", name, i, got[i], want[i])
+		}
+	}
+}
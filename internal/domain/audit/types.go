@@ -2,6 +2,7 @@ package audit
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 )
 
@@ -42,6 +43,8 @@ type AuditEvent struct {
 	IPAddress          *string         `json:"ip_address,omitempty"`
 	UserAgent          *string         `json:"user_agent,omitempty"`
 	CreatedAt          time.Time       `json:"created_at"`
+	PrevHash           string          `json:"prev_hash,omitempty"`
+	Hash               string          `json:"hash,omitempty"`
 }
 
 // EventDetails captures the specifics of an audited action
@@ -66,6 +69,13 @@ type PermissionCheck struct {
 	Reason     string `json:"reason,omitempty"`
 }
 
+// TimeWindow bounds a listing to events created between From and To (inclusive).
+// A zero From or To leaves that side of the window open.
+type TimeWindow struct {
+	From time.Time
+	To   time.Time
+}
+
 // Task 4.6: QueryInput defines optional compound filters for audit queries.
 type QueryInput struct {
 	WorkspaceID string
@@ -79,13 +89,59 @@ type QueryInput struct {
 	Offset      int
 }
 
-// Task 4.6: ExportInput defines filters for audit CSV export.
-type ExportInput struct {
-	WorkspaceID string
-	ActorID     string
-	EntityType  string
-	Action      string
-	Outcome     string
-	DateFrom    string
-	DateTo      string
+// AuditFilter combines optional criteria for AuditService.ListByFilter.
+// Empty fields do not filter; set fields are ANDed together. A zero From or
+// To leaves that side of the time range open.
+type AuditFilter struct {
+	ActorType  ActorType
+	Action     string
+	Outcome    Outcome
+	EntityType string
+	From       time.Time
+	To         time.Time
+}
+
+// Task 4.6: ExportFilter defines optional filters for audit export.
+// The workspace is passed separately to Export and is always enforced.
+type ExportFilter struct {
+	ActorID    string
+	EntityType string
+	Action     string
+	Outcome    string
+	DateFrom   string
+	DateTo     string
+}
+
+// ExportFormat selects the serialization used by AuditService.Export.
+type ExportFormat string
+
+const (
+	ExportFormatCSV    ExportFormat = "csv"
+	ExportFormatNDJSON ExportFormat = "ndjson"
+)
+
+// ErrUnsupportedExportFormat is returned by Export for unknown formats.
+var ErrUnsupportedExportFormat = errors.New("unsupported audit export format")
+
+// ErrPurgeWorkspaceRequired guards Purge against running without a workspace scope.
+var ErrPurgeWorkspaceRequired = errors.New("audit purge requires a workspace id")
+
+// ChainBreakReason explains why a link of the audit hash chain failed.
+type ChainBreakReason string
+
+const (
+	// ChainBreakPrevHashMismatch means a preceding event was deleted or reordered.
+	ChainBreakPrevHashMismatch ChainBreakReason = "prev_hash_mismatch"
+	// ChainBreakHashMismatch means the event content was modified after logging.
+	ChainBreakHashMismatch ChainBreakReason = "hash_mismatch"
+)
+
+// ChainBreak reports the first broken link found by AuditService.VerifyChain.
+// Task 4.7: tamper-evident audit trail.
+type ChainBreak struct {
+	EventID          string
+	Position         int // 1-based position within the workspace chain
+	Reason           ChainBreakReason
+	ExpectedPrevHash string
+	ActualPrevHash   string
 }
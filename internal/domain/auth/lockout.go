@@ -0,0 +1,244 @@
+// Failed login tracking and temporary lockout.
+// Attempts are counted per email (existing or not) from each client IP, per
+// client IP, and per email from any IP, so the response never depends on
+// whether the account exists. Guesses from one address lock the account for
+// that address only; the account-wide counter has a higher threshold and
+// stops guessing spread across many addresses.
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
+	pkgauth "github.com/matiasleandrokruk/fenix/pkg/auth"
+)
+
+// ErrLoginLocked is returned by Login while an email (from the client's IP or
+// from everywhere) or the IP itself is locked out after too many failed
+// attempts.
+var ErrLoginLocked = errors.New("too many failed login attempts")
+
+const actionLoginLockout = "login_lockout"
+
+const (
+	lockoutScopeEmail   = "email"
+	lockoutScopeEmailIP = "email_ip"
+	lockoutScopeIP      = "ip"
+)
+
+// LockoutPolicy configures login lockout. MaxFailures failed attempts within
+// Window lock the email (for that IP) or the IP for Cooldown;
+// EmailMaxFailures failed attempts from any IP lock the email everywhere.
+// EmailMaxFailures <= 0 uses emailLockoutFactor times MaxFailures.
+// MaxFailures <= 0 disables lockout.
+type LockoutPolicy struct {
+	MaxFailures      int
+	EmailMaxFailures int
+	Window           time.Duration
+	Cooldown         time.Duration
+}
+
+// emailLockoutFactor sets the default account-wide threshold relative to
+// MaxFailures, so one attacker cannot lock a user out from every address
+// as quickly as from their own.
+const emailLockoutFactor = 4
+
+// DefaultLockoutPolicy locks after 5 failures in 15 minutes (20 for the email
+// from any IP) for 15 minutes.
+func DefaultLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{MaxFailures: 5, EmailMaxFailures: 20, Window: 15 * time.Minute, Cooldown: 15 * time.Minute}
+}
+
+// WithLoginLockout enables login lockout with the given policy.
+func WithLoginLockout(policy LockoutPolicy) Option {
+	return func(s *authService) { s.lockout = policy }
+}
+
+func (p LockoutPolicy) enabled() bool {
+	return p.MaxFailures > 0
+}
+
+func (p LockoutPolicy) emailMaxFailures() int {
+	if p.EmailMaxFailures > 0 {
+		return p.EmailMaxFailures
+	}
+	return p.MaxFailures * emailLockoutFactor
+}
+
+// lockoutSubject is one key failed attempts are counted under, with the
+// number of failures that locks it.
+type lockoutSubject struct {
+	scope       string
+	subject     string
+	maxFailures int
+}
+
+// loginLockoutSubjects keys failures by email and client IP, by client IP,
+// and by email alone with the higher account-wide threshold. Without an IP
+// (callers outside HTTP) the email alone is the key, at MaxFailures.
+func (p LockoutPolicy) loginLockoutSubjects(input LoginInput) []lockoutSubject {
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if input.IP == "" {
+		return []lockoutSubject{{scope: lockoutScopeEmail, subject: email, maxFailures: p.MaxFailures}}
+	}
+	return []lockoutSubject{
+		{scope: lockoutScopeEmailIP, subject: email + "|" + input.IP, maxFailures: p.MaxFailures},
+		{scope: lockoutScopeIP, subject: input.IP, maxFailures: p.MaxFailures},
+		{scope: lockoutScopeEmail, subject: email, maxFailures: p.emailMaxFailures()},
+	}
+}
+
+// checkLockout returns ErrLoginLocked if any subject is currently locked.
+func (s *authService) checkLockout(ctx context.Context, subjects []lockoutSubject) error {
+	if !s.lockout.enabled() {
+		return nil
+	}
+	now := time.Now().UTC()
+	for _, sub := range subjects {
+		var lockedUntil sql.NullTime
+		err := s.db.QueryRowContext(ctx,
+			`SELECT locked_until FROM login_attempt WHERE scope = ? AND subject = ?`,
+			sub.scope, sub.subject,
+		).Scan(&lockedUntil)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check login lockout: %w", err)
+		}
+		if lockedUntil.Valid && now.Before(lockedUntil.Time) {
+			return ErrLoginLocked
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt for every subject, locking and
+// auditing those that reach the threshold.
+func (s *authService) recordLoginFailure(ctx context.Context, subjects []lockoutSubject, workspaceID, userID string) error {
+	if !s.lockout.enabled() {
+		return nil
+	}
+	for _, sub := range subjects {
+		locked, err := s.recordSubjectFailure(ctx, sub)
+		if err != nil {
+			return err
+		}
+		if locked {
+			s.logAuthDenied(ctx, workspaceID, userID, actionLoginLockout, map[string]any{
+				"reason":  "too_many_failed_attempts",
+				"scope":   sub.scope,
+				"subject": sub.subject,
+			})
+		}
+	}
+	return nil
+}
+
+func (s *authService) recordSubjectFailure(ctx context.Context, sub lockoutSubject) (bool, error) {
+	now := time.Now().UTC()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin login attempt transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	failures := 0
+	windowStart := now
+	var storedStart time.Time
+	err = tx.QueryRowContext(ctx,
+		`SELECT failures, window_started_at FROM login_attempt WHERE scope = ? AND subject = ?`,
+		sub.scope, sub.subject,
+	).Scan(&failures, &storedStart)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return false, fmt.Errorf("failed to read login attempts: %w", err)
+	case now.Sub(storedStart) < s.lockout.Window:
+		windowStart = storedStart
+	default:
+		failures = 0 // window elapsed: start counting again
+	}
+	failures++
+
+	var lockedUntil any
+	locked := failures >= sub.maxFailures
+	if locked {
+		lockedUntil = now.Add(s.lockout.Cooldown)
+		failures = 0
+		windowStart = now
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO login_attempt (scope, subject, failures, window_started_at, locked_until, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(scope, subject) DO UPDATE SET
+			failures = excluded.failures,
+			window_started_at = excluded.window_started_at,
+			locked_until = COALESCE(excluded.locked_until, login_attempt.locked_until),
+			updated_at = excluded.updated_at
+	`, sub.scope, sub.subject, failures, windowStart, lockedUntil, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to record login attempt: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit login attempt: %w", err)
+	}
+	return locked, nil
+}
+
+// resetLoginFailures clears the email counter after a successful login. The IP
+// counter is left to expire with its window so that logging into an account
+// the client owns cannot reset guesses made against other accounts.
+func (s *authService) resetLoginFailures(ctx context.Context, subjects []lockoutSubject) {
+	if !s.lockout.enabled() {
+		return
+	}
+	for _, sub := range subjects {
+		if sub.scope == lockoutScopeIP {
+			continue
+		}
+		// Best effort: a stale counter must not fail a valid login.
+		_, _ = s.db.ExecContext(ctx,
+			`DELETE FROM login_attempt WHERE scope = ? AND subject = ?`, sub.scope, sub.subject,
+		)
+	}
+}
+
+var (
+	dummyHashOnce sync.Once
+	dummyHash     string
+)
+
+// burnPasswordCheck runs a bcrypt comparison against a fixed hash so that
+// logins for unknown emails take as long as those with a wrong password.
+func burnPasswordCheck(password string) {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = pkgauth.HashPassword("fenix-dummy-password")
+	})
+	_ = pkgauth.VerifyPassword(dummyHash, password)
+}
+
+func (s *authService) logAuthDenied(ctx context.Context, workspaceID, userID, action string, metadata map[string]any) {
+	if s.auditLogger == nil {
+		return
+	}
+	_ = s.auditLogger.LogWithDetails(
+		ctx,
+		workspaceID,
+		userID,
+		domainaudit.ActorTypeUser,
+		action,
+		nil,
+		nil,
+		&domainaudit.EventDetails{Metadata: metadata},
+		domainaudit.OutcomeDenied,
+	)
+}
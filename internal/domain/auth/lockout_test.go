@@ -0,0 +1,185 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/audit"
+	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
+)
+
+var testLockout = domainauth.LockoutPolicy{MaxFailures: 3, Window: time.Minute, Cooldown: time.Minute}
+
+func registerLockoutUser(t *testing.T, svc domainauth.AuthService, email string) *domainauth.AuthResult {
+	t.Helper()
+	result, err := svc.Register(context.Background(), domainauth.RegisterInput{
+		Email: email, Password: "SecurePass123!", DisplayName: "Lock", WorkspaceName: "Acme Corp",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return result
+}
+
+func failLogins(t *testing.T, svc domainauth.AuthService, input domainauth.LoginInput, n int) []error {
+	t.Helper()
+	errs := make([]error, 0, n)
+	for range n {
+		_, err := svc.Login(context.Background(), input)
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func TestAuthService_Login_LocksEmailAfterMaxFailures(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthServiceWithAudit(db, audit.NewAuditService(db), domainauth.WithLoginLockout(testLockout))
+	reg := registerLockoutUser(t, svc, "lock@acme.com")
+
+	errs := failLogins(t, svc, domainauth.LoginInput{Email: "lock@acme.com", Password: "WrongPassword!"}, 4)
+	for i, err := range errs[:3] {
+		if !errors.Is(err, domainauth.ErrInvalidCredentials) {
+			t.Fatalf("attempt %d error = %v; want ErrInvalidCredentials", i+1, err)
+		}
+	}
+	if !errors.Is(errs[3], domainauth.ErrLoginLocked) {
+		t.Fatalf("attempt 4 error = %v; want ErrLoginLocked", errs[3])
+	}
+
+	// The correct password is refused while locked.
+	if _, err := svc.Login(context.Background(), domainauth.LoginInput{Email: "LOCK@acme.com", Password: "SecurePass123!"}); !errors.Is(err, domainauth.ErrLoginLocked) {
+		t.Fatalf("Login() while locked error = %v; want ErrLoginLocked", err)
+	}
+
+	var denied int
+	if err := db.QueryRow(
+		`SELECT COUNT(*) FROM audit_event WHERE workspace_id = ? AND action = 'login_lockout' AND outcome = ?`,
+		reg.WorkspaceID, string(audit.OutcomeDenied),
+	).Scan(&denied); err != nil {
+		t.Fatalf("count audit events: %v", err)
+	}
+	if denied != 1 {
+		t.Fatalf("login_lockout denied events = %d; want 1", denied)
+	}
+}
+
+func TestAuthService_Login_UnknownEmailLocksLikeKnownEmail(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db, domainauth.WithLoginLockout(testLockout))
+	registerLockoutUser(t, svc, "known@acme.com")
+
+	known := failLogins(t, svc, domainauth.LoginInput{Email: "known@acme.com", Password: "WrongPassword!"}, 5)
+	unknown := failLogins(t, svc, domainauth.LoginInput{Email: "ghost@acme.com", Password: "WrongPassword!"}, 5)
+
+	for i := range known {
+		if !errors.Is(unknown[i], known[i]) {
+			t.Fatalf("attempt %d: known email error %v, unknown email error %v; want identical", i+1, known[i], unknown[i])
+		}
+	}
+}
+
+func TestAuthService_Login_SuccessResetsFailures(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db, domainauth.WithLoginLockout(testLockout))
+	registerLockoutUser(t, svc, "reset@acme.com")
+
+	wrong := domainauth.LoginInput{Email: "reset@acme.com", Password: "WrongPassword!"}
+	failLogins(t, svc, wrong, 2)
+	if _, err := svc.Login(context.Background(), domainauth.LoginInput{Email: "reset@acme.com", Password: "SecurePass123!"}); err != nil {
+		t.Fatalf("Login() error = %v; want nil", err)
+	}
+	for i, err := range failLogins(t, svc, wrong, 2) {
+		if !errors.Is(err, domainauth.ErrInvalidCredentials) {
+			t.Fatalf("post-reset attempt %d error = %v; want ErrInvalidCredentials", i+1, err)
+		}
+	}
+}
+
+func TestAuthService_Login_LockExpiresAfterCooldown(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	policy := domainauth.LockoutPolicy{MaxFailures: 2, Window: time.Minute, Cooldown: 200 * time.Millisecond}
+	svc := domainauth.NewAuthService(db, domainauth.WithLoginLockout(policy))
+	registerLockoutUser(t, svc, "cool@acme.com")
+
+	failLogins(t, svc, domainauth.LoginInput{Email: "cool@acme.com", Password: "WrongPassword!"}, 2)
+	good := domainauth.LoginInput{Email: "cool@acme.com", Password: "SecurePass123!"}
+	if _, err := svc.Login(context.Background(), good); !errors.Is(err, domainauth.ErrLoginLocked) {
+		t.Fatalf("Login() during cooldown error = %v; want ErrLoginLocked", err)
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if _, err := svc.Login(context.Background(), good); err != nil {
+		t.Fatalf("Login() after cooldown error = %v; want nil", err)
+	}
+}
+
+func TestAuthService_Login_LocksIPAcrossEmails(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db, domainauth.WithLoginLockout(testLockout))
+	registerLockoutUser(t, svc, "victim@acme.com")
+
+	for _, email := range []string{"a@acme.com", "b@acme.com", "c@acme.com"} {
+		_, _ = svc.Login(context.Background(), domainauth.LoginInput{Email: email, Password: "WrongPassword!", IP: "203.0.113.7"})
+	}
+
+	_, err := svc.Login(context.Background(), domainauth.LoginInput{Email: "victim@acme.com", Password: "SecurePass123!", IP: "203.0.113.7"})
+	if !errors.Is(err, domainauth.ErrLoginLocked) {
+		t.Fatalf("Login() from locked IP error = %v; want ErrLoginLocked", err)
+	}
+	if _, err := svc.Login(context.Background(), domainauth.LoginInput{Email: "victim@acme.com", Password: "SecurePass123!", IP: "198.51.100.1"}); err != nil {
+		t.Fatalf("Login() from other IP error = %v; want nil", err)
+	}
+}
+
+func TestAuthService_Login_EmailLockIsPerIP(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db, domainauth.WithLoginLockout(testLockout))
+	registerLockoutUser(t, svc, "target@acme.com")
+
+	attacker := domainauth.LoginInput{Email: "target@acme.com", Password: "WrongPassword!", IP: "203.0.113.9"}
+	failLogins(t, svc, attacker, 3)
+	if _, err := svc.Login(context.Background(), attacker); !errors.Is(err, domainauth.ErrLoginLocked) {
+		t.Fatalf("Login() from attacking IP error = %v; want ErrLoginLocked", err)
+	}
+
+	// The owner, on another address, is not locked out by someone else's guesses.
+	if _, err := svc.Login(context.Background(), domainauth.LoginInput{Email: "target@acme.com", Password: "SecurePass123!", IP: "198.51.100.2"}); err != nil {
+		t.Fatalf("Login() from owner IP error = %v; want nil", err)
+	}
+}
+
+func TestAuthService_Login_EmailLocksEverywhereWhenGuessesRotateIP(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	policy := testLockout
+	policy.EmailMaxFailures = 5
+	svc := domainauth.NewAuthService(db, domainauth.WithLoginLockout(policy))
+	registerLockoutUser(t, svc, "target@acme.com")
+
+	// Each guess comes from a new address, so no per-IP counter ever trips.
+	for i := range 5 {
+		attacker := domainauth.LoginInput{Email: "target@acme.com", Password: "WrongPassword!", IP: fmt.Sprintf("203.0.113.%d", i+1)}
+		if _, err := svc.Login(context.Background(), attacker); !errors.Is(err, domainauth.ErrInvalidCredentials) {
+			t.Fatalf("guess %d error = %v; want ErrInvalidCredentials", i+1, err)
+		}
+	}
+	if _, err := svc.Login(context.Background(), domainauth.LoginInput{Email: "target@acme.com", Password: "SecurePass123!", IP: "198.51.100.2"}); !errors.Is(err, domainauth.ErrLoginLocked) {
+		t.Fatalf("Login() after spread guesses error = %v; want ErrLoginLocked", err)
+	}
+}
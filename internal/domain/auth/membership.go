@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+)
+
+// IsWorkspaceMember reports whether userID is an active user of workspaceID
+// and the workspace is not soft-deleted.
+// Auth middleware uses it to reject tokens whose workspace claim does not
+// match the user's own workspace.
+func (s *authService) IsWorkspaceMember(ctx context.Context, userID, workspaceID string) (bool, error) {
+	var exists int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS(
+			SELECT 1 FROM user_account u
+			JOIN workspace w ON w.id = u.workspace_id
+			WHERE u.id = ? AND u.workspace_id = ? AND u.status = 'active' AND w.deleted_at IS NULL
+		)`,
+		userID, workspaceID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check workspace membership: %w", err)
+	}
+	return exists == 1, nil
+}
@@ -0,0 +1,42 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
+)
+
+// TestAuthService_IsWorkspaceMember verifies membership follows the user's
+// own workspace and active status.
+func TestAuthService_IsWorkspaceMember(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db)
+	own := registerForRefresh(t, svc, "member@acme.com")
+	other := registerForRefresh(t, svc, "member@globex.com")
+
+	tests := []struct {
+		name        string
+		workspaceID string
+		want        bool
+	}{
+		{"own workspace", own.WorkspaceID, true},
+		{"other workspace", other.WorkspaceID, false},
+		{"unknown workspace", "ws-missing", false},
+	}
+	for _, tt := range tests {
+		got, err := svc.IsWorkspaceMember(context.Background(), own.UserID, tt.workspaceID)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: IsWorkspaceMember() = %v, %v; want %v, nil", tt.name, got, err, tt.want)
+		}
+	}
+
+	if _, err := db.Exec(`UPDATE user_account SET status = 'suspended' WHERE id = ?`, own.UserID); err != nil {
+		t.Fatalf("suspend user: %v", err)
+	}
+	if got, err := svc.IsWorkspaceMember(context.Background(), own.UserID, own.WorkspaceID); err != nil || got {
+		t.Errorf("suspended: IsWorkspaceMember() = %v, %v; want false, nil", got, err)
+	}
+}
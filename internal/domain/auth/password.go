@@ -0,0 +1,185 @@
+// Password strength rules applied by Register.
+package auth
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1" //nolint:gosec // SHA-1 is what the HIBP range API is keyed by, not used for storage
+	_ "embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrWeakPassword is returned by Register when the password does not meet the
+// strength rules. The wrapped message says which rule failed.
+var ErrWeakPassword = errors.New("weak password")
+
+// MinPasswordLength is the minimum accepted password length in characters.
+const MinPasswordLength = 12
+
+// minPasswordClasses is how many of lower, upper, digit and symbol a password
+// must mix.
+const minPasswordClasses = 3
+
+// minPasswordDistinct rejects long runs of few characters such as "Aaaaaaaaaaa1".
+const minPasswordDistinct = 5
+
+//go:embed common_passwords.txt
+var commonPasswordsFile string
+
+var commonPasswords = parseCommonPasswords(commonPasswordsFile)
+
+// BreachChecker reports whether a password appears in a known breach corpus.
+type BreachChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// Option configures an AuthService built by NewAuthService or NewAuthServiceWithAudit.
+type Option func(*authService)
+
+// WithBreachChecker makes Register reject passwords the checker reports as breached.
+func WithBreachChecker(checker BreachChecker) Option {
+	return func(s *authService) { s.breachChecker = checker }
+}
+
+// ValidatePassword checks length, character diversity and the embedded
+// common-passwords list. Errors wrap ErrWeakPassword.
+func ValidatePassword(password string) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return fmt.Errorf("%w: must be at least %d characters", ErrWeakPassword, MinPasswordLength)
+	}
+	if passwordClasses(password) < minPasswordClasses {
+		return fmt.Errorf("%w: must mix at least %d of lowercase, uppercase, digits and symbols", ErrWeakPassword, minPasswordClasses)
+	}
+	if distinctRunes(password) < minPasswordDistinct {
+		return fmt.Errorf("%w: must use at least %d different characters", ErrWeakPassword, minPasswordDistinct)
+	}
+	if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+		return fmt.Errorf("%w: password is too common", ErrWeakPassword)
+	}
+	return nil
+}
+
+// checkPassword runs ValidatePassword and, when configured, the breach check.
+// A breach checker failure is not fatal: registration must not depend on the
+// availability of a third-party service.
+func (s *authService) checkPassword(ctx context.Context, password string) error {
+	if err := ValidatePassword(password); err != nil {
+		return err
+	}
+	if s.breachChecker == nil {
+		return nil
+	}
+	breached, err := s.breachChecker.IsBreached(ctx, password)
+	if err == nil && breached {
+		return fmt.Errorf("%w: password appears in a known data breach", ErrWeakPassword)
+	}
+	return nil
+}
+
+func passwordClasses(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	n := 0
+	for _, ok := range []bool{lower, upper, digit, symbol} {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+func distinctRunes(password string) int {
+	seen := make(map[rune]struct{}, len(password))
+	for _, r := range password {
+		seen[r] = struct{}{}
+	}
+	return len(seen)
+}
+
+func parseCommonPasswords(file string) map[string]struct{} {
+	out := make(map[string]struct{})
+	for _, line := range strings.Split(file, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		out[strings.ToLower(line)] = struct{}{}
+	}
+	return out
+}
+
+// DefaultHIBPBaseURL is the HaveIBeenPwned Pwned Passwords API.
+const DefaultHIBPBaseURL = "https://api.pwnedpasswords.com"
+
+// HIBPChecker is a BreachChecker backed by the HaveIBeenPwned range API.
+// Only the first five hex characters of the password's SHA-1 leave the
+// process (k-anonymity); the suffix is matched locally.
+type HIBPChecker struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+// NewHIBPChecker returns a checker for DefaultHIBPBaseURL with a short timeout.
+func NewHIBPChecker() *HIBPChecker {
+	return &HIBPChecker{
+		BaseURL: DefaultHIBPBaseURL,
+		Client:  &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// IsBreached reports whether password appears in the Pwned Passwords corpus.
+func (c *HIBPChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // see import
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:5], digest[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(c.BaseURL, "/")+"/range/"+prefix, nil)
+	if err != nil {
+		return false, fmt.Errorf("build hibp request: %w", err)
+	}
+	// Padding hides the real result count from observers of the response size.
+	req.Header.Set("Add-Padding", "true")
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("hibp range request: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("hibp range request: unexpected status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		hashSuffix, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if ok && strings.EqualFold(hashSuffix, suffix) && count != "0" {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("read hibp range response: %w", err)
+	}
+	return false, nil
+}
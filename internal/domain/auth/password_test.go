@@ -0,0 +1,171 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec // mirrors the HIBP range API keying
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
+)
+
+func TestValidatePassword(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		password string
+		wantErr  bool
+	}{
+		{name: "mixed classes", password: "SecurePass123!"},
+		{name: "exact minimum length", password: "Exactly12Chr"},
+		{name: "lower digit symbol", password: "correct-horse-42"},
+		{name: "unicode letters", password: "Contraseña-Ñandú7"},
+		{name: "too short", password: "Short1!", wantErr: true},
+		{name: "eleven chars", password: "OnlyEleven!", wantErr: true},
+		{name: "single class", password: "alllowercaseletters", wantErr: true},
+		{name: "two classes", password: "lowercase12345", wantErr: true},
+		{name: "few distinct characters", password: "Aaaaaaaaaaa1", wantErr: true},
+		{name: "common password", password: "Password1234", wantErr: true},
+		{name: "common password any case", password: "WELCOME2025!", wantErr: true},
+		{name: "common password with symbol", password: "P@ssw0rd1234", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := domainauth.ValidatePassword(tc.password)
+			if tc.wantErr && !errors.Is(err, domainauth.ErrWeakPassword) {
+				t.Fatalf("ValidatePassword(%q) = %v; want ErrWeakPassword", tc.password, err)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("ValidatePassword(%q) = %v; want nil", tc.password, err)
+			}
+		})
+	}
+}
+
+func TestAuthService_Register_WeakPassword(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db)
+
+	_, err := svc.Register(context.Background(), domainauth.RegisterInput{
+		Email:         "weak@acme.com",
+		Password:      "Welcome12345",
+		DisplayName:   "Weak",
+		WorkspaceName: "Acme Corp",
+	})
+	if !errors.Is(err, domainauth.ErrWeakPassword) {
+		t.Fatalf("Register() error = %v; want ErrWeakPassword", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM user_account WHERE email = ?`, "weak@acme.com").Scan(&count); err != nil {
+		t.Fatalf("count users: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("user rows = %d; want 0", count)
+	}
+}
+
+type stubBreachChecker struct {
+	breached bool
+	err      error
+}
+
+func (s stubBreachChecker) IsBreached(context.Context, string) (bool, error) {
+	return s.breached, s.err
+}
+
+func TestAuthService_Register_BreachChecker(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		checker stubBreachChecker
+		wantErr bool
+	}{
+		{name: "breached", checker: stubBreachChecker{breached: true}, wantErr: true},
+		{name: "not breached", checker: stubBreachChecker{}},
+		{name: "checker unavailable", checker: stubBreachChecker{err: errors.New("timeout")}},
+	}
+	for i, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			db := mustOpenDB(t)
+			svc := domainauth.NewAuthService(db, domainauth.WithBreachChecker(tc.checker))
+
+			_, err := svc.Register(context.Background(), domainauth.RegisterInput{
+				Email:         fmt.Sprintf("breach%d@acme.com", i),
+				Password:      "SecurePass123!",
+				DisplayName:   "Breach",
+				WorkspaceName: "Acme Corp",
+			})
+			if tc.wantErr && !errors.Is(err, domainauth.ErrWeakPassword) {
+				t.Fatalf("Register() error = %v; want ErrWeakPassword", err)
+			}
+			if !tc.wantErr && err != nil {
+				t.Fatalf("Register() error = %v; want nil", err)
+			}
+		})
+	}
+}
+
+func TestHIBPChecker_IsBreached(t *testing.T) {
+	t.Parallel()
+
+	sum := sha1.Sum([]byte("SecurePass123!")) //nolint:gosec // test fixture
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	var gotPath, gotPadding string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotPadding = r.Header.Get("Add-Padding")
+		fmt.Fprintf(w, "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n%s:42\r\nFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:0\r\n", digest[5:])
+	}))
+	defer srv.Close()
+
+	checker := &domainauth.HIBPChecker{BaseURL: srv.URL, Client: srv.Client()}
+
+	breached, err := checker.IsBreached(context.Background(), "SecurePass123!")
+	if err != nil {
+		t.Fatalf("IsBreached() error = %v", err)
+	}
+	if !breached {
+		t.Fatal("IsBreached() = false; want true")
+	}
+	if gotPath != "/range/"+digest[:5] {
+		t.Fatalf("path = %q; want only the 5-char hash prefix", gotPath)
+	}
+	if gotPadding != "true" {
+		t.Fatalf("Add-Padding = %q; want true", gotPadding)
+	}
+
+	breached, err = checker.IsBreached(context.Background(), "AnotherSecure456?")
+	if err != nil {
+		t.Fatalf("IsBreached() error = %v", err)
+	}
+	if breached {
+		t.Fatal("IsBreached() = true for a password not in the range; want false")
+	}
+}
+
+func TestHIBPChecker_UnexpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	checker := &domainauth.HIBPChecker{BaseURL: srv.URL, Client: srv.Client()}
+	if _, err := checker.IsBreached(context.Background(), "SecurePass123!"); err == nil {
+		t.Fatal("IsBreached() error = nil; want status error")
+	}
+}
@@ -0,0 +1,169 @@
+// Task 1.6.15: Rotating refresh tokens
+// Access JWTs stay short-lived; clients exchange a single-use refresh token for
+// a new access JWT and a new refresh token via Refresh.
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pkgauth "github.com/matiasleandrokruk/fenix/pkg/auth"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+// ErrInvalidRefreshToken is returned by Refresh for unknown, expired, revoked or
+// reused refresh tokens. A single error avoids leaking which case applied.
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+
+const actionTokenRefresh = "token_refresh"
+
+// execer is satisfied by *sql.DB and *sql.Tx.
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// storedRefreshToken is the refresh_token row looked up during Refresh.
+type storedRefreshToken struct {
+	id          string
+	userID      string
+	workspaceID string
+	role        string
+	revoked     bool
+	expiresAt   time.Time
+}
+
+// issueTokens signs an access JWT and persists a new refresh token for the user.
+func (s *authService) issueTokens(ctx context.Context, userID, workspaceID, role string) (*AuthResult, error) {
+	token, err := pkgauth.GenerateJWTWithRole(userID, workspaceID, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+	}
+	refreshToken, _, err := insertRefreshToken(ctx, s.db, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthResult{
+		Token:        token,
+		RefreshToken: refreshToken,
+		UserID:       userID,
+		WorkspaceID:  workspaceID,
+	}, nil
+}
+
+// insertRefreshToken stores the hash of a fresh refresh token and returns the
+// plaintext token together with its row id.
+func insertRefreshToken(ctx context.Context, db execer, userID, workspaceID string) (string, string, error) {
+	token, hash, err := pkgauth.GenerateRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
+	id := uuid.NewV7().String()
+	now := time.Now().UTC()
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO refresh_token (id, workspace_id, user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, id, workspaceID, userID, hash, now.Add(pkgauth.GetRefreshTokenExpiry()), now)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return token, id, nil
+}
+
+// Refresh exchanges a valid refresh token for a new access JWT and a new
+// refresh token. The presented token is revoked on use. Presenting an already
+// revoked token is treated as theft: every refresh token of the user is revoked.
+func (s *authService) Refresh(ctx context.Context, refreshToken string) (*AuthResult, error) {
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	stored, err := lookupRefreshToken(ctx, tx, pkgauth.HashRefreshToken(refreshToken))
+	if err != nil {
+		s.logAuthFailure(ctx, "unknown", "unknown", actionTokenRefresh, "refresh_token_not_found")
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if stored.revoked {
+		if _, revokeErr := tx.ExecContext(ctx,
+			`UPDATE refresh_token SET revoked = 1 WHERE user_id = ? AND revoked = 0`, stored.userID,
+		); revokeErr != nil {
+			return nil, fmt.Errorf("failed to revoke refresh tokens: %w", revokeErr)
+		}
+		if commitErr := tx.Commit(); commitErr != nil {
+			return nil, fmt.Errorf("commit refresh token revocation: %w", commitErr)
+		}
+		s.logAuthFailure(ctx, stored.workspaceID, stored.userID, actionTokenRefresh, "refresh_token_reused")
+		return nil, ErrInvalidRefreshToken
+	}
+	if !time.Now().Before(stored.expiresAt) {
+		s.logAuthFailure(ctx, stored.workspaceID, stored.userID, actionTokenRefresh, "refresh_token_expired")
+		return nil, ErrInvalidRefreshToken
+	}
+
+	result, err := s.rotateRefreshToken(ctx, tx, stored)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit refresh token rotation: %w", err)
+	}
+
+	s.logAuthSuccess(ctx, stored.workspaceID, stored.userID, actionTokenRefresh)
+	return result, nil
+}
+
+func lookupRefreshToken(ctx context.Context, tx *sql.Tx, hash string) (*storedRefreshToken, error) {
+	var rt storedRefreshToken
+	err := tx.QueryRowContext(ctx, `
+		SELECT rt.id, rt.user_id, rt.workspace_id, u.role, rt.revoked, rt.expires_at
+		FROM refresh_token rt
+		JOIN user_account u ON u.id = rt.user_id
+		WHERE rt.token_hash = ? AND u.status = 'active'
+		LIMIT 1
+	`, hash).Scan(&rt.id, &rt.userID, &rt.workspaceID, &rt.role, &rt.revoked, &rt.expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rt, nil
+}
+
+// rotateRefreshToken issues the replacement tokens and revokes the old one.
+// The conditional UPDATE makes concurrent refreshes of the same token lose.
+// The role is re-read from user_account so role changes apply on refresh.
+func (s *authService) rotateRefreshToken(ctx context.Context, tx *sql.Tx, old *storedRefreshToken) (*AuthResult, error) {
+	token, err := pkgauth.GenerateJWTWithRole(old.userID, old.workspaceID, old.role)
+	if err != nil {
+		s.logAuthFailure(ctx, old.workspaceID, old.userID, actionTokenRefresh, "jwt_generation_failed")
+		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+	}
+	refreshToken, newID, err := insertRefreshToken(ctx, tx, old.userID, old.workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE refresh_token SET revoked = 1, replaced_by = ? WHERE id = ? AND revoked = 0`, newID, old.id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if n, rowsErr := res.RowsAffected(); rowsErr != nil || n != 1 {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return &AuthResult{
+		Token:        token,
+		RefreshToken: refreshToken,
+		UserID:       old.userID,
+		WorkspaceID:  old.workspaceID,
+	}, nil
+}
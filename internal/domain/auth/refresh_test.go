@@ -0,0 +1,100 @@
+// Task 1.6.15: Tests for rotating refresh tokens.
+// Traces: FR-060
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
+)
+
+func registerForRefresh(t *testing.T, svc domainauth.AuthService, email string) *domainauth.AuthResult {
+	t.Helper()
+	result, err := svc.Register(context.Background(), domainauth.RegisterInput{
+		Email:         email,
+		Password:      "SecurePass123!",
+		DisplayName:   "Refresh",
+		WorkspaceName: "Refresh Corp",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if result.RefreshToken == "" {
+		t.Fatal("Register() RefreshToken is empty; want refresh token")
+	}
+	return result
+}
+
+// TestAuthService_Refresh_RotatesToken verifies Refresh returns new tokens and invalidates the old one.
+func TestAuthService_Refresh_RotatesToken(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db)
+	reg := registerForRefresh(t, svc, "rotate@acme.com")
+
+	refreshed, err := svc.Refresh(context.Background(), reg.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v; want nil", err)
+	}
+	if refreshed.Token == "" || refreshed.RefreshToken == "" {
+		t.Fatal("Refresh() returned empty tokens")
+	}
+	if refreshed.RefreshToken == reg.RefreshToken {
+		t.Error("Refresh() returned the same refresh token; want rotation")
+	}
+	if refreshed.UserID != reg.UserID || refreshed.WorkspaceID != reg.WorkspaceID {
+		t.Errorf("Refresh() identity = %s/%s; want %s/%s", refreshed.UserID, refreshed.WorkspaceID, reg.UserID, reg.WorkspaceID)
+	}
+
+	var stored string
+	if err := db.QueryRow(`SELECT token_hash FROM refresh_token WHERE user_id = ? LIMIT 1`, reg.UserID).Scan(&stored); err != nil {
+		t.Fatalf("query refresh_token: %v", err)
+	}
+	if stored == reg.RefreshToken {
+		t.Error("refresh token stored in plaintext; want hash")
+	}
+}
+
+// TestAuthService_Refresh_ReuseRevokesFamily verifies a reused token fails and revokes the rotated token too.
+func TestAuthService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db)
+	reg := registerForRefresh(t, svc, "reuse@acme.com")
+
+	refreshed, err := svc.Refresh(context.Background(), reg.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	if _, err := svc.Refresh(context.Background(), reg.RefreshToken); !errors.Is(err, domainauth.ErrInvalidRefreshToken) {
+		t.Fatalf("Refresh(reused) error = %v; want ErrInvalidRefreshToken", err)
+	}
+	if _, err := svc.Refresh(context.Background(), refreshed.RefreshToken); !errors.Is(err, domainauth.ErrInvalidRefreshToken) {
+		t.Fatalf("Refresh(after reuse) error = %v; want ErrInvalidRefreshToken", err)
+	}
+}
+
+// TestAuthService_Refresh_InvalidAndExpired verifies unknown and expired tokens are rejected.
+func TestAuthService_Refresh_InvalidAndExpired(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db)
+	reg := registerForRefresh(t, svc, "expired@acme.com")
+
+	if _, err := svc.Refresh(context.Background(), "not-a-token"); !errors.Is(err, domainauth.ErrInvalidRefreshToken) {
+		t.Fatalf("Refresh(unknown) error = %v; want ErrInvalidRefreshToken", err)
+	}
+
+	if _, err := db.Exec(`UPDATE refresh_token SET expires_at = datetime('now', '-1 hour') WHERE user_id = ?`, reg.UserID); err != nil {
+		t.Fatalf("expire refresh token: %v", err)
+	}
+	if _, err := svc.Refresh(context.Background(), reg.RefreshToken); !errors.Is(err, domainauth.ErrInvalidRefreshToken) {
+		t.Fatalf("Refresh(expired) error = %v; want ErrInvalidRefreshToken", err)
+	}
+}
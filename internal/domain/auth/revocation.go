@@ -0,0 +1,83 @@
+// Task 1.6.16: Logout and access token revocation
+// Revoked access JWTs are tracked by jti until their natural expiry.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pkgauth "github.com/matiasleandrokruk/fenix/pkg/auth"
+)
+
+// ErrTokenNotRevocable is returned by Logout for tokens issued without a jti.
+var ErrTokenNotRevocable = errors.New("token has no jti and cannot be revoked")
+
+const actionLogout = "logout"
+
+// LogoutInput identifies the access token (and optionally the refresh token) to revoke.
+type LogoutInput struct {
+	WorkspaceID  string
+	UserID       string
+	TokenID      string // jti of the current access JWT
+	ExpiresAt    time.Time
+	RefreshToken string // optional; revoked alongside the access token
+}
+
+// Logout adds the access token jti to the revocation list and revokes the given
+// refresh token of the same user, if any. Expired revocations are purged on the way.
+func (s *authService) Logout(ctx context.Context, input LogoutInput) error {
+	if input.TokenID == "" {
+		return ErrTokenNotRevocable
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO revoked_token (jti, workspace_id, user_id, expires_at, revoked_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(jti) DO NOTHING
+	`, input.TokenID, input.WorkspaceID, input.UserID, input.ExpiresAt.UTC(), time.Now().UTC())
+	if err != nil {
+		s.logAuthFailure(ctx, input.WorkspaceID, input.UserID, actionLogout, "revoke_failed")
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	if input.RefreshToken != "" {
+		if _, err = s.db.ExecContext(ctx,
+			`UPDATE refresh_token SET revoked = 1 WHERE token_hash = ? AND user_id = ?`,
+			pkgauth.HashRefreshToken(input.RefreshToken), input.UserID,
+		); err != nil {
+			return fmt.Errorf("failed to revoke refresh token: %w", err)
+		}
+	}
+
+	// Best effort: a failed cleanup must not fail the logout itself.
+	_, _ = s.PurgeExpiredRevocations(ctx)
+
+	s.logAuthSuccess(ctx, input.WorkspaceID, input.UserID, actionLogout)
+	return nil
+}
+
+// IsTokenRevoked reports whether the access token with the given jti was revoked.
+func (s *authService) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var exists int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM revoked_token WHERE jti = ?)`, jti,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return exists == 1, nil
+}
+
+// PurgeExpiredRevocations deletes revocation rows whose token has already
+// expired and returns how many were removed.
+func (s *authService) PurgeExpiredRevocations(ctx context.Context) (int64, error) {
+	res, err := s.db.ExecContext(ctx,
+		`DELETE FROM revoked_token WHERE expires_at < ?`, time.Now().UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired revocations: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,92 @@
+// Task 1.6.16: Tests for logout and access token revocation.
+// Traces: FR-060
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
+	"github.com/matiasleandrokruk/fenix/pkg/auth"
+)
+
+// TestAuthService_Logout_RevokesTokenAndRefreshToken verifies the jti is revoked and the refresh token stops working.
+func TestAuthService_Logout_RevokesTokenAndRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db)
+	reg := registerForRefresh(t, svc, "logout@acme.com")
+
+	claims, err := auth.ParseJWT(reg.Token)
+	if err != nil {
+		t.Fatalf("ParseJWT() error = %v", err)
+	}
+	if claims.ID == "" {
+		t.Fatal("issued JWT has no jti")
+	}
+
+	err = svc.Logout(context.Background(), domainauth.LogoutInput{
+		WorkspaceID:  reg.WorkspaceID,
+		UserID:       reg.UserID,
+		TokenID:      claims.ID,
+		ExpiresAt:    claims.ExpiresAt.Time,
+		RefreshToken: reg.RefreshToken,
+	})
+	if err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+
+	revoked, err := svc.IsTokenRevoked(context.Background(), claims.ID)
+	if err != nil || !revoked {
+		t.Fatalf("IsTokenRevoked() = %v, %v; want true, nil", revoked, err)
+	}
+	if _, err := svc.Refresh(context.Background(), reg.RefreshToken); !errors.Is(err, domainauth.ErrInvalidRefreshToken) {
+		t.Fatalf("Refresh(after logout) error = %v; want ErrInvalidRefreshToken", err)
+	}
+}
+
+// TestAuthService_Logout_WithoutJTI verifies tokens lacking a jti are rejected.
+func TestAuthService_Logout_WithoutJTI(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db)
+
+	err := svc.Logout(context.Background(), domainauth.LogoutInput{WorkspaceID: "ws", UserID: "u"})
+	if !errors.Is(err, domainauth.ErrTokenNotRevocable) {
+		t.Fatalf("Logout() error = %v; want ErrTokenNotRevocable", err)
+	}
+}
+
+// TestAuthService_PurgeExpiredRevocations verifies only expired jti rows are removed.
+func TestAuthService_PurgeExpiredRevocations(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db)
+	reg := registerForRefresh(t, svc, "purge@acme.com")
+
+	for jti, exp := range map[string]time.Time{
+		"expired-jti": time.Now().Add(-time.Hour),
+		"active-jti":  time.Now().Add(time.Hour),
+	} {
+		if err := svc.Logout(context.Background(), domainauth.LogoutInput{
+			WorkspaceID: reg.WorkspaceID, UserID: reg.UserID, TokenID: jti, ExpiresAt: exp,
+		}); err != nil {
+			t.Fatalf("Logout(%s) error = %v", jti, err)
+		}
+	}
+	if _, err := svc.PurgeExpiredRevocations(context.Background()); err != nil {
+		t.Fatalf("PurgeExpiredRevocations() error = %v", err)
+	}
+
+	if revoked, _ := svc.IsTokenRevoked(context.Background(), "expired-jti"); revoked {
+		t.Error("expired jti still present after purge")
+	}
+	if revoked, _ := svc.IsTokenRevoked(context.Background(), "active-jti"); !revoked {
+		t.Error("active jti was purged; want kept until expiry")
+	}
+}
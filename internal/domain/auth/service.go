@@ -24,6 +24,14 @@ var ErrEmailAlreadyExists = errors.New("email already registered")
 
 const actionLogin = "login"
 
+// RBAC roles stored on user_account.role and carried in the JWT role claim.
+// RoleAdmin covers RoleAgent, which covers RoleMember.
+const (
+	RoleAdmin  = "admin"
+	RoleAgent  = "agent"
+	RoleMember = "member"
+)
+
 // RegisterInput holds the data needed to create a new workspace and user.
 // Task 1.6: WorkspaceName creates the tenant; Email is the unique login identifier.
 type RegisterInput struct {
@@ -37,16 +45,19 @@ type RegisterInput struct {
 type LoginInput struct {
 	Email    string
 	Password string
+	IP       string // client address; optional, counted for lockout when set
 }
 
-// AuthResult is returned after successful Register or Login.
+// AuthResult is returned after successful Register, Login or Refresh.
 // Token is a signed JWT containing UserID and WorkspaceID claims.
+// RefreshToken is the opaque single-use token accepted by Refresh.
 //
 //nolint:revive // API de dominio estable; renombrar rompe referencias amplias
 type AuthResult struct {
-	Token       string
-	UserID      string
-	WorkspaceID string
+	Token        string
+	RefreshToken string
+	UserID       string
+	WorkspaceID  string
 }
 
 // AuthService defines the authentication business operations.
@@ -55,12 +66,31 @@ type AuthResult struct {
 type AuthService interface {
 	Register(ctx context.Context, input RegisterInput) (*AuthResult, error)
 	Login(ctx context.Context, input LoginInput) (*AuthResult, error)
+	Refresh(ctx context.Context, refreshToken string) (*AuthResult, error)
+	Logout(ctx context.Context, input LogoutInput) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+	IsWorkspaceMember(ctx context.Context, userID, workspaceID string) (bool, error)
+	PurgeExpiredRevocations(ctx context.Context) (int64, error)
 }
 
 // authService is the concrete implementation backed by SQLite.
 type authService struct {
-	db          *sql.DB
-	auditLogger auditLogger
+	db            *sql.DB
+	auditLogger   auditLogger
+	breachChecker BreachChecker
+	lockout       LockoutPolicy
+	provision     WorkspaceProvisioner
+}
+
+// WorkspaceProvisioner creates the workspace of a registering user inside the
+// Register transaction and returns its ID.
+type WorkspaceProvisioner func(ctx context.Context, tx *sql.Tx, name string) (string, error)
+
+// WithWorkspaceProvisioner makes Register create workspaces with provision,
+// e.g. through crm.WorkspaceService.CreateTx. Without it Register inserts the
+// workspace row itself.
+func WithWorkspaceProvisioner(provision WorkspaceProvisioner) Option {
+	return func(s *authService) { s.provision = provision }
 }
 
 type auditLogger interface {
@@ -78,52 +108,63 @@ type auditLogger interface {
 }
 
 // NewAuthService creates a new AuthService backed by the provided DB.
-func NewAuthService(db *sql.DB) AuthService {
-	return &authService{db: db}
+func NewAuthService(db *sql.DB, opts ...Option) AuthService {
+	return newAuthService(&authService{db: db}, opts)
 }
 
 // NewAuthServiceWithAudit creates a new AuthService with audit logging.
-func NewAuthServiceWithAudit(db *sql.DB, logger auditLogger) AuthService {
-	return &authService{db: db, auditLogger: logger}
+func NewAuthServiceWithAudit(db *sql.DB, logger auditLogger, opts ...Option) AuthService {
+	return newAuthService(&authService{db: db, auditLogger: logger}, opts)
+}
+
+func newAuthService(s *authService, opts []Option) AuthService {
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
-// Register creates a new workspace and user, then returns a JWT.
+// Register creates a new workspace and user, then returns a JWT and refresh token.
 // Task 1.6.8: Workspace + user creation is atomic via SQLite transaction.
 // Password is hashed with bcrypt before storage; plaintext is never stored.
+// Passwords failing ValidatePassword or the breach check return ErrWeakPassword.
 func (s *authService) Register(ctx context.Context, input RegisterInput) (*AuthResult, error) {
+	if err := s.checkPassword(ctx, input.Password); err != nil {
+		return nil, err
+	}
+
 	hash, err := pkgauth.HashPassword(input.Password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	workspaceID := uuid.NewV7().String()
 	userID := uuid.NewV7().String()
 
-	if insErr := s.insertWorkspaceAndUser(ctx, insertParams{
-		workspaceID:   workspaceID,
+	workspaceID, insErr := s.insertWorkspaceAndUser(ctx, insertParams{
 		userID:        userID,
 		workspaceName: input.WorkspaceName,
 		email:         input.Email,
 		passwordHash:  hash,
 		displayName:   input.DisplayName,
-	}); insErr != nil {
+	})
+	if insErr != nil {
 		return nil, insErr
 	}
 
-	token, err := pkgauth.GenerateJWT(userID, workspaceID)
+	// The user who creates a workspace owns it.
+	result, err := s.issueTokens(ctx, userID, workspaceID, RoleAdmin)
 	if err != nil {
-		s.logAuthFailure(ctx, workspaceID, userID, "register", "jwt_generation_failed")
-		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+		s.logAuthFailure(ctx, workspaceID, userID, "register", "token_issuance_failed")
+		return nil, err
 	}
 
 	s.logAuthSuccess(ctx, workspaceID, userID, "register")
 
-	return &AuthResult{Token: token, UserID: userID, WorkspaceID: workspaceID}, nil
+	return result, nil
 }
 
 // insertParams bundles the data needed for atomic workspace + user creation.
 type insertParams struct {
-	workspaceID   string
 	userID        string
 	workspaceName string
 	email         string
@@ -131,96 +172,126 @@ type insertParams struct {
 	displayName   string
 }
 
-// insertWorkspaceAndUser executes workspace + user creation in a single transaction.
+// insertWorkspaceAndUser executes workspace + user creation in a single transaction
+// and returns the new workspace ID.
 // Task 1.6.8: Extracted from Register to reduce cyclomatic complexity below threshold.
-func (s *authService) insertWorkspaceAndUser(ctx context.Context, p insertParams) error {
+func (s *authService) insertWorkspaceAndUser(ctx context.Context, p insertParams) (string, error) {
 	now := time.Now().UTC().Format(time.RFC3339)
-	slug := generateSlug(p.workspaceName, p.workspaceID)
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback() //nolint:errcheck
 
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO workspace (id, name, slug, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?)
-	`, p.workspaceID, p.workspaceName, slug, now, now)
+	provision := s.provision
+	if provision == nil {
+		provision = insertWorkspace
+	}
+	workspaceID, err := provision(ctx, tx, p.workspaceName)
 	if err != nil {
-		return fmt.Errorf("failed to create workspace: %w", err)
+		return "", fmt.Errorf("failed to create workspace: %w", err)
 	}
 
 	_, err = tx.ExecContext(ctx, `
-		INSERT INTO user_account (id, workspace_id, email, password_hash, display_name, status, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, 'active', ?, ?)
-	`, p.userID, p.workspaceID, p.email, p.passwordHash, p.displayName, now, now)
+		INSERT INTO user_account (id, workspace_id, email, password_hash, display_name, status, role, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, 'active', ?, ?, ?)
+	`, p.userID, workspaceID, p.email, p.passwordHash, p.displayName, RoleAdmin, now, now)
 	if err != nil {
 		if isUniqueViolation(err) {
-			return ErrEmailAlreadyExists
+			return "", ErrEmailAlreadyExists
 		}
-		return fmt.Errorf("failed to create user: %w", err)
+		return "", fmt.Errorf("failed to create user: %w", err)
 	}
 
 	if commitErr := tx.Commit(); commitErr != nil {
-		return fmt.Errorf("commit workspace and user transaction: %w", commitErr)
+		return "", fmt.Errorf("commit workspace and user transaction: %w", commitErr)
 	}
-	return nil
+	return workspaceID, nil
 }
 
-// Login verifies credentials and returns a JWT.
+// insertWorkspace is the WorkspaceProvisioner used when none is configured.
+func insertWorkspace(ctx context.Context, tx *sql.Tx, name string) (string, error) {
+	id := uuid.NewV7().String()
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO workspace (id, name, slug, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, id, name, generateSlug(name, id), now, now)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Login verifies credentials and returns a JWT and refresh token.
 // Task 1.6.8: Always returns ErrInvalidCredentials for any failure (email not found OR wrong password)
 // to avoid revealing whether the email exists (security).
+// With a LockoutPolicy, repeated failures for the same email from the same
+// IP, or from the same IP across emails, return ErrLoginLocked until the
+// cooldown passes, whether or not the email exists.
 func (s *authService) Login(ctx context.Context, input LoginInput) (*AuthResult, error) {
-	var userID, workspaceID string
+	subjects := s.lockout.loginLockoutSubjects(input)
+	if err := s.checkLockout(ctx, subjects); err != nil {
+		if errors.Is(err, ErrLoginLocked) {
+			s.logAuthDenied(ctx, "unknown", "unknown", actionLogin, map[string]any{"reason": "locked_out"})
+		}
+		return nil, err
+	}
+
+	var userID, workspaceID, role string
 	var passwordHash sql.NullString
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT id, workspace_id, password_hash
+		SELECT id, workspace_id, password_hash, role
 		FROM user_account
 		WHERE email = ? AND status = 'active'
 		LIMIT 1
-	`, input.Email).Scan(&userID, &workspaceID, &passwordHash)
+	`, input.Email).Scan(&userID, &workspaceID, &passwordHash, &role)
 
 	if err != nil {
 		// Whether the user doesn't exist or there's a DB error, return generic message
+		burnPasswordCheck(input.Password)
 		s.logAuthFailure(ctx, "unknown", "unknown", actionLogin, "user_not_found_or_query_error")
-		return nil, ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, subjects, "unknown", "unknown")
 	}
 
 	// User found but has no password hash (OIDC-only account)
 	if !passwordHash.Valid || passwordHash.String == "" {
+		burnPasswordCheck(input.Password)
 		s.logAuthFailure(ctx, workspaceID, userID, actionLogin, "missing_password_hash")
-		return nil, ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, subjects, workspaceID, userID)
 	}
 
 	// Verify password (constant-time comparison via bcrypt)
 	if !pkgauth.VerifyPassword(passwordHash.String, input.Password) {
 		s.logAuthFailure(ctx, workspaceID, userID, actionLogin, "invalid_password")
-		return nil, ErrInvalidCredentials
+		return nil, s.loginFailed(ctx, subjects, workspaceID, userID)
 	}
 
-	// Credentials valid — issue JWT
-	token, err := pkgauth.GenerateJWT(userID, workspaceID)
+	// Credentials valid — issue JWT + refresh token
+	result, err := s.issueTokens(ctx, userID, workspaceID, role)
 	if err != nil {
-		s.logAuthFailure(ctx, workspaceID, userID, actionLogin, "jwt_generation_failed")
-		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+		s.logAuthFailure(ctx, workspaceID, userID, actionLogin, "token_issuance_failed")
+		return nil, err
 	}
 
+	s.resetLoginFailures(ctx, subjects)
 	s.logAuthSuccess(ctx, workspaceID, userID, actionLogin)
 
-	return &AuthResult{
-		Token:       token,
-		UserID:      userID,
-		WorkspaceID: workspaceID,
-	}, nil
+	return result, nil
+}
+
+// loginFailed records a failed attempt and returns ErrInvalidCredentials. The
+// attempt that reaches the threshold still gets ErrInvalidCredentials; only
+// later attempts see ErrLoginLocked.
+func (s *authService) loginFailed(ctx context.Context, subjects []lockoutSubject, workspaceID, userID string) error {
+	if err := s.recordLoginFailure(ctx, subjects, workspaceID, userID); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
 }
 
-// generateSlug creates a URL-safe workspace slug from the name + a short ID suffix.
-// generateSlug creates a URL-safe workspace slug from the name + the full workspace ID.
-// Task 1.6.8: Full ID used as suffix to guarantee uniqueness even for identical names.
-// Task 1.6.11 fix: Replaced first-8-chars truncation — UUID v7 timestamps are identical
-// for workspaces created within the same millisecond, causing UNIQUE constraint failures.
 // slugChar maps a single rune to its slug representation.
 // Returns the lowercase char for letters, digit as-is, '-' for spaces/dashes, or -1 to skip.
 // Extracted from generateSlug to reduce cyclomatic complexity (each case is 1 branch).
@@ -237,10 +308,11 @@ func slugChar(c rune) rune {
 	}
 }
 
+// generateSlug creates a URL-safe workspace slug from the name + the full workspace ID.
+// Task 1.6.11 fix: the full ID guarantees uniqueness even for identical names.
 func generateSlug(name, id string) string {
 	// strings.Map calls slugChar for each rune; -1 means drop the character.
 	slug := strings.Map(slugChar, name)
-	// Use full UUID as suffix — guarantees uniqueness regardless of timing
 	return slug + "-" + id
 }
 
@@ -157,6 +157,40 @@ func TestAuthService_Register_WorkspaceCreated(t *testing.T) {
 	}
 }
 
+// TestAuthService_Register_UsesWorkspaceProvisioner verifies Register creates
+// the workspace through the configured provisioner, in its transaction.
+func TestAuthService_Register_UsesWorkspaceProvisioner(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	var provisioned string
+	svc := domainauth.NewAuthService(db, domainauth.WithWorkspaceProvisioner(
+		func(ctx context.Context, tx *sql.Tx, name string) (string, error) {
+			provisioned = name
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO workspace (id, name, slug, created_at, updated_at)
+				VALUES ('ws-provisioned', ?, 'provisioned', datetime('now'), datetime('now'))`, name)
+			return "ws-provisioned", err
+		}))
+
+	result, err := svc.Register(context.Background(), domainauth.RegisterInput{
+		Email:         "erin@example.com",
+		Password:      "SecurePass123!",
+		DisplayName:   "Erin",
+		WorkspaceName: "Provisioned Co",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if provisioned != "Provisioned Co" || result.WorkspaceID != "ws-provisioned" {
+		t.Fatalf("provisioned %q, workspace %q; want the provisioner's workspace", provisioned, result.WorkspaceID)
+	}
+	var userWorkspace string
+	if err := db.QueryRow(`SELECT workspace_id FROM user_account WHERE id = ?`, result.UserID).Scan(&userWorkspace); err != nil || userWorkspace != "ws-provisioned" {
+		t.Fatalf("user workspace = %q (%v); want ws-provisioned", userWorkspace, err)
+	}
+}
+
 // TestAuthService_Register_DuplicateEmail verifies that duplicate email returns error.
 func TestAuthService_Register_DuplicateEmail(t *testing.T) {
 	t.Parallel()
@@ -249,6 +283,43 @@ func TestAuthService_Login_TokenIsValid(t *testing.T) {
 	}
 }
 
+// TestAuthService_Login_RoleClaim verifies the JWT role follows user_account.role:
+// workspace owners register as admin and a changed role shows up on next login.
+func TestAuthService_Login_RoleClaim(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	svc := domainauth.NewAuthService(db)
+
+	reg, err := svc.Register(context.Background(), domainauth.RegisterInput{
+		Email: "grace@acme.com", Password: "SecurePass123!", DisplayName: "Grace", WorkspaceName: "Acme Corp",
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if claims, _ := auth.ParseJWT(reg.Token); claims == nil || claims.Role != domainauth.RoleAdmin {
+		t.Fatalf("Register() role claim = %+v; want %q", claims, domainauth.RoleAdmin)
+	}
+
+	if _, err = db.Exec(`UPDATE user_account SET role = ? WHERE id = ?`, domainauth.RoleAgent, reg.UserID); err != nil {
+		t.Fatalf("update role: %v", err)
+	}
+
+	login, err := svc.Login(context.Background(), domainauth.LoginInput{
+		Email: "grace@acme.com", Password: "SecurePass123!",
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	claims, err := auth.ParseJWT(login.Token)
+	if err != nil {
+		t.Fatalf("Login() token is not valid JWT: %v", err)
+	}
+	if claims.Role != domainauth.RoleAgent {
+		t.Errorf("Login() role claim = %q; want %q", claims.Role, domainauth.RoleAgent)
+	}
+}
+
 // TestAuthService_Login_WrongPassword verifies that wrong password returns error.
 func TestAuthService_Login_WrongPassword(t *testing.T) {
 	t.Parallel()
@@ -4,11 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/domain/blackboard"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
 
@@ -113,7 +114,7 @@ func persistDerivedArtifact(
 ) {
 	raw, err := json.Marshal(payload)
 	if err != nil {
-		log.Printf("blackboard specialized agents: marshal payload actor=%s: %v", actorID, err)
+		logArtifactError(ctx, "marshal payload failed", actorID, err)
 		return
 	}
 
@@ -128,7 +129,7 @@ func persistDerivedArtifact(
 		CreatedAt:            now,
 	})
 	if appendErr != nil {
-		log.Printf("blackboard specialized agents: append timeline actor=%s: %v", actorID, appendErr)
+		logArtifactError(ctx, "append timeline failed", actorID, appendErr)
 		return
 	}
 
@@ -143,7 +144,7 @@ func persistDerivedArtifact(
 		CreatedAt:            now,
 		UpdatedAt:            now,
 	}); histErr != nil {
-		log.Printf("blackboard specialized agents: set historical memory actor=%s: %v", actorID, histErr)
+		logArtifactError(ctx, "set historical memory failed", actorID, histErr)
 		return
 	}
 
@@ -160,7 +161,7 @@ func persistDerivedArtifact(
 	}
 	pointerRaw, marshalErr := json.Marshal(pointer)
 	if marshalErr != nil {
-		log.Printf("blackboard specialized agents: marshal pointer actor=%s: %v", actorID, marshalErr)
+		logArtifactError(ctx, "marshal pointer failed", actorID, marshalErr)
 		return
 	}
 	if setErr := attachment.Memory.Set(ctx, blackboard.AgentMemory{
@@ -172,10 +173,16 @@ func persistDerivedArtifact(
 		CreatedAt:            now,
 		UpdatedAt:            now,
 	}); setErr != nil {
-		log.Printf("blackboard specialized agents: set memory actor=%s: %v", actorID, setErr)
+		logArtifactError(ctx, "set memory failed", actorID, setErr)
 	}
 }
 
+func logArtifactError(ctx context.Context, msg, actorID string, err error) {
+	logging.FromContext(ctx).Error(msg,
+		slog.String(logging.KeyComponent, "blackboard.specializedAgents"),
+		slog.String("actor_id", actorID), logging.Err(err))
+}
+
 func memoryKeyFor(actorID string) string {
 	return fmt.Sprintf("specialized_agents/%s/last_artifact", actorID)
 }
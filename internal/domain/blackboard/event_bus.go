@@ -7,8 +7,10 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 const (
@@ -124,8 +126,10 @@ func (b *workspaceBus) deliver(event ReasoningEvent) {
 		select {
 		case ch <- event:
 		default:
-			log.Printf("workspace bus: subscriber buffer full for event_type=%s workspace=%s — event dropped",
-				event.EventType, b.cognitiveWorkspaceID)
+			slog.Warn("subscriber buffer full, event dropped",
+				slog.String(logging.KeyComponent, "blackboard.workspaceBus"),
+				slog.String("event_type", string(event.EventType)),
+				slog.String("cognitive_workspace_id", b.cognitiveWorkspaceID))
 		}
 	}
 }
@@ -11,24 +11,27 @@ import (
 
 	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
 
 // Account domain model — represents a customer/organization account.
 type Account struct {
-	ID          string     `json:"id"`
-	WorkspaceID string     `json:"workspaceId"`
-	Name        string     `json:"name"`
-	Domain      *string    `json:"domain,omitempty"`
-	Industry    *string    `json:"industry,omitempty"`
-	SizeSegment *string    `json:"sizeSegment,omitempty"` // smb|mid|enterprise
-	OwnerID     string     `json:"ownerId"`
-	Address     *string    `json:"address,omitempty"`  // JSON blob
-	Metadata    *string    `json:"metadata,omitempty"` // JSON blob
-	CreatedAt   time.Time  `json:"createdAt"`
-	UpdatedAt   time.Time  `json:"updatedAt"`
-	DeletedAt   *time.Time `json:"deletedAt,omitempty"`
+	ID          string  `json:"id"`
+	WorkspaceID string  `json:"workspaceId"`
+	Name        string  `json:"name"`
+	Domain      *string `json:"domain,omitempty"`
+	Industry    *string `json:"industry,omitempty"`
+	SizeSegment *string `json:"sizeSegment,omitempty"` // smb|mid|enterprise
+	OwnerID     string  `json:"ownerId"`
+	Address     *string `json:"address,omitempty"`  // JSON blob
+	Metadata    *string `json:"metadata,omitempty"` // JSON blob
+	// CustomFields holds the values of the workspace's account custom fields.
+	CustomFields map[string]any `json:"customFields,omitempty"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+	DeletedAt    *time.Time     `json:"deletedAt,omitempty"`
 }
 
 // CreateAccountInput defines required + optional fields for account creation.
@@ -41,6 +44,9 @@ type CreateAccountInput struct {
 	OwnerID     string
 	Address     string // JSON
 	Metadata    string // JSON
+	// CustomFields is validated against the workspace's account
+	// custom_field_def rows; required fields must be present.
+	CustomFields map[string]any
 }
 
 // UpdateAccountInput defines fields that can be updated.
@@ -52,17 +58,29 @@ type UpdateAccountInput struct {
 	OwnerID     string
 	Address     string // JSON
 	Metadata    string // JSON
+	// CustomFields replaces the stored values after validation; nil keeps them.
+	CustomFields map[string]any
 }
 
 // ListAccountsInput defines pagination for account listings.
 type ListAccountsInput struct {
 	Limit  int
 	Offset int
+	// Cursor switches to keyset pagination ordered by created_at, id
+	// (newest first) instead of the name-ordered offset listing. Offset is
+	// ignored; a zero cursor starts from the newest account.
+	Cursor *KeysetCursor
+}
+
+// KeysetCursor is the (created_at, id) position of the last record of a page.
+type KeysetCursor struct {
+	CreatedAt time.Time
+	ID        string
 }
 
 // AccountService provides account operations scoped to a workspace.
 type AccountService struct {
-	db      *sql.DB
+	db      *sqlite.ContextDB
 	querier sqlcgen.Querier
 	bus     eventbus.EventBus
 	audit   auditLogger
@@ -70,18 +88,20 @@ type AccountService struct {
 
 // NewAccountService creates an AccountService instance.
 func NewAccountService(db *sql.DB) *AccountService {
+	cdb := sqlite.NewContextDB(db)
 	return &AccountService{
-		db:      db,
-		querier: sqlcgen.New(db),
+		db:      cdb,
+		querier: sqlcgen.New(cdb),
 		audit:   newCRMAuditService(db),
 	}
 }
 
 // NewAccountServiceWithBus creates an AccountService with CDC event publishing enabled.
 func NewAccountServiceWithBus(db *sql.DB, bus eventbus.EventBus) *AccountService {
+	cdb := sqlite.NewContextDB(db)
 	return &AccountService{
-		db:      db,
-		querier: sqlcgen.New(db),
+		db:      cdb,
+		querier: sqlcgen.New(cdb),
 		bus:     bus,
 		audit:   newCRMAuditService(db),
 	}
@@ -99,25 +119,30 @@ func (s *AccountService) Create(ctx context.Context, input CreateAccountInput) (
 	sizeSegment := nullString(input.SizeSegment)
 	address := nullString(input.Address)
 	metadata := nullString(input.Metadata)
+	customFields, err := encodeCustomFields(ctx, s.db, input.WorkspaceID, timelineEntityAccount, input.CustomFields, true)
+	if err != nil {
+		return nil, err
+	}
 
-	err := s.querier.CreateAccount(ctx, sqlcgen.CreateAccountParams{
-		ID:          accountID,
-		WorkspaceID: input.WorkspaceID,
-		Name:        input.Name,
-		Domain:      domain,
-		Industry:    industry,
-		SizeSegment: sizeSegment,
-		OwnerID:     input.OwnerID,
-		Address:     address,
-		Metadata:    metadata,
-		CreatedAt:   now.Format(time.RFC3339),
-		UpdatedAt:   now.Format(time.RFC3339),
+	err = s.querier.CreateAccount(ctx, sqlcgen.CreateAccountParams{
+		ID:           accountID,
+		WorkspaceID:  input.WorkspaceID,
+		Name:         input.Name,
+		Domain:       domain,
+		Industry:     industry,
+		SizeSegment:  sizeSegment,
+		OwnerID:      input.OwnerID,
+		Address:      address,
+		Metadata:     metadata,
+		CreatedAt:    now.Format(time.RFC3339),
+		UpdatedAt:    now.Format(time.RFC3339),
+		CustomFields: customFields,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create account: %w", err)
 	}
 	logCRMAudit(ctx, s.audit, input.WorkspaceID, input.OwnerID, actionAccountCreated, timelineEntityAccount, accountID)
-	s.publishRecordChanged(knowledge.ChangeTypeCreated, input.WorkspaceID, accountID)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeCreated, input.WorkspaceID, accountID)
 
 	// Return the created account by fetching it
 	return s.Get(ctx, input.WorkspaceID, accountID)
@@ -139,8 +164,17 @@ func (s *AccountService) Get(ctx context.Context, workspaceID, accountID string)
 	return rowToAccount(row), nil
 }
 
+// DeletedAt returns when an account was soft-deleted, nil if it is live, or
+// sql.ErrNoRows if it never existed in the workspace.
+func (s *AccountService) DeletedAt(ctx context.Context, workspaceID, accountID string) (*time.Time, error) {
+	return softDeletedAt(ctx, s.db, "account", workspaceID, accountID)
+}
+
 // List retrieves active accounts in a workspace with pagination.
 func (s *AccountService) List(ctx context.Context, workspaceID string, input ListAccountsInput) ([]*Account, int, error) {
+	if input.Cursor != nil {
+		return s.listAfter(ctx, workspaceID, input)
+	}
 	return listWorkspacePage(
 		ctx,
 		workspaceID,
@@ -159,6 +193,48 @@ func (s *AccountService) List(ctx context.Context, workspaceID string, input Lis
 	)
 }
 
+// listAfter returns the page of accounts that follows input.Cursor.
+func (s *AccountService) listAfter(ctx context.Context, workspaceID string, input ListAccountsInput) ([]*Account, int, error) {
+	total, err := s.querier.CountAccountsByWorkspace(ctx, workspaceID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count accounts: %w", err)
+	}
+
+	query := `
+		SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at, custom_fields
+		FROM account
+		WHERE workspace_id = ? AND deleted_at IS NULL`
+	args := []any{workspaceID}
+	if input.Cursor.ID != "" {
+		query += ` AND (created_at, id) < (?, ?)`
+		args = append(args, input.Cursor.CreatedAt.UTC().Format(time.RFC3339), input.Cursor.ID)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, input.Limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	out := make([]*Account, 0, input.Limit)
+	for rows.Next() {
+		var row sqlcgen.Account
+		if err = rows.Scan(
+			&row.ID, &row.WorkspaceID, &row.Name, &row.Domain, &row.Industry, &row.SizeSegment,
+			&row.OwnerID, &row.Address, &row.Metadata, &row.CreatedAt, &row.UpdatedAt, &row.DeletedAt, &row.CustomFields,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan account: %w", err)
+		}
+		out = append(out, rowToAccount(row))
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate accounts: %w", err)
+	}
+	return out, int(total), nil
+}
+
 // ListByOwner retrieves all accounts owned by a user.
 func (s *AccountService) ListByOwner(ctx context.Context, workspaceID, ownerID string) ([]*Account, error) {
 	rows, err := s.querier.ListAccountsByOwner(ctx, sqlcgen.ListAccountsByOwnerParams{
@@ -181,24 +257,29 @@ func (s *AccountService) Update(ctx context.Context, workspaceID, accountID stri
 	sizeSegment := nullString(input.SizeSegment)
 	address := nullString(input.Address)
 	metadata := nullString(input.Metadata)
+	customFields, err := encodeCustomFields(ctx, s.db, workspaceID, timelineEntityAccount, input.CustomFields, false)
+	if err != nil {
+		return nil, err
+	}
 
-	err := s.querier.UpdateAccount(ctx, sqlcgen.UpdateAccountParams{
-		Name:        input.Name,
-		Domain:      domain,
-		Industry:    industry,
-		SizeSegment: sizeSegment,
-		OwnerID:     input.OwnerID,
-		Address:     address,
-		Metadata:    metadata,
-		UpdatedAt:   now.Format(time.RFC3339),
-		ID:          accountID,
-		WorkspaceID: workspaceID,
+	err = s.querier.UpdateAccount(ctx, sqlcgen.UpdateAccountParams{
+		Name:         input.Name,
+		Domain:       domain,
+		Industry:     industry,
+		SizeSegment:  sizeSegment,
+		OwnerID:      input.OwnerID,
+		Address:      address,
+		Metadata:     metadata,
+		UpdatedAt:    now.Format(time.RFC3339),
+		CustomFields: customFields,
+		ID:           accountID,
+		WorkspaceID:  workspaceID,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("update account: %w", err)
 	}
 	logCRMAudit(ctx, s.audit, workspaceID, input.OwnerID, actionAccountUpdated, timelineEntityAccount, accountID)
-	s.publishRecordChanged(knowledge.ChangeTypeUpdated, workspaceID, accountID)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeUpdated, workspaceID, accountID)
 
 	return s.Get(ctx, workspaceID, accountID)
 }
@@ -220,16 +301,41 @@ func (s *AccountService) Delete(ctx context.Context, workspaceID, accountID stri
 		}); deleteErr != nil {
 		return deleteErr
 	}
-	s.publishRecordChanged(knowledge.ChangeTypeDeleted, workspaceID, accountID)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeDeleted, workspaceID, accountID)
 
 	return nil
 }
 
-func (s *AccountService) publishRecordChanged(changeType knowledge.ChangeType, workspaceID, accountID string) {
+// Restore clears deleted_at on a soft-deleted account and returns it.
+// Returns sql.ErrNoRows if the account does not exist and ErrRecordNotDeleted
+// if it is not deleted.
+func (s *AccountService) Restore(ctx context.Context, workspaceID, accountID string) (*Account, error) {
+	err := restoreSoftDeleted(ctx, s.db, "account", workspaceID, accountID, func(now string) (int64, error) {
+		return s.querier.RestoreAccount(ctx, sqlcgen.RestoreAccountParams{
+			UpdatedAt:   now,
+			ID:          accountID,
+			WorkspaceID: workspaceID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	restored, err := s.Get(ctx, workspaceID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	logCRMAudit(ctx, s.audit, workspaceID, restored.OwnerID, actionAccountRestored, timelineEntityAccount, accountID)
+	// The reindexer drops deleted records, so a restore is announced as a create.
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeCreated, workspaceID, accountID)
+
+	return restored, nil
+}
+
+func (s *AccountService) publishRecordChanged(ctx context.Context, changeType knowledge.ChangeType, workspaceID, accountID string) {
 	if s.bus == nil {
 		return
 	}
-	s.bus.Publish(knowledge.TopicForChangeType(changeType), knowledge.RecordChangedEvent{
+	publishAfterCommit(ctx, s.bus, knowledge.TopicForChangeType(changeType), knowledge.RecordChangedEvent{
 		EntityType:  knowledge.EntityTypeAccount,
 		EntityID:    accountID,
 		WorkspaceID: workspaceID,
@@ -253,18 +359,19 @@ func rowToAccount(row sqlcgen.Account) *Account {
 	updatedAt, _ := time.Parse(time.RFC3339, row.UpdatedAt)
 
 	return &Account{
-		ID:          row.ID,
-		WorkspaceID: row.WorkspaceID,
-		Name:        row.Name,
-		Domain:      row.Domain,
-		Industry:    row.Industry,
-		SizeSegment: row.SizeSegment,
-		OwnerID:     row.OwnerID,
-		Address:     row.Address,
-		Metadata:    row.Metadata,
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
-		DeletedAt:   deletedAtTime,
+		ID:           row.ID,
+		WorkspaceID:  row.WorkspaceID,
+		Name:         row.Name,
+		Domain:       row.Domain,
+		Industry:     row.Industry,
+		SizeSegment:  row.SizeSegment,
+		OwnerID:      row.OwnerID,
+		Address:      row.Address,
+		Metadata:     row.Metadata,
+		CustomFields: decodeCustomFields(row.CustomFields),
+		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
+		DeletedAt:    deletedAtTime,
 	}
 }
 
@@ -0,0 +1,95 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
+)
+
+// MaxAccountBulkDeleteIDs caps the IDs accepted by BulkDelete.
+const MaxAccountBulkDeleteIDs = 500
+
+// ErrAccountBulkDeleteTooLarge is returned when BulkDelete gets more than
+// MaxAccountBulkDeleteIDs IDs.
+var ErrAccountBulkDeleteTooLarge = errors.New("account bulk delete exceeds id limit")
+
+// Statuses of an AccountBulkDeleteResult.
+const (
+	BulkDeleteStatusDeleted = "deleted"
+	BulkDeleteStatusSkipped = "skipped" // already deleted
+	BulkDeleteStatusError   = "error"
+)
+
+// AccountBulkDeleteResult is the outcome for one requested ID. Error is set
+// only with status error.
+type AccountBulkDeleteResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BulkDelete soft-deletes the accounts ids in one transaction and reports
+// one result per ID, in order. Accounts already deleted, including an ID
+// repeated in ids, are skipped and IDs not found in the workspace are
+// errors; neither aborts the batch. Each deleted account gets its own audit
+// event once the transaction commits.
+func (s *AccountService) BulkDelete(ctx context.Context, workspaceID string, ids []string) ([]AccountBulkDeleteResult, error) {
+	if len(ids) > MaxAccountBulkDeleteIDs {
+		return nil, fmt.Errorf("%w: max %d ids", ErrAccountBulkDeleteTooLarge, MaxAccountBulkDeleteIDs)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin bulk delete: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := sqlcgen.New(s.db).WithTx(tx.Tx)
+	now := nowRFC3339()
+	results := make([]AccountBulkDeleteResult, len(ids))
+	owners := make(map[string]string, len(ids)) // deleted account ID -> owner
+	for i, id := range ids {
+		results[i] = AccountBulkDeleteResult{ID: id, Status: BulkDeleteStatusDeleted}
+		var ownerID string
+		var deletedAt sql.NullString
+		scanErr := tx.QueryRowContext(ctx,
+			`SELECT owner_id, deleted_at FROM account WHERE id = ? AND workspace_id = ?`, id, workspaceID,
+		).Scan(&ownerID, &deletedAt)
+		switch {
+		case errors.Is(scanErr, sql.ErrNoRows):
+			results[i].Status, results[i].Error = BulkDeleteStatusError, "account not found"
+			continue
+		case scanErr != nil:
+			return nil, fmt.Errorf("load account %s: %w", id, scanErr)
+		case deletedAt.Valid:
+			results[i].Status = BulkDeleteStatusSkipped
+			continue
+		}
+		if err = qtx.SoftDeleteAccount(ctx, sqlcgen.SoftDeleteAccountParams{
+			DeletedAt:   &now,
+			UpdatedAt:   now,
+			ID:          id,
+			WorkspaceID: workspaceID,
+		}); err != nil {
+			return nil, fmt.Errorf("soft delete account %s: %w", id, err)
+		}
+		owners[id] = ownerID
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit bulk delete: %w", err)
+	}
+
+	for _, res := range results {
+		if res.Status != BulkDeleteStatusDeleted {
+			continue
+		}
+		logCRMAudit(ctx, s.audit, workspaceID, owners[res.ID], actionAccountDeleted, timelineEntityAccount, res.ID)
+		s.publishRecordChanged(ctx, knowledge.ChangeTypeDeleted, workspaceID, res.ID)
+	}
+	return results, nil
+}
@@ -0,0 +1,81 @@
+package crm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestAccountService_BulkDelete_MixedIDs(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewAccountService(db)
+
+	newAccount := func(name string) string {
+		acc, err := svc.Create(ctx, crm.CreateAccountInput{WorkspaceID: wsID, Name: name, OwnerID: ownerID})
+		if err != nil {
+			t.Fatalf("Create(%s) error = %v", name, err)
+		}
+		return acc.ID
+	}
+	live, gone, live2 := newAccount("Acme"), newAccount("Globex"), newAccount("Initech")
+	if err := svc.Delete(ctx, wsID, gone); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	results, err := svc.BulkDelete(ctx, wsID, []string{live, "missing-id", gone, live2, live})
+	if err != nil {
+		t.Fatalf("BulkDelete() error = %v", err)
+	}
+	want := []string{
+		crm.BulkDeleteStatusDeleted,
+		crm.BulkDeleteStatusError,
+		crm.BulkDeleteStatusSkipped,
+		crm.BulkDeleteStatusDeleted,
+		crm.BulkDeleteStatusSkipped,
+	}
+	if len(results) != len(want) {
+		t.Fatalf("BulkDelete() = %d results; want %d", len(results), len(want))
+	}
+	for i, res := range results {
+		if res.Status != want[i] {
+			t.Errorf("results[%d] = %+v; want status %s", i, res, want[i])
+		}
+	}
+	if results[1].Error == "" {
+		t.Errorf("missing id result has no error")
+	}
+
+	for _, id := range []string{live, live2} {
+		if _, err = svc.Get(ctx, wsID, id); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("Get(%s) error = %v; want sql.ErrNoRows", id, err)
+		}
+	}
+
+	// One audit event per deletion: the earlier Delete plus the two bulk ones.
+	var events int
+	if err = db.QueryRow(`SELECT COUNT(*) FROM audit_event WHERE workspace_id = ? AND action = ?`, wsID, "account.deleted").Scan(&events); err != nil {
+		t.Fatalf("count audit events: %v", err)
+	}
+	if events != 3 {
+		t.Errorf("account.deleted audit events = %d; want 3", events)
+	}
+}
+
+func TestAccountService_BulkDelete_TooManyIDs(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+
+	ids := make([]string, crm.MaxAccountBulkDeleteIDs+1)
+	if _, err := crm.NewAccountService(db).BulkDelete(context.Background(), wsID, ids); !errors.Is(err, crm.ErrAccountBulkDeleteTooLarge) {
+		t.Fatalf("BulkDelete() error = %v; want ErrAccountBulkDeleteTooLarge", err)
+	}
+}
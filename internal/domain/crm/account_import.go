@@ -0,0 +1,228 @@
+package crm
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+const (
+	// MaxAccountImportRows caps the data rows accepted by ImportCSV.
+	MaxAccountImportRows = 5000
+	accountImportBatch   = 200
+)
+
+var (
+	// ErrInvalidAccountImport is returned when the file as a whole cannot be
+	// imported (bad header, unknown owner). Row-level problems are reported
+	// in the per-row results instead.
+	ErrInvalidAccountImport = errors.New("invalid account import")
+	// ErrAccountImportTooLarge is returned when the file exceeds MaxAccountImportRows.
+	ErrAccountImportTooLarge = errors.New("account import exceeds row limit")
+)
+
+var validSizeSegments = map[string]struct{}{
+	"smb":        {},
+	"mid":        {},
+	"enterprise": {},
+}
+
+// AccountImportResult is the outcome of one CSV data row. Line is the
+// 1-based line in the file (the header is line 1). Exactly one of ID or
+// Error is set.
+type AccountImportResult struct {
+	Line  int    `json:"line"`
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// accountImportRow is a parsed data row waiting to be inserted.
+type accountImportRow struct {
+	result      int // index into the results slice
+	name        string
+	domain      string
+	industry    string
+	sizeSegment string
+}
+
+// ImportCSV creates accounts from a CSV file with the header
+// name,domain,industry,sizeSegment (only name is required, column order is
+// free). Invalid rows are reported and skipped; valid rows are inserted in
+// batches, one transaction per batch.
+func (s *AccountService) ImportCSV(ctx context.Context, workspaceID, ownerID string, r io.Reader) ([]AccountImportResult, error) {
+	if err := ensureUserExists(ctx, s.db, workspaceID, ownerID); err != nil {
+		return nil, wrapValidationError(ErrInvalidAccountImport, "owner_id is invalid", err)
+	}
+
+	results, rows, err := parseAccountCSV(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(rows); start += accountImportBatch {
+		end := min(start+accountImportBatch, len(rows))
+		if err = s.insertImportBatch(ctx, workspaceID, ownerID, rows[start:end], results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func parseAccountCSV(r io.Reader) ([]AccountImportResult, []accountImportRow, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, wrapValidationError(ErrInvalidAccountImport, "missing header row", err)
+	}
+	cols, err := accountImportColumns(header)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []AccountImportResult
+	var rows []accountImportRow
+	for line := 2; ; line++ {
+		record, readErr := cr.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if len(results) == MaxAccountImportRows {
+			return nil, nil, fmt.Errorf("%w: max %d rows", ErrAccountImportTooLarge, MaxAccountImportRows)
+		}
+		results = append(results, AccountImportResult{Line: line})
+		idx := len(results) - 1
+
+		var parseErr *csv.ParseError
+		if errors.As(readErr, &parseErr) {
+			results[idx].Error = parseErr.Err.Error()
+			continue
+		}
+		if readErr != nil {
+			return nil, nil, fmt.Errorf("read csv: %w", readErr)
+		}
+
+		row := accountImportRow{
+			result:      idx,
+			name:        cols.value(record, "name"),
+			domain:      cols.value(record, "domain"),
+			industry:    cols.value(record, "industry"),
+			sizeSegment: strings.ToLower(cols.value(record, "sizesegment")),
+		}
+		if msg := validateAccountImportRow(row); msg != "" {
+			results[idx].Error = msg
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return results, rows, nil
+}
+
+// accountImportCols maps lower-cased header names to column positions.
+type accountImportCols map[string]int
+
+func accountImportColumns(header []string) (accountImportCols, error) {
+	cols := accountImportCols{}
+	for i, h := range header {
+		name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		switch name {
+		case "name", "domain", "industry", "sizesegment":
+			cols[name] = i
+		default:
+			return nil, wrapValidationError(ErrInvalidAccountImport, fmt.Sprintf("unknown column %q", h), nil)
+		}
+	}
+	if _, ok := cols["name"]; !ok {
+		return nil, wrapValidationError(ErrInvalidAccountImport, "name column is required", nil)
+	}
+	return cols, nil
+}
+
+func (c accountImportCols) value(record []string, col string) string {
+	i, ok := c[col]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func validateAccountImportRow(row accountImportRow) string {
+	if row.name == "" {
+		return "name is required"
+	}
+	if row.sizeSegment != "" && !isValidEnum(row.sizeSegment, validSizeSegments) {
+		return "sizeSegment must be smb, mid or enterprise"
+	}
+	return ""
+}
+
+// insertImportBatch inserts one batch in a transaction. A failing insert only
+// marks its own row; SQLite keeps the rest of the transaction intact. Rows
+// carry no custom fields, so they are checked against the workspace's account
+// field definitions like Create does: with a required field defined, every
+// row fails with that error.
+func (s *AccountService) insertImportBatch(
+	ctx context.Context,
+	workspaceID, ownerID string,
+	batch []accountImportRow,
+	results []AccountImportResult,
+) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin import batch: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	qtx := sqlcgen.New(s.db).WithTx(tx.Tx)
+	now := time.Now().UTC().Format(time.RFC3339)
+	created := make([]string, 0, len(batch))
+	for _, row := range batch {
+		customFields, cfErr := encodeCustomFields(ctx, tx.Tx, workspaceID, timelineEntityAccount, nil, true)
+		if errors.Is(cfErr, ErrInvalidCustomFields) {
+			results[row.result].Error = cfErr.Error()
+			continue
+		}
+		if cfErr != nil {
+			return cfErr
+		}
+		id := uuid.NewV7().String()
+		insErr := qtx.CreateAccount(ctx, sqlcgen.CreateAccountParams{
+			ID:           id,
+			WorkspaceID:  workspaceID,
+			Name:         row.name,
+			Domain:       nullString(row.domain),
+			Industry:     nullString(row.industry),
+			SizeSegment:  nullString(row.sizeSegment),
+			OwnerID:      ownerID,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+			CustomFields: customFields,
+		})
+		if insErr != nil {
+			results[row.result].Error = insErr.Error()
+			continue
+		}
+		results[row.result].ID = id
+		created = append(created, id)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit import batch: %w", err)
+	}
+
+	for _, id := range created {
+		logCRMAudit(ctx, s.audit, workspaceID, ownerID, actionAccountCreated, timelineEntityAccount, id)
+		s.publishRecordChanged(ctx, knowledge.ChangeTypeCreated, workspaceID, id)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package crm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestAccountService_ImportCSV_PerRowResults(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewAccountService(db)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+
+	csvData := "name,domain,industry,sizeSegment\n" +
+		"Acme,acme.com,Tech,mid\n" +
+		",missing.com,Tech,smb\n" +
+		"Globex,globex.com,Energy,huge\n" +
+		"Initech,,,\n"
+
+	results, err := svc.ImportCSV(context.Background(), wsID, ownerID, strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("ImportCSV() error = %v", err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("len(results) = %d; want 4", len(results))
+	}
+	for i, wantOK := range []bool{true, false, false, true} {
+		res := results[i]
+		if res.Line != i+2 {
+			t.Errorf("results[%d].Line = %d; want %d", i, res.Line, i+2)
+		}
+		if wantOK && (res.ID == "" || res.Error != "") {
+			t.Errorf("results[%d] = %+v; want created", i, res)
+		}
+		if !wantOK && (res.ID != "" || res.Error == "") {
+			t.Errorf("results[%d] = %+v; want error", i, res)
+		}
+	}
+
+	acc, err := svc.Get(context.Background(), wsID, results[0].ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if acc.Name != "Acme" || acc.Domain == nil || *acc.Domain != "acme.com" || acc.OwnerID != ownerID {
+		t.Errorf("imported account = %+v", acc)
+	}
+}
+
+func TestAccountService_ImportCSV_SpansBatches(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewAccountService(db)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+
+	var b strings.Builder
+	b.WriteString("sizeSegment,name\n")
+	for i := 0; i < 450; i++ {
+		fmt.Fprintf(&b, "smb,Account %d\n", i)
+	}
+
+	results, err := svc.ImportCSV(context.Background(), wsID, ownerID, strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("ImportCSV() error = %v", err)
+	}
+	_, total, err := svc.List(context.Background(), wsID, crm.ListAccountsInput{Limit: 1})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(results) != 450 || total != 450 {
+		t.Fatalf("results = %d, total = %d; want 450 each", len(results), total)
+	}
+}
+
+func TestAccountService_ImportCSV_FileErrors(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewAccountService(db)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+
+	tooMany := "name\n" + strings.Repeat("x\n", crm.MaxAccountImportRows+1)
+	tests := []struct {
+		name    string
+		ownerID string
+		data    string
+		wantErr error
+	}{
+		{"empty file", ownerID, "", crm.ErrInvalidAccountImport},
+		{"missing name column", ownerID, "domain\nacme.com\n", crm.ErrInvalidAccountImport},
+		{"unknown column", ownerID, "name,revenue\nAcme,1\n", crm.ErrInvalidAccountImport},
+		{"unknown owner", "nobody", "name\nAcme\n", crm.ErrInvalidAccountImport},
+		{"too many rows", ownerID, tooMany, crm.ErrAccountImportTooLarge},
+	}
+	for _, tc := range tests {
+		_, err := svc.ImportCSV(context.Background(), wsID, tc.ownerID, strings.NewReader(tc.data))
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: error = %v; want %v", tc.name, err, tc.wantErr)
+		}
+	}
+
+	_, total, _ := svc.List(context.Background(), wsID, crm.ListAccountsInput{Limit: 1})
+	if total != 0 {
+		t.Errorf("total = %d; want 0 after rejected files", total)
+	}
+}
+
+func TestAccountService_ImportCSV_RequiredCustomFieldFailsRows(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	svc := crm.NewAccountService(db)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+
+	if _, err := crm.NewCustomFieldService(db).Create(ctx, crm.CreateCustomFieldDefInput{
+		WorkspaceID: wsID, EntityType: "account", Name: "tier", Type: crm.CustomFieldTypeString, Required: true,
+	}); err != nil {
+		t.Fatalf("define tier: %v", err)
+	}
+
+	results, err := svc.ImportCSV(ctx, wsID, ownerID, strings.NewReader("name\nAcme\nGlobex\n"))
+	if err != nil {
+		t.Fatalf("ImportCSV() error = %v", err)
+	}
+	for i, res := range results {
+		if res.ID != "" || !strings.Contains(res.Error, "tier is required") {
+			t.Errorf("results[%d] = %+v; want a tier is required error", i, res)
+		}
+	}
+	var count int
+	if err = db.QueryRow(`SELECT COUNT(*) FROM account WHERE workspace_id = ?`, wsID).Scan(&count); err != nil {
+		t.Fatalf("count accounts: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("imported %d accounts; want 0", count)
+	}
+}
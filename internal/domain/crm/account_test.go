@@ -5,6 +5,7 @@ package crm_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"sync/atomic"
 	"testing"
@@ -241,6 +242,75 @@ func TestAccountService_Delete(t *testing.T) {
 	}
 }
 
+// TestAccountService_Restore clears deleted_at and rejects live or unknown accounts.
+func TestAccountService_DeletedAt(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewAccountService(db)
+
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	created, _ := svc.Create(context.Background(), crm.CreateAccountInput{
+		WorkspaceID: wsID,
+		Name:        "To Delete",
+		OwnerID:     ownerID,
+	})
+
+	if deletedAt, err := svc.DeletedAt(context.Background(), wsID, created.ID); err != nil || deletedAt != nil {
+		t.Fatalf("DeletedAt(live) = %v, %v; want nil, nil", deletedAt, err)
+	}
+	if _, err := svc.DeletedAt(context.Background(), wsID, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("DeletedAt(missing) error = %v; want sql.ErrNoRows", err)
+	}
+	if err := svc.Delete(context.Background(), wsID, created.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	deletedAt, err := svc.DeletedAt(context.Background(), wsID, created.ID)
+	if err != nil || deletedAt == nil || deletedAt.IsZero() {
+		t.Fatalf("DeletedAt(deleted) = %v, %v; want timestamp", deletedAt, err)
+	}
+}
+
+func TestAccountService_Restore(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewAccountService(db)
+
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	created, _ := svc.Create(context.Background(), crm.CreateAccountInput{
+		WorkspaceID: wsID,
+		Name:        "To Restore",
+		OwnerID:     ownerID,
+	})
+
+	if _, err := svc.Restore(context.Background(), wsID, created.ID); !errors.Is(err, crm.ErrRecordNotDeleted) {
+		t.Fatalf("Restore(live) error = %v; want ErrRecordNotDeleted", err)
+	}
+	if _, err := svc.Restore(context.Background(), wsID, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Restore(missing) error = %v; want sql.ErrNoRows", err)
+	}
+
+	if err := svc.Delete(context.Background(), wsID, created.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	restored, err := svc.Restore(context.Background(), wsID, created.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v; want nil", err)
+	}
+	if restored.DeletedAt != nil || restored.Name != "To Restore" {
+		t.Errorf("Restore() = %+v; want live account", restored)
+	}
+
+	var audits int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM audit_event WHERE action = 'account.restored' AND entity_id = ?`, created.ID).Scan(&audits); err != nil {
+		t.Fatalf("count audit events: %v", err)
+	}
+	if audits != 1 {
+		t.Errorf("account.restored audit events = %d; want 1", audits)
+	}
+}
+
 // TestAccountService_ListByOwner returns accounts owned by a user.
 func TestAccountService_ListByOwner(t *testing.T) {
 	t.Parallel()
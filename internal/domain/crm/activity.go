@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
@@ -59,31 +60,38 @@ type UpdateActivityInput struct {
 	Metadata     string
 }
 
+// ListActivitiesInput pages activities; set filters are ANDed together.
 type ListActivitiesInput struct {
-	Limit  int
-	Offset int
+	Limit      int
+	Offset     int
+	EntityType string
+	EntityID   string
+	OwnerID    string
+	Status     string
 }
 
 type ActivityService struct {
-	db      *sql.DB
+	db      *sqlite.ContextDB
 	querier sqlcgen.Querier
 	bus     eventbus.EventBus
 }
 
 func NewActivityService(db *sql.DB) *ActivityService {
-	return &ActivityService{db: db, querier: sqlcgen.New(db)}
+	cdb := sqlite.NewContextDB(db)
+	return &ActivityService{db: cdb, querier: sqlcgen.New(cdb)}
 }
 
 func NewActivityServiceWithBus(db *sql.DB, bus eventbus.EventBus) *ActivityService {
-	return &ActivityService{db: db, querier: sqlcgen.New(db), bus: bus}
+	cdb := sqlite.NewContextDB(db)
+	return &ActivityService{db: cdb, querier: sqlcgen.New(cdb), bus: bus}
 }
 
 func (s *ActivityService) Create(ctx context.Context, input CreateActivityInput) (*Activity, error) {
 	id := uuid.NewV7().String()
 	now := nowRFC3339()
-	status := input.Status
-	if status == "" {
-		status = "pending"
+	status := firstNonEmpty(input.Status, activityStatusPending)
+	if err := validateActivityValues(input.ActivityType, input.EntityType, status); err != nil {
+		return nil, err
 	}
 
 	err := s.querier.CreateActivity(ctx, sqlcgen.CreateActivityParams{
@@ -114,7 +122,7 @@ func (s *ActivityService) Create(ctx context.Context, input CreateActivityInput)
 	if getErr != nil {
 		return nil, getErr
 	}
-	publishActivityCreated(s.bus, activity)
+	publishActivityCreated(ctx, s.bus, activity)
 	return activity, nil
 }
 
@@ -130,6 +138,9 @@ func (s *ActivityService) Get(ctx context.Context, workspaceID, activityID strin
 }
 
 func (s *ActivityService) List(ctx context.Context, workspaceID string, input ListActivitiesInput) ([]*Activity, int, error) {
+	if input.EntityType != "" || input.EntityID != "" || input.OwnerID != "" || input.Status != "" {
+		return s.listFiltered(ctx, workspaceID, input)
+	}
 	return listWorkspacePage(
 		ctx,
 		workspaceID,
@@ -148,8 +159,25 @@ func (s *ActivityService) List(ctx context.Context, workspaceID string, input Li
 	)
 }
 
+// Update replaces the activity's fields. An empty status keeps the current
+// one; moving to completed stamps completed_at unless the input sets it.
 func (s *ActivityService) Update(ctx context.Context, workspaceID, activityID string, input UpdateActivityInput) (*Activity, error) {
-	err := s.querier.UpdateActivity(ctx, sqlcgen.UpdateActivityParams{
+	existing, err := s.Get(ctx, workspaceID, activityID)
+	if err != nil {
+		return nil, err
+	}
+	input.Status = firstNonEmpty(input.Status, existing.Status)
+	if err = validateActivityValues(input.ActivityType, input.EntityType, input.Status); err != nil {
+		return nil, err
+	}
+	if err = validateActivityTransition(existing.Status, input.Status); err != nil {
+		return nil, err
+	}
+	if input.Status == activityStatusCompleted && input.CompletedAt == "" {
+		input.CompletedAt = firstNonEmpty(formatOptionalTime(existing.CompletedAt), nowRFC3339())
+	}
+
+	err = s.querier.UpdateActivity(ctx, sqlcgen.UpdateActivityParams{
 		ActivityType: input.ActivityType,
 		EntityType:   input.EntityType,
 		EntityID:     input.EntityID,
@@ -175,6 +203,30 @@ func (s *ActivityService) Update(ctx context.Context, workspaceID, activityID st
 	return s.Get(ctx, workspaceID, activityID)
 }
 
+// Complete marks a pending activity completed. Completing a completed
+// activity is a no-op; a cancelled one returns ErrInvalidActivityTransition.
+func (s *ActivityService) Complete(ctx context.Context, workspaceID, activityID string) (*Activity, error) {
+	existing, err := s.Get(ctx, workspaceID, activityID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status == activityStatusCompleted {
+		return existing, nil
+	}
+	return s.Update(ctx, workspaceID, activityID, UpdateActivityInput{
+		ActivityType: existing.ActivityType,
+		EntityType:   existing.EntityType,
+		EntityID:     existing.EntityID,
+		OwnerID:      existing.OwnerID,
+		AssignedTo:   stringValue(existing.AssignedTo),
+		Subject:      existing.Subject,
+		Body:         stringValue(existing.Body),
+		Status:       activityStatusCompleted,
+		DueAt:        formatOptionalTime(existing.DueAt),
+		Metadata:     stringValue(existing.Metadata),
+	})
+}
+
 func (s *ActivityService) Delete(ctx context.Context, workspaceID, activityID string) error {
 	err := s.querier.DeleteActivity(ctx, sqlcgen.DeleteActivityParams{ID: activityID, WorkspaceID: workspaceID})
 	if err != nil {
@@ -186,6 +238,62 @@ func (s *ActivityService) Delete(ctx context.Context, workspaceID, activityID st
 	return nil
 }
 
+func (s *ActivityService) listFiltered(ctx context.Context, workspaceID string, input ListActivitiesInput) ([]*Activity, int, error) {
+	where := "workspace_id = ?"
+	args := []any{workspaceID}
+	for _, f := range []struct{ column, value string }{
+		{"entity_type", input.EntityType},
+		{"entity_id", input.EntityID},
+		{"owner_id", input.OwnerID},
+		{"status", input.Status},
+	} {
+		if f.value != "" {
+			where += " AND " + f.column + " = ?"
+			args = append(args, f.value)
+		}
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM activity WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count activities: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, workspace_id, activity_type, entity_type, entity_id, owner_id, assigned_to,
+		       subject, body, status, due_at, completed_at, metadata, created_at, updated_at
+		FROM activity
+		WHERE `+where+`
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?`, append(args, input.Limit, input.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list activities: %w", err)
+	}
+	defer rows.Close()
+
+	items := []*Activity{}
+	for rows.Next() {
+		var row sqlcgen.Activity
+		if err = rows.Scan(
+			&row.ID, &row.WorkspaceID, &row.ActivityType, &row.EntityType, &row.EntityID, &row.OwnerID, &row.AssignedTo,
+			&row.Subject, &row.Body, &row.Status, &row.DueAt, &row.CompletedAt, &row.Metadata, &row.CreatedAt, &row.UpdatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan activity: %w", err)
+		}
+		items = append(items, rowToActivity(row))
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list activities: %w", err)
+	}
+	return items, total, nil
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
 func rowToActivity(row sqlcgen.Activity) *Activity {
 	createdAt := parseRFC3339Time(row.CreatedAt)
 	updatedAt := parseRFC3339Time(row.UpdatedAt)
@@ -3,6 +3,7 @@ package crm_test
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -132,3 +133,72 @@ func TestActivityService_Delete_DeletesRowAndWritesTimelineEvent(t *testing.T) {
 		t.Fatalf("expected row to be deleted")
 	}
 }
+
+func TestActivityService_ValidationTransitionsAndFilters(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewActivityService(db)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	ctx := context.Background()
+
+	if _, err := svc.Create(ctx, crm.CreateActivityInput{
+		WorkspaceID: wsID, ActivityType: "meeting", EntityType: "account", EntityID: "acc-1", OwnerID: ownerID, Subject: "x",
+	}); !errors.Is(err, crm.ErrInvalidActivityInput) {
+		t.Fatalf("Create(invalid type) error = %v; want ErrInvalidActivityInput", err)
+	}
+
+	create := func(entityID, subject string) *crm.Activity {
+		t.Helper()
+		act, err := svc.Create(ctx, crm.CreateActivityInput{
+			WorkspaceID: wsID, ActivityType: "task", EntityType: "account", EntityID: entityID, OwnerID: ownerID, Subject: subject,
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		return act
+	}
+	first := create("acc-1", "First")
+	second := create("acc-1", "Second")
+	create("acc-2", "Other account")
+
+	done, err := svc.Complete(ctx, wsID, first.ID)
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if done.Status != "completed" || done.CompletedAt == nil {
+		t.Fatalf("expected completed activity with completed_at, got %+v", done)
+	}
+
+	_, err = svc.Update(ctx, wsID, first.ID, crm.UpdateActivityInput{
+		ActivityType: "task", EntityType: "account", EntityID: "acc-1", OwnerID: ownerID, Subject: "First", Status: "pending",
+	})
+	if !errors.Is(err, crm.ErrInvalidActivityTransition) {
+		t.Fatalf("reopen error = %v; want ErrInvalidActivityTransition", err)
+	}
+
+	if _, err = svc.Update(ctx, wsID, second.ID, crm.UpdateActivityInput{
+		ActivityType: "task", EntityType: "account", EntityID: "acc-1", OwnerID: ownerID, Subject: "Second", Status: "cancelled",
+	}); err != nil {
+		t.Fatalf("cancel error = %v", err)
+	}
+	if _, err = svc.Complete(ctx, wsID, second.ID); !errors.Is(err, crm.ErrInvalidActivityTransition) {
+		t.Fatalf("Complete(cancelled) error = %v; want ErrInvalidActivityTransition", err)
+	}
+
+	items, total, err := svc.List(ctx, wsID, crm.ListActivitiesInput{Limit: 10, EntityType: "account", EntityID: "acc-1", Status: "completed"})
+	if err != nil {
+		t.Fatalf("List(filtered) error = %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].ID != first.ID {
+		t.Fatalf("expected only the completed acc-1 task, got total=%d items=%+v", total, items)
+	}
+
+	_, total, err = svc.List(ctx, wsID, crm.ListActivitiesInput{Limit: 10, OwnerID: ownerID})
+	if err != nil {
+		t.Fatalf("List(owner) error = %v", err)
+	}
+	if total != 3 {
+		t.Fatalf("expected 3 activities for owner, got %d", total)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
@@ -45,12 +46,13 @@ type ListAttachmentsInput struct {
 }
 
 type AttachmentService struct {
-	db      *sql.DB
+	db      *sqlite.ContextDB
 	querier sqlcgen.Querier
 }
 
 func NewAttachmentService(db *sql.DB) *AttachmentService {
-	return &AttachmentService{db: db, querier: sqlcgen.New(db)}
+	cdb := sqlite.NewContextDB(db)
+	return &AttachmentService{db: cdb, querier: sqlcgen.New(cdb)}
 }
 
 func (s *AttachmentService) Create(ctx context.Context, input CreateAttachmentInput) (*Attachment, error) {
@@ -34,6 +34,8 @@ const (
 	actionDealCreated    = "deal.created"
 	actionDealUpdated    = "deal.updated"
 	actionDealDeleted    = "deal.deleted"
+	actionDealWon        = "deal.won"
+	actionDealLost       = "deal.lost"
 	actionCaseCreated    = "case.created"
 	actionCaseUpdated    = "case.updated"
 	actionCaseDeleted    = "case.deleted"
@@ -42,6 +44,22 @@ const (
 	actionNoteDeleted    = "note.deleted"
 )
 
+// Restore actions reverse a soft delete.
+const (
+	actionAccountRestored = "account.restored"
+	actionLeadRestored    = "lead.restored"
+	actionCaseRestored    = "case.restored"
+)
+
+// actionCaseReopened records a resolved or closed case moving back to open.
+const actionCaseReopened = "case.reopened"
+
+// actionCaseEscalated records a case moving up one priority through Escalate.
+const actionCaseEscalated = "case.escalated"
+
+// actionCaseAssigned records a case owner change made through Assign or AutoAssign.
+const actionCaseAssigned = "case.assigned"
+
 func newCRMAuditService(db *sql.DB) *domainaudit.AuditService {
 	return domainaudit.NewAuditService(db)
 }
@@ -0,0 +1,297 @@
+package crm
+
+import (
+	"context"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
+)
+
+// Audited decorator actions — recorded with before/after snapshots.
+const (
+	actionAuditedCreateAccount  = "create_account"
+	actionAuditedUpdateAccount  = "update_account"
+	actionAuditedDeleteAccount  = "delete_account"
+	actionAuditedCreateLead     = "create_lead"
+	actionAuditedUpdateLead     = "update_lead"
+	actionAuditedDeleteLead     = "delete_lead"
+	actionAuditedCreateCase     = "create_case"
+	actionAuditedUpdateCase     = "update_case"
+	actionAuditedDeleteCase     = "delete_case"
+	actionAuditedCreatePipeline = "create_pipeline"
+	actionAuditedUpdatePipeline = "update_pipeline"
+	actionAuditedDeletePipeline = "delete_pipeline"
+
+	auditEntityPipeline = "pipeline"
+)
+
+// AuditedAccountService decorates AccountService so every mutation emits an
+// audit event with before/after values. Read methods pass through unchanged.
+type AuditedAccountService struct {
+	*AccountService
+	audit auditLogger
+}
+
+// NewAuditedAccountService wraps svc with detailed mutation auditing.
+// The wrapped copy keeps its built-in audit events except those of the
+// Create, Update and Delete calls made here, which would duplicate ours.
+func NewAuditedAccountService(svc *AccountService, auditSvc *domainaudit.AuditService) *AuditedAccountService {
+	inner := *svc
+	inner.audit = newDecoratedAuditLogger(inner.audit)
+	return &AuditedAccountService{AccountService: &inner, audit: auditSvc}
+}
+
+// Create inserts the account and records a create_account event.
+func (s *AuditedAccountService) Create(ctx context.Context, input CreateAccountInput) (*Account, error) {
+	out, err := s.AccountService.Create(withSuppressedAudit(ctx, actionAccountCreated), input)
+	if err != nil {
+		return nil, err
+	}
+	logAuditedMutation(ctx, s.audit, input.WorkspaceID, actionAuditedCreateAccount, timelineEntityAccount, out.ID, nil, out)
+	return out, nil
+}
+
+// Update modifies the account and records an update_account event.
+func (s *AuditedAccountService) Update(ctx context.Context, workspaceID, accountID string, input UpdateAccountInput) (*Account, error) {
+	before, err := s.AccountService.Get(ctx, workspaceID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.AccountService.Update(withSuppressedAudit(ctx, actionAccountUpdated), workspaceID, accountID, input)
+	if err != nil {
+		return nil, err
+	}
+	logAuditedMutation(ctx, s.audit, workspaceID, actionAuditedUpdateAccount, timelineEntityAccount, accountID, before, out)
+	return out, nil
+}
+
+// Delete soft-deletes the account and records a delete_account event.
+func (s *AuditedAccountService) Delete(ctx context.Context, workspaceID, accountID string) error {
+	before, err := s.AccountService.Get(ctx, workspaceID, accountID)
+	if err != nil {
+		return err
+	}
+	if err = s.AccountService.Delete(withSuppressedAudit(ctx, actionAccountDeleted), workspaceID, accountID); err != nil {
+		return err
+	}
+	logAuditedMutation(ctx, s.audit, workspaceID, actionAuditedDeleteAccount, timelineEntityAccount, accountID, before, nil)
+	return nil
+}
+
+// AuditedLeadService decorates LeadService with detailed mutation auditing.
+type AuditedLeadService struct {
+	*LeadService
+	audit auditLogger
+}
+
+// NewAuditedLeadService wraps svc with detailed mutation auditing.
+func NewAuditedLeadService(svc *LeadService, auditSvc *domainaudit.AuditService) *AuditedLeadService {
+	inner := *svc
+	inner.audit = newDecoratedAuditLogger(inner.audit)
+	return &AuditedLeadService{LeadService: &inner, audit: auditSvc}
+}
+
+// Create inserts the lead and records a create_lead event.
+func (s *AuditedLeadService) Create(ctx context.Context, input CreateLeadInput) (*Lead, error) {
+	out, err := s.LeadService.Create(withSuppressedAudit(ctx, actionLeadCreated), input)
+	if err != nil {
+		return nil, err
+	}
+	logAuditedMutation(ctx, s.audit, input.WorkspaceID, actionAuditedCreateLead, timelineEntityLead, out.ID, nil, out)
+	return out, nil
+}
+
+// Update modifies the lead and records an update_lead event.
+func (s *AuditedLeadService) Update(ctx context.Context, workspaceID, leadID string, input UpdateLeadInput) (*Lead, error) {
+	before, err := s.LeadService.Get(ctx, workspaceID, leadID)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.LeadService.Update(withSuppressedAudit(ctx, actionLeadUpdated), workspaceID, leadID, input)
+	if err != nil {
+		return nil, err
+	}
+	logAuditedMutation(ctx, s.audit, workspaceID, actionAuditedUpdateLead, timelineEntityLead, leadID, before, out)
+	return out, nil
+}
+
+// Delete soft-deletes the lead and records a delete_lead event.
+func (s *AuditedLeadService) Delete(ctx context.Context, workspaceID, leadID string) error {
+	before, err := s.LeadService.Get(ctx, workspaceID, leadID)
+	if err != nil {
+		return err
+	}
+	if err = s.LeadService.Delete(withSuppressedAudit(ctx, actionLeadDeleted), workspaceID, leadID); err != nil {
+		return err
+	}
+	logAuditedMutation(ctx, s.audit, workspaceID, actionAuditedDeleteLead, timelineEntityLead, leadID, before, nil)
+	return nil
+}
+
+// AuditedCaseService decorates CaseService with detailed mutation auditing.
+type AuditedCaseService struct {
+	*CaseService
+	audit auditLogger
+}
+
+// NewAuditedCaseService wraps svc with detailed mutation auditing.
+func NewAuditedCaseService(svc *CaseService, auditSvc *domainaudit.AuditService) *AuditedCaseService {
+	inner := *svc
+	inner.audit = newDecoratedAuditLogger(inner.audit)
+	return &AuditedCaseService{CaseService: &inner, audit: auditSvc}
+}
+
+// Create inserts the case and records a create_case event.
+func (s *AuditedCaseService) Create(ctx context.Context, input CreateCaseInput) (*CaseTicket, error) {
+	out, err := s.CaseService.Create(withSuppressedAudit(ctx, actionCaseCreated), input)
+	if err != nil {
+		return nil, err
+	}
+	logAuditedMutation(ctx, s.audit, input.WorkspaceID, actionAuditedCreateCase, timelineEntityCase, out.ID, nil, out)
+	return out, nil
+}
+
+// Update modifies the case and records an update_case event.
+func (s *AuditedCaseService) Update(ctx context.Context, workspaceID, caseID string, input UpdateCaseInput) (*CaseTicket, error) {
+	before, err := s.CaseService.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.CaseService.Update(withSuppressedAudit(ctx, actionCaseUpdated), workspaceID, caseID, input)
+	if err != nil {
+		return nil, err
+	}
+	logAuditedMutation(ctx, s.audit, workspaceID, actionAuditedUpdateCase, timelineEntityCase, caseID, before, out)
+	return out, nil
+}
+
+// Delete soft-deletes the case and records a delete_case event.
+func (s *AuditedCaseService) Delete(ctx context.Context, workspaceID, caseID string) error {
+	before, err := s.CaseService.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return err
+	}
+	if err = s.CaseService.Delete(withSuppressedAudit(ctx, actionCaseDeleted), workspaceID, caseID); err != nil {
+		return err
+	}
+	logAuditedMutation(ctx, s.audit, workspaceID, actionAuditedDeleteCase, timelineEntityCase, caseID, before, nil)
+	return nil
+}
+
+// AuditedPipelineService decorates PipelineService with detailed mutation auditing.
+type AuditedPipelineService struct {
+	*PipelineService
+	audit auditLogger
+}
+
+// NewAuditedPipelineService wraps svc with detailed mutation auditing.
+// PipelineService has no built-in audit call, so svc is used as-is.
+func NewAuditedPipelineService(svc *PipelineService, auditSvc *domainaudit.AuditService) *AuditedPipelineService {
+	return &AuditedPipelineService{PipelineService: svc, audit: auditSvc}
+}
+
+// Create inserts the pipeline and records a create_pipeline event.
+func (s *AuditedPipelineService) Create(ctx context.Context, input CreatePipelineInput) (*Pipeline, error) {
+	out, err := s.PipelineService.Create(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	logAuditedMutation(ctx, s.audit, input.WorkspaceID, actionAuditedCreatePipeline, auditEntityPipeline, out.ID, nil, out)
+	return out, nil
+}
+
+// Update modifies the pipeline and records an update_pipeline event.
+func (s *AuditedPipelineService) Update(ctx context.Context, workspaceID, pipelineID string, input UpdatePipelineInput) (*Pipeline, error) {
+	before, err := s.PipelineService.Get(ctx, workspaceID, pipelineID)
+	if err != nil {
+		return nil, err
+	}
+	out, err := s.PipelineService.Update(ctx, workspaceID, pipelineID, input)
+	if err != nil {
+		return nil, err
+	}
+	logAuditedMutation(ctx, s.audit, workspaceID, actionAuditedUpdatePipeline, auditEntityPipeline, pipelineID, before, out)
+	return out, nil
+}
+
+// Delete removes the pipeline and records a delete_pipeline event.
+func (s *AuditedPipelineService) Delete(ctx context.Context, workspaceID, pipelineID string) error {
+	before, err := s.PipelineService.Get(ctx, workspaceID, pipelineID)
+	if err != nil {
+		return err
+	}
+	if err = s.PipelineService.Delete(ctx, workspaceID, pipelineID); err != nil {
+		return err
+	}
+	logAuditedMutation(ctx, s.audit, workspaceID, actionAuditedDeletePipeline, auditEntityPipeline, pipelineID, before, nil)
+	return nil
+}
+
+type suppressedAuditKey struct{}
+
+// withSuppressedAudit marks ctx so the wrapped service skips its own event
+// for action; the decorator records that mutation with before/after values.
+func withSuppressedAudit(ctx context.Context, action string) context.Context {
+	return context.WithValue(ctx, suppressedAuditKey{}, action)
+}
+
+// decoratedAuditLogger is the audit logger of a service wrapped by an
+// audited decorator. It drops only the event marked by withSuppressedAudit.
+type decoratedAuditLogger struct {
+	auditLogger
+}
+
+func newDecoratedAuditLogger(inner auditLogger) auditLogger {
+	if inner == nil {
+		return nil
+	}
+	return decoratedAuditLogger{auditLogger: inner}
+}
+
+func (l decoratedAuditLogger) LogWithDetails(
+	ctx context.Context,
+	workspaceID string,
+	actorID string,
+	actorType domainaudit.ActorType,
+	action string,
+	entityType *string,
+	entityID *string,
+	details *domainaudit.EventDetails,
+	outcome domainaudit.Outcome,
+) error {
+	if suppressed, _ := ctx.Value(suppressedAuditKey{}).(string); suppressed == action {
+		return nil
+	}
+	return l.auditLogger.LogWithDetails(ctx, workspaceID, actorID, actorType, action, entityType, entityID, details, outcome)
+}
+
+// logAuditedMutation records a mutation with before/after snapshots. The actor
+// is taken from ctxkeys.UserID; requests without a user are attributed to system.
+func logAuditedMutation(
+	ctx context.Context,
+	auditSvc auditLogger,
+	workspaceID, action, entityType, entityID string,
+	before, after any,
+) {
+	if auditSvc == nil {
+		return
+	}
+
+	actorID, _ := ctx.Value(ctxkeys.UserID).(string)
+	actorType := domainaudit.ActorTypeSystem
+	if actorID != "" {
+		actorType = domainaudit.ActorTypeUser
+	}
+
+	_ = auditSvc.LogWithDetails(
+		ctx,
+		workspaceID,
+		resolveAuditActorID(actorID),
+		actorType,
+		action,
+		&entityType,
+		&entityID,
+		&domainaudit.EventDetails{OldValue: before, NewValue: after},
+		domainaudit.OutcomeSuccess,
+	)
+}
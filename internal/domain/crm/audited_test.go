@@ -0,0 +1,127 @@
+// Traces: FR-070
+package crm_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestAuditedAccountService_LogsBeforeAfterWithContextActor(t *testing.T) {
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	actorID := createUser(t, db, wsID)
+	ctx := context.WithValue(context.Background(), ctxkeys.UserID, actorID)
+
+	svc := crm.NewAuditedAccountService(crm.NewAccountService(db), domainaudit.NewAuditService(db))
+
+	account, err := svc.Create(ctx, crm.CreateAccountInput{WorkspaceID: wsID, Name: "Acme", OwnerID: ownerID})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if _, err = svc.Update(ctx, wsID, account.ID, crm.UpdateAccountInput{Name: "Acme Renamed", OwnerID: ownerID}); err != nil {
+		t.Fatalf("update account: %v", err)
+	}
+	if err = svc.Delete(ctx, wsID, account.ID); err != nil {
+		t.Fatalf("delete account: %v", err)
+	}
+
+	assertAuditCount(t, db, wsID, "create_account", 1)
+	assertAuditCount(t, db, wsID, "update_account", 1)
+	assertAuditCount(t, db, wsID, "delete_account", 1)
+	assertAuditCount(t, db, wsID, "account.created", 0)
+	assertAuditCount(t, db, wsID, "account.updated", 0)
+
+	actor, details := loadAuditedEvent(t, db, wsID, "update_account")
+	if actor != actorID {
+		t.Fatalf("expected actor %q, got %q", actorID, actor)
+	}
+	oldValue, _ := details.OldValue.(map[string]any)
+	newValue, _ := details.NewValue.(map[string]any)
+	if oldValue["name"] != "Acme" || newValue["name"] != "Acme Renamed" {
+		t.Fatalf("unexpected before/after values: old=%v new=%v", oldValue, newValue)
+	}
+}
+
+func TestAuditedServices_KeepInnerAuditEventsTheyDoNotDuplicate(t *testing.T) {
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	agentID := createUser(t, db, wsID)
+	ctx := context.WithValue(context.Background(), ctxkeys.UserID, ownerID)
+	auditSvc := domainaudit.NewAuditService(db)
+
+	accounts := crm.NewAuditedAccountService(crm.NewAccountService(db), auditSvc)
+	account, err := accounts.Create(ctx, crm.CreateAccountInput{WorkspaceID: wsID, Name: "Acme", OwnerID: ownerID})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if err = accounts.Delete(ctx, wsID, account.ID); err != nil {
+		t.Fatalf("delete account: %v", err)
+	}
+	if _, err = accounts.Restore(ctx, wsID, account.ID); err != nil {
+		t.Fatalf("restore account: %v", err)
+	}
+
+	cases := crm.NewAuditedCaseService(crm.NewCaseService(db), auditSvc)
+	ticket, err := cases.Create(ctx, crm.CreateCaseInput{WorkspaceID: wsID, OwnerID: ownerID, Subject: "VPN down", Priority: "medium"})
+	if err != nil {
+		t.Fatalf("create case: %v", err)
+	}
+	if _, err = cases.Assign(ctx, wsID, ticket.ID, agentID); err != nil {
+		t.Fatalf("assign case: %v", err)
+	}
+	if _, err = cases.Escalate(ctx, wsID, ticket.ID, "customer is blocked"); err != nil {
+		t.Fatalf("escalate case: %v", err)
+	}
+
+	assertAuditCount(t, db, wsID, "account.restored", 1)
+	assertAuditCount(t, db, wsID, "case.assigned", 1)
+	assertAuditCount(t, db, wsID, "case.escalated", 1)
+	assertAuditCount(t, db, wsID, "account.deleted", 0)
+	assertAuditCount(t, db, wsID, "case.created", 0)
+}
+
+func TestAuditedPipelineService_WithoutActorFallsBackToSystem(t *testing.T) {
+	db := mustOpenDBWithMigrations(t)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+
+	svc := crm.NewAuditedPipelineService(crm.NewPipelineService(db), domainaudit.NewAuditService(db))
+	pipeline, err := svc.Create(context.Background(), crm.CreatePipelineInput{WorkspaceID: wsID, Name: "Sales", EntityType: "deal"})
+	if err != nil {
+		t.Fatalf("create pipeline: %v", err)
+	}
+	if err = svc.Delete(context.Background(), wsID, pipeline.ID); err != nil {
+		t.Fatalf("delete pipeline: %v", err)
+	}
+
+	assertAuditCount(t, db, wsID, "create_pipeline", 1)
+	assertAuditCount(t, db, wsID, "delete_pipeline", 1)
+	actor, details := loadAuditedEvent(t, db, wsID, "delete_pipeline")
+	if actor != "system" {
+		t.Fatalf("expected system actor, got %q", actor)
+	}
+	if details.OldValue == nil || details.NewValue != nil {
+		t.Fatalf("expected only old_value on delete, got %+v", details)
+	}
+}
+
+func loadAuditedEvent(t *testing.T, db *sql.DB, workspaceID, action string) (string, domainaudit.EventDetails) {
+	t.Helper()
+	var actorID, raw string
+	if err := db.QueryRow(
+		`SELECT actor_id, details FROM audit_event WHERE workspace_id = ? AND action = ?`,
+		workspaceID, action,
+	).Scan(&actorID, &raw); err != nil {
+		t.Fatalf("load audit event %s: %v", action, err)
+	}
+	var details domainaudit.EventDetails
+	if err := json.Unmarshal([]byte(raw), &details); err != nil {
+		t.Fatalf("decode audit details: %v", err)
+	}
+	return actorID, details
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
@@ -76,7 +77,11 @@ type ListCasesInput struct {
 	Priority  string
 	OwnerID   string
 	AccountID string
-	Sort      string
+	Channel   string
+	// CreatedFrom and CreatedTo bound created_at, inclusive; zero means unbounded.
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Sort        string
 }
 
 const (
@@ -84,19 +89,32 @@ const (
 	caseSortCreatedAtDesc = "-created_at"
 )
 
+const (
+	caseStatusOpen      = "open"
+	caseStatusResolved  = "resolved"
+	caseStatusClosed    = "closed"
+	caseStatusEscalated = "escalated"
+)
+
+// ErrCaseNotReopenable is returned by Reopen when the case is not resolved or closed.
+var ErrCaseNotReopenable = errors.New("case is not resolved or closed")
+
 type CaseService struct {
-	db      *sql.DB
-	querier sqlcgen.Querier
-	bus     eventbus.EventBus
-	audit   auditLogger
+	db        *sqlite.ContextDB
+	querier   sqlcgen.Querier
+	bus       eventbus.EventBus
+	audit     auditLogger
+	slaPolicy CaseSLAPolicy
 }
 
 func NewCaseService(db *sql.DB) *CaseService {
-	return &CaseService{db: db, querier: sqlcgen.New(db), audit: newCRMAuditService(db)}
+	cdb := sqlite.NewContextDB(db)
+	return &CaseService{db: cdb, querier: sqlcgen.New(cdb), audit: newCRMAuditService(db)}
 }
 
 func NewCaseServiceWithBus(db *sql.DB, bus eventbus.EventBus) *CaseService {
-	return &CaseService{db: db, querier: sqlcgen.New(db), bus: bus, audit: newCRMAuditService(db)}
+	cdb := sqlite.NewContextDB(db)
+	return &CaseService{db: cdb, querier: sqlcgen.New(cdb), bus: bus, audit: newCRMAuditService(db)}
 }
 
 func (s *CaseService) Create(ctx context.Context, input CreateCaseInput) (*CaseTicket, error) {
@@ -142,7 +160,7 @@ func (s *CaseService) Create(ctx context.Context, input CreateCaseInput) (*CaseT
 		return nil, fmt.Errorf("create case timeline: %w", timelineErr)
 	}
 	logCRMAudit(ctx, s.audit, input.WorkspaceID, input.OwnerID, actionCaseCreated, timelineEntityCase, id)
-	s.publishRecordChanged(knowledge.ChangeTypeCreated, input.WorkspaceID, id)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeCreated, input.WorkspaceID, id)
 
 	return s.Get(ctx, input.WorkspaceID, id)
 }
@@ -203,7 +221,8 @@ func (s *CaseService) pageCases(ctx context.Context, workspaceID string, input L
 }
 
 func shouldUseFilteredCaseList(input ListCasesInput) bool {
-	return input.Status != "" || input.Priority != "" || input.OwnerID != "" || input.AccountID != "" || input.Sort != caseSortCreatedAtDesc
+	return input.Status != "" || input.Priority != "" || input.OwnerID != "" || input.AccountID != "" ||
+		input.Channel != "" || !input.CreatedFrom.IsZero() || !input.CreatedTo.IsZero() || input.Sort != caseSortCreatedAtDesc
 }
 
 func (s *CaseService) listFiltered(ctx context.Context, workspaceID string, input ListCasesInput) ([]*CaseTicket, error) {
@@ -213,7 +232,7 @@ func (s *CaseService) listFiltered(ctx context.Context, workspaceID string, inpu
 	}
 
 	out := mapRows(rows, rowToCaseTicket)
-	out = filterCasesByPriority(out, input.Priority)
+	out = filterCases(out, input)
 	sortCasesByCreatedAt(out, input.Sort)
 
 	return out, nil
@@ -272,19 +291,28 @@ func (s *CaseService) listCasesByWorkspaceAll(ctx context.Context, workspaceID s
 	return rows, nil
 }
 
-func filterCasesByPriority(items []*CaseTicket, priority string) []*CaseTicket {
-	if priority == "" {
-		return items
-	}
+// filterCases applies every filter of input to rows selected by only one of
+// them, so e.g. an owner and a status filter combine.
+func filterCases(items []*CaseTicket, input ListCasesInput) []*CaseTicket {
 	filtered := make([]*CaseTicket, 0, len(items))
 	for _, item := range items {
-		if item.Priority == priority {
+		if caseMatchesFilter(item, input) {
 			filtered = append(filtered, item)
 		}
 	}
 	return filtered
 }
 
+func caseMatchesFilter(item *CaseTicket, input ListCasesInput) bool {
+	return (input.Status == "" || item.Status == input.Status) &&
+		(input.Priority == "" || item.Priority == input.Priority) &&
+		(input.OwnerID == "" || item.OwnerID == input.OwnerID) &&
+		(input.AccountID == "" || (item.AccountID != nil && *item.AccountID == input.AccountID)) &&
+		(input.Channel == "" || (item.Channel != nil && *item.Channel == input.Channel)) &&
+		(input.CreatedFrom.IsZero() || !item.CreatedAt.Before(input.CreatedFrom)) &&
+		(input.CreatedTo.IsZero() || !item.CreatedAt.After(input.CreatedTo))
+}
+
 func sortCasesByCreatedAt(items []*CaseTicket, sortBy string) {
 	if sortBy == caseSortCreatedAtAsc {
 		sort.SliceStable(items, func(i, j int) bool { return items[i].CreatedAt.Before(items[j].CreatedAt) })
@@ -349,13 +377,13 @@ func (s *CaseService) Update(ctx context.Context, workspaceID, caseID string, in
 		return nil, fmt.Errorf("update case timeline: %w", timelineErr)
 	}
 	logCRMAudit(ctx, s.audit, workspaceID, input.OwnerID, actionCaseUpdated, timelineEntityCase, caseID)
-	s.publishRecordChanged(knowledge.ChangeTypeUpdated, workspaceID, caseID)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeUpdated, workspaceID, caseID)
 
 	ticket, getErr := s.Get(ctx, workspaceID, caseID)
 	if getErr != nil {
 		return nil, getErr
 	}
-	publishCaseUpdated(s.bus, ticket)
+	publishCaseUpdated(ctx, s.bus, ticket)
 	return ticket, nil
 }
 
@@ -379,15 +407,103 @@ func (s *CaseService) Delete(ctx context.Context, workspaceID, caseID string) er
 		return fmt.Errorf("delete case timeline: %w", timelineErr)
 	}
 	logCRMAudit(ctx, s.audit, workspaceID, existing.OwnerID, actionCaseDeleted, timelineEntityCase, caseID)
-	s.publishRecordChanged(knowledge.ChangeTypeDeleted, workspaceID, caseID)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeDeleted, workspaceID, caseID)
+	return nil
+}
+
+// Restore clears deleted_at on a soft-deleted case and returns it.
+// Returns sql.ErrNoRows if the case does not exist and ErrRecordNotDeleted
+// if it is not deleted.
+func (s *CaseService) Restore(ctx context.Context, workspaceID, caseID string) (*CaseTicket, error) {
+	err := restoreSoftDeleted(ctx, s.db, "case_ticket", workspaceID, caseID, func(now string) (int64, error) {
+		return s.querier.RestoreCase(ctx, sqlcgen.RestoreCaseParams{
+			UpdatedAt:   now,
+			ID:          caseID,
+			WorkspaceID: workspaceID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	restored, err := s.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if timelineErr := createTimelineEvent(ctx, s.querier, workspaceID, timelineEntityCase, caseID, restored.OwnerID, timelineActionUpdated); timelineErr != nil {
+		return nil, fmt.Errorf("restore case timeline: %w", timelineErr)
+	}
+	logCRMAudit(ctx, s.audit, workspaceID, restored.OwnerID, actionCaseRestored, timelineEntityCase, caseID)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeCreated, workspaceID, caseID)
+	return restored, nil
+}
+
+// Reopen moves a resolved or closed case back to open and records the
+// transition in case_status_history. Returns sql.ErrNoRows if the case does
+// not exist and ErrCaseNotReopenable if it is not in a terminal status.
+func (s *CaseService) Reopen(ctx context.Context, workspaceID, caseID, reason string) (*CaseTicket, error) {
+	existing, err := s.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != caseStatusResolved && existing.Status != caseStatusClosed {
+		return nil, ErrCaseNotReopenable
+	}
+	if err = s.reopenWithHistory(ctx, existing, reason); err != nil {
+		return nil, err
+	}
+
+	reopened, err := s.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if timelineErr := createTimelineEvent(ctx, s.querier, workspaceID, timelineEntityCase, caseID, reopened.OwnerID, timelineActionUpdated); timelineErr != nil {
+		return nil, fmt.Errorf("reopen case timeline: %w", timelineErr)
+	}
+	logCRMAudit(ctx, s.audit, workspaceID, reopened.OwnerID, actionCaseReopened, timelineEntityCase, caseID)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeUpdated, workspaceID, caseID)
+	publishCaseUpdated(ctx, s.bus, reopened)
+	return reopened, nil
+}
+
+// reopenWithHistory flips the status and appends the history row atomically.
+// The status guard in the UPDATE makes a concurrent reopen lose cleanly.
+func (s *CaseService) reopenWithHistory(ctx context.Context, existing *CaseTicket, reason string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin reopen case: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := nowRFC3339()
+	res, err := tx.ExecContext(ctx, `
+		UPDATE case_ticket
+		SET status = ?, updated_at = ?
+		WHERE id = ? AND workspace_id = ? AND status = ? AND deleted_at IS NULL
+	`, caseStatusOpen, now, existing.ID, existing.WorkspaceID, existing.Status)
+	if err != nil {
+		return fmt.Errorf("reopen case: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCaseNotReopenable
+	}
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO case_status_history (id, workspace_id, case_id, from_status, to_status, reason, actor_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, uuid.NewV7().String(), existing.WorkspaceID, existing.ID, existing.Status, caseStatusOpen,
+		nullString(reason), nullString(existing.OwnerID), now); err != nil {
+		return fmt.Errorf("insert case status history: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit reopen case: %w", err)
+	}
 	return nil
 }
 
-func (s *CaseService) publishRecordChanged(changeType knowledge.ChangeType, workspaceID, caseID string) {
+func (s *CaseService) publishRecordChanged(ctx context.Context, changeType knowledge.ChangeType, workspaceID, caseID string) {
 	if s.bus == nil {
 		return
 	}
-	s.bus.Publish(knowledge.TopicForChangeType(changeType), knowledge.RecordChangedEvent{
+	publishAfterCommit(ctx, s.bus, knowledge.TopicForChangeType(changeType), knowledge.RecordChangedEvent{
 		EntityType:  knowledge.EntityTypeCaseTicket,
 		EntityID:    caseID,
 		WorkspaceID: workspaceID,
@@ -0,0 +1,185 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+// Assignment methods recorded in case_assignment_history.
+const (
+	caseAssignmentManual = "manual"
+	caseAssignmentAuto   = "auto"
+)
+
+var (
+	// ErrInvalidCaseAssignee is returned when the assignee is not an active
+	// user of the case's workspace.
+	ErrInvalidCaseAssignee = errors.New("invalid case assignee")
+	// ErrNoCaseAssigneeAvailable is returned by AutoAssign when no active
+	// user matches.
+	ErrNoCaseAssigneeAvailable = errors.New("no active user available for case assignment")
+)
+
+// AutoAssignInput narrows the users AutoAssign rotates through. SkillTag,
+// when set, keeps only users listing it under "skills" in their preferences.
+type AutoAssignInput struct {
+	SkillTag string
+}
+
+// Assign makes ownerID the owner of the case, records the change in
+// case_assignment_history and emits a case.assigned audit event. Returns
+// sql.ErrNoRows if the case does not exist and ErrInvalidCaseAssignee if
+// ownerID is not an active user of the workspace.
+func (s *CaseService) Assign(ctx context.Context, workspaceID, caseID, ownerID string) (*CaseTicket, error) {
+	existing, err := s.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if err = ensureActiveUser(ctx, s.db, workspaceID, ownerID); err != nil {
+		return nil, err
+	}
+	return s.assign(ctx, existing, ownerID, caseAssignmentManual)
+}
+
+// AutoAssign assigns the case round-robin among the active users of the
+// workspace: the user whose latest automatic assignment is oldest (or who
+// never got one) is picked next. Returns ErrNoCaseAssigneeAvailable when no
+// active user matches input.
+func (s *CaseService) AutoAssign(ctx context.Context, workspaceID, caseID string, input AutoAssignInput) (*CaseTicket, error) {
+	existing, err := s.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	ownerID, err := s.nextRoundRobinAssignee(ctx, workspaceID, input.SkillTag)
+	if err != nil {
+		return nil, err
+	}
+	return s.assign(ctx, existing, ownerID, caseAssignmentAuto)
+}
+
+func (s *CaseService) assign(ctx context.Context, existing *CaseTicket, ownerID, method string) (*CaseTicket, error) {
+	actorID, _ := ctx.Value(ctxkeys.UserID).(string)
+	if err := s.assignWithHistory(ctx, existing, ownerID, method, actorID); err != nil {
+		return nil, err
+	}
+
+	assigned, err := s.Get(ctx, existing.WorkspaceID, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+	if timelineErr := createTimelineEvent(ctx, s.querier, existing.WorkspaceID, timelineEntityCase, existing.ID, ownerID, timelineActionUpdated); timelineErr != nil {
+		return nil, fmt.Errorf("assign case timeline: %w", timelineErr)
+	}
+	s.logAssignment(ctx, existing, ownerID, method, actorID)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeUpdated, existing.WorkspaceID, existing.ID)
+	publishCaseUpdated(ctx, s.bus, assigned)
+	return assigned, nil
+}
+
+// assignWithHistory updates the owner and appends the history row atomically.
+func (s *CaseService) assignWithHistory(ctx context.Context, existing *CaseTicket, ownerID, method, actorID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin assign case: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := nowRFC3339()
+	res, err := tx.ExecContext(ctx, `
+		UPDATE case_ticket
+		SET owner_id = ?, updated_at = ?
+		WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL
+	`, ownerID, now, existing.ID, existing.WorkspaceID)
+	if err != nil {
+		return fmt.Errorf("assign case: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO case_assignment_history (id, workspace_id, case_id, from_owner_id, to_owner_id, method, actor_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, uuid.NewV7().String(), existing.WorkspaceID, existing.ID, nullString(existing.OwnerID), ownerID, method,
+		nullString(actorID), now); err != nil {
+		return fmt.Errorf("insert case assignment history: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit assign case: %w", err)
+	}
+	return nil
+}
+
+// nextRoundRobinAssignee orders candidates by their latest automatic
+// assignment (history rowid, so assignments within the same second keep
+// their order), never-assigned users first.
+func (s *CaseService) nextRoundRobinAssignee(ctx context.Context, workspaceID, skillTag string) (string, error) {
+	var ownerID string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT u.id
+		FROM user_account u
+		LEFT JOIN (
+			SELECT to_owner_id, MAX(rowid) AS last_seq
+			FROM case_assignment_history
+			WHERE workspace_id = ? AND method = ?
+			GROUP BY to_owner_id
+		) h ON h.to_owner_id = u.id
+		WHERE u.workspace_id = ? AND u.status = 'active'
+		  AND (? = '' OR CASE WHEN json_valid(u.preferences) THEN EXISTS (
+			SELECT 1 FROM json_each(u.preferences, '$.skills') WHERE value = ?
+		  ) ELSE 0 END)
+		ORDER BY h.last_seq IS NOT NULL, h.last_seq, u.id
+		LIMIT 1
+	`, workspaceID, caseAssignmentAuto, workspaceID, skillTag, skillTag).Scan(&ownerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", ErrNoCaseAssigneeAvailable
+	}
+	if err != nil {
+		return "", fmt.Errorf("select round-robin assignee: %w", err)
+	}
+	return ownerID, nil
+}
+
+func (s *CaseService) logAssignment(ctx context.Context, existing *CaseTicket, ownerID, method, actorID string) {
+	if s.audit == nil {
+		return
+	}
+	actorType := domainaudit.ActorTypeSystem
+	if actorID != "" {
+		actorType = domainaudit.ActorTypeUser
+	}
+	entityType := timelineEntityCase
+	_ = s.audit.LogWithDetails(
+		ctx,
+		existing.WorkspaceID,
+		resolveAuditActorID(actorID),
+		actorType,
+		actionCaseAssigned,
+		&entityType,
+		&existing.ID,
+		&domainaudit.EventDetails{
+			OldValue: map[string]string{"owner_id": existing.OwnerID},
+			NewValue: map[string]string{"owner_id": ownerID},
+			Metadata: map[string]string{"method": method},
+		},
+		domainaudit.OutcomeSuccess,
+	)
+}
+
+func ensureActiveUser(ctx context.Context, db sqlcgen.DBTX, workspaceID, userID string) error {
+	err := ensureExists(ctx, db,
+		`SELECT 1 FROM user_account WHERE id = ? AND workspace_id = ? AND status = 'active' LIMIT 1`,
+		userID, workspaceID,
+	)
+	if err != nil {
+		return wrapValidationError(ErrInvalidCaseAssignee, "owner must be an active user of the workspace", err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package crm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestCaseService_Assign_RecordsHistoryAndAudit(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	agentID := createUser(t, db, wsID)
+	svc := crm.NewCaseService(db)
+	ctx := context.WithValue(context.Background(), ctxkeys.UserID, ownerID)
+
+	created, err := svc.Create(ctx, crm.CreateCaseInput{WorkspaceID: wsID, OwnerID: ownerID, Subject: "VPN down"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	assigned, err := svc.Assign(ctx, wsID, created.ID, agentID)
+	if err != nil {
+		t.Fatalf("Assign() error = %v", err)
+	}
+	if assigned.OwnerID != agentID {
+		t.Fatalf("owner = %q; want %q", assigned.OwnerID, agentID)
+	}
+
+	var fromOwner, toOwner, method, actor string
+	if err = db.QueryRow(
+		`SELECT from_owner_id, to_owner_id, method, actor_id FROM case_assignment_history WHERE workspace_id = ? AND case_id = ?`,
+		wsID, created.ID,
+	).Scan(&fromOwner, &toOwner, &method, &actor); err != nil {
+		t.Fatalf("query case_assignment_history: %v", err)
+	}
+	if fromOwner != ownerID || toOwner != agentID || method != "manual" || actor != ownerID {
+		t.Fatalf("history = %q -> %q (%s by %s)", fromOwner, toOwner, method, actor)
+	}
+
+	var audits int
+	if err = db.QueryRow(
+		`SELECT COUNT(*) FROM audit_event WHERE workspace_id = ? AND action = 'case.assigned' AND entity_id = ? AND actor_id = ?`,
+		wsID, created.ID, ownerID,
+	).Scan(&audits); err != nil {
+		t.Fatalf("count audit events: %v", err)
+	}
+	if audits != 1 {
+		t.Fatalf("case.assigned audit events = %d; want 1", audits)
+	}
+
+	if _, err = svc.Assign(ctx, wsID, "missing", agentID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Assign(missing case) error = %v; want sql.ErrNoRows", err)
+	}
+}
+
+func TestCaseService_Assign_RejectsInactiveAndCrossWorkspaceUsers(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	_, foreignID := setupWorkspaceAndOwner(t, db)
+	suspendedID := createUser(t, db, wsID)
+	if _, err := db.Exec(`UPDATE user_account SET status = 'suspended' WHERE id = ?`, suspendedID); err != nil {
+		t.Fatalf("suspend user: %v", err)
+	}
+	svc := crm.NewCaseService(db)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, crm.CreateCaseInput{WorkspaceID: wsID, OwnerID: ownerID, Subject: "Billing"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, userID := range []string{suspendedID, foreignID, "missing"} {
+		if _, err = svc.Assign(ctx, wsID, created.ID, userID); !errors.Is(err, crm.ErrInvalidCaseAssignee) {
+			t.Fatalf("Assign(%s) error = %v; want ErrInvalidCaseAssignee", userID, err)
+		}
+	}
+	unchanged, err := svc.Get(ctx, wsID, created.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if unchanged.OwnerID != ownerID {
+		t.Fatalf("owner = %q; want unchanged %q", unchanged.OwnerID, ownerID)
+	}
+}
+
+func TestCaseService_AutoAssign_RoundRobinsActiveUsers(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	secondID := createUser(t, db, wsID)
+	suspendedID := createUser(t, db, wsID)
+	if _, err := db.Exec(`UPDATE user_account SET status = 'suspended' WHERE id = ?`, suspendedID); err != nil {
+		t.Fatalf("suspend user: %v", err)
+	}
+	svc := crm.NewCaseService(db)
+	ctx := context.Background()
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		created, err := svc.Create(ctx, crm.CreateCaseInput{WorkspaceID: wsID, OwnerID: ownerID, Subject: "Escalated"})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		assigned, err := svc.AutoAssign(ctx, wsID, created.ID, crm.AutoAssignInput{})
+		if err != nil {
+			t.Fatalf("AutoAssign() error = %v", err)
+		}
+		got = append(got, assigned.OwnerID)
+	}
+	if got[0] == got[1] || got[0] != got[2] || got[1] != got[3] {
+		t.Fatalf("assignees = %v; want alternating rotation", got)
+	}
+	for _, id := range got {
+		if id != ownerID && id != secondID {
+			t.Fatalf("assignee %q is not an active workspace user", id)
+		}
+	}
+}
+
+func TestCaseService_AutoAssign_FiltersBySkillTag(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	billingID := createUser(t, db, wsID)
+	if _, err := db.Exec(`UPDATE user_account SET preferences = '{"skills":["billing","refunds"]}' WHERE id = ?`, billingID); err != nil {
+		t.Fatalf("set skills: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE user_account SET preferences = 'not json' WHERE id = ?`, ownerID); err != nil {
+		t.Fatalf("set invalid preferences: %v", err)
+	}
+	svc := crm.NewCaseService(db)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, crm.CreateCaseInput{WorkspaceID: wsID, OwnerID: ownerID, Subject: "Refund"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		assigned, err := svc.AutoAssign(ctx, wsID, created.ID, crm.AutoAssignInput{SkillTag: "billing"})
+		if err != nil {
+			t.Fatalf("AutoAssign() error = %v", err)
+		}
+		if assigned.OwnerID != billingID {
+			t.Fatalf("owner = %q; want billing user %q", assigned.OwnerID, billingID)
+		}
+	}
+	if _, err = svc.AutoAssign(ctx, wsID, created.ID, crm.AutoAssignInput{SkillTag: "legal"}); !errors.Is(err, crm.ErrNoCaseAssigneeAvailable) {
+		t.Fatalf("AutoAssign(legal) error = %v; want ErrNoCaseAssigneeAvailable", err)
+	}
+}
@@ -0,0 +1,203 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
+	"github.com/matiasleandrokruk/fenix/internal/domain/knowledge"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+// TopicCaseEscalated is published with a CaseEscalatedEvent after Escalate.
+const TopicCaseEscalated = "case.escalated"
+
+// casePriorities lists case priorities from lowest to highest.
+var casePriorities = []string{"low", "medium", "high", "urgent"}
+
+// ErrCaseAtMaxPriority is returned by Escalate when the case already has the
+// highest priority.
+var ErrCaseAtMaxPriority = errors.New("case is already at the highest priority")
+
+// CaseSLAPolicy maps a case priority to the time allowed to resolve it,
+// counted from the moment the case reaches that priority.
+type CaseSLAPolicy map[string]time.Duration
+
+// DefaultCaseSLAPolicy returns the SLA used when none is set.
+func DefaultCaseSLAPolicy() CaseSLAPolicy {
+	return CaseSLAPolicy{
+		"low":    72 * time.Hour,
+		"medium": 48 * time.Hour,
+		"high":   24 * time.Hour,
+		"urgent": 4 * time.Hour,
+	}
+}
+
+// CaseEscalatedEvent is the payload of TopicCaseEscalated.
+type CaseEscalatedEvent struct {
+	WorkspaceID  string    `json:"workspace_id"`
+	CaseID       string    `json:"case_id"`
+	FromPriority string    `json:"from_priority"`
+	ToPriority   string    `json:"to_priority"`
+	SLADeadline  string    `json:"sla_deadline"`
+	Reason       string    `json:"reason,omitempty"`
+	OccurredAt   time.Time `json:"occurred_at"`
+	// AgentRunID is the agent run that escalated the case, if any.
+	AgentRunID string `json:"agent_run_id,omitempty"`
+}
+
+// SetSLAPolicy replaces the priority→SLA policy Escalate uses. Priorities
+// missing from policy fall back to DefaultCaseSLAPolicy.
+func (s *CaseService) SetSLAPolicy(policy CaseSLAPolicy) {
+	s.slaPolicy = policy
+}
+
+func (s *CaseService) slaFor(priority string) time.Duration {
+	if d, ok := s.slaPolicy[priority]; ok && d > 0 {
+		return d
+	}
+	return DefaultCaseSLAPolicy()[priority]
+}
+
+// Escalate raises the case one priority level, marks it escalated and moves
+// sla_deadline to the SLA of the new priority unless the current deadline is
+// earlier: escalating never grants the case more time. It records the
+// transition in case_status_history in the same transaction. It emits a
+// case.escalated audit event and TopicCaseEscalated. Returns sql.ErrNoRows
+// if the case does not exist and ErrCaseAtMaxPriority if it is already
+// urgent.
+func (s *CaseService) Escalate(ctx context.Context, workspaceID, caseID, reason string) (*CaseTicket, error) {
+	existing, err := s.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	next, err := nextCasePriority(existing.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	deadline := escalationDeadline(existing.SLADeadline, now.Add(s.slaFor(next)))
+	actorID, _ := ctx.Value(ctxkeys.UserID).(string)
+	if err = s.escalateWithHistory(ctx, existing, next, deadline, reason, actorID, now.Format(time.RFC3339)); err != nil {
+		return nil, err
+	}
+
+	escalated, err := s.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return nil, err
+	}
+	if timelineErr := createTimelineEvent(ctx, s.querier, workspaceID, timelineEntityCase, caseID, escalated.OwnerID, timelineActionUpdated); timelineErr != nil {
+		return nil, fmt.Errorf("escalate case timeline: %w", timelineErr)
+	}
+	s.logEscalation(ctx, existing, escalated, reason, actorID)
+	s.publishRecordChanged(ctx, knowledge.ChangeTypeUpdated, workspaceID, caseID)
+	publishCaseUpdated(ctx, s.bus, escalated)
+	if s.bus != nil {
+		publishAfterCommit(ctx, s.bus, TopicCaseEscalated, CaseEscalatedEvent{
+			WorkspaceID:  workspaceID,
+			CaseID:       caseID,
+			FromPriority: existing.Priority,
+			ToPriority:   next,
+			SLADeadline:  deadline,
+			Reason:       reason,
+			OccurredAt:   now,
+			AgentRunID:   agentRunID(ctx),
+		})
+	}
+	return escalated, nil
+}
+
+// escalationDeadline returns the earlier of the current deadline and
+// candidate. An unset or unparsable current deadline yields candidate.
+func escalationDeadline(current *string, candidate time.Time) string {
+	if current != nil {
+		if existing, err := time.Parse(time.RFC3339, *current); err == nil && existing.Before(candidate) {
+			return *current
+		}
+	}
+	return candidate.Format(time.RFC3339)
+}
+
+func nextCasePriority(priority string) (string, error) {
+	i := slices.Index(casePriorities, priority)
+	if i < 0 {
+		return "", invalidCaseInput("priority is invalid", nil)
+	}
+	if i == len(casePriorities)-1 {
+		return "", ErrCaseAtMaxPriority
+	}
+	return casePriorities[i+1], nil
+}
+
+// escalateWithHistory updates priority, status and deadline and appends the
+// history row atomically. The priority guard in the UPDATE makes a
+// concurrent escalation lose cleanly instead of skipping a level.
+func (s *CaseService) escalateWithHistory(ctx context.Context, existing *CaseTicket, priority, deadline, reason, actorID, now string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin escalate case: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE case_ticket
+		SET priority = ?, status = ?, sla_deadline = ?, updated_at = ?
+		WHERE id = ? AND workspace_id = ? AND priority = ? AND deleted_at IS NULL
+	`, priority, caseStatusEscalated, deadline, now, existing.ID, existing.WorkspaceID, existing.Priority)
+	if err != nil {
+		return fmt.Errorf("escalate case: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO case_status_history (id, workspace_id, case_id, from_status, to_status, reason, actor_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, uuid.NewV7().String(), existing.WorkspaceID, existing.ID, existing.Status, caseStatusEscalated,
+		nullString(reason), nullString(actorID), now); err != nil {
+		return fmt.Errorf("insert case status history: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit escalate case: %w", err)
+	}
+	return nil
+}
+
+func (s *CaseService) logEscalation(ctx context.Context, before, after *CaseTicket, reason, actorID string) {
+	if s.audit == nil {
+		return
+	}
+	actorType := domainaudit.ActorTypeSystem
+	if actorID != "" {
+		actorType = domainaudit.ActorTypeUser
+	}
+	entityType := timelineEntityCase
+	_ = s.audit.LogWithDetails(
+		ctx,
+		before.WorkspaceID,
+		resolveAuditActorID(actorID),
+		actorType,
+		actionCaseEscalated,
+		&entityType,
+		&before.ID,
+		&domainaudit.EventDetails{
+			OldValue: map[string]string{"priority": before.Priority, "status": before.Status, "sla_deadline": derefCaseString(before.SLADeadline)},
+			NewValue: map[string]string{"priority": after.Priority, "status": after.Status, "sla_deadline": derefCaseString(after.SLADeadline)},
+			Metadata: map[string]string{"reason": reason},
+		},
+		domainaudit.OutcomeSuccess,
+	)
+}
+
+func derefCaseString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,137 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+)
+
+// TopicCaseSLABreached is published with a CaseSLABreachedEvent for each
+// unresolved case the CaseSLAMonitor finds past its sla_deadline.
+const TopicCaseSLABreached = "case.sla_breached"
+
+// DefaultCaseSLAInterval is how often NewCaseSLAMonitor checks by default.
+const DefaultCaseSLAInterval = 5 * time.Minute
+
+const caseSLAComponent = "crm.CaseSLAMonitor"
+
+// CaseSLABreachedEvent is the payload of TopicCaseSLABreached.
+type CaseSLABreachedEvent struct {
+	WorkspaceID string    `json:"workspace_id"`
+	CaseID      string    `json:"case_id"`
+	Priority    string    `json:"priority"`
+	Status      string    `json:"status"`
+	OwnerID     string    `json:"owner_id"`
+	SLADeadline string    `json:"sla_deadline"`
+	OccurredAt  time.Time `json:"occurred_at"`
+}
+
+// CaseSLAMonitor periodically reports the cases that are neither resolved nor
+// closed once their sla_deadline has passed, publishing TopicCaseSLABreached.
+// Each case is reported once, marked by sla_breached_at.
+type CaseSLAMonitor struct {
+	db       *sql.DB
+	bus      eventbus.EventBus
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewCaseSLAMonitor returns a monitor checking every interval once started.
+// Zero or negative intervals use DefaultCaseSLAInterval.
+func NewCaseSLAMonitor(db *sql.DB, bus eventbus.EventBus, interval time.Duration) *CaseSLAMonitor {
+	if interval <= 0 {
+		interval = DefaultCaseSLAInterval
+	}
+	return &CaseSLAMonitor{db: db, bus: bus, interval: interval, now: time.Now}
+}
+
+// Start checks right away and then every interval until ctx is done.
+// Failures are logged and retried on the next tick.
+func (m *CaseSLAMonitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		if _, err := m.Check(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("case SLA check failed", slog.String(logging.KeyComponent, caseSLAComponent), logging.Err(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check reports the newly breached cases of every workspace and returns the
+// events it published.
+func (m *CaseSLAMonitor) Check(ctx context.Context) ([]CaseSLABreachedEvent, error) {
+	now := m.now().UTC()
+	breached, err := m.findBreached(ctx, now.Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	events := make([]CaseSLABreachedEvent, 0, len(breached))
+	for _, event := range breached {
+		event.OccurredAt = now
+		reported, reportErr := m.report(ctx, event, now)
+		if reportErr != nil {
+			return events, reportErr
+		}
+		if reported {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// findBreached returns the unresolved, unreported cases whose sla_deadline is
+// at or before cutoff.
+func (m *CaseSLAMonitor) findBreached(ctx context.Context, cutoff string) ([]CaseSLABreachedEvent, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT workspace_id, id, priority, status, owner_id, sla_deadline
+		FROM case_ticket
+		WHERE sla_deadline IS NOT NULL AND sla_deadline != ''
+		  AND sla_deadline <= ?
+		  AND sla_breached_at IS NULL
+		  AND status NOT IN (?, ?)
+		  AND deleted_at IS NULL
+		ORDER BY sla_deadline
+	`, cutoff, caseStatusResolved, caseStatusClosed)
+	if err != nil {
+		return nil, fmt.Errorf("find breached cases: %w", err)
+	}
+	defer rows.Close()
+	var out []CaseSLABreachedEvent
+	for rows.Next() {
+		var e CaseSLABreachedEvent
+		if err = rows.Scan(&e.WorkspaceID, &e.CaseID, &e.Priority, &e.Status, &e.OwnerID, &e.SLADeadline); err != nil {
+			return nil, fmt.Errorf("scan breached case: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// report claims the breach by stamping sla_breached_at, so concurrent
+// monitors do not report it twice, then publishes the event. reported is
+// false when another monitor claimed it first.
+func (m *CaseSLAMonitor) report(ctx context.Context, event CaseSLABreachedEvent, now time.Time) (bool, error) {
+	res, err := m.db.ExecContext(ctx,
+		`UPDATE case_ticket SET sla_breached_at = ? WHERE id = ? AND sla_breached_at IS NULL`,
+		now.Format(time.RFC3339), event.CaseID)
+	if err != nil {
+		return false, fmt.Errorf("mark case SLA breached: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	if m.bus != nil {
+		m.bus.Publish(TopicCaseSLABreached, event)
+	}
+	return true, nil
+}
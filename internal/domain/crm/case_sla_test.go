@@ -0,0 +1,63 @@
+package crm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestCaseSLAMonitor_Check_ReportsBreachedCasesOnce(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	past := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	future := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+
+	cases := crm.NewCaseService(db)
+	create := func(subject, deadline, status string) *crm.CaseTicket {
+		ticket, err := cases.Create(ctx, crm.CreateCaseInput{
+			WorkspaceID: wsID, OwnerID: ownerID, Subject: subject, Priority: "high", Status: status, SLADeadline: deadline,
+		})
+		if err != nil {
+			t.Fatalf("Create(%s) error = %v", subject, err)
+		}
+		return ticket
+	}
+	breached := create("Breached", past, "open")
+	create("On time", future, "open")
+	create("Resolved late", past, "resolved")
+	create("No SLA", "", "open")
+
+	bus := eventbus.New()
+	breachedCh := bus.Subscribe(crm.TopicCaseSLABreached)
+	monitor := crm.NewCaseSLAMonitor(db, bus, time.Minute)
+
+	events, err := monitor.Check(ctx)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(events) != 1 || events[0].CaseID != breached.ID {
+		t.Fatalf("Check() events = %+v; want only case %s", events, breached.ID)
+	}
+	if e := events[0]; e.WorkspaceID != wsID || e.OwnerID != ownerID || e.Priority != "high" || e.SLADeadline != past {
+		t.Fatalf("event = %+v", e)
+	}
+	select {
+	case <-breachedCh:
+	case <-time.After(time.Second):
+		t.Fatal("expected case.sla_breached to be published")
+	}
+
+	again, err := monitor.Check(ctx)
+	if err != nil {
+		t.Fatalf("second Check() error = %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("second Check() events = %+v; want none, the breach was reported", again)
+	}
+}
@@ -17,3 +17,20 @@ const (
 	timelineActionUpdated = "updated"
 	timelineActionDeleted = "deleted"
 )
+
+// Activity status constants.
+const (
+	activityStatusPending   = "pending"
+	activityStatusCompleted = "completed"
+	activityStatusCancelled = "cancelled"
+)
+
+// Lead status constants, mirroring the CHECK constraint on lead.status.
+// "lost" is the disqualified state.
+const (
+	leadStatusNew       = "new"
+	leadStatusContacted = "contacted"
+	leadStatusQualified = "qualified"
+	leadStatusConverted = "converted"
+	leadStatusLost      = "lost"
+)
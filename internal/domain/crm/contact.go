@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
@@ -66,16 +67,17 @@ type ListContactsInput struct {
 
 // ContactService provides contact operations scoped to a workspace.
 type ContactService struct {
-	db      *sql.DB
+	db      *sqlite.ContextDB
 	querier sqlcgen.Querier
 	audit   auditLogger
 }
 
 // NewContactService creates a ContactService instance.
 func NewContactService(db *sql.DB) *ContactService {
+	cdb := sqlite.NewContextDB(db)
 	return &ContactService{
-		db:      db,
-		querier: sqlcgen.New(db),
+		db:      cdb,
+		querier: sqlcgen.New(cdb),
 		audit:   newCRMAuditService(db),
 	}
 }
@@ -88,7 +88,7 @@ func TestLeadDealCaseNoteServices_EmitAuditAndTimeline(t *testing.T) {
 	}
 	if _, err = leadSvc.Update(context.Background(), wsID, lead.ID, crm.UpdateLeadInput{
 		OwnerID: ownerID,
-		Status:  "qualified",
+		Status:  "contacted",
 	}); err != nil {
 		t.Fatalf("update lead: %v", err)
 	}
@@ -0,0 +1,261 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+// Custom field types a custom_field_def can declare.
+const (
+	CustomFieldTypeString  = "string"
+	CustomFieldTypeNumber  = "number"
+	CustomFieldTypeBoolean = "boolean"
+	CustomFieldTypeDate    = "date" // YYYY-MM-DD
+)
+
+// customFieldDateLayout is the format of date custom field values.
+const customFieldDateLayout = "2006-01-02"
+
+var (
+	// ErrInvalidCustomFields is returned when custom field values name an
+	// undefined field, miss a required one or do not match the declared type.
+	ErrInvalidCustomFields = errors.New("invalid custom fields")
+	// ErrInvalidCustomFieldDef is returned for a definition with an unknown
+	// entity type or field type, or an empty name.
+	ErrInvalidCustomFieldDef = errors.New("invalid custom field definition")
+	// ErrCustomFieldDefExists is returned when the workspace already defines
+	// a field with the same name for the entity type.
+	ErrCustomFieldDefExists = errors.New("custom field already defined")
+)
+
+var (
+	validCustomFieldEntityTypes = map[string]struct{}{
+		timelineEntityAccount: {},
+		timelineEntityDeal:    {},
+	}
+	validCustomFieldTypes = map[string]struct{}{
+		CustomFieldTypeString:  {},
+		CustomFieldTypeNumber:  {},
+		CustomFieldTypeBoolean: {},
+		CustomFieldTypeDate:    {},
+	}
+)
+
+// CustomFieldDef declares one custom field of accounts or deals in a workspace.
+type CustomFieldDef struct {
+	ID          string    `json:"id"`
+	WorkspaceID string    `json:"workspaceId"`
+	EntityType  string    `json:"entityType"` // account|deal
+	Name        string    `json:"name"`
+	Type        string    `json:"type"` // string|number|boolean|date
+	Required    bool      `json:"required"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// CreateCustomFieldDefInput defines a new custom field.
+type CreateCustomFieldDefInput struct {
+	WorkspaceID string
+	EntityType  string
+	Name        string
+	Type        string
+	Required    bool
+}
+
+// CustomFieldService manages the per-workspace custom field definitions.
+type CustomFieldService struct {
+	db *sqlite.ContextDB
+}
+
+// NewCustomFieldService creates a CustomFieldService instance.
+func NewCustomFieldService(db *sql.DB) *CustomFieldService {
+	return &CustomFieldService{db: sqlite.NewContextDB(db)}
+}
+
+// Create adds a field definition. Existing records are not revalidated; a new
+// required field is enforced the next time their custom fields are written.
+func (s *CustomFieldService) Create(ctx context.Context, input CreateCustomFieldDefInput) (*CustomFieldDef, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if err := validateCustomFieldDef(input); err != nil {
+		return nil, err
+	}
+	def := &CustomFieldDef{
+		ID:          uuid.NewV7().String(),
+		WorkspaceID: input.WorkspaceID,
+		EntityType:  input.EntityType,
+		Name:        input.Name,
+		Type:        input.Type,
+		Required:    input.Required,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO custom_field_def (id, workspace_id, entity_type, name, field_type, required, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		def.ID, def.WorkspaceID, def.EntityType, def.Name, def.Type, def.Required, def.CreatedAt.Format(time.RFC3339))
+	if err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil, fmt.Errorf("%w: %s.%s", ErrCustomFieldDefExists, def.EntityType, def.Name)
+		}
+		return nil, fmt.Errorf("create custom field def: %w", err)
+	}
+	return def, nil
+}
+
+// List returns the workspace's field definitions ordered by entity type and
+// name; entityType, when set, keeps only that entity's fields.
+func (s *CustomFieldService) List(ctx context.Context, workspaceID, entityType string) ([]*CustomFieldDef, error) {
+	return listCustomFieldDefs(ctx, s.db, workspaceID, entityType)
+}
+
+func validateCustomFieldDef(input CreateCustomFieldDefInput) error {
+	if _, ok := validCustomFieldEntityTypes[input.EntityType]; !ok {
+		return fmt.Errorf("%w: entity type %q", ErrInvalidCustomFieldDef, input.EntityType)
+	}
+	if _, ok := validCustomFieldTypes[input.Type]; !ok {
+		return fmt.Errorf("%w: field type %q", ErrInvalidCustomFieldDef, input.Type)
+	}
+	if input.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCustomFieldDef)
+	}
+	return nil
+}
+
+func listCustomFieldDefs(ctx context.Context, db sqlcgen.DBTX, workspaceID, entityType string) ([]*CustomFieldDef, error) {
+	query := `
+		SELECT id, workspace_id, entity_type, name, field_type, required, created_at
+		FROM custom_field_def
+		WHERE workspace_id = ?`
+	args := []any{workspaceID}
+	if entityType != "" {
+		query += ` AND entity_type = ?`
+		args = append(args, entityType)
+	}
+	query += ` ORDER BY entity_type, name`
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list custom field defs: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*CustomFieldDef{}
+	for rows.Next() {
+		var def CustomFieldDef
+		var createdAt string
+		if err = rows.Scan(&def.ID, &def.WorkspaceID, &def.EntityType, &def.Name, &def.Type, &def.Required, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan custom field def: %w", err)
+		}
+		def.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		out = append(out, &def)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate custom field defs: %w", err)
+	}
+	return out, nil
+}
+
+// encodeCustomFields validates values against the workspace's definitions
+// for entityType and returns them as the JSON stored in custom_fields.
+// A nil map encodes to nil, which leaves stored values untouched on update;
+// on create it is still checked for required fields.
+func encodeCustomFields(ctx context.Context, db sqlcgen.DBTX, workspaceID, entityType string, values map[string]any, creating bool) (*string, error) {
+	if values == nil && !creating {
+		return nil, nil
+	}
+	defs, err := listCustomFieldDefs(ctx, db, workspaceID, entityType)
+	if err != nil {
+		return nil, err
+	}
+	if err = validateCustomFields(defs, values); err != nil {
+		return nil, err
+	}
+	if values == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return nil, fmt.Errorf("encode custom fields: %w", err)
+	}
+	encoded := string(raw)
+	return &encoded, nil
+}
+
+// validateCustomFields checks values against defs: every key must be
+// defined, every required field present and non-null, and every value of
+// its declared type. Numbers may be any Go numeric type or a json.Number;
+// dates are YYYY-MM-DD strings. All problems are reported together.
+func validateCustomFields(defs []*CustomFieldDef, values map[string]any) error {
+	byName := make(map[string]*CustomFieldDef, len(defs))
+	var problems []string
+	for _, def := range defs {
+		byName[def.Name] = def
+		if v, ok := values[def.Name]; def.Required && (!ok || v == nil) {
+			problems = append(problems, fmt.Sprintf("%s is required", def.Name))
+		}
+	}
+	for name, value := range values {
+		def, ok := byName[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s is not a defined field", name))
+		case value != nil && !customFieldValueMatches(def.Type, value):
+			problems = append(problems, fmt.Sprintf("%s must be a %s", name, def.Type))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return fmt.Errorf("%w: %s", ErrInvalidCustomFields, strings.Join(problems, "; "))
+}
+
+func customFieldValueMatches(fieldType string, value any) bool {
+	switch fieldType {
+	case CustomFieldTypeString:
+		_, ok := value.(string)
+		return ok
+	case CustomFieldTypeNumber:
+		switch v := value.(type) {
+		case float64, float32, int, int32, int64:
+			return true
+		case json.Number:
+			_, err := v.Float64()
+			return err == nil
+		}
+		return false
+	case CustomFieldTypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case CustomFieldTypeDate:
+		s, ok := value.(string)
+		if !ok {
+			return false
+		}
+		_, err := time.Parse(customFieldDateLayout, s)
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// decodeCustomFields parses a stored custom_fields column; malformed JSON
+// reads as no custom fields.
+func decodeCustomFields(raw *string) map[string]any {
+	if raw == nil || *raw == "" {
+		return nil
+	}
+	var values map[string]any
+	if json.Unmarshal([]byte(*raw), &values) != nil {
+		return nil
+	}
+	return values
+}
@@ -0,0 +1,112 @@
+package crm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestAccountService_CustomFields_TypeValidation(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+
+	defs := crm.NewCustomFieldService(db)
+	for _, in := range []crm.CreateCustomFieldDefInput{
+		{Name: "tier", Type: crm.CustomFieldTypeString, Required: true},
+		{Name: "seats", Type: crm.CustomFieldTypeNumber},
+		{Name: "active", Type: crm.CustomFieldTypeBoolean},
+		{Name: "renewal", Type: crm.CustomFieldTypeDate},
+	} {
+		in.WorkspaceID = wsID
+		in.EntityType = "account"
+		if _, err := defs.Create(ctx, in); err != nil {
+			t.Fatalf("define %s: %v", in.Name, err)
+		}
+	}
+
+	svc := crm.NewAccountService(db)
+	for name, values := range map[string]map[string]any{
+		"number as string":  {"tier": "gold", "seats": "ten"},
+		"string as number":  {"tier": 3.0},
+		"boolean as string": {"tier": "gold", "active": "yes"},
+		"malformed date":    {"tier": "gold", "renewal": "31/12/2026"},
+		"undefined field":   {"tier": "gold", "color": "blue"},
+		"missing required":  {"seats": 10.0},
+		"null required":     {"tier": nil},
+	} {
+		_, err := svc.Create(ctx, crm.CreateAccountInput{WorkspaceID: wsID, Name: "Acme", OwnerID: ownerID, CustomFields: values})
+		if !errors.Is(err, crm.ErrInvalidCustomFields) {
+			t.Fatalf("%s: Create() error = %v; want ErrInvalidCustomFields", name, err)
+		}
+	}
+
+	valid := map[string]any{"tier": "gold", "seats": 25, "active": true, "renewal": "2026-12-31"}
+	created, err := svc.Create(ctx, crm.CreateAccountInput{WorkspaceID: wsID, Name: "Acme", OwnerID: ownerID, CustomFields: valid})
+	if err != nil {
+		t.Fatalf("Create(valid) error = %v", err)
+	}
+	got, err := svc.Get(ctx, wsID, created.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.CustomFields["tier"] != "gold" || got.CustomFields["seats"] != 25.0 || got.CustomFields["active"] != true || got.CustomFields["renewal"] != "2026-12-31" {
+		t.Fatalf("stored custom fields = %v", got.CustomFields)
+	}
+
+	// Omitted custom fields keep the stored values; invalid ones are rejected.
+	updated, err := svc.Update(ctx, wsID, created.ID, crm.UpdateAccountInput{Name: "Acme Corp", OwnerID: ownerID})
+	if err != nil {
+		t.Fatalf("Update(no custom fields) error = %v", err)
+	}
+	if updated.CustomFields["tier"] != "gold" {
+		t.Fatalf("custom fields after update = %v; want them kept", updated.CustomFields)
+	}
+	if _, err = svc.Update(ctx, wsID, created.ID, crm.UpdateAccountInput{Name: "Acme Corp", OwnerID: ownerID, CustomFields: map[string]any{"tier": "gold", "seats": true}}); !errors.Is(err, crm.ErrInvalidCustomFields) {
+		t.Fatalf("Update(invalid) error = %v; want ErrInvalidCustomFields", err)
+	}
+}
+
+func TestCustomFieldService_CreateAndList(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewCustomFieldService(db)
+
+	if _, err := svc.Create(ctx, crm.CreateCustomFieldDefInput{WorkspaceID: wsID, EntityType: "account", Name: "tier", Type: "enum"}); !errors.Is(err, crm.ErrInvalidCustomFieldDef) {
+		t.Fatalf("Create(unknown type) error = %v; want ErrInvalidCustomFieldDef", err)
+	}
+	if _, err := svc.Create(ctx, crm.CreateCustomFieldDefInput{WorkspaceID: wsID, EntityType: "lead", Name: "tier", Type: crm.CustomFieldTypeString}); !errors.Is(err, crm.ErrInvalidCustomFieldDef) {
+		t.Fatalf("Create(unknown entity) error = %v; want ErrInvalidCustomFieldDef", err)
+	}
+	if _, err := svc.Create(ctx, crm.CreateCustomFieldDefInput{WorkspaceID: wsID, EntityType: "account", Name: "tier", Type: crm.CustomFieldTypeString}); err != nil {
+		t.Fatalf("Create(account.tier) error = %v", err)
+	}
+	if _, err := svc.Create(ctx, crm.CreateCustomFieldDefInput{WorkspaceID: wsID, EntityType: "account", Name: "tier", Type: crm.CustomFieldTypeNumber}); !errors.Is(err, crm.ErrCustomFieldDefExists) {
+		t.Fatalf("Create(duplicate) error = %v; want ErrCustomFieldDefExists", err)
+	}
+	if _, err := svc.Create(ctx, crm.CreateCustomFieldDefInput{WorkspaceID: wsID, EntityType: "deal", Name: "tier", Type: crm.CustomFieldTypeNumber}); err != nil {
+		t.Fatalf("Create(deal.tier) error = %v", err)
+	}
+
+	all, err := svc.List(ctx, wsID, "")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("List() = %d defs; want 2", len(all))
+	}
+	deal, err := svc.List(ctx, wsID, "deal")
+	if err != nil {
+		t.Fatalf("List(deal) error = %v", err)
+	}
+	if len(deal) != 1 || deal[0].Type != crm.CustomFieldTypeNumber {
+		t.Fatalf("List(deal) = %+v; want the deal tier number field", deal)
+	}
+}
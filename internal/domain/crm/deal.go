@@ -6,31 +6,37 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
 
 type Deal struct {
-	ID                string     `json:"id"`
-	WorkspaceID       string     `json:"workspaceId"`
-	AccountID         string     `json:"accountId"`
-	ContactID         *string    `json:"contactId,omitempty"`
-	PipelineID        string     `json:"pipelineId"`
-	StageID           string     `json:"stageId"`
-	OwnerID           string     `json:"ownerId"`
-	Title             string     `json:"title"`
-	Amount            *float64   `json:"amount,omitempty"`
-	Currency          *string    `json:"currency,omitempty"`
-	ExpectedClose     *string    `json:"expectedClose,omitempty"`
-	Status            string     `json:"status"`
-	Metadata          *string    `json:"metadata,omitempty"`
-	ActiveSignalCount *int       `json:"active_signal_count,omitempty"`
-	CreatedAt         time.Time  `json:"createdAt"`
-	UpdatedAt         time.Time  `json:"updatedAt"`
-	DeletedAt         *time.Time `json:"deletedAt,omitempty"`
+	ID            string     `json:"id"`
+	WorkspaceID   string     `json:"workspaceId"`
+	AccountID     string     `json:"accountId"`
+	ContactID     *string    `json:"contactId,omitempty"`
+	PipelineID    string     `json:"pipelineId"`
+	StageID       string     `json:"stageId"`
+	OwnerID       string     `json:"ownerId"`
+	Title         string     `json:"title"`
+	Amount        *float64   `json:"amount,omitempty"`
+	Currency      *string    `json:"currency,omitempty"`
+	ExpectedClose *string    `json:"expectedClose,omitempty"`
+	Status        string     `json:"status"`
+	Metadata      *string    `json:"metadata,omitempty"`
+	ClosedAt      *time.Time `json:"closedAt,omitempty"`
+	CloseReason   *string    `json:"closeReason,omitempty"`
+	// CustomFields holds the values of the workspace's deal custom fields.
+	CustomFields      map[string]any `json:"customFields,omitempty"`
+	ActiveSignalCount *int           `json:"active_signal_count,omitempty"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt"`
+	DeletedAt         *time.Time     `json:"deletedAt,omitempty"`
 }
 
 type CreateDealInput struct {
@@ -46,6 +52,9 @@ type CreateDealInput struct {
 	ExpectedClose string
 	Status        string
 	Metadata      string
+	// CustomFields is validated against the workspace's deal
+	// custom_field_def rows; required fields must be present.
+	CustomFields map[string]any
 }
 
 type UpdateDealInput struct {
@@ -60,6 +69,8 @@ type UpdateDealInput struct {
 	ExpectedClose string
 	Status        string
 	Metadata      string
+	// CustomFields replaces the stored values after validation; nil keeps them.
+	CustomFields map[string]any
 }
 
 type ListDealsInput struct {
@@ -78,19 +89,31 @@ const (
 	sortCreatedAtDesc = "-created_at"
 )
 
+const (
+	dealStatusOpen = "open"
+	dealStatusWon  = "won"
+	dealStatusLost = "lost"
+)
+
+// ErrDealNotOpen is returned by CloseWon and CloseLost when the deal is
+// already won, lost or closed.
+var ErrDealNotOpen = errors.New("deal is not open")
+
 type DealService struct {
-	db      *sql.DB
+	db      *sqlite.ContextDB
 	querier sqlcgen.Querier
 	audit   auditLogger
 	bus     eventbus.EventBus
 }
 
 func NewDealService(db *sql.DB) *DealService {
-	return &DealService{db: db, querier: sqlcgen.New(db), audit: newCRMAuditService(db)}
+	cdb := sqlite.NewContextDB(db)
+	return &DealService{db: cdb, querier: sqlcgen.New(cdb), audit: newCRMAuditService(db)}
 }
 
 func NewDealServiceWithBus(db *sql.DB, bus eventbus.EventBus) *DealService {
-	return &DealService{db: db, querier: sqlcgen.New(db), audit: newCRMAuditService(db), bus: bus}
+	cdb := sqlite.NewContextDB(db)
+	return &DealService{db: cdb, querier: sqlcgen.New(cdb), audit: newCRMAuditService(db), bus: bus}
 }
 
 func (s *DealService) Create(ctx context.Context, input CreateDealInput) (*Deal, error) {
@@ -98,35 +121,19 @@ func (s *DealService) Create(ctx context.Context, input CreateDealInput) (*Deal,
 	now := nowRFC3339()
 	status := input.Status
 	if status == "" {
-		status = "open"
+		status = dealStatusOpen
 	}
 	input.Status = status
 	if validationErr := validateDealInput(ctx, s.db, input.WorkspaceID, input); validationErr != nil {
 		return nil, validationErr
 	}
-
-	err := s.querier.CreateDeal(ctx, sqlcgen.CreateDealParams{
-		ID:            id,
-		WorkspaceID:   input.WorkspaceID,
-		AccountID:     input.AccountID,
-		ContactID:     nullString(input.ContactID),
-		PipelineID:    input.PipelineID,
-		StageID:       input.StageID,
-		OwnerID:       input.OwnerID,
-		Title:         input.Title,
-		Amount:        input.Amount,
-		Currency:      nullString(input.Currency),
-		ExpectedClose: nullString(input.ExpectedClose),
-		Status:        status,
-		Metadata:      nullString(input.Metadata),
-		CreatedAt:     now,
-		UpdatedAt:     now,
-	})
+	customFields, err := encodeCustomFields(ctx, s.db, input.WorkspaceID, timelineEntityDeal, input.CustomFields, true)
 	if err != nil {
-		return nil, fmt.Errorf("create deal: %w", err)
+		return nil, err
 	}
-	if timelineErr := createTimelineEvent(ctx, s.querier, input.WorkspaceID, timelineEntityDeal, id, input.OwnerID, timelineActionCreated); timelineErr != nil {
-		return nil, fmt.Errorf("create deal timeline: %w", timelineErr)
+
+	if err = s.createWithStageHistory(ctx, id, input, customFields, now); err != nil {
+		return nil, err
 	}
 	logCRMAudit(ctx, s.audit, input.WorkspaceID, input.OwnerID, actionDealCreated, timelineEntityDeal, id)
 
@@ -304,8 +311,42 @@ func (s *DealService) Update(ctx context.Context, workspaceID, dealID string, in
 	}); validationErr != nil {
 		return nil, validationErr
 	}
+	customFields, err := encodeCustomFields(ctx, s.db, workspaceID, timelineEntityDeal, input.CustomFields, false)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.Get(ctx, workspaceID, dealID)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.updateWithStageHistory(ctx, existing, input, customFields); err != nil {
+		return nil, err
+	}
+	if timelineErr := createTimelineEvent(ctx, s.querier, workspaceID, timelineEntityDeal, dealID, input.OwnerID, timelineActionUpdated); timelineErr != nil {
+		return nil, fmt.Errorf("update deal timeline: %w", timelineErr)
+	}
+	logCRMAudit(ctx, s.audit, workspaceID, input.OwnerID, actionDealUpdated, timelineEntityDeal, dealID)
+	deal, getErr := s.Get(ctx, workspaceID, dealID)
+	if getErr != nil {
+		return nil, getErr
+	}
+	publishDealUpdated(ctx, s.bus, deal)
+	return deal, nil
+}
 
-	err := s.querier.UpdateDeal(ctx, sqlcgen.UpdateDealParams{
+// createWithStageHistory inserts the deal with its first deal_stage_history
+// row and its timeline event atomically.
+func (s *DealService) createWithStageHistory(ctx context.Context, id string, input CreateDealInput, customFields *string, now string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin create deal: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	q := sqlcgen.New(tx)
+	if err = q.CreateDeal(ctx, sqlcgen.CreateDealParams{
+		ID:            id,
+		WorkspaceID:   input.WorkspaceID,
 		AccountID:     input.AccountID,
 		ContactID:     nullString(input.ContactID),
 		PipelineID:    input.PipelineID,
@@ -317,23 +358,76 @@ func (s *DealService) Update(ctx context.Context, workspaceID, dealID string, in
 		ExpectedClose: nullString(input.ExpectedClose),
 		Status:        input.Status,
 		Metadata:      nullString(input.Metadata),
-		UpdatedAt:     nowRFC3339(),
-		ID:            dealID,
-		WorkspaceID:   workspaceID,
-	})
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		CustomFields:  customFields,
+	}); err != nil {
+		return fmt.Errorf("create deal: %w", err)
+	}
+	if err = insertDealStageHistory(ctx, tx, input.WorkspaceID, id, "", input.StageID, input.OwnerID, now); err != nil {
+		return err
+	}
+	if timelineErr := createTimelineEvent(ctx, q, input.WorkspaceID, timelineEntityDeal, id, input.OwnerID, timelineActionCreated); timelineErr != nil {
+		return fmt.Errorf("create deal timeline: %w", timelineErr)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit create deal: %w", err)
+	}
+	return nil
+}
+
+// updateWithStageHistory writes the update and, when the stage changes, its
+// deal_stage_history row atomically.
+func (s *DealService) updateWithStageHistory(ctx context.Context, existing *Deal, input UpdateDealInput, customFields *string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("update deal: %w", err)
+		return fmt.Errorf("begin update deal: %w", err)
 	}
-	if timelineErr := createTimelineEvent(ctx, s.querier, workspaceID, timelineEntityDeal, dealID, input.OwnerID, timelineActionUpdated); timelineErr != nil {
-		return nil, fmt.Errorf("update deal timeline: %w", timelineErr)
+	defer func() { _ = tx.Rollback() }()
+
+	now := nowRFC3339()
+	if err = sqlcgen.New(tx).UpdateDeal(ctx, sqlcgen.UpdateDealParams{
+		AccountID:     input.AccountID,
+		ContactID:     nullString(input.ContactID),
+		PipelineID:    input.PipelineID,
+		StageID:       input.StageID,
+		OwnerID:       input.OwnerID,
+		Title:         input.Title,
+		Amount:        input.Amount,
+		Currency:      nullString(input.Currency),
+		ExpectedClose: nullString(input.ExpectedClose),
+		Status:        input.Status,
+		Metadata:      nullString(input.Metadata),
+		UpdatedAt:     now,
+		CustomFields:  customFields,
+		ID:            existing.ID,
+		WorkspaceID:   existing.WorkspaceID,
+	}); err != nil {
+		return fmt.Errorf("update deal: %w", err)
 	}
-	logCRMAudit(ctx, s.audit, workspaceID, input.OwnerID, actionDealUpdated, timelineEntityDeal, dealID)
-	deal, getErr := s.Get(ctx, workspaceID, dealID)
-	if getErr != nil {
-		return nil, getErr
+	if input.StageID != existing.StageID {
+		if err = insertDealStageHistory(ctx, tx, existing.WorkspaceID, existing.ID, existing.StageID, input.StageID, input.OwnerID, now); err != nil {
+			return err
+		}
 	}
-	publishDealUpdated(s.bus, deal)
-	return deal, nil
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit update deal: %w", err)
+	}
+	return nil
+}
+
+// insertDealStageHistory records that deal entered toStageID at createdAt.
+// fromStageID is empty when the deal was just created.
+func insertDealStageHistory(ctx context.Context, db interface {
+	ExecContext(context.Context, string, ...any) (sql.Result, error)
+}, workspaceID, dealID, fromStageID, toStageID, actorID, createdAt string) error {
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO deal_stage_history (id, workspace_id, deal_id, from_stage_id, to_stage_id, actor_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, uuid.NewV7().String(), workspaceID, dealID, nullString(fromStageID), toStageID, nullString(actorID), createdAt); err != nil {
+		return fmt.Errorf("insert deal stage history: %w", err)
+	}
+	return nil
 }
 
 func (s *DealService) Delete(ctx context.Context, workspaceID, dealID string) error {
@@ -350,6 +444,57 @@ func (s *DealService) Delete(ctx context.Context, workspaceID, dealID string) er
 		})
 }
 
+// CloseWon marks an open deal as won, stamping closed_at and the optional
+// reason. It returns sql.ErrNoRows if the deal does not exist and
+// ErrDealNotOpen if it is not open.
+func (s *DealService) CloseWon(ctx context.Context, workspaceID, dealID, reason string) (*Deal, error) {
+	return s.close(ctx, workspaceID, dealID, dealStatusWon, reason, actionDealWon)
+}
+
+// CloseLost marks an open deal as lost. A loss reason is required so losses
+// can be analyzed later.
+func (s *DealService) CloseLost(ctx context.Context, workspaceID, dealID, reason string) (*Deal, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, invalidDealInput("close reason is required for lost deals", nil)
+	}
+	return s.close(ctx, workspaceID, dealID, dealStatusLost, reason, actionDealLost)
+}
+
+func (s *DealService) close(ctx context.Context, workspaceID, dealID, status, reason, action string) (*Deal, error) {
+	existing, err := s.Get(ctx, workspaceID, dealID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Status != dealStatusOpen {
+		return nil, ErrDealNotOpen
+	}
+
+	// The status guard makes a concurrent close lose cleanly.
+	now := nowRFC3339()
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE deal
+		SET status = ?, closed_at = ?, close_reason = ?, updated_at = ?
+		WHERE id = ? AND workspace_id = ? AND status = ? AND deleted_at IS NULL
+	`, status, now, nullString(strings.TrimSpace(reason)), now, dealID, workspaceID, dealStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("close deal: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, ErrDealNotOpen
+	}
+
+	if timelineErr := createTimelineEvent(ctx, s.querier, workspaceID, timelineEntityDeal, dealID, existing.OwnerID, timelineActionUpdated); timelineErr != nil {
+		return nil, fmt.Errorf("close deal timeline: %w", timelineErr)
+	}
+	logCRMAudit(ctx, s.audit, workspaceID, existing.OwnerID, action, timelineEntityDeal, dealID)
+	deal, err := s.Get(ctx, workspaceID, dealID)
+	if err != nil {
+		return nil, err
+	}
+	publishDealUpdated(ctx, s.bus, deal)
+	return deal, nil
+}
+
 func rowToDeal(row sqlcgen.Deal) *Deal {
 	createdAt := parseRFC3339Time(row.CreatedAt)
 	updatedAt := parseRFC3339Time(row.UpdatedAt)
@@ -369,6 +514,9 @@ func rowToDeal(row sqlcgen.Deal) *Deal {
 		ExpectedClose: row.ExpectedClose,
 		Status:        row.Status,
 		Metadata:      row.Metadata,
+		ClosedAt:      parseOptionalRFC3339(row.ClosedAt),
+		CloseReason:   row.CloseReason,
+		CustomFields:  decodeCustomFields(row.CustomFields),
 		CreatedAt:     createdAt,
 		UpdatedAt:     updatedAt,
 		DeletedAt:     deletedAt,
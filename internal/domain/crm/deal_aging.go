@@ -0,0 +1,230 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+)
+
+// TopicDealStalled is published with a DealStalledEvent for each open deal
+// the DealAgingMonitor finds stuck in its stage.
+const TopicDealStalled = "deal.stalled"
+
+// Defaults of NewDealAgingMonitor.
+const (
+	DefaultDealStalledAfter  = 14 * 24 * time.Hour
+	DefaultDealAgingInterval = time.Hour
+)
+
+const dealAgingComponent = "crm.DealAgingMonitor"
+
+// DealStalledEvent is the payload of TopicDealStalled.
+type DealStalledEvent struct {
+	WorkspaceID    string    `json:"workspace_id"`
+	DealID         string    `json:"deal_id"`
+	StageID        string    `json:"stage_id"`
+	OwnerID        string    `json:"owner_id"`
+	DaysInStage    int       `json:"days_in_stage"`
+	StageEnteredAt time.Time `json:"stage_entered_at"`
+	TaskID         string    `json:"task_id"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+// DealAgingMonitor periodically reports open deals that have stayed in their
+// current stage, per deal_stage_history, longer than a threshold: it creates
+// a follow-up task for the owner and publishes TopicDealStalled. Each stay in
+// a stage is reported once; moving the deal to another stage starts over.
+type DealAgingMonitor struct {
+	db           *sql.DB
+	bus          eventbus.EventBus
+	activities   *ActivityService
+	stalledAfter time.Duration
+	interval     time.Duration
+	now          func() time.Time
+}
+
+// NewDealAgingMonitor returns a monitor reporting deals in the same stage for
+// longer than stalledAfter, checking every interval once started. Zero or
+// negative values use DefaultDealStalledAfter and DefaultDealAgingInterval.
+func NewDealAgingMonitor(db *sql.DB, bus eventbus.EventBus, stalledAfter, interval time.Duration) *DealAgingMonitor {
+	if stalledAfter <= 0 {
+		stalledAfter = DefaultDealStalledAfter
+	}
+	if interval <= 0 {
+		interval = DefaultDealAgingInterval
+	}
+	return &DealAgingMonitor{
+		db:           db,
+		bus:          bus,
+		activities:   NewActivityService(db),
+		stalledAfter: stalledAfter,
+		interval:     interval,
+		now:          time.Now,
+	}
+}
+
+// Start checks every workspace right away and then every interval until ctx
+// is done. Failures are logged and retried on the next tick.
+func (m *DealAgingMonitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		if err := m.CheckAll(ctx); err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).Error("deal aging check failed", slog.String(logging.KeyComponent, dealAgingComponent), logging.Err(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll runs Check for every workspace.
+func (m *DealAgingMonitor) CheckAll(ctx context.Context) error {
+	workspaceIDs, err := m.workspaceIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, workspaceID := range workspaceIDs {
+		if _, err = m.Check(ctx, workspaceID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *DealAgingMonitor) workspaceIDs(ctx context.Context) ([]string, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT id FROM workspace`)
+	if err != nil {
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan workspace: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+type stalledDeal struct {
+	historyID      string
+	dealID         string
+	stageID        string
+	ownerID        string
+	title          string
+	stageEnteredAt string
+}
+
+// Check reports the newly stalled open deals of workspaceID and returns the
+// events it published.
+func (m *DealAgingMonitor) Check(ctx context.Context, workspaceID string) ([]DealStalledEvent, error) {
+	now := m.now().UTC()
+	deals, err := m.findStalled(ctx, workspaceID, now.Add(-m.stalledAfter).Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	events := make([]DealStalledEvent, 0, len(deals))
+	for _, deal := range deals {
+		event, reported, reportErr := m.report(ctx, workspaceID, deal, now)
+		if reportErr != nil {
+			return events, reportErr
+		}
+		if reported {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// findStalled returns the open deals whose latest stage history row is for
+// their current stage, was created at or before cutoff and was not reported.
+func (m *DealAgingMonitor) findStalled(ctx context.Context, workspaceID, cutoff string) ([]stalledDeal, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT h.id, d.id, d.stage_id, d.owner_id, d.title, h.created_at
+		FROM deal d
+		JOIN deal_stage_history h ON h.id = (
+			SELECT id FROM deal_stage_history
+			WHERE workspace_id = d.workspace_id AND deal_id = d.id
+			ORDER BY created_at DESC, rowid DESC
+			LIMIT 1
+		)
+		WHERE d.workspace_id = ?
+		  AND d.status = ?
+		  AND d.deleted_at IS NULL
+		  AND h.to_stage_id = d.stage_id
+		  AND h.stalled_at IS NULL
+		  AND h.created_at <= ?
+		ORDER BY h.created_at
+	`, workspaceID, dealStatusOpen, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("find stalled deals: %w", err)
+	}
+	defer rows.Close()
+	var out []stalledDeal
+	for rows.Next() {
+		var d stalledDeal
+		if err = rows.Scan(&d.historyID, &d.dealID, &d.stageID, &d.ownerID, &d.title, &d.stageEnteredAt); err != nil {
+			return nil, fmt.Errorf("scan stalled deal: %w", err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// report claims the stay by stamping stalled_at, so concurrent monitors do
+// not report it twice, then creates the follow-up task and publishes the
+// event. reported is false when another monitor claimed it first.
+func (m *DealAgingMonitor) report(ctx context.Context, workspaceID string, deal stalledDeal, now time.Time) (DealStalledEvent, bool, error) {
+	res, err := m.db.ExecContext(ctx,
+		`UPDATE deal_stage_history SET stalled_at = ? WHERE id = ? AND stalled_at IS NULL`,
+		now.Format(time.RFC3339), deal.historyID)
+	if err != nil {
+		return DealStalledEvent{}, false, fmt.Errorf("mark deal stalled: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return DealStalledEvent{}, false, nil
+	}
+
+	enteredAt := parseRFC3339Time(deal.stageEnteredAt)
+	days := int(math.Floor(now.Sub(enteredAt).Hours() / 24))
+	task, err := m.activities.Create(ctx, CreateActivityInput{
+		WorkspaceID:  workspaceID,
+		ActivityType: "task",
+		EntityType:   timelineEntityDeal,
+		EntityID:     deal.dealID,
+		OwnerID:      deal.ownerID,
+		AssignedTo:   deal.ownerID,
+		Subject:      fmt.Sprintf("Follow up on stalled deal: %s", deal.title),
+		Body:         fmt.Sprintf("This deal has been in its current stage for %d days.", days),
+		DueAt:        now.Add(24 * time.Hour).Format(time.RFC3339),
+	})
+	if err != nil {
+		return DealStalledEvent{}, false, fmt.Errorf("create stalled deal task: %w", err)
+	}
+
+	event := DealStalledEvent{
+		WorkspaceID:    workspaceID,
+		DealID:         deal.dealID,
+		StageID:        deal.stageID,
+		OwnerID:        deal.ownerID,
+		DaysInStage:    days,
+		StageEnteredAt: enteredAt,
+		TaskID:         task.ID,
+		OccurredAt:     now,
+	}
+	if m.bus != nil {
+		m.bus.Publish(TopicDealStalled, event)
+	}
+	return event, true, nil
+}
@@ -0,0 +1,100 @@
+package crm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestDealAgingMonitor_Check_ReportsStalledDealsOnce(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	accountID := "acc-" + randID()
+	if _, err := db.Exec(`INSERT INTO account (id, workspace_id, name, owner_id, created_at, updated_at) VALUES (?, ?, 'Acme', ?, ?, ?)`, accountID, wsID, ownerID, now, now); err != nil {
+		t.Fatalf("seed account error = %v", err)
+	}
+	pipelineID := "pl-" + randID()
+	if _, err := db.Exec(`INSERT INTO pipeline (id, workspace_id, name, entity_type, created_at, updated_at) VALUES (?, ?, 'Sales', 'deal', ?, ?)`, pipelineID, wsID, now, now); err != nil {
+		t.Fatalf("seed pipeline error = %v", err)
+	}
+	discovery, proposal := "st-"+randID(), "st-"+randID()
+	for i, stageID := range []string{discovery, proposal} {
+		if _, err := db.Exec(`INSERT INTO pipeline_stage (id, pipeline_id, name, position, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`, stageID, pipelineID, stageID, i+1, now, now); err != nil {
+			t.Fatalf("seed stage error = %v", err)
+		}
+	}
+
+	deals := crm.NewDealService(db)
+	create := func(title string) *crm.Deal {
+		deal, err := deals.Create(ctx, crm.CreateDealInput{
+			WorkspaceID: wsID, AccountID: accountID, PipelineID: pipelineID, StageID: discovery, OwnerID: ownerID, Title: title,
+		})
+		if err != nil {
+			t.Fatalf("Create(%s) error = %v", title, err)
+		}
+		return deal
+	}
+	stalled, fresh, moved := create("Stalled"), create("Fresh"), create("Moved")
+	if _, err := db.Exec(`UPDATE deal_stage_history SET created_at = ? WHERE deal_id IN (?, ?)`,
+		time.Now().UTC().Add(-30*24*time.Hour).Format(time.RFC3339), stalled.ID, moved.ID); err != nil {
+		t.Fatalf("backdate history error = %v", err)
+	}
+	// Moving to another stage restarts the clock.
+	if _, err := deals.Update(ctx, wsID, moved.ID, crm.UpdateDealInput{
+		AccountID: accountID, PipelineID: pipelineID, StageID: proposal, OwnerID: ownerID, Title: moved.Title, Status: "open",
+	}); err != nil {
+		t.Fatalf("Update(moved) error = %v", err)
+	}
+	var historyRows int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM deal_stage_history WHERE deal_id = ?`, moved.ID).Scan(&historyRows); err != nil {
+		t.Fatalf("count history error = %v", err)
+	}
+	if historyRows != 2 {
+		t.Fatalf("moved deal history rows = %d; want 2", historyRows)
+	}
+
+	bus := eventbus.New()
+	stalledCh := bus.Subscribe(crm.TopicDealStalled)
+	monitor := crm.NewDealAgingMonitor(db, bus, 14*24*time.Hour, time.Hour)
+
+	events, err := monitor.Check(ctx, wsID)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(events) != 1 || events[0].DealID != stalled.ID {
+		t.Fatalf("Check() events = %+v; want only deal %s (fresh %s, moved %s)", events, stalled.ID, fresh.ID, moved.ID)
+	}
+	event := events[0]
+	if event.DaysInStage < 29 || event.StageID != discovery || event.OwnerID != ownerID || event.TaskID == "" {
+		t.Fatalf("event = %+v", event)
+	}
+	select {
+	case <-stalledCh:
+	case <-time.After(time.Second):
+		t.Fatal("expected deal.stalled event")
+	}
+
+	task, err := crm.NewActivityService(db).Get(ctx, wsID, event.TaskID)
+	if err != nil {
+		t.Fatalf("Get(task) error = %v", err)
+	}
+	if task.ActivityType != "task" || task.EntityID != stalled.ID || task.AssignedTo == nil || *task.AssignedTo != ownerID {
+		t.Fatalf("task = %+v", task)
+	}
+
+	again, err := monitor.Check(ctx, wsID)
+	if err != nil {
+		t.Fatalf("second Check() error = %v", err)
+	}
+	if len(again) != 0 {
+		t.Fatalf("second Check() events = %+v; want none", again)
+	}
+}
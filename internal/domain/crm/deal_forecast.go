@@ -0,0 +1,29 @@
+package crm
+
+import (
+	"context"
+	"fmt"
+)
+
+// weightedForecastSQL sums amount × stage probability over the open deals of
+// a pipeline. Deals without an amount, or in a stage without a probability,
+// count as zero.
+const weightedForecastSQL = `
+	SELECT COALESCE(SUM(COALESCE(d.amount, 0) * COALESCE(ps.probability, 0)), 0)
+	FROM deal d
+	JOIN pipeline_stage ps ON ps.id = d.stage_id
+	WHERE d.workspace_id = ?
+	  AND d.pipeline_id = ?
+	  AND d.status = 'open'
+	  AND d.deleted_at IS NULL`
+
+// WeightedForecast returns the probability-weighted value of the open deals
+// in a pipeline: the sum of each deal's amount times its stage's win
+// probability.
+func (s *DealService) WeightedForecast(ctx context.Context, workspaceID, pipelineID string) (float64, error) {
+	var total float64
+	if err := s.db.QueryRowContext(ctx, weightedForecastSQL, workspaceID, pipelineID).Scan(&total); err != nil {
+		return 0, fmt.Errorf("weighted forecast: %w", err)
+	}
+	return total, nil
+}
@@ -3,7 +3,10 @@ package crm_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
@@ -53,19 +56,30 @@ func TestLeadService_CRUD(t *testing.T) {
 	}
 
 	updated, err := svc.Update(context.Background(), wsID, created.ID, crm.UpdateLeadInput{
-		Status:  "qualified",
+		Status:  "contacted",
 		OwnerID: ownerID,
 	})
 	if err != nil {
 		t.Fatalf("Update() error = %v", err)
 	}
-	if updated.Status != "qualified" {
-		t.Fatalf("expected qualified, got %q", updated.Status)
+	if updated.Status != "contacted" {
+		t.Fatalf("expected contacted, got %q", updated.Status)
 	}
 
 	if err := svc.Delete(context.Background(), wsID, created.ID); err != nil {
 		t.Fatalf("Delete() error = %v", err)
 	}
+
+	restored, err := svc.Restore(context.Background(), wsID, created.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Fatalf("expected restored lead, got deletedAt=%v", restored.DeletedAt)
+	}
+	if _, err := svc.Restore(context.Background(), wsID, created.ID); !errors.Is(err, crm.ErrRecordNotDeleted) {
+		t.Fatalf("Restore(live) error = %v; want ErrRecordNotDeleted", err)
+	}
 }
 
 func TestCaseService_CRUD(t *testing.T) {
@@ -116,6 +130,186 @@ func TestCaseService_CRUD(t *testing.T) {
 	if err := svc.Delete(context.Background(), wsID, created.ID); err != nil {
 		t.Fatalf("Delete() error = %v", err)
 	}
+
+	restored, err := svc.Restore(context.Background(), wsID, created.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.Subject != "Case Updated" {
+		t.Fatalf("expected restored case, got %+v", restored)
+	}
+	if _, err := svc.Restore(context.Background(), wsID, "missing"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Restore(missing) error = %v; want sql.ErrNoRows", err)
+	}
+}
+
+func TestCaseService_Escalate(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	bus := eventbus.New()
+	escalations := bus.Subscribe(crm.TopicCaseEscalated)
+	svc := crm.NewCaseServiceWithBus(db, bus)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, crm.CreateCaseInput{
+		WorkspaceID: wsID,
+		OwnerID:     ownerID,
+		Subject:     "Checkout down",
+		Priority:    "medium",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	slaLeft := func(ticket *crm.CaseTicket) time.Duration {
+		t.Helper()
+		if ticket.SLADeadline == nil {
+			t.Fatal("sla_deadline not set")
+		}
+		deadline, parseErr := time.Parse(time.RFC3339, *ticket.SLADeadline)
+		if parseErr != nil {
+			t.Fatalf("parse sla_deadline %q: %v", *ticket.SLADeadline, parseErr)
+		}
+		return time.Until(deadline)
+	}
+
+	high, err := svc.Escalate(ctx, wsID, created.ID, "customer is blocked")
+	if err != nil {
+		t.Fatalf("Escalate() error = %v", err)
+	}
+	if high.Priority != "high" || high.Status != "escalated" {
+		t.Fatalf("after first escalation priority=%q status=%q; want high, escalated", high.Priority, high.Status)
+	}
+	if left := slaLeft(high); left < 23*time.Hour || left > 24*time.Hour {
+		t.Fatalf("high SLA left = %v; want about 24h", left)
+	}
+
+	urgent, err := svc.Escalate(ctx, wsID, created.ID, "revenue impact")
+	if err != nil {
+		t.Fatalf("second Escalate() error = %v", err)
+	}
+	if urgent.Priority != "urgent" {
+		t.Fatalf("after second escalation priority=%q; want urgent", urgent.Priority)
+	}
+	if left := slaLeft(urgent); left < 3*time.Hour || left > 4*time.Hour {
+		t.Fatalf("urgent SLA left = %v; want about 4h", left)
+	}
+
+	if _, err = svc.Escalate(ctx, wsID, created.ID, "again"); !errors.Is(err, crm.ErrCaseAtMaxPriority) {
+		t.Fatalf("Escalate(urgent) error = %v; want ErrCaseAtMaxPriority", err)
+	}
+	if _, err = svc.Escalate(ctx, wsID, "missing", ""); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Escalate(missing) error = %v; want sql.ErrNoRows", err)
+	}
+
+	var historyRows, auditRows int
+	if err = db.QueryRow(
+		`SELECT COUNT(*) FROM case_status_history WHERE case_id = ? AND to_status = 'escalated'`, created.ID,
+	).Scan(&historyRows); err != nil {
+		t.Fatalf("count case_status_history: %v", err)
+	}
+	if err = db.QueryRow(
+		`SELECT COUNT(*) FROM audit_event WHERE entity_id = ? AND action = 'case.escalated'`, created.ID,
+	).Scan(&auditRows); err != nil {
+		t.Fatalf("count audit_event: %v", err)
+	}
+	if historyRows != 2 || auditRows != 2 {
+		t.Fatalf("history rows = %d, audit rows = %d; want 2 and 2", historyRows, auditRows)
+	}
+
+	select {
+	case evt := <-escalations:
+		payload, ok := evt.Payload.(crm.CaseEscalatedEvent)
+		if !ok || payload.CaseID != created.ID || payload.FromPriority != "medium" || payload.ToPriority != "high" {
+			t.Fatalf("first case.escalated payload = %#v", evt.Payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected case.escalated event")
+	}
+}
+
+func TestCaseService_Escalate_KeepsEarlierDeadline(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewCaseService(db)
+	ctx := context.Background()
+
+	deadline := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	created, err := svc.Create(ctx, crm.CreateCaseInput{
+		WorkspaceID: wsID,
+		OwnerID:     ownerID,
+		Subject:     "Checkout down",
+		Priority:    "medium",
+		SLADeadline: deadline,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	escalated, err := svc.Escalate(ctx, wsID, created.ID, "customer is blocked")
+	if err != nil {
+		t.Fatalf("Escalate() error = %v", err)
+	}
+	if escalated.SLADeadline == nil || *escalated.SLADeadline != deadline {
+		t.Fatalf("sla_deadline = %v; want the earlier %s kept", escalated.SLADeadline, deadline)
+	}
+}
+
+func TestCaseService_Reopen(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewCaseService(db)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, crm.CreateCaseInput{
+		WorkspaceID: wsID,
+		OwnerID:     ownerID,
+		Subject:     "Printer jam",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err = svc.Reopen(ctx, wsID, created.ID, "still broken"); !errors.Is(err, crm.ErrCaseNotReopenable) {
+		t.Fatalf("Reopen(open case) error = %v; want ErrCaseNotReopenable", err)
+	}
+
+	if _, err = svc.Update(ctx, wsID, created.ID, crm.UpdateCaseInput{
+		OwnerID:  ownerID,
+		Subject:  created.Subject,
+		Priority: created.Priority,
+		Status:   "resolved",
+	}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	reopened, err := svc.Reopen(ctx, wsID, created.ID, "customer says it is still broken")
+	if err != nil {
+		t.Fatalf("Reopen() error = %v", err)
+	}
+	if reopened.Status != "open" {
+		t.Fatalf("status = %q; want open", reopened.Status)
+	}
+
+	var fromStatus, toStatus, reason string
+	if err = db.QueryRow(
+		`SELECT from_status, to_status, reason FROM case_status_history WHERE workspace_id = ? AND case_id = ?`,
+		wsID, created.ID,
+	).Scan(&fromStatus, &toStatus, &reason); err != nil {
+		t.Fatalf("query case_status_history: %v", err)
+	}
+	if fromStatus != "resolved" || toStatus != "open" || reason != "customer says it is still broken" {
+		t.Fatalf("history = %q -> %q (%q)", fromStatus, toStatus, reason)
+	}
+
+	if _, err = svc.Reopen(ctx, wsID, "missing", ""); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Reopen(missing) error = %v; want sql.ErrNoRows", err)
+	}
 }
 
 func TestCaseService_NewCaseServiceWithBus_PublishesCreatedEvent(t *testing.T) {
@@ -216,6 +410,139 @@ func TestDealService_CRUD(t *testing.T) {
 	}
 }
 
+func TestDealService_WeightedForecast(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	accountID := "acc-" + randID()
+	if _, err := db.Exec(`INSERT INTO account (id, workspace_id, name, owner_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`, accountID, wsID, "Acme", ownerID, now, now); err != nil {
+		t.Fatalf("seed account error = %v", err)
+	}
+	pipelineID := "pl-" + randID()
+	if _, err := db.Exec(`INSERT INTO pipeline (id, workspace_id, name, entity_type, created_at, updated_at) VALUES (?, ?, ?, 'deal', ?, ?)`, pipelineID, wsID, "Sales", now, now); err != nil {
+		t.Fatalf("seed pipeline error = %v", err)
+	}
+	stages := map[string]string{}
+	for i, stage := range []struct {
+		name        string
+		probability any
+	}{{"Discovery", 0.1}, {"Proposal", 0.5}, {"Commit", 0.9}, {"Unscored", nil}} {
+		id := "st-" + randID()
+		if _, err := db.Exec(`INSERT INTO pipeline_stage (id, pipeline_id, name, position, probability, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`, id, pipelineID, stage.name, i+1, stage.probability, now, now); err != nil {
+			t.Fatalf("seed stage error = %v", err)
+		}
+		stages[stage.name] = id
+	}
+
+	svc := crm.NewDealService(db)
+	createDeal := func(stage string, amount float64) *crm.Deal {
+		deal, err := svc.Create(context.Background(), crm.CreateDealInput{
+			WorkspaceID: wsID,
+			AccountID:   accountID,
+			PipelineID:  pipelineID,
+			StageID:     stages[stage],
+			OwnerID:     ownerID,
+			Title:       stage + " deal",
+			Amount:      &amount,
+		})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		return deal
+	}
+	createDeal("Discovery", 1000) // 100
+	createDeal("Proposal", 2000)  // 1000
+	createDeal("Commit", 500)     // 450
+	createDeal("Unscored", 700)   // 0
+	won := createDeal("Commit", 10000)
+	if _, err := svc.CloseWon(context.Background(), wsID, won.ID, "signed"); err != nil {
+		t.Fatalf("CloseWon() error = %v", err)
+	}
+
+	got, err := svc.WeightedForecast(context.Background(), wsID, pipelineID)
+	if err != nil {
+		t.Fatalf("WeightedForecast() error = %v", err)
+	}
+	if math.Abs(got-1550) > 1e-9 {
+		t.Fatalf("WeightedForecast() = %v; want 1550", got)
+	}
+	if empty, err := svc.WeightedForecast(context.Background(), wsID, "missing"); err != nil || empty != 0 {
+		t.Fatalf("WeightedForecast(missing) = %v, %v; want 0, nil", empty, err)
+	}
+}
+
+func TestDealService_CloseWonAndLost(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	now := time.Now().UTC().Format(time.RFC3339)
+	ctx := context.Background()
+
+	accountID := "acc-close-" + randID()
+	if _, err := db.Exec(`INSERT INTO account (id, workspace_id, name, owner_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`, accountID, wsID, "Acme", ownerID, now, now); err != nil {
+		t.Fatalf("seed account: %v", err)
+	}
+	pipelineID := "pl-close-" + randID()
+	if _, err := db.Exec(`INSERT INTO pipeline (id, workspace_id, name, entity_type, created_at, updated_at) VALUES (?, ?, ?, 'deal', ?, ?)`, pipelineID, wsID, "Sales", now, now); err != nil {
+		t.Fatalf("seed pipeline: %v", err)
+	}
+	stageID := "st-close-" + randID()
+	if _, err := db.Exec(`INSERT INTO pipeline_stage (id, pipeline_id, name, position, created_at, updated_at) VALUES (?, ?, ?, 1, ?, ?)`, stageID, pipelineID, "Negotiation", now, now); err != nil {
+		t.Fatalf("seed stage: %v", err)
+	}
+
+	svc := crm.NewDealService(db)
+	newDeal := func(title string) *crm.Deal {
+		t.Helper()
+		d, err := svc.Create(ctx, crm.CreateDealInput{WorkspaceID: wsID, AccountID: accountID, PipelineID: pipelineID, StageID: stageID, OwnerID: ownerID, Title: title})
+		if err != nil {
+			t.Fatalf("Create(%q) error = %v", title, err)
+		}
+		return d
+	}
+
+	won, err := svc.CloseWon(ctx, wsID, newDeal("Won deal").ID, "")
+	if err != nil {
+		t.Fatalf("CloseWon() error = %v", err)
+	}
+	if won.Status != "won" || won.ClosedAt == nil || won.CloseReason != nil {
+		t.Fatalf("won deal = status %q closedAt %v reason %v", won.Status, won.ClosedAt, won.CloseReason)
+	}
+	if _, err = svc.CloseLost(ctx, wsID, won.ID, "changed mind"); !errors.Is(err, crm.ErrDealNotOpen) {
+		t.Fatalf("CloseLost(won deal) error = %v; want ErrDealNotOpen", err)
+	}
+
+	open := newDeal("Lost deal")
+	if _, err = svc.CloseLost(ctx, wsID, open.ID, "  "); !errors.Is(err, crm.ErrInvalidDealInput) {
+		t.Fatalf("CloseLost(no reason) error = %v; want ErrInvalidDealInput", err)
+	}
+	lost, err := svc.CloseLost(ctx, wsID, open.ID, "price too high")
+	if err != nil {
+		t.Fatalf("CloseLost() error = %v", err)
+	}
+	if lost.Status != "lost" || lost.ClosedAt == nil || lost.CloseReason == nil || *lost.CloseReason != "price too high" {
+		t.Fatalf("lost deal = status %q closedAt %v reason %v", lost.Status, lost.ClosedAt, lost.CloseReason)
+	}
+
+	for action, dealID := range map[string]string{"deal.won": won.ID, "deal.lost": lost.ID} {
+		var n int
+		if err = db.QueryRow(`SELECT COUNT(*) FROM audit_event WHERE workspace_id = ? AND action = ? AND entity_id = ?`, wsID, action, dealID).Scan(&n); err != nil {
+			t.Fatalf("count %s audit events: %v", action, err)
+		}
+		if n != 1 {
+			t.Fatalf("%s audit events = %d; want 1", action, n)
+		}
+	}
+
+	if _, err = svc.CloseWon(ctx, wsID, "missing", ""); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("CloseWon(missing) error = %v; want sql.ErrNoRows", err)
+	}
+}
+
 func TestDealService_List_FilterByAccount(t *testing.T) {
 	t.Parallel()
 
@@ -528,6 +855,46 @@ func TestDealService_List_SortAscending(t *testing.T) {
 	}
 }
 
+func TestCaseService_List_FilterByChannelAndCreatedRange(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewCaseService(db)
+
+	seed := func(subject, channel, createdAt string) string {
+		c, err := svc.Create(ctx, crm.CreateCaseInput{WorkspaceID: wsID, OwnerID: ownerID, Subject: subject, Channel: channel})
+		if err != nil {
+			t.Fatalf("seed %s: %v", subject, err)
+		}
+		if _, err = db.Exec(`UPDATE case_ticket SET created_at = ? WHERE id = ?`, createdAt, c.ID); err != nil {
+			t.Fatalf("backdate %s: %v", subject, err)
+		}
+		return c.ID
+	}
+	inRange := seed("Email in range", "email", "2026-03-10T00:00:00Z")
+	seed("Email too old", "email", "2026-01-10T00:00:00Z")
+	seed("Phone in range", "phone", "2026-03-11T00:00:00Z")
+	deleted := seed("Deleted email in range", "email", "2026-03-12T00:00:00Z")
+	if err := svc.Delete(ctx, wsID, deleted); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	items, total, err := svc.List(ctx, wsID, crm.ListCasesInput{
+		Limit:       10,
+		Channel:     "email",
+		CreatedFrom: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		CreatedTo:   time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].ID != inRange {
+		t.Fatalf("List() = %d items (total %d); want only the live in-range email case", len(items), total)
+	}
+}
+
 func TestCaseService_List_SortAscending(t *testing.T) {
 	t.Parallel()
 
@@ -7,10 +7,28 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
 
+// TopicLeadCreated is published with a LeadCreatedEvent for each new lead.
+const TopicLeadCreated = "lead.created"
+
+// LeadCreatedEvent is the payload of TopicLeadCreated.
+type LeadCreatedEvent struct {
+	WorkspaceID string    `json:"workspace_id"`
+	LeadID      string    `json:"lead_id"`
+	OwnerID     string    `json:"owner_id"`
+	Source      string    `json:"source,omitempty"`
+	Status      string    `json:"status"`
+	OccurredAt  time.Time `json:"occurred_at"`
+	// AgentRunID is the agent run that created the lead, if any.
+	AgentRunID string `json:"agent_run_id,omitempty"`
+}
+
 type Lead struct {
 	ID          string     `json:"id"`
 	WorkspaceID string     `json:"workspaceId"`
@@ -35,6 +53,9 @@ type CreateLeadInput struct {
 	OwnerID     string
 	Score       *float64
 	Metadata    string
+	// ReturnExisting makes Create return an existing lead that matches on
+	// email or phone (see FindDuplicates) instead of inserting a new one.
+	ReturnExisting bool
 }
 
 type UpdateLeadInput struct {
@@ -53,21 +74,40 @@ type ListLeadsInput struct {
 }
 
 type LeadService struct {
-	db      *sql.DB
+	db      *sqlite.ContextDB
 	querier sqlcgen.Querier
+	bus     eventbus.EventBus
 	audit   auditLogger
 }
 
 func NewLeadService(db *sql.DB) *LeadService {
-	return &LeadService{db: db, querier: sqlcgen.New(db), audit: newCRMAuditService(db)}
+	cdb := sqlite.NewContextDB(db)
+	return &LeadService{db: cdb, querier: sqlcgen.New(cdb), audit: newCRMAuditService(db)}
+}
+
+// NewLeadServiceWithBus returns a LeadService that publishes TopicLeadCreated.
+func NewLeadServiceWithBus(db *sql.DB, bus eventbus.EventBus) *LeadService {
+	svc := NewLeadService(db)
+	svc.bus = bus
+	return svc
 }
 
 func (s *LeadService) Create(ctx context.Context, input CreateLeadInput) (*Lead, error) {
+	if input.ReturnExisting {
+		existing, err := s.findAutoDedupMatch(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			return existing, nil
+		}
+	}
+
 	id := uuid.NewV7().String()
 	now := nowRFC3339()
 	status := input.Status
 	if status == "" {
-		status = "new"
+		status = leadStatusNew
 	}
 
 	err := s.querier.CreateLead(ctx, sqlcgen.CreateLeadParams{
@@ -91,7 +131,31 @@ func (s *LeadService) Create(ctx context.Context, input CreateLeadInput) (*Lead,
 	}
 	logCRMAudit(ctx, s.audit, input.WorkspaceID, input.OwnerID, actionLeadCreated, timelineEntityLead, id)
 
-	return s.Get(ctx, input.WorkspaceID, id)
+	lead, err := s.getScored(ctx, input.WorkspaceID, id, input.Score == nil)
+	if err != nil {
+		return nil, err
+	}
+	s.publishCreated(ctx, lead)
+	return lead, nil
+}
+
+func (s *LeadService) publishCreated(ctx context.Context, lead *Lead) {
+	if s.bus == nil {
+		return
+	}
+	var source string
+	if lead.Source != nil {
+		source = *lead.Source
+	}
+	publishAfterCommit(ctx, s.bus, TopicLeadCreated, LeadCreatedEvent{
+		WorkspaceID: lead.WorkspaceID,
+		LeadID:      lead.ID,
+		OwnerID:     lead.OwnerID,
+		Source:      source,
+		Status:      lead.Status,
+		OccurredAt:  lead.CreatedAt,
+		AgentRunID:  agentRunID(ctx),
+	})
 }
 
 func (s *LeadService) Get(ctx context.Context, workspaceID, leadID string) (*Lead, error) {
@@ -133,8 +197,54 @@ func (s *LeadService) ListByOwner(ctx context.Context, workspaceID, ownerID stri
 	return out, nil
 }
 
+// Update changes a lead. A status change must follow the lead lifecycle
+// (see leadStatusTransitions) or ErrInvalidLeadTransition is returned; each
+// change is recorded in lead_status_history. An empty status keeps the
+// current one. Returns sql.ErrNoRows if the lead does not exist.
 func (s *LeadService) Update(ctx context.Context, workspaceID, leadID string, input UpdateLeadInput) (*Lead, error) {
-	err := s.querier.UpdateLead(ctx, sqlcgen.UpdateLeadParams{
+	existing, err := s.Get(ctx, workspaceID, leadID)
+	if err != nil {
+		return nil, err
+	}
+	input.Status = firstNonEmpty(input.Status, existing.Status)
+	if err = validateLeadTransition(existing.Status, input.Status); err != nil {
+		return nil, err
+	}
+	if err = s.updateWithHistory(ctx, existing, input); err != nil {
+		return nil, err
+	}
+	if timelineErr := createTimelineEvent(ctx, s.querier, workspaceID, timelineEntityLead, leadID, input.OwnerID, timelineActionUpdated); timelineErr != nil {
+		return nil, fmt.Errorf("update lead timeline: %w", timelineErr)
+	}
+	logCRMAudit(ctx, s.audit, workspaceID, input.OwnerID, actionLeadUpdated, timelineEntityLead, leadID)
+
+	return s.getScored(ctx, workspaceID, leadID, input.Score == nil)
+}
+
+// updateWithHistory writes the update and, when the status changes, its
+// lead_status_history row atomically. The status guard makes an update that
+// raced a concurrent status change fail with ErrInvalidLeadTransition instead
+// of applying a transition that was validated against a stale status. The
+// history actor is the user in ctx, not the lead owner.
+func (s *LeadService) updateWithHistory(ctx context.Context, existing *Lead, input UpdateLeadInput) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin update lead: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := nowRFC3339()
+	res, err := tx.ExecContext(ctx, `
+		UPDATE lead SET status = ?
+		WHERE id = ? AND workspace_id = ? AND status = ? AND deleted_at IS NULL
+	`, input.Status, existing.ID, existing.WorkspaceID, existing.Status)
+	if err != nil {
+		return fmt.Errorf("update lead status: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("%w: %s changed concurrently", ErrInvalidLeadTransition, existing.Status)
+	}
+	if err = sqlcgen.New(tx).UpdateLead(ctx, sqlcgen.UpdateLeadParams{
 		ContactID:   nullString(input.ContactID),
 		AccountID:   nullString(input.AccountID),
 		Source:      nullString(input.Source),
@@ -142,19 +252,39 @@ func (s *LeadService) Update(ctx context.Context, workspaceID, leadID string, in
 		OwnerID:     input.OwnerID,
 		Score:       input.Score,
 		Metadata:    nullString(input.Metadata),
-		UpdatedAt:   nowRFC3339(),
-		ID:          leadID,
-		WorkspaceID: workspaceID,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("update lead: %w", err)
+		UpdatedAt:   now,
+		ID:          existing.ID,
+		WorkspaceID: existing.WorkspaceID,
+	}); err != nil {
+		return fmt.Errorf("update lead: %w", err)
 	}
-	if timelineErr := createTimelineEvent(ctx, s.querier, workspaceID, timelineEntityLead, leadID, input.OwnerID, timelineActionUpdated); timelineErr != nil {
-		return nil, fmt.Errorf("update lead timeline: %w", timelineErr)
+	if input.Status != existing.Status {
+		actorID, _ := ctx.Value(ctxkeys.UserID).(string)
+		if _, err = tx.ExecContext(ctx, `
+			INSERT INTO lead_status_history (id, workspace_id, lead_id, from_status, to_status, actor_id, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, uuid.NewV7().String(), existing.WorkspaceID, existing.ID, existing.Status, input.Status,
+			nullString(actorID), now); err != nil {
+			return fmt.Errorf("insert lead status history: %w", err)
+		}
 	}
-	logCRMAudit(ctx, s.audit, workspaceID, input.OwnerID, actionLeadUpdated, timelineEntityLead, leadID)
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit update lead: %w", err)
+	}
+	return nil
+}
 
-	return s.Get(ctx, workspaceID, leadID)
+// getScored returns the lead, first recomputing its score when rescore is
+// set. An explicit score on create or update is kept as a manual override.
+func (s *LeadService) getScored(ctx context.Context, workspaceID, leadID string, rescore bool) (*Lead, error) {
+	lead, err := s.Get(ctx, workspaceID, leadID)
+	if err != nil || !rescore {
+		return lead, err
+	}
+	if _, err := s.scoreLead(ctx, lead); err != nil {
+		return nil, fmt.Errorf("score lead: %w", err)
+	}
+	return lead, nil
 }
 
 func (s *LeadService) Delete(ctx context.Context, workspaceID, leadID string) error {
@@ -171,6 +301,28 @@ func (s *LeadService) Delete(ctx context.Context, workspaceID, leadID string) er
 		})
 }
 
+// Restore clears deleted_at on a soft-deleted lead and returns it.
+// Returns sql.ErrNoRows if the lead does not exist and ErrRecordNotDeleted
+// if it is not deleted.
+func (s *LeadService) Restore(ctx context.Context, workspaceID, leadID string) (*Lead, error) {
+	err := restoreSoftDeleted(ctx, s.db, "lead", workspaceID, leadID, func(now string) (int64, error) {
+		return s.querier.RestoreLead(ctx, sqlcgen.RestoreLeadParams{UpdatedAt: now, ID: leadID, WorkspaceID: workspaceID})
+	})
+	if err != nil {
+		return nil, err
+	}
+	restored, err := s.Get(ctx, workspaceID, leadID)
+	if err != nil {
+		return nil, err
+	}
+	// timeline_event has no "restored" type; the audit action carries it.
+	if timelineErr := createTimelineEvent(ctx, s.querier, workspaceID, timelineEntityLead, leadID, restored.OwnerID, timelineActionUpdated); timelineErr != nil {
+		return nil, fmt.Errorf("restore lead timeline: %w", timelineErr)
+	}
+	logCRMAudit(ctx, s.audit, workspaceID, restored.OwnerID, actionLeadRestored, timelineEntityLead, leadID)
+	return restored, nil
+}
+
 func rowToLead(row sqlcgen.Lead) *Lead {
 	createdAt := parseRFC3339Time(row.CreatedAt)
 	updatedAt := parseRFC3339Time(row.UpdatedAt)
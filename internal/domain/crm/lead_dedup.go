@@ -0,0 +1,246 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Duplicate match scores. A lead matching on several fields takes the
+// highest score; email and phone identify a person, name+account only
+// suggests one.
+const (
+	leadMatchScoreEmail       = 1.0
+	leadMatchScorePhone       = 0.9
+	leadMatchScoreNameAccount = 0.6
+
+	// leadAutoDedupMinScore is the score Create requires before it returns an
+	// existing lead instead of inserting. Name+account alone is not enough.
+	leadAutoDedupMinScore = leadMatchScorePhone
+
+	// leadMinPhoneDigits ignores short fragments ("ext 12") as phone numbers.
+	leadMinPhoneDigits = 7
+)
+
+// Fields reported in LeadDuplicate.MatchedOn.
+const (
+	LeadMatchFieldEmail       = "email"
+	LeadMatchFieldPhone       = "phone"
+	LeadMatchFieldNameAccount = "name_account"
+)
+
+// LeadMatchInput identifies a person to look for among existing leads.
+// Email, Phone and Name are normalized before comparison; Name only counts
+// together with AccountID.
+type LeadMatchInput struct {
+	Email     string
+	Phone     string
+	Name      string
+	AccountID string
+	// ExcludeLeadID skips one lead, typically the one being checked.
+	ExcludeLeadID string
+}
+
+// LeadDuplicate is an existing lead that likely refers to the same person.
+type LeadDuplicate struct {
+	Lead      *Lead    `json:"lead"`
+	Score     float64  `json:"score"`
+	MatchedOn []string `json:"matchedOn"`
+}
+
+// leadIdentity is the normalized identity of a lead. Leads carry no contact
+// fields of their own: they come from the linked contact, falling back to
+// the email/phone/name keys of the lead metadata written by web forms.
+type leadIdentity struct {
+	email     string
+	phone     string
+	name      string
+	accountID string
+}
+
+// FindDuplicates returns the non-deleted leads of the workspace that match
+// input, highest score first.
+func (s *LeadService) FindDuplicates(ctx context.Context, workspaceID string, input LeadMatchInput) ([]LeadDuplicate, error) {
+	want := leadIdentity{
+		email:     normalizeLeadEmail(input.Email),
+		phone:     normalizeLeadPhone(input.Phone),
+		name:      normalizeLeadName(input.Name),
+		accountID: strings.TrimSpace(input.AccountID),
+	}
+	if want.email == "" && want.phone == "" && (want.name == "" || want.accountID == "") {
+		return []LeadDuplicate{}, nil
+	}
+
+	candidates, err := s.listLeadIdentities(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]LeadDuplicate, 0)
+	for id, got := range candidates {
+		if id == input.ExcludeLeadID {
+			continue
+		}
+		score, matchedOn := scoreLeadMatch(want, got)
+		if score == 0 {
+			continue
+		}
+		lead, getErr := s.Get(ctx, workspaceID, id)
+		if getErr != nil {
+			return nil, getErr
+		}
+		out = append(out, LeadDuplicate{Lead: lead, Score: score, MatchedOn: matchedOn})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		return out[i].Lead.CreatedAt.Before(out[j].Lead.CreatedAt)
+	})
+	return out, nil
+}
+
+// findAutoDedupMatch returns the existing lead Create should reuse for
+// input, or nil when there is no strong enough match.
+func (s *LeadService) findAutoDedupMatch(ctx context.Context, input CreateLeadInput) (*Lead, error) {
+	match, err := s.matchInputForCreate(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	dups, err := s.FindDuplicates(ctx, input.WorkspaceID, match)
+	if err != nil {
+		return nil, err
+	}
+	if len(dups) == 0 || dups[0].Score < leadAutoDedupMinScore {
+		return nil, nil
+	}
+	return dups[0].Lead, nil
+}
+
+// matchInputForCreate derives the identity of a lead that is about to be
+// created, the same way listLeadIdentities does for stored leads.
+func (s *LeadService) matchInputForCreate(ctx context.Context, input CreateLeadInput) (LeadMatchInput, error) {
+	meta := leadMetadataIdentity(input.Metadata)
+	match := LeadMatchInput{Email: meta.email, Phone: meta.phone, Name: meta.name, AccountID: input.AccountID}
+	if input.ContactID == "" {
+		return match, nil
+	}
+
+	var email, phone sql.NullString
+	var firstName, lastName string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT email, phone, first_name, last_name
+		FROM contact
+		WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL
+	`, input.ContactID, input.WorkspaceID).Scan(&email, &phone, &firstName, &lastName)
+	if errors.Is(err, sql.ErrNoRows) {
+		return match, nil
+	}
+	if err != nil {
+		return LeadMatchInput{}, fmt.Errorf("load lead contact: %w", err)
+	}
+	match.Email = firstNonEmpty(email.String, match.Email)
+	match.Phone = firstNonEmpty(phone.String, match.Phone)
+	match.Name = firstNonEmpty(strings.TrimSpace(firstName+" "+lastName), match.Name)
+	return match, nil
+}
+
+func (s *LeadService) listLeadIdentities(ctx context.Context, workspaceID string) (map[string]leadIdentity, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT l.id, l.account_id, l.metadata, c.email, c.phone, c.first_name, c.last_name
+		FROM lead l
+		LEFT JOIN contact c ON c.id = l.contact_id AND c.deleted_at IS NULL
+		WHERE l.workspace_id = ? AND l.deleted_at IS NULL
+	`, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("list lead identities: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[string]leadIdentity)
+	for rows.Next() {
+		var id string
+		var accountID, metadata, email, phone, firstName, lastName sql.NullString
+		if err = rows.Scan(&id, &accountID, &metadata, &email, &phone, &firstName, &lastName); err != nil {
+			return nil, fmt.Errorf("scan lead identity: %w", err)
+		}
+		meta := leadMetadataIdentity(metadata.String)
+		out[id] = leadIdentity{
+			email:     firstNonEmpty(normalizeLeadEmail(email.String), meta.email),
+			phone:     firstNonEmpty(normalizeLeadPhone(phone.String), meta.phone),
+			name:      firstNonEmpty(normalizeLeadName(firstName.String+" "+lastName.String), meta.name),
+			accountID: accountID.String,
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate lead identities: %w", err)
+	}
+	return out, nil
+}
+
+func scoreLeadMatch(want, got leadIdentity) (float64, []string) {
+	var score float64
+	var matchedOn []string
+	if want.email != "" && want.email == got.email {
+		score = max(score, leadMatchScoreEmail)
+		matchedOn = append(matchedOn, LeadMatchFieldEmail)
+	}
+	if want.phone != "" && want.phone == got.phone {
+		score = max(score, leadMatchScorePhone)
+		matchedOn = append(matchedOn, LeadMatchFieldPhone)
+	}
+	if want.name != "" && want.accountID != "" && want.name == got.name && want.accountID == got.accountID {
+		score = max(score, leadMatchScoreNameAccount)
+		matchedOn = append(matchedOn, LeadMatchFieldNameAccount)
+	}
+	return score, matchedOn
+}
+
+// leadMetadataIdentity reads the optional email/phone/name keys of a lead's
+// metadata JSON, normalized. Malformed metadata yields an empty identity.
+func leadMetadataIdentity(metadata string) leadIdentity {
+	if strings.TrimSpace(metadata) == "" {
+		return leadIdentity{}
+	}
+	var fields struct {
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+		Name  string `json:"name"`
+	}
+	if json.Unmarshal([]byte(metadata), &fields) != nil {
+		return leadIdentity{}
+	}
+	return leadIdentity{
+		email: normalizeLeadEmail(fields.Email),
+		phone: normalizeLeadPhone(fields.Phone),
+		name:  normalizeLeadName(fields.Name),
+	}
+}
+
+func normalizeLeadEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// normalizeLeadPhone keeps only digits so "+1 (555) 010-2000" and
+// "15550102000" compare equal.
+func normalizeLeadPhone(phone string) string {
+	digits := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, phone)
+	if len(digits) < leadMinPhoneDigits {
+		return ""
+	}
+	return digits
+}
+
+func normalizeLeadName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
@@ -0,0 +1,121 @@
+package crm_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestLeadService_FindDuplicates(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewLeadService(db)
+	contactSvc := crm.NewContactService(db)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	accountID := createAccount(t, db, wsID, ownerID)
+
+	contact, err := contactSvc.Create(ctx, crm.CreateContactInput{
+		WorkspaceID: wsID,
+		AccountID:   accountID,
+		FirstName:   "Ada",
+		LastName:    "Lovelace",
+		Email:       "Ada@Example.com",
+		OwnerID:     ownerID,
+	})
+	if err != nil {
+		t.Fatalf("create contact: %v", err)
+	}
+	byContact, err := svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: wsID, ContactID: contact.ID, AccountID: accountID, OwnerID: ownerID})
+	if err != nil {
+		t.Fatalf("create lead: %v", err)
+	}
+	byForm, err := svc.Create(ctx, crm.CreateLeadInput{
+		WorkspaceID: wsID,
+		OwnerID:     ownerID,
+		Source:      "website",
+		Metadata:    `{"email":"grace@example.com","phone":"+1 (555) 010-2000","name":"Grace Hopper"}`,
+	})
+	if err != nil {
+		t.Fatalf("create form lead: %v", err)
+	}
+	deleted, err := svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID, Metadata: `{"email":"grace@example.com"}`})
+	if err != nil {
+		t.Fatalf("create deleted lead: %v", err)
+	}
+	if err = svc.Delete(ctx, wsID, deleted.ID); err != nil {
+		t.Fatalf("delete lead: %v", err)
+	}
+
+	otherWS, otherOwner := setupWorkspaceAndOwner(t, db)
+	if _, err = svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: otherWS, OwnerID: otherOwner, Metadata: `{"email":"ada@example.com"}`}); err != nil {
+		t.Fatalf("create other workspace lead: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		input     crm.LeadMatchInput
+		wantID    string
+		wantScore float64
+	}{
+		{"email is case-insensitive", crm.LeadMatchInput{Email: " ada@EXAMPLE.com "}, byContact.ID, 1.0},
+		{"phone ignores formatting", crm.LeadMatchInput{Phone: "15550102000"}, byForm.ID, 0.9},
+		{"name needs account", crm.LeadMatchInput{Name: "ada  lovelace", AccountID: accountID}, byContact.ID, 0.6},
+		{"name alone does not match", crm.LeadMatchInput{Name: "Ada Lovelace"}, "", 0},
+		{"excluded lead", crm.LeadMatchInput{Email: "ada@example.com", ExcludeLeadID: byContact.ID}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dups, findErr := svc.FindDuplicates(ctx, wsID, tt.input)
+			if findErr != nil {
+				t.Fatalf("FindDuplicates() error = %v", findErr)
+			}
+			if tt.wantID == "" {
+				if len(dups) != 0 {
+					t.Fatalf("expected no duplicates, got %+v", dups)
+				}
+				return
+			}
+			if len(dups) != 1 || dups[0].Lead.ID != tt.wantID || dups[0].Score != tt.wantScore {
+				t.Fatalf("duplicates = %+v; want %s with score %v", dups, tt.wantID, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestLeadService_Create_ReturnExisting(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewLeadService(db)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+
+	first, err := svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID, Metadata: `{"email":"lin@example.com"}`})
+	if err != nil {
+		t.Fatalf("create lead: %v", err)
+	}
+
+	again, err := svc.Create(ctx, crm.CreateLeadInput{
+		WorkspaceID:    wsID,
+		OwnerID:        ownerID,
+		Metadata:       `{"email":"LIN@example.com"}`,
+		ReturnExisting: true,
+	})
+	if err != nil {
+		t.Fatalf("create duplicate lead: %v", err)
+	}
+	if again.ID != first.ID {
+		t.Fatalf("expected existing lead %s, got %s", first.ID, again.ID)
+	}
+
+	forced, err := svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID, Metadata: `{"email":"lin@example.com"}`})
+	if err != nil {
+		t.Fatalf("create without dedup: %v", err)
+	}
+	if forced.ID == first.ID {
+		t.Fatal("expected a new lead when ReturnExisting is false")
+	}
+}
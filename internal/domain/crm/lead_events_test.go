@@ -0,0 +1,40 @@
+package crm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestLeadService_Create_PublishesLeadCreated(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	bus := eventbus.New()
+	createdCh := bus.Subscribe(crm.TopicLeadCreated)
+
+	ctx := context.WithValue(context.Background(), ctxkeys.RunID, "run-1")
+	lead, err := crm.NewLeadServiceWithBus(db, bus).Create(ctx, crm.CreateLeadInput{
+		WorkspaceID: wsID, OwnerID: ownerID, Source: "web",
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	select {
+	case evt := <-createdCh:
+		payload, ok := evt.Payload.(crm.LeadCreatedEvent)
+		if !ok {
+			t.Fatalf("payload type = %T", evt.Payload)
+		}
+		if payload.LeadID != lead.ID || payload.WorkspaceID != wsID || payload.Source != "web" || payload.AgentRunID != "run-1" {
+			t.Fatalf("payload = %+v", payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected lead.created to be published")
+	}
+}
@@ -0,0 +1,287 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
+)
+
+const (
+	// leadRecencyWindow is the lead age at which the recency signal reaches 0.
+	leadRecencyWindow = 90 * 24 * time.Hour
+	// leadKnowledgeMatchesForFull is the knowledge match count that maxes out
+	// the knowledge signal.
+	leadKnowledgeMatchesForFull = 3
+)
+
+// LeadScoreWeights weighs each lead score signal. Only the ratios matter;
+// workspaces override the defaults in settings:
+// {"lead_scoring": {"weights": {"size_segment": 40, "source": 20, "recency": 20, "knowledge": 20}}}.
+type LeadScoreWeights struct {
+	SizeSegment float64 `json:"sizeSegment"`
+	Source      float64 `json:"source"`
+	Recency     float64 `json:"recency"`
+	Knowledge   float64 `json:"knowledge"`
+}
+
+// DefaultLeadScoreWeights is used when a workspace configures no weights.
+var DefaultLeadScoreWeights = LeadScoreWeights{SizeSegment: 30, Source: 25, Recency: 25, Knowledge: 20}
+
+// LeadScoreSignals are the inputs of a lead score, each in [0, 1].
+type LeadScoreSignals struct {
+	SizeSegment float64 `json:"sizeSegment"`
+	Source      float64 `json:"source"`
+	Recency     float64 `json:"recency"`
+	Knowledge   float64 `json:"knowledge"`
+}
+
+// LeadScore is a computed score with the signals and weights behind it.
+type LeadScore struct {
+	LeadID  string           `json:"leadId"`
+	Score   float64          `json:"score"`
+	Signals LeadScoreSignals `json:"signals"`
+	Weights LeadScoreWeights `json:"weights"`
+}
+
+// ListLeadsByScoreInput pages the prioritized lead queue. Status, when set,
+// restricts the queue to leads in that status.
+type ListLeadsByScoreInput struct {
+	Limit  int
+	Offset int
+	Status string
+}
+
+var leadSizeSegmentSignals = map[string]float64{
+	"enterprise": 1.0,
+	"mid":        0.6,
+	"smb":        0.3,
+}
+
+var leadSourceSignals = map[string]float64{
+	"referral":   1.0,
+	"partner":    0.9,
+	"event":      0.7,
+	"trade_show": 0.7,
+	"website":    0.5,
+	"inbound":    0.5,
+	"outbound":   0.2,
+}
+
+// leadOtherSourceSignal scores a source outside leadSourceSignals.
+const leadOtherSourceSignal = 0.3
+
+// ComputeLeadScore combines signals into a 0–100 score using weights.
+// Negative weights count as zero; all-zero weights fall back to
+// DefaultLeadScoreWeights.
+func ComputeLeadScore(signals LeadScoreSignals, weights LeadScoreWeights) float64 {
+	weights = normalizeLeadScoreWeights(weights)
+	total := weights.SizeSegment + weights.Source + weights.Recency + weights.Knowledge
+	weighted := signals.SizeSegment*weights.SizeSegment +
+		signals.Source*weights.Source +
+		signals.Recency*weights.Recency +
+		signals.Knowledge*weights.Knowledge
+	return math.Round(100 * weighted / total)
+}
+
+func normalizeLeadScoreWeights(weights LeadScoreWeights) LeadScoreWeights {
+	weights.SizeSegment = math.Max(weights.SizeSegment, 0)
+	weights.Source = math.Max(weights.Source, 0)
+	weights.Recency = math.Max(weights.Recency, 0)
+	weights.Knowledge = math.Max(weights.Knowledge, 0)
+	if weights.SizeSegment+weights.Source+weights.Recency+weights.Knowledge == 0 {
+		return DefaultLeadScoreWeights
+	}
+	return weights
+}
+
+// LeadSourceSignal scores a lead source: known sources by their value,
+// any other non-empty source low, and no source zero.
+func LeadSourceSignal(source string) float64 {
+	source = strings.ToLower(strings.TrimSpace(source))
+	if source == "" {
+		return 0
+	}
+	if signal, ok := leadSourceSignals[source]; ok {
+		return signal
+	}
+	return leadOtherSourceSignal
+}
+
+// LeadRecencySignal decays linearly from 1 for a lead created at now to 0
+// for a lead leadRecencyWindow old, counted in whole days.
+func LeadRecencySignal(createdAt, now time.Time) float64 {
+	days := math.Floor(now.Sub(createdAt).Hours() / 24)
+	if days <= 0 {
+		return 1
+	}
+	return math.Max(0, 1-days*24/leadRecencyWindow.Hours())
+}
+
+// Score computes the lead's score from its account size segment, source,
+// age and knowledge base matches, stores it on the lead and returns it.
+func (s *LeadService) Score(ctx context.Context, workspaceID, leadID string) (*LeadScore, error) {
+	lead, err := s.Get(ctx, workspaceID, leadID)
+	if err != nil {
+		return nil, err
+	}
+	return s.scoreLead(ctx, lead)
+}
+
+func (s *LeadService) scoreLead(ctx context.Context, lead *Lead) (*LeadScore, error) {
+	weights, err := s.loadLeadScoreWeights(ctx, lead.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	signals, err := s.leadScoreSignals(ctx, lead)
+	if err != nil {
+		return nil, err
+	}
+	score := ComputeLeadScore(signals, weights)
+
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE lead SET score = ? WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL`,
+		score, lead.ID, lead.WorkspaceID,
+	); err != nil {
+		return nil, fmt.Errorf("store lead score: %w", err)
+	}
+	lead.Score = &score
+	return &LeadScore{LeadID: lead.ID, Score: score, Signals: signals, Weights: normalizeLeadScoreWeights(weights)}, nil
+}
+
+func (s *LeadService) leadScoreSignals(ctx context.Context, lead *Lead) (LeadScoreSignals, error) {
+	signals := LeadScoreSignals{
+		Source:  LeadSourceSignal(stringValue(lead.Source)),
+		Recency: LeadRecencySignal(lead.CreatedAt, time.Now().UTC()),
+	}
+
+	var accountName string
+	if lead.AccountID != nil && *lead.AccountID != "" {
+		var segment sql.NullString
+		err := s.db.QueryRowContext(ctx,
+			`SELECT name, size_segment FROM account WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL`,
+			*lead.AccountID, lead.WorkspaceID,
+		).Scan(&accountName, &segment)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return LeadScoreSignals{}, fmt.Errorf("load lead account: %w", err)
+		}
+		signals.SizeSegment = leadSizeSegmentSignals[strings.ToLower(segment.String)]
+	}
+
+	matches, err := s.countLeadKnowledgeMatches(ctx, lead, accountName)
+	if err != nil {
+		return LeadScoreSignals{}, err
+	}
+	signals.Knowledge = math.Min(float64(matches)/leadKnowledgeMatchesForFull, 1)
+	return signals, nil
+}
+
+// countLeadKnowledgeMatches counts live knowledge items linked to the lead or
+// its account, or mentioning the account name in their title or content.
+// Links are looked up by idx_knowledge_entity and mentions through the
+// knowledge full-text index, so no write scans the workspace's content.
+func (s *LeadService) countLeadKnowledgeMatches(ctx context.Context, lead *Lead, accountName string) (int64, error) {
+	query := `
+		SELECT id FROM knowledge_item
+		WHERE entity_type = 'lead' AND entity_id = ? AND workspace_id = ? AND deleted_at IS NULL`
+	args := []any{lead.ID, lead.WorkspaceID}
+	if accountID := stringValue(lead.AccountID); accountID != "" {
+		query += `
+		UNION
+		SELECT id FROM knowledge_item
+		WHERE entity_type = 'account' AND entity_id = ? AND workspace_id = ? AND deleted_at IS NULL`
+		args = append(args, accountID, lead.WorkspaceID)
+	}
+	if phrase := ftsPhrase(accountName); phrase != "" {
+		query += `
+		UNION
+		SELECT ki.id FROM knowledge_item_fts
+		JOIN knowledge_item ki ON ki.id = knowledge_item_fts.id
+		WHERE knowledge_item_fts MATCH ? AND knowledge_item_fts.workspace_id = ? AND ki.deleted_at IS NULL`
+		args = append(args, phrase, lead.WorkspaceID)
+	}
+
+	var matches int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (`+query+`)`, args...).Scan(&matches); err != nil {
+		return 0, fmt.Errorf("count lead knowledge matches: %w", err)
+	}
+	return matches, nil
+}
+
+// ftsPhrase quotes text as a single FTS5 phrase, or returns "" for blank text.
+func ftsPhrase(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+	return `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+}
+
+func (s *LeadService) loadLeadScoreWeights(ctx context.Context, workspaceID string) (LeadScoreWeights, error) {
+	var weights LeadScoreWeights
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(json_extract(settings, '$.lead_scoring.weights.size_segment'), 0),
+		       COALESCE(json_extract(settings, '$.lead_scoring.weights.source'), 0),
+		       COALESCE(json_extract(settings, '$.lead_scoring.weights.recency'), 0),
+		       COALESCE(json_extract(settings, '$.lead_scoring.weights.knowledge'), 0)
+		FROM workspace
+		WHERE id = ? AND json_valid(settings)`,
+		workspaceID,
+	).Scan(&weights.SizeSegment, &weights.Source, &weights.Recency, &weights.Knowledge)
+	if errors.Is(err, sql.ErrNoRows) {
+		return DefaultLeadScoreWeights, nil
+	}
+	if err != nil {
+		return LeadScoreWeights{}, fmt.Errorf("load lead score weights: %w", err)
+	}
+	return normalizeLeadScoreWeights(weights), nil
+}
+
+// ListByScore returns the workspace's live leads highest score first, as a
+// prioritized queue. Unscored leads come last; ties go to the newest lead.
+func (s *LeadService) ListByScore(ctx context.Context, workspaceID string, input ListLeadsByScoreInput) ([]*Lead, int, error) {
+	where := `WHERE workspace_id = ? AND deleted_at IS NULL`
+	args := []any{workspaceID}
+	if input.Status != "" {
+		where += ` AND status = ?`
+		args = append(args, input.Status)
+	}
+
+	var total int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM lead `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count leads by score: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, workspace_id, contact_id, account_id, source, status, owner_id, score, metadata, created_at, updated_at, deleted_at
+		FROM lead `+where+`
+		ORDER BY score IS NULL, score DESC, created_at DESC, id DESC
+		LIMIT ? OFFSET ?`,
+		append(args, input.Limit, input.Offset)...,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list leads by score: %w", err)
+	}
+	defer rows.Close()
+
+	leads := make([]*Lead, 0)
+	for rows.Next() {
+		var row sqlcgen.Lead
+		if err := rows.Scan(
+			&row.ID, &row.WorkspaceID, &row.ContactID, &row.AccountID, &row.Source, &row.Status,
+			&row.OwnerID, &row.Score, &row.Metadata, &row.CreatedAt, &row.UpdatedAt, &row.DeletedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan lead: %w", err)
+		}
+		leads = append(leads, rowToLead(row))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate leads by score: %w", err)
+	}
+	return leads, total, nil
+}
@@ -0,0 +1,226 @@
+package crm_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestComputeLeadScore(t *testing.T) {
+	t.Parallel()
+
+	all := crm.LeadScoreSignals{SizeSegment: 1, Source: 1, Recency: 1, Knowledge: 1}
+	tests := []struct {
+		name    string
+		signals crm.LeadScoreSignals
+		weights crm.LeadScoreWeights
+		want    float64
+	}{
+		{name: "all signals", signals: all, weights: crm.DefaultLeadScoreWeights, want: 100},
+		{name: "no signals", signals: crm.LeadScoreSignals{}, weights: crm.DefaultLeadScoreWeights, want: 0},
+		{name: "default weights", signals: crm.LeadScoreSignals{SizeSegment: 1, Source: 0.5}, weights: crm.DefaultLeadScoreWeights, want: 43},
+		{name: "zero weights use defaults", signals: crm.LeadScoreSignals{SizeSegment: 1}, weights: crm.LeadScoreWeights{}, want: 30},
+		{name: "custom ratios", signals: crm.LeadScoreSignals{SizeSegment: 1, Knowledge: 0.5}, weights: crm.LeadScoreWeights{SizeSegment: 1, Knowledge: 1}, want: 75},
+		{name: "negative weight ignored", signals: crm.LeadScoreSignals{Source: 1, Recency: 1}, weights: crm.LeadScoreWeights{Source: 2, Recency: -5}, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := crm.ComputeLeadScore(tt.signals, tt.weights); got != tt.want {
+				t.Fatalf("ComputeLeadScore = %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLeadRecencyAndSourceSignals(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		age  time.Duration
+		want float64
+	}{
+		{0, 1},
+		{23 * time.Hour, 1},
+		{45 * 24 * time.Hour, 0.5},
+		{120 * 24 * time.Hour, 0},
+	} {
+		if got := crm.LeadRecencySignal(now.Add(-tc.age), now); got != tc.want {
+			t.Fatalf("LeadRecencySignal(age %v) = %v; want %v", tc.age, got, tc.want)
+		}
+	}
+	if got := crm.LeadSourceSignal(" Referral "); got != 1 {
+		t.Fatalf("LeadSourceSignal(referral) = %v; want 1", got)
+	}
+	if got := crm.LeadSourceSignal("podcast"); got != 0.3 {
+		t.Fatalf("LeadSourceSignal(podcast) = %v; want 0.3", got)
+	}
+	if got := crm.LeadSourceSignal(""); got != 0 {
+		t.Fatalf("LeadSourceSignal(\"\") = %v; want 0", got)
+	}
+}
+
+func TestLeadService_Score_StoresScoreAndRecomputesOnUpdate(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	account, err := crm.NewAccountService(db).Create(ctx, crm.CreateAccountInput{
+		WorkspaceID: wsID, Name: "Globex", SizeSegment: "enterprise", OwnerID: ownerID,
+	})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if _, err := db.Exec(`
+		INSERT INTO knowledge_item (id, workspace_id, source_type, title, raw_content, entity_type, entity_id)
+		VALUES ('ki-1', ?, 'note', 'Globex renewal call', 'budget approved', 'account', ?)`,
+		wsID, account.ID,
+	); err != nil {
+		t.Fatalf("insert knowledge item: %v", err)
+	}
+
+	svc := crm.NewLeadService(db)
+	lead, err := svc.Create(ctx, crm.CreateLeadInput{
+		WorkspaceID: wsID, AccountID: account.ID, Source: "referral", OwnerID: ownerID,
+	})
+	if err != nil {
+		t.Fatalf("create lead: %v", err)
+	}
+	// enterprise 30 + referral 25 + new 25 + 1/3 knowledge of 20 = 86.67
+	if lead.Score == nil || *lead.Score != 87 {
+		t.Fatalf("score on create = %v; want 87", lead.Score)
+	}
+
+	score, err := svc.Score(ctx, wsID, lead.ID)
+	if err != nil {
+		t.Fatalf("Score: %v", err)
+	}
+	if score.Score != 87 || score.Signals.SizeSegment != 1 || score.Signals.Source != 1 || score.Signals.Recency != 1 {
+		t.Fatalf("unexpected score breakdown: %+v", score)
+	}
+
+	if _, err := db.Exec(
+		`UPDATE workspace SET settings = '{"lead_scoring":{"weights":{"size_segment":0,"source":1}}}' WHERE id = ?`, wsID,
+	); err != nil {
+		t.Fatalf("set weights: %v", err)
+	}
+	updated, err := svc.Update(ctx, wsID, lead.ID, crm.UpdateLeadInput{
+		AccountID: account.ID, Source: "website", Status: "contacted", OwnerID: ownerID,
+	})
+	if err != nil {
+		t.Fatalf("update lead: %v", err)
+	}
+	if updated.Score == nil || *updated.Score != 50 {
+		t.Fatalf("score after update = %v; want 50 (website source only)", updated.Score)
+	}
+
+	manual := 5.0
+	overridden, err := svc.Update(ctx, wsID, lead.ID, crm.UpdateLeadInput{
+		Status: "contacted", OwnerID: ownerID, Score: &manual,
+	})
+	if err != nil {
+		t.Fatalf("update lead with manual score: %v", err)
+	}
+	if overridden.Score == nil || *overridden.Score != manual {
+		t.Fatalf("manual score = %v; want %v", overridden.Score, manual)
+	}
+}
+
+func TestLeadService_Score_CountsAccountMentionsThroughFTS(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	if _, err := db.Exec(
+		`UPDATE workspace SET settings = '{"lead_scoring":{"weights":{"knowledge":1}}}' WHERE id = ?`, wsID,
+	); err != nil {
+		t.Fatalf("set weights: %v", err)
+	}
+	account, err := crm.NewAccountService(db).Create(ctx, crm.CreateAccountInput{
+		WorkspaceID: wsID, Name: "Globex", OwnerID: ownerID,
+	})
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	for _, item := range []struct{ id, title string }{
+		{"ki-mention", "Call notes: Globex wants a demo"},
+		{"ki-other", "Initech pricing"},
+	} {
+		if _, err = db.Exec(`INSERT INTO knowledge_item (id, workspace_id, source_type, title, raw_content) VALUES (?, ?, 'note', ?, '')`,
+			item.id, wsID, item.title); err != nil {
+			t.Fatalf("insert knowledge item: %v", err)
+		}
+		if _, err = db.Exec(`INSERT INTO knowledge_item_fts (id, workspace_id, title, normalized_content) VALUES (?, ?, ?, '')`,
+			item.id, wsID, item.title); err != nil {
+			t.Fatalf("index knowledge item: %v", err)
+		}
+	}
+
+	lead, err := crm.NewLeadService(db).Create(ctx, crm.CreateLeadInput{
+		WorkspaceID: wsID, AccountID: account.ID, OwnerID: ownerID,
+	})
+	if err != nil {
+		t.Fatalf("create lead: %v", err)
+	}
+	// One mention of the account name out of leadKnowledgeMatchesForFull.
+	if lead.Score == nil || *lead.Score != 33 {
+		t.Fatalf("score = %v; want 33", lead.Score)
+	}
+}
+
+func TestLeadService_ListByScore_OrdersQueue(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewLeadService(db)
+
+	ids := map[float64]string{}
+	for _, score := range []float64{10, 80, 50} {
+		score := score
+		lead, err := svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID, Score: &score})
+		if err != nil {
+			t.Fatalf("create lead: %v", err)
+		}
+		ids[score] = lead.ID
+	}
+	contacted := 95.0
+	if _, err := svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID, Status: "contacted", Score: &contacted}); err != nil {
+		t.Fatalf("create contacted lead: %v", err)
+	}
+	unscored, err := svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID})
+	if err != nil {
+		t.Fatalf("create unscored lead: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE lead SET score = NULL WHERE id = ?`, unscored.ID); err != nil {
+		t.Fatalf("clear score: %v", err)
+	}
+
+	leads, total, err := svc.ListByScore(ctx, wsID, crm.ListLeadsByScoreInput{Limit: 10, Status: "new"})
+	if err != nil {
+		t.Fatalf("ListByScore: %v", err)
+	}
+	want := []string{ids[80], ids[50], ids[10], unscored.ID}
+	if total != len(want) || len(leads) != len(want) {
+		t.Fatalf("total=%d len=%d; want %d", total, len(leads), len(want))
+	}
+	for i, id := range want {
+		if leads[i].ID != id {
+			t.Fatalf("leads[%d] = %s; want %s", i, leads[i].ID, id)
+		}
+	}
+
+	page, total, err := svc.ListByScore(ctx, wsID, crm.ListLeadsByScoreInput{Limit: 1, Offset: 0})
+	if err != nil {
+		t.Fatalf("ListByScore all statuses: %v", err)
+	}
+	if total != 5 || len(page) != 1 || page[0].Score == nil || *page[0].Score != contacted {
+		t.Fatalf("unfiltered first page = %+v (total %d); want the contacted lead scored 95", page, total)
+	}
+}
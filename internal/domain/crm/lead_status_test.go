@@ -0,0 +1,93 @@
+package crm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestLeadService_Update_StatusTransitions(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewLeadService(db)
+	ctx := context.Background()
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+
+	lead, err := svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID})
+	if err != nil {
+		t.Fatalf("create lead: %v", err)
+	}
+
+	_, err = svc.Update(ctx, wsID, lead.ID, crm.UpdateLeadInput{Status: "converted", OwnerID: ownerID})
+	if !errors.Is(err, crm.ErrInvalidLeadTransition) {
+		t.Fatalf("new -> converted error = %v; want ErrInvalidLeadTransition", err)
+	}
+	if got, _ := svc.Get(ctx, wsID, lead.ID); got.Status != "new" {
+		t.Fatalf("status after rejected transition = %q; want new", got.Status)
+	}
+
+	for _, status := range []string{"contacted", "qualified", "converted"} {
+		updated, updateErr := svc.Update(ctx, wsID, lead.ID, crm.UpdateLeadInput{Status: status, OwnerID: ownerID})
+		if updateErr != nil {
+			t.Fatalf("transition to %s: %v", status, updateErr)
+		}
+		if updated.Status != status {
+			t.Fatalf("status = %q; want %q", updated.Status, status)
+		}
+	}
+	if _, err = svc.Update(ctx, wsID, lead.ID, crm.UpdateLeadInput{Status: "lost", OwnerID: ownerID}); !errors.Is(err, crm.ErrInvalidLeadTransition) {
+		t.Fatalf("converted -> lost error = %v; want ErrInvalidLeadTransition", err)
+	}
+}
+
+func TestLeadService_Update_RecordsStatusHistory(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewLeadService(db)
+	// The history records who made the change, not the lead owner.
+	const actorID = "user-changing-status"
+	ctx := context.WithValue(context.Background(), ctxkeys.UserID, actorID)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+
+	lead, err := svc.Create(ctx, crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID, Source: "website"})
+	if err != nil {
+		t.Fatalf("create lead: %v", err)
+	}
+	// An update that keeps the status is not a transition.
+	if _, err = svc.Update(ctx, wsID, lead.ID, crm.UpdateLeadInput{Source: "referral", OwnerID: ownerID}); err != nil {
+		t.Fatalf("update source: %v", err)
+	}
+	if _, err = svc.Update(ctx, wsID, lead.ID, crm.UpdateLeadInput{Status: "contacted", OwnerID: ownerID}); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx,
+		`SELECT from_status, to_status, actor_id FROM lead_status_history WHERE workspace_id = ? AND lead_id = ?`,
+		wsID, lead.ID)
+	if err != nil {
+		t.Fatalf("query lead_status_history: %v", err)
+	}
+	defer rows.Close()
+	var history []string
+	for rows.Next() {
+		var from, to, actor string
+		if err = rows.Scan(&from, &to, &actor); err != nil {
+			t.Fatalf("scan history: %v", err)
+		}
+		if actor != actorID {
+			t.Fatalf("actor_id = %q; want %q", actor, actorID)
+		}
+		history = append(history, from+"->"+to)
+	}
+	if err = rows.Err(); err != nil {
+		t.Fatalf("iterate history: %v", err)
+	}
+	if len(history) != 1 || history[0] != "new->contacted" {
+		t.Fatalf("history = %v; want [new->contacted]", history)
+	}
+}
@@ -8,23 +8,36 @@ import (
 	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
 
 type Note struct {
-	ID          string    `json:"id"`
-	WorkspaceID string    `json:"workspaceId"`
-	EntityType  string    `json:"entityType"`
-	EntityID    string    `json:"entityId"`
-	AuthorID    string    `json:"authorId"`
-	Content     string    `json:"content"`
-	IsInternal  bool      `json:"isInternal"`
-	Metadata    *string   `json:"metadata,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
-	UpdatedAt   time.Time `json:"updatedAt"`
+	ID          string  `json:"id"`
+	WorkspaceID string  `json:"workspaceId"`
+	EntityType  string  `json:"entityType"`
+	EntityID    string  `json:"entityId"`
+	AuthorID    string  `json:"authorId"`
+	Content     string  `json:"content"`
+	IsInternal  bool    `json:"isInternal"`
+	Metadata    *string `json:"metadata,omitempty"`
+	// ParentNoteID is the note this one replies to, on the same entity.
+	ParentNoteID *string   `json:"parentNoteId,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
 }
 
+// NoteThread is a note with its replies, oldest first.
+type NoteThread struct {
+	Note
+	Replies []*NoteThread `json:"replies"`
+}
+
+// ErrInvalidNoteParent is returned by Create when the parent note does not
+// exist on the same entity of the workspace.
+var ErrInvalidNoteParent = errors.New("invalid parent note")
+
 type CreateNoteInput struct {
 	WorkspaceID string
 	EntityType  string
@@ -33,6 +46,8 @@ type CreateNoteInput struct {
 	Content     string
 	IsInternal  bool
 	Metadata    string
+	// ParentNoteID, when set, makes the note a reply in that note's thread.
+	ParentNoteID string
 }
 
 type UpdateNoteInput struct {
@@ -47,7 +62,7 @@ type ListNotesInput struct {
 }
 
 type NoteService struct {
-	db      *sql.DB
+	db      *sqlite.ContextDB
 	querier sqlcgen.Querier
 	audit   auditLogger
 	bus     eventbus.EventBus
@@ -56,27 +71,33 @@ type NoteService struct {
 const noteEntityType = "note"
 
 func NewNoteService(db *sql.DB) *NoteService {
-	return &NoteService{db: db, querier: sqlcgen.New(db), audit: newCRMAuditService(db)}
+	cdb := sqlite.NewContextDB(db)
+	return &NoteService{db: cdb, querier: sqlcgen.New(cdb), audit: newCRMAuditService(db)}
 }
 
 func NewNoteServiceWithBus(db *sql.DB, bus eventbus.EventBus) *NoteService {
-	return &NoteService{db: db, querier: sqlcgen.New(db), audit: newCRMAuditService(db), bus: bus}
+	cdb := sqlite.NewContextDB(db)
+	return &NoteService{db: cdb, querier: sqlcgen.New(cdb), audit: newCRMAuditService(db), bus: bus}
 }
 
 func (s *NoteService) Create(ctx context.Context, input CreateNoteInput) (*Note, error) {
+	if err := s.ensureParentNote(ctx, input); err != nil {
+		return nil, err
+	}
 	id := uuid.NewV7().String()
 	now := nowRFC3339()
 	err := s.querier.CreateNote(ctx, sqlcgen.CreateNoteParams{
-		ID:          id,
-		WorkspaceID: input.WorkspaceID,
-		EntityType:  input.EntityType,
-		EntityID:    input.EntityID,
-		AuthorID:    input.AuthorID,
-		Content:     input.Content,
-		IsInternal:  input.IsInternal,
-		Metadata:    nullString(input.Metadata),
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		ID:           id,
+		WorkspaceID:  input.WorkspaceID,
+		EntityType:   input.EntityType,
+		EntityID:     input.EntityID,
+		AuthorID:     input.AuthorID,
+		Content:      input.Content,
+		IsInternal:   input.IsInternal,
+		Metadata:     nullString(input.Metadata),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		ParentNoteID: nullString(input.ParentNoteID),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("create note: %w", err)
@@ -89,7 +110,7 @@ func (s *NoteService) Create(ctx context.Context, input CreateNoteInput) (*Note,
 	if getErr != nil {
 		return nil, getErr
 	}
-	publishNoteCreated(s.bus, note)
+	publishNoteCreated(ctx, s.bus, note)
 	return note, nil
 }
 
@@ -123,6 +144,20 @@ func (s *NoteService) List(ctx context.Context, workspaceID string, input ListNo
 	)
 }
 
+// ListThread returns the notes of an entity as reply trees: roots and
+// replies are ordered by creation time, oldest first.
+func (s *NoteService) ListThread(ctx context.Context, workspaceID, entityType, entityID string) ([]*NoteThread, error) {
+	rows, err := s.querier.ListNoteThreadByEntity(ctx, sqlcgen.ListNoteThreadByEntityParams{
+		WorkspaceID: workspaceID,
+		EntityType:  entityType,
+		EntityID:    entityID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list note thread: %w", err)
+	}
+	return buildNoteThreads(rows), nil
+}
+
 func (s *NoteService) Update(ctx context.Context, workspaceID, noteID string, input UpdateNoteInput) (*Note, error) {
 	existing, getErr := s.Get(ctx, workspaceID, noteID)
 	if getErr != nil {
@@ -163,19 +198,60 @@ func (s *NoteService) Delete(ctx context.Context, workspaceID, noteID string) er
 	return nil
 }
 
+func (s *NoteService) ensureParentNote(ctx context.Context, input CreateNoteInput) error {
+	if input.ParentNoteID == "" {
+		return nil
+	}
+	err := ensureExists(ctx, s.db,
+		`SELECT 1 FROM note WHERE id = ? AND workspace_id = ? AND entity_type = ? AND entity_id = ? LIMIT 1`,
+		input.ParentNoteID, input.WorkspaceID, input.EntityType, input.EntityID,
+	)
+	if err != nil {
+		return wrapValidationError(ErrInvalidNoteParent, "parent note must belong to the same entity", err)
+	}
+	return nil
+}
+
+// buildNoteThreads nests rows, already sorted by creation time, under their
+// parents. A reply whose parent is not in rows is kept as a root.
+func buildNoteThreads(rows []sqlcgen.Note) []*NoteThread {
+	byID := make(map[string]*NoteThread, len(rows))
+	for _, row := range rows {
+		byID[row.ID] = &NoteThread{Note: *rowToNote(row), Replies: []*NoteThread{}}
+	}
+	roots := make([]*NoteThread, 0, len(rows))
+	for _, row := range rows {
+		node := byID[row.ID]
+		if parent, ok := byID[derefNoteParent(row.ParentNoteID)]; ok && parent != node {
+			parent.Replies = append(parent.Replies, node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+	return roots
+}
+
+func derefNoteParent(id *string) string {
+	if id == nil {
+		return ""
+	}
+	return *id
+}
+
 func rowToNote(row sqlcgen.Note) *Note {
 	createdAt := parseRFC3339Time(row.CreatedAt)
 	updatedAt := parseRFC3339Time(row.UpdatedAt)
 	return &Note{
-		ID:          row.ID,
-		WorkspaceID: row.WorkspaceID,
-		EntityType:  row.EntityType,
-		EntityID:    row.EntityID,
-		AuthorID:    row.AuthorID,
-		Content:     row.Content,
-		IsInternal:  row.IsInternal,
-		Metadata:    row.Metadata,
-		CreatedAt:   createdAt,
-		UpdatedAt:   updatedAt,
+		ID:           row.ID,
+		WorkspaceID:  row.WorkspaceID,
+		EntityType:   row.EntityType,
+		EntityID:     row.EntityID,
+		AuthorID:     row.AuthorID,
+		Content:      row.Content,
+		IsInternal:   row.IsInternal,
+		Metadata:     row.Metadata,
+		ParentNoteID: row.ParentNoteID,
+		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
 	}
 }
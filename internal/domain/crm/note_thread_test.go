@@ -0,0 +1,107 @@
+package crm_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestNoteService_ListThread_NestsRepliesByTime(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, authorID := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewNoteService(db)
+	ctx := context.Background()
+
+	minute := 0
+	create := func(content, parentID string) string {
+		t.Helper()
+		note, err := svc.Create(ctx, crm.CreateNoteInput{
+			WorkspaceID:  wsID,
+			EntityType:   "case",
+			EntityID:     "case-1",
+			AuthorID:     authorID,
+			Content:      content,
+			ParentNoteID: parentID,
+		})
+		if err != nil {
+			t.Fatalf("Create(%s) error = %v", content, err)
+		}
+		// Notes created within the same second would tie on created_at.
+		minute++
+		if _, err = db.Exec(`UPDATE note SET created_at = ? WHERE id = ?`,
+			fmt.Sprintf("2026-01-01T10:%02d:00Z", minute), note.ID); err != nil {
+			t.Fatalf("set created_at: %v", err)
+		}
+		return note.ID
+	}
+
+	question := create("My VPN keeps dropping", "")
+	answer := create("Which client version?", question)
+	followUp := create("4.2.1", answer)
+	other := create("Also, billing is wrong", "")
+	internal := create("Escalating to network team", question)
+
+	threads, err := svc.ListThread(ctx, wsID, "case", "case-1")
+	if err != nil {
+		t.Fatalf("ListThread() error = %v", err)
+	}
+	if len(threads) != 2 || threads[0].ID != question || threads[1].ID != other {
+		t.Fatalf("roots = %v; want [%s %s]", threadIDs(threads), question, other)
+	}
+	replies := threads[0].Replies
+	if len(replies) != 2 || replies[0].ID != answer || replies[1].ID != internal {
+		t.Fatalf("replies = %v; want [%s %s]", threadIDs(replies), answer, internal)
+	}
+	nested := replies[0].Replies
+	if len(nested) != 1 || nested[0].ID != followUp || nested[0].ParentNoteID == nil || *nested[0].ParentNoteID != answer {
+		t.Fatalf("nested replies = %v; want [%s] under %s", threadIDs(nested), followUp, answer)
+	}
+	if len(threads[1].Replies) != 0 {
+		t.Fatalf("other thread replies = %v; want none", threadIDs(threads[1].Replies))
+	}
+
+	empty, err := svc.ListThread(ctx, wsID, "case", "case-2")
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("ListThread(other case) = %v, %v; want empty", threadIDs(empty), err)
+	}
+}
+
+func TestNoteService_Create_RejectsParentFromAnotherEntity(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, authorID := setupWorkspaceAndOwner(t, db)
+	otherWS, otherAuthor := setupWorkspaceAndOwner(t, db)
+	svc := crm.NewNoteService(db)
+	ctx := context.Background()
+
+	parent, err := svc.Create(ctx, crm.CreateNoteInput{
+		WorkspaceID: wsID, EntityType: "case", EntityID: "case-1", AuthorID: authorID, Content: "root",
+	})
+	if err != nil {
+		t.Fatalf("Create(root) error = %v", err)
+	}
+
+	for _, input := range []crm.CreateNoteInput{
+		{WorkspaceID: wsID, EntityType: "case", EntityID: "case-2", AuthorID: authorID, Content: "x", ParentNoteID: parent.ID},
+		{WorkspaceID: otherWS, EntityType: "case", EntityID: "case-1", AuthorID: otherAuthor, Content: "x", ParentNoteID: parent.ID},
+		{WorkspaceID: wsID, EntityType: "case", EntityID: "case-1", AuthorID: authorID, Content: "x", ParentNoteID: "missing"},
+	} {
+		if _, err = svc.Create(ctx, input); !errors.Is(err, crm.ErrInvalidNoteParent) {
+			t.Fatalf("Create(%+v) error = %v; want ErrInvalidNoteParent", input, err)
+		}
+	}
+}
+
+func threadIDs(threads []*crm.NoteThread) []string {
+	ids := make([]string, 0, len(threads))
+	for _, thread := range threads {
+		ids = append(ids, thread.ID)
+	}
+	return ids
+}
@@ -0,0 +1,171 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+)
+
+// ErrInvalidOwnerReassignment is returned by ReassignOwner when either user
+// is not an active member of the workspace or both are the same user.
+var ErrInvalidOwnerReassignment = errors.New("invalid owner reassignment")
+
+// actionOwnerReassigned records a bulk owner change made by ReassignOwner.
+const actionOwnerReassigned = "user.owner_reassigned"
+
+const auditEntityUser = "user"
+
+// OwnerReassignment counts the live records moved by ReassignOwner, per
+// entity type.
+type OwnerReassignment struct {
+	Accounts int `json:"accounts"`
+	Contacts int `json:"contacts"`
+	Leads    int `json:"leads"`
+	Deals    int `json:"deals"`
+	Cases    int `json:"cases"`
+}
+
+// ownerReassignTables lists the owned tables in the order they are moved.
+var ownerReassignTables = []struct {
+	table, entity, updateAction string
+	count                       func(*OwnerReassignment) *int
+}{
+	{"account", timelineEntityAccount, actionAccountUpdated, func(r *OwnerReassignment) *int { return &r.Accounts }},
+	{"contact", timelineEntityContact, actionContactUpdated, func(r *OwnerReassignment) *int { return &r.Contacts }},
+	{"lead", timelineEntityLead, actionLeadUpdated, func(r *OwnerReassignment) *int { return &r.Leads }},
+	{"deal", timelineEntityDeal, actionDealUpdated, func(r *OwnerReassignment) *int { return &r.Deals }},
+	{"case_ticket", timelineEntityCase, actionCaseUpdated, func(r *OwnerReassignment) *int { return &r.Cases }},
+}
+
+// OwnerReassignService moves record ownership between users, e.g. when a rep
+// leaves the workspace.
+type OwnerReassignService struct {
+	db    *sqlite.ContextDB
+	audit auditLogger
+}
+
+// NewOwnerReassignService creates an OwnerReassignService.
+func NewOwnerReassignService(db *sql.DB) *OwnerReassignService {
+	return &OwnerReassignService{db: sqlite.NewContextDB(db), audit: newCRMAuditService(db)}
+}
+
+// ReassignOwner makes toUserID the owner of every live account, contact,
+// lead, deal and case owned by fromUserID, in one transaction. Soft-deleted
+// records keep their owner. Once committed, each moved record gets an
+// <entity>.updated audit event and the whole change a user.owner_reassigned
+// event carrying the counts. Returns ErrInvalidOwnerReassignment unless both
+// users are distinct active members of the workspace.
+func (s *OwnerReassignService) ReassignOwner(ctx context.Context, workspaceID, fromUserID, toUserID string) (*OwnerReassignment, error) {
+	if fromUserID == toUserID {
+		return nil, fmt.Errorf("%w: target user must differ from the source user", ErrInvalidOwnerReassignment)
+	}
+	for _, userID := range []string{fromUserID, toUserID} {
+		if err := ensureExists(ctx, s.db,
+			`SELECT 1 FROM user_account WHERE id = ? AND workspace_id = ? AND status = 'active' LIMIT 1`,
+			userID, workspaceID,
+		); err != nil {
+			return nil, wrapValidationError(ErrInvalidOwnerReassignment, fmt.Sprintf("user %s must be an active user of the workspace", userID), err)
+		}
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin owner reassignment: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := nowRFC3339()
+	var out OwnerReassignment
+	moved := make([][]string, len(ownerReassignTables))
+	for i, t := range ownerReassignTables {
+		ids, listErr := ownedRecordIDs(ctx, tx.Tx, t.table, workspaceID, fromUserID)
+		if listErr != nil {
+			return nil, listErr
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		if _, err = tx.ExecContext(ctx,
+			`UPDATE `+t.table+` SET owner_id = ?, updated_at = ?
+			 WHERE workspace_id = ? AND owner_id = ? AND deleted_at IS NULL`,
+			toUserID, now, workspaceID, fromUserID,
+		); err != nil {
+			return nil, fmt.Errorf("reassign %s owner: %w", t.table, err)
+		}
+		moved[i] = ids
+		*t.count(&out) = len(ids)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit owner reassignment: %w", err)
+	}
+
+	s.logReassignment(ctx, workspaceID, fromUserID, toUserID, out, moved)
+	return &out, nil
+}
+
+func ownedRecordIDs(ctx context.Context, tx *sql.Tx, table, workspaceID, ownerID string) ([]string, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT id FROM `+table+` WHERE workspace_id = ? AND owner_id = ? AND deleted_at IS NULL ORDER BY id`,
+		workspaceID, ownerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list %s owned by user: %w", table, err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan %s id: %w", table, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (s *OwnerReassignService) logReassignment(
+	ctx context.Context,
+	workspaceID, fromUserID, toUserID string,
+	counts OwnerReassignment,
+	moved [][]string,
+) {
+	if s.audit == nil {
+		return
+	}
+	actorID, _ := ctx.Value(ctxkeys.UserID).(string)
+	actorType := domainaudit.ActorTypeSystem
+	if actorID != "" {
+		actorType = domainaudit.ActorTypeUser
+	}
+	oldValue := map[string]string{"owner_id": fromUserID}
+	newValue := map[string]string{"owner_id": toUserID}
+
+	for i, t := range ownerReassignTables {
+		entityType := t.entity
+		for _, id := range moved[i] {
+			_ = s.audit.LogWithDetails(ctx, workspaceID, resolveAuditActorID(actorID), actorType, t.updateAction,
+				&entityType, &id,
+				&domainaudit.EventDetails{
+					OldValue: oldValue,
+					NewValue: newValue,
+					Metadata: map[string]string{"reason": actionOwnerReassigned},
+				},
+				domainaudit.OutcomeSuccess,
+			)
+		}
+	}
+
+	entityType := auditEntityUser
+	_ = s.audit.LogWithDetails(ctx, workspaceID, resolveAuditActorID(actorID), actorType, actionOwnerReassigned,
+		&entityType, &fromUserID,
+		&domainaudit.EventDetails{OldValue: oldValue, NewValue: newValue, Metadata: counts},
+		domainaudit.OutcomeSuccess,
+	)
+}
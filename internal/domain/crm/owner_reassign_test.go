@@ -0,0 +1,116 @@
+package crm_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestOwnerReassignService_ReassignOwner_MovesEveryEntityType(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, fromID := setupWorkspaceAndOwner(t, db)
+	toID := createUser(t, db, wsID)
+	adminID := createUser(t, db, wsID)
+
+	seedDealForReports(t, db, wsID, fromID) // account + deal
+	seedCaseForReports(t, db, wsID, fromID)
+	seedCaseForReports(t, db, wsID, fromID)
+	accountID := createAccount(t, db, wsID, adminID)
+	if _, err := db.Exec(`INSERT INTO contact (id, workspace_id, account_id, first_name, last_name, owner_id, created_at, updated_at)
+		VALUES ('c-'||hex(randomblob(4)), ?, ?, 'Ada', 'Lovelace', ?, datetime('now'), datetime('now'))`, wsID, accountID, fromID); err != nil {
+		t.Fatalf("seed contact: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO lead (id, workspace_id, owner_id, created_at, updated_at)
+		VALUES ('l-'||hex(randomblob(4)), ?, ?, datetime('now'), datetime('now'))`, wsID, fromID); err != nil {
+		t.Fatalf("seed lead: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO lead (id, workspace_id, owner_id, created_at, updated_at, deleted_at)
+		VALUES ('l-deleted', ?, ?, datetime('now'), datetime('now'), datetime('now'))`, wsID, fromID); err != nil {
+		t.Fatalf("seed deleted lead: %v", err)
+	}
+
+	svc := crm.NewOwnerReassignService(db)
+	ctx := context.WithValue(context.Background(), ctxkeys.UserID, adminID)
+	got, err := svc.ReassignOwner(ctx, wsID, fromID, toID)
+	if err != nil {
+		t.Fatalf("ReassignOwner() error = %v", err)
+	}
+	want := crm.OwnerReassignment{Accounts: 1, Contacts: 1, Leads: 1, Deals: 1, Cases: 2}
+	if *got != want {
+		t.Fatalf("ReassignOwner() = %+v; want %+v", *got, want)
+	}
+
+	for _, table := range []string{"account", "contact", "deal", "case_ticket"} {
+		var left int
+		if err = db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE workspace_id = ? AND owner_id = ?`, wsID, fromID).Scan(&left); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if left != 0 {
+			t.Fatalf("%s rows still owned by source user = %d", table, left)
+		}
+	}
+	var deletedOwner string
+	if err = db.QueryRow(`SELECT owner_id FROM lead WHERE id = 'l-deleted'`).Scan(&deletedOwner); err != nil {
+		t.Fatalf("load deleted lead: %v", err)
+	}
+	if deletedOwner != fromID {
+		t.Fatalf("soft-deleted lead owner = %q; want it untouched", deletedOwner)
+	}
+
+	assertAuditCount(t, db, wsID, "account.updated", 1)
+	assertAuditCount(t, db, wsID, "contact.updated", 1)
+	assertAuditCount(t, db, wsID, "lead.updated", 1)
+	assertAuditCount(t, db, wsID, "deal.updated", 1)
+	assertAuditCount(t, db, wsID, "case.updated", 2)
+	assertAuditCount(t, db, wsID, "user.owner_reassigned", 1)
+
+	var actorID, entityID, details string
+	if err = db.QueryRow(`SELECT actor_id, entity_id, details FROM audit_event WHERE workspace_id = ? AND action = 'user.owner_reassigned'`, wsID).
+		Scan(&actorID, &entityID, &details); err != nil {
+		t.Fatalf("load bulk audit event: %v", err)
+	}
+	var decoded struct {
+		NewValue map[string]string     `json:"new_value"`
+		Metadata crm.OwnerReassignment `json:"metadata"`
+	}
+	if err = json.Unmarshal([]byte(details), &decoded); err != nil {
+		t.Fatalf("decode details: %v", err)
+	}
+	if actorID != adminID || entityID != fromID || decoded.NewValue["owner_id"] != toID || decoded.Metadata != want {
+		t.Fatalf("bulk audit event = actor %s entity %s details %s", actorID, entityID, details)
+	}
+}
+
+func TestOwnerReassignService_ReassignOwner_RejectsInvalidUsers(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	wsID, fromID := setupWorkspaceAndOwner(t, db)
+	_, foreignID := setupWorkspaceAndOwner(t, db)
+	suspendedID := createUser(t, db, wsID)
+	if _, err := db.Exec(`UPDATE user_account SET status = 'suspended' WHERE id = ?`, suspendedID); err != nil {
+		t.Fatalf("suspend user: %v", err)
+	}
+	seedCaseForReports(t, db, wsID, fromID)
+	svc := crm.NewOwnerReassignService(db)
+
+	cases := []struct{ from, to string }{
+		{fromID, fromID},
+		{fromID, suspendedID},
+		{fromID, foreignID},
+		{fromID, "missing"},
+		{suspendedID, fromID},
+	}
+	for _, tc := range cases {
+		if _, err := svc.ReassignOwner(context.Background(), wsID, tc.from, tc.to); !errors.Is(err, crm.ErrInvalidOwnerReassignment) {
+			t.Fatalf("ReassignOwner(%s, %s) error = %v; want ErrInvalidOwnerReassignment", tc.from, tc.to, err)
+		}
+	}
+	assertAuditCount(t, db, wsID, "user.owner_reassigned", 0)
+}
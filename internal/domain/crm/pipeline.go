@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
@@ -68,13 +70,33 @@ type UpdatePipelineStageInput struct {
 	RequiredFields string
 }
 
+var (
+	// ErrStageInUse is returned when deleting a stage that deals or cases
+	// still sit in and no reassignment stage was given.
+	ErrStageInUse = errors.New("pipeline stage is in use")
+	// ErrInvalidStageReassignment is returned when the reassignment stage is
+	// missing, is the stage being deleted, or belongs to another pipeline.
+	ErrInvalidStageReassignment = errors.New("invalid stage reassignment")
+	// ErrInvalidStageProbability is returned when a stage's win probability
+	// is outside 0-1.
+	ErrInvalidStageProbability = errors.New("stage probability must be between 0 and 1")
+)
+
+func validateStageProbability(p *float64) error {
+	if p != nil && (*p < 0 || *p > 1) {
+		return ErrInvalidStageProbability
+	}
+	return nil
+}
+
 type PipelineService struct {
-	db      *sql.DB
+	db      *sqlite.ContextDB
 	querier sqlcgen.Querier
 }
 
 func NewPipelineService(db *sql.DB) *PipelineService {
-	return &PipelineService{db: db, querier: sqlcgen.New(db)}
+	cdb := sqlite.NewContextDB(db)
+	return &PipelineService{db: cdb, querier: sqlcgen.New(cdb)}
 }
 
 func (s *PipelineService) Create(ctx context.Context, input CreatePipelineInput) (*Pipeline, error) {
@@ -148,6 +170,9 @@ func (s *PipelineService) Delete(ctx context.Context, workspaceID, pipelineID st
 }
 
 func (s *PipelineService) CreateStage(ctx context.Context, input CreatePipelineStageInput) (*PipelineStage, error) {
+	if err := validateStageProbability(input.Probability); err != nil {
+		return nil, err
+	}
 	id := uuid.NewV7().String()
 	now := time.Now().UTC().Format(time.RFC3339)
 	err := s.querier.CreatePipelineStage(ctx, sqlcgen.CreatePipelineStageParams{
@@ -187,6 +212,9 @@ func (s *PipelineService) ListStages(ctx context.Context, pipelineID string) ([]
 }
 
 func (s *PipelineService) UpdateStage(ctx context.Context, stageID string, input UpdatePipelineStageInput) (*PipelineStage, error) {
+	if err := validateStageProbability(input.Probability); err != nil {
+		return nil, err
+	}
 	err := s.querier.UpdatePipelineStage(ctx, sqlcgen.UpdatePipelineStageParams{
 		Name:           input.Name,
 		Position:       input.Position,
@@ -202,10 +230,114 @@ func (s *PipelineService) UpdateStage(ctx context.Context, stageID string, input
 	return s.GetStage(ctx, stageID)
 }
 
-func (s *PipelineService) DeleteStage(ctx context.Context, stageID string) error {
-	if err := s.querier.DeletePipelineStage(ctx, stageID); err != nil {
+// DeleteStage removes a stage. Deals and cases in the stage are moved to
+// reassignTo first, in the same transaction, each moved deal getting a
+// deal_stage_history row; with reassignTo empty, a stage
+// still in use is kept and ErrStageInUse returned.
+func (s *PipelineService) DeleteStage(ctx context.Context, stageID, reassignTo string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin delete pipeline stage: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if reassignTo == "" {
+		err = ensureStageUnused(ctx, tx.Tx, stageID)
+	} else {
+		err = reassignStageRecords(ctx, tx.Tx, stageID, reassignTo)
+	}
+	if err != nil {
+		return err
+	}
+	if err = sqlcgen.New(s.db).WithTx(tx.Tx).DeletePipelineStage(ctx, stageID); err != nil {
 		return fmt.Errorf("delete pipeline stage: %w", err)
 	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete pipeline stage: %w", err)
+	}
+	return nil
+}
+
+// ensureStageUnused counts soft-deleted deals too: deal.stage_id is a
+// RESTRICT foreign key, so they block the delete just the same.
+func ensureStageUnused(ctx context.Context, tx *sql.Tx, stageID string) error {
+	var inUse int64
+	err := tx.QueryRowContext(ctx, `
+		SELECT (SELECT COUNT(*) FROM deal WHERE stage_id = ?)
+		     + (SELECT COUNT(*) FROM case_ticket WHERE stage_id = ? AND deleted_at IS NULL)
+	`, stageID, stageID).Scan(&inUse)
+	if err != nil {
+		return fmt.Errorf("count pipeline stage records: %w", err)
+	}
+	if inUse > 0 {
+		return ErrStageInUse
+	}
+	return nil
+}
+
+func reassignStageRecords(ctx context.Context, tx *sql.Tx, stageID, reassignTo string) error {
+	if reassignTo == stageID {
+		return ErrInvalidStageReassignment
+	}
+	var sameStagePipeline bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pipeline_stage target
+			JOIN pipeline_stage source ON source.pipeline_id = target.pipeline_id
+			WHERE target.id = ? AND source.id = ?
+		)
+	`, reassignTo, stageID).Scan(&sameStagePipeline)
+	if err != nil {
+		return fmt.Errorf("check reassignment stage: %w", err)
+	}
+	if !sameStagePipeline {
+		return ErrInvalidStageReassignment
+	}
+
+	now := nowRFC3339()
+	if err = recordReassignedDealStages(ctx, tx, stageID, reassignTo, now); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE deal SET stage_id = ?, updated_at = ? WHERE stage_id = ?`, reassignTo, now, stageID); err != nil {
+		return fmt.Errorf("reassign deals: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, `UPDATE case_ticket SET stage_id = ?, updated_at = ? WHERE stage_id = ?`, reassignTo, now, stageID); err != nil {
+		return fmt.Errorf("reassign cases: %w", err)
+	}
+	return nil
+}
+
+// recordReassignedDealStages writes a deal_stage_history row for every deal
+// about to move from stageID to reassignTo, attributed to the request user.
+func recordReassignedDealStages(ctx context.Context, tx *sql.Tx, stageID, reassignTo, now string) error {
+	rows, err := tx.QueryContext(ctx, `SELECT id, workspace_id FROM deal WHERE stage_id = ?`, stageID)
+	if err != nil {
+		return fmt.Errorf("list reassigned deals: %w", err)
+	}
+	type movedDeal struct{ id, workspaceID string }
+	var moved []movedDeal
+	for rows.Next() {
+		var deal movedDeal
+		if err = rows.Scan(&deal.id, &deal.workspaceID); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("scan reassigned deal: %w", err)
+		}
+		moved = append(moved, deal)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return fmt.Errorf("list reassigned deals: %w", err)
+	}
+	if err = rows.Close(); err != nil {
+		return fmt.Errorf("list reassigned deals: %w", err)
+	}
+
+	actorID, _ := ctx.Value(ctxkeys.UserID).(string)
+	for _, deal := range moved {
+		if err = insertDealStageHistory(ctx, tx, deal.workspaceID, deal.id, stageID, reassignTo, actorID, now); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -4,6 +4,7 @@ package crm_test
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"testing"
 
 	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
@@ -120,7 +121,7 @@ func TestPipelineService_StageCRUD(t *testing.T) {
 		t.Fatalf("unexpected updated stage: %+v", updated)
 	}
 
-	if err := svc.DeleteStage(context.Background(), stage.ID); err != nil {
+	if err := svc.DeleteStage(context.Background(), stage.ID, ""); err != nil {
 		t.Fatalf("DeleteStage() error = %v", err)
 	}
 
@@ -129,3 +130,101 @@ func TestPipelineService_StageCRUD(t *testing.T) {
 		t.Fatalf("expected sql.ErrNoRows after stage delete, got %v", err)
 	}
 }
+
+func TestPipelineService_StageProbabilityValidated(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewPipelineService(db)
+	wsID, _ := setupWorkspaceAndOwner(t, db)
+
+	p, err := svc.Create(context.Background(), crm.CreatePipelineInput{WorkspaceID: wsID, Name: "Sales", EntityType: "deal"})
+	if err != nil {
+		t.Fatalf("seed pipeline Create() error = %v", err)
+	}
+
+	tooHigh := 1.5
+	if _, err := svc.CreateStage(context.Background(), crm.CreatePipelineStageInput{PipelineID: p.ID, Name: "Won", Position: 1, Probability: &tooHigh}); !errors.Is(err, crm.ErrInvalidStageProbability) {
+		t.Fatalf("CreateStage(1.5) error = %v; want ErrInvalidStageProbability", err)
+	}
+
+	half := 0.5
+	stage, err := svc.CreateStage(context.Background(), crm.CreatePipelineStageInput{PipelineID: p.ID, Name: "Proposal", Position: 1, Probability: &half})
+	if err != nil {
+		t.Fatalf("CreateStage(0.5) error = %v", err)
+	}
+	if stage.Probability == nil || *stage.Probability != 0.5 {
+		t.Fatalf("stage probability = %v; want 0.5", stage.Probability)
+	}
+
+	negative := -0.1
+	if _, err := svc.UpdateStage(context.Background(), stage.ID, crm.UpdatePipelineStageInput{Name: "Proposal", Position: 1, Probability: &negative}); !errors.Is(err, crm.ErrInvalidStageProbability) {
+		t.Fatalf("UpdateStage(-0.1) error = %v; want ErrInvalidStageProbability", err)
+	}
+}
+
+func TestPipelineService_DeleteStage_InUseRequiresReassignment(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	db := mustOpenDBWithMigrations(t)
+	svc := crm.NewPipelineService(db)
+	wsID, ownerID := setupWorkspaceAndOwner(t, db)
+
+	p, err := svc.Create(ctx, crm.CreatePipelineInput{WorkspaceID: wsID, Name: "Sales", EntityType: "deal"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	discovery, err := svc.CreateStage(ctx, crm.CreatePipelineStageInput{PipelineID: p.ID, Name: "Discovery", Position: 1})
+	if err != nil {
+		t.Fatalf("CreateStage(discovery) error = %v", err)
+	}
+	proposal, err := svc.CreateStage(ctx, crm.CreatePipelineStageInput{PipelineID: p.ID, Name: "Proposal", Position: 2})
+	if err != nil {
+		t.Fatalf("CreateStage(proposal) error = %v", err)
+	}
+	deal, err := crm.NewDealService(db).Create(ctx, crm.CreateDealInput{
+		WorkspaceID: wsID,
+		AccountID:   createAccount(t, db, wsID, ownerID),
+		PipelineID:  p.ID,
+		StageID:     discovery.ID,
+		OwnerID:     ownerID,
+		Title:       "Deal",
+	})
+	if err != nil {
+		t.Fatalf("create deal: %v", err)
+	}
+
+	if err = svc.DeleteStage(ctx, discovery.ID, ""); !errors.Is(err, crm.ErrStageInUse) {
+		t.Fatalf("DeleteStage() error = %v; want ErrStageInUse", err)
+	}
+	if err = svc.DeleteStage(ctx, discovery.ID, discovery.ID); !errors.Is(err, crm.ErrInvalidStageReassignment) {
+		t.Fatalf("DeleteStage(self) error = %v; want ErrInvalidStageReassignment", err)
+	}
+	if _, err = svc.GetStage(ctx, discovery.ID); err != nil {
+		t.Fatalf("stage removed by a blocked delete: %v", err)
+	}
+
+	if err = svc.DeleteStage(ctx, discovery.ID, proposal.ID); err != nil {
+		t.Fatalf("DeleteStage(reassign) error = %v", err)
+	}
+	if _, err = svc.GetStage(ctx, discovery.ID); err != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows after stage delete, got %v", err)
+	}
+	moved, err := crm.NewDealService(db).Get(ctx, wsID, deal.ID)
+	if err != nil {
+		t.Fatalf("get deal: %v", err)
+	}
+	if moved.StageID != proposal.ID {
+		t.Fatalf("deal stage = %q; want %q", moved.StageID, proposal.ID)
+	}
+	var fromStage, toStage string
+	if err = db.QueryRow(`
+		SELECT from_stage_id, to_stage_id FROM deal_stage_history
+		WHERE deal_id = ? AND from_stage_id IS NOT NULL`, deal.ID).Scan(&fromStage, &toStage); err != nil {
+		t.Fatalf("reassignment stage history: %v", err)
+	}
+	if fromStage != discovery.ID || toStage != proposal.ID {
+		t.Fatalf("stage history = %s -> %s; want %s -> %s", fromStage, toStage, discovery.ID, proposal.ID)
+	}
+}
@@ -1,19 +1,22 @@
 package crm
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
 	"github.com/matiasleandrokruk/fenix/internal/domain/relationship"
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 )
 
-func publishActivityCreated(bus eventbus.EventBus, activity *Activity) {
+func publishActivityCreated(ctx context.Context, bus eventbus.EventBus, activity *Activity) {
 	if bus == nil || activity == nil {
 		return
 	}
-	bus.Publish(relationship.TopicActivityCreated, map[string]any{
+	publishAfterCommit(ctx, bus, relationship.TopicActivityCreated, map[string]any{
 		"workspace_id":       activity.WorkspaceID,
 		"entity_type":        activity.EntityType,
 		"entity_id":          activity.EntityID,
@@ -25,11 +28,11 @@ func publishActivityCreated(bus eventbus.EventBus, activity *Activity) {
 	})
 }
 
-func publishNoteCreated(bus eventbus.EventBus, note *Note) {
+func publishNoteCreated(ctx context.Context, bus eventbus.EventBus, note *Note) {
 	if bus == nil || note == nil {
 		return
 	}
-	bus.Publish(relationship.TopicNoteCreated, map[string]any{
+	publishAfterCommit(ctx, bus, relationship.TopicNoteCreated, map[string]any{
 		"workspace_id":       note.WorkspaceID,
 		"entity_type":        note.EntityType,
 		"entity_id":          note.EntityID,
@@ -40,11 +43,11 @@ func publishNoteCreated(bus eventbus.EventBus, note *Note) {
 	})
 }
 
-func publishDealUpdated(bus eventbus.EventBus, deal *Deal) {
+func publishDealUpdated(ctx context.Context, bus eventbus.EventBus, deal *Deal) {
 	if bus == nil || deal == nil {
 		return
 	}
-	bus.Publish(relationship.TopicDealUpdated, map[string]any{
+	publishAfterCommit(ctx, bus, relationship.TopicDealUpdated, withAgentRunID(ctx, map[string]any{
 		"workspace_id":       deal.WorkspaceID,
 		"entity_type":        "deal",
 		"entity_id":          deal.ID,
@@ -52,14 +55,14 @@ func publishDealUpdated(bus eventbus.EventBus, deal *Deal) {
 		"source_entity_type": "deal",
 		"source_entity_id":   deal.ID,
 		"occurred_at":        deal.UpdatedAt.UTC().Format(time.RFC3339),
-	})
+	}))
 }
 
-func publishCaseUpdated(bus eventbus.EventBus, ticket *CaseTicket) {
+func publishCaseUpdated(ctx context.Context, bus eventbus.EventBus, ticket *CaseTicket) {
 	if bus == nil || ticket == nil {
 		return
 	}
-	bus.Publish(relationship.TopicCaseUpdated, map[string]any{
+	publishAfterCommit(ctx, bus, relationship.TopicCaseUpdated, withAgentRunID(ctx, map[string]any{
 		"workspace_id":       ticket.WorkspaceID,
 		"entity_type":        "case",
 		"entity_id":          ticket.ID,
@@ -67,7 +70,29 @@ func publishCaseUpdated(bus eventbus.EventBus, ticket *CaseTicket) {
 		"source_entity_type": "case",
 		"source_entity_id":   ticket.ID,
 		"occurred_at":        ticket.UpdatedAt.UTC().Format(time.RFC3339),
-	})
+	}))
+}
+
+// publishAfterCommit publishes once the request transaction of ctx commits,
+// so subscribers never see a change that is rolled back; without one it
+// publishes right away.
+func publishAfterCommit(ctx context.Context, bus eventbus.EventBus, topic string, payload any) {
+	sqlite.AfterCommit(ctx, func() { bus.Publish(topic, payload) })
+}
+
+// agentRunID returns the agent run a change is made from, if any, so the
+// events it publishes can name their cause and not re-trigger that agent.
+func agentRunID(ctx context.Context) string {
+	runID, _ := ctx.Value(ctxkeys.RunID).(string)
+	return runID
+}
+
+// withAgentRunID adds "agent_run_id" to payload when ctx carries a run.
+func withAgentRunID(ctx context.Context, payload map[string]any) map[string]any {
+	if runID := agentRunID(ctx); runID != "" {
+		payload["agent_run_id"] = runID
+	}
+	return payload
 }
 
 func joinNonEmpty(values ...string) string {
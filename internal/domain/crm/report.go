@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 )
 
@@ -18,7 +19,7 @@ type ReportService struct {
 }
 
 func NewReportService(db *sql.DB) *ReportService {
-	return &ReportService{querier: sqlcgen.New(db)}
+	return &ReportService{querier: sqlcgen.New(sqlite.NewContextDB(db))}
 }
 
 type SalesFunnelReport struct {
@@ -2,6 +2,8 @@ package crm
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -40,6 +42,55 @@ func mapRows[T any, R any](rows []R, mapper func(R) *T) []*T {
 	return out
 }
 
+// ErrRecordNotDeleted is returned by Restore methods when the record exists but
+// has not been soft-deleted.
+var ErrRecordNotDeleted = errors.New("record is not deleted")
+
+// restoreSoftDeleted clears deleted_at through restore. When nothing was
+// restored it tells a missing record (sql.ErrNoRows) from a live one
+// (ErrRecordNotDeleted).
+func restoreSoftDeleted(
+	ctx context.Context,
+	db sqlcgen.DBTX,
+	table, workspaceID, id string,
+	restore func(now string) (int64, error),
+) error {
+	n, err := restore(nowRFC3339())
+	if err != nil {
+		return fmt.Errorf("restore %s: %w", table, err)
+	}
+	if n > 0 {
+		return nil
+	}
+	err = ensureExists(ctx, db, `SELECT 1 FROM `+table+` WHERE id = ? AND workspace_id = ? LIMIT 1`, id, workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sql.ErrNoRows
+	}
+	if err != nil {
+		return err
+	}
+	return ErrRecordNotDeleted
+}
+
+// softDeletedAt returns when the record in table was soft-deleted, nil if it
+// is live, or sql.ErrNoRows if it does not exist in the workspace.
+func softDeletedAt(ctx context.Context, db sqlcgen.DBTX, table, workspaceID, id string) (*time.Time, error) {
+	var deletedAt sql.NullString
+	err := db.QueryRowContext(ctx,
+		`SELECT deleted_at FROM `+table+` WHERE id = ? AND workspace_id = ? LIMIT 1`, id, workspaceID,
+	).Scan(&deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, sql.ErrNoRows
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get %s deleted_at: %w", table, err)
+	}
+	if !deletedAt.Valid {
+		return nil, nil
+	}
+	return parseOptionalRFC3339(&deletedAt.String), nil
+}
+
 // softDeleteWithSideEffects executes the soft-delete DB call then records the
 // timeline event and audit log. It is the shared skeleton for all CRM Delete methods
 // that follow the pattern: soft-delete → timeline → audit.
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 )
@@ -41,12 +42,13 @@ type ListTimelineInput struct {
 }
 
 type TimelineService struct {
-	db      *sql.DB
+	db      *sqlite.ContextDB
 	querier sqlcgen.Querier
 }
 
 func NewTimelineService(db *sql.DB) *TimelineService {
-	return &TimelineService{db: db, querier: sqlcgen.New(db)}
+	cdb := sqlite.NewContextDB(db)
+	return &TimelineService{db: cdb, querier: sqlcgen.New(cdb)}
 }
 
 func (s *TimelineService) Create(ctx context.Context, input CreateTimelineEventInput) (*TimelineEvent, error) {
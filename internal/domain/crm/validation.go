@@ -5,11 +5,22 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 )
 
 var (
 	ErrInvalidDealInput = errors.New("invalid deal input")
 	ErrInvalidCaseInput = errors.New("invalid case input")
+
+	ErrInvalidActivityInput = errors.New("invalid activity input")
+	// ErrInvalidActivityTransition is returned when an activity leaves a
+	// terminal status (completed, cancelled).
+	ErrInvalidActivityTransition = errors.New("invalid activity status transition")
+	// ErrInvalidLeadTransition is returned when a lead status change skips
+	// or reverses a step of new -> contacted -> qualified -> converted, or
+	// leaves converted or lost.
+	ErrInvalidLeadTransition = errors.New("invalid lead status transition")
 )
 
 var (
@@ -32,23 +43,56 @@ var (
 		"closed":      {},
 		"escalated":   {},
 	}
+	validActivityTypes = map[string]struct{}{
+		"task":  {},
+		"event": {},
+		"call":  {},
+		"email": {},
+	}
+	validActivityEntityTypes = map[string]struct{}{
+		"account": {},
+		"contact": {},
+		"deal":    {},
+		"case":    {},
+	}
+	// leadStatusTransitions lists the statuses reachable from each status.
+	// A lead can be disqualified (lost) from any open status; converted and
+	// lost are terminal.
+	leadStatusTransitions = map[string]map[string]struct{}{
+		leadStatusNew:       {leadStatusNew: {}, leadStatusContacted: {}, leadStatusLost: {}},
+		leadStatusContacted: {leadStatusContacted: {}, leadStatusQualified: {}, leadStatusLost: {}},
+		leadStatusQualified: {leadStatusQualified: {}, leadStatusConverted: {}, leadStatusLost: {}},
+		leadStatusConverted: {leadStatusConverted: {}},
+		leadStatusLost:      {leadStatusLost: {}},
+	}
+	// activityStatusTransitions lists the statuses reachable from each
+	// status; completed and cancelled are terminal.
+	activityStatusTransitions = map[string]map[string]struct{}{
+		activityStatusPending: {
+			activityStatusPending:   {},
+			activityStatusCompleted: {},
+			activityStatusCancelled: {},
+		},
+		activityStatusCompleted: {activityStatusCompleted: {}},
+		activityStatusCancelled: {activityStatusCancelled: {}},
+	}
 )
 
-func validateDealInput(ctx context.Context, db *sql.DB, workspaceID string, input CreateDealInput) error {
+func validateDealInput(ctx context.Context, db sqlcgen.DBTX, workspaceID string, input CreateDealInput) error {
 	if err := validateDealRelations(ctx, db, workspaceID, input); err != nil {
 		return err
 	}
 	return validateDealValues(input)
 }
 
-func validateCaseInput(ctx context.Context, db *sql.DB, workspaceID string, input CreateCaseInput) error {
+func validateCaseInput(ctx context.Context, db sqlcgen.DBTX, workspaceID string, input CreateCaseInput) error {
 	if err := validateCaseRelations(ctx, db, workspaceID, input); err != nil {
 		return err
 	}
 	return validateCaseValues(input)
 }
 
-func validateDealRelations(ctx context.Context, db *sql.DB, workspaceID string, input CreateDealInput) error {
+func validateDealRelations(ctx context.Context, db sqlcgen.DBTX, workspaceID string, input CreateDealInput) error {
 	if err := ensureUserExists(ctx, db, workspaceID, input.OwnerID); err != nil {
 		return invalidDealInput("owner_id is invalid", err)
 	}
@@ -79,7 +123,7 @@ func validateDealValues(input CreateDealInput) error {
 	return nil
 }
 
-func validateCaseRelations(ctx context.Context, db *sql.DB, workspaceID string, input CreateCaseInput) error {
+func validateCaseRelations(ctx context.Context, db sqlcgen.DBTX, workspaceID string, input CreateCaseInput) error {
 	if err := ensureUserExists(ctx, db, workspaceID, input.OwnerID); err != nil {
 		return invalidCaseInput("owner_id is invalid", err)
 	}
@@ -95,7 +139,7 @@ func validateCaseRelations(ctx context.Context, db *sql.DB, workspaceID string,
 	return validateOptionalCaseStage(ctx, db, input.StageID, input.PipelineID)
 }
 
-func validateOptionalCaseAccount(ctx context.Context, db *sql.DB, workspaceID, accountID string) error {
+func validateOptionalCaseAccount(ctx context.Context, db sqlcgen.DBTX, workspaceID, accountID string) error {
 	if accountID == "" {
 		return nil
 	}
@@ -105,7 +149,7 @@ func validateOptionalCaseAccount(ctx context.Context, db *sql.DB, workspaceID, a
 	return nil
 }
 
-func validateOptionalCaseContact(ctx context.Context, db *sql.DB, workspaceID, contactID string) error {
+func validateOptionalCaseContact(ctx context.Context, db sqlcgen.DBTX, workspaceID, contactID string) error {
 	if contactID == "" {
 		return nil
 	}
@@ -115,7 +159,7 @@ func validateOptionalCaseContact(ctx context.Context, db *sql.DB, workspaceID, c
 	return nil
 }
 
-func validateOptionalCasePipeline(ctx context.Context, db *sql.DB, workspaceID, pipelineID string) error {
+func validateOptionalCasePipeline(ctx context.Context, db sqlcgen.DBTX, workspaceID, pipelineID string) error {
 	if pipelineID == "" {
 		return nil
 	}
@@ -125,7 +169,7 @@ func validateOptionalCasePipeline(ctx context.Context, db *sql.DB, workspaceID,
 	return nil
 }
 
-func validateOptionalCaseStage(ctx context.Context, db *sql.DB, stageID, pipelineID string) error {
+func validateOptionalCaseStage(ctx context.Context, db sqlcgen.DBTX, stageID, pipelineID string) error {
 	if stageID == "" {
 		return nil
 	}
@@ -148,23 +192,50 @@ func validateCaseValues(input CreateCaseInput) error {
 	return nil
 }
 
-func ensureUserExists(ctx context.Context, db *sql.DB, workspaceID, userID string) error {
+func validateActivityValues(activityType, entityType, status string) error {
+	if !isValidEnum(activityType, validActivityTypes) {
+		return invalidActivityInput("activity_type is invalid", nil)
+	}
+	if !isValidEnum(entityType, validActivityEntityTypes) {
+		return invalidActivityInput("entity_type is invalid", nil)
+	}
+	if _, ok := activityStatusTransitions[status]; !ok {
+		return invalidActivityInput("status is invalid", nil)
+	}
+	return nil
+}
+
+func validateActivityTransition(from, to string) error {
+	if !isValidEnum(to, activityStatusTransitions[from]) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidActivityTransition, from, to)
+	}
+	return nil
+}
+
+func validateLeadTransition(from, to string) error {
+	if !isValidEnum(to, leadStatusTransitions[from]) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidLeadTransition, from, to)
+	}
+	return nil
+}
+
+func ensureUserExists(ctx context.Context, db sqlcgen.DBTX, workspaceID, userID string) error {
 	return ensureExists(ctx, db, `SELECT 1 FROM user_account WHERE id = ? AND workspace_id = ? LIMIT 1`, userID, workspaceID)
 }
 
-func ensureAccountExists(ctx context.Context, db *sql.DB, workspaceID, accountID string) error {
+func ensureAccountExists(ctx context.Context, db sqlcgen.DBTX, workspaceID, accountID string) error {
 	return ensureExists(ctx, db, `SELECT 1 FROM account WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL LIMIT 1`, accountID, workspaceID)
 }
 
-func ensureContactExists(ctx context.Context, db *sql.DB, workspaceID, contactID string) error {
+func ensureContactExists(ctx context.Context, db sqlcgen.DBTX, workspaceID, contactID string) error {
 	return ensureExists(ctx, db, `SELECT 1 FROM contact WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL LIMIT 1`, contactID, workspaceID)
 }
 
-func ensurePipelineExists(ctx context.Context, db *sql.DB, workspaceID, pipelineID string) error {
+func ensurePipelineExists(ctx context.Context, db sqlcgen.DBTX, workspaceID, pipelineID string) error {
 	return ensureExists(ctx, db, `SELECT 1 FROM pipeline WHERE id = ? AND workspace_id = ? LIMIT 1`, pipelineID, workspaceID)
 }
 
-func ensureStageBelongsToPipeline(ctx context.Context, db *sql.DB, stageID, pipelineID string) error {
+func ensureStageBelongsToPipeline(ctx context.Context, db sqlcgen.DBTX, stageID, pipelineID string) error {
 	var stagePipelineID string
 	err := db.QueryRowContext(ctx, `SELECT pipeline_id FROM pipeline_stage WHERE id = ? LIMIT 1`, stageID).Scan(&stagePipelineID)
 	if err != nil {
@@ -176,7 +247,7 @@ func ensureStageBelongsToPipeline(ctx context.Context, db *sql.DB, stageID, pipe
 	return nil
 }
 
-func ensureExists(ctx context.Context, db *sql.DB, query string, args ...any) error {
+func ensureExists(ctx context.Context, db sqlcgen.DBTX, query string, args ...any) error {
 	var exists int
 	if err := db.QueryRowContext(ctx, query, args...).Scan(&exists); err != nil {
 		return fmt.Errorf("check related record exists: %w", err)
@@ -192,6 +263,10 @@ func invalidCaseInput(reason string, err error) error {
 	return wrapValidationError(ErrInvalidCaseInput, reason, err)
 }
 
+func invalidActivityInput(reason string, err error) error {
+	return wrapValidationError(ErrInvalidActivityInput, reason, err)
+}
+
 func wrapValidationError(base error, reason string, err error) error {
 	if err == nil || errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("%w: %s", base, reason)
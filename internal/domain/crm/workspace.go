@@ -0,0 +1,246 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+type Workspace struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Settings  *string   `json:"settings,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CreateWorkspaceInput describes a new workspace. Slug is derived from Name
+// and the workspace ID when empty. SeedDefaults provisions the default sales
+// and support pipelines and the support agent definition with it.
+type CreateWorkspaceInput struct {
+	Name         string
+	Slug         string
+	Settings     string
+	SeedDefaults bool
+}
+
+type UpdateWorkspaceInput struct {
+	Name     string
+	Slug     string
+	Settings string
+}
+
+var (
+	// ErrWorkspaceSlugTaken is returned when another workspace, deleted or
+	// not, already uses the slug.
+	ErrWorkspaceSlugTaken = errors.New("workspace slug already taken")
+	// ErrInvalidWorkspaceSlug is returned for slugs that are not lowercase
+	// letters, digits and hyphens.
+	ErrInvalidWorkspaceSlug = errors.New("workspace slug must be lowercase letters, digits and hyphens")
+)
+
+var workspaceSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+const workspaceColumns = `id, name, slug, settings, created_at, updated_at`
+
+type WorkspaceService struct {
+	db *sqlite.ContextDB
+}
+
+func NewWorkspaceService(db *sql.DB) *WorkspaceService {
+	return &WorkspaceService{db: sqlite.NewContextDB(db)}
+}
+
+// Create inserts the workspace and, with SeedDefaults, its default records
+// in one transaction.
+func (s *WorkspaceService) Create(ctx context.Context, input CreateWorkspaceInput) (*Workspace, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin create workspace: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	workspaceID, err := s.CreateTx(ctx, tx.Tx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit create workspace: %w", err)
+	}
+	return s.Get(ctx, workspaceID)
+}
+
+// CreateTx is Create within the caller's transaction, for callers creating
+// other records with the workspace. It returns the new workspace ID.
+func (s *WorkspaceService) CreateTx(ctx context.Context, tx *sql.Tx, input CreateWorkspaceInput) (string, error) {
+	id := uuid.NewV7().String()
+	slug := input.Slug
+	if slug == "" {
+		slug = workspaceSlug(input.Name, id)
+	}
+	if err := ensureWorkspaceSlugFree(ctx, tx, slug, ""); err != nil {
+		return "", err
+	}
+
+	now := nowRFC3339()
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO workspace (id, name, slug, settings, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, id, input.Name, slug, nullString(input.Settings), now, now)
+	if err != nil {
+		return "", fmt.Errorf("create workspace: %w", err)
+	}
+	if input.SeedDefaults {
+		if err = seedWorkspaceDefaults(ctx, tx, id, now); err != nil {
+			return "", err
+		}
+	}
+	return id, nil
+}
+
+// Get returns sql.ErrNoRows for missing and soft-deleted workspaces.
+func (s *WorkspaceService) Get(ctx context.Context, workspaceID string) (*Workspace, error) {
+	row := s.db.QueryRowContext(ctx,
+		`SELECT `+workspaceColumns+` FROM workspace WHERE id = ? AND deleted_at IS NULL`, workspaceID)
+	ws, err := scanWorkspace(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, sql.ErrNoRows
+		}
+		return nil, fmt.Errorf("get workspace by id: %w", err)
+	}
+	return ws, nil
+}
+
+// ListForUser returns the live workspaces userID is an active member of.
+func (s *WorkspaceService) ListForUser(ctx context.Context, userID string) ([]*Workspace, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT w.id, w.name, w.slug, w.settings, w.created_at, w.updated_at
+		FROM workspace w
+		JOIN user_account u ON u.workspace_id = w.id
+		WHERE u.id = ? AND u.status = 'active' AND w.deleted_at IS NULL
+		ORDER BY w.name ASC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list workspaces for user: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*Workspace{}
+	for rows.Next() {
+		ws, scanErr := scanWorkspace(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("scan workspace: %w", scanErr)
+		}
+		out = append(out, ws)
+	}
+	return out, rows.Err()
+}
+
+func (s *WorkspaceService) Update(ctx context.Context, workspaceID string, input UpdateWorkspaceInput) (*Workspace, error) {
+	if !workspaceSlugPattern.MatchString(input.Slug) {
+		return nil, ErrInvalidWorkspaceSlug
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin update workspace: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = ensureWorkspaceSlugFree(ctx, tx.Tx, input.Slug, workspaceID); err != nil {
+		return nil, err
+	}
+	res, err := tx.ExecContext(ctx, `
+		UPDATE workspace SET name = ?, slug = ?, settings = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL
+	`, input.Name, input.Slug, nullString(input.Settings), nowRFC3339(), workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("update workspace: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, sql.ErrNoRows
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit update workspace: %w", err)
+	}
+	return s.Get(ctx, workspaceID)
+}
+
+// Delete soft-deletes the workspace. Its records are kept; the workspace
+// disappears from Get and ListForUser.
+func (s *WorkspaceService) Delete(ctx context.Context, workspaceID string) error {
+	now := nowRFC3339()
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE workspace SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL`, now, now, workspaceID)
+	if err != nil {
+		return fmt.Errorf("delete workspace: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func ensureWorkspaceSlugFree(ctx context.Context, tx *sql.Tx, slug, workspaceID string) error {
+	if !workspaceSlugPattern.MatchString(slug) {
+		return ErrInvalidWorkspaceSlug
+	}
+	var taken bool
+	err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM workspace WHERE slug = ? AND id != ?)`, slug, workspaceID).Scan(&taken)
+	if err != nil {
+		return fmt.Errorf("check workspace slug: %w", err)
+	}
+	if taken {
+		return ErrWorkspaceSlugTaken
+	}
+	return nil
+}
+
+// workspaceSlug makes a URL-safe slug from the name and the full workspace
+// ID, so identical names never collide.
+func workspaceSlug(name, id string) string {
+	slug := strings.Trim(strings.Map(workspaceSlugChar, name), "-")
+	if slug == "" {
+		return id
+	}
+	return slug + "-" + id
+}
+
+// workspaceSlugChar lowercases letters, keeps digits, turns spaces and
+// dashes into '-' and drops everything else.
+func workspaceSlugChar(c rune) rune {
+	switch {
+	case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		return c
+	case c >= 'A' && c <= 'Z':
+		return c + 32 // to lower
+	case c == ' ', c == '-':
+		return '-'
+	default:
+		return -1 // skip
+	}
+}
+
+type workspaceScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWorkspace(row workspaceScanner) (*Workspace, error) {
+	var ws Workspace
+	var createdAt, updatedAt string
+	if err := row.Scan(&ws.ID, &ws.Name, &ws.Slug, &ws.Settings, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	ws.CreatedAt = parseRFC3339Time(createdAt)
+	ws.UpdatedAt = parseRFC3339Time(updatedAt)
+	return &ws, nil
+}
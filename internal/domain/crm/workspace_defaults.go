@@ -0,0 +1,86 @@
+package crm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+type defaultStage struct {
+	name        string
+	probability *float64
+}
+
+type defaultPipeline struct {
+	name       string
+	entityType string
+	stages     []defaultStage
+}
+
+func stageProbability(p float64) *float64 { return &p }
+
+// defaultPipelines are seeded into new workspaces provisioned with
+// SeedDefaults; stage positions follow the slice order from 1.
+var defaultPipelines = []defaultPipeline{
+	{
+		name:       "Sales",
+		entityType: "deal",
+		stages: []defaultStage{
+			{"Qualification", stageProbability(0.1)},
+			{"Discovery", stageProbability(0.25)},
+			{"Proposal", stageProbability(0.5)},
+			{"Negotiation", stageProbability(0.75)},
+			{"Closed Won", stageProbability(1)},
+			{"Closed Lost", stageProbability(0)},
+		},
+	},
+	{
+		name:       "Support",
+		entityType: "case",
+		stages: []defaultStage{
+			{"New", nil},
+			{"In Progress", nil},
+			{"Waiting on Customer", nil},
+			{"Resolved", nil},
+		},
+	},
+}
+
+const (
+	defaultAgentName        = "Support Agent"
+	defaultAgentDescription = "Resolves, escalates or abstains on support cases using the knowledge base."
+	defaultAgentObjective   = `{"goal":"resolve support cases with grounded answers"}`
+)
+
+func seedWorkspaceDefaults(ctx context.Context, tx *sql.Tx, workspaceID, now string) error {
+	for _, p := range defaultPipelines {
+		pipelineID := uuid.NewV7().String()
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO pipeline (id, workspace_id, name, entity_type, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+		`, pipelineID, workspaceID, p.name, p.entityType, now, now)
+		if err != nil {
+			return fmt.Errorf("seed pipeline %s: %w", p.name, err)
+		}
+		for i, st := range p.stages {
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO pipeline_stage (id, pipeline_id, name, position, probability, created_at, updated_at)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+			`, uuid.NewV7().String(), pipelineID, st.name, i+1, st.probability, now, now)
+			if err != nil {
+				return fmt.Errorf("seed pipeline stage %s: %w", st.name, err)
+			}
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO agent_definition (id, workspace_id, name, description, agent_type, objective, status)
+		VALUES (?, ?, ?, ?, 'support', ?, 'active')
+	`, uuid.NewV7().String(), workspaceID, defaultAgentName, defaultAgentDescription, defaultAgentObjective)
+	if err != nil {
+		return fmt.Errorf("seed agent definition: %w", err)
+	}
+	return nil
+}
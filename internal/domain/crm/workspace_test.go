@@ -0,0 +1,149 @@
+package crm_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
+)
+
+func TestWorkspaceService_Create_RejectsTakenAndInvalidSlugs(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	svc := crm.NewWorkspaceService(db)
+
+	acme, err := svc.Create(ctx, crm.CreateWorkspaceInput{Name: "Acme", Slug: "acme"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if acme.Slug != "acme" || acme.Name != "Acme" {
+		t.Fatalf("Create() = %+v", acme)
+	}
+	if _, err = svc.Create(ctx, crm.CreateWorkspaceInput{Name: "Acme Again", Slug: "acme"}); !errors.Is(err, crm.ErrWorkspaceSlugTaken) {
+		t.Fatalf("Create(taken slug) error = %v; want ErrWorkspaceSlugTaken", err)
+	}
+	if _, err = svc.Create(ctx, crm.CreateWorkspaceInput{Name: "Bad", Slug: "Not A Slug"}); !errors.Is(err, crm.ErrInvalidWorkspaceSlug) {
+		t.Fatalf("Create(invalid slug) error = %v; want ErrInvalidWorkspaceSlug", err)
+	}
+
+	derived, err := svc.Create(ctx, crm.CreateWorkspaceInput{Name: "Acme Corp!"})
+	if err != nil {
+		t.Fatalf("Create(no slug) error = %v", err)
+	}
+	if derived.Slug != "acme-corp-"+derived.ID {
+		t.Fatalf("derived slug = %q", derived.Slug)
+	}
+
+	if _, err = svc.Update(ctx, derived.ID, crm.UpdateWorkspaceInput{Name: derived.Name, Slug: "acme"}); !errors.Is(err, crm.ErrWorkspaceSlugTaken) {
+		t.Fatalf("Update(taken slug) error = %v; want ErrWorkspaceSlugTaken", err)
+	}
+	// Keeping its own slug is not a conflict.
+	updated, err := svc.Update(ctx, acme.ID, crm.UpdateWorkspaceInput{Name: "Acme Inc", Slug: "acme"})
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated.Name != "Acme Inc" {
+		t.Fatalf("Update() name = %q", updated.Name)
+	}
+
+	// Soft-deleted workspaces keep their slug.
+	if err = svc.Delete(ctx, acme.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err = svc.Get(ctx, acme.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Get(deleted) error = %v; want sql.ErrNoRows", err)
+	}
+	if err = svc.Delete(ctx, acme.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Delete(deleted) error = %v; want sql.ErrNoRows", err)
+	}
+	if _, err = svc.Create(ctx, crm.CreateWorkspaceInput{Name: "Acme", Slug: "acme"}); !errors.Is(err, crm.ErrWorkspaceSlugTaken) {
+		t.Fatalf("Create(slug of deleted) error = %v; want ErrWorkspaceSlugTaken", err)
+	}
+}
+
+func TestWorkspaceService_Create_SeedsDefaults(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	svc := crm.NewWorkspaceService(db)
+
+	seeded, err := svc.Create(ctx, crm.CreateWorkspaceInput{Name: "Seeded", SeedDefaults: true})
+	if err != nil {
+		t.Fatalf("Create(seeded) error = %v", err)
+	}
+	bare, err := svc.Create(ctx, crm.CreateWorkspaceInput{Name: "Bare"})
+	if err != nil {
+		t.Fatalf("Create(bare) error = %v", err)
+	}
+
+	pipelines, total, err := crm.NewPipelineService(db).List(ctx, seeded.ID, crm.ListPipelinesInput{Limit: 10})
+	if err != nil {
+		t.Fatalf("List(pipelines) error = %v", err)
+	}
+	if total != 2 {
+		t.Fatalf("seeded pipelines = %d; want 2", total)
+	}
+	stagesByType := map[string]int{}
+	for _, p := range pipelines {
+		stages, stageErr := crm.NewPipelineService(db).ListStages(ctx, p.ID)
+		if stageErr != nil {
+			t.Fatalf("ListStages() error = %v", stageErr)
+		}
+		stagesByType[p.EntityType] = len(stages)
+	}
+	if stagesByType["deal"] != 6 || stagesByType["case"] != 4 {
+		t.Fatalf("seeded stages = %v; want 6 deal and 4 case stages", stagesByType)
+	}
+
+	var agentType string
+	if err = db.QueryRow(`SELECT agent_type FROM agent_definition WHERE workspace_id = ?`, seeded.ID).Scan(&agentType); err != nil {
+		t.Fatalf("load seeded agent definition: %v", err)
+	}
+	if agentType != "support" {
+		t.Fatalf("seeded agent_type = %q; want support", agentType)
+	}
+
+	var bareRecords int
+	if err = db.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM pipeline WHERE workspace_id = ?)
+		     + (SELECT COUNT(*) FROM agent_definition WHERE workspace_id = ?)`, bare.ID, bare.ID).Scan(&bareRecords); err != nil {
+		t.Fatalf("count bare records: %v", err)
+	}
+	if bareRecords != 0 {
+		t.Fatalf("workspace without SeedDefaults has %d default records; want 0", bareRecords)
+	}
+}
+
+func TestWorkspaceService_ListForUser(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDBWithMigrations(t)
+	ctx := context.Background()
+	wsID, userID := setupWorkspaceAndOwner(t, db)
+	setupWorkspaceAndOwner(t, db)
+	svc := crm.NewWorkspaceService(db)
+
+	got, err := svc.ListForUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListForUser() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != wsID {
+		t.Fatalf("ListForUser() = %+v; want only %s", got, wsID)
+	}
+
+	if err = svc.Delete(ctx, wsID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	got, err = svc.ListForUser(ctx, userID)
+	if err != nil {
+		t.Fatalf("ListForUser(after delete) error = %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("ListForUser(after delete) = %+v; want none", got)
+	}
+}
@@ -190,6 +190,10 @@ func missingAuditForMutationViolations(toolCalls []TraceToolCall, auditEvents []
 		if toolCall.Status != traceStatusExecuted || !isMutatingTool(toolCall.ToolName) {
 			continue
 		}
+		// Agent tool calls are audited under the tool name itself.
+		if hasAuditAction(auditEvents, toolCall.ToolName) {
+			continue
+		}
 		out = append(out, newHardGateViolation(
 			"missing_audit_for_mutation",
 			fmt.Sprintf("mutating tool %q executed without audit trail", toolCall.ToolName),
@@ -120,6 +120,23 @@ func TestHardGateMissingAuditForMutation(t *testing.T) {
 	}
 }
 
+func TestHardGateAgentToolAuditSatisfiesMutationAudit(t *testing.T) {
+	t.Parallel()
+
+	scenario := makeHardGateScenario()
+	trace := makeHardGateTrace()
+	trace.AuditEvents = []eval.TraceAuditEvent{
+		{ID: "evt-001", Action: "create_task", Outcome: "success", ActorID: "run-hardgate-001"},
+	}
+	setHardGateFinalState(&trace, map[string]any{"case.status": "In Progress"})
+
+	violations := eval.EvaluateHardGates(scenario, trace, eval.Compare(scenario, trace))
+
+	if hasHardGate(violations, "missing_audit_for_mutation") {
+		t.Fatalf("unexpected missing_audit_for_mutation violation: %#v", violations)
+	}
+}
+
 func TestHardGateCompletedWhenExpectedAbstention(t *testing.T) {
 	t.Parallel()
 
@@ -298,7 +298,9 @@ func validateContract(t *ActualRunTrace) TraceContractValidation {
 	mutatorsTraceable := true
 	for _, tc := range t.ToolCalls {
 		if tc.Status == "executed" {
-			if _, ok := auditActions["tool.executed"]; !ok {
+			_, generic := auditActions["tool.executed"]
+			_, named := auditActions[tc.ToolName]
+			if !generic && !named {
 				mutatorsTraceable = false
 				break
 			}
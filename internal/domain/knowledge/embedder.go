@@ -1,17 +1,21 @@
 // Package knowledge — Task 2.4: EmbedderService.
 // Consumes knowledge.ingested events from the event bus, calls LLMProvider.Embed()
-// in batch per knowledge_item, stores vectors in vec_embedding, and marks
+// in batches per knowledge_item, stores vectors in vec_embedding, and marks
 // embedding_document rows as 'embedded' or 'failed'.
 package knowledge
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
 	"github.com/matiasleandrokruk/fenix/internal/infra/llm"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 
 	"database/sql"
@@ -20,13 +24,30 @@ import (
 const (
 	embedMaxRetries = 3
 	embedBaseDelay  = 100 * time.Millisecond
+
+	embedderComponent = "knowledge.embedder"
+)
+
+var (
+	// ErrEmbeddingCountMismatch is returned when the provider does not return
+	// exactly one vector per chunk.
+	ErrEmbeddingCountMismatch = errors.New("embedding count does not match chunk count")
+	// ErrEmbeddingDimensionMismatch is returned when a vector's length differs
+	// from the expected dimension, e.g. after an embed model swap.
+	ErrEmbeddingDimensionMismatch = errors.New("embedding dimension mismatch")
 )
 
 // EmbedderService processes pending embedding_document rows (Task 2.4).
 type EmbedderService struct {
-	db  *sql.DB
-	q   *sqlcgen.Queries
-	llm llm.LLMProvider
+	db         *sql.DB
+	q          *sqlcgen.Queries
+	llm        llm.LLMProvider
+	dimensions int
+	batchSize  int
+	workers    int
+	// storeMu serialises vector writes so concurrent batches do not contend
+	// for the SQLite write lock; only the Embed calls run in parallel.
+	storeMu sync.Mutex
 }
 
 // NewEmbedderService creates an EmbedderService backed by the given DB and LLM provider.
@@ -38,6 +59,25 @@ func NewEmbedderService(db *sql.DB, provider llm.LLMProvider) *EmbedderService {
 	}
 }
 
+// SetDimensions fixes the vector length every embedding must have. With 0
+// (the default) the expected length is that of the vectors already stored
+// for the workspace, so a model swap is still caught once data exists.
+func (s *EmbedderService) SetDimensions(n int) {
+	s.dimensions = n
+}
+
+// SetBatchSize caps how many chunks go into one Embed call. With 0 (the
+// default) all pending chunks of an item are embedded in a single batch.
+func (s *EmbedderService) SetBatchSize(n int) {
+	s.batchSize = n
+}
+
+// SetWorkers sets how many batches of one item are embedded concurrently.
+// Values below 1 mean 1, i.e. batches run one after another.
+func (s *EmbedderService) SetWorkers(n int) {
+	s.workers = n
+}
+
 // Start subscribes to TopicKnowledgeIngested and runs EmbedChunks for each event.
 // Runs in the calling goroutine — launch with: go svc.Start(ctx, bus)
 // Stops when ctx is cancelled.
@@ -53,14 +93,24 @@ func (s *EmbedderService) Start(ctx context.Context, bus eventbus.EventBus) {
 				continue
 			}
 			// Best-effort: log error but keep running
-			_ = s.EmbedChunks(ctx, payload.KnowledgeItemID, payload.WorkspaceID)
+			if err := s.EmbedChunks(ctx, payload.KnowledgeItemID, payload.WorkspaceID); err != nil {
+				logging.FromContext(ctx).Error("embed knowledge item failed",
+					slog.String(logging.KeyComponent, embedderComponent),
+					slog.String(logging.KeyWorkspaceID, payload.WorkspaceID),
+					slog.String("knowledge_item_id", payload.KnowledgeItemID),
+					logging.Err(err))
+			}
 		}
 	}
 }
 
-// EmbedChunks fetches all pending chunks for a knowledge_item, calls LLM.Embed()
-// in a single batch, stores vectors in vec_embedding, and marks status='embedded'.
-// If the LLM call fails after all retries, marks chunks as 'failed' and returns an error.
+// EmbedChunks fetches all pending chunks for a knowledge_item, embeds them in
+// batches of at most SetBatchSize chunks using up to SetWorkers concurrent
+// LLM.Embed() calls, and stores each batch's vectors in its own transaction.
+// A batch whose Embed call fails after all retries, or whose vectors fail
+// validation, has only its chunks marked 'failed'; the errors of all failed
+// batches are joined in the returned error. Batches not started before ctx is
+// cancelled stay 'pending'.
 func (s *EmbedderService) EmbedChunks(ctx context.Context, knowledgeItemID, workspaceID string) error {
 	chunks, err := s.fetchPendingChunks(ctx, knowledgeItemID, workspaceID)
 	if err != nil {
@@ -70,6 +120,67 @@ func (s *EmbedderService) EmbedChunks(ctx context.Context, knowledgeItemID, work
 		return nil // nothing to embed
 	}
 
+	want, err := s.expectedDimension(ctx, workspaceID)
+	if err != nil {
+		s.markAllFailed(ctx, chunks)
+		return fmt.Errorf("embedder: %w", err)
+	}
+	dims := &batchDimension{want: want}
+
+	batches := splitEmbedBatches(chunks, s.batchSize)
+	if len(batches) == 1 {
+		return s.embedBatch(ctx, workspaceID, batches[0], dims)
+	}
+	return s.embedBatchesConcurrently(ctx, workspaceID, batches, dims)
+}
+
+// embedBatchesConcurrently runs embedBatch for every batch on a pool of
+// workers and joins the per-batch errors.
+func (s *EmbedderService) embedBatchesConcurrently(ctx context.Context, workspaceID string, batches [][]sqlcgen.EmbeddingDocument, dims *batchDimension) error {
+	workers := min(max(s.workers, 1), len(batches))
+	errs := make([]error, len(batches)+1)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := s.embedBatch(ctx, workspaceID, batches[i], dims); err != nil {
+					errs[i] = fmt.Errorf("batch %d/%d: %w", i+1, len(batches), err)
+				}
+			}
+		}()
+	}
+
+	errs[len(batches)] = dispatchEmbedBatches(ctx, jobs, len(batches))
+	close(jobs)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// dispatchEmbedBatches hands batch indexes 0..n-1 to the workers until ctx is
+// cancelled, reporting how many batches were left unstarted.
+func dispatchEmbedBatches(ctx context.Context, jobs chan<- int, n int) error {
+	for i := range n {
+		// Checked before the select, which would otherwise pick at random
+		// between a cancelled ctx and an idle worker.
+		if ctx.Err() == nil {
+			select {
+			case jobs <- i:
+				continue
+			case <-ctx.Done():
+			}
+		}
+		return fmt.Errorf("embedder: %d of %d batches not started: %w", n-i, n, ctx.Err())
+	}
+	return nil
+}
+
+// embedBatch embeds one batch of chunks and stores the vectors, marking the
+// batch 'failed' on any error.
+func (s *EmbedderService) embedBatch(ctx context.Context, workspaceID string, chunks []sqlcgen.EmbeddingDocument, dims *batchDimension) error {
 	texts := make([]string, len(chunks))
 	for i, c := range chunks {
 		texts[i] = c.ChunkText
@@ -81,13 +192,51 @@ func (s *EmbedderService) EmbedChunks(ctx context.Context, knowledgeItemID, work
 		return fmt.Errorf("embedder: LLM.Embed: %w", err)
 	}
 
-	if storeErr := s.storeVectors(ctx, chunks, vecs, workspaceID); storeErr != nil {
+	if checkErr := s.checkEmbeddings(len(chunks), vecs, dims); checkErr != nil {
+		s.markAllFailed(ctx, chunks)
+		return fmt.Errorf("embedder: %w", checkErr)
+	}
+
+	s.storeMu.Lock()
+	storeErr := s.storeVectors(ctx, chunks, vecs, workspaceID)
+	s.storeMu.Unlock()
+	if storeErr != nil {
 		s.markAllFailed(ctx, chunks)
 		return fmt.Errorf("embedder: store vectors: %w", storeErr)
 	}
 	return nil
 }
 
+// splitEmbedBatches cuts chunks into consecutive batches of at most size
+// chunks; size < 1 keeps them in one batch.
+func splitEmbedBatches(chunks []sqlcgen.EmbeddingDocument, size int) [][]sqlcgen.EmbeddingDocument {
+	if size < 1 || size >= len(chunks) {
+		return [][]sqlcgen.EmbeddingDocument{chunks}
+	}
+	batches := make([][]sqlcgen.EmbeddingDocument, 0, (len(chunks)+size-1)/size)
+	for start := 0; start < len(chunks); start += size {
+		batches = append(batches, chunks[start:min(start+size, len(chunks))])
+	}
+	return batches
+}
+
+// batchDimension is the vector length shared by the batches of one
+// EmbedChunks call. When neither configured nor stored, the first batch to
+// return vectors sets it.
+type batchDimension struct {
+	mu   sync.Mutex
+	want int
+}
+
+func (d *batchDimension) resolve(first int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.want == 0 {
+		d.want = first
+	}
+	return d.want
+}
+
 // fetchPendingChunks returns all embedding_document rows with status='pending'
 // for the given knowledge_item within the workspace.
 func (s *EmbedderService) fetchPendingChunks(ctx context.Context, itemID, wsID string) ([]sqlcgen.EmbeddingDocument, error) {
@@ -102,7 +251,8 @@ func (s *EmbedderService) fetchPendingChunks(ctx context.Context, itemID, wsID s
 }
 
 // callEmbedWithRetry calls LLMProvider.Embed() with exponential backoff.
-// Attempts: maxRetries (100ms, 200ms, 400ms delays).
+// Attempts: maxRetries (100ms, 200ms, 400ms delays). Each failed attempt is
+// logged as a warning.
 func (s *EmbedderService) callEmbedWithRetry(ctx context.Context, texts []string) ([][]float32, error) {
 	var lastErr error
 	delay := embedBaseDelay
@@ -120,10 +270,54 @@ func (s *EmbedderService) callEmbedWithRetry(ctx context.Context, texts []string
 			return resp.Embeddings, nil
 		}
 		lastErr = err
+		logging.FromContext(ctx).Warn("embed attempt failed",
+			slog.String(logging.KeyComponent, embedderComponent),
+			slog.Int(logging.KeyAttempt, attempt+1),
+			logging.Err(err))
 	}
 	return nil, fmt.Errorf("all %d retries failed: %w", embedMaxRetries, lastErr)
 }
 
+// checkEmbeddings validates the provider output before anything is stored:
+// one vector per chunk, all of the dimension dims resolves to.
+func (s *EmbedderService) checkEmbeddings(chunkCount int, vecs [][]float32, dims *batchDimension) error {
+	if len(vecs) != chunkCount {
+		return fmt.Errorf("%w: got %d vectors for %d chunks", ErrEmbeddingCountMismatch, len(vecs), chunkCount)
+	}
+	if len(vecs) == 0 {
+		return nil
+	}
+	want := dims.resolve(len(vecs[0]))
+	model := s.llm.ModelInfo().ID
+	for i, vec := range vecs {
+		if len(vec) == 0 || len(vec) != want {
+			return fmt.Errorf("%w: embedding[%d] from model %q has %d dimensions, expected %d",
+				ErrEmbeddingDimensionMismatch, i, model, len(vec), want)
+		}
+	}
+	return nil
+}
+
+// expectedDimension returns the configured dimension, else the length of a
+// vector already stored for the workspace, else 0.
+func (s *EmbedderService) expectedDimension(ctx context.Context, workspaceID string) (int, error) {
+	if s.dimensions > 0 {
+		return s.dimensions, nil
+	}
+	var n int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT json_array_length(embedding) FROM vec_embedding WHERE workspace_id = ? LIMIT 1`,
+		workspaceID,
+	).Scan(&n)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read stored embedding dimension: %w", err)
+	}
+	return n, nil
+}
+
 // storeVectors inserts float32 vectors into vec_embedding and marks each
 // embedding_document as 'embedded'. Runs in a single transaction.
 func (s *EmbedderService) storeVectors(ctx context.Context, chunks []sqlcgen.EmbeddingDocument, vecs [][]float32, workspaceID string) error {
@@ -175,8 +369,10 @@ func storeChunkVector(ctx context.Context, qtx *sqlcgen.Queries, chunk sqlcgen.E
 
 // markAllFailed sets embedding_status='failed' on all given chunks.
 // Called after all retries are exhausted. Errors are silently ignored to avoid
-// masking the original embed error.
+// masking the original embed error. The update outlives ctx cancellation so a
+// batch interrupted mid-flight is not left 'pending'.
 func (s *EmbedderService) markAllFailed(ctx context.Context, chunks []sqlcgen.EmbeddingDocument) {
+	ctx = context.WithoutCancel(ctx)
 	for _, chunk := range chunks {
 		_ = s.q.UpdateEmbeddingDocumentStatus(ctx, sqlcgen.UpdateEmbeddingDocumentStatusParams{
 			EmbeddingStatus: string(EmbeddingStatusFailed),
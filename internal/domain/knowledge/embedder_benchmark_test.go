@@ -0,0 +1,59 @@
+package knowledge
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/llm"
+)
+
+// embedBenchmarkLatency stands in for the round trip to the embed model,
+// which dominates EmbedChunks in production.
+const embedBenchmarkLatency = 2 * time.Millisecond
+
+func BenchmarkEmbedChunks_Serial(b *testing.B) {
+	benchmarkEmbedChunks(b, 1)
+}
+
+func BenchmarkEmbedChunks_Parallel4(b *testing.B) {
+	benchmarkEmbedChunks(b, 4)
+}
+
+func benchmarkEmbedChunks(b *testing.B, workers int) {
+	db := setupSearchBenchmarkDB(b)
+	defer db.Close()
+
+	stub := newStubEmbedder(8)
+	embed := stub.embedFunc
+	stub.embedFunc = func(ctx context.Context, req llm.EmbedRequest) (*llm.EmbedResponse, error) {
+		time.Sleep(embedBenchmarkLatency)
+		return embed(ctx, req)
+	}
+	svc := NewEmbedderService(db, stub)
+	svc.SetBatchSize(1)
+	svc.SetWorkers(workers)
+	wsID := createBenchmarkWorkspace(b, db)
+	ingest := NewIngestService(db, eventbus.New())
+	content := strings.Repeat("benchmark embedding chunk content ", 1000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		item, err := ingest.Ingest(context.Background(), CreateKnowledgeItemInput{
+			WorkspaceID: wsID,
+			SourceType:  SourceTypeDocument,
+			Title:       "Embed Benchmark",
+			RawContent:  content,
+		})
+		if err != nil {
+			b.Fatalf("ingest failed: %v", err)
+		}
+		b.StartTimer()
+		if err := svc.EmbedChunks(context.Background(), item.ID, wsID); err != nil {
+			b.Fatalf("EmbedChunks: %v", err)
+		}
+	}
+}
@@ -6,8 +6,10 @@ package knowledge
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"math"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -216,6 +218,217 @@ func TestEmbedderService_EmbedChunks_LLMError_StatusFailed(t *testing.T) {
 	}
 }
 
+func TestEmbedderService_EmbedChunks_DimensionMismatch_StatusFailed(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	wsID := createWorkspace(t, db)
+	ingest := NewIngestService(db, eventbus.New())
+	ingestDoc := func(title string) string {
+		t.Helper()
+		item, err := ingest.Ingest(ctx, CreateKnowledgeItemInput{
+			WorkspaceID: wsID,
+			SourceType:  SourceTypeDocument,
+			Title:       title,
+			RawContent:  "content to embed",
+		})
+		if err != nil {
+			t.Fatalf("ingest failed: %v", err)
+		}
+		return item.ID
+	}
+	assertFailed := func(itemID string) {
+		t.Helper()
+		var status string
+		if err := db.QueryRowContext(ctx,
+			`SELECT embedding_status FROM embedding_document WHERE knowledge_item_id = ?`, itemID,
+		).Scan(&status); err != nil {
+			t.Fatalf("query failed: %v", err)
+		}
+		if status != string(EmbeddingStatusFailed) {
+			t.Errorf("expected status 'failed' after dimension mismatch, got %q", status)
+		}
+	}
+
+	// Configured dimension: a 3-dim vector against an expected 4.
+	configured := NewEmbedderService(db, newStubEmbedder(3))
+	configured.SetDimensions(4)
+	itemID := ingestDoc("Configured Dimension")
+	err := configured.EmbedChunks(ctx, itemID, wsID)
+	if !errors.Is(err, ErrEmbeddingDimensionMismatch) {
+		t.Fatalf("expected ErrEmbeddingDimensionMismatch, got %v", err)
+	}
+	assertFailed(itemID)
+
+	// Inferred dimension: stored 3-dim vectors, then the model starts returning 5.
+	if err = NewEmbedderService(db, newStubEmbedder(3)).EmbedChunks(ctx, ingestDoc("Stored"), wsID); err != nil {
+		t.Fatalf("EmbedChunks failed: %v", err)
+	}
+	itemID = ingestDoc("Swapped Model")
+	err = NewEmbedderService(db, newStubEmbedder(5)).EmbedChunks(ctx, itemID, wsID)
+	if !errors.Is(err, ErrEmbeddingDimensionMismatch) {
+		t.Fatalf("expected ErrEmbeddingDimensionMismatch, got %v", err)
+	}
+	assertFailed(itemID)
+
+	var vecCount int
+	if err = db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM vec_embedding WHERE workspace_id = ? AND json_array_length(embedding) != 3`, wsID,
+	).Scan(&vecCount); err != nil {
+		t.Fatalf("vec_embedding count query failed: %v", err)
+	}
+	if vecCount != 0 {
+		t.Errorf("expected no mismatched vectors stored, got %d", vecCount)
+	}
+}
+
+func TestEmbedderService_EmbedChunks_CountMismatch_StatusFailed(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	stub := &stubEmbedder{
+		embedFunc: func(_ context.Context, _ llm.EmbedRequest) (*llm.EmbedResponse, error) {
+			return &llm.EmbedResponse{}, nil
+		},
+	}
+	svc := NewEmbedderService(db, stub)
+	wsID := createWorkspace(t, db)
+
+	item, err := NewIngestService(db, eventbus.New()).Ingest(context.Background(), CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeDocument,
+		Title:       "Short Batch",
+		RawContent:  "content to embed",
+	})
+	if err != nil {
+		t.Fatalf("ingest failed: %v", err)
+	}
+
+	if err = svc.EmbedChunks(context.Background(), item.ID, wsID); !errors.Is(err, ErrEmbeddingCountMismatch) {
+		t.Fatalf("expected ErrEmbeddingCountMismatch, got %v", err)
+	}
+}
+
+// ingestLongDocument ingests a document long enough to produce several
+// chunks and returns its item ID. last is the final word of the document, so
+// it only appears in the last chunk.
+func ingestLongDocument(t *testing.T, db *sql.DB, wsID, last string) string {
+	t.Helper()
+	ingest := NewIngestService(db, eventbus.New())
+	item, err := ingest.Ingest(context.Background(), CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeDocument,
+		Title:       "Long Doc",
+		RawContent:  strings.Repeat("lorem ipsum dolor sit amet ", 500) + last,
+	})
+	if err != nil {
+		t.Fatalf("ingest failed: %v", err)
+	}
+	return item.ID
+}
+
+func embeddingStatusCounts(t *testing.T, db *sql.DB, itemID string) map[string]int {
+	t.Helper()
+	rows, err := db.QueryContext(context.Background(),
+		`SELECT embedding_status, COUNT(*) FROM embedding_document WHERE knowledge_item_id = ? GROUP BY embedding_status`, itemID)
+	if err != nil {
+		t.Fatalf("status query failed: %v", err)
+	}
+	defer rows.Close()
+	counts := map[string]int{}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			t.Fatalf("scan failed: %v", err)
+		}
+		counts[status] = n
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("row iteration error: %v", err)
+	}
+	return counts
+}
+
+func TestEmbedderService_EmbedChunks_ParallelBatches_FailOnlyFailedBatch(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ok := newStubEmbedder(3)
+	stub := &stubEmbedder{
+		embedFunc: func(ctx context.Context, req llm.EmbedRequest) (*llm.EmbedResponse, error) {
+			for _, text := range req.Texts {
+				if strings.HasSuffix(text, "poison") {
+					return nil, errors.New("provider rejected batch")
+				}
+			}
+			return ok.embedFunc(ctx, req)
+		},
+	}
+	svc := NewEmbedderService(db, stub)
+	svc.SetBatchSize(1)
+	svc.SetWorkers(3)
+	wsID := createWorkspace(t, db)
+	itemID := ingestLongDocument(t, db, wsID, "poison")
+
+	total := 0
+	for _, n := range embeddingStatusCounts(t, db, itemID) {
+		total += n
+	}
+	if total < 3 {
+		t.Fatalf("expected a multi-chunk document, got %d chunks", total)
+	}
+
+	err := svc.EmbedChunks(context.Background(), itemID, wsID)
+	if err == nil || !strings.Contains(err.Error(), "provider rejected batch") {
+		t.Fatalf("expected joined batch error, got %v", err)
+	}
+	counts := embeddingStatusCounts(t, db, itemID)
+	if counts[string(EmbeddingStatusFailed)] != 1 || counts[string(EmbeddingStatusEmbedded)] != total-1 {
+		t.Fatalf("expected only the poisoned chunk failed, got %v", counts)
+	}
+
+	var vecCount int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM vec_embedding WHERE workspace_id = ?`, wsID).Scan(&vecCount); err != nil {
+		t.Fatalf("vec_embedding count query failed: %v", err)
+	}
+	if vecCount != total-1 {
+		t.Fatalf("expected %d vec_embedding rows, got %d", total-1, vecCount)
+	}
+}
+
+func TestEmbedderService_EmbedChunks_CancelStopsRemainingBatches(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stub := &stubEmbedder{
+		embedFunc: func(_ context.Context, _ llm.EmbedRequest) (*llm.EmbedResponse, error) {
+			cancel()
+			return nil, context.Canceled
+		},
+	}
+	svc := NewEmbedderService(db, stub)
+	svc.SetBatchSize(1)
+	svc.SetWorkers(1)
+	wsID := createWorkspace(t, db)
+	itemID := ingestLongDocument(t, db, wsID, "end")
+
+	err := svc.EmbedChunks(ctx, itemID, wsID)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if got := atomic.LoadInt32(&stub.callCount); got != 1 {
+		t.Fatalf("expected 1 Embed call before cancellation, got %d", got)
+	}
+	counts := embeddingStatusCounts(t, db, itemID)
+	if counts[string(EmbeddingStatusFailed)] != 1 || counts[string(EmbeddingStatusPending)] == 0 {
+		t.Fatalf("expected the interrupted batch failed and the rest pending, got %v", counts)
+	}
+}
+
 func TestEmbedderService_WorkspaceIsolation(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -8,9 +8,10 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"math"
 	"sort"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite/sqlcgen"
 	"github.com/matiasleandrokruk/fenix/pkg/uuid"
@@ -23,9 +24,19 @@ const (
 
 // EvidenceConfig configures EvidencePackService behavior.
 type EvidenceConfig struct {
-	DefaultTopK            int
-	FreshnessWarning       time.Duration
-	DedupThreshold         float64
+	DefaultTopK int
+	// FreshnessWarning is the age after which an item counts as stale.
+	FreshnessWarning time.Duration
+	// FreshnessWarningBySource overrides FreshnessWarning per source type,
+	// e.g. shorter for tickets than for policy documents. Non-positive
+	// entries are ignored.
+	FreshnessWarningBySource map[SourceType]time.Duration
+	DedupThreshold           float64
+	// TextDedupThreshold is the share of a candidate snippet's word shingles
+	// found in the chunks of a selected item above which the snippet counts
+	// as a copy, catching boilerplate repeated across documents whose vectors
+	// differ.
+	TextDedupThreshold     float64
 	HighConfidenceMin      float64
 	MediumConfidenceMin    float64
 	PermissionCheckStubbed bool
@@ -37,12 +48,21 @@ func DefaultEvidenceConfig() EvidenceConfig {
 		DefaultTopK:            10,
 		FreshnessWarning:       30 * 24 * time.Hour,
 		DedupThreshold:         0.95,
+		TextDedupThreshold:     0.8,
 		HighConfidenceMin:      0.8,
 		MediumConfidenceMin:    0.5,
 		PermissionCheckStubbed: true,
 	}
 }
 
+// freshnessThreshold returns the staleness threshold for sourceType.
+func (c EvidenceConfig) freshnessThreshold(sourceType SourceType) time.Duration {
+	if d, ok := c.FreshnessWarningBySource[sourceType]; ok && d > 0 {
+		return d
+	}
+	return c.FreshnessWarning
+}
+
 // calculateConfidence maps top score to low/medium/high.
 func (c EvidenceConfig) calculateConfidence(topScore float64) ConfidenceLevel {
 	if topScore >= c.HighConfidenceMin {
@@ -70,6 +90,9 @@ func NewEvidencePackService(db *sql.DB, searchSvc *SearchService, cfg EvidenceCo
 	if cfg.DedupThreshold <= 0 {
 		cfg.DedupThreshold = 0.95
 	}
+	if cfg.TextDedupThreshold <= 0 {
+		cfg.TextDedupThreshold = 0.8
+	}
 	if cfg.HighConfidenceMin <= 0 {
 		cfg.HighConfidenceMin = 0.8
 	}
@@ -89,7 +112,22 @@ func NewEvidencePackService(db *sql.DB, searchSvc *SearchService, cfg EvidenceCo
 }
 
 // BuildEvidencePack executes hybrid search and returns curated evidence.
+// With input.RunID set the pack is also stored for that run; a storage
+// failure is returned as an error so a run never lacks its audit record.
 func (s *EvidencePackService) BuildEvidencePack(ctx context.Context, input BuildEvidencePackInput) (*EvidencePack, error) {
+	pack, err := s.buildEvidencePack(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if input.RunID != "" {
+		if err = s.storeRunEvidencePack(ctx, input.WorkspaceID, input.RunID, pack); err != nil {
+			return nil, err
+		}
+	}
+	return pack, nil
+}
+
+func (s *EvidencePackService) buildEvidencePack(ctx context.Context, input BuildEvidencePackInput) (*EvidencePack, error) {
 	topK := s.resolveTopK(input.Limit)
 
 	searchRes, err := s.search.HybridSearch(ctx, SearchInput{
@@ -156,41 +194,55 @@ func (s *EvidencePackService) selectCandidates(
 	topK int,
 ) ([]SearchResult, int, int) {
 	selected := make([]SearchResult, 0, topK)
-	selectedVectors := make([][]float32, 0, topK)
+	seen := selectedEvidence{vectors: make([][]float32, 0, topK), shingles: make([]map[string]struct{}, 0, topK)}
 	dedupCount := 0
 	staleCount := 0
+	chunks := s.loadCandidateChunks(ctx, wsID, candidates)
 
 	for _, candidate := range candidates {
 		if len(selected) >= topK {
 			break
 		}
-		accepted, dedup, stale := s.evaluateCandidate(ctx, wsID, candidate, representativeVectors, selectedVectors)
+		shingles := textShingles(candidate.Snippet)
+		accepted, dedup, stale := s.evaluateCandidate(ctx, wsID, candidate, shingles, representativeVectors, seen)
 		dedupCount += dedup
 		staleCount += stale
 		if accepted {
-			vec := representativeVectors[candidate.KnowledgeItemID]
 			selected = append(selected, candidate)
-			if vec != nil {
-				selectedVectors = append(selectedVectors, vec)
+			if vec := representativeVectors[candidate.KnowledgeItemID]; vec != nil {
+				seen.vectors = append(seen.vectors, vec)
 			}
+			seen.shingles = append(seen.shingles, chunkShingles(chunks[candidate.KnowledgeItemID], shingles))
 		}
 	}
 
 	return selected, dedupCount, staleCount
 }
 
+// selectedEvidence holds what candidates are compared against for dedup:
+// the representative vectors and, per selected item, the shingles of its
+// chunks and snippet.
+type selectedEvidence struct {
+	vectors  [][]float32
+	shingles []map[string]struct{}
+}
+
 func (s *EvidencePackService) evaluateCandidate(
 	ctx context.Context,
 	wsID string,
 	candidate SearchResult,
+	shingles map[string]struct{},
 	representativeVectors map[string][]float32,
-	selectedVectors [][]float32,
+	seen selectedEvidence,
 ) (accepted bool, dedupCount, staleCount int) {
 	if s.isStale(ctx, candidate.KnowledgeItemID, wsID) {
 		staleCount++
 	}
 	vec, hasVec := representativeVectors[candidate.KnowledgeItemID]
-	if hasVec && s.isNearDuplicate(vec, selectedVectors) {
+	if hasVec && s.isNearDuplicate(vec, seen.vectors) {
+		return false, 1, staleCount
+	}
+	if s.isDuplicateText(shingles, seen.shingles) {
 		return false, 1, staleCount
 	}
 	return true, 0, staleCount
@@ -220,7 +272,7 @@ func (s *EvidencePackService) packConfidence(selected []SearchResult) Confidence
 	if len(selected) == 0 {
 		return ConfidenceLow
 	}
-	return s.cfg.calculateConfidence(s.normalizeConfidenceScore(selected[0].Score))
+	return s.cfg.calculateConfidence(selected[0].Score)
 }
 
 func (s *EvidencePackService) filteredCount(total, selected int) int {
@@ -303,7 +355,7 @@ func (s *EvidencePackService) persistEvidence(ctx context.Context, wsID string,
 }
 
 func (s *EvidencePackService) isStale(ctx context.Context, itemID, wsID string) bool {
-	if s.cfg.FreshnessWarning <= 0 {
+	if s.cfg.FreshnessWarning <= 0 && len(s.cfg.FreshnessWarningBySource) == 0 {
 		return false
 	}
 	item, err := s.q.GetKnowledgeItemByID(ctx, sqlcgen.GetKnowledgeItemByIDParams{
@@ -313,7 +365,58 @@ func (s *EvidencePackService) isStale(ctx context.Context, itemID, wsID string)
 	if err != nil {
 		return false
 	}
-	return time.Since(item.UpdatedAt) > s.cfg.FreshnessWarning
+	threshold := s.cfg.freshnessThreshold(SourceType(item.SourceType))
+	return threshold > 0 && time.Since(item.UpdatedAt) > threshold
+}
+
+// loadCandidateChunks returns the live chunk texts of every candidate, keyed
+// by knowledge item, in one query. On error the text pass falls back to the
+// snippets alone.
+func (s *EvidencePackService) loadCandidateChunks(ctx context.Context, wsID string, candidates []SearchResult) map[string][]string {
+	out := make(map[string][]string, len(candidates))
+	if len(candidates) == 0 {
+		return out
+	}
+	placeholders := make([]string, len(candidates))
+	args := make([]any, 0, len(candidates)+1)
+	args = append(args, wsID)
+	for i, candidate := range candidates {
+		placeholders[i] = "?"
+		args = append(args, candidate.KnowledgeItemID)
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT knowledge_item_id, chunk_text
+		FROM embedding_document
+		WHERE workspace_id = ? AND deleted_at IS NULL
+		  AND knowledge_item_id IN (`+strings.Join(placeholders, ", ")+`)
+		ORDER BY knowledge_item_id, chunk_index`, args...)
+	if err != nil {
+		return out
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var itemID, text string
+		if rows.Scan(&itemID, &text) != nil {
+			return out
+		}
+		out[itemID] = append(out[itemID], text)
+	}
+	return out
+}
+
+// chunkShingles returns the shingles of every chunk plus snippetShingles,
+// without forming shingles across chunk boundaries.
+func chunkShingles(chunks []string, snippetShingles map[string]struct{}) map[string]struct{} {
+	out := make(map[string]struct{}, len(snippetShingles))
+	for shingle := range snippetShingles {
+		out[shingle] = struct{}{}
+	}
+	for _, chunk := range chunks {
+		for shingle := range textShingles(chunk) {
+			out[shingle] = struct{}{}
+		}
+	}
+	return out
 }
 
 func (s *EvidencePackService) getRepresentativeVectors(ctx context.Context, wsID string) (map[string][]float32, error) {
@@ -336,21 +439,60 @@ func (s *EvidencePackService) getRepresentativeVectors(ctx context.Context, wsID
 	return out, nil
 }
 
-// nearDuplicateVectors returns true if cosine similarity is above threshold.
-func nearDuplicateVectors(a, b []float32, threshold float64) bool {
-	return float64(cosineSimilarity(a, b)) >= threshold
+// isDuplicateText reports whether the snippet shingles are contained in any
+// selected item's at or above TextDedupThreshold. Containment rather than
+// similarity of whole documents lets a paragraph shared by otherwise
+// different documents count as a copy.
+func (s *EvidencePackService) isDuplicateText(shingles map[string]struct{}, selected []map[string]struct{}) bool {
+	if len(shingles) == 0 {
+		return false
+	}
+	for _, existing := range selected {
+		if shingleContainment(shingles, existing) >= s.cfg.TextDedupThreshold {
+			return true
+		}
+	}
+	return false
 }
 
-// normalizeConfidenceScore maps RRF score to [0,1] for confidence thresholds.
-// RRF absolute values are tiny (e.g. ~0.01-0.03 with k=60), so we normalize
-// against the theoretical max with two retrieval methods (BM25 + vector).
-func (s *EvidencePackService) normalizeConfidenceScore(raw float64) float64 {
-	if raw <= 0 {
-		return 0
+const evidenceShingleSize = 3
+
+// textShingles returns the overlapping word 3-grams of text after folding
+// case and dropping punctuation, so reflowed or re-punctuated copies of the
+// same paragraph produce the same set. Texts shorter than a shingle yield
+// one shingle of all their words.
+func textShingles(text string) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return nil
+	}
+	if len(words) < evidenceShingleSize {
+		return map[string]struct{}{strings.Join(words, " "): {}}
+	}
+	out := make(map[string]struct{}, len(words)-evidenceShingleSize+1)
+	for i := 0; i+evidenceShingleSize <= len(words); i++ {
+		out[strings.Join(words[i:i+evidenceShingleSize], " ")] = struct{}{}
 	}
-	maxScore := 2.0 / float64(rrfK+1)
-	if maxScore <= 0 {
+	return out
+}
+
+// shingleContainment returns the share of a's shingles also in b.
+func shingleContainment(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
 		return 0
 	}
-	return math.Min(1.0, raw/maxScore)
+	shared := 0
+	for shingle := range a {
+		if _, ok := b[shingle]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a))
+}
+
+// nearDuplicateVectors returns true if cosine similarity is above threshold.
+func nearDuplicateVectors(a, b []float32, threshold float64) bool {
+	return float64(cosineSimilarity(a, b)) >= threshold
 }
@@ -0,0 +1,60 @@
+package knowledge
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+// ErrEvidencePackNotFound is returned by GetEvidencePackForRun when no pack
+// was stored for the run.
+var ErrEvidencePackNotFound = errors.New("evidence pack not found")
+
+// storeRunEvidencePack saves the assembled pack against the agent run that
+// requested it (BuildEvidencePackInput.RunID).
+func (s *EvidencePackService) storeRunEvidencePack(ctx context.Context, workspaceID, runID string, pack *EvidencePack) error {
+	raw, err := json.Marshal(pack)
+	if err != nil {
+		return fmt.Errorf("evidence: encode run evidence pack: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO run_evidence_pack (id, workspace_id, run_id, query, confidence, pack, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, uuid.NewV7().String(), workspaceID, runID, pack.Query, string(pack.Confidence), string(raw),
+		time.Now().UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("evidence: store run evidence pack: %w", err)
+	}
+	return nil
+}
+
+// GetEvidencePackForRun returns the evidence pack stored for an agent run,
+// exactly as the agent received it. When the run built several packs the
+// most recent one is returned.
+func (s *EvidencePackService) GetEvidencePackForRun(ctx context.Context, workspaceID, runID string) (*EvidencePack, error) {
+	var raw string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT pack
+		FROM run_evidence_pack
+		WHERE workspace_id = ? AND run_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`, workspaceID, runID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrEvidencePackNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("evidence: get run evidence pack: %w", err)
+	}
+
+	var pack EvidencePack
+	if err = json.Unmarshal([]byte(raw), &pack); err != nil {
+		return nil, fmt.Errorf("evidence: decode run evidence pack: %w", err)
+	}
+	return &pack, nil
+}
@@ -7,7 +7,10 @@ package knowledge
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"math"
+	"slices"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -176,25 +179,17 @@ func TestEvidencePackService_HelperMethods(t *testing.T) {
 	}
 }
 
-func TestEvidencePackService_PackConfidenceAndNormalizeScore(t *testing.T) {
+func TestEvidencePackService_PackConfidence(t *testing.T) {
 	svc := &EvidencePackService{cfg: DefaultEvidenceConfig()}
 
 	if got := svc.packConfidence(nil); got != ConfidenceLow {
 		t.Fatalf("packConfidence(nil)=%v, want %v", got, ConfidenceLow)
 	}
 
-	highRaw := 2.0 / float64(rrfK+1)
-	high := svc.packConfidence([]SearchResult{{Score: highRaw}})
+	high := svc.packConfidence([]SearchResult{{Score: normalizeRRFScore(2.0 / float64(rrfK+1))}})
 	if high != ConfidenceHigh {
 		t.Fatalf("expected high confidence for normalized max score, got %v", high)
 	}
-
-	if got := svc.normalizeConfidenceScore(0); got != 0 {
-		t.Fatalf("normalizeConfidenceScore(0)=%f, want 0", got)
-	}
-	if got := svc.normalizeConfidenceScore(highRaw * 10); got != 1.0 {
-		t.Fatalf("normalizeConfidenceScore(>max)=%f, want 1.0", got)
-	}
 }
 
 func TestEvidencePackService_EmptyEvidencePackAndWarnings(t *testing.T) {
@@ -237,14 +232,6 @@ func TestEvidencePackService_EmptyEvidencePackAndWarnings(t *testing.T) {
 	}
 }
 
-func TestEvidencePackService_NormalizeConfidenceScore_Negative(t *testing.T) {
-	svc := &EvidencePackService{cfg: DefaultEvidenceConfig()}
-
-	if got := svc.normalizeConfidenceScore(-0.5); got != 0 {
-		t.Fatalf("normalizeConfidenceScore(negative)=%f, want 0", got)
-	}
-}
-
 // ============================================================================
 // Integration tests (real DB + stub LLM)
 // ============================================================================
@@ -544,6 +531,70 @@ func TestEvidencePackService_Deduplication(t *testing.T) {
 	_ = hasDedupWarning
 }
 
+func TestShingleContainment_SnippetInsideLargerDocument(t *testing.T) {
+	snippet := textShingles("This document is confidential. Do not distribute without written consent.")
+	doc := textShingles("Quarterly pricing review for the northern region.\nthis document is CONFIDENTIAL -- do not distribute without\nwritten consent")
+	if got := shingleContainment(snippet, doc); got != 1 {
+		t.Fatalf("containment(snippet in doc) = %v; want 1", got)
+	}
+	unrelated := textShingles("Pricing tiers start at ten euros per seat and scale with volume.")
+	if got := shingleContainment(snippet, unrelated); got != 0 {
+		t.Fatalf("containment(unrelated) = %v; want 0", got)
+	}
+}
+
+func TestEvidencePackService_DeduplicatesSharedParagraphAcrossDocuments(t *testing.T) {
+	db := evidenceSetupTestDB(t)
+	defer db.Close()
+
+	// Every embed call gets its own axis, so no two documents are vector
+	// near-duplicates and only the text pass can catch the shared footer.
+	var calls atomic.Int32
+	stub := &stubEmbedder{
+		embedFunc: func(_ context.Context, req llm.EmbedRequest) (*llm.EmbedResponse, error) {
+			axis := int(calls.Add(1)-1) % 4
+			vecs := make([][]float32, len(req.Texts))
+			for i := range vecs {
+				vecs[i] = make([]float32, 4)
+				vecs[i][axis] = 1
+			}
+			return &llm.EmbedResponse{Embeddings: vecs}, nil
+		},
+	}
+
+	wsID := evidenceCreateWorkspace(t, db)
+	bus := eventbus.New()
+	ingest := NewIngestService(db, bus)
+	embedder := NewEmbedderService(db, stub)
+	searchSvc := NewSearchService(db, stub)
+	evidenceSvc := NewEvidencePackService(db, searchSvc, DefaultEvidenceConfig())
+
+	// The footer is longer than the search snippet, so the snippet of both
+	// documents falls inside it while their bodies differ.
+	const footer = "Terms of use: this material is provided as is, without warranty of any kind. " +
+		"Liability disclaimer: the vendor accepts no liability for indirect, incidental or consequential damages, " +
+		"lost profits or lost data arising from the use of this material, even when advised of the possibility."
+	evidenceIngestAndEmbedDoc(t, ingest, embedder, wsID, "Master services agreement",
+		"The supplier hosts the platform in two regions and reports uptime monthly.\n\n"+footer)
+	evidenceIngestAndEmbedDoc(t, ingest, embedder, wsID, "Order form",
+		"Seats are billed annually in advance and renew unless cancelled in writing.\n\n"+footer)
+
+	pack, err := evidenceSvc.BuildEvidencePack(context.Background(), BuildEvidencePackInput{
+		Query:       "liability disclaimer",
+		WorkspaceID: wsID,
+		Limit:       10,
+	})
+	if err != nil {
+		t.Fatalf("BuildEvidencePack failed: %v", err)
+	}
+	if pack.SourceCount != 1 || pack.DedupCount != 1 || pack.FilteredCount != 1 {
+		t.Fatalf("sources=%d dedup=%d filtered=%d; want 1, 1, 1", pack.SourceCount, pack.DedupCount, pack.FilteredCount)
+	}
+	if !slices.Contains(pack.Warnings, "1 items deduplicated") {
+		t.Fatalf("warnings = %v; want dedup warning", pack.Warnings)
+	}
+}
+
 func TestEvidencePackService_EvidencePersisted(t *testing.T) {
 	db := evidenceSetupTestDB(t)
 	defer db.Close()
@@ -632,10 +683,123 @@ func TestEvidencePackService_FreshnessWarning(t *testing.T) {
 	}
 }
 
+func TestEvidencePackService_FreshnessWarningBySource(t *testing.T) {
+	db := evidenceSetupTestDB(t)
+	defer db.Close()
+
+	stub := newStubEmbedder(3)
+	wsID := evidenceCreateWorkspace(t, db)
+
+	bus := eventbus.New()
+	ingest := NewIngestService(db, bus)
+	embedder := NewEmbedderService(db, stub)
+	searchSvc := NewSearchService(db, stub)
+
+	// Tickets go stale at once; everything else keeps the 30 day default.
+	cfg := DefaultEvidenceConfig()
+	cfg.FreshnessWarningBySource = map[SourceType]time.Duration{SourceTypeTicket: time.Nanosecond}
+	evidenceSvc := NewEvidencePackService(db, searchSvc, cfg)
+
+	for _, src := range []struct {
+		sourceType SourceType
+		title      string
+	}{
+		{SourceTypeTicket, "Refund ticket"},
+		{SourceTypeDocument, "Refund policy"},
+	} {
+		item, err := ingest.Ingest(context.Background(), CreateKnowledgeItemInput{
+			WorkspaceID: wsID,
+			SourceType:  src.sourceType,
+			Title:       src.title,
+			RawContent:  src.title + ": refund requests are handled within five days",
+		})
+		if err != nil {
+			t.Fatalf("ingest %s: %v", src.sourceType, err)
+		}
+		if err = embedder.EmbedChunks(context.Background(), item.ID, wsID); err != nil {
+			t.Fatalf("EmbedChunks %s: %v", src.sourceType, err)
+		}
+	}
+
+	pack, err := evidenceSvc.BuildEvidencePack(context.Background(), BuildEvidencePackInput{
+		Query:       "refund",
+		WorkspaceID: wsID,
+		Limit:       10,
+	})
+	if err != nil {
+		t.Fatalf("BuildEvidencePack failed: %v", err)
+	}
+	if !slices.Contains(pack.Warnings, "1 items stale") {
+		t.Fatalf("warnings = %v; want only the ticket counted as stale", pack.Warnings)
+	}
+}
+
 // ============================================================================
 // Test Helpers (duplicated here since they may not be exported from other files)
 // ============================================================================
 
+func TestEvidencePackService_StoresPackForRun(t *testing.T) {
+	db := evidenceSetupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	stub := newStubEmbedder(3)
+	wsID := evidenceCreateWorkspace(t, db)
+	runID := evidenceCreateAgentRun(t, db, wsID)
+
+	ingest := NewIngestService(db, eventbus.New())
+	embedder := NewEmbedderService(db, stub)
+	evidenceSvc := NewEvidencePackService(db, NewSearchService(db, stub), DefaultEvidenceConfig())
+	evidenceIngestAndEmbedDoc(t, ingest, embedder, wsID, "Refund Policy", "refunds are issued within 14 days of purchase")
+
+	if _, err := evidenceSvc.GetEvidencePackForRun(ctx, wsID, runID); !errors.Is(err, ErrEvidencePackNotFound) {
+		t.Fatalf("expected ErrEvidencePackNotFound before build, got %v", err)
+	}
+
+	built, err := evidenceSvc.BuildEvidencePack(ctx, BuildEvidencePackInput{
+		Query:       "refunds",
+		WorkspaceID: wsID,
+		RunID:       runID,
+	})
+	if err != nil {
+		t.Fatalf("BuildEvidencePack failed: %v", err)
+	}
+
+	stored, err := evidenceSvc.GetEvidencePackForRun(ctx, wsID, runID)
+	if err != nil {
+		t.Fatalf("GetEvidencePackForRun failed: %v", err)
+	}
+	if stored.Query != built.Query || stored.Confidence != built.Confidence || stored.SourceCount != built.SourceCount {
+		t.Fatalf("stored pack %+v does not match built pack %+v", stored, built)
+	}
+	if len(stored.Sources) == 0 || stored.Sources[0].ID != built.Sources[0].ID || stored.Sources[0].Score != built.Sources[0].Score {
+		t.Fatalf("stored sources %+v; want %+v", stored.Sources, built.Sources)
+	}
+
+	if _, err = evidenceSvc.GetEvidencePackForRun(ctx, evidenceCreateWorkspace(t, db), runID); !errors.Is(err, ErrEvidencePackNotFound) {
+		t.Fatalf("expected ErrEvidencePackNotFound from another workspace, got %v", err)
+	}
+}
+
+func evidenceCreateAgentRun(t *testing.T, db *sql.DB, wsID string) string {
+	t.Helper()
+	defID := evidenceGenerateTestUUID()
+	runID := evidenceGenerateTestUUID()
+	if _, err := db.Exec(
+		`INSERT INTO agent_definition (id, workspace_id, name, agent_type, status) VALUES (?, ?, 'Support', 'support', 'active')`,
+		defID, wsID,
+	); err != nil {
+		t.Fatalf("failed to create agent definition: %v", err)
+	}
+	if _, err := db.Exec(
+		`INSERT INTO agent_run (id, workspace_id, agent_definition_id, trigger_type) VALUES (?, ?, ?, 'manual')`,
+		runID, wsID, defID,
+	); err != nil {
+		t.Fatalf("failed to create agent run: %v", err)
+	}
+	return runID
+}
+
 func evidenceSetupTestDB(t *testing.T) *sql.DB {
 	t.Helper()
 	db, err := sqlite.NewDB(":memory:")
@@ -0,0 +1,140 @@
+// Package knowledge — search relevance feedback.
+package knowledge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+// FeedbackSignal is a user's reaction to a search result.
+type FeedbackSignal string
+
+const (
+	FeedbackClick      FeedbackSignal = "click"
+	FeedbackHelpful    FeedbackSignal = "helpful"
+	FeedbackNotHelpful FeedbackSignal = "not_helpful"
+)
+
+const (
+	// DefaultFeedbackBoost is the share of a first-rank RRF contribution that
+	// positive feedback can add to an item at most.
+	DefaultFeedbackBoost = 0.5
+
+	// Weights of each signal in an item's net feedback for a query. A click
+	// is a weaker endorsement than an explicit vote.
+	feedbackClickWeight   = 0.25
+	feedbackHelpfulWeight = 1.0
+)
+
+var (
+	// ErrInvalidFeedbackSignal is returned by RecordFeedback for a signal
+	// other than click, helpful or not_helpful.
+	ErrInvalidFeedbackSignal = errors.New("invalid feedback signal")
+	// ErrFeedbackQueryRequired is returned by RecordFeedback for a blank query.
+	ErrFeedbackQueryRequired = errors.New("feedback query is required")
+	// ErrFeedbackItemNotFound is returned by RecordFeedback when the knowledge
+	// item does not exist in the workspace.
+	ErrFeedbackItemNotFound = errors.New("knowledge item not found")
+)
+
+// SetFeedbackBoost bounds how much positive feedback can lift an item, as a
+// share of the RRF score of ranking first in one method. It is clamped to
+// [0, 1], so feedback weighs at most as much as one method's top rank: it
+// reorders close results but cannot beat an item both methods agree on.
+// 0 disables the boost.
+func (s *SearchService) SetFeedbackBoost(share float64) {
+	s.feedbackBoost = min(max(share, 0), 1)
+}
+
+// RecordFeedback stores a user signal on a result returned for query.
+// Later searches for the same query (case and spacing insensitive) rank
+// items with net positive feedback slightly higher.
+func (s *SearchService) RecordFeedback(ctx context.Context, workspaceID, query, knowledgeItemID string, signal FeedbackSignal) error {
+	switch signal {
+	case FeedbackClick, FeedbackHelpful, FeedbackNotHelpful:
+	default:
+		return ErrInvalidFeedbackSignal
+	}
+	normalized := normalizeFeedbackQuery(query)
+	if normalized == "" {
+		return ErrFeedbackQueryRequired
+	}
+
+	var exists int
+	err := s.db.QueryRowContext(ctx,
+		`SELECT 1 FROM knowledge_item WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL`,
+		knowledgeItemID, workspaceID,
+	).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrFeedbackItemNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("record feedback: load item: %w", err)
+	}
+
+	var actorID any
+	if v, ok := ctx.Value(ctxkeys.UserID).(string); ok && v != "" {
+		actorID = v
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO search_feedback (id, workspace_id, query, query_normalized, knowledge_item_id, signal, actor_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		uuid.NewV7().String(), workspaceID, strings.TrimSpace(query), normalized, knowledgeItemID, string(signal), actorID,
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return fmt.Errorf("record feedback: %w", err)
+	}
+	return nil
+}
+
+// feedbackBoosts returns the RRF score bonus of every item with net positive
+// feedback for query. The bonus saturates with the amount of feedback and
+// never exceeds feedbackBoost first-rank contributions.
+func (s *SearchService) feedbackBoosts(ctx context.Context, workspaceID, query string) (map[string]float64, error) {
+	normalized := normalizeFeedbackQuery(query)
+	if s.feedbackBoost == 0 || normalized == "" {
+		return nil, nil
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT knowledge_item_id,
+		       SUM(CASE signal WHEN 'helpful' THEN ? WHEN 'click' THEN ? ELSE -? END) AS net
+		FROM search_feedback
+		WHERE workspace_id = ? AND query_normalized = ?
+		GROUP BY knowledge_item_id
+		HAVING net > 0`,
+		feedbackHelpfulWeight, feedbackClickWeight, feedbackHelpfulWeight, workspaceID, normalized,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("load feedback: %w", err)
+	}
+	defer rows.Close()
+
+	maxBonus := s.feedbackBoost / float64(rrfK+1)
+	boosts := make(map[string]float64)
+	for rows.Next() {
+		var (
+			id  string
+			net float64
+		)
+		if err = rows.Scan(&id, &net); err != nil {
+			return nil, fmt.Errorf("scan feedback: %w", err)
+		}
+		boosts[id] = maxBonus * net / (net + 1)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate feedback: %w", err)
+	}
+	return boosts, nil
+}
+
+func normalizeFeedbackQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
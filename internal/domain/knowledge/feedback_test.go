@@ -0,0 +1,106 @@
+package knowledge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestSearchService_RecordFeedback_BoostsHelpfulItem(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	stub := newStubEmbedder(3)
+	wsID := createWorkspace(t, db)
+	ingest := NewIngestService(db, eventbus.New())
+	embedder := NewEmbedderService(db, stub)
+	svc := NewSearchService(db, stub)
+	ctx := context.Background()
+
+	// Identical documents tie on BM25 and vector scores, so only feedback
+	// separates them from the id order.
+	for i := 0; i < 4; i++ {
+		ingestAndEmbedDoc(t, ingest, embedder, wsID, "VPN setup guide", "how to configure the vpn client on laptops")
+	}
+	ranking := func(query string) []string {
+		t.Helper()
+		results, err := svc.HybridSearch(ctx, SearchInput{Query: query, WorkspaceID: wsID})
+		if err != nil {
+			t.Fatalf("HybridSearch(%q) error = %v", query, err)
+		}
+		ids := make([]string, len(results.Items))
+		for i, item := range results.Items {
+			ids[i] = item.KnowledgeItemID
+		}
+		return ids
+	}
+
+	before := ranking("vpn")
+	if len(before) != 4 {
+		t.Fatalf("results = %d; want 4", len(before))
+	}
+	favourite, disliked := before[3], before[0]
+	for i := 0; i < 3; i++ {
+		if err := svc.RecordFeedback(ctx, wsID, "VPN ", favourite, FeedbackHelpful); err != nil {
+			t.Fatalf("RecordFeedback(helpful) error = %v", err)
+		}
+	}
+	if err := svc.RecordFeedback(ctx, wsID, "vpn", disliked, FeedbackClick); err != nil {
+		t.Fatalf("RecordFeedback(click) error = %v", err)
+	}
+	if err := svc.RecordFeedback(ctx, wsID, "vpn", disliked, FeedbackNotHelpful); err != nil {
+		t.Fatalf("RecordFeedback(not_helpful) error = %v", err)
+	}
+
+	after := ranking("vpn")
+	if after[0] != favourite {
+		t.Fatalf("top result = %s; want %s after helpful feedback (before %v)", after[0], favourite, before)
+	}
+	if after[1] != disliked {
+		t.Fatalf("second result = %s; want %s unboosted (net feedback <= 0)", after[1], disliked)
+	}
+
+	svc.SetFeedbackBoost(0)
+	if disabled := ranking("vpn"); disabled[0] != before[0] {
+		t.Fatalf("top result with boost disabled = %s; want %s", disabled[0], before[0])
+	}
+}
+
+func TestSearchService_FeedbackBoost_IsBounded(t *testing.T) {
+	t.Parallel()
+
+	bm25 := []bm25Row{{id: "first"}, {id: "second"}}
+	vec := []vectorRow{{knowledgeItemID: "first"}}
+	// The largest bonus the default boost allows cannot lift an item only
+	// BM25 found above one both methods rank first.
+	results := rrfMerge(bm25, vec, map[string]float64{"second": DefaultFeedbackBoost / float64(rrfK+1)}, 10)
+	if results[0].KnowledgeItemID != "first" {
+		t.Fatalf("top result = %s; want first", results[0].KnowledgeItemID)
+	}
+	// Items neither method retrieved are not pulled in by feedback.
+	results = rrfMerge(bm25, nil, map[string]float64{"absent": 1}, 10)
+	if len(results) != 2 {
+		t.Fatalf("results = %d; want 2", len(results))
+	}
+}
+
+func TestSearchService_RecordFeedback_Validates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	wsID := createWorkspace(t, db)
+	svc := NewSearchService(db, newStubEmbedder(3))
+	ctx := context.Background()
+
+	if err := svc.RecordFeedback(ctx, wsID, "vpn", "item-1", "love"); !errors.Is(err, ErrInvalidFeedbackSignal) {
+		t.Fatalf("unknown signal error = %v; want ErrInvalidFeedbackSignal", err)
+	}
+	if err := svc.RecordFeedback(ctx, wsID, "  ", "item-1", FeedbackClick); !errors.Is(err, ErrFeedbackQueryRequired) {
+		t.Fatalf("blank query error = %v; want ErrFeedbackQueryRequired", err)
+	}
+	if err := svc.RecordFeedback(ctx, wsID, "vpn", "item-1", FeedbackClick); !errors.Is(err, ErrFeedbackItemNotFound) {
+		t.Fatalf("unknown item error = %v; want ErrFeedbackItemNotFound", err)
+	}
+}
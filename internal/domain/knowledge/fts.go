@@ -0,0 +1,80 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+)
+
+// FTSStatus compares knowledge_item rows with their knowledge_item_fts entries
+// for one workspace. The FTS triggers index every row, including soft-deleted
+// ones, so a healthy index has one entry per base row.
+type FTSStatus struct {
+	WorkspaceID string
+	BaseRows    int64 // knowledge_item rows
+	IndexedRows int64 // knowledge_item_fts rows
+	Missing     int64 // base rows without an FTS entry
+	Orphaned    int64 // FTS entries without a base row
+}
+
+// InSync reports whether the FTS index matches the base table exactly.
+func (s FTSStatus) InSync() bool {
+	return s.BaseRows == s.IndexedRows && s.Missing == 0 && s.Orphaned == 0
+}
+
+// VerifyFTS reports drift between knowledge_item and knowledge_item_fts for a
+// workspace without modifying either.
+func (s *IngestService) VerifyFTS(ctx context.Context, workspaceID string) (FTSStatus, error) {
+	status := FTSStatus{WorkspaceID: workspaceID}
+	err := s.db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM knowledge_item WHERE workspace_id = ?1),
+			(SELECT COUNT(*) FROM knowledge_item_fts WHERE workspace_id = ?1),
+			(SELECT COUNT(*) FROM knowledge_item ki
+			 WHERE ki.workspace_id = ?1
+			   AND NOT EXISTS (SELECT 1 FROM knowledge_item_fts f WHERE f.id = ki.id AND f.workspace_id = ?1)),
+			(SELECT COUNT(*) FROM knowledge_item_fts f
+			 WHERE f.workspace_id = ?1
+			   AND NOT EXISTS (SELECT 1 FROM knowledge_item ki WHERE ki.id = f.id AND ki.workspace_id = ?1))`,
+		workspaceID,
+	).Scan(&status.BaseRows, &status.IndexedRows, &status.Missing, &status.Orphaned)
+	if err != nil {
+		return FTSStatus{}, fmt.Errorf("verify knowledge fts: %w", err)
+	}
+	return status, nil
+}
+
+// RebuildFTS drops every knowledge_item_fts entry of the workspace and
+// repopulates the index from knowledge_item in one transaction, using the same
+// columns as the knowledge_item_ai trigger. Returns the number of rows indexed.
+func (s *IngestService) RebuildFTS(ctx context.Context, workspaceID string) (int64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin knowledge fts rebuild: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err = tx.ExecContext(ctx,
+		`DELETE FROM knowledge_item_fts WHERE workspace_id = ?`, workspaceID,
+	); err != nil {
+		return 0, fmt.Errorf("clear knowledge fts: %w", err)
+	}
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO knowledge_item_fts (id, workspace_id, title, normalized_content)
+		 SELECT id, workspace_id, title, COALESCE(normalized_content, raw_content)
+		 FROM knowledge_item
+		 WHERE workspace_id = ?`,
+		workspaceID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("repopulate knowledge fts: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("repopulate knowledge fts: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit knowledge fts rebuild: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,86 @@
+package knowledge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestIngestService_VerifyAndRebuildFTS(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	svc := NewIngestService(db, eventbus.New())
+	search := NewSearchService(db, newStubEmbedder(3))
+	wsID := createWorkspace(t, db)
+	otherWS := createWorkspace(t, db)
+
+	ingestDoc := func(ws, title, content string) *KnowledgeItem {
+		t.Helper()
+		item, err := svc.Ingest(ctx, CreateKnowledgeItemInput{
+			WorkspaceID: ws,
+			SourceType:  SourceTypeDocument,
+			Title:       title,
+			RawContent:  content,
+		})
+		if err != nil {
+			t.Fatalf("Ingest %q failed: %v", title, err)
+		}
+		return item
+	}
+	drifted := ingestDoc(wsID, "Onboarding", "onboarding checklist for new customers")
+	ingestDoc(wsID, "Renewals", "renewal reminders go out sixty days early")
+	ingestDoc(otherWS, "Other", "onboarding in another workspace")
+
+	status, err := svc.VerifyFTS(ctx, wsID)
+	if err != nil {
+		t.Fatalf("VerifyFTS failed: %v", err)
+	}
+	if !status.InSync() || status.BaseRows != 2 {
+		t.Fatalf("fresh index status = %+v, want 2 rows in sync", status)
+	}
+
+	// Simulate drift from a manual data fix: one entry lost, one stale entry left behind.
+	if _, err = db.Exec(`DELETE FROM knowledge_item_fts WHERE id = ?`, drifted.ID); err != nil {
+		t.Fatalf("delete fts row: %v", err)
+	}
+	if _, err = db.Exec(
+		`INSERT INTO knowledge_item_fts (id, workspace_id, title, normalized_content) VALUES ('ghost', ?, 'Ghost', 'stale')`,
+		wsID,
+	); err != nil {
+		t.Fatalf("insert fts row: %v", err)
+	}
+
+	status, err = svc.VerifyFTS(ctx, wsID)
+	if err != nil {
+		t.Fatalf("VerifyFTS failed: %v", err)
+	}
+	want := FTSStatus{WorkspaceID: wsID, BaseRows: 2, IndexedRows: 2, Missing: 1, Orphaned: 1}
+	if status != want || status.InSync() {
+		t.Fatalf("drifted status = %+v, want %+v (out of sync)", status, want)
+	}
+
+	n, err := svc.RebuildFTS(ctx, wsID)
+	if err != nil {
+		t.Fatalf("RebuildFTS failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("RebuildFTS indexed %d rows, want 2", n)
+	}
+	if status, err = svc.VerifyFTS(ctx, wsID); err != nil || !status.InSync() {
+		t.Fatalf("status after rebuild = %+v, err = %v; want in sync", status, err)
+	}
+	if other, otherErr := svc.VerifyFTS(ctx, otherWS); otherErr != nil || !other.InSync() || other.IndexedRows != 1 {
+		t.Fatalf("other workspace status = %+v, err = %v; want untouched", other, otherErr)
+	}
+
+	rows, err := search.bm25Search(ctx, "onboarding", searchScope{workspaceID: wsID}, 10)
+	if err != nil {
+		t.Fatalf("bm25Search failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0].id != drifted.ID {
+		t.Fatalf("bm25 results after rebuild = %+v, want only %s", rows, drifted.ID)
+	}
+}
@@ -0,0 +1,190 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// fuzzyMaxTerms caps the query terms corrected by fuzzy search; each one
+	// reads a range of the index vocabulary.
+	fuzzyMaxTerms = 8
+	// fuzzyPrefixMinLen is the shortest term also matched as a prefix of
+	// itself minus its last letter, which catches a trailing typo or plural.
+	fuzzyPrefixMinLen = 4
+	// fuzzyMaxCandidates caps the corrections of one term checked against
+	// the workspace.
+	fuzzyMaxCandidates = 5
+)
+
+// fuzzyBM25Search is the BM25 fallback for SearchInput.Fuzzy: the query
+// terms are rewritten by fuzzyMatchQuery and searched again.
+func (s *SearchService) fuzzyBM25Search(ctx context.Context, query string, scope searchScope, limit int) ([]bm25Row, error) {
+	match, err := s.fuzzyMatchQuery(ctx, scope.workspaceID, query)
+	if err != nil || match == "" {
+		return nil, err
+	}
+	return s.bm25Search(ctx, match, scope, limit)
+}
+
+// fuzzyMatchQuery rewrites query into an FTS5 expression OR-ing, for every
+// term, the closest term of the workspace's vocabulary and the term's
+// prefix. It returns "" when no term is usable.
+func (s *SearchService) fuzzyMatchQuery(ctx context.Context, workspaceID, query string) (string, error) {
+	seen := make(map[string]bool)
+	var parts []string
+	add := func(part string) {
+		if !seen[part] {
+			seen[part] = true
+			parts = append(parts, part)
+		}
+	}
+	for _, term := range queryTerms(query) {
+		corrected, err := s.closestVocabTerm(ctx, workspaceID, term)
+		if err != nil {
+			return "", err
+		}
+		if corrected != "" {
+			add(`"` + corrected + `"`)
+		}
+		if runes := []rune(term); len(runes) >= fuzzyPrefixMinLen {
+			add(`"` + string(runes[:len(runes)-1]) + `"*`)
+		}
+	}
+	return strings.Join(parts, " OR "), nil
+}
+
+// closestVocabTerm returns the term of workspaceID's live items nearest to
+// term by edit distance, preferring the term found in more documents on
+// ties, or "" when none is within fuzzyMaxEdits.
+//
+// The shared vocabulary is read only over terms starting with term's first
+// letter, a range fts5vocab serves without a full scan, so a typo in the
+// first letter is left to the prefix match. Candidates are then checked, best
+// first, against the workspace: the vocabulary spans every tenant.
+func (s *SearchService) closestVocabTerm(ctx context.Context, workspaceID, term string) (string, error) {
+	runes := []rune(term)
+	n := len(runes)
+	if n == 0 {
+		return "", nil
+	}
+	maxEdits := fuzzyMaxEdits(n)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT term FROM knowledge_item_fts_vocab
+		WHERE term >= ? AND term < ? AND length(term) BETWEEN ? AND ?
+		ORDER BY doc DESC, term`, string(runes[0]), string(runes[0]+1), n-maxEdits, n+maxEdits)
+	if err != nil {
+		return "", fmt.Errorf("fuzzy search vocabulary: %w", err)
+	}
+	var candidates []fuzzyCandidate
+	for rows.Next() {
+		var candidate string
+		if err = rows.Scan(&candidate); err != nil {
+			_ = rows.Close()
+			return "", fmt.Errorf("fuzzy search vocabulary scan: %w", err)
+		}
+		if d := editDistance(term, candidate); d <= maxEdits {
+			candidates = append(candidates, fuzzyCandidate{term: candidate, distance: d})
+		}
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return "", fmt.Errorf("fuzzy search vocabulary: %w", err)
+	}
+	_ = rows.Close()
+
+	// Stable, so equally distant terms keep the vocabulary's doc order.
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	for i, candidate := range candidates {
+		if i == fuzzyMaxCandidates {
+			break
+		}
+		inWorkspace, err := s.termInWorkspace(ctx, workspaceID, candidate.term)
+		if err != nil {
+			return "", err
+		}
+		if inWorkspace {
+			return candidate.term, nil
+		}
+	}
+	return "", nil
+}
+
+type fuzzyCandidate struct {
+	term     string
+	distance int
+}
+
+// termInWorkspace reports whether a live item of workspaceID contains term.
+func (s *SearchService) termInWorkspace(ctx context.Context, workspaceID, term string) (bool, error) {
+	var found bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM knowledge_item_fts
+			JOIN knowledge_item ki ON ki.id = knowledge_item_fts.id
+			WHERE knowledge_item_fts MATCH ?
+			  AND knowledge_item_fts.workspace_id = ?
+			  AND ki.deleted_at IS NULL
+		)`, `"`+term+`"`, workspaceID).Scan(&found)
+	if err != nil {
+		return false, fmt.Errorf("fuzzy search vocabulary lookup: %w", err)
+	}
+	return found, nil
+}
+
+// fuzzyMaxEdits allows one typo in short terms and two in longer ones;
+// terms under four letters must match exactly.
+func fuzzyMaxEdits(termLen int) int {
+	switch {
+	case termLen < 4:
+		return 0
+	case termLen < 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// queryTerms splits query into lowercase words, leaving out the
+// entity_type:/entity_id: scope tokens.
+func queryTerms(query string) []string {
+	var terms []string
+	for _, token := range strings.Fields(query) {
+		if strings.HasPrefix(token, "entity_type:") || strings.HasPrefix(token, "entity_id:") {
+			continue
+		}
+		words := strings.FieldsFunc(strings.ToLower(token), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		terms = append(terms, words...)
+		if len(terms) >= fuzzyMaxTerms {
+			return terms[:fuzzyMaxTerms]
+		}
+	}
+	return terms
+}
+
+// editDistance is the Levenshtein distance between a and b, in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
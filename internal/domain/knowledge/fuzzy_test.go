@@ -0,0 +1,104 @@
+package knowledge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestHybridSearch_FuzzyFindsMisspelledQuery(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	wsID := createWorkspace(t, db)
+	ctx := context.Background()
+	ingest := NewIngestService(db, eventbus.New())
+	svc := NewSearchService(db, newStubEmbedder(3))
+
+	// Not embedded: only keyword matching can find them.
+	var passwordItemID string
+	for _, doc := range []struct{ title, content string }{
+		{"Password reset", "Customers can reset their password from the login page."},
+		{"Invoices", "Billing sends invoices on the first day of the month."},
+	} {
+		item, err := ingest.Ingest(ctx, CreateKnowledgeItemInput{
+			WorkspaceID: wsID, SourceType: SourceTypeDocument, Title: doc.title, RawContent: doc.content,
+		})
+		if err != nil {
+			t.Fatalf("Ingest(%s) error = %v", doc.title, err)
+		}
+		if doc.title == "Password reset" {
+			passwordItemID = item.ID
+		}
+	}
+
+	input := SearchInput{Query: "pasword resett", WorkspaceID: wsID}
+	exact, err := svc.HybridSearch(ctx, input)
+	if err != nil {
+		t.Fatalf("HybridSearch() error = %v", err)
+	}
+	if len(exact.Items) != 0 {
+		t.Fatalf("HybridSearch() without Fuzzy = %+v; want no results", exact.Items)
+	}
+
+	input.Fuzzy = true
+	fuzzy, err := svc.HybridSearch(ctx, input)
+	if err != nil {
+		t.Fatalf("HybridSearch(Fuzzy) error = %v", err)
+	}
+	if len(fuzzy.Items) != 1 || fuzzy.Items[0].KnowledgeItemID != passwordItemID {
+		t.Fatalf("HybridSearch(Fuzzy) = %+v; want only %s", fuzzy.Items, passwordItemID)
+	}
+	if fuzzy.Items[0].Method != EvidenceMethodBM25 {
+		t.Fatalf("method = %s; want bm25", fuzzy.Items[0].Method)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want int
+	}{
+		{"password", "password", 0},
+		{"pasword", "password", 1},
+		{"resett", "reset", 1},
+		{"invoce", "invoices", 2},
+		{"", "abc", 3},
+	} {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClosestVocabTerm_IgnoresOtherWorkspaces(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	ctx := context.Background()
+	ingest := NewIngestService(db, eventbus.New())
+	svc := NewSearchService(db, newStubEmbedder(3))
+	own, other := createWorkspace(t, db), createWorkspace(t, db)
+	for wsID, content := range map[string]string{
+		own:   "Billing sends invoices on the first day of the month.",
+		other: "Customers can reset their password from the login page.",
+	} {
+		if _, err := ingest.Ingest(ctx, CreateKnowledgeItemInput{
+			WorkspaceID: wsID, SourceType: SourceTypeDocument, Title: "Doc", RawContent: content,
+		}); err != nil {
+			t.Fatalf("Ingest() error = %v", err)
+		}
+	}
+
+	got, err := svc.closestVocabTerm(ctx, own, "pasword")
+	if err != nil {
+		t.Fatalf("closestVocabTerm() error = %v", err)
+	}
+	if got != "" {
+		t.Fatalf("closestVocabTerm(own) = %q; want no term from another workspace", got)
+	}
+	if got, err = svc.closestVocabTerm(ctx, other, "pasword"); err != nil || got != "password" {
+		t.Fatalf("closestVocabTerm(other) = %q, %v; want password", got, err)
+	}
+}
@@ -0,0 +1,48 @@
+package knowledge
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Highlight is a span of a search result snippet that matches a query term,
+// as rune offsets: Snippet[Start:End] once converted to []rune.
+type Highlight struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// HighlightTerms returns the spans of snippet whose words start with one of
+// the query terms, compared case-insensitively, in snippet order. Entity
+// scope tokens in query (entity_type:, entity_id:) are ignored.
+func HighlightTerms(snippet, query string) []Highlight {
+	terms := queryTerms(query)
+	if len(terms) == 0 || snippet == "" {
+		return nil
+	}
+	var out []Highlight
+	runes := []rune(snippet)
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		word := strings.ToLower(string(runes[start:end]))
+		for _, term := range terms {
+			if strings.HasPrefix(word, term) {
+				out = append(out, Highlight{Start: start, End: end})
+				break
+			}
+		}
+		start = end
+	}
+	return out
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
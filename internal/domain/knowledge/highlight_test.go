@@ -0,0 +1,61 @@
+package knowledge
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestHighlightTerms(t *testing.T) {
+	for _, tt := range []struct {
+		snippet, query string
+		want           []Highlight
+	}{
+		{"Reset your Password now", "password reset", []Highlight{{0, 5}, {11, 19}}},
+		{"Resetting passwords", "reset", []Highlight{{0, 9}}},
+		{"Über café policy", "café", []Highlight{{5, 9}}},
+		{"Invoices are monthly", "entity_type:deal refund", nil},
+		{"", "reset", nil},
+	} {
+		got := HighlightTerms(tt.snippet, tt.query)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("HighlightTerms(%q, %q) = %v; want %v", tt.snippet, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestHybridSearch_SourceTypeFilterAndHighlights(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	wsID := createWorkspace(t, db)
+	ctx := context.Background()
+	ingest := NewIngestService(db, eventbus.New())
+	svc := NewSearchService(db, newStubEmbedder(3))
+
+	var docID string
+	for _, st := range []SourceType{SourceTypeDocument, SourceTypeCase} {
+		item, err := ingest.Ingest(ctx, CreateKnowledgeItemInput{
+			WorkspaceID: wsID, SourceType: st, Title: "Refund policy", RawContent: "Refunds are issued within 14 days.",
+		})
+		if err != nil {
+			t.Fatalf("Ingest(%s) error = %v", st, err)
+		}
+		if st == SourceTypeDocument {
+			docID = item.ID
+		}
+	}
+
+	results, err := svc.HybridSearch(ctx, SearchInput{Query: "refund", WorkspaceID: wsID, SourceType: SourceTypeDocument})
+	if err != nil {
+		t.Fatalf("HybridSearch() error = %v", err)
+	}
+	if len(results.Items) != 1 || results.Items[0].KnowledgeItemID != docID {
+		t.Fatalf("HybridSearch(source_type=document) = %+v; want only %s", results.Items, docID)
+	}
+	if len(results.Items[0].Highlights) == 0 {
+		t.Fatalf("highlights = none; want a match in %q", results.Items[0].Snippet)
+	}
+}
@@ -0,0 +1,119 @@
+package knowledge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+)
+
+const searchComponent = "knowledge.search"
+
+// IndexStats summarizes the search index of a workspace. Only live chunks
+// count: neither the chunk nor its knowledge item is soft-deleted.
+type IndexStats struct {
+	Chunks   int `json:"chunks"`
+	Embedded int `json:"embedded"`
+	Pending  int `json:"pending"`
+	Failed   int `json:"failed"`
+	// AvgVectorDimension is the mean length of the embedded chunks'
+	// vectors; 0 without any. A value between two model sizes means the
+	// workspace mixes embeddings of different models.
+	AvgVectorDimension float64 `json:"avg_vector_dimension"`
+}
+
+// IndexStats returns the chunk counts of workspaceID by embedding status and
+// the average dimension of its stored vectors, so operators can see the
+// embedding backlog.
+func (s *SearchService) IndexStats(ctx context.Context, workspaceID string) (*IndexStats, error) {
+	var stats IndexStats
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+		       COALESCE(SUM(ed.embedding_status = 'embedded'), 0),
+		       COALESCE(SUM(ed.embedding_status = 'pending'), 0),
+		       COALESCE(SUM(ed.embedding_status = 'failed'), 0)
+		FROM embedding_document ed
+		JOIN knowledge_item ki ON ki.id = ed.knowledge_item_id
+		WHERE ed.workspace_id = ?
+		  AND ed.deleted_at IS NULL
+		  AND ki.deleted_at IS NULL`, workspaceID,
+	).Scan(&stats.Chunks, &stats.Embedded, &stats.Pending, &stats.Failed)
+	if err != nil {
+		return nil, fmt.Errorf("index stats: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(AVG(json_array_length(v.embedding)), 0)
+		FROM vec_embedding v
+		JOIN embedding_document ed ON ed.id = v.id
+		JOIN knowledge_item ki ON ki.id = ed.knowledge_item_id
+		WHERE ed.workspace_id = ?
+		  AND ed.embedding_status = 'embedded'
+		  AND ed.deleted_at IS NULL
+		  AND ki.deleted_at IS NULL
+		  AND json_valid(v.embedding)`, workspaceID,
+	).Scan(&stats.AvgVectorDimension)
+	if err != nil {
+		return nil, fmt.Errorf("index stats vector dimension: %w", err)
+	}
+	return &stats, nil
+}
+
+// WarmUp reads every vector the vector search of workspaceID would scan, so
+// their pages are cached by SQLite and the OS before the first query of a
+// large workspace. It returns the number of vectors read.
+func (s *SearchService) WarmUp(ctx context.Context, workspaceID string) (int, error) {
+	var vectors int
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM vec_embedding v
+		JOIN embedding_document ed ON ed.id = v.id
+		JOIN knowledge_item ki ON ki.id = ed.knowledge_item_id
+		WHERE ed.workspace_id = ?
+		  AND ed.embedding_status = 'embedded'
+		  AND ed.deleted_at IS NULL
+		  AND ki.deleted_at IS NULL
+		  AND json_valid(v.embedding)`, workspaceID,
+	).Scan(&vectors)
+	if err != nil {
+		return 0, fmt.Errorf("warm up vector index: %w", err)
+	}
+	return vectors, nil
+}
+
+// WarmUpAll runs WarmUp for every workspace, logging failures and going on
+// with the next one. It is meant to run once in the background at startup.
+func (s *SearchService) WarmUpAll(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+	workspaceIDs, err := s.workspaceIDs(ctx)
+	if err != nil {
+		logger.Error("vector index warm-up failed", slog.String(logging.KeyComponent, searchComponent), logging.Err(err))
+		return
+	}
+	for _, workspaceID := range workspaceIDs {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, err = s.WarmUp(ctx, workspaceID); err != nil {
+			logger.Error("vector index warm-up failed", slog.String(logging.KeyComponent, searchComponent), slog.String(logging.KeyWorkspaceID, workspaceID), logging.Err(err))
+		}
+	}
+}
+
+func (s *SearchService) workspaceIDs(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM workspace`)
+	if err != nil {
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
+	defer rows.Close()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan workspace: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,66 @@
+package knowledge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestSearchService_IndexStats_CountsBacklogPerWorkspace(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	stub := newStubEmbedder(3)
+	wsID := createWorkspace(t, db)
+	otherWS := createWorkspace(t, db)
+	ctx := context.Background()
+
+	ingest := NewIngestService(db, eventbus.New())
+	embedder := NewEmbedderService(db, stub)
+	svc := NewSearchService(db, stub)
+
+	ingestAndEmbedDoc(t, ingest, embedder, wsID, "Embedded", "embedded chunk about refunds")
+	ingestAndEmbedDoc(t, ingest, embedder, otherWS, "Other Workspace", "chunk of another workspace")
+	deleted := ingestAndEmbedDoc(t, ingest, embedder, wsID, "Deleted", "deleted chunk about billing")
+	if err := ingest.Delete(ctx, wsID, deleted.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	for _, title := range []string{"Pending", "Failed"} {
+		if _, err := ingest.Ingest(ctx, CreateKnowledgeItemInput{
+			WorkspaceID: wsID, SourceType: SourceTypeDocument, Title: title, RawContent: title + " chunk waiting for embedding",
+		}); err != nil {
+			t.Fatalf("Ingest(%s) error = %v", title, err)
+		}
+	}
+	if _, err := db.Exec(`
+		UPDATE embedding_document SET embedding_status = 'failed'
+		WHERE knowledge_item_id = (SELECT id FROM knowledge_item WHERE workspace_id = ? AND title = 'Failed')`, wsID); err != nil {
+		t.Fatalf("mark failed: %v", err)
+	}
+
+	stats, err := svc.IndexStats(ctx, wsID)
+	if err != nil {
+		t.Fatalf("IndexStats() error = %v", err)
+	}
+	want := IndexStats{Chunks: 3, Embedded: 1, Pending: 1, Failed: 1, AvgVectorDimension: 3}
+	if *stats != want {
+		t.Fatalf("IndexStats() = %+v; want %+v", *stats, want)
+	}
+
+	empty, err := svc.IndexStats(ctx, createWorkspace(t, db))
+	if err != nil {
+		t.Fatalf("IndexStats(empty) error = %v", err)
+	}
+	if *empty != (IndexStats{}) {
+		t.Fatalf("IndexStats(empty) = %+v; want zero", *empty)
+	}
+
+	vectors, err := svc.WarmUp(ctx, wsID)
+	if err != nil {
+		t.Fatalf("WarmUp() error = %v", err)
+	}
+	if vectors != 1 {
+		t.Fatalf("WarmUp() read %d vectors; want 1", vectors)
+	}
+}
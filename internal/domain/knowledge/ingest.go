@@ -5,8 +5,13 @@ package knowledge
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -31,33 +36,63 @@ const (
 	DefaultChunkOverlap = 50
 )
 
+// ErrInvalidSections is returned by Ingest for an input with both Sections
+// and RawContent.
+var ErrInvalidSections = errors.New("knowledge item sections replace raw content")
+
 // IngestService handles knowledge item creation and chunking (Task 2.2).
 type IngestService struct {
-	db  *sql.DB
-	bus eventbus.EventBus
-	q   *sqlcgen.Queries
+	db         *sql.DB
+	bus        eventbus.EventBus
+	q          *sqlcgen.Queries
+	httpClient *http.Client
+	// normalizers derive normalized_content per SourceType (see RegisterNormalizer).
+	normalizers map[SourceType]Normalizer
 }
 
 // NewIngestService creates an IngestService backed by the given DB and event bus.
 func NewIngestService(db *sql.DB, bus eventbus.EventBus) *IngestService {
 	return &IngestService{
-		db:  db,
-		bus: bus,
-		q:   sqlcgen.New(db),
+		db:          db,
+		bus:         bus,
+		q:           sqlcgen.New(db),
+		httpClient:  newURLFetchClient(isPublicAddr),
+		normalizers: defaultNormalizers(),
 	}
 }
 
-// Ingest creates (or updates) a knowledge_item, splits the raw content into
+// Ingest creates (or updates) a knowledge_item, splits its normalized content into
 // chunks, inserts embedding_document rows with status=pending, and publishes
 // a knowledge.ingested event.
 //
 // Idempotency: if a knowledge_item already exists for the same
-// (workspace_id, entity_type, entity_id), the existing item is updated and
-// its old chunks are replaced.
+// (workspace_id, entity_type, entity_id), it is updated in place. When its
+// content hash matches the input nothing is written and the item comes back
+// with Outcome unchanged; otherwise its chunks and vectors are replaced in
+// the same transaction as the update. The returned item's Outcome says which
+// of created, updated or unchanged happened.
+//
+// With DedupOnIngest, an input that would create a new item but whose
+// normalized content already exists in the workspace returns that item with
+// Outcome unchanged and WasDuplicate set; nothing is written.
+//
+// Returns ErrQuotaExceeded when the workspace's knowledge quota cannot fit
+// the new item or the growth of an updated one.
 func (s *IngestService) Ingest(ctx context.Context, input CreateKnowledgeItemInput) (*KnowledgeItem, error) {
+	input, err := withSectionContent(input)
+	if err != nil {
+		return nil, err
+	}
 	now := time.Now()
-	normalized := normalizeContent(input.RawContent)
+	normalized := s.normalize(input)
+	hash := contentHash(input)
 	existingID := s.findExistingItemID(ctx, input)
+	if existingID == "" && input.DedupOnIngest {
+		duplicate, dupErr := s.findDuplicateItem(ctx, input.WorkspaceID, normalized)
+		if dupErr != nil || duplicate != nil {
+			return duplicate, dupErr
+		}
+	}
 
 	tx, txErr := s.db.BeginTx(ctx, nil)
 	if txErr != nil {
@@ -65,16 +100,43 @@ func (s *IngestService) Ingest(ctx context.Context, input CreateKnowledgeItemInp
 	}
 	defer tx.Rollback() //nolint:errcheck
 
+	createdAt := now
+	outcome := IngestOutcomeCreated
+	if existingID != "" {
+		stored, storedErr := loadStoredItemHash(ctx, tx, existingID)
+		if storedErr != nil {
+			return nil, storedErr
+		}
+		if stored.hash.Valid && stored.hash.String == hash {
+			item := ingestedItem(existingID, input, normalized, hash, stored.createdAt, stored.updatedAt)
+			item.Outcome = IngestOutcomeUnchanged
+			return item, nil
+		}
+		createdAt = stored.createdAt
+		outcome = IngestOutcomeUpdated
+	}
+
+	delta, deltaErr := ingestUsageDelta(ctx, tx, existingID, input.RawContent)
+	if deltaErr != nil {
+		return nil, deltaErr
+	}
+	if quotaErr := checkQuota(ctx, tx, input.WorkspaceID, delta); quotaErr != nil {
+		return nil, quotaErr
+	}
+
 	qtx := sqlcgen.New(tx)
-	itemID, upErr := s.upsertKnowledgeItem(ctx, tx, qtx, existingID, input, normalized, now)
+	itemID, upErr := s.upsertKnowledgeItem(ctx, tx, qtx, existingID, input, normalized, hash, now)
 	if upErr != nil {
 		return nil, upErr
 	}
 
-	chunks := Chunk(input.RawContent, DefaultChunkSize, DefaultChunkOverlap)
+	chunks := s.chunkInput(input, normalized)
 	if chunkErr := insertChunks(ctx, qtx, itemID, input.WorkspaceID, chunks, now); chunkErr != nil {
 		return nil, chunkErr
 	}
+	if usageErr := applyUsageDelta(ctx, tx, input.WorkspaceID, delta, now); usageErr != nil {
+		return nil, usageErr
+	}
 
 	if commitErr := tx.Commit(); commitErr != nil {
 		return nil, fmt.Errorf("commit knowledge ingest transaction: %w", commitErr)
@@ -86,8 +148,15 @@ func (s *IngestService) Ingest(ctx context.Context, input CreateKnowledgeItemInp
 		ChunkCount:      len(chunks),
 	})
 
+	item := ingestedItem(itemID, input, normalized, hash, createdAt, now)
+	item.Outcome = outcome
+	return item, nil
+}
+
+// ingestedItem is the KnowledgeItem stored for input.
+func ingestedItem(id string, input CreateKnowledgeItemInput, normalized, hash string, createdAt, updatedAt time.Time) *KnowledgeItem {
 	return &KnowledgeItem{
-		ID:                itemID,
+		ID:                id,
 		WorkspaceID:       input.WorkspaceID,
 		SourceSystem:      input.SourceSystem,
 		SourceType:        input.SourceType,
@@ -101,27 +170,236 @@ func (s *IngestService) Ingest(ctx context.Context, input CreateKnowledgeItemInp
 		EntityType:        input.EntityType,
 		EntityID:          input.EntityID,
 		Metadata:          input.Metadata,
-		CreatedAt:         now,
-		UpdatedAt:         now,
+		Language:          ptrFromStr(DetectLanguage(normalized)),
+		ContentHash:       &hash,
+		CreatedAt:         createdAt,
+		UpdatedAt:         updatedAt,
+	}
+}
+
+// contentHash is the hex SHA-256 of every input field Ingest stores, so an
+// equal hash means re-ingesting would not change the item. Fields are
+// length-prefixed so values cannot run into each other.
+func contentHash(input CreateKnowledgeItemInput) string {
+	h := sha256.New()
+	for _, field := range []string{
+		string(input.SourceType),
+		stringOrEmpty(input.SourceSystem),
+		stringOrEmpty(input.SourceObjectID),
+		stringOrEmpty(input.RefreshStrategy),
+		stringOrEmpty(input.DeleteBehavior),
+		stringOrEmpty(input.PermissionContext),
+		input.Title,
+		input.RawContent,
+		stringOrEmpty(input.Metadata),
+	} {
+		fmt.Fprintf(h, "%d:%s", len(field), field)
+	}
+	// Only hashed when set, so items ingested without chunk metadata or
+	// sections keep their hash.
+	if chunkMetadata := encodeChunkMetadata(input.ChunkMetadata); chunkMetadata != nil {
+		fmt.Fprintf(h, "%d:%s", len(*chunkMetadata), *chunkMetadata)
+	}
+	for _, section := range input.Sections {
+		metadata := stringOrEmpty(encodeChunkMetadata(section.Metadata))
+		fmt.Fprintf(h, "section:%d:%s%d:%s", len(section.Text), section.Text, len(metadata), metadata)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// withSectionContent returns input with RawContent set to its sections
+// joined by blank lines. It fails with ErrInvalidSections when input has
+// both sections and RawContent.
+func withSectionContent(input CreateKnowledgeItemInput) (CreateKnowledgeItemInput, error) {
+	if len(input.Sections) == 0 {
+		return input, nil
+	}
+	if input.RawContent != "" {
+		return input, ErrInvalidSections
+	}
+	texts := make([]string, len(input.Sections))
+	for i, section := range input.Sections {
+		texts[i] = section.Text
+	}
+	input.RawContent = strings.Join(texts, "\n\n")
+	return input, nil
+}
+
+// ingestChunk is one chunk to store with its metadata (JSON, or nil for none).
+type ingestChunk struct {
+	text     string
+	metadata *string
+}
+
+// chunkInput splits the normalized content of input into chunks. Each
+// section is chunked on its own, so no chunk spans two sections, and its
+// chunks carry the section metadata over input.ChunkMetadata.
+func (s *IngestService) chunkInput(input CreateKnowledgeItemInput, normalized string) []ingestChunk {
+	if len(input.Sections) == 0 {
+		return tagChunks(Chunk(normalized, DefaultChunkSize, DefaultChunkOverlap), input.ChunkMetadata)
+	}
+	var chunks []ingestChunk
+	for _, section := range input.Sections {
+		metadata := make(map[string]string, len(input.ChunkMetadata)+len(section.Metadata))
+		for k, v := range input.ChunkMetadata {
+			metadata[k] = v
+		}
+		for k, v := range section.Metadata {
+			metadata[k] = v
+		}
+		text := s.normalizeText(input.SourceType, section.Text)
+		chunks = append(chunks, tagChunks(Chunk(text, DefaultChunkSize, DefaultChunkOverlap), metadata)...)
+	}
+	return chunks
+}
+
+func tagChunks(texts []string, metadata map[string]string) []ingestChunk {
+	encoded := encodeChunkMetadata(metadata)
+	chunks := make([]ingestChunk, len(texts))
+	for i, text := range texts {
+		chunks[i] = ingestChunk{text: text, metadata: encoded}
+	}
+	return chunks
+}
+
+// encodeChunkMetadata returns metadata as the JSON stored in
+// embedding_document.metadata, or nil when it is empty. encoding/json sorts
+// the keys, so equal maps encode identically.
+func encodeChunkMetadata(metadata map[string]string) *string {
+	if len(metadata) == 0 {
+		return nil
+	}
+	raw, _ := json.Marshal(metadata) // a map[string]string always marshals
+	encoded := string(raw)
+	return &encoded
+}
+
+// storedItemHash is the stored content hash and timestamps of an item.
+type storedItemHash struct {
+	hash      sql.NullString
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+func loadStoredItemHash(ctx context.Context, tx *sql.Tx, itemID string) (storedItemHash, error) {
+	var stored storedItemHash
+	if err := tx.QueryRowContext(ctx,
+		`SELECT content_hash, created_at, updated_at FROM knowledge_item WHERE id = ?`, itemID,
+	).Scan(&stored.hash, &stored.createdAt, &stored.updatedAt); err != nil {
+		return storedItemHash{}, fmt.Errorf("load knowledge item hash: %w", err)
+	}
+	return stored, nil
+}
+
+// Get returns a live knowledge item of the workspace, or sql.ErrNoRows when
+// it does not exist or was deleted.
+func (s *IngestService) Get(ctx context.Context, workspaceID, itemID string) (*KnowledgeItem, error) {
+	row, err := s.q.GetKnowledgeItemByID(ctx, sqlcgen.GetKnowledgeItemByIDParams{
+		ID:          itemID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &KnowledgeItem{
+		ID:                row.ID,
+		WorkspaceID:       row.WorkspaceID,
+		SourceSystem:      row.SourceSystem,
+		SourceType:        SourceType(row.SourceType),
+		SourceObjectID:    row.SourceObjectID,
+		RefreshStrategy:   row.RefreshStrategy,
+		DeleteBehavior:    row.DeleteBehavior,
+		PermissionContext: row.PermissionContext,
+		Title:             row.Title,
+		RawContent:        row.RawContent,
+		NormalizedContent: row.NormalizedContent,
+		EntityType:        row.EntityType,
+		EntityID:          row.EntityID,
+		Metadata:          row.Metadata,
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+		DeletedAt:         row.DeletedAt,
 	}, nil
 }
 
+// Delete soft-deletes a knowledge_item. Its vec_embedding rows are removed and
+// its embedding_document rows are marked deleted in the same transaction, so
+// neither BM25 nor vector search can return the item afterwards. Returns
+// sql.ErrNoRows when the item does not exist or is already deleted.
+func (s *IngestService) Delete(ctx context.Context, workspaceID, itemID string) error {
+	tx, txErr := s.db.BeginTx(ctx, nil)
+	if txErr != nil {
+		return fmt.Errorf("begin knowledge delete transaction: %w", txErr)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	now := time.Now()
+	var contentBytes int64
+	err := tx.QueryRowContext(ctx,
+		`UPDATE knowledge_item SET deleted_at = ?, updated_at = ?
+		 WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL
+		 RETURNING length(CAST(raw_content AS BLOB))`,
+		now, now, itemID, workspaceID,
+	).Scan(&contentBytes)
+	if err != nil {
+		return fmt.Errorf("soft delete knowledge item: %w", err)
+	}
+	if err = applyUsageDelta(ctx, tx, workspaceID, usageDelta{items: -1, bytes: -contentBytes}, now); err != nil {
+		return err
+	}
+
+	if err = sqlcgen.New(tx).DeleteVecEmbeddingsByKnowledgeItem(ctx, sqlcgen.DeleteVecEmbeddingsByKnowledgeItemParams{
+		KnowledgeItemID: itemID,
+		WorkspaceID:     workspaceID,
+	}); err != nil {
+		return fmt.Errorf("delete knowledge vectors: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx,
+		`UPDATE embedding_document SET deleted_at = ?
+		 WHERE knowledge_item_id = ? AND workspace_id = ? AND deleted_at IS NULL`,
+		now, itemID, workspaceID,
+	); err != nil {
+		return fmt.Errorf("soft delete knowledge chunks: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit knowledge delete transaction: %w", err)
+	}
+	return nil
+}
+
+// ingestUsageDelta is the usage change of ingesting rawContent: a new item
+// adds one item and its bytes, an update only the change in size.
+func ingestUsageDelta(ctx context.Context, tx *sql.Tx, existingID, rawContent string) (usageDelta, error) {
+	newBytes := int64(len(rawContent))
+	if existingID == "" {
+		return usageDelta{items: 1, bytes: newBytes}, nil
+	}
+	var oldBytes int64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT length(CAST(raw_content AS BLOB)) FROM knowledge_item WHERE id = ?`, existingID,
+	).Scan(&oldBytes); err != nil {
+		return usageDelta{}, fmt.Errorf("load knowledge item size: %w", err)
+	}
+	return usageDelta{bytes: newBytes - oldBytes}, nil
+}
+
 // upsertKnowledgeItem inserts a new item or updates+clears chunks of an existing one.
 // Returns the item ID (new or existing).
 func (s *IngestService) upsertKnowledgeItem(
 	ctx context.Context, tx *sql.Tx, qtx *sqlcgen.Queries,
-	existingID string, input CreateKnowledgeItemInput, normalized string, now time.Time,
+	existingID string, input CreateKnowledgeItemInput, normalized, hash string, now time.Time,
 ) (string, error) {
 	if existingID == "" {
-		return s.insertKnowledgeItem(ctx, qtx, input, normalized, now)
+		return s.insertKnowledgeItem(ctx, qtx, input, normalized, hash, now)
 	}
-	return existingID, s.updateKnowledgeItem(ctx, tx, qtx, existingID, input, normalized, now)
+	return existingID, s.updateKnowledgeItem(ctx, tx, qtx, existingID, input, normalized, hash, now)
 }
 
 // insertKnowledgeItem inserts a new knowledge_item row and returns its ID.
 func (s *IngestService) insertKnowledgeItem(
 	ctx context.Context, qtx *sqlcgen.Queries,
-	input CreateKnowledgeItemInput, normalized string, now time.Time,
+	input CreateKnowledgeItemInput, normalized, hash string, now time.Time,
 ) (string, error) {
 	itemID := uuid.NewV7().String()
 	err := qtx.CreateKnowledgeItem(ctx, sqlcgen.CreateKnowledgeItemParams{
@@ -139,6 +417,9 @@ func (s *IngestService) insertKnowledgeItem(
 		EntityType:        input.EntityType,
 		EntityID:          input.EntityID,
 		Metadata:          input.Metadata,
+		Language:          ptrFromStr(DetectLanguage(normalized)),
+		ContentHash:       &hash,
+		NormalizedHash:    NormalizedContentHash(normalized),
 		CreatedAt:         now,
 		UpdatedAt:         now,
 	})
@@ -148,10 +429,11 @@ func (s *IngestService) insertKnowledgeItem(
 	return itemID, nil
 }
 
-// updateKnowledgeItem updates content fields and removes old chunks for re-chunking.
+// updateKnowledgeItem updates content fields and removes the old chunks and
+// their vectors for re-chunking.
 func (s *IngestService) updateKnowledgeItem(
 	ctx context.Context, tx *sql.Tx, qtx *sqlcgen.Queries,
-	itemID string, input CreateKnowledgeItemInput, normalized string, now time.Time,
+	itemID string, input CreateKnowledgeItemInput, normalized, hash string, now time.Time,
 ) error {
 	if _, err := tx.ExecContext(ctx,
 		`UPDATE knowledge_item
@@ -165,6 +447,9 @@ func (s *IngestService) updateKnowledgeItem(
 		     raw_content=?,
 		     normalized_content=?,
 		     metadata=?,
+		     language=?,
+		     content_hash=?,
+		     normalized_hash=?,
 		     updated_at=?
 		 WHERE id=? AND workspace_id=?`,
 		input.SourceSystem,
@@ -177,12 +462,21 @@ func (s *IngestService) updateKnowledgeItem(
 		input.RawContent,
 		normalized,
 		input.Metadata,
+		ptrFromStr(DetectLanguage(normalized)),
+		hash,
+		NormalizedContentHash(normalized),
 		now,
 		itemID,
 		input.WorkspaceID,
 	); err != nil {
 		return fmt.Errorf("update knowledge item: %w", err)
 	}
+	if err := qtx.DeleteVecEmbeddingsByKnowledgeItem(ctx, sqlcgen.DeleteVecEmbeddingsByKnowledgeItemParams{
+		KnowledgeItemID: itemID,
+		WorkspaceID:     input.WorkspaceID,
+	}); err != nil {
+		return fmt.Errorf("delete knowledge vectors: %w", err)
+	}
 	if err := qtx.DeleteEmbeddingDocumentsByKnowledgeItem(ctx, sqlcgen.DeleteEmbeddingDocumentsByKnowledgeItemParams{
 		KnowledgeItemID: itemID,
 		WorkspaceID:     input.WorkspaceID,
@@ -192,18 +486,20 @@ func (s *IngestService) updateKnowledgeItem(
 	return nil
 }
 
-// insertChunks inserts embedding_document rows for each chunk with status=pending.
-func insertChunks(ctx context.Context, qtx *sqlcgen.Queries, itemID, workspaceID string, chunks []string, now time.Time) error {
-	for i, chunkText := range chunks {
-		tokenCount := int64(len(strings.Fields(chunkText)))
+// insertChunks inserts embedding_document rows for each chunk with
+// status=pending.
+func insertChunks(ctx context.Context, qtx *sqlcgen.Queries, itemID, workspaceID string, chunks []ingestChunk, now time.Time) error {
+	for i, chunk := range chunks {
+		tokenCount := int64(len(strings.Fields(chunk.text)))
 		if err := qtx.CreateEmbeddingDocument(ctx, sqlcgen.CreateEmbeddingDocumentParams{
 			ID:              uuid.NewV7().String(),
 			KnowledgeItemID: itemID,
 			WorkspaceID:     workspaceID,
 			ChunkIndex:      int64(i),
-			ChunkText:       chunkText,
+			ChunkText:       chunk.text,
 			TokenCount:      &tokenCount,
 			EmbeddingStatus: string(EmbeddingStatusPending),
+			Metadata:        chunk.metadata,
 			CreatedAt:       now,
 		}); err != nil {
 			return fmt.Errorf("create embedding document: %w", err)
@@ -213,10 +509,12 @@ func insertChunks(ctx context.Context, qtx *sqlcgen.Queries, itemID, workspaceID
 }
 
 // findExistingItemID returns the ID of an existing knowledge_item for the same
-// entity (workspace+entity_type+entity_id), or empty string if not found.
+// entity (workspace+entity_type+entity_id), or, for URL items without an
+// entity, the same page (workspace+source_object_id). Returns empty string if
+// not found.
 func (s *IngestService) findExistingItemID(ctx context.Context, input CreateKnowledgeItemInput) string {
 	if input.EntityType == nil || input.EntityID == nil {
-		return ""
+		return s.findExistingSourceItemID(ctx, input)
 	}
 	item, err := s.q.GetKnowledgeItemByEntity(ctx, sqlcgen.GetKnowledgeItemByEntityParams{
 		WorkspaceID: input.WorkspaceID,
@@ -229,10 +527,76 @@ func (s *IngestService) findExistingItemID(ctx context.Context, input CreateKnow
 	return item.ID
 }
 
-// normalizeContent strips HTML tags and trims whitespace from raw content.
-// Returns the raw content unchanged when no HTML is detected (MVP).
-func normalizeContent(raw string) string {
-	return strings.TrimSpace(raw)
+func (s *IngestService) findExistingSourceItemID(ctx context.Context, input CreateKnowledgeItemInput) string {
+	// Only URL sources update in place by source object; other sources without
+	// an entity keep creating a new item per ingest.
+	if input.SourceSystem == nil || *input.SourceSystem != SourceSystemURL || input.SourceObjectID == nil {
+		return ""
+	}
+	var id string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id FROM knowledge_item
+		 WHERE workspace_id = ? AND source_system = ? AND source_object_id = ?
+		   AND entity_id IS NULL AND deleted_at IS NULL
+		 ORDER BY created_at DESC
+		 LIMIT 1`,
+		input.WorkspaceID, *input.SourceSystem, *input.SourceObjectID,
+	).Scan(&id)
+	if err != nil {
+		return ""
+	}
+	return id
+}
+
+// findDuplicateItem returns the oldest live item in the workspace whose
+// normalized content equals normalized, or nil when there is none.
+func (s *IngestService) findDuplicateItem(ctx context.Context, workspaceID, normalized string) (*KnowledgeItem, error) {
+	if normalized == "" {
+		return nil, nil
+	}
+	item := &KnowledgeItem{WorkspaceID: workspaceID, Outcome: IngestOutcomeUnchanged, WasDuplicate: true}
+	var sourceType string
+	err := s.db.QueryRowContext(ctx,
+		`SELECT id, source_system, source_type, source_object_id, refresh_strategy, delete_behavior,
+		        permission_context, title, raw_content, normalized_content, entity_type, entity_id,
+		        metadata, language, content_hash, created_at, updated_at
+		 FROM knowledge_item
+		 WHERE workspace_id = ? AND normalized_hash = ? AND normalized_content = ? AND deleted_at IS NULL
+		 ORDER BY created_at
+		 LIMIT 1`,
+		workspaceID, *NormalizedContentHash(normalized), normalized,
+	).Scan(&item.ID, &item.SourceSystem, &sourceType, &item.SourceObjectID, &item.RefreshStrategy, &item.DeleteBehavior,
+		&item.PermissionContext, &item.Title, &item.RawContent, &item.NormalizedContent, &item.EntityType, &item.EntityID,
+		&item.Metadata, &item.Language, &item.ContentHash, &item.CreatedAt, &item.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("find duplicate knowledge item: %w", err)
+	}
+	item.SourceType = SourceType(sourceType)
+	return item, nil
+}
+
+// NormalizedContentHash is the hex SHA-256 of normalized content, stored in
+// the indexed knowledge_item.normalized_hash so duplicate lookups need not
+// compare full texts; nil for empty content. Writers of normalized_content
+// must store it alongside.
+func NormalizedContentHash(normalized string) *string {
+	if normalized == "" {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(normalized))
+	encoded := hex.EncodeToString(sum[:])
+	return &encoded
+}
+
+// stringOrEmpty returns the pointed-to value, or "" for nil.
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
 }
 
 // ptrFromStr returns nil for empty string, otherwise a pointer to the value.
@@ -7,6 +7,8 @@ package knowledge
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"testing"
 	"time"
 
@@ -237,6 +239,199 @@ func TestIngestService_Idempotent_SameEntity_UpdatesAndReplacesChunks(t *testing
 	}
 }
 
+func TestIngestService_Reingest_ReportsCreatedUpdatedUnchanged(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	bus := eventbus.New()
+	events := bus.Subscribe(TopicKnowledgeIngested)
+	svc := NewIngestService(db, bus)
+	wsID := createWorkspace(t, db)
+	ctx := context.Background()
+
+	entityType, entityID := "case", newID()
+	input := CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeCase,
+		Title:       "Refund policy",
+		RawContent:  "refunds are processed within five business days",
+		EntityType:  &entityType,
+		EntityID:    &entityID,
+	}
+	created, err := svc.Ingest(ctx, input)
+	if err != nil {
+		t.Fatalf("first ingest failed: %v", err)
+	}
+	if created.Outcome != IngestOutcomeCreated || created.ContentHash == nil {
+		t.Fatalf("first ingest: outcome=%q hash=%v; want created with hash", created.Outcome, created.ContentHash)
+	}
+	<-events
+
+	// Embed the chunk so an unchanged re-ingest can be seen to keep it.
+	var chunkID string
+	if err = db.QueryRow(`SELECT id FROM embedding_document WHERE knowledge_item_id = ?`, created.ID).Scan(&chunkID); err != nil {
+		t.Fatalf("load chunk: %v", err)
+	}
+	if _, err = db.Exec(`UPDATE embedding_document SET embedding_status = 'embedded' WHERE id = ?`, chunkID); err != nil {
+		t.Fatalf("mark chunk embedded: %v", err)
+	}
+	if _, err = db.Exec(
+		`INSERT INTO vec_embedding (id, workspace_id, embedding, created_at) VALUES (?, ?, '[0.1,0.2]', ?)`,
+		chunkID, wsID, time.Now(),
+	); err != nil {
+		t.Fatalf("insert vector: %v", err)
+	}
+
+	unchanged, err := svc.Ingest(ctx, input)
+	if err != nil {
+		t.Fatalf("unchanged ingest failed: %v", err)
+	}
+	if unchanged.Outcome != IngestOutcomeUnchanged || unchanged.ID != created.ID {
+		t.Fatalf("unchanged ingest: outcome=%q id=%q; want unchanged %q", unchanged.Outcome, unchanged.ID, created.ID)
+	}
+	var status string
+	if err = db.QueryRow(`SELECT embedding_status FROM embedding_document WHERE id = ?`, chunkID).Scan(&status); err != nil {
+		t.Fatalf("unchanged ingest dropped chunk: %v", err)
+	}
+	if status != string(EmbeddingStatusEmbedded) || countRows(t, db, `SELECT COUNT(*) FROM vec_embedding WHERE id = ?`, chunkID) != 1 {
+		t.Fatalf("unchanged ingest touched chunk: status=%q", status)
+	}
+	select {
+	case evt := <-events:
+		t.Fatalf("unchanged ingest published %v", evt.Payload)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	input.RawContent = "refunds are processed within ten business days"
+	updated, err := svc.Ingest(ctx, input)
+	if err != nil {
+		t.Fatalf("updated ingest failed: %v", err)
+	}
+	if updated.Outcome != IngestOutcomeUpdated || updated.ID != created.ID || *updated.ContentHash == *created.ContentHash {
+		t.Fatalf("updated ingest: outcome=%q id=%q; want updated %q with a new hash", updated.Outcome, updated.ID, created.ID)
+	}
+	if !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("updated ingest changed createdAt: %v -> %v", created.CreatedAt, updated.CreatedAt)
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM embedding_document WHERE id = ?`, chunkID); n != 0 {
+		t.Errorf("stale chunk still present after update")
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM vec_embedding WHERE id = ?`, chunkID); n != 0 {
+		t.Errorf("stale vector still present after update")
+	}
+	if n := countRows(t, db,
+		`SELECT COUNT(*) FROM embedding_document WHERE knowledge_item_id = ? AND embedding_status = 'pending'`, created.ID,
+	); n != 1 {
+		t.Errorf("expected 1 pending re-chunked row, got %d", n)
+	}
+	var storedHash string
+	if err = db.QueryRow(`SELECT content_hash FROM knowledge_item WHERE id = ?`, created.ID).Scan(&storedHash); err != nil {
+		t.Fatalf("load stored hash: %v", err)
+	}
+	if storedHash != *updated.ContentHash {
+		t.Errorf("stored hash = %q; want %q", storedHash, *updated.ContentHash)
+	}
+}
+
+func TestIngestService_Reingest_UnhashedItemIsUpdated(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewIngestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+	entityType, entityID := "case", newID()
+	input := CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeCase,
+		Title:       "Legacy",
+		RawContent:  "ingested before content hashes existed",
+		EntityType:  &entityType,
+		EntityID:    &entityID,
+	}
+	item, err := svc.Ingest(context.Background(), input)
+	if err != nil {
+		t.Fatalf("first ingest failed: %v", err)
+	}
+	if _, err = db.Exec(`UPDATE knowledge_item SET content_hash = NULL WHERE id = ?`, item.ID); err != nil {
+		t.Fatalf("clear hash: %v", err)
+	}
+
+	again, err := svc.Ingest(context.Background(), input)
+	if err != nil {
+		t.Fatalf("re-ingest failed: %v", err)
+	}
+	if again.Outcome != IngestOutcomeUpdated {
+		t.Fatalf("outcome = %q; want updated for an item without a stored hash", again.Outcome)
+	}
+}
+
+func TestIngestService_DedupOnIngest_ReturnsExistingItem(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewIngestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+	ctx := context.Background()
+
+	original, err := svc.Ingest(ctx, CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeDocument,
+		Title:       "Refund policy",
+		RawContent:  "refunds are processed within five business days",
+	})
+	if err != nil {
+		t.Fatalf("first ingest failed: %v", err)
+	}
+
+	copyInput := CreateKnowledgeItemInput{
+		WorkspaceID:   wsID,
+		SourceType:    SourceTypeDocument,
+		Title:         "Refunds (copy)",
+		RawContent:    "  refunds are processed within five business days\n",
+		DedupOnIngest: true,
+	}
+	duplicate, err := svc.Ingest(ctx, copyInput)
+	if err != nil {
+		t.Fatalf("dedup ingest failed: %v", err)
+	}
+	if !duplicate.WasDuplicate || duplicate.ID != original.ID || duplicate.Outcome != IngestOutcomeUnchanged {
+		t.Fatalf("dedup ingest: id=%q duplicate=%v outcome=%q; want existing %q, duplicate, unchanged",
+			duplicate.ID, duplicate.WasDuplicate, duplicate.Outcome, original.ID)
+	}
+	if duplicate.Title != "Refund policy" {
+		t.Fatalf("title = %q; want the existing item's", duplicate.Title)
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM knowledge_item WHERE workspace_id = ?`, wsID); n != 1 {
+		t.Fatalf("knowledge items = %d; want 1 after a duplicate ingest", n)
+	}
+	var storedHash string
+	if err = db.QueryRow(`SELECT normalized_hash FROM knowledge_item WHERE id = ?`, original.ID).Scan(&storedHash); err != nil {
+		t.Fatalf("load normalized hash: %v", err)
+	}
+	if want := NormalizedContentHash("refunds are processed within five business days"); storedHash != *want {
+		t.Fatalf("normalized_hash = %q; want %q", storedHash, *want)
+	}
+
+	copyInput.DedupOnIngest = false
+	created, err := svc.Ingest(ctx, copyInput)
+	if err != nil {
+		t.Fatalf("ingest without dedup failed: %v", err)
+	}
+	if created.WasDuplicate || created.ID == original.ID || created.Outcome != IngestOutcomeCreated {
+		t.Fatalf("ingest without dedup: id=%q duplicate=%v outcome=%q; want a new item",
+			created.ID, created.WasDuplicate, created.Outcome)
+	}
+}
+
+func countRows(t *testing.T, db *sql.DB, query string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	return n
+}
+
 func TestIngestService_PersistsConnectorBoundaryMetadata(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -368,6 +563,104 @@ func TestIngestService_ReingestUpdatesConnectorBoundaryMetadata(t *testing.T) {
 	}
 }
 
+func TestIngestService_SameSourceObjectWithoutEntity_OnlyURLUpdatesInPlace(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewIngestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+
+	ingestTwice := func(sourceSystem string, sourceType SourceType) (string, string) {
+		t.Helper()
+		sourceObjectID := "object-1"
+		ids := make([]string, 0, 2)
+		for _, content := range []string{"first version", "second version"} {
+			item, err := svc.Ingest(context.Background(), CreateKnowledgeItemInput{
+				WorkspaceID:    wsID,
+				SourceSystem:   &sourceSystem,
+				SourceType:     sourceType,
+				SourceObjectID: &sourceObjectID,
+				Title:          sourceSystem + " item",
+				RawContent:     content,
+			})
+			if err != nil {
+				t.Fatalf("Ingest(%s) failed: %v", sourceSystem, err)
+			}
+			ids = append(ids, item.ID)
+		}
+		return ids[0], ids[1]
+	}
+
+	if first, second := ingestTwice("google_drive", SourceTypeDocument); first == second {
+		t.Errorf("google_drive re-ingest updated item %q; want a new item", first)
+	}
+	if first, second := ingestTwice(SourceSystemURL, SourceTypeURL); first != second {
+		t.Errorf("url re-ingest created item %q; want update of %q", second, first)
+	}
+}
+
+func TestIngestService_Sections_TagTheirOwnChunks(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewIngestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+
+	item, err := svc.Ingest(context.Background(), CreateKnowledgeItemInput{
+		WorkspaceID:   wsID,
+		SourceType:    SourceTypeDocument,
+		Title:         "Password FAQ",
+		ChunkMetadata: map[string]string{"source": "help_center", "section": "body"},
+		Sections: []ContentSection{
+			{Text: "Accounts lock after five failed logins.", Metadata: map[string]string{"section": "summary"}},
+			{Text: buildText(600)},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if item.RawContent != "Accounts lock after five failed logins.\n\n"+buildText(600) {
+		t.Errorf("RawContent does not join the sections")
+	}
+
+	rows, err := db.Query(
+		`SELECT metadata FROM embedding_document WHERE knowledge_item_id = ? ORDER BY chunk_index`, item.ID)
+	if err != nil {
+		t.Fatalf("load chunks: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var metadata string
+		if err = rows.Scan(&metadata); err != nil {
+			t.Fatalf("scan chunk: %v", err)
+		}
+		got = append(got, metadata)
+	}
+	if len(got) < 3 {
+		t.Fatalf("chunks = %d; want the summary chunk and at least 2 body chunks", len(got))
+	}
+	if got[0] != `{"section":"summary","source":"help_center"}` {
+		t.Errorf("summary chunk metadata = %s", got[0])
+	}
+	for _, metadata := range got[1:] {
+		if metadata != `{"section":"body","source":"help_center"}` {
+			t.Errorf("body chunk metadata = %s", metadata)
+		}
+	}
+
+	_, err = svc.Ingest(context.Background(), CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeDocument,
+		Title:       "Both",
+		RawContent:  "raw",
+		Sections:    []ContentSection{{Text: "section"}},
+	})
+	if !errors.Is(err, ErrInvalidSections) {
+		t.Fatalf("Ingest(raw content and sections) error = %v; want ErrInvalidSections", err)
+	}
+}
+
 func TestIngestService_WorkspaceIsolation(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -452,6 +745,35 @@ func TestIngestService_PublishesEvent(t *testing.T) {
 	}
 }
 
+func TestIngestService_Delete_MissingOrDeletedItem(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewIngestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+	otherWS := createWorkspace(t, db)
+
+	item, err := svc.Ingest(context.Background(), CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeDocument,
+		Title:       "Delete Test",
+		RawContent:  "content to delete",
+	})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+
+	if err = svc.Delete(context.Background(), otherWS, item.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Delete from other workspace err = %v, want sql.ErrNoRows", err)
+	}
+	if err = svc.Delete(context.Background(), wsID, item.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if err = svc.Delete(context.Background(), wsID, item.ID); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("second Delete err = %v, want sql.ErrNoRows", err)
+	}
+}
+
 // ============================================================================
 // Error Branch Tests (Task 2.2 audit remediation)
 // ============================================================================
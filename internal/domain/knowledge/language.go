@@ -0,0 +1,57 @@
+package knowledge
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Languages detected at ingest and accepted by SearchInput.Language.
+const (
+	LanguageSpanish = "es"
+	LanguageEnglish = "en"
+)
+
+// minLanguageHits is the number of stopword hits below which a text is too
+// short or too ambiguous to label.
+const minLanguageHits = 2
+
+var languageStopwords = map[string]map[string]struct{}{
+	LanguageSpanish: wordSet("el la los las de del que y en un una por para con no es se su al lo como más pero sus le ya o este esta sí porque cuando muy sin sobre también hasta hay donde quien desde todo nos durante uno ni contra ese eso ante ellos e esto mí antes algunos qué unos yo otro otras otra él tanto esa estos mucho quienes nada muchos cual poco ella estar estas algunas algo nosotros usted"),
+	LanguageEnglish: wordSet("the of and to in is that for it as was with be by on not he i this are or his from at which but have an they you were her she there been one all we their has would when if will what can more so no who out do about how up them than into its our these some could other then also only after"),
+}
+
+// DetectLanguage returns the language of text by counting Spanish and English
+// stopwords, or "" when neither clearly wins. Spanish-only letters (ñ, ¿, ¡)
+// count as Spanish hits.
+func DetectLanguage(text string) string {
+	text = strings.ToLower(text)
+	hits := map[string]int{LanguageSpanish: strings.Count(text, "¿") + strings.Count(text, "¡")}
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if strings.ContainsRune(word, 'ñ') {
+			hits[LanguageSpanish]++
+		}
+		for lang, stopwords := range languageStopwords {
+			if _, ok := stopwords[word]; ok {
+				hits[lang]++
+			}
+		}
+	}
+
+	es, en := hits[LanguageSpanish], hits[LanguageEnglish]
+	switch {
+	case es >= minLanguageHits && es > en:
+		return LanguageSpanish
+	case en >= minLanguageHits && en > es:
+		return LanguageEnglish
+	default:
+		return ""
+	}
+}
+
+func wordSet(words string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, w := range strings.Fields(words) {
+		set[w] = struct{}{}
+	}
+	return set
+}
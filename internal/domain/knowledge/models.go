@@ -23,6 +23,7 @@ const (
 	SourceTypeTicket    SourceType = "ticket"
 	SourceTypeKBArticle SourceType = "kb_article"
 	SourceTypeAPI       SourceType = "api"
+	SourceTypeURL       SourceType = "url"
 	SourceTypeOther     SourceType = "other"
 )
 
@@ -35,6 +36,15 @@ const (
 	EmbeddingStatusFailed   EmbeddingStatus = "failed"
 )
 
+// IngestOutcome reports what IngestService.Ingest did with an item.
+type IngestOutcome string
+
+const (
+	IngestOutcomeCreated   IngestOutcome = "created"
+	IngestOutcomeUpdated   IngestOutcome = "updated"
+	IngestOutcomeUnchanged IngestOutcome = "unchanged"
+)
+
 // EvidenceMethod identifies how a piece of evidence was retrieved (Task 2.5/2.6).
 type EvidenceMethod string
 
@@ -72,9 +82,16 @@ type KnowledgeItem struct {
 	EntityType        *string
 	EntityID          *string
 	Metadata          *string
+	Language          *string // detected at ingest; nil when undetected
+	ContentHash       *string // hash of the ingested fields; nil for items ingested before hashing
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 	DeletedAt         *time.Time
+	// Outcome is set on items returned by IngestService.Ingest only.
+	Outcome IngestOutcome
+	// WasDuplicate is set when Ingest returned an existing item instead of
+	// creating one (see CreateKnowledgeItemInput.DedupOnIngest).
+	WasDuplicate bool
 }
 
 // IsDeleted returns true if the knowledge item has been soft-deleted.
@@ -100,8 +117,12 @@ type EmbeddingDocument struct {
 	ChunkText       string
 	TokenCount      *int64
 	EmbeddingStatus EmbeddingStatus
-	EmbeddedAt      *time.Time
-	CreatedAt       time.Time
+	// Metadata tags the chunk, e.g. {"section": "faq_answer"}; see
+	// CreateKnowledgeItemInput.ChunkMetadata, CreateKnowledgeItemInput.Sections
+	// and SearchInput.MetadataFilters.
+	Metadata   map[string]string
+	EmbeddedAt *time.Time
+	CreatedAt  time.Time
 }
 
 // IsPending returns true if the chunk has not yet been embedded.
@@ -179,6 +200,25 @@ type CreateKnowledgeItemInput struct {
 	EntityType        *string
 	EntityID          *string
 	Metadata          *string
+	// DedupOnIngest makes Ingest return an existing workspace item with the
+	// same normalized content instead of creating a new one. It does not
+	// apply when the input matches an item by entity or source object.
+	DedupOnIngest bool
+	// ChunkMetadata is stored on every chunk of the item, so searches can
+	// keep only chunks with given tags with SearchInput.MetadataFilters.
+	ChunkMetadata map[string]string
+	// Sections splits the content into parts chunked on their own, whose
+	// chunks carry the section's Metadata over ChunkMetadata, e.g. a
+	// "summary" and a "body". When set, RawContent must be empty; Ingest
+	// stores the section texts joined by blank lines as RawContent.
+	Sections []ContentSection
+}
+
+// ContentSection is one part of a knowledge item's content; see
+// CreateKnowledgeItemInput.Sections.
+type ContentSection struct {
+	Text     string
+	Metadata map[string]string
 }
 
 // CreateEmbeddingDocumentInput carries the fields required to create a new chunk.
@@ -208,4 +248,7 @@ type BuildEvidencePackInput struct {
 	EntityType  string
 	EntityID    string
 	Limit       int // 0 uses default (10), capped at 50
+	// RunID, when set, stores the built pack for that agent run so it can be
+	// read back with GetEvidencePackForRun.
+	RunID string
 }
@@ -0,0 +1,160 @@
+// Package knowledge — normalization of raw content before indexing.
+// IngestService stores RawContent untouched and derives normalized_content
+// (the FTS-indexed text) and the embedded chunks through the Normalizer
+// registered for the item's SourceType.
+package knowledge
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Normalizer turns the raw content of a knowledge item into the plain text
+// that is indexed for search and embedding.
+type Normalizer interface {
+	Normalize(raw string) string
+}
+
+// NormalizerFunc adapts a plain function to the Normalizer interface.
+type NormalizerFunc func(raw string) string
+
+// Normalize calls f(raw).
+func (f NormalizerFunc) Normalize(raw string) string {
+	return f(raw)
+}
+
+// PassthroughNormalizer keeps the content as-is, only trimming surrounding
+// whitespace. It is used for source types without a registered normalizer.
+type PassthroughNormalizer struct{}
+
+// Normalize returns raw without leading and trailing whitespace.
+func (PassthroughNormalizer) Normalize(raw string) string {
+	return strings.TrimSpace(raw)
+}
+
+// HTMLNormalizer reduces an HTML fragment or document to its readable text:
+// tags are dropped, entities decoded, and script, style and other
+// boilerplate elements skipped. Block elements become line breaks.
+type HTMLNormalizer struct{}
+
+// Normalize returns the readable text of raw. Input that fails to parse is
+// returned trimmed.
+func (HTMLNormalizer) Normalize(raw string) string {
+	root, err := html.Parse(strings.NewReader(raw))
+	if err != nil {
+		return strings.TrimSpace(raw)
+	}
+	var sb strings.Builder
+	writeReadableText(&sb, root)
+	return collapseLines(sb.String())
+}
+
+var (
+	mdFence    = regexp.MustCompile("^\\s*(```|~~~)")
+	mdRule     = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+	mdHeading  = regexp.MustCompile(`^\s{0,3}#{1,6}\s+`)
+	mdQuote    = regexp.MustCompile(`^\s*(>\s?)+`)
+	mdListItem = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s+`)
+	mdImage    = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink     = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	// Emphasis needs the same delimiter on both sides. Underscores only
+	// delimit at word boundaries, so snake_case identifiers are kept; the
+	// boundary characters are captured since RE2 has no lookaround.
+	mdStrong             = regexp.MustCompile(`\*\*([^*\s](?:[^*]*[^*\s])?)\*\*`)
+	mdItalic             = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*`)
+	mdStrikethrough      = regexp.MustCompile(`~~([^~\s](?:[^~]*[^~\s])?)~~`)
+	mdUnderscoreStrong   = regexp.MustCompile(`(^|[^\p{L}\p{N}_])__([^_\s](?:[^_]*[^_\s])?)__($|[^\p{L}\p{N}_])`)
+	mdUnderscoreEmphasis = regexp.MustCompile(`(^|[^\p{L}\p{N}_])_([^_\s](?:[^_]*[^_\s])?)_($|[^\p{L}\p{N}_])`)
+	mdInlineCode         = regexp.MustCompile("`([^`]*)`")
+	mdInlineTag          = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+)
+
+// MarkdownNormalizer reduces Markdown to plain text: headings, quotes and
+// list markers, emphasis, inline code and HTML tags are stripped; links and
+// images keep their text; fenced code blocks keep their content.
+type MarkdownNormalizer struct{}
+
+// Normalize returns the plain text of the Markdown document raw.
+func (MarkdownNormalizer) Normalize(raw string) string {
+	lines := strings.Split(raw, "\n")
+	out := lines[:0]
+	inFence := false
+	for _, line := range lines {
+		if mdFence.MatchString(line) {
+			inFence = !inFence
+			continue
+		}
+		if !inFence {
+			line = normalizeMarkdownLine(line)
+		}
+		out = append(out, line)
+	}
+	return collapseLines(strings.Join(out, "\n"))
+}
+
+func normalizeMarkdownLine(line string) string {
+	if mdRule.MatchString(line) {
+		return ""
+	}
+	line = mdHeading.ReplaceAllString(line, "")
+	line = mdQuote.ReplaceAllString(line, "")
+	line = mdListItem.ReplaceAllString(line, "")
+	line = mdImage.ReplaceAllString(line, "$1")
+	line = mdLink.ReplaceAllString(line, "$1")
+	line = mdInlineCode.ReplaceAllString(line, "$1")
+	line = mdStrong.ReplaceAllString(line, "$1")
+	line = mdItalic.ReplaceAllString(line, "$1")
+	line = mdStrikethrough.ReplaceAllString(line, "$1")
+	line = replaceAtBoundaries(mdUnderscoreStrong, line)
+	line = replaceAtBoundaries(mdUnderscoreEmphasis, line)
+	line = mdInlineTag.ReplaceAllString(line, "")
+	return html.UnescapeString(line)
+}
+
+// replaceAtBoundaries strips the delimiters matched by re, keeping the
+// boundary characters around them. A match consumes its trailing boundary,
+// so the line is rescanned until nothing changes to catch adjacent spans.
+func replaceAtBoundaries(re *regexp.Regexp, line string) string {
+	for {
+		next := re.ReplaceAllString(line, "$1$2$3")
+		if next == line {
+			return line
+		}
+		line = next
+	}
+}
+
+// defaultNormalizers maps the source types whose content is usually rich
+// text to their normalizer; the rest fall back to PassthroughNormalizer.
+func defaultNormalizers() map[SourceType]Normalizer {
+	return map[SourceType]Normalizer{
+		SourceTypeEmail:     HTMLNormalizer{},
+		SourceTypeKBArticle: MarkdownNormalizer{},
+	}
+}
+
+// RegisterNormalizer sets the Normalizer used for items of sourceType,
+// replacing any previous one. A nil n restores the passthrough behaviour.
+// It is not safe to call concurrently with Ingest.
+func (s *IngestService) RegisterNormalizer(sourceType SourceType, n Normalizer) {
+	if n == nil {
+		delete(s.normalizers, sourceType)
+		return
+	}
+	s.normalizers[sourceType] = n
+}
+
+// normalize returns the indexed text of input.RawContent.
+func (s *IngestService) normalize(input CreateKnowledgeItemInput) string {
+	return s.normalizeText(input.SourceType, input.RawContent)
+}
+
+// normalizeText returns the indexed text of raw content of sourceType.
+func (s *IngestService) normalizeText(sourceType SourceType, raw string) string {
+	if n, ok := s.normalizers[sourceType]; ok {
+		return strings.TrimSpace(n.Normalize(raw))
+	}
+	return PassthroughNormalizer{}.Normalize(raw)
+}
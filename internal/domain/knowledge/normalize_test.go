@@ -0,0 +1,113 @@
+package knowledge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func TestHTMLNormalizer_StripsTagsAndBoilerplate(t *testing.T) {
+	raw := `<html><head><style>p{color:red}</style></head><body>
+<h1>Refund policy</h1><p>Refunds take <b>5&nbsp;days</b> &amp; need a receipt.</p>
+<script>track()</script></body></html>`
+
+	got := HTMLNormalizer{}.Normalize(raw)
+	want := "Refund policy\nRefunds take 5 days & need a receipt."
+	if got != want {
+		t.Fatalf("Normalize() = %q; want %q", got, want)
+	}
+}
+
+func TestMarkdownNormalizer_StripsSyntax(t *testing.T) {
+	raw := "# Setup\n\n> **Note:** read the [install guide](https://example.com/install) first.\n\n" +
+		"- Run `make build`\n- ![diagram](d.png) shows _the_ flow\n\n---\n```sh\nmake test\n```\n"
+
+	got := MarkdownNormalizer{}.Normalize(raw)
+	want := "Setup\nNote: read the install guide first.\nRun make build\ndiagram shows the flow\nmake test"
+	if got != want {
+		t.Fatalf("Normalize() = %q; want %q", got, want)
+	}
+}
+
+func TestMarkdownNormalizer_KeepsSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"Set user_id and account_id in the request": "Set user_id and account_id in the request",
+		"Call `get_lead` then read my__init__file":  "Call get_lead then read my__init__file",
+		"_one_ _two_ and __bold__, *a* and **b**":   "one two and bold, a and b",
+		"Mixed *delimiters_ stay":                   "Mixed *delimiters_ stay",
+	}
+	for raw, want := range cases {
+		if got := (MarkdownNormalizer{}).Normalize(raw); got != want {
+			t.Errorf("Normalize(%q) = %q; want %q", raw, got, want)
+		}
+	}
+}
+
+func TestIngestService_HTMLEmail_IndexesTextKeepsRawHTML(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewIngestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+	raw := `<div class="msg"><p>The <strong>invoice</strong> was charged twice.</p></div>`
+
+	item, err := svc.Ingest(context.Background(), CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeEmail,
+		Title:       "Billing issue",
+		RawContent:  raw,
+	})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+
+	var storedRaw, normalized string
+	if err = db.QueryRow(`SELECT raw_content, normalized_content FROM knowledge_item WHERE id = ?`, item.ID).
+		Scan(&storedRaw, &normalized); err != nil {
+		t.Fatalf("load knowledge_item: %v", err)
+	}
+	if storedRaw != raw {
+		t.Errorf("raw_content = %q; want the original HTML", storedRaw)
+	}
+	if normalized != "The invoice was charged twice." {
+		t.Errorf("normalized_content = %q", normalized)
+	}
+
+	if n := countRows(t, db, `SELECT COUNT(*) FROM knowledge_item_fts WHERE knowledge_item_fts MATCH 'invoice' AND id = ?`, item.ID); n != 1 {
+		t.Errorf("FTS match on text = %d; want 1", n)
+	}
+	if n := countRows(t, db, `SELECT COUNT(*) FROM knowledge_item_fts WHERE knowledge_item_fts MATCH 'strong OR msg' AND id = ?`, item.ID); n != 0 {
+		t.Errorf("FTS match on markup = %d; want 0", n)
+	}
+
+	var chunk string
+	if err = db.QueryRow(`SELECT chunk_text FROM embedding_document WHERE knowledge_item_id = ?`, item.ID).Scan(&chunk); err != nil {
+		t.Fatalf("load chunk: %v", err)
+	}
+	if chunk != "The invoice was charged twice." {
+		t.Errorf("chunk_text = %q", chunk)
+	}
+}
+
+func TestIngestService_RegisterNormalizer_OverridesSourceType(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewIngestService(db, eventbus.New())
+	svc.RegisterNormalizer(SourceTypeDocument, HTMLNormalizer{})
+	wsID := createWorkspace(t, db)
+
+	item, err := svc.Ingest(context.Background(), CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeDocument,
+		Title:       "Exported page",
+		RawContent:  "<p>Hello <em>world</em></p>",
+	})
+	if err != nil {
+		t.Fatalf("Ingest failed: %v", err)
+	}
+	if item.NormalizedContent == nil || *item.NormalizedContent != "Hello world" {
+		t.Fatalf("NormalizedContent = %v; want %q", item.NormalizedContent, "Hello world")
+	}
+}
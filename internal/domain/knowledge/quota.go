@@ -0,0 +1,128 @@
+// Package knowledge — per-workspace ingestion quotas.
+package knowledge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrQuotaExceeded is returned by Ingest when storing the item would take the
+// workspace past its configured knowledge item count or byte limit.
+var ErrQuotaExceeded = errors.New("knowledge quota exceeded")
+
+// Quota is a workspace's ingestion limit, read from workspace.settings
+// ({"knowledge_quota": {"max_items": N, "max_bytes": M}}). Zero means no limit.
+type Quota struct {
+	MaxItems int64
+	MaxBytes int64
+}
+
+// Usage is what a workspace currently stores: live knowledge items and the
+// byte size of their raw content.
+type Usage struct {
+	Items int64
+	Bytes int64
+}
+
+// usageDelta is the change one ingest or delete makes to Usage.
+type usageDelta struct {
+	items int64
+	bytes int64
+}
+
+// GetUsage returns the workspace's tracked knowledge usage.
+func (s *IngestService) GetUsage(ctx context.Context, workspaceID string) (Usage, error) {
+	return loadUsage(ctx, s.db, workspaceID)
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func loadQuota(ctx context.Context, q queryRower, workspaceID string) (Quota, error) {
+	var quota Quota
+	err := q.QueryRowContext(ctx, `
+		SELECT COALESCE(json_extract(settings, '$.knowledge_quota.max_items'), 0),
+		       COALESCE(json_extract(settings, '$.knowledge_quota.max_bytes'), 0)
+		FROM workspace
+		WHERE id = ? AND json_valid(settings)`,
+		workspaceID,
+	).Scan(&quota.MaxItems, &quota.MaxBytes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Quota{}, nil
+	}
+	if err != nil {
+		return Quota{}, fmt.Errorf("load knowledge quota: %w", err)
+	}
+	return quota, nil
+}
+
+func loadUsage(ctx context.Context, q queryRower, workspaceID string) (Usage, error) {
+	var usage Usage
+	err := q.QueryRowContext(ctx,
+		`SELECT knowledge_items, knowledge_bytes FROM workspace_usage WHERE workspace_id = ?`,
+		workspaceID,
+	).Scan(&usage.Items, &usage.Bytes)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Usage{}, nil
+	}
+	if err != nil {
+		return Usage{}, fmt.Errorf("load workspace usage: %w", err)
+	}
+	return usage, nil
+}
+
+// checkQuota rejects a delta that grows usage past a configured limit.
+// Deltas that do not grow a metric pass, so an over-quota workspace can
+// still shrink or delete content.
+func checkQuota(ctx context.Context, tx *sql.Tx, workspaceID string, delta usageDelta) error {
+	if delta.items <= 0 && delta.bytes <= 0 {
+		return nil
+	}
+	quota, err := loadQuota(ctx, tx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if quota.MaxItems <= 0 && quota.MaxBytes <= 0 {
+		return nil
+	}
+	usage, err := loadUsage(ctx, tx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if delta.items > 0 && quota.MaxItems > 0 && usage.Items+delta.items > quota.MaxItems {
+		return fmt.Errorf("%w: %d of %d items used", ErrQuotaExceeded, usage.Items, quota.MaxItems)
+	}
+	if delta.bytes > 0 && quota.MaxBytes > 0 && usage.Bytes+delta.bytes > quota.MaxBytes {
+		return fmt.Errorf("%w: %d of %d bytes used, item needs %d more", ErrQuotaExceeded, usage.Bytes, quota.MaxBytes, delta.bytes)
+	}
+	return nil
+}
+
+// applyUsageDelta adds delta to the workspace's usage row, creating it on
+// first use. Counters are clamped at zero.
+func applyUsageDelta(ctx context.Context, tx *sql.Tx, workspaceID string, delta usageDelta, now time.Time) error {
+	if delta.items == 0 && delta.bytes == 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT OR IGNORE INTO workspace_usage (workspace_id, updated_at) VALUES (?, ?)`,
+		workspaceID, now,
+	); err != nil {
+		return fmt.Errorf("create workspace usage: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE workspace_usage
+		SET knowledge_items = MAX(knowledge_items + ?, 0),
+		    knowledge_bytes = MAX(knowledge_bytes + ?, 0),
+		    updated_at = ?
+		WHERE workspace_id = ?`,
+		delta.items, delta.bytes, now, workspaceID,
+	); err != nil {
+		return fmt.Errorf("update workspace usage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Traces: FR-090
+package knowledge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+func setKnowledgeQuota(t *testing.T, db *sql.DB, wsID, quotaJSON string) {
+	t.Helper()
+	if _, err := db.Exec(`UPDATE workspace SET settings = ? WHERE id = ?`, `{"knowledge_quota":`+quotaJSON+`}`, wsID); err != nil {
+		t.Fatalf("set knowledge quota: %v", err)
+	}
+}
+
+func ingestNote(svc *IngestService, wsID, title, content string) (*KnowledgeItem, error) {
+	return svc.Ingest(context.Background(), CreateKnowledgeItemInput{
+		WorkspaceID: wsID,
+		SourceType:  SourceTypeNote,
+		Title:       title,
+		RawContent:  content,
+	})
+}
+
+func TestIngestService_ItemQuota_BlocksIngestWhenFull(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewIngestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+	otherWS := createWorkspace(t, db)
+	setKnowledgeQuota(t, db, wsID, `{"max_items": 2}`)
+
+	first, err := ingestNote(svc, wsID, "One", "first note")
+	if err != nil {
+		t.Fatalf("Ingest #1: %v", err)
+	}
+	if _, err = ingestNote(svc, wsID, "Two", "second note"); err != nil {
+		t.Fatalf("Ingest #2: %v", err)
+	}
+	if _, err = ingestNote(svc, wsID, "Three", "third note"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Ingest #3 err = %v; want ErrQuotaExceeded", err)
+	}
+	var items int
+	if err = db.QueryRow(`SELECT COUNT(*) FROM knowledge_item WHERE workspace_id = ?`, wsID).Scan(&items); err != nil {
+		t.Fatalf("count items: %v", err)
+	}
+	if items != 2 {
+		t.Fatalf("items = %d; want 2 (rejected ingest must not persist)", items)
+	}
+
+	if _, err = ingestNote(svc, otherWS, "Elsewhere", "other workspace note"); err != nil {
+		t.Fatalf("other workspace is not limited: %v", err)
+	}
+
+	if err = svc.Delete(context.Background(), wsID, first.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err = ingestNote(svc, wsID, "Three", "third note"); err != nil {
+		t.Fatalf("Ingest after delete freed a slot: %v", err)
+	}
+}
+
+func TestIngestService_ByteQuota_CountsGrowthOfUpdates(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewIngestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+	setKnowledgeQuota(t, db, wsID, `{"max_bytes": 20}`)
+
+	entityType, entityID := "account", "acc-1"
+	ingest := func(content string) error {
+		_, err := svc.Ingest(context.Background(), CreateKnowledgeItemInput{
+			WorkspaceID: wsID,
+			SourceType:  SourceTypeNote,
+			Title:       "Account note",
+			RawContent:  content,
+			EntityType:  &entityType,
+			EntityID:    &entityID,
+		})
+		return err
+	}
+
+	if err := ingest("0123456789"); err != nil {
+		t.Fatalf("Ingest: %v", err)
+	}
+	if err := ingest("0123456789012345"); err != nil {
+		t.Fatalf("re-ingest within quota: %v", err)
+	}
+	if err := ingest("012345678901234567890"); !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("re-ingest over quota err = %v; want ErrQuotaExceeded", err)
+	}
+
+	usage, err := svc.GetUsage(context.Background(), wsID)
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	if usage != (Usage{Items: 1, Bytes: 16}) {
+		t.Fatalf("usage = %+v; want {Items:1 Bytes:16}", usage)
+	}
+}
@@ -325,7 +325,7 @@ func (s *ReindexService) applyChange(ctx context.Context, evt RecordChangedEvent
 		}
 		return s.handleDelete(ctx, *item)
 	}
-	return s.handleUpsert(ctx, evt, item)
+	return s.handleUpsert(ctx, evt)
 }
 
 func (s *ReindexService) handleDelete(ctx context.Context, item sqlcgen.KnowledgeItem) error {
@@ -354,21 +354,14 @@ func (s *ReindexService) handleDelete(ctx context.Context, item sqlcgen.Knowledg
 	return nil
 }
 
-func (s *ReindexService) handleUpsert(ctx context.Context, evt RecordChangedEvent, item *sqlcgen.KnowledgeItem) error {
+func (s *ReindexService) handleUpsert(ctx context.Context, evt RecordChangedEvent) error {
 	title, rawContent, sourceType, buildErr := s.buildKnowledgePayloadFromEntity(ctx, evt)
 	if buildErr != nil {
 		return buildErr
 	}
 
-	if item != nil {
-		if delErr := s.q.DeleteVecEmbeddingsByKnowledgeItem(ctx, sqlcgen.DeleteVecEmbeddingsByKnowledgeItemParams{
-			KnowledgeItemID: item.ID,
-			WorkspaceID:     item.WorkspaceID,
-		}); delErr != nil {
-			return fmt.Errorf("delete stale knowledge vectors: %w", delErr)
-		}
-	}
-
+	// Ingest replaces the stale chunks and vectors of a changed item and
+	// leaves an unchanged one alone.
 	_, ingestErr := s.ingest.Ingest(ctx, CreateKnowledgeItemInput{
 		WorkspaceID: evt.WorkspaceID,
 		SourceType:  sourceType,
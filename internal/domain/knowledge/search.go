@@ -7,6 +7,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"sort"
@@ -21,30 +22,74 @@ const (
 	rrfK         = 60 // RRF constant — industry standard
 	defaultLimit = 20 // default search result limit
 	maxLimit     = 50 // maximum search result limit
+
+	// vectorChunkOverfetch widens the vector candidate list so that
+	// collapsing chunks to items still leaves about limit distinct items.
+	vectorChunkOverfetch = 3
 )
 
+// MaxSearchOffset is the largest SearchInput.Offset HybridSearch accepts.
+const MaxSearchOffset = 500
+
+// ErrOffsetOutOfRange is returned by HybridSearch for an offset past
+// MaxSearchOffset, which would otherwise be clamped to a page the caller did
+// not ask for.
+var ErrOffsetOutOfRange = fmt.Errorf("offset must be at most %d", MaxSearchOffset)
+
+// ErrEntityScopeRequired is returned by SearchForEntity when the entity type
+// or id is empty, which would otherwise widen the search to the workspace.
+var ErrEntityScopeRequired = errors.New("entity type and id are required")
+
 // SearchInput carries parameters for a hybrid search query.
 type SearchInput struct {
 	Query       string
 	WorkspaceID string
 	EntityType  string
 	EntityID    string
-	Limit       int // 0 → defaultLimit, capped at maxLimit
+	// Language restricts results to items detected in this language ("es",
+	// "en"). Items whose language could not be detected always match.
+	Language string
+	Limit    int // 0 → defaultLimit, capped at maxLimit
+	// Offset skips that many fused results, for paging. Negative → 0;
+	// above MaxSearchOffset is ErrOffsetOutOfRange.
+	Offset int
+	// MetadataFilters keeps only chunks whose metadata has each key with
+	// the given value (see CreateKnowledgeItemInput.ChunkMetadata). Vector
+	// candidates are filtered chunk by chunk; BM25 matches whole items, so
+	// it keeps items with at least one matching chunk.
+	MetadataFilters map[string]string
+	// Fuzzy retries BM25 with spell-corrected and prefix terms when the
+	// query matches nothing as typed, e.g. because of a typo.
+	Fuzzy bool
+	// SourceType restricts results to items of this source type.
+	SourceType SourceType
 }
 
 // SearchResult is a single ranked result from hybrid search.
+//
+// Score is the RRF score normalized to [0,1] against the best possible fused
+// score: 1 means rank 1 in both BM25 and vector search, and a document ranked
+// first by only one method scores about 0.5. It is comparable across queries
+// and is safe to use as a confidence.
 type SearchResult struct {
 	KnowledgeItemID string
 	Title           string
 	Snippet         string
 	Score           float64
 	Method          EvidenceMethod // bm25, vector, or hybrid
+	// Highlights locate the query terms in Snippet.
+	Highlights []Highlight
 }
 
 // SearchResults is the response from HybridSearch.
 type SearchResults struct {
 	Items []SearchResult
 	Query string
+	// TotalCandidates is the number of distinct items fused for the page,
+	// i.e. at most Offset+Limit+1.
+	TotalCandidates int
+	// HasMore reports whether a result exists past this page.
+	HasMore bool
 }
 
 type rrfDocInfo struct {
@@ -55,17 +100,19 @@ type rrfDocInfo struct {
 
 // SearchService implements hybrid search (Task 2.5).
 type SearchService struct {
-	db  *sql.DB
-	q   *sqlcgen.Queries
-	llm llm.LLMProvider
+	db            *sql.DB
+	q             *sqlcgen.Queries
+	llm           llm.LLMProvider
+	feedbackBoost float64
 }
 
 // NewSearchService creates a SearchService backed by the given DB and LLM provider.
 func NewSearchService(db *sql.DB, provider llm.LLMProvider) *SearchService {
 	return &SearchService{
-		db:  db,
-		q:   sqlcgen.New(db),
-		llm: provider,
+		db:            db,
+		q:             sqlcgen.New(db),
+		llm:           provider,
+		feedbackBoost: DefaultFeedbackBoost,
 	}
 }
 
@@ -73,9 +120,21 @@ func NewSearchService(db *sql.DB, provider llm.LLMProvider) *SearchService {
 // BM25 (FTS5) and LLM.Embed() run concurrently to overlap Ollama RTT with DB query.
 // Graceful degradation: if LLM.Embed() fails, returns BM25-only results without error.
 // Task 2.5 audit: switched from sequential to parallel execution.
+//
+// Paging fetches Offset+Limit+1 candidates per method and slices the fused
+// ranking, so page N is stable across identical queries as long as the
+// index does not change.
+//
+// Items with positive feedback for the query (see RecordFeedback) get a
+// bounded bonus before ranking. Failing to load feedback only drops it.
 func (s *SearchService) HybridSearch(ctx context.Context, input SearchInput) (*SearchResults, error) {
-	limit := resolveLimit(input.Limit)
-	entityType, entityID := resolveEntityScope(input.Query, input.EntityType, input.EntityID)
+	if input.Offset > MaxSearchOffset {
+		return nil, ErrOffsetOutOfRange
+	}
+	limit := ResolveLimit(input.Limit)
+	offset := max(input.Offset, 0)
+	window := offset + limit + 1
+	scope := newSearchScope(input)
 
 	var (
 		bm25Results []bm25Row
@@ -90,7 +149,7 @@ func (s *SearchService) HybridSearch(ctx context.Context, input SearchInput) (*S
 	// Goroutine 1: BM25 search via FTS5 (always available, no LLM required)
 	go func() {
 		defer wg.Done()
-		res, err := s.bm25Search(ctx, input.Query, input.WorkspaceID, entityType, entityID, limit)
+		res, err := s.bm25Search(ctx, input.Query, scope, window)
 		mu.Lock()
 		bm25Results, bm25Err = res, err
 		mu.Unlock()
@@ -99,17 +158,83 @@ func (s *SearchService) HybridSearch(ctx context.Context, input SearchInput) (*S
 	// Goroutine 2: vector search — degrade gracefully if LLM embed fails
 	go func() {
 		defer wg.Done()
-		vecResults = s.vectorSearchWithFallback(ctx, input.Query, input.WorkspaceID, entityType, entityID, limit)
+		vecResults = s.vectorSearchWithFallback(ctx, input.Query, scope, window*vectorChunkOverfetch)
 	}()
 
 	wg.Wait()
 
+	if bm25Err == nil && input.Fuzzy && len(bm25Results) == 0 {
+		bm25Results, bm25Err = s.fuzzyBM25Search(ctx, input.Query, scope, window)
+	}
 	if bm25Err != nil {
 		return nil, fmt.Errorf("search: bm25: %w", bm25Err)
 	}
 
-	items := rrfMerge(bm25Results, vecResults, limit)
-	return &SearchResults{Items: items, Query: input.Query}, nil
+	boosts, boostErr := s.feedbackBoosts(ctx, input.WorkspaceID, input.Query)
+	if boostErr != nil {
+		boosts = nil // graceful degradation
+	}
+	fused := rrfMerge(bm25Results, vecResults, boosts, window)
+	page := fused[min(offset, len(fused)):min(offset+limit, len(fused))]
+	for i := range page {
+		page[i].Highlights = HighlightTerms(page[i].Snippet, input.Query)
+	}
+	return &SearchResults{
+		Items:           page,
+		Query:           input.Query,
+		TotalCandidates: len(fused),
+		HasMore:         len(fused) > offset+limit,
+	}, nil
+}
+
+// SearchForEntity runs HybridSearch over the knowledge items linked to one
+// CRM entity only, e.g. a case's attachments. Both the BM25 and the vector
+// candidates are filtered in SQL, inside the workspace.
+func (s *SearchService) SearchForEntity(ctx context.Context, workspaceID, entityType, entityID, query string) (*SearchResults, error) {
+	entityType = strings.TrimSpace(entityType)
+	entityID = strings.TrimSpace(entityID)
+	if entityType == "" || entityID == "" {
+		return nil, ErrEntityScopeRequired
+	}
+	return s.HybridSearch(ctx, SearchInput{
+		Query:       query,
+		WorkspaceID: workspaceID,
+		EntityType:  entityType,
+		EntityID:    entityID,
+	})
+}
+
+// searchScope holds the filters shared by the BM25 and vector candidates.
+type searchScope struct {
+	workspaceID string
+	entityType  string
+	entityID    string
+	language    string
+	sourceType  string
+	metadata    map[string]string
+}
+
+func newSearchScope(input SearchInput) searchScope {
+	entityType, entityID := resolveEntityScope(input.Query, input.EntityType, input.EntityID)
+	return searchScope{
+		workspaceID: input.WorkspaceID,
+		entityType:  entityType,
+		entityID:    entityID,
+		language:    strings.TrimSpace(input.Language),
+		sourceType:  strings.TrimSpace(string(input.SourceType)),
+		metadata:    input.MetadataFilters,
+	}
+}
+
+// itemSQL returns the " AND ..." conditions, and their arguments, that the
+// knowledge_item aliased ki must meet. Empty filters match every item.
+func (sc searchScope) itemSQL() (string, []any) {
+	return `
+		  AND (? = '' OR ki.entity_type = ?)
+		  AND (? = '' OR ki.entity_id = ?)
+		  AND (? = '' OR ki.language IS NULL OR ki.language = ?)
+		  AND (? = '' OR ki.source_type = ?)`,
+		[]any{sc.entityType, sc.entityType, sc.entityID, sc.entityID, sc.language, sc.language, sc.sourceType, sc.sourceType}
 }
 
 func resolveEntityScope(query, entityType, entityID string) (string, string) {
@@ -136,12 +261,12 @@ func assignScopeValue(currentValue, token, prefix string) string {
 
 // vectorSearchWithFallback embeds the query and runs vector search.
 // Returns empty slice on LLM failure (caller falls back to BM25-only).
-func (s *SearchService) vectorSearchWithFallback(ctx context.Context, query, wsID, entityType, entityID string, limit int) []vectorRow {
+func (s *SearchService) vectorSearchWithFallback(ctx context.Context, query string, scope searchScope, limit int) []vectorRow {
 	resp, err := s.llm.Embed(ctx, llm.EmbedRequest{Texts: []string{query}})
 	if err != nil || len(resp.Embeddings) == 0 {
 		return nil // graceful degradation
 	}
-	results, err := s.vectorSearch(ctx, wsID, entityType, entityID, resp.Embeddings[0], limit)
+	results, err := s.vectorSearch(ctx, scope, resp.Embeddings[0], limit)
 	if err != nil {
 		return nil // graceful degradation
 	}
@@ -159,22 +284,35 @@ type bm25Row struct {
 // bm25Search executes FTS5 MATCH and returns results ordered by BM25 score.
 // Note: FTS5 bm25() returns negative values (lower = better match).
 // Raw SQL used because sqlc does not support CREATE VIRTUAL TABLE fts5 syntax.
-func (s *SearchService) bm25Search(ctx context.Context, query, wsID, entityType, entityID string, limit int) ([]bm25Row, error) {
-	const ftsQuery = `
+// With metadata filters only items with a live chunk matching them are kept.
+func (s *SearchService) bm25Search(ctx context.Context, query string, scope searchScope, limit int) ([]bm25Row, error) {
+	itemSQL, itemArgs := scope.itemSQL()
+	ftsQuery := `
 		SELECT ki.id, ki.title,
-		       snippet(knowledge_item_fts, 2, '', '', '...', 32) AS snippet,
+		       snippet(knowledge_item_fts, 3, '', '', '...', 32) AS snippet,
 		       bm25(knowledge_item_fts) AS score
 		FROM knowledge_item_fts
 		JOIN knowledge_item ki ON ki.id = knowledge_item_fts.id
 		WHERE knowledge_item_fts MATCH ?
 		  AND knowledge_item_fts.workspace_id = ?
-		  AND ki.deleted_at IS NULL
-		  AND (? = '' OR ki.entity_type = ?)
-		  AND (? = '' OR ki.entity_id = ?)
-		ORDER BY bm25(knowledge_item_fts)
+		  AND ki.deleted_at IS NULL` + itemSQL
+	args := append([]any{query, scope.workspaceID}, itemArgs...)
+	if len(scope.metadata) > 0 {
+		filterSQL, filterArgs := metadataFilterSQL("ed", scope.metadata)
+		ftsQuery += `
+		  AND EXISTS (
+		      SELECT 1 FROM embedding_document ed
+		      WHERE ed.knowledge_item_id = ki.id
+		        AND ed.workspace_id = ki.workspace_id
+		        AND ed.deleted_at IS NULL` + filterSQL + `)`
+		args = append(args, filterArgs...)
+	}
+	ftsQuery += `
+		ORDER BY bm25(knowledge_item_fts), ki.id
 		LIMIT ?`
+	args = append(args, limit)
 
-	rows, err := s.db.QueryContext(ctx, ftsQuery, query, wsID, entityType, entityType, entityID, entityID, limit)
+	rows, err := s.db.QueryContext(ctx, ftsQuery, args...)
 	if err != nil {
 		// FTS5 MATCH with invalid syntax returns an error — treat as no results
 		return nil, nil //nolint:nilerr
@@ -206,13 +344,16 @@ type vectorRow struct {
 
 // vectorSearch executes similarity ranking inside SQLite using the persisted
 // vector store. This removes the previous Go-side full scan over all vectors.
-func (s *SearchService) vectorSearch(ctx context.Context, wsID, entityType, entityID string, queryVec []float32, limit int) ([]vectorRow, error) {
+// Metadata filters restrict the candidate chunks in SQL.
+func (s *SearchService) vectorSearch(ctx context.Context, scope searchScope, queryVec []float32, limit int) ([]vectorRow, error) {
 	queryJSON, err := encodeEmbedding(queryVec)
 	if err != nil {
 		return nil, fmt.Errorf("vectorSearch encode query: %w", err)
 	}
 
-	const vectorQuery = `
+	itemSQL, itemArgs := scope.itemSQL()
+	filterSQL, filterArgs := metadataFilterSQL("ed", scope.metadata)
+	vectorQuery := `
 		SELECT v.id, ed.knowledge_item_id, ki.title, ed.chunk_text,
 		       cosine_similarity_json(v.embedding, ?) AS similarity
 		FROM vec_embedding v
@@ -220,15 +361,18 @@ func (s *SearchService) vectorSearch(ctx context.Context, wsID, entityType, enti
 		JOIN knowledge_item ki ON ki.id = ed.knowledge_item_id
 		WHERE ed.workspace_id = ?
 		  AND ed.embedding_status = 'embedded'
-		  AND ki.deleted_at IS NULL
-		  AND (? = '' OR ki.entity_type = ?)
-		  AND (? = '' OR ki.entity_id = ?)
+		  AND ed.deleted_at IS NULL
+		  AND ki.deleted_at IS NULL` + itemSQL + `
 		  AND json_valid(v.embedding)
-		  AND json_array_length(v.embedding) = json_array_length(?)
-		ORDER BY similarity DESC, ed.knowledge_item_id ASC
+		  AND json_array_length(v.embedding) = json_array_length(?)` + filterSQL + `
+		ORDER BY similarity DESC, ed.knowledge_item_id ASC, v.id ASC
 		LIMIT ?`
 
-	rows, err := s.db.QueryContext(ctx, vectorQuery, queryJSON, wsID, entityType, entityType, entityID, entityID, queryJSON, limit)
+	args := append([]any{queryJSON, scope.workspaceID}, itemArgs...)
+	args = append(args, queryJSON)
+	args = append(args, filterArgs...)
+	args = append(args, limit)
+	rows, err := s.db.QueryContext(ctx, vectorQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("vectorSearch query: %w", err)
 	}
@@ -254,9 +398,35 @@ func (s *SearchService) vectorSearch(ctx context.Context, wsID, entityType, enti
 	return results, nil
 }
 
+// metadataFilterSQL returns " AND ..." conditions, and their arguments,
+// requiring the metadata of the embedding_document aliased alias to hold each
+// filter key with its value. Keys are sorted so the SQL is stable; no filters
+// give an empty condition.
+func metadataFilterSQL(alias string, filters map[string]string) (string, []any) {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	args := make([]any, 0, 2*len(keys))
+	for _, key := range keys {
+		sb.WriteString(`
+		  AND EXISTS (SELECT 1 FROM json_each(` + alias + `.metadata) m WHERE m.key = ? AND m.value = ?)`)
+		args = append(args, key, filters[key])
+	}
+	return sb.String(), args
+}
+
 // rrfMerge combines BM25 and vector results via Reciprocal Rank Fusion (k=60).
 // Documents present in both lists get a higher combined score (hybrid method).
-func rrfMerge(bm25Results []bm25Row, vecResults []vectorRow, limit int) []SearchResult {
+// Vector rows are chunks, so they are first collapsed to the best-ranked chunk
+// per knowledge item: each item contributes one rank per method and appears
+// once in the results, however many of its chunks matched.
+// boosts adds a feedback bonus to the raw score of items already retrieved;
+// it never brings in items neither method found.
+func rrfMerge(bm25Results []bm25Row, vecResults []vectorRow, boosts map[string]float64, limit int) []SearchResult {
 	scores := make(map[string]float64)
 	docs := make(map[string]rrfDocInfo)
 
@@ -266,12 +436,18 @@ func rrfMerge(bm25Results []bm25Row, vecResults []vectorRow, limit int) []Search
 		docs[r.id] = rrfDocInfo{title: r.title, snippet: r.snippet, method: EvidenceMethodBM25}
 	}
 
-	// Vector ranks contribute to RRF score (keyed by knowledge_item_id for dedup)
-	for rank, r := range vecResults {
+	// Vector ranks contribute to RRF score (one rank per knowledge item)
+	for rank, r := range bestChunkPerItem(vecResults) {
 		scores[r.knowledgeItemID] += 1.0 / float64(rrfK+rank+1)
 		docs[r.knowledgeItemID] = mergeVectorDocInfo(docs[r.knowledgeItemID], r)
 	}
 
+	for id, bonus := range boosts {
+		if _, retrieved := scores[id]; retrieved {
+			scores[id] += bonus
+		}
+	}
+
 	type ranked struct {
 		id    string
 		score float64
@@ -280,7 +456,12 @@ func rrfMerge(bm25Results []bm25Row, vecResults []vectorRow, limit int) []Search
 	for id, score := range scores {
 		all = append(all, ranked{id: id, score: score})
 	}
-	sort.Slice(all, func(i, j int) bool { return all[i].score > all[j].score })
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].score != all[j].score {
+			return all[i].score > all[j].score
+		}
+		return all[i].id < all[j].id
+	})
 
 	results := make([]SearchResult, 0, min(limit, len(all)))
 	for i := 0; i < len(all) && i < limit; i++ {
@@ -290,13 +471,38 @@ func rrfMerge(bm25Results []bm25Row, vecResults []vectorRow, limit int) []Search
 			KnowledgeItemID: id,
 			Title:           info.title,
 			Snippet:         info.snippet,
-			Score:           all[i].score,
+			Score:           normalizeRRFScore(all[i].score),
 			Method:          info.method,
 		})
 	}
 	return results
 }
 
+// bestChunkPerItem keeps the first (highest-similarity) chunk of each
+// knowledge item, preserving order.
+func bestChunkPerItem(rows []vectorRow) []vectorRow {
+	seen := make(map[string]struct{}, len(rows))
+	out := make([]vectorRow, 0, len(rows))
+	for _, r := range rows {
+		if _, dup := seen[r.knowledgeItemID]; dup {
+			continue
+		}
+		seen[r.knowledgeItemID] = struct{}{}
+		out = append(out, r)
+	}
+	return out
+}
+
+// normalizeRRFScore maps a raw RRF score to [0,1]. Raw values are tiny
+// (~0.01-0.03 with k=60), so they are scaled against the theoretical max of
+// two retrieval methods (BM25 + vector) both ranking the document first.
+func normalizeRRFScore(raw float64) float64 {
+	if raw <= 0 {
+		return 0
+	}
+	return math.Min(1.0, raw/(2.0/float64(rrfK+1)))
+}
+
 func mergeVectorDocInfo(existing rrfDocInfo, result vectorRow) rrfDocInfo {
 	if existing.method == "" {
 		return rrfDocInfo{
@@ -345,8 +551,9 @@ func decodeEmbedding(jsonStr string) ([]float32, error) {
 	return vec, nil
 }
 
-// resolveLimit returns the effective limit, applying default and max caps.
-func resolveLimit(limit int) int {
+// ResolveLimit returns the effective search limit: 0 or less gives the
+// default (20) and larger values are capped at 50.
+func ResolveLimit(limit int) int {
 	if limit <= 0 {
 		return defaultLimit
 	}
@@ -27,7 +27,7 @@ func BenchmarkVectorSearch_SQLite(b *testing.B) {
 	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		if _, err := svc.vectorSearch(context.Background(), wsID, "", "", queryVec, 10); err != nil {
+		if _, err := svc.vectorSearch(context.Background(), searchScope{workspaceID: wsID}, queryVec, 10); err != nil {
 			b.Fatalf("vectorSearch: %v", err)
 		}
 	}
@@ -73,8 +73,8 @@ func createBenchmarkWorkspace(b *testing.B, db *sql.DB) string {
 
 	id := newID()
 	_, err := db.Exec(`
-		INSERT INTO workspace (id, name, slug, plan, status, created_at, updated_at)
-		VALUES (?, ?, ?, 'pro', 'active', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		INSERT INTO workspace (id, name, slug, created_at, updated_at)
+		VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
 	`, id, "Bench Workspace", "bench-workspace-"+id[:8])
 	if err != nil {
 		b.Fatalf("create bench workspace: %v", err)
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -103,8 +104,8 @@ func TestResolveLimit(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := resolveLimit(tt.in); got != tt.want {
-				t.Fatalf("resolveLimit(%d)=%d, want %d", tt.in, got, tt.want)
+			if got := ResolveLimit(tt.in); got != tt.want {
+				t.Fatalf("ResolveLimit(%d)=%d, want %d", tt.in, got, tt.want)
 			}
 		})
 	}
@@ -129,7 +130,7 @@ func TestRRFMerge_RankingFormula(t *testing.T) {
 		{id: "chunk-C", knowledgeItemID: "C", similarity: 0.80}, // rank 2
 	}
 
-	results := rrfMerge(bm25Results, vecResults, 10)
+	results := rrfMerge(bm25Results, vecResults, nil, 10)
 
 	if len(results) != 3 {
 		t.Fatalf("expected 3 results, got %d", len(results))
@@ -156,6 +157,76 @@ func TestRRFMerge_RankingFormula(t *testing.T) {
 	}
 }
 
+func TestRRFMerge_CollapsesChunksOfSameItem(t *testing.T) {
+	bm25Results := []bm25Row{{id: "A", title: "Doc A", snippet: "bm25 A"}}
+	vecResults := []vectorRow{
+		{id: "chunk-A1", knowledgeItemID: "A", title: "Doc A", snippet: "best chunk A", similarity: 0.95},
+		{id: "chunk-A2", knowledgeItemID: "A", title: "Doc A", snippet: "chunk A2", similarity: 0.90},
+		{id: "chunk-A3", knowledgeItemID: "A", title: "Doc A", snippet: "chunk A3", similarity: 0.85},
+		{id: "chunk-B1", knowledgeItemID: "B", title: "Doc B", snippet: "chunk B", similarity: 0.80},
+	}
+
+	results := rrfMerge(bm25Results, vecResults, nil, 10)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results (one per item), got %d", len(results))
+	}
+	if results[0].KnowledgeItemID != "A" || results[0].Method != EvidenceMethodHybrid {
+		t.Fatalf("results[0] = %s/%s, want A/hybrid", results[0].KnowledgeItemID, results[0].Method)
+	}
+	if results[0].Score != 1.0 {
+		t.Fatalf("A ranked first by both methods: score = %f, want 1.0 (extra chunks must not add)", results[0].Score)
+	}
+	// B is the second distinct item in the vector list, not the fourth chunk.
+	wantB := normalizeRRFScore(1.0 / float64(rrfK+2))
+	if results[1].KnowledgeItemID != "B" || results[1].Method != EvidenceMethodVector || results[1].Score != wantB {
+		t.Fatalf("results[1] = %+v, want B/vector with score %f", results[1], wantB)
+	}
+
+	vecOnly := rrfMerge(nil, vecResults[:3], nil, 10)
+	if len(vecOnly) != 1 || vecOnly[0].Method != EvidenceMethodVector || vecOnly[0].Snippet != "best chunk A" {
+		t.Fatalf("vector-only chunks of one item = %+v, want single vector result with best chunk", vecOnly)
+	}
+}
+
+func TestRRFMerge_ScoresNormalizedAndMonotonic(t *testing.T) {
+	var bm25Results []bm25Row
+	var vecResults []vectorRow
+	for i := 0; i < 8; i++ {
+		id := fmt.Sprintf("doc-%d", i)
+		bm25Results = append(bm25Results, bm25Row{id: id})
+		if i%2 == 0 {
+			vecResults = append(vecResults, vectorRow{id: "chunk-" + id, knowledgeItemID: id})
+		}
+	}
+	vecResults = append(vecResults, vectorRow{id: "chunk-only", knowledgeItemID: "vector-only"})
+
+	results := rrfMerge(bm25Results, vecResults, nil, maxLimit)
+	if len(results) != 9 {
+		t.Fatalf("expected 9 results, got %d", len(results))
+	}
+	if results[0].Score != 1.0 {
+		t.Fatalf("top result in both lists at rank 1: score = %f, want 1.0", results[0].Score)
+	}
+	for i, r := range results {
+		if r.Score <= 0 || r.Score > 1 {
+			t.Fatalf("results[%d].Score = %f, want in (0,1]", i, r.Score)
+		}
+		if i > 0 && r.Score > results[i-1].Score {
+			t.Fatalf("results[%d].Score = %f exceeds previous %f", i, r.Score, results[i-1].Score)
+		}
+	}
+
+	if got := normalizeRRFScore(0); got != 0 {
+		t.Fatalf("normalizeRRFScore(0) = %f, want 0", got)
+	}
+	if got := normalizeRRFScore(-0.5); got != 0 {
+		t.Fatalf("normalizeRRFScore(negative) = %f, want 0", got)
+	}
+	if got := normalizeRRFScore(1); got != 1 {
+		t.Fatalf("normalizeRRFScore(>max) = %f, want 1", got)
+	}
+}
+
 // ============================================================================
 // Integration tests (real DB + real FTS5 + stub embedder)
 // ============================================================================
@@ -266,6 +337,131 @@ func TestSearchService_Hybrid_CombinesBothMethods(t *testing.T) {
 	}
 }
 
+func TestSearchService_ChunkedDocument_SingleResult(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	stub := newStubEmbedder(4)
+	wsID := createWorkspace(t, db)
+
+	bus := eventbus.New()
+	ingest := NewIngestService(db, bus)
+	embedder := NewEmbedderService(db, stub)
+	svc := NewSearchService(db, stub)
+
+	// ~1200 tokens: three chunks at DefaultChunkSize with overlap.
+	long := ingestAndEmbedDoc(t, ingest, embedder, wsID, "Renewal Playbook", strings.Repeat("renewal pricing discount ", 400))
+	short := ingestAndEmbedDoc(t, ingest, embedder, wsID, "Pricing Note", "pricing note for renewal calls")
+
+	var chunks int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM embedding_document WHERE knowledge_item_id = ?`, long.ID).Scan(&chunks); err != nil {
+		t.Fatalf("count chunks: %v", err)
+	}
+	if chunks != 3 {
+		t.Fatalf("expected long document to be chunked 3 times, got %d", chunks)
+	}
+
+	results, err := svc.HybridSearch(context.Background(), SearchInput{
+		Query:       "renewal pricing",
+		WorkspaceID: wsID,
+		Limit:       2,
+	})
+	if err != nil {
+		t.Fatalf("HybridSearch failed: %v", err)
+	}
+
+	seen := map[string]int{}
+	for _, item := range results.Items {
+		seen[item.KnowledgeItemID]++
+	}
+	if seen[long.ID] != 1 || seen[short.ID] != 1 || len(results.Items) != 2 {
+		t.Fatalf("expected each document exactly once within limit 2, got %+v", results.Items)
+	}
+}
+
+func TestSearchService_SoftDeletedItem_ExcludedFromBM25AndVector(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	stub := newStubEmbedder(3)
+	wsID := createWorkspace(t, db)
+
+	ingest := NewIngestService(db, eventbus.New())
+	embedder := NewEmbedderService(db, stub)
+	svc := NewSearchService(db, stub)
+	ctx := context.Background()
+
+	deleted := ingestAndEmbedDoc(t, ingest, embedder, wsID, "Refund Policy", "refund requests are approved within thirty days")
+	kept := ingestAndEmbedDoc(t, ingest, embedder, wsID, "Refund Escalation", "escalate refund disputes to the billing team")
+	queryVec := []float32{0.1, 0.1, 0.1}
+
+	bm25IDs := func() map[string]bool {
+		t.Helper()
+		rows, err := svc.bm25Search(ctx, "refund", searchScope{workspaceID: wsID}, 10)
+		if err != nil {
+			t.Fatalf("bm25Search failed: %v", err)
+		}
+		ids := map[string]bool{}
+		for _, r := range rows {
+			ids[r.id] = true
+		}
+		return ids
+	}
+	vectorIDs := func() map[string]bool {
+		t.Helper()
+		rows, err := svc.vectorSearch(ctx, searchScope{workspaceID: wsID}, queryVec, 10)
+		if err != nil {
+			t.Fatalf("vectorSearch failed: %v", err)
+		}
+		ids := map[string]bool{}
+		for _, r := range rows {
+			ids[r.knowledgeItemID] = true
+		}
+		return ids
+	}
+
+	if !bm25IDs()[deleted.ID] || !vectorIDs()[deleted.ID] {
+		t.Fatal("expected document in BM25 and vector results before delete")
+	}
+
+	if err := ingest.Delete(ctx, wsID, deleted.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	if bm25 := bm25IDs(); bm25[deleted.ID] || !bm25[kept.ID] {
+		t.Errorf("bm25 results after delete = %v, want only %s", bm25, kept.ID)
+	}
+	if vec := vectorIDs(); vec[deleted.ID] || !vec[kept.ID] {
+		t.Errorf("vector results after delete = %v, want only %s", vec, kept.ID)
+	}
+
+	results, err := svc.HybridSearch(ctx, SearchInput{Query: "refund", WorkspaceID: wsID, Limit: 10})
+	if err != nil {
+		t.Fatalf("HybridSearch failed: %v", err)
+	}
+	for _, r := range results.Items {
+		if r.KnowledgeItemID == deleted.ID {
+			t.Fatalf("HybridSearch returned soft-deleted item %s", deleted.ID)
+		}
+	}
+
+	var vecCount, liveChunks int
+	if err = db.QueryRow(
+		`SELECT COUNT(*) FROM vec_embedding v JOIN embedding_document ed ON ed.id = v.id
+		 WHERE ed.knowledge_item_id = ?`, deleted.ID,
+	).Scan(&vecCount); err != nil {
+		t.Fatalf("count vec_embedding: %v", err)
+	}
+	if err = db.QueryRow(
+		`SELECT COUNT(*) FROM embedding_document WHERE knowledge_item_id = ? AND deleted_at IS NULL`, deleted.ID,
+	).Scan(&liveChunks); err != nil {
+		t.Fatalf("count embedding_document: %v", err)
+	}
+	if vecCount != 0 || liveChunks != 0 {
+		t.Errorf("after delete: %d vectors, %d live chunks; want 0 and 0", vecCount, liveChunks)
+	}
+}
+
 func TestSearchService_WorkspaceIsolation(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -298,6 +494,183 @@ func TestSearchService_WorkspaceIsolation(t *testing.T) {
 	}
 }
 
+func TestSearchService_SearchForEntity_RestrictsToLinkedItems(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	stub := newStubEmbedder(3)
+	wsA := createWorkspace(t, db)
+	wsB := createWorkspace(t, db)
+
+	ingest := NewIngestService(db, eventbus.New())
+	embedder := NewEmbedderService(db, stub)
+	svc := NewSearchService(db, stub)
+
+	caseType := "case"
+	ingestLinked := func(wsID, caseID, title string) {
+		t.Helper()
+		item, err := ingest.Ingest(context.Background(), CreateKnowledgeItemInput{
+			WorkspaceID: wsID,
+			SourceType:  SourceTypeDocument,
+			Title:       title,
+			RawContent:  "refund request attachment for the billing dispute",
+			EntityType:  &caseType,
+			EntityID:    &caseID,
+		})
+		if err != nil {
+			t.Fatalf("ingest failed for %q: %v", title, err)
+		}
+		if err = embedder.EmbedChunks(context.Background(), item.ID, wsID); err != nil {
+			t.Fatalf("EmbedChunks failed for %q: %v", title, err)
+		}
+	}
+	ingestLinked(wsA, "case-1", "Case 1 Attachment")
+	ingestLinked(wsA, "case-2", "Case 2 Attachment")
+	ingestLinked(wsB, "case-1", "Other Workspace Attachment")
+	ingestAndEmbedDoc(t, ingest, embedder, wsA, "Unlinked Doc", "refund request attachment for the billing dispute")
+
+	results, err := svc.SearchForEntity(context.Background(), wsA, caseType, "case-1", "refund billing")
+	if err != nil {
+		t.Fatalf("SearchForEntity failed: %v", err)
+	}
+	if len(results.Items) != 1 || results.Items[0].Title != "Case 1 Attachment" {
+		t.Fatalf("expected only the case-1 attachment, got %+v", results.Items)
+	}
+	if results.Items[0].Method != EvidenceMethodHybrid {
+		t.Errorf("expected BM25 and vector candidates to be merged, got method %q", results.Items[0].Method)
+	}
+
+	if _, err = svc.SearchForEntity(context.Background(), wsA, caseType, " ", "refund"); !errors.Is(err, ErrEntityScopeRequired) {
+		t.Fatalf("expected ErrEntityScopeRequired, got %v", err)
+	}
+}
+
+func TestSearchService_MetadataFilters_KeepOnlyTaggedSections(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	stub := newStubEmbedder(3)
+	wsA := createWorkspace(t, db)
+	wsB := createWorkspace(t, db)
+
+	ingest := NewIngestService(db, eventbus.New())
+	embedder := NewEmbedderService(db, stub)
+	svc := NewSearchService(db, stub)
+
+	ingestSection := func(wsID, title, section string) {
+		t.Helper()
+		item, err := ingest.Ingest(context.Background(), CreateKnowledgeItemInput{
+			WorkspaceID:   wsID,
+			SourceType:    SourceTypeDocument,
+			Title:         title,
+			RawContent:    "how to reset the password of a locked account",
+			ChunkMetadata: map[string]string{"section": section},
+		})
+		if err != nil {
+			t.Fatalf("ingest failed for %q: %v", title, err)
+		}
+		if err = embedder.EmbedChunks(context.Background(), item.ID, wsID); err != nil {
+			t.Fatalf("EmbedChunks failed for %q: %v", title, err)
+		}
+	}
+	ingestSection(wsA, "FAQ Answer", "faq_answer")
+	ingestSection(wsA, "Guide Body", "body")
+	ingestSection(wsB, "Other Workspace FAQ", "faq_answer")
+	ingestAndEmbedDoc(t, ingest, embedder, wsA, "Untagged Doc", "how to reset the password of a locked account")
+
+	var stored string
+	if err := db.QueryRow(`SELECT ed.metadata FROM embedding_document ed JOIN knowledge_item ki ON ki.id = ed.knowledge_item_id WHERE ki.title = 'FAQ Answer'`).Scan(&stored); err != nil {
+		t.Fatalf("load chunk metadata: %v", err)
+	}
+	if stored != `{"section":"faq_answer"}` {
+		t.Fatalf("chunk metadata = %s; want the section tag", stored)
+	}
+
+	results, err := svc.HybridSearch(context.Background(), SearchInput{
+		Query:           "reset password",
+		WorkspaceID:     wsA,
+		MetadataFilters: map[string]string{"section": "faq_answer"},
+	})
+	if err != nil {
+		t.Fatalf("HybridSearch failed: %v", err)
+	}
+	if len(results.Items) != 1 || results.Items[0].Title != "FAQ Answer" {
+		t.Fatalf("expected only the workspace's FAQ answer, got %+v", results.Items)
+	}
+	if results.Items[0].Method != EvidenceMethodHybrid {
+		t.Errorf("expected BM25 and vector candidates to be filtered alike, got method %q", results.Items[0].Method)
+	}
+
+	unfiltered, err := svc.HybridSearch(context.Background(), SearchInput{Query: "reset password", WorkspaceID: wsA})
+	if err != nil {
+		t.Fatalf("HybridSearch (unfiltered) failed: %v", err)
+	}
+	if len(unfiltered.Items) != 3 {
+		t.Fatalf("expected all 3 workspace docs without filters, got %d", len(unfiltered.Items))
+	}
+}
+
+func TestSearchService_Language_SpanishQueryMatchesSpanishDoc(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	stub := newStubEmbedder(3)
+	wsID := createWorkspace(t, db)
+	ingest := NewIngestService(db, eventbus.New())
+	embedder := NewEmbedderService(db, stub)
+	svc := NewSearchService(db, stub)
+
+	es := ingestAndEmbedDoc(t, ingest, embedder, wsID, "Facturación atrasada",
+		"La facturación del cliente se retrasa porque el pago no llegó a tiempo.")
+	en := ingestAndEmbedDoc(t, ingest, embedder, wsID, "Billing glossary",
+		"The facturacion field is the invoice code that the billing team uses for all of the Spanish accounts.")
+	if es.Language == nil || *es.Language != LanguageSpanish {
+		t.Fatalf("expected Spanish doc to be stored as %q, got %v", LanguageSpanish, es.Language)
+	}
+	if en.Language == nil || *en.Language != LanguageEnglish {
+		t.Fatalf("expected English doc to be stored as %q, got %v", LanguageEnglish, en.Language)
+	}
+
+	// Accent-insensitive: "facturacion" matches "facturación".
+	results, err := svc.HybridSearch(context.Background(), SearchInput{
+		Query:       "facturacion",
+		WorkspaceID: wsID,
+		Language:    LanguageSpanish,
+	})
+	if err != nil {
+		t.Fatalf("HybridSearch failed: %v", err)
+	}
+	if len(results.Items) != 1 || results.Items[0].KnowledgeItemID != es.ID {
+		t.Fatalf("expected only the Spanish doc, got %+v", results.Items)
+	}
+
+	results, err = svc.HybridSearch(context.Background(), SearchInput{Query: "facturacion", WorkspaceID: wsID})
+	if err != nil {
+		t.Fatalf("HybridSearch failed: %v", err)
+	}
+	if len(results.Items) != 2 {
+		t.Fatalf("expected both docs without a language filter, got %+v", results.Items)
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"¿Cuándo llega el pago de la factura?", LanguageSpanish},
+		{"El cliente pidió una reunión para la próxima semana", LanguageSpanish},
+		{"The customer asked for a meeting next week", LanguageEnglish},
+		{"Renewal", ""},
+		{"", ""},
+	}
+	for _, tc := range cases {
+		if got := DetectLanguage(tc.text); got != tc.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
 func TestSearchService_EmptyIndex_NoResults(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -352,6 +725,73 @@ func TestSearchService_Limit_Respected(t *testing.T) {
 	}
 }
 
+func TestSearchService_Offset_PagesStably(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	stub := newStubEmbedder(3)
+	wsID := createWorkspace(t, db)
+
+	bus := eventbus.New()
+	ingest := NewIngestService(db, bus)
+	embedder := NewEmbedderService(db, stub)
+	svc := NewSearchService(db, stub)
+
+	// Identical documents tie on every score, so order relies on the id tiebreak.
+	for i := 0; i < 10; i++ {
+		ingestAndEmbedDoc(t, ingest, embedder, wsID,
+			"Document about retrieval",
+			"retrieval augmented generation system for knowledge base",
+		)
+	}
+
+	search := func(offset, limit int) *SearchResults {
+		t.Helper()
+		results, err := svc.HybridSearch(context.Background(), SearchInput{
+			Query:       "retrieval",
+			WorkspaceID: wsID,
+			Limit:       limit,
+			Offset:      offset,
+		})
+		if err != nil {
+			t.Fatalf("HybridSearch(offset=%d) failed: %v", offset, err)
+		}
+		return results
+	}
+
+	all := search(0, 10)
+	if len(all.Items) != 10 || all.HasMore {
+		t.Fatalf("full result set = %d items (has_more=%v); want 10, false", len(all.Items), all.HasMore)
+	}
+
+	page2 := search(4, 4)
+	if len(page2.Items) != 4 || !page2.HasMore || page2.TotalCandidates != 9 {
+		t.Fatalf("page 2 = %d items (has_more=%v, total=%d); want 4, true, 9",
+			len(page2.Items), page2.HasMore, page2.TotalCandidates)
+	}
+	for i, item := range page2.Items {
+		if item.KnowledgeItemID != all.Items[4+i].KnowledgeItemID {
+			t.Fatalf("page 2 item %d = %s; want %s", i, item.KnowledgeItemID, all.Items[4+i].KnowledgeItemID)
+		}
+	}
+	if again := search(4, 4); fmt.Sprint(again.Items) != fmt.Sprint(page2.Items) {
+		t.Fatalf("page 2 changed across identical queries:\n%v\n%v", page2.Items, again.Items)
+	}
+
+	last := search(8, 4)
+	if len(last.Items) != 2 || last.HasMore {
+		t.Fatalf("last page = %d items (has_more=%v); want 2, false", len(last.Items), last.HasMore)
+	}
+	if past := search(20, 4); len(past.Items) != 0 || past.HasMore {
+		t.Fatalf("page past the end = %d items (has_more=%v); want 0, false", len(past.Items), past.HasMore)
+	}
+	if _, err := svc.HybridSearch(context.Background(), SearchInput{
+		Query: "retrieval", WorkspaceID: wsID, Offset: MaxSearchOffset + 1,
+	}); !errors.Is(err, ErrOffsetOutOfRange) {
+		t.Fatalf("HybridSearch(offset=%d) error = %v; want ErrOffsetOutOfRange", MaxSearchOffset+1, err)
+	}
+}
+
 // TestSearchService_VectorFallback_EmptyEmbeddings covers the
 // len(resp.Embeddings) == 0 branch in vectorSearchWithFallback (Task 2.5 audit).
 func TestSearchService_VectorFallback_EmptyEmbeddings(t *testing.T) {
@@ -413,7 +853,7 @@ func TestSearchService_BM25_InvalidFTSSyntax(t *testing.T) {
 	svc := NewSearchService(db, stub)
 
 	// FTS5 interprets empty string as syntax error — triggers the //nolint:nilerr path
-	results, err := svc.bm25Search(context.Background(), "\"\"\"invalid fts5\"\"\"", wsID, "", "", 10)
+	results, err := svc.bm25Search(context.Background(), "\"\"\"invalid fts5\"\"\"", searchScope{workspaceID: wsID}, 10)
 	// bm25Search treats FTS5 errors as no results (graceful degradation)
 	if err != nil {
 		t.Fatalf("bm25Search should degrade gracefully on FTS5 syntax error, got: %v", err)
@@ -0,0 +1,408 @@
+package knowledge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// SourceSystemURL is the source_system recorded for items ingested by IngestURL.
+const SourceSystemURL = "url"
+
+const (
+	// MaxURLContentBytes caps the response body accepted by IngestURL.
+	MaxURLContentBytes = 2 << 20
+	urlFetchTimeout    = 15 * time.Second
+	urlUserAgent       = "fenix-knowledge/1.0"
+	maxURLRedirects    = 5
+)
+
+var (
+	// ErrInvalidURL is returned for URLs that are not absolute http(s) URLs.
+	ErrInvalidURL = errors.New("invalid url")
+	// ErrURLFetchFailed is returned when the page cannot be fetched or the
+	// server answers with a non-2xx status.
+	ErrURLFetchFailed = errors.New("url fetch failed")
+	// ErrURLOptOut is returned when the page asks not to be indexed
+	// (X-Robots-Tag header or robots meta tag with noindex/none).
+	ErrURLOptOut = errors.New("url opts out of indexing")
+	// ErrURLContentTooLarge is returned when the body exceeds MaxURLContentBytes.
+	ErrURLContentTooLarge = errors.New("url content exceeds size limit")
+	// ErrURLUnsupportedContent is returned for responses that are neither HTML nor plain text.
+	ErrURLUnsupportedContent = errors.New("url content type not supported")
+	// ErrURLNoContent is returned when no readable text remains after extraction.
+	ErrURLNoContent = errors.New("url has no readable content")
+	// ErrURLNotPublic is returned when the URL, or a redirect it answers with,
+	// resolves to a loopback, private, link-local or otherwise internal address.
+	ErrURLNotPublic = errors.New("url does not resolve to a public address")
+)
+
+// urlMetadata is stored as the knowledge_item metadata JSON for URL sources.
+type urlMetadata struct {
+	SourceURL  string `json:"source_url"`
+	FetchedURL string `json:"fetched_url"`
+}
+
+// urlPage is the readable form of a fetched page.
+type urlPage struct {
+	title        string
+	text         string
+	canonicalURL string
+	noIndex      bool
+}
+
+// IngestURL fetches rawURL, reduces it to readable text and ingests it as a
+// SourceTypeURL item. The canonical URL is stored as source_object_id (and in
+// metadata.source_url), so ingesting the same page again updates the existing
+// item instead of creating a duplicate. Chunking and the knowledge.ingested
+// event follow the normal Ingest path.
+func (s *IngestService) IngestURL(ctx context.Context, workspaceID, rawURL string) (*KnowledgeItem, error) {
+	target, err := parseIngestURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	page, finalURL, err := s.fetchURL(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if page.noIndex {
+		return nil, fmt.Errorf("%w: %s", ErrURLOptOut, rawURL)
+	}
+	if page.text == "" {
+		return nil, fmt.Errorf("%w: %s", ErrURLNoContent, rawURL)
+	}
+
+	canonical := page.canonicalURL
+	if canonical == "" {
+		canonical = stripFragment(finalURL)
+	}
+	title := page.title
+	if title == "" {
+		title = canonical
+	}
+	meta, err := json.Marshal(urlMetadata{SourceURL: canonical, FetchedURL: finalURL.String()})
+	if err != nil {
+		return nil, fmt.Errorf("encode url metadata: %w", err)
+	}
+	sourceSystem := SourceSystemURL
+
+	return s.Ingest(ctx, CreateKnowledgeItemInput{
+		WorkspaceID:    workspaceID,
+		SourceSystem:   &sourceSystem,
+		SourceType:     SourceTypeURL,
+		SourceObjectID: &canonical,
+		Title:          title,
+		RawContent:     page.text,
+		Metadata:       ptrFromStr(string(meta)),
+	})
+}
+
+func parseIngestURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q must be an absolute http or https url", ErrInvalidURL, rawURL)
+	}
+	return u, nil
+}
+
+// newURLFetchClient returns the client IngestURL fetches with. Its dialer
+// refuses every address allowed rejects, checked on the resolved IP so a
+// public name cannot point at an internal host, and each redirect is
+// re-validated before it is followed. Proxies are not used: the check must
+// see the address actually dialed.
+func newURLFetchClient(allowed func(netip.Addr) bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: urlFetchTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrURLNotPublic, address)
+			}
+			if addr, err := netip.ParseAddr(host); err != nil || !allowed(addr.Unmap()) {
+				return fmt.Errorf("%w: %s", ErrURLNotPublic, host)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   urlFetchTimeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxURLRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxURLRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirect to %q", ErrInvalidURL, req.URL.Scheme)
+			}
+			if addr, err := netip.ParseAddr(req.URL.Hostname()); err == nil && !allowed(addr.Unmap()) {
+				return fmt.Errorf("%w: redirect to %s", ErrURLNotPublic, addr)
+			}
+			return nil
+		},
+	}
+}
+
+// cgnatPrefix is the shared address space (RFC 6598), internal to carriers.
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+// isPublicAddr reports whether addr is a publicly routable unicast address.
+// It rejects loopback, RFC 1918 / unique local, link-local (which includes
+// the 169.254.169.254 cloud metadata endpoint), unspecified, multicast and
+// shared addresses.
+func isPublicAddr(addr netip.Addr) bool {
+	return addr.IsGlobalUnicast() &&
+		!addr.IsPrivate() &&
+		!cgnatPrefix.Contains(addr)
+}
+
+// fetchURL downloads target and extracts its readable text. The returned URL
+// is the final one after redirects.
+func (s *IngestService) fetchURL(ctx context.Context, target *url.URL) (urlPage, *url.URL, error) {
+	ctx, cancel := context.WithTimeout(ctx, urlFetchTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return urlPage{}, nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	req.Header.Set("User-Agent", urlUserAgent)
+	req.Header.Set("Accept", "text/html, application/xhtml+xml, text/plain;q=0.9")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrURLNotPublic) {
+			return urlPage{}, nil, fmt.Errorf("%w: %s", ErrURLNotPublic, target)
+		}
+		return urlPage{}, nil, fmt.Errorf("%w: %v", ErrURLFetchFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return urlPage{}, nil, fmt.Errorf("%w: %s returned %s", ErrURLFetchFailed, target, resp.Status)
+	}
+	if robotsNoIndex(resp.Header.Values("X-Robots-Tag")...) {
+		return urlPage{noIndex: true}, resp.Request.URL, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxURLContentBytes+1))
+	if err != nil {
+		return urlPage{}, nil, fmt.Errorf("%w: read body: %v", ErrURLFetchFailed, err)
+	}
+	if len(body) > MaxURLContentBytes {
+		return urlPage{}, nil, fmt.Errorf("%w: max %d bytes", ErrURLContentTooLarge, MaxURLContentBytes)
+	}
+
+	finalURL := resp.Request.URL
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/html", "application/xhtml+xml", "":
+		page, parseErr := extractHTML(string(body), finalURL)
+		if parseErr != nil {
+			return urlPage{}, nil, fmt.Errorf("%w: parse html: %v", ErrURLFetchFailed, parseErr)
+		}
+		return page, finalURL, nil
+	case "text/plain":
+		return urlPage{text: collapseLines(string(body))}, finalURL, nil
+	default:
+		return urlPage{}, nil, fmt.Errorf("%w: %s", ErrURLUnsupportedContent, mediaType)
+	}
+}
+
+// robotsNoIndex reports whether any robots directive value opts out of
+// indexing. Values may be scoped to a crawler ("googlebot: noindex").
+func robotsNoIndex(values ...string) bool {
+	for _, v := range values {
+		if _, scoped, ok := strings.Cut(v, ":"); ok {
+			v = scoped
+		}
+		for _, directive := range strings.Split(v, ",") {
+			switch strings.ToLower(strings.TrimSpace(directive)) {
+			case "noindex", "none":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// boilerplateAtoms are elements whose text never belongs to the readable body.
+var boilerplateAtoms = map[atom.Atom]struct{}{
+	atom.Script:   {},
+	atom.Style:    {},
+	atom.Noscript: {},
+	atom.Template: {},
+	atom.Nav:      {},
+	atom.Header:   {},
+	atom.Footer:   {},
+	atom.Aside:    {},
+	atom.Form:     {},
+	atom.Iframe:   {},
+	atom.Svg:      {},
+}
+
+// blockAtoms start a new line in the extracted text.
+var blockAtoms = map[atom.Atom]struct{}{
+	atom.P: {}, atom.Div: {}, atom.Section: {}, atom.Article: {}, atom.Main: {},
+	atom.H1: {}, atom.H2: {}, atom.H3: {}, atom.H4: {}, atom.H5: {}, atom.H6: {},
+	atom.Li: {}, atom.Ul: {}, atom.Ol: {}, atom.Tr: {}, atom.Table: {},
+	atom.Br: {}, atom.Pre: {}, atom.Blockquote: {}, atom.Dt: {}, atom.Dd: {},
+}
+
+// extractHTML reduces an HTML document to its title, canonical link, robots
+// opt-out and readable text. Text comes from <main> or <article> when present,
+// otherwise from <body>, skipping navigation and other boilerplate.
+func extractHTML(doc string, base *url.URL) (urlPage, error) {
+	root, err := html.Parse(strings.NewReader(doc))
+	if err != nil {
+		return urlPage{}, err
+	}
+
+	var page urlPage
+	var body, content *html.Node
+	for n := range root.Descendants() {
+		if n.Type != html.ElementNode {
+			continue
+		}
+		switch n.DataAtom {
+		case atom.Title:
+			if page.title == "" {
+				page.title = collapseSpaces(nodeText(n))
+			}
+		case atom.Meta:
+			if strings.EqualFold(attr(n, "name"), "robots") && robotsNoIndex(attr(n, "content")) {
+				page.noIndex = true
+			}
+		case atom.Link:
+			if page.canonicalURL == "" && hasToken(attr(n, "rel"), "canonical") {
+				page.canonicalURL = resolveCanonical(base, attr(n, "href"))
+			}
+		case atom.Body:
+			body = n
+		case atom.Main, atom.Article:
+			if content == nil {
+				content = n
+			}
+		}
+	}
+	if content == nil {
+		content = body
+	}
+	if content != nil {
+		var sb strings.Builder
+		writeReadableText(&sb, content)
+		page.text = collapseLines(sb.String())
+	}
+	return page, nil
+}
+
+func writeReadableText(sb *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		sb.WriteString(n.Data)
+		return
+	case html.ElementNode:
+		if _, skip := boilerplateAtoms[n.DataAtom]; skip {
+			return
+		}
+	}
+	_, block := blockAtoms[n.DataAtom]
+	if block {
+		sb.WriteByte('\n')
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeReadableText(sb, c)
+	}
+	if block {
+		sb.WriteByte('\n')
+	}
+}
+
+func nodeText(n *html.Node) string {
+	var sb strings.Builder
+	for c := range n.Descendants() {
+		if c.Type == html.TextNode {
+			sb.WriteString(c.Data)
+		}
+	}
+	return sb.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasToken(list, token string) bool {
+	for _, f := range strings.Fields(list) {
+		if strings.EqualFold(f, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveCanonical resolves href against base, accepting only http(s) results
+// on base's host. The canonical URL becomes the item's source_object_id, so a
+// page naming another host could otherwise overwrite that host's item.
+func resolveCanonical(base *url.URL, href string) string {
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || href == "" {
+		return ""
+	}
+	u := base.ResolveReference(ref)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ""
+	}
+	if !strings.EqualFold(u.Host, base.Host) {
+		return ""
+	}
+	return stripFragment(u)
+}
+
+func stripFragment(u *url.URL) string {
+	c := *u
+	c.Fragment = ""
+	c.RawFragment = ""
+	return c.String()
+}
+
+// collapseSpaces folds runs of whitespace into single spaces.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// collapseLines collapses whitespace within each line and drops blank lines.
+func collapseLines(s string) string {
+	lines := strings.Split(s, "\n")
+	out := lines[:0]
+	for _, line := range lines {
+		if line = collapseSpaces(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return strings.Join(out, "\n")
+}
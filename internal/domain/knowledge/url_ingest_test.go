@@ -0,0 +1,313 @@
+package knowledge
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+)
+
+const helpCenterPage = `<!doctype html>
+<html>
+<head>
+  <title>Reset your password | Help Center</title>
+  <link rel="canonical" href="/articles/reset-password">
+  <script>var tracking = "ignore me";</script>
+</head>
+<body>
+  <header>Acme logo</header>
+  <nav><a href="/">Home</a> <a href="/billing">Billing</a></nav>
+  <main>
+    <h1>Reset your password</h1>
+    <p>Open   Settings and choose <b>Security</b>.</p>
+    <p>Click "Reset password" and follow the email link.</p>
+  </main>
+  <footer>Copyright Acme</footer>
+</body>
+</html>`
+
+func newURLTestServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// newURLTestService returns an IngestService whose fetch client may dial the
+// loopback test servers the default client refuses.
+func newURLTestService(db *sql.DB, bus eventbus.EventBus) *IngestService {
+	svc := NewIngestService(db, bus)
+	svc.httpClient = newURLFetchClient(func(netip.Addr) bool { return true })
+	return svc
+}
+
+func TestIngestService_IngestURL_ExtractsReadableText(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	srv := newURLTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(helpCenterPage))
+	})
+
+	bus := eventbus.New()
+	ch := bus.Subscribe(TopicKnowledgeIngested)
+	svc := newURLTestService(db, bus)
+	wsID := createWorkspace(t, db)
+
+	item, err := svc.IngestURL(context.Background(), wsID, srv.URL+"/hc/123#top")
+	if err != nil {
+		t.Fatalf("IngestURL failed: %v", err)
+	}
+
+	if item.SourceType != SourceTypeURL {
+		t.Errorf("source type = %q, want %q", item.SourceType, SourceTypeURL)
+	}
+	if item.Title != "Reset your password | Help Center" {
+		t.Errorf("title = %q", item.Title)
+	}
+	wantText := "Reset your password\nOpen Settings and choose Security.\nClick \"Reset password\" and follow the email link."
+	if item.RawContent != wantText {
+		t.Errorf("raw content = %q, want %q", item.RawContent, wantText)
+	}
+	for _, noise := range []string{"Acme logo", "Billing", "Copyright", "tracking"} {
+		if strings.Contains(item.RawContent, noise) {
+			t.Errorf("raw content should not contain %q", noise)
+		}
+	}
+
+	wantCanonical := srv.URL + "/articles/reset-password"
+	if item.SourceObjectID == nil || *item.SourceObjectID != wantCanonical {
+		t.Errorf("source_object_id = %v, want %q", item.SourceObjectID, wantCanonical)
+	}
+	if item.Metadata == nil || !strings.Contains(*item.Metadata, `"source_url":"`+wantCanonical+`"`) {
+		t.Errorf("metadata = %v, want source_url %q", item.Metadata, wantCanonical)
+	}
+
+	var storedType string
+	if err = db.QueryRow(`SELECT source_type FROM knowledge_item WHERE id = ?`, item.ID).Scan(&storedType); err != nil {
+		t.Fatalf("select knowledge_item: %v", err)
+	}
+	if storedType != string(SourceTypeURL) {
+		t.Errorf("stored source_type = %q, want %q", storedType, SourceTypeURL)
+	}
+
+	select {
+	case evt := <-ch:
+		payload, ok := evt.Payload.(IngestedEventPayload)
+		if !ok || payload.KnowledgeItemID != item.ID {
+			t.Errorf("unexpected event payload %#v", evt.Payload)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Error("timeout: expected knowledge.ingested event within 200ms")
+	}
+}
+
+func TestIngestService_IngestURL_ReingestUpdatesSameItem(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	body := "First version of the article."
+	srv := newURLTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(body))
+	})
+
+	svc := newURLTestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+
+	first, err := svc.IngestURL(context.Background(), wsID, srv.URL+"/faq.txt")
+	if err != nil {
+		t.Fatalf("first IngestURL failed: %v", err)
+	}
+	body = "Second version of the article."
+	second, err := svc.IngestURL(context.Background(), wsID, srv.URL+"/faq.txt")
+	if err != nil {
+		t.Fatalf("second IngestURL failed: %v", err)
+	}
+
+	if first.ID != second.ID {
+		t.Fatalf("expected re-ingest to update item %q, got new item %q", first.ID, second.ID)
+	}
+	var count int
+	var content string
+	if err = db.QueryRow(
+		`SELECT COUNT(*), MAX(raw_content) FROM knowledge_item WHERE workspace_id = ?`, wsID,
+	).Scan(&count, &content); err != nil {
+		t.Fatalf("count knowledge_item: %v", err)
+	}
+	if count != 1 || content != body {
+		t.Errorf("got %d items with content %q, want 1 item with %q", count, content, body)
+	}
+}
+
+func TestIngestService_IngestURL_Errors(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := newURLTestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		path    string
+		wantErr error
+	}{
+		{
+			name: "non-2xx status",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				http.Error(w, "gone", http.StatusNotFound)
+			},
+			wantErr: ErrURLFetchFailed,
+		},
+		{
+			name: "x-robots-tag noindex",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("X-Robots-Tag", "googlebot: noindex, nofollow")
+				_, _ = w.Write([]byte("<p>secret</p>"))
+			},
+			wantErr: ErrURLOptOut,
+		},
+		{
+			name: "robots meta none",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				_, _ = w.Write([]byte(`<html><head><meta name="robots" content="none"></head><body><p>hidden</p></body></html>`))
+			},
+			wantErr: ErrURLOptOut,
+		},
+		{
+			name: "body over size cap",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/plain")
+				_, _ = w.Write([]byte(strings.Repeat("a", MaxURLContentBytes+1)))
+			},
+			wantErr: ErrURLContentTooLarge,
+		},
+		{
+			name: "unsupported content type",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/pdf")
+				_, _ = w.Write([]byte("%PDF-1.7"))
+			},
+			wantErr: ErrURLUnsupportedContent,
+		},
+		{
+			name: "only boilerplate",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				_, _ = w.Write([]byte(`<html><body><nav>Home</nav><footer>Footer</footer></body></html>`))
+			},
+			wantErr: ErrURLNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newURLTestServer(t, tt.handler)
+			_, err := svc.IngestURL(context.Background(), wsID, srv.URL+"/page")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM knowledge_item WHERE workspace_id = ?`, wsID).Scan(&count); err != nil {
+		t.Fatalf("count knowledge_item: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected no items after failed ingests, got %d", count)
+	}
+}
+
+func TestIngestService_IngestURL_RejectsInvalidURL(t *testing.T) {
+	svc := NewIngestService(nil, eventbus.New())
+	for _, raw := range []string{"", "ftp://example.com/file", "/relative/path", "http://"} {
+		if _, err := svc.IngestURL(context.Background(), "ws", raw); !errors.Is(err, ErrInvalidURL) {
+			t.Errorf("IngestURL(%q) err = %v, want ErrInvalidURL", raw, err)
+		}
+	}
+}
+
+func TestIngestService_IngestURL_RefusesInternalAddresses(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	var hits atomic.Int32
+	srv := newURLTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("internal page"))
+	})
+	wsID := createWorkspace(t, db)
+
+	// The default client refuses the loopback test server outright.
+	if _, err := NewIngestService(db, eventbus.New()).IngestURL(context.Background(), wsID, srv.URL+"/page"); !errors.Is(err, ErrURLNotPublic) {
+		t.Fatalf("loopback err = %v, want ErrURLNotPublic", err)
+	}
+	if n := hits.Load(); n != 0 {
+		t.Fatalf("loopback server was hit %d times", n)
+	}
+
+	// A client allowed to reach the server still refuses its redirect to the
+	// metadata address.
+	svc := NewIngestService(db, eventbus.New())
+	svc.httpClient = newURLFetchClient(func(addr netip.Addr) bool { return addr.IsLoopback() })
+	if _, err := svc.IngestURL(context.Background(), wsID, srv.URL+"/redirect"); !errors.Is(err, ErrURLNotPublic) {
+		t.Fatalf("redirect err = %v, want ErrURLNotPublic", err)
+	}
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":   true,
+		"2606:4700::1111": true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"169.254.169.254": false,
+		"100.64.0.1":      false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fd00:ec2::254":   false,
+		"fe80::1":         false,
+	} {
+		if got := isPublicAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("isPublicAddr(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestIngestService_IngestURL_IgnoresCrossHostCanonical(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	srv := newURLTestServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(`<html><head><link rel="canonical" href="https://docs.example.com/articles/1"></head><body><p>Spoofed article.</p></body></html>`))
+	})
+	svc := newURLTestService(db, eventbus.New())
+	wsID := createWorkspace(t, db)
+
+	item, err := svc.IngestURL(context.Background(), wsID, srv.URL+"/page")
+	if err != nil {
+		t.Fatalf("IngestURL failed: %v", err)
+	}
+	if want := srv.URL + "/page"; item.SourceObjectID == nil || *item.SourceObjectID != want {
+		t.Errorf("source_object_id = %v, want %q", item.SourceObjectID, want)
+	}
+}
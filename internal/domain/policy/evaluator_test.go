@@ -17,14 +17,12 @@ import (
 
 func setupPolicyTestDB(t *testing.T) *sql.DB {
 	t.Helper()
-	db, err := sqlite.NewDB(":memory:")
+	// IMPORTANT: sqlite :memory: is per-connection.
+	// MemoryOptions forces a single connection so migrations and queries share same DB.
+	db, err := sqlite.NewDBWithOptions(":memory:", sqlite.MemoryOptions())
 	if err != nil {
 		t.Fatalf("sqlite.NewDB failed: %v", err)
 	}
-	// IMPORTANT: sqlite :memory: is per-connection.
-	// Force a single connection so migrations and queries share same DB.
-	db.SetMaxOpenConns(1)
-	db.SetMaxIdleConns(1)
 	if err := sqlite.MigrateUp(db); err != nil {
 		t.Fatalf("sqlite.MigrateUp failed: %v", err)
 	}
@@ -7,10 +7,11 @@ package relationship
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 // Approval topic constants — copied from internal/domain/audit/service.go (unexported there).
@@ -76,7 +77,7 @@ func (g *GraphExtractor) Run(ctx context.Context) {
 func (g *GraphExtractor) handle(ctx context.Context, ev eventbus.Event) {
 	input, err := parseGraphPayload(ev)
 	if err != nil {
-		log.Printf("relationship.GraphExtractor: parse payload topic=%s err=%v", ev.Topic, err)
+		logging.FromContext(ctx).Error("parse payload failed", slog.String(logging.KeyComponent, "relationship.GraphExtractor"), slog.String(logging.KeyTopic, ev.Topic), logging.Err(err))
 		return
 	}
 
@@ -94,7 +95,7 @@ func (g *GraphExtractor) handle(ctx context.Context, ev eventbus.Event) {
 		input.toEntityType, input.toEntityID,
 		influenceType, strength,
 	); upsertErr != nil {
-		log.Printf("relationship.GraphExtractor: UpsertEdge topic=%s err=%v", ev.Topic, upsertErr)
+		logging.FromContext(ctx).Error("upsert edge failed", slog.String(logging.KeyComponent, "relationship.GraphExtractor"), slog.String(logging.KeyTopic, ev.Topic), logging.Err(upsertErr))
 	}
 }
 
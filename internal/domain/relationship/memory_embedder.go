@@ -6,11 +6,12 @@ package relationship
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
 	"github.com/matiasleandrokruk/fenix/internal/infra/llm"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 const (
@@ -59,18 +60,18 @@ func (m *MemoryEmbedder) Run(ctx context.Context) {
 func (m *MemoryEmbedder) handle(ctx context.Context, ev eventbus.Event) {
 	input, err := parseSignalPayload(ev)
 	if err != nil {
-		log.Printf("relationship.MemoryEmbedder: parse payload topic=%s err=%v", ev.Topic, err)
+		logging.FromContext(ctx).Error("parse payload failed", slog.String(logging.KeyComponent, "relationship.MemoryEmbedder"), slog.String(logging.KeyTopic, ev.Topic), logging.Err(err))
 		return
 	}
 
 	vector, err := m.callEmbedWithRetry(ctx, input.summary)
 	if err != nil {
-		log.Printf("relationship.MemoryEmbedder: embed topic=%s signal_id=%s err=%v", ev.Topic, input.signalID, err)
+		logging.FromContext(ctx).Error("embed signal failed", slog.String(logging.KeyComponent, "relationship.MemoryEmbedder"), slog.String(logging.KeyTopic, ev.Topic), slog.String("signal_id", input.signalID), logging.Err(err))
 		return
 	}
 
 	if repoErr := m.repo.UpsertSignalEmbedding(ctx, input.workspaceID, input.signalID, vector); repoErr != nil {
-		log.Printf("relationship.MemoryEmbedder: UpsertSignalEmbedding topic=%s signal_id=%s err=%v", ev.Topic, input.signalID, repoErr)
+		logging.FromContext(ctx).Error("upsert signal embedding failed", slog.String(logging.KeyComponent, "relationship.MemoryEmbedder"), slog.String(logging.KeyTopic, ev.Topic), slog.String("signal_id", input.signalID), logging.Err(repoErr))
 	}
 }
 
@@ -6,12 +6,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
 	"github.com/matiasleandrokruk/fenix/internal/infra/llm"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 // summarizerSystemPrompt instructs the LLM to return only structured JSON.
@@ -78,32 +79,32 @@ func (s *Summarizer) Run(ctx context.Context) {
 func (s *Summarizer) handle(ctx context.Context, ev eventbus.Event) {
 	input, err := parseEventPayload(ev)
 	if err != nil {
-		log.Printf("relationship.Summarizer: parse payload topic=%s err=%v", ev.Topic, err)
+		logging.FromContext(ctx).Error("parse payload failed", slog.String(logging.KeyComponent, "relationship.Summarizer"), slog.String(logging.KeyTopic, ev.Topic), logging.Err(err))
 		return
 	}
 
 	sigType, err := signalTypeFor(ev.Topic, ev.Payload)
 	if err != nil {
-		log.Printf("relationship.Summarizer: reject topic=%s err=%v", ev.Topic, err)
+		logging.FromContext(ctx).Warn("event rejected", slog.String(logging.KeyComponent, "relationship.Summarizer"), slog.String(logging.KeyTopic, ev.Topic), logging.Err(err))
 		return
 	}
 
 	summary, sentiment, err := s.callLLM(ctx, input.rawText)
 	if err != nil {
-		log.Printf("relationship.Summarizer: LLM call topic=%s err=%v", ev.Topic, err)
+		logging.FromContext(ctx).Error("LLM call failed", slog.String(logging.KeyComponent, "relationship.Summarizer"), slog.String(logging.KeyTopic, ev.Topic), logging.Err(err))
 		return
 	}
 
 	mem, err := s.repo.UpsertMemory(ctx, input.workspaceID, input.entityType, input.entityID, summary)
 	if err != nil {
-		log.Printf("relationship.Summarizer: UpsertMemory topic=%s err=%v", ev.Topic, err)
+		logging.FromContext(ctx).Error("upsert memory failed", slog.String(logging.KeyComponent, "relationship.Summarizer"), slog.String(logging.KeyTopic, ev.Topic), logging.Err(err))
 		return
 	}
 
 	signalID, insertErr := s.repo.InsertSignal(ctx, mem.ID, sigType, SentimentType(sentiment),
 		summary, input.sourceEntityType, input.sourceEntityID, input.occurredAt)
 	if insertErr != nil {
-		log.Printf("relationship.Summarizer: InsertSignal topic=%s err=%v", ev.Topic, insertErr)
+		logging.FromContext(ctx).Error("insert signal failed", slog.String(logging.KeyComponent, "relationship.Summarizer"), slog.String(logging.KeyTopic, ev.Topic), logging.Err(insertErr))
 		// non-fatal: memory already upserted
 		return
 	}
@@ -4,9 +4,10 @@ package relationship
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 // TrustSignalRepository combines the read/write capabilities needed by TrustDriver.
@@ -52,17 +53,17 @@ func (d *TrustDriver) Run(ctx context.Context) {
 func (d *TrustDriver) handle(ctx context.Context, ev eventbus.Event) {
 	input, err := parseTrustDriverPayload(ev)
 	if err != nil {
-		log.Printf("relationship.TrustDriver: parse payload topic=%s err=%v", ev.Topic, err)
+		logging.FromContext(ctx).Error("parse payload failed", slog.String(logging.KeyComponent, "relationship.TrustDriver"), slog.String(logging.KeyTopic, ev.Topic), logging.Err(err))
 		return
 	}
 
 	signals, err := d.repo.ListSignalsByMemory(ctx, input.memoryID)
 	if err != nil {
-		log.Printf("relationship.TrustDriver: ListSignalsByMemory topic=%s signal_id=%s err=%v", ev.Topic, input.signalID, err)
+		logging.FromContext(ctx).Error("list signals by memory failed", slog.String(logging.KeyComponent, "relationship.TrustDriver"), slog.String(logging.KeyTopic, ev.Topic), slog.String("signal_id", input.signalID), logging.Err(err))
 		return
 	}
 	if scoreErr := d.engine.Score(ctx, input.memoryID, signals); scoreErr != nil {
-		log.Printf("relationship.TrustDriver: Score topic=%s signal_id=%s err=%v", ev.Topic, input.signalID, scoreErr)
+		logging.FromContext(ctx).Error("score trust failed", slog.String(logging.KeyComponent, "relationship.TrustDriver"), slog.String(logging.KeyTopic, ev.Topic), slog.String("signal_id", input.signalID), logging.Err(scoreErr))
 	}
 }
 
@@ -0,0 +1,102 @@
+package tool
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+const redactedParamValue = "[REDACTED]"
+
+// sensitiveParamSuffixes end the names of params whose values never reach the
+// audit trail, e.g. "password", "access_token" or "client_secret".
+var sensitiveParamSuffixes = []string{"password", "secret", "token", "api_key", "apikey", "credential", "credentials", "authorization"}
+
+// toolTargetIDParams maps id-carrying params to the entity type they address,
+// in the order they are tried.
+var toolTargetIDParams = []struct {
+	param      string
+	entityType string
+}{
+	{"case_id", "case"},
+	{"lead_id", "lead"},
+	{"deal_id", "deal"},
+	{"account_id", "account"},
+	{"contact_id", "contact"},
+}
+
+// toolTargetEntity returns the entity a tool call acts on when its params
+// identify one, falling back to the tool itself.
+func toolTargetEntity(toolName string, params json.RawMessage) (string, string) {
+	var payload map[string]any
+	if json.Unmarshal(params, &payload) == nil {
+		entityType, _ := payload["entity_type"].(string)
+		entityID, _ := payload["entity_id"].(string)
+		if entityType != "" && entityID != "" {
+			return entityType, entityID
+		}
+		for _, target := range toolTargetIDParams {
+			if id, _ := payload[target.param].(string); id != "" {
+				return target.entityType, id
+			}
+		}
+		if id, _ := payload["id"].(string); id != "" && strings.HasSuffix(toolName, "_knowledge_item") {
+			return "knowledge_item", id
+		}
+	}
+	return "tool", toolName
+}
+
+// sanitizeToolParams decodes params for the audit trail with the values of
+// sensitive keys replaced, at any depth. It returns nil for params that are
+// not a JSON object.
+func sanitizeToolParams(params json.RawMessage) map[string]any {
+	var payload map[string]any
+	if len(params) == 0 || json.Unmarshal(params, &payload) != nil {
+		return nil
+	}
+	redactParams(payload)
+	return payload
+}
+
+// RedactSecrets returns raw with the values of sensitive keys replaced at any
+// depth, using the same rules as the audit trail. Input that is not valid
+// JSON is returned unchanged.
+func RedactSecrets(raw json.RawMessage) json.RawMessage {
+	var payload any
+	if len(raw) == 0 || json.Unmarshal(raw, &payload) != nil {
+		return raw
+	}
+	redactParams(payload)
+	out, err := json.Marshal(payload)
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
+func redactParams(v any) {
+	switch node := v.(type) {
+	case map[string]any:
+		for key, child := range node {
+			if isSensitiveParam(key) {
+				node[key] = redactedParamValue
+				continue
+			}
+			redactParams(child)
+		}
+	case []any:
+		for _, child := range node {
+			redactParams(child)
+		}
+	}
+}
+
+func isSensitiveParam(key string) bool {
+	key = strings.ToLower(key)
+	for _, suffix := range sensitiveParamSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
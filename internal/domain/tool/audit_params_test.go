@@ -0,0 +1,46 @@
+package tool
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSanitizeToolParams_RedactsSensitiveKeys(t *testing.T) {
+	t.Parallel()
+
+	got := sanitizeToolParams(json.RawMessage(`{"title":"x","password":"p","nested":{"access_token":"t","max_tokens":5},"items":[{"client_secret":"s"}]}`))
+	if got["title"] != "x" || got["password"] != redactedParamValue {
+		t.Fatalf("unexpected top-level params: %v", got)
+	}
+	nested, _ := got["nested"].(map[string]any)
+	if nested["access_token"] != redactedParamValue || nested["max_tokens"] != float64(5) {
+		t.Fatalf("unexpected nested params: %v", nested)
+	}
+	items, _ := got["items"].([]any)
+	if item, _ := items[0].(map[string]any); item["client_secret"] != redactedParamValue {
+		t.Fatalf("unexpected list params: %v", items)
+	}
+	if sanitizeToolParams(json.RawMessage(`[1]`)) != nil {
+		t.Fatal("expected nil for non-object params")
+	}
+}
+
+func TestToolTargetEntity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		tool, params, wantType, wantID string
+	}{
+		{BuiltinCreateTask, `{"entity_type":"deal","entity_id":"d-1"}`, "deal", "d-1"},
+		{BuiltinUpdateCase, `{"case_id":"c-1","status":"resolved"}`, "case", "c-1"},
+		{BuiltinUpdateLead, `{"lead_id":"l-1"}`, "lead", "l-1"},
+		{BuiltinUpdateKnowledgeItem, `{"id":"k-1"}`, "knowledge_item", "k-1"},
+		{BuiltinQueryMetrics, `{"metric":"x"}`, "tool", BuiltinQueryMetrics},
+	}
+	for _, tt := range tests {
+		gotType, gotID := toolTargetEntity(tt.tool, json.RawMessage(tt.params))
+		if gotType != tt.wantType || gotID != tt.wantID {
+			t.Errorf("toolTargetEntity(%s, %s) = %s/%s; want %s/%s", tt.tool, tt.params, gotType, gotID, tt.wantType, tt.wantID)
+		}
+	}
+}
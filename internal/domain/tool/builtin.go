@@ -14,24 +14,56 @@ import (
 
 const (
 	BuiltinCreateTask          = "create_task"
+	BuiltinCreateNote          = "create_note"
 	BuiltinUpdateCase          = "update_case"
 	BuiltinUpdateDeal          = "update_deal"
+	BuiltinCreateDeal          = "create_deal"
 	BuiltinSendReply           = "send_reply"
 	BuiltinGetLead             = "get_lead"
 	BuiltinGetAccount          = "get_account"
 	BuiltinGetDeal             = "get_deal"
+	BuiltinGetCase             = "get_case"
+	BuiltinListCases           = "list_cases"
+	BuiltinUpdateLead          = "update_lead"
 	BuiltinCreateKnowledgeItem = "create_knowledge_item"
 	BuiltinUpdateKnowledgeItem = "update_knowledge_item"
 	BuiltinQueryMetrics        = "query_metrics"
 )
 
+// SearchKnowledge is the knowledge search the Go agents run in-process
+// through knowledge.SearchService. It has no executor or tool definition,
+// but agent definitions may allow it.
+const SearchKnowledge = "search_knowledge"
+
+// Params schemas of the built-in tools. They seed the persisted tool
+// definitions and back each executor's ParamsSchema.
+const (
+	createTaskParamsSchema          = `{"type":"object","required":["owner_id","title","entity_type","entity_id"],"properties":{"owner_id":{"type":"string"},"title":{"type":"string"},"due_date":{"type":"string"},"entity_type":{"type":"string"},"entity_id":{"type":"string"}},"additionalProperties":false}`
+	createNoteParamsSchema          = `{"type":"object","required":["author_id","content","entity_type","entity_id"],"properties":{"author_id":{"type":"string"},"content":{"type":"string"},"entity_type":{"type":"string","enum":["account","contact","deal","case"]},"entity_id":{"type":"string"},"is_internal":{"type":"boolean"}},"additionalProperties":false}`
+	updateCaseParamsSchema          = `{"type":"object","required":["case_id"],"properties":{"case_id":{"type":"string"},"status":{"type":"string"},"priority":{"type":"string"},"tags":{"type":"array","items":{"type":"string"}}},"additionalProperties":false}`
+	updateDealParamsSchema          = `{"type":"object","required":["deal_id"],"properties":{"deal_id":{"type":"string"},"status":{"type":"string"},"stage_id":{"type":"string"},"amount":{"type":"number"}},"additionalProperties":false}`
+	createDealParamsSchema          = `{"type":"object","required":["account_id","pipeline_id","stage_id","owner_id"],"properties":{"account_id":{"type":"string"},"pipeline_id":{"type":"string"},"stage_id":{"type":"string"},"owner_id":{"type":"string"},"amount":{"type":"number","minimum":0},"title":{"type":"string"}},"additionalProperties":false}`
+	sendReplyParamsSchema           = `{"type":"object","required":["case_id","body"],"properties":{"case_id":{"type":"string"},"body":{"type":"string"},"is_internal":{"type":"boolean"},"parent_note_id":{"type":"string"}},"additionalProperties":false}`
+	getLeadParamsSchema             = `{"type":"object","required":["lead_id"],"properties":{"lead_id":{"type":"string"}},"additionalProperties":false}`
+	getAccountParamsSchema          = `{"type":"object","required":["account_id"],"properties":{"account_id":{"type":"string"}},"additionalProperties":false}`
+	getDealParamsSchema             = `{"type":"object","required":["deal_id"],"properties":{"deal_id":{"type":"string"}},"additionalProperties":false}`
+	updateLeadParamsSchema          = `{"type":"object","required":["lead_id"],"properties":{"lead_id":{"type":"string"},"status":{"type":"string","enum":["new","contacted","qualified","converted","lost"]},"owner_id":{"type":"string"},"metadata":{"type":"object"}},"additionalProperties":false}`
+	getCaseParamsSchema             = `{"type":"object","required":["case_id"],"properties":{"case_id":{"type":"string"}},"additionalProperties":false}`
+	listCasesParamsSchema           = `{"type":"object","properties":{"status":{"type":"string"},"owner_id":{"type":"string"},"priority":{"type":"string"},"limit":{"type":"integer","minimum":1}},"additionalProperties":false}`
+	createKnowledgeItemParamsSchema = `{"type":"object","required":["title","content","source_type","workspace_id"],"properties":{"title":{"type":"string"},"content":{"type":"string"},"source_type":{"type":"string"},"workspace_id":{"type":"string"},"source_system":{"type":"string"},"source_object_id":{"type":"string"},"refresh_strategy":{"type":"string"},"delete_behavior":{"type":"string"},"permission_context":{"type":"string"},"dedup_on_ingest":{"type":"boolean"}},"additionalProperties":false}`
+	updateKnowledgeItemParamsSchema = `{"type":"object","required":["id"],"properties":{"id":{"type":"string"},"title":{"type":"string"},"content":{"type":"string"}},"additionalProperties":false}`
+	queryMetricsParamsSchema        = `{"type":"object","required":["metric","workspace_id"],"properties":{"metric":{"type":"string","enum":["sales_funnel","deal_aging","win_rate","forecast","case_volume","case_backlog","mttr"]},"workspace_id":{"type":"string"},"from":{"type":"string"},"to":{"type":"string"}},"additionalProperties":false}`
+)
+
 type BuiltinServices struct {
-	DB      *sql.DB
-	Case    *crm.CaseService
-	Lead    *crm.LeadService
-	Account *crm.AccountService
-	Deal    *crm.DealService
-	Ingest  knowledgeIngestor
+	DB       *sql.DB
+	Case     *crm.CaseService
+	Lead     *crm.LeadService
+	Account  *crm.AccountService
+	Deal     *crm.DealService
+	Activity *crm.ActivityService
+	Note     *crm.NoteService
+	Ingest   knowledgeIngestor
 }
 
 type knowledgeIngestor interface {
@@ -50,61 +82,91 @@ func builtinDefinitions() []builtinDefinition {
 		{
 			Name:                BuiltinCreateTask,
 			Description:         "Create a CRM task activity linked to an entity",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["owner_id","title","entity_type","entity_id"],"properties":{"owner_id":{"type":"string"},"title":{"type":"string"},"due_date":{"type":"string"},"entity_type":{"type":"string"},"entity_id":{"type":"string"}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(createTaskParamsSchema),
 			RequiredPermissions: []string{"tools:create_task"},
 		},
+		{
+			Name:                BuiltinCreateNote,
+			Description:         "Attach a note to a CRM entity; notes are internal unless is_internal is false",
+			InputSchema:         json.RawMessage(createNoteParamsSchema),
+			RequiredPermissions: []string{"tools:create_note"},
+		},
 		{
 			Name:                BuiltinUpdateCase,
 			Description:         "Update case status/priority and emit record.updated",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["case_id"],"properties":{"case_id":{"type":"string"},"status":{"type":"string"},"priority":{"type":"string"},"tags":{"type":"array","items":{"type":"string"}}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(updateCaseParamsSchema),
 			RequiredPermissions: []string{"tools:update_case"},
 		},
 		{
 			Name:                BuiltinUpdateDeal,
 			Description:         "Update deal status/stage/amount and emit record.updated",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["deal_id"],"properties":{"deal_id":{"type":"string"},"status":{"type":"string"},"stage_id":{"type":"string"},"amount":{"type":"number"}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(updateDealParamsSchema),
 			RequiredPermissions: []string{"tools:update_deal"},
 		},
+		{
+			Name:                BuiltinCreateDeal,
+			Description:         "Create a deal for an account in a pipeline stage",
+			InputSchema:         json.RawMessage(createDealParamsSchema),
+			RequiredPermissions: []string{"tools:create_deal"},
+		},
 		{
 			Name:                BuiltinSendReply,
 			Description:         "Create a case reply note",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["case_id","body"],"properties":{"case_id":{"type":"string"},"body":{"type":"string"},"is_internal":{"type":"boolean"}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(sendReplyParamsSchema),
 			RequiredPermissions: []string{"tools:send_reply"},
 		},
 		{
 			Name:                BuiltinGetLead,
 			Description:         "Fetch a lead by id in current workspace",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["lead_id"],"properties":{"lead_id":{"type":"string"}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(getLeadParamsSchema),
 			RequiredPermissions: []string{"tools:get_lead"},
 		},
 		{
 			Name:                BuiltinGetAccount,
 			Description:         "Fetch an account by id in current workspace",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["account_id"],"properties":{"account_id":{"type":"string"}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(getAccountParamsSchema),
 			RequiredPermissions: []string{"tools:get_account"},
 		},
 		{
 			Name:                BuiltinGetDeal,
 			Description:         "Fetch a deal by id in current workspace",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["deal_id"],"properties":{"deal_id":{"type":"string"}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(getDealParamsSchema),
 			RequiredPermissions: []string{"tools:get_deal"},
 		},
+		{
+			Name:                BuiltinUpdateLead,
+			Description:         "Update lead status/owner/metadata and return the lead",
+			InputSchema:         json.RawMessage(updateLeadParamsSchema),
+			RequiredPermissions: []string{"tools:update_lead"},
+		},
+		{
+			Name:                BuiltinGetCase,
+			Description:         "Fetch a case by id in current workspace",
+			InputSchema:         json.RawMessage(getCaseParamsSchema),
+			RequiredPermissions: []string{"tools:get_case"},
+		},
+		{
+			Name:                BuiltinListCases,
+			Description:         "List cases in current workspace filtered by status/owner/priority",
+			InputSchema:         json.RawMessage(listCasesParamsSchema),
+			RequiredPermissions: []string{"tools:list_cases"},
+		},
 		{
 			Name:                BuiltinCreateKnowledgeItem,
 			Description:         "Create knowledge item from title/content/source",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["title","content","source_type","workspace_id"],"properties":{"title":{"type":"string"},"content":{"type":"string"},"source_type":{"type":"string"},"workspace_id":{"type":"string"},"source_system":{"type":"string"},"source_object_id":{"type":"string"},"refresh_strategy":{"type":"string"},"delete_behavior":{"type":"string"},"permission_context":{"type":"string"}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(createKnowledgeItemParamsSchema),
 			RequiredPermissions: []string{"tools:create_knowledge_item"},
 		},
 		{
 			Name:                BuiltinUpdateKnowledgeItem,
 			Description:         "Update an existing knowledge item title/content",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["id"],"properties":{"id":{"type":"string"},"title":{"type":"string"},"content":{"type":"string"}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(updateKnowledgeItemParamsSchema),
 			RequiredPermissions: []string{"tools:update_knowledge_item"},
 		},
 		{
 			Name:                BuiltinQueryMetrics,
 			Description:         "Query aggregated CRM metrics",
-			InputSchema:         json.RawMessage(`{"type":"object","required":["metric","workspace_id"],"properties":{"metric":{"type":"string","enum":["sales_funnel","deal_aging","case_volume","case_backlog","mttr"]},"workspace_id":{"type":"string"},"from":{"type":"string"},"to":{"type":"string"}},"additionalProperties":false}`),
+			InputSchema:         json.RawMessage(queryMetricsParamsSchema),
 			RequiredPermissions: []string{"tools:query_metrics"},
 		},
 	}
@@ -189,13 +251,18 @@ func RegisterBuiltInExecutors(registry *ToolRegistry, services BuiltinServices)
 		name     string
 		executor ToolExecutor
 	}{
-		{name: BuiltinCreateTask, executor: NewCreateTaskExecutor(services.DB)},
+		{name: BuiltinCreateTask, executor: NewCreateTaskExecutor(services.Activity)},
+		{name: BuiltinCreateNote, executor: NewCreateNoteExecutor(services.Note)},
 		{name: BuiltinUpdateCase, executor: NewUpdateCaseExecutor(services.Case)},
 		{name: BuiltinUpdateDeal, executor: NewUpdateDealExecutor(services.Deal)},
+		{name: BuiltinCreateDeal, executor: NewCreateDealExecutor(services.Deal, services.Account)},
 		{name: BuiltinSendReply, executor: NewSendReplyExecutor(services.DB, services.Case)},
 		{name: BuiltinGetLead, executor: NewGetLeadExecutor(services.Lead)},
+		{name: BuiltinUpdateLead, executor: NewUpdateLeadExecutor(services.Lead)},
 		{name: BuiltinGetAccount, executor: NewGetAccountExecutor(services.Account)},
 		{name: BuiltinGetDeal, executor: NewGetDealExecutor(services.Deal)},
+		{name: BuiltinGetCase, executor: NewGetCaseExecutor(services.Case)},
+		{name: BuiltinListCases, executor: NewListCasesExecutor(services.Case)},
 		{name: BuiltinCreateKnowledgeItem, executor: NewCreateKnowledgeItemExecutor(services.Ingest)},
 		{name: BuiltinUpdateKnowledgeItem, executor: NewUpdateKnowledgeItemExecutor(services.DB)},
 		{name: BuiltinQueryMetrics, executor: NewQueryMetricsExecutor(services.DB)},
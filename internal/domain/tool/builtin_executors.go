@@ -21,10 +21,14 @@ const errInvalidParams = "%w: invalid params"
 
 const errDBNotConfigured = "%w: db not configured"
 
-type CreateTaskExecutor struct{ db *sql.DB }
+type CreateTaskExecutor struct{ activities *crm.ActivityService }
 
-func NewCreateTaskExecutor(db *sql.DB) ToolExecutor {
-	return &CreateTaskExecutor{db: db}
+func NewCreateTaskExecutor(activities *crm.ActivityService) ToolExecutor {
+	return &CreateTaskExecutor{activities: activities}
+}
+
+func (*CreateTaskExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(createTaskParamsSchema)
 }
 
 type createTaskParams struct {
@@ -44,11 +48,20 @@ func (e *CreateTaskExecutor) Execute(ctx context.Context, params json.RawMessage
 	if err != nil {
 		return nil, err
 	}
-	taskID, createdAt, err := e.insertTaskActivity(ctx, workspaceID, in)
+	if ctxkeys.IsDryRun(ctx) {
+		return marshalDryRun(map[string]any{
+			"owner_id":    in.OwnerID,
+			"title":       in.Title,
+			"due_date":    in.DueDate,
+			"entity_type": in.EntityType,
+			"entity_id":   in.EntityID,
+		}), nil
+	}
+	task, err := e.createTask(ctx, workspaceID, in)
 	if err != nil {
 		return nil, err
 	}
-	return marshalTaskCreated(taskID, createdAt), nil
+	return marshalTaskCreated(task.ID, task.CreatedAt.UTC().Format(time.RFC3339)), nil
 }
 
 func parseCreateTaskParams(params json.RawMessage) (createTaskParams, error) {
@@ -62,22 +75,23 @@ func parseCreateTaskParams(params json.RawMessage) (createTaskParams, error) {
 	return in, nil
 }
 
-func (e *CreateTaskExecutor) insertTaskActivity(ctx context.Context, workspaceID string, in createTaskParams) (string, string, error) {
-	if e.db == nil {
-		return "", "", fmt.Errorf(errDBNotConfigured, ErrBuiltinExecutionFailed)
-	}
-	taskID := uuid.NewV7().String()
-	now := time.Now().UTC().Format(time.RFC3339)
-	_, err := e.db.ExecContext(ctx, `
-		INSERT INTO activity (
-			id, workspace_id, activity_type, entity_type, entity_id,
-			owner_id, subject, status, due_at, created_at, updated_at
-		) VALUES (?, ?, 'task', ?, ?, ?, ?, 'pending', ?, ?, ?)
-	`, taskID, workspaceID, in.EntityType, in.EntityID, in.OwnerID, in.Title, nullableString(in.DueDate), now, now)
+func (e *CreateTaskExecutor) createTask(ctx context.Context, workspaceID string, in createTaskParams) (*crm.Activity, error) {
+	if e.activities == nil {
+		return nil, fmt.Errorf("%w: activity service not configured", ErrBuiltinExecutionFailed)
+	}
+	task, err := e.activities.Create(ctx, crm.CreateActivityInput{
+		WorkspaceID:  workspaceID,
+		ActivityType: "task",
+		EntityType:   in.EntityType,
+		EntityID:     in.EntityID,
+		OwnerID:      in.OwnerID,
+		Subject:      in.Title,
+		DueAt:        in.DueDate,
+	})
 	if err != nil {
-		return "", "", fmt.Errorf("%w: create activity: %w", ErrBuiltinExecutionFailed, err)
+		return nil, fmt.Errorf("%w: create activity: %w", ErrBuiltinExecutionFailed, err)
 	}
-	return taskID, now, nil
+	return task, nil
 }
 
 func marshalTaskCreated(taskID, createdAt string) json.RawMessage {
@@ -85,12 +99,81 @@ func marshalTaskCreated(taskID, createdAt string) json.RawMessage {
 	return out
 }
 
+type CreateNoteExecutor struct{ notes *crm.NoteService }
+
+func NewCreateNoteExecutor(notes *crm.NoteService) ToolExecutor {
+	return &CreateNoteExecutor{notes: notes}
+}
+
+func (*CreateNoteExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(createNoteParamsSchema)
+}
+
+type createNoteParams struct {
+	AuthorID   string `json:"author_id"`
+	Content    string `json:"content"`
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+	IsInternal *bool  `json:"is_internal"`
+}
+
+func (e *CreateNoteExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
+	in, err := parseCreateNoteParams(params)
+	if err != nil {
+		return nil, err
+	}
+	workspaceID, err := workspaceIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ctxkeys.IsDryRun(ctx) {
+		return marshalDryRun(map[string]any{
+			"author_id":   in.AuthorID,
+			"content":     in.Content,
+			"entity_type": in.EntityType,
+			"entity_id":   in.EntityID,
+			"is_internal": in.IsInternal == nil || *in.IsInternal,
+		}), nil
+	}
+	if e.notes == nil {
+		return nil, fmt.Errorf("%w: note service not configured", ErrBuiltinExecutionFailed)
+	}
+	note, err := e.notes.Create(ctx, crm.CreateNoteInput{
+		WorkspaceID: workspaceID,
+		EntityType:  in.EntityType,
+		EntityID:    in.EntityID,
+		AuthorID:    in.AuthorID,
+		Content:     in.Content,
+		IsInternal:  in.IsInternal == nil || *in.IsInternal,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: create note: %w", ErrBuiltinExecutionFailed, err)
+	}
+	out, _ := json.Marshal(map[string]any{"note_id": note.ID, "created_at": note.CreatedAt.UTC().Format(time.RFC3339)})
+	return out, nil
+}
+
+func parseCreateNoteParams(params json.RawMessage) (createNoteParams, error) {
+	var in createNoteParams
+	if err := json.Unmarshal(params, &in); err != nil {
+		return createNoteParams{}, fmt.Errorf(errInvalidParams, ErrBuiltinExecutionFailed)
+	}
+	if in.AuthorID == "" || strings.TrimSpace(in.Content) == "" || in.EntityType == "" || in.EntityID == "" {
+		return createNoteParams{}, fmt.Errorf("%w: author_id, content, entity_type and entity_id are required", ErrBuiltinExecutionFailed)
+	}
+	return in, nil
+}
+
 type UpdateCaseExecutor struct{ cases *crm.CaseService }
 
 func NewUpdateCaseExecutor(cases *crm.CaseService) ToolExecutor {
 	return &UpdateCaseExecutor{cases: cases}
 }
 
+func (*UpdateCaseExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(updateCaseParamsSchema)
+}
+
 type updateCaseParams struct {
 	CaseID   string   `json:"case_id"`
 	Status   string   `json:"status"`
@@ -107,6 +190,9 @@ func (e *UpdateCaseExecutor) Execute(ctx context.Context, params json.RawMessage
 	if err != nil {
 		return nil, err
 	}
+	if ctxkeys.IsDryRun(ctx) {
+		return e.previewUpdateCase(ctx, workspaceID, in)
+	}
 	updated, err := e.updateCase(ctx, workspaceID, in)
 	if err != nil {
 		return nil, err
@@ -126,12 +212,9 @@ func parseUpdateCaseParams(params json.RawMessage) (updateCaseParams, error) {
 }
 
 func (e *UpdateCaseExecutor) updateCase(ctx context.Context, workspaceID string, in updateCaseParams) (*crm.CaseTicket, error) {
-	if e.cases == nil {
-		return nil, fmt.Errorf("%w: case service not configured", ErrBuiltinExecutionFailed)
-	}
-	existing, err := e.cases.Get(ctx, workspaceID, in.CaseID)
+	existing, err := e.getCase(ctx, workspaceID, in.CaseID)
 	if err != nil {
-		return nil, fmt.Errorf("%w: case not found", ErrBuiltinExecutionFailed)
+		return nil, err
 	}
 	updated, err := e.cases.Update(ctx, workspaceID, in.CaseID, buildUpdateCaseInput(existing, in))
 	if err != nil {
@@ -140,6 +223,32 @@ func (e *UpdateCaseExecutor) updateCase(ctx context.Context, workspaceID string,
 	return updated, nil
 }
 
+// previewUpdateCase reports the case fields an update would set.
+func (e *UpdateCaseExecutor) previewUpdateCase(ctx context.Context, workspaceID string, in updateCaseParams) (json.RawMessage, error) {
+	existing, err := e.getCase(ctx, workspaceID, in.CaseID)
+	if err != nil {
+		return nil, err
+	}
+	next := buildUpdateCaseInput(existing, in)
+	return marshalDryRun(map[string]any{
+		"case_id":  existing.ID,
+		"status":   next.Status,
+		"priority": next.Priority,
+		"metadata": next.Metadata,
+	}), nil
+}
+
+func (e *UpdateCaseExecutor) getCase(ctx context.Context, workspaceID, caseID string) (*crm.CaseTicket, error) {
+	if e.cases == nil {
+		return nil, fmt.Errorf("%w: case service not configured", ErrBuiltinExecutionFailed)
+	}
+	existing, err := e.cases.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: case not found", ErrBuiltinExecutionFailed)
+	}
+	return existing, nil
+}
+
 func buildUpdateCaseInput(existing *crm.CaseTicket, in updateCaseParams) crm.UpdateCaseInput {
 	return crm.UpdateCaseInput{
 		AccountID:   derefString(existing.AccountID),
@@ -177,6 +286,10 @@ func NewUpdateDealExecutor(deals *crm.DealService) ToolExecutor {
 	return &UpdateDealExecutor{deals: deals}
 }
 
+func (*UpdateDealExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(updateDealParamsSchema)
+}
+
 type updateDealParams struct {
 	DealID  string   `json:"deal_id"`
 	Status  string   `json:"status"`
@@ -193,6 +306,9 @@ func (e *UpdateDealExecutor) Execute(ctx context.Context, params json.RawMessage
 	if err != nil {
 		return nil, err
 	}
+	if ctxkeys.IsDryRun(ctx) {
+		return e.previewUpdateDeal(ctx, workspaceID, in)
+	}
 	updated, err := e.updateDeal(ctx, workspaceID, in)
 	if err != nil {
 		return nil, err
@@ -212,14 +328,45 @@ func parseUpdateDealParams(params json.RawMessage) (updateDealParams, error) {
 }
 
 func (e *UpdateDealExecutor) updateDeal(ctx context.Context, workspaceID string, in updateDealParams) (*crm.Deal, error) {
+	existing, err := e.getDeal(ctx, workspaceID, in.DealID)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := e.deals.Update(ctx, workspaceID, in.DealID, buildUpdateDealInput(existing, in))
+	if err != nil {
+		return nil, fmt.Errorf("%w: update deal: %w", ErrBuiltinExecutionFailed, err)
+	}
+	return updated, nil
+}
+
+// previewUpdateDeal reports the deal fields an update would set.
+func (e *UpdateDealExecutor) previewUpdateDeal(ctx context.Context, workspaceID string, in updateDealParams) (json.RawMessage, error) {
+	existing, err := e.getDeal(ctx, workspaceID, in.DealID)
+	if err != nil {
+		return nil, err
+	}
+	next := buildUpdateDealInput(existing, in)
+	return marshalDryRun(map[string]any{
+		"deal_id":  existing.ID,
+		"status":   next.Status,
+		"stage_id": next.StageID,
+		"amount":   next.Amount,
+	}), nil
+}
+
+func (e *UpdateDealExecutor) getDeal(ctx context.Context, workspaceID, dealID string) (*crm.Deal, error) {
 	if e.deals == nil {
 		return nil, fmt.Errorf("%w: deal service not configured", ErrBuiltinExecutionFailed)
 	}
-	existing, err := e.deals.Get(ctx, workspaceID, in.DealID)
+	existing, err := e.deals.Get(ctx, workspaceID, dealID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: deal not found", ErrBuiltinExecutionFailed)
 	}
-	updated, err := e.deals.Update(ctx, workspaceID, in.DealID, crm.UpdateDealInput{
+	return existing, nil
+}
+
+func buildUpdateDealInput(existing *crm.Deal, in updateDealParams) crm.UpdateDealInput {
+	return crm.UpdateDealInput{
 		AccountID:     existing.AccountID,
 		ContactID:     derefString(existing.ContactID),
 		PipelineID:    existing.PipelineID,
@@ -231,11 +378,7 @@ func (e *UpdateDealExecutor) updateDeal(ctx context.Context, workspaceID string,
 		ExpectedClose: derefString(existing.ExpectedClose),
 		Status:        firstNonEmpty(in.Status, existing.Status),
 		Metadata:      derefString(existing.Metadata),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("%w: update deal: %w", ErrBuiltinExecutionFailed, err)
 	}
-	return updated, nil
 }
 
 func firstNonNilFloat(primary, fallback *float64) *float64 {
@@ -250,6 +393,101 @@ func marshalDealUpdated(updated *crm.Deal) json.RawMessage {
 	return out
 }
 
+// CreateDealExecutor opens a deal for an account, e.g. when prospecting
+// converts a hot lead. DealService.Create checks that every ID belongs to
+// the workspace and that the stage belongs to the pipeline.
+type CreateDealExecutor struct {
+	deals    *crm.DealService
+	accounts *crm.AccountService
+}
+
+func NewCreateDealExecutor(deals *crm.DealService, accounts *crm.AccountService) ToolExecutor {
+	return &CreateDealExecutor{deals: deals, accounts: accounts}
+}
+
+func (*CreateDealExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(createDealParamsSchema)
+}
+
+type createDealParams struct {
+	AccountID  string   `json:"account_id"`
+	PipelineID string   `json:"pipeline_id"`
+	StageID    string   `json:"stage_id"`
+	OwnerID    string   `json:"owner_id"`
+	Amount     *float64 `json:"amount"`
+	Title      string   `json:"title"`
+}
+
+func (e *CreateDealExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
+	in, err := parseCreateDealParams(params)
+	if err != nil {
+		return nil, err
+	}
+	workspaceID, err := workspaceIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if e.deals == nil || e.accounts == nil {
+		return nil, fmt.Errorf("%w: deal service not configured", ErrBuiltinExecutionFailed)
+	}
+	title, err := e.dealTitle(ctx, workspaceID, in)
+	if err != nil {
+		return nil, err
+	}
+	if ctxkeys.IsDryRun(ctx) {
+		return marshalDryRun(map[string]any{
+			"account_id":  in.AccountID,
+			"pipeline_id": in.PipelineID,
+			"stage_id":    in.StageID,
+			"owner_id":    in.OwnerID,
+			"amount":      in.Amount,
+			"title":       title,
+		}), nil
+	}
+	created, err := e.deals.Create(ctx, crm.CreateDealInput{
+		WorkspaceID: workspaceID,
+		AccountID:   in.AccountID,
+		PipelineID:  in.PipelineID,
+		StageID:     in.StageID,
+		OwnerID:     in.OwnerID,
+		Title:       title,
+		Amount:      in.Amount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: create deal: %w", ErrBuiltinExecutionFailed, err)
+	}
+	out, _ := json.Marshal(map[string]any{
+		"deal_id":    created.ID,
+		"stage_id":   created.StageID,
+		"created_at": created.CreatedAt.UTC().Format(time.RFC3339),
+	})
+	return out, nil
+}
+
+func parseCreateDealParams(params json.RawMessage) (createDealParams, error) {
+	var in createDealParams
+	if err := json.Unmarshal(params, &in); err != nil {
+		return createDealParams{}, fmt.Errorf(errInvalidParams, ErrBuiltinExecutionFailed)
+	}
+	if in.AccountID == "" || in.PipelineID == "" || in.StageID == "" || in.OwnerID == "" {
+		return createDealParams{}, fmt.Errorf("%w: account_id, pipeline_id, stage_id and owner_id are required", ErrBuiltinExecutionFailed)
+	}
+	return in, nil
+}
+
+// dealTitle returns the requested title, defaulting to the account name.
+// The lookup also rejects accounts outside the workspace.
+func (e *CreateDealExecutor) dealTitle(ctx context.Context, workspaceID string, in createDealParams) (string, error) {
+	account, err := e.accounts.Get(ctx, workspaceID, in.AccountID)
+	if err != nil {
+		return "", fmt.Errorf("%w: account not found", ErrBuiltinExecutionFailed)
+	}
+	if title := strings.TrimSpace(in.Title); title != "" {
+		return title, nil
+	}
+	return account.Name, nil
+}
+
 type SendReplyExecutor struct {
 	db    *sql.DB
 	cases *crm.CaseService
@@ -259,10 +497,16 @@ func NewSendReplyExecutor(db *sql.DB, cases *crm.CaseService) ToolExecutor {
 	return &SendReplyExecutor{db: db, cases: cases}
 }
 
+func (*SendReplyExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(sendReplyParamsSchema)
+}
+
 type sendReplyParams struct {
 	CaseID     string `json:"case_id"`
 	Body       string `json:"body"`
 	IsInternal bool   `json:"is_internal"`
+	// ParentNoteID threads the reply under a note of the same case.
+	ParentNoteID string `json:"parent_note_id"`
 }
 
 func (e *SendReplyExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
@@ -274,7 +518,23 @@ func (e *SendReplyExecutor) Execute(ctx context.Context, params json.RawMessage)
 	if err != nil {
 		return nil, err
 	}
-	noteID, createdAt, err := e.insertReplyNote(ctx, workspaceID, in)
+	authorID, err := e.replyAuthor(ctx, workspaceID, in.CaseID)
+	if err != nil {
+		return nil, err
+	}
+	if err = e.ensureReplyParent(ctx, workspaceID, in); err != nil {
+		return nil, err
+	}
+	if ctxkeys.IsDryRun(ctx) {
+		return marshalDryRun(map[string]any{
+			"case_id":        in.CaseID,
+			"author_id":      authorID,
+			"body":           in.Body,
+			"is_internal":    in.IsInternal,
+			"parent_note_id": in.ParentNoteID,
+		}), nil
+	}
+	noteID, createdAt, err := e.insertReplyNote(ctx, workspaceID, authorID, in)
 	if err != nil {
 		return nil, err
 	}
@@ -292,29 +552,58 @@ func parseSendReplyParams(params json.RawMessage) (sendReplyParams, error) {
 	return in, nil
 }
 
-func (e *SendReplyExecutor) insertReplyNote(ctx context.Context, workspaceID string, in sendReplyParams) (string, string, error) {
+// replyAuthor checks the case exists and returns who the reply is attributed
+// to: the acting user, else the case owner.
+func (e *SendReplyExecutor) replyAuthor(ctx context.Context, workspaceID, caseID string) (string, error) {
 	if e.cases == nil || e.db == nil {
-		return "", "", fmt.Errorf("%w: case service or db not configured", ErrBuiltinExecutionFailed)
+		return "", fmt.Errorf("%w: case service or db not configured", ErrBuiltinExecutionFailed)
+	}
+	caseTicket, err := e.cases.Get(ctx, workspaceID, caseID)
+	if err != nil {
+		return "", fmt.Errorf("%w: case not found", ErrBuiltinExecutionFailed)
+	}
+	return firstNonEmpty(userIDFromContext(ctx), caseTicket.OwnerID), nil
+}
+
+// ensureReplyParent checks that the note being replied to is on the case.
+func (e *SendReplyExecutor) ensureReplyParent(ctx context.Context, workspaceID string, in sendReplyParams) error {
+	if in.ParentNoteID == "" {
+		return nil
 	}
-	caseTicket, err := e.cases.Get(ctx, workspaceID, in.CaseID)
+	var exists int
+	err := e.db.QueryRowContext(ctx, `
+		SELECT 1 FROM note
+		WHERE id = ? AND workspace_id = ? AND entity_type = 'case' AND entity_id = ?
+	`, in.ParentNoteID, workspaceID, in.CaseID).Scan(&exists)
 	if err != nil {
-		return "", "", fmt.Errorf("%w: case not found", ErrBuiltinExecutionFailed)
+		return fmt.Errorf("%w: parent note not found on case", ErrBuiltinExecutionFailed)
 	}
-	authorID := firstNonEmpty(userIDFromContext(ctx), caseTicket.OwnerID)
+	return nil
+}
+
+func (e *SendReplyExecutor) insertReplyNote(ctx context.Context, workspaceID, authorID string, in sendReplyParams) (string, string, error) {
 	noteID := uuid.NewV7().String()
 	now := time.Now().UTC().Format(time.RFC3339)
-	_, err = e.db.ExecContext(ctx, `
+	_, err := e.db.ExecContext(ctx, `
 		INSERT INTO note (
 			id, workspace_id, entity_type, entity_id, author_id,
-			content, is_internal, created_at, updated_at
-		) VALUES (?, ?, 'case', ?, ?, ?, ?, ?, ?)
-	`, noteID, workspaceID, in.CaseID, authorID, in.Body, in.IsInternal, now, now)
+			content, is_internal, created_at, updated_at, parent_note_id
+		) VALUES (?, ?, 'case', ?, ?, ?, ?, ?, ?, ?)
+	`, noteID, workspaceID, in.CaseID, authorID, in.Body, in.IsInternal, now, now, nullIfEmpty(in.ParentNoteID))
 	if err != nil {
 		return "", "", fmt.Errorf("%w: create note: %w", ErrBuiltinExecutionFailed, err)
 	}
 	return noteID, now, nil
 }
 
+// marshalDryRun encodes the preview a write executor returns under
+// ctxkeys.DryRun: what it would write, marked with "dry_run": true.
+func marshalDryRun(preview map[string]any) json.RawMessage {
+	preview["dry_run"] = true
+	out, _ := json.Marshal(preview)
+	return out
+}
+
 func marshalReplyCreated(noteID, createdAt string) json.RawMessage {
 	out, _ := json.Marshal(map[string]any{"note_id": noteID, "created_at": createdAt})
 	return out
@@ -333,6 +622,14 @@ func userIDFromContext(ctx context.Context) string {
 	return userID
 }
 
+// nullIfEmpty maps "" to SQL NULL for optional columns.
+func nullIfEmpty(v string) any {
+	if v == "" {
+		return nil
+	}
+	return v
+}
+
 func derefString(v *string) string {
 	if v == nil {
 		return ""
@@ -363,6 +660,10 @@ func NewGetLeadExecutor(leads *crm.LeadService) ToolExecutor {
 	return &GetLeadExecutor{leads: leads}
 }
 
+func (*GetLeadExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(getLeadParamsSchema)
+}
+
 type getLeadParams struct {
 	LeadID string `json:"lead_id"`
 }
@@ -376,6 +677,134 @@ func (e *GetLeadExecutor) Execute(ctx context.Context, params json.RawMessage) (
 		func(wsID string) (any, error) { return e.leads.Get(ctx, wsID, in.LeadID) })
 }
 
+type UpdateLeadExecutor struct{ leads *crm.LeadService }
+
+func NewUpdateLeadExecutor(leads *crm.LeadService) ToolExecutor {
+	return &UpdateLeadExecutor{leads: leads}
+}
+
+func (*UpdateLeadExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(updateLeadParamsSchema)
+}
+
+// leadStatuses mirrors the CHECK constraint on lead.status.
+var leadStatuses = map[string]bool{
+	"new":       true,
+	"contacted": true,
+	"qualified": true,
+	"converted": true,
+	"lost":      true,
+}
+
+type updateLeadParams struct {
+	LeadID   string         `json:"lead_id"`
+	Status   string         `json:"status"`
+	OwnerID  string         `json:"owner_id"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+func (e *UpdateLeadExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
+	in, err := parseUpdateLeadParams(params)
+	if err != nil {
+		return nil, err
+	}
+	workspaceID, err := workspaceIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ctxkeys.IsDryRun(ctx) {
+		return e.previewUpdateLead(ctx, workspaceID, in)
+	}
+	updated, err := e.updateLead(ctx, workspaceID, in)
+	if err != nil {
+		return nil, err
+	}
+	out, _ := json.Marshal(map[string]any{"lead": updated})
+	return out, nil
+}
+
+func parseUpdateLeadParams(params json.RawMessage) (updateLeadParams, error) {
+	var in updateLeadParams
+	if err := json.Unmarshal(params, &in); err != nil {
+		return updateLeadParams{}, fmt.Errorf(errInvalidParams, ErrBuiltinExecutionFailed)
+	}
+	if in.LeadID == "" {
+		return updateLeadParams{}, fmt.Errorf("%w: lead_id is required", ErrBuiltinExecutionFailed)
+	}
+	if in.Status != "" && !leadStatuses[in.Status] {
+		return updateLeadParams{}, fmt.Errorf("%w: invalid lead status %q", ErrBuiltinExecutionFailed, in.Status)
+	}
+	return in, nil
+}
+
+func (e *UpdateLeadExecutor) updateLead(ctx context.Context, workspaceID string, in updateLeadParams) (*crm.Lead, error) {
+	next, err := e.buildUpdateLeadInput(ctx, workspaceID, in)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := e.leads.Update(ctx, workspaceID, in.LeadID, next)
+	if err != nil {
+		return nil, fmt.Errorf("%w: update lead: %w", ErrBuiltinExecutionFailed, err)
+	}
+	return updated, nil
+}
+
+// previewUpdateLead reports the lead fields an update would set.
+func (e *UpdateLeadExecutor) previewUpdateLead(ctx context.Context, workspaceID string, in updateLeadParams) (json.RawMessage, error) {
+	next, err := e.buildUpdateLeadInput(ctx, workspaceID, in)
+	if err != nil {
+		return nil, err
+	}
+	return marshalDryRun(map[string]any{
+		"lead_id":  in.LeadID,
+		"status":   next.Status,
+		"owner_id": next.OwnerID,
+		"metadata": next.Metadata,
+	}), nil
+}
+
+func (e *UpdateLeadExecutor) buildUpdateLeadInput(ctx context.Context, workspaceID string, in updateLeadParams) (crm.UpdateLeadInput, error) {
+	if e.leads == nil {
+		return crm.UpdateLeadInput{}, fmt.Errorf("%w: lead service not configured", ErrBuiltinExecutionFailed)
+	}
+	existing, err := e.leads.Get(ctx, workspaceID, in.LeadID)
+	if err != nil {
+		return crm.UpdateLeadInput{}, fmt.Errorf("%w: lead not found: %w", ErrBuiltinExecutionFailed, err)
+	}
+	metadata, err := mergeLeadMetadata(existing.Metadata, in.Metadata)
+	if err != nil {
+		return crm.UpdateLeadInput{}, err
+	}
+	return crm.UpdateLeadInput{
+		ContactID: derefString(existing.ContactID),
+		AccountID: derefString(existing.AccountID),
+		Source:    derefString(existing.Source),
+		Status:    firstNonEmpty(in.Status, existing.Status),
+		OwnerID:   firstNonEmpty(in.OwnerID, existing.OwnerID),
+		// No score: the lead is rescored from its updated fields.
+		Metadata: metadata,
+	}, nil
+}
+
+// mergeLeadMetadata sets the given keys on the lead's metadata object,
+// keeping the keys it does not mention.
+func mergeLeadMetadata(existing *string, updates map[string]any) (string, error) {
+	if len(updates) == 0 {
+		return derefString(existing), nil
+	}
+	merged := map[string]any{}
+	if raw := derefString(existing); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &merged); err != nil {
+			return "", fmt.Errorf("%w: existing lead metadata is not an object", ErrBuiltinExecutionFailed)
+		}
+	}
+	for key, value := range updates {
+		merged[key] = value
+	}
+	raw, _ := json.Marshal(merged)
+	return string(raw), nil
+}
+
 // Task 4.5a — GetAccountExecutor
 type GetAccountExecutor struct{ accounts *crm.AccountService }
 
@@ -383,6 +812,10 @@ func NewGetAccountExecutor(accounts *crm.AccountService) ToolExecutor {
 	return &GetAccountExecutor{accounts: accounts}
 }
 
+func (*GetAccountExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(getAccountParamsSchema)
+}
+
 type getAccountParams struct {
 	AccountID string `json:"account_id"`
 }
@@ -402,6 +835,10 @@ func NewGetDealExecutor(deals *crm.DealService) ToolExecutor {
 	return &GetDealExecutor{deals: deals}
 }
 
+func (*GetDealExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(getDealParamsSchema)
+}
+
 type getDealParams struct {
 	DealID string `json:"deal_id"`
 }
@@ -415,9 +852,87 @@ func (e *GetDealExecutor) Execute(ctx context.Context, params json.RawMessage) (
 		func(wsID string) (any, error) { return e.deals.Get(ctx, wsID, in.DealID) })
 }
 
+type GetCaseExecutor struct{ cases *crm.CaseService }
+
+func NewGetCaseExecutor(cases *crm.CaseService) ToolExecutor {
+	return &GetCaseExecutor{cases: cases}
+}
+
+func (*GetCaseExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(getCaseParamsSchema)
+}
+
+type getCaseParams struct {
+	CaseID string `json:"case_id"`
+}
+
+func (e *GetCaseExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
+	var in getCaseParams
+	if err := json.Unmarshal(params, &in); err != nil {
+		return nil, fmt.Errorf(errInvalidParams, ErrBuiltinExecutionFailed)
+	}
+	return getEntityByID(ctx, in.CaseID, "case_id", "case", e.cases != nil,
+		func(wsID string) (any, error) { return e.cases.Get(ctx, wsID, in.CaseID) })
+}
+
+const (
+	listCasesDefaultLimit = 20
+	listCasesMaxLimit     = 100
+)
+
+type ListCasesExecutor struct{ cases *crm.CaseService }
+
+func NewListCasesExecutor(cases *crm.CaseService) ToolExecutor {
+	return &ListCasesExecutor{cases: cases}
+}
+
+func (*ListCasesExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(listCasesParamsSchema)
+}
+
+type listCasesParams struct {
+	Status   string `json:"status"`
+	OwnerID  string `json:"owner_id"`
+	Priority string `json:"priority"`
+	Limit    int    `json:"limit"`
+}
+
+func (e *ListCasesExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
+	var in listCasesParams
+	if err := json.Unmarshal(params, &in); err != nil {
+		return nil, fmt.Errorf(errInvalidParams, ErrBuiltinExecutionFailed)
+	}
+	workspaceID, err := workspaceIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if e.cases == nil {
+		return nil, fmt.Errorf("%w: case service not configured", ErrBuiltinExecutionFailed)
+	}
+	items, total, err := e.cases.List(ctx, workspaceID, crm.ListCasesInput{
+		Limit:    resolveListCasesLimit(in.Limit),
+		Status:   in.Status,
+		Priority: in.Priority,
+		OwnerID:  in.OwnerID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: list cases: %w", ErrBuiltinExecutionFailed, err)
+	}
+	out, _ := json.Marshal(map[string]any{"cases": items, "total": total})
+	return out, nil
+}
+
+func resolveListCasesLimit(limit int) int {
+	if limit <= 0 {
+		return listCasesDefaultLimit
+	}
+	return min(limit, listCasesMaxLimit)
+}
+
 // getEntityByID is a shared helper for single-entity lookup executors.
 // It validates the entity ID, extracts the workspace from context, checks the
 // service is configured, calls the provided getter, and marshals the result.
+// A missing entity wraps sql.ErrNoRows so callers can tell it from a failure.
 func getEntityByID(ctx context.Context, entityID, idField, resultKey string, svcConfigured bool, get func(wsID string) (any, error)) (json.RawMessage, error) {
 	if entityID == "" {
 		return nil, fmt.Errorf("%w: %s is required", ErrBuiltinExecutionFailed, idField)
@@ -430,8 +945,11 @@ func getEntityByID(ctx context.Context, entityID, idField, resultKey string, svc
 		return nil, fmt.Errorf("%w: %s service not configured", ErrBuiltinExecutionFailed, resultKey)
 	}
 	entity, err := get(workspaceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s not found: %w", ErrBuiltinExecutionFailed, resultKey, err)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("%w: %s not found", ErrBuiltinExecutionFailed, resultKey)
+		return nil, fmt.Errorf("%w: get %s: %w", ErrBuiltinExecutionFailed, resultKey, err)
 	}
 	out, _ := json.Marshal(map[string]any{resultKey: entity})
 	return out, nil
@@ -444,6 +962,10 @@ func NewCreateKnowledgeItemExecutor(ingest knowledgeIngestor) ToolExecutor {
 	return &CreateKnowledgeItemExecutor{ingest: ingest}
 }
 
+func (*CreateKnowledgeItemExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(createKnowledgeItemParamsSchema)
+}
+
 type createKnowledgeItemParams struct {
 	Title             string  `json:"title"`
 	Content           string  `json:"content"`
@@ -454,6 +976,9 @@ type createKnowledgeItemParams struct {
 	DeleteBehavior    *string `json:"delete_behavior"`
 	PermissionContext *string `json:"permission_context"`
 	WorkspaceID       string  `json:"workspace_id"`
+	// DedupOnIngest returns an existing item with the same content instead
+	// of creating a new one.
+	DedupOnIngest bool `json:"dedup_on_ingest"`
 }
 
 func (e *CreateKnowledgeItemExecutor) Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error) {
@@ -465,11 +990,23 @@ func (e *CreateKnowledgeItemExecutor) Execute(ctx context.Context, params json.R
 	if err != nil {
 		return nil, err
 	}
+	if ctxkeys.IsDryRun(ctx) {
+		return marshalDryRun(map[string]any{
+			"workspace_id":   workspaceID,
+			"title":          in.Title,
+			"source_type":    in.SourceType,
+			"content_length": len(in.Content),
+		}), nil
+	}
 	item, err := e.createKnowledgeItem(ctx, workspaceID, in)
 	if err != nil {
 		return nil, err
 	}
-	out, _ := json.Marshal(map[string]any{"knowledge_item_id": item.ID, "created_at": item.CreatedAt.Format(time.RFC3339)})
+	out, _ := json.Marshal(map[string]any{
+		"knowledge_item_id": item.ID,
+		"created_at":        item.CreatedAt.Format(time.RFC3339),
+		"was_duplicate":     item.WasDuplicate,
+	})
 	return out, nil
 }
 
@@ -505,6 +1042,7 @@ func (e *CreateKnowledgeItemExecutor) createKnowledgeItem(
 		PermissionContext: in.PermissionContext,
 		Title:             in.Title,
 		RawContent:        in.Content,
+		DedupOnIngest:     in.DedupOnIngest,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("%w: create knowledge item: %w", ErrBuiltinExecutionFailed, err)
@@ -523,6 +1061,10 @@ func NewUpdateKnowledgeItemExecutor(db *sql.DB) ToolExecutor {
 	return &UpdateKnowledgeItemExecutor{db: db}
 }
 
+func (*UpdateKnowledgeItemExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(updateKnowledgeItemParamsSchema)
+}
+
 type updateKnowledgeItemParams struct {
 	ID      string `json:"id"`
 	Title   string `json:"title"`
@@ -538,6 +1080,9 @@ func (e *UpdateKnowledgeItemExecutor) Execute(ctx context.Context, params json.R
 	if err != nil {
 		return nil, err
 	}
+	if ctxkeys.IsDryRun(ctx) {
+		return e.previewUpdateKnowledgeItem(ctx, workspaceID, in)
+	}
 	updateErr := e.updateKnowledgeItem(ctx, workspaceID, in)
 	if updateErr != nil {
 		return nil, updateErr
@@ -560,18 +1105,44 @@ func parseUpdateKnowledgeItemParams(params json.RawMessage) (updateKnowledgeItem
 	return in, nil
 }
 
+// previewUpdateKnowledgeItem reports the fields an update would set on an
+// existing item.
+func (e *UpdateKnowledgeItemExecutor) previewUpdateKnowledgeItem(ctx context.Context, workspaceID string, in updateKnowledgeItemParams) (json.RawMessage, error) {
+	if e.db == nil {
+		return nil, fmt.Errorf(errDBNotConfigured, ErrBuiltinExecutionFailed)
+	}
+	var exists int
+	err := e.db.QueryRowContext(ctx,
+		`SELECT 1 FROM knowledge_item WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL`,
+		in.ID, workspaceID,
+	).Scan(&exists)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: knowledge item not found", ErrBuiltinExecutionFailed)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: load knowledge item: %w", ErrBuiltinExecutionFailed, err)
+	}
+	return marshalDryRun(map[string]any{
+		"knowledge_item_id": in.ID,
+		"title":             in.Title,
+		"content":           in.Content,
+	}), nil
+}
+
 func (e *UpdateKnowledgeItemExecutor) updateKnowledgeItem(ctx context.Context, workspaceID string, in updateKnowledgeItemParams) error {
 	if e.db == nil {
 		return fmt.Errorf(errDBNotConfigured, ErrBuiltinExecutionFailed)
 	}
+	normalized := strings.TrimSpace(in.Content)
 	res, err := e.db.ExecContext(ctx, `
 		UPDATE knowledge_item
 		SET title = COALESCE(NULLIF(?, ''), title),
 		    raw_content = COALESCE(NULLIF(?, ''), raw_content),
 		    normalized_content = COALESCE(NULLIF(?, ''), normalized_content),
+		    normalized_hash = COALESCE(?, normalized_hash),
 		    updated_at = ?
 		WHERE id = ? AND workspace_id = ? AND deleted_at IS NULL
-	`, in.Title, in.Content, strings.TrimSpace(in.Content), time.Now().UTC(), in.ID, workspaceID)
+	`, in.Title, in.Content, normalized, knowledge.NormalizedContentHash(normalized), time.Now().UTC(), in.ID, workspaceID)
 	if err != nil {
 		return fmt.Errorf("%w: update knowledge item: %w", ErrBuiltinExecutionFailed, err)
 	}
@@ -585,10 +1156,18 @@ func (e *UpdateKnowledgeItemExecutor) updateKnowledgeItem(ctx context.Context, w
 // Task 4.5a — QueryMetricsExecutor
 type QueryMetricsExecutor struct{ db *sql.DB }
 
+// maxMetricRows caps the rows a query_metrics result carries; larger result
+// sets return the first rows with "truncated": true.
+const maxMetricRows = 100
+
 func NewQueryMetricsExecutor(db *sql.DB) ToolExecutor {
 	return &QueryMetricsExecutor{db: db}
 }
 
+func (*QueryMetricsExecutor) ParamsSchema() json.RawMessage {
+	return json.RawMessage(queryMetricsParamsSchema)
+}
+
 type queryMetricsParams struct {
 	Metric      string `json:"metric"`
 	WorkspaceID string `json:"workspace_id"`
@@ -612,10 +1191,15 @@ func (e *QueryMetricsExecutor) Execute(ctx context.Context, params json.RawMessa
 	if err != nil {
 		return nil, err
 	}
-	out, _ := json.Marshal(map[string]any{
+	result := map[string]any{
 		"metric": in.Metric,
 		"data":   data,
-	})
+	}
+	if len(data) > maxMetricRows {
+		result["data"] = data[:maxMetricRows]
+		result[resultTruncatedKey] = true
+	}
+	out, _ := json.Marshal(result)
 	return out, nil
 }
 
@@ -666,6 +1250,37 @@ func (e *QueryMetricsExecutor) queryMetric(ctx context.Context, workspaceID stri
 			  AND (? = '' OR d.created_at <= ?)
 			GROUP BY d.stage_id
 		`, workspaceID, from, from, to, to)
+	case "win_rate":
+		// Deals closed before closed_at existed fall back to updated_at.
+		return e.queryRowsAsMaps(ctx, `
+			SELECT COALESCE(SUM(d.status = 'won'), 0) AS won_count,
+			       COALESCE(SUM(d.status = 'lost'), 0) AS lost_count,
+			       COALESCE(CAST(SUM(d.status = 'won') AS REAL) / NULLIF(COUNT(*), 0), 0) AS win_rate
+			FROM deal d
+			WHERE d.workspace_id = ?
+			  AND d.deleted_at IS NULL
+			  AND d.status IN ('won', 'lost')
+			  AND (? = '' OR COALESCE(d.closed_at, d.updated_at) >= ?)
+			  AND (? = '' OR COALESCE(d.closed_at, d.updated_at) <= ?)
+		`, workspaceID, from, from, to, to)
+	case "forecast":
+		// Open deals weighted by their stage's win probability; from/to
+		// bound the expected close date.
+		return e.queryRowsAsMaps(ctx, `
+			SELECT d.pipeline_id,
+			       COUNT(*) AS open_deals,
+			       COALESCE(SUM(d.amount), 0) AS open_value,
+			       COALESCE(SUM(COALESCE(d.amount, 0) * COALESCE(ps.probability, 0)), 0) AS weighted_forecast
+			FROM deal d
+			JOIN pipeline_stage ps ON ps.id = d.stage_id
+			WHERE d.workspace_id = ?
+			  AND d.deleted_at IS NULL
+			  AND d.status = 'open'
+			  AND (? = '' OR d.expected_close >= ?)
+			  AND (? = '' OR d.expected_close <= ?)
+			GROUP BY d.pipeline_id
+			ORDER BY weighted_forecast DESC
+		`, workspaceID, from, from, to, to)
 	case "case_volume":
 		return e.queryRowsAsMaps(ctx, `
 			SELECT c.priority, c.status, COUNT(*) AS total
@@ -716,8 +1331,9 @@ func (e *QueryMetricsExecutor) queryRowsAsMaps(ctx context.Context, query string
 		return nil, fmt.Errorf("%w: read columns: %w", ErrBuiltinExecutionFailed, err)
 	}
 
+	// One row past the cap tells Execute the result was truncated.
 	out := make([]map[string]any, 0)
-	for rows.Next() {
+	for len(out) <= maxMetricRows && rows.Next() {
 		item, scanErr := scanMetricRow(rows, cols)
 		if scanErr != nil {
 			return nil, scanErr
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -20,7 +21,7 @@ func TestCreateTaskExecutor_Execute_CreatesActivity(t *testing.T) {
 	wsID := createWorkspace(t, db)
 	ownerID := createToolUser(t, db, wsID)
 
-	exec := NewCreateTaskExecutor(db)
+	exec := NewCreateTaskExecutor(crm.NewActivityService(db))
 	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
 
 	params := json.RawMessage(`{"owner_id":"` + ownerID + `","title":"Follow up","entity_type":"case","entity_id":"case-1"}`)
@@ -125,6 +126,270 @@ func TestSendReplyExecutor_Execute_CreatesNote(t *testing.T) {
 	}
 }
 
+func TestSendReplyExecutor_Execute_ThreadsUnderParentNote(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	caseSvc := crm.NewCaseService(db)
+	noteSvc := crm.NewNoteService(db)
+	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
+
+	created, err := caseSvc.Create(ctx, crm.CreateCaseInput{WorkspaceID: wsID, OwnerID: ownerID, Subject: "Threaded"})
+	if err != nil {
+		t.Fatalf("Create case error = %v", err)
+	}
+	other, err := caseSvc.Create(ctx, crm.CreateCaseInput{WorkspaceID: wsID, OwnerID: ownerID, Subject: "Other"})
+	if err != nil {
+		t.Fatalf("Create case error = %v", err)
+	}
+	question, err := noteSvc.Create(ctx, crm.CreateNoteInput{
+		WorkspaceID: wsID, EntityType: "case", EntityID: created.ID, AuthorID: ownerID, Content: "Customer question",
+	})
+	if err != nil {
+		t.Fatalf("Create note error = %v", err)
+	}
+
+	exec := NewSendReplyExecutor(db, caseSvc)
+	out, err := exec.Execute(ctx, json.RawMessage(`{"case_id":"`+created.ID+`","body":"Answer","parent_note_id":"`+question.ID+`"}`))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var decoded struct {
+		NoteID string `json:"note_id"`
+	}
+	_ = json.Unmarshal(out, &decoded)
+	reply, err := noteSvc.Get(ctx, wsID, decoded.NoteID)
+	if err != nil {
+		t.Fatalf("Get reply error = %v", err)
+	}
+	if reply.ParentNoteID == nil || *reply.ParentNoteID != question.ID {
+		t.Fatalf("reply parent = %v; want %s", reply.ParentNoteID, question.ID)
+	}
+
+	if _, err = exec.Execute(ctx, json.RawMessage(`{"case_id":"`+other.ID+`","body":"Answer","parent_note_id":"`+question.ID+`"}`)); !errors.Is(err, ErrBuiltinExecutionFailed) {
+		t.Fatalf("Execute(parent on another case) error = %v; want ErrBuiltinExecutionFailed", err)
+	}
+}
+
+func TestWriteExecutors_DryRun_DoNotPersist(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	caseSvc := crm.NewCaseService(db)
+
+	created, err := caseSvc.Create(context.Background(), crm.CreateCaseInput{
+		WorkspaceID: wsID,
+		OwnerID:     ownerID,
+		Subject:     "Dry run case",
+		Status:      "open",
+		Priority:    "medium",
+	})
+	if err != nil {
+		t.Fatalf("Create case error = %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
+	ctx = context.WithValue(ctx, ctxkeys.UserID, ownerID)
+	ctx = ctxkeys.WithDryRun(ctx)
+
+	cases := []struct {
+		name   string
+		exec   ToolExecutor
+		params string
+		table  string
+		check  func(t *testing.T, out map[string]any)
+	}{
+		{
+			name:   "create_task",
+			exec:   NewCreateTaskExecutor(crm.NewActivityService(db)),
+			params: `{"owner_id":"` + ownerID + `","title":"Follow up","entity_type":"case","entity_id":"` + created.ID + `"}`,
+			table:  "activity",
+			check: func(t *testing.T, out map[string]any) {
+				if out["title"] != "Follow up" {
+					t.Fatalf("preview title = %v", out["title"])
+				}
+			},
+		},
+		{
+			name:   "update_case",
+			exec:   NewUpdateCaseExecutor(caseSvc),
+			params: `{"case_id":"` + created.ID + `","status":"in_progress","priority":"high"}`,
+			table:  "case_ticket",
+			check: func(t *testing.T, out map[string]any) {
+				if out["status"] != "in_progress" || out["priority"] != "high" {
+					t.Fatalf("preview = %v, want planned status/priority", out)
+				}
+			},
+		},
+		{
+			name:   "send_reply",
+			exec:   NewSendReplyExecutor(db, caseSvc),
+			params: `{"case_id":"` + created.ID + `","body":"Reply body"}`,
+			table:  "note",
+			check: func(t *testing.T, out map[string]any) {
+				if out["author_id"] != ownerID || out["body"] != "Reply body" {
+					t.Fatalf("preview = %v, want author and body", out)
+				}
+			},
+		},
+		{
+			name:   "create_knowledge_item",
+			exec:   NewCreateKnowledgeItemExecutor(knowledge.NewIngestService(db, eventbus.New())),
+			params: `{"title":"KB1","content":"contenido","source_type":"document"}`,
+			table:  "knowledge_item",
+			check: func(t *testing.T, out map[string]any) {
+				if out["title"] != "KB1" {
+					t.Fatalf("preview title = %v", out["title"])
+				}
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		before := countRows(t, db, tc.table, wsID)
+		raw, err := tc.exec.Execute(ctx, json.RawMessage(tc.params))
+		if err != nil {
+			t.Fatalf("%s: Execute() error = %v", tc.name, err)
+		}
+		var out map[string]any
+		if err := json.Unmarshal(raw, &out); err != nil {
+			t.Fatalf("%s: decode output: %v", tc.name, err)
+		}
+		if out["dry_run"] != true {
+			t.Fatalf("%s: output = %s, want dry_run marker", tc.name, raw)
+		}
+		tc.check(t, out)
+		if after := countRows(t, db, tc.table, wsID); after != before {
+			t.Fatalf("%s: %s rows = %d, want unchanged %d", tc.name, tc.table, after, before)
+		}
+	}
+
+	unchanged, err := caseSvc.Get(context.Background(), wsID, created.ID)
+	if err != nil {
+		t.Fatalf("Get case error = %v", err)
+	}
+	if unchanged.Status != "open" || unchanged.Priority != "medium" {
+		t.Fatalf("dry-run updated case: status=%q priority=%q", unchanged.Status, unchanged.Priority)
+	}
+
+	// Validation still applies in dry-run.
+	if _, err := NewSendReplyExecutor(db, caseSvc).Execute(ctx, json.RawMessage(`{"case_id":"missing","body":"x"}`)); !errors.Is(err, ErrBuiltinExecutionFailed) {
+		t.Fatalf("dry-run reply to missing case error = %v, want ErrBuiltinExecutionFailed", err)
+	}
+}
+
+func TestNoteAndUpdateExecutors_DryRun_DoNotPersist(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	bg := context.Background()
+	accountID, pipelineID, stageID := createToolDealContext(t, db, wsID, ownerID)
+
+	dealSvc := crm.NewDealService(db)
+	deal, err := dealSvc.Create(bg, crm.CreateDealInput{
+		WorkspaceID: wsID, AccountID: accountID, PipelineID: pipelineID, StageID: stageID,
+		OwnerID: ownerID, Title: "Dry run deal", Status: "open",
+	})
+	if err != nil {
+		t.Fatalf("create deal: %v", err)
+	}
+	leadSvc := crm.NewLeadService(db)
+	lead, err := leadSvc.Create(bg, crm.CreateLeadInput{WorkspaceID: wsID, OwnerID: ownerID, Source: "web", Status: "new"})
+	if err != nil {
+		t.Fatalf("create lead: %v", err)
+	}
+	wsCtx := context.WithValue(bg, ctxkeys.WorkspaceID, wsID)
+	item, err := knowledge.NewIngestService(db, eventbus.New()).Ingest(wsCtx, knowledge.CreateKnowledgeItemInput{
+		WorkspaceID: wsID, SourceType: knowledge.SourceTypeDocument, Title: "Old title", RawContent: "old content",
+	})
+	if err != nil {
+		t.Fatalf("ingest: %v", err)
+	}
+	ctx := ctxkeys.WithDryRun(wsCtx)
+
+	cases := []struct {
+		name   string
+		exec   ToolExecutor
+		params string
+		want   map[string]any
+	}{
+		{
+			name:   "create_note",
+			exec:   NewCreateNoteExecutor(crm.NewNoteService(db)),
+			params: `{"author_id":"` + ownerID + `","content":"Draft","entity_type":"account","entity_id":"` + accountID + `"}`,
+			want:   map[string]any{"content": "Draft", "is_internal": true},
+		},
+		{
+			name:   "update_deal",
+			exec:   NewUpdateDealExecutor(dealSvc),
+			params: `{"deal_id":"` + deal.ID + `","status":"won","amount":500}`,
+			want:   map[string]any{"deal_id": deal.ID, "status": "won", "amount": float64(500)},
+		},
+		{
+			name:   "update_lead",
+			exec:   NewUpdateLeadExecutor(leadSvc),
+			params: `{"lead_id":"` + lead.ID + `","status":"qualified"}`,
+			want:   map[string]any{"lead_id": lead.ID, "status": "qualified"},
+		},
+		{
+			name:   "update_knowledge_item",
+			exec:   NewUpdateKnowledgeItemExecutor(db),
+			params: `{"id":"` + item.ID + `","title":"New title"}`,
+			want:   map[string]any{"knowledge_item_id": item.ID, "title": "New title"},
+		},
+	}
+	for _, tc := range cases {
+		raw, err := tc.exec.Execute(ctx, json.RawMessage(tc.params))
+		if err != nil {
+			t.Fatalf("%s: Execute() error = %v", tc.name, err)
+		}
+		var out map[string]any
+		if err := json.Unmarshal(raw, &out); err != nil {
+			t.Fatalf("%s: decode output: %v", tc.name, err)
+		}
+		if out["dry_run"] != true {
+			t.Fatalf("%s: output = %s, want dry_run marker", tc.name, raw)
+		}
+		for key, want := range tc.want {
+			if out[key] != want {
+				t.Errorf("%s: preview %s = %v, want %v", tc.name, key, out[key], want)
+			}
+		}
+	}
+
+	if n := countRows(t, db, "note", wsID); n != 0 {
+		t.Errorf("dry-run create_note stored %d notes", n)
+	}
+	if got, _ := dealSvc.Get(bg, wsID, deal.ID); got.Status != "open" || got.Amount != nil {
+		t.Errorf("dry-run update_deal changed deal: status=%q amount=%v", got.Status, got.Amount)
+	}
+	if got, _ := leadSvc.Get(bg, wsID, lead.ID); got.Status != "new" {
+		t.Errorf("dry-run update_lead changed status to %q", got.Status)
+	}
+	var title string
+	if err := db.QueryRow(`SELECT title FROM knowledge_item WHERE id = ?`, item.ID).Scan(&title); err != nil || title != "Old title" {
+		t.Errorf("dry-run update_knowledge_item: title = %q, err = %v", title, err)
+	}
+	if _, err := NewUpdateKnowledgeItemExecutor(db).Execute(ctx, json.RawMessage(`{"id":"missing","title":"x"}`)); !errors.Is(err, ErrBuiltinExecutionFailed) {
+		t.Errorf("dry-run update of a missing item: err = %v, want ErrBuiltinExecutionFailed", err)
+	}
+}
+
+func countRows(t *testing.T, db *sql.DB, table, wsID string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE workspace_id = ?`, wsID).Scan(&n); err != nil {
+		t.Fatalf("count %s: %v", table, err)
+	}
+	return n
+}
+
 func TestEnsureBuiltInToolDefinitionsForAllWorkspaces_Idempotent(t *testing.T) {
 	t.Parallel()
 
@@ -143,8 +408,8 @@ func TestEnsureBuiltInToolDefinitionsForAllWorkspaces_Idempotent(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListToolDefinitions error = %v", err)
 	}
-	if len(items) != 10 {
-		t.Fatalf("expected 10 built-in definitions, got %d", len(items))
+	if len(items) != 15 {
+		t.Fatalf("expected 15 built-in definitions, got %d", len(items))
 	}
 }
 
@@ -155,12 +420,14 @@ func TestRegisterBuiltInExecutors(t *testing.T) {
 	r := NewToolRegistry(db)
 
 	if err := RegisterBuiltInExecutors(r, BuiltinServices{
-		DB:      db,
-		Case:    crm.NewCaseService(db),
-		Lead:    crm.NewLeadService(db),
-		Account: crm.NewAccountService(db),
-		Deal:    crm.NewDealService(db),
-		Ingest:  knowledge.NewIngestService(db, eventbus.New()),
+		DB:       db,
+		Case:     crm.NewCaseService(db),
+		Lead:     crm.NewLeadService(db),
+		Account:  crm.NewAccountService(db),
+		Deal:     crm.NewDealService(db),
+		Activity: crm.NewActivityService(db),
+		Note:     crm.NewNoteService(db),
+		Ingest:   knowledge.NewIngestService(db, eventbus.New()),
 	}); err != nil {
 		t.Fatalf("RegisterBuiltInExecutors error = %v", err)
 	}
@@ -183,9 +450,21 @@ func TestRegisterBuiltInExecutors(t *testing.T) {
 	if _, err := r.Get(BuiltinGetDeal); err != nil {
 		t.Fatalf("expected get_deal executor registered, err = %v", err)
 	}
+	if _, err := r.Get(BuiltinUpdateLead); err != nil {
+		t.Fatalf("expected update_lead executor registered, err = %v", err)
+	}
+	if _, err := r.Get(BuiltinGetCase); err != nil {
+		t.Fatalf("expected get_case executor registered, err = %v", err)
+	}
+	if _, err := r.Get(BuiltinListCases); err != nil {
+		t.Fatalf("expected list_cases executor registered, err = %v", err)
+	}
 	if _, err := r.Get(BuiltinUpdateDeal); err != nil {
 		t.Fatalf("expected update_deal executor registered, err = %v", err)
 	}
+	if _, err := r.Get(BuiltinCreateDeal); err != nil {
+		t.Fatalf("expected create_deal executor registered, err = %v", err)
+	}
 	if _, err := r.Get(BuiltinCreateKnowledgeItem); err != nil {
 		t.Fatalf("expected create_knowledge_item executor registered, err = %v", err)
 	}
@@ -230,6 +509,67 @@ func TestGetLeadExecutor_SuccessAndNotFound(t *testing.T) {
 	}
 }
 
+func TestUpdateLeadExecutor_UpdatesStatusOwnerAndMetadata(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	newOwnerID := createToolUser(t, db, wsID)
+	leadSvc := crm.NewLeadService(db)
+
+	lead, err := leadSvc.Create(context.Background(), crm.CreateLeadInput{
+		WorkspaceID: wsID,
+		OwnerID:     ownerID,
+		Source:      "web",
+		Status:      "new",
+		Metadata:    `{"email":"ana@example.com"}`,
+	})
+	if err != nil {
+		t.Fatalf("create lead: %v", err)
+	}
+
+	// A stale score must not survive the update: the executor rescores.
+	if _, err = db.Exec(`UPDATE lead SET score = 1 WHERE id = ?`, lead.ID); err != nil {
+		t.Fatalf("set stale score: %v", err)
+	}
+
+	exec := NewUpdateLeadExecutor(leadSvc)
+	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
+
+	out, err := exec.Execute(ctx, json.RawMessage(`{"lead_id":"`+lead.ID+`","status":"contacted","owner_id":"`+newOwnerID+`","metadata":{"channel":"email"}}`))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var decoded struct {
+		Lead crm.Lead `json:"lead"`
+	}
+	if err = json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	updated := decoded.Lead
+	if updated.Status != "contacted" || updated.OwnerID != newOwnerID || updated.Source == nil || *updated.Source != "web" {
+		t.Fatalf("unexpected lead %+v", updated)
+	}
+	if updated.Score == nil || *updated.Score == 1 || *updated.Score != *lead.Score {
+		t.Fatalf("score = %v; want the recomputed %v", updated.Score, *lead.Score)
+	}
+	var metadata map[string]any
+	if err = json.Unmarshal([]byte(derefString(updated.Metadata)), &metadata); err != nil {
+		t.Fatalf("decode metadata: %v", err)
+	}
+	if metadata["email"] != "ana@example.com" || metadata["channel"] != "email" {
+		t.Fatalf("expected merged metadata, got %v", metadata)
+	}
+
+	if _, err = exec.Execute(ctx, json.RawMessage(`{"lead_id":"`+lead.ID+`","status":"archived"}`)); !errors.Is(err, ErrBuiltinExecutionFailed) {
+		t.Fatalf("expected invalid status error, got %v", err)
+	}
+	if _, err = exec.Execute(ctx, json.RawMessage(`{"lead_id":"missing","status":"lost"}`)); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
 func TestGetAccountExecutor_SuccessAndNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -301,6 +641,106 @@ func TestGetDealExecutor_SuccessAndNotFound(t *testing.T) {
 	}
 }
 
+func TestGetCaseExecutor_SuccessAndNotFound(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	otherWS := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	caseSvc := crm.NewCaseService(db)
+
+	created, err := caseSvc.Create(context.Background(), crm.CreateCaseInput{
+		WorkspaceID: wsID,
+		OwnerID:     ownerID,
+		Subject:     "Login broken",
+		Status:      "open",
+		Priority:    "high",
+	})
+	if err != nil {
+		t.Fatalf("create case: %v", err)
+	}
+
+	exec := NewGetCaseExecutor(caseSvc)
+	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
+
+	out, err := exec.Execute(ctx, json.RawMessage(`{"case_id":"`+created.ID+`"}`))
+	if err != nil {
+		t.Fatalf("Execute success error = %v", err)
+	}
+	var decoded struct {
+		Case crm.CaseTicket `json:"case"`
+	}
+	if err = json.Unmarshal(out, &decoded); err != nil || decoded.Case.ID != created.ID || decoded.Case.Subject != "Login broken" {
+		t.Fatalf("unexpected output %s (err = %v)", string(out), err)
+	}
+
+	otherCtx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, otherWS)
+	for _, c := range []struct {
+		ctx    context.Context
+		caseID string
+	}{{ctx, "missing"}, {otherCtx, created.ID}} {
+		_, err = exec.Execute(c.ctx, json.RawMessage(`{"case_id":"`+c.caseID+`"}`))
+		if !errors.Is(err, ErrBuiltinExecutionFailed) || !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("expected not found error for %q, got %v", c.caseID, err)
+		}
+	}
+}
+
+func TestListCasesExecutor_FiltersAndLimit(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	otherWS := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	otherOwnerID := createToolUser(t, db, wsID)
+	caseSvc := crm.NewCaseService(db)
+
+	for _, in := range []crm.CreateCaseInput{
+		{WorkspaceID: wsID, OwnerID: ownerID, Subject: "A", Status: "open", Priority: "high"},
+		{WorkspaceID: wsID, OwnerID: ownerID, Subject: "B", Status: "open", Priority: "low"},
+		{WorkspaceID: wsID, OwnerID: ownerID, Subject: "C", Status: "resolved", Priority: "high"},
+		{WorkspaceID: wsID, OwnerID: otherOwnerID, Subject: "D", Status: "open", Priority: "high"},
+		{WorkspaceID: otherWS, OwnerID: createToolUser(t, db, otherWS), Subject: "E", Status: "open", Priority: "high"},
+	} {
+		if _, err := caseSvc.Create(context.Background(), in); err != nil {
+			t.Fatalf("create case %s: %v", in.Subject, err)
+		}
+	}
+
+	exec := NewListCasesExecutor(caseSvc)
+	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
+	list := func(params string) (subjects []string, total int) {
+		t.Helper()
+		out, err := exec.Execute(ctx, json.RawMessage(params))
+		if err != nil {
+			t.Fatalf("Execute(%s) error = %v", params, err)
+		}
+		var decoded struct {
+			Cases []crm.CaseTicket `json:"cases"`
+			Total int              `json:"total"`
+		}
+		if err = json.Unmarshal(out, &decoded); err != nil {
+			t.Fatalf("decode output: %v", err)
+		}
+		for _, c := range decoded.Cases {
+			subjects = append(subjects, c.Subject)
+		}
+		return subjects, decoded.Total
+	}
+
+	if subjects, total := list(`{"status":"open","owner_id":"` + ownerID + `","priority":"high"}`); total != 1 || len(subjects) != 1 || subjects[0] != "A" {
+		t.Fatalf("expected only case A, got %v (total %d)", subjects, total)
+	}
+	if subjects, total := list(`{"status":"open","limit":2}`); total != 3 || len(subjects) != 2 {
+		t.Fatalf("expected 2 of 3 open cases in workspace, got %v (total %d)", subjects, total)
+	}
+	if subjects, total := list(`{}`); total != 4 || len(subjects) != 4 {
+		t.Fatalf("expected all 4 workspace cases, got %v (total %d)", subjects, total)
+	}
+}
+
 func TestUpdateDealExecutor_Execute_UpdatesDeal(t *testing.T) {
 	t.Parallel()
 
@@ -343,6 +783,82 @@ func TestUpdateDealExecutor_Execute_UpdatesDeal(t *testing.T) {
 	}
 }
 
+func TestCreateDealExecutor_Execute_CreatesDeal(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	accountID, pipelineID, stageID := createToolDealContext(t, db, wsID, ownerID)
+
+	dealSvc := crm.NewDealService(db)
+	exec := NewCreateDealExecutor(dealSvc, crm.NewAccountService(db))
+	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
+	params := json.RawMessage(`{"account_id":"` + accountID + `","pipeline_id":"` + pipelineID + `","stage_id":"` + stageID + `","owner_id":"` + ownerID + `","amount":5000}`)
+
+	raw, err := exec.Execute(ctx, params)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if out["stage_id"] != stageID {
+		t.Fatalf("stage_id = %v; want %s", out["stage_id"], stageID)
+	}
+	dealID, _ := out["deal_id"].(string)
+	created, err := dealSvc.Get(context.Background(), wsID, dealID)
+	if err != nil {
+		t.Fatalf("Get deal error = %v", err)
+	}
+	if created.Title != "ACME Expansion" || created.OwnerID != ownerID {
+		t.Fatalf("deal = %+v; want title from account and owner %s", created, ownerID)
+	}
+	if created.Amount == nil || *created.Amount != 5000 {
+		t.Fatalf("expected amount 5000, got %#v", created.Amount)
+	}
+}
+
+func TestCreateDealExecutor_Execute_RejectsInvalidReferences(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	accountID, pipelineID, stageID := createToolDealContext(t, db, wsID, ownerID)
+
+	otherPipeline, err := crm.NewPipelineService(db).Create(context.Background(), crm.CreatePipelineInput{
+		WorkspaceID: wsID,
+		Name:        "Renewals",
+		EntityType:  "deal",
+	})
+	if err != nil {
+		t.Fatalf("create pipeline: %v", err)
+	}
+	otherWsID := createWorkspace(t, db)
+
+	exec := NewCreateDealExecutor(crm.NewDealService(db), crm.NewAccountService(db))
+	cases := []struct {
+		name        string
+		workspaceID string
+		pipelineID  string
+	}{
+		{name: "stage outside pipeline", workspaceID: wsID, pipelineID: otherPipeline.ID},
+		{name: "other workspace", workspaceID: otherWsID, pipelineID: pipelineID},
+	}
+	for _, tc := range cases {
+		ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, tc.workspaceID)
+		params := json.RawMessage(`{"account_id":"` + accountID + `","pipeline_id":"` + tc.pipelineID + `","stage_id":"` + stageID + `","owner_id":"` + ownerID + `"}`)
+		if _, err := exec.Execute(ctx, params); !errors.Is(err, ErrBuiltinExecutionFailed) {
+			t.Fatalf("%s: Execute() error = %v; want ErrBuiltinExecutionFailed", tc.name, err)
+		}
+	}
+	if n := countRows(t, db, "deal", wsID); n != 0 {
+		t.Fatalf("deal rows = %d; want 0", n)
+	}
+}
+
 func createToolDealContext(t *testing.T, db *sql.DB, wsID, ownerID string) (string, string, string) {
 	t.Helper()
 
@@ -392,12 +908,62 @@ func TestCreateKnowledgeItemExecutor_SuccessAndWorkspaceMismatch(t *testing.T) {
 		t.Fatal("expected non-empty output")
 	}
 
+	// Without dedup_on_ingest repeated content is a new item; with it the
+	// first item comes back.
+	copyOut, err := exec.Execute(ctx, json.RawMessage(`{"title":"KB1 copy","content":"contenido","source_type":"document"}`))
+	if err != nil {
+		t.Fatalf("Execute copy error = %v", err)
+	}
+	dupOut, err := exec.Execute(ctx, json.RawMessage(`{"title":"KB1 dup","content":"contenido","source_type":"document","dedup_on_ingest":true}`))
+	if err != nil {
+		t.Fatalf("Execute duplicate error = %v", err)
+	}
+	var first, copied, dup struct {
+		KnowledgeItemID string `json:"knowledge_item_id"`
+		WasDuplicate    bool   `json:"was_duplicate"`
+	}
+	if err = json.Unmarshal(out, &first); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if err = json.Unmarshal(copyOut, &copied); err != nil {
+		t.Fatalf("unmarshal copy output: %v", err)
+	}
+	if copied.WasDuplicate || copied.KnowledgeItemID == first.KnowledgeItemID {
+		t.Fatalf("copy output = %+v; want a new item without dedup_on_ingest", copied)
+	}
+	if err = json.Unmarshal(dupOut, &dup); err != nil {
+		t.Fatalf("unmarshal duplicate output: %v", err)
+	}
+	if first.WasDuplicate || !dup.WasDuplicate || dup.KnowledgeItemID != first.KnowledgeItemID {
+		t.Fatalf("duplicate output = %+v; want was_duplicate for %q", dup, first.KnowledgeItemID)
+	}
+
 	_, err = exec.Execute(ctx, json.RawMessage(`{"title":"KB2","content":"contenido","source_type":"document","workspace_id":"otro"}`))
 	if err == nil {
 		t.Fatal("expected workspace mismatch error")
 	}
 }
 
+func TestCreateKnowledgeItemExecutor_PropagatesQuotaExceeded(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	if _, err := db.Exec(`UPDATE workspace SET settings = '{"knowledge_quota":{"max_items":1}}' WHERE id = ?`, wsID); err != nil {
+		t.Fatalf("set quota: %v", err)
+	}
+	exec := NewCreateKnowledgeItemExecutor(knowledge.NewIngestService(db, eventbus.New()))
+	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
+
+	if _, err := exec.Execute(ctx, json.RawMessage(`{"title":"KB1","content":"contenido","source_type":"document"}`)); err != nil {
+		t.Fatalf("Execute within quota error = %v", err)
+	}
+	_, err := exec.Execute(ctx, json.RawMessage(`{"title":"KB2","content":"otro contenido","source_type":"document"}`))
+	if !errors.Is(err, knowledge.ErrQuotaExceeded) {
+		t.Fatalf("Execute over quota error = %v; want knowledge.ErrQuotaExceeded", err)
+	}
+}
+
 func TestCreateKnowledgeItemExecutor_PersistsConnectorBoundaryFields(t *testing.T) {
 	t.Parallel()
 
@@ -496,6 +1062,22 @@ func TestQueryMetricsExecutor_SalesFunnelAndInvalidMetric(t *testing.T) {
 	}
 }
 
+func TestQueryMetricsExecutor_QueryRowsAsMaps_StopsOnePastRowCap(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	exec := &QueryMetricsExecutor{db: db}
+	rows, err := exec.queryRowsAsMaps(context.Background(), `
+		WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM n WHERE x < ?)
+		SELECT x FROM n`, maxMetricRows*3)
+	if err != nil {
+		t.Fatalf("queryRowsAsMaps error = %v", err)
+	}
+	if len(rows) != maxMetricRows+1 {
+		t.Fatalf("rows = %d; want %d (cap + 1)", len(rows), maxMetricRows+1)
+	}
+}
+
 func TestUpdateKnowledgeItemExecutor_InvalidParamsAndMissingDB(t *testing.T) {
 	t.Parallel()
 
@@ -594,6 +1176,85 @@ func TestQueryMetricsExecutor_CoversAdditionalMetrics(t *testing.T) {
 	}
 }
 
+func TestQueryMetricsExecutor_WinRate(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	pipelineID, stageID := createPipelineStageForToolTest(t, db, wsID)
+	for _, status := range []string{"won", "won", "won", "lost", "open"} {
+		createDealForMetrics(t, db, wsID, ownerID, pipelineID, stageID, status, 100)
+	}
+
+	exec := NewQueryMetricsExecutor(db)
+	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
+	out, err := exec.Execute(ctx, json.RawMessage(`{"metric":"win_rate","workspace_id":"`+wsID+`"}`))
+	if err != nil {
+		t.Fatalf("Execute(win_rate) error = %v", err)
+	}
+
+	var got struct {
+		Data []struct {
+			WonCount  int64   `json:"won_count"`
+			LostCount int64   `json:"lost_count"`
+			WinRate   float64 `json:"win_rate"`
+		} `json:"data"`
+	}
+	if err = json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	if len(got.Data) != 1 || got.Data[0].WonCount != 3 || got.Data[0].LostCount != 1 || got.Data[0].WinRate != 0.75 {
+		t.Fatalf("unexpected win_rate data: %s", out)
+	}
+}
+
+func TestQueryMetricsExecutor_Forecast(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	ownerID := createToolUser(t, db, wsID)
+	pipelineID, earlyStage := createPipelineStageForToolTest(t, db, wsID)
+	lateStage := "stage-tool-" + randID()
+	if _, err := db.Exec(`
+		INSERT INTO pipeline_stage (id, pipeline_id, name, position, probability, created_at, updated_at)
+		VALUES (?, ?, 'Negotiation', 2, 0.8, datetime('now'), datetime('now'))
+	`, lateStage, pipelineID); err != nil {
+		t.Fatalf("create late stage: %v", err)
+	}
+	if _, err := db.Exec(`UPDATE pipeline_stage SET probability = 0.2 WHERE id = ?`, earlyStage); err != nil {
+		t.Fatalf("set early stage probability: %v", err)
+	}
+	createDealForMetrics(t, db, wsID, ownerID, pipelineID, earlyStage, "open", 1000)
+	createDealForMetrics(t, db, wsID, ownerID, pipelineID, lateStage, "open", 500)
+	createDealForMetrics(t, db, wsID, ownerID, pipelineID, lateStage, "won", 9000)
+
+	exec := NewQueryMetricsExecutor(db)
+	ctx := context.WithValue(context.Background(), ctxkeys.WorkspaceID, wsID)
+	out, err := exec.Execute(ctx, json.RawMessage(`{"metric":"forecast","workspace_id":"`+wsID+`"}`))
+	if err != nil {
+		t.Fatalf("Execute(forecast) error = %v", err)
+	}
+
+	var got struct {
+		Data []struct {
+			PipelineID       string  `json:"pipeline_id"`
+			OpenDeals        int64   `json:"open_deals"`
+			OpenValue        float64 `json:"open_value"`
+			WeightedForecast float64 `json:"weighted_forecast"`
+		} `json:"data"`
+	}
+	if err = json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("decode output: %v", err)
+	}
+	// 1000×0.2 + 500×0.8; the won deal is excluded.
+	if len(got.Data) != 1 || got.Data[0].PipelineID != pipelineID || got.Data[0].OpenDeals != 2 ||
+		got.Data[0].OpenValue != 1500 || got.Data[0].WeightedForecast != 600 {
+		t.Fatalf("unexpected forecast data: %s", out)
+	}
+}
+
 func createPipelineStageForToolTest(t *testing.T, db *sql.DB, workspaceID string) (string, string) {
 	t.Helper()
 	pipelineID := "pipeline-tool-" + randID()
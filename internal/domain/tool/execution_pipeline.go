@@ -33,6 +33,7 @@ const (
 	ToolErrorInvalidInput     ExecutionErrorCode = "invalid_input"
 	ToolErrorPermissionDenied ExecutionErrorCode = "permission_denied"
 	ToolErrorToolInactive     ExecutionErrorCode = "tool_inactive"
+	ToolErrorNotAllowed       ExecutionErrorCode = "tool_not_allowed"
 	ToolErrorInternal         ExecutionErrorCode = "internal_error"
 )
 
@@ -58,6 +59,35 @@ func (r *ToolRegistry) Execute(ctx context.Context, workspaceID, toolName string
 	return r.executeDefinition(ctx, workspaceID, def, normalizeToolParams(params))
 }
 
+// Deny records a call the caller refused to make, e.g. an agent asking for a
+// tool outside its allowlist, and returns err wrapped as an *ExecutionError.
+// The call is audited as denied but, since nothing ran, not recorded as usage.
+func (r *ToolRegistry) Deny(ctx context.Context, workspaceID, toolName string, params json.RawMessage, err error) error {
+	r.auditToolExecution(ctx, workspaceID, toolName, normalizeToolParams(params), audit.OutcomeDenied, string(ToolErrorNotAllowed))
+	return &ExecutionError{ToolName: toolName, Code: ToolErrorNotAllowed, Err: err}
+}
+
+// RunInProcess runs fn as a call to toolName, a tool without an executor
+// that the caller runs itself, e.g. SearchKnowledge, whose full result the
+// agents need rather than the capped JSON Execute returns. The call is
+// audited and recorded as usage like an executed one; a failure of fn comes
+// back as an *ExecutionError.
+func (r *ToolRegistry) RunInProcess(
+	ctx context.Context,
+	workspaceID, toolName string,
+	params json.RawMessage,
+	fn func(context.Context) error,
+) error {
+	startedAt := time.Now()
+	params = normalizeToolParams(params)
+	if err := fn(ctx); err != nil {
+		return r.handleExecutionError(ctx, workspaceID, toolName, params, ToolErrorInternal, err, startedAt)
+	}
+	r.auditToolExecution(ctx, workspaceID, toolName, params, audit.OutcomeSuccess, "")
+	r.recordToolUsage(ctx, workspaceID, toolName, startedAt)
+	return nil
+}
+
 func (r *ToolRegistry) executeDefinition(
 	ctx context.Context,
 	workspaceID string,
@@ -81,7 +111,7 @@ func (r *ToolRegistry) executeDefinition(
 
 	r.auditToolExecution(ctx, workspaceID, def.Name, params, audit.OutcomeSuccess, "")
 	r.recordToolUsage(ctx, workspaceID, def.Name, startedAt)
-	return out, nil
+	return limitResult(out, r.maxResultBytes), nil
 }
 
 func (r *ToolRegistry) ensureExecutable(
@@ -94,8 +124,8 @@ func (r *ToolRegistry) ensureExecutable(
 	if !def.IsActive {
 		return r.handleExecutionError(ctx, workspaceID, def.Name, params, ToolErrorToolInactive, ErrToolInactive, startedAt)
 	}
-	if err := r.ValidateParams(ctx, workspaceID, def.Name, params); err != nil {
-		return r.handleExecutionError(ctx, workspaceID, def.Name, params, ToolErrorInvalidInput, err, startedAt)
+	if err := r.validateDefinitionParams(def, params); err != nil {
+		return r.handleExecutionError(ctx, workspaceID, def.Name, params, resolveSchemaErrorCode(err), err, startedAt)
 	}
 	if err := r.enforceToolPermission(ctx, def.Name); err != nil {
 		return r.handleExecutionError(ctx, workspaceID, def.Name, params, ToolErrorPermissionDenied, err, startedAt)
@@ -137,6 +167,9 @@ func (r *ToolRegistry) handleExecutionError(
 	return wrapped
 }
 
+// auditToolExecution records one audit event per tool call. Calls made inside
+// an agent run are attributed to the agent with the tool name as the action;
+// direct calls keep the generic tool.executed / tool.denied actions.
 func (r *ToolRegistry) auditToolExecution(
 	ctx context.Context,
 	workspaceID, toolName string,
@@ -144,7 +177,7 @@ func (r *ToolRegistry) auditToolExecution(
 	outcome audit.Outcome,
 	errorCode string,
 ) {
-	if r.audit == nil || !isBuiltinTool(toolName) {
+	if r.audit == nil {
 		return
 	}
 
@@ -152,10 +185,13 @@ func (r *ToolRegistry) auditToolExecution(
 	if outcome == audit.OutcomeDenied {
 		action = "tool.denied"
 	}
-
 	actorID, actorType := auditActorFromContext(ctx)
-	entityType := "tool"
-	entityID := toolName
+	if runID := runIDFromContext(ctx); runID != nil {
+		action = toolName
+		actorID, actorType = *runID, audit.ActorTypeAgent
+	}
+
+	entityType, entityID := toolTargetEntity(toolName, params)
 	_ = r.audit.LogWithDetails(
 		ctx,
 		workspaceID,
@@ -164,11 +200,19 @@ func (r *ToolRegistry) auditToolExecution(
 		action,
 		&entityType,
 		&entityID,
-		&audit.EventDetails{Metadata: buildToolAuditMetadata(toolName, params, errorCode)},
+		&audit.EventDetails{Metadata: buildToolAuditMetadata(ctx, toolName, params, errorCode)},
 		outcome,
 	)
 }
 
+// resolveSchemaErrorCode separates bad params from a broken schema.
+func resolveSchemaErrorCode(err error) ExecutionErrorCode {
+	if errors.Is(err, ErrToolValidationFailed) {
+		return ToolErrorInvalidInput
+	}
+	return ToolErrorInternal
+}
+
 func resolveAuditOutcome(code ExecutionErrorCode) audit.Outcome {
 	if code == ToolErrorPermissionDenied {
 		return audit.OutcomeDenied
@@ -183,11 +227,20 @@ func auditActorFromContext(ctx context.Context) (string, audit.ActorType) {
 	return "system", audit.ActorTypeSystem
 }
 
-func buildToolAuditMetadata(toolName string, params json.RawMessage, errorCode string) map[string]any {
+func buildToolAuditMetadata(ctx context.Context, toolName string, params json.RawMessage, errorCode string) map[string]any {
 	meta := map[string]any{
 		"tool_name":  toolName,
 		"param_keys": extractParamKeys(params),
 	}
+	if sanitized := sanitizeToolParams(params); sanitized != nil {
+		meta["params"] = sanitized
+	}
+	if runID := runIDFromContext(ctx); runID != nil {
+		meta["run_id"] = *runID
+		if userID, ok := ctx.Value(ctxkeys.UserID).(string); ok && strings.TrimSpace(userID) != "" {
+			meta["triggered_by_user_id"] = userID
+		}
+	}
 	if errorCode != "" {
 		meta["error_code"] = errorCode
 	}
@@ -243,9 +296,9 @@ func normalizeToolParams(params json.RawMessage) json.RawMessage {
 
 func isBuiltinTool(toolName string) bool {
 	switch toolName {
-	case BuiltinCreateTask, BuiltinUpdateCase, BuiltinSendReply,
-		BuiltinGetLead, BuiltinGetAccount, BuiltinCreateKnowledgeItem,
-		BuiltinUpdateKnowledgeItem, BuiltinQueryMetrics:
+	case BuiltinCreateTask, BuiltinCreateNote, BuiltinUpdateCase, BuiltinSendReply,
+		BuiltinGetLead, BuiltinUpdateLead, BuiltinGetAccount, BuiltinGetCase, BuiltinListCases, BuiltinCreateKnowledgeItem,
+		BuiltinUpdateKnowledgeItem, BuiltinQueryMetrics, BuiltinCreateDeal:
 		return true
 	default:
 		return false
@@ -8,7 +8,12 @@ import (
 // ToolExecutor defines the runtime contract for executable tools.
 // Task 3.3: foundation contract used by the tool registry.
 //
+// ParamsSchema returns the JSON schema Execute expects its params to match;
+// the registry validates params against it before dispatch. A nil schema
+// skips that check.
+//
 //nolint:revive // contrato público del módulo tool
 type ToolExecutor interface {
 	Execute(ctx context.Context, params json.RawMessage) (json.RawMessage, error)
+	ParamsSchema() json.RawMessage
 }
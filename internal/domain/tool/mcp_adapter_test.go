@@ -22,6 +22,8 @@ func (mcpEchoExecutor) Execute(_ context.Context, params json.RawMessage) (json.
 	}), nil
 }
 
+func (mcpEchoExecutor) ParamsSchema() json.RawMessage { return nil }
+
 type stubMCPResourceProvider struct {
 	items []*MCPResourceDescriptor
 	read  map[string]*MCPResourcePayload
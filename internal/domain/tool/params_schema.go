@@ -0,0 +1,192 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ParamViolation is one mismatch between tool params and the tool's schema.
+// Field is a dotted path into the params ("tags[1]", "metadata.x").
+type ParamViolation struct {
+	Field   string
+	Message string
+}
+
+// ParamsValidationError is returned by ToolRegistry.ValidateParams, and by
+// ToolRegistry.Execute wrapped in an ExecutionError with code invalid_input,
+// when params do not match the tool's schema. It lists every violation so a
+// malformed LLM-generated call can be corrected in one round trip.
+type ParamsValidationError struct {
+	ToolName   string
+	Violations []ParamViolation
+}
+
+func (e *ParamsValidationError) Error() string {
+	parts := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		parts = append(parts, v.Field+": "+v.Message)
+	}
+	return fmt.Sprintf("%s params invalid: %s", e.ToolName, strings.Join(parts, "; "))
+}
+
+func (e *ParamsValidationError) Unwrap() error {
+	return ErrToolValidationFailed
+}
+
+// validateParamsSchema checks params, which must be a JSON object, against
+// schema. It supports the subset of JSON schema the tool definitions use:
+// type, properties, required, additionalProperties, items, enum and minimum.
+// Without "additionalProperties": false unknown keys are allowed.
+func validateParamsSchema(toolName string, schema, params json.RawMessage) error {
+	var rules map[string]any
+	if len(schema) > 0 {
+		if err := json.Unmarshal(schema, &rules); err != nil {
+			return fmt.Errorf("%w: params schema must be a json object", ErrToolDefinitionInvalid)
+		}
+	}
+	var value any
+	if err := json.Unmarshal(params, &value); err != nil {
+		return &ParamsValidationError{
+			ToolName:   toolName,
+			Violations: []ParamViolation{{Field: "params", Message: "must be valid json"}},
+		}
+	}
+	if _, isObject := value.(map[string]any); !isObject {
+		return &ParamsValidationError{
+			ToolName:   toolName,
+			Violations: []ParamViolation{{Field: "params", Message: "must be an object, got " + jsonTypeName(value)}},
+		}
+	}
+
+	var violations []ParamViolation
+	checkSchemaValue("", value, rules, &violations)
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ParamsValidationError{ToolName: toolName, Violations: violations}
+}
+
+func checkSchemaValue(path string, value any, rules map[string]any, out *[]ParamViolation) {
+	field := path
+	if field == "" {
+		field = "params"
+	}
+	if want, ok := rules["type"].(string); ok && !matchesSchemaType(value, want) {
+		*out = append(*out, ParamViolation{Field: field, Message: fmt.Sprintf("must be %s, got %s", withArticle(want), jsonTypeName(value))})
+		return
+	}
+	if enum, ok := rules["enum"].([]any); ok && !containsValue(enum, value) {
+		*out = append(*out, ParamViolation{Field: field, Message: fmt.Sprintf("must be one of %s", formatEnum(enum))})
+	}
+	if minimum, ok := rules["minimum"].(float64); ok {
+		if n, isNum := value.(float64); isNum && n < minimum {
+			*out = append(*out, ParamViolation{Field: field, Message: fmt.Sprintf("must be >= %v", minimum)})
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		checkSchemaObject(path, v, rules, out)
+	case []any:
+		if items, ok := rules["items"].(map[string]any); ok {
+			for i, item := range v {
+				checkSchemaValue(fmt.Sprintf("%s[%d]", field, i), item, items, out)
+			}
+		}
+	}
+}
+
+func checkSchemaObject(path string, obj map[string]any, rules map[string]any, out *[]ParamViolation) {
+	props, _ := rules["properties"].(map[string]any)
+	for _, key := range extractStringSlice(rules["required"]) {
+		if _, ok := obj[key]; !ok {
+			*out = append(*out, ParamViolation{Field: joinFieldPath(path, key), Message: "is required"})
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		propRules, declared := props[key].(map[string]any)
+		if !declared {
+			if !resolveAdditionalProperties(rules) {
+				*out = append(*out, ParamViolation{Field: joinFieldPath(path, key), Message: "is not allowed"})
+			}
+			continue
+		}
+		checkSchemaValue(joinFieldPath(path, key), obj[key], propRules, out)
+	}
+}
+
+func matchesSchemaType(value any, want string) bool {
+	switch want {
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return jsonTypeName(value) == want
+	}
+}
+
+func jsonTypeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func withArticle(typeName string) string {
+	switch typeName {
+	case "array", "integer", "object":
+		return "an " + typeName
+	default:
+		return "a " + typeName
+	}
+}
+
+func containsValue(enum []any, value any) bool {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(allowed, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, 0, len(enum))
+	for _, v := range enum {
+		raw, _ := json.Marshal(v)
+		parts = append(parts, string(raw))
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+func joinFieldPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,170 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+)
+
+type schemaExecutor struct {
+	schema json.RawMessage
+	calls  *int
+}
+
+func (e schemaExecutor) Execute(_ context.Context, _ json.RawMessage) (json.RawMessage, error) {
+	*e.calls++
+	return json.RawMessage(`{"ok":true}`), nil
+}
+
+func (e schemaExecutor) ParamsSchema() json.RawMessage { return e.schema }
+
+func TestValidateParamsSchema_BuiltinSchemas(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		schema   string
+		params   string
+		wantErrs []ParamViolation
+	}{
+		{
+			name:   "valid create_task",
+			schema: createTaskParamsSchema,
+			params: `{"owner_id":"u1","title":"Call back","entity_type":"case","entity_id":"c1"}`,
+		},
+		{
+			name:   "missing required and misspelled field",
+			schema: createTaskParamsSchema,
+			params: `{"owner_id":"u1","titel":"Call back","entity_type":"case","entity_id":"c1"}`,
+			wantErrs: []ParamViolation{
+				{Field: "title", Message: "is required"},
+				{Field: "titel", Message: "is not allowed"},
+			},
+		},
+		{
+			name:     "wrong type",
+			schema:   sendReplyParamsSchema,
+			params:   `{"case_id":"c1","body":"Hi","is_internal":"yes"}`,
+			wantErrs: []ParamViolation{{Field: "is_internal", Message: "must be a boolean, got string"}},
+		},
+		{
+			name:     "enum",
+			schema:   queryMetricsParamsSchema,
+			params:   `{"metric":"revenue","workspace_id":"ws-1"}`,
+			wantErrs: []ParamViolation{{Field: "metric", Message: `must be one of ["sales_funnel", "deal_aging", "win_rate", "forecast", "case_volume", "case_backlog", "mttr"]`}},
+		},
+		{
+			name:     "integer minimum",
+			schema:   listCasesParamsSchema,
+			params:   `{"limit":0}`,
+			wantErrs: []ParamViolation{{Field: "limit", Message: "must be >= 1"}},
+		},
+		{
+			name:     "integer rejects fractions",
+			schema:   listCasesParamsSchema,
+			params:   `{"limit":2.5}`,
+			wantErrs: []ParamViolation{{Field: "limit", Message: "must be an integer, got number"}},
+		},
+		{
+			name:     "array items",
+			schema:   updateCaseParamsSchema,
+			params:   `{"case_id":"c1","tags":["vip",7]}`,
+			wantErrs: []ParamViolation{{Field: "tags[1]", Message: "must be a string, got number"}},
+		},
+		{
+			name:     "params not an object",
+			schema:   getCaseParamsSchema,
+			params:   `["c1"]`,
+			wantErrs: []ParamViolation{{Field: "params", Message: "must be an object, got array"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateParamsSchema("tool_x", json.RawMessage(tt.schema), json.RawMessage(tt.params))
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("validateParamsSchema() = %v; want nil", err)
+				}
+				return
+			}
+			var valErr *ParamsValidationError
+			if !errors.As(err, &valErr) || !errors.Is(err, ErrToolValidationFailed) {
+				t.Fatalf("validateParamsSchema() = %v; want *ParamsValidationError", err)
+			}
+			if len(valErr.Violations) != len(tt.wantErrs) {
+				t.Fatalf("violations = %+v; want %+v", valErr.Violations, tt.wantErrs)
+			}
+			for i, want := range tt.wantErrs {
+				if valErr.Violations[i] != want {
+					t.Fatalf("violation %d = %+v; want %+v", i, valErr.Violations[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuiltinExecutors_ParamsSchemaMatchesDefinitions(t *testing.T) {
+	t.Parallel()
+
+	registry := NewToolRegistry(nil)
+	if err := RegisterBuiltInExecutors(registry, BuiltinServices{}); err != nil {
+		t.Fatalf("RegisterBuiltInExecutors() error = %v", err)
+	}
+	for _, def := range builtinDefinitions() {
+		executor, err := registry.Get(def.Name)
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", def.Name, err)
+		}
+		schema := executor.ParamsSchema()
+		if string(schema) != string(def.InputSchema) {
+			t.Fatalf("%s ParamsSchema differs from its definition", def.Name)
+		}
+		if err = validateToolSchema(schema); err != nil {
+			t.Fatalf("%s ParamsSchema invalid: %v", def.Name, err)
+		}
+	}
+}
+
+func TestToolRegistry_Execute_RejectsParamsFailingExecutorSchema(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	r := NewToolRegistry(db)
+	calls := 0
+	if err := r.Register("lookup", schemaExecutor{schema: json.RawMessage(getCaseParamsSchema), calls: &calls}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	// The persisted definition is looser than the executor schema.
+	if _, err := r.CreateToolDefinition(context.Background(), CreateToolDefinitionInput{
+		WorkspaceID: wsID,
+		Name:        "lookup",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"case_id":{}},"additionalProperties":false}`),
+	}); err != nil {
+		t.Fatalf("CreateToolDefinition() error = %v", err)
+	}
+	ctx := context.WithValue(context.Background(), ctxkeys.UserID, "user-1")
+
+	_, err := r.Execute(ctx, wsID, "lookup", json.RawMessage(`{"case_id":42}`))
+	var valErr *ParamsValidationError
+	if !errors.As(err, &valErr) || !IsToolExecutionErrorCode(err, ToolErrorInvalidInput) {
+		t.Fatalf("Execute() error = %v; want invalid_input *ParamsValidationError", err)
+	}
+	if valErr.ToolName != "lookup" || len(valErr.Violations) != 1 || valErr.Violations[0].Field != "case_id" {
+		t.Fatalf("validation error = %+v", valErr)
+	}
+	if calls != 0 {
+		t.Fatalf("executor called %d times; want 0", calls)
+	}
+
+	if _, err = r.Execute(ctx, wsID, "lookup", json.RawMessage(`{"case_id":"c1"}`)); err != nil {
+		t.Fatalf("Execute(valid) error = %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("executor called %d times; want 1", calls)
+	}
+}
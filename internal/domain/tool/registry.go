@@ -74,6 +74,8 @@ type ToolRegistry struct {
 	authz     ToolAuthorizer
 	audit     AuditLogger
 	usage     UsageRecorder
+	// maxResultBytes caps Execute results; see SetMaxResultBytes.
+	maxResultBytes int
 }
 
 func NewToolRegistry(db *sql.DB) *ToolRegistry {
@@ -89,7 +91,14 @@ func NewToolRegistryWithRuntime(db *sql.DB, authz ToolAuthorizer, audit AuditLog
 }
 
 func NewToolRegistryWithRuntimeAndUsage(db *sql.DB, authz ToolAuthorizer, audit AuditLogger, usage UsageRecorder) *ToolRegistry {
-	return &ToolRegistry{db: db, executors: make(map[string]ToolExecutor), authz: authz, audit: audit, usage: usage}
+	return &ToolRegistry{
+		db:             db,
+		executors:      make(map[string]ToolExecutor),
+		authz:          authz,
+		audit:          audit,
+		usage:          usage,
+		maxResultBytes: DefaultMaxResultBytes,
+	}
 }
 
 func (r *ToolRegistry) Register(name string, executor ToolExecutor) error {
@@ -224,6 +233,28 @@ func (r *ToolRegistry) ListToolDefinitions(ctx context.Context, workspaceID stri
 	return out, nil
 }
 
+// KnownToolNames returns the tool names an agent definition may allow in
+// workspaceID: the built-ins, SearchKnowledge, every registered executor and
+// the workspace's own tool definitions, active or not.
+func (r *ToolRegistry) KnownToolNames(ctx context.Context, workspaceID string) (map[string]bool, error) {
+	defs, err := r.ListToolDefinitions(ctx, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(defs)+len(r.executors))
+	known[SearchKnowledge] = true
+	for _, def := range builtinDefinitions() {
+		known[def.Name] = true
+	}
+	for name := range r.executors {
+		known[name] = true
+	}
+	for _, def := range defs {
+		known[def.Name] = true
+	}
+	return known, nil
+}
+
 func (r *ToolRegistry) GetToolDefinitionByID(ctx context.Context, workspaceID, id string) (*ToolDefinition, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT id, workspace_id, name, description, input_schema,
@@ -278,31 +309,26 @@ func (r *ToolRegistry) DeleteToolDefinition(ctx context.Context, workspaceID, id
 	return ensureRowsAffected(res, ErrToolDefinitionNotFound)
 }
 
+// ValidateParams checks params against the schema of toolName: the
+// executor's ParamsSchema when one is registered, the persisted input schema
+// otherwise. Mismatches are returned as a *ParamsValidationError.
 func (r *ToolRegistry) ValidateParams(ctx context.Context, workspaceID, toolName string, params json.RawMessage) error {
 	def, defErr := r.getToolDefinitionByName(ctx, workspaceID, toolName)
 	if defErr != nil {
 		return defErr
 	}
+	return r.validateDefinitionParams(def, params)
+}
 
+func (r *ToolRegistry) validateDefinitionParams(def *ToolDefinition, params json.RawMessage) error {
 	if len(params) == 0 {
 		params = json.RawMessage(`{}`)
 	}
-
-	var input map[string]any
-	if unmarshalErr := json.Unmarshal(params, &input); unmarshalErr != nil {
-		return fmt.Errorf("%w: params must be a json object", ErrToolValidationFailed)
+	schema := def.InputSchema
+	if executor, err := r.Get(def.Name); err == nil && len(executor.ParamsSchema()) > 0 {
+		schema = executor.ParamsSchema()
 	}
-
-	var schema map[string]any
-	if unmarshalErr := json.Unmarshal(def.InputSchema, &schema); unmarshalErr != nil {
-		return fmt.Errorf("%w: invalid persisted schema", ErrToolValidationFailed)
-	}
-
-	if valErr := validateAgainstMinimalSchema(input, schema); valErr != nil {
-		return valErr
-	}
-
-	return nil
+	return validateParamsSchema(def.Name, schema, params)
 }
 
 func (r *ToolRegistry) getToolDefinitionByName(ctx context.Context, workspaceID, toolName string) (*ToolDefinition, error) {
@@ -356,18 +382,6 @@ func validateToolSchema(raw json.RawMessage) error {
 	return validateSchemaRequiredKeys(schema, props)
 }
 
-func validateAgainstMinimalSchema(input, schema map[string]any) error {
-	if valErr := validateRequiredFields(input, extractStringSlice(schema["required"])); valErr != nil {
-		return valErr
-	}
-
-	if resolveAdditionalProperties(schema) {
-		return nil
-	}
-
-	return validateUnknownFields(input, buildAllowedPropsSet(schema))
-}
-
 func validateUpdateInput(in UpdateToolDefinitionInput) error {
 	if strings.TrimSpace(in.ID) == "" {
 		return fmt.Errorf("id is required")
@@ -468,15 +482,6 @@ func parseRequiredKeys(v any) ([]string, error) {
 	return out, nil
 }
 
-func validateRequiredFields(input map[string]any, requiredKeys []string) error {
-	for _, key := range requiredKeys {
-		if _, keyExists := input[key]; !keyExists {
-			return fmt.Errorf("%w: missing required field %q", ErrToolValidationFailed, key)
-		}
-	}
-	return nil
-}
-
 func resolveAdditionalProperties(schema map[string]any) bool {
 	allowAdditional := true
 	if addProp, hasProp := schema["additionalProperties"].(bool); hasProp {
@@ -485,25 +490,6 @@ func resolveAdditionalProperties(schema map[string]any) bool {
 	return allowAdditional
 }
 
-func buildAllowedPropsSet(schema map[string]any) map[string]struct{} {
-	allowedProps := map[string]struct{}{}
-	if props, hasProps := schema["properties"].(map[string]any); hasProps {
-		for key := range props {
-			allowedProps[key] = struct{}{}
-		}
-	}
-	return allowedProps
-}
-
-func validateUnknownFields(input map[string]any, allowedProps map[string]struct{}) error {
-	for key := range input {
-		if _, allowed := allowedProps[key]; !allowed {
-			return fmt.Errorf("%w: unknown field %q", ErrToolValidationFailed, key)
-		}
-	}
-	return nil
-}
-
 func extractStringSlice(v any) []string {
 	if arr, isArr := v.([]any); isArr {
 		out := make([]string, 0, len(arr))
@@ -23,6 +23,8 @@ func (noopExecutor) Execute(_ context.Context, _ json.RawMessage) (json.RawMessa
 	return json.RawMessage(`{"ok":true}`), nil
 }
 
+func (noopExecutor) ParamsSchema() json.RawMessage { return nil }
+
 type toolPermStub struct {
 	allow bool
 	err   error
@@ -117,8 +119,9 @@ func TestToolRegistry_ValidateParams_InvalidJSON_ReturnsError(t *testing.T) {
 	if err == nil {
 		t.Fatalf("expected validation error for invalid JSON")
 	}
-	if !errors.Is(err, ErrToolValidationFailed) {
-		t.Fatalf("expected ErrToolValidationFailed, got: %v", err)
+	var valErr *ParamsValidationError
+	if !errors.As(err, &valErr) || !errors.Is(err, ErrToolValidationFailed) {
+		t.Fatalf("expected *ParamsValidationError, got: %v", err)
 	}
 }
 
@@ -172,6 +175,37 @@ func TestToolRegistry_ListToolDefinitions_DeserializesSchemaAndPerms(t *testing.
 	}
 }
 
+func TestToolRegistry_KnownToolNames(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	r := NewToolRegistry(db)
+	if err := r.Register("crm_webhook", noopExecutor{}); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	if _, err := r.CreateToolDefinition(context.Background(), CreateToolDefinitionInput{
+		WorkspaceID: wsID,
+		Name:        "lookup_invoice",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}},"additionalProperties":false}`),
+	}); err != nil {
+		t.Fatalf("CreateToolDefinition returned error: %v", err)
+	}
+
+	known, err := r.KnownToolNames(context.Background(), wsID)
+	if err != nil {
+		t.Fatalf("KnownToolNames returned error: %v", err)
+	}
+	for _, name := range []string{BuiltinCreateTask, SearchKnowledge, "crm_webhook", "lookup_invoice"} {
+		if !known[name] {
+			t.Errorf("KnownToolNames missing %q", name)
+		}
+	}
+	if known["bogus_tool"] {
+		t.Errorf("KnownToolNames unexpectedly contains bogus_tool")
+	}
+}
+
 func TestToolRegistry_UpdateActivateDeactivateDeleteLifecycle(t *testing.T) {
 	t.Parallel()
 
@@ -323,6 +357,89 @@ func TestToolRegistry_Execute_BuiltinAuditAndErrorContract(t *testing.T) {
 	}
 }
 
+func TestToolRegistry_Execute_AuditsAgentToolCall(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	auditService := audit.NewAuditService(db)
+	r := NewToolRegistryWithRuntime(db, toolPermStub{allow: true}, auditService)
+	if err := r.Register(BuiltinCreateTask, noopExecutor{}); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	if err := r.EnsureBuiltInToolDefinitions(context.Background(), wsID); err != nil {
+		t.Fatalf("EnsureBuiltInToolDefinitions returned error: %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), ctxkeys.UserID, "user-1")
+	ctx = context.WithValue(ctx, ctxkeys.RunID, "run-1")
+	params := json.RawMessage(`{"owner_id":"user-1","title":"Follow up","entity_type":"case","entity_id":"case-1"}`)
+	if _, err := r.Execute(ctx, wsID, BuiltinCreateTask, params); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	events, err := auditService.ListByAction(context.Background(), wsID, BuiltinCreateTask, 10, 0)
+	if err != nil {
+		t.Fatalf("ListByAction returned error: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 create_task audit event, got %d", len(events))
+	}
+	event := events[0]
+	if event.ActorType != audit.ActorTypeAgent || event.ActorID != "run-1" {
+		t.Fatalf("unexpected actor %s/%s", event.ActorType, event.ActorID)
+	}
+	if event.Outcome != audit.OutcomeSuccess {
+		t.Fatalf("unexpected outcome: %s", event.Outcome)
+	}
+	if event.EntityType == nil || *event.EntityType != "case" || event.EntityID == nil || *event.EntityID != "case-1" {
+		t.Fatalf("unexpected target entity %v/%v", event.EntityType, event.EntityID)
+	}
+	var details struct {
+		Metadata struct {
+			Params            map[string]any `json:"params"`
+			RunID             string         `json:"run_id"`
+			TriggeredByUserID string         `json:"triggered_by_user_id"`
+		} `json:"metadata"`
+	}
+	if err := json.Unmarshal(event.Details, &details); err != nil {
+		t.Fatalf("unmarshal details: %v", err)
+	}
+	if details.Metadata.Params["title"] != "Follow up" || details.Metadata.RunID != "run-1" || details.Metadata.TriggeredByUserID != "user-1" {
+		t.Fatalf("unexpected details: %s", event.Details)
+	}
+}
+
+func TestToolRegistry_RunInProcess_AuditsAndRecordsUsage(t *testing.T) {
+	t.Parallel()
+
+	auditStub := &toolAuditStub{}
+	usageStub := &toolUsageStub{}
+	r := NewToolRegistryWithRuntimeAndUsage(nil, nil, auditStub, usageStub)
+	ctx := context.WithValue(context.Background(), ctxkeys.RunID, "run-1")
+	params := json.RawMessage(`{"query":"reset password"}`)
+
+	if err := r.RunInProcess(ctx, "ws-1", SearchKnowledge, params, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("RunInProcess returned error: %v", err)
+	}
+	underlying := errors.New("search failed")
+	err := r.RunInProcess(ctx, "ws-1", SearchKnowledge, params, func(context.Context) error { return underlying })
+	var execErr *ExecutionError
+	if !errors.As(err, &execErr) || execErr.Code != ToolErrorInternal || !errors.Is(err, underlying) {
+		t.Fatalf("expected internal ExecutionError wrapping the failure, got %v", err)
+	}
+
+	if len(auditStub.actions) != 2 || auditStub.actions[0] != SearchKnowledge || auditStub.outcomes[0] != audit.OutcomeSuccess {
+		t.Fatalf("unexpected audit actions %#v outcomes %#v", auditStub.actions, auditStub.outcomes)
+	}
+	if auditStub.outcomes[1] == audit.OutcomeSuccess {
+		t.Fatalf("failed call audited as success: %#v", auditStub.outcomes)
+	}
+	if len(usageStub.inputs) != 2 || usageStub.inputs[0].ToolName == nil || *usageStub.inputs[0].ToolName != SearchKnowledge {
+		t.Fatalf("unexpected usage events: %#v", usageStub.inputs)
+	}
+}
+
 func TestToolRegistry_Execute_RecordsUsage(t *testing.T) {
 	t.Parallel()
 
@@ -371,62 +488,37 @@ func TestToolRegistry_Execute_RecordsUsage(t *testing.T) {
 	}
 }
 
-func TestValidateAgainstMinimalSchema(t *testing.T) {
+func TestValidateParamsSchema_MinimalSchemas(t *testing.T) {
 	t.Parallel()
 
-	t.Run("missing required field", func(t *testing.T) {
-		t.Parallel()
-		input := map[string]any{"name": "alice"}
-		schema := map[string]any{
-			"required": []any{"name", "email"},
-		}
-
-		err := validateAgainstMinimalSchema(input, schema)
-		if !errors.Is(err, ErrToolValidationFailed) {
-			t.Fatalf("expected ErrToolValidationFailed, got %v", err)
-		}
-	})
-
-	t.Run("unknown field rejected when additional properties false", func(t *testing.T) {
-		t.Parallel()
-		input := map[string]any{"name": "alice", "unexpected": true}
-		schema := map[string]any{
-			"additionalProperties": false,
-			"properties": map[string]any{
-				"name": map[string]any{"type": "string"},
-			},
-		}
-
-		err := validateAgainstMinimalSchema(input, schema)
-		if !errors.Is(err, ErrToolValidationFailed) {
-			t.Fatalf("expected ErrToolValidationFailed, got %v", err)
-		}
-	})
-
-	t.Run("unknown field allowed when additional properties true", func(t *testing.T) {
-		t.Parallel()
-		input := map[string]any{"name": "alice", "unexpected": true}
-		schema := map[string]any{
-			"additionalProperties": true,
-			"properties": map[string]any{
-				"name": map[string]any{"type": "string"},
-			},
-		}
-
-		if err := validateAgainstMinimalSchema(input, schema); err != nil {
-			t.Fatalf("validateAgainstMinimalSchema returned error: %v", err)
-		}
-	})
-
-	t.Run("default additional properties true", func(t *testing.T) {
-		t.Parallel()
-		input := map[string]any{"unknown": true}
-		schema := map[string]any{}
-
-		if err := validateAgainstMinimalSchema(input, schema); err != nil {
-			t.Fatalf("validateAgainstMinimalSchema returned error: %v", err)
-		}
-	})
+	cases := []struct {
+		name    string
+		schema  string
+		params  string
+		wantErr bool
+	}{
+		{"missing required field", `{"required":["name","email"]}`, `{"name":"alice"}`, true},
+		{"unknown field rejected when additional properties false", `{"additionalProperties":false,"properties":{"name":{"type":"string"}}}`, `{"name":"alice","unexpected":true}`, true},
+		{"unknown field allowed when additional properties true", `{"additionalProperties":true,"properties":{"name":{"type":"string"}}}`, `{"name":"alice","unexpected":true}`, false},
+		{"default additional properties true", `{}`, `{"unknown":true}`, false},
+		{"params must be an object", `{}`, `["a"]`, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			err := validateParamsSchema("tool", json.RawMessage(tc.schema), json.RawMessage(tc.params))
+			if !tc.wantErr {
+				if err != nil {
+					t.Fatalf("validateParamsSchema returned error: %v", err)
+				}
+				return
+			}
+			var valErr *ParamsValidationError
+			if !errors.As(err, &valErr) || !errors.Is(err, ErrToolValidationFailed) {
+				t.Fatalf("expected *ParamsValidationError, got %v", err)
+			}
+		})
+	}
 }
 
 func TestExtractStringSlice(t *testing.T) {
@@ -0,0 +1,171 @@
+package tool
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"unicode/utf8"
+)
+
+// DefaultMaxResultBytes caps the encoded size of a tool result handed back to
+// an agent (about 4k tokens), so a single call cannot fill the model context.
+const DefaultMaxResultBytes = 16 * 1024
+
+const (
+	// resultTruncatedKey flags a result cut down to the size cap.
+	resultTruncatedKey = "truncated"
+	// resultOmittedKey maps each shortened array field to the number of
+	// elements dropped from its end.
+	resultOmittedKey = "omitted"
+	// resultBytesKey reports the original size of a result that could not be
+	// shortened to fit.
+	resultBytesKey = "result_bytes"
+	truncationMark = "…"
+)
+
+// SetMaxResultBytes sets the size cap applied to results of Execute. Results
+// over the cap are shortened and flagged with "truncated": true; n <= 0
+// disables the cap.
+func (r *ToolRegistry) SetMaxResultBytes(n int) {
+	r.maxResultBytes = n
+}
+
+// limitResult shortens out to at most maxBytes when it is a larger JSON
+// object or array. Arrays lose trailing elements and strings their tail,
+// largest fields first; the result gains "truncated": true and, for arrays,
+// "omitted" counts. A top-level array is wrapped as {"data": [...]}. Results
+// that cannot be shortened become {"truncated": true, "result_bytes": n}.
+func limitResult(out json.RawMessage, maxBytes int) json.RawMessage {
+	if maxBytes <= 0 || len(out) <= maxBytes {
+		return out
+	}
+
+	var value any
+	dec := json.NewDecoder(bytes.NewReader(out))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return tooLargeResult(len(out))
+	}
+
+	obj, ok := value.(map[string]any)
+	if !ok {
+		list, isList := value.([]any)
+		if !isList {
+			return tooLargeResult(len(out))
+		}
+		obj = map[string]any{"data": list}
+	}
+
+	omitted := map[string]int{}
+	obj[resultTruncatedKey] = true
+	obj[resultOmittedKey] = omitted
+	for _, key := range keysBySize(obj) {
+		size := encodedSize(obj)
+		if size <= maxBytes {
+			break
+		}
+		if list, isList := obj[key].([]any); isList {
+			// Reserve room for the omitted count before sizing the array.
+			omitted[key] = len(list)
+			size = encodedSize(obj)
+		}
+		budget := encodedSize(obj[key]) - (size - maxBytes)
+		shrunk, dropped := shrinkValue(obj[key], budget)
+		obj[key] = shrunk
+		if dropped > 0 {
+			omitted[key] = dropped
+		} else {
+			delete(omitted, key)
+		}
+	}
+	if len(omitted) == 0 {
+		delete(obj, resultOmittedKey)
+	}
+
+	limited, err := json.Marshal(obj)
+	if err != nil || len(limited) > maxBytes {
+		return tooLargeResult(len(out))
+	}
+	return limited
+}
+
+// shrinkValue cuts v down to an encoded size of at most budget where it can:
+// arrays keep their longest fitting prefix (dropped reports how many
+// elements went), strings their longest fitting prefix and objects shrink
+// their largest fields. Other values are returned unchanged.
+func shrinkValue(v any, budget int) (shrunk any, dropped int) {
+	switch x := v.(type) {
+	case []any:
+		keep := sort.Search(len(x)+1, func(n int) bool {
+			return encodedSize(x[:n]) > budget
+		}) - 1
+		keep = max(keep, 0)
+		return x[:keep], len(x) - keep
+	case string:
+		return shrinkString(x, budget), 0
+	case map[string]any:
+		for _, key := range keysBySize(x) {
+			size := encodedSize(x)
+			if size <= budget {
+				break
+			}
+			x[key], _ = shrinkValue(x[key], encodedSize(x[key])-(size-budget))
+		}
+		return x, 0
+	default:
+		return v, 0
+	}
+}
+
+// shrinkString returns the longest prefix of s, marked as cut, whose JSON
+// encoding fits budget, or "" if none does.
+func shrinkString(s string, budget int) string {
+	if encodedSize(s) <= budget {
+		return s
+	}
+	n := sort.Search(len(s)+1, func(n int) bool {
+		return encodedSize(s[:n]+truncationMark) > budget
+	}) - 1
+	if n <= 0 {
+		return ""
+	}
+	for n > 0 && !utf8.ValidString(s[:n]) {
+		n--
+	}
+	return s[:n] + truncationMark
+}
+
+// keysBySize returns the keys of obj, largest encoded value first, skipping
+// the truncation markers.
+func keysBySize(obj map[string]any) []string {
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		if key != resultTruncatedKey && key != resultOmittedKey {
+			keys = append(keys, key)
+		}
+	}
+	sizes := make(map[string]int, len(keys))
+	for _, key := range keys {
+		sizes[key] = encodedSize(obj[key])
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if sizes[keys[i]] != sizes[keys[j]] {
+			return sizes[keys[i]] > sizes[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func encodedSize(v any) int {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+func tooLargeResult(size int) json.RawMessage {
+	out, _ := json.Marshal(map[string]any{resultTruncatedKey: true, resultBytesKey: size})
+	return out
+}
@@ -0,0 +1,126 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+)
+
+type fixedExecutor struct{ out json.RawMessage }
+
+func (e fixedExecutor) Execute(_ context.Context, _ json.RawMessage) (json.RawMessage, error) {
+	return e.out, nil
+}
+
+func (fixedExecutor) ParamsSchema() json.RawMessage { return nil }
+
+func TestLimitResult_UnderCapIsUnchanged(t *testing.T) {
+	t.Parallel()
+
+	out := json.RawMessage(`{"id":"lead-1","status":"new"}`)
+	if got := limitResult(out, 1024); string(got) != string(out) {
+		t.Fatalf("limitResult = %s; want unchanged", got)
+	}
+	if got := limitResult(out, 0); string(got) != string(out) {
+		t.Fatalf("limitResult with cap disabled = %s; want unchanged", got)
+	}
+}
+
+func TestLimitResult_TruncatesArraysAndStrings(t *testing.T) {
+	t.Parallel()
+
+	rows := make([]map[string]any, 200)
+	for i := range rows {
+		rows[i] = map[string]any{"stage_id": strings.Repeat("s", 20), "deal_count": i}
+	}
+	out, _ := json.Marshal(map[string]any{"metric": "sales_funnel", "data": rows})
+
+	got := limitResult(out, 1024)
+	if len(got) > 1024 {
+		t.Fatalf("len = %d; want <= 1024", len(got))
+	}
+	var body struct {
+		Metric    string           `json:"metric"`
+		Data      []map[string]any `json:"data"`
+		Truncated bool             `json:"truncated"`
+		Omitted   map[string]int   `json:"omitted"`
+	}
+	if err := json.Unmarshal(got, &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !body.Truncated || body.Metric != "sales_funnel" || len(body.Data) == 0 {
+		t.Fatalf("body = %+v", body)
+	}
+	if body.Omitted["data"] != 200-len(body.Data) {
+		t.Fatalf("omitted = %v with %d rows kept; want %d", body.Omitted, len(body.Data), 200-len(body.Data))
+	}
+
+	account, _ := json.Marshal(map[string]any{"id": "acc-1", "notes": strings.Repeat("ñ", 5000)})
+	got = limitResult(account, 512)
+	var acc map[string]any
+	if err := json.Unmarshal(got, &acc); err != nil {
+		t.Fatalf("decode account: %v", err)
+	}
+	notes, _ := acc["notes"].(string)
+	if len(got) > 512 || acc["id"] != "acc-1" || acc[resultTruncatedKey] != true || !strings.HasSuffix(notes, truncationMark) {
+		t.Fatalf("account result = %s", got)
+	}
+}
+
+func TestLimitResult_TopLevelArrayAndUnshrinkable(t *testing.T) {
+	t.Parallel()
+
+	list, _ := json.Marshal(make([]int, 1000))
+	var wrapped map[string]any
+	if err := json.Unmarshal(limitResult(list, 256), &wrapped); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, ok := wrapped["data"].([]any); !ok || wrapped[resultTruncatedKey] != true {
+		t.Fatalf("wrapped = %v; want data array and truncated flag", wrapped)
+	}
+
+	huge := json.RawMessage(`"` + strings.Repeat("x", 500) + `"`)
+	if got := string(limitResult(huge, 64)); got != `{"result_bytes":502,"truncated":true}` {
+		t.Fatalf("limitResult(scalar) = %s", got)
+	}
+}
+
+func TestToolRegistry_Execute_CapsResultSize(t *testing.T) {
+	t.Parallel()
+
+	db := openToolTestDB(t)
+	wsID := createWorkspace(t, db)
+	r := NewToolRegistry(db)
+	big, _ := json.Marshal(map[string]any{"items": make([]string, DefaultMaxResultBytes)})
+	if err := r.Register("list_everything", fixedExecutor{out: big}); err != nil {
+		t.Fatalf("Register returned error: %v", err)
+	}
+	if _, err := r.CreateToolDefinition(context.Background(), CreateToolDefinitionInput{
+		WorkspaceID: wsID,
+		Name:        "list_everything",
+		InputSchema: json.RawMessage(`{"type":"object","properties":{"filter":{"type":"string"}},"additionalProperties":false}`),
+	}); err != nil {
+		t.Fatalf("CreateToolDefinition returned error: %v", err)
+	}
+	ctx := context.WithValue(context.Background(), ctxkeys.UserID, "user-1")
+
+	out, err := r.Execute(ctx, wsID, "list_everything", nil)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(out) > DefaultMaxResultBytes || !strings.Contains(string(out), `"truncated":true`) {
+		t.Fatalf("default cap: len = %d, truncated flag missing: %.80s", len(out), out)
+	}
+
+	r.SetMaxResultBytes(0)
+	out, err = r.Execute(ctx, wsID, "list_everything", nil)
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(out) != len(big) {
+		t.Fatalf("disabled cap: len = %d; want %d", len(out), len(big))
+	}
+}
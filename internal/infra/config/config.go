@@ -4,7 +4,9 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config holds runtime configuration for FenixCRM.
@@ -25,6 +27,20 @@ type Config struct {
 	OpenAICompatBaseURL string // OPENAI_COMPAT_BASE_URL
 	OpenAICompatAPIKey  string // OPENAI_COMPAT_API_KEY
 	OpenAICompatModel   string // OPENAI_COMPAT_MODEL
+	// EmbedDimensions is the vector length the embed model must return.
+	// 0 infers it from the vectors already stored for each workspace.
+	EmbedDimensions int // EMBED_DIMENSIONS — default: 0
+	// EmbedBatchSize caps the chunks sent per embed call; 0 embeds all of an
+	// item's chunks in one call. EmbedWorkers is how many of an item's batches
+	// are embedded concurrently.
+	EmbedBatchSize int // EMBED_BATCH_SIZE — default: 0
+	EmbedWorkers   int // EMBED_WORKERS — default: 1
+	// SearchFeedbackBoost caps the ranking bonus of search results users
+	// marked helpful, as a share (0-1) of a first-rank RRF score. 0 disables it.
+	SearchFeedbackBoost float64 // SEARCH_FEEDBACK_BOOST — default: 0.5
+	// LLMHealthInterval is how often the chat and embed providers are
+	// health-checked for /readyz and agent triggers.
+	LLMHealthInterval time.Duration // LLM_HEALTH_INTERVAL — default: 30s
 
 	// Security
 	// BFFOrigin is the primary allowed CORS origin for the BFF (Express gateway).
@@ -32,6 +48,21 @@ type Config struct {
 	// Set via CORS_ALLOWED_ORIGINS as a comma-separated list, or BFF_ORIGIN for legacy single-origin config.
 	BFFOrigin          string   // BFF_ORIGIN — default: "http://localhost:3000"
 	CORSAllowedOrigins []string // CORS_ALLOWED_ORIGINS — default: BFFOrigin + local dev origins
+	// PasswordBreachCheck rejects registration passwords found in the
+	// HaveIBeenPwned corpus via its k-anonymity range API.
+	PasswordBreachCheck bool // PASSWORD_BREACH_CHECK — default: false
+	// TrustedProxies lists the reverse proxies (IPs or CIDRs) whose
+	// X-Forwarded-For / X-Real-IP headers name the client. Without any, the
+	// socket address is the client IP for rate limits and login lockout.
+	TrustedProxies []string // TRUSTED_PROXIES — default: none
+
+	// Agent runtime
+	// ToolMaxResultBytes caps the encoded size of tool results returned to
+	// agents; larger results are truncated. 0 keeps tool.DefaultMaxResultBytes.
+	ToolMaxResultBytes int // TOOL_MAX_RESULT_BYTES — default: 0
+	// WebhookSecretKey encrypts the run webhook secrets stored in agent
+	// definitions. Changing it invalidates the stored secrets.
+	WebhookSecretKey string // WEBHOOK_SECRET_KEY — default: JWT_SECRET
 }
 
 const (
@@ -48,8 +79,20 @@ const (
 	envKeyEmbedProvider       = "EMBED_PROVIDER"
 	envKeyOpenAICompatBaseURL = "OPENAI_COMPAT_BASE_URL"
 	//nolint:gosec // env var key name, not a credential value
-	envKeyOpenAICompatAPIKey = "OPENAI_COMPAT_API_KEY"
-	envKeyOpenAICompatModel  = "OPENAI_COMPAT_MODEL"
+	envKeyOpenAICompatAPIKey  = "OPENAI_COMPAT_API_KEY"
+	envKeyOpenAICompatModel   = "OPENAI_COMPAT_MODEL"
+	envKeyEmbedDimensions     = "EMBED_DIMENSIONS"
+	envKeyEmbedBatchSize      = "EMBED_BATCH_SIZE"
+	envKeyEmbedWorkers        = "EMBED_WORKERS"
+	envKeySearchFeedbackBoost = "SEARCH_FEEDBACK_BOOST"
+	envKeyLLMHealthInterval   = "LLM_HEALTH_INTERVAL"
+
+	envKeyPasswordBreachCheck = "PASSWORD_BREACH_CHECK"
+	envKeyTrustedProxies      = "TRUSTED_PROXIES"
+	envKeyToolMaxResultBytes  = "TOOL_MAX_RESULT_BYTES"
+	//nolint:gosec // env var key names, not credential values
+	envKeyWebhookSecretKey = "WEBHOOK_SECRET_KEY"
+	envKeyJWTSecret        = "JWT_SECRET"
 )
 
 // Load reads configuration from environment variables, applying defaults for missing values.
@@ -73,8 +116,17 @@ func Load() Config {
 		OpenAICompatBaseURL: envOr(envKeyOpenAICompatBaseURL, ""),
 		OpenAICompatAPIKey:  envOr(envKeyOpenAICompatAPIKey, ""),
 		OpenAICompatModel:   envOr(envKeyOpenAICompatModel, ""),
+		EmbedDimensions:     envIntOr(envKeyEmbedDimensions, 0),
+		EmbedBatchSize:      envIntOr(envKeyEmbedBatchSize, 0),
+		EmbedWorkers:        envIntOr(envKeyEmbedWorkers, 1),
+		SearchFeedbackBoost: envFloatOr(envKeySearchFeedbackBoost, 0.5),
+		LLMHealthInterval:   envDurationOr(envKeyLLMHealthInterval, 30*time.Second),
 		BFFOrigin:           bffOrigin,
 		CORSAllowedOrigins:  corsAllowedOrigins(bffOrigin),
+		PasswordBreachCheck: envBoolOr(envKeyPasswordBreachCheck, false),
+		TrustedProxies:      splitCSV(os.Getenv(envKeyTrustedProxies)),
+		ToolMaxResultBytes:  envIntOr(envKeyToolMaxResultBytes, 0),
+		WebhookSecretKey:    envOr(envKeyWebhookSecretKey, os.Getenv(envKeyJWTSecret)),
 	}
 }
 
@@ -126,3 +178,43 @@ func envOr(key, fallback string) string {
 	}
 	return fallback
 }
+
+// envIntOr returns the environment variable key as a non-negative int, or
+// fallback if it is unset or not such a number.
+func envIntOr(key string, fallback int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n < 0 {
+		return fallback
+	}
+	return n
+}
+
+// envFloatOr returns the environment variable key as a non-negative float, or
+// fallback if it is unset or not such a number.
+func envFloatOr(key string, fallback float64) float64 {
+	f, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || f < 0 {
+		return fallback
+	}
+	return f
+}
+
+// envDurationOr returns the environment variable key as a positive
+// duration such as "30s", or fallback if it is unset or not such a duration.
+func envDurationOr(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// envBoolOr returns the environment variable key parsed as a bool, or
+// fallback if it is unset or not a bool.
+func envBoolOr(key string, fallback bool) bool {
+	b, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return b
+}
@@ -2,7 +2,10 @@
 // No t.Parallel() — env vars are process-global and not thread-safe.
 package config
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestLoad_Defaults(t *testing.T) {
 	// Ensure env vars are unset so defaults apply.
@@ -15,8 +18,13 @@ func TestLoad_Defaults(t *testing.T) {
 	t.Setenv("OPENAI_COMPAT_BASE_URL", "")
 	t.Setenv("OPENAI_COMPAT_API_KEY", "")
 	t.Setenv("OPENAI_COMPAT_MODEL", "")
+	t.Setenv("EMBED_DIMENSIONS", "")
+	t.Setenv("EMBED_BATCH_SIZE", "")
+	t.Setenv("EMBED_WORKERS", "")
 	t.Setenv("BFF_ORIGIN", "")
 	t.Setenv("CORS_ALLOWED_ORIGINS", "")
+	t.Setenv("PASSWORD_BREACH_CHECK", "")
+	t.Setenv("LLM_HEALTH_INTERVAL", "")
 
 	cfg := Load()
 
@@ -42,6 +50,21 @@ func TestLoad_Defaults(t *testing.T) {
 	if cfg.OpenAICompatBaseURL != "" {
 		t.Errorf("expected empty OpenAICompatBaseURL, got %q", cfg.OpenAICompatBaseURL)
 	}
+	if cfg.EmbedDimensions != 0 {
+		t.Errorf("expected EmbedDimensions 0, got %d", cfg.EmbedDimensions)
+	}
+	if cfg.EmbedBatchSize != 0 || cfg.EmbedWorkers != 1 {
+		t.Errorf("expected EmbedBatchSize 0 and EmbedWorkers 1, got %d and %d", cfg.EmbedBatchSize, cfg.EmbedWorkers)
+	}
+	if cfg.SearchFeedbackBoost != 0.5 {
+		t.Errorf("expected SearchFeedbackBoost 0.5, got %v", cfg.SearchFeedbackBoost)
+	}
+	if cfg.PasswordBreachCheck {
+		t.Error("expected PasswordBreachCheck false by default")
+	}
+	if cfg.LLMHealthInterval != 30*time.Second {
+		t.Errorf("expected LLMHealthInterval 30s, got %v", cfg.LLMHealthInterval)
+	}
 	if !containsString(cfg.CORSAllowedOrigins, "http://localhost:3000") || !containsString(cfg.CORSAllowedOrigins, "http://localhost:5173") {
 		t.Errorf("expected default CORSAllowedOrigins to include BFF and local dev origins, got %#v", cfg.CORSAllowedOrigins)
 	}
@@ -52,8 +75,33 @@ func TestLoad_EnvOverrides(t *testing.T) {
 	t.Setenv("OLLAMA_BASE_URL", "http://ollama.internal:11434")
 	t.Setenv("OLLAMA_MODEL", "mxbai-embed-large")
 	t.Setenv("OLLAMA_CHAT_MODEL", "llama3.1:8b")
+	t.Setenv("EMBED_DIMENSIONS", "1024")
+	t.Setenv("EMBED_BATCH_SIZE", "32")
+	t.Setenv("EMBED_WORKERS", "4")
+	t.Setenv("PASSWORD_BREACH_CHECK", "true")
+	t.Setenv("TOOL_MAX_RESULT_BYTES", "4096")
+	t.Setenv("SEARCH_FEEDBACK_BOOST", "0.2")
+	t.Setenv("LLM_HEALTH_INTERVAL", "5s")
 
 	cfg := Load()
+	if cfg.LLMHealthInterval != 5*time.Second {
+		t.Errorf("expected LLMHealthInterval 5s, got %v", cfg.LLMHealthInterval)
+	}
+	if cfg.SearchFeedbackBoost != 0.2 {
+		t.Errorf("expected SearchFeedbackBoost 0.2, got %v", cfg.SearchFeedbackBoost)
+	}
+	if cfg.ToolMaxResultBytes != 4096 {
+		t.Errorf("expected ToolMaxResultBytes 4096, got %d", cfg.ToolMaxResultBytes)
+	}
+	if cfg.EmbedDimensions != 1024 {
+		t.Errorf("expected EmbedDimensions 1024, got %d", cfg.EmbedDimensions)
+	}
+	if cfg.EmbedBatchSize != 32 || cfg.EmbedWorkers != 4 {
+		t.Errorf("expected EmbedBatchSize 32 and EmbedWorkers 4, got %d and %d", cfg.EmbedBatchSize, cfg.EmbedWorkers)
+	}
+	if !cfg.PasswordBreachCheck {
+		t.Error("expected PasswordBreachCheck true")
+	}
 
 	if cfg.LLMProvider != "openai" {
 		t.Errorf("expected LLMProvider 'openai', got %q", cfg.LLMProvider)
@@ -89,6 +137,19 @@ func TestLoad_ChatProvider_OverridesLLMProvider(t *testing.T) {
 	}
 }
 
+func TestLoad_WebhookSecretKey_FallsBackToJWTSecret(t *testing.T) {
+	t.Setenv("WEBHOOK_SECRET_KEY", "")
+	t.Setenv("JWT_SECRET", "jwt-secret")
+
+	if cfg := Load(); cfg.WebhookSecretKey != "jwt-secret" {
+		t.Errorf("expected WebhookSecretKey to fall back to JWT_SECRET, got %q", cfg.WebhookSecretKey)
+	}
+	t.Setenv("WEBHOOK_SECRET_KEY", "webhook-key")
+	if cfg := Load(); cfg.WebhookSecretKey != "webhook-key" {
+		t.Errorf("expected WebhookSecretKey 'webhook-key', got %q", cfg.WebhookSecretKey)
+	}
+}
+
 func TestLoad_OpenAICompatFields(t *testing.T) {
 	t.Setenv("CHAT_PROVIDER", "openai-compat")
 	t.Setenv("OPENAI_COMPAT_BASE_URL", "https://api.groq.com/openai")
@@ -4,13 +4,19 @@
 //
 // Design:
 //   - Buffered Go channel per topic (buffer=100).
-//   - Publish is non-blocking: drops the event silently if the buffer is full.
+//   - Publish is non-blocking: drops the event if the buffer is full and logs
+//     the drop as a warning.
 //   - Subscribe returns a read-only channel; the caller owns the consumption loop.
 //   - No persistence: events are fire-and-forget (MVP constraint).
 //   - EventBus interface for testability.
 package eventbus
 
-import "sync"
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+)
 
 // Event is a single published message.
 type Event struct {
@@ -30,6 +36,7 @@ const defaultBufferSize = 100
 type Bus struct {
 	mu          sync.RWMutex
 	subscribers map[string][]chan Event
+	logger      *slog.Logger
 }
 
 // New returns a new in-memory Bus.
@@ -39,6 +46,14 @@ func New() *Bus {
 	}
 }
 
+// SetLogger sets the logger dropped events are reported to. Without one the
+// bus logs to slog.Default().
+func (b *Bus) SetLogger(l *slog.Logger) {
+	b.mu.Lock()
+	b.logger = l
+	b.mu.Unlock()
+}
+
 // Subscribe registers a new subscriber for topic and returns a read-only channel.
 // The caller must consume the channel to prevent blocking on future Publish calls.
 func (b *Bus) Subscribe(topic string) <-chan Event {
@@ -50,17 +65,25 @@ func (b *Bus) Subscribe(topic string) <-chan Event {
 }
 
 // Publish sends an Event to all subscribers of topic.
-// If a subscriber's buffer is full the event is dropped (non-blocking).
+// If a subscriber's buffer is full the event is dropped (non-blocking) and
+// the drop is logged.
 func (b *Bus) Publish(topic string, payload any) {
 	evt := Event{Topic: topic, Payload: payload}
 	b.mu.RLock()
 	subs := b.subscribers[topic]
+	logger := b.logger
 	b.mu.RUnlock()
 	for _, ch := range subs {
 		select {
 		case ch <- evt:
 		default:
 			// buffer full — drop event (fire-and-forget)
+			if logger == nil {
+				logger = slog.Default()
+			}
+			logger.Warn("event dropped: subscriber buffer full",
+				slog.String(logging.KeyComponent, "eventbus"),
+				slog.String(logging.KeyTopic, topic))
 		}
 	}
 }
@@ -2,6 +2,9 @@
 package eventbus
 
 import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
 	"testing"
 	"time"
 )
@@ -90,3 +93,22 @@ func TestEventBus_NonBlockingPublish_FullBuffer(t *testing.T) {
 		t.Error("Publish blocked when buffer was full (should be non-blocking)")
 	}
 }
+
+func TestEventBus_FullBuffer_LogsDroppedEvent(t *testing.T) {
+	var out bytes.Buffer
+	bus := New()
+	bus.SetLogger(slog.New(slog.NewJSONHandler(&out, nil)))
+	_ = bus.Subscribe("overflow.topic")
+
+	for i := 0; i <= defaultBufferSize; i++ {
+		bus.Publish("overflow.topic", i)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("want one JSON record for the dropped event, got %q: %v", out.String(), err)
+	}
+	if record["level"] != "WARN" || record["component"] != "eventbus" || record["topic"] != "overflow.topic" {
+		t.Fatalf("record = %v; want a WARN from eventbus for overflow.topic", record)
+	}
+}
@@ -0,0 +1,153 @@
+// Package llm — Embed result cache.
+package llm
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+)
+
+// DefaultEmbedCacheSize is the number of embeddings CachingProvider keeps
+// when no size is given. At 768 dims that is roughly 12 MB of vectors.
+const DefaultEmbedCacheSize = 4096
+
+// CachingProvider wraps an LLMProvider and caches Embed results per text,
+// keyed by a hash of the model ID and the text, evicting the least recently
+// used entry once size is reached. Boilerplate chunks shared by many
+// documents are embedded once. ChatCompletion is never cached.
+type CachingProvider struct {
+	LLMProvider
+
+	mu      sync.Mutex
+	size    int
+	entries map[[sha256.Size]byte]*list.Element
+	order   *list.List // front = most recently used
+	hits    int64
+	misses  int64
+}
+
+// EmbedCacheStats reports cumulative cache lookups of a CachingProvider.
+type EmbedCacheStats struct {
+	Hits    int64
+	Misses  int64
+	Entries int
+}
+
+type embedCacheEntry struct {
+	key    [sha256.Size]byte
+	vector []float32
+}
+
+// NewCachingProvider wraps inner with an Embed cache of at most size entries.
+// size <= 0 uses DefaultEmbedCacheSize.
+func NewCachingProvider(inner LLMProvider, size int) *CachingProvider {
+	if size <= 0 {
+		size = DefaultEmbedCacheSize
+	}
+	return &CachingProvider{
+		LLMProvider: inner,
+		size:        size,
+		entries:     make(map[[sha256.Size]byte]*list.Element),
+		order:       list.New(),
+	}
+}
+
+// Embed returns cached vectors for texts seen before and asks the wrapped
+// provider only for the rest, in a single call. Duplicate texts within one
+// request are embedded once. Tokens reports only what the provider consumed.
+func (c *CachingProvider) Embed(ctx context.Context, req EmbedRequest) (*EmbedResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = c.ModelInfo().ID
+	}
+
+	embeddings := make([][]float32, len(req.Texts))
+	keys := make([][sha256.Size]byte, len(req.Texts))
+	missIndex := make(map[[sha256.Size]byte]int) // key → position in missTexts
+	missTexts := make([]string, 0)
+
+	c.mu.Lock()
+	for i, text := range req.Texts {
+		keys[i] = embedCacheKey(model, text)
+		if vec, ok := c.get(keys[i]); ok {
+			embeddings[i] = vec
+			c.hits++
+			continue
+		}
+		c.misses++
+		if _, pending := missIndex[keys[i]]; !pending {
+			missIndex[keys[i]] = len(missTexts)
+			missTexts = append(missTexts, text)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(missTexts) == 0 {
+		return &EmbedResponse{Embeddings: embeddings}, nil
+	}
+
+	resp, err := c.LLMProvider.Embed(ctx, EmbedRequest{Model: req.Model, Texts: missTexts})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Embeddings) != len(missTexts) {
+		return nil, fmt.Errorf("llm embed cache: provider returned %d embeddings for %d texts", len(resp.Embeddings), len(missTexts))
+	}
+
+	c.mu.Lock()
+	for key, pos := range missIndex {
+		c.put(key, resp.Embeddings[pos])
+	}
+	c.mu.Unlock()
+
+	for i := range embeddings {
+		if embeddings[i] == nil {
+			embeddings[i] = copyVector(resp.Embeddings[missIndex[keys[i]]])
+		}
+	}
+	return &EmbedResponse{Embeddings: embeddings, Tokens: resp.Tokens}, nil
+}
+
+// Stats returns the cumulative hit and miss counts and the current size.
+func (c *CachingProvider) Stats() EmbedCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return EmbedCacheStats{Hits: c.hits, Misses: c.misses, Entries: c.order.Len()}
+}
+
+// get returns a copy of the cached vector and marks it recently used.
+// Callers hold c.mu.
+func (c *CachingProvider) get(key [sha256.Size]byte) ([]float32, bool) {
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return copyVector(el.Value.(*embedCacheEntry).vector), true
+}
+
+// put stores a copy of vector, evicting the least recently used entry when
+// the cache is full. Callers hold c.mu.
+func (c *CachingProvider) put(key [sha256.Size]byte, vector []float32) {
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*embedCacheEntry).vector = copyVector(vector)
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&embedCacheEntry{key: key, vector: copyVector(vector)})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*embedCacheEntry).key)
+	}
+}
+
+func embedCacheKey(model, text string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(model + "\x00" + text))
+}
+
+func copyVector(v []float32) []float32 {
+	return append([]float32(nil), v...)
+}
@@ -0,0 +1,131 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// countingEmbedProvider returns one vector per text and records each call.
+type countingEmbedProvider struct {
+	calls [][]string
+	err   error
+}
+
+func (p *countingEmbedProvider) ChatCompletion(context.Context, ChatRequest) (*ChatResponse, error) {
+	return &ChatResponse{Content: "ok"}, nil
+}
+
+func (p *countingEmbedProvider) Embed(_ context.Context, req EmbedRequest) (*EmbedResponse, error) {
+	p.calls = append(p.calls, req.Texts)
+	if p.err != nil {
+		return nil, p.err
+	}
+	out := make([][]float32, len(req.Texts))
+	for i, text := range req.Texts {
+		out[i] = []float32{float32(len(text)), 1}
+	}
+	return &EmbedResponse{Embeddings: out, Tokens: len(req.Texts)}, nil
+}
+
+func (p *countingEmbedProvider) ModelInfo() ModelMeta { return ModelMeta{ID: "embed-test"} }
+
+func (p *countingEmbedProvider) HealthCheck(context.Context) error { return nil }
+
+func TestCachingProvider_RepeatedTextCallsProviderOnce(t *testing.T) {
+	t.Parallel()
+
+	inner := &countingEmbedProvider{}
+	cache := NewCachingProvider(inner, 8)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		resp, err := cache.Embed(ctx, EmbedRequest{Texts: []string{"Confidential, do not forward"}})
+		if err != nil {
+			t.Fatalf("Embed #%d: %v", i, err)
+		}
+		if len(resp.Embeddings) != 1 || resp.Embeddings[0][0] != 28 {
+			t.Fatalf("Embed #%d returned %v", i, resp.Embeddings)
+		}
+	}
+
+	if len(inner.calls) != 1 {
+		t.Fatalf("provider calls = %d; want 1", len(inner.calls))
+	}
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Fatalf("stats = %+v; want 2 hits, 1 miss, 1 entry", stats)
+	}
+}
+
+func TestCachingProvider_BatchSendsOnlyUncachedTexts(t *testing.T) {
+	t.Parallel()
+
+	inner := &countingEmbedProvider{}
+	cache := NewCachingProvider(inner, 8)
+	ctx := context.Background()
+
+	if _, err := cache.Embed(ctx, EmbedRequest{Texts: []string{"a"}}); err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+	resp, err := cache.Embed(ctx, EmbedRequest{Texts: []string{"a", "bb", "bb", "ccc"}})
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	if got := inner.calls[1]; len(got) != 2 || got[0] != "bb" || got[1] != "ccc" {
+		t.Fatalf("second provider call texts = %v; want [bb ccc]", got)
+	}
+	want := []float32{1, 2, 2, 3}
+	for i, vec := range resp.Embeddings {
+		if vec[0] != want[i] {
+			t.Fatalf("embedding %d = %v; want first value %v", i, vec, want[i])
+		}
+	}
+	if resp.Tokens != 2 {
+		t.Fatalf("Tokens = %d; want 2 (only provider-embedded texts)", resp.Tokens)
+	}
+}
+
+func TestCachingProvider_EvictsLeastRecentlyUsedAndKeysByModel(t *testing.T) {
+	t.Parallel()
+
+	inner := &countingEmbedProvider{}
+	cache := NewCachingProvider(inner, 2)
+	ctx := context.Background()
+	embed := func(model, text string) {
+		t.Helper()
+		if _, err := cache.Embed(ctx, EmbedRequest{Model: model, Texts: []string{text}}); err != nil {
+			t.Fatalf("Embed(%q, %q): %v", model, text, err)
+		}
+	}
+
+	embed("", "a")
+	embed("", "b")
+	embed("", "a") // a is now most recently used
+	embed("", "c") // evicts b
+	embed("", "a")
+	embed("", "b")
+	embed("other-model", "a")
+
+	if len(inner.calls) != 5 {
+		t.Fatalf("provider calls = %d; want 5 (a, b, c, b again, a for other-model)", len(inner.calls))
+	}
+}
+
+func TestCachingProvider_ProviderErrorIsNotCached(t *testing.T) {
+	t.Parallel()
+
+	inner := &countingEmbedProvider{err: errors.New("ollama down")}
+	cache := NewCachingProvider(inner, 8)
+
+	if _, err := cache.Embed(context.Background(), EmbedRequest{Texts: []string{"a"}}); err == nil {
+		t.Fatal("expected provider error")
+	}
+	inner.err = nil
+	if _, err := cache.Embed(context.Background(), EmbedRequest{Texts: []string{"a"}}); err != nil {
+		t.Fatalf("Embed after recovery: %v", err)
+	}
+	if len(inner.calls) != 2 {
+		t.Fatalf("provider calls = %d; want 2", len(inner.calls))
+	}
+}
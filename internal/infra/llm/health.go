@@ -0,0 +1,96 @@
+// Package llm — cached provider health.
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultHealthInterval is how often a HealthMonitor checks its provider when
+// no interval is given.
+const DefaultHealthInterval = 30 * time.Second
+
+// healthCheckTimeout bounds each HealthCheck call of a HealthMonitor.
+const healthCheckTimeout = 5 * time.Second
+
+// HealthStatus is the outcome of the last health check of a provider.
+type HealthStatus struct {
+	Err       error
+	CheckedAt time.Time // zero until the first check completes
+}
+
+// Healthy reports whether the check passed. A provider not checked yet
+// counts as healthy.
+func (s HealthStatus) Healthy() bool {
+	return s.Err == nil
+}
+
+type healthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthMonitor calls a provider's HealthCheck on an interval and caches the
+// result, so readiness probes and request paths read it without paying the
+// provider's latency.
+type HealthMonitor struct {
+	provider healthChecker
+	interval time.Duration
+
+	mu   sync.RWMutex
+	last HealthStatus
+}
+
+// NewHealthMonitor returns a monitor of provider checking every interval once
+// started. interval <= 0 uses DefaultHealthInterval.
+func NewHealthMonitor(provider healthChecker, interval time.Duration) *HealthMonitor {
+	if interval <= 0 {
+		interval = DefaultHealthInterval
+	}
+	return &HealthMonitor{provider: provider, interval: interval}
+}
+
+// Start checks the provider right away and then every interval until ctx is
+// done.
+func (m *HealthMonitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		m.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check calls the provider's HealthCheck now and caches the result.
+func (m *HealthMonitor) Check(ctx context.Context) HealthStatus {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	status := HealthStatus{Err: m.provider.HealthCheck(ctx), CheckedAt: time.Now().UTC()}
+
+	m.mu.Lock()
+	m.last = status
+	m.mu.Unlock()
+	return status
+}
+
+// LastHealth returns the result of the latest check.
+func (m *HealthMonitor) LastHealth() HealthStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.last
+}
+
+// HealthCheck returns the cached error of the latest check, checking now only
+// if the monitor has not checked yet. It lets a HealthMonitor stand in for
+// its provider in readiness checks.
+func (m *HealthMonitor) HealthCheck(ctx context.Context) error {
+	status := m.LastHealth()
+	if status.CheckedAt.IsZero() {
+		status = m.Check(ctx)
+	}
+	return status.Err
+}
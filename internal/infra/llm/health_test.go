@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flappingProvider fails every other HealthCheck, starting healthy.
+type flappingProvider struct {
+	calls atomic.Int64
+}
+
+func (p *flappingProvider) HealthCheck(context.Context) error {
+	if p.calls.Add(1)%2 == 0 {
+		return errors.New("provider down")
+	}
+	return nil
+}
+
+func TestHealthMonitor_CachesFlappingProvider(t *testing.T) {
+	t.Parallel()
+
+	provider := &flappingProvider{}
+	monitor := NewHealthMonitor(provider, time.Hour)
+	ctx := context.Background()
+
+	if status := monitor.LastHealth(); !status.CheckedAt.IsZero() || !status.Healthy() {
+		t.Fatalf("LastHealth() before any check = %+v; want unchecked and healthy", status)
+	}
+	// HealthCheck checks once when nothing is cached, then serves the cache.
+	if err := monitor.HealthCheck(ctx); err != nil {
+		t.Fatalf("first HealthCheck() = %v; want nil", err)
+	}
+	if err := monitor.HealthCheck(ctx); err != nil || provider.calls.Load() != 1 {
+		t.Fatalf("cached HealthCheck() = %v after %d calls; want nil after 1", err, provider.calls.Load())
+	}
+
+	down := monitor.Check(ctx)
+	if down.Healthy() || down.CheckedAt.IsZero() {
+		t.Fatalf("Check() = %+v; want unhealthy with a timestamp", down)
+	}
+	if status := monitor.LastHealth(); status.Healthy() || !status.CheckedAt.Equal(down.CheckedAt) {
+		t.Fatalf("LastHealth() = %+v; want the failed check", status)
+	}
+	if err := monitor.HealthCheck(ctx); err == nil {
+		t.Fatal("HealthCheck() after failed check = nil; want cached error")
+	}
+	if !monitor.Check(ctx).Healthy() || !monitor.LastHealth().Healthy() {
+		t.Fatal("provider recovered but monitor still reports unhealthy")
+	}
+}
+
+func TestHealthMonitor_StartChecksOnInterval(t *testing.T) {
+	t.Parallel()
+
+	provider := &flappingProvider{}
+	monitor := NewHealthMonitor(provider, 10*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		monitor.Start(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for provider.calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+	if provider.calls.Load() < 3 {
+		t.Fatalf("Start() made %d checks; want at least 3", provider.calls.Load())
+	}
+	if monitor.LastHealth().CheckedAt.IsZero() {
+		t.Fatal("LastHealth() not recorded by Start()")
+	}
+}
+
+func TestNewHealthMonitor_DefaultInterval(t *testing.T) {
+	t.Parallel()
+
+	if got := NewHealthMonitor(&flappingProvider{}, 0).interval; got != DefaultHealthInterval {
+		t.Fatalf("interval = %v; want %v", got, DefaultHealthInterval)
+	}
+}
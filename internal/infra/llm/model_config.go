@@ -0,0 +1,102 @@
+// Package llm — per-workspace model configuration store.
+package llm
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrModelConfigNotFound is returned when a workspace has no model config.
+var ErrModelConfigNotFound = errors.New("workspace model config not found")
+
+// ErrInvalidModelConfig is returned by Upsert for an unknown provider or a
+// negative temperature ceiling.
+var ErrInvalidModelConfig = errors.New("invalid workspace model config")
+
+// ModelConfig is the model/provider selection of one workspace. Empty fields
+// fall back to the global configuration; a nil TemperatureCeiling means
+// requests keep the temperature they ask for.
+type ModelConfig struct {
+	WorkspaceID        string
+	Provider           string
+	ChatModel          string
+	EmbedModel         string
+	TemperatureCeiling *float64
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// ModelConfigService persists ModelConfig rows in workspace_model_config.
+type ModelConfigService struct {
+	db *sql.DB
+}
+
+// NewModelConfigService creates a ModelConfigService.
+func NewModelConfigService(db *sql.DB) *ModelConfigService {
+	return &ModelConfigService{db: db}
+}
+
+// Get returns the workspace's config or ErrModelConfigNotFound.
+func (s *ModelConfigService) Get(ctx context.Context, workspaceID string) (*ModelConfig, error) {
+	var cfg ModelConfig
+	var ceiling sql.NullFloat64
+	var createdAt, updatedAt string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT workspace_id, provider, chat_model, embed_model, temperature_ceiling, created_at, updated_at
+		FROM workspace_model_config
+		WHERE workspace_id = ?
+	`, workspaceID).Scan(&cfg.WorkspaceID, &cfg.Provider, &cfg.ChatModel, &cfg.EmbedModel, &ceiling, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrModelConfigNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get workspace model config: %w", err)
+	}
+	if ceiling.Valid {
+		cfg.TemperatureCeiling = &ceiling.Float64
+	}
+	cfg.CreatedAt, _ = time.Parse(time.RFC3339Nano, createdAt)
+	cfg.UpdatedAt, _ = time.Parse(time.RFC3339Nano, updatedAt)
+	return &cfg, nil
+}
+
+// Upsert creates or replaces the workspace's config and returns it.
+func (s *ModelConfigService) Upsert(ctx context.Context, cfg ModelConfig) (*ModelConfig, error) {
+	switch cfg.Provider {
+	case "", providerOllama, providerOpenAICompat:
+	default:
+		return nil, fmt.Errorf("%w: provider %q is not supported", ErrInvalidModelConfig, cfg.Provider)
+	}
+	if cfg.TemperatureCeiling != nil && *cfg.TemperatureCeiling < 0 {
+		return nil, fmt.Errorf("%w: temperature ceiling must not be negative", ErrInvalidModelConfig)
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO workspace_model_config (
+			workspace_id, provider, chat_model, embed_model, temperature_ceiling, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(workspace_id) DO UPDATE SET
+			provider = excluded.provider,
+			chat_model = excluded.chat_model,
+			embed_model = excluded.embed_model,
+			temperature_ceiling = excluded.temperature_ceiling,
+			updated_at = excluded.updated_at
+	`, cfg.WorkspaceID, cfg.Provider, cfg.ChatModel, cfg.EmbedModel, cfg.TemperatureCeiling, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("upsert workspace model config: %w", err)
+	}
+	return s.Get(ctx, cfg.WorkspaceID)
+}
+
+// Delete removes the workspace's config so it falls back to the default
+// provider. Deleting a missing config is not an error.
+func (s *ModelConfigService) Delete(ctx context.Context, workspaceID string) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM workspace_model_config WHERE workspace_id = ?`, workspaceID); err != nil {
+		return fmt.Errorf("delete workspace model config: %w", err)
+	}
+	return nil
+}
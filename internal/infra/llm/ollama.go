@@ -12,14 +12,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 const (
 	mimeJSON          = "application/json"
 	headerContentType = "Content-Type"
+
+	ollamaComponent       = "llm.ollama"
+	openAICompatComponent = "llm.openai_compat"
 )
 
 // OllamaProvider implements LLMProvider against a running Ollama instance (Task 2.3).
@@ -220,7 +225,9 @@ func (p *OllamaProvider) doPost(ctx context.Context, path string, body []byte) (
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		errBody, _ := io.ReadAll(resp.Body)
 		resp.Body.Close() //nolint:errcheck
-		log.Printf("[ollama] doPost %s: status %d body=%s", path, resp.StatusCode, string(errBody))
+		logging.FromContext(ctx).Warn("provider request failed",
+			slog.String(logging.KeyComponent, ollamaComponent),
+			slog.String("path", path), slog.Int("status", resp.StatusCode), slog.String("body", string(errBody)))
 		return nil, fmt.Errorf("ollama post %s: status %d", path, resp.StatusCode)
 	}
 	return resp.Body, nil
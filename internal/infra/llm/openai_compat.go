@@ -13,9 +13,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 const headerAuthorization = "Authorization"
@@ -205,6 +207,9 @@ func ensureSuccessStatus(resp *http.Response, method, path string) error {
 
 	errBody, _ := io.ReadAll(resp.Body)
 	resp.Body.Close() //nolint:errcheck
-	log.Printf("[openai-compat] %s %s: status %d body=%s", method, path, resp.StatusCode, string(errBody))
+	logging.FromContext(resp.Request.Context()).Warn("provider request failed",
+		slog.String(logging.KeyComponent, openAICompatComponent),
+		slog.String("method", method), slog.String("path", path),
+		slog.Int("status", resp.StatusCode), slog.String("body", string(errBody)))
 	return fmt.Errorf("openai-compat %s %s: status %d", method, path, resp.StatusCode)
 }
@@ -4,12 +4,16 @@
 package llm
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
 )
 
 // ============================================================================
@@ -72,6 +76,35 @@ func TestOpenAICompatProvider_ChatCompletion_ServerError_ReturnsError(t *testing
 	}
 }
 
+func TestOpenAICompatProvider_ChatCompletion_ServerError_LogsToContextLogger(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream exploded", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, slog.LevelInfo, logging.FormatJSON)
+	if err != nil {
+		t.Fatalf("logging.New: %v", err)
+	}
+	p := NewOpenAICompatProvider(srv.URL, "test-key", "llama3-8b-8192")
+	if _, err = p.ChatCompletion(logging.WithLogger(context.Background(), logger), ChatRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+	}); err == nil {
+		t.Fatal("expected error for 502 response, got nil")
+	}
+
+	var record map[string]any
+	if err = json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("log line is not JSON: %v (%q)", err, buf.String())
+	}
+	if record[logging.KeyComponent] != openAICompatComponent || record["status"] != float64(http.StatusBadGateway) {
+		t.Fatalf("log record = %v; want component %q and status 502", record, openAICompatComponent)
+	}
+}
+
 func TestOpenAICompatProvider_ChatCompletion_EmptyChoices_ReturnsError(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,124 @@
+// Package llm — per-workspace provider resolution.
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/config"
+)
+
+// ProviderFactory resolves the LLMProvider of a workspace from its
+// ModelConfig. Workspaces without a config, and lookups that fail, get the
+// default chat provider, so a broken config never takes a tenant offline.
+type ProviderFactory struct {
+	cfg          config.Config
+	configs      *ModelConfigService
+	defaultChat  LLMProvider
+	defaultEmbed LLMProvider
+
+	mu    sync.Mutex
+	cache map[string]cachedWorkspaceProvider
+}
+
+type cachedWorkspaceProvider struct {
+	updatedAt time.Time
+	provider  LLMProvider
+}
+
+// NewProviderFactory creates a ProviderFactory. cfg supplies base URLs, API
+// keys and the models a workspace config leaves empty; defaultChat and
+// defaultEmbed are the global providers built from the same cfg.
+func NewProviderFactory(cfg config.Config, configs *ModelConfigService, defaultChat, defaultEmbed LLMProvider) *ProviderFactory {
+	return &ProviderFactory{
+		cfg:          cfg,
+		configs:      configs,
+		defaultChat:  defaultChat,
+		defaultEmbed: defaultEmbed,
+		cache:        make(map[string]cachedWorkspaceProvider),
+	}
+}
+
+// ForWorkspace returns the provider configured for workspaceID. Built
+// providers are reused until the workspace config changes.
+func (f *ProviderFactory) ForWorkspace(ctx context.Context, workspaceID string) LLMProvider {
+	if f.configs == nil || workspaceID == "" {
+		return f.defaultChat
+	}
+	mc, err := f.configs.Get(ctx, workspaceID)
+	if err != nil {
+		return f.defaultChat
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if cached, ok := f.cache[workspaceID]; ok && cached.updatedAt.Equal(mc.UpdatedAt) {
+		return cached.provider
+	}
+	p := f.build(mc)
+	f.cache[workspaceID] = cachedWorkspaceProvider{updatedAt: mc.UpdatedAt, provider: p}
+	return p
+}
+
+func (f *ProviderFactory) build(mc *ModelConfig) LLMProvider {
+	ollamaChatModel := firstNonEmptyModel(mc.ChatModel, f.cfg.OllamaChatModel)
+
+	var chat LLMProvider
+	var chatModel string
+	switch firstNonEmptyModel(mc.Provider, f.cfg.ChatProvider) {
+	case providerOpenAICompat:
+		chatModel = firstNonEmptyModel(mc.ChatModel, f.cfg.OpenAICompatModel)
+		chat = NewOpenAICompatProvider(f.cfg.OpenAICompatBaseURL, f.cfg.OpenAICompatAPIKey, chatModel)
+	default:
+		chatModel = ollamaChatModel
+		chat = NewOllamaProvider(f.cfg.OllamaBaseURL, f.cfg.OllamaModel, chatModel)
+	}
+
+	embed := f.defaultEmbed
+	if mc.EmbedModel != "" {
+		embed = NewOllamaProvider(f.cfg.OllamaBaseURL, mc.EmbedModel, ollamaChatModel)
+	}
+
+	meta := chat.ModelInfo()
+	meta.ID = chatModel
+	return &workspaceProvider{chat: chat, embed: embed, temperatureCeiling: mc.TemperatureCeiling, meta: meta}
+}
+
+// workspaceProvider combines a workspace's chat and embed providers and
+// enforces its temperature ceiling.
+type workspaceProvider struct {
+	chat               LLMProvider
+	embed              LLMProvider
+	temperatureCeiling *float64
+	meta               ModelMeta
+}
+
+func (p *workspaceProvider) ChatCompletion(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if p.temperatureCeiling != nil && float64(req.Temperature) > *p.temperatureCeiling {
+		req.Temperature = float32(*p.temperatureCeiling)
+	}
+	return p.chat.ChatCompletion(ctx, req)
+}
+
+func (p *workspaceProvider) Embed(ctx context.Context, req EmbedRequest) (*EmbedResponse, error) {
+	return p.embed.Embed(ctx, req)
+}
+
+// ModelInfo reports the workspace's chat model.
+func (p *workspaceProvider) ModelInfo() ModelMeta {
+	return p.meta
+}
+
+func (p *workspaceProvider) HealthCheck(ctx context.Context) error {
+	return p.chat.HealthCheck(ctx)
+}
+
+func firstNonEmptyModel(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/config"
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+)
+
+func TestProviderFactory_ForWorkspace(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if err = sqlite.MigrateUp(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if _, err = db.Exec(`
+		INSERT INTO workspace (id, name, slug, created_at, updated_at)
+		VALUES ('ws-a', 'A', 'a', datetime('now'), datetime('now')), ('ws-b', 'B', 'b', datetime('now'), datetime('now'))
+	`); err != nil {
+		t.Fatalf("insert workspaces: %v", err)
+	}
+
+	var gotReq openaiChatRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&gotReq)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(openaiChatResponse{ //nolint:errcheck
+			Choices: []openaiChoice{{Message: openaiMessage{Role: "assistant", Content: "ok"}, FinishReason: "stop"}},
+		})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	configs := NewModelConfigService(db)
+	defaultChat := NewOllamaProvider("http://localhost:11434", "nomic-embed-text", "llama3.2:3b")
+	factory := NewProviderFactory(config.Config{
+		ChatProvider:        providerOllama,
+		OpenAICompatBaseURL: srv.URL,
+		OpenAICompatAPIKey:  "key",
+		OpenAICompatModel:   "global-model",
+	}, configs, defaultChat, defaultChat)
+
+	if got := factory.ForWorkspace(ctx, "ws-b"); got != defaultChat {
+		t.Fatalf("unconfigured workspace got %T, want the default provider", got)
+	}
+
+	ceiling := 0.3
+	if _, err = configs.Upsert(ctx, ModelConfig{
+		WorkspaceID:        "ws-a",
+		Provider:           providerOpenAICompat,
+		ChatModel:          "tenant-model",
+		TemperatureCeiling: &ceiling,
+	}); err != nil {
+		t.Fatalf("Upsert: %v", err)
+	}
+
+	p := factory.ForWorkspace(ctx, "ws-a")
+	if info := p.ModelInfo(); info.ID != "tenant-model" || info.Provider != providerOpenAICompat {
+		t.Fatalf("ModelInfo() = %+v", info)
+	}
+	if _, err = p.ChatCompletion(ctx, ChatRequest{Messages: []Message{{Role: "user", Content: "hi"}}, Temperature: 0.9}); err != nil {
+		t.Fatalf("ChatCompletion: %v", err)
+	}
+	if gotReq.Model != "tenant-model" || gotReq.Temperature != float32(ceiling) {
+		t.Fatalf("request model=%q temperature=%v; want tenant-model at %v", gotReq.Model, gotReq.Temperature, ceiling)
+	}
+	if factory.ForWorkspace(ctx, "ws-a") != p {
+		t.Fatal("expected the provider to be reused while the config is unchanged")
+	}
+
+	if err = configs.Delete(ctx, "ws-a"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if got := factory.ForWorkspace(ctx, "ws-a"); got != defaultChat {
+		t.Fatalf("deleted config got %T, want the default provider", got)
+	}
+}
+
+func TestModelConfigService_UpsertRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	svc := NewModelConfigService(nil)
+	negative := -1.0
+	for _, cfg := range []ModelConfig{
+		{WorkspaceID: "ws", Provider: "anthropic"},
+		{WorkspaceID: "ws", TemperatureCeiling: &negative},
+	} {
+		if _, err := svc.Upsert(context.Background(), cfg); !errors.Is(err, ErrInvalidModelConfig) {
+			t.Fatalf("Upsert(%+v) error = %v; want ErrInvalidModelConfig", cfg, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package llm — token counting and prompt budget pre-checks.
+package llm
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+// ErrPromptTooLarge is returned by CheckPromptFits when the prompt plus the
+// requested completion does not fit the model's context window.
+var ErrPromptTooLarge = errors.New("prompt exceeds model context window")
+
+// Tokenizer counts the tokens a model would see for a text.
+type Tokenizer interface {
+	CountTokens(text string) int
+}
+
+// Chat formats add a few framing tokens around every message and the reply
+// (OpenAI documents 3 per message plus 3 priming the answer).
+const (
+	tokensPerMessage = 3
+	tokensPerReply   = 3
+)
+
+// CountTokens returns the number of tokens text takes for model. Models
+// without a native tokenizer use ApproxTokenizer, which tracks BPE
+// vocabularies such as cl100k within a few percent on English prose.
+func CountTokens(model, text string) int {
+	return tokenizerFor(model).CountTokens(text)
+}
+
+// CountMessageTokens returns the prompt size of msgs for model, including
+// the per-message framing of chat formats.
+func CountMessageTokens(model string, msgs []Message) int {
+	tok := tokenizerFor(model)
+	total := tokensPerReply
+	for _, msg := range msgs {
+		total += tokensPerMessage + tok.CountTokens(msg.Role) + tok.CountTokens(msg.Content)
+	}
+	return total
+}
+
+// CheckPromptFits returns ErrPromptTooLarge when the prompt of req plus its
+// MaxTokens completion exceeds contextWindow tokens. A contextWindow <= 0
+// (unknown) always fits.
+func CheckPromptFits(model string, req ChatRequest, contextWindow int) error {
+	if contextWindow <= 0 {
+		return nil
+	}
+	if req.Model != "" {
+		model = req.Model
+	}
+	prompt := CountMessageTokens(model, req.Messages)
+	if prompt+req.MaxTokens > contextWindow {
+		return fmt.Errorf("%w: %d prompt + %d completion tokens > %d", ErrPromptTooLarge, prompt, req.MaxTokens, contextWindow)
+	}
+	return nil
+}
+
+// tokenizerFor returns the tokenizer for model. No native tokenizer is
+// bundled yet, so every model shares the approximation.
+func tokenizerFor(string) Tokenizer {
+	return ApproxTokenizer{}
+}
+
+// ApproxTokenizer estimates BPE token counts without a vocabulary. It splits
+// text the way GPT-style pre-tokenizers do (words with their leading space,
+// digit runs, punctuation runs, whitespace) and prices each piece:
+//   - words: one token, plus one per further 9 letters; non-ASCII letters
+//     cost an extra half token each, CJK characters a full token each
+//   - digits: one token per 3 digits
+//   - punctuation and symbols: one token per 2 characters
+//   - whitespace: free when a single space, else one token per run
+type ApproxTokenizer struct{}
+
+// CountTokens implements Tokenizer.
+func (ApproxTokenizer) CountTokens(text string) int {
+	runes := []rune(text)
+	total := 0
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case isCJK(r):
+			total++
+			i++
+		case unicode.IsLetter(r) || unicode.IsMark(r):
+			j := i
+			ascii, other := 0, 0
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsMark(runes[j])) && !isCJK(runes[j]) {
+				if runes[j] < unicode.MaxASCII {
+					ascii++
+				} else {
+					other++
+				}
+				j++
+			}
+			total += 1 + max(ascii-1, 0)/9 + (other+1)/2
+			i = j
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			total += (j - i + 2) / 3
+			i = j
+		case unicode.IsSpace(r):
+			j := i
+			newline := false
+			for j < len(runes) && unicode.IsSpace(runes[j]) {
+				newline = newline || runes[j] == '\n'
+				j++
+			}
+			if newline || j-i > 1 {
+				total++
+			}
+			i = j
+		default:
+			j := i
+			for j < len(runes) && !unicode.IsLetter(runes[j]) && !unicode.IsDigit(runes[j]) && !unicode.IsSpace(runes[j]) {
+				j++
+			}
+			total += (j - i + 1) / 2
+			i = j
+		}
+	}
+	return total
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
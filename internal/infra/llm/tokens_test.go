@@ -0,0 +1,85 @@
+package llm
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Known counts come from the cl100k_base vocabulary (gpt-4, gpt-3.5-turbo).
+var knownTokenCounts = []struct {
+	text string
+	want int
+}{
+	{"Hello, world!", 4},
+	{"Hello world", 2},
+	{"The quick brown fox jumps over the lazy dog.", 10},
+	{"Tokenization is the process of splitting text into tokens.", 11},
+	{"I love programming in Go.", 6},
+	{"1234567890", 4},
+}
+
+func TestCountTokens_MatchesKnownCountsWithinTolerance(t *testing.T) {
+	t.Parallel()
+
+	gotTotal, wantTotal := 0, 0
+	for _, tc := range knownTokenCounts {
+		got := CountTokens("gpt-4", tc.text)
+		if diff := got - tc.want; diff < -2 || diff > 2 {
+			t.Fatalf("CountTokens(%q) = %d; want %d ± 2", tc.text, got, tc.want)
+		}
+		gotTotal += got
+		wantTotal += tc.want
+	}
+	if diff := float64(gotTotal-wantTotal) / float64(wantTotal); diff < -0.15 || diff > 0.15 {
+		t.Fatalf("total tokens = %d; want %d ± 15%%", gotTotal, wantTotal)
+	}
+}
+
+func TestApproxTokenizer_CountsPieces(t *testing.T) {
+	t.Parallel()
+
+	tok := ApproxTokenizer{}
+	for _, tc := range []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"   ", 1},
+		{"a\n\nb", 3},
+		{"internationalization", 3},
+		{"...", 2},
+		{"你好世界", 4},
+	} {
+		if got := tok.CountTokens(tc.text); got != tc.want {
+			t.Fatalf("CountTokens(%q) = %d; want %d", tc.text, got, tc.want)
+		}
+	}
+	if got := tok.CountTokens(strings.Repeat("word ", 1000)); got != 1000 {
+		t.Fatalf("CountTokens(1000 words) = %d; want 1000", got)
+	}
+}
+
+func TestCheckPromptFits(t *testing.T) {
+	t.Parallel()
+
+	req := ChatRequest{
+		Messages:  []Message{{Role: "system", Content: "Be brief."}, {Role: "user", Content: "Hello world"}},
+		MaxTokens: 100,
+	}
+	prompt := CountMessageTokens("llama3.2:3b", req.Messages)
+	// 3 reply + 2 × 3 framing + system(1) + "Be brief."(3) + user(1) + "Hello world"(2)
+	if prompt != 16 {
+		t.Fatalf("CountMessageTokens = %d; want 16", prompt)
+	}
+
+	if err := CheckPromptFits("llama3.2:3b", req, prompt+100); err != nil {
+		t.Fatalf("CheckPromptFits(exact fit) = %v", err)
+	}
+	if err := CheckPromptFits("llama3.2:3b", req, prompt+99); !errors.Is(err, ErrPromptTooLarge) {
+		t.Fatalf("CheckPromptFits(one short) = %v; want ErrPromptTooLarge", err)
+	}
+	if err := CheckPromptFits("llama3.2:3b", req, 0); err != nil {
+		t.Fatalf("CheckPromptFits(unknown window) = %v", err)
+	}
+}
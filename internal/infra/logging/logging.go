@@ -0,0 +1,84 @@
+// Package logging builds the process-wide slog.Logger and carries it through
+// contexts, so handlers and background workers log with the configured level,
+// format and a shared set of attribute keys.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Output formats accepted by New.
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Attribute keys shared by every component, so log queries do not depend on
+// which package wrote the line.
+const (
+	KeyComponent   = "component"
+	KeyError       = "error"
+	KeyWorkspaceID = "workspace_id"
+	KeyTopic       = "topic"
+	KeyRequestID   = "request_id"
+	KeyAttempt     = "attempt"
+)
+
+// ErrUnknownFormat is returned by New for a format other than json or text.
+var ErrUnknownFormat = errors.New("unknown log format")
+
+type contextKey struct{}
+
+// New returns a logger writing records of at least level to w, encoded as
+// JSON or as key=value text. An empty format means text.
+func New(w io.Writer, level slog.Level, format string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// ParseLevel parses debug, info, warn or error (case-insensitive); an empty
+// string is info.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("parse log level: %w", err)
+	}
+	return level, nil
+}
+
+// WithLogger returns a copy of ctx carrying l.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext returns the logger stored by WithLogger, or slog.Default()
+// when ctx carries none.
+func FromContext(ctx context.Context) *slog.Logger {
+	if l, ok := ctx.Value(contextKey{}).(*slog.Logger); ok && l != nil {
+		return l
+	}
+	return slog.Default()
+}
+
+// Err is the attribute every component logs errors under.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.String(KeyError, "")
+	}
+	return slog.String(KeyError, err.Error())
+}
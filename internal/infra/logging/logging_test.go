@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNew_Formats(t *testing.T) {
+	t.Parallel()
+
+	var jsonOut, textOut bytes.Buffer
+	jsonLogger, err := New(&jsonOut, slog.LevelInfo, FormatJSON)
+	if err != nil {
+		t.Fatalf("New(json) error = %v", err)
+	}
+	textLogger, err := New(&textOut, slog.LevelWarn, "")
+	if err != nil {
+		t.Fatalf("New(text) error = %v", err)
+	}
+	jsonLogger.Info("hello", slog.String(KeyComponent, "test"))
+	textLogger.Info("filtered")
+	textLogger.Warn("kept", Err(errors.New("boom")))
+
+	if !strings.HasPrefix(jsonOut.String(), "{") || !strings.Contains(jsonOut.String(), `"component":"test"`) {
+		t.Fatalf("json output = %q", jsonOut.String())
+	}
+	if strings.Contains(textOut.String(), "filtered") || !strings.Contains(textOut.String(), "error=boom") {
+		t.Fatalf("text output = %q; want only the warning with its error", textOut.String())
+	}
+	if _, err = New(&jsonOut, slog.LevelInfo, "xml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("New(xml) error = %v; want ErrUnknownFormat", err)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]slog.Level{"": slog.LevelInfo, "debug": slog.LevelDebug, "WARN": slog.LevelWarn, "error": slog.LevelError} {
+		got, err := ParseLevel(in)
+		if err != nil || got != want {
+			t.Fatalf("ParseLevel(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Fatal("ParseLevel(loud) error = nil; want an error")
+	}
+}
+
+func TestFromContext_FallsBackToDefault(t *testing.T) {
+	t.Parallel()
+
+	if FromContext(context.Background()) != slog.Default() {
+		t.Fatal("FromContext(empty) is not slog.Default()")
+	}
+	l := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	if FromContext(WithLogger(context.Background(), l)) != l {
+		t.Fatal("FromContext did not return the stored logger")
+	}
+}
@@ -0,0 +1,58 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrBackupDestinationExists is returned by Backup when destPath is already taken.
+var ErrBackupDestinationExists = errors.New("backup destination already exists")
+
+// Backup writes a consistent snapshot of db to destPath using VACUUM INTO.
+// It runs inside a single read transaction, so writers keep working and the
+// copy reflects one point in time. The destination must not exist and its
+// parent directory must. Returns the number of pages in the backup.
+func Backup(ctx context.Context, db *sql.DB, destPath string) (int64, error) {
+	if destPath == "" {
+		return 0, errors.New("sqlite.Backup: destination path is required")
+	}
+	if _, err := os.Stat(destPath); err == nil {
+		return 0, fmt.Errorf("sqlite.Backup: %w: %s", ErrBackupDestinationExists, destPath)
+	} else if !os.IsNotExist(err) {
+		return 0, fmt.Errorf("sqlite.Backup: stat %q: %w", destPath, err)
+	}
+	if dir := filepath.Dir(destPath); dir != "" {
+		if _, err := os.Stat(dir); err != nil {
+			return 0, fmt.Errorf("sqlite.Backup: parent directory %q: %w", dir, err)
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", destPath); err != nil {
+		return 0, fmt.Errorf("sqlite.Backup: vacuum into %q: %w", destPath, err)
+	}
+
+	pages, err := backupPageCount(ctx, destPath)
+	if err != nil {
+		return 0, fmt.Errorf("sqlite.Backup: %w", err)
+	}
+	return pages, nil
+}
+
+// backupPageCount opens the finished backup read-only and reads its page count.
+func backupPageCount(ctx context.Context, path string) (int64, error) {
+	dst, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return 0, fmt.Errorf("open backup %q: %w", path, err)
+	}
+	defer dst.Close()
+
+	var pages int64
+	if err = dst.QueryRowContext(ctx, "PRAGMA page_count").Scan(&pages); err != nil {
+		return 0, fmt.Errorf("read backup page count: %w", err)
+	}
+	return pages, nil
+}
@@ -0,0 +1,65 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+)
+
+func TestBackup_CopiesDataWhileOpen(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	if _, err := db.Exec("CREATE TABLE item (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := db.Exec("INSERT INTO item (name) VALUES (?)", "row"); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	dest := filepath.Join(t.TempDir(), "backup.sqlite")
+	pages, err := sqlite.Backup(context.Background(), db, dest)
+	if err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if pages <= 0 {
+		t.Errorf("Backup() pages = %d; want > 0", pages)
+	}
+
+	// The source stays writable after the backup.
+	if _, err := db.Exec("INSERT INTO item (name) VALUES ('after')"); err != nil {
+		t.Fatalf("insert after backup: %v", err)
+	}
+
+	restored, err := sql.Open("sqlite", dest)
+	if err != nil {
+		t.Fatalf("open backup: %v", err)
+	}
+	defer restored.Close()
+	var n int
+	if err := restored.QueryRow("SELECT COUNT(*) FROM item").Scan(&n); err != nil {
+		t.Fatalf("count backup rows: %v", err)
+	}
+	if n != 100 {
+		t.Errorf("backup rows = %d; want 100", n)
+	}
+}
+
+func TestBackup_DestinationExists(t *testing.T) {
+	t.Parallel()
+
+	db := mustOpenDB(t)
+	dest := filepath.Join(t.TempDir(), "backup.sqlite")
+	if _, err := sqlite.Backup(context.Background(), db, dest); err != nil {
+		t.Fatalf("first Backup() error = %v", err)
+	}
+	if _, err := sqlite.Backup(context.Background(), db, dest); !errors.Is(err, sqlite.ErrBackupDestinationExists) {
+		t.Fatalf("second Backup() error = %v; want ErrBackupDestinationExists", err)
+	}
+}
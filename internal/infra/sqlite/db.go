@@ -5,23 +5,105 @@ package sqlite
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"time"
 
 	// Register the modernc sqlite driver under the name "sqlite"
 	_ "modernc.org/sqlite"
 )
 
-// NewDB opens (or creates) a SQLite database at path and configures it for production use:
-//   - WAL journal mode (allows concurrent reads during writes)
+// JournalMode is the SQLite journal_mode applied on every connection.
+type JournalMode string
+
+const (
+	// JournalModeWAL allows concurrent readers during a write.
+	JournalModeWAL JournalMode = "WAL"
+	// JournalModeDelete is SQLite's default rollback journal.
+	JournalModeDelete JournalMode = "DELETE"
+)
+
+// Options configures the connection pool and per-connection PRAGMAs used by
+// NewDBWithOptions. Start from DefaultOptions or MemoryOptions and override.
+type Options struct {
+	MaxOpenConns int           // 0 means unlimited (database/sql semantics)
+	MaxIdleConns int           // idle connections kept in the pool
+	BusyTimeout  time.Duration // how long a writer waits on a locked database
+	JournalMode  JournalMode   // JournalModeWAL or JournalModeDelete
+	ForeignKeys  bool          // PRAGMA foreign_keys
+}
+
+// DefaultOptions returns the production configuration used by NewDB:
+// WAL, foreign keys on, 5s busy timeout and a 10/5 connection pool.
+func DefaultOptions() Options {
+	return Options{
+		MaxOpenConns: 10,
+		MaxIdleConns: 5,
+		BusyTimeout:  5 * time.Second,
+		JournalMode:  JournalModeWAL,
+		ForeignKeys:  true,
+	}
+}
+
+// MemoryOptions returns DefaultOptions pinned to a single connection.
+// ":memory:" databases are per-connection, so a pool larger than one would
+// hand out empty databases.
+func MemoryOptions() Options {
+	opts := DefaultOptions()
+	opts.MaxOpenConns = 1
+	opts.MaxIdleConns = 1
+	return opts
+}
+
+func (o Options) validate() error {
+	if o.JournalMode != JournalModeWAL && o.JournalMode != JournalModeDelete {
+		return fmt.Errorf("journal mode must be %s or %s, got %q", JournalModeWAL, JournalModeDelete, o.JournalMode)
+	}
+	if o.MaxOpenConns < 0 || o.MaxIdleConns < 0 || o.BusyTimeout < 0 {
+		return errors.New("pool sizes and busy timeout must not be negative")
+	}
+	return nil
+}
+
+// dsn builds the connection string. modernc.org/sqlite runs each _pragma
+// parameter on every new connection, so pooled connections agree.
+func (o Options) dsn(path string) string {
+	foreignKeys := "OFF"
+	if o.ForeignKeys {
+		foreignKeys = "ON"
+	}
+	return path +
+		"?_pragma=journal_mode(" + string(o.JournalMode) + ")" +
+		"&_pragma=foreign_keys(" + foreignKeys + ")" +
+		"&_pragma=busy_timeout(" + strconv.FormatInt(o.BusyTimeout.Milliseconds(), 10) + ")" +
+		"&_pragma=synchronous(NORMAL)" +
+		"&_pragma=cache_size(-64000)" + // 64MB page cache (negative = KB)
+		"&_pragma=temp_store(MEMORY)" // temp tables in RAM
+}
+
+// NewDB opens (or creates) a SQLite database at path with DefaultOptions:
+//   - WAL journal mode (allows concurrent readers during writes)
 //   - Foreign key enforcement (SQLite disables FKs by default)
 //   - 5-second busy timeout (prevents SQLITE_BUSY errors under burst writes)
 //   - Synchronous=NORMAL (safe + faster than FULL for WAL mode)
 //
-// Use ":memory:" as path for in-memory databases in tests.
+// Use ":memory:" as path for in-memory databases in tests, ideally through
+// NewDBWithOptions with MemoryOptions.
 // Returns an error if the parent directory does not exist (will not create it).
 func NewDB(path string) (*sql.DB, error) {
+	return NewDBWithOptions(path, DefaultOptions())
+}
+
+// NewDBWithOptions opens (or creates) a SQLite database at path with the
+// given pool size and PRAGMAs.
+func NewDBWithOptions(path string, opts Options) (*sql.DB, error) {
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("sqlite.NewDB: %w", err)
+	}
+
 	// Task 1.2.4: Validate parent directory exists (for non-memory paths)
 	if path != ":memory:" {
 		dir := filepath.Dir(path)
@@ -30,26 +112,15 @@ func NewDB(path string) (*sql.DB, error) {
 		}
 	}
 
-	// DSN with PRAGMAs applied at connection time via query parameters.
-	// modernc.org/sqlite supports _pragma=... params in the DSN.
-	dsn := path +
-		"?_pragma=journal_mode(WAL)" +
-		"&_pragma=foreign_keys(ON)" +
-		"&_pragma=busy_timeout(5000)" +
-		"&_pragma=synchronous(NORMAL)" +
-		"&_pragma=cache_size(-64000)" + // 64MB page cache (negative = KB)
-		"&_pragma=temp_store(MEMORY)" // temp tables in RAM
-
-	db, err := sql.Open("sqlite", dsn)
+	db, err := sql.Open("sqlite", opts.dsn(path))
 	if err != nil {
 		return nil, fmt.Errorf("sqlite.NewDB: open %q: %w", path, err)
 	}
 
-	// Task 1.2.4: Connection pool configuration for SQLite WAL.
-	// WAL allows concurrent readers but serializes writers.
-	// MaxOpenConns > 1 is safe for reads; writers are serialized by SQLite itself.
-	db.SetMaxOpenConns(10)
-	db.SetMaxIdleConns(5)
+	// Task 1.2.4: WAL allows concurrent readers but serializes writers, so a
+	// pool > 1 is safe for reads; writers are serialized by SQLite itself.
+	db.SetMaxOpenConns(opts.MaxOpenConns)
+	db.SetMaxIdleConns(opts.MaxIdleConns)
 
 	// Verify the connection is alive and PRAGMAs were applied.
 	if pingErr := db.Ping(); pingErr != nil {
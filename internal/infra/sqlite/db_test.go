@@ -6,7 +6,9 @@ import (
 	"database/sql"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
 )
@@ -161,6 +163,91 @@ func TestNewDB_ConnectionPool(t *testing.T) {
 	}
 }
 
+// TestNewDBWithOptions_AppliesPragmasAndPool verifies journal mode, FK
+// enforcement, busy timeout and pool size come from Options.
+func TestNewDBWithOptions_AppliesPragmasAndPool(t *testing.T) {
+	t.Parallel()
+
+	opts := sqlite.DefaultOptions()
+	opts.JournalMode = sqlite.JournalModeDelete
+	opts.ForeignKeys = false
+	opts.BusyTimeout = 1500 * time.Millisecond
+	opts.MaxOpenConns = 3
+
+	db, err := sqlite.NewDBWithOptions(tempDBPath(t), opts)
+	if err != nil {
+		t.Fatalf("NewDBWithOptions error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	var mode string
+	var fk, timeout int
+	if err := db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("PRAGMA journal_mode: %v", err)
+	}
+	if err := db.QueryRow("PRAGMA foreign_keys").Scan(&fk); err != nil {
+		t.Fatalf("PRAGMA foreign_keys: %v", err)
+	}
+	if err := db.QueryRow("PRAGMA busy_timeout").Scan(&timeout); err != nil {
+		t.Fatalf("PRAGMA busy_timeout: %v", err)
+	}
+	if mode != "delete" || fk != 0 || timeout != 1500 {
+		t.Errorf("journal_mode=%q foreign_keys=%d busy_timeout=%d; want delete, 0, 1500", mode, fk, timeout)
+	}
+	if got := db.Stats().MaxOpenConnections; got != 3 {
+		t.Errorf("MaxOpenConnections = %d; want 3", got)
+	}
+}
+
+// TestNewDBWithOptions_MemorySharesOneConnection verifies MemoryOptions keeps
+// every query on the same in-memory database.
+func TestNewDBWithOptions_MemorySharesOneConnection(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite.NewDBWithOptions(":memory:", sqlite.MemoryOptions())
+	if err != nil {
+		t.Fatalf("NewDBWithOptions error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec("CREATE TABLE t (id INTEGER)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := db.Exec("INSERT INTO t (id) VALUES (1)"); err != nil {
+				t.Errorf("insert: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM t").Scan(&n); err != nil || n != 4 {
+		t.Errorf("count = %d, err = %v; want 4 rows on one database", n, err)
+	}
+}
+
+// TestNewDBWithOptions_InvalidOptions verifies bad options are rejected before opening.
+func TestNewDBWithOptions_InvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := sqlite.DefaultOptions()
+	opts.JournalMode = "TRUNCATE"
+	if _, err := sqlite.NewDBWithOptions(":memory:", opts); err == nil {
+		t.Error("expected error for unsupported journal mode")
+	}
+
+	opts = sqlite.DefaultOptions()
+	opts.MaxOpenConns = -1
+	if _, err := sqlite.NewDBWithOptions(":memory:", opts); err == nil {
+		t.Error("expected error for negative pool size")
+	}
+}
+
 // --- helpers ---
 
 // mustOpenDB opens a temp SQLite DB, registers cleanup, and fails the test on error.
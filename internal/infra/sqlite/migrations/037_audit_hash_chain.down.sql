@@ -0,0 +1,5 @@
+-- Migration 037 rollback: Remove audit_event hash chain columns
+
+DROP INDEX IF EXISTS idx_audit_workspace_hash;
+ALTER TABLE audit_event DROP COLUMN hash;
+ALTER TABLE audit_event DROP COLUMN prev_hash;
@@ -0,0 +1,10 @@
+-- Migration 037: Tamper-evident hash chain for audit_event
+-- Related to: FR-070
+-- hash = sha256(prev_hash || canonical(event)), chained per workspace in
+-- insertion (rowid) order. Rows written before this migration keep empty
+-- hashes and are skipped by chain verification.
+
+ALTER TABLE audit_event ADD COLUMN prev_hash TEXT NOT NULL DEFAULT '';
+ALTER TABLE audit_event ADD COLUMN hash TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_audit_workspace_hash ON audit_event(workspace_id, hash);
@@ -0,0 +1,12 @@
+-- Migration 038 rollback: Restore unconditional append-only delete trigger
+
+DROP TRIGGER IF EXISTS trg_audit_event_no_delete;
+
+CREATE TRIGGER trg_audit_event_no_delete
+BEFORE DELETE ON audit_event
+BEGIN
+    SELECT RAISE(ABORT, 'audit_event is append-only');
+END;
+
+DROP TABLE IF EXISTS audit_chain_anchor;
+DROP TABLE IF EXISTS audit_purge_session;
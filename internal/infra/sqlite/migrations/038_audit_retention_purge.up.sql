@@ -0,0 +1,27 @@
+-- Migration 038: Audit retention purge with chain re-anchoring
+-- Related to: FR-070
+-- audit_event stays append-only for normal writes. AuditService.Purge opens a
+-- per-workspace purge session inside its transaction, which is the only way to
+-- get past trg_audit_event_no_delete. audit_chain_anchor keeps the hash of the
+-- last purged chained event so the remaining chain still verifies.
+
+CREATE TABLE IF NOT EXISTS audit_purge_session (
+    workspace_id TEXT NOT NULL PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS audit_chain_anchor (
+    workspace_id TEXT     NOT NULL PRIMARY KEY REFERENCES workspace(id) ON DELETE CASCADE,
+    anchor_hash  TEXT     NOT NULL,
+    updated_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS trg_audit_event_no_delete;
+
+CREATE TRIGGER trg_audit_event_no_delete
+BEFORE DELETE ON audit_event
+WHEN NOT EXISTS (
+    SELECT 1 FROM audit_purge_session WHERE workspace_id = OLD.workspace_id
+)
+BEGIN
+    SELECT RAISE(ABORT, 'audit_event is append-only');
+END;
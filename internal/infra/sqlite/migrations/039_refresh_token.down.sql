@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_refresh_token_expires;
+DROP INDEX IF EXISTS idx_refresh_token_user;
+DROP TABLE IF EXISTS refresh_token;
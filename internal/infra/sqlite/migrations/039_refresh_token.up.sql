@@ -0,0 +1,18 @@
+-- Migration 039: Rotating refresh tokens
+-- Related to: FR-060
+-- Only the SHA-256 of each refresh token is stored. A token is single use:
+-- refreshing revokes it and records the token that replaced it.
+
+CREATE TABLE IF NOT EXISTS refresh_token (
+    id           TEXT     NOT NULL PRIMARY KEY,
+    workspace_id TEXT     NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    user_id      TEXT     NOT NULL REFERENCES user_account(id) ON DELETE CASCADE,
+    token_hash   TEXT     NOT NULL UNIQUE,
+    expires_at   DATETIME NOT NULL,
+    revoked      INTEGER  NOT NULL DEFAULT 0 CHECK(revoked IN (0, 1)),
+    replaced_by  TEXT,
+    created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_token_user ON refresh_token(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_expires ON refresh_token(expires_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_revoked_token_expires;
+DROP TABLE IF EXISTS revoked_token;
@@ -0,0 +1,14 @@
+-- Migration 040: Access token revocation list
+-- Related to: FR-060
+-- Stores the jti of logged-out access JWTs until they would have expired.
+-- Rows past expires_at are useless (the JWT is rejected anyway) and are purged.
+
+CREATE TABLE IF NOT EXISTS revoked_token (
+    jti          TEXT     NOT NULL PRIMARY KEY,
+    workspace_id TEXT     NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    user_id      TEXT     NOT NULL,
+    expires_at   DATETIME NOT NULL,
+    revoked_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_token_expires ON revoked_token(expires_at);
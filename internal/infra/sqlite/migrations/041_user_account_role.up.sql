@@ -0,0 +1,9 @@
+-- Migration 041: Coarse RBAC role on user_account
+-- The role is copied into the JWT and checked by handlers.RequireRole.
+-- New users default to least privilege; existing users keep the full access
+-- they had before roles existed.
+
+ALTER TABLE user_account ADD COLUMN role TEXT NOT NULL DEFAULT 'member'
+    CHECK (role IN ('admin', 'agent', 'member'));
+
+UPDATE user_account SET role = 'admin';
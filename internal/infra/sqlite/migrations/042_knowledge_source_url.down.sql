@@ -0,0 +1,75 @@
+-- Migration 042: revert knowledge_item source_type CHECK (drops 'url').
+-- SQLite cannot alter a CHECK constraint, so the table is rebuilt. The
+-- migration runner already wraps this file in a transaction; foreign keys
+-- from embedding_document/evidence are checked at commit.
+
+PRAGMA defer_foreign_keys = ON;
+
+CREATE TABLE knowledge_item_new (
+    id TEXT PRIMARY KEY,
+    workspace_id TEXT NOT NULL,
+    source_type TEXT NOT NULL CHECK(source_type IN ('email', 'document', 'kb_article', 'api', 'note', 'call', 'case', 'ticket', 'other')),
+    title TEXT NOT NULL,
+    raw_content TEXT NOT NULL,
+    normalized_content TEXT,
+    entity_type TEXT,
+    entity_id TEXT,
+    metadata TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at DATETIME,
+    source_system TEXT,
+    source_object_id TEXT,
+    refresh_strategy TEXT,
+    delete_behavior TEXT,
+    permission_context TEXT,
+
+    FOREIGN KEY (workspace_id) REFERENCES workspace(id),
+    UNIQUE(workspace_id, entity_type, entity_id)
+);
+
+INSERT INTO knowledge_item_new (
+    id, workspace_id, source_type, title, raw_content, normalized_content,
+    entity_type, entity_id, metadata, created_at, updated_at, deleted_at,
+    source_system, source_object_id, refresh_strategy, delete_behavior, permission_context
+)
+SELECT
+    id, workspace_id,
+    CASE WHEN source_type = 'url' THEN 'document' ELSE source_type END,
+    title, raw_content, normalized_content,
+    entity_type, entity_id, metadata, created_at, updated_at, deleted_at,
+    source_system, source_object_id, refresh_strategy, delete_behavior, permission_context
+FROM knowledge_item;
+
+DROP TABLE knowledge_item;
+ALTER TABLE knowledge_item_new RENAME TO knowledge_item;
+
+CREATE INDEX idx_knowledge_workspace ON knowledge_item(workspace_id);
+CREATE INDEX idx_knowledge_entity ON knowledge_item(entity_type, entity_id);
+CREATE INDEX idx_knowledge_created ON knowledge_item(created_at);
+CREATE INDEX idx_knowledge_deleted ON knowledge_item(deleted_at);
+CREATE INDEX idx_knowledge_source ON knowledge_item(workspace_id, source_type);
+CREATE INDEX idx_knowledge_source_object
+    ON knowledge_item(workspace_id, source_system, source_object_id);
+
+-- FTS5 sync triggers (see 012) are dropped with the old table.
+CREATE TRIGGER knowledge_item_ai
+AFTER INSERT ON knowledge_item
+BEGIN
+    INSERT INTO knowledge_item_fts (id, workspace_id, title, normalized_content)
+    VALUES (new.id, new.workspace_id, new.title, COALESCE(new.normalized_content, new.raw_content));
+END;
+
+CREATE TRIGGER knowledge_item_au
+AFTER UPDATE ON knowledge_item
+BEGIN
+    DELETE FROM knowledge_item_fts WHERE id = old.id;
+    INSERT INTO knowledge_item_fts (id, workspace_id, title, normalized_content)
+    VALUES (new.id, new.workspace_id, new.title, COALESCE(new.normalized_content, new.raw_content));
+END;
+
+CREATE TRIGGER knowledge_item_ad
+AFTER DELETE ON knowledge_item
+BEGIN
+    DELETE FROM knowledge_item_fts WHERE id = old.id;
+END;
@@ -0,0 +1 @@
+ALTER TABLE embedding_document DROP COLUMN deleted_at;
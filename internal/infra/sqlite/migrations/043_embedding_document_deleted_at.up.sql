@@ -0,0 +1,5 @@
+-- Migration 043: soft-delete marker for embedding_document chunks.
+-- Set by IngestService.Delete together with knowledge_item.deleted_at; the
+-- chunk's vec_embedding row is removed at the same time.
+
+ALTER TABLE embedding_document ADD COLUMN deleted_at DATETIME;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_case_status_history_case;
+DROP TABLE IF EXISTS case_status_history;
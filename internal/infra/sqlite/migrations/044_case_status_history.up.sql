@@ -0,0 +1,17 @@
+-- Migration 044: Case status history
+-- Append-only log of case_ticket status transitions that need an explicit
+-- record, starting with reopens of resolved/closed cases.
+
+CREATE TABLE IF NOT EXISTS case_status_history (
+    id           TEXT NOT NULL PRIMARY KEY,                -- UUID v7
+    workspace_id TEXT NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    case_id      TEXT NOT NULL REFERENCES case_ticket(id) ON DELETE CASCADE,
+    from_status  TEXT NOT NULL,
+    to_status    TEXT NOT NULL,
+    reason       TEXT,                                     -- Optional free-text reason
+    actor_id     TEXT,                                     -- User who triggered the change
+    created_at   TEXT NOT NULL                             -- ISO 8601 UTC
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_status_history_case
+    ON case_status_history (workspace_id, case_id, created_at);
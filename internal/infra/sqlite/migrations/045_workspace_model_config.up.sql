@@ -0,0 +1,16 @@
+-- Migration 045: Per-workspace LLM model configuration
+-- Read by llm.ProviderFactory; workspaces without a row use the global
+-- provider from config. Empty model columns fall back to the global models.
+
+CREATE TABLE IF NOT EXISTS workspace_model_config (
+    workspace_id        TEXT NOT NULL PRIMARY KEY REFERENCES workspace(id) ON DELETE CASCADE,
+    provider            TEXT NOT NULL DEFAULT ''
+                        CHECK (provider IN ('', 'ollama', 'openai-compat')),
+                                                           -- '' = global CHAT_PROVIDER
+    chat_model          TEXT NOT NULL DEFAULT '',
+    embed_model         TEXT NOT NULL DEFAULT '',
+    temperature_ceiling REAL CHECK (temperature_ceiling IS NULL OR temperature_ceiling >= 0),
+                                                           -- NULL = no ceiling
+    created_at          TEXT NOT NULL,                     -- ISO 8601 UTC
+    updated_at          TEXT NOT NULL
+);
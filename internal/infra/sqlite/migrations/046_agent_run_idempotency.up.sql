@@ -0,0 +1,13 @@
+-- Migration 046: Idempotency keys for agent trigger endpoints
+-- Maps a client Idempotency-Key to the run it created, so a retried trigger
+-- returns the original run. run_id is NULL while the first request is still
+-- running; rows older than agent.IdempotencyKeyTTL are replaced on reuse.
+
+CREATE TABLE IF NOT EXISTS agent_run_idempotency (
+    workspace_id        TEXT NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    idempotency_key     TEXT NOT NULL,
+    request_fingerprint TEXT NOT NULL,                     -- sha256 of endpoint + body
+    run_id              TEXT REFERENCES agent_run(id) ON DELETE CASCADE,
+    created_at          TEXT NOT NULL,                     -- ISO 8601 UTC
+    PRIMARY KEY (workspace_id, idempotency_key)
+);
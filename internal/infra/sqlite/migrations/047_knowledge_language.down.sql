@@ -0,0 +1,19 @@
+-- Migration 047 down: restore the plain unicode61 FTS table.
+-- SQLite cannot drop a column referenced by an index, so the index goes first.
+
+DROP INDEX IF EXISTS idx_knowledge_language;
+ALTER TABLE knowledge_item DROP COLUMN language;
+
+DROP TABLE knowledge_item_fts;
+
+CREATE VIRTUAL TABLE knowledge_item_fts USING fts5(
+    id UNINDEXED,
+    workspace_id UNINDEXED,
+    title,
+    normalized_content,
+    tokenize = 'unicode61'
+);
+
+INSERT INTO knowledge_item_fts (id, workspace_id, title, normalized_content)
+SELECT id, workspace_id, title, COALESCE(normalized_content, raw_content)
+FROM knowledge_item;
@@ -0,0 +1,24 @@
+-- Migration 047: detected language on knowledge_item + accent-folding FTS.
+-- language holds the ISO 639-1 code detected at ingest ('es', 'en'); NULL
+-- means undetected. The FTS table is rebuilt with remove_diacritics so
+-- Spanish queries match with or without accents ("facturacion" finds
+-- "facturación"). The knowledge_item_* triggers (see 042) insert by table
+-- name and keep working against the new table.
+
+ALTER TABLE knowledge_item ADD COLUMN language TEXT;
+
+CREATE INDEX idx_knowledge_language ON knowledge_item(workspace_id, language);
+
+DROP TABLE knowledge_item_fts;
+
+CREATE VIRTUAL TABLE knowledge_item_fts USING fts5(
+    id UNINDEXED,
+    workspace_id UNINDEXED,
+    title,
+    normalized_content,
+    tokenize = 'unicode61 remove_diacritics 2'
+);
+
+INSERT INTO knowledge_item_fts (id, workspace_id, title, normalized_content)
+SELECT id, workspace_id, title, COALESCE(normalized_content, raw_content)
+FROM knowledge_item;
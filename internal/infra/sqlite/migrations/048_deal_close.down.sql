@@ -0,0 +1,6 @@
+-- Migration 048 down: drop deal close columns.
+-- SQLite cannot drop a column referenced by an index, so the index goes first.
+
+DROP INDEX IF EXISTS idx_deal_closed;
+ALTER TABLE deal DROP COLUMN close_reason;
+ALTER TABLE deal DROP COLUMN closed_at;
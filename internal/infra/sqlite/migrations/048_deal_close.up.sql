@@ -0,0 +1,9 @@
+-- Migration 048: Structured deal close (won / lost).
+-- closed_at is the RFC3339 time a deal left 'open'; close_reason records why
+-- it was won or lost. Both stay NULL for open deals and for deals closed
+-- before this migration.
+
+ALTER TABLE deal ADD COLUMN closed_at TEXT;
+ALTER TABLE deal ADD COLUMN close_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_deal_closed ON deal (workspace_id, status, closed_at);
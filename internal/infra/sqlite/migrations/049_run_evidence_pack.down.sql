@@ -0,0 +1,4 @@
+-- Migration 049 down: drop stored run evidence packs.
+
+DROP INDEX IF EXISTS idx_run_evidence_pack_run;
+DROP TABLE IF EXISTS run_evidence_pack;
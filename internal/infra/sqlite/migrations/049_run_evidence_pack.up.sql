@@ -0,0 +1,17 @@
+-- Migration 049: Evidence packs stored per agent run.
+-- Keeps the full pack an agent was given (sources, scores, confidence,
+-- warnings) as JSON so auditors can review exactly what evidence a run saw.
+-- A run that builds several packs keeps one row per pack.
+
+CREATE TABLE IF NOT EXISTS run_evidence_pack (
+    id           TEXT NOT NULL PRIMARY KEY,          -- UUID v7
+    workspace_id TEXT NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    run_id       TEXT NOT NULL REFERENCES agent_run(id) ON DELETE CASCADE,
+    query        TEXT NOT NULL,
+    confidence   TEXT NOT NULL,
+    pack         TEXT NOT NULL,                      -- JSON-encoded knowledge.EvidencePack
+    created_at   TEXT NOT NULL                       -- RFC3339 UTC
+);
+
+CREATE INDEX IF NOT EXISTS idx_run_evidence_pack_run
+    ON run_evidence_pack (workspace_id, run_id, created_at);
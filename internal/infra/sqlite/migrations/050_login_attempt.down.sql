@@ -0,0 +1,3 @@
+-- Migration 050 down: drop failed login attempt tracking.
+
+DROP TABLE IF EXISTS login_attempt;
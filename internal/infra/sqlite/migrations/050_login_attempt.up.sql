@@ -0,0 +1,15 @@
+-- Migration 050: Failed login attempts for brute-force lockout.
+-- One row per (scope, subject): scope 'email' keys on the lowercased login
+-- email whether or not an account exists, scope 'ip' on the client address.
+-- failures counts failed attempts since window_started_at; locked_until is
+-- set once the threshold is reached and login is refused until it passes.
+
+CREATE TABLE IF NOT EXISTS login_attempt (
+    scope             TEXT     NOT NULL CHECK (scope IN ('email', 'ip')),
+    subject           TEXT     NOT NULL,
+    failures          INTEGER  NOT NULL DEFAULT 0,
+    window_started_at DATETIME NOT NULL,
+    locked_until      DATETIME,
+    updated_at        DATETIME NOT NULL,
+    PRIMARY KEY (scope, subject)
+);
@@ -0,0 +1,3 @@
+-- Migration 051 down: drop per-workspace storage usage.
+
+DROP TABLE IF EXISTS workspace_usage;
@@ -0,0 +1,18 @@
+-- Migration 051: Per-workspace storage usage for ingestion quotas.
+-- knowledge_items / knowledge_bytes count live (not soft-deleted) knowledge
+-- items and the byte size of their raw content. IngestService keeps them up
+-- to date on ingest and delete; limits live in workspace.settings under
+-- knowledge_quota.max_items / knowledge_quota.max_bytes.
+
+CREATE TABLE IF NOT EXISTS workspace_usage (
+    workspace_id    TEXT     PRIMARY KEY REFERENCES workspace(id) ON DELETE CASCADE,
+    knowledge_items INTEGER  NOT NULL DEFAULT 0,
+    knowledge_bytes INTEGER  NOT NULL DEFAULT 0,
+    updated_at      DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT OR IGNORE INTO workspace_usage (workspace_id, knowledge_items, knowledge_bytes)
+SELECT workspace_id, COUNT(*), COALESCE(SUM(length(CAST(raw_content AS BLOB))), 0)
+FROM knowledge_item
+WHERE deleted_at IS NULL
+GROUP BY workspace_id;
@@ -0,0 +1,3 @@
+-- Migration 052 down: drop the lead score index.
+
+DROP INDEX IF EXISTS idx_lead_score;
@@ -0,0 +1,5 @@
+-- Migration 052: index for the prioritized lead queue (LeadService.ListByScore).
+-- lead.score is computed on create/update from the workspace's lead_scoring
+-- weights; the queue reads live leads highest score first.
+
+CREATE INDEX IF NOT EXISTS idx_lead_score ON lead (workspace_id, score DESC) WHERE deleted_at IS NULL;
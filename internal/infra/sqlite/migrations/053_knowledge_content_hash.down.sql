@@ -0,0 +1,3 @@
+-- Migration 053 down: drop the knowledge_item content hash.
+
+ALTER TABLE knowledge_item DROP COLUMN content_hash;
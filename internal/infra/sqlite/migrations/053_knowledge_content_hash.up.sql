@@ -0,0 +1,7 @@
+-- Migration 053: content hash on knowledge_item for incremental re-ingest.
+-- content_hash is the hex SHA-256 IngestService computes over the fields it
+-- stores (title, raw_content, metadata, source descriptors). Re-ingesting an
+-- item with the same hash is a no-op; NULL (items ingested before this
+-- migration) always counts as changed.
+
+ALTER TABLE knowledge_item ADD COLUMN content_hash TEXT;
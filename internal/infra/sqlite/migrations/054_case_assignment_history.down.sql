@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_case_assignment_history_owner;
+DROP INDEX IF EXISTS idx_case_assignment_history_case;
+DROP TABLE IF EXISTS case_assignment_history;
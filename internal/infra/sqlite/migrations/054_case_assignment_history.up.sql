@@ -0,0 +1,21 @@
+-- Migration 054: Case assignment history
+-- Append-only log of case_ticket owner changes made through CaseService.Assign
+-- and AutoAssign. Auto rows also define the round-robin order: the active user
+-- whose latest auto assignment is oldest is picked next.
+
+CREATE TABLE IF NOT EXISTS case_assignment_history (
+    id            TEXT NOT NULL PRIMARY KEY,               -- UUID v7
+    workspace_id  TEXT NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    case_id       TEXT NOT NULL REFERENCES case_ticket(id) ON DELETE CASCADE,
+    from_owner_id TEXT,                                    -- Previous owner
+    to_owner_id   TEXT NOT NULL,                           -- New owner
+    method        TEXT NOT NULL CHECK (method IN ('manual', 'auto')),
+    actor_id      TEXT,                                    -- User who triggered the change
+    created_at    TEXT NOT NULL                            -- ISO 8601 UTC
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_assignment_history_case
+    ON case_assignment_history (workspace_id, case_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_case_assignment_history_owner
+    ON case_assignment_history (workspace_id, method, to_owner_id);
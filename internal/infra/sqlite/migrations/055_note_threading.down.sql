@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_note_parent;
+ALTER TABLE note DROP COLUMN parent_note_id;
@@ -0,0 +1,16 @@
+-- Migration 055: Note threading
+-- A note may reply to another note on the same entity. Deleting a parent
+-- promotes its replies to thread roots instead of dropping them.
+-- The send_reply tool learns parent_note_id; seeded definitions that still
+-- carry the stock schema are upgraded in place.
+
+ALTER TABLE note ADD COLUMN parent_note_id TEXT REFERENCES note(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_note_parent
+    ON note (workspace_id, parent_note_id)
+    WHERE parent_note_id IS NOT NULL;
+
+UPDATE tool_definition
+SET input_schema = '{"type":"object","required":["case_id","body"],"properties":{"case_id":{"type":"string"},"body":{"type":"string"},"is_internal":{"type":"boolean"},"parent_note_id":{"type":"string"}},"additionalProperties":false}'
+WHERE name = 'send_reply'
+  AND input_schema = '{"type":"object","required":["case_id","body"],"properties":{"case_id":{"type":"string"},"body":{"type":"string"},"is_internal":{"type":"boolean"}},"additionalProperties":false}';
@@ -0,0 +1,18 @@
+-- Migration 056: Search relevance feedback
+-- Append-only user signals on knowledge search results. Rows are keyed by the
+-- normalized query so SearchService can boost items users found helpful for
+-- the same query.
+
+CREATE TABLE IF NOT EXISTS search_feedback (
+    id                TEXT NOT NULL PRIMARY KEY,               -- UUID v7
+    workspace_id      TEXT NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    query             TEXT NOT NULL,                           -- Query as entered
+    query_normalized  TEXT NOT NULL,                           -- Lower-cased, whitespace-collapsed
+    knowledge_item_id TEXT NOT NULL REFERENCES knowledge_item(id) ON DELETE CASCADE,
+    signal            TEXT NOT NULL CHECK (signal IN ('click', 'helpful', 'not_helpful')),
+    actor_id          TEXT,                                    -- User who gave the signal
+    created_at        TEXT NOT NULL                            -- ISO 8601 UTC
+);
+
+CREATE INDEX IF NOT EXISTS idx_search_feedback_query
+    ON search_feedback (workspace_id, query_normalized, knowledge_item_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_lead_status_history_lead;
+DROP TABLE IF EXISTS lead_status_history;
@@ -0,0 +1,15 @@
+-- Migration 057: Lead status history
+-- Append-only log of lead status transitions made through LeadService.Update.
+
+CREATE TABLE IF NOT EXISTS lead_status_history (
+    id           TEXT NOT NULL PRIMARY KEY,                -- UUID v7
+    workspace_id TEXT NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    lead_id      TEXT NOT NULL REFERENCES lead(id) ON DELETE CASCADE,
+    from_status  TEXT NOT NULL,
+    to_status    TEXT NOT NULL,
+    actor_id     TEXT,                                     -- User who triggered the change
+    created_at   TEXT NOT NULL                             -- ISO 8601 UTC
+);
+
+CREATE INDEX IF NOT EXISTS idx_lead_status_history_lead
+    ON lead_status_history (workspace_id, lead_id, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS custom_field_def;
+ALTER TABLE deal DROP COLUMN custom_fields;
+ALTER TABLE account DROP COLUMN custom_fields;
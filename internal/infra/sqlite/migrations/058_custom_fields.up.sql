@@ -0,0 +1,17 @@
+-- Migration 058: Custom fields for accounts and deals
+-- custom_fields holds a JSON object of tenant-specific attributes, validated
+-- against the workspace's custom_field_def rows for the entity type.
+
+ALTER TABLE account ADD COLUMN custom_fields TEXT;
+ALTER TABLE deal ADD COLUMN custom_fields TEXT;
+
+CREATE TABLE IF NOT EXISTS custom_field_def (
+    id           TEXT    NOT NULL PRIMARY KEY,             -- UUID v7
+    workspace_id TEXT    NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    entity_type  TEXT    NOT NULL CHECK (entity_type IN ('account', 'deal')),
+    name         TEXT    NOT NULL,                         -- Key in custom_fields
+    field_type   TEXT    NOT NULL CHECK (field_type IN ('string', 'number', 'boolean', 'date')),
+    required     INTEGER NOT NULL DEFAULT 0,               -- 1 = must be present
+    created_at   TEXT    NOT NULL,                         -- ISO 8601 UTC
+    UNIQUE (workspace_id, entity_type, name)
+);
@@ -0,0 +1,5 @@
+-- Migration 059: chunk-level metadata for embedding_document.
+-- JSON object of string values (e.g. {"section":"faq_answer"}) stamped on
+-- each chunk at ingest; SearchInput.MetadataFilters matches it by equality.
+
+ALTER TABLE embedding_document ADD COLUMN metadata TEXT;
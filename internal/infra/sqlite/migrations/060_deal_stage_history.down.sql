@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_deal_stage_history_deal;
+DROP TABLE IF EXISTS deal_stage_history;
@@ -0,0 +1,23 @@
+-- Migration 060: Deal stage history
+-- Append-only log of the stages a deal entered through DealService. The
+-- DealAgingMonitor measures days in the current stage from the latest row
+-- and stamps stalled_at once it has alerted on that stay.
+
+CREATE TABLE IF NOT EXISTS deal_stage_history (
+    id            TEXT NOT NULL PRIMARY KEY,                -- UUID v7
+    workspace_id  TEXT NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    deal_id       TEXT NOT NULL REFERENCES deal(id) ON DELETE CASCADE,
+    from_stage_id TEXT,                                     -- NULL when the deal was created
+    to_stage_id   TEXT NOT NULL,
+    actor_id      TEXT,                                     -- User who triggered the change
+    stalled_at    TEXT,                                     -- ISO 8601 UTC; set when the stay was reported stalled
+    created_at    TEXT NOT NULL                             -- ISO 8601 UTC
+);
+
+CREATE INDEX IF NOT EXISTS idx_deal_stage_history_deal
+    ON deal_stage_history (workspace_id, deal_id, created_at);
+
+-- Existing deals entered their current stage no later than their last update.
+INSERT INTO deal_stage_history (id, workspace_id, deal_id, from_stage_id, to_stage_id, actor_id, stalled_at, created_at)
+SELECT 'backfill-' || id, workspace_id, id, NULL, stage_id, NULL, NULL, updated_at
+FROM deal;
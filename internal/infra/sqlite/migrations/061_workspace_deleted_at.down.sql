@@ -0,0 +1 @@
+ALTER TABLE workspace DROP COLUMN deleted_at;
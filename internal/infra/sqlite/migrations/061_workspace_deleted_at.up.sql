@@ -0,0 +1,5 @@
+-- Migration 061: soft-delete marker for workspaces.
+-- Set by WorkspaceService.Delete; a deleted workspace is hidden from the
+-- workspace API and its members no longer pass the membership check.
+
+ALTER TABLE workspace ADD COLUMN deleted_at TEXT;
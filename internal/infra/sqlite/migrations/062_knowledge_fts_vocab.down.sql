@@ -0,0 +1 @@
+DROP TABLE IF EXISTS knowledge_item_fts_vocab;
@@ -0,0 +1,6 @@
+-- Migration 062: term vocabulary of the knowledge FTS index.
+-- Read by fuzzy search to correct misspelled query terms to terms that
+-- exist in the index. fts5vocab tables are views over the FTS index; they
+-- hold no data of their own.
+
+CREATE VIRTUAL TABLE IF NOT EXISTS knowledge_item_fts_vocab USING fts5vocab(knowledge_item_fts, 'row');
@@ -0,0 +1 @@
+ALTER TABLE case_ticket DROP COLUMN sla_breached_at;
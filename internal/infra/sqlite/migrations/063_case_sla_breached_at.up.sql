@@ -0,0 +1,5 @@
+-- Migration 063: SLA breach marker for cases.
+-- Set by CaseSLAMonitor when it reports that an open case passed its
+-- sla_deadline, so each breach publishes case.sla_breached once.
+
+ALTER TABLE case_ticket ADD COLUMN sla_breached_at TEXT;
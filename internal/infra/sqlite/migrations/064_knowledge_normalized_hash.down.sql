@@ -0,0 +1,4 @@
+-- Migration 064 down: drop the knowledge_item normalized content hash.
+
+DROP INDEX IF EXISTS idx_knowledge_item_normalized_hash;
+ALTER TABLE knowledge_item DROP COLUMN normalized_hash;
@@ -0,0 +1,9 @@
+-- Migration 064: indexed hash of knowledge_item.normalized_content.
+-- IngestService looks up DedupOnIngest duplicates by this hex SHA-256
+-- instead of comparing the full normalized text. NULL for items ingested
+-- before this migration, which dedup does not match until re-ingested.
+
+ALTER TABLE knowledge_item ADD COLUMN normalized_hash TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_item_normalized_hash
+    ON knowledge_item (workspace_id, normalized_hash) WHERE deleted_at IS NULL;
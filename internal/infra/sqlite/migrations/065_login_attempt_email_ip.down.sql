@@ -0,0 +1,19 @@
+-- Migration 065 down: restore the email/ip-only scope check.
+-- email_ip counters are dropped.
+
+CREATE TABLE login_attempt_old (
+    scope             TEXT     NOT NULL CHECK (scope IN ('email', 'ip')),
+    subject           TEXT     NOT NULL,
+    failures          INTEGER  NOT NULL DEFAULT 0,
+    window_started_at DATETIME NOT NULL,
+    locked_until      DATETIME,
+    updated_at        DATETIME NOT NULL,
+    PRIMARY KEY (scope, subject)
+);
+
+INSERT INTO login_attempt_old (scope, subject, failures, window_started_at, locked_until, updated_at)
+SELECT scope, subject, failures, window_started_at, locked_until, updated_at FROM login_attempt
+WHERE scope IN ('email', 'ip');
+
+DROP TABLE login_attempt;
+ALTER TABLE login_attempt_old RENAME TO login_attempt;
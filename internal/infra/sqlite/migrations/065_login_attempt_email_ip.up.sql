@@ -0,0 +1,21 @@
+-- Migration 065: key login lockout by email and client IP.
+-- Adds scope 'email_ip' (subject "<email>|<ip>") so failed guesses from one
+-- address no longer lock the account for every address. Scope 'email' stays
+-- for logins without a known IP. SQLite cannot alter a CHECK constraint, so
+-- the table is rebuilt; counters are short-lived and carried over as-is.
+
+CREATE TABLE login_attempt_new (
+    scope             TEXT     NOT NULL CHECK (scope IN ('email', 'email_ip', 'ip')),
+    subject           TEXT     NOT NULL,
+    failures          INTEGER  NOT NULL DEFAULT 0,
+    window_started_at DATETIME NOT NULL,
+    locked_until      DATETIME,
+    updated_at        DATETIME NOT NULL,
+    PRIMARY KEY (scope, subject)
+);
+
+INSERT INTO login_attempt_new (scope, subject, failures, window_started_at, locked_until, updated_at)
+SELECT scope, subject, failures, window_started_at, locked_until, updated_at FROM login_attempt;
+
+DROP TABLE login_attempt;
+ALTER TABLE login_attempt_new RENAME TO login_attempt;
@@ -3,11 +3,11 @@
 -- IMPORTANT: All account queries filter by workspace_id for multi-tenancy isolation.
 
 -- name: CreateAccount :exec
-INSERT INTO account (id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO account (id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, custom_fields)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetAccountByID :one
-SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at, custom_fields
 FROM account
 WHERE id = ?
   AND workspace_id = ?
@@ -15,7 +15,7 @@ WHERE id = ?
 LIMIT 1;
 
 -- name: ListAccountsByWorkspace :many
-SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at, custom_fields
 FROM account
 WHERE workspace_id = ?
   AND deleted_at IS NULL
@@ -24,7 +24,7 @@ LIMIT ?
 OFFSET ?;
 
 -- name: ListAccountsByOwner :many
-SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at, custom_fields
 FROM account
 WHERE workspace_id = ?
   AND owner_id = ?
@@ -40,11 +40,20 @@ SET name = ?,
     owner_id = ?,
     address = ?,
     metadata = ?,
-    updated_at = ?
+    updated_at = ?,
+    custom_fields = COALESCE(?, custom_fields)
 WHERE id = ?
   AND workspace_id = ?
   AND deleted_at IS NULL;
 
+-- name: RestoreAccount :execrows
+UPDATE account
+SET deleted_at = NULL,
+    updated_at = ?
+WHERE id = ?
+  AND workspace_id = ?
+  AND deleted_at IS NOT NULL;
+
 -- name: SoftDeleteAccount :exec
 UPDATE account
 SET deleted_at = ?,
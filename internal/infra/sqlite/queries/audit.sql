@@ -6,8 +6,9 @@
 INSERT INTO audit_event (
     id, workspace_id, actor_id, actor_type, action,
     entity_type, entity_id, details, permissions_checked,
-    outcome, trace_id, ip_address, user_agent, created_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+    outcome, trace_id, ip_address, user_agent, created_at,
+    prev_hash, hash
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetAuditEventByID :one
 -- Retrieves a single audit event by ID
@@ -26,32 +27,44 @@ LIMIT ? OFFSET ?;
 SELECT COUNT(*) FROM audit_event WHERE workspace_id = ?;
 
 -- name: ListAuditEventsByActor :many
--- Lists audit events for a specific actor
+-- Lists audit events for a specific actor within a time window
 SELECT * FROM audit_event
-WHERE actor_id = ?
+WHERE actor_id = sqlc.arg(actor_id)
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(sqlc.arg(date_from), 1, 19)
+          AND substr(sqlc.arg(date_to), 1, 19)
 ORDER BY created_at DESC
-LIMIT ?;
+LIMIT sqlc.arg(limit);
 
 -- name: ListAuditEventsByEntity :many
--- Lists audit events for a specific entity
+-- Lists audit events for a specific entity within a time window
 SELECT * FROM audit_event
-WHERE entity_type = ? AND entity_id = ?
+WHERE entity_type = sqlc.arg(entity_type) AND entity_id = sqlc.arg(entity_id)
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(sqlc.arg(date_from), 1, 19)
+          AND substr(sqlc.arg(date_to), 1, 19)
 ORDER BY created_at DESC
-LIMIT ?;
+LIMIT sqlc.arg(limit);
 
 -- name: ListAuditEventsByOutcome :many
--- Lists audit events filtered by outcome (success/denied/error)
+-- Lists audit events filtered by outcome (success/denied/error) within a time window
 SELECT * FROM audit_event
-WHERE workspace_id = ? AND outcome = ?
+WHERE workspace_id = sqlc.arg(workspace_id) AND outcome = sqlc.arg(outcome)
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(sqlc.arg(date_from), 1, 19)
+          AND substr(sqlc.arg(date_to), 1, 19)
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: ListAuditEventsByAction :many
--- Lists audit events filtered by action type
+-- Lists audit events filtered by action type within a time window
 SELECT * FROM audit_event
-WHERE workspace_id = ? AND action = ?
+WHERE workspace_id = sqlc.arg(workspace_id) AND action = sqlc.arg(action)
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(sqlc.arg(date_from), 1, 19)
+          AND substr(sqlc.arg(date_to), 1, 19)
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?;
+LIMIT sqlc.arg(limit) OFFSET sqlc.arg(offset);
 
 -- name: ListAuditEventsByTimeRange :many
 -- Lists audit events within a time range
@@ -63,6 +76,14 @@ WHERE workspace_id = sqlc.arg(workspace_id)
 ORDER BY created_at DESC
 LIMIT sqlc.arg(lim) OFFSET sqlc.arg(off);
 
+-- name: CountAuditEventsByTimeRange :one
+-- Counts audit events for a workspace within a time range
+SELECT COUNT(*) FROM audit_event
+WHERE workspace_id = sqlc.arg(workspace_id)
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(sqlc.arg(date_from), 1, 19)
+          AND substr(sqlc.arg(date_to), 1, 19);
+
 -- name: QueryAuditEvents :many
 -- Lists audit events filtered by optional compound criteria
 SELECT * FROM audit_event
@@ -82,9 +103,105 @@ WHERE workspace_id = sqlc.arg(workspace_id)
 ORDER BY created_at DESC
 LIMIT sqlc.arg(lim) OFFSET sqlc.arg(off);
 
+-- name: ListAuditEventsForExport :many
+-- Keyset page over (created_at, id) DESC used to stream audit exports.
+-- cursor_created_at is the raw text of created_at from the previous page.
+SELECT *, CAST(created_at AS TEXT) AS cursor_created_at FROM audit_event
+WHERE workspace_id = sqlc.arg(workspace_id)
+  AND (sqlc.arg(actor_id) = '' OR actor_id = sqlc.arg(actor_id))
+  AND (sqlc.arg(entity_type) = '' OR entity_type = sqlc.arg(entity_type))
+  AND (sqlc.arg(action) = '' OR action = sqlc.arg(action))
+  AND (sqlc.arg(outcome) = '' OR outcome = sqlc.arg(outcome))
+  AND (
+      sqlc.arg(date_from) = '' OR
+      substr(created_at, 1, 19) >= substr(sqlc.arg(date_from), 1, 19)
+  )
+  AND (
+      sqlc.arg(date_to) = '' OR
+      substr(created_at, 1, 19) <= substr(sqlc.arg(date_to), 1, 19)
+  )
+  AND (
+      sqlc.arg(cursor_id) = '' OR
+      CAST(created_at AS TEXT) < sqlc.arg(cursor_created_at) OR
+      (CAST(created_at AS TEXT) = sqlc.arg(cursor_created_at) AND id < sqlc.arg(cursor_id))
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(lim);
+
 -- name: ListAuditEventsByTraceID :many
 -- F2-T1: returns all audit events for a given trace_id, ordered by creation time.
 -- Used by ActualRunTrace builder to enrich agent runs with their audit trail.
 SELECT * FROM audit_event
 WHERE trace_id = sqlc.arg(trace_id)
 ORDER BY created_at ASC;
+
+-- name: GetLatestAuditEventHash :one
+-- Task 4.7: Returns the most recent chain hash for a workspace (insertion order).
+SELECT hash FROM audit_event
+WHERE workspace_id = ? AND hash != ''
+ORDER BY rowid DESC
+LIMIT 1;
+
+-- name: ListAuditChainPage :many
+-- Task 4.7: Keyset page over hashed audit events in insertion (rowid) order
+-- used by chain verification.
+SELECT rowid AS seq, * FROM audit_event
+WHERE workspace_id = sqlc.arg(workspace_id)
+  AND hash != ''
+  AND rowid > sqlc.arg(after_seq)
+ORDER BY rowid ASC
+LIMIT sqlc.arg(lim);
+
+-- name: CountAuditEventsForPurge :one
+-- Task 4.8: Counts events a retention purge would remove. Only the oldest
+-- contiguous run (insertion order) older than the cutoff qualifies, so the
+-- remaining hash chain stays linked.
+SELECT COUNT(*) FROM audit_event
+WHERE workspace_id = sqlc.arg(workspace_id)
+  AND substr(created_at, 1, 19) < sqlc.arg(cutoff)
+  AND rowid < COALESCE((
+      SELECT MIN(rowid) FROM audit_event
+      WHERE workspace_id = sqlc.arg(workspace_id)
+        AND substr(created_at, 1, 19) >= sqlc.arg(cutoff)
+  ), 9223372036854775807);
+
+-- name: GetAuditPurgeAnchorHash :one
+-- Task 4.8: Hash of the newest chained event a purge removes; becomes the chain anchor.
+SELECT hash FROM audit_event
+WHERE workspace_id = sqlc.arg(workspace_id)
+  AND substr(created_at, 1, 19) < sqlc.arg(cutoff)
+  AND rowid < COALESCE((
+      SELECT MIN(rowid) FROM audit_event
+      WHERE workspace_id = sqlc.arg(workspace_id)
+        AND substr(created_at, 1, 19) >= sqlc.arg(cutoff)
+  ), 9223372036854775807)
+  AND hash != ''
+ORDER BY rowid DESC
+LIMIT 1;
+
+-- name: DeleteAuditEventsForPurge :execrows
+-- Task 4.8: Requires an open audit_purge_session row for the workspace.
+DELETE FROM audit_event
+WHERE workspace_id = sqlc.arg(workspace_id)
+  AND substr(created_at, 1, 19) < sqlc.arg(cutoff)
+  AND rowid < COALESCE((
+      SELECT MIN(rowid) FROM audit_event
+      WHERE workspace_id = sqlc.arg(workspace_id)
+        AND substr(created_at, 1, 19) >= sqlc.arg(cutoff)
+  ), 9223372036854775807);
+
+-- name: BeginAuditPurgeSession :exec
+INSERT INTO audit_purge_session (workspace_id) VALUES (?);
+
+-- name: EndAuditPurgeSession :exec
+DELETE FROM audit_purge_session WHERE workspace_id = ?;
+
+-- name: GetAuditChainAnchor :one
+SELECT anchor_hash FROM audit_chain_anchor WHERE workspace_id = ?;
+
+-- name: UpsertAuditChainAnchor :exec
+INSERT INTO audit_chain_anchor (workspace_id, anchor_hash, updated_at)
+VALUES (?, ?, ?)
+ON CONFLICT(workspace_id) DO UPDATE SET
+    anchor_hash = excluded.anchor_hash,
+    updated_at = excluded.updated_at;
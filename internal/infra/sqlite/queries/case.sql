@@ -105,6 +105,14 @@ WHERE id = ?
   AND workspace_id = ?
   AND deleted_at IS NULL;
 
+-- name: RestoreCase :execrows
+UPDATE case_ticket
+SET deleted_at = NULL,
+    updated_at = ?
+WHERE id = ?
+  AND workspace_id = ?
+  AND deleted_at IS NOT NULL;
+
 -- name: SoftDeleteCase :exec
 UPDATE case_ticket
 SET deleted_at = ?,
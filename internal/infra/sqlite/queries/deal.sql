@@ -2,11 +2,11 @@
 -- Task 1.5: Deal management queries
 
 -- name: CreateDeal :exec
-INSERT INTO deal (id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO deal (id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, custom_fields)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetDealByID :one
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE id = ?
   AND workspace_id = ?
@@ -14,7 +14,7 @@ WHERE id = ?
 LIMIT 1;
 
 -- name: ListDealsByWorkspace :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND deleted_at IS NULL
@@ -23,7 +23,7 @@ LIMIT ?
 OFFSET ?;
 
 -- name: ListDealsByOwner :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND owner_id = ?
@@ -31,7 +31,7 @@ WHERE workspace_id = ?
 ORDER BY created_at DESC;
 
 -- name: ListDealsByAccount :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND account_id = ?
@@ -39,7 +39,7 @@ WHERE workspace_id = ?
 ORDER BY created_at DESC;
 
 -- name: ListDealsByPipeline :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND pipeline_id = ?
@@ -47,7 +47,7 @@ WHERE workspace_id = ?
 ORDER BY stage_id, created_at DESC;
 
 -- name: ListDealsByStage :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND stage_id = ?
@@ -56,7 +56,7 @@ WHERE workspace_id = ?
 ORDER BY created_at DESC;
 
 -- name: ListDealsByStatus :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND status = ?
@@ -76,7 +76,8 @@ SET account_id = ?,
     expected_close = ?,
     status = ?,
     metadata = ?,
-    updated_at = ?
+    updated_at = ?,
+    custom_fields = COALESCE(?, custom_fields)
 WHERE id = ?
   AND workspace_id = ?
   AND deleted_at IS NULL;
@@ -13,8 +13,9 @@
 INSERT INTO knowledge_item (
     id, workspace_id, source_system, source_type, source_object_id,
     refresh_strategy, delete_behavior, permission_context, title, raw_content,
-    normalized_content, entity_type, entity_id, metadata, created_at, updated_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+    normalized_content, entity_type, entity_id, metadata, language, content_hash, normalized_hash,
+    created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetKnowledgeItemByID :one
 -- Task 2.1/2.2: Retrieve a single knowledge item (excludes soft-deleted)
@@ -80,8 +81,8 @@ WHERE workspace_id = ? AND deleted_at IS NULL;
 -- Task 2.2/2.4: Insert a chunk with pending embedding status
 INSERT INTO embedding_document (
     id, knowledge_item_id, workspace_id, chunk_index,
-    chunk_text, token_count, embedding_status, created_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+    chunk_text, token_count, embedding_status, metadata, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetEmbeddingDocumentByID :one
 -- Task 2.4: Get a single embedding document
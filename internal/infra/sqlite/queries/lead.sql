@@ -60,6 +60,14 @@ WHERE id = ?
   AND workspace_id = ?
   AND deleted_at IS NULL;
 
+-- name: RestoreLead :execrows
+UPDATE lead
+SET deleted_at = NULL,
+    updated_at = ?
+WHERE id = ?
+  AND workspace_id = ?
+  AND deleted_at IS NOT NULL;
+
 -- name: SoftDeleteLead :exec
 UPDATE lead
 SET deleted_at = ?,
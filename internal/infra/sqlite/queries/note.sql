@@ -2,18 +2,18 @@
 -- Task 1.5: Note/comment management queries
 
 -- name: CreateNote :exec
-INSERT INTO note (id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+INSERT INTO note (id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: GetNoteByID :one
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE id = ?
   AND workspace_id = ?
 LIMIT 1;
 
 -- name: ListNotesByWorkspace :many
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE workspace_id = ?
 ORDER BY created_at DESC
@@ -21,7 +21,7 @@ LIMIT ?
 OFFSET ?;
 
 -- name: ListNotesByEntity :many
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE workspace_id = ?
   AND entity_type = ?
@@ -29,7 +29,7 @@ WHERE workspace_id = ?
 ORDER BY created_at DESC;
 
 -- name: ListNotesByEntityPublic :many
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE workspace_id = ?
   AND entity_type = ?
@@ -37,8 +37,16 @@ WHERE workspace_id = ?
   AND is_internal = 0
 ORDER BY created_at DESC;
 
+-- name: ListNoteThreadByEntity :many
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
+FROM note
+WHERE workspace_id = ?
+  AND entity_type = ?
+  AND entity_id = ?
+ORDER BY created_at ASC, id ASC;
+
 -- name: ListNotesByAuthor :many
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE workspace_id = ?
   AND author_id = ?
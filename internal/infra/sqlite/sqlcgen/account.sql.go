@@ -24,22 +24,23 @@ func (q *Queries) CountAccountsByWorkspace(ctx context.Context, workspaceID stri
 
 const createAccount = `-- name: CreateAccount :exec
 
-INSERT INTO account (id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO account (id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, custom_fields)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateAccountParams struct {
-	ID          string  `db:"id" json:"id"`
-	WorkspaceID string  `db:"workspace_id" json:"workspaceId"`
-	Name        string  `db:"name" json:"name"`
-	Domain      *string `db:"domain" json:"domain"`
-	Industry    *string `db:"industry" json:"industry"`
-	SizeSegment *string `db:"size_segment" json:"sizeSegment"`
-	OwnerID     string  `db:"owner_id" json:"ownerId"`
-	Address     *string `db:"address" json:"address"`
-	Metadata    *string `db:"metadata" json:"metadata"`
-	CreatedAt   string  `db:"created_at" json:"createdAt"`
-	UpdatedAt   string  `db:"updated_at" json:"updatedAt"`
+	ID           string  `db:"id" json:"id"`
+	WorkspaceID  string  `db:"workspace_id" json:"workspaceId"`
+	Name         string  `db:"name" json:"name"`
+	Domain       *string `db:"domain" json:"domain"`
+	Industry     *string `db:"industry" json:"industry"`
+	SizeSegment  *string `db:"size_segment" json:"sizeSegment"`
+	OwnerID      string  `db:"owner_id" json:"ownerId"`
+	Address      *string `db:"address" json:"address"`
+	Metadata     *string `db:"metadata" json:"metadata"`
+	CreatedAt    string  `db:"created_at" json:"createdAt"`
+	UpdatedAt    string  `db:"updated_at" json:"updatedAt"`
+	CustomFields *string `db:"custom_fields" json:"customFields"`
 }
 
 // SQL queries for account table
@@ -58,12 +59,13 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) er
 		arg.Metadata,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.CustomFields,
 	)
 	return err
 }
 
 const getAccountByID = `-- name: GetAccountByID :one
-SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at, custom_fields
 FROM account
 WHERE id = ?
   AND workspace_id = ?
@@ -92,12 +94,13 @@ func (q *Queries) GetAccountByID(ctx context.Context, arg GetAccountByIDParams)
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.CustomFields,
 	)
 	return i, err
 }
 
 const listAccountsByOwner = `-- name: ListAccountsByOwner :many
-SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at, custom_fields
 FROM account
 WHERE workspace_id = ?
   AND owner_id = ?
@@ -132,6 +135,7 @@ func (q *Queries) ListAccountsByOwner(ctx context.Context, arg ListAccountsByOwn
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.CustomFields,
 		); err != nil {
 			return nil, err
 		}
@@ -147,7 +151,7 @@ func (q *Queries) ListAccountsByOwner(ctx context.Context, arg ListAccountsByOwn
 }
 
 const listAccountsByWorkspace = `-- name: ListAccountsByWorkspace :many
-SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, name, domain, industry, size_segment, owner_id, address, metadata, created_at, updated_at, deleted_at, custom_fields
 FROM account
 WHERE workspace_id = ?
   AND deleted_at IS NULL
@@ -184,6 +188,7 @@ func (q *Queries) ListAccountsByWorkspace(ctx context.Context, arg ListAccountsB
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.CustomFields,
 		); err != nil {
 			return nil, err
 		}
@@ -198,6 +203,29 @@ func (q *Queries) ListAccountsByWorkspace(ctx context.Context, arg ListAccountsB
 	return items, nil
 }
 
+const restoreAccount = `-- name: RestoreAccount :execrows
+UPDATE account
+SET deleted_at = NULL,
+    updated_at = ?
+WHERE id = ?
+  AND workspace_id = ?
+  AND deleted_at IS NOT NULL
+`
+
+type RestoreAccountParams struct {
+	UpdatedAt   string `db:"updated_at" json:"updatedAt"`
+	ID          string `db:"id" json:"id"`
+	WorkspaceID string `db:"workspace_id" json:"workspaceId"`
+}
+
+func (q *Queries) RestoreAccount(ctx context.Context, arg RestoreAccountParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreAccount, arg.UpdatedAt, arg.ID, arg.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const softDeleteAccount = `-- name: SoftDeleteAccount :exec
 UPDATE account
 SET deleted_at = ?,
@@ -233,23 +261,25 @@ SET name = ?,
     owner_id = ?,
     address = ?,
     metadata = ?,
-    updated_at = ?
+    updated_at = ?,
+    custom_fields = COALESCE(?, custom_fields)
 WHERE id = ?
   AND workspace_id = ?
   AND deleted_at IS NULL
 `
 
 type UpdateAccountParams struct {
-	Name        string  `db:"name" json:"name"`
-	Domain      *string `db:"domain" json:"domain"`
-	Industry    *string `db:"industry" json:"industry"`
-	SizeSegment *string `db:"size_segment" json:"sizeSegment"`
-	OwnerID     string  `db:"owner_id" json:"ownerId"`
-	Address     *string `db:"address" json:"address"`
-	Metadata    *string `db:"metadata" json:"metadata"`
-	UpdatedAt   string  `db:"updated_at" json:"updatedAt"`
-	ID          string  `db:"id" json:"id"`
-	WorkspaceID string  `db:"workspace_id" json:"workspaceId"`
+	Name         string  `db:"name" json:"name"`
+	Domain       *string `db:"domain" json:"domain"`
+	Industry     *string `db:"industry" json:"industry"`
+	SizeSegment  *string `db:"size_segment" json:"sizeSegment"`
+	OwnerID      string  `db:"owner_id" json:"ownerId"`
+	Address      *string `db:"address" json:"address"`
+	Metadata     *string `db:"metadata" json:"metadata"`
+	UpdatedAt    string  `db:"updated_at" json:"updatedAt"`
+	CustomFields *string `db:"custom_fields" json:"customFields"`
+	ID           string  `db:"id" json:"id"`
+	WorkspaceID  string  `db:"workspace_id" json:"workspaceId"`
 }
 
 func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) error {
@@ -262,6 +292,7 @@ func (q *Queries) UpdateAccount(ctx context.Context, arg UpdateAccountParams) er
 		arg.Address,
 		arg.Metadata,
 		arg.UpdatedAt,
+		arg.CustomFields,
 		arg.ID,
 		arg.WorkspaceID,
 	)
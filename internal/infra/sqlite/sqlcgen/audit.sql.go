@@ -11,6 +11,37 @@ import (
 	"time"
 )
 
+const beginAuditPurgeSession = `-- name: BeginAuditPurgeSession :exec
+INSERT INTO audit_purge_session (workspace_id) VALUES (?)
+`
+
+func (q *Queries) BeginAuditPurgeSession(ctx context.Context, workspaceID string) error {
+	_, err := q.db.ExecContext(ctx, beginAuditPurgeSession, workspaceID)
+	return err
+}
+
+const countAuditEventsByTimeRange = `-- name: CountAuditEventsByTimeRange :one
+SELECT COUNT(*) FROM audit_event
+WHERE workspace_id = ?1
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(?2, 1, 19)
+          AND substr(?3, 1, 19)
+`
+
+type CountAuditEventsByTimeRangeParams struct {
+	WorkspaceID string      `db:"workspace_id" json:"workspaceId"`
+	DateFrom    interface{} `db:"date_from" json:"dateFrom"`
+	DateTo      interface{} `db:"date_to" json:"dateTo"`
+}
+
+// Counts audit events for a workspace within a time range
+func (q *Queries) CountAuditEventsByTimeRange(ctx context.Context, arg CountAuditEventsByTimeRangeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAuditEventsByTimeRange, arg.WorkspaceID, arg.DateFrom, arg.DateTo)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countAuditEventsByWorkspace = `-- name: CountAuditEventsByWorkspace :one
 SELECT COUNT(*) FROM audit_event WHERE workspace_id = ?
 `
@@ -23,13 +54,40 @@ func (q *Queries) CountAuditEventsByWorkspace(ctx context.Context, workspaceID s
 	return count, err
 }
 
+const countAuditEventsForPurge = `-- name: CountAuditEventsForPurge :one
+SELECT COUNT(*) FROM audit_event
+WHERE workspace_id = ?1
+  AND substr(created_at, 1, 19) < ?2
+  AND rowid < COALESCE((
+      SELECT MIN(rowid) FROM audit_event
+      WHERE workspace_id = ?1
+        AND substr(created_at, 1, 19) >= ?2
+  ), 9223372036854775807)
+`
+
+type CountAuditEventsForPurgeParams struct {
+	WorkspaceID string      `db:"workspace_id" json:"workspaceId"`
+	Cutoff      interface{} `db:"cutoff" json:"cutoff"`
+}
+
+// Task 4.8: Counts events a retention purge would remove. Only the oldest
+// contiguous run (insertion order) older than the cutoff qualifies, so the
+// remaining hash chain stays linked.
+func (q *Queries) CountAuditEventsForPurge(ctx context.Context, arg CountAuditEventsForPurgeParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAuditEventsForPurge, arg.WorkspaceID, arg.Cutoff)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createAuditEvent = `-- name: CreateAuditEvent :exec
 
 INSERT INTO audit_event (
     id, workspace_id, actor_id, actor_type, action,
     entity_type, entity_id, details, permissions_checked,
-    outcome, trace_id, ip_address, user_agent, created_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    outcome, trace_id, ip_address, user_agent, created_at,
+    prev_hash, hash
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateAuditEventParams struct {
@@ -47,6 +105,8 @@ type CreateAuditEventParams struct {
 	IpAddress          *string         `db:"ip_address" json:"ipAddress"`
 	UserAgent          *string         `db:"user_agent" json:"userAgent"`
 	CreatedAt          time.Time       `db:"created_at" json:"createdAt"`
+	PrevHash           string          `db:"prev_hash" json:"prevHash"`
+	Hash               string          `db:"hash" json:"hash"`
 }
 
 // Queries for audit_event table
@@ -68,12 +128,59 @@ func (q *Queries) CreateAuditEvent(ctx context.Context, arg CreateAuditEventPara
 		arg.IpAddress,
 		arg.UserAgent,
 		arg.CreatedAt,
+		arg.PrevHash,
+		arg.Hash,
 	)
 	return err
 }
 
+const deleteAuditEventsForPurge = `-- name: DeleteAuditEventsForPurge :execrows
+DELETE FROM audit_event
+WHERE workspace_id = ?1
+  AND substr(created_at, 1, 19) < ?2
+  AND rowid < COALESCE((
+      SELECT MIN(rowid) FROM audit_event
+      WHERE workspace_id = ?1
+        AND substr(created_at, 1, 19) >= ?2
+  ), 9223372036854775807)
+`
+
+type DeleteAuditEventsForPurgeParams struct {
+	WorkspaceID string      `db:"workspace_id" json:"workspaceId"`
+	Cutoff      interface{} `db:"cutoff" json:"cutoff"`
+}
+
+// Task 4.8: Requires an open audit_purge_session row for the workspace.
+func (q *Queries) DeleteAuditEventsForPurge(ctx context.Context, arg DeleteAuditEventsForPurgeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAuditEventsForPurge, arg.WorkspaceID, arg.Cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const endAuditPurgeSession = `-- name: EndAuditPurgeSession :exec
+DELETE FROM audit_purge_session WHERE workspace_id = ?
+`
+
+func (q *Queries) EndAuditPurgeSession(ctx context.Context, workspaceID string) error {
+	_, err := q.db.ExecContext(ctx, endAuditPurgeSession, workspaceID)
+	return err
+}
+
+const getAuditChainAnchor = `-- name: GetAuditChainAnchor :one
+SELECT anchor_hash FROM audit_chain_anchor WHERE workspace_id = ?
+`
+
+func (q *Queries) GetAuditChainAnchor(ctx context.Context, workspaceID string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getAuditChainAnchor, workspaceID)
+	var anchor_hash string
+	err := row.Scan(&anchor_hash)
+	return anchor_hash, err
+}
+
 const getAuditEventByID = `-- name: GetAuditEventByID :one
-SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at FROM audit_event WHERE id = ? LIMIT 1
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event WHERE id = ? LIMIT 1
 `
 
 // Retrieves a single audit event by ID
@@ -95,31 +202,160 @@ func (q *Queries) GetAuditEventByID(ctx context.Context, id string) (AuditEvent,
 		&i.IpAddress,
 		&i.UserAgent,
 		&i.CreatedAt,
+		&i.PrevHash,
+		&i.Hash,
 	)
 	return i, err
 }
 
+const getAuditPurgeAnchorHash = `-- name: GetAuditPurgeAnchorHash :one
+SELECT hash FROM audit_event
+WHERE workspace_id = ?1
+  AND substr(created_at, 1, 19) < ?2
+  AND rowid < COALESCE((
+      SELECT MIN(rowid) FROM audit_event
+      WHERE workspace_id = ?1
+        AND substr(created_at, 1, 19) >= ?2
+  ), 9223372036854775807)
+  AND hash != ''
+ORDER BY rowid DESC
+LIMIT 1
+`
+
+type GetAuditPurgeAnchorHashParams struct {
+	WorkspaceID string      `db:"workspace_id" json:"workspaceId"`
+	Cutoff      interface{} `db:"cutoff" json:"cutoff"`
+}
+
+// Task 4.8: Hash of the newest chained event a purge removes; becomes the chain anchor.
+func (q *Queries) GetAuditPurgeAnchorHash(ctx context.Context, arg GetAuditPurgeAnchorHashParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getAuditPurgeAnchorHash, arg.WorkspaceID, arg.Cutoff)
+	var hash string
+	err := row.Scan(&hash)
+	return hash, err
+}
+
+const getLatestAuditEventHash = `-- name: GetLatestAuditEventHash :one
+SELECT hash FROM audit_event
+WHERE workspace_id = ? AND hash != ''
+ORDER BY rowid DESC
+LIMIT 1
+`
+
+// Task 4.7: Returns the most recent chain hash for a workspace (insertion order).
+func (q *Queries) GetLatestAuditEventHash(ctx context.Context, workspaceID string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLatestAuditEventHash, workspaceID)
+	var hash string
+	err := row.Scan(&hash)
+	return hash, err
+}
+
+const listAuditChainPage = `-- name: ListAuditChainPage :many
+SELECT rowid AS seq, id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event
+WHERE workspace_id = ?1
+  AND hash != ''
+  AND rowid > ?2
+ORDER BY rowid ASC
+LIMIT ?3
+`
+
+type ListAuditChainPageParams struct {
+	WorkspaceID string `db:"workspace_id" json:"workspaceId"`
+	AfterSeq    int64  `db:"after_seq" json:"afterSeq"`
+	Lim         int64  `db:"lim" json:"lim"`
+}
+
+type ListAuditChainPageRow struct {
+	Seq                int64           `db:"seq" json:"seq"`
+	ID                 string          `db:"id" json:"id"`
+	WorkspaceID        string          `db:"workspace_id" json:"workspaceId"`
+	ActorID            string          `db:"actor_id" json:"actorId"`
+	ActorType          string          `db:"actor_type" json:"actorType"`
+	Action             string          `db:"action" json:"action"`
+	EntityType         *string         `db:"entity_type" json:"entityType"`
+	EntityID           *string         `db:"entity_id" json:"entityId"`
+	Details            json.RawMessage `db:"details" json:"details"`
+	PermissionsChecked json.RawMessage `db:"permissions_checked" json:"permissionsChecked"`
+	Outcome            string          `db:"outcome" json:"outcome"`
+	TraceID            *string         `db:"trace_id" json:"traceId"`
+	IpAddress          *string         `db:"ip_address" json:"ipAddress"`
+	UserAgent          *string         `db:"user_agent" json:"userAgent"`
+	CreatedAt          time.Time       `db:"created_at" json:"createdAt"`
+	PrevHash           string          `db:"prev_hash" json:"prevHash"`
+	Hash               string          `db:"hash" json:"hash"`
+}
+
+// Task 4.7: Keyset page over hashed audit events in insertion (rowid) order
+// used by chain verification.
+func (q *Queries) ListAuditChainPage(ctx context.Context, arg ListAuditChainPageParams) ([]ListAuditChainPageRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditChainPage, arg.WorkspaceID, arg.AfterSeq, arg.Lim)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAuditChainPageRow{}
+	for rows.Next() {
+		var i ListAuditChainPageRow
+		if err := rows.Scan(
+			&i.Seq,
+			&i.ID,
+			&i.WorkspaceID,
+			&i.ActorID,
+			&i.ActorType,
+			&i.Action,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Details,
+			&i.PermissionsChecked,
+			&i.Outcome,
+			&i.TraceID,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listAuditEventsByAction = `-- name: ListAuditEventsByAction :many
-SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at FROM audit_event
-WHERE workspace_id = ? AND action = ?
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event
+WHERE workspace_id = ?1 AND action = ?2
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(?3, 1, 19)
+          AND substr(?4, 1, 19)
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+LIMIT ?6 OFFSET ?5
 `
 
 type ListAuditEventsByActionParams struct {
-	WorkspaceID string `db:"workspace_id" json:"workspaceId"`
-	Action      string `db:"action" json:"action"`
-	Limit       int64  `db:"limit" json:"limit"`
-	Offset      int64  `db:"offset" json:"offset"`
+	WorkspaceID string      `db:"workspace_id" json:"workspaceId"`
+	Action      string      `db:"action" json:"action"`
+	DateFrom    interface{} `db:"date_from" json:"dateFrom"`
+	DateTo      interface{} `db:"date_to" json:"dateTo"`
+	Offset      int64       `db:"offset" json:"offset"`
+	Limit       int64       `db:"limit" json:"limit"`
 }
 
-// Lists audit events filtered by action type
+// Lists audit events filtered by action type within a time window
 func (q *Queries) ListAuditEventsByAction(ctx context.Context, arg ListAuditEventsByActionParams) ([]AuditEvent, error) {
 	rows, err := q.db.QueryContext(ctx, listAuditEventsByAction,
 		arg.WorkspaceID,
 		arg.Action,
-		arg.Limit,
+		arg.DateFrom,
+		arg.DateTo,
 		arg.Offset,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
@@ -143,6 +379,8 @@ func (q *Queries) ListAuditEventsByAction(ctx context.Context, arg ListAuditEven
 			&i.IpAddress,
 			&i.UserAgent,
 			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
 		); err != nil {
 			return nil, err
 		}
@@ -158,20 +396,30 @@ func (q *Queries) ListAuditEventsByAction(ctx context.Context, arg ListAuditEven
 }
 
 const listAuditEventsByActor = `-- name: ListAuditEventsByActor :many
-SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at FROM audit_event
-WHERE actor_id = ?
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event
+WHERE actor_id = ?1
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(?2, 1, 19)
+          AND substr(?3, 1, 19)
 ORDER BY created_at DESC
-LIMIT ?
+LIMIT ?4
 `
 
 type ListAuditEventsByActorParams struct {
-	ActorID string `db:"actor_id" json:"actorId"`
-	Limit   int64  `db:"limit" json:"limit"`
+	ActorID  string      `db:"actor_id" json:"actorId"`
+	DateFrom interface{} `db:"date_from" json:"dateFrom"`
+	DateTo   interface{} `db:"date_to" json:"dateTo"`
+	Limit    int64       `db:"limit" json:"limit"`
 }
 
-// Lists audit events for a specific actor
+// Lists audit events for a specific actor within a time window
 func (q *Queries) ListAuditEventsByActor(ctx context.Context, arg ListAuditEventsByActorParams) ([]AuditEvent, error) {
-	rows, err := q.db.QueryContext(ctx, listAuditEventsByActor, arg.ActorID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, listAuditEventsByActor,
+		arg.ActorID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -194,6 +442,8 @@ func (q *Queries) ListAuditEventsByActor(ctx context.Context, arg ListAuditEvent
 			&i.IpAddress,
 			&i.UserAgent,
 			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
 		); err != nil {
 			return nil, err
 		}
@@ -209,21 +459,32 @@ func (q *Queries) ListAuditEventsByActor(ctx context.Context, arg ListAuditEvent
 }
 
 const listAuditEventsByEntity = `-- name: ListAuditEventsByEntity :many
-SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at FROM audit_event
-WHERE entity_type = ? AND entity_id = ?
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event
+WHERE entity_type = ?1 AND entity_id = ?2
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(?3, 1, 19)
+          AND substr(?4, 1, 19)
 ORDER BY created_at DESC
-LIMIT ?
+LIMIT ?5
 `
 
 type ListAuditEventsByEntityParams struct {
-	EntityType *string `db:"entity_type" json:"entityType"`
-	EntityID   *string `db:"entity_id" json:"entityId"`
-	Limit      int64   `db:"limit" json:"limit"`
+	EntityType *string     `db:"entity_type" json:"entityType"`
+	EntityID   *string     `db:"entity_id" json:"entityId"`
+	DateFrom   interface{} `db:"date_from" json:"dateFrom"`
+	DateTo     interface{} `db:"date_to" json:"dateTo"`
+	Limit      int64       `db:"limit" json:"limit"`
 }
 
-// Lists audit events for a specific entity
+// Lists audit events for a specific entity within a time window
 func (q *Queries) ListAuditEventsByEntity(ctx context.Context, arg ListAuditEventsByEntityParams) ([]AuditEvent, error) {
-	rows, err := q.db.QueryContext(ctx, listAuditEventsByEntity, arg.EntityType, arg.EntityID, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, listAuditEventsByEntity,
+		arg.EntityType,
+		arg.EntityID,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -246,6 +507,8 @@ func (q *Queries) ListAuditEventsByEntity(ctx context.Context, arg ListAuditEven
 			&i.IpAddress,
 			&i.UserAgent,
 			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
 		); err != nil {
 			return nil, err
 		}
@@ -261,26 +524,33 @@ func (q *Queries) ListAuditEventsByEntity(ctx context.Context, arg ListAuditEven
 }
 
 const listAuditEventsByOutcome = `-- name: ListAuditEventsByOutcome :many
-SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at FROM audit_event
-WHERE workspace_id = ? AND outcome = ?
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event
+WHERE workspace_id = ?1 AND outcome = ?2
+  AND substr(created_at, 1, 19)
+      BETWEEN substr(?3, 1, 19)
+          AND substr(?4, 1, 19)
 ORDER BY created_at DESC
-LIMIT ? OFFSET ?
+LIMIT ?6 OFFSET ?5
 `
 
 type ListAuditEventsByOutcomeParams struct {
-	WorkspaceID string `db:"workspace_id" json:"workspaceId"`
-	Outcome     string `db:"outcome" json:"outcome"`
-	Limit       int64  `db:"limit" json:"limit"`
-	Offset      int64  `db:"offset" json:"offset"`
+	WorkspaceID string      `db:"workspace_id" json:"workspaceId"`
+	Outcome     string      `db:"outcome" json:"outcome"`
+	DateFrom    interface{} `db:"date_from" json:"dateFrom"`
+	DateTo      interface{} `db:"date_to" json:"dateTo"`
+	Offset      int64       `db:"offset" json:"offset"`
+	Limit       int64       `db:"limit" json:"limit"`
 }
 
-// Lists audit events filtered by outcome (success/denied/error)
+// Lists audit events filtered by outcome (success/denied/error) within a time window
 func (q *Queries) ListAuditEventsByOutcome(ctx context.Context, arg ListAuditEventsByOutcomeParams) ([]AuditEvent, error) {
 	rows, err := q.db.QueryContext(ctx, listAuditEventsByOutcome,
 		arg.WorkspaceID,
 		arg.Outcome,
-		arg.Limit,
+		arg.DateFrom,
+		arg.DateTo,
 		arg.Offset,
+		arg.Limit,
 	)
 	if err != nil {
 		return nil, err
@@ -304,6 +574,8 @@ func (q *Queries) ListAuditEventsByOutcome(ctx context.Context, arg ListAuditEve
 			&i.IpAddress,
 			&i.UserAgent,
 			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
 		); err != nil {
 			return nil, err
 		}
@@ -319,7 +591,7 @@ func (q *Queries) ListAuditEventsByOutcome(ctx context.Context, arg ListAuditEve
 }
 
 const listAuditEventsByTimeRange = `-- name: ListAuditEventsByTimeRange :many
-SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at FROM audit_event
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event
 WHERE workspace_id = ?1
   AND substr(created_at, 1, 19)
       BETWEEN substr(?2, 1, 19)
@@ -367,6 +639,8 @@ func (q *Queries) ListAuditEventsByTimeRange(ctx context.Context, arg ListAuditE
 			&i.IpAddress,
 			&i.UserAgent,
 			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
 		); err != nil {
 			return nil, err
 		}
@@ -382,7 +656,7 @@ func (q *Queries) ListAuditEventsByTimeRange(ctx context.Context, arg ListAuditE
 }
 
 const listAuditEventsByTraceID = `-- name: ListAuditEventsByTraceID :many
-SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at FROM audit_event
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event
 WHERE trace_id = ?1
 ORDER BY created_at ASC
 `
@@ -413,6 +687,8 @@ func (q *Queries) ListAuditEventsByTraceID(ctx context.Context, traceID *string)
 			&i.IpAddress,
 			&i.UserAgent,
 			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
 		); err != nil {
 			return nil, err
 		}
@@ -428,7 +704,7 @@ func (q *Queries) ListAuditEventsByTraceID(ctx context.Context, traceID *string)
 }
 
 const listAuditEventsByWorkspace = `-- name: ListAuditEventsByWorkspace :many
-SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at FROM audit_event
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event
 WHERE workspace_id = ?
 ORDER BY created_at DESC
 LIMIT ? OFFSET ?
@@ -466,6 +742,119 @@ func (q *Queries) ListAuditEventsByWorkspace(ctx context.Context, arg ListAuditE
 			&i.IpAddress,
 			&i.UserAgent,
 			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAuditEventsForExport = `-- name: ListAuditEventsForExport :many
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash, CAST(created_at AS TEXT) AS cursor_created_at FROM audit_event
+WHERE workspace_id = ?1
+  AND (?2 = '' OR actor_id = ?2)
+  AND (?3 = '' OR entity_type = ?3)
+  AND (?4 = '' OR action = ?4)
+  AND (?5 = '' OR outcome = ?5)
+  AND (
+      ?6 = '' OR
+      substr(created_at, 1, 19) >= substr(?6, 1, 19)
+  )
+  AND (
+      ?7 = '' OR
+      substr(created_at, 1, 19) <= substr(?7, 1, 19)
+  )
+  AND (
+      ?8 = '' OR
+      CAST(created_at AS TEXT) < ?9 OR
+      (CAST(created_at AS TEXT) = ?9 AND id < ?8)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT ?10
+`
+
+type ListAuditEventsForExportParams struct {
+	WorkspaceID     string      `db:"workspace_id" json:"workspaceId"`
+	ActorID         interface{} `db:"actor_id" json:"actorId"`
+	EntityType      interface{} `db:"entity_type" json:"entityType"`
+	Action          interface{} `db:"action" json:"action"`
+	Outcome         interface{} `db:"outcome" json:"outcome"`
+	DateFrom        interface{} `db:"date_from" json:"dateFrom"`
+	DateTo          interface{} `db:"date_to" json:"dateTo"`
+	CursorID        interface{} `db:"cursor_id" json:"cursorId"`
+	CursorCreatedAt interface{} `db:"cursor_created_at" json:"cursorCreatedAt"`
+	Lim             int64       `db:"lim" json:"lim"`
+}
+
+type ListAuditEventsForExportRow struct {
+	ID                 string          `db:"id" json:"id"`
+	WorkspaceID        string          `db:"workspace_id" json:"workspaceId"`
+	ActorID            string          `db:"actor_id" json:"actorId"`
+	ActorType          string          `db:"actor_type" json:"actorType"`
+	Action             string          `db:"action" json:"action"`
+	EntityType         *string         `db:"entity_type" json:"entityType"`
+	EntityID           *string         `db:"entity_id" json:"entityId"`
+	Details            json.RawMessage `db:"details" json:"details"`
+	PermissionsChecked json.RawMessage `db:"permissions_checked" json:"permissionsChecked"`
+	Outcome            string          `db:"outcome" json:"outcome"`
+	TraceID            *string         `db:"trace_id" json:"traceId"`
+	IpAddress          *string         `db:"ip_address" json:"ipAddress"`
+	UserAgent          *string         `db:"user_agent" json:"userAgent"`
+	CreatedAt          time.Time       `db:"created_at" json:"createdAt"`
+	PrevHash           string          `db:"prev_hash" json:"prevHash"`
+	Hash               string          `db:"hash" json:"hash"`
+	CursorCreatedAt    string          `db:"cursor_created_at" json:"cursorCreatedAt"`
+}
+
+// Keyset page over (created_at, id) DESC used to stream audit exports.
+// cursor_created_at is the raw text of created_at from the previous page.
+func (q *Queries) ListAuditEventsForExport(ctx context.Context, arg ListAuditEventsForExportParams) ([]ListAuditEventsForExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAuditEventsForExport,
+		arg.WorkspaceID,
+		arg.ActorID,
+		arg.EntityType,
+		arg.Action,
+		arg.Outcome,
+		arg.DateFrom,
+		arg.DateTo,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Lim,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListAuditEventsForExportRow{}
+	for rows.Next() {
+		var i ListAuditEventsForExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.ActorID,
+			&i.ActorType,
+			&i.Action,
+			&i.EntityType,
+			&i.EntityID,
+			&i.Details,
+			&i.PermissionsChecked,
+			&i.Outcome,
+			&i.TraceID,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
+			&i.CursorCreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -481,7 +870,7 @@ func (q *Queries) ListAuditEventsByWorkspace(ctx context.Context, arg ListAuditE
 }
 
 const queryAuditEvents = `-- name: QueryAuditEvents :many
-SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at FROM audit_event
+SELECT id, workspace_id, actor_id, actor_type, "action", entity_type, entity_id, details, permissions_checked, outcome, trace_id, ip_address, user_agent, created_at, prev_hash, hash FROM audit_event
 WHERE workspace_id = ?1
   AND (?2 = '' OR actor_id = ?2)
   AND (?3 = '' OR entity_type = ?3)
@@ -546,6 +935,8 @@ func (q *Queries) QueryAuditEvents(ctx context.Context, arg QueryAuditEventsPara
 			&i.IpAddress,
 			&i.UserAgent,
 			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
 		); err != nil {
 			return nil, err
 		}
@@ -559,3 +950,22 @@ func (q *Queries) QueryAuditEvents(ctx context.Context, arg QueryAuditEventsPara
 	}
 	return items, nil
 }
+
+const upsertAuditChainAnchor = `-- name: UpsertAuditChainAnchor :exec
+INSERT INTO audit_chain_anchor (workspace_id, anchor_hash, updated_at)
+VALUES (?, ?, ?)
+ON CONFLICT(workspace_id) DO UPDATE SET
+    anchor_hash = excluded.anchor_hash,
+    updated_at = excluded.updated_at
+`
+
+type UpsertAuditChainAnchorParams struct {
+	WorkspaceID string    `db:"workspace_id" json:"workspaceId"`
+	AnchorHash  string    `db:"anchor_hash" json:"anchorHash"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+func (q *Queries) UpsertAuditChainAnchor(ctx context.Context, arg UpsertAuditChainAnchorParams) error {
+	_, err := q.db.ExecContext(ctx, upsertAuditChainAnchor, arg.WorkspaceID, arg.AnchorHash, arg.UpdatedAt)
+	return err
+}
@@ -505,6 +505,29 @@ func (q *Queries) ListOpenCasesByPriority(ctx context.Context, workspaceID strin
 	return items, nil
 }
 
+const restoreCase = `-- name: RestoreCase :execrows
+UPDATE case_ticket
+SET deleted_at = NULL,
+    updated_at = ?
+WHERE id = ?
+  AND workspace_id = ?
+  AND deleted_at IS NOT NULL
+`
+
+type RestoreCaseParams struct {
+	UpdatedAt   string `db:"updated_at" json:"updatedAt"`
+	ID          string `db:"id" json:"id"`
+	WorkspaceID string `db:"workspace_id" json:"workspaceId"`
+}
+
+func (q *Queries) RestoreCase(ctx context.Context, arg RestoreCaseParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreCase, arg.UpdatedAt, arg.ID, arg.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const softDeleteCase = `-- name: SoftDeleteCase :exec
 UPDATE case_ticket
 SET deleted_at = ?,
@@ -43,8 +43,8 @@ func (q *Queries) CountDealsByWorkspace(ctx context.Context, workspaceID string)
 
 const createDeal = `-- name: CreateDeal :exec
 
-INSERT INTO deal (id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO deal (id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, custom_fields)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateDealParams struct {
@@ -64,6 +64,7 @@ type CreateDealParams struct {
 	CreatedAt     string   `db:"created_at" json:"createdAt"`
 	UpdatedAt     string   `db:"updated_at" json:"updatedAt"`
 	DeletedAt     *string  `db:"deleted_at" json:"deletedAt"`
+	CustomFields  *string  `db:"custom_fields" json:"customFields"`
 }
 
 // SQL queries for deal table
@@ -86,12 +87,13 @@ func (q *Queries) CreateDeal(ctx context.Context, arg CreateDealParams) error {
 		arg.CreatedAt,
 		arg.UpdatedAt,
 		arg.DeletedAt,
+		arg.CustomFields,
 	)
 	return err
 }
 
 const getDealByID = `-- name: GetDealByID :one
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE id = ?
   AND workspace_id = ?
@@ -124,12 +126,15 @@ func (q *Queries) GetDealByID(ctx context.Context, arg GetDealByIDParams) (Deal,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.ClosedAt,
+		&i.CloseReason,
+		&i.CustomFields,
 	)
 	return i, err
 }
 
 const listDealsByAccount = `-- name: ListDealsByAccount :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND account_id = ?
@@ -168,6 +173,9 @@ func (q *Queries) ListDealsByAccount(ctx context.Context, arg ListDealsByAccount
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ClosedAt,
+			&i.CloseReason,
+			&i.CustomFields,
 		); err != nil {
 			return nil, err
 		}
@@ -183,7 +191,7 @@ func (q *Queries) ListDealsByAccount(ctx context.Context, arg ListDealsByAccount
 }
 
 const listDealsByOwner = `-- name: ListDealsByOwner :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND owner_id = ?
@@ -222,6 +230,9 @@ func (q *Queries) ListDealsByOwner(ctx context.Context, arg ListDealsByOwnerPara
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ClosedAt,
+			&i.CloseReason,
+			&i.CustomFields,
 		); err != nil {
 			return nil, err
 		}
@@ -237,7 +248,7 @@ func (q *Queries) ListDealsByOwner(ctx context.Context, arg ListDealsByOwnerPara
 }
 
 const listDealsByPipeline = `-- name: ListDealsByPipeline :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND pipeline_id = ?
@@ -276,6 +287,9 @@ func (q *Queries) ListDealsByPipeline(ctx context.Context, arg ListDealsByPipeli
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ClosedAt,
+			&i.CloseReason,
+			&i.CustomFields,
 		); err != nil {
 			return nil, err
 		}
@@ -291,7 +305,7 @@ func (q *Queries) ListDealsByPipeline(ctx context.Context, arg ListDealsByPipeli
 }
 
 const listDealsByStage = `-- name: ListDealsByStage :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND stage_id = ?
@@ -331,6 +345,9 @@ func (q *Queries) ListDealsByStage(ctx context.Context, arg ListDealsByStagePara
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ClosedAt,
+			&i.CloseReason,
+			&i.CustomFields,
 		); err != nil {
 			return nil, err
 		}
@@ -346,7 +363,7 @@ func (q *Queries) ListDealsByStage(ctx context.Context, arg ListDealsByStagePara
 }
 
 const listDealsByStatus = `-- name: ListDealsByStatus :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND status = ?
@@ -385,6 +402,9 @@ func (q *Queries) ListDealsByStatus(ctx context.Context, arg ListDealsByStatusPa
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ClosedAt,
+			&i.CloseReason,
+			&i.CustomFields,
 		); err != nil {
 			return nil, err
 		}
@@ -400,7 +420,7 @@ func (q *Queries) ListDealsByStatus(ctx context.Context, arg ListDealsByStatusPa
 }
 
 const listDealsByWorkspace = `-- name: ListDealsByWorkspace :many
-SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, account_id, contact_id, pipeline_id, stage_id, owner_id, title, amount, currency, expected_close, status, metadata, created_at, updated_at, deleted_at, closed_at, close_reason, custom_fields
 FROM deal
 WHERE workspace_id = ?
   AND deleted_at IS NULL
@@ -441,6 +461,9 @@ func (q *Queries) ListDealsByWorkspace(ctx context.Context, arg ListDealsByWorks
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.ClosedAt,
+			&i.CloseReason,
+			&i.CustomFields,
 		); err != nil {
 			return nil, err
 		}
@@ -514,7 +537,8 @@ SET account_id = ?,
     expected_close = ?,
     status = ?,
     metadata = ?,
-    updated_at = ?
+    updated_at = ?,
+    custom_fields = COALESCE(?, custom_fields)
 WHERE id = ?
   AND workspace_id = ?
   AND deleted_at IS NULL
@@ -533,6 +557,7 @@ type UpdateDealParams struct {
 	Status        string   `db:"status" json:"status"`
 	Metadata      *string  `db:"metadata" json:"metadata"`
 	UpdatedAt     string   `db:"updated_at" json:"updatedAt"`
+	CustomFields  *string  `db:"custom_fields" json:"customFields"`
 	ID            string   `db:"id" json:"id"`
 	WorkspaceID   string   `db:"workspace_id" json:"workspaceId"`
 }
@@ -551,6 +576,7 @@ func (q *Queries) UpdateDeal(ctx context.Context, arg UpdateDealParams) error {
 		arg.Status,
 		arg.Metadata,
 		arg.UpdatedAt,
+		arg.CustomFields,
 		arg.ID,
 		arg.WorkspaceID,
 	)
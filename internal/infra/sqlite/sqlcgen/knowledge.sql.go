@@ -40,8 +40,8 @@ const createEmbeddingDocument = `-- name: CreateEmbeddingDocument :exec
 
 INSERT INTO embedding_document (
     id, knowledge_item_id, workspace_id, chunk_index,
-    chunk_text, token_count, embedding_status, created_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+    chunk_text, token_count, embedding_status, metadata, created_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateEmbeddingDocumentParams struct {
@@ -52,6 +52,7 @@ type CreateEmbeddingDocumentParams struct {
 	ChunkText       string    `db:"chunk_text" json:"chunkText"`
 	TokenCount      *int64    `db:"token_count" json:"tokenCount"`
 	EmbeddingStatus string    `db:"embedding_status" json:"embeddingStatus"`
+	Metadata        *string   `db:"metadata" json:"metadata"`
 	CreatedAt       time.Time `db:"created_at" json:"createdAt"`
 }
 
@@ -68,6 +69,7 @@ func (q *Queries) CreateEmbeddingDocument(ctx context.Context, arg CreateEmbeddi
 		arg.ChunkText,
 		arg.TokenCount,
 		arg.EmbeddingStatus,
+		arg.Metadata,
 		arg.CreatedAt,
 	)
 	return err
@@ -118,8 +120,9 @@ const createKnowledgeItem = `-- name: CreateKnowledgeItem :exec
 INSERT INTO knowledge_item (
     id, workspace_id, source_system, source_type, source_object_id,
     refresh_strategy, delete_behavior, permission_context, title, raw_content,
-    normalized_content, entity_type, entity_id, metadata, created_at, updated_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    normalized_content, entity_type, entity_id, metadata, language, content_hash, normalized_hash,
+    created_at, updated_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateKnowledgeItemParams struct {
@@ -137,6 +140,9 @@ type CreateKnowledgeItemParams struct {
 	EntityType        *string   `db:"entity_type" json:"entityType"`
 	EntityID          *string   `db:"entity_id" json:"entityId"`
 	Metadata          *string   `db:"metadata" json:"metadata"`
+	Language          *string   `db:"language" json:"language"`
+	ContentHash       *string   `db:"content_hash" json:"contentHash"`
+	NormalizedHash    *string   `db:"normalized_hash" json:"normalizedHash"`
 	CreatedAt         time.Time `db:"created_at" json:"createdAt"`
 	UpdatedAt         time.Time `db:"updated_at" json:"updatedAt"`
 }
@@ -166,6 +172,9 @@ func (q *Queries) CreateKnowledgeItem(ctx context.Context, arg CreateKnowledgeIt
 		arg.EntityType,
 		arg.EntityID,
 		arg.Metadata,
+		arg.Language,
+		arg.ContentHash,
+		arg.NormalizedHash,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
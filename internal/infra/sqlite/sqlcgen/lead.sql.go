@@ -318,6 +318,29 @@ func (q *Queries) ListLeadsByWorkspace(ctx context.Context, arg ListLeadsByWorks
 	return items, nil
 }
 
+const restoreLead = `-- name: RestoreLead :execrows
+UPDATE lead
+SET deleted_at = NULL,
+    updated_at = ?
+WHERE id = ?
+  AND workspace_id = ?
+  AND deleted_at IS NOT NULL
+`
+
+type RestoreLeadParams struct {
+	UpdatedAt   string `db:"updated_at" json:"updatedAt"`
+	ID          string `db:"id" json:"id"`
+	WorkspaceID string `db:"workspace_id" json:"workspaceId"`
+}
+
+func (q *Queries) RestoreLead(ctx context.Context, arg RestoreLeadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreLead, arg.UpdatedAt, arg.ID, arg.WorkspaceID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const softDeleteLead = `-- name: SoftDeleteLead :exec
 UPDATE lead
 SET deleted_at = ?,
@@ -10,18 +10,19 @@ import (
 )
 
 type Account struct {
-	ID          string  `db:"id" json:"id"`
-	WorkspaceID string  `db:"workspace_id" json:"workspaceId"`
-	Name        string  `db:"name" json:"name"`
-	Domain      *string `db:"domain" json:"domain"`
-	Industry    *string `db:"industry" json:"industry"`
-	SizeSegment *string `db:"size_segment" json:"sizeSegment"`
-	OwnerID     string  `db:"owner_id" json:"ownerId"`
-	Address     *string `db:"address" json:"address"`
-	Metadata    *string `db:"metadata" json:"metadata"`
-	CreatedAt   string  `db:"created_at" json:"createdAt"`
-	UpdatedAt   string  `db:"updated_at" json:"updatedAt"`
-	DeletedAt   *string `db:"deleted_at" json:"deletedAt"`
+	ID           string  `db:"id" json:"id"`
+	WorkspaceID  string  `db:"workspace_id" json:"workspaceId"`
+	Name         string  `db:"name" json:"name"`
+	Domain       *string `db:"domain" json:"domain"`
+	Industry     *string `db:"industry" json:"industry"`
+	SizeSegment  *string `db:"size_segment" json:"sizeSegment"`
+	OwnerID      string  `db:"owner_id" json:"ownerId"`
+	Address      *string `db:"address" json:"address"`
+	Metadata     *string `db:"metadata" json:"metadata"`
+	CreatedAt    string  `db:"created_at" json:"createdAt"`
+	UpdatedAt    string  `db:"updated_at" json:"updatedAt"`
+	DeletedAt    *string `db:"deleted_at" json:"deletedAt"`
+	CustomFields *string `db:"custom_fields" json:"customFields"`
 }
 
 type Activity struct {
@@ -134,6 +135,12 @@ type Attachment struct {
 	CreatedAt   string  `db:"created_at" json:"createdAt"`
 }
 
+type AuditChainAnchor struct {
+	WorkspaceID string    `db:"workspace_id" json:"workspaceId"`
+	AnchorHash  string    `db:"anchor_hash" json:"anchorHash"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updatedAt"`
+}
+
 type AuditEvent struct {
 	ID                 string          `db:"id" json:"id"`
 	WorkspaceID        string          `db:"workspace_id" json:"workspaceId"`
@@ -149,6 +156,12 @@ type AuditEvent struct {
 	IpAddress          *string         `db:"ip_address" json:"ipAddress"`
 	UserAgent          *string         `db:"user_agent" json:"userAgent"`
 	CreatedAt          time.Time       `db:"created_at" json:"createdAt"`
+	PrevHash           string          `db:"prev_hash" json:"prevHash"`
+	Hash               string          `db:"hash" json:"hash"`
+}
+
+type AuditPurgeSession struct {
+	WorkspaceID string `db:"workspace_id" json:"workspaceId"`
 }
 
 type CaseTicket struct {
@@ -206,6 +219,9 @@ type Deal struct {
 	CreatedAt     string   `db:"created_at" json:"createdAt"`
 	UpdatedAt     string   `db:"updated_at" json:"updatedAt"`
 	DeletedAt     *string  `db:"deleted_at" json:"deletedAt"`
+	ClosedAt      *string  `db:"closed_at" json:"closedAt"`
+	CloseReason   *string  `db:"close_reason" json:"closeReason"`
+	CustomFields  *string  `db:"custom_fields" json:"customFields"`
 }
 
 type EmbeddingDocument struct {
@@ -293,16 +309,17 @@ type Lead struct {
 }
 
 type Note struct {
-	ID          string  `db:"id" json:"id"`
-	WorkspaceID string  `db:"workspace_id" json:"workspaceId"`
-	EntityType  string  `db:"entity_type" json:"entityType"`
-	EntityID    string  `db:"entity_id" json:"entityId"`
-	AuthorID    string  `db:"author_id" json:"authorId"`
-	Content     string  `db:"content" json:"content"`
-	IsInternal  bool    `db:"is_internal" json:"isInternal"`
-	Metadata    *string `db:"metadata" json:"metadata"`
-	CreatedAt   string  `db:"created_at" json:"createdAt"`
-	UpdatedAt   string  `db:"updated_at" json:"updatedAt"`
+	ID           string  `db:"id" json:"id"`
+	WorkspaceID  string  `db:"workspace_id" json:"workspaceId"`
+	EntityType   string  `db:"entity_type" json:"entityType"`
+	EntityID     string  `db:"entity_id" json:"entityId"`
+	AuthorID     string  `db:"author_id" json:"authorId"`
+	Content      string  `db:"content" json:"content"`
+	IsInternal   bool    `db:"is_internal" json:"isInternal"`
+	Metadata     *string `db:"metadata" json:"metadata"`
+	CreatedAt    string  `db:"created_at" json:"createdAt"`
+	UpdatedAt    string  `db:"updated_at" json:"updatedAt"`
+	ParentNoteID *string `db:"parent_note_id" json:"parentNoteId"`
 }
 
 type Pipeline struct {
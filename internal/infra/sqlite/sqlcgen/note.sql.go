@@ -43,21 +43,22 @@ func (q *Queries) CountNotesByWorkspace(ctx context.Context, workspaceID string)
 
 const createNote = `-- name: CreateNote :exec
 
-INSERT INTO note (id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO note (id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateNoteParams struct {
-	ID          string  `db:"id" json:"id"`
-	WorkspaceID string  `db:"workspace_id" json:"workspaceId"`
-	EntityType  string  `db:"entity_type" json:"entityType"`
-	EntityID    string  `db:"entity_id" json:"entityId"`
-	AuthorID    string  `db:"author_id" json:"authorId"`
-	Content     string  `db:"content" json:"content"`
-	IsInternal  bool    `db:"is_internal" json:"isInternal"`
-	Metadata    *string `db:"metadata" json:"metadata"`
-	CreatedAt   string  `db:"created_at" json:"createdAt"`
-	UpdatedAt   string  `db:"updated_at" json:"updatedAt"`
+	ID           string  `db:"id" json:"id"`
+	WorkspaceID  string  `db:"workspace_id" json:"workspaceId"`
+	EntityType   string  `db:"entity_type" json:"entityType"`
+	EntityID     string  `db:"entity_id" json:"entityId"`
+	AuthorID     string  `db:"author_id" json:"authorId"`
+	Content      string  `db:"content" json:"content"`
+	IsInternal   bool    `db:"is_internal" json:"isInternal"`
+	Metadata     *string `db:"metadata" json:"metadata"`
+	CreatedAt    string  `db:"created_at" json:"createdAt"`
+	UpdatedAt    string  `db:"updated_at" json:"updatedAt"`
+	ParentNoteID *string `db:"parent_note_id" json:"parentNoteId"`
 }
 
 // SQL queries for note table
@@ -74,6 +75,7 @@ func (q *Queries) CreateNote(ctx context.Context, arg CreateNoteParams) error {
 		arg.Metadata,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.ParentNoteID,
 	)
 	return err
 }
@@ -95,7 +97,7 @@ func (q *Queries) DeleteNote(ctx context.Context, arg DeleteNoteParams) error {
 }
 
 const getNoteByID = `-- name: GetNoteByID :one
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE id = ?
   AND workspace_id = ?
@@ -121,12 +123,63 @@ func (q *Queries) GetNoteByID(ctx context.Context, arg GetNoteByIDParams) (Note,
 		&i.Metadata,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ParentNoteID,
 	)
 	return i, err
 }
 
+const listNoteThreadByEntity = `-- name: ListNoteThreadByEntity :many
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
+FROM note
+WHERE workspace_id = ?
+  AND entity_type = ?
+  AND entity_id = ?
+ORDER BY created_at ASC, id ASC
+`
+
+type ListNoteThreadByEntityParams struct {
+	WorkspaceID string `db:"workspace_id" json:"workspaceId"`
+	EntityType  string `db:"entity_type" json:"entityType"`
+	EntityID    string `db:"entity_id" json:"entityId"`
+}
+
+func (q *Queries) ListNoteThreadByEntity(ctx context.Context, arg ListNoteThreadByEntityParams) ([]Note, error) {
+	rows, err := q.db.QueryContext(ctx, listNoteThreadByEntity, arg.WorkspaceID, arg.EntityType, arg.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Note{}
+	for rows.Next() {
+		var i Note
+		if err := rows.Scan(
+			&i.ID,
+			&i.WorkspaceID,
+			&i.EntityType,
+			&i.EntityID,
+			&i.AuthorID,
+			&i.Content,
+			&i.IsInternal,
+			&i.Metadata,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ParentNoteID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listNotesByAuthor = `-- name: ListNotesByAuthor :many
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE workspace_id = ?
   AND author_id = ?
@@ -158,6 +211,7 @@ func (q *Queries) ListNotesByAuthor(ctx context.Context, arg ListNotesByAuthorPa
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentNoteID,
 		); err != nil {
 			return nil, err
 		}
@@ -173,7 +227,7 @@ func (q *Queries) ListNotesByAuthor(ctx context.Context, arg ListNotesByAuthorPa
 }
 
 const listNotesByEntity = `-- name: ListNotesByEntity :many
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE workspace_id = ?
   AND entity_type = ?
@@ -207,6 +261,7 @@ func (q *Queries) ListNotesByEntity(ctx context.Context, arg ListNotesByEntityPa
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentNoteID,
 		); err != nil {
 			return nil, err
 		}
@@ -222,7 +277,7 @@ func (q *Queries) ListNotesByEntity(ctx context.Context, arg ListNotesByEntityPa
 }
 
 const listNotesByEntityPublic = `-- name: ListNotesByEntityPublic :many
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE workspace_id = ?
   AND entity_type = ?
@@ -257,6 +312,7 @@ func (q *Queries) ListNotesByEntityPublic(ctx context.Context, arg ListNotesByEn
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentNoteID,
 		); err != nil {
 			return nil, err
 		}
@@ -272,7 +328,7 @@ func (q *Queries) ListNotesByEntityPublic(ctx context.Context, arg ListNotesByEn
 }
 
 const listNotesByWorkspace = `-- name: ListNotesByWorkspace :many
-SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at
+SELECT id, workspace_id, entity_type, entity_id, author_id, content, is_internal, metadata, created_at, updated_at, parent_note_id
 FROM note
 WHERE workspace_id = ?
 ORDER BY created_at DESC
@@ -306,6 +362,7 @@ func (q *Queries) ListNotesByWorkspace(ctx context.Context, arg ListNotesByWorks
 			&i.Metadata,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ParentNoteID,
 		); err != nil {
 			return nil, err
 		}
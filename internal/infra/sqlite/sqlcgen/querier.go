@@ -14,6 +14,7 @@ type Querier interface {
 	// USER ROLE (assignment) queries
 	// ========================
 	AssignRole(ctx context.Context, arg AssignRoleParams) error
+	BeginAuditPurgeSession(ctx context.Context, workspaceID string) error
 	CaseBacklogByWorkspace(ctx context.Context, arg CaseBacklogByWorkspaceParams) ([]CaseBacklogByWorkspaceRow, error)
 	CaseMTTRByWorkspace(ctx context.Context, workspaceID string) ([]CaseMTTRByWorkspaceRow, error)
 	CaseVolumeByWorkspace(ctx context.Context, workspaceID string) ([]CaseVolumeByWorkspaceRow, error)
@@ -24,8 +25,14 @@ type Querier interface {
 	CountAgentRunsByWorkspace(ctx context.Context, workspaceID string) (int64, error)
 	CountAttachmentsByEntity(ctx context.Context, arg CountAttachmentsByEntityParams) (int64, error)
 	CountAttachmentsByWorkspace(ctx context.Context, workspaceID string) (int64, error)
+	// Counts audit events for a workspace within a time range
+	CountAuditEventsByTimeRange(ctx context.Context, arg CountAuditEventsByTimeRangeParams) (int64, error)
 	// Counts total audit events for a workspace
 	CountAuditEventsByWorkspace(ctx context.Context, workspaceID string) (int64, error)
+	// Task 4.8: Counts events a retention purge would remove. Only the oldest
+	// contiguous run (insertion order) older than the cutoff qualifies, so the
+	// remaining hash chain stays linked.
+	CountAuditEventsForPurge(ctx context.Context, arg CountAuditEventsForPurgeParams) (int64, error)
 	CountCasesByStatus(ctx context.Context, arg CountCasesByStatusParams) (int64, error)
 	CountCasesByWorkspace(ctx context.Context, workspaceID string) (int64, error)
 	CountContactsByWorkspace(ctx context.Context, workspaceID string) (int64, error)
@@ -140,6 +147,8 @@ type Querier interface {
 	DeleteAgentDefinition(ctx context.Context, arg DeleteAgentDefinitionParams) error
 	DeleteAgentRun(ctx context.Context, arg DeleteAgentRunParams) error
 	DeleteAttachment(ctx context.Context, arg DeleteAttachmentParams) error
+	// Task 4.8: Requires an open audit_purge_session row for the workspace.
+	DeleteAuditEventsForPurge(ctx context.Context, arg DeleteAuditEventsForPurgeParams) (int64, error)
 	// Task 2.7: Remove all chunks when knowledge item is deleted/reindexed
 	DeleteEmbeddingDocumentsByKnowledgeItem(ctx context.Context, arg DeleteEmbeddingDocumentsByKnowledgeItemParams) error
 	DeleteEvalSuite(ctx context.Context, arg DeleteEvalSuiteParams) error
@@ -155,6 +164,7 @@ type Querier interface {
 	DeleteWorkflow(ctx context.Context, arg DeleteWorkflowParams) error
 	DeleteWorkspace(ctx context.Context, id string) error
 	DismissSignal(ctx context.Context, arg DismissSignalParams) (Signal, error)
+	EndAuditPurgeSession(ctx context.Context, workspaceID string) error
 	GetAccountByID(ctx context.Context, arg GetAccountByIDParams) (Account, error)
 	GetActivePrompt(ctx context.Context, arg GetActivePromptParams) (PromptVersion, error)
 	GetActiveWorkflowByName(ctx context.Context, arg GetActiveWorkflowByNameParams) (Workflow, error)
@@ -171,8 +181,11 @@ type Querier interface {
 	// search.go because sqlc does not support CREATE VIRTUAL TABLE fts5 syntax.
 	GetAllEmbeddedVectorsByWorkspace(ctx context.Context, workspaceID string) ([]GetAllEmbeddedVectorsByWorkspaceRow, error)
 	GetAttachmentByID(ctx context.Context, arg GetAttachmentByIDParams) (Attachment, error)
+	GetAuditChainAnchor(ctx context.Context, workspaceID string) (string, error)
 	// Retrieves a single audit event by ID
 	GetAuditEventByID(ctx context.Context, id string) (AuditEvent, error)
+	// Task 4.8: Hash of the newest chained event a purge removes; becomes the chain anchor.
+	GetAuditPurgeAnchorHash(ctx context.Context, arg GetAuditPurgeAnchorHashParams) (string, error)
 	GetCaseByID(ctx context.Context, arg GetCaseByIDParams) (CaseTicket, error)
 	GetContactByID(ctx context.Context, arg GetContactByIDParams) (Contact, error)
 	GetDealByID(ctx context.Context, arg GetDealByIDParams) (Deal, error)
@@ -186,6 +199,8 @@ type Querier interface {
 	GetKnowledgeItemByEntity(ctx context.Context, arg GetKnowledgeItemByEntityParams) (GetKnowledgeItemByEntityRow, error)
 	// Task 2.1/2.2: Retrieve a single knowledge item (excludes soft-deleted)
 	GetKnowledgeItemByID(ctx context.Context, arg GetKnowledgeItemByIDParams) (GetKnowledgeItemByIDRow, error)
+	// Task 4.7: Returns the most recent chain hash for a workspace (insertion order).
+	GetLatestAuditEventHash(ctx context.Context, workspaceID string) (string, error)
 	GetLatestPromptVersionNumber(ctx context.Context, arg GetLatestPromptVersionNumberParams) (interface{}, error)
 	GetLatestTimelineEventByEntity(ctx context.Context, arg GetLatestTimelineEventByEntityParams) (TimelineEvent, error)
 	GetLeadByID(ctx context.Context, arg GetLeadByIDParams) (Lead, error)
@@ -237,13 +252,16 @@ type Querier interface {
 	ListAttachmentsByEntity(ctx context.Context, arg ListAttachmentsByEntityParams) ([]Attachment, error)
 	ListAttachmentsByUploader(ctx context.Context, arg ListAttachmentsByUploaderParams) ([]Attachment, error)
 	ListAttachmentsByWorkspace(ctx context.Context, arg ListAttachmentsByWorkspaceParams) ([]Attachment, error)
-	// Lists audit events filtered by action type
+	// Task 4.7: Keyset page over hashed audit events in insertion (rowid) order
+	// used by chain verification.
+	ListAuditChainPage(ctx context.Context, arg ListAuditChainPageParams) ([]ListAuditChainPageRow, error)
+	// Lists audit events filtered by action type within a time window
 	ListAuditEventsByAction(ctx context.Context, arg ListAuditEventsByActionParams) ([]AuditEvent, error)
-	// Lists audit events for a specific actor
+	// Lists audit events for a specific actor within a time window
 	ListAuditEventsByActor(ctx context.Context, arg ListAuditEventsByActorParams) ([]AuditEvent, error)
-	// Lists audit events for a specific entity
+	// Lists audit events for a specific entity within a time window
 	ListAuditEventsByEntity(ctx context.Context, arg ListAuditEventsByEntityParams) ([]AuditEvent, error)
-	// Lists audit events filtered by outcome (success/denied/error)
+	// Lists audit events filtered by outcome (success/denied/error) within a time window
 	ListAuditEventsByOutcome(ctx context.Context, arg ListAuditEventsByOutcomeParams) ([]AuditEvent, error)
 	// Lists audit events within a time range
 	ListAuditEventsByTimeRange(ctx context.Context, arg ListAuditEventsByTimeRangeParams) ([]AuditEvent, error)
@@ -253,6 +271,9 @@ type Querier interface {
 	// Lists audit events for a workspace with pagination
 	// Results ordered by created_at DESC (newest first)
 	ListAuditEventsByWorkspace(ctx context.Context, arg ListAuditEventsByWorkspaceParams) ([]AuditEvent, error)
+	// Keyset page over (created_at, id) DESC used to stream audit exports.
+	// cursor_created_at is the raw text of created_at from the previous page.
+	ListAuditEventsForExport(ctx context.Context, arg ListAuditEventsForExportParams) ([]ListAuditEventsForExportRow, error)
 	ListCasesByAccount(ctx context.Context, arg ListCasesByAccountParams) ([]CaseTicket, error)
 	ListCasesByOwner(ctx context.Context, arg ListCasesByOwnerParams) ([]CaseTicket, error)
 	ListCasesBySLADeadline(ctx context.Context, arg ListCasesBySLADeadlineParams) ([]CaseTicket, error)
@@ -283,6 +304,7 @@ type Querier interface {
 	ListLeadsByOwner(ctx context.Context, arg ListLeadsByOwnerParams) ([]Lead, error)
 	ListLeadsByStatus(ctx context.Context, arg ListLeadsByStatusParams) ([]Lead, error)
 	ListLeadsByWorkspace(ctx context.Context, arg ListLeadsByWorkspaceParams) ([]Lead, error)
+	ListNoteThreadByEntity(ctx context.Context, arg ListNoteThreadByEntityParams) ([]Note, error)
 	ListNotesByAuthor(ctx context.Context, arg ListNotesByAuthorParams) ([]Note, error)
 	ListNotesByEntity(ctx context.Context, arg ListNotesByEntityParams) ([]Note, error)
 	ListNotesByEntityPublic(ctx context.Context, arg ListNotesByEntityPublicParams) ([]Note, error)
@@ -314,6 +336,9 @@ type Querier interface {
 	ListWorkspaces(ctx context.Context) ([]Workspace, error)
 	// Lists audit events filtered by optional compound criteria
 	QueryAuditEvents(ctx context.Context, arg QueryAuditEventsParams) ([]AuditEvent, error)
+	RestoreAccount(ctx context.Context, arg RestoreAccountParams) (int64, error)
+	RestoreCase(ctx context.Context, arg RestoreCaseParams) (int64, error)
+	RestoreLead(ctx context.Context, arg RestoreLeadParams) (int64, error)
 	RevokeAllRoles(ctx context.Context, userID string) error
 	RevokeRole(ctx context.Context, arg RevokeRoleParams) error
 	// Task 4.5e - Reporting base queries (FR-003)
@@ -353,6 +378,7 @@ type Querier interface {
 	UpdateUserStatus(ctx context.Context, arg UpdateUserStatusParams) error
 	UpdateWorkflow(ctx context.Context, arg UpdateWorkflowParams) (Workflow, error)
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) error
+	UpsertAuditChainAnchor(ctx context.Context, arg UpsertAuditChainAnchorParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,211 @@
+// tx.go: request-scoped transactions.
+// A transaction stored in the context with ContextWithTx is picked up by
+// every ContextDB statement made with that context, so several service calls
+// can share one transaction without threading *sql.Tx through their
+// signatures. Without one, ContextDB uses the pooled connection as before.
+//
+// Side effects that must not outlive a rollback, such as event bus
+// publishes, are queued with AfterCommit; writes that must survive it, such
+// as audit events, with AfterTx. The owner of the transaction runs them with
+// FinishTx once it has committed or rolled back.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+type txContextKey struct{}
+
+// txScope is the context transaction and the callbacks queued on it.
+type txScope struct {
+	tx          *sql.Tx
+	mu          sync.Mutex
+	afterCommit []func()
+	afterTx     []func(context.Context)
+}
+
+// ContextWithTx returns a copy of ctx carrying tx.
+func ContextWithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, &txScope{tx: tx})
+}
+
+// TxFromContext returns the transaction stored by ContextWithTx, if any.
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	scope := scopeFromContext(ctx)
+	if scope == nil {
+		return nil, false
+	}
+	return scope.tx, scope.tx != nil
+}
+
+// WithoutTx returns a copy of ctx whose statements run on the pool again.
+func WithoutTx(ctx context.Context) context.Context {
+	return context.WithValue(ctx, txContextKey{}, (*txScope)(nil))
+}
+
+func scopeFromContext(ctx context.Context) *txScope {
+	scope, _ := ctx.Value(txContextKey{}).(*txScope)
+	return scope
+}
+
+// AfterCommit runs fn once the context transaction commits, and drops it if
+// the transaction rolls back. Without a context transaction fn runs now.
+func AfterCommit(ctx context.Context, fn func()) {
+	scope := scopeFromContext(ctx)
+	if scope == nil {
+		fn()
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.afterCommit = append(scope.afterCommit, fn)
+}
+
+// AfterTx runs fn once the context transaction has ended, whether it
+// committed or not, with a context outside of it. Without a context
+// transaction fn runs now with ctx.
+func AfterTx(ctx context.Context, fn func(ctx context.Context)) {
+	scope := scopeFromContext(ctx)
+	if scope == nil {
+		fn(ctx)
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	scope.afterTx = append(scope.afterTx, fn)
+}
+
+// FinishTx runs the callbacks queued on the transaction of ctx, which must
+// have ended: those of AfterTx always, those of AfterCommit only when
+// committed. The queue is emptied, so calling it again does nothing.
+func FinishTx(ctx context.Context, committed bool) {
+	scope := scopeFromContext(ctx)
+	if scope == nil {
+		return
+	}
+	scope.mu.Lock()
+	afterCommit, afterTx := scope.afterCommit, scope.afterTx
+	scope.afterCommit, scope.afterTx = nil, nil
+	scope.mu.Unlock()
+
+	outside := WithoutTx(ctx)
+	for _, fn := range afterTx {
+		fn(outside)
+	}
+	if committed {
+		for _, fn := range afterCommit {
+			fn()
+		}
+	}
+}
+
+// ContextDB is a *sql.DB whose statements run on the context transaction
+// when there is one. It satisfies sqlcgen.DBTX.
+type ContextDB struct {
+	*sql.DB
+}
+
+// NewContextDB wraps db.
+func NewContextDB(db *sql.DB) *ContextDB {
+	return &ContextDB{DB: db}
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (d *ContextDB) conn(ctx context.Context) execer {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	return d.DB
+}
+
+// ExecContext runs query on the context transaction or the pool.
+func (d *ContextDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.conn(ctx).ExecContext(ctx, query, args...)
+}
+
+// PrepareContext prepares query on the context transaction or the pool.
+func (d *ContextDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.conn(ctx).PrepareContext(ctx, query)
+}
+
+// QueryContext runs query on the context transaction or the pool.
+func (d *ContextDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.conn(ctx).QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs query on the context transaction or the pool.
+func (d *ContextDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.conn(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// savepointSeq names nested savepoints uniquely.
+var savepointSeq atomic.Uint64
+
+// Tx is a transaction begun through ContextDB.BeginTx. Nested in a context
+// transaction it is a SAVEPOINT of it: Commit releases the savepoint and
+// Rollback undoes only the work done since BeginTx, leaving the outer
+// transaction to be committed or rolled back by its owner.
+type Tx struct {
+	*sql.Tx
+	savepoint string
+	done      bool
+}
+
+// BeginTx starts a transaction on the pool, or a savepoint on the context
+// transaction when ctx carries one (opts are then ignored).
+func (d *ContextDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	outer, ok := TxFromContext(ctx)
+	if !ok {
+		tx, err := d.DB.BeginTx(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &Tx{Tx: tx}, nil
+	}
+	name := fmt.Sprintf("fenix_sp_%d", savepointSeq.Add(1))
+	if _, err := outer.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return nil, fmt.Errorf("begin savepoint: %w", err)
+	}
+	return &Tx{Tx: outer, savepoint: name}, nil
+}
+
+// Commit commits the transaction or releases the savepoint.
+func (t *Tx) Commit() error {
+	if t.savepoint == "" {
+		return t.Tx.Commit()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.Tx.Exec("RELEASE SAVEPOINT " + t.savepoint)
+	return err
+}
+
+// Rollback rolls the transaction back, or undoes and releases the
+// savepoint. Like sql.Tx it returns sql.ErrTxDone once committed or rolled
+// back, so it can be deferred.
+func (t *Tx) Rollback() error {
+	if t.savepoint == "" {
+		return t.Tx.Rollback()
+	}
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+	_, err := t.Tx.Exec("ROLLBACK TO SAVEPOINT " + t.savepoint)
+	if err == nil {
+		_, err = t.Tx.Exec("RELEASE SAVEPOINT " + t.savepoint)
+	}
+	return err
+}
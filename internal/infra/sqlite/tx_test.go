@@ -0,0 +1,116 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/matiasleandrokruk/fenix/internal/infra/sqlite"
+)
+
+// TestContextDB_NestedTxIsSavepoint verifies a transaction begun under a
+// context transaction only rolls back its own writes.
+func TestContextDB_NestedTxIsSavepoint(t *testing.T) {
+	t.Parallel()
+
+	db, err := sqlite.NewDB(tempDBPath(t))
+	if err != nil {
+		t.Fatalf("NewDB error = %v", err)
+	}
+	defer db.Close()
+	if _, err = db.Exec(`CREATE TABLE item (name TEXT NOT NULL)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	outer, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	ctx := sqlite.ContextWithTx(context.Background(), outer)
+	cdb := sqlite.NewContextDB(db)
+	if _, err = cdb.ExecContext(ctx, `INSERT INTO item (name) VALUES ('kept')`); err != nil {
+		t.Fatalf("insert kept: %v", err)
+	}
+
+	inner, err := cdb.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("nested BeginTx: %v", err)
+	}
+	if _, err = inner.ExecContext(ctx, `INSERT INTO item (name) VALUES ('undone')`); err != nil {
+		t.Fatalf("insert undone: %v", err)
+	}
+	if err = inner.Rollback(); err != nil {
+		t.Fatalf("nested Rollback: %v", err)
+	}
+	if err = inner.Rollback(); !errors.Is(err, sql.ErrTxDone) {
+		t.Fatalf("second Rollback error = %v; want sql.ErrTxDone", err)
+	}
+
+	var inTx int
+	if err = cdb.QueryRowContext(ctx, `SELECT COUNT(*) FROM item`).Scan(&inTx); err != nil {
+		t.Fatalf("count in tx: %v", err)
+	}
+	if inTx != 1 {
+		t.Fatalf("rows seen in tx = %d; want 1", inTx)
+	}
+	if err = outer.Commit(); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	var names []string
+	rows, err := db.Query(`SELECT name FROM item`)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		names = append(names, name)
+	}
+	if len(names) != 1 || names[0] != "kept" {
+		t.Fatalf("committed rows = %v; want [kept]", names)
+	}
+}
+
+// TestFinishTx_RunsCallbacksByOutcome verifies AfterCommit callbacks only run
+// once committed, AfterTx ones always, outside the transaction, and both
+// right away without one.
+func TestFinishTx_RunsCallbacksByOutcome(t *testing.T) {
+	t.Parallel()
+
+	for _, committed := range []bool{true, false} {
+		var commits, ends int
+		ctx := sqlite.ContextWithTx(context.Background(), &sql.Tx{})
+		sqlite.AfterCommit(ctx, func() { commits++ })
+		sqlite.AfterTx(ctx, func(ctx context.Context) {
+			if _, inTx := sqlite.TxFromContext(ctx); inTx {
+				t.Error("AfterTx callback ran inside the transaction")
+			}
+			ends++
+		})
+		if commits != 0 || ends != 0 {
+			t.Fatalf("callbacks ran before FinishTx")
+		}
+		sqlite.FinishTx(ctx, committed)
+		sqlite.FinishTx(ctx, committed)
+
+		wantCommits := 0
+		if committed {
+			wantCommits = 1
+		}
+		if commits != wantCommits || ends != 1 {
+			t.Fatalf("committed=%v: commits=%d ends=%d; want %d and 1", committed, commits, ends, wantCommits)
+		}
+	}
+
+	ran := 0
+	sqlite.AfterCommit(context.Background(), func() { ran++ })
+	sqlite.AfterTx(context.Background(), func(context.Context) { ran++ })
+	if ran != 2 {
+		t.Fatalf("callbacks without a transaction ran %d times; want 2", ran)
+	}
+}
@@ -0,0 +1,99 @@
+// Package tracing exports OpenTelemetry traces to an OTLP collector. Without
+// a configured provider every span is a no-op, so callers can start spans
+// unconditionally.
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the service.name resource attribute of exported spans.
+const ServiceName = "fenix"
+
+// instrumentationName scopes the tracers handed out by Start.
+const instrumentationName = "github.com/matiasleandrokruk/fenix"
+
+type traceIDKey struct{}
+
+// NewProvider returns a tracer provider batching spans to the OTLP/HTTP
+// collector at endpointURL, e.g. http://localhost:4318. Call Shutdown on the
+// provider to flush pending spans.
+func NewProvider(ctx context.Context, endpointURL string) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpointURL))
+	if err != nil {
+		return nil, fmt.Errorf("create otlp exporter: %w", err)
+	}
+	return NewTracerProvider(sdktrace.WithBatcher(exporter)), nil
+}
+
+// NewTracerProvider returns a tracer provider for the fenix service whose
+// root spans started under WithTraceID take the requested trace ID. opts
+// choose where spans go.
+func NewTracerProvider(opts ...sdktrace.TracerProviderOption) *sdktrace.TracerProvider {
+	opts = append(opts,
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", ServiceName))),
+		sdktrace.WithIDGenerator(idGenerator{}),
+	)
+	return sdktrace.NewTracerProvider(opts...)
+}
+
+// WithTraceID returns ctx asking providers built by NewTracerProvider to give the
+// next root span the trace ID id: 32 hex digits, dashes ignored, so a UUID
+// keeps its bytes. An invalid id leaves ctx unchanged.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	traceID, err := trace.TraceIDFromHex(strings.ReplaceAll(id, "-", ""))
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// Start starts a span named name as a child of the span carried by ctx,
+// using that span's provider. Without a span in ctx it is a no-op.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return trace.SpanFromContext(ctx).TracerProvider().Tracer(instrumentationName).
+		Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End marks span failed with err, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// idGenerator generates random IDs, except for the trace ID requested
+// through WithTraceID.
+type idGenerator struct{}
+
+func (idGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	traceID, _ := ctx.Value(traceIDKey{}).(trace.TraceID)
+	for !traceID.IsValid() {
+		_, _ = rand.Read(traceID[:])
+	}
+	return traceID, newSpanID()
+}
+
+func (idGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	return newSpanID()
+}
+
+func newSpanID() trace.SpanID {
+	var spanID trace.SpanID
+	for !spanID.IsValid() {
+		_, _ = rand.Read(spanID[:])
+	}
+	return spanID
+}
@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestWithTraceID_SetsRootSpanTraceID(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")
+
+	_, span := tracer.Start(WithTraceID(context.Background(), "0192f0c1-7a3b-7c4d-8e5f-60718293a4b5"), "root")
+	span.End()
+	if got := span.SpanContext().TraceID().String(); got != "0192f0c17a3b7c4d8e5f60718293a4b5" {
+		t.Fatalf("trace ID = %s; want the requested one", got)
+	}
+
+	_, span = tracer.Start(WithTraceID(context.Background(), "not-a-trace-id"), "root")
+	span.End()
+	if !span.SpanContext().TraceID().IsValid() {
+		t.Fatal("trace ID for an invalid request is not valid; want a random one")
+	}
+}
+
+func TestStart_NestsUnderContextSpanAndRecordsError(t *testing.T) {
+	if _, span := Start(context.Background(), "orphan"); span.IsRecording() {
+		t.Fatal("Start without a span in ctx recorded; want a no-op span")
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	ctx, parent := NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	End(child, errors.New("boom"))
+	parent.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 2 || ended[0].Name() != "child" {
+		t.Fatalf("ended spans = %d; want child then parent", len(ended))
+	}
+	if ended[0].Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Fatal("child span is not nested under the context span")
+	}
+	if ended[0].Status().Code != codes.Error || ended[0].Status().Description != "boom" {
+		t.Fatalf("child status = %+v; want error boom", ended[0].Status())
+	}
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
+)
+
+const (
+	actionHTTPPanic    = "http.panic"
+	systemActorID      = "system"
+	errInternalPayload = `{"error":{"code":"INTERNAL_ERROR","message":"internal server error"}}`
+	serverComponent    = "server"
+)
+
+// panicAuditor is the subset of domainaudit.AuditService used to record
+// recovered panics.
+type panicAuditor interface {
+	LogWithDetails(
+		ctx context.Context,
+		workspaceID string,
+		actorID string,
+		actorType domainaudit.ActorType,
+		action string,
+		entityType *string,
+		entityID *string,
+		details *domainaudit.EventDetails,
+		outcome domainaudit.Outcome,
+	) error
+}
+
+// recoverPanics wraps the whole router so a panic anywhere below it, in
+// middleware or handlers, answers 500 instead of dropping the connection.
+// The stack is logged with the request ID to the context's logger (see
+// logging.FromContext), and requests that got as far as
+// AuthMiddleware also leave a system audit event in their workspace.
+//
+// It runs outside chi's RequestID middleware, so it assigns the X-Request-Id
+// header itself when missing; RequestID then reuses that value.
+func recoverPanics(next http.Handler, auditor panicAuditor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(middleware.RequestIDHeader)
+		if requestID == "" {
+			requestID = uuid.NewV7().String()
+			r.Header.Set(middleware.RequestIDHeader, requestID)
+		}
+		ctx, principal := ctxkeys.WithPrincipal(r.Context())
+		r = r.WithContext(ctx)
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if err, ok := rec.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(rec) // net/http's signal to abort the response silently
+			}
+			logging.FromContext(ctx).Error("panic recovered",
+				slog.String(logging.KeyComponent, serverComponent),
+				slog.String(logging.KeyRequestID, requestID),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.String("panic", fmt.Sprint(rec)),
+				slog.String("stack", string(debug.Stack())))
+			auditPanic(context.WithoutCancel(ctx), auditor, principal, r, requestID, rec)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(errInternalPayload))
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func auditPanic(ctx context.Context, auditor panicAuditor, principal *ctxkeys.Principal, r *http.Request, requestID string, rec any) {
+	if auditor == nil || principal.WorkspaceID == "" {
+		return
+	}
+	err := auditor.LogWithDetails(
+		ctx,
+		principal.WorkspaceID,
+		systemActorID,
+		domainaudit.ActorTypeSystem,
+		actionHTTPPanic,
+		nil,
+		nil,
+		&domainaudit.EventDetails{Metadata: map[string]any{
+			"request_id": requestID,
+			"method":     r.Method,
+			"path":       r.URL.Path,
+			"user_id":    principal.UserID,
+			"panic":      fmt.Sprint(rec),
+		}},
+		domainaudit.OutcomeError,
+	)
+	if err != nil {
+		logging.FromContext(ctx).Error("audit panic failed",
+			slog.String(logging.KeyComponent, serverComponent),
+			slog.String(logging.KeyRequestID, requestID),
+			logging.Err(err))
+	}
+}
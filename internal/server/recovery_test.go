@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/matiasleandrokruk/fenix/internal/api/ctxkeys"
+	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
+)
+
+// fakeAuth stands in for AuthMiddleware: it fills the Principal installed by
+// recoverPanics before handing over to next.
+func fakeAuth(workspaceID, userID string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p := ctxkeys.PrincipalFrom(r.Context()); p != nil {
+			p.WorkspaceID = workspaceID
+			p.UserID = userID
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestRecoverPanics_Returns500AndAuditsEvent(t *testing.T) {
+	db := openRuntimeTestDB(t)
+	seedWorkspace(t, db, "ws-panic")
+	auditSvc := domainaudit.NewAuditService(db)
+
+	var seenRequestID string
+	panicking := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seenRequestID = middleware.GetReqID(r.Context())
+		panic("nil map write")
+	})
+	handler := recoverPanics(middleware.RequestID(fakeAuth("ws-panic", "user-1", panicking)), auditSvc)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/cases/c1", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d; want 500", rec.Code)
+	}
+	if seenRequestID == "" {
+		t.Fatal("inner RequestID middleware saw no request id")
+	}
+
+	events, err := auditSvc.ListByOutcome(context.Background(), "ws-panic", domainaudit.OutcomeError, 10, 0)
+	if err != nil {
+		t.Fatalf("ListByOutcome() error = %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("audit events = %d; want 1", len(events))
+	}
+	event := events[0]
+	if event.ActorType != domainaudit.ActorTypeSystem || event.Action != actionHTTPPanic {
+		t.Fatalf("event = %s/%s; want system/%s", event.ActorType, event.Action, actionHTTPPanic)
+	}
+	var details struct {
+		Metadata map[string]any `json:"metadata"`
+	}
+	if err = json.Unmarshal(event.Details, &details); err != nil {
+		t.Fatalf("unmarshal details: %v", err)
+	}
+	if details.Metadata["request_id"] != seenRequestID || details.Metadata["panic"] != "nil map write" ||
+		details.Metadata["user_id"] != "user-1" {
+		t.Fatalf("metadata = %v", details.Metadata)
+	}
+}
+
+func TestRecoverPanics_CatchesPanicBeforeAuth(t *testing.T) {
+	db := openRuntimeTestDB(t)
+	auditSvc := domainaudit.NewAuditService(db)
+
+	panickingMiddleware := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("middleware bug")
+	})
+	handler := recoverPanics(panickingMiddleware, auditSvc)
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d; want 500", rec.Code)
+	}
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM audit_event`).Scan(&n); err != nil {
+		t.Fatalf("count audit events: %v", err)
+	}
+	if n != 0 {
+		t.Fatalf("audit events = %d; want 0 without a workspace", n)
+	}
+}
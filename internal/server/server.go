@@ -6,14 +6,25 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
 	"sync"
 	"time"
 
 	"github.com/matiasleandrokruk/fenix/internal/api"
+	domainaudit "github.com/matiasleandrokruk/fenix/internal/domain/audit"
+	domainauth "github.com/matiasleandrokruk/fenix/internal/domain/auth"
+	"github.com/matiasleandrokruk/fenix/internal/domain/crm"
 	configpkg "github.com/matiasleandrokruk/fenix/internal/infra/config"
 	"github.com/matiasleandrokruk/fenix/internal/infra/eventbus"
 	"github.com/matiasleandrokruk/fenix/internal/infra/llm"
+	"github.com/matiasleandrokruk/fenix/internal/infra/logging"
+	"github.com/matiasleandrokruk/fenix/internal/infra/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Config holds HTTP server configuration.
@@ -23,16 +34,61 @@ type Config struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	IdleTimeout  time.Duration
+	// ShutdownGracePeriod bounds how long Shutdown waits for in-flight
+	// requests before closing their connections. 0 waits until the Shutdown
+	// context is done.
+	ShutdownGracePeriod time.Duration
+	// LoginMaxFailures failed logins for one email or IP within
+	// LoginFailureWindow lock it out of POST /auth/login for
+	// LoginLockoutDuration. LoginMaxFailures <= 0 disables the lockout.
+	LoginMaxFailures     int
+	LoginFailureWindow   time.Duration
+	LoginLockoutDuration time.Duration
+	// MaxRequestBodyBytes rejects larger request bodies with 413.
+	// RequestTimeout is the deadline of each request's context; handlers
+	// failing after it answer 503. Either 0 disables the limit. Streaming
+	// (SSE) routes are exempt from RequestTimeout and WriteTimeout.
+	MaxRequestBodyBytes int64
+	RequestTimeout      time.Duration
+	// LogLevel is the minimum level logged and LogFormat the encoding,
+	// logging.FormatJSON or logging.FormatText. Logs go to LogOutput, or to
+	// stderr when it is nil. The logger reaches handlers and background
+	// workers through their contexts.
+	LogLevel  slog.Level
+	LogFormat string
+	LogOutput io.Writer
+	// OTLPEndpoint is the OTLP/HTTP collector URL agent run traces are
+	// exported to, e.g. http://localhost:4318. Empty disables tracing.
+	OTLPEndpoint string
+	// DealStalledAfter is how long an open deal may stay in one stage before
+	// the deal aging monitor, checking every DealAgingInterval, creates a
+	// follow-up task and publishes deal.stalled. 0 uses the crm defaults.
+	DealStalledAfter  time.Duration
+	DealAgingInterval time.Duration
+	// CaseSLAInterval is how often the case SLA monitor looks for cases past
+	// their sla_deadline to publish case.sla_breached. 0 uses the crm default.
+	CaseSLAInterval time.Duration
 }
 
 // DefaultConfig returns default HTTP server configuration.
 func DefaultConfig() Config {
 	return Config{
-		Host:         "0.0.0.0",
-		Port:         8080,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 2 * time.Minute,
-		IdleTimeout:  2 * time.Minute,
+		Host:                 "0.0.0.0",
+		Port:                 8080,
+		ReadTimeout:          15 * time.Second,
+		WriteTimeout:         2 * time.Minute,
+		IdleTimeout:          2 * time.Minute,
+		ShutdownGracePeriod:  10 * time.Second,
+		LoginMaxFailures:     5,
+		LoginFailureWindow:   15 * time.Minute,
+		LoginLockoutDuration: 15 * time.Minute,
+		MaxRequestBodyBytes:  10 << 20, // 10 MiB: room for knowledge ingest payloads
+		RequestTimeout:       90 * time.Second,
+		LogLevel:             slog.LevelInfo,
+		LogFormat:            logging.FormatText,
+		DealStalledAfter:     crm.DefaultDealStalledAfter,
+		DealAgingInterval:    crm.DefaultDealAgingInterval,
+		CaseSLAInterval:      crm.DefaultCaseSLAInterval,
 	}
 }
 
@@ -40,15 +96,27 @@ func DefaultConfig() Config {
 type Server struct {
 	config Config
 	db     *sql.DB
+	logger *slog.Logger
 	http   *http.Server
 	cancel context.CancelFunc
 	bgCtx  context.Context
 	bgWG   sync.WaitGroup
+	// tracerProvider is nil unless Config.OTLPEndpoint is set.
+	tracerProvider *sdktrace.TracerProvider
 }
 
 // NewServer creates a new HTTP server with the given database and configuration.
 // Task 1.3.9: Initialize HTTP server with database and routing
 func NewServer(db *sql.DB, config Config) (*Server, error) {
+	logOutput := config.LogOutput
+	if logOutput == nil {
+		logOutput = os.Stderr
+	}
+	logger, err := logging.New(logOutput, config.LogLevel, config.LogFormat)
+	if err != nil {
+		return nil, fmt.Errorf("server: create logger: %w", err)
+	}
+
 	appCfg := configpkg.Load()
 	chatProvider, err := llm.NewChatProvider(appCfg)
 	if err != nil {
@@ -59,32 +127,65 @@ func NewServer(db *sql.DB, config Config) (*Server, error) {
 		return nil, fmt.Errorf("server: create embed provider: %w", err)
 	}
 
-	bgCtx, cancel := context.WithCancel(context.Background())
+	bgCtx, cancel := context.WithCancel(logging.WithLogger(context.Background(), logger))
 	s := &Server{
 		config: config,
 		db:     db,
+		logger: logger,
 		bgCtx:  bgCtx,
 		cancel: cancel,
 	}
+	var tracerProvider trace.TracerProvider
+	if config.OTLPEndpoint != "" {
+		s.tracerProvider, err = tracing.NewProvider(bgCtx, config.OTLPEndpoint)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("server: create tracer provider: %w", err)
+		}
+		tracerProvider = s.tracerProvider
+	}
 	sharedBus := eventbus.New()
+	sharedBus.SetLogger(logger)
 
 	router, err := api.NewRouterWithRuntime(db, appCfg, api.RouterRuntime{
 		Bus:               sharedBus,
 		BackgroundContext: bgCtx,
 		StartBackground:   s.startBackground,
+		LoginLockout: &domainauth.LockoutPolicy{
+			MaxFailures: config.LoginMaxFailures,
+			Window:      config.LoginFailureWindow,
+			Cooldown:    config.LoginLockoutDuration,
+		},
+		MaxBodyBytes:   config.MaxRequestBodyBytes,
+		RequestTimeout: config.RequestTimeout,
+		Logger:         logger,
+		TracerProvider: tracerProvider,
+		// Handler wraps the router in recoverPanics below.
+		PanicsRecoveredOutside: true,
 	})
 	if err != nil {
 		cancel()
+		if s.tracerProvider != nil {
+			_ = s.tracerProvider.Shutdown(context.Background())
+		}
 		return nil, fmt.Errorf("server: build router: %w", err)
 	}
 	s.startRelationshipRuntime(sharedBus, chatProvider, embedProvider)
+	dealAging := crm.NewDealAgingMonitor(db, sharedBus, config.DealStalledAfter, config.DealAgingInterval)
+	s.startBackground(func() { dealAging.Start(s.bgCtx) })
+	caseSLA := crm.NewCaseSLAMonitor(db, sharedBus, config.CaseSLAInterval)
+	s.startBackground(func() { caseSLA.Start(s.bgCtx) })
 
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", config.Host, config.Port),
-		Handler:      router,
+		Handler:      recoverPanics(router, domainaudit.NewAuditService(db)),
 		ReadTimeout:  config.ReadTimeout,
 		WriteTimeout: config.WriteTimeout,
 		IdleTimeout:  config.IdleTimeout,
+		BaseContext: func(net.Listener) context.Context {
+			return logging.WithLogger(context.Background(), logger)
+		},
+		ErrorLog: slog.NewLogLogger(logger.Handler(), slog.LevelError),
 	}
 
 	s.http = httpServer
@@ -93,7 +194,7 @@ func NewServer(db *sql.DB, config Config) (*Server, error) {
 
 // Start starts the HTTP server and blocks until an error occurs.
 func (s *Server) Start(_ context.Context) error {
-	fmt.Printf("Starting HTTP server on %s\n", s.http.Addr)
+	s.logger.Info("starting HTTP server", slog.String(logging.KeyComponent, serverComponent), slog.String("addr", s.http.Addr))
 	if err := s.http.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("listen and serve: %w", err)
 	}
@@ -101,30 +202,56 @@ func (s *Server) Start(_ context.Context) error {
 }
 
 // Shutdown gracefully shuts down the server and closes the database connection.
+//
+// It stops accepting new connections and waits for in-flight requests for up
+// to ShutdownGracePeriod, closing whatever is still open after that. Only then
+// is the background context cancelled, so the embedder listener and other
+// workers started via startBackground stop after the last request that could
+// have fed them. Shutdown waits for those workers until ctx is done, then
+// flushes pending traces.
 func (s *Server) Shutdown(ctx context.Context) error {
-	fmt.Println("Shutting down server...")
+	s.logger.Info("shutting down server", slog.String(logging.KeyComponent, serverComponent))
 
-	// Shutdown HTTP server
-	if err := s.http.Shutdown(ctx); err != nil {
-		if s.cancel != nil {
-			s.cancel()
-		}
-		return fmt.Errorf("server shutdown error: %w", err)
-	}
+	drainErr := s.drainHTTP(ctx)
 
 	if s.cancel != nil {
 		s.cancel()
 	}
 	if err := s.waitBackground(ctx); err != nil {
-		return fmt.Errorf("background shutdown error: %w", err)
+		return errors.Join(drainErr, fmt.Errorf("background shutdown error: %w", err))
+	}
+	if s.tracerProvider != nil {
+		if err := s.tracerProvider.Shutdown(ctx); err != nil {
+			s.logger.Warn("flush traces failed", slog.String(logging.KeyComponent, serverComponent), logging.Err(err))
+		}
 	}
 
 	// Close database connection
 	if err := s.db.Close(); err != nil {
-		return fmt.Errorf("database close error: %w", err)
+		return errors.Join(drainErr, fmt.Errorf("database close error: %w", err))
+	}
+	if drainErr != nil {
+		return drainErr
+	}
+
+	s.logger.Info("server shutdown complete", slog.String(logging.KeyComponent, serverComponent))
+	return nil
+}
+
+// drainHTTP stops the listener and waits for active handlers within the grace
+// period. Handlers still running when it elapses are cut off.
+func (s *Server) drainHTTP(ctx context.Context) error {
+	drainCtx := ctx
+	if s.config.ShutdownGracePeriod > 0 {
+		var cancel context.CancelFunc
+		drainCtx, cancel = context.WithTimeout(ctx, s.config.ShutdownGracePeriod)
+		defer cancel()
 	}
 
-	fmt.Println("Server shutdown complete")
+	if err := s.http.Shutdown(drainCtx); err != nil {
+		_ = s.http.Close()
+		return fmt.Errorf("server shutdown error: %w", err)
+	}
 	return nil
 }
 
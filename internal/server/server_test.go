@@ -2,6 +2,16 @@
 package server
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -26,6 +36,9 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.IdleTimeout != 2*time.Minute {
 		t.Fatalf("IdleTimeout = %v; want %v", cfg.IdleTimeout, 2*time.Minute)
 	}
+	if cfg.ShutdownGracePeriod != 10*time.Second {
+		t.Fatalf("ShutdownGracePeriod = %v; want %v", cfg.ShutdownGracePeriod, 10*time.Second)
+	}
 }
 
 func TestNewServer_ConfiguresAddressAndHandler(t *testing.T) {
@@ -57,3 +70,141 @@ func TestNewServer_ConfiguresAddressAndHandler(t *testing.T) {
 		t.Fatal("Handler should not be nil")
 	}
 }
+
+func TestShutdown_DrainsInFlightRequestBeforeCancellingBackground(t *testing.T) {
+	db, err := sqlite.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.NewDB error = %v", err)
+	}
+	if err = sqlite.MigrateUp(db); err != nil {
+		t.Fatalf("sqlite.MigrateUp error = %v", err)
+	}
+
+	cfg := DefaultConfig()
+	cfg.ShutdownGracePeriod = 5 * time.Second
+	s, err := NewServer(db, cfg)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	started := make(chan struct{})
+	bgCancelledDuringRequest := make(chan bool, 1)
+	s.http.Handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		bgCancelledDuringRequest <- s.bgCtx.Err() != nil
+		_, _ = io.WriteString(w, "done")
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen error = %v", err)
+	}
+	go func() { _ = s.http.Serve(ln) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		resp, getErr := http.Get("http://" + ln.Addr().String() + "/slow")
+		if getErr != nil {
+			resCh <- result{err: getErr}
+			return
+		}
+		defer resp.Body.Close()
+		body, readErr := io.ReadAll(resp.Body)
+		resCh <- result{body: string(body), err: readErr}
+	}()
+
+	<-started
+	if err = s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	res := <-resCh
+	if res.err != nil {
+		t.Fatalf("in-flight request was cut off: %v", res.err)
+	}
+	if res.body != "done" {
+		t.Fatalf("body = %q; want %q", res.body, "done")
+	}
+	if <-bgCancelledDuringRequest {
+		t.Fatal("background context was cancelled before the in-flight request finished")
+	}
+	if s.bgCtx.Err() == nil {
+		t.Fatal("background context should be cancelled after Shutdown")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for the concurrent writes of request
+// handlers and background workers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestNewServer_JSONLogFormat(t *testing.T) {
+	db, err := sqlite.NewDB(":memory:")
+	if err != nil {
+		t.Fatalf("sqlite.NewDB error = %v", err)
+	}
+	if err = sqlite.MigrateUp(db); err != nil {
+		t.Fatalf("sqlite.MigrateUp error = %v", err)
+	}
+
+	var out syncBuffer
+	cfg := DefaultConfig()
+	cfg.LogFormat = "json"
+	cfg.LogLevel = slog.LevelInfo
+	cfg.LogOutput = &out
+	s, err := NewServer(db, cfg)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.http.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if err = s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	records := map[string]map[string]any{}
+	scanner := bufio.NewScanner(bytes.NewBufferString(out.String()))
+	for scanner.Scan() {
+		var record map[string]any
+		if err = json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", scanner.Text(), err)
+		}
+		for _, key := range []string{"time", "level", "msg"} {
+			if _, ok := record[key]; !ok {
+				t.Fatalf("log record %v lacks %q", record, key)
+			}
+		}
+		records[record["msg"].(string)] = record
+	}
+
+	access, ok := records["http request"]
+	if !ok {
+		t.Fatalf("no access record in %q", out.String())
+	}
+	if access["path"] != "/health" || access["status"] != float64(rec.Code) || access["request_id"] == "" {
+		t.Fatalf("access record = %v", access)
+	}
+	if shutdown, ok := records["server shutdown complete"]; !ok || shutdown["component"] != "server" {
+		t.Fatalf("shutdown record = %v", shutdown)
+	}
+}
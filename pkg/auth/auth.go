@@ -3,12 +3,17 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"os"
 	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/matiasleandrokruk/fenix/pkg/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -20,9 +25,16 @@ const BCryptCost = 12
 // DefaultJWTExpiry is the default JWT expiration time in hours if not set via env.
 const DefaultJWTExpiry = 24
 
+// DefaultRefreshTokenExpiry is the default refresh token lifetime in hours (30 days).
+const DefaultRefreshTokenExpiry = 720
+
+// refreshTokenBytes is the entropy of an opaque refresh token.
+const refreshTokenBytes = 32
+
 const (
-	envJWTSecret = "JWT_SECRET"
-	envJWTExpiry = "JWT_EXPIRY"
+	envJWTSecret          = "JWT_SECRET"
+	envJWTExpiry          = "JWT_EXPIRY"
+	envRefreshTokenExpiry = "REFRESH_TOKEN_EXPIRY"
 )
 
 // ===== ENVIRONMENT VARIABLES =====
@@ -38,7 +50,7 @@ func getJWTSecret() []byte {
 }
 
 // parseJWTExpiry parses an expiry string (hours) into a Duration.
-// Task 1.6.4: Extracted for testability — getJWTExpiry is the env-reading wrapper.
+// Task 1.6.4: Extracted for testability — GetJWTExpiry is the env-reading wrapper.
 // Returns DefaultJWTExpiry if empty string or invalid number (graceful degradation).
 func parseJWTExpiry(expiryStr string) time.Duration {
 	if expiryStr == "" {
@@ -53,11 +65,21 @@ func parseJWTExpiry(expiryStr string) time.Duration {
 	return time.Duration(hours) * time.Hour
 }
 
-// getJWTExpiry reads JWT_EXPIRY from environment in hours. Defaults to DefaultJWTExpiry.
-func getJWTExpiry() time.Duration {
+// GetJWTExpiry reads JWT_EXPIRY from environment in hours. Defaults to DefaultJWTExpiry.
+func GetJWTExpiry() time.Duration {
 	return parseJWTExpiry(os.Getenv(envJWTExpiry))
 }
 
+// GetRefreshTokenExpiry reads REFRESH_TOKEN_EXPIRY from environment in hours.
+// Defaults to DefaultRefreshTokenExpiry for empty, invalid or non-positive values.
+func GetRefreshTokenExpiry() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv(envRefreshTokenExpiry))
+	if err != nil || hours <= 0 {
+		return time.Duration(DefaultRefreshTokenExpiry) * time.Hour
+	}
+	return time.Duration(hours) * time.Hour
+}
+
 // ===== BCRYPT FUNCTIONS =====
 
 // HashPassword hashes a plaintext password using bcrypt.
@@ -87,20 +109,30 @@ func VerifyPassword(hash, password string) bool {
 type Claims struct {
 	UserID      string `json:"user_id"`
 	WorkspaceID string `json:"workspace_id"`
+	Role        string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // GenerateJWT creates a signed JWT token with user and workspace claims.
 // Task 1.6.4: Uses JWT_SECRET from env and JWT_EXPIRY (default 24 hours).
+// Every token carries a unique jti so it can be revoked on logout.
 // Panics if JWT_SECRET is not set (fail-fast for configuration errors).
 func GenerateJWT(userID, workspaceID string) (string, error) {
+	return GenerateJWTWithRole(userID, workspaceID, "")
+}
+
+// GenerateJWTWithRole is GenerateJWT with an RBAC role claim.
+// The role is read by handlers.RequireRole; an empty role is omitted.
+func GenerateJWTWithRole(userID, workspaceID, role string) (string, error) {
 	now := time.Now()
-	expiresAt := now.Add(getJWTExpiry())
+	expiresAt := now.Add(GetJWTExpiry())
 
 	claims := &Claims{
 		UserID:      userID,
 		WorkspaceID: workspaceID,
+		Role:        role,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewV7().String(), // jti — key for the revocation list
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
@@ -143,3 +175,23 @@ func ParseJWT(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+// ===== REFRESH TOKEN FUNCTIONS =====
+
+// GenerateRefreshToken returns a random opaque refresh token and its storage hash.
+// Only the hash is persisted; the token itself is handed to the client once.
+func GenerateRefreshToken() (token, hash string, err error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashRefreshToken(token), nil
+}
+
+// HashRefreshToken returns the hex SHA-256 of a refresh token for lookup.
+// Refresh tokens carry enough entropy that a fast hash is sufficient.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
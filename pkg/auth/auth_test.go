@@ -416,3 +416,55 @@ func countJWTParts(token string) int {
 	}
 	return count
 }
+
+func TestGenerateRefreshToken_UniqueAndHashed(t *testing.T) {
+	token1, hash1, err := GenerateRefreshToken()
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken failed: %v", err)
+	}
+	token2, _, err := GenerateRefreshToken()
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken failed: %v", err)
+	}
+	if token1 == token2 {
+		t.Error("expected distinct refresh tokens")
+	}
+	if hash1 == token1 || hash1 != HashRefreshToken(token1) {
+		t.Error("expected hash to be the deterministic SHA-256 of the token")
+	}
+}
+
+func TestGenerateJWT_UniqueJTI(t *testing.T) {
+	token1, err := GenerateJWT("user-1", "ws-1")
+	if err != nil {
+		t.Fatalf("GenerateJWT failed: %v", err)
+	}
+	token2, err := GenerateJWT("user-1", "ws-1")
+	if err != nil {
+		t.Fatalf("GenerateJWT failed: %v", err)
+	}
+	c1, _ := ParseJWT(token1)
+	c2, _ := ParseJWT(token2)
+	if c1 == nil || c2 == nil || c1.ID == "" || c1.ID == c2.ID {
+		t.Fatal("expected every JWT to carry a distinct jti")
+	}
+}
+
+func TestGenerateJWTWithRole_RoleClaim(t *testing.T) {
+	token, err := GenerateJWTWithRole("user-1", "ws-1", "agent")
+	if err != nil {
+		t.Fatalf("GenerateJWTWithRole failed: %v", err)
+	}
+	claims, err := ParseJWT(token)
+	if err != nil {
+		t.Fatalf("ParseJWT failed: %v", err)
+	}
+	if claims.Role != "agent" {
+		t.Errorf("Role = %q; want agent", claims.Role)
+	}
+
+	legacy, _ := GenerateJWT("user-1", "ws-1")
+	if c, _ := ParseJWT(legacy); c == nil || c.Role != "" {
+		t.Error("expected GenerateJWT to omit the role claim")
+	}
+}
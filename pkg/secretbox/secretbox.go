@@ -0,0 +1,82 @@
+// Package secretbox encrypts short secrets (webhook signing keys and the like)
+// for storage with AES-256-GCM. This is a leaf package with no domain
+// dependencies.
+package secretbox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sealedPrefix marks a sealed value and its format version.
+const sealedPrefix = "enc:v1:"
+
+var (
+	ErrEmptyKey  = errors.New("secretbox: empty key")
+	ErrNotSealed = errors.New("secretbox: value is not sealed")
+	ErrOpen      = errors.New("secretbox: cannot open sealed value")
+)
+
+// Box seals and opens secrets under one key.
+type Box struct {
+	aead cipher.AEAD
+}
+
+// New returns a Box keyed with the SHA-256 of key, so any non-empty string
+// (e.g. an env var) can serve as key material.
+func New(key string) (*Box, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: new cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("secretbox: new gcm: %w", err)
+	}
+	return &Box{aead: aead}, nil
+}
+
+// Seal encrypts plaintext with a random nonce and returns
+// "enc:v1:" followed by the base64 of nonce and ciphertext.
+func (b *Box) Seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("secretbox: read nonce: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return sealedPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Open decrypts a value produced by Seal. It returns ErrNotSealed for values
+// without the sealed prefix and ErrOpen when the value was sealed under
+// another key or has been tampered with.
+func (b *Box) Open(sealed string) (string, error) {
+	if !IsSealed(sealed) {
+		return "", ErrNotSealed
+	}
+	data, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix))
+	if err != nil || len(data) < b.aead.NonceSize() {
+		return "", ErrOpen
+	}
+	nonce, ciphertext := data[:b.aead.NonceSize()], data[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrOpen
+	}
+	return string(plaintext), nil
+}
+
+// IsSealed reports whether s looks like a value produced by Seal.
+func IsSealed(s string) bool {
+	return strings.HasPrefix(s, sealedPrefix)
+}
@@ -0,0 +1,51 @@
+package secretbox
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBox_SealOpenRoundTrip(t *testing.T) {
+	box, err := New("key-material")
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	sealed, err := box.Seal("hook-secret")
+	if err != nil {
+		t.Fatalf("Seal: %v", err)
+	}
+	if !IsSealed(sealed) || strings.Contains(sealed, "hook-secret") {
+		t.Fatalf("sealed = %q", sealed)
+	}
+	again, _ := box.Seal("hook-secret")
+	if again == sealed {
+		t.Fatal("two seals of the same plaintext are identical; nonce is not random")
+	}
+	got, err := box.Open(sealed)
+	if err != nil || got != "hook-secret" {
+		t.Fatalf("Open = %q, %v", got, err)
+	}
+}
+
+func TestBox_OpenRejectsOtherKeyAndPlaintext(t *testing.T) {
+	box, _ := New("key-a")
+	other, _ := New("key-b")
+	sealed, _ := box.Seal("hook-secret")
+
+	if _, err := other.Open(sealed); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Open with other key err = %v, want ErrOpen", err)
+	}
+	if _, err := box.Open(sealed[:len(sealed)-2]); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Open truncated err = %v, want ErrOpen", err)
+	}
+	if _, err := box.Open("hook-secret"); !errors.Is(err, ErrNotSealed) {
+		t.Fatalf("Open plaintext err = %v, want ErrNotSealed", err)
+	}
+}
+
+func TestNew_RejectsEmptyKey(t *testing.T) {
+	if _, err := New(""); !errors.Is(err, ErrEmptyKey) {
+		t.Fatalf("err = %v, want ErrEmptyKey", err)
+	}
+}
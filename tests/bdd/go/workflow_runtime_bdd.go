@@ -59,6 +59,8 @@ func (e bddToolExecutor) Execute(_ context.Context, _ json.RawMessage) (json.Raw
 	return e.result, nil
 }
 
+func (bddToolExecutor) ParamsSchema() json.RawMessage { return nil }
+
 func (bddPendingNestedRunner) Run(ctx context.Context, rc *agentdomain.RunContext, input agentdomain.TriggerAgentInput) (*agentdomain.Run, error) {
 	run, err := rc.Orchestrator.TriggerAgent(ctx, input)
 	if err != nil {